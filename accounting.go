@@ -0,0 +1,60 @@
+package main
+
+import (
+	"bytes"
+
+	"github.com/thrasher-corp/gocryptotrader/accounting"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// accountingSource returns the most complete wallet history source of an
+// exchange, Kraken's ledger records trades as well as transfers
+func accountingSource(exch exchange.IBotExchange) accounting.Source {
+	if e, ok := exchange.Underlying(exch).(*kraken.Kraken); ok {
+		return &accounting.KrakenLedger{Exchange: e}
+	}
+	return &accounting.FundingHistory{Exchange: exch}
+}
+
+// ExportAccounting returns the wallet history of the named exchange, or of
+// every loaded exchange with authenticated API support when the name is empty,
+// in a plain text accounting format
+func ExportAccounting(exchName string, format accounting.Format, accounts accounting.Accounts) ([]byte, error) {
+	var exchanges []exchange.IBotExchange
+	if exchName != "" {
+		exch := GetExchangeByName(exchName)
+		if exch == nil {
+			return nil, ErrExchangeNotFound
+		}
+		exchanges = append(exchanges, exch)
+	} else {
+		for _, exch := range GetLoadedExchanges() {
+			if exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+				exchanges = append(exchanges, exch)
+			}
+		}
+	}
+
+	var entries []accounting.Entry
+	for i := range exchanges {
+		resp, err := accountingSource(exchanges[i]).GetEntries()
+		if err != nil {
+			if exchName != "" {
+				return nil, err
+			}
+			log.Errorf("Failed to get %s wallet history for accounting export: %s",
+				exchanges[i].GetName(), err)
+			continue
+		}
+		entries = append(entries, resp...)
+	}
+
+	var b bytes.Buffer
+	err := accounting.Export(&b, format, entries, accounts)
+	if err != nil {
+		return nil, err
+	}
+	return b.Bytes(), nil
+}
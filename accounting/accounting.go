@@ -0,0 +1,201 @@
+// Package accounting normalises exchange wallet history into ledger entries
+// and exports them in the beancount and ledger-cli plain text accounting
+// formats, so bot activity can be imported into existing accounting books
+package accounting
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"time"
+	"unicode"
+)
+
+// Entry types
+const (
+	Deposit    = "deposit"
+	Withdrawal = "withdrawal"
+	Trade      = "trade"
+)
+
+// Default account names. {exchange}, {currency} and {type} are replaced with
+// the entry's exchange, the posting's currency and the entry's type
+const (
+	DefaultAsset    = "Assets:Exchanges:{exchange}:{currency}"
+	DefaultFee      = "Expenses:Fees:{exchange}"
+	DefaultTransfer = "Equity:Transfers:{exchange}"
+	DefaultOther    = "Income:Exchanges:{exchange}:{type}"
+)
+
+var (
+	errUnknownFormat = errors.New("unknown accounting export format")
+)
+
+// Leg holds a change to one currency balance of an entry. Amounts are signed,
+// fees are positive and deducted from the balance in addition to the amount
+type Leg struct {
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+	Fee      float64 `json:"fee,omitempty"`
+}
+
+// Entry defines a normalised wallet history entry
+type Entry struct {
+	Time        time.Time `json:"time"`
+	Exchange    string    `json:"exchange"`
+	Type        string    `json:"type"`
+	Reference   string    `json:"reference,omitempty"`
+	Description string    `json:"description,omitempty"`
+	Legs        []Leg     `json:"legs"`
+}
+
+// Accounts defines the account names entries are posted to
+type Accounts struct {
+	// Asset holds the exchange balances
+	Asset string `json:"asset"`
+	// Fee is charged the fees of every entry
+	Fee string `json:"fee"`
+	// Transfer balances deposits and withdrawals
+	Transfer string `json:"transfer"`
+	// Other balances the remaining single currency entries, e.g. staking
+	// rewards and margin interest
+	Other string `json:"other"`
+}
+
+// withDefaults returns the accounts with unset names defaulted
+func (a Accounts) withDefaults() Accounts {
+	if a.Asset == "" {
+		a.Asset = DefaultAsset
+	}
+	if a.Fee == "" {
+		a.Fee = DefaultFee
+	}
+	if a.Transfer == "" {
+		a.Transfer = DefaultTransfer
+	}
+	if a.Other == "" {
+		a.Other = DefaultOther
+	}
+	return a
+}
+
+// name returns the account name of a template. Each account component is
+// reduced to letters, digits and dashes and capitalised, which both formats
+// accept
+func name(template string, e *Entry, c string) string {
+	r := strings.NewReplacer("{exchange}", e.Exchange, "{currency}", c, "{type}", e.Type)
+	parts := strings.Split(r.Replace(template), ":")
+	for i := range parts {
+		parts[i] = strings.Map(func(r rune) rune {
+			if unicode.IsLetter(r) || unicode.IsDigit(r) || r == '-' {
+				return r
+			}
+			return -1
+		}, parts[i])
+		if parts[i] == "" {
+			parts[i] = "Unknown"
+		}
+		if first := rune(parts[i][0]); !unicode.IsUpper(first) {
+			if unicode.IsLetter(first) {
+				parts[i] = strings.ToUpper(parts[i][:1]) + parts[i][1:]
+			} else {
+				parts[i] = "X" + parts[i]
+			}
+		}
+	}
+	return strings.Join(parts, ":")
+}
+
+// posting is a balance change of an account
+type posting struct {
+	account  string
+	amount   float64
+	currency string
+	// cost is the total amount of costCurrency exchanged for the posting
+	cost         float64
+	costCurrency string
+}
+
+// postings returns the balanced postings of an entry, none when it changes no
+// balances
+func postings(e *Entry, a *Accounts) []posting {
+	var p []posting
+	// legs holds the index of each leg's amount posting
+	legs := make([]int, len(e.Legs))
+	var amounts []int
+	for i := range e.Legs {
+		l := &e.Legs[i]
+		c := strings.ToUpper(l.Currency)
+		if l.Amount != 0 {
+			legs[i] = len(p)
+			amounts = append(amounts, i)
+			p = append(p, posting{account: name(a.Asset, e, c), amount: l.Amount, currency: c})
+		}
+		if l.Fee != 0 {
+			p = append(p,
+				posting{account: name(a.Asset, e, c), amount: -l.Fee, currency: c},
+				posting{account: name(a.Fee, e, c), amount: l.Fee, currency: c})
+		}
+	}
+
+	if len(amounts) == 2 && e.Legs[amounts[0]].Amount*e.Legs[amounts[1]].Amount < 0 {
+		// Exchanges of one currency for another are balanced at the total
+		// cost of the currency acquired
+		in, out := amounts[0], amounts[1]
+		if e.Legs[in].Amount < 0 {
+			in, out = out, in
+		}
+		p[legs[in]].cost = -e.Legs[out].Amount
+		p[legs[in]].costCurrency = strings.ToUpper(e.Legs[out].Currency)
+		return p
+	}
+
+	// Anything else is balanced per currency by a transfer account for
+	// deposits and withdrawals, or the other account
+	counter := a.Other
+	if e.Type == Deposit || e.Type == Withdrawal {
+		counter = a.Transfer
+	}
+	var currencies []string
+	residual := make(map[string]float64)
+	for _, i := range amounts {
+		c := strings.ToUpper(e.Legs[i].Currency)
+		if _, ok := residual[c]; !ok {
+			currencies = append(currencies, c)
+		}
+		residual[c] += e.Legs[i].Amount
+	}
+	for _, c := range currencies {
+		if residual[c] != 0 {
+			p = append(p, posting{account: name(counter, e, c), amount: -residual[c], currency: c})
+		}
+	}
+	return p
+}
+
+// opened returns the accounts posted to and the date each is first used
+func opened(entries []Entry, a *Accounts) map[string]time.Time {
+	opens := make(map[string]time.Time)
+	for i := range entries {
+		p := postings(&entries[i], a)
+		for j := range p {
+			if t, ok := opens[p[j].account]; !ok || entries[i].Time.Before(t) {
+				opens[p[j].account] = entries[i].Time
+			}
+		}
+	}
+	return opens
+}
+
+// Sort orders entries by time, then exchange and reference
+func Sort(entries []Entry) {
+	sort.SliceStable(entries, func(i, j int) bool {
+		if !entries[i].Time.Equal(entries[j].Time) {
+			return entries[i].Time.Before(entries[j].Time)
+		}
+		if entries[i].Exchange != entries[j].Exchange {
+			return entries[i].Exchange < entries[j].Exchange
+		}
+		return entries[i].Reference < entries[j].Reference
+	})
+}
@@ -0,0 +1,135 @@
+package accounting
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+)
+
+func testEntries() []Entry {
+	return []Entry{
+		{
+			Time:      time.Date(2019, 6, 2, 8, 0, 0, 0, time.UTC),
+			Exchange:  "Kraken",
+			Type:      Trade,
+			Reference: "T1",
+			Legs: []Leg{
+				{Currency: "xbt", Amount: 0.5},
+				{Currency: "USD", Amount: -4000, Fee: 6.4},
+			},
+		},
+		{
+			Time:      time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC),
+			Exchange:  "Kraken",
+			Type:      Deposit,
+			Reference: "D1",
+			Legs:      []Leg{{Currency: "USD", Amount: 5000}},
+		},
+	}
+}
+
+func TestExportBeancount(t *testing.T) {
+	var b bytes.Buffer
+	err := Export(&b, Beancount, testEntries(), Accounts{Fee: "Expenses:Trading:{exchange}:{currency}"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := `2019-06-01 open Assets:Exchanges:Kraken:USD
+2019-06-02 open Assets:Exchanges:Kraken:XBT
+2019-06-01 open Equity:Transfers:Kraken
+2019-06-02 open Expenses:Trading:Kraken:USD
+
+2019-06-01 * "Kraken" "deposit"
+  type: "deposit"
+  reference: "D1"
+  Assets:Exchanges:Kraken:USD  5000 USD
+  Equity:Transfers:Kraken  -5000 USD
+
+2019-06-02 * "Kraken" "trade"
+  type: "trade"
+  reference: "T1"
+  Assets:Exchanges:Kraken:XBT  0.5 XBT @@ 4000 USD
+  Assets:Exchanges:Kraken:USD  -4000 USD
+  Assets:Exchanges:Kraken:USD  -6.4 USD
+  Expenses:Trading:Kraken:USD  6.4 USD
+`
+	if b.String() != expected {
+		t.Errorf("Test Failed - Export() unexpected beancount output\n%s", b.String())
+	}
+}
+
+func TestExportLedger(t *testing.T) {
+	entries := []Entry{{
+		Time:        time.Date(2019, 6, 3, 0, 0, 0, 0, time.UTC),
+		Exchange:    "Kraken",
+		Type:        "staking",
+		Description: "reward",
+		Legs:        []Leg{{Currency: "DOT.S", Amount: 1.25}},
+	}}
+	var b bytes.Buffer
+	if err := Export(&b, Ledger, entries, Accounts{}); err != nil {
+		t.Fatal(err)
+	}
+	expected := `account Assets:Exchanges:Kraken:DOTS
+account Income:Exchanges:Kraken:Staking
+
+2019/06/03 * Kraken reward
+    ; type: staking
+    Assets:Exchanges:Kraken:DOTS  1.25 "DOT.S"
+    Income:Exchanges:Kraken:Staking  -1.25 "DOT.S"
+`
+	if b.String() != expected {
+		t.Errorf("Test Failed - Export() unexpected ledger output\n%s", b.String())
+	}
+
+	if err := Export(&b, "csv", entries, Accounts{}); err == nil {
+		t.Error("Test Failed - Export() expected unknown format error")
+	}
+}
+
+func TestFundingEntries(t *testing.T) {
+	entries := FundingEntries([]exchange.FundHistory{
+		{ExchangeName: "OKEX", Currency: "BTC", Amount: 1, TransferType: "withdrawal", Fee: 0.0005, CryptoTxID: "tx"},
+		{ExchangeName: "OKEX", Currency: "BTC", Amount: 2, TransferType: "deposit"},
+	})
+	if len(entries) != 2 || entries[0].Type != Withdrawal || entries[0].Legs[0].Amount != -1 ||
+		entries[0].Reference != "tx" || entries[1].Type != Deposit || entries[1].Legs[0].Amount != 2 {
+		t.Errorf("Test Failed - FundingEntries() unexpected entries %+v", entries)
+	}
+}
+
+func TestOrderEntries(t *testing.T) {
+	entries := OrderEntries([]exchange.OrderDetail{
+		{Exchange: "Bitstamp", ID: "1", CurrencyPair: currency.NewPair(currency.BTC, currency.USD),
+			OrderSide: exchange.SellOrderSide, Price: 8000, ExecutedAmount: 0.5, Fee: 10},
+		{Exchange: "Bitstamp", ID: "2", CurrencyPair: currency.NewPair(currency.BTC, currency.USD),
+			OrderSide: exchange.BuyOrderSide, Price: 8000, Amount: 1},
+	})
+	if len(entries) != 1 || entries[0].Type != Trade || entries[0].Reference != "1" {
+		t.Fatalf("Test Failed - OrderEntries() unexpected entries %+v", entries)
+	}
+	l := entries[0].Legs
+	if l[0].Currency != "BTC" || l[0].Amount != -0.5 || l[1].Amount != 4000 || l[1].Fee != 10 {
+		t.Errorf("Test Failed - OrderEntries() unexpected legs %+v", l)
+	}
+}
+
+func TestKrakenEntries(t *testing.T) {
+	entries := KrakenEntries("Kraken", map[string]kraken.LedgerInfo{
+		"L1": {Refid: "T1", Time: 1559390400, Type: "trade", Asset: "XXBT", Amount: 0.5},
+		"L2": {Refid: "T1", Time: 1559390400, Type: "trade", Asset: "ZUSD", Amount: -4000, Fee: 6.4},
+		"L3": {Refid: "D1", Time: 1559300000, Type: "deposit", Asset: "ZUSD", Amount: 5000},
+	})
+	if len(entries) != 2 || entries[0].Reference != "D1" || len(entries[1].Legs) != 2 {
+		t.Fatalf("Test Failed - KrakenEntries() unexpected entries %+v", entries)
+	}
+	for _, l := range entries[1].Legs {
+		if l.Currency != "BTC" && l.Currency != "USD" {
+			t.Errorf("Test Failed - KrakenEntries() expected standard asset codes, got %s", l.Currency)
+		}
+	}
+}
@@ -0,0 +1,120 @@
+package accounting
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Format defines a plain text accounting format
+type Format string
+
+// Export formats
+const (
+	Beancount Format = "beancount"
+	Ledger    Format = "ledger"
+)
+
+// Export writes the entries in the format, preceded by the accounts they post
+// to. Entries are written in time order
+func Export(w io.Writer, format Format, entries []Entry, accounts Accounts) error {
+	if format != Beancount && format != Ledger {
+		return fmt.Errorf("%s %v", format, errUnknownFormat)
+	}
+	a := accounts.withDefaults()
+	sorted := append([]Entry(nil), entries...)
+	Sort(sorted)
+
+	opens := opened(sorted, &a)
+	names := make([]string, 0, len(opens))
+	for k := range opens {
+		names = append(names, k)
+	}
+	sort.Strings(names)
+
+	b := bufio.NewWriter(w)
+	for i := range names {
+		if format == Beancount {
+			fmt.Fprintf(b, "%s open %s\n", opens[names[i]].UTC().Format("2006-01-02"), names[i])
+		} else {
+			fmt.Fprintf(b, "account %s\n", names[i])
+		}
+	}
+
+	for i := range sorted {
+		e := &sorted[i]
+		p := postings(e, &a)
+		if len(p) == 0 {
+			continue
+		}
+		narration := e.Description
+		if narration == "" {
+			narration = e.Type
+		}
+		b.WriteString("\n")
+		if format == Beancount {
+			fmt.Fprintf(b, "%s * %s %s\n", e.Time.UTC().Format("2006-01-02"),
+				quote(e.Exchange), quote(narration))
+			fmt.Fprintf(b, "  type: %s\n", quote(e.Type))
+			if e.Reference != "" {
+				fmt.Fprintf(b, "  reference: %s\n", quote(e.Reference))
+			}
+		} else {
+			fmt.Fprintf(b, "%s * %s %s\n", e.Time.UTC().Format("2006/01/02"),
+				e.Exchange, strings.Replace(narration, "\n", " ", -1))
+			fmt.Fprintf(b, "    ; type: %s\n", e.Type)
+			if e.Reference != "" {
+				fmt.Fprintf(b, "    ; reference: %s\n", e.Reference)
+			}
+		}
+		for j := range p {
+			indent := "  "
+			if format == Ledger {
+				indent = "    "
+			}
+			fmt.Fprintf(b, "%s%s  %s", indent, p[j].account, amount(format, p[j].amount, p[j].currency))
+			if p[j].costCurrency != "" {
+				fmt.Fprintf(b, " @@ %s", amount(format, p[j].cost, p[j].costCurrency))
+			}
+			b.WriteString("\n")
+		}
+	}
+	return b.Flush()
+}
+
+// amount formats an amount of a commodity without exponents
+func amount(format Format, v float64, c string) string {
+	return strconv.FormatFloat(v, 'f', -1, 64) + " " + commodity(format, c)
+}
+
+// commodity returns the currency as a valid commodity. Beancount commodities
+// must start with a letter, ledger quotes commodities containing anything else
+func commodity(format Format, c string) string {
+	if format == Ledger {
+		for _, r := range c {
+			if !unicode.IsLetter(r) {
+				return strconv.Quote(c)
+			}
+		}
+		return c
+	}
+	c = strings.Map(func(r rune) rune {
+		if unicode.IsUpper(r) || unicode.IsDigit(r) || strings.ContainsRune("'._-", r) {
+			return r
+		}
+		return -1
+	}, strings.ToUpper(c))
+	if c == "" || !unicode.IsUpper(rune(c[0])) {
+		c = "X" + c
+	}
+	return c
+}
+
+// quote returns a beancount string
+func quote(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", " ").Replace(s) + `"`
+}
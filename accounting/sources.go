@@ -0,0 +1,191 @@
+package accounting
+
+import (
+	"math"
+	"strings"
+	"time"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+)
+
+// krakenLedgerPages caps the pages of 50 entries fetched from Kraken's ledger
+const krakenLedgerPages = 20
+
+// Source returns an exchange's wallet history as ledger entries
+type Source interface {
+	GetName() string
+	GetEntries() ([]Entry, error)
+}
+
+// FundingEntries returns the deposits and withdrawals of an exchange's
+// normalised funding history
+func FundingEntries(history []exchange.FundHistory) []Entry {
+	entries := make([]Entry, 0, len(history))
+	for i := range history {
+		h := &history[i]
+		e := Entry{
+			Time:        h.Timestamp,
+			Exchange:    h.ExchangeName,
+			Type:        Deposit,
+			Reference:   h.TransferID,
+			Description: h.Description,
+		}
+		if e.Reference == "" {
+			e.Reference = h.CryptoTxID
+		}
+		amount := math.Abs(h.Amount)
+		if strings.Contains(strings.ToLower(h.TransferType), "withdraw") {
+			e.Type = Withdrawal
+			amount = -amount
+		}
+		e.Legs = []Leg{{Currency: h.Currency, Amount: amount, Fee: h.Fee}}
+		entries = append(entries, e)
+	}
+	return entries
+}
+
+// FundingHistory reports the funding history of any exchange implementing it
+type FundingHistory struct {
+	Exchange exchange.IBotExchange
+}
+
+// GetName returns the exchange name
+func (f *FundingHistory) GetName() string {
+	return f.Exchange.GetName()
+}
+
+// GetEntries returns the exchange's deposits and withdrawals
+func (f *FundingHistory) GetEntries() ([]Entry, error) {
+	history, err := f.Exchange.GetFundingHistory()
+	if err != nil {
+		return nil, err
+	}
+	for i := range history {
+		if history[i].ExchangeName == "" {
+			history[i].ExchangeName = f.Exchange.GetName()
+		}
+	}
+	return FundingEntries(history), nil
+}
+
+// OrderEntries returns trade entries for the executed amounts of orders. Fees
+// are assumed charged in the quote currency and entries are timed at the
+// order date, as the normalised order history does not record fill times
+func OrderEntries(orders []exchange.OrderDetail) []Entry {
+	var entries []Entry
+	for i := range orders {
+		o := &orders[i]
+		if o.ExecutedAmount <= 0 {
+			continue
+		}
+		base, quote := o.ExecutedAmount, -o.ExecutedAmount*o.Price
+		if o.OrderSide == exchange.SellOrderSide || o.OrderSide == exchange.AskOrderSide {
+			base, quote = -base, -quote
+		}
+		entries = append(entries, Entry{
+			Time:      o.OrderDate,
+			Exchange:  o.Exchange,
+			Type:      Trade,
+			Reference: o.ID,
+			Legs: []Leg{
+				{Currency: o.CurrencyPair.Base.String(), Amount: base},
+				{Currency: o.CurrencyPair.Quote.String(), Amount: quote, Fee: o.Fee},
+			},
+		})
+	}
+	return entries
+}
+
+// OrderHistory reports the filled orders of any exchange implementing
+// order history within the time range
+type OrderHistory struct {
+	Exchange exchange.IBotExchange
+	Start    time.Time
+	End      time.Time
+}
+
+// GetName returns the exchange name
+func (o *OrderHistory) GetName() string {
+	return o.Exchange.GetName()
+}
+
+// GetEntries returns the exchange's filled orders for its enabled pairs
+func (o *OrderHistory) GetEntries() ([]Entry, error) {
+	orders, err := o.Exchange.GetOrderHistory(&exchange.GetOrdersRequest{
+		OrderType:  exchange.AnyOrderType,
+		OrderSide:  exchange.AnyOrderSide,
+		StartTicks: o.Start,
+		EndTicks:   o.End,
+		Currencies: o.Exchange.GetEnabledCurrencies(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		if orders[i].Exchange == "" {
+			orders[i].Exchange = o.Exchange.GetName()
+		}
+	}
+	return OrderEntries(orders), nil
+}
+
+// KrakenEntries returns the entries of Kraken's ledger, which records every
+// balance change. The ledger records each side of a trade separately so they
+// are combined by reference
+func KrakenEntries(exchangeName string, ledger map[string]kraken.LedgerInfo) []Entry {
+	refs := make(map[string]*Entry)
+	var entries []*Entry
+	for id, l := range ledger {
+		ref := l.Refid
+		if ref == "" {
+			ref = id
+		}
+		e, ok := refs[ref]
+		if !ok {
+			e = &Entry{Exchange: exchangeName, Type: l.Type, Reference: ref}
+			refs[ref] = e
+			entries = append(entries, e)
+		}
+		if t := time.Unix(0, int64(l.Time*float64(time.Second))); e.Time.IsZero() || t.Before(e.Time) {
+			e.Time = t
+		}
+		e.Legs = append(e.Legs, Leg{Currency: kraken.AssetCode(l.Asset).String(), Amount: l.Amount, Fee: l.Fee})
+	}
+
+	resp := make([]Entry, 0, len(entries))
+	for i := range entries {
+		resp = append(resp, *entries[i])
+	}
+	Sort(resp)
+	return resp
+}
+
+// KrakenLedger reports Kraken's ledger
+type KrakenLedger struct {
+	Exchange *kraken.Kraken
+}
+
+// GetName returns the exchange name
+func (k *KrakenLedger) GetName() string {
+	return k.Exchange.GetName()
+}
+
+// GetEntries returns the entries of Kraken's ledger, newest pages first up to
+// the page cap
+func (k *KrakenLedger) GetEntries() ([]Entry, error) {
+	ledger := make(map[string]kraken.LedgerInfo)
+	for page := 0; page < krakenLedgerPages; page++ {
+		resp, err := k.Exchange.GetLedgers(kraken.GetLedgersOptions{Ofs: int64(len(ledger))})
+		if err != nil {
+			return nil, err
+		}
+		for id := range resp.Ledger {
+			ledger[id] = resp.Ledger[id]
+		}
+		if len(resp.Ledger) == 0 || int64(len(ledger)) >= resp.Count {
+			break
+		}
+	}
+	return KrakenEntries(k.GetName(), ledger), nil
+}
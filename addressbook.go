@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+
+	"github.com/thrasher-corp/gocryptotrader/addressbook"
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errAddressBookNotLoaded = errors.New("address book not loaded")
+
+// ActivateAddressBook loads the withdrawal address book which every
+// withdrawal made through the bot is approved against
+func ActivateAddressBook() {
+	path := bot.addressBookFile
+	if path == "" {
+		path = filepath.Join(bot.dataDir, "addressbook.json")
+	}
+
+	b, err := addressbook.Load(path)
+	if err != nil {
+		log.Errorf("Address book failed to load from %s: %s", path, err)
+		return
+	}
+	b.RequireProof = bot.addressBookRequireProof
+	bot.addressBook = b
+	log.Debugf("Address book loaded %d destinations from %s.", len(b.List()), path)
+}
+
+// WithdrawCryptocurrencyFunds withdraws funds from an exchange once the
+// destination has been approved against the address book
+func WithdrawCryptocurrencyFunds(exchName string, req *exchange.WithdrawRequest) (string, error) {
+	if bot.addressBook == nil {
+		return "", errAddressBookNotLoaded
+	}
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return "", ErrExchangeNotFound
+	}
+
+	entry, err := bot.addressBook.Approve(exch.GetName(), req)
+	if err != nil {
+		log.Warnf("Withdrawal from %s rejected: %s", exch.GetName(), err)
+		return "", err
+	}
+
+	id, err := exch.WithdrawCryptocurrencyFunds(req)
+	if err != nil {
+		return "", err
+	}
+
+	msg := fmt.Sprintf("%s withdrawal of %v %s to %s submitted with ID %s",
+		exch.GetName(), req.Amount, req.Currency, entry.Label, id)
+	log.Debugln(msg)
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "WITHDRAWAL",
+			TradeDetails: msg,
+		})
+	}
+	return id, nil
+}
+
+// GetAddressBook returns the loaded address book
+func GetAddressBook() (*addressbook.Book, error) {
+	if bot.addressBook == nil {
+		return nil, errAddressBookNotLoaded
+	}
+	return bot.addressBook, nil
+}
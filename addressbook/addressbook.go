@@ -0,0 +1,299 @@
+// Package addressbook stores labelled withdrawal destinations along with the
+// exchanges permitted to withdraw to them and records proving ownership of
+// each address for travel rule compliance. Withdrawals are only approved to
+// destinations held in the book
+package addressbook
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+var (
+	errLabelNotSet        = errors.New("address book label not set")
+	errCurrencyNotSet     = errors.New("address book currency not set")
+	errAddressNotSet      = errors.New("address book address not set")
+	errDuplicateAddress   = errors.New("address book destination already exists")
+	errEntryNotFound      = errors.New("address book entry not found")
+	errProofMethodNotSet  = errors.New("address book ownership proof method not set")
+	errNilWithdrawRequest = errors.New("address book withdraw request is nil")
+	errUnknownDestination = errors.New("withdrawal destination not in address book")
+	errExchangeNotAllowed = errors.New("withdrawal destination not approved for exchange")
+	errOwnershipNotProven = errors.New("withdrawal destination has no ownership proof")
+)
+
+// Proof records how ownership of an address was established
+type Proof struct {
+	// Method describes the proof, e.g. signed message or test transaction
+	Method    string    `json:"method"`
+	Reference string    `json:"reference,omitempty"`
+	Note      string    `json:"note,omitempty"`
+	Verified  time.Time `json:"verified"`
+}
+
+// Entry defines a withdrawal destination
+type Entry struct {
+	ID       string        `json:"id"`
+	Label    string        `json:"label"`
+	Currency currency.Code `json:"currency"`
+	Address  string        `json:"address"`
+	// Tag holds the memo, tag or payment ID required by some currencies
+	Tag string `json:"tag,omitempty"`
+	// Exchanges lists the exchanges permitted to withdraw to the address,
+	// when empty any exchange may withdraw to it
+	Exchanges []string  `json:"exchanges,omitempty"`
+	Owner     string    `json:"owner,omitempty"`
+	Proofs    []Proof   `json:"proofs,omitempty"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+// AllowsExchange returns whether the exchange may withdraw to the entry
+func (e *Entry) AllowsExchange(exchangeName string) bool {
+	if len(e.Exchanges) == 0 {
+		return true
+	}
+	return common.StringDataCompareInsensitive(e.Exchanges, exchangeName)
+}
+
+// validate trims the destination and checks its required fields are set
+func (e *Entry) validate() error {
+	e.Address = strings.TrimSpace(e.Address)
+	e.Tag = strings.TrimSpace(e.Tag)
+	switch {
+	case e.Label == "":
+		return errLabelNotSet
+	case e.Currency.String() == "":
+		return errCurrencyNotSet
+	case e.Address == "":
+		return errAddressNotSet
+	}
+	return nil
+}
+
+func (e *Entry) matches(c currency.Code, address, tag string) bool {
+	return e.Currency.Match(c) && e.Address == address && e.Tag == tag
+}
+
+// Book holds address book entries, persisting them to a file when a path is
+// set
+type Book struct {
+	// RequireProof rejects withdrawals to destinations without an ownership
+	// proof on record
+	RequireProof bool
+
+	path    string
+	entries map[string]*Entry
+	mtx     sync.Mutex
+}
+
+// Load returns the address book stored at path. A missing file returns an
+// empty book which is created on the first change, an empty path keeps the
+// book in memory only
+func Load(path string) (*Book, error) {
+	b := &Book{path: path, entries: make(map[string]*Entry)}
+	if path == "" {
+		return b, nil
+	}
+
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return b, nil
+		}
+		return nil, err
+	}
+	var entries []*Entry
+	err = json.Unmarshal(data, &entries)
+	if err != nil {
+		return nil, err
+	}
+	for i := range entries {
+		b.entries[entries[i].ID] = entries[i]
+	}
+	return b, nil
+}
+
+// save writes the book to its file, the caller must hold the lock
+func (b *Book) save() error {
+	if b.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(b.list(), "", " ")
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(b.path, data)
+}
+
+func (b *Book) list() []Entry {
+	entries := make([]Entry, 0, len(b.entries))
+	for _, e := range b.entries {
+		entries = append(entries, copyEntry(e))
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		if entries[i].Label == entries[j].Label {
+			return entries[i].ID < entries[j].ID
+		}
+		return entries[i].Label < entries[j].Label
+	})
+	return entries
+}
+
+func copyEntry(e *Entry) Entry {
+	c := *e
+	c.Exchanges = append([]string(nil), e.Exchanges...)
+	c.Proofs = append([]Proof(nil), e.Proofs...)
+	return c
+}
+
+// Add stores a new destination and returns it with its generated ID
+func (b *Book) Add(e Entry) (Entry, error) {
+	err := e.validate()
+	if err != nil {
+		return Entry{}, err
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	for _, existing := range b.entries {
+		if existing.matches(e.Currency, e.Address, e.Tag) {
+			return Entry{}, fmt.Errorf("%v as %s", errDuplicateAddress, existing.Label)
+		}
+	}
+
+	id, err := common.GetRandomSalt(nil, 8)
+	if err != nil {
+		return Entry{}, err
+	}
+	e.ID = common.HexEncodeToString(id)
+	e.Currency = e.Currency.Upper()
+	e.Created = time.Now()
+	e.Updated = e.Created
+	stored := copyEntry(&e)
+	b.entries[e.ID] = &stored
+	return e, b.save()
+}
+
+// Update replaces the details of an existing destination. Ownership proofs are
+// retained unless the destination itself changes
+func (b *Book) Update(e Entry) error {
+	err := e.validate()
+	if err != nil {
+		return err
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	existing, ok := b.entries[e.ID]
+	if !ok {
+		return fmt.Errorf("%s %v", e.ID, errEntryNotFound)
+	}
+	for id, other := range b.entries {
+		if id != e.ID && other.matches(e.Currency, e.Address, e.Tag) {
+			return fmt.Errorf("%v as %s", errDuplicateAddress, other.Label)
+		}
+	}
+	if !existing.Currency.Match(e.Currency) || existing.Address != e.Address || existing.Tag != e.Tag {
+		// Proofs relate to the address they were recorded against
+		existing.Proofs = nil
+	}
+	existing.Label = e.Label
+	existing.Currency = e.Currency.Upper()
+	existing.Address = e.Address
+	existing.Tag = e.Tag
+	existing.Exchanges = append([]string(nil), e.Exchanges...)
+	existing.Owner = e.Owner
+	existing.Updated = time.Now()
+	return b.save()
+}
+
+// Remove deletes a destination
+func (b *Book) Remove(id string) error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if _, ok := b.entries[id]; !ok {
+		return fmt.Errorf("%s %v", id, errEntryNotFound)
+	}
+	delete(b.entries, id)
+	return b.save()
+}
+
+// AddProof records an ownership proof against a destination
+func (b *Book) AddProof(id string, p Proof) error {
+	if p.Method == "" {
+		return errProofMethodNotSet
+	}
+	if p.Verified.IsZero() {
+		p.Verified = time.Now()
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	e, ok := b.entries[id]
+	if !ok {
+		return fmt.Errorf("%s %v", id, errEntryNotFound)
+	}
+	e.Proofs = append(e.Proofs, p)
+	e.Updated = time.Now()
+	return b.save()
+}
+
+// Get returns a destination by ID
+func (b *Book) Get(id string) (Entry, error) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	e, ok := b.entries[id]
+	if !ok {
+		return Entry{}, fmt.Errorf("%s %v", id, errEntryNotFound)
+	}
+	return copyEntry(e), nil
+}
+
+// List returns all destinations ordered by label
+func (b *Book) List() []Entry {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.list()
+}
+
+// Find returns the destination for the currency, address and tag
+func (b *Book) Find(c currency.Code, address, tag string) (Entry, bool) {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	for _, e := range b.entries {
+		if e.matches(c, address, tag) {
+			return copyEntry(e), true
+		}
+	}
+	return Entry{}, false
+}
+
+// Approve checks a withdrawal against the address book, returning the
+// destination entry when the withdrawal is permitted
+func (b *Book) Approve(exchangeName string, req *exchange.WithdrawRequest) (Entry, error) {
+	if req == nil {
+		return Entry{}, errNilWithdrawRequest
+	}
+	e, ok := b.Find(req.Currency, strings.TrimSpace(req.Address), strings.TrimSpace(req.AddressTag))
+	if !ok {
+		return Entry{}, fmt.Errorf("%s %s %v", req.Currency, req.Address, errUnknownDestination)
+	}
+	if !e.AllowsExchange(exchangeName) {
+		return Entry{}, fmt.Errorf("%s %v %s", e.Label, errExchangeNotAllowed, exchangeName)
+	}
+	if b.RequireProof && len(e.Proofs) == 0 {
+		return Entry{}, fmt.Errorf("%s %v", e.Label, errOwnershipNotProven)
+	}
+	return e, nil
+}
@@ -0,0 +1,207 @@
+package addressbook
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+const testAddress = "1F5zVDgNjorJ51oGebSvNCrSAHpwGkUdDB"
+
+func testEntry() Entry {
+	return Entry{
+		Label:     "Cold wallet",
+		Currency:  currency.BTC,
+		Address:   testAddress,
+		Exchanges: []string{"Bitstamp"},
+		Owner:     "Treasury",
+	}
+}
+
+func TestAdd(t *testing.T) {
+	b, err := Load("")
+	if err != nil {
+		t.Fatal("Test Failed - Load() error", err)
+	}
+
+	invalid := []struct {
+		mod func(e *Entry)
+		err error
+	}{
+		{func(e *Entry) { e.Label = "" }, errLabelNotSet},
+		{func(e *Entry) { e.Currency = currency.Code{} }, errCurrencyNotSet},
+		{func(e *Entry) { e.Address = " " }, errAddressNotSet},
+	}
+	for i := range invalid {
+		e := testEntry()
+		invalid[i].mod(&e)
+		if _, err = b.Add(e); err != invalid[i].err {
+			t.Errorf("Test Failed - Add() test %d expected %v, got %v", i, invalid[i].err, err)
+		}
+	}
+
+	e, err := b.Add(testEntry())
+	if err != nil {
+		t.Fatal("Test Failed - Add() error", err)
+	}
+	if e.ID == "" || e.Created.IsZero() {
+		t.Error("Test Failed - Add() ID or created time not set")
+	}
+	if _, err = b.Add(testEntry()); err == nil {
+		t.Error("Test Failed - Add() expected duplicate destination error")
+	}
+
+	// The same address with a different tag is a separate destination
+	tagged := testEntry()
+	tagged.Label = "Tagged"
+	tagged.Tag = "12345"
+	if _, err = b.Add(tagged); err != nil {
+		t.Error("Test Failed - Add() tagged destination error", err)
+	}
+	if len(b.List()) != 2 {
+		t.Error("Test Failed - List() expected 2 entries")
+	}
+}
+
+func TestUpdateAndRemove(t *testing.T) {
+	b, err := Load("")
+	if err != nil {
+		t.Fatal("Test Failed - Load() error", err)
+	}
+	e, err := b.Add(testEntry())
+	if err != nil {
+		t.Fatal("Test Failed - Add() error", err)
+	}
+	if err = b.AddProof(e.ID, Proof{}); err != errProofMethodNotSet {
+		t.Error("Test Failed - AddProof() expected method not set error", err)
+	}
+	if err = b.AddProof("missing", Proof{Method: "signed message"}); err == nil {
+		t.Error("Test Failed - AddProof() expected entry not found error")
+	}
+	if err = b.AddProof(e.ID, Proof{Method: "signed message", Reference: "sig"}); err != nil {
+		t.Fatal("Test Failed - AddProof() error", err)
+	}
+
+	e.Label = "Renamed"
+	if err = b.Update(e); err != nil {
+		t.Fatal("Test Failed - Update() error", err)
+	}
+	got, err := b.Get(e.ID)
+	if err != nil {
+		t.Fatal("Test Failed - Get() error", err)
+	}
+	if got.Label != "Renamed" || len(got.Proofs) != 1 {
+		t.Errorf("Test Failed - Update() unexpected entry %+v", got)
+	}
+
+	// Changing the address discards proofs made against the old one
+	e.Address = "3J98t1WpEZ73CNmQviecrnyiWrnqRhWNLy"
+	if err = b.Update(e); err != nil {
+		t.Fatal("Test Failed - Update() error", err)
+	}
+	if got, _ = b.Get(e.ID); len(got.Proofs) != 0 {
+		t.Error("Test Failed - Update() proofs retained after address change")
+	}
+
+	if err = b.Remove(e.ID); err != nil {
+		t.Error("Test Failed - Remove() error", err)
+	}
+	if err = b.Remove(e.ID); err == nil {
+		t.Error("Test Failed - Remove() expected entry not found error")
+	}
+	if err = b.Update(e); err == nil {
+		t.Error("Test Failed - Update() expected entry not found error")
+	}
+}
+
+func TestApprove(t *testing.T) {
+	b, err := Load("")
+	if err != nil {
+		t.Fatal("Test Failed - Load() error", err)
+	}
+	e, err := b.Add(testEntry())
+	if err != nil {
+		t.Fatal("Test Failed - Add() error", err)
+	}
+
+	if _, err = b.Approve("Bitstamp", nil); err != errNilWithdrawRequest {
+		t.Error("Test Failed - Approve() expected nil request error", err)
+	}
+	req := &exchange.WithdrawRequest{Currency: currency.BTC, Address: testAddress, Amount: 1}
+	if _, err = b.Approve("Bitstamp", req); err != nil {
+		t.Error("Test Failed - Approve() error", err)
+	}
+	if _, err = b.Approve("Kraken", req); err == nil {
+		t.Error("Test Failed - Approve() expected exchange not allowed error")
+	}
+	if _, err = b.Approve("Bitstamp", &exchange.WithdrawRequest{
+		Currency:   currency.BTC,
+		Address:    testAddress,
+		AddressTag: "1",
+	}); err == nil {
+		t.Error("Test Failed - Approve() expected unknown destination error for tag")
+	}
+	if _, err = b.Approve("Bitstamp", &exchange.WithdrawRequest{
+		Currency: currency.LTC,
+		Address:  testAddress,
+	}); err == nil {
+		t.Error("Test Failed - Approve() expected unknown destination error for currency")
+	}
+
+	b.RequireProof = true
+	if _, err = b.Approve("Bitstamp", req); err == nil {
+		t.Error("Test Failed - Approve() expected ownership not proven error")
+	}
+	if err = b.AddProof(e.ID, Proof{Method: "test transaction"}); err != nil {
+		t.Fatal("Test Failed - AddProof() error", err)
+	}
+	if _, err = b.Approve("Bitstamp", req); err != nil {
+		t.Error("Test Failed - Approve() error after proof", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "addressbook")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "addressbook.json")
+
+	b, err := Load(path)
+	if err != nil {
+		t.Fatal("Test Failed - Load() error on missing file", err)
+	}
+	e, err := b.Add(testEntry())
+	if err != nil {
+		t.Fatal("Test Failed - Add() error", err)
+	}
+	if err = b.AddProof(e.ID, Proof{Method: "signed message", Note: "verified by compliance"}); err != nil {
+		t.Fatal("Test Failed - AddProof() error", err)
+	}
+
+	loaded, err := Load(path)
+	if err != nil {
+		t.Fatal("Test Failed - Load() error", err)
+	}
+	got, err := loaded.Get(e.ID)
+	if err != nil {
+		t.Fatal("Test Failed - Get() error", err)
+	}
+	if got.Address != testAddress || !got.Currency.Match(currency.BTC) ||
+		len(got.Proofs) != 1 || !got.AllowsExchange("bitstamp") {
+		t.Errorf("Test Failed - Load() unexpected entry %+v", got)
+	}
+
+	err = ioutil.WriteFile(path, []byte("{"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = Load(path); err == nil {
+		t.Error("Test Failed - Load() expected error for invalid file")
+	}
+}
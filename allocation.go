@@ -0,0 +1,109 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/allocation"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/drawdown"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errAllocationsDisabled = errors.New("strategy capital allocation not enabled")
+
+// ActivateAllocations starts virtual accounting of exchange balances per
+// strategy. Strategies trade through GetAllocatedExchange so their orders are
+// limited to the capital allocated to them
+func ActivateAllocations() {
+	if !bot.allocations {
+		return
+	}
+
+	quote := bot.config.Currency.FiatDisplayCurrency
+	l, err := allocation.NewLedger(func(exchName string, c currency.Code) (float64, error) {
+		exch := GetExchangeByName(exchName)
+		if exch == nil {
+			return 0, ErrExchangeNotFound
+		}
+		return drawdown.ValueInQuote(exch, c, quote)
+	})
+	if err != nil {
+		log.Errorf("Strategy capital allocation failed to start: %s", err)
+		return
+	}
+
+	exchanges := GetLoadedExchanges()
+	for i := range exchanges {
+		err = l.SyncBalances(exchanges[i])
+		if err != nil {
+			log.Errorf("Strategy capital allocation failed to sync %s balances: %s",
+				exchanges[i].GetName(), err)
+		}
+	}
+	bot.allocationLedger = l
+
+	go func() {
+		for {
+			select {
+			case <-bot.shutdown:
+				return
+			case <-time.After(allocation.DefaultReconcileInterval):
+			}
+			for i := range exchanges {
+				err := l.Reconcile(exchanges[i])
+				if err != nil {
+					log.Errorf("Strategy capital allocation failed to reconcile %s orders: %s",
+						exchanges[i].GetName(), err)
+				}
+			}
+		}
+	}()
+	log.Debugf("Strategy capital allocation enabled, reporting in %s.", quote)
+}
+
+// GetAllocatedExchange returns the exchange a strategy trades through, orders
+// are limited to the strategy's allocation and booked to its sub-account
+func GetAllocatedExchange(exchName, strategy string) (exchange.IBotExchange, error) {
+	if bot.allocationLedger == nil {
+		return nil, errAllocationsDisabled
+	}
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+	return bot.allocationLedger.Guard(exch, strategy), nil
+}
+
+// AllocateStrategyCapital allocates an exchange balance to a strategy, a
+// negative amount returns funds from the strategy
+func AllocateStrategyCapital(strategy, exchName string, c currency.Code, amount float64) error {
+	if bot.allocationLedger == nil {
+		return errAllocationsDisabled
+	}
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return ErrExchangeNotFound
+	}
+	// Funds deployed for yield are unwound before they are allocated
+	if bot.yieldOptimizer != nil && amount > 0 {
+		err := bot.yieldOptimizer.Require(exch.GetName(), c, amount)
+		if err != nil {
+			return err
+		}
+	}
+	err := bot.allocationLedger.SyncBalances(exch)
+	if err != nil {
+		return err
+	}
+	return bot.allocationLedger.Allocate(strategy, exch.GetName(), c, amount)
+}
+
+// GetStrategyAllocations returns every strategy's sub-account report
+func GetStrategyAllocations() ([]allocation.Report, error) {
+	if bot.allocationLedger == nil {
+		return nil, errAllocationsDisabled
+	}
+	return bot.allocationLedger.GetReports(), nil
+}
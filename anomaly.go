@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/anomaly"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errAnomalyWatcherDisabled = errors.New("account anomaly watcher not enabled")
+
+// ActivateAnomalyWatcher starts watching Bitmex accounts for API key changes,
+// withdrawals to destinations missing from the address book and suspicious
+// notifications. With lockdown enabled every exchange is guarded so orders and
+// withdrawals are blocked once a critical anomaly is found
+func ActivateAnomalyWatcher() {
+	if !bot.anomalyWatch {
+		return
+	}
+
+	var sources []anomaly.Source
+	var exchanges []exchange.IBotExchange
+	for _, exch := range GetLoadedExchanges() {
+		exchanges = append(exchanges, exch)
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		if e, ok := exchange.Underlying(exch).(*bitmex.Bitmex); ok {
+			sources = append(sources, &anomaly.BitmexActivity{Exchange: e})
+		}
+	}
+
+	cfg := anomaly.Config{Lockdown: bot.anomalyLockdown}
+	if bot.addressBook != nil {
+		book := bot.addressBook
+		cfg.Trusted = func(exchName string, c currency.Code, address string) bool {
+			entry, ok := book.Find(c, address, "")
+			return ok && entry.AllowsExchange(exchName)
+		}
+	}
+
+	w, err := anomaly.New(cfg, sources, exchanges, handleAnomaly)
+	if err != nil {
+		log.Errorf("Account anomaly watcher failed to start: %s", err)
+		return
+	}
+
+	if bot.anomalyLockdown {
+		for x := range bot.exchanges {
+			if bot.exchanges[x] == nil {
+				continue
+			}
+			bot.exchanges[x] = w.Guard(bot.exchanges[x])
+		}
+	}
+
+	w.Start(anomaly.DefaultCheckInterval)
+	bot.anomalyWatcher = w
+	log.Debugf("Account anomaly watcher enabled for %d exchanges, lockdown %v.",
+		len(sources), bot.anomalyLockdown)
+}
+
+func handleAnomaly(a anomaly.Anomaly) {
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         a.Type,
+			TradeDetails: a.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(a, "account_anomaly", "", a.Exchange)
+	}
+}
+
+// ResetAnomalyLockdown lifts the lockdown applied by the account anomaly
+// watcher
+func ResetAnomalyLockdown() error {
+	if bot.anomalyWatcher == nil {
+		return errAnomalyWatcherDisabled
+	}
+	return bot.anomalyWatcher.Reset()
+}
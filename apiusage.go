@@ -0,0 +1,64 @@
+package main
+
+import (
+	"errors"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/apiusage"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/request"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errAPIUsageDisabled = errors.New("API usage tracking not enabled")
+
+// ActivateAPIUsage starts counting every exchange's REST requests and
+// websocket messages. It is enabled before exchanges are set up so their
+// startup requests are counted
+func ActivateAPIUsage() {
+	if !bot.apiUsage {
+		return
+	}
+	apiusage.Enable(apiusage.New())
+	log.Debugf("API usage tracking enabled.")
+}
+
+// usageLimit converts a requester rate limit, a limit without a rate is
+// unknown
+func usageLimit(r *request.RateLimit) apiusage.Limit {
+	if r == nil {
+		return apiusage.Limit{}
+	}
+	return apiusage.Limit{Requests: r.GetRate(), Per: r.GetDuration()}
+}
+
+// GetAPIUsage returns the API usage today of the named exchange, or of every
+// loaded exchange when no name is given, projected against the rate limits
+// each exchange documents
+func GetAPIUsage(exchName string) ([]apiusage.Report, error) {
+	t := apiusage.Active()
+	if t == nil {
+		return nil, errAPIUsageDisabled
+	}
+	exchanges := GetLoadedExchanges()
+	if exchName != "" {
+		exch := GetExchangeByName(exchName)
+		if exch == nil {
+			return nil, ErrExchangeNotFound
+		}
+		exchanges = []exchange.IBotExchange{exch}
+	}
+
+	type rateLimits interface {
+		GetRequestRateLimits() (unauth, auth *request.RateLimit)
+	}
+	var resp []apiusage.Report
+	for _, exch := range exchanges {
+		var public, private apiusage.Limit
+		if e, ok := exchange.Underlying(exch).(rateLimits); ok {
+			unauth, auth := e.GetRequestRateLimits()
+			public, private = usageLimit(unauth), usageLimit(auth)
+		}
+		resp = append(resp, t.Report(exch.GetName(), public, private))
+	}
+	return resp, nil
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errAssetStatusUnsupported = errors.New("exchange does not publish asset deposit and withdrawal statuses")
+
+// AssetStatus returns whether deposits and withdrawals of a currency are
+// enabled on each of its networks on an exchange
+func AssetStatus(exchName string, c currency.Code) (exchange.AssetStatus, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return exchange.AssetStatus{}, ErrExchangeNotFound
+	}
+	a, ok := exchange.Underlying(exch).(exchange.AssetStatusChecker)
+	if !ok {
+		return exchange.AssetStatus{}, errAssetStatusUnsupported
+	}
+	return a.GetAssetStatus(c)
+}
+
+// checkTransferNetwork returns an error when a currency's network is
+// suspended for withdrawals from, or deposits to, an exchange. Transfers are
+// not blocked when the status is unavailable
+func checkTransferNetwork(exchName string, c currency.Code, withdraw bool) error {
+	s, err := AssetStatus(exchName, c)
+	if err != nil {
+		if err != errAssetStatusUnsupported {
+			log.Warnf("%s %s network status unavailable: %s", exchName, c, err)
+		}
+		return nil
+	}
+	if withdraw {
+		return s.CheckWithdraw()
+	}
+	return s.CheckDeposit()
+}
+
+var errChainsUnsupported = errors.New("exchange does not support transfers on multiple chains")
+
+// DepositAddress holds a deposit address and the transfer rules of the chain
+// it is on, when the exchange publishes them
+type DepositAddress struct {
+	exchange.ChainDepositAddress
+	ChainInfo *exchange.ChainInfo `json:"chainInfo,omitempty"`
+}
+
+// GetChainInfo returns the transfer rules of each of a currency's chains on
+// an exchange
+func GetChainInfo(exchName string, c currency.Code) ([]exchange.ChainInfo, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+	p, ok := exchange.Underlying(exch).(exchange.ChainProvider)
+	if !ok {
+		return nil, errChainsUnsupported
+	}
+	return p.GetChainInfo(c)
+}
+
+// GetDepositAddress returns the deposit address of a currency on the chain,
+// or the exchange's default chain when empty. An error is returned when
+// deposits on the chain are suspended
+func GetDepositAddress(exchName string, c currency.Code, chain string) (DepositAddress, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return DepositAddress{}, ErrExchangeNotFound
+	}
+	p, ok := exchange.Underlying(exch).(exchange.ChainProvider)
+	if !ok {
+		if chain != "" {
+			return DepositAddress{}, errChainsUnsupported
+		}
+		address, err := exch.GetDepositAddress(c, "")
+		if err != nil {
+			return DepositAddress{}, err
+		}
+		return DepositAddress{ChainDepositAddress: exchange.ChainDepositAddress{
+			Currency: c,
+			Address:  address,
+		}}, nil
+	}
+
+	a, err := p.GetChainDepositAddress(c, chain)
+	if err != nil {
+		return DepositAddress{}, err
+	}
+	resp := DepositAddress{ChainDepositAddress: a}
+	chains, err := p.GetChainInfo(c)
+	if err != nil {
+		log.Warnf("%s %s chain info unavailable: %s", exchName, c, err)
+		return resp, nil
+	}
+	info, err := exchange.FindChain(chains, a.Chain)
+	if err != nil {
+		log.Warnf("%s %s chain info unavailable: %s", exchName, c, err)
+		return resp, nil
+	}
+	if !info.DepositEnabled {
+		return DepositAddress{}, fmt.Errorf("%s %s %s deposits %v", exchName, c, info.Chain, exchange.ErrNetworkSuspended)
+	}
+	resp.ChainInfo = &info
+	return resp, nil
+}
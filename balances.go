@@ -0,0 +1,54 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/balances"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errBalancesDisabled = errors.New("balance aggregation not enabled")
+
+// ActivateBalances starts polling the balances of every exchange with
+// authenticated API support, aggregating them into a portfolio snapshot valued
+// in the fiat display currency
+func ActivateBalances() {
+	if !bot.balances {
+		return
+	}
+
+	var exchanges []exchange.IBotExchange
+	for _, exch := range GetLoadedExchanges() {
+		if exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			exchanges = append(exchanges, exch)
+		}
+	}
+
+	fiat := bot.config.Currency.FiatDisplayCurrency
+	a, err := balances.New(exchanges, fiat,
+		func(exchName string, c currency.Code) (float64, error) {
+			exch := GetExchangeByName(exchName)
+			if exch == nil {
+				return 0, ErrExchangeNotFound
+			}
+			return balances.FiatValue(exch, c, fiat)
+		}, bot.balancesMaxAge)
+	if err != nil {
+		log.Errorf("Balance aggregation failed to start: %s", err)
+		return
+	}
+	a.Start(bot.balancesInterval)
+	bot.balanceAggregator = a
+	log.Debugf("Balance aggregation enabled for %d exchanges, valued in %s.", len(exchanges), fiat)
+}
+
+// GetPortfolioSnapshot returns the balances of every exchange aggregated by
+// currency
+func GetPortfolioSnapshot() (balances.Snapshot, error) {
+	if bot.balanceAggregator == nil {
+		return balances.Snapshot{}, errBalancesDisabled
+	}
+	return bot.balanceAggregator.GetSnapshot()
+}
@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errInvalidBitmexMarginSpec = errors.New("bitmex margin settings must be in the format SYMBOL=cross or SYMBOL=isolated/LEVERAGE[/BUFFER[/DISTANCE]]")
+
+// ActivateBitmexMargin sets the margin mode and leverage of the -bitmexmargin
+// positions before strategies start trading, then keeps isolated positions
+// away from liquidation by topping up their margin
+func ActivateBitmexMargin() {
+	if bot.bitmexMargin == "" {
+		return
+	}
+
+	if bot.dryRun || bot.dropCopy {
+		log.Warnf("Bitmex margin manager disabled in dry run and drop copy modes.")
+		return
+	}
+
+	settings, err := parseBitmexMarginSettings(bot.bitmexMargin)
+	if err != nil {
+		log.Errorf("Bitmex margin manager failed to start: %s", err)
+		return
+	}
+
+	var e *bitmex.Bitmex
+	for _, exch := range GetLoadedExchanges() {
+		if b, ok := exchange.Underlying(exch).(*bitmex.Bitmex); ok {
+			e = b
+			break
+		}
+	}
+	if e == nil {
+		log.Errorf("Bitmex margin manager failed to start: %s", ErrExchangeNotFound)
+		return
+	}
+
+	m := bitmex.NewMarginManager(e)
+	for i := range settings {
+		err = m.Add(settings[i])
+		if err != nil {
+			log.Errorf("Bitmex margin manager failed to start: %s", err)
+			return
+		}
+	}
+
+	err = m.Prepare()
+	if err != nil {
+		log.Errorf("Bitmex margin manager failed to start: %s", err)
+		return
+	}
+	m.Start(bot.bitmexMarginInterval)
+	bot.bitmexMarginManager = m
+	log.Debugf("Bitmex margin manager enabled for %d positions.", len(settings))
+}
+
+// parseBitmexMarginSettings parses margin settings in the format
+// XBTUSD=isolated/10/0.5/0.05,ETHUSD=cross where the optional buffer is the
+// required excess of position margin over maintenance margin and the distance
+// is the fraction between mark and liquidation price which triggers a top up
+func parseBitmexMarginSettings(s string) ([]bitmex.MarginSettings, error) {
+	var settings []bitmex.MarginSettings
+	for _, o := range strings.Split(s, ",") {
+		kv := strings.Split(strings.TrimSpace(o), "=")
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errInvalidBitmexMarginSpec
+		}
+		parts := strings.Split(kv[1], "/")
+
+		m := bitmex.MarginSettings{Symbol: strings.ToUpper(kv[0])}
+		switch strings.ToLower(parts[0]) {
+		case bitmex.CrossMargin.String():
+			if len(parts) != 1 {
+				return nil, errInvalidBitmexMarginSpec
+			}
+		case bitmex.IsolatedMargin.String():
+			if len(parts) < 2 || len(parts) > 4 {
+				return nil, errInvalidBitmexMarginSpec
+			}
+			m.Mode = bitmex.IsolatedMargin
+			values := make([]float64, 3)
+			for i := 1; i < len(parts); i++ {
+				v, err := strconv.ParseFloat(parts[i], 64)
+				if err != nil {
+					return nil, errInvalidBitmexMarginSpec
+				}
+				values[i-1] = v
+			}
+			m.Leverage, m.Buffer, m.LiquidationDistance = values[0], values[1], values[2]
+		default:
+			return nil, errInvalidBitmexMarginSpec
+		}
+		settings = append(settings, m)
+	}
+	return settings, nil
+}
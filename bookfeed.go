@@ -0,0 +1,65 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bookfeed"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errOrderbookFeedDisabled = errors.New("orderbook feed not enabled")
+
+// ActivateOrderbookFeed starts distributing REST and websocket orderbook
+// updates to strategies as deltas
+func ActivateOrderbookFeed() {
+	if !bot.orderbookFeed {
+		return
+	}
+	bot.bookFeed = bookfeed.New()
+	log.Debugf("Orderbook feed enabled.")
+}
+
+// publishOrderbook distributes an updated orderbook to feed subscribers if
+// the feed is enabled
+func publishOrderbook(o *orderbook.Base) {
+	if bot.bookFeed == nil {
+		return
+	}
+	err := bot.bookFeed.Publish(o)
+	if err != nil {
+		log.Debugf("Failed to publish %s %s orderbook: %s", o.ExchangeName, o.Pair, err)
+	}
+}
+
+// publishWebsocketOrderbook distributes the stored orderbook of a websocket
+// orderbook update
+func publishWebsocketOrderbook(exchName string, p currency.Pair, assetType string) {
+	if bot.bookFeed == nil {
+		return
+	}
+	o, err := orderbook.Get(exchName, p, assetType)
+	if err != nil {
+		log.Debugf("Failed to get %s %s websocket orderbook: %s", exchName, p, err)
+		return
+	}
+	publishOrderbook(&o)
+}
+
+// SubscribeOrderbookFeed subscribes to orderbook updates as deltas
+func SubscribeOrderbookFeed(opts bookfeed.Options) (*bookfeed.Subscription, error) {
+	if bot.bookFeed == nil {
+		return nil, errOrderbookFeedDisabled
+	}
+	return bot.bookFeed.Subscribe(opts)
+}
+
+// GetOrderbookFeedStats returns the delivery statistics of every orderbook
+// feed subscriber
+func GetOrderbookFeedStats() ([]bookfeed.Stats, error) {
+	if bot.bookFeed == nil {
+		return nil, errOrderbookFeedDisabled
+	}
+	return bot.bookFeed.GetStats(), nil
+}
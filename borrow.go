@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/borrow"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errInvalidBorrowThreshold = errors.New("borrow rate thresholds must be in the format CURRENCY:PERCENT")
+
+// ActivateBorrowRateMonitor starts monitoring borrow rates across all loaded
+// exchanges against the configured annualised rate thresholds
+func ActivateBorrowRateMonitor() {
+	if bot.borrowRateThresholds == "" {
+		return
+	}
+
+	thresholds, err := parseBorrowRateThresholds(bot.borrowRateThresholds)
+	if err != nil {
+		log.Errorf("Borrow rate monitor failed to start: %s", err)
+		return
+	}
+
+	m, err := borrow.NewMonitor(GetLoadedExchanges(), thresholds, handleBorrowRateAlert)
+	if err != nil {
+		log.Errorf("Borrow rate monitor failed to start: %s", err)
+		return
+	}
+	m.Start(borrow.DefaultCheckInterval)
+	bot.borrowRateMonitor = m
+	log.Debugf("Borrow rate monitor enabled for %d currencies.", len(thresholds))
+}
+
+// parseBorrowRateThresholds parses thresholds in the format BTC:20,ETH:15
+// where each value is the maximum annualised rate as a percentage
+func parseBorrowRateThresholds(s string) (map[currency.Code]float64, error) {
+	thresholds := make(map[currency.Code]float64)
+	for _, t := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(t), ":")
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errInvalidBorrowThreshold
+		}
+		percent, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, errInvalidBorrowThreshold
+		}
+		thresholds[currency.NewCode(parts[0]).Upper()] = percent / 100
+	}
+	return thresholds, nil
+}
+
+func handleBorrowRateAlert(a borrow.Alert) {
+	log.Warnf("Borrow rate alert: %s", a.String())
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "BORROW_RATE_ALERT",
+			TradeDetails: a.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(a, "borrow_rate_alert", "", a.Rate.Exchange)
+	}
+}
+
+// GetBorrowRates returns the borrow rates for a currency across all loaded
+// exchanges ordered from cheapest to most expensive
+func GetBorrowRates(c string) ([]exchange.BorrowRate, error) {
+	return borrow.Compare(GetLoadedExchanges(), currency.NewCode(c))
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bracket"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errBracketsDisabled = errors.New("bracket orders not enabled")
+
+// ActivateBracketOrders starts managing bracket orders, resuming the open
+// brackets stored in the data directory
+func ActivateBracketOrders() {
+	if !bot.bracketOrders {
+		return
+	}
+
+	path := filepath.Join(bot.dataDir, "brackets.json")
+	m, err := bracket.New(path, GetExchangeByName, bracketPrice, handleBracketEvent)
+	if err != nil {
+		log.Errorf("Bracket order manager failed to load from %s: %s", path, err)
+		return
+	}
+	m.Start(bracket.DefaultCheckInterval)
+	bot.bracketManager = m
+	log.Debugf("Bracket order manager enabled, persisting to %s.", path)
+}
+
+// bracketPrice fetches the last price client side stop losses are triggered
+// by, stored tickers may be too stale to protect a position
+func bracketPrice(exchName string, p currency.Pair) (float64, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return 0, ErrExchangeNotFound
+	}
+	t, err := exch.UpdateTicker(p, ticker.Spot)
+	if err != nil {
+		return 0, err
+	}
+	return t.Last, nil
+}
+
+func handleBracketEvent(e bracket.Event) {
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Bracket, "bracket_event", "", e.Bracket.Exchange)
+	}
+}
+
+// SubmitBracketOrder places a bracket order on the named exchange
+func SubmitBracketOrder(exchName string, order *exchange.BracketOrder) (bracket.Bracket, error) {
+	if bot.bracketManager == nil {
+		return bracket.Bracket{}, errBracketsDisabled
+	}
+	return bot.bracketManager.Submit(exchName, order)
+}
+
+// CancelBracketOrder cancels the open orders of a bracket
+func CancelBracketOrder(id string) (bracket.Bracket, error) {
+	if bot.bracketManager == nil {
+		return bracket.Bracket{}, errBracketsDisabled
+	}
+	return bot.bracketManager.Cancel(id)
+}
+
+// GetBracketOrders returns every bracket order placed through the bot
+func GetBracketOrders() ([]bracket.Bracket, error) {
+	if bot.bracketManager == nil {
+		return nil, errBracketsDisabled
+	}
+	return bot.bracketManager.List(), nil
+}
@@ -0,0 +1,197 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/dropcopy"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/breakeven"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// settlementCheckInterval is the delay between applying the futures
+// settlements and deliveries of tracked positions
+const settlementCheckInterval = time.Hour
+
+var errBreakEvenTrackerDisabled = errors.New("break-even tracker not enabled")
+
+// ActivateBreakEvenTracker starts tracking the break-even exit price of open
+// positions, alerting when the mark price crosses it. Fills are recorded
+// through RecordFill and from drop copy order fills. Positions are kept in
+// the data directory and futures positions are settled from the exchange's
+// settlement history, including settlements while the bot was offline
+func ActivateBreakEvenTracker() {
+	if !bot.breakEven {
+		return
+	}
+
+	t := breakeven.New(exitFeeRate, markPrice, handleBreakEvenCross)
+	err := t.Load(filepath.Join(bot.dataDir, "breakeven.json"))
+	if err != nil {
+		log.Errorf("Break-even tracker failed to load positions: %s", err)
+	}
+	t.Start(breakeven.DefaultCheckInterval)
+	bot.breakEvenTracker = t
+
+	go func() {
+		for {
+			applySettlements(t)
+			select {
+			case <-bot.shutdown:
+				return
+			case <-time.After(settlementCheckInterval):
+			}
+		}
+	}()
+	log.Debugf("Break-even tracker enabled.")
+}
+
+// applySettlements applies the settlement history of each underlying pair
+// with tracked positions, since the earliest of them opened, from exchanges
+// providing it
+func applySettlements(t *breakeven.Tracker) {
+	positions := t.GetPositions()
+	earliest := make(map[string]*breakeven.Position)
+	for i := range positions {
+		p := &positions[i]
+		k := p.Exchange + "_" + p.Pair.String()
+		if e, ok := earliest[k]; !ok || p.Opened.Before(e.Opened) {
+			earliest[k] = p
+		}
+	}
+
+	for _, p := range earliest {
+		provider, ok := exchange.Underlying(GetExchangeByName(p.Exchange)).(exchange.SettlementProvider)
+		if !ok {
+			continue
+		}
+		settlements, err := provider.GetSettlementHistory(p.Pair, p.Opened)
+		if err != nil {
+			log.Errorf("Break-even tracker failed to get %s %s settlement history: %s",
+				p.Exchange, p.Pair, err)
+			continue
+		}
+		for i := range settlements {
+			pos, applied, err := t.ApplySettlement(&settlements[i])
+			if err != nil {
+				log.Errorf("Break-even tracker failed to apply %s %s settlement: %s",
+					p.Exchange, settlements[i].Contract, err)
+				continue
+			}
+			if applied {
+				handleSettlement(&settlements[i], &pos)
+			}
+		}
+	}
+}
+
+func handleSettlement(s *exchange.Settlement, p *breakeven.Position) {
+	action := "settled"
+	if s.Type == exchange.Delivered {
+		action = "delivered"
+	}
+	msg := fmt.Sprintf("%s %s position %s at %s on %s, size %v, realised PnL %s",
+		p.Exchange, p.AssetType, action, common.FormatPrice(s.Price),
+		s.Time.Format(time.RFC3339), p.Size, common.FormatAmount(p.RealisedPnL, p.CostCurrency().String()))
+	log.Debugln(msg)
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         string(s.Type),
+			TradeDetails: msg,
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(p, "breakeven", p.AssetType, p.Exchange)
+	}
+}
+
+// exitFeeRate returns the exchange's taker fee for a unit trade
+func exitFeeRate(exchName string, p currency.Pair, _ string) (float64, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return 0, ErrExchangeNotFound
+	}
+	return exch.GetFeeByType(&exchange.FeeBuilder{
+		FeeType:       exchange.CryptocurrencyTradeFee,
+		Pair:          p,
+		PurchasePrice: 1,
+		Amount:        1,
+	})
+}
+
+// markPrice returns the last stored ticker price
+func markPrice(exchName string, p currency.Pair, assetType string) (float64, error) {
+	t, err := ticker.GetTicker(exchName, p, assetType)
+	if err != nil {
+		return 0, err
+	}
+	return t.Last, nil
+}
+
+func handleBreakEvenCross(e breakeven.Event) {
+	p := &e.Position
+	direction := "below"
+	if e.Type == breakeven.AboveBreakEven {
+		direction = "above"
+	}
+	msg := fmt.Sprintf("%s %s %s position of %v moved %s its break-even price %s, mark price %s, net PnL %s",
+		p.Exchange, p.Pair, p.AssetType, p.Size, direction, common.FormatPrice(p.BreakEven),
+		common.FormatPrice(p.MarkPrice), common.FormatAmount(p.NetPnL, p.CostCurrency().String()))
+	log.Debugln(msg)
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: msg,
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e, "breakeven", p.AssetType, p.Exchange)
+	}
+}
+
+// recordDropCopyFill applies a mirrored order fill to the break-even tracker,
+// the fee of each fill is estimated from the exchange's fee rate
+func recordDropCopyFill(e *dropcopy.Event) {
+	if bot.breakEvenTracker == nil || e.Order == nil || e.Type != dropcopy.OrderFill {
+		return
+	}
+	err := RecordFill(&breakeven.Fill{
+		Exchange:  e.Exchange,
+		Pair:      e.Order.CurrencyPair,
+		AssetType: ticker.Spot,
+		Side:      e.Order.OrderSide,
+		Amount:    e.Filled,
+		Price:     e.Order.Price,
+		Fee:       -1,
+		Timestamp: e.Timestamp,
+	})
+	if err != nil {
+		log.Errorf("Break-even tracker failed to record %s fill: %s", e.Exchange, err)
+	}
+}
+
+// RecordFill applies a fill to its position's break-even price
+func RecordFill(f *breakeven.Fill) error {
+	if bot.breakEvenTracker == nil {
+		return errBreakEvenTrackerDisabled
+	}
+	return bot.breakEvenTracker.AddFill(f)
+}
+
+// GetBreakEvenPositions returns all tracked positions and their break-even
+// prices
+func GetBreakEvenPositions() ([]breakeven.Position, error) {
+	if bot.breakEvenTracker == nil {
+		return nil, errBreakEvenTrackerDisabled
+	}
+	return bot.breakEvenTracker.GetPositions(), nil
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Cancel confirmation event types
+const (
+	cancelFilledEvent      = "CANCEL_FAILED_ORDER_FILLED"
+	cancelUnconfirmedEvent = "CANCEL_UNCONFIRMED"
+)
+
+// ActivateCancelConfirmation wraps every loaded exchange so each cancel waits
+// for the order to reach a terminal state, retrying cancels which leave the
+// order open and reporting orders which filled before they were cancelled
+func ActivateCancelConfirmation() {
+	if bot.cancelTimeout <= 0 {
+		return
+	}
+
+	cfg := exchange.CancelConfig{
+		Timeout: bot.cancelTimeout,
+		Retries: bot.cancelRetries,
+	}
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		name := bot.exchanges[x].GetName()
+		bot.exchanges[x] = exchange.NewConfirmedCancel(bot.exchanges[x], cfg,
+			func(r exchange.CancelResult) {
+				handleCancelResult(name, r)
+			})
+	}
+	log.Debugf("Cancel confirmation enabled, waiting %s per attempt with %d retries.",
+		bot.cancelTimeout, bot.cancelRetries)
+}
+
+func handleCancelResult(exchName string, r exchange.CancelResult) {
+	var eventType string
+	switch r.Outcome {
+	case exchange.CancelOrderFilled:
+		eventType = cancelFilledEvent
+		log.Warnf("%s order %s filled before it could be cancelled.", exchName, r.OrderID)
+	case exchange.CancelUnconfirmed:
+		eventType = cancelUnconfirmedEvent
+		log.Errorf("%s order %s still open after %d cancel attempts, last status %q.",
+			exchName, r.OrderID, r.Attempts, r.Status)
+	default:
+		return
+	}
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         eventType,
+			TradeDetails: exchName + " order " + r.OrderID + " " + r.Outcome,
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(r, "cancel_confirmation", "", exchName)
+	}
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/candlestore"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// candleDatabase is the SQLite candle database in the data directory used
+// when no DSN is set
+const candleDatabase = "candles.db"
+
+var (
+	errCandleStoreDisabled = errors.New("candle store not enabled")
+	errCandleStoreNoDSN    = errors.New("candle store postgres DSN not set")
+)
+
+// ActivateCandleStore opens the candle database and starts syncing the
+// candles of every enabled pair at the -candlesintervals intervals
+func ActivateCandleStore() {
+	if bot.candleDriver == "" {
+		return
+	}
+
+	dsn := bot.candleDSN
+	if dsn == "" {
+		if bot.candleDriver != candlestore.SQLite {
+			log.Errorf("Candle store failed to open: %s", errCandleStoreNoDSN)
+			return
+		}
+		dsn = filepath.Join(bot.dataDir, candleDatabase)
+	}
+	intervals, err := parseCandleIntervals(bot.candleIntervals)
+	if err != nil {
+		log.Errorf("Candle store failed to parse intervals: %s", err)
+		return
+	}
+	store, err := candlestore.Open(bot.candleDriver, dsn)
+	if err != nil {
+		log.Errorf("Candle store failed to open: %s", err)
+		return
+	}
+	m, err := candlestore.New(store, GetLoadedExchanges(), candlestore.Config{
+		Intervals: intervals,
+		Backfill:  bot.candleBackfill,
+	})
+	if err != nil {
+		store.Close()
+		log.Errorf("Candle store failed to start: %s", err)
+		return
+	}
+	m.Start(bot.candleSyncInterval)
+	bot.candleStore = m
+	log.Debugf("Candle store enabled using %s, syncing every %s.", bot.candleDriver, bot.candleSyncInterval)
+}
+
+// parseCandleIntervals parses comma separated intervals such as 1m,1h,1d
+func parseCandleIntervals(s string) ([]kline.Interval, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var intervals []kline.Interval
+	for _, i := range strings.Split(s, ",") {
+		interval, err := kline.ParseInterval(i)
+		if err != nil {
+			return nil, err
+		}
+		intervals = append(intervals, interval)
+	}
+	return intervals, nil
+}
+
+// GetCandles returns the closed candles of a pair at the interval between
+// from and to from the candle store, downloading only those not yet stored
+func GetCandles(exchName string, p currency.Pair, interval kline.Interval, from, to time.Time) ([]exchange.Candle, error) {
+	if bot.candleStore == nil {
+		return nil, errCandleStoreDisabled
+	}
+	return bot.candleStore.GetCandles(exchName, p, interval, from, to)
+}
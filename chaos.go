@@ -0,0 +1,37 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/chaos"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errChaosDisabled = errors.New("chaos fault injection not enabled")
+
+// ActivateChaos enables fault injection into the exchange request and
+// websocket layers for resilience testing
+func ActivateChaos() {
+	if bot.chaosSettings == "" {
+		return
+	}
+
+	cfg, err := chaos.ParseConfig(bot.chaosSettings)
+	if err != nil {
+		log.Fatalf("Chaos fault injection failed to start: %s", err)
+	}
+	inj, err := chaos.New(cfg)
+	if err != nil {
+		log.Fatalf("Chaos fault injection failed to start: %s", err)
+	}
+	chaos.Enable(inj)
+}
+
+// GetChaosStats returns the faults injected so far
+func GetChaosStats() (chaos.Stats, error) {
+	inj := chaos.Active()
+	if inj == nil {
+		return chaos.Stats{}, errChaosDisabled
+	}
+	return inj.GetStats(), nil
+}
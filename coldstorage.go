@@ -0,0 +1,104 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/drawdown"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+	"github.com/thrasher-corp/gocryptotrader/portfolio"
+)
+
+var (
+	errInvalidColdStorageSpec = errors.New("cold storage addresses must be in the format COIN:ADDRESS")
+	errInvalidTargetWeights   = errors.New("target weights must be in the format COIN:PERCENT")
+)
+
+// ActivateColdStorage adds the configured cold wallet addresses to the
+// portfolio. The portfolio watcher fetches their balances from public
+// blockchain explorers and they are valued as non tradeable holdings
+func ActivateColdStorage() {
+	if bot.coldStorage == "" {
+		return
+	}
+
+	addresses, err := parseColdStorageAddresses(bot.coldStorage)
+	if err != nil {
+		log.Errorf("Cold storage failed to load: %s", err)
+		return
+	}
+	for i := range addresses {
+		bot.portfolio.AddColdStorageAddress(addresses[i].Address, addresses[i].CoinType)
+	}
+	log.Debugf("Cold storage tracking %d address(es).", len(addresses))
+}
+
+// parseColdStorageAddresses parses addresses in the format
+// BTC:1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy,ETH:0xb794f5ea0ba39494ce839613fffba74279579268
+func parseColdStorageAddresses(s string) ([]portfolio.Address, error) {
+	var addresses []portfolio.Address
+	for _, a := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(a), ":")
+		if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+			return nil, errInvalidColdStorageSpec
+		}
+		addresses = append(addresses, portfolio.Address{
+			Address:     parts[1],
+			CoinType:    currency.NewCode(parts[0]).Upper(),
+			Description: portfolio.PortfolioAddressColdStorage,
+		})
+	}
+	return addresses, nil
+}
+
+// parseTargetWeights parses target weights in the format BTC:40,USD:60
+func parseTargetWeights(s string) (map[currency.Code]float64, error) {
+	weights := make(map[currency.Code]float64)
+	for _, w := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(w), ":")
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errInvalidTargetWeights
+		}
+		percent, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, errInvalidTargetWeights
+		}
+		weights[currency.NewCode(parts[0]).Upper()] = percent / 100
+	}
+	return weights, nil
+}
+
+// portfolioPrice returns the price of a coin in the fiat display currency from
+// the first loaded exchange able to value it
+func portfolioPrice(c currency.Code) (float64, error) {
+	quote := bot.config.Currency.FiatDisplayCurrency
+	err := errors.New("no exchange can value " + c.String())
+	for _, exch := range GetLoadedExchanges() {
+		var price float64
+		price, err = drawdown.ValueInQuote(exch, c, quote)
+		if err == nil {
+			return price, nil
+		}
+	}
+	return 0, err
+}
+
+// GetPortfolioValuation returns the value of the portfolio in the fiat display
+// currency, including cold storage and other non tradeable holdings
+func GetPortfolioValuation() portfolio.Valuation {
+	return bot.portfolio.GetValuation(portfolioPrice)
+}
+
+// GetPortfolioRebalance returns the trades moving the portfolio to the target
+// weights in the format BTC:40,USD:60. Non tradeable holdings count towards
+// their coin's weight but are never sold
+func GetPortfolioRebalance(targets string) ([]portfolio.Adjustment, error) {
+	weights, err := parseTargetWeights(targets)
+	if err != nil {
+		return nil, err
+	}
+	v := GetPortfolioValuation()
+	return v.Rebalance(weights)
+}
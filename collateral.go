@@ -0,0 +1,129 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/collateral"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/drawdown"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okex"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errCollateralManagerDisabled = errors.New("collateral manager not enabled")
+
+// readOnlyVenue hides the transfer capabilities of a venue whose exchange is
+// read only or simulating requests in dry run mode
+type readOnlyVenue struct {
+	collateral.Venue
+}
+
+// ActivateCollateralManager starts valuing BitMEX and OKEX margin in the
+// display currency and recommending collateral moves between them. Moves are
+// only executed when enabled and each destination is approved against the
+// address book
+func ActivateCollateralManager() {
+	if !bot.collateral {
+		return
+	}
+
+	var venues []collateral.Venue
+	for _, exch := range GetLoadedExchanges() {
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		var v collateral.Venue
+		switch e := exchange.Underlying(exch).(type) {
+		case *bitmex.Bitmex:
+			v = &collateral.BitmexVenue{Exchange: e}
+		case *okex.OKEX:
+			v = &collateral.OKEXVenue{Exchange: e}
+		default:
+			continue
+		}
+		if exchange.IsReadOnly(exch) || bot.dryRun {
+			v = readOnlyVenue{v}
+		}
+		venues = append(venues, v)
+	}
+
+	m, err := collateral.NewManager(venues, collateral.Config{
+		Base:    bot.config.Currency.FiatDisplayCurrency,
+		Execute: bot.collateralExecute,
+		Network: checkTransferNetwork,
+	}, collateralPrice, approveCollateralMove, handleCollateralAlert)
+	if err != nil {
+		log.Errorf("Collateral manager failed to start: %s", err)
+		return
+	}
+	m.Start(collateral.DefaultCheckInterval)
+	bot.collateralManager = m
+	log.Debugf("Collateral manager enabled for %d venues.", len(venues))
+}
+
+// collateralPrice values a currency in the display currency using the first
+// loaded exchange with a stored ticker
+func collateralPrice(c currency.Code) (float64, error) {
+	quote := bot.config.Currency.FiatDisplayCurrency
+	var err error
+	for _, exch := range GetLoadedExchanges() {
+		var price float64
+		price, err = drawdown.ValueInQuote(exch, c, quote)
+		if err == nil {
+			return price, nil
+		}
+	}
+	return 0, err
+}
+
+// approveCollateralMove approves a collateral move destination against the
+// address book
+func approveCollateralMove(exchName string, c currency.Code, amount float64, address string) error {
+	if bot.addressBook == nil {
+		return errAddressBookNotLoaded
+	}
+	_, err := bot.addressBook.Approve(exchName, &exchange.WithdrawRequest{
+		Currency: c,
+		Amount:   amount,
+		Address:  address,
+	})
+	return err
+}
+
+func handleCollateralAlert(a collateral.Alert) {
+	log.Warnf("Collateral alert: %s", a.String())
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "COLLATERAL_ALERT",
+			TradeDetails: a.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		exch := a.Status.Exchange
+		if a.Move != nil {
+			exch = a.Move.To
+		}
+		relayWebsocketEvent(a, "collateral_alert", "", exch)
+	}
+}
+
+// GetCollateralReport returns the margin utilization of each venue and the
+// recommended and executed collateral moves
+func GetCollateralReport() (collateral.Report, error) {
+	if bot.collateralManager == nil {
+		return collateral.Report{}, errCollateralManagerDisabled
+	}
+	return bot.collateralManager.GetReport(), nil
+}
+
+// ExecuteCollateralMove executes a recommended collateral move
+func ExecuteCollateralMove(id int64) error {
+	if bot.collateralManager == nil {
+		return errCollateralManagerDisabled
+	}
+	return bot.collateralManager.Execute(id)
+}
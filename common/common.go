@@ -552,11 +552,99 @@ func RecvWindow(d time.Duration) int64 {
 	return int64(d) / int64(time.Millisecond)
 }
 
+// NumberFormat flags relax number parsing for exchange feeds which send
+// quirky values, the zero value parses strictly
+type NumberFormat uint8
+
+// Number parsing relaxations
+const (
+	// NumberTrimSpace ignores leading and trailing whitespace
+	NumberTrimSpace NumberFormat = 1 << iota
+	// NumberEmptyAsZero parses empty strings as zero
+	NumberEmptyAsZero
+	// NumberThousands allows comma thousand separators such as 1,234.5
+	NumberThousands
+	// NumberScientific allows scientific notation such as 1e3 for integers,
+	// floats always allow it
+	NumberScientific
+	// NumberNonString allows numbers decoded from JSON as float64 or
+	// json.Number rather than strings
+	NumberNonString
+	// NumberTolerant enables every relaxation
+	NumberTolerant = NumberTrimSpace | NumberEmptyAsZero | NumberThousands |
+		NumberScientific | NumberNonString
+)
+
+var errInvalidThousands = errors.New("invalid thousand separators")
+
+// numberString returns the raw value as a string ready for strconv parsing
+func numberString(raw interface{}, format NumberFormat) (string, error) {
+	var str string
+	switch v := raw.(type) {
+	case string:
+		str = v
+	case json.Number:
+		if format&NumberNonString == 0 {
+			return "", fmt.Errorf("unable to parse, value not string: %T", raw)
+		}
+		str = v.String()
+	case float64:
+		if format&NumberNonString == 0 {
+			return "", fmt.Errorf("unable to parse, value not string: %T", raw)
+		}
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return "", fmt.Errorf("unable to parse, value not finite: %v", v)
+		}
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	default:
+		return "", fmt.Errorf("unable to parse, value not string: %T", raw)
+	}
+
+	if format&NumberTrimSpace != 0 {
+		str = strings.TrimSpace(str)
+	}
+	if str == "" && format&NumberEmptyAsZero != 0 {
+		return "0", nil
+	}
+	if format&NumberThousands != 0 && strings.Contains(str, ",") {
+		return stripThousands(str)
+	}
+	return str, nil
+}
+
+// stripThousands removes comma thousand separators, rejecting commas which
+// do not separate groups of three digits so decimal commas are not misread
+func stripThousands(str string) (string, error) {
+	number, fraction := str, ""
+	if i := strings.IndexByte(str, '.'); i >= 0 {
+		number, fraction = str[:i], str[i:]
+	}
+	if strings.Contains(fraction, ",") {
+		return "", fmt.Errorf("%s %v", str, errInvalidThousands)
+	}
+	sign := ""
+	if number != "" && (number[0] == '-' || number[0] == '+') {
+		sign, number = number[:1], number[1:]
+	}
+	groups := strings.Split(number, ",")
+	for i := range groups {
+		if groups[i] == "" || len(groups[i]) > 3 || (i > 0 && len(groups[i]) != 3) {
+			return "", fmt.Errorf("%s %v", str, errInvalidThousands)
+		}
+	}
+	return sign + strings.Join(groups, "") + fraction, nil
+}
+
 // FloatFromString format
 func FloatFromString(raw interface{}) (float64, error) {
-	str, ok := raw.(string)
-	if !ok {
-		return 0, fmt.Errorf("unable to parse, value not string: %T", raw)
+	return FloatFromStringFormat(raw, 0)
+}
+
+// FloatFromStringFormat parses a float with the format's relaxations
+func FloatFromStringFormat(raw interface{}, format NumberFormat) (float64, error) {
+	str, err := numberString(raw, format)
+	if err != nil {
+		return 0, err
 	}
 	flt, err := strconv.ParseFloat(str, 64)
 	if err != nil {
@@ -567,30 +655,58 @@ func FloatFromString(raw interface{}) (float64, error) {
 
 // IntFromString format
 func IntFromString(raw interface{}) (int, error) {
-	str, ok := raw.(string)
-	if !ok {
-		return 0, fmt.Errorf("unable to parse, value not string: %T", raw)
+	return IntFromStringFormat(raw, 0)
+}
+
+// IntFromStringFormat parses an int with the format's relaxations
+func IntFromStringFormat(raw interface{}, format NumberFormat) (int, error) {
+	str, err := numberString(raw, format)
+	if err != nil {
+		return 0, err
 	}
-	n, err := strconv.Atoi(str)
+	n, err := parseInt(str, format, strconv.IntSize)
 	if err != nil {
 		return 0, fmt.Errorf("unable to parse as int: %T", raw)
 	}
-	return n, nil
+	return int(n), nil
 }
 
 // Int64FromString format
 func Int64FromString(raw interface{}) (int64, error) {
-	str, ok := raw.(string)
-	if !ok {
-		return 0, fmt.Errorf("unable to parse, value not string: %T", raw)
+	return Int64FromStringFormat(raw, 0)
+}
+
+// Int64FromStringFormat parses an int64 with the format's relaxations
+func Int64FromStringFormat(raw interface{}, format NumberFormat) (int64, error) {
+	str, err := numberString(raw, format)
+	if err != nil {
+		return 0, err
 	}
-	n, err := strconv.ParseInt(str, 10, 64)
+	n, err := parseInt(str, format, 64)
 	if err != nil {
 		return 0, fmt.Errorf("unable to parse as int64: %T", raw)
 	}
 	return n, nil
 }
 
+// parseInt parses an integer of the bit size, values in scientific notation
+// are accepted when the format allows and they are whole numbers
+func parseInt(str string, format NumberFormat, bitSize int) (int64, error) {
+	n, err := strconv.ParseInt(str, 10, bitSize)
+	if err == nil || format&NumberScientific == 0 || !strings.ContainsAny(str, "eE") {
+		return n, err
+	}
+	flt, fErr := strconv.ParseFloat(str, 64)
+	if fErr != nil || flt != math.Trunc(flt) {
+		return 0, err
+	}
+	limit := math.Ldexp(1, bitSize-1)
+	if flt >= limit || flt < -limit {
+		return 0, err
+	}
+	return int64(flt), nil
+}
+
 // TimeFromUnixTimestampFloat format
 func TimeFromUnixTimestampFloat(raw interface{}) (time.Time, error) {
 	ts, ok := raw.(float64)
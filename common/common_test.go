@@ -2,14 +2,18 @@ package common
 
 import (
 	"bytes"
+	"encoding/json"
+	"math"
 	"net/url"
 	"os"
 	"os/user"
 	"path/filepath"
 	"reflect"
 	"runtime"
+	"strconv"
 	"strings"
 	"testing"
+	"testing/quick"
 	"time"
 )
 
@@ -937,6 +941,119 @@ func TestInt64FromString(t *testing.T) {
 	}
 }
 
+func TestFloatFromStringFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		raw      interface{}
+		format   NumberFormat
+		expected float64
+		valid    bool
+	}{
+		{"1.5", 0, 1.5, true},
+		{"1.5e-7", 0, 1.5e-7, true},
+		{"", 0, 0, false},
+		{"", NumberEmptyAsZero, 0, true},
+		{" 2.5 ", 0, 0, false},
+		{" 2.5 ", NumberTrimSpace, 2.5, true},
+		{"  ", NumberTrimSpace | NumberEmptyAsZero, 0, true},
+		{"1,234,567.89", 0, 0, false},
+		{"1,234,567.89", NumberThousands, 1234567.89, true},
+		{"-12,345", NumberThousands, -12345, true},
+		{"1,2345", NumberThousands, 0, false},
+		{"1234,567", NumberThousands, 0, false},
+		{",123", NumberThousands, 0, false},
+		{"1,5", NumberThousands, 0, false},
+		{"1.234,5", NumberThousands, 0, false},
+		{float64(3.25), 0, 0, false},
+		{float64(3.25), NumberNonString, 3.25, true},
+		{json.Number("4.75"), NumberNonString, 4.75, true},
+		{math.NaN(), NumberNonString, 0, false},
+		{[]byte("1"), NumberTolerant, 0, false},
+		{" 1,000.5E2 ", NumberTolerant, 100050, true},
+	}
+	for i := range tests {
+		actual, err := FloatFromStringFormat(tests[i].raw, tests[i].format)
+		if (err == nil) != tests[i].valid || actual != tests[i].expected {
+			t.Errorf("Test failed. Common FloatFromStringFormat %v format %d. Expected '%v' valid %v. Actual '%v' Error: %v",
+				tests[i].raw, tests[i].format, tests[i].expected, tests[i].valid, actual, err)
+		}
+	}
+}
+
+func TestInt64FromStringFormat(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		raw      interface{}
+		format   NumberFormat
+		expected int64
+		valid    bool
+	}{
+		{"1337", 0, 1337, true},
+		{"1e3", 0, 0, false},
+		{"1e3", NumberScientific, 1000, true},
+		{"1.5E3", NumberScientific, 1500, true},
+		{"1.5e0", NumberScientific, 0, false},
+		{"1e19", NumberScientific, 0, false},
+		{"-9.2e18", NumberScientific, -9200000000000000000, true},
+		{"", NumberEmptyAsZero, 0, true},
+		{"1,000,000", NumberThousands, 1000000, true},
+		{float64(42), NumberNonString, 42, true},
+		{float64(42.5), NumberNonString, 0, false},
+		{json.Number("7"), NumberNonString, 7, true},
+		{" 2,500 ", NumberTolerant, 2500, true},
+	}
+	for i := range tests {
+		actual, err := Int64FromStringFormat(tests[i].raw, tests[i].format)
+		if (err == nil) != tests[i].valid || actual != tests[i].expected {
+			t.Errorf("Test failed. Common Int64FromStringFormat %v format %d. Expected '%v' valid %v. Actual '%v' Error: %v",
+				tests[i].raw, tests[i].format, tests[i].expected, tests[i].valid, actual, err)
+		}
+	}
+
+	n, err := IntFromStringFormat("2e2", NumberScientific)
+	if n != 200 || err != nil {
+		t.Errorf("Test failed. Common IntFromStringFormat. Expected '200'. Actual '%v'. Error: %v", n, err)
+	}
+}
+
+// TestNumberFormatFuzz checks random values survive formatting with thousand
+// separators and padding, and that arbitrary strings never panic
+func TestNumberFormatFuzz(t *testing.T) {
+	t.Parallel()
+	roundTrip := func(n int64, frac uint16) bool {
+		str := strconv.FormatInt(n, 10)
+		sign := ""
+		if n < 0 {
+			sign, str = "-", str[1:]
+		}
+		for i := len(str) - 3; i > 0; i -= 3 {
+			str = str[:i] + "," + str[i:]
+		}
+		str = " " + sign + str + "." + strconv.Itoa(int(frac)) + " "
+
+		expected, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(str), ",", "", -1), 64)
+		if err != nil {
+			return false
+		}
+		actual, err := FloatFromStringFormat(str, NumberTolerant)
+		return err == nil && actual == expected
+	}
+	if err := quick.Check(roundTrip, nil); err != nil {
+		t.Errorf("Test failed. Common FloatFromStringFormat round trip. Error: %v", err)
+	}
+
+	arbitrary := func(s string) bool {
+		for _, format := range []NumberFormat{0, NumberThousands, NumberTolerant} {
+			_, _ = FloatFromStringFormat(s, format)
+			_, _ = Int64FromStringFormat(s, format)
+		}
+		return true
+	}
+	if err := quick.Check(arbitrary, &quick.Config{MaxCount: 1000}); err != nil {
+		t.Errorf("Test failed. Common number format arbitrary input. Error: %v", err)
+	}
+}
+
 func TestTimeFromUnixTimestampFloat(t *testing.T) {
 	t.Parallel()
 	testTimestamp := float64(1414456320000)
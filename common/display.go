@@ -0,0 +1,257 @@
+package common
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Display precision defaults
+const (
+	// DefaultDisplayPrecision is the decimal places shown for amounts of
+	// currencies without a display precision, down to the satoshi
+	DefaultDisplayPrecision = 8
+	// DefaultPriceDigits is the significant digits shown for prices
+	DefaultPriceDigits = 8
+	// maxPriceDecimals limits the decimals shown for tiny prices
+	maxPriceDecimals = 12
+)
+
+// DefaultCurrencyPrecision holds the decimal places shown for amounts of
+// fiat currencies and stablecoins, keyed by upper case currency code
+var DefaultCurrencyPrecision = map[string]int{
+	"AUD":  2,
+	"CAD":  2,
+	"CHF":  2,
+	"CNY":  2,
+	"EUR":  2,
+	"GBP":  2,
+	"HKD":  2,
+	"JPY":  0,
+	"KRW":  0,
+	"NZD":  2,
+	"RUB":  2,
+	"SGD":  2,
+	"USD":  2,
+	"DAI":  2,
+	"PAX":  2,
+	"TUSD": 2,
+	"USDC": 2,
+	"USDT": 2,
+}
+
+// NumberLocale defines the separators numbers are displayed with
+type NumberLocale struct {
+	Thousands string
+	Decimal   string
+}
+
+// Number locales
+var (
+	// LocaleEnglish displays numbers as 1,234.56
+	LocaleEnglish = NumberLocale{Thousands: ",", Decimal: "."}
+	// LocaleEuropean displays numbers as 1.234,56
+	LocaleEuropean = NumberLocale{Thousands: ".", Decimal: ","}
+	// LocaleFrench displays numbers as 1 234,56
+	LocaleFrench = NumberLocale{Thousands: " ", Decimal: ","}
+	// LocaleSwiss displays numbers as 1'234.56
+	LocaleSwiss = NumberLocale{Thousands: "'", Decimal: "."}
+	// LocalePlain displays numbers without thousand separators as 1234.56
+	LocalePlain = NumberLocale{Decimal: "."}
+)
+
+var errUnknownLocale = errors.New("unknown number locale")
+
+// languageLocales maps language codes to the separators they use
+var languageLocales = map[string]NumberLocale{
+	"en": LocaleEnglish,
+	"ja": LocaleEnglish,
+	"ko": LocaleEnglish,
+	"zh": LocaleEnglish,
+	"da": LocaleEuropean,
+	"de": LocaleEuropean,
+	"es": LocaleEuropean,
+	"id": LocaleEuropean,
+	"it": LocaleEuropean,
+	"nl": LocaleEuropean,
+	"pt": LocaleEuropean,
+	"tr": LocaleEuropean,
+	"cs": LocaleFrench,
+	"fi": LocaleFrench,
+	"fr": LocaleFrench,
+	"no": LocaleFrench,
+	"pl": LocaleFrench,
+	"ru": LocaleFrench,
+	"sv": LocaleFrench,
+	"uk": LocaleFrench,
+}
+
+// ParseNumberLocale returns the number locale of a language tag such as en,
+// de-DE or fr_FR, or plain for no thousand separators. Empty tags return
+// LocaleEnglish
+func ParseNumberLocale(tag string) (NumberLocale, error) {
+	tag = strings.Replace(strings.ToLower(strings.TrimSpace(tag)), "_", "-", -1)
+	switch tag {
+	case "":
+		return LocaleEnglish, nil
+	case "plain":
+		return LocalePlain, nil
+	case "de-ch", "it-ch", "fr-ch":
+		return LocaleSwiss, nil
+	}
+	if l, ok := languageLocales[strings.SplitN(tag, "-", 2)[0]]; ok {
+		return l, nil
+	}
+	return NumberLocale{}, errors.New(tag + " " + errUnknownLocale.Error())
+}
+
+// FormatDecimal rounds the value to the decimal places and separates its
+// digits using the locale. Negative precisions show the fewest decimals which
+// represent the value exactly
+func FormatDecimal(v float64, precision int, locale NumberLocale) string {
+	return formatDecimal(v, precision, locale, false)
+}
+
+// formatDecimal formats the value, trimming trailing fractional zeros if set
+func formatDecimal(v float64, precision int, locale NumberLocale, trim bool) string {
+	if math.IsNaN(v) || math.IsInf(v, 0) {
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	}
+	s := strconv.FormatFloat(v, 'f', precision, 64)
+	negative := strings.HasPrefix(s, "-")
+	s = strings.TrimPrefix(s, "-")
+
+	integer, fraction := s, ""
+	if i := strings.IndexByte(s, '.'); i >= 0 {
+		integer, fraction = s[:i], s[i+1:]
+	}
+	if trim {
+		fraction = strings.TrimRight(fraction, "0")
+	}
+	if strings.Trim(integer+fraction, "0") == "" {
+		// rounding to zero should not display as -0
+		negative = false
+	}
+
+	var b strings.Builder
+	if negative {
+		b.WriteByte('-')
+	}
+	for i := range integer {
+		if i > 0 && (len(integer)-i)%3 == 0 {
+			b.WriteString(locale.Thousands)
+		}
+		b.WriteByte(integer[i])
+	}
+	if fraction != "" {
+		if locale.Decimal == "" {
+			b.WriteByte('.')
+		} else {
+			b.WriteString(locale.Decimal)
+		}
+		b.WriteString(fraction)
+	}
+	return b.String()
+}
+
+// DisplayFormat defines how amounts and prices are displayed in reports,
+// notifications and logs. Values are only rounded when displayed, they are
+// kept at full precision everywhere else
+type DisplayFormat struct {
+	Locale NumberLocale
+	// Precision overrides the decimal places shown for amounts of a currency,
+	// keyed by upper case currency code
+	Precision map[string]int
+	// DefaultPrecision is shown for currencies without a precision,
+	// DefaultDisplayPrecision when zero
+	DefaultPrecision int
+	// PriceDigits is the significant digits shown for prices,
+	// DefaultPriceDigits when zero
+	PriceDigits int
+}
+
+// CurrencyPrecision returns the decimal places shown for amounts of the
+// currency
+func (f *DisplayFormat) CurrencyPrecision(code string) int {
+	code = strings.ToUpper(code)
+	if p, ok := f.Precision[code]; ok {
+		return p
+	}
+	if p, ok := DefaultCurrencyPrecision[code]; ok {
+		return p
+	}
+	if f.DefaultPrecision > 0 {
+		return f.DefaultPrecision
+	}
+	return DefaultDisplayPrecision
+}
+
+// Amount formats an amount of the currency to its precision
+func (f *DisplayFormat) Amount(v float64, code string) string {
+	return FormatDecimal(v, f.CurrencyPrecision(code), f.Locale)
+}
+
+// Price formats a price to the significant digits, showing more decimals the
+// lower the price. Trailing zeros are trimmed
+func (f *DisplayFormat) Price(v float64) string {
+	digits := f.PriceDigits
+	if digits <= 0 {
+		digits = DefaultPriceDigits
+	}
+	decimals := 0
+	if v != 0 && !math.IsNaN(v) && !math.IsInf(v, 0) {
+		decimals = digits - 1 - int(math.Floor(math.Log10(math.Abs(v))))
+	}
+	if decimals < 0 {
+		decimals = 0
+	} else if decimals > maxPriceDecimals {
+		decimals = maxPriceDecimals
+	}
+	return formatDecimal(v, decimals, f.Locale, true)
+}
+
+// Percent formats a percentage to two decimal places
+func (f *DisplayFormat) Percent(v float64) string {
+	return FormatDecimal(v, 2, f.Locale) + "%"
+}
+
+var (
+	display    = DisplayFormat{Locale: LocaleEnglish}
+	displayMtx sync.RWMutex
+)
+
+// SetDisplayFormat sets the format used by FormatAmount, FormatPrice and
+// FormatPercent
+func SetDisplayFormat(f DisplayFormat) {
+	displayMtx.Lock()
+	display = f
+	displayMtx.Unlock()
+}
+
+// GetDisplayFormat returns the format used by FormatAmount, FormatPrice and
+// FormatPercent
+func GetDisplayFormat() DisplayFormat {
+	displayMtx.RLock()
+	defer displayMtx.RUnlock()
+	return display
+}
+
+// FormatAmount formats an amount of the currency using the display format
+func FormatAmount(v float64, code string) string {
+	f := GetDisplayFormat()
+	return f.Amount(v, code)
+}
+
+// FormatPrice formats a price using the display format
+func FormatPrice(v float64) string {
+	f := GetDisplayFormat()
+	return f.Price(v)
+}
+
+// FormatPercent formats a percentage using the display format
+func FormatPercent(v float64) string {
+	f := GetDisplayFormat()
+	return f.Percent(v)
+}
@@ -0,0 +1,106 @@
+package common
+
+import (
+	"math"
+	"testing"
+)
+
+func TestFormatDecimal(t *testing.T) {
+	t.Parallel()
+	tests := []struct {
+		value     float64
+		precision int
+		locale    NumberLocale
+		expected  string
+	}{
+		{66.66666666666666, 2, LocaleEnglish, "66.67"},
+		{1234567.891, 2, LocaleEnglish, "1,234,567.89"},
+		{1234567.891, 2, LocaleEuropean, "1.234.567,89"},
+		{1234567.891, 2, LocaleFrench, "1 234 567,89"},
+		{1234567.891, 2, LocaleSwiss, "1'234'567.89"},
+		{1234567.891, 2, LocalePlain, "1234567.89"},
+		{-1234.5, 0, LocaleEnglish, "-1,234"},
+		{-0.001, 2, LocaleEnglish, "0.00"},
+		{123.45, -1, LocaleEnglish, "123.45"},
+		{1.5, 3, NumberLocale{}, "1.500"},
+		{math.Inf(1), 2, LocaleEnglish, "+Inf"},
+	}
+	for i := range tests {
+		actual := FormatDecimal(tests[i].value, tests[i].precision, tests[i].locale)
+		if actual != tests[i].expected {
+			t.Errorf("Test failed. FormatDecimal %v precision %d. Expected '%s'. Actual '%s'",
+				tests[i].value, tests[i].precision, tests[i].expected, actual)
+		}
+	}
+}
+
+func TestParseNumberLocale(t *testing.T) {
+	t.Parallel()
+	tests := map[string]NumberLocale{
+		"":      LocaleEnglish,
+		"en-US": LocaleEnglish,
+		"de_DE": LocaleEuropean,
+		"fr":    LocaleFrench,
+		"de-CH": LocaleSwiss,
+		"plain": LocalePlain,
+	}
+	for tag, expected := range tests {
+		actual, err := ParseNumberLocale(tag)
+		if err != nil || actual != expected {
+			t.Errorf("Test failed. ParseNumberLocale %s. Expected %+v. Actual %+v Error: %v",
+				tag, expected, actual, err)
+		}
+	}
+	if _, err := ParseNumberLocale("xx"); err == nil {
+		t.Error("Test failed. ParseNumberLocale expected unknown locale error")
+	}
+}
+
+func TestDisplayFormat(t *testing.T) {
+	f := DisplayFormat{Locale: LocaleEnglish, Precision: map[string]int{"BTC": 4}}
+	amounts := []struct {
+		value    float64
+		code     string
+		expected string
+	}{
+		{1234.5678, "usd", "1,234.57"},
+		{1234.5678, "JPY", "1,235"},
+		{0.123456789, "BTC", "0.1235"},
+		{0.123456789, "ETH", "0.12345679"},
+	}
+	for i := range amounts {
+		if actual := f.Amount(amounts[i].value, amounts[i].code); actual != amounts[i].expected {
+			t.Errorf("Test failed. DisplayFormat Amount %v %s. Expected '%s'. Actual '%s'",
+				amounts[i].value, amounts[i].code, amounts[i].expected, actual)
+		}
+	}
+
+	prices := map[float64]string{
+		9123.456789:  "9,123.4568",
+		0.0000123456: "0.0000123456",
+		120000000.7:  "120,000,001",
+		100:          "100",
+		0:            "0",
+	}
+	for value, expected := range prices {
+		if actual := f.Price(value); actual != expected {
+			t.Errorf("Test failed. DisplayFormat Price %v. Expected '%s'. Actual '%s'", value, expected, actual)
+		}
+	}
+	if actual := f.Percent(-12.3456); actual != "-12.35%" {
+		t.Errorf("Test failed. DisplayFormat Percent. Expected '-12.35%%'. Actual '%s'", actual)
+	}
+
+	previous := GetDisplayFormat()
+	defer SetDisplayFormat(previous)
+	SetDisplayFormat(DisplayFormat{Locale: LocaleEuropean})
+	if actual := FormatAmount(1234.5, "EUR"); actual != "1.234,50" {
+		t.Errorf("Test failed. FormatAmount. Expected '1.234,50'. Actual '%s'", actual)
+	}
+	if actual := FormatPrice(0.5); actual != "0,5" {
+		t.Errorf("Test failed. FormatPrice. Expected '0,5'. Actual '%s'", actual)
+	}
+	if actual := FormatPercent(5); actual != "5,00%" {
+		t.Errorf("Test failed. FormatPercent. Expected '5,00%%'. Actual '%s'", actual)
+	}
+}
@@ -2,6 +2,7 @@ package base
 
 import (
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
@@ -24,6 +25,7 @@ var (
 // medium
 type Orderbook struct {
 	CurrencyPair string
+	Quote        string
 	AssetType    string
 	TotalAsks    float64
 	TotalBids    float64
@@ -58,11 +60,41 @@ type Base struct {
 	Connected bool
 }
 
+// Event severities in escalating order
+const (
+	SeverityInfo     = "info"
+	SeverityWarning  = "warning"
+	SeverityCritical = "critical"
+)
+
 // Event is a generalise event type
 type Event struct {
 	Type         string
 	GainLoss     string
 	TradeDetails string
+	// Severity is inferred from the event type when not set
+	Severity string
+}
+
+// GetSeverity returns the event severity. Events without one are critical
+// when their type names a failure, breaker or anomaly and warnings when it
+// names an alert, cancellation or withdrawal
+func (e *Event) GetSeverity() string {
+	if e.Severity != "" {
+		return e.Severity
+	}
+	t := strings.ToUpper(e.Type)
+	for _, s := range []string{"FAIL", "ERROR", "BREAKER", "TRIPPED", "ANOMALY", "LOCKDOWN"} {
+		if strings.Contains(t, s) {
+			return SeverityCritical
+		}
+	}
+	for _, s := range []string{"ALERT", "WARN", "CANCEL", "WITHDRAW", "DIVERGE"} {
+		if strings.Contains(t, s) {
+			return SeverityWarning
+		}
+	}
+	return SeverityInfo
 }
 
 // IsEnabled returns if the comms package has been enabled in the configuration
@@ -101,15 +133,15 @@ func (b *Base) GetTicker(exchangeName string) string {
 	var packagedTickers []string
 	for i := range tickerPrices {
 		packagedTickers = append(packagedTickers, fmt.Sprintf(
-			"Currency Pair: %s Ask: %f, Bid: %f High: %f Last: %f Low: %f ATH: %f Volume: %f",
+			"Currency Pair: %s Ask: %s, Bid: %s High: %s Last: %s Low: %s ATH: %s Volume: %s",
 			tickerPrices[i].Pair,
-			tickerPrices[i].Ask,
-			tickerPrices[i].Bid,
-			tickerPrices[i].High,
-			tickerPrices[i].Last,
-			tickerPrices[i].Low,
-			tickerPrices[i].PriceATH,
-			tickerPrices[i].Volume))
+			common.FormatPrice(tickerPrices[i].Ask),
+			common.FormatPrice(tickerPrices[i].Bid),
+			common.FormatPrice(tickerPrices[i].High),
+			common.FormatPrice(tickerPrices[i].Last),
+			common.FormatPrice(tickerPrices[i].Low),
+			common.FormatPrice(tickerPrices[i].PriceATH),
+			common.FormatAmount(tickerPrices[i].Volume, tickerPrices[i].Pair.Base.String())))
 	}
 	return common.JoinStrings(packagedTickers, "\n")
 }
@@ -134,12 +166,12 @@ func (b *Base) GetOrderbook(exchangeName string) string {
 	var packagedOrderbooks []string
 	for i := range orderbooks {
 		packagedOrderbooks = append(packagedOrderbooks, fmt.Sprintf(
-			"Currency Pair: %s AssetType: %s, LastUpdated: %s TotalAsks: %f TotalBids: %f",
+			"Currency Pair: %s AssetType: %s, LastUpdated: %s TotalAsks: %s TotalBids: %s",
 			orderbooks[i].CurrencyPair,
 			orderbooks[i].AssetType,
 			orderbooks[i].LastUpdated,
-			orderbooks[i].TotalAsks,
-			orderbooks[i].TotalBids))
+			common.FormatAmount(orderbooks[i].TotalAsks, orderbooks[i].Quote),
+			common.FormatAmount(orderbooks[i].TotalBids, orderbooks[i].Quote)))
 	}
 	return common.JoinStrings(packagedOrderbooks, "\n")
 }
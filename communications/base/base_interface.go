@@ -103,6 +103,7 @@ func (c IComm) StageOrderbookData(exchangeName, assetType string, ob *orderbook.
 
 	OrderbookStaged[exchangeName][assetType][ob.Pair.String()] = Orderbook{
 		CurrencyPair: ob.Pair.String(),
+		Quote:        ob.Pair.Quote.String(),
 		TotalAsks:    totalAsks,
 		TotalBids:    totalBids}
 }
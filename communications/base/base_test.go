@@ -167,6 +167,26 @@ func TestPushEvent(t *testing.T) {
 	}
 }
 
+func TestEventSeverity(t *testing.T) {
+	tests := []struct {
+		event    Event
+		severity string
+	}{
+		{Event{Type: "EXCHANGE_BREAKER_TRIPPED"}, SeverityCritical},
+		{Event{Type: "BRACKET_FAILED"}, SeverityCritical},
+		{Event{Type: "COLLATERAL_ALERT"}, SeverityWarning},
+		{Event{Type: "ORDERS_CANCELLED"}, SeverityWarning},
+		{Event{Type: "WARMUP_READY"}, SeverityInfo},
+		{Event{Type: "WARMUP_READY", Severity: SeverityCritical}, SeverityCritical},
+	}
+	for x := range tests {
+		if s := tests[x].event.GetSeverity(); s != tests[x].severity {
+			t.Errorf("Test Failed - GetSeverity() %s expected %s, got %s",
+				tests[x].event.Type, tests[x].severity, s)
+		}
+	}
+}
+
 func TestStageTickerData(t *testing.T) {
 	_, ok := TickerStaged["bitstamp"]["someAsset"]["BTCUSD"]
 	if ok {
@@ -0,0 +1,179 @@
+package smtpservice
+
+import (
+	"fmt"
+	"html"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Delivery modes
+const (
+	Immediate = "immediate"
+	Digest    = "digest"
+	None      = "none"
+)
+
+// DefaultDigestTime is the UTC time of day the digest is sent at when unset
+const DefaultDigestTime = "00:00"
+
+// DefaultSeverities emails critical events as they happen and collects the
+// rest into the daily digest
+var DefaultSeverities = map[string]string{
+	base.SeverityCritical: Immediate,
+	base.SeverityWarning:  Digest,
+	base.SeverityInfo:     Digest,
+}
+
+// Digest sections in the order they are listed
+const (
+	sectionFills    = "Fills"
+	sectionPnL      = "PnL"
+	sectionBalances = "Balance changes"
+	sectionErrors   = "Errors"
+	sectionOther    = "Other"
+)
+
+var sections = []string{sectionFills, sectionPnL, sectionBalances, sectionErrors, sectionOther}
+
+type digestEvent struct {
+	base.Event
+	time time.Time
+}
+
+// section returns the digest section an event is listed under
+func section(e *base.Event) string {
+	t := strings.ToUpper(e.Type)
+	contains := func(subs ...string) bool {
+		for i := range subs {
+			if strings.Contains(t, subs[i]) {
+				return true
+			}
+		}
+		return false
+	}
+	switch {
+	case e.GetSeverity() == base.SeverityCritical:
+		return sectionErrors
+	case e.GainLoss != "" || contains("PNL", "BREAKEVEN", "YIELD"):
+		return sectionPnL
+	case contains("FILL", "ORDER", "BRACKET", "TRADE"):
+		return sectionFills
+	case contains("DEPOSIT", "WITHDRAW", "BALANCE", "COLLATERAL", "TRANSFER"):
+		return sectionBalances
+	}
+	return sectionOther
+}
+
+func eventText(e *base.Event) string {
+	text := e.Type
+	if e.TradeDetails != "" {
+		text += ": " + e.TradeDetails
+	}
+	if e.GainLoss != "" {
+		text += " (" + e.GainLoss + ")"
+	}
+	return text
+}
+
+// mode returns the delivery mode of a severity
+func (s *SMTPservice) mode(severity string) string {
+	if m, ok := s.Severities[severity]; ok {
+		return m
+	}
+	return DefaultSeverities[severity]
+}
+
+func (s *SMTPservice) queue(e base.Event) {
+	s.mtx.Lock()
+	s.digest = append(s.digest, digestEvent{Event: e, time: time.Now()})
+	s.mtx.Unlock()
+}
+
+// nextDigest returns the next time the digest is due after now
+func nextDigest(now time.Time, at string) time.Time {
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		t, _ = time.Parse("15:04", DefaultDigestTime)
+	}
+	now = now.UTC()
+	next := time.Date(now.Year(), now.Month(), now.Day(), t.Hour(), t.Minute(), 0, 0, time.UTC)
+	if !next.After(now) {
+		next = next.AddDate(0, 0, 1)
+	}
+	return next
+}
+
+func (s *SMTPservice) startDigest() {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.digestStarted {
+		return
+	}
+	s.digestStarted = true
+	go func() {
+		for {
+			time.Sleep(time.Until(nextDigest(time.Now(), s.DigestTime)))
+			if err := s.SendDigest(); err != nil {
+				log.Errorf("SMTPservice failed to send digest: %s", err)
+			}
+		}
+	}()
+}
+
+// Digest returns the queued events grouped into fills, PnL, balance changes,
+// errors and other events. The queue is left untouched
+func (s *SMTPservice) Digest() string {
+	s.mtx.Lock()
+	events := append([]digestEvent(nil), s.digest...)
+	s.mtx.Unlock()
+	return formatDigest(events)
+}
+
+func formatDigest(events []digestEvent) string {
+	if len(events) == 0 {
+		return ""
+	}
+	grouped := make(map[string][]string)
+	for i := range events {
+		sec := section(&events[i].Event)
+		grouped[sec] = append(grouped[sec], fmt.Sprintf("  %s [%s] %s",
+			events[i].time.UTC().Format("2006-01-02 15:04:05"),
+			events[i].GetSeverity(),
+			eventText(&events[i].Event)))
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "%d events since the last digest\n", len(events))
+	for _, sec := range sections {
+		if len(grouped[sec]) == 0 {
+			continue
+		}
+		fmt.Fprintf(&b, "\n%s (%d)\n%s\n", sec, len(grouped[sec]), strings.Join(grouped[sec], "\n"))
+	}
+	return b.String()
+}
+
+// SendDigest emails the queued events and clears the queue. Nothing is sent
+// when no events have been queued. Events are requeued if sending fails
+func (s *SMTPservice) SendDigest() error {
+	s.mtx.Lock()
+	events := s.digest
+	s.digest = nil
+	s.mtx.Unlock()
+	if len(events) == 0 {
+		return nil
+	}
+
+	err := s.Send(fmt.Sprintf("GoCryptoTrader digest %s", time.Now().UTC().Format("2006-01-02")),
+		"<pre>"+html.EscapeString(formatDigest(events))+"</pre>")
+	if err != nil {
+		s.mtx.Lock()
+		s.digest = append(events, s.digest...)
+		s.mtx.Unlock()
+	}
+	return err
+}
@@ -3,7 +3,9 @@ package smtpservice
 import (
 	"errors"
 	"fmt"
+	"html"
 	"net/smtp"
+	"sync"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/communications/base"
@@ -23,6 +25,14 @@ type SMTPservice struct {
 	AccountName     string
 	AccountPassword string
 	RecipientList   string
+	// Severities maps each event severity to its delivery mode
+	Severities map[string]string
+	// DigestTime is the UTC time of day the digest is sent at
+	DigestTime string
+
+	digest        []digestEvent
+	digestStarted bool
+	mtx           sync.Mutex
 }
 
 // Setup takes in a SMTP configuration and sets SMTP server details and
@@ -36,17 +46,48 @@ func (s *SMTPservice) Setup(cfg *config.CommunicationsConfig) {
 	s.AccountName = cfg.SMTPConfig.AccountName
 	s.AccountPassword = cfg.SMTPConfig.AccountPassword
 	s.RecipientList = cfg.SMTPConfig.RecipientList
+	s.DigestTime = cfg.SMTPConfig.DigestTime
+	s.Severities = make(map[string]string)
+	for k, v := range DefaultSeverities {
+		s.Severities[k] = v
+	}
+	for k, v := range cfg.SMTPConfig.Severities {
+		s.Severities[k] = v
+	}
 }
 
-// Connect connects to service
+// Connect validates the delivery modes and starts sending the daily digest
+// when any severity is delivered by digest
 func (s *SMTPservice) Connect() error {
+	digest := false
+	for severity, mode := range s.Severities {
+		switch mode {
+		case Digest:
+			digest = true
+		case Immediate, None:
+		default:
+			return fmt.Errorf("SMTPservice unknown delivery mode %q for %s events", mode, severity)
+		}
+	}
+	if digest {
+		s.startDigest()
+	}
 	s.Connected = true
 	return nil
 }
 
-// PushEvent sends an event to supplied recipient list via SMTP
-func (s *SMTPservice) PushEvent(base.Event) error {
-	return common.ErrNotYetImplemented
+// PushEvent sends an event to the recipient list immediately, or queues it
+// for the daily digest, depending on the delivery mode of its severity
+func (s *SMTPservice) PushEvent(e base.Event) error {
+	severity := e.GetSeverity()
+	switch s.mode(severity) {
+	case Immediate:
+		return s.Send(fmt.Sprintf("GoCryptoTrader %s: %s", severity, e.Type),
+			"<pre>"+html.EscapeString(eventText(&e))+"</pre>")
+	case Digest:
+		s.queue(e)
+	}
+	return nil
 }
 
 // Send sends an email template to the recipient list via your SMTP host when
@@ -2,6 +2,7 @@ package smtpservice
 
 import (
 	"testing"
+	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/communications/base"
 	"github.com/thrasher-corp/gocryptotrader/config"
@@ -24,10 +25,73 @@ func TestConnect(t *testing.T) {
 }
 
 func TestPushEvent(t *testing.T) {
-	err := s.PushEvent(base.Event{})
-	if err == nil {
+	err := s.PushEvent(base.Event{Type: "WARMUP_READY"})
+	if err != nil {
 		t.Error("test failed - smtpservice PushEvent() error", err)
 	}
+	err = s.PushEvent(base.Event{Type: "DRAWDOWN_BREAKER"})
+	if err == nil {
+		t.Error("test failed - smtpservice PushEvent() expected send error")
+	}
+	if len(s.digest) != 1 {
+		t.Error("test failed - smtpservice PushEvent() expected one digest event")
+	}
+
+	s.Severities[base.SeverityInfo] = None
+	err = s.PushEvent(base.Event{Type: "WARMUP_READY"})
+	if err != nil || len(s.digest) != 1 {
+		t.Error("test failed - smtpservice PushEvent() expected event dropped", err)
+	}
+
+	s.Severities[base.SeverityInfo] = "hourly"
+	if err = s.Connect(); err == nil {
+		t.Error("test failed - smtpservice Connect() expected unknown mode error")
+	}
+	s.Severities[base.SeverityInfo] = Digest
+}
+
+func TestDigest(t *testing.T) {
+	var d SMTPservice
+	if d.Digest() != "" {
+		t.Error("test failed - smtpservice Digest() expected empty digest")
+	}
+	now := time.Date(2019, 6, 2, 8, 0, 0, 0, time.UTC)
+	d.digest = []digestEvent{
+		{Event: base.Event{Type: "BRACKET_ACTIVATED", TradeDetails: "entry filled"}, time: now},
+		{Event: base.Event{Type: "DEPOSIT_CREDITED", TradeDetails: "1 BTC"}, time: now},
+		{Event: base.Event{Type: "EXCHANGE_BREAKER_TRIPPED", TradeDetails: "paused"}, time: now},
+		{Event: base.Event{Type: "ORDER_FILLED", GainLoss: "+5 USD"}, time: now},
+	}
+	expected := `4 events since the last digest
+
+Fills (1)
+  2019-06-02 08:00:00 [info] BRACKET_ACTIVATED: entry filled
+
+PnL (1)
+  2019-06-02 08:00:00 [info] ORDER_FILLED (+5 USD)
+
+Balance changes (1)
+  2019-06-02 08:00:00 [info] DEPOSIT_CREDITED: 1 BTC
+
+Errors (1)
+  2019-06-02 08:00:00 [critical] EXCHANGE_BREAKER_TRIPPED: paused
+`
+	if got := d.Digest(); got != expected {
+		t.Errorf("test failed - smtpservice Digest() unexpected digest\n%s", got)
+	}
+}
+
+func TestNextDigest(t *testing.T) {
+	now := time.Date(2019, 6, 2, 8, 0, 0, 0, time.UTC)
+	if n := nextDigest(now, "09:30"); !n.Equal(time.Date(2019, 6, 2, 9, 30, 0, 0, time.UTC)) {
+		t.Error("test failed - nextDigest() expected later today", n)
+	}
+	if n := nextDigest(now, "08:00"); !n.Equal(time.Date(2019, 6, 3, 8, 0, 0, 0, time.UTC)) {
+		t.Error("test failed - nextDigest() expected tomorrow", n)
+	}
+	if n := nextDigest(now, "bad"); !n.Equal(time.Date(2019, 6, 3, 0, 0, 0, 0, time.UTC)) {
+		t.Error("test failed - nextDigest() expected default time", n)
+	}
 }
 
 func TestSend(t *testing.T) {
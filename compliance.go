@@ -0,0 +1,112 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/compliance"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// complianceAuditFile is the journal of blocked actions in the data directory
+const complianceAuditFile = "compliance.log"
+
+var (
+	errComplianceDisabled = errors.New("compliance rules not enabled")
+	complianceAuditMtx    sync.Mutex
+)
+
+// ComplianceResponse holds the compliance rules enforced and the number of
+// actions they have blocked
+type ComplianceResponse struct {
+	Rules   compliance.Rules `json:"rules"`
+	Blocked int64            `json:"blocked"`
+}
+
+// ActivateCompliance guards every loaded exchange so orders and withdrawals
+// of blacklisted assets, on blacklisted venues or to sanctioned addresses are
+// blocked. Blocked actions are journaled to compliance.log in the data
+// directory. Address book destinations which are sanctioned are reported
+func ActivateCompliance() {
+	if bot.complianceRules == "" {
+		return
+	}
+
+	f, err := compliance.Load(bot.complianceRules, handleComplianceEvent)
+	if err != nil {
+		log.Errorf("Compliance rules failed to load from %s: %s", bot.complianceRules, err)
+		return
+	}
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		bot.exchanges[x] = f.Guard(bot.exchanges[x])
+	}
+	bot.compliance = f
+
+	if bot.addressBook != nil {
+		entries := bot.addressBook.List()
+		for i := range entries {
+			if r, ok := f.SanctionedAddress(entries[i].Currency, entries[i].Address); ok {
+				log.Warnf("Compliance: address book destination %s %s is sanctioned, withdrawals to it are blocked: %s",
+					entries[i].Label, entries[i].Address, r.Reason)
+			}
+		}
+	}
+
+	rules := f.GetRules()
+	log.Debugf("Compliance rules enabled, blacklisting %d assets, %d venues and %d addresses.",
+		len(rules.Assets), len(rules.Venues), len(rules.Addresses))
+}
+
+func handleComplianceEvent(e compliance.Event) {
+	log.Warnf("Compliance: %s", e.String())
+	err := writeComplianceAudit(&e)
+	if err != nil {
+		log.Errorf("Compliance failed to write audit journal: %s", err)
+	}
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e, "compliance", "", e.Exchange)
+	}
+}
+
+func writeComplianceAudit(e *compliance.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	complianceAuditMtx.Lock()
+	defer complianceAuditMtx.Unlock()
+	f, err := os.OpenFile(filepath.Join(bot.dataDir, complianceAuditFile),
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
+
+// GetCompliance returns the compliance rules enforced
+func GetCompliance() (ComplianceResponse, error) {
+	if bot.compliance == nil {
+		return ComplianceResponse{}, errComplianceDisabled
+	}
+	return ComplianceResponse{
+		Rules:   bot.compliance.GetRules(),
+		Blocked: bot.compliance.GetBlocked(),
+	}, nil
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/compositeindex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// indexPriceMaxAge is the age after which a constituent's stored ticker is
+// treated as an outage rather than used in the local index
+const indexPriceMaxAge = time.Minute * 5
+
+var (
+	errIndexTrackerDisabled = errors.New("composite index tracker not enabled")
+	errStaleTicker          = errors.New("ticker is stale")
+)
+
+// ActivateIndexTracker starts tracking the Bitmex .BXBT index constituents,
+// recomputing the index from the constituent exchanges' stored tickers.
+// Constituent exchanges must be enabled for their prices to be included
+func ActivateIndexTracker() {
+	if !bot.indexTracking {
+		return
+	}
+
+	var source *compositeindex.BitmexComposite
+	for _, exch := range GetLoadedExchanges() {
+		if e, ok := exchange.Underlying(exch).(*bitmex.Bitmex); ok {
+			source = &compositeindex.BitmexComposite{Exchange: e}
+			break
+		}
+	}
+	if source == nil {
+		log.Errorf("Composite index tracker failed to start: %s", ErrExchangeNotFound)
+		return
+	}
+
+	t, err := compositeindex.New(compositeindex.Config{}, source, indexConstituentPrice, handleIndexEvent)
+	if err != nil {
+		log.Errorf("Composite index tracker failed to start: %s", err)
+		return
+	}
+	t.Start(compositeindex.DefaultCheckInterval)
+	bot.indexTracker = t
+	log.Debugf("Composite index tracker enabled for %s.", compositeindex.DefaultSymbol)
+}
+
+// indexConstituentPrice returns the last price of the pair stored for the
+// exchange, falling back to the XBT code some exchanges list bitcoin under
+func indexConstituentPrice(exchName string, p currency.Pair) (float64, error) {
+	t, err := ticker.GetTicker(exchName, p, ticker.Spot)
+	if err != nil && p.Base.Match(currency.BTC) {
+		t, err = ticker.GetTicker(exchName, currency.NewPair(currency.XBT, p.Quote), ticker.Spot)
+	}
+	if err != nil {
+		return 0, err
+	}
+	if time.Since(t.LastUpdated) > indexPriceMaxAge {
+		return 0, fmt.Errorf("%s %s %v, last updated %s", exchName, p, errStaleTicker, t.LastUpdated)
+	}
+	return t.Last, nil
+}
+
+func handleIndexEvent(e compositeindex.Event) {
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Snapshot, "composite_index", "", "")
+	}
+}
+
+// GetCompositeIndices returns the latest published and local value of each
+// tracked composite index
+func GetCompositeIndices() ([]compositeindex.Snapshot, error) {
+	if bot.indexTracker == nil {
+		return nil, errIndexTrackerDisabled
+	}
+	return bot.indexTracker.GetLatest(), nil
+}
+
+// GetCompositeIndexHistory returns the recorded snapshots of an index
+func GetCompositeIndexHistory(symbol string) ([]compositeindex.Snapshot, error) {
+	if bot.indexTracker == nil {
+		return nil, errIndexTrackerDisabled
+	}
+	return bot.indexTracker.GetHistory(symbol)
+}
@@ -40,6 +40,8 @@ const (
 	configMaxAuthFailres                       = 3
 	defaultNTPAllowedDifference                = 50000000
 	defaultNTPAllowedNegativeDifference        = 50000000
+	// ConfigKeyEnv holds the config encryption key when running headless
+	ConfigKeyEnv = "GCT_CONFIG_KEY"
 )
 
 // Constants here hold some messages
@@ -77,6 +79,9 @@ var (
 	IsInitialSetup bool
 	testBypass     bool
 	m              sync.Mutex
+	// Headless disables all interactive prompts so the bot can run without a
+	// keyboard, the config encryption key is read from ConfigKeyEnv
+	Headless bool
 )
 
 // WebserverConfig struct holds the prestart variables for the webserver.
@@ -157,6 +162,7 @@ type ExchangeConfig struct {
 	Verbose                          bool                      `json:"verbose"`
 	Websocket                        bool                      `json:"websocket"`
 	UseSandbox                       bool                      `json:"useSandbox"`
+	DryRun                           bool                      `json:"dryRun,omitempty"`
 	RESTPollingDelay                 time.Duration             `json:"restPollingDelay"`
 	HTTPTimeout                      time.Duration             `json:"httpTimeout"`
 	WebsocketResponseCheckTimeout    time.Duration             `json:"websocketResponseCheckTimeout"`
@@ -171,9 +177,11 @@ type ExchangeConfig struct {
 	APIAuthPEMKey                    string                    `json:"apiAuthPemKey,omitempty"`
 	APIURL                           string                    `json:"apiUrl"`
 	APIURLSecondary                  string                    `json:"apiUrlSecondary"`
+	APIVersion                       string                    `json:"apiVersion,omitempty"`
 	ProxyAddress                     string                    `json:"proxyAddress"`
 	WebsocketURL                     string                    `json:"websocketUrl"`
 	ClientID                         string                    `json:"clientId,omitempty"`
+	FeeDeduction                     string                    `json:"feeDeduction,omitempty"`
 	AvailablePairs                   currency.Pairs            `json:"availablePairs"`
 	EnabledPairs                     currency.Pairs            `json:"enabledPairs"`
 	BaseCurrencies                   currency.Currencies       `json:"baseCurrencies"`
@@ -211,14 +219,36 @@ type BankTransaction struct {
 type CurrencyConfig struct {
 	ForexProviders                []base.Settings           `json:"forexProviders"`
 	CryptocurrencyProvider        CryptocurrencyProvider    `json:"cryptocurrencyProvider"`
+	SecondaryCryptoProvider       CryptocurrencyProvider    `json:"secondaryCryptocurrencyProvider"`
 	Cryptocurrencies              currency.Currencies       `json:"cryptocurrencies"`
 	CurrencyPairFormat            *CurrencyPairFormatConfig `json:"currencyPairFormat"`
 	FiatDisplayCurrency           currency.Code             `json:"fiatDisplayCurrency"`
 	CurrencyFileUpdateDuration    time.Duration             `json:"currencyFileUpdateDuration"`
 	ForeignExchangeUpdateDuration time.Duration             `json:"foreignExchangeUpdateDuration"`
+	DisplayLocale                 string                    `json:"displayLocale,omitempty"`
+	DisplayPrecision              map[string]int            `json:"displayPrecision,omitempty"`
+	DisplayPriceDigits            int                       `json:"displayPriceDigits,omitempty"`
 }
 
-// CryptocurrencyProvider defines coinmarketcap tools
+// DisplayFormat returns the format amounts and prices are displayed in by
+// reports, notifications and logs
+func (c *CurrencyConfig) DisplayFormat() common.DisplayFormat {
+	locale, err := common.ParseNumberLocale(c.DisplayLocale)
+	if err != nil {
+		locale = common.LocaleEnglish
+	}
+	precision := make(map[string]int, len(c.DisplayPrecision))
+	for code, p := range c.DisplayPrecision {
+		precision[strings.ToUpper(code)] = p
+	}
+	return common.DisplayFormat{
+		Locale:      locale,
+		Precision:   precision,
+		PriceDigits: c.DisplayPriceDigits,
+	}
+}
+
+// CryptocurrencyProvider defines coinmarketcap and coingecko tools
 type CryptocurrencyProvider struct {
 	Name        string `json:"name"`
 	Enabled     bool   `json:"enabled"`
@@ -273,6 +303,12 @@ type SMTPConfig struct {
 	AccountName     string `json:"accountName"`
 	AccountPassword string `json:"accountPassword"`
 	RecipientList   string `json:"recipientList"`
+	// Severities sets how events of each severity are delivered: immediate,
+	// digest or none. Unset severities use the SMTP package defaults
+	Severities map[string]string `json:"severities,omitempty"`
+	// DigestTime is the UTC time of day, formatted 15:04, the daily digest
+	// is sent at
+	DigestTime string `json:"digestTime,omitempty"`
 }
 
 // TelegramConfig holds all variables to start and run the Telegram package
@@ -550,6 +586,12 @@ func (c *Config) CheckCommunicationsConfig() {
 			c.Communications.SMTPConfig.Enabled = false
 			log.Warn("SMTP enabled in config but variable data not set, disabling.")
 		}
+		if c.Communications.SMTPConfig.DigestTime != "" {
+			if _, err := time.Parse("15:04", c.Communications.SMTPConfig.DigestTime); err != nil {
+				c.Communications.SMTPConfig.DigestTime = ""
+				log.Warn("SMTP digest time invalid, using default.")
+			}
+		}
 	}
 	if c.Communications.TelegramConfig.Enabled {
 		if c.Communications.TelegramConfig.VerificationToken == "" {
@@ -1038,6 +1080,14 @@ func (c *Config) CheckCurrencyConfigValues() error {
 		}
 	}
 
+	if c.Currency.SecondaryCryptoProvider == (CryptocurrencyProvider{}) {
+		c.Currency.SecondaryCryptoProvider.Name = "CoinGecko"
+		c.Currency.SecondaryCryptoProvider.Enabled = false
+		c.Currency.SecondaryCryptoProvider.Verbose = false
+		c.Currency.SecondaryCryptoProvider.AccountPlan = DefaultUnsetAccountPlan
+		c.Currency.SecondaryCryptoProvider.APIkey = DefaultUnsetAPIKey
+	}
+
 	if c.Currency.Cryptocurrencies.Join() == "" {
 		if c.Cryptocurrencies.Join() != "" {
 			c.Currency.Cryptocurrencies = c.Cryptocurrencies
@@ -1067,6 +1117,21 @@ func (c *Config) CheckCurrencyConfigValues() error {
 			c.Currency.FiatDisplayCurrency = currency.USD
 		}
 	}
+
+	if _, err := common.ParseNumberLocale(c.Currency.DisplayLocale); err != nil {
+		log.Warnf("Currency display locale %s, displaying numbers in en.", err)
+		c.Currency.DisplayLocale = ""
+	}
+	for code, precision := range c.Currency.DisplayPrecision {
+		if precision < 0 {
+			log.Warnf("Currency display precision of %s cannot be negative, using the default.", code)
+			delete(c.Currency.DisplayPrecision, code)
+		}
+	}
+	if c.Currency.DisplayPriceDigits < 0 {
+		log.Warnf("Currency display price digits cannot be negative, using the default.")
+		c.Currency.DisplayPriceDigits = 0
+	}
 	return nil
 }
 
@@ -1347,6 +1412,10 @@ func (c *Config) ReadConfig(configPath string) error {
 		}
 
 		if c.EncryptConfig == configFileEncryptionPrompt {
+			if Headless {
+				log.Warn("Config encryption prompt skipped in headless mode.")
+				return nil
+			}
 			m.Lock()
 			IsInitialSetup = true
 			m.Unlock()
@@ -1356,6 +1425,9 @@ func (c *Config) ReadConfig(configPath string) error {
 			}
 		}
 	} else {
+		if Headless {
+			return c.decryptHeadless(file)
+		}
 		errCounter := 0
 		for {
 			if errCounter >= configMaxAuthFailres {
@@ -1391,6 +1463,19 @@ func (c *Config) ReadConfig(configPath string) error {
 	return nil
 }
 
+// decryptHeadless decrypts the config with the key held in ConfigKeyEnv
+func (c *Config) decryptHeadless(file []byte) error {
+	key := os.Getenv(ConfigKeyEnv)
+	if key == "" {
+		return fmt.Errorf("config is encrypted and %s is not set", ConfigKeyEnv)
+	}
+	data, err := DecryptConfigFile(file, []byte(key))
+	if err != nil {
+		return err
+	}
+	return ConfirmConfigJSON(data, &c)
+}
+
 // SaveConfig saves your configuration to your desired path
 func (c *Config) SaveConfig(configPath string) error {
 	defaultPath, err := GetFilePath(configPath)
@@ -0,0 +1,237 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+)
+
+// Secret reference schemes. Config values prefixed with a registered scheme,
+// for example "env:BINANCE_API_KEY", are resolved through the matching secret
+// source when an exchange or communication medium is set up. The unresolved
+// reference is what remains stored in the config file
+const (
+	SecretSchemeEnv    = "env"
+	SecretSchemeFile   = "file"
+	SecretSchemeDocker = "docker"
+	SecretSchemeVault  = "vault"
+
+	// DefaultDockerSecretsPath is where Docker and Kubernetes mount secrets
+	DefaultDockerSecretsPath = "/run/secrets"
+
+	// Vault environment variables
+	VaultAddressEnv = "VAULT_ADDR"
+	VaultTokenEnv   = "VAULT_TOKEN"
+)
+
+var (
+	secretSources = map[string]SecretSource{
+		SecretSchemeEnv:    EnvSecretSource{},
+		SecretSchemeFile:   FileSecretSource{},
+		SecretSchemeDocker: FileSecretSource{Dir: DefaultDockerSecretsPath},
+		SecretSchemeVault:  &VaultSecretSource{},
+	}
+	secretSourcesMu sync.RWMutex
+
+	errSecretSourceNil     = errors.New("secret source is nil")
+	errSecretNotFound      = errors.New("secret not found")
+	errEmptySecretRef      = errors.New("secret reference is empty")
+	errVaultAddressUnset   = errors.New("vault address not set")
+	errVaultTokenUnset     = errors.New("vault token not set")
+	errVaultFieldMissing   = errors.New("vault reference must be in the format path#field")
+	errVaultFieldNotString = errors.New("vault secret field is not a string")
+)
+
+// SecretSource resolves a secret reference to its value
+type SecretSource interface {
+	Resolve(ref string) (string, error)
+}
+
+// RegisterSecretSource registers a secret source for the supplied scheme,
+// replacing any existing source
+func RegisterSecretSource(scheme string, s SecretSource) error {
+	if s == nil {
+		return errSecretSourceNil
+	}
+	secretSourcesMu.Lock()
+	secretSources[strings.ToLower(scheme)] = s
+	secretSourcesMu.Unlock()
+	return nil
+}
+
+// IsSecretReference returns whether the value references a registered secret
+// source
+func IsSecretReference(value string) bool {
+	_, _, ok := getSecretSource(value)
+	return ok
+}
+
+// ResolveSecret returns the value of a secret reference. Values without a
+// registered scheme prefix are returned unchanged
+func ResolveSecret(value string) (string, error) {
+	s, ref, ok := getSecretSource(value)
+	if !ok {
+		return value, nil
+	}
+	if ref == "" {
+		return "", errEmptySecretRef
+	}
+	return s.Resolve(ref)
+}
+
+func getSecretSource(value string) (SecretSource, string, bool) {
+	i := strings.Index(value, ":")
+	if i <= 0 {
+		return nil, "", false
+	}
+	secretSourcesMu.RLock()
+	s, ok := secretSources[strings.ToLower(value[:i])]
+	secretSourcesMu.RUnlock()
+	if !ok {
+		return nil, "", false
+	}
+	return s, value[i+1:], true
+}
+
+// resolveSecrets resolves each of the supplied fields in place
+func resolveSecrets(name string, fields map[string]*string) error {
+	for field, value := range fields {
+		resolved, err := ResolveSecret(*value)
+		if err != nil {
+			return fmt.Errorf("%s %s: %v", name, field, err)
+		}
+		*value = resolved
+	}
+	return nil
+}
+
+// ResolveSecrets resolves secret references held in the exchange API
+// credentials and proxy address
+func (e *ExchangeConfig) ResolveSecrets() error {
+	return resolveSecrets(e.Name, map[string]*string{
+		"apiKey":        &e.APIKey,
+		"apiSecret":     &e.APISecret,
+		"apiAuthPemKey": &e.APIAuthPEMKey,
+		"clientId":      &e.ClientID,
+		"proxyAddress":  &e.ProxyAddress,
+	})
+}
+
+// ResolveSecrets resolves secret references held in the communication
+// medium tokens and passwords
+func (c *CommunicationsConfig) ResolveSecrets() error {
+	return resolveSecrets("communications", map[string]*string{
+		"slack verificationToken":    &c.SlackConfig.VerificationToken,
+		"smsGlobal password":         &c.SMSGlobalConfig.Password,
+		"smtp accountPassword":       &c.SMTPConfig.AccountPassword,
+		"telegram verificationToken": &c.TelegramConfig.VerificationToken,
+	})
+}
+
+// EnvSecretSource resolves secrets from environment variables
+type EnvSecretSource struct{}
+
+// Resolve returns the value of the named environment variable
+func (EnvSecretSource) Resolve(ref string) (string, error) {
+	v, ok := os.LookupEnv(ref)
+	if !ok {
+		return "", fmt.Errorf("environment variable %s %v", ref, errSecretNotFound)
+	}
+	return v, nil
+}
+
+// FileSecretSource resolves secrets from files, such as Docker secrets. Refs
+// are relative to Dir when it is set
+type FileSecretSource struct {
+	Dir string
+}
+
+// Resolve returns the trimmed contents of the referenced file
+func (f FileSecretSource) Resolve(ref string) (string, error) {
+	path := ref
+	if f.Dir != "" {
+		path = filepath.Join(f.Dir, filepath.Clean("/"+ref))
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(data)), nil
+}
+
+// VaultSecretSource resolves secrets from a HashiCorp Vault KV secrets engine.
+// Refs are in the format path#field, for example
+// secret/data/gocryptotrader/binance#apiKey. The address and token default to
+// the VAULT_ADDR and VAULT_TOKEN environment variables
+type VaultSecretSource struct {
+	Address string
+	Token   string
+}
+
+// Resolve returns the field of the referenced Vault secret
+func (v *VaultSecretSource) Resolve(ref string) (string, error) {
+	i := strings.LastIndex(ref, "#")
+	if i <= 0 || i == len(ref)-1 {
+		return "", errVaultFieldMissing
+	}
+	path, field := strings.Trim(ref[:i], "/"), ref[i+1:]
+
+	address := v.Address
+	if address == "" {
+		address = os.Getenv(VaultAddressEnv)
+	}
+	if address == "" {
+		return "", errVaultAddressUnset
+	}
+	token := v.Token
+	if token == "" {
+		token = os.Getenv(VaultTokenEnv)
+	}
+	if token == "" {
+		return "", errVaultTokenUnset
+	}
+
+	resp, err := common.SendHTTPRequest(http.MethodGet,
+		strings.TrimSuffix(address, "/")+"/v1/"+path,
+		map[string]string{"X-Vault-Token": token},
+		nil)
+	if err != nil {
+		return "", err
+	}
+
+	var secret struct {
+		Data   map[string]interface{} `json:"data"`
+		Errors []string               `json:"errors"`
+	}
+	err = common.JSONDecode([]byte(resp), &secret)
+	if err != nil {
+		return "", err
+	}
+	if len(secret.Errors) > 0 {
+		return "", fmt.Errorf("vault %s: %s", path, strings.Join(secret.Errors, ", "))
+	}
+
+	data := secret.Data
+	// KV version 2 nests the secret beneath data alongside its metadata
+	if nested, ok := data["data"].(map[string]interface{}); ok {
+		if _, ok := data["metadata"]; ok {
+			data = nested
+		}
+	}
+	value, ok := data[field]
+	if !ok {
+		return "", fmt.Errorf("vault %s#%s %v", path, field, errSecretNotFound)
+	}
+	s, ok := value.(string)
+	if !ok {
+		return "", errVaultFieldNotString
+	}
+	return s, nil
+}
@@ -0,0 +1,142 @@
+package config
+
+import (
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestResolveSecret(t *testing.T) {
+	v, err := ResolveSecret("plainvalue")
+	if err != nil || v != "plainvalue" {
+		t.Error("Test Failed - ResolveSecret() expected plain value to be unchanged")
+	}
+	v, err = ResolveSecret("http://127.0.0.1:8080")
+	if err != nil || v != "http://127.0.0.1:8080" {
+		t.Error("Test Failed - ResolveSecret() expected unregistered scheme to be unchanged")
+	}
+
+	err = os.Setenv("GCT_TEST_SECRET", "supersecret")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("GCT_TEST_SECRET")
+	v, err = ResolveSecret("env:GCT_TEST_SECRET")
+	if err != nil || v != "supersecret" {
+		t.Error("Test Failed - ResolveSecret() env error", err)
+	}
+	_, err = ResolveSecret("env:GCT_TEST_SECRET_MISSING")
+	if err == nil {
+		t.Error("Test Failed - ResolveSecret() expected missing env error")
+	}
+	_, err = ResolveSecret("env:")
+	if err != errEmptySecretRef {
+		t.Error("Test Failed - ResolveSecret() expected empty reference error", err)
+	}
+
+	dir, err := ioutil.TempDir("", "gctsecrets")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	err = ioutil.WriteFile(filepath.Join(dir, "apikey"), []byte("filesecret\n"), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = ResolveSecret("file:" + filepath.Join(dir, "apikey"))
+	if err != nil || v != "filesecret" {
+		t.Error("Test Failed - ResolveSecret() file error", err)
+	}
+
+	err = RegisterSecretSource("testdocker", FileSecretSource{Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err = ResolveSecret("testdocker:apikey")
+	if err != nil || v != "filesecret" {
+		t.Error("Test Failed - ResolveSecret() docker error", err)
+	}
+	_, err = ResolveSecret("testdocker:../" + filepath.Base(dir) + "/apikey")
+	if err == nil {
+		t.Error("Test Failed - ResolveSecret() expected path traversal to be contained")
+	}
+	if RegisterSecretSource("nil", nil) != errSecretSourceNil {
+		t.Error("Test Failed - RegisterSecretSource() expected nil source error")
+	}
+}
+
+func TestVaultSecretSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("X-Vault-Token") != "token" {
+			w.WriteHeader(http.StatusForbidden)
+			w.Write([]byte(`{"errors":["permission denied"]}`))
+			return
+		}
+		switch r.URL.Path {
+		case "/v1/secret/data/gct":
+			w.Write([]byte(`{"data":{"data":{"apiKey":"kv2key"},"metadata":{"version":1}}}`))
+		case "/v1/kv/gct":
+			w.Write([]byte(`{"data":{"apiKey":"kv1key","limit":5}}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"errors":[]}`))
+		}
+	}))
+	defer server.Close()
+
+	v := &VaultSecretSource{Address: server.URL, Token: "token"}
+	s, err := v.Resolve("secret/data/gct#apiKey")
+	if err != nil || s != "kv2key" {
+		t.Error("Test Failed - Vault Resolve() kv2 error", err)
+	}
+	s, err = v.Resolve("/kv/gct#apiKey")
+	if err != nil || s != "kv1key" {
+		t.Error("Test Failed - Vault Resolve() kv1 error", err)
+	}
+	if _, err = v.Resolve("kv/gct#limit"); err != errVaultFieldNotString {
+		t.Error("Test Failed - Vault Resolve() expected non string error", err)
+	}
+	if _, err = v.Resolve("kv/gct#missing"); err == nil {
+		t.Error("Test Failed - Vault Resolve() expected missing field error")
+	}
+	if _, err = v.Resolve("kv/gct"); err != errVaultFieldMissing {
+		t.Error("Test Failed - Vault Resolve() expected field format error", err)
+	}
+
+	denied := &VaultSecretSource{Address: server.URL, Token: "bad"}
+	if _, err = denied.Resolve("kv/gct#apiKey"); err == nil {
+		t.Error("Test Failed - Vault Resolve() expected permission error")
+	}
+}
+
+func TestResolveSecrets(t *testing.T) {
+	err := os.Setenv("GCT_TEST_API_KEY", "key")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.Unsetenv("GCT_TEST_API_KEY")
+
+	e := ExchangeConfig{
+		Name:         "test",
+		APIKey:       "env:GCT_TEST_API_KEY",
+		APISecret:    "secret",
+		ProxyAddress: "http://proxy:8080",
+	}
+	err = e.ResolveSecrets()
+	if err != nil {
+		t.Fatal("Test Failed - ResolveSecrets() error", err)
+	}
+	if e.APIKey != "key" || e.APISecret != "secret" || e.ProxyAddress != "http://proxy:8080" {
+		t.Errorf("Test Failed - ResolveSecrets() unexpected values %s %s %s",
+			e.APIKey, e.APISecret, e.ProxyAddress)
+	}
+
+	c := CommunicationsConfig{}
+	c.TelegramConfig.VerificationToken = "env:GCT_TEST_MISSING_TOKEN"
+	if c.ResolveSecrets() == nil {
+		t.Error("Test Failed - ResolveSecrets() expected missing token error")
+	}
+}
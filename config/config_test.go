@@ -25,6 +25,33 @@ func TestGetCurrencyConfig(t *testing.T) {
 	_ = cfg.GetCurrencyConfig()
 }
 
+func TestCurrencyDisplayFormat(t *testing.T) {
+	cfg := GetConfig()
+	err := cfg.LoadConfig(ConfigTestFile)
+	if err != nil {
+		t.Error("Test failed. CurrencyDisplayFormat LoadConfig error", err)
+	}
+	cfg.Currency.DisplayLocale = "xx"
+	cfg.Currency.DisplayPrecision = map[string]int{"btc": 4, "eth": -1}
+	cfg.Currency.DisplayPriceDigits = -1
+	err = cfg.CheckCurrencyConfigValues()
+	if err != nil {
+		t.Error("Test failed. CurrencyDisplayFormat CheckCurrencyConfigValues error", err)
+	}
+	if cfg.Currency.DisplayLocale != "" || len(cfg.Currency.DisplayPrecision) != 1 ||
+		cfg.Currency.DisplayPriceDigits != 0 {
+		t.Errorf("Test failed. CurrencyDisplayFormat invalid settings not reset %+v", cfg.Currency)
+	}
+
+	cfg.Currency.DisplayLocale = "de-DE"
+	f := cfg.Currency.DisplayFormat()
+	if f.Locale != common.LocaleEuropean || f.CurrencyPrecision("BTC") != 4 {
+		t.Errorf("Test failed. CurrencyDisplayFormat unexpected format %+v", f)
+	}
+	cfg.Currency.DisplayLocale = ""
+	cfg.Currency.DisplayPrecision = nil
+}
+
 func TestGetExchangeBankAccounts(t *testing.T) {
 	cfg := GetConfig()
 	err := cfg.LoadConfig(ConfigTestFile)
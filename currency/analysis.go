@@ -0,0 +1,137 @@
+package currency
+
+import (
+	"errors"
+	"sort"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency/coingecko"
+	"github.com/thrasher-corp/gocryptotrader/currency/coinmarketcap"
+)
+
+// AnalysisProvider defines a cryptocurrency market analysis provider that can
+// be used to seed the currency code roll
+type AnalysisProvider interface {
+	GetName() string
+	GetListings() ([]Listing, error)
+}
+
+// Listing defines a provider agnostic cryptocurrency listing entry
+type Listing struct {
+	ID       int
+	Name     string
+	Symbol   string
+	Platform string
+}
+
+// coinmarketcapProvider wraps the coinmarketcap client to satisfy the
+// AnalysisProvider interface
+type coinmarketcapProvider struct {
+	*coinmarketcap.Coinmarketcap
+}
+
+// GetName returns the provider name
+func (c coinmarketcapProvider) GetName() string {
+	return c.Name
+}
+
+// GetListings returns the active cryptocurrency listings from coinmarketcap
+func (c coinmarketcapProvider) GetListings() ([]Listing, error) {
+	m, err := c.GetCryptocurrencyIDMap()
+	if err != nil {
+		return nil, err
+	}
+
+	var listings []Listing
+	for x := range m {
+		if m[x].IsActive != 1 {
+			continue
+		}
+		listings = append(listings, Listing{
+			ID:       m[x].ID,
+			Name:     m[x].Name,
+			Symbol:   m[x].Symbol,
+			Platform: m[x].Platform.Symbol,
+		})
+	}
+	return listings, nil
+}
+
+// coingeckoPlatforms maps coingecko platform IDs to their native blockchain
+// symbol so that tokens are associated the same way as coinmarketcap
+var coingeckoPlatforms = map[string]string{
+	"ethereum":            "ETH",
+	"binance-smart-chain": "BNB",
+	"binancecoin":         "BNB",
+	"tron":                "TRX",
+	"neo":                 "NEO",
+	"eos":                 "EOS",
+	"waves":               "WAVES",
+	"stellar":             "XLM",
+	"omni":                "OMNI",
+	"ontology":            "ONT",
+	"nem":                 "XEM",
+	"qtum":                "QTUM",
+	"vechain":             "VET",
+}
+
+// coingeckoProvider wraps the coingecko client to satisfy the
+// AnalysisProvider interface
+type coingeckoProvider struct {
+	*coingecko.CoinGecko
+}
+
+// GetName returns the provider name
+func (c coingeckoProvider) GetName() string {
+	return c.Name
+}
+
+// GetListings returns the cryptocurrency listings from coingecko, coingecko
+// uses string identifiers so the numeric ID is left unset
+func (c coingeckoProvider) GetListings() ([]Listing, error) {
+	coins, err := c.GetCoinsList()
+	if err != nil {
+		return nil, err
+	}
+
+	var listings []Listing
+	for x := range coins {
+		if coins[x].Symbol == "" {
+			continue
+		}
+		listings = append(listings, Listing{
+			Name:     coins[x].Name,
+			Symbol:   strings.ToUpper(coins[x].Symbol),
+			Platform: getCoingeckoPlatform(coins[x].Platforms),
+		})
+	}
+
+	if len(listings) == 0 {
+		return nil, errors.New("coingecko listing empty")
+	}
+	return listings, nil
+}
+
+// getCoingeckoPlatform returns the blockchain symbol a token is issued on,
+// platform keys are sorted so the result is deterministic
+func getCoingeckoPlatform(platforms map[string]string) string {
+	var keys []string
+	for k, v := range platforms {
+		if k == "" || v == "" {
+			continue
+		}
+		keys = append(keys, k)
+	}
+
+	if len(keys) == 0 {
+		return ""
+	}
+
+	sort.Strings(keys)
+	for i := range keys {
+		if s, ok := coingeckoPlatforms[keys[i]]; ok {
+			return s
+		}
+	}
+	return strings.ToUpper(keys[0])
+}
@@ -0,0 +1,82 @@
+package currency
+
+import (
+	"errors"
+	"testing"
+)
+
+type testAnalysisProvider struct {
+	name     string
+	listings []Listing
+	err      error
+	calls    int
+}
+
+func (t *testAnalysisProvider) GetName() string {
+	return t.name
+}
+
+func (t *testAnalysisProvider) GetListings() ([]Listing, error) {
+	t.calls++
+	return t.listings, t.err
+}
+
+func TestUpdateCurrenciesFailover(t *testing.T) {
+	var s Storage
+	err := s.UpdateCurrencies()
+	if err == nil {
+		t.Error("Test Failed - UpdateCurrencies() error cannot be nil")
+	}
+
+	primary := &testAnalysisProvider{
+		name: "primary",
+		err:  errors.New("quota exhausted"),
+	}
+	secondary := &testAnalysisProvider{
+		name: "secondary",
+		listings: []Listing{
+			{Name: "Failover Coin", Symbol: "FAILOVERCOIN"},
+			{Name: "Failover Token", Symbol: "FAILOVERTOKEN", Platform: "ETH"},
+		},
+	}
+	s.currencyAnalysis = []AnalysisProvider{primary, secondary}
+
+	err = s.UpdateCurrencies()
+	if err != nil {
+		t.Fatal("Test Failed - UpdateCurrencies() error", err)
+	}
+
+	if primary.calls != 1 || secondary.calls != 1 {
+		t.Error("Test Failed - UpdateCurrencies() providers not called in order")
+	}
+
+	c := s.currencyCodes.Register("FAILOVERTOKEN")
+	if c.Item.Role != Token || c.Item.AssocChain != "ETH" {
+		t.Error("Test Failed - UpdateCurrencies() token not loaded from secondary provider")
+	}
+
+	secondary.err = errors.New("rate limited")
+	err = s.UpdateCurrencies()
+	if err == nil {
+		t.Error("Test Failed - UpdateCurrencies() error cannot be nil when all providers fail")
+	}
+}
+
+func TestGetCoingeckoPlatform(t *testing.T) {
+	if p := getCoingeckoPlatform(nil); p != "" {
+		t.Error("Test Failed - getCoingeckoPlatform() expected empty platform")
+	}
+
+	p := getCoingeckoPlatform(map[string]string{
+		"tron":     "T123",
+		"ethereum": "0x123",
+	})
+	if p != "ETH" {
+		t.Errorf("Test Failed - getCoingeckoPlatform() expected ETH received %s", p)
+	}
+
+	p = getCoingeckoPlatform(map[string]string{"somechain": "0x123"})
+	if p != "SOMECHAIN" {
+		t.Errorf("Test Failed - getCoingeckoPlatform() expected SOMECHAIN received %s", p)
+	}
+}
@@ -0,0 +1,211 @@
+package currency
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+)
+
+// Asset class consts
+const (
+	Unclassified AssetClass = iota
+	Stablecoin
+	WrappedAsset
+	DerivativeContract
+	Index
+
+	UnclassifiedString       = "unclassified"
+	StablecoinString         = "stablecoin"
+	WrappedAssetString       = "wrappedAsset"
+	DerivativeContractString = "derivativeContract"
+	IndexString              = "index"
+)
+
+var (
+	errPegNotSet          = errors.New("asset class requires a pegged currency")
+	errInvalidMultiplier  = errors.New("derivative contract multiplier must be positive")
+	errAssetClassNotFound = errors.New("asset class unsupported")
+)
+
+// AssetClass defines how a currency code should be treated by routing, risk
+// and valuation logic beyond its role
+type AssetClass uint8
+
+func (a AssetClass) String() string {
+	switch a {
+	case Unclassified:
+		return UnclassifiedString
+	case Stablecoin:
+		return StablecoinString
+	case WrappedAsset:
+		return WrappedAssetString
+	case DerivativeContract:
+		return DerivativeContractString
+	case Index:
+		return IndexString
+	default:
+		return "UNKNOWN"
+	}
+}
+
+// MarshalJSON conforms AssetClass to the marshaler interface
+func (a AssetClass) MarshalJSON() ([]byte, error) {
+	return common.JSONEncode(a.String())
+}
+
+// UnmarshalJSON conforms AssetClass to the unmarshaller interface
+func (a *AssetClass) UnmarshalJSON(d []byte) error {
+	var incoming string
+	err := common.JSONDecode(d, &incoming)
+	if err != nil {
+		return err
+	}
+
+	switch incoming {
+	case UnclassifiedString, "":
+		*a = Unclassified
+	case StablecoinString:
+		*a = Stablecoin
+	case WrappedAssetString:
+		*a = WrappedAsset
+	case DerivativeContractString:
+		*a = DerivativeContract
+	case IndexString:
+		*a = Index
+	default:
+		return fmt.Errorf("unmarshal error asset class %s unsupported for currency",
+			incoming)
+	}
+	return nil
+}
+
+// defaultAssetClasses defines the asset classes seeded into storage, keyed by
+// symbol with the currency they are pegged to
+var defaultAssetClasses = []struct {
+	Symbol string
+	Class  AssetClass
+	Peg    string
+}{
+	{"USDT", Stablecoin, "USD"},
+	{"USDC", Stablecoin, "USD"},
+	{"DAI", Stablecoin, "USD"},
+	{"TUSD", Stablecoin, "USD"},
+	{"PAX", Stablecoin, "USD"},
+	{"GUSD", Stablecoin, "USD"},
+	{"BUSD", Stablecoin, "USD"},
+	{"WBTC", WrappedAsset, "BTC"},
+}
+
+// UpdateAssetClass sets the asset class and associated metadata of a currency
+// code, registering the code if it is not yet tracked. Stablecoins and wrapped
+// assets require the currency they are pegged to and derivative contracts
+// require a contract multiplier
+func (b *BaseCodes) UpdateAssetClass(symbol string, class AssetClass, peg string, multiplier float64) error {
+	symbol = common.StringToUpper(symbol)
+	peg = common.StringToUpper(peg)
+
+	switch class {
+	case Unclassified, Index:
+		peg = ""
+		multiplier = 0
+	case Stablecoin, WrappedAsset:
+		if peg == "" {
+			return fmt.Errorf("%s %s %v", symbol, class, errPegNotSet)
+		}
+		multiplier = 0
+	case DerivativeContract:
+		if multiplier <= 0 {
+			return fmt.Errorf("%s %v", symbol, errInvalidMultiplier)
+		}
+	default:
+		return fmt.Errorf("%s %v", symbol, errAssetClassNotFound)
+	}
+
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	for i := range b.Items {
+		if b.Items[i].Symbol != symbol {
+			continue
+		}
+		b.Items[i].AssetClass = class
+		b.Items[i].PeggedCurrency = peg
+		b.Items[i].ContractMultiplier = multiplier
+		if class == DerivativeContract && b.Items[i].Role == Unset {
+			b.Items[i].Role = Contract
+		}
+		return nil
+	}
+
+	item := &Item{
+		Symbol:             symbol,
+		AssetClass:         class,
+		PeggedCurrency:     peg,
+		ContractMultiplier: multiplier,
+	}
+	if class == DerivativeContract {
+		item.Role = Contract
+	}
+	b.Items = append(b.Items, item)
+	return nil
+}
+
+// GetAssetClass returns the asset class of the currency code
+func (c Code) GetAssetClass() AssetClass {
+	if c.Item == nil {
+		return Unclassified
+	}
+	return c.Item.AssetClass
+}
+
+// IsStablecoin returns if the currency code is a stablecoin
+func (c Code) IsStablecoin() bool {
+	return c.GetAssetClass() == Stablecoin
+}
+
+// IsWrappedAsset returns if the currency code wraps another asset
+func (c Code) IsWrappedAsset() bool {
+	return c.GetAssetClass() == WrappedAsset
+}
+
+// IsDerivativeContract returns if the currency code is a derivative contract
+func (c Code) IsDerivativeContract() bool {
+	return c.GetAssetClass() == DerivativeContract
+}
+
+// IsIndex returns if the currency code is a price index
+func (c Code) IsIndex() bool {
+	return c.GetAssetClass() == Index
+}
+
+// GetPeggedCurrency returns the currency a stablecoin or wrapped asset is
+// pegged to, an empty code is returned when the code is not pegged
+func (c Code) GetPeggedCurrency() Code {
+	if c.Item == nil || c.Item.PeggedCurrency == "" {
+		return Code{}
+	}
+	return NewCode(c.Item.PeggedCurrency)
+}
+
+// GetContractMultiplier returns the value of a single derivative contract in
+// its underlying, one is returned for all other asset classes
+func (c Code) GetContractMultiplier() float64 {
+	if c.Item == nil || c.Item.ContractMultiplier == 0 {
+		return 1
+	}
+	return c.Item.ContractMultiplier
+}
+
+// valuationCode returns the fiat currency a stablecoin is pegged to so it can
+// be valued through foreign exchange rates, otherwise the code is returned
+// unchanged
+func (c Code) valuationCode() Code {
+	if !c.IsStablecoin() {
+		return c
+	}
+	peg := c.GetPeggedCurrency()
+	if peg.IsEmpty() {
+		return c
+	}
+	return peg
+}
@@ -0,0 +1,128 @@
+package currency
+
+import (
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+)
+
+func TestAssetClassString(t *testing.T) {
+	if Stablecoin.String() != StablecoinString {
+		t.Errorf("Test Failed - AssetClass String() error expected %s but received %s",
+			StablecoinString,
+			Stablecoin)
+	}
+
+	var random AssetClass = 1 << 7
+	if random.String() != "UNKNOWN" {
+		t.Errorf("Test Failed - AssetClass String() error expected %s but received %s",
+			"UNKNOWN",
+			random)
+	}
+}
+
+func TestAssetClassJSON(t *testing.T) {
+	d, err := common.JSONEncode(DerivativeContract)
+	if err != nil {
+		t.Fatal("Test Failed - AssetClass MarshalJSON() error", err)
+	}
+	if string(d) != `"derivativeContract"` {
+		t.Errorf("Test Failed - AssetClass MarshalJSON() unexpected result %s", d)
+	}
+
+	var a AssetClass
+	err = common.JSONDecode(d, &a)
+	if err != nil {
+		t.Fatal("Test Failed - AssetClass UnmarshalJSON() error", err)
+	}
+	if a != DerivativeContract {
+		t.Errorf("Test Failed - AssetClass UnmarshalJSON() expected %s but received %s",
+			DerivativeContract,
+			a)
+	}
+
+	err = common.JSONDecode([]byte(`"bla"`), &a)
+	if err == nil {
+		t.Error("Test Failed - AssetClass UnmarshalJSON() expected error")
+	}
+}
+
+func TestUpdateAssetClass(t *testing.T) {
+	var b BaseCodes
+	err := b.UpdateAssetClass("usdx", Stablecoin, "", 0)
+	if err == nil {
+		t.Error("Test Failed - UpdateAssetClass() expected error without peg")
+	}
+
+	err = b.UpdateAssetClass("XBTUSD", DerivativeContract, "", 0)
+	if err == nil {
+		t.Error("Test Failed - UpdateAssetClass() expected error without multiplier")
+	}
+
+	err = b.UpdateAssetClass("usdx", Stablecoin, "usd", 0)
+	if err != nil {
+		t.Fatal("Test Failed - UpdateAssetClass() error", err)
+	}
+	c := b.Register("USDX")
+	if !c.IsStablecoin() || c.Item.PeggedCurrency != "USD" {
+		t.Error("Test Failed - UpdateAssetClass() stablecoin not classified")
+	}
+
+	err = b.UpdateAssetClass("XBTUSD", DerivativeContract, "", 100)
+	if err != nil {
+		t.Fatal("Test Failed - UpdateAssetClass() error", err)
+	}
+	c = b.Register("XBTUSD")
+	if !c.IsDerivativeContract() || c.GetContractMultiplier() != 100 {
+		t.Error("Test Failed - UpdateAssetClass() contract not classified")
+	}
+	if c.Item.Role != Contract {
+		t.Errorf("Test Failed - UpdateAssetClass() expected role %s but received %s",
+			Contract,
+			c.Item.Role)
+	}
+
+	err = b.UpdateAssetClass("BXBT", Index, "BTC", 10)
+	if err != nil {
+		t.Fatal("Test Failed - UpdateAssetClass() error", err)
+	}
+	c = b.Register("BXBT")
+	if !c.IsIndex() || c.Item.PeggedCurrency != "" || c.GetContractMultiplier() != 1 {
+		t.Error("Test Failed - UpdateAssetClass() index metadata not cleared")
+	}
+
+	// Loading a file entry without an asset class keeps the seeded class
+	err = b.LoadItem(&Item{Symbol: "USDX", FullName: "USDX", Role: Token})
+	if err != nil {
+		t.Fatal("Test Failed - LoadItem() error", err)
+	}
+	if !b.Register("USDX").IsStablecoin() {
+		t.Error("Test Failed - LoadItem() overwrote asset class")
+	}
+}
+
+func TestDefaultAssetClasses(t *testing.T) {
+	if !USDT.IsStablecoin() || !USDC.IsStablecoin() || !DAI.IsStablecoin() {
+		t.Error("Test Failed - default stablecoins not classified")
+	}
+	if !USDT.GetPeggedCurrency().Match(USD) {
+		t.Errorf("Test Failed - USDT expected peg USD but received %s",
+			USDT.GetPeggedCurrency())
+	}
+	if !WBTC.IsWrappedAsset() || !WBTC.GetPeggedCurrency().Match(BTC) {
+		t.Error("Test Failed - WBTC not classified as wrapped BTC")
+	}
+	if BTC.GetAssetClass() != Unclassified || !BTC.GetPeggedCurrency().IsEmpty() {
+		t.Error("Test Failed - BTC should be unclassified")
+	}
+}
+
+func TestConvertCurrencyStablecoin(t *testing.T) {
+	v, err := ConvertCurrency(100, USDT, USD)
+	if err != nil {
+		t.Fatal("Test Failed - ConvertCurrency() error", err)
+	}
+	if v != 100 {
+		t.Errorf("Test Failed - ConvertCurrency() expected 100 but received %f", v)
+	}
+}
@@ -352,6 +352,14 @@ func (b *BaseCodes) LoadItem(item *Item) error {
 	defer b.mtx.Unlock()
 	for i := range b.Items {
 		if b.Items[i].Symbol == item.Symbol {
+			// Asset classes seeded in memory are kept when loading files which
+			// predate them
+			if item.AssetClass != Unclassified {
+				b.Items[i].AssetClass = item.AssetClass
+				b.Items[i].PeggedCurrency = item.PeggedCurrency
+				b.Items[i].ContractMultiplier = item.ContractMultiplier
+			}
+
 			if b.Items[i].Role == Unset {
 				b.Items[i].AssocChain = item.AssocChain
 				b.Items[i].AssocExchange = item.AssocExchange
@@ -411,6 +419,13 @@ type Item struct {
 	Role          Role     `json:"role"`
 	AssocChain    string   `json:"associatedBlockchain"`
 	AssocExchange []string `json:"associatedExchanges"`
+	// AssetClass further classifies the code for routing, risk and valuation
+	AssetClass AssetClass `json:"assetClass,omitempty"`
+	// PeggedCurrency is the symbol a stablecoin or wrapped asset tracks
+	PeggedCurrency string `json:"peggedCurrency,omitempty"`
+	// ContractMultiplier is the value of a single derivative contract in its
+	// underlying
+	ContractMultiplier float64 `json:"contractMultiplier,omitempty"`
 }
 
 // String conforms to the stringer interface
@@ -2099,4 +2114,8 @@ var (
 	ZWD        = NewCode("ZWD")
 	XETH       = NewCode("XETH")
 	FX_BTC     = NewCode("FX_BTC") // nolint: golint
+	USDC       = NewCode("USDC")
+	GUSD       = NewCode("GUSD")
+	BUSD       = NewCode("BUSD")
+	WBTC       = NewCode("WBTC")
 )
@@ -0,0 +1,145 @@
+// Package coingecko connects to the CoinGecko public API to retrieve
+// cryptocurrency metadata and market data. It is used as a secondary
+// cryptocurrency analysis provider when coinmarketcap is unavailable. Please
+// see https://www.coingecko.com/api/documentations/v3 for API documentation
+package coingecko
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/request"
+)
+
+// CoinGecko url, plan and endpoint consts
+const (
+	baseURL    = "https://api.coingecko.com/api"
+	proURL     = "https://pro-api.coingecko.com/api"
+	version    = "/v3/"
+	planPro    = "pro"
+	defaultKey = "Key"
+
+	endpointPing        = "ping"
+	endpointCoinsList   = "coins/list"
+	endpointSimplePrice = "simple/price"
+
+	// CoinGecko rate limits public requests to roughly 10 per minute
+	unauthrate     = 10
+	authrate       = 500
+	defaultTimeOut = time.Second * 15
+)
+
+// CoinGecko is the overarching type across this package
+type CoinGecko struct {
+	Verbose    bool
+	Enabled    bool
+	Name       string
+	APIkey     string
+	APIUrl     string
+	APIVersion string
+	Requester  *request.Requester
+}
+
+// SetDefaults sets default values for the provider
+func (c *CoinGecko) SetDefaults() {
+	c.Name = "CoinGecko"
+	c.Enabled = false
+	c.Verbose = false
+	c.APIUrl = baseURL
+	c.APIVersion = version
+	c.Requester = request.New(c.Name,
+		request.NewRateLimit(time.Minute, authrate),
+		request.NewRateLimit(time.Minute, unauthrate),
+		common.NewHTTPClientWithTimeout(defaultTimeOut))
+}
+
+// Setup sets user configuration
+func (c *CoinGecko) Setup(conf Settings) {
+	if !conf.Enabled {
+		c.Enabled = false
+		return
+	}
+
+	c.Enabled = true
+	c.Verbose = conf.Verbose
+	if conf.APIkey != "" && conf.APIkey != defaultKey {
+		c.APIkey = conf.APIkey
+		if strings.EqualFold(conf.AccountPlan, planPro) {
+			c.APIUrl = proURL
+		}
+	}
+}
+
+// Ping checks the API server status
+func (c *CoinGecko) Ping() (Ping, error) {
+	var resp Ping
+	return resp, c.SendHTTPRequest(http.MethodGet, endpointPing, nil, &resp)
+}
+
+// GetCoinsList returns all supported coins with their ID, name, symbol and
+// associated platform contract addresses
+func (c *CoinGecko) GetCoinsList() ([]Coin, error) {
+	var resp []Coin
+	val := url.Values{}
+	val.Set("include_platform", "true")
+
+	err := c.SendHTTPRequest(http.MethodGet, endpointCoinsList, val, &resp)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp) == 0 {
+		return nil, errors.New("coingecko returned an empty coin list")
+	}
+
+	return resp, nil
+}
+
+// GetSimplePrice returns the current price of the supplied coin IDs in the
+// supplied versus currencies
+//
+// coinIDs = coingecko IDs e.g. bitcoin, ethereum
+// vsCurrencies = target currencies e.g. usd, aud
+func (c *CoinGecko) GetSimplePrice(coinIDs, vsCurrencies []string) (SimplePrice, error) {
+	if len(coinIDs) == 0 || len(vsCurrencies) == 0 {
+		return nil, errors.New("coin IDs and versus currencies must be supplied")
+	}
+
+	val := url.Values{}
+	val.Set("ids", strings.ToLower(strings.Join(coinIDs, ",")))
+	val.Set("vs_currencies", strings.ToLower(strings.Join(vsCurrencies, ",")))
+
+	var resp SimplePrice
+	return resp, c.SendHTTPRequest(http.MethodGet, endpointSimplePrice, val, &resp)
+}
+
+// SendHTTPRequest sends a valid HTTP request
+func (c *CoinGecko) SendHTTPRequest(method, endpoint string, v url.Values, result interface{}) error {
+	headers := make(map[string]string)
+	headers["Accept"] = "application/json"
+
+	auth := false
+	if c.APIkey != "" {
+		headers["X-Cg-Pro-Api-Key"] = c.APIkey
+		auth = true
+	}
+
+	path := c.APIUrl + c.APIVersion + endpoint
+	if v != nil {
+		path = path + "?" + v.Encode()
+	}
+
+	return c.Requester.SendPayload(method,
+		path,
+		headers,
+		strings.NewReader(""),
+		result,
+		auth,
+		false,
+		c.Verbose,
+		false)
+}
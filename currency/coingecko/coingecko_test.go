@@ -0,0 +1,46 @@
+package coingecko
+
+import (
+	"testing"
+)
+
+var c CoinGecko
+
+func TestSetDefaults(t *testing.T) {
+	c.SetDefaults()
+	if c.Name != "CoinGecko" {
+		t.Error("Test Failed - SetDefaults() name not set")
+	}
+	if c.APIUrl != baseURL {
+		t.Error("Test Failed - SetDefaults() API URL not set")
+	}
+}
+
+func TestSetup(t *testing.T) {
+	c.SetDefaults()
+	c.Setup(Settings{Enabled: false})
+	if c.Enabled {
+		t.Error("Test Failed - Setup() should not enable provider")
+	}
+
+	c.Setup(Settings{Enabled: true, APIkey: defaultKey})
+	if !c.Enabled {
+		t.Error("Test Failed - Setup() should enable provider")
+	}
+	if c.APIkey != "" {
+		t.Error("Test Failed - Setup() default API key should be ignored")
+	}
+
+	c.Setup(Settings{Enabled: true, APIkey: "test", AccountPlan: "pro"})
+	if c.APIUrl != proURL {
+		t.Error("Test Failed - Setup() pro plan should use pro API URL")
+	}
+}
+
+func TestGetSimplePrice(t *testing.T) {
+	c.SetDefaults()
+	_, err := c.GetSimplePrice(nil, []string{"usd"})
+	if err == nil {
+		t.Error("Test Failed - GetSimplePrice() error cannot be nil")
+	}
+}
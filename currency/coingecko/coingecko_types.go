@@ -0,0 +1,32 @@
+package coingecko
+
+// Settings defines the current settings from configuration file
+type Settings struct {
+	Name        string `json:"name"`
+	Enabled     bool   `json:"enabled"`
+	Verbose     bool   `json:"verbose"`
+	APIkey      string `json:"apiKey"`
+	AccountPlan string `json:"accountPlan"`
+}
+
+// Coin defines a singular coin entry returned by the coins list endpoint
+type Coin struct {
+	ID        string            `json:"id"`
+	Symbol    string            `json:"symbol"`
+	Name      string            `json:"name"`
+	Platforms map[string]string `json:"platforms"`
+}
+
+// Ping defines the server status response
+type Ping struct {
+	GeckoSays string `json:"gecko_says"`
+}
+
+// SimplePrice defines a price lookup response keyed by coin ID then by versus
+// currency
+type SimplePrice map[string]map[string]float64
+
+// Error defines an error response returned by the API
+type Error struct {
+	Error string `json:"error"`
+}
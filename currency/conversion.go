@@ -3,16 +3,38 @@ package currency
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
+	"time"
 
 	log "github.com/thrasher-corp/gocryptotrader/logger"
 )
 
+// Rate history settings
+const (
+	// RateHistoryRetention is how long past conversion rates are kept
+	RateHistoryRetention = time.Hour * 24 * 30
+	// RateHistoryInterval is the shortest time between recorded rates, rate
+	// updates in between are not recorded
+	RateHistoryInterval = time.Minute * 10
+)
+
+var errNoRateHistory = errors.New("no conversion rates recorded")
+
+// rateSnapshot holds the rates from the base currency to every currency as
+// they were updated at a time
+type rateSnapshot struct {
+	time  time.Time
+	base  *Item
+	rates map[*Item]float64
+}
+
 // ConversionRates defines protected conversion rate map for concurrent updating
 // and retrieval of foreign exchange rates for mainly fiat currencies
 type ConversionRates struct {
-	m   map[*Item]map[*Item]*float64
-	mtx sync.Mutex
+	m       map[*Item]map[*Item]*float64
+	history []rateSnapshot
+	mtx     sync.Mutex
 }
 
 // HasData returns if conversion rates are present
@@ -60,6 +82,73 @@ func (c *ConversionRates) GetRate(from, to Code) (float64, error) {
 	return *p, nil
 }
 
+// GetRateAt returns the rate recorded in the rate history at or before the
+// time
+func (c *ConversionRates) GetRateAt(from, to Code, t time.Time) (float64, error) {
+	if from.Item == USDT.Item {
+		from = USD
+	}
+
+	if to.Item == USDT.Item {
+		to = USD
+	}
+
+	if from.Item == RUR.Item {
+		from = RUB
+	}
+
+	if to.Item == RUR.Item {
+		to = RUB
+	}
+
+	if from.Item == to.Item {
+		return 1, nil
+	}
+
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+
+	i := sort.Search(len(c.history), func(i int) bool {
+		return c.history[i].time.After(t)
+	})
+	if i == 0 {
+		return 0, fmt.Errorf("%v at or before %s", errNoRateHistory, t.Format(time.RFC3339))
+	}
+	snapshot := c.history[i-1]
+	fromRate, ok := snapshot.rates[from.Item]
+	if !ok {
+		return 0, fmt.Errorf("rate not recorded for %s at %s", from, snapshot.time.Format(time.RFC3339))
+	}
+	toRate, ok := snapshot.rates[to.Item]
+	if !ok {
+		return 0, fmt.Errorf("rate not recorded for %s at %s", to, snapshot.time.Format(time.RFC3339))
+	}
+	return toRate / fromRate, nil
+}
+
+// record adds the base currency rates to the rate history, dropping rates
+// older than the retention
+func (c *ConversionRates) record(now time.Time, base Code, rates map[Code]float64) {
+	if n := len(c.history); n > 0 && now.Sub(c.history[n-1].time) < RateHistoryInterval {
+		return
+	}
+
+	snapshot := rateSnapshot{
+		time:  now,
+		base:  base.Item,
+		rates: map[*Item]float64{base.Item: 1},
+	}
+	for code, rate := range rates {
+		snapshot.rates[code.Item] = rate
+	}
+
+	cutoff := now.Add(-RateHistoryRetention)
+	i := sort.Search(len(c.history), func(i int) bool {
+		return c.history[i].time.After(cutoff)
+	})
+	c.history = append(c.history[i:], snapshot)
+}
+
 // Register registers a new conversion rate if not found adds it and allows for
 // quick updates
 func (c *ConversionRates) Register(from, to Code) (Conversion, error) {
@@ -206,6 +295,7 @@ func (c *ConversionRates) Update(m map[string]float64) error {
 			}
 		}
 	}
+	c.record(time.Now(), mainBaseCurrency, solidvalues[mainBaseCurrency])
 
 	c.m = nil
 	for key, val := range solidvalues {
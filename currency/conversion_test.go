@@ -1,7 +1,9 @@
 package currency
 
 import (
+	"math"
 	"testing"
+	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
 )
@@ -175,3 +177,49 @@ func TestConversionsRatesSystem(t *testing.T) {
 			inverseR)
 	}
 }
+
+func TestConversionRatesHistory(t *testing.T) {
+	var c ConversionRates
+	start := time.Now()
+	if _, err := c.GetRateAt(USD, EUR, start); err == nil {
+		t.Error("Test Failed - GetRateAt() expected no rate history error")
+	}
+
+	c.record(start, USD, map[Code]float64{EUR: 0.9, GBP: 0.8})
+	c.record(start.Add(time.Minute), USD, map[Code]float64{EUR: 0.5, GBP: 0.5})
+	c.record(start.Add(RateHistoryInterval), USD, map[Code]float64{EUR: 0.95, GBP: 0.75})
+	if len(c.history) != 2 {
+		t.Fatalf("Test Failed - record() expected updates within the interval skipped, got %d snapshots", len(c.history))
+	}
+
+	for _, tc := range []struct {
+		from, to Code
+		at       time.Time
+		expected float64
+	}{
+		{USD, EUR, start, 0.9},
+		{USDT, EUR, start.Add(time.Minute * 5), 0.9},
+		{EUR, USD, start.Add(RateHistoryInterval), 1 / 0.95},
+		{GBP, EUR, start.Add(time.Hour), 0.95 / 0.75},
+		{EUR, EUR, start.Add(-time.Hour), 1},
+	} {
+		r, err := c.GetRateAt(tc.from, tc.to, tc.at)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(r-tc.expected) > 1e-12 {
+			t.Errorf("Test Failed - GetRateAt() %s%s expected %v, got %v", tc.from, tc.to, tc.expected, r)
+		}
+	}
+	if _, err := c.GetRateAt(USD, EUR, start.Add(-time.Second)); err == nil {
+		t.Error("Test Failed - GetRateAt() expected no rate before the first snapshot")
+	}
+	if _, err := c.GetRateAt(USD, JPY, start); err == nil {
+		t.Error("Test Failed - GetRateAt() expected rate not recorded error")
+	}
+
+	c.record(start.Add(RateHistoryRetention+time.Minute), USD, map[Code]float64{EUR: 1})
+	if len(c.history) != 2 || !c.history[0].time.Equal(start.Add(RateHistoryInterval)) {
+		t.Errorf("Test Failed - record() expected rates beyond the retention dropped, got %d snapshots", len(c.history))
+	}
+}
@@ -1,5 +1,11 @@
 package currency
 
+import (
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency/forexprovider/base"
+)
+
 // GetDefaultExchangeRates returns the currency exchange rates based off the
 // default fiat values
 func GetDefaultExchangeRates() (Conversions, error) {
@@ -61,11 +67,22 @@ func ConvertCurrency(amount float64, from, to Code) (float64, error) {
 	return storage.ConvertCurrency(amount, from, to)
 }
 
+// GetRateAt returns the conversion rate between currencies recorded at or
+// before the time
+func GetRateAt(from, to Code, t time.Time) (float64, error) {
+	return storage.GetStorageRateAt(from, to, t)
+}
+
 // SeedForeignExchangeData seeds FX data with the currencies supplied
 func SeedForeignExchangeData(c Currencies) error {
 	return storage.SeedForeignExchangeRatesByCurrencies(c)
 }
 
+// GetForexProviderHealth returns the health of the foreign exchange providers
+func GetForexProviderHealth() ([]base.ProviderHealth, error) {
+	return storage.GetForexProviderHealth()
+}
+
 // GetTotalMarketCryptocurrencies returns the full market cryptocurrencies
 func GetTotalMarketCryptocurrencies() ([]Code, error) {
 	return storage.GetTotalMarketCryptocurrencies()
@@ -76,6 +93,34 @@ func RunStorageUpdater(o BotOverrides, m *MainConfiguration, filepath string, v
 	return storage.RunUpdater(o, m, filepath, v)
 }
 
+// UpdateAssetClass sets the asset class of a currency code along with the
+// currency it is pegged to and its contract multiplier where applicable
+func UpdateAssetClass(c Code, class AssetClass, peg Code, multiplier float64) error {
+	return storage.UpdateAssetClass(c, class, peg, multiplier)
+}
+
+// StopStorageUpdater stops the running foreign exchange updater instance
+func StopStorageUpdater() error {
+	return storage.Stop()
+}
+
+// RestartStorageUpdater restarts the foreign exchange updater instance
+func RestartStorageUpdater() error {
+	return storage.Restart()
+}
+
+// IsStorageUpdaterRunning returns whether the foreign exchange updater is
+// running
+func IsStorageUpdaterRunning() bool {
+	return storage.IsRunning()
+}
+
+// SetStorageUpdateDelays changes the foreign exchange and currency file update
+// delays of the storage updater
+func SetStorageUpdateDelays(foreignExchange, currencyFile time.Duration) error {
+	return storage.SetUpdateDelays(foreignExchange, currencyFile)
+}
+
 // CopyPairFormat copies the pair format from a list of pairs once matched
 func CopyPairFormat(p Pair, pairs []Pair, exact bool) Pair {
 	for x := range pairs {
@@ -3,18 +3,20 @@ package currency
 import (
 	"time"
 
+	"github.com/thrasher-corp/gocryptotrader/currency/coingecko"
 	"github.com/thrasher-corp/gocryptotrader/currency/coinmarketcap"
 )
 
 // MainConfiguration is the main configuration from the config.json file
 type MainConfiguration struct {
-	ForexProviders         []FXSettings
-	CryptocurrencyProvider coinmarketcap.Settings
-	Cryptocurrencies       Currencies
-	CurrencyPairFormat     interface{}
-	FiatDisplayCurrency    Code
-	CurrencyDelay          time.Duration
-	FxRateDelay            time.Duration
+	ForexProviders                  []FXSettings
+	CryptocurrencyProvider          coinmarketcap.Settings
+	SecondaryCryptocurrencyProvider coingecko.Settings
+	Cryptocurrencies                Currencies
+	CurrencyPairFormat              interface{}
+	FiatDisplayCurrency             Code
+	CurrencyDelay                   time.Duration
+	FxRateDelay                     time.Duration
 }
 
 // BotOverrides defines a bot overriding factor for quick running currency
@@ -25,6 +27,7 @@ type BotOverrides struct {
 	FxCurrencyLayer     bool
 	FxFixer             bool
 	FxOpenExchangeRates bool
+	FxExchangeRates     bool
 }
 
 // CoinmarketcapSettings refers to settings
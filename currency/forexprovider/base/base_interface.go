@@ -4,8 +4,18 @@ import (
 	"errors"
 	"fmt"
 	"sync"
+	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Health scoring defaults used to determine when a primary provider should be
+// demoted in favour of a healthier supporting provider
+const (
+	DefaultMaxConsecutiveFailures = 3
+	DefaultMaxErrorRate           = 0.5
+	healthWindow                  = 20
 )
 
 // IFXProvider enforces standard functions for all foreign exchange providers
@@ -27,6 +37,71 @@ type FXHandler struct {
 	mtx     sync.Mutex
 }
 
+// Health defines request outcome tracking for a singular provider, the error
+// rate is calculated across a rolling window of the most recent requests
+type Health struct {
+	Requests            int64
+	Failures            int64
+	ConsecutiveFailures int64
+	LastFailure         time.Time
+	LastError           string
+	window              []bool
+}
+
+// ProviderHealth defines a snapshot of a providers health for reporting
+type ProviderHealth struct {
+	Name                string
+	Primary             bool
+	Requests            int64
+	Failures            int64
+	ConsecutiveFailures int64
+	ErrorRate           float64
+	LastFailure         time.Time
+	LastError           string
+}
+
+// record adds a request outcome to the health window
+func (h *Health) record(err error) {
+	h.Requests++
+	if err != nil {
+		h.Failures++
+		h.ConsecutiveFailures++
+		h.LastFailure = time.Now()
+		h.LastError = err.Error()
+	} else {
+		h.ConsecutiveFailures = 0
+	}
+
+	h.window = append(h.window, err != nil)
+	if len(h.window) > healthWindow {
+		h.window = h.window[len(h.window)-healthWindow:]
+	}
+}
+
+// ErrorRate returns the failure ratio across the rolling window
+func (h *Health) ErrorRate() float64 {
+	if len(h.window) == 0 {
+		return 0
+	}
+	var failed float64
+	for i := range h.window {
+		if h.window[i] {
+			failed++
+		}
+	}
+	return failed / float64(len(h.window))
+}
+
+// IsUnhealthy returns true if the provider has exceeded the consecutive
+// failure limit or the rolling error rate threshold
+func (h *Health) IsUnhealthy() bool {
+	if h.ConsecutiveFailures >= DefaultMaxConsecutiveFailures {
+		return true
+	}
+	return len(h.window) >= DefaultMaxConsecutiveFailures &&
+		h.ErrorRate() > DefaultMaxErrorRate
+}
+
 // Provider defines a singular foreign exchange provider with its supported
 // currencies to cross reference request currencies and if not supported shunt
 // request traffic to and from other providers so that we can maintain full
@@ -34,6 +109,7 @@ type FXHandler struct {
 type Provider struct {
 	Provider            IFXProvider
 	SupportedCurrencies []string
+	Health              Health
 }
 
 // GetNewRate access rates by predetermined logic based on how a provider
@@ -44,13 +120,18 @@ func (p *Provider) GetNewRate(base string, currencies []string) (map[string]floa
 			p.Provider.GetName())
 	}
 
+	var rates map[string]float64
+	var err error
 	switch p.Provider.GetName() {
 	case "ExchangeRates":
-		return p.Provider.GetRates(base, "") // Zero value to get all rates
+		rates, err = p.Provider.GetRates(base, "") // Zero value to get all rates
 
 	default:
-		return p.Provider.GetRates(base, common.JoinStrings(currencies, ","))
+		rates, err = p.Provider.GetRates(base, common.JoinStrings(currencies, ","))
 	}
+
+	p.Health.record(err)
+	return rates, err
 }
 
 // CheckCurrencies cross references supplied currencies with exchange supported
@@ -81,6 +162,8 @@ func (f *FXHandler) GetCurrencyData(baseCurrency string, currencies []string) (m
 		return nil, errors.New("primary foreign exchange provider details not set")
 	}
 
+	defer f.checkPrimaryHealth()
+
 	shunt := f.Primary.CheckCurrencies(fullRange)
 	rates, err := f.Primary.GetNewRate(baseCurrency, currencies)
 	if err != nil {
@@ -153,3 +236,65 @@ func (f *FXHandler) backupGetRate(base string, currencies []string) (map[string]
 
 	return nil, fmt.Errorf("currencies %s not supported", shunt)
 }
+
+// checkPrimaryHealth demotes an unhealthy primary provider and promotes the
+// healthiest supporting provider in its place
+func (f *FXHandler) checkPrimaryHealth() {
+	if !f.Primary.Health.IsUnhealthy() || len(f.Support) == 0 {
+		return
+	}
+
+	target := -1
+	for i := range f.Support {
+		if !f.Support[i].Provider.IsEnabled() ||
+			f.Support[i].Health.IsUnhealthy() {
+			continue
+		}
+		if target == -1 ||
+			f.Support[i].Health.ErrorRate() < f.Support[target].Health.ErrorRate() {
+			target = i
+		}
+	}
+
+	if target == -1 {
+		return
+	}
+
+	log.Warnf("Forex provider %s unhealthy (error rate %.2f, consecutive failures %d), promoting %s to primary",
+		f.Primary.Provider.GetName(),
+		f.Primary.Health.ErrorRate(),
+		f.Primary.Health.ConsecutiveFailures,
+		f.Support[target].Provider.GetName())
+
+	f.Primary, f.Support[target] = f.Support[target], f.Primary
+}
+
+// GetProviderHealth returns a health snapshot of all providers starting with
+// the primary provider
+func (f *FXHandler) GetProviderHealth() []ProviderHealth {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+
+	var health []ProviderHealth
+	if f.Primary.Provider != nil {
+		health = append(health, f.Primary.getHealth(true))
+	}
+	for i := range f.Support {
+		health = append(health, f.Support[i].getHealth(false))
+	}
+	return health
+}
+
+// getHealth returns a health snapshot of the provider
+func (p *Provider) getHealth(primary bool) ProviderHealth {
+	return ProviderHealth{
+		Name:                p.Provider.GetName(),
+		Primary:             primary,
+		Requests:            p.Health.Requests,
+		Failures:            p.Health.Failures,
+		ConsecutiveFailures: p.Health.ConsecutiveFailures,
+		ErrorRate:           p.Health.ErrorRate(),
+		LastFailure:         p.Health.LastFailure,
+		LastError:           p.Health.LastError,
+	}
+}
@@ -0,0 +1,86 @@
+package base
+
+import (
+	"errors"
+	"testing"
+)
+
+type testProvider struct {
+	Base
+	fail bool
+}
+
+func (t *testProvider) Setup(config Settings) error {
+	t.Settings = config
+	return nil
+}
+
+func (t *testProvider) GetRates(baseCurrency, symbols string) (map[string]float64, error) {
+	if t.fail {
+		return nil, errors.New("provider offline")
+	}
+	return map[string]float64{baseCurrency + "AUD": 1.5}, nil
+}
+
+func (t *testProvider) GetSupportedCurrencies() ([]string, error) {
+	return []string{"USD", "AUD"}, nil
+}
+
+func newTestProvider(name string, fail bool) Provider {
+	p := &testProvider{fail: fail}
+	p.Setup(Settings{Name: name, Enabled: true})
+	return Provider{
+		Provider:            p,
+		SupportedCurrencies: []string{"USD", "AUD"},
+	}
+}
+
+func TestHealthErrorRate(t *testing.T) {
+	var h Health
+	if h.ErrorRate() != 0 {
+		t.Error("Test Failed - ErrorRate() expected zero on empty window")
+	}
+
+	h.record(nil)
+	h.record(errors.New("fail"))
+	if h.ErrorRate() != 0.5 {
+		t.Errorf("Test Failed - ErrorRate() expected 0.5 received %v", h.ErrorRate())
+	}
+
+	for i := 0; i < healthWindow; i++ {
+		h.record(nil)
+	}
+	if h.ErrorRate() != 0 {
+		t.Error("Test Failed - ErrorRate() window should roll off old failures")
+	}
+	if h.Requests != healthWindow+2 || h.Failures != 1 {
+		t.Error("Test Failed - record() totals incorrect")
+	}
+}
+
+func TestPrimaryPromotion(t *testing.T) {
+	f := FXHandler{
+		Primary: newTestProvider("broken", true),
+		Support: []Provider{newTestProvider("healthy", false)},
+	}
+
+	for i := 0; i < DefaultMaxConsecutiveFailures; i++ {
+		_, err := f.GetCurrencyData("USD", []string{"AUD"})
+		if err != nil {
+			t.Fatal("Test Failed - GetCurrencyData() backup should succeed", err)
+		}
+	}
+
+	if f.Primary.Provider.GetName() != "healthy" {
+		t.Errorf("Test Failed - expected healthy provider to be promoted received %s",
+			f.Primary.Provider.GetName())
+	}
+
+	health := f.GetProviderHealth()
+	if len(health) != 2 || !health[0].Primary || health[1].Name != "broken" {
+		t.Error("Test Failed - GetProviderHealth() unexpected snapshot")
+	}
+	if health[1].ConsecutiveFailures != DefaultMaxConsecutiveFailures {
+		t.Error("Test Failed - GetProviderHealth() failures not tracked")
+	}
+}
@@ -66,9 +66,7 @@ func (f *ForexProviders) SetProvider(b base.IFXProvider) error {
 	}
 
 	if b.IsPrimaryProvider() {
-		f.FXHandler = base.FXHandler{
-			Primary: providerBase,
-		}
+		f.FXHandler.Primary = providerBase
 		return nil
 	}
 
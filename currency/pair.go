@@ -110,6 +110,10 @@ func (p *Pair) UnmarshalJSON(d []byte) error {
 	if err != nil {
 		return err
 	}
+	if pair == "" {
+		*p = Pair{}
+		return nil
+	}
 
 	*p = NewPairFromString(pair)
 	return nil
@@ -56,6 +56,13 @@ func TestPairUnmarshalJSON(t *testing.T) {
 		t.Errorf("Test Failed - Pairs UnmarshalJSON() error expected %s but received %s",
 			configPair, unmarshalHere)
 	}
+
+	// Empty pairs such as those of unset fields round trip to the zero value
+	err = common.JSONDecode([]byte(`""`), &unmarshalHere)
+	if err != nil || !unmarshalHere.IsEmpty() {
+		t.Errorf("Test Failed - Pair UnmarshalJSON() expected empty pair, received %s %v",
+			unmarshalHere, err)
+	}
 }
 
 func TestPairMarshalJSON(t *testing.T) {
@@ -1,13 +1,16 @@
 package currency
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency/coingecko"
 	"github.com/thrasher-corp/gocryptotrader/currency/coinmarketcap"
 	"github.com/thrasher-corp/gocryptotrader/currency/forexprovider"
 	"github.com/thrasher-corp/gocryptotrader/currency/forexprovider/base"
@@ -21,6 +24,13 @@ const (
 	DefaultForeignExchangeDelay = 1 * time.Minute
 )
 
+var (
+	errUpdaterRunning    = errors.New("currency storage updater already running")
+	errUpdaterNotRunning = errors.New("currency storage updater not running")
+	errNoForexProviders  = errors.New("currency storage no foreign exchange providers set")
+	errInvalidDelay      = errors.New("currency storage update delays must be positive")
+)
+
 func init() {
 	storage.SetDefaults()
 }
@@ -65,8 +75,9 @@ type Storage struct {
 	fiatExchangeMarkets *forexprovider.ForexProviders
 
 	// CurrencyAnalysis defines a full market analysis suite to receieve and
-	// define different fiat currencies, cryptocurrencies and markets
-	currencyAnalysis *coinmarketcap.Coinmarketcap
+	// define different fiat currencies, cryptocurrencies and markets. Providers
+	// are ordered by priority and fall through on failure
+	currencyAnalysis []AnalysisProvider
 
 	// Path defines the main folder to dump and find currency JSON
 	path string
@@ -77,7 +88,7 @@ type Storage struct {
 
 	mtx            sync.Mutex
 	wg             sync.WaitGroup
-	shutdownC      chan struct{}
+	cancel         context.CancelFunc
 	updaterRunning bool
 	Verbose        bool
 }
@@ -90,28 +101,68 @@ func (s *Storage) SetDefaults() {
 	s.SetDefaultCryptocurrencies(BTC, LTC, ETH, DOGE, DASH, XRP, XMR)
 	s.SetupConversionRates()
 	s.fiatExchangeMarkets = forexprovider.NewDefaultFXProvider()
+	s.SetDefaultAssetClasses()
+}
+
+// SetDefaultAssetClasses classifies well known stablecoins and wrapped assets
+func (s *Storage) SetDefaultAssetClasses() {
+	for i := range defaultAssetClasses {
+		err := s.currencyCodes.UpdateAssetClass(defaultAssetClasses[i].Symbol,
+			defaultAssetClasses[i].Class,
+			defaultAssetClasses[i].Peg,
+			0)
+		if err != nil {
+			log.Errorf("Failed to set default asset class for %s: %s",
+				defaultAssetClasses[i].Symbol,
+				err)
+		}
+	}
+}
+
+// UpdateAssetClass sets the asset class and metadata of a currency code
+func (s *Storage) UpdateAssetClass(c Code, class AssetClass, peg Code, multiplier float64) error {
+	if c.IsEmpty() {
+		return errors.New("cannot classify an empty currency code")
+	}
+	return s.currencyCodes.UpdateAssetClass(c.Item.Symbol,
+		class,
+		peg.Upper().String(),
+		multiplier)
 }
 
 // RunUpdater runs the foreign exchange updater service. This will set up a JSON
 // dump file and keep foreign exchange rates updated as fast as possible without
 // triggering rate limiters, it will also run a full cryptocurrency check
-// through coin market cap and expose analytics for exchange services
+// through coin market cap, falling back to coingecko, and expose analytics for
+// exchange services. Calling it while the updater is running stops the running
+// instance and starts it again with the new settings
 func (s *Storage) RunUpdater(overrides BotOverrides, settings *MainConfiguration, filePath string, verbose bool) error {
 	s.mtx.Lock()
+	defer s.mtx.Unlock()
 
 	if !settings.Cryptocurrencies.HasData() {
-		s.mtx.Unlock()
 		return errors.New("currency storage error, no cryptocurrencies loaded")
 	}
 	s.cryptocurrencies = settings.Cryptocurrencies
 
 	if settings.FiatDisplayCurrency.IsEmpty() {
-		s.mtx.Unlock()
 		return errors.New("currency storage error, no fiat display currency set in config")
 	}
 	s.baseCurrency = settings.FiatDisplayCurrency
 	log.Debugf("Fiat display currency: %s.", s.baseCurrency)
 
+	if filePath == "" {
+		return errors.New("currency package runUpdater error filepath not set")
+	}
+
+	if s.updaterRunning {
+		err := s.stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	s.currencyAnalysis = nil
 	if settings.CryptocurrencyProvider.Enabled {
 		log.Debugf("Setting up currency analysis system with Coinmarketcap...")
 		c := &coinmarketcap.Coinmarketcap{}
@@ -124,12 +175,15 @@ func (s *Storage) RunUpdater(overrides BotOverrides, settings *MainConfiguration
 			Verbose:     settings.CryptocurrencyProvider.Verbose,
 		})
 
-		s.currencyAnalysis = c
+		s.currencyAnalysis = append(s.currencyAnalysis, coinmarketcapProvider{c})
 	}
 
-	if filePath == "" {
-		s.mtx.Unlock()
-		return errors.New("currency package runUpdater error filepath not set")
+	if settings.SecondaryCryptocurrencyProvider.Enabled {
+		log.Debugf("Setting up secondary currency analysis system with CoinGecko...")
+		g := &coingecko.CoinGecko{}
+		g.SetDefaults()
+		g.Setup(settings.SecondaryCryptocurrencyProvider)
+		s.currencyAnalysis = append(s.currencyAnalysis, coingeckoProvider{g})
 	}
 
 	s.path = filePath + common.GetOSPathSlash() + "currency.json"
@@ -180,8 +234,8 @@ func (s *Storage) RunUpdater(overrides BotOverrides, settings *MainConfiguration
 			}
 
 		case "ExchangeRates":
-			// TODO ADD OVERRIDE
-			if settings.ForexProviders[i].Enabled {
+			if overrides.FxExchangeRates ||
+				settings.ForexProviders[i].Enabled {
 				settings.ForexProviders[i].Enabled = true
 				fxSettings = append(fxSettings,
 					base.Settings(settings.ForexProviders[i]))
@@ -193,7 +247,6 @@ func (s *Storage) RunUpdater(overrides BotOverrides, settings *MainConfiguration
 		var err error
 		s.fiatExchangeMarkets, err = forexprovider.StartFXService(fxSettings)
 		if err != nil {
-			s.mtx.Unlock()
 			return err
 		}
 
@@ -205,17 +258,126 @@ func (s *Storage) RunUpdater(overrides BotOverrides, settings *MainConfiguration
 				s.fiatExchangeMarkets.Support[i].Provider.GetName())
 		}
 
-		// Mutex present in this go routine to lock down retrieving rate data
-		// until this system initially updates
-		go s.ForeignExchangeUpdater()
-	} else {
-		log.Warnf("No foreign exchange providers enabled in config.json")
-		s.mtx.Unlock()
+		return s.start()
 	}
 
+	log.Warnf("No foreign exchange providers enabled in config.json")
 	return nil
 }
 
+// Start starts the foreign exchange updater using the currently configured
+// providers and update delays
+func (s *Storage) Start() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.start()
+}
+
+// Stop stops the foreign exchange updater and waits for it to exit
+func (s *Storage) Stop() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.stop()
+}
+
+// Restart stops the foreign exchange updater if it is running and starts it
+// again, picking up any provider or delay changes
+func (s *Storage) Restart() error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.updaterRunning {
+		err := s.stop()
+		if err != nil {
+			return err
+		}
+	}
+	return s.start()
+}
+
+// IsRunning returns whether the foreign exchange updater is running
+func (s *Storage) IsRunning() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.updaterRunning
+}
+
+// SetUpdateDelays changes the foreign exchange and currency file update
+// delays, restarting the updater if it is running
+func (s *Storage) SetUpdateDelays(foreignExchange, currencyFile time.Duration) error {
+	if foreignExchange <= 0 || currencyFile <= 0 {
+		return errInvalidDelay
+	}
+	return s.reconfigure(func() error {
+		s.foreignExchangeUpdateDelay = foreignExchange
+		s.currencyFileUpdateDelay = currencyFile
+		return nil
+	})
+}
+
+// start launches the updater routine, s.mtx must be held
+func (s *Storage) start() error {
+	if s.updaterRunning {
+		return errUpdaterRunning
+	}
+	if s.fiatExchangeMarkets == nil {
+		return errNoForexProviders
+	}
+	if s.foreignExchangeUpdateDelay <= 0 {
+		s.foreignExchangeUpdateDelay = DefaultForeignExchangeDelay
+	}
+	if s.currencyFileUpdateDelay <= 0 {
+		s.currencyFileUpdateDelay = DefaultCurrencyFileDelay
+	}
+
+	var ctx context.Context
+	ctx, s.cancel = context.WithCancel(context.Background())
+	s.updaterRunning = true
+	s.wg.Add(1)
+	go s.foreignExchangeUpdater(ctx)
+	return nil
+}
+
+// stop cancels the updater routine and waits for it to return, s.mtx must be
+// held
+func (s *Storage) stop() error {
+	if !s.updaterRunning {
+		return errUpdaterNotRunning
+	}
+	s.cancel()
+	s.wg.Wait()
+	s.cancel = nil
+	s.updaterRunning = false
+	log.Debugf("Foreign exchange updater stopped")
+	return nil
+}
+
+// reconfigure applies a configuration change while the updater is stopped so
+// the running routine never observes a partial change, the updater is started
+// again if it was running beforehand
+func (s *Storage) reconfigure(change func() error) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	wasRunning := s.updaterRunning
+	if wasRunning {
+		err := s.stop()
+		if err != nil {
+			return err
+		}
+	}
+
+	err := change()
+	if !wasRunning {
+		return err
+	}
+
+	startErr := s.start()
+	if err != nil {
+		return err
+	}
+	return startErr
+}
+
 // SetupConversionRates sets default conversion rate values
 func (s *Storage) SetupConversionRates() {
 	s.fxRates = ConversionRates{
@@ -241,39 +403,40 @@ func (s *Storage) SetDefaultCryptocurrencies(c ...Code) {
 	}
 }
 
-// SetupForexProviders sets up a new instance of the forex providers
+// SetupForexProviders sets up a new instance of the forex providers,
+// restarting the updater if it is running
 func (s *Storage) SetupForexProviders(setting ...base.Settings) error {
-	addr, err := forexprovider.StartFXService(setting)
-	if err != nil {
-		return err
-	}
+	return s.reconfigure(func() error {
+		addr, err := forexprovider.StartFXService(setting)
+		if err != nil {
+			return err
+		}
 
-	s.fiatExchangeMarkets = addr
-	return nil
+		s.fiatExchangeMarkets = addr
+		return nil
+	})
 }
 
-// ForeignExchangeUpdater is a routine that seeds foreign exchange rate and keeps
-// updated as fast as possible
-func (s *Storage) ForeignExchangeUpdater() {
-	log.Debugf("Foreign exchange updater started, seeding FX rate list..")
-
-	s.wg.Add(1)
+// foreignExchangeUpdater is a routine that seeds foreign exchange rate and keeps
+// updated as fast as possible until the context is cancelled
+func (s *Storage) foreignExchangeUpdater(ctx context.Context) {
 	defer s.wg.Done()
+	log.Debugf("Foreign exchange updater started, seeding FX rate list..")
 
 	err := s.SeedCurrencyAnalysisData()
 	if err != nil {
 		log.Error(err)
 	}
 
+	if ctx.Err() != nil {
+		return
+	}
+
 	err = s.SeedForeignExchangeRates()
 	if err != nil {
 		log.Error(err)
 	}
 
-	// Unlock main rate retrieval mutex so all routines waiting can get access
-	// to data
-	s.mtx.Unlock()
-
 	// Set tickers to client defined rates or defaults
 	SeedForeignExchangeTick := time.NewTicker(s.foreignExchangeUpdateDelay)
 	SeedCurrencyAnalysisTick := time.NewTicker(s.currencyFileUpdateDelay)
@@ -282,7 +445,7 @@ func (s *Storage) ForeignExchangeUpdater() {
 
 	for {
 		select {
-		case <-s.shutdownC:
+		case <-ctx.Done():
 			return
 
 		case <-SeedForeignExchangeTick.C:
@@ -340,8 +503,8 @@ func (s *Storage) SeedCurrencyAnalysisData() error {
 // FetchCurrencyAnalysisData fetches a new fresh batch of currency data and
 // loads it into memory
 func (s *Storage) FetchCurrencyAnalysisData() error {
-	if s.currencyAnalysis == nil {
-		log.Warn("Currency analysis system offline please set api keys for coinmarketcap")
+	if len(s.currencyAnalysis) == 0 {
+		log.Warn("Currency analysis system offline please set api keys for coinmarketcap or enable coingecko")
 		return errors.New("currency analysis system offline")
 	}
 
@@ -413,32 +576,46 @@ func (s *Storage) LoadFileCurrencyData(f *File) error {
 	return nil
 }
 
-// UpdateCurrencies updates currency roll and information using coin market cap
+// UpdateCurrencies updates currency roll and information using the enabled
+// analysis providers, if a provider fails the next provider is used
 func (s *Storage) UpdateCurrencies() error {
-	m, err := s.currencyAnalysis.GetCryptocurrencyIDMap()
-	if err != nil {
-		return err
-	}
-
-	for x := range m {
-		if m[x].IsActive != 1 {
+	var errs []string
+	for i := range s.currencyAnalysis {
+		listings, err := s.currencyAnalysis[i].GetListings()
+		if err != nil {
+			log.Warnf("Currency analysis provider %s failed: %s",
+				s.currencyAnalysis[i].GetName(),
+				err)
+			errs = append(errs, err.Error())
 			continue
 		}
+		return s.updateCurrencyCodes(listings)
+	}
 
-		if m[x].Platform.Symbol != "" {
-			err := s.currencyCodes.UpdateToken(m[x].Name,
-				m[x].Symbol,
-				m[x].Platform.Symbol,
-				m[x].ID)
+	if len(errs) == 0 {
+		return errors.New("no currency analysis providers set")
+	}
+	return fmt.Errorf("all currency analysis providers failed: %s",
+		strings.Join(errs, ", "))
+}
+
+// updateCurrencyCodes loads provider listings into the currency codes
+func (s *Storage) updateCurrencyCodes(listings []Listing) error {
+	for x := range listings {
+		if listings[x].Platform != "" {
+			err := s.currencyCodes.UpdateToken(listings[x].Name,
+				listings[x].Symbol,
+				listings[x].Platform,
+				listings[x].ID)
 			if err != nil {
 				return err
 			}
 			continue
 		}
 
-		err := s.currencyCodes.UpdateCryptocurrency(m[x].Name,
-			m[x].Symbol,
-			m[x].ID)
+		err := s.currencyCodes.UpdateCryptocurrency(listings[x].Name,
+			listings[x].Symbol,
+			listings[x].ID)
 		if err != nil {
 			return err
 		}
@@ -446,6 +623,15 @@ func (s *Storage) UpdateCurrencies() error {
 	return nil
 }
 
+// GetForexProviderHealth returns the health of the enabled foreign exchange
+// providers
+func (s *Storage) GetForexProviderHealth() ([]base.ProviderHealth, error) {
+	if s.fiatExchangeMarkets == nil {
+		return nil, errors.New("foreign exchange providers not set")
+	}
+	return s.fiatExchangeMarkets.GetProviderHealth(), nil
+}
+
 // SeedForeignExchangeRatesByCurrencies seeds the foreign exchange rates by
 // currencies supplied
 func (s *Storage) SeedForeignExchangeRatesByCurrencies(c Currencies) error {
@@ -468,7 +654,7 @@ func (s *Storage) SeedForeignExchangeRate(from, to Code) (map[string]float64, er
 // GetDefaultForeignExchangeRates returns foreign exchange rates based off
 // default fiat currencies.
 func (s *Storage) GetDefaultForeignExchangeRates() (Conversions, error) {
-	if !s.updaterRunning {
+	if !s.IsRunning() {
 		err := s.SeedDefaultForeignExchangeRates()
 		if err != nil {
 			return nil, err
@@ -492,7 +678,7 @@ func (s *Storage) SeedDefaultForeignExchangeRates() error {
 
 // GetExchangeRates returns storage seeded exchange rates
 func (s *Storage) GetExchangeRates() (Conversions, error) {
-	if !s.updaterRunning {
+	if !s.IsRunning() {
 		err := s.SeedForeignExchangeRates()
 		if err != nil {
 			return nil, err
@@ -538,11 +724,33 @@ func (s *Storage) SetupCryptoProvider(settings coinmarketcap.Settings) error {
 		return errors.New("currencyprovider error api key or plan not set in config.json")
 	}
 
-	s.currencyAnalysis = new(coinmarketcap.Coinmarketcap)
-	s.currencyAnalysis.SetDefaults()
-	s.currencyAnalysis.Setup(settings)
+	c := new(coinmarketcap.Coinmarketcap)
+	c.SetDefaults()
+	c.Setup(settings)
 
-	return nil
+	return s.reconfigure(func() error {
+		s.currencyAnalysis = append([]AnalysisProvider{coinmarketcapProvider{c}},
+			s.currencyAnalysis...)
+		return nil
+	})
+}
+
+// SetupSecondaryCryptoProvider sets configuration parameters and starts a new
+// instance of the coingecko currency analyser which is used when the primary
+// provider fails
+func (s *Storage) SetupSecondaryCryptoProvider(settings coingecko.Settings) error {
+	if !settings.Enabled {
+		return errors.New("secondary currency provider not enabled")
+	}
+
+	g := new(coingecko.CoinGecko)
+	g.SetDefaults()
+	g.Setup(settings)
+
+	return s.reconfigure(func() error {
+		s.currencyAnalysis = append(s.currencyAnalysis, coingeckoProvider{g})
+		return nil
+	})
 }
 
 // GetTotalMarketCryptocurrencies returns the total seeded market
@@ -711,6 +919,12 @@ func (s *Storage) UpdateEnabledFiatCurrencies(c Currencies) {
 // ConvertCurrency for example converts $1 USD to the equivalent Japanese Yen
 // or vice versa.
 func (s *Storage) ConvertCurrency(amount float64, from, to Code) (float64, error) {
+	// Stablecoins are valued at their fiat peg
+	from, to = from.valuationCode(), to.valuationCode()
+	if from.Match(to) {
+		return amount, nil
+	}
+
 	s.mtx.Lock()
 	defer s.mtx.Unlock()
 
@@ -744,6 +958,12 @@ func (s *Storage) GetStorageRate(from, to Code) (float64, error) {
 	return s.fxRates.GetRate(from, to)
 }
 
+// GetStorageRateAt returns the conversion rate recorded at or before the time
+func (s *Storage) GetStorageRateAt(from, to Code, t time.Time) (float64, error) {
+	from, to = from.valuationCode(), to.valuationCode()
+	return s.fxRates.GetRateAt(from, to, t)
+}
+
 // NewConversion returns a new conversion object that has a pointer to a related
 // rate with its inversion.
 func (s *Storage) NewConversion(from, to Code) (Conversion, error) {
@@ -1,6 +1,9 @@
 package currency
 
-import "testing"
+import (
+	"testing"
+	"time"
+)
 
 func TestRunUpdater(t *testing.T) {
 	var newStorage Storage
@@ -26,3 +29,73 @@ func TestRunUpdater(t *testing.T) {
 		t.Fatal("Test Failed storage RunUpdater() error", err)
 	}
 }
+
+func TestUpdaterLifecycle(t *testing.T) {
+	var newStorage Storage
+	err := newStorage.Start()
+	if err != errNoForexProviders {
+		t.Fatalf("Test Failed storage Start() expected %v, received %v",
+			errNoForexProviders, err)
+	}
+
+	newStorage.SetDefaults()
+	err = newStorage.Stop()
+	if err != errUpdaterNotRunning {
+		t.Fatalf("Test Failed storage Stop() expected %v, received %v",
+			errUpdaterNotRunning, err)
+	}
+
+	err = newStorage.Start()
+	if err != nil {
+		t.Fatal("Test Failed storage Start() error", err)
+	}
+	if !newStorage.IsRunning() {
+		t.Fatal("Test Failed storage IsRunning() expected running updater")
+	}
+
+	err = newStorage.Start()
+	if err != errUpdaterRunning {
+		t.Fatalf("Test Failed storage Start() expected %v, received %v",
+			errUpdaterRunning, err)
+	}
+
+	err = newStorage.SetUpdateDelays(0, time.Hour)
+	if err != errInvalidDelay {
+		t.Fatalf("Test Failed storage SetUpdateDelays() expected %v, received %v",
+			errInvalidDelay, err)
+	}
+
+	err = newStorage.SetUpdateDelays(time.Hour, time.Hour*2)
+	if err != nil {
+		t.Fatal("Test Failed storage SetUpdateDelays() error", err)
+	}
+	if !newStorage.IsRunning() {
+		t.Fatal("Test Failed storage SetUpdateDelays() expected updater to be restarted")
+	}
+	if newStorage.foreignExchangeUpdateDelay != time.Hour ||
+		newStorage.currencyFileUpdateDelay != time.Hour*2 {
+		t.Fatal("Test Failed storage SetUpdateDelays() delays not updated")
+	}
+
+	err = newStorage.Restart()
+	if err != nil {
+		t.Fatal("Test Failed storage Restart() error", err)
+	}
+
+	err = newStorage.Stop()
+	if err != nil {
+		t.Fatal("Test Failed storage Stop() error", err)
+	}
+	if newStorage.IsRunning() {
+		t.Fatal("Test Failed storage IsRunning() expected stopped updater")
+	}
+
+	err = newStorage.Restart()
+	if err != nil {
+		t.Fatal("Test Failed storage Restart() error", err)
+	}
+	err = newStorage.Stop()
+	if err != nil {
+		t.Fatal("Test Failed storage Stop() error", err)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/dashboard"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/breakeven"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/drawdown"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// ActivateDashboard draws the terminal dashboard to stdout. Open orders and
+// equity are only shown for exchanges with authenticated API support
+func ActivateDashboard() {
+	if !bot.dashboard {
+		return
+	}
+
+	var authenticated []exchange.IBotExchange
+	for _, exch := range GetLoadedExchanges() {
+		if exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			authenticated = append(authenticated, exch)
+		}
+	}
+
+	sources := dashboard.Sources{
+		Tickers:   dashboardTickers,
+		Positions: dashboardPositions,
+	}
+	if len(authenticated) > 0 {
+		sources.Orders = func() ([]exchange.OrderDetail, error) {
+			return dashboardOrders(authenticated)
+		}
+		sources.Equity = drawdown.AccountEquity(authenticated, bot.config.Currency.FiatDisplayCurrency)
+	}
+
+	d, err := dashboard.New(os.Stdout, sources, dashboard.Config{
+		Interval: bot.dashboardInterval,
+		Quote:    bot.config.Currency.FiatDisplayCurrency,
+	})
+	if err != nil {
+		log.Errorf("Dashboard failed to start: %s", err)
+		return
+	}
+	err = d.Start()
+	if err != nil {
+		log.Errorf("Dashboard failed to start: %s", err)
+		return
+	}
+	bot.dashboardView = d
+	log.Debugf("Terminal dashboard enabled.")
+}
+
+// dashboardTickers returns the stored tickers of every enabled pair
+func dashboardTickers() []dashboard.Ticker {
+	var tickers []dashboard.Ticker
+	for _, exch := range GetLoadedExchanges() {
+		if !exch.IsEnabled() {
+			continue
+		}
+		assetTypes, err := exchange.GetExchangeAssetTypes(exch.GetName())
+		if err != nil {
+			continue
+		}
+		pairs := exch.GetEnabledCurrencies()
+		for i := range assetTypes {
+			for j := range pairs {
+				t, err := ticker.GetTicker(exch.GetName(), pairs[j], assetTypes[i])
+				if err != nil {
+					continue
+				}
+				tickers = append(tickers, dashboard.Ticker{
+					Exchange:  exch.GetName(),
+					AssetType: assetTypes[i],
+					Price:     t,
+				})
+			}
+		}
+	}
+	return tickers
+}
+
+// dashboardOrders returns the open orders on the exchanges, orders from
+// exchanges which fail are omitted and reported in the error
+func dashboardOrders(exchanges []exchange.IBotExchange) ([]exchange.OrderDetail, error) {
+	var orders []exchange.OrderDetail
+	var errs []string
+	for i := range exchanges {
+		resp, err := exchanges[i].GetActiveOrders(&exchange.GetOrdersRequest{})
+		if err != nil {
+			errs = append(errs, exchanges[i].GetName()+": "+err.Error())
+			continue
+		}
+		for j := range resp {
+			if resp[j].Exchange == "" {
+				resp[j].Exchange = exchanges[i].GetName()
+			}
+		}
+		orders = append(orders, resp...)
+	}
+	if len(errs) > 0 {
+		return orders, errors.New(strings.Join(errs, ", "))
+	}
+	return orders, nil
+}
+
+// dashboardPositions returns the positions held by the break-even tracker
+func dashboardPositions() []breakeven.Position {
+	if bot.breakEvenTracker == nil {
+		return nil
+	}
+	return bot.breakEvenTracker.GetPositions()
+}
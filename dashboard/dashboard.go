@@ -0,0 +1,335 @@
+// Package dashboard renders a terminal dashboard of live tickers, open orders,
+// positions and session PnL, redrawn in place for monitoring headless servers
+// without the web interface
+package dashboard
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/breakeven"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+)
+
+// Default dashboard settings
+const (
+	DefaultInterval        = time.Second * 5
+	DefaultAccountInterval = time.Second * 30
+	DefaultMaxRows         = 20
+)
+
+// clearScreen moves the cursor home and clears the terminal so each frame is
+// drawn in place
+const clearScreen = "\033[H\033[2J"
+
+var (
+	errNilWriter      = errors.New("dashboard writer is nil")
+	errNoSources      = errors.New("dashboard requires at least one data source")
+	errAlreadyRunning = errors.New("dashboard already running")
+	errNotRunning     = errors.New("dashboard not running")
+)
+
+// Ticker holds the latest stored ticker of an exchange pair
+type Ticker struct {
+	Exchange  string
+	AssetType string
+	ticker.Price
+}
+
+// Sources supplies the data drawn by the dashboard, nil sources are omitted
+type Sources struct {
+	Tickers   func() []Ticker
+	Orders    func() ([]exchange.OrderDetail, error)
+	Positions func() []breakeven.Position
+	// Equity returns the account value in the quote currency, session PnL is
+	// its change since the dashboard started
+	Equity func() (float64, error)
+}
+
+// Config defines how often the dashboard is redrawn
+type Config struct {
+	Interval time.Duration
+	// AccountInterval is how often open orders and equity are fetched, which
+	// require authenticated requests to every exchange
+	AccountInterval time.Duration
+	// MaxRows limits the rows drawn per section
+	MaxRows int
+	Quote   currency.Code
+}
+
+// Snapshot holds the data drawn in a single frame
+type Snapshot struct {
+	Time        time.Time
+	Started     time.Time
+	Quote       currency.Code
+	Tickers     []Ticker
+	Orders      []exchange.OrderDetail
+	Positions   []breakeven.Position
+	Equity      float64
+	StartEquity float64
+	Errors      []string
+}
+
+// SessionPnL returns the change in equity since the session started
+func (s *Snapshot) SessionPnL() float64 {
+	if s.StartEquity == 0 {
+		return 0
+	}
+	return s.Equity - s.StartEquity
+}
+
+// Dashboard periodically redraws a snapshot of the bot's state
+type Dashboard struct {
+	w       io.Writer
+	sources Sources
+	cfg     Config
+
+	started     time.Time
+	startEquity float64
+	orders      []exchange.OrderDetail
+	ordersErr   error
+	equity      float64
+	equityErr   error
+	accountAt   time.Time
+
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a dashboard drawing the sources to the writer
+func New(w io.Writer, sources Sources, cfg Config) (*Dashboard, error) {
+	if w == nil {
+		return nil, errNilWriter
+	}
+	if sources.Tickers == nil && sources.Orders == nil &&
+		sources.Positions == nil && sources.Equity == nil {
+		return nil, errNoSources
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.AccountInterval <= 0 {
+		cfg.AccountInterval = DefaultAccountInterval
+	}
+	if cfg.MaxRows <= 0 {
+		cfg.MaxRows = DefaultMaxRows
+	}
+	return &Dashboard{
+		w:       w,
+		sources: sources,
+		cfg:     cfg,
+		started: time.Now(),
+	}, nil
+}
+
+// Snapshot collects the current state from the sources. Open orders and
+// equity are fetched no more often than the account interval
+func (d *Dashboard) Snapshot() Snapshot {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	s := Snapshot{
+		Time:    time.Now(),
+		Started: d.started,
+		Quote:   d.cfg.Quote,
+	}
+	if d.sources.Tickers != nil {
+		s.Tickers = d.sources.Tickers()
+	}
+	if d.sources.Positions != nil {
+		s.Positions = d.sources.Positions()
+	}
+
+	if s.Time.Sub(d.accountAt) >= d.cfg.AccountInterval {
+		d.accountAt = s.Time
+		if d.sources.Orders != nil {
+			d.orders, d.ordersErr = d.sources.Orders()
+		}
+		if d.sources.Equity != nil {
+			d.equity, d.equityErr = d.sources.Equity()
+			if d.equityErr == nil && d.startEquity == 0 {
+				d.startEquity = d.equity
+			}
+		}
+	}
+	s.Orders = d.orders
+	if d.ordersErr != nil {
+		s.Errors = append(s.Errors, "orders: "+d.ordersErr.Error())
+	}
+	if d.equityErr != nil {
+		s.Errors = append(s.Errors, "equity: "+d.equityErr.Error())
+	} else {
+		s.Equity = d.equity
+	}
+	s.StartEquity = d.startEquity
+	return s
+}
+
+// Refresh collects a snapshot and redraws the dashboard
+func (d *Dashboard) Refresh() error {
+	s := d.Snapshot()
+	var b strings.Builder
+	b.WriteString(clearScreen)
+	Render(&b, &s, d.cfg.MaxRows)
+	_, err := io.WriteString(d.w, b.String())
+	return err
+}
+
+// Start redraws the dashboard at the configured interval until stopped
+func (d *Dashboard) Start() error {
+	d.mtx.Lock()
+	if d.shutdown != nil {
+		d.mtx.Unlock()
+		return errAlreadyRunning
+	}
+	d.shutdown = make(chan struct{})
+	shutdown := d.shutdown
+	d.mtx.Unlock()
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		t := time.NewTicker(d.cfg.Interval)
+		defer t.Stop()
+		for {
+			// Write errors are ignored, the terminal may be detached
+			_ = d.Refresh()
+			select {
+			case <-shutdown:
+				return
+			case <-t.C:
+			}
+		}
+	}()
+	return nil
+}
+
+// Stop stops redrawing the dashboard
+func (d *Dashboard) Stop() error {
+	d.mtx.Lock()
+	if d.shutdown == nil {
+		d.mtx.Unlock()
+		return errNotRunning
+	}
+	close(d.shutdown)
+	d.shutdown = nil
+	d.mtx.Unlock()
+	d.wg.Wait()
+	return nil
+}
+
+// Render writes a frame of the snapshot, limiting each section to maxRows
+func Render(w io.Writer, s *Snapshot, maxRows int) {
+	fmt.Fprintf(w, "GoCryptoTrader  %s  session %s\n",
+		s.Time.Format("2006-01-02 15:04:05"),
+		s.Time.Sub(s.Started).Truncate(time.Second))
+
+	var realised, unrealised float64
+	for i := range s.Positions {
+		realised += s.Positions[i].RealisedPnL
+		unrealised += s.Positions[i].NetPnL
+	}
+	quote := s.Quote.String()
+	if s.StartEquity != 0 {
+		fmt.Fprintf(w, "Session PnL: %s %s (%s)  equity %s  start %s\n",
+			signed(common.FormatAmount(s.SessionPnL(), quote)), s.Quote,
+			signed(common.FormatPercent(s.SessionPnL()/s.StartEquity*100)),
+			common.FormatAmount(s.Equity, quote),
+			common.FormatAmount(s.StartEquity, quote))
+	}
+	if len(s.Positions) > 0 {
+		fmt.Fprintf(w, "Positions PnL: realised %s  unrealised %s\n",
+			signed(common.FormatAmount(realised, quote)),
+			signed(common.FormatAmount(unrealised, quote)))
+	}
+
+	tickers := append([]Ticker(nil), s.Tickers...)
+	sort.Slice(tickers, func(i, j int) bool {
+		if tickers[i].Exchange != tickers[j].Exchange {
+			return tickers[i].Exchange < tickers[j].Exchange
+		}
+		return tickers[i].Pair.String() < tickers[j].Pair.String()
+	})
+	section(w, "TICKERS", "EXCHANGE\tPAIR\tASSET\tLAST\tBID\tASK\tVOLUME\tUPDATED",
+		len(tickers), maxRows, func(tw io.Writer, i int) {
+			t := &tickers[i]
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				t.Exchange, t.Pair, t.AssetType, common.FormatPrice(t.Last),
+				common.FormatPrice(t.Bid), common.FormatPrice(t.Ask),
+				common.FormatAmount(t.Volume, t.Pair.Base.String()),
+				age(s.Time, t.LastUpdated))
+		})
+
+	orders := append([]exchange.OrderDetail(nil), s.Orders...)
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].OrderDate.After(orders[j].OrderDate)
+	})
+	section(w, "OPEN ORDERS", "EXCHANGE\tID\tPAIR\tSIDE\tTYPE\tPRICE\tAMOUNT\tFILLED\tAGE",
+		len(orders), maxRows, func(tw io.Writer, i int) {
+			o := &orders[i]
+			base := o.CurrencyPair.Base.String()
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+				o.Exchange, o.ID, o.CurrencyPair, o.OrderSide, o.OrderType,
+				common.FormatPrice(o.Price), common.FormatAmount(o.Amount, base),
+				common.FormatAmount(o.ExecutedAmount, base), age(s.Time, o.OrderDate))
+		})
+
+	section(w, "POSITIONS", "EXCHANGE\tPAIR\tASSET\tSIZE\tENTRY\tMARK\tBREAK-EVEN\tNET PNL",
+		len(s.Positions), maxRows, func(tw io.Writer, i int) {
+			p := &s.Positions[i]
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%v\t%s\t%s\t%s\t%s\n",
+				p.Exchange, p.Pair, p.AssetType, p.Size, common.FormatPrice(p.EntryPrice),
+				common.FormatPrice(p.MarkPrice), common.FormatPrice(p.BreakEven),
+				signed(common.FormatAmount(p.NetPnL, p.CostCurrency().String())))
+		})
+
+	for i := range s.Errors {
+		fmt.Fprintf(w, "\nError: %s", s.Errors[i])
+	}
+	if len(s.Errors) > 0 {
+		fmt.Fprintln(w)
+	}
+}
+
+// section writes a titled table of up to maxRows rows
+func section(w io.Writer, title, header string, rows, maxRows int, row func(io.Writer, int)) {
+	fmt.Fprintf(w, "\n%s (%d)\n", title, rows)
+	if rows == 0 {
+		return
+	}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, header)
+	for i := 0; i < rows && i < maxRows; i++ {
+		row(tw, i)
+	}
+	tw.Flush()
+	if rows > maxRows {
+		fmt.Fprintf(w, "... %d more\n", rows-maxRows)
+	}
+}
+
+// signed prefixes formatted non-negative values with a plus sign
+func signed(s string) string {
+	if strings.HasPrefix(s, "-") {
+		return s
+	}
+	return "+" + s
+}
+
+// age returns the time elapsed since t to the second, or - if unknown
+func age(now, t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+	return now.Sub(t).Truncate(time.Second).String()
+}
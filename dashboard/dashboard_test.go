@@ -0,0 +1,157 @@
+package dashboard
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/breakeven"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+)
+
+type safeBuffer struct {
+	b   bytes.Buffer
+	mtx sync.Mutex
+}
+
+func (s *safeBuffer) Write(p []byte) (int, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.b.Write(p)
+}
+
+func (s *safeBuffer) String() string {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.b.String()
+}
+
+func testSources(equity *float64, orderCalls *int) Sources {
+	p := currency.NewPair(currency.BTC, currency.USD)
+	return Sources{
+		Tickers: func() []Ticker {
+			return []Ticker{
+				{Exchange: "Kraken", AssetType: ticker.Spot, Price: ticker.Price{Pair: p, Last: 100, Bid: 99, Ask: 101}},
+				{Exchange: "Bitmex", AssetType: "perpetual", Price: ticker.Price{Pair: p, Last: 102}},
+			}
+		},
+		Orders: func() ([]exchange.OrderDetail, error) {
+			*orderCalls++
+			return []exchange.OrderDetail{
+				{Exchange: "Kraken", ID: "OABC", CurrencyPair: p, OrderSide: exchange.BuyOrderSide, OrderType: exchange.LimitOrderType, Price: 95, Amount: 1},
+			}, nil
+		},
+		Positions: func() []breakeven.Position {
+			return []breakeven.Position{{Exchange: "Bitmex", Pair: p, Size: 1, NetPnL: 5, RealisedPnL: 2}}
+		},
+		Equity: func() (float64, error) {
+			if *equity < 0 {
+				return 0, errors.New("no balances could be valued")
+			}
+			return *equity, nil
+		},
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil, Sources{}, Config{}); err != errNilWriter {
+		t.Error("Test Failed - New() expected nil writer error", err)
+	}
+	if _, err := New(&bytes.Buffer{}, Sources{}, Config{}); err != errNoSources {
+		t.Error("Test Failed - New() expected no sources error", err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	equity := 1000.0
+	var orderCalls int
+	d, err := New(&bytes.Buffer{}, testSources(&equity, &orderCalls), Config{Quote: currency.USD})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Every snapshot refreshes account data
+	d.cfg.AccountInterval = time.Nanosecond
+
+	s := d.Snapshot()
+	if len(s.Tickers) != 2 || len(s.Orders) != 1 || len(s.Positions) != 1 || s.SessionPnL() != 0 {
+		t.Fatalf("Test Failed - Snapshot() unexpected snapshot %+v", s)
+	}
+
+	// Session PnL is measured from the first equity valuation
+	equity = 1050
+	s = d.Snapshot()
+	if s.SessionPnL() != 50 || s.StartEquity != 1000 {
+		t.Errorf("Test Failed - Snapshot() expected session PnL 50, got %v", s.SessionPnL())
+	}
+
+	equity = -1
+	s = d.Snapshot()
+	if len(s.Errors) != 1 || s.StartEquity != 1000 {
+		t.Errorf("Test Failed - Snapshot() expected equity error %v", s.Errors)
+	}
+
+	// Account data is cached between account intervals
+	d.cfg.AccountInterval = time.Hour
+	equity = 2000
+	calls := orderCalls
+	s = d.Snapshot()
+	if orderCalls != calls || len(s.Orders) != 1 || len(s.Errors) != 1 {
+		t.Errorf("Test Failed - Snapshot() expected cached account data, fetched %d times", orderCalls-calls)
+	}
+}
+
+func TestRender(t *testing.T) {
+	equity := 1000.0
+	var orderCalls int
+	d, _ := New(&bytes.Buffer{}, testSources(&equity, &orderCalls), Config{Quote: currency.USD, AccountInterval: time.Nanosecond})
+	d.Snapshot()
+	equity = 990
+	s := d.Snapshot()
+
+	var b bytes.Buffer
+	Render(&b, &s, 1)
+	out := b.String()
+	for _, want := range []string{
+		"Session PnL: -10.00 USD (-1.00%)",
+		"realised +2.00  unrealised +5.00",
+		"TICKERS (2)",
+		"... 1 more",
+		"OPEN ORDERS (1)",
+		"OABC",
+		"POSITIONS (1)",
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("Test Failed - Render() missing %q in\n%s", want, out)
+		}
+	}
+	// Tickers are sorted by exchange
+	if strings.Index(out, "Bitmex") > strings.Index(out, "... 1 more") {
+		t.Error("Test Failed - Render() expected tickers sorted by exchange")
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	var b safeBuffer
+	d, _ := New(&b, Sources{Tickers: func() []Ticker { return nil }}, Config{Interval: time.Millisecond})
+	if err := d.Stop(); err != errNotRunning {
+		t.Error("Test Failed - Stop() expected not running error", err)
+	}
+	if err := d.Start(); err != nil {
+		t.Fatal(err)
+	}
+	if err := d.Start(); err != errAlreadyRunning {
+		t.Error("Test Failed - Start() expected already running error", err)
+	}
+	time.Sleep(time.Millisecond * 20)
+	if err := d.Stop(); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(b.String(), clearScreen) {
+		t.Error("Test Failed - Start() expected frames drawn in place")
+	}
+}
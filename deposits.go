@@ -0,0 +1,68 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/deposits"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/poloniex"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errDepositTrackerDisabled = errors.New("deposit confirmation tracker not enabled")
+
+// ActivateDepositTracker starts tracking inbound deposits and their
+// confirmations on Poloniex and Kraken. Tracking only reads account history
+// so read only and guarded exchanges are included
+func ActivateDepositTracker() {
+	if !bot.depositTracking {
+		return
+	}
+
+	var sources []deposits.Source
+	for _, exch := range GetLoadedExchanges() {
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		switch e := exchange.Underlying(exch).(type) {
+		case *poloniex.Poloniex:
+			sources = append(sources, &deposits.PoloniexDeposits{Exchange: e})
+		case *kraken.Kraken:
+			sources = append(sources, &deposits.KrakenDeposits{Exchange: e})
+		}
+	}
+
+	t, err := deposits.New(sources, handleDepositEvent)
+	if err != nil {
+		log.Errorf("Deposit confirmation tracker failed to start: %s", err)
+		return
+	}
+	t.Start(deposits.DefaultCheckInterval)
+	bot.depositTracker = t
+	log.Debugf("Deposit confirmation tracker enabled for %d exchanges.", len(sources))
+}
+
+func handleDepositEvent(e deposits.Event) {
+	log.Debugln(e.String())
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Deposit, "deposit_event", "", e.Deposit.Exchange)
+	}
+}
+
+// GetDeposits returns tracked deposits on the named exchange, or every
+// exchange when empty. Credited deposits are only included when all is set
+func GetDeposits(exchName string, all bool) ([]deposits.Deposit, error) {
+	if bot.depositTracker == nil {
+		return nil, errDepositTrackerDisabled
+	}
+	return bot.depositTracker.GetDeposits(exchName, all), nil
+}
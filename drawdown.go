@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/drawdown"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errDrawdownBreakerDisabled = errors.New("drawdown circuit breaker not enabled")
+
+// ActivateDrawdownBreaker starts the drawdown circuit breaker across all
+// loaded exchanges and locks their order entry once it trips
+func ActivateDrawdownBreaker() {
+	if bot.maxDrawdown <= 0 {
+		return
+	}
+
+	exchanges := make([]exchange.IBotExchange, 0, len(bot.exchanges))
+	for x := range bot.exchanges {
+		if bot.exchanges[x] != nil {
+			exchanges = append(exchanges, bot.exchanges[x])
+		}
+	}
+
+	quote := bot.config.Currency.FiatDisplayCurrency
+	b, err := drawdown.New(drawdown.Config{
+		MaxDrawdown: bot.maxDrawdown,
+		Window:      bot.drawdownWindow,
+		Flatten:     bot.drawdownFlatten,
+		Quote:       quote,
+	},
+		drawdown.AccountEquity(exchanges, quote),
+		exchanges,
+		handleDrawdownTrip)
+	if err != nil {
+		log.Errorf("Drawdown circuit breaker failed to start: %s", err)
+		return
+	}
+
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		bot.exchanges[x] = b.Guard(bot.exchanges[x])
+	}
+
+	b.Start(drawdown.DefaultCheckInterval)
+	bot.drawdownBreaker = b
+	log.Debugf("Drawdown circuit breaker enabled, maximum drawdown %.2f%%.",
+		bot.maxDrawdown)
+}
+
+func handleDrawdownTrip(s drawdown.Status) {
+	details := fmt.Sprintf("Drawdown circuit breaker tripped, trading locked until reset: %s",
+		s.Reason)
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "DRAWDOWN_BREAKER",
+			TradeDetails: details,
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(s, "drawdown_breaker", "", "")
+	}
+}
+
+// ResetDrawdownBreaker unlocks trading after the drawdown circuit breaker has
+// tripped
+func ResetDrawdownBreaker() error {
+	if bot.drawdownBreaker == nil {
+		return errDrawdownBreakerDisabled
+	}
+	return bot.drawdownBreaker.Reset()
+}
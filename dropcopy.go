@@ -0,0 +1,116 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/dropcopy"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+const dropCopyJournalFile = "dropcopy.log"
+
+var dropCopyJournalMtx sync.Mutex
+
+// ActivateDropCopy wraps all loaded exchanges as read only and starts
+// mirroring their account activity into the journal, communication mediums
+// and websocket clients
+func ActivateDropCopy() {
+	if !bot.dropCopy {
+		return
+	}
+
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		if _, ok := bot.exchanges[x].(*exchange.ReadOnly); ok {
+			continue
+		}
+		bot.exchanges[x] = exchange.NewReadOnly(bot.exchanges[x])
+	}
+
+	m, err := dropcopy.New(bot.exchanges, dropcopy.DefaultPollingInterval, handleDropCopyEvent)
+	if err != nil {
+		log.Errorf("Drop copy mode failed to start: %s", err)
+		return
+	}
+
+	err = m.Start()
+	if err != nil {
+		log.Errorf("Drop copy mode failed to start: %s", err)
+		return
+	}
+	bot.dropCopyMirror = m
+	log.Debugf("Drop copy mode enabled, trading disabled for all exchanges.")
+}
+
+func handleDropCopyEvent(e dropcopy.Event) {
+	details := formatDropCopyEvent(&e)
+	log.Infof("Dropcopy: %s", details)
+
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: details,
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e, "dropcopy_event", "", e.Exchange)
+	}
+
+	recordDropCopyFill(&e)
+	recordDropCopyTrade(&e)
+	recordDropCopyExecution(&e)
+
+	err := writeDropCopyJournal(&e)
+	if err != nil {
+		log.Errorf("Dropcopy: failed to write journal. Error: %s", err)
+	}
+}
+
+func formatDropCopyEvent(e *dropcopy.Event) string {
+	switch {
+	case e.Order != nil && e.Type == dropcopy.OrderFill:
+		return fmt.Sprintf("%s %s %s order %s %s filled %s @ %s",
+			e.Exchange, e.Type, e.Order.CurrencyPair, e.Order.ID, e.Order.OrderSide,
+			common.FormatAmount(e.Filled, e.Order.CurrencyPair.Base.String()),
+			common.FormatPrice(e.Order.Price))
+	case e.Order != nil:
+		return fmt.Sprintf("%s %s %s order %s %s %s @ %s status %s",
+			e.Exchange, e.Type, e.Order.CurrencyPair, e.Order.ID, e.Order.OrderSide,
+			common.FormatAmount(e.Order.Amount, e.Order.CurrencyPair.Base.String()),
+			common.FormatPrice(e.Order.Price), e.Order.Status)
+	case e.Balance != nil:
+		c := e.Balance.CurrencyName.String()
+		return fmt.Sprintf("%s %s %s %s -> %s (hold %s)",
+			e.Exchange, e.Type, c, common.FormatAmount(e.Previous, c),
+			common.FormatAmount(e.Balance.TotalValue, c), common.FormatAmount(e.Balance.Hold, c))
+	}
+	return fmt.Sprintf("%s %s", e.Exchange, e.Type)
+}
+
+func writeDropCopyJournal(e *dropcopy.Event) error {
+	data, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+
+	dropCopyJournalMtx.Lock()
+	defer dropCopyJournalMtx.Unlock()
+	f, err := os.OpenFile(filepath.Join(bot.dataDir, dropCopyJournalFile),
+		os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(data, '\n'))
+	return err
+}
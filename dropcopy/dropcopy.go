@@ -0,0 +1,369 @@
+// Package dropcopy mirrors exchange account activity such as orders, fills and
+// balance changes without trading. It is intended to run with read only API
+// keys so that external or manual trading can be monitored alongside the bot.
+package dropcopy
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Event types emitted by the mirror
+const (
+	OrderNew     = "ORDER_NEW"
+	OrderUpdate  = "ORDER_UPDATE"
+	OrderFill    = "ORDER_FILL"
+	OrderClosed  = "ORDER_CLOSED"
+	BalanceDelta = "BALANCE_CHANGE"
+
+	// DefaultPollingInterval is the default delay between account polls
+	DefaultPollingInterval = time.Second * 30
+)
+
+var (
+	errNoExchanges    = errors.New("dropcopy no exchanges supplied")
+	errNoHandler      = errors.New("dropcopy no event handler supplied")
+	errAlreadyRunning = errors.New("dropcopy mirror already running")
+	errNotRunning     = errors.New("dropcopy mirror not running")
+	errOrderNotFound  = errors.New("dropcopy closed order not found")
+)
+
+// Event defines a mirrored account activity event
+type Event struct {
+	Type      string                        `json:"type"`
+	Exchange  string                        `json:"exchange"`
+	Timestamp time.Time                     `json:"timestamp"`
+	Order     *exchange.OrderDetail         `json:"order,omitempty"`
+	Filled    float64                       `json:"filled,omitempty"`
+	Balance   *exchange.AccountCurrencyInfo `json:"balance,omitempty"`
+	Previous  float64                       `json:"previous,omitempty"`
+}
+
+// Handler is called for every mirrored event
+type Handler func(Event)
+
+// closedOrderFunc returns the final state of an order no longer open
+type closedOrderFunc func(o *exchange.OrderDetail) (exchange.OrderDetail, error)
+
+// Mirror polls exchange account activity and emits events for changes
+type Mirror struct {
+	exchanges []exchange.IBotExchange
+	handler   Handler
+	interval  time.Duration
+
+	orders   map[string]map[string]exchange.OrderDetail
+	balances map[string]map[currency.Code]exchange.AccountCurrencyInfo
+	seeded   map[string]bool
+
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+	running  bool
+}
+
+// New returns a new drop copy mirror for the supplied exchanges
+func New(exchanges []exchange.IBotExchange, interval time.Duration, h Handler) (*Mirror, error) {
+	if len(exchanges) == 0 {
+		return nil, errNoExchanges
+	}
+
+	if h == nil {
+		return nil, errNoHandler
+	}
+
+	if interval <= 0 {
+		interval = DefaultPollingInterval
+	}
+
+	return &Mirror{
+		exchanges: exchanges,
+		handler:   h,
+		interval:  interval,
+		orders:    make(map[string]map[string]exchange.OrderDetail),
+		balances:  make(map[string]map[currency.Code]exchange.AccountCurrencyInfo),
+		seeded:    make(map[string]bool),
+	}, nil
+}
+
+// Start starts the mirror polling routine
+func (m *Mirror) Start() error {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	if m.running {
+		return errAlreadyRunning
+	}
+	m.running = true
+	m.shutdown = make(chan struct{})
+	m.wg.Add(1)
+	go m.run()
+	return nil
+}
+
+// Stop stops the mirror polling routine
+func (m *Mirror) Stop() error {
+	m.mtx.Lock()
+	if !m.running {
+		m.mtx.Unlock()
+		return errNotRunning
+	}
+	m.running = false
+	close(m.shutdown)
+	m.mtx.Unlock()
+	m.wg.Wait()
+	return nil
+}
+
+func (m *Mirror) run() {
+	defer m.wg.Done()
+	tick := time.NewTicker(m.interval)
+	defer tick.Stop()
+
+	m.poll()
+	for {
+		select {
+		case <-m.shutdown:
+			return
+		case <-tick.C:
+			m.poll()
+		}
+	}
+}
+
+// poll fetches account activity for all exchanges and emits events for
+// changes since the previous poll. The first poll for an exchange seeds the
+// mirror state and emits the current open orders and balances. Only the
+// polling routine calls poll, so the mirror state is not locked
+func (m *Mirror) poll() {
+	for i := range m.exchanges {
+		if m.exchanges[i] == nil || !m.exchanges[i].IsEnabled() {
+			continue
+		}
+
+		name := m.exchanges[i].GetName()
+		if !m.exchanges[i].GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			log.Debugf("Dropcopy: skipping %s, authenticated API support disabled", name)
+			continue
+		}
+
+		orders, err := m.exchanges[i].GetActiveOrders(&exchange.GetOrdersRequest{
+			OrderType: exchange.AnyOrderType,
+			OrderSide: exchange.AnyOrderSide,
+		})
+		if err != nil {
+			log.Errorf("Dropcopy: %s failed to get active orders. Error: %s", name, err)
+		} else {
+			m.emit(m.diffOrders(name, orders, closedOrder(m.exchanges[i])))
+		}
+
+		acc, err := m.exchanges[i].GetAccountInfo()
+		if err != nil {
+			log.Errorf("Dropcopy: %s failed to get account info. Error: %s", name, err)
+			continue
+		}
+		m.emit(m.diffBalances(name, &acc))
+		m.seeded[name] = true
+	}
+}
+
+func (m *Mirror) emit(events []Event) {
+	for i := range events {
+		m.handler(events[i])
+	}
+}
+
+// closedOrder returns a closedOrderFunc fetching the order from the exchange,
+// falling back to its order history for exchanges which cannot fetch a
+// single order
+func closedOrder(e exchange.IBotExchange) closedOrderFunc {
+	return func(o *exchange.OrderDetail) (exchange.OrderDetail, error) {
+		detail, err := e.GetOrderInfo(o.ID)
+		if err == nil && (detail.ID == "" || detail.ID == o.ID) && detail.Amount > 0 {
+			return detail, nil
+		}
+
+		req := exchange.GetOrdersRequest{
+			OrderType:  exchange.AnyOrderType,
+			OrderSide:  exchange.AnyOrderSide,
+			StartTicks: o.OrderDate,
+		}
+		if !o.CurrencyPair.IsEmpty() {
+			req.Currencies = []currency.Pair{o.CurrencyPair}
+		}
+		history, err := e.GetOrderHistory(&req)
+		if err != nil {
+			return exchange.OrderDetail{}, err
+		}
+		for i := range history {
+			if history[i].ID == o.ID {
+				return history[i], nil
+			}
+		}
+		return exchange.OrderDetail{}, errOrderNotFound
+	}
+}
+
+// diffOrders compares the current open orders against the previous snapshot.
+// Orders no longer open are looked up with closed to report fills made since
+// the previous poll and whether they filled or were cancelled
+func (m *Mirror) diffOrders(exch string, current []exchange.OrderDetail, closed closedOrderFunc) []Event {
+	now := time.Now()
+	prev := m.orders[exch]
+	next := make(map[string]exchange.OrderDetail)
+	var events []Event
+
+	for i := range current {
+		o := current[i]
+		next[o.ID] = o
+		old, ok := prev[o.ID]
+		if !ok {
+			events = append(events, Event{
+				Type:      OrderNew,
+				Exchange:  exch,
+				Timestamp: now,
+				Order:     &o,
+			})
+			continue
+		}
+
+		if o.ExecutedAmount > old.ExecutedAmount {
+			events = append(events, Event{
+				Type:      OrderFill,
+				Exchange:  exch,
+				Timestamp: now,
+				Order:     &o,
+				Filled:    o.ExecutedAmount - old.ExecutedAmount,
+				Previous:  old.ExecutedAmount,
+			})
+			continue
+		}
+
+		if o.Status != old.Status ||
+			o.Price != old.Price ||
+			o.Amount != old.Amount {
+			events = append(events, Event{
+				Type:      OrderUpdate,
+				Exchange:  exch,
+				Timestamp: now,
+				Order:     &o,
+			})
+		}
+	}
+
+	for id := range prev {
+		if _, ok := next[id]; ok {
+			continue
+		}
+		o := prev[id]
+		if closed != nil {
+			final, err := closed(&o)
+			if err != nil {
+				log.Debugf("Dropcopy: %s unable to fetch closed order %s. Error: %s", exch, id, err)
+			} else {
+				o = mergeClosedOrder(&o, &final)
+				if o.ExecutedAmount > prev[id].ExecutedAmount {
+					filled := o
+					events = append(events, Event{
+						Type:      OrderFill,
+						Exchange:  exch,
+						Timestamp: now,
+						Order:     &filled,
+						Filled:    o.ExecutedAmount - prev[id].ExecutedAmount,
+						Previous:  prev[id].ExecutedAmount,
+					})
+				}
+			}
+		}
+		events = append(events, Event{
+			Type:      OrderClosed,
+			Exchange:  exch,
+			Timestamp: now,
+			Order:     &o,
+		})
+	}
+
+	m.orders[exch] = next
+	return events
+}
+
+// mergeClosedOrder updates the last open state of an order with its final
+// state, classifying it as filled or cancelled when the exchange does not
+// report a status
+func mergeClosedOrder(last, final *exchange.OrderDetail) exchange.OrderDetail {
+	o := *last
+	if final.ExecutedAmount > o.ExecutedAmount {
+		o.ExecutedAmount = final.ExecutedAmount
+		o.RemainingAmount = o.Amount - o.ExecutedAmount
+	}
+	if final.Price > 0 {
+		o.Price = final.Price
+	}
+	if final.Fee > 0 {
+		o.Fee = final.Fee
+	}
+	switch {
+	case final.Status != "":
+		o.Status = final.Status
+	case o.Amount > 0 && o.ExecutedAmount >= o.Amount:
+		o.Status = string(exchange.FilledOrderStatus)
+	default:
+		o.Status = string(exchange.CancelledOrderStatus)
+	}
+	return o
+}
+
+// diffBalances compares the current balances against the previous snapshot
+func (m *Mirror) diffBalances(exch string, acc *exchange.AccountInfo) []Event {
+	now := time.Now()
+	prev := m.balances[exch]
+	next := make(map[currency.Code]exchange.AccountCurrencyInfo)
+	for i := range acc.Accounts {
+		for j := range acc.Accounts[i].Currencies {
+			c := acc.Accounts[i].Currencies[j]
+			agg := next[c.CurrencyName]
+			agg.CurrencyName = c.CurrencyName
+			agg.TotalValue += c.TotalValue
+			agg.Hold += c.Hold
+			next[c.CurrencyName] = agg
+		}
+	}
+
+	var events []Event
+	for code, bal := range next {
+		old, ok := prev[code]
+		if ok && old.TotalValue == bal.TotalValue && old.Hold == bal.Hold {
+			continue
+		}
+		if !ok && m.seeded[exch] && bal.TotalValue == 0 {
+			continue
+		}
+		b := bal
+		events = append(events, Event{
+			Type:      BalanceDelta,
+			Exchange:  exch,
+			Timestamp: now,
+			Balance:   &b,
+			Previous:  old.TotalValue,
+		})
+	}
+
+	for code, old := range prev {
+		if _, ok := next[code]; ok || old.TotalValue == 0 {
+			continue
+		}
+		events = append(events, Event{
+			Type:      BalanceDelta,
+			Exchange:  exch,
+			Timestamp: now,
+			Balance:   &exchange.AccountCurrencyInfo{CurrencyName: code},
+			Previous:  old.TotalValue,
+		})
+	}
+
+	m.balances[exch] = next
+	return events
+}
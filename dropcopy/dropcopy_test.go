@@ -0,0 +1,208 @@
+package dropcopy
+
+import (
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+func testMirror() *Mirror {
+	return &Mirror{
+		handler:  func(Event) {},
+		orders:   make(map[string]map[string]exchange.OrderDetail),
+		balances: make(map[string]map[currency.Code]exchange.AccountCurrencyInfo),
+		seeded:   make(map[string]bool),
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, 0, func(Event) {})
+	if err != errNoExchanges {
+		t.Errorf("Test Failed - New() error expected %v but received %v",
+			errNoExchanges, err)
+	}
+
+	_, err = New([]exchange.IBotExchange{nil}, 0, nil)
+	if err != errNoHandler {
+		t.Errorf("Test Failed - New() error expected %v but received %v",
+			errNoHandler, err)
+	}
+
+	m, err := New([]exchange.IBotExchange{nil}, 0, func(Event) {})
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+
+	if m.interval != DefaultPollingInterval {
+		t.Error("Test Failed - New() default interval not set")
+	}
+}
+
+func TestStartStop(t *testing.T) {
+	m, err := New([]exchange.IBotExchange{nil}, 0, func(Event) {})
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+
+	err = m.Stop()
+	if err != errNotRunning {
+		t.Error("Test Failed - Stop() expected not running error")
+	}
+
+	err = m.Start()
+	if err != nil {
+		t.Fatal("Test Failed - Start() error", err)
+	}
+
+	err = m.Start()
+	if err != errAlreadyRunning {
+		t.Error("Test Failed - Start() expected already running error")
+	}
+
+	err = m.Stop()
+	if err != nil {
+		t.Error("Test Failed - Stop() error", err)
+	}
+}
+
+func TestDiffOrders(t *testing.T) {
+	m := testMirror()
+	orders := []exchange.OrderDetail{
+		{ID: "1", Amount: 1, Price: 100, Status: "OPEN"},
+		{ID: "2", Amount: 2, Price: 200, Status: "OPEN"},
+	}
+
+	events := m.diffOrders("test", orders, nil)
+	if len(events) != 2 || events[0].Type != OrderNew || events[1].Type != OrderNew {
+		t.Fatalf("Test Failed - diffOrders() expected 2 new order events, received %v", events)
+	}
+
+	events = m.diffOrders("test", orders, nil)
+	if len(events) != 0 {
+		t.Fatalf("Test Failed - diffOrders() expected no events, received %v", events)
+	}
+
+	orders = []exchange.OrderDetail{
+		{ID: "1", Amount: 1, Price: 100, Status: "OPEN", ExecutedAmount: 0.4},
+	}
+	events = m.diffOrders("test", orders, nil)
+	if len(events) != 2 {
+		t.Fatalf("Test Failed - diffOrders() expected 2 events, received %v", events)
+	}
+
+	if events[0].Type != OrderFill || events[0].Filled != 0.4 {
+		t.Errorf("Test Failed - diffOrders() expected fill of 0.4, received %v", events[0])
+	}
+
+	if events[1].Type != OrderClosed || events[1].Order.ID != "2" {
+		t.Errorf("Test Failed - diffOrders() expected order 2 closed, received %v", events[1])
+	}
+
+	orders[0].Price = 101
+	events = m.diffOrders("test", orders, nil)
+	if len(events) != 1 || events[0].Type != OrderUpdate {
+		t.Errorf("Test Failed - diffOrders() expected order update, received %v", events)
+	}
+}
+
+func TestDiffClosedOrders(t *testing.T) {
+	m := testMirror()
+	orders := []exchange.OrderDetail{
+		{ID: "1", Amount: 1, Price: 100, Status: "OPEN", ExecutedAmount: 0.25},
+		{ID: "2", Amount: 2, Price: 200, Status: "OPEN"},
+		{ID: "3", Amount: 3, Price: 300, Status: "OPEN"},
+	}
+	m.diffOrders("test", orders, nil)
+
+	// Order 1 filled completely, order 2 was cancelled and order 3 cannot be
+	// found between polls
+	history := map[string]exchange.OrderDetail{
+		"1": {ID: "1", Amount: 1, ExecutedAmount: 1},
+		"2": {ID: "2", Amount: 2, Status: string(exchange.CancelledOrderStatus)},
+	}
+	events := m.diffOrders("test", nil, func(o *exchange.OrderDetail) (exchange.OrderDetail, error) {
+		d, ok := history[o.ID]
+		if !ok {
+			return d, errOrderNotFound
+		}
+		return d, nil
+	})
+
+	closed := make(map[string]Event)
+	var fills []Event
+	for i := range events {
+		switch events[i].Type {
+		case OrderFill:
+			fills = append(fills, events[i])
+		case OrderClosed:
+			closed[events[i].Order.ID] = events[i]
+		}
+	}
+	if len(fills) != 1 || fills[0].Order.ID != "1" || fills[0].Filled != 0.75 {
+		t.Errorf("Test Failed - diffOrders() expected the remaining 0.75 of order 1 filled, received %v", fills)
+	}
+	if len(closed) != 3 {
+		t.Fatalf("Test Failed - diffOrders() expected 3 closed orders, received %v", events)
+	}
+	if s := closed["1"].Order.Status; s != string(exchange.FilledOrderStatus) {
+		t.Errorf("Test Failed - diffOrders() expected order 1 filled, received %s", s)
+	}
+	if s := closed["2"].Order.Status; s != string(exchange.CancelledOrderStatus) {
+		t.Errorf("Test Failed - diffOrders() expected order 2 cancelled, received %s", s)
+	}
+	if s := closed["3"].Order.Status; s != "OPEN" {
+		t.Errorf("Test Failed - diffOrders() expected order 3 last state, received %s", s)
+	}
+}
+
+func TestDiffBalances(t *testing.T) {
+	m := testMirror()
+	acc := exchange.AccountInfo{
+		Exchange: "test",
+		Accounts: []exchange.Account{
+			{Currencies: []exchange.AccountCurrencyInfo{
+				{CurrencyName: currency.BTC, TotalValue: 1},
+				{CurrencyName: currency.USD, TotalValue: 1000},
+			}},
+			{Currencies: []exchange.AccountCurrencyInfo{
+				{CurrencyName: currency.BTC, TotalValue: 1, Hold: 0.5},
+			}},
+		},
+	}
+
+	events := m.diffBalances("test", &acc)
+	if len(events) != 2 {
+		t.Fatalf("Test Failed - diffBalances() expected 2 events, received %v", events)
+	}
+	m.seeded["test"] = true
+
+	if b := m.balances["test"][currency.BTC]; b.TotalValue != 2 || b.Hold != 0.5 {
+		t.Errorf("Test Failed - diffBalances() expected aggregated BTC balance, received %v", b)
+	}
+
+	events = m.diffBalances("test", &acc)
+	if len(events) != 0 {
+		t.Fatalf("Test Failed - diffBalances() expected no events, received %v", events)
+	}
+
+	acc.Accounts = acc.Accounts[:1]
+	acc.Accounts[0].Currencies = acc.Accounts[0].Currencies[:1]
+	events = m.diffBalances("test", &acc)
+	if len(events) != 2 {
+		t.Fatalf("Test Failed - diffBalances() expected 2 events, received %v", events)
+	}
+
+	for i := range events {
+		switch events[i].Balance.CurrencyName {
+		case currency.BTC:
+			if events[i].Previous != 2 || events[i].Balance.TotalValue != 1 {
+				t.Errorf("Test Failed - diffBalances() unexpected BTC event %v", events[i])
+			}
+		case currency.USD:
+			if events[i].Previous != 1000 || events[i].Balance.TotalValue != 0 {
+				t.Errorf("Test Failed - diffBalances() unexpected USD event %v", events[i])
+			}
+		}
+	}
+}
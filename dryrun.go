@@ -0,0 +1,34 @@
+package main
+
+import (
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// ActivateDryRun puts every loaded exchange in dry run mode with the -dryrun
+// flag, otherwise those with dry run enabled in their config. Orders,
+// withdrawals and transfers are logged and simulated instead of being sent.
+// The dry run wrapper is applied first so every other wrapper still runs
+func ActivateDryRun() {
+	var names []string
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		name := bot.exchanges[x].GetName()
+		if !bot.dryRun {
+			cfg, err := bot.config.GetExchangeConfig(name)
+			if err != nil || !cfg.DryRun {
+				continue
+			}
+		}
+		if b, ok := exchange.Underlying(bot.exchanges[x]).(interface{ SetDryRun(bool) }); ok {
+			b.SetDryRun(true)
+		}
+		bot.exchanges[x] = exchange.NewDryRun(bot.exchanges[x])
+		names = append(names, name)
+	}
+	if len(names) > 0 {
+		log.Warnf("Dry run mode enabled, orders, withdrawals and transfers are simulated for %v.", names)
+	}
+}
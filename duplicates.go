@@ -0,0 +1,44 @@
+package main
+
+import (
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/duplicates"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// ActivateDuplicateOrderGuard wraps every loaded exchange so orders repeating
+// the pair, side, price and amount of an order submitted within the duplicate
+// window are blocked. It wraps the exchanges inside the trading rules so
+// orders are compared after rounding
+func ActivateDuplicateOrderGuard() {
+	if bot.duplicateWindow <= 0 {
+		return
+	}
+
+	d, err := duplicates.New(bot.duplicateWindow, handleDuplicateOrderEvent)
+	if err != nil {
+		log.Errorf("Duplicate order guard failed to start: %s", err)
+		return
+	}
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		bot.exchanges[x] = d.Guard(bot.exchanges[x])
+	}
+	log.Debugf("Duplicate order guard enabled with a %s window.", bot.duplicateWindow)
+}
+
+func handleDuplicateOrderEvent(e duplicates.Event) {
+	log.Warnf("Duplicate order guard: %s", e.String())
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e, "duplicate_order", "", e.Order.Exchange)
+	}
+}
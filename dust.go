@@ -0,0 +1,92 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/dust"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// ActivateDustCleanup periodically cleans up balances below each exchange's
+// minimum order notional, valued in the -dust quote currency. Dust is
+// converted on exchanges with a dust conversion endpoint and otherwise
+// tracked until its value is large enough to sell
+func ActivateDustCleanup() {
+	if bot.dustQuote == "" {
+		return
+	}
+
+	if bot.dryRun || bot.dropCopy {
+		log.Warnf("Dust cleanup disabled in dry run and drop copy modes.")
+		return
+	}
+
+	m, err := dust.New(currency.NewCode(bot.dustQuote), 0)
+	if err != nil {
+		log.Errorf("Dust cleanup failed to start: %s", err)
+		return
+	}
+
+	go func() {
+		for {
+			cleanupDust(m)
+			select {
+			case <-bot.shutdown:
+				return
+			case <-time.After(bot.dustInterval):
+			}
+		}
+	}()
+	log.Debugf("Dust cleanup enabled, valuing dust in %s every %s.", bot.dustQuote, bot.dustInterval)
+}
+
+// cleanupDust cleans up the dust of each exchange with authenticated API
+// support
+func cleanupDust(m *dust.Manager) {
+	exchanges := GetLoadedExchanges()
+	for i := range exchanges {
+		if !exchanges[i].GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		r, err := m.Cleanup(exchanges[i])
+		switch err {
+		case nil:
+		case dust.ErrNothingToClean:
+			continue
+		default:
+			log.Errorf("Dust cleanup failed on %s: %s", exchanges[i].GetName(), err)
+		}
+		if len(r.Converted) > 0 || len(r.Sold) > 0 {
+			handleDustCleanup(&r)
+		}
+	}
+}
+
+func handleDustCleanup(r *dust.Result) {
+	var cleaned []string
+	for i := range r.Converted {
+		cleaned = append(cleaned, "converted "+r.Converted[i].String())
+	}
+	for i := range r.Sold {
+		c := r.Sold[i].Currency.String()
+		cleaned = append(cleaned, "sold "+common.FormatAmount(r.Sold[i].Amount, c)+" "+c)
+	}
+	details := fmt.Sprintf("%s dust cleanup %s", r.Exchange, strings.Join(cleaned, ", "))
+	log.Infof("%s", details)
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "DUST_CLEANUP",
+			TradeDetails: details,
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(r, "dust_cleanup", "", r.Exchange)
+	}
+}
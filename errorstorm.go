@@ -0,0 +1,83 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/errorstorm"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errErrorStormBreakerDisabled = errors.New("exchange error storm breaker not enabled")
+
+// ActivateErrorStormBreaker starts the error storm breaker across all loaded
+// exchanges, pausing new orders to an exchange while its authenticated
+// requests are failing or its websocket keeps disconnecting
+func ActivateErrorStormBreaker() {
+	if !bot.errorBreaker {
+		return
+	}
+
+	exchanges := make([]exchange.IBotExchange, 0, len(bot.exchanges))
+	for x := range bot.exchanges {
+		if bot.exchanges[x] != nil {
+			exchanges = append(exchanges, bot.exchanges[x])
+		}
+	}
+
+	b, err := errorstorm.New(errorstorm.Config{
+		Window:         bot.errorBreakerWindow,
+		MaxErrorRate:   bot.errorBreakerRate,
+		MaxDisconnects: bot.errorBreakerDisconnects,
+		Cooldown:       bot.errorBreakerCooldown,
+	},
+		exchanges,
+		errorstorm.AccountHealth,
+		handleErrorStormEvent)
+	if err != nil {
+		log.Errorf("Exchange error storm breaker failed to start: %s", err)
+		return
+	}
+
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		bot.exchanges[x] = b.Guard(bot.exchanges[x])
+	}
+
+	b.Start(errorstorm.DefaultCheckInterval)
+	bot.errorStormBreaker = b
+	log.Debugf("Exchange error storm breaker enabled.")
+}
+
+func handleErrorStormEvent(e errorstorm.Event) {
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Status, "error_storm_breaker", "", e.Status.Exchange)
+	}
+}
+
+// GetErrorStormStatus returns the error storm breaker status of each exchange
+func GetErrorStormStatus() ([]errorstorm.Status, error) {
+	if bot.errorStormBreaker == nil {
+		return nil, errErrorStormBreakerDisabled
+	}
+	return bot.errorStormBreaker.GetStatus(), nil
+}
+
+// ResetErrorStormBreaker resumes order flow to an exchange without waiting for
+// its cool down and health check
+func ResetErrorStormBreaker(exchName string) error {
+	if bot.errorStormBreaker == nil {
+		return errErrorStormBreakerDisabled
+	}
+	return bot.errorStormBreaker.Reset(exchName)
+}
@@ -81,6 +81,11 @@ func ReloadExchange(name string) error {
 		return err
 	}
 
+	err = exchCfg.ResolveSecrets()
+	if err != nil {
+		return err
+	}
+
 	e := GetExchangeByName(name)
 	e.Setup(&exchCfg)
 	log.Debugf("%s exchange reloaded successfully.\n", name)
@@ -200,6 +205,11 @@ func LoadExchange(name string, useWG bool, wg *sync.WaitGroup) error {
 		return err
 	}
 
+	err = exchCfg.ResolveSecrets()
+	if err != nil {
+		return err
+	}
+
 	exchCfg.Enabled = true
 	exch.Setup(&exchCfg)
 
@@ -0,0 +1,577 @@
+// Package allocation partitions real exchange balances into virtual
+// sub-accounts per strategy. Each strategy trades through a guarded exchange
+// which only accepts orders its allocation can fund, and fills are booked
+// against the strategy so PnL can be reported per strategy even though every
+// strategy shares the same exchange accounts
+package allocation
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// DefaultReconcileInterval is how often open orders are polled for fills
+const DefaultReconcileInterval = time.Second * 30
+
+var (
+	// ErrInsufficientAllocation is returned when an order needs more funds
+	// than the strategy has available in its allocation
+	ErrInsufficientAllocation = errors.New("order exceeds strategy allocation")
+	// ErrNotSupported is returned for calls which cannot be attributed to a
+	// single strategy's allocation
+	ErrNotSupported = errors.New("not supported for allocated strategies")
+
+	errStrategyNotSet    = errors.New("allocation strategy not set")
+	errNilPriceFunc      = errors.New("allocation price function is nil")
+	errZeroAmount        = errors.New("allocation amount cannot be zero")
+	errExceedsBalance    = errors.New("allocation exceeds unallocated exchange balance")
+	errBelowReserved     = errors.New("deallocation exceeds strategy's available balance")
+	errStrategyNotFound  = errors.New("allocation strategy not found")
+	errOrderNotFound     = errors.New("allocation order not found")
+	errInvalidFill       = errors.New("allocation fill amount and price must be positive")
+	errNoPriceForMarket  = errors.New("allocation no price available to reserve market order")
+	errInvalidOrderSide  = errors.New("allocation order side must be buy or sell")
+	errBalancesNotSynced = errors.New("allocation exchange balances not synced")
+)
+
+// PriceFunc returns the value of one unit of a currency on an exchange in the
+// ledger's reporting currency
+type PriceFunc func(exchangeName string, c currency.Code) (float64, error)
+
+// Balance holds a strategy's virtual balance of a currency on an exchange
+type Balance struct {
+	Exchange string
+	Currency currency.Code
+	Total    float64
+	// Reserved is held against the strategy's open orders
+	Reserved float64
+}
+
+// Available returns the balance free to fund new orders
+func (b *Balance) Available() float64 {
+	return b.Total - b.Reserved
+}
+
+// Report summarises a strategy's virtual sub-account
+type Report struct {
+	Strategy string
+	Balances []Balance
+	// Capital is the net value allocated to the strategy at the time of each
+	// allocation
+	Capital float64
+	// Equity is the current value of the strategy's balances
+	Equity float64
+	PnL    float64
+	Fees   float64
+	Orders int
+	// Unpriced lists balances which could not be valued and are excluded
+	// from equity
+	Unpriced []string `json:",omitempty"`
+	Updated  time.Time
+}
+
+type account struct {
+	balances map[string]*Balance
+	capital  float64
+	fees     float64
+}
+
+type order struct {
+	strategy string
+	exchange string
+	pair     currency.Pair
+	buy      bool
+	amount   float64
+	filled   float64
+	fee      float64
+	// reserved is the amount of the funding currency still held
+	reserved float64
+}
+
+// fundingCurrency returns the currency which funds the order
+func (o *order) fundingCurrency() currency.Code {
+	if o.buy {
+		return o.pair.Quote
+	}
+	return o.pair.Base
+}
+
+func balanceKey(exchangeName string, c currency.Code) string {
+	return strings.ToLower(exchangeName) + "_" + c.Upper().String()
+}
+
+func orderKey(exchangeName, orderID string) string {
+	return strings.ToLower(exchangeName) + "_" + orderID
+}
+
+// Ledger tracks every strategy's virtual sub-account
+type Ledger struct {
+	price    PriceFunc
+	real     map[string]float64
+	accounts map[string]*account
+	orders   map[string]*order
+	mtx      sync.Mutex
+}
+
+// NewLedger returns an allocation ledger valuing balances with the supplied
+// price function
+func NewLedger(price PriceFunc) (*Ledger, error) {
+	if price == nil {
+		return nil, errNilPriceFunc
+	}
+	return &Ledger{
+		price:    price,
+		real:     make(map[string]float64),
+		accounts: make(map[string]*account),
+		orders:   make(map[string]*order),
+	}, nil
+}
+
+// SyncBalances updates the real exchange balances allocations are made from
+func (l *Ledger) SyncBalances(e exchange.IBotExchange) error {
+	acc, err := e.GetAccountInfo()
+	if err != nil {
+		return err
+	}
+	totals := make(map[string]float64)
+	for i := range acc.Accounts {
+		for j := range acc.Accounts[i].Currencies {
+			c := acc.Accounts[i].Currencies[j]
+			totals[balanceKey(e.GetName(), c.CurrencyName)] += c.TotalValue
+		}
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	prefix := strings.ToLower(e.GetName()) + "_"
+	for k := range l.real {
+		if strings.HasPrefix(k, prefix) {
+			delete(l.real, k)
+		}
+	}
+	for k, v := range totals {
+		l.real[k] = v
+	}
+	l.real[prefix] = 1 // marks the exchange as synced
+	return nil
+}
+
+// allocated returns the total allocated across strategies, the caller must
+// hold the lock
+func (l *Ledger) allocated(k string) float64 {
+	var total float64
+	for _, a := range l.accounts {
+		if b, ok := a.balances[k]; ok {
+			total += b.Total
+		}
+	}
+	return total
+}
+
+// GetAllocated returns the amount of a currency on an exchange allocated
+// across all strategies
+func (l *Ledger) GetAllocated(exchangeName string, c currency.Code) float64 {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	return l.allocated(balanceKey(exchangeName, c))
+}
+
+// Allocate moves an amount of an exchange balance into a strategy's
+// sub-account. A negative amount returns funds from the strategy
+func (l *Ledger) Allocate(strategy, exchangeName string, c currency.Code, amount float64) error {
+	if strategy == "" {
+		return errStrategyNotSet
+	}
+	if amount == 0 {
+		return errZeroAmount
+	}
+	price, err := l.price(exchangeName, c)
+	if err != nil {
+		return err
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if _, ok := l.real[strings.ToLower(exchangeName)+"_"]; !ok {
+		return fmt.Errorf("%s %v", exchangeName, errBalancesNotSynced)
+	}
+	k := balanceKey(exchangeName, c)
+	a, ok := l.accounts[strategy]
+	if !ok {
+		a = &account{balances: make(map[string]*Balance)}
+		l.accounts[strategy] = a
+	}
+	b, ok := a.balances[k]
+	if !ok {
+		b = &Balance{Exchange: exchangeName, Currency: c.Upper()}
+	}
+
+	if amount > 0 {
+		if unallocated := l.real[k] - l.allocated(k); amount > unallocated {
+			return fmt.Errorf("%v %s %s: requested %v, unallocated %v",
+				errExceedsBalance, exchangeName, c, amount, unallocated)
+		}
+	} else if -amount > b.Available() {
+		return fmt.Errorf("%v %s %s: requested %v, available %v",
+			errBelowReserved, exchangeName, c, -amount, b.Available())
+	}
+
+	b.Total += amount
+	a.balances[k] = b
+	a.capital += amount * price
+	return nil
+}
+
+// Guard wraps an exchange so the strategy can only trade within its
+// allocation. Cancellations through the wrapper release reserved funds
+func (l *Ledger) Guard(e exchange.IBotExchange, strategy string) exchange.IBotExchange {
+	return &Allocated{IBotExchange: e, ledger: l, strategy: strategy}
+}
+
+// reserve holds the funds needed by an order, the caller must hold the lock
+func (l *Ledger) reserve(strategy, exchangeName string, p currency.Pair, buy bool, amount, price float64) (*order, error) {
+	o := &order{strategy: strategy, exchange: exchangeName, pair: p, buy: buy, amount: amount}
+	need := amount
+	if buy {
+		need = amount * price
+	}
+	a, ok := l.accounts[strategy]
+	if !ok {
+		return nil, fmt.Errorf("%s %v", strategy, errStrategyNotFound)
+	}
+	b, ok := a.balances[balanceKey(exchangeName, o.fundingCurrency())]
+	if !ok || need > b.Available() {
+		var available float64
+		if ok {
+			available = b.Available()
+		}
+		return nil, fmt.Errorf("%v: %s needs %v %s, available %v",
+			ErrInsufficientAllocation, strategy, need, o.fundingCurrency(), available)
+	}
+	b.Reserved += need
+	o.reserved = need
+	return o, nil
+}
+
+// release returns an order's remaining reservation, the caller must hold the
+// lock
+func (l *Ledger) release(o *order) {
+	if a, ok := l.accounts[o.strategy]; ok {
+		if b, ok := a.balances[balanceKey(o.exchange, o.fundingCurrency())]; ok {
+			b.Reserved -= o.reserved
+			if b.Reserved < 0 {
+				b.Reserved = 0
+			}
+		}
+	}
+	o.reserved = 0
+}
+
+// balance returns the strategy's balance, creating it if needed. The caller
+// must hold the lock
+func (a *account) balance(exchangeName string, c currency.Code) *Balance {
+	k := balanceKey(exchangeName, c)
+	b, ok := a.balances[k]
+	if !ok {
+		b = &Balance{Exchange: exchangeName, Currency: c.Upper()}
+		a.balances[k] = b
+	}
+	return b
+}
+
+// Fill books an execution of an order placed through a guarded exchange
+// against the owning strategy. The fee is charged in the fee currency
+func (l *Ledger) Fill(exchangeName, orderID string, amount, price, fee float64, feeCurrency currency.Code) error {
+	if amount <= 0 || price <= 0 {
+		return errInvalidFill
+	}
+
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	k := orderKey(exchangeName, orderID)
+	o, ok := l.orders[k]
+	if !ok {
+		return fmt.Errorf("%s %s %v", exchangeName, orderID, errOrderNotFound)
+	}
+	a := l.accounts[o.strategy]
+	base := a.balance(exchangeName, o.pair.Base)
+	quote := a.balance(exchangeName, o.pair.Quote)
+
+	spent := amount
+	if o.buy {
+		spent = amount * price
+		quote.Total -= spent
+		base.Total += amount
+	} else {
+		base.Total -= amount
+		quote.Total += amount * price
+	}
+	funding := quote
+	if !o.buy {
+		funding = base
+	}
+	held := spent
+	if held > o.reserved {
+		held = o.reserved
+	}
+	funding.Reserved -= held
+	o.reserved -= held
+
+	if fee > 0 {
+		a.balance(exchangeName, feeCurrency).Total -= fee
+		if p, err := l.price(exchangeName, feeCurrency); err == nil {
+			a.fees += fee * p
+		}
+	}
+
+	o.filled += amount
+	if o.filled >= o.amount {
+		// Release anything left over from price improvement
+		l.release(o)
+		delete(l.orders, k)
+	}
+	return nil
+}
+
+// Reconcile polls the exchange for fills of orders placed through guarded
+// exchanges and books them to their strategies. Fees reported by the exchange
+// are charged in the quote currency. Orders the exchange reports as closed
+// without filling release their remaining reservation
+func (l *Ledger) Reconcile(e exchange.IBotExchange) error {
+	name := e.GetName()
+	l.mtx.Lock()
+	var ids []string
+	prefix := orderKey(name, "")
+	for k := range l.orders {
+		if strings.HasPrefix(k, prefix) {
+			ids = append(ids, strings.TrimPrefix(k, prefix))
+		}
+	}
+	l.mtx.Unlock()
+
+	var errs []string
+	for i := range ids {
+		d, err := e.GetOrderInfo(ids[i])
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s %s: %v", name, ids[i], err))
+			continue
+		}
+
+		l.mtx.Lock()
+		o, ok := l.orders[orderKey(name, ids[i])]
+		if !ok {
+			l.mtx.Unlock()
+			continue
+		}
+		filled, fee, pair := d.ExecutedAmount-o.filled, d.Fee-o.fee, o.pair
+		if fee < 0 {
+			fee = 0
+		}
+		o.fee += fee
+		l.mtx.Unlock()
+
+		if filled > 0 && d.Price > 0 {
+			err = l.Fill(name, ids[i], filled, d.Price, fee, pair.Quote)
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s %s: %v", name, ids[i], err))
+			}
+		}
+
+		switch exchange.OrderStatus(strings.ToUpper(d.Status)) {
+		case exchange.CancelledOrderStatus,
+			exchange.RejectedOrderStatus,
+			exchange.ExpiredOrderStatus:
+			l.mtx.Lock()
+			if o, ok = l.orders[orderKey(name, ids[i])]; ok {
+				l.release(o)
+				delete(l.orders, orderKey(name, ids[i]))
+			}
+			l.mtx.Unlock()
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// GetReport returns the strategy's sub-account report
+func (l *Ledger) GetReport(strategy string) (Report, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	a, ok := l.accounts[strategy]
+	if !ok {
+		return Report{}, fmt.Errorf("%s %v", strategy, errStrategyNotFound)
+	}
+
+	r := Report{
+		Strategy: strategy,
+		Capital:  a.capital,
+		Fees:     a.fees,
+		Updated:  time.Now(),
+	}
+	for _, b := range a.balances {
+		r.Balances = append(r.Balances, *b)
+		if b.Total == 0 {
+			continue
+		}
+		p, err := l.price(b.Exchange, b.Currency)
+		if err != nil {
+			r.Unpriced = append(r.Unpriced, b.Exchange+" "+b.Currency.String())
+			continue
+		}
+		r.Equity += b.Total * p
+	}
+	sort.Slice(r.Balances, func(i, j int) bool {
+		return balanceKey(r.Balances[i].Exchange, r.Balances[i].Currency) <
+			balanceKey(r.Balances[j].Exchange, r.Balances[j].Currency)
+	})
+	for _, o := range l.orders {
+		if o.strategy == strategy {
+			r.Orders++
+		}
+	}
+	r.PnL = r.Equity - r.Capital
+	return r, nil
+}
+
+// GetReports returns every strategy's sub-account report ordered by strategy
+func (l *Ledger) GetReports() []Report {
+	l.mtx.Lock()
+	strategies := make([]string, 0, len(l.accounts))
+	for s := range l.accounts {
+		strategies = append(strategies, s)
+	}
+	l.mtx.Unlock()
+
+	sort.Strings(strategies)
+	reports := make([]Report, 0, len(strategies))
+	for i := range strategies {
+		r, err := l.GetReport(strategies[i])
+		if err != nil {
+			continue
+		}
+		reports = append(reports, r)
+	}
+	return reports
+}
+
+// Allocated is an exchange which only accepts orders a strategy's allocation
+// can fund
+type Allocated struct {
+	exchange.IBotExchange
+	ledger   *Ledger
+	strategy string
+}
+
+// Unwrap returns the underlying exchange
+func (a *Allocated) Unwrap() exchange.IBotExchange {
+	return a.IBotExchange
+}
+
+// SubmitOrder reserves the funds needed from the strategy's allocation before
+// submitting the order. Market buys are reserved at the current price
+func (a *Allocated) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	var buy bool
+	switch side {
+	case exchange.BuyOrderSide, exchange.BidOrderSide:
+		buy = true
+	case exchange.SellOrderSide, exchange.AskOrderSide:
+	default:
+		return exchange.SubmitOrderResponse{}, errInvalidOrderSide
+	}
+
+	reservePrice := price
+	if buy && (orderType == exchange.MarketOrderType || price <= 0) {
+		var err error
+		reservePrice, err = a.marketPrice(p)
+		if err != nil {
+			return exchange.SubmitOrderResponse{}, err
+		}
+	}
+
+	name := a.GetName()
+	a.ledger.mtx.Lock()
+	o, err := a.ledger.reserve(a.strategy, name, p, buy, amount, reservePrice)
+	a.ledger.mtx.Unlock()
+	if err != nil {
+		return exchange.SubmitOrderResponse{}, err
+	}
+
+	resp, err := a.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+	a.ledger.mtx.Lock()
+	defer a.ledger.mtx.Unlock()
+	if err != nil || !resp.IsOrderPlaced {
+		a.ledger.release(o)
+		return resp, err
+	}
+	a.ledger.orders[orderKey(name, resp.OrderID)] = o
+	return resp, nil
+}
+
+// marketPrice returns the pair price from the ledger's valuations
+func (a *Allocated) marketPrice(p currency.Pair) (float64, error) {
+	base, err := a.ledger.price(a.GetName(), p.Base)
+	if err != nil {
+		return 0, err
+	}
+	quote, err := a.ledger.price(a.GetName(), p.Quote)
+	if err != nil {
+		return 0, err
+	}
+	if base <= 0 || quote <= 0 {
+		return 0, errNoPriceForMarket
+	}
+	return base / quote, nil
+}
+
+// CancelOrder cancels the order and releases its reserved funds
+func (a *Allocated) CancelOrder(c *exchange.OrderCancellation) error {
+	err := a.IBotExchange.CancelOrder(c)
+	if err != nil {
+		return err
+	}
+	a.ledger.mtx.Lock()
+	defer a.ledger.mtx.Unlock()
+	k := orderKey(a.GetName(), c.OrderID)
+	if o, ok := a.ledger.orders[k]; ok && o.strategy == a.strategy {
+		a.ledger.release(o)
+		delete(a.ledger.orders, k)
+	}
+	return nil
+}
+
+// ModifyOrder is not supported as amendments could exceed the allocation,
+// orders should be cancelled and replaced
+func (a *Allocated) ModifyOrder(_ *exchange.ModifyOrder) (string, error) {
+	return "", ErrNotSupported
+}
+
+// CancelAllOrders is not supported as it would cancel other strategies'
+// orders on the shared account
+func (a *Allocated) CancelAllOrders(_ *exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error) {
+	return exchange.CancelAllOrdersResponse{}, ErrNotSupported
+}
+
+// WithdrawCryptocurrencyFunds is not supported for allocated strategies
+func (a *Allocated) WithdrawCryptocurrencyFunds(_ *exchange.WithdrawRequest) (string, error) {
+	return "", ErrNotSupported
+}
+
+// WithdrawFiatFunds is not supported for allocated strategies
+func (a *Allocated) WithdrawFiatFunds(_ *exchange.WithdrawRequest) (string, error) {
+	return "", ErrNotSupported
+}
+
+// WithdrawFiatFundsToInternationalBank is not supported for allocated
+// strategies
+func (a *Allocated) WithdrawFiatFundsToInternationalBank(_ *exchange.WithdrawRequest) (string, error) {
+	return "", ErrNotSupported
+}
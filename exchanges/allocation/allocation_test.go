@@ -0,0 +1,303 @@
+package allocation
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+var testPair = currency.NewPairWithDelimiter("BTC", "USDT", "-")
+
+type testExchange struct {
+	exchange.IBotExchange
+	balances  map[currency.Code]float64
+	submitErr error
+	orders    int
+	cancelled []string
+	info      map[string]exchange.OrderDetail
+}
+
+func (t *testExchange) GetName() string {
+	return "Test"
+}
+
+func (t *testExchange) GetAccountInfo() (exchange.AccountInfo, error) {
+	var currencies []exchange.AccountCurrencyInfo
+	for c, v := range t.balances {
+		currencies = append(currencies, exchange.AccountCurrencyInfo{CurrencyName: c, TotalValue: v})
+	}
+	return exchange.AccountInfo{
+		Exchange: t.GetName(),
+		Accounts: []exchange.Account{{Currencies: currencies}},
+	}, nil
+}
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	if t.submitErr != nil {
+		return exchange.SubmitOrderResponse{}, t.submitErr
+	}
+	t.orders++
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true, OrderID: strconv.Itoa(t.orders)}, nil
+}
+
+func (t *testExchange) CancelOrder(c *exchange.OrderCancellation) error {
+	t.cancelled = append(t.cancelled, c.OrderID)
+	return nil
+}
+
+func (t *testExchange) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	d, ok := t.info[orderID]
+	if !ok {
+		return d, errors.New("order not found")
+	}
+	return d, nil
+}
+
+func testPrices(_ string, c currency.Code) (float64, error) {
+	switch {
+	case c.Match(currency.USDT):
+		return 1, nil
+	case c.Match(currency.BTC):
+		return 10000, nil
+	}
+	return 0, errors.New("no price")
+}
+
+func testLedger(t *testing.T) (*Ledger, *testExchange) {
+	l, err := NewLedger(testPrices)
+	if err != nil {
+		t.Fatal("Test Failed - NewLedger() error", err)
+	}
+	e := &testExchange{balances: map[currency.Code]float64{
+		currency.USDT: 20000,
+		currency.BTC:  1,
+	}}
+	if err = l.SyncBalances(e); err != nil {
+		t.Fatal("Test Failed - SyncBalances() error", err)
+	}
+	return l, e
+}
+
+func TestNewLedger(t *testing.T) {
+	if _, err := NewLedger(nil); err != errNilPriceFunc {
+		t.Error("Test Failed - NewLedger() expected nil price function error", err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	l, err := NewLedger(testPrices)
+	if err != nil {
+		t.Fatal("Test Failed - NewLedger() error", err)
+	}
+	err = l.Allocate("grid", "Test", currency.USDT, 100)
+	if err == nil || !strings.Contains(err.Error(), errBalancesNotSynced.Error()) {
+		t.Error("Test Failed - Allocate() expected balances not synced error", err)
+	}
+
+	l, _ = testLedger(t)
+	if err = l.Allocate("", "Test", currency.USDT, 100); err != errStrategyNotSet {
+		t.Error("Test Failed - Allocate() expected strategy not set error", err)
+	}
+	if err = l.Allocate("grid", "Test", currency.USDT, 0); err != errZeroAmount {
+		t.Error("Test Failed - Allocate() expected zero amount error", err)
+	}
+	if err = l.Allocate("grid", "Test", currency.USDT, 15000); err != nil {
+		t.Fatal("Test Failed - Allocate() error", err)
+	}
+	// Allocations across strategies cannot exceed the real balance
+	err = l.Allocate("momentum", "Test", currency.USDT, 6000)
+	if err == nil || !strings.Contains(err.Error(), errExceedsBalance.Error()) {
+		t.Error("Test Failed - Allocate() expected exceeds balance error", err)
+	}
+	if err = l.Allocate("momentum", "Test", currency.USDT, 5000); err != nil {
+		t.Error("Test Failed - Allocate() error", err)
+	}
+	if a := l.GetAllocated("test", currency.USDT); a != 20000 {
+		t.Errorf("Test Failed - GetAllocated() expected 20000, got %v", a)
+	}
+	if err = l.Allocate("grid", "Test", currency.USDT, -20000); err == nil {
+		t.Error("Test Failed - Allocate() expected deallocation error")
+	}
+	if err = l.Allocate("grid", "Test", currency.USDT, -5000); err != nil {
+		t.Error("Test Failed - Allocate() deallocation error", err)
+	}
+
+	r, err := l.GetReport("grid")
+	if err != nil {
+		t.Fatal("Test Failed - GetReport() error", err)
+	}
+	if r.Capital != 10000 || r.Equity != 10000 || r.PnL != 0 {
+		t.Errorf("Test Failed - GetReport() unexpected report %+v", r)
+	}
+	if len(l.GetReports()) != 2 {
+		t.Error("Test Failed - GetReports() expected 2 reports")
+	}
+}
+
+func TestSubmitOrder(t *testing.T) {
+	l, e := testLedger(t)
+	if err := l.Allocate("grid", "Test", currency.USDT, 10000); err != nil {
+		t.Fatal("Test Failed - Allocate() error", err)
+	}
+	g := l.Guard(e, "grid")
+
+	_, err := g.SubmitOrder(testPair, exchange.BuyOrderSide, exchange.LimitOrderType, 2, 9000, "")
+	if err == nil || !strings.Contains(err.Error(), ErrInsufficientAllocation.Error()) {
+		t.Error("Test Failed - SubmitOrder() expected insufficient allocation error", err)
+	}
+	// Sells are funded from the base currency which has not been allocated
+	_, err = g.SubmitOrder(testPair, exchange.SellOrderSide, exchange.LimitOrderType, 0.1, 11000, "")
+	if err == nil || !strings.Contains(err.Error(), ErrInsufficientAllocation.Error()) {
+		t.Error("Test Failed - SubmitOrder() expected insufficient allocation error", err)
+	}
+	if e.orders != 0 {
+		t.Fatal("Test Failed - SubmitOrder() rejected order reached exchange")
+	}
+
+	e.submitErr = errors.New("rejected")
+	if _, err = g.SubmitOrder(testPair, exchange.BuyOrderSide, exchange.LimitOrderType, 0.5, 9000, ""); err != e.submitErr {
+		t.Error("Test Failed - SubmitOrder() expected exchange error", err)
+	}
+	e.submitErr = nil
+	resp, err := g.SubmitOrder(testPair, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 9000, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	// A market order is reserved at the current price
+	_, err = g.SubmitOrder(testPair, exchange.BuyOrderSide, exchange.MarketOrderType, 0.2, 0, "")
+	if err == nil {
+		t.Error("Test Failed - SubmitOrder() expected market order to exceed allocation")
+	}
+
+	r, err := l.GetReport("grid")
+	if err != nil {
+		t.Fatal("Test Failed - GetReport() error", err)
+	}
+	if len(r.Balances) != 1 || r.Balances[0].Reserved != 9000 || r.Orders != 1 {
+		t.Errorf("Test Failed - SubmitOrder() unexpected reservation %+v", r.Balances)
+	}
+
+	if err = g.CancelOrder(&exchange.OrderCancellation{OrderID: resp.OrderID}); err != nil {
+		t.Fatal("Test Failed - CancelOrder() error", err)
+	}
+	if r, _ = l.GetReport("grid"); r.Balances[0].Reserved != 0 || r.Orders != 0 {
+		t.Errorf("Test Failed - CancelOrder() reservation not released %+v", r.Balances)
+	}
+
+	if _, err = g.CancelAllOrders(&exchange.OrderCancellation{}); err != ErrNotSupported {
+		t.Error("Test Failed - CancelAllOrders() expected not supported error", err)
+	}
+	if _, err = g.WithdrawCryptocurrencyFunds(&exchange.WithdrawRequest{}); err != ErrNotSupported {
+		t.Error("Test Failed - WithdrawCryptocurrencyFunds() expected not supported error", err)
+	}
+	if g.(*Allocated).Unwrap() != e {
+		t.Error("Test Failed - Unwrap() unexpected exchange")
+	}
+}
+
+func TestFill(t *testing.T) {
+	l, e := testLedger(t)
+	if err := l.Allocate("grid", "Test", currency.USDT, 10000); err != nil {
+		t.Fatal("Test Failed - Allocate() error", err)
+	}
+	if err := l.Allocate("momentum", "Test", currency.BTC, 1); err != nil {
+		t.Fatal("Test Failed - Allocate() error", err)
+	}
+	grid := l.Guard(e, "grid")
+	resp, err := grid.SubmitOrder(testPair, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 9000, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+
+	if err = l.Fill("Test", "missing", 1, 9000, 0, currency.USDT); err == nil {
+		t.Error("Test Failed - Fill() expected order not found error")
+	}
+	if err = l.Fill("Test", resp.OrderID, 0, 9000, 0, currency.USDT); err != errInvalidFill {
+		t.Error("Test Failed - Fill() expected invalid fill error", err)
+	}
+	if err = l.Fill("Test", resp.OrderID, 0.5, 9000, 4.5, currency.USDT); err != nil {
+		t.Fatal("Test Failed - Fill() error", err)
+	}
+	// The remainder fills below the limit price, releasing the difference
+	if err = l.Fill("Test", resp.OrderID, 0.5, 8800, 4.4, currency.USDT); err != nil {
+		t.Fatal("Test Failed - Fill() error", err)
+	}
+
+	r, err := l.GetReport("grid")
+	if err != nil {
+		t.Fatal("Test Failed - GetReport() error", err)
+	}
+	// 10000 - 4500 - 4400 - fees 8.9 = 1091.1 USDT and 1 BTC valued at 10000
+	if r.Orders != 0 || r.Fees != 8.9 || r.Capital != 10000 {
+		t.Errorf("Test Failed - GetReport() unexpected report %+v", r)
+	}
+	if pnl := r.PnL - 1091.1; pnl > 1e-9 || pnl < -1e-9 {
+		t.Errorf("Test Failed - GetReport() expected PnL 1091.1, got %v", r.PnL)
+	}
+	for i := range r.Balances {
+		if r.Balances[i].Reserved != 0 {
+			t.Errorf("Test Failed - Fill() reservation not released %+v", r.Balances[i])
+		}
+	}
+
+	// The other strategy's sell is booked to its own sub-account
+	momentum := l.Guard(e, "momentum")
+	resp, err = momentum.SubmitOrder(testPair, exchange.SellOrderSide, exchange.LimitOrderType, 1, 11000, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if err = l.Fill("Test", resp.OrderID, 1, 11000, 0, currency.USDT); err != nil {
+		t.Fatal("Test Failed - Fill() error", err)
+	}
+	if r, _ = l.GetReport("momentum"); r.PnL != 1000 {
+		t.Errorf("Test Failed - GetReport() expected momentum PnL 1000, got %v", r.PnL)
+	}
+}
+
+func TestReconcile(t *testing.T) {
+	l, e := testLedger(t)
+	if err := l.Allocate("grid", "Test", currency.USDT, 10000); err != nil {
+		t.Fatal("Test Failed - Allocate() error", err)
+	}
+	g := l.Guard(e, "grid")
+	filled, err := g.SubmitOrder(testPair, exchange.BuyOrderSide, exchange.LimitOrderType, 0.5, 9000, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	cancelled, err := g.SubmitOrder(testPair, exchange.BuyOrderSide, exchange.LimitOrderType, 0.5, 8000, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+
+	e.info = map[string]exchange.OrderDetail{
+		filled.OrderID: {Price: 9000, ExecutedAmount: 0.25, Fee: 2, Status: string(exchange.PartiallyFilledOrderStatus)},
+	}
+	if err = l.Reconcile(e); err == nil {
+		t.Error("Test Failed - Reconcile() expected error for unknown order")
+	}
+	e.info[filled.OrderID] = exchange.OrderDetail{Price: 9000, ExecutedAmount: 0.5, Fee: 4, Status: string(exchange.FilledOrderStatus)}
+	e.info[cancelled.OrderID] = exchange.OrderDetail{Status: string(exchange.CancelledOrderStatus)}
+	if err = l.Reconcile(e); err != nil {
+		t.Fatal("Test Failed - Reconcile() error", err)
+	}
+
+	r, err := l.GetReport("grid")
+	if err != nil {
+		t.Fatal("Test Failed - GetReport() error", err)
+	}
+	if r.Orders != 0 || r.Fees != 4 {
+		t.Errorf("Test Failed - Reconcile() unexpected report %+v", r)
+	}
+	for i := range r.Balances {
+		b := r.Balances[i]
+		if b.Reserved != 0 ||
+			(b.Currency.Match(currency.BTC) && b.Total != 0.5) ||
+			(b.Currency.Match(currency.USDT) && b.Total != 5496) {
+			t.Errorf("Test Failed - Reconcile() unexpected balance %+v", b)
+		}
+	}
+}
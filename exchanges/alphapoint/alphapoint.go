@@ -537,11 +537,25 @@ func (a *Alphapoint) SendHTTPRequest(method, path string, data map[string]interf
 	return a.SendPayload(method, path, headers, bytes.NewBuffer(PayloadJSON), result, false, false, a.Verbose, a.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Every
+// endpoint is a POST so they are classified by name
+func mutating(path string) bool {
+	switch path {
+	case alphapointCreateAccount, alphapointWithdraw, alphapointCreateOrder,
+		alphapointModifyOrder, alphapointCancelOrder, alphapointCancelAllOrders:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated request
 func (a *Alphapoint) SendAuthenticatedHTTPRequest(method, path string, data map[string]interface{}, result interface{}) error {
 	if !a.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, a.Name)
 	}
+	if a.DryRunRequest(mutating(path), path, data) {
+		return nil
+	}
 
 	n := a.Requester.GetNonce(true)
 
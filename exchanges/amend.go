@@ -0,0 +1,111 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+)
+
+var (
+	errAmendNoChange      = errors.New("order amendment must change the price or amount")
+	errAmendInvalidValue  = errors.New("order amendment price and amount cannot be negative")
+	errAmendAlreadyFilled = errors.New("order has already filled the amended amount")
+	errAmendUnverified    = errors.New("order cancelled but fills could not be verified, replacement not placed")
+)
+
+// AmendResult holds the outcome of amending an order
+type AmendResult struct {
+	// OrderID is the ID of the amended order, which differs from the original
+	// when the exchange or a cancel/replace issued a new order
+	OrderID string
+	// Replaced is set when the order was cancelled and replaced rather than
+	// amended natively
+	Replaced bool
+	// Filled is the amount of the original order executed before it was
+	// amended
+	Filled float64
+	// Amount is the amount of the amended order
+	Amount float64
+}
+
+// AmendOrder changes the price and total amount of an open order, a zero price
+// or amount leaves it unchanged. The exchange's native amend is used where
+// supported. Otherwise the order is cancelled and a replacement placed for the
+// amended amount less anything already filled. Fills are checked again once
+// the cancellation is confirmed so an order which filled while being
+// cancelled is never replaced in full
+func AmendOrder(e IBotExchange, orderID string, price, amount float64) (AmendResult, error) {
+	if price < 0 || amount < 0 {
+		return AmendResult{}, errAmendInvalidValue
+	}
+	if price == 0 && amount == 0 {
+		return AmendResult{}, errAmendNoChange
+	}
+
+	o, err := e.GetOrderInfo(orderID)
+	if err != nil {
+		return AmendResult{}, err
+	}
+	if price == 0 {
+		price = o.Price
+	}
+	if amount == 0 {
+		amount = o.Amount
+	}
+	if amount <= o.ExecutedAmount {
+		return AmendResult{}, fmt.Errorf("%s %v: filled %v of %v",
+			orderID, errAmendAlreadyFilled, o.ExecutedAmount, amount)
+	}
+
+	newID, err := e.ModifyOrder(&ModifyOrder{
+		OrderID:      orderID,
+		OrderType:    o.OrderType,
+		OrderSide:    o.OrderSide,
+		Price:        price,
+		Amount:       amount,
+		CurrencyPair: o.CurrencyPair,
+	})
+	if err != common.ErrFunctionNotSupported {
+		if err != nil {
+			return AmendResult{}, err
+		}
+		if newID == "" {
+			newID = orderID
+		}
+		return AmendResult{OrderID: newID, Filled: o.ExecutedAmount, Amount: amount}, nil
+	}
+
+	err = e.CancelOrder(&OrderCancellation{
+		AccountID:    o.AccountID,
+		OrderID:      orderID,
+		Side:         o.OrderSide,
+		CurrencyPair: o.CurrencyPair,
+	})
+	if err != nil {
+		return AmendResult{}, err
+	}
+
+	cancelled, err := e.GetOrderInfo(orderID)
+	if err != nil {
+		return AmendResult{Replaced: true, Filled: o.ExecutedAmount},
+			fmt.Errorf("%s %v: %s", orderID, errAmendUnverified, err)
+	}
+	result := AmendResult{Replaced: true, Filled: cancelled.ExecutedAmount}
+	remaining := amount - cancelled.ExecutedAmount
+	if remaining <= 0 {
+		return result, fmt.Errorf("%s %v: filled %v of %v",
+			orderID, errAmendAlreadyFilled, cancelled.ExecutedAmount, amount)
+	}
+
+	resp, err := e.SubmitOrder(o.CurrencyPair, o.OrderSide, o.OrderType, remaining, price, "")
+	if err != nil {
+		return result, err
+	}
+	if !resp.IsOrderPlaced {
+		return result, fmt.Errorf("%s replacement order not placed", orderID)
+	}
+	result.OrderID = resp.OrderID
+	result.Amount = remaining
+	return result, nil
+}
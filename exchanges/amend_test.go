@@ -0,0 +1,107 @@
+package exchange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testAmendExchange struct {
+	IBotExchange
+	order OrderDetail
+	// fillOnCancel is executed between the cancel request and its confirmation
+	fillOnCancel float64
+	native       bool
+	modified     *ModifyOrder
+	cancelled    bool
+	submitted    float64
+	price        float64
+}
+
+func (t *testAmendExchange) GetOrderInfo(_ string) (OrderDetail, error) {
+	return t.order, nil
+}
+
+func (t *testAmendExchange) ModifyOrder(m *ModifyOrder) (string, error) {
+	if !t.native {
+		return "", common.ErrFunctionNotSupported
+	}
+	t.modified = m
+	return "2", nil
+}
+
+func (t *testAmendExchange) CancelOrder(_ *OrderCancellation) error {
+	t.cancelled = true
+	t.order.ExecutedAmount += t.fillOnCancel
+	return nil
+}
+
+func (t *testAmendExchange) SubmitOrder(_ currency.Pair, _ OrderSide, _ OrderType, amount, price float64, _ string) (SubmitOrderResponse, error) {
+	t.submitted = amount
+	t.price = price
+	return SubmitOrderResponse{IsOrderPlaced: true, OrderID: "3"}, nil
+}
+
+func newTestAmendExchange() *testAmendExchange {
+	return &testAmendExchange{order: OrderDetail{
+		ID:             "1",
+		CurrencyPair:   currency.NewPair(currency.BTC, currency.USD),
+		OrderSide:      BuyOrderSide,
+		OrderType:      LimitOrderType,
+		Price:          100,
+		Amount:         2,
+		ExecutedAmount: 0.5,
+	}}
+}
+
+func TestAmendOrder(t *testing.T) {
+	e := newTestAmendExchange()
+	if _, err := AmendOrder(e, "1", 0, 0); err != errAmendNoChange {
+		t.Error("Test Failed - AmendOrder() expected no change error", err)
+	}
+	if _, err := AmendOrder(e, "1", -1, 0); err != errAmendInvalidValue {
+		t.Error("Test Failed - AmendOrder() expected invalid value error", err)
+	}
+	_, err := AmendOrder(e, "1", 0, 0.5)
+	if err == nil || !strings.Contains(err.Error(), errAmendAlreadyFilled.Error()) {
+		t.Error("Test Failed - AmendOrder() expected already filled error", err)
+	}
+
+	e.native = true
+	r, err := AmendOrder(e, "1", 101, 0)
+	if err != nil {
+		t.Fatal("Test Failed - AmendOrder() native error", err)
+	}
+	if r.Replaced || r.OrderID != "2" || e.modified.Price != 101 || e.modified.Amount != 2 || e.cancelled {
+		t.Errorf("Test Failed - AmendOrder() unexpected native amend %+v %+v", r, e.modified)
+	}
+}
+
+func TestAmendOrderCancelReplace(t *testing.T) {
+	e := newTestAmendExchange()
+	r, err := AmendOrder(e, "1", 99, 3)
+	if err != nil {
+		t.Fatal("Test Failed - AmendOrder() cancel/replace error", err)
+	}
+	if !e.cancelled || !r.Replaced || r.OrderID != "3" || e.submitted != 2.5 || e.price != 99 {
+		t.Errorf("Test Failed - AmendOrder() unexpected replacement %+v %v @ %v", r, e.submitted, e.price)
+	}
+
+	// Fills made while cancelling reduce the replacement
+	e = newTestAmendExchange()
+	e.fillOnCancel = 1
+	r, err = AmendOrder(e, "1", 99, 0)
+	if err != nil || r.Filled != 1.5 || e.submitted != 0.5 {
+		t.Errorf("Test Failed - AmendOrder() expected reduced replacement %+v %v %v", r, e.submitted, err)
+	}
+
+	// Orders filled in full while cancelling are not replaced
+	e = newTestAmendExchange()
+	e.fillOnCancel = 1.5
+	r, err = AmendOrder(e, "1", 99, 0)
+	if err == nil || e.submitted != 0 || r.OrderID != "" {
+		t.Errorf("Test Failed - AmendOrder() expected filled order not replaced %+v %v", r, err)
+	}
+}
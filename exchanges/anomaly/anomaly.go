@@ -0,0 +1,518 @@
+// Package anomaly watches account activity for signs of compromise, such as
+// API key changes, withdrawals to untrusted addresses and notifications of
+// access from unknown sources, alerting immediately and optionally locking
+// down trading and withdrawals until manually reset
+package anomaly
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Anomaly types
+const (
+	KeyAdded             = "API_KEY_ADDED"
+	KeyRemoved           = "API_KEY_REMOVED"
+	KeyChanged           = "API_KEY_CHANGED"
+	UnexpectedWithdrawal = "UNEXPECTED_WITHDRAWAL"
+	SuspiciousNotice     = "SUSPICIOUS_NOTIFICATION"
+)
+
+// Anomaly severities
+const (
+	// Warning anomalies are alerted only
+	Warning = "warning"
+	// Critical anomalies lock down the account when lockdown is enabled
+	Critical = "critical"
+)
+
+// Default watcher settings
+const (
+	DefaultCheckInterval = time.Minute
+	// DefaultLookback is how far back withdrawals and notifications are
+	// fetched and remembered
+	DefaultLookback = time.Hour * 24
+)
+
+var (
+	// ErrLockedDown is returned when an order or withdrawal is attempted while
+	// the account is locked down
+	ErrLockedDown = errors.New("trading locked down by account anomaly watcher, manual reset required")
+
+	errNoSources     = errors.New("no activity sources supplied")
+	errNotLockedDown = errors.New("account is not locked down")
+)
+
+// DefaultKeywords are matched against notifications, case insensitive, to
+// flag access from unknown sources
+var DefaultKeywords = []string{
+	"login",
+	"logged in",
+	"sign in",
+	"new ip",
+	"new device",
+	"unknown device",
+	"api key",
+	"password",
+	"two-factor",
+	"2fa",
+}
+
+// APIKey holds an account API key
+type APIKey struct {
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+	CIDR        string   `json:"cidr,omitempty"`
+	Enabled     bool     `json:"enabled"`
+}
+
+// fingerprint returns the key's access settings, permissions are sorted so
+// ordering differences are not reported as changes
+func (k *APIKey) fingerprint() string {
+	perms := append([]string(nil), k.Permissions...)
+	sort.Strings(perms)
+	return fmt.Sprintf("%s|%s|%s|%v", k.Name, strings.Join(perms, ","), k.CIDR, k.Enabled)
+}
+
+// Withdrawal holds an outbound withdrawal
+type Withdrawal struct {
+	ID        string        `json:"id"`
+	Currency  currency.Code `json:"currency"`
+	Amount    float64       `json:"amount"`
+	Address   string        `json:"address"`
+	Status    string        `json:"status"`
+	Timestamp time.Time     `json:"timestamp"`
+}
+
+// Notice holds an account notification
+type Notice struct {
+	ID        string    `json:"id"`
+	Title     string    `json:"title"`
+	Body      string    `json:"body"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Activity holds the account state reported by a source. Nil API keys are
+// treated as not reported rather than every key being removed
+type Activity struct {
+	APIKeys     []APIKey
+	Withdrawals []Withdrawal
+	Notices     []Notice
+}
+
+// Source is implemented by exchanges which report account activity
+type Source interface {
+	GetName() string
+	// GetActivity returns the API keys and the withdrawals and notifications
+	// made since the time
+	GetActivity(since time.Time) (Activity, error)
+}
+
+// Anomaly defines suspicious account activity
+type Anomaly struct {
+	Type      string    `json:"type"`
+	Severity  string    `json:"severity"`
+	Exchange  string    `json:"exchange"`
+	Detail    string    `json:"detail"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// String implements the stringer interface
+func (a *Anomaly) String() string {
+	return fmt.Sprintf("%s %s anomaly %s: %s", a.Exchange, a.Severity, a.Type, a.Detail)
+}
+
+// Config defines the watcher settings
+type Config struct {
+	// Lockdown cancels all open orders and blocks new orders and withdrawals
+	// on every exchange when a critical anomaly is detected
+	Lockdown bool
+	// Keywords flag notifications, DefaultKeywords are used when empty
+	Keywords []string
+	// Trusted returns whether a withdrawal destination is expected, every
+	// withdrawal is reported when nil
+	Trusted  func(exchangeName string, c currency.Code, address string) bool
+	Lookback time.Duration
+}
+
+// Status is a snapshot of the watcher state
+type Status struct {
+	LockedDown   bool      `json:"lockedDown"`
+	LockedDownAt time.Time `json:"lockedDownAt,omitempty"`
+	Reason       string    `json:"reason,omitempty"`
+	Anomalies    []Anomaly `json:"anomalies"`
+	Errors       []string  `json:"errors,omitempty"`
+}
+
+// maxAnomalies limits the anomalies retained for status reporting
+const maxAnomalies = 100
+
+// account holds what has been seen on a single source
+type account struct {
+	keys        map[string]string
+	withdrawals map[string]time.Time
+	notices     map[string]time.Time
+}
+
+// Watcher polls sources for account activity and reports anomalies
+type Watcher struct {
+	cfg       Config
+	sources   []Source
+	exchanges []exchange.IBotExchange
+	onAnomaly func(Anomaly)
+	accounts  map[string]*account
+	status    Status
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+	mtx       sync.Mutex
+}
+
+// New returns an anomaly watcher for the sources. Exchanges are the venues
+// whose open orders are cancelled on lockdown, onAnomaly is called for each
+// anomaly detected
+func New(cfg Config, sources []Source, exchanges []exchange.IBotExchange, onAnomaly func(Anomaly)) (*Watcher, error) {
+	if len(sources) == 0 {
+		return nil, errNoSources
+	}
+	if len(cfg.Keywords) == 0 {
+		cfg.Keywords = DefaultKeywords
+	}
+	if cfg.Lookback <= 0 {
+		cfg.Lookback = DefaultLookback
+	}
+	return &Watcher{
+		cfg:       cfg,
+		sources:   sources,
+		exchanges: exchanges,
+		onAnomaly: onAnomaly,
+		accounts:  make(map[string]*account),
+	}, nil
+}
+
+// Check polls every source and locks down the account when a critical
+// anomaly is found and lockdown is enabled
+func (w *Watcher) Check() {
+	var found []Anomaly
+	for i := range w.sources {
+		anomalies, err := w.check(w.sources[i])
+		if err != nil {
+			log.Errorf("Anomaly watcher failed to check %s: %s", w.sources[i].GetName(), err)
+			continue
+		}
+		found = append(found, anomalies...)
+	}
+
+	var critical []string
+	for i := range found {
+		log.Warnf("Anomaly watcher: %s", found[i].String())
+		if w.onAnomaly != nil {
+			w.onAnomaly(found[i])
+		}
+		if found[i].Severity == Critical {
+			critical = append(critical, found[i].String())
+		}
+	}
+	if w.cfg.Lockdown && len(critical) > 0 {
+		w.Lockdown(strings.Join(critical, "; "))
+	}
+}
+
+// check diffs a source's activity against what was previously seen. The
+// first check records the current state without reporting anomalies
+func (w *Watcher) check(s Source) ([]Anomaly, error) {
+	since := time.Now().Add(-w.cfg.Lookback)
+	activity, err := s.GetActivity(since)
+	if err != nil {
+		return nil, err
+	}
+
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	name := strings.ToLower(s.GetName())
+	acc, seeded := w.accounts[name]
+	if !seeded {
+		acc = &account{
+			withdrawals: make(map[string]time.Time),
+			notices:     make(map[string]time.Time),
+		}
+		w.accounts[name] = acc
+	}
+
+	now := time.Now()
+	var anomalies []Anomaly
+	report := func(anomalyType, severity, detail string) {
+		if !seeded {
+			return
+		}
+		anomalies = append(anomalies, Anomaly{
+			Type:      anomalyType,
+			Severity:  severity,
+			Exchange:  s.GetName(),
+			Detail:    detail,
+			Timestamp: now,
+		})
+	}
+
+	if activity.APIKeys != nil {
+		keys := make(map[string]string, len(activity.APIKeys))
+		for i := range activity.APIKeys {
+			k := &activity.APIKeys[i]
+			keys[k.ID] = k.fingerprint()
+			prev, ok := acc.keys[k.ID]
+			switch {
+			case acc.keys == nil:
+			case !ok:
+				report(KeyAdded, Critical, fmt.Sprintf("API key %s %q added with permissions %v",
+					k.ID, k.Name, k.Permissions))
+			case prev != keys[k.ID]:
+				report(KeyChanged, Critical, fmt.Sprintf("API key %s %q changed to permissions %v, cidr %q, enabled %v",
+					k.ID, k.Name, k.Permissions, k.CIDR, k.Enabled))
+			}
+		}
+		for id := range acc.keys {
+			if _, ok := keys[id]; !ok {
+				report(KeyRemoved, Critical, fmt.Sprintf("API key %s removed", id))
+			}
+		}
+		acc.keys = keys
+	}
+
+	for i := range activity.Withdrawals {
+		wd := &activity.Withdrawals[i]
+		if _, ok := acc.withdrawals[wd.ID]; ok {
+			continue
+		}
+		acc.withdrawals[wd.ID] = wd.Timestamp
+		if w.cfg.Trusted != nil && w.cfg.Trusted(s.GetName(), wd.Currency, wd.Address) {
+			continue
+		}
+		report(UnexpectedWithdrawal, Critical, fmt.Sprintf("withdrawal %s of %v %s to untrusted address %s, status %s",
+			wd.ID, wd.Amount, wd.Currency, wd.Address, wd.Status))
+	}
+
+	for i := range activity.Notices {
+		n := &activity.Notices[i]
+		if _, ok := acc.notices[n.ID]; ok {
+			continue
+		}
+		acc.notices[n.ID] = n.Timestamp
+		if keyword := w.match(n.Title + " " + n.Body); keyword != "" {
+			report(SuspiciousNotice, Warning, fmt.Sprintf("notification %s mentions %q: %s %s",
+				n.ID, keyword, n.Title, n.Body))
+		}
+	}
+
+	prune(acc.withdrawals, since)
+	prune(acc.notices, since)
+	w.status.Anomalies = append(w.status.Anomalies, anomalies...)
+	if excess := len(w.status.Anomalies) - maxAnomalies; excess > 0 {
+		w.status.Anomalies = w.status.Anomalies[excess:]
+	}
+	return anomalies, nil
+}
+
+// match returns the first keyword found in the text
+func (w *Watcher) match(text string) string {
+	text = strings.ToLower(text)
+	for i := range w.cfg.Keywords {
+		if strings.Contains(text, strings.ToLower(w.cfg.Keywords[i])) {
+			return w.cfg.Keywords[i]
+		}
+	}
+	return ""
+}
+
+// prune removes entries seen before the cutoff, entries without a timestamp
+// are kept as they cannot be aged
+func prune(seen map[string]time.Time, cutoff time.Time) {
+	for id, ts := range seen {
+		if !ts.IsZero() && ts.Before(cutoff) {
+			delete(seen, id)
+		}
+	}
+}
+
+// Lockdown blocks new orders and withdrawals and cancels all open orders. It
+// can be called directly to lock the account manually
+func (w *Watcher) Lockdown(reason string) {
+	w.mtx.Lock()
+	if w.status.LockedDown {
+		w.mtx.Unlock()
+		return
+	}
+	w.status.LockedDown = true
+	w.status.LockedDownAt = time.Now()
+	w.status.Reason = reason
+	w.status.Errors = nil
+	w.mtx.Unlock()
+
+	log.Warnf("Anomaly watcher locked down trading: %s", reason)
+
+	var errs []string
+	for i := range w.exchanges {
+		if w.exchanges[i] == nil || !w.exchanges[i].IsEnabled() {
+			continue
+		}
+		_, err := w.exchanges[i].CancelAllOrders(&exchange.OrderCancellation{})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s cancel all orders: %s",
+				w.exchanges[i].GetName(), err))
+		}
+	}
+	for i := range errs {
+		log.Errorf("Anomaly watcher: %s", errs[i])
+	}
+
+	w.mtx.Lock()
+	w.status.Errors = errs
+	w.mtx.Unlock()
+}
+
+// Reset lifts the lockdown once the anomaly has been investigated
+func (w *Watcher) Reset() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if !w.status.LockedDown {
+		return errNotLockedDown
+	}
+	w.status.LockedDown = false
+	w.status.LockedDownAt = time.Time{}
+	w.status.Reason = ""
+	w.status.Errors = nil
+	log.Debugf("Anomaly watcher lockdown reset, trading unlocked")
+	return nil
+}
+
+// IsLockedDown returns whether trading and withdrawals are blocked
+func (w *Watcher) IsLockedDown() bool {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	return w.status.LockedDown
+}
+
+// GetStatus returns the lockdown state and recent anomalies
+func (w *Watcher) GetStatus() Status {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	s := w.status
+	s.Anomalies = append([]Anomaly(nil), w.status.Anomalies...)
+	s.Errors = append([]string(nil), w.status.Errors...)
+	return s
+}
+
+// Start checks the sources at the interval until stopped
+func (w *Watcher) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	w.mtx.Lock()
+	if w.shutdown != nil {
+		w.mtx.Unlock()
+		return
+	}
+	w.shutdown = make(chan struct{})
+	shutdown := w.shutdown
+	w.mtx.Unlock()
+
+	w.wg.Add(1)
+	go func() {
+		defer w.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			w.Check()
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the watcher
+func (w *Watcher) Stop() {
+	w.mtx.Lock()
+	if w.shutdown == nil {
+		w.mtx.Unlock()
+		return
+	}
+	close(w.shutdown)
+	w.shutdown = nil
+	w.mtx.Unlock()
+	w.wg.Wait()
+}
+
+// Guard wraps an exchange so new orders and withdrawals are rejected while
+// the account is locked down. Cancellations remain available
+func (w *Watcher) Guard(e exchange.IBotExchange) exchange.IBotExchange {
+	return &Guarded{IBotExchange: e, watcher: w}
+}
+
+// Guarded is an exchange locked down by an anomaly watcher
+type Guarded struct {
+	exchange.IBotExchange
+	watcher *Watcher
+}
+
+// Unwrap returns the underlying exchange
+func (g *Guarded) Unwrap() exchange.IBotExchange {
+	return g.IBotExchange
+}
+
+// SubmitOrder rejects orders while locked down
+func (g *Guarded) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	if g.watcher.IsLockedDown() {
+		return exchange.SubmitOrderResponse{}, ErrLockedDown
+	}
+	return g.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+}
+
+// CheckOrder rejects orders placed natively while locked down
+func (g *Guarded) CheckOrder(_ *exchange.OrderSubmission) error {
+	if g.watcher.IsLockedDown() {
+		return ErrLockedDown
+	}
+	return nil
+}
+
+// ModifyOrder rejects order amendments while locked down
+func (g *Guarded) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
+	if g.watcher.IsLockedDown() {
+		return "", ErrLockedDown
+	}
+	return g.IBotExchange.ModifyOrder(action)
+}
+
+// WithdrawCryptocurrencyFunds rejects withdrawals while locked down
+func (g *Guarded) WithdrawCryptocurrencyFunds(req *exchange.WithdrawRequest) (string, error) {
+	if g.watcher.IsLockedDown() {
+		return "", ErrLockedDown
+	}
+	return g.IBotExchange.WithdrawCryptocurrencyFunds(req)
+}
+
+// WithdrawFiatFunds rejects withdrawals while locked down
+func (g *Guarded) WithdrawFiatFunds(req *exchange.WithdrawRequest) (string, error) {
+	if g.watcher.IsLockedDown() {
+		return "", ErrLockedDown
+	}
+	return g.IBotExchange.WithdrawFiatFunds(req)
+}
+
+// WithdrawFiatFundsToInternationalBank rejects withdrawals while locked down
+func (g *Guarded) WithdrawFiatFundsToInternationalBank(req *exchange.WithdrawRequest) (string, error) {
+	if g.watcher.IsLockedDown() {
+		return "", ErrLockedDown
+	}
+	return g.IBotExchange.WithdrawFiatFundsToInternationalBank(req)
+}
@@ -0,0 +1,170 @@
+package anomaly
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testSource struct {
+	activity Activity
+	err      error
+}
+
+func (t *testSource) GetName() string { return "Test" }
+
+func (t *testSource) GetActivity(_ time.Time) (Activity, error) {
+	return t.activity, t.err
+}
+
+type testExchange struct {
+	exchange.IBotExchange
+	cancelled int
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) IsEnabled() bool { return true }
+
+func (t *testExchange) CancelAllOrders(_ *exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error) {
+	t.cancelled++
+	return exchange.CancelAllOrdersResponse{}, nil
+}
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) WithdrawCryptocurrencyFunds(_ *exchange.WithdrawRequest) (string, error) {
+	return "1", nil
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{}, nil, nil, nil); err != errNoSources {
+		t.Error("Test Failed - New() expected no sources error", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	s := &testSource{activity: Activity{
+		APIKeys: []APIKey{
+			{ID: "a", Name: "bot", Permissions: []string{"order", "withdraw"}, Enabled: true},
+			{ID: "b", Name: "read", Enabled: true},
+		},
+		Withdrawals: []Withdrawal{{ID: "w1", Currency: currency.BTC, Amount: 1, Address: "evil", Timestamp: now}},
+		Notices:     []Notice{{ID: "n1", Title: "New login", Timestamp: now}},
+	}}
+	trusted := func(_ string, _ currency.Code, address string) bool { return address == "cold" }
+	var anomalies []Anomaly
+	w, err := New(Config{Trusted: trusted}, []Source{s}, nil, func(a Anomaly) {
+		anomalies = append(anomalies, a)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Existing activity is recorded without alerts
+	w.Check()
+	if len(anomalies) != 0 {
+		t.Fatalf("Test Failed - Check() unexpected seed anomalies %+v", anomalies)
+	}
+
+	// Reordered permissions are not a change
+	s.activity.APIKeys[0].Permissions = []string{"withdraw", "order"}
+	w.Check()
+	if len(anomalies) != 0 {
+		t.Fatalf("Test Failed - Check() unexpected anomalies %+v", anomalies)
+	}
+
+	s.activity = Activity{
+		APIKeys: []APIKey{
+			{ID: "a", Name: "bot", Permissions: []string{"order"}, CIDR: "1.2.3.4/32", Enabled: true},
+			{ID: "c", Name: "new", Enabled: true},
+		},
+		Withdrawals: []Withdrawal{
+			{ID: "w1", Currency: currency.BTC, Amount: 1, Address: "evil", Timestamp: now},
+			{ID: "w2", Currency: currency.BTC, Amount: 2, Address: "cold", Timestamp: now},
+			{ID: "w3", Currency: currency.BTC, Amount: 3, Address: "evil", Timestamp: now},
+		},
+		Notices: []Notice{
+			{ID: "n2", Title: "Order filled", Timestamp: now},
+			{ID: "n3", Body: "Access from a NEW IP address", Timestamp: now},
+		},
+	}
+	w.Check()
+	counts := make(map[string]int)
+	for i := range anomalies {
+		counts[anomalies[i].Type]++
+		if anomalies[i].Exchange != "Test" {
+			t.Errorf("Test Failed - Check() unexpected exchange %s", anomalies[i].Exchange)
+		}
+	}
+	expected := map[string]int{
+		KeyAdded:             1,
+		KeyChanged:           1,
+		KeyRemoved:           1,
+		UnexpectedWithdrawal: 1,
+		SuspiciousNotice:     1,
+	}
+	for k, v := range expected {
+		if counts[k] != v {
+			t.Errorf("Test Failed - Check() expected %d %s anomalies, got %d", v, k, counts[k])
+		}
+	}
+	if len(w.GetStatus().Anomalies) != len(anomalies) {
+		t.Error("Test Failed - GetStatus() expected anomalies to be retained")
+	}
+
+	// Sources which stop reporting keys do not remove them
+	anomalies = nil
+	s.activity = Activity{}
+	w.Check()
+	if len(anomalies) != 0 {
+		t.Errorf("Test Failed - Check() unexpected anomalies %+v", anomalies)
+	}
+}
+
+func TestLockdown(t *testing.T) {
+	e := &testExchange{}
+	s := &testSource{}
+	w, err := New(Config{Lockdown: true}, []Source{s}, []exchange.IBotExchange{e}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	g := w.Guard(e)
+
+	w.Check()
+	s.activity.Notices = []Notice{{ID: "1", Title: "Login from new device"}}
+	w.Check()
+	if w.IsLockedDown() {
+		t.Fatal("Test Failed - Check() warnings should not lock down")
+	}
+
+	s.activity.Withdrawals = []Withdrawal{{ID: "1", Currency: currency.BTC, Amount: 1, Address: "x"}}
+	w.Check()
+	if !w.IsLockedDown() || e.cancelled != 1 {
+		t.Fatalf("Test Failed - Check() expected lockdown and cancellation, cancelled %d", e.cancelled)
+	}
+	if _, err = g.SubmitOrder(currency.Pair{}, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 1, ""); err != ErrLockedDown {
+		t.Error("Test Failed - SubmitOrder() expected locked down error", err)
+	}
+	if _, err = g.WithdrawCryptocurrencyFunds(&exchange.WithdrawRequest{}); err != ErrLockedDown {
+		t.Error("Test Failed - WithdrawCryptocurrencyFunds() expected locked down error", err)
+	}
+
+	if err = w.Reset(); err != nil {
+		t.Fatal(err)
+	}
+	if err = w.Reset(); err != errNotLockedDown {
+		t.Error("Test Failed - Reset() expected not locked down error", err)
+	}
+	if _, err = g.SubmitOrder(currency.Pair{}, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 1, ""); err != nil {
+		t.Error("Test Failed - SubmitOrder() expected order after reset", err)
+	}
+	if exchange.Underlying(g) != e {
+		t.Error("Test Failed - Unwrap() expected underlying exchange")
+	}
+}
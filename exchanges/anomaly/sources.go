@@ -0,0 +1,93 @@
+package anomaly
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+)
+
+const (
+	bitmexWithdrawal = "Withdrawal"
+	// bitmexSatoshi converts Bitmex wallet amounts, which are in XBt, to XBT
+	bitmexSatoshi = 1e8
+)
+
+// BitmexActivity reports Bitmex API keys, wallet withdrawals and
+// notifications
+type BitmexActivity struct {
+	Exchange *bitmex.Bitmex
+}
+
+// GetName returns the exchange name
+func (b *BitmexActivity) GetName() string {
+	return b.Exchange.GetName()
+}
+
+// GetActivity returns the account's API keys and the withdrawals and
+// notifications made since the time
+func (b *BitmexActivity) GetActivity(since time.Time) (Activity, error) {
+	keys, err := b.Exchange.GetAPIKeys()
+	if err != nil {
+		return Activity{}, err
+	}
+	activity := Activity{APIKeys: make([]APIKey, 0, len(keys))}
+	for i := range keys {
+		perms := make([]string, len(keys[i].Permissions))
+		for j := range keys[i].Permissions {
+			perms[j] = fmt.Sprint(keys[i].Permissions[j])
+		}
+		activity.APIKeys = append(activity.APIKeys, APIKey{
+			ID:          keys[i].ID,
+			Name:        keys[i].Name,
+			Permissions: perms,
+			CIDR:        keys[i].Cidr,
+			Enabled:     keys[i].Enabled,
+		})
+	}
+
+	history, err := b.Exchange.GetWalletHistory("XBt")
+	if err != nil {
+		return Activity{}, err
+	}
+	for i := range history {
+		h := &history[i]
+		if h.TransactType != bitmexWithdrawal {
+			continue
+		}
+		ts, _ := time.Parse(time.RFC3339, h.TransactTime)
+		if !ts.IsZero() && ts.Before(since) {
+			continue
+		}
+		activity.Withdrawals = append(activity.Withdrawals, Withdrawal{
+			ID:        h.TransactID,
+			Currency:  currency.XBT,
+			Amount:    math.Abs(float64(h.Amount)) / bitmexSatoshi,
+			Address:   h.Address,
+			Status:    h.TransactStatus,
+			Timestamp: ts,
+		})
+	}
+
+	notifications, err := b.Exchange.GetCurrentNotifications()
+	if err != nil {
+		return Activity{}, err
+	}
+	for i := range notifications {
+		n := &notifications[i]
+		ts, _ := time.Parse(time.RFC3339, n.Date)
+		if !ts.IsZero() && ts.Before(since) {
+			continue
+		}
+		activity.Notices = append(activity.Notices, Notice{
+			ID:        strconv.FormatInt(int64(n.ID), 10),
+			Title:     n.Title,
+			Body:      n.Body,
+			Timestamp: ts,
+		})
+	}
+	return activity, nil
+}
@@ -407,11 +407,24 @@ func (a *ANX) SendHTTPRequest(path string, result interface{}) error {
 	return a.SendPayload(http.MethodGet, path, nil, nil, result, false, false, a.Verbose, a.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Every
+// endpoint is a POST so they are classified by name
+func mutating(path string) bool {
+	switch path {
+	case anxOrderNew, anxOrderCancel, anxSend, anxSubaccountNew:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends a authenticated HTTP request
 func (a *ANX) SendAuthenticatedHTTPRequest(path string, params map[string]interface{}, result interface{}) error {
 	if !a.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, a.Name)
 	}
+	if a.DryRunRequest(mutating(path), path, params) {
+		return nil
+	}
 
 	n := a.Requester.GetNonce(true)
 	req := make(map[string]interface{})
@@ -0,0 +1,297 @@
+// Package apiusage counts each exchange's REST requests by endpoint class,
+// the requests rejected for exceeding rate limits and websocket message
+// volumes per UTC day. Usage is projected over the day and compared with the
+// exchange's documented rate limits, showing the headroom left before more
+// pairs or strategies are added
+package apiusage
+
+import (
+	"strings"
+	"sync"
+	"time"
+)
+
+// Endpoint classes requests are counted under
+const (
+	// MarketData requests poll public data such as tickers and orderbooks
+	MarketData = "market_data"
+	// Account requests query balances, orders and fills
+	Account = "account"
+	// Trading requests place, amend and cancel orders or move funds
+	Trading = "trading"
+)
+
+const day = time.Hour * 24
+
+// Counts holds the requests sent to endpoints of a class
+type Counts struct {
+	Requests    int64 `json:"requests"`
+	Errors      int64 `json:"errors"`
+	RateLimited int64 `json:"rateLimited"`
+}
+
+// WebsocketCounts holds the websocket messages sent and received
+type WebsocketCounts struct {
+	Received      int64 `json:"received"`
+	Sent          int64 `json:"sent"`
+	ReceivedBytes int64 `json:"receivedBytes"`
+}
+
+// minuteCounter tracks the busiest minute of a rate limit
+type minuteCounter struct {
+	minute time.Time
+	count  int64
+	peak   int64
+}
+
+func (m *minuteCounter) add(t time.Time) {
+	minute := t.Truncate(time.Minute)
+	if !minute.Equal(m.minute) {
+		m.minute = minute
+		m.count = 0
+	}
+	m.count++
+	if m.count > m.peak {
+		m.peak = m.count
+	}
+}
+
+// Day holds an exchange's usage over a UTC day
+type Day struct {
+	Date      time.Time         `json:"date"`
+	Classes   map[string]Counts `json:"classes"`
+	Websocket WebsocketCounts   `json:"websocket"`
+	// PublicPeakMinute and PrivatePeakMinute are the most unauthenticated
+	// and authenticated requests sent in a minute, as exchanges limit them
+	// separately
+	PublicPeakMinute  int64 `json:"publicPeakMinute"`
+	PrivatePeakMinute int64 `json:"privatePeakMinute"`
+	public            minuteCounter
+	private           minuteCounter
+	publicRequests    int64
+	privateRequests   int64
+}
+
+func newDay(t time.Time) *Day {
+	return &Day{
+		Date:    t.UTC().Truncate(day),
+		Classes: make(map[string]Counts),
+	}
+}
+
+// usage holds an exchange's usage today and on the previous day
+type usage struct {
+	today    *Day
+	previous *Day
+}
+
+// Limit defines a documented rate limit of Requests per Per, a zero limit is
+// unknown
+type Limit struct {
+	Requests int           `json:"requests"`
+	Per      time.Duration `json:"per"`
+}
+
+// perWindow returns the requests allowed over the window
+func (l Limit) perWindow(window time.Duration) float64 {
+	if l.Requests <= 0 || l.Per <= 0 {
+		return 0
+	}
+	return float64(l.Requests) * float64(window) / float64(l.Per)
+}
+
+// Projection compares the requests against a rate limit. Daily utilisation
+// shows whether the day's volume fits the limit, peak utilisation whether the
+// busiest minute did. Utilisations are zero when the limit is unknown
+type Projection struct {
+	Limit     Limit `json:"limit"`
+	Requests  int64 `json:"requests"`
+	Projected int64 `json:"projected"`
+	// DailyLimit is the requests the limit allows in a day
+	DailyLimit      int64   `json:"dailyLimit"`
+	Utilisation     float64 `json:"utilisation"`
+	PeakMinute      int64   `json:"peakMinute"`
+	MinuteLimit     float64 `json:"minuteLimit"`
+	PeakUtilisation float64 `json:"peakUtilisation"`
+	Headroom        float64 `json:"headroom"`
+}
+
+func newProjection(requests, peak int64, limit Limit, factor float64) Projection {
+	p := Projection{
+		Limit:      limit,
+		Requests:   requests,
+		Projected:  int64(float64(requests) * factor),
+		DailyLimit: int64(limit.perWindow(day)),
+		PeakMinute: peak,
+	}
+	p.MinuteLimit = limit.perWindow(time.Minute)
+	if p.DailyLimit > 0 {
+		p.Utilisation = float64(p.Projected) / float64(p.DailyLimit)
+	}
+	if p.MinuteLimit > 0 {
+		p.PeakUtilisation = float64(peak) / p.MinuteLimit
+		// Bursts usually reach rate limits before daily volume does, the
+		// headroom left is that of the more utilised of the two
+		p.Headroom = 1 - p.PeakUtilisation
+		if p.Utilisation > p.PeakUtilisation {
+			p.Headroom = 1 - p.Utilisation
+		}
+	}
+	return p
+}
+
+// Report holds an exchange's usage today projected over the day
+type Report struct {
+	Exchange string            `json:"exchange"`
+	Date     time.Time         `json:"date"`
+	Elapsed  time.Duration     `json:"elapsed"`
+	Classes  map[string]Counts `json:"classes"`
+	Total    Counts            `json:"total"`
+	Public   Projection        `json:"public"`
+	Private  Projection        `json:"private"`
+	// Websocket counts today's messages, ProjectedWebsocket the messages
+	// received projected over the day
+	Websocket          WebsocketCounts `json:"websocket"`
+	ProjectedWebsocket int64           `json:"projectedWebsocket"`
+	Previous           *Day            `json:"previous,omitempty"`
+}
+
+// Tracker counts the API usage of every exchange
+type Tracker struct {
+	exchanges map[string]*usage
+	started   time.Time
+	now       func() time.Time
+	mtx       sync.Mutex
+}
+
+// New returns a tracker counting usage from now
+func New() *Tracker {
+	return &Tracker{
+		exchanges: make(map[string]*usage),
+		started:   time.Now(),
+		now:       time.Now,
+	}
+}
+
+// day returns the exchange's usage today, rolling over to a new day when the
+// date has changed. The caller must hold the lock
+func (t *Tracker) day(exchangeName string, now time.Time) *Day {
+	key := strings.ToLower(exchangeName)
+	u, ok := t.exchanges[key]
+	if !ok {
+		u = &usage{today: newDay(now)}
+		t.exchanges[key] = u
+	}
+	if date := now.UTC().Truncate(day); !date.Equal(u.today.Date) {
+		u.previous = u.today
+		u.today = newDay(now)
+	}
+	return u.today
+}
+
+// Request counts a request sent to an endpoint of the class
+func (t *Tracker) Request(exchangeName, class string, authenticated, failed, rateLimited bool) {
+	now := t.now()
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	d := t.day(exchangeName, now)
+	c := d.Classes[class]
+	c.Requests++
+	if failed {
+		c.Errors++
+	}
+	if rateLimited {
+		c.RateLimited++
+	}
+	d.Classes[class] = c
+	if authenticated {
+		d.privateRequests++
+		d.private.add(now)
+		d.PrivatePeakMinute = d.private.peak
+	} else {
+		d.publicRequests++
+		d.public.add(now)
+		d.PublicPeakMinute = d.public.peak
+	}
+}
+
+// WebsocketMessage counts a websocket message sent or received
+func (t *Tracker) WebsocketMessage(exchangeName string, sent bool, size int) {
+	now := t.now()
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	d := t.day(exchangeName, now)
+	if sent {
+		d.Websocket.Sent++
+		return
+	}
+	d.Websocket.Received++
+	d.Websocket.ReceivedBytes += int64(size)
+}
+
+// Report returns the exchange's usage today, projecting it over the day
+// against its unauthenticated and authenticated rate limits
+func (t *Tracker) Report(exchangeName string, public, private Limit) Report {
+	now := t.now()
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	d := t.day(exchangeName, now)
+
+	r := Report{
+		Exchange:  exchangeName,
+		Date:      d.Date,
+		Classes:   make(map[string]Counts, len(d.Classes)),
+		Websocket: d.Websocket,
+	}
+	start := d.Date
+	if t.started.After(start) {
+		start = t.started
+	}
+	r.Elapsed = now.Sub(start)
+	// Projections are not extrapolated from under a minute of usage
+	elapsed := r.Elapsed
+	if elapsed < time.Minute {
+		elapsed = time.Minute
+	}
+	factor := float64(day) / float64(elapsed)
+	if factor < 1 {
+		factor = 1
+	}
+
+	for class, c := range d.Classes {
+		r.Classes[class] = c
+		r.Total.Requests += c.Requests
+		r.Total.Errors += c.Errors
+		r.Total.RateLimited += c.RateLimited
+	}
+	r.Public = newProjection(d.publicRequests, d.PublicPeakMinute, public, factor)
+	r.Private = newProjection(d.privateRequests, d.PrivatePeakMinute, private, factor)
+	r.ProjectedWebsocket = int64(float64(d.Websocket.Received) * factor)
+
+	u := t.exchanges[strings.ToLower(exchangeName)]
+	if u.previous != nil && u.previous.Date.Equal(d.Date.Add(-day)) {
+		prev := *u.previous
+		r.Previous = &prev
+	}
+	return r
+}
+
+var (
+	active    *Tracker
+	activeMtx sync.RWMutex
+)
+
+// Enable sets the tracker the request and websocket layers count usage with,
+// nil disables tracking
+func Enable(t *Tracker) {
+	activeMtx.Lock()
+	active = t
+	activeMtx.Unlock()
+}
+
+// Active returns the enabled tracker, or nil when tracking is disabled
+func Active() *Tracker {
+	activeMtx.RLock()
+	defer activeMtx.RUnlock()
+	return active
+}
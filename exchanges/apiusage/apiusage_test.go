@@ -0,0 +1,103 @@
+package apiusage
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func newTestTracker(now *time.Time) *Tracker {
+	t := New()
+	t.started = *now
+	t.now = func() time.Time { return *now }
+	return t
+}
+
+func TestReport(t *testing.T) {
+	now := time.Date(2019, 7, 1, 6, 0, 0, 0, time.UTC)
+	tr := newTestTracker(&now)
+	tr.started = now.Add(-time.Hour * 6)
+
+	for i := 0; i < 30; i++ {
+		tr.Request("Binance", MarketData, false, false, false)
+	}
+	now = now.Add(time.Minute)
+	for i := 0; i < 10; i++ {
+		tr.Request("binance", MarketData, false, false, false)
+	}
+	tr.Request("binance", Account, true, false, false)
+	tr.Request("binance", Trading, true, true, true)
+	tr.WebsocketMessage("binance", false, 100)
+	tr.WebsocketMessage("binance", false, 50)
+	tr.WebsocketMessage("binance", true, 20)
+
+	r := tr.Report("Binance", Limit{Requests: 60, Per: time.Minute}, Limit{Requests: 10, Per: time.Second})
+	if r.Total.Requests != 42 || r.Total.Errors != 1 || r.Total.RateLimited != 1 {
+		t.Errorf("Test Failed - Report() unexpected totals %+v", r.Total)
+	}
+	if r.Classes[MarketData].Requests != 40 || r.Classes[Trading].RateLimited != 1 {
+		t.Errorf("Test Failed - Report() unexpected classes %+v", r.Classes)
+	}
+	if r.Websocket.Received != 2 || r.Websocket.Sent != 1 || r.Websocket.ReceivedBytes != 150 {
+		t.Errorf("Test Failed - Report() unexpected websocket counts %+v", r.Websocket)
+	}
+
+	// 40 requests in the first 6h1m of the day are projected over 24h
+	p := r.Public
+	if p.Requests != 40 || p.Projected != int64(40*1440/361) || p.DailyLimit != 86400 ||
+		p.PeakMinute != 30 || p.MinuteLimit != 60 {
+		t.Errorf("Test Failed - Report() unexpected public projection %+v", p)
+	}
+	if !closeTo(p.PeakUtilisation, 0.5) || !closeTo(p.Headroom, 0.5) {
+		t.Errorf("Test Failed - Report() expected headroom of the busiest minute %+v", p)
+	}
+	if r.Private.Requests != 2 || r.Private.MinuteLimit != 600 || r.Private.PeakMinute != 2 {
+		t.Errorf("Test Failed - Report() unexpected private projection %+v", r.Private)
+	}
+
+	unknown := tr.Report("binance", Limit{}, Limit{})
+	if unknown.Public.DailyLimit != 0 || unknown.Public.Headroom != 0 || unknown.Public.Projected == 0 {
+		t.Errorf("Test Failed - Report() expected projection without limit %+v", unknown.Public)
+	}
+}
+
+func TestReportRollover(t *testing.T) {
+	now := time.Date(2019, 7, 1, 23, 59, 0, 0, time.UTC)
+	tr := newTestTracker(&now)
+	tr.Request("kraken", MarketData, false, false, false)
+	tr.WebsocketMessage("kraken", false, 10)
+
+	now = now.Add(time.Hour)
+	tr.Request("kraken", Account, true, false, false)
+	r := tr.Report("kraken", Limit{}, Limit{})
+	if !r.Date.Equal(time.Date(2019, 7, 2, 0, 0, 0, 0, time.UTC)) || r.Total.Requests != 1 ||
+		r.Elapsed != time.Minute*59 {
+		t.Errorf("Test Failed - Report() expected new day %+v", r)
+	}
+	if r.Previous == nil || r.Previous.Classes[MarketData].Requests != 1 || r.Previous.Websocket.Received != 1 {
+		t.Fatalf("Test Failed - Report() expected previous day %+v", r.Previous)
+	}
+
+	// Days without usage leave no previous day
+	now = now.Add(time.Hour * 48)
+	r = tr.Report("kraken", Limit{}, Limit{})
+	if r.Previous != nil || r.Total.Requests != 0 {
+		t.Errorf("Test Failed - Report() expected empty day %+v", r)
+	}
+}
+
+func TestEnable(t *testing.T) {
+	if Active() != nil {
+		t.Fatal("Test Failed - Active() expected tracking disabled")
+	}
+	tr := New()
+	Enable(tr)
+	if Active() != tr {
+		t.Error("Test Failed - Enable() tracker not active")
+	}
+	Enable(nil)
+}
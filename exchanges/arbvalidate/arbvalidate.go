@@ -0,0 +1,542 @@
+// Package arbvalidate replays recorded orderbooks to validate a cross-exchange
+// arbitrage configuration before it runs live. Opportunities are detected on
+// the recorded books and each leg executes against its venue's book once the
+// venue's order latency has elapsed, paying taker fees and the withdrawal fee
+// of rebalancing the traded inventory. Inventory moved by a trade is
+// unavailable until its transfer completes and liquidity taken by a fill is
+// not traded again until the venue's book next updates. The report compares the captured
+// profit to the profit expected at detection and recommends whether to go live
+package arbvalidate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wsrecorder"
+)
+
+// Default validation settings
+const (
+	DefaultMinEdgeBps   = 5
+	DefaultSharpePeriod = time.Hour
+	DefaultMinSharpe    = 2
+	DefaultMinCapture   = 0.5
+	DefaultMinTrades    = 10
+)
+
+var (
+	errTooFewVenues   = errors.New("at least two venues are required")
+	errDuplicateVenue = errors.New("venue supplied more than once")
+	errInvalidAmount  = errors.New("max trade amount must be positive")
+	errInvalidVenue   = errors.New("venue fees, latency, transfer time and balances cannot be negative")
+	errInvalidPair    = errors.New("pair not set")
+	errNoBooks        = errors.New("no recorded books found for the venues")
+)
+
+// Venue holds the execution costs of trading on an exchange
+type Venue struct {
+	Exchange string
+	// TakerFee is charged as a fraction of the traded notional
+	TakerFee float64
+	// Latency is the delay between detecting an opportunity and the order
+	// reaching the venue
+	Latency time.Duration
+	// TransferTime is how long inventory withdrawn from the venue takes to
+	// arrive at another venue
+	TransferTime time.Duration
+	// WithdrawFee is charged in the base currency on each withdrawal
+	WithdrawFee float64
+	// Base and Quote are the starting balances, zero is unlimited
+	Base  float64
+	Quote float64
+}
+
+// Config defines the arbitrage configuration validated
+type Config struct {
+	Pair      currency.Pair
+	AssetType string
+	Venues    []Venue
+	// MaxAmount is the largest amount traded on a single opportunity
+	MaxAmount float64
+	// MinEdgeBps is the edge after taker fees each traded unit must clear
+	MinEdgeBps float64
+	// SharpePeriod is the period profits are bucketed by for the Sharpe ratio
+	SharpePeriod time.Duration
+	// MinSharpe, MinCapture and MinTrades are the thresholds the replay must
+	// meet for the configuration to go live
+	MinSharpe  float64
+	MinCapture float64
+	MinTrades  int
+}
+
+// Trade holds a replayed opportunity
+type Trade struct {
+	Detected  time.Time `json:"detected"`
+	Completed time.Time `json:"completed"`
+	Buy       string    `json:"buy"`
+	Sell      string    `json:"sell"`
+	Amount    float64   `json:"amount"`
+	BuyPrice  float64   `json:"buyPrice"`
+	SellPrice float64   `json:"sellPrice"`
+	// Unhedged is the amount one leg filled beyond the other when the book
+	// thinned during the latency
+	Unhedged    float64 `json:"unhedged"`
+	ExpectedPnL float64 `json:"expectedPnl"`
+	RealisedPnL float64 `json:"realisedPnl"`
+}
+
+// Report holds the outcome of a validation run
+type Report struct {
+	Pair      currency.Pair `json:"pair"`
+	AssetType string        `json:"assetType"`
+	Start     time.Time     `json:"start"`
+	End       time.Time     `json:"end"`
+	Detected  int           `json:"detected"`
+	Completed int           `json:"completed"`
+	// Incomplete counts trades whose legs had not executed by the end of
+	// the recording
+	Incomplete int `json:"incomplete"`
+	// InventoryLimited counts opportunities missed while inventory was
+	// committed or in transfer
+	InventoryLimited int     `json:"inventoryLimited"`
+	ExpectedPnL      float64 `json:"expectedPnl"`
+	RealisedPnL      float64 `json:"realisedPnl"`
+	// Capture is the realised profit as a fraction of the expected profit
+	Capture float64 `json:"capture"`
+	WinRate float64 `json:"winRate"`
+	// Sharpe is the annualised Sharpe ratio of the realised profit per
+	// SharpePeriod
+	Sharpe  float64  `json:"sharpe"`
+	Trades  []Trade  `json:"trades"`
+	Go      bool     `json:"go"`
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// String implements the stringer interface
+func (r *Report) String() string {
+	verdict := "GO"
+	if !r.Go {
+		verdict = "NO-GO: " + strings.Join(r.Reasons, ", ")
+	}
+	return fmt.Sprintf("%s %s %s to %s\n\topportunities: %d, completed: %d, incomplete: %d, inventory limited: %d\n\texpected: %.8f, realised: %.8f, capture: %.2f%%\n\twin rate: %.2f%%, sharpe: %.2f\n\t%s",
+		r.Pair, r.AssetType,
+		r.Start.Format(time.RFC3339), r.End.Format(time.RFC3339),
+		r.Detected, r.Completed, r.Incomplete, r.InventoryLimited,
+		r.ExpectedPnL, r.RealisedPnL, r.Capture*100,
+		r.WinRate*100, r.Sharpe,
+		verdict)
+}
+
+// balance holds a venue's available inventory
+type balance struct {
+	base, quote           float64
+	limitBase, limitQuote bool
+}
+
+// transfer holds inventory arriving at a venue
+type transfer struct {
+	arrives     time.Time
+	venue       int
+	base, quote float64
+}
+
+// leg holds one side of an opportunity awaiting execution
+type leg struct {
+	venue    int
+	executes time.Time
+	done     bool
+	filled   float64
+	notional float64
+}
+
+// opportunity holds a detected trade with legs in flight
+type opportunity struct {
+	trade     Trade
+	buy, sell leg
+	cost      float64
+}
+
+// validator replays a recorded stream
+type validator struct {
+	cfg       Config
+	it        *wsrecorder.Iterator
+	balances  []balance
+	transfers []transfer
+	inFlight  map[[2]int]*opportunity
+	// consumed marks the venues whose recorded book still holds liquidity
+	// taken by a replayed fill, cleared by the venue's next book update
+	consumed []bool
+	report   Report
+}
+
+// Validate replays the recorded stream through the arbitrage configuration
+func Validate(r io.Reader, cfg Config) (*Report, error) {
+	err := cfg.setDefaults()
+	if err != nil {
+		return nil, err
+	}
+	v := validator{
+		cfg:      cfg,
+		it:       wsrecorder.NewIterator(r),
+		balances: make([]balance, len(cfg.Venues)),
+		inFlight: make(map[[2]int]*opportunity),
+		consumed: make([]bool, len(cfg.Venues)),
+		report:   Report{Pair: cfg.Pair, AssetType: cfg.AssetType},
+	}
+	for i := range cfg.Venues {
+		v.balances[i] = balance{
+			base:       cfg.Venues[i].Base,
+			quote:      cfg.Venues[i].Quote,
+			limitBase:  cfg.Venues[i].Base > 0,
+			limitQuote: cfg.Venues[i].Quote > 0,
+		}
+	}
+
+	var books bool
+	for v.it.Next() {
+		e := v.it.Event()
+		if v.report.Start.IsZero() {
+			v.report.Start = e.Timestamp
+		}
+		v.report.End = e.Timestamp
+		venue := -1
+		if (e.Type == wsrecorder.Snapshot || e.Type == wsrecorder.Delta) &&
+			strings.EqualFold(e.AssetType, cfg.AssetType) &&
+			e.Pair.Base.Match(cfg.Pair.Base) && e.Pair.Quote.Match(cfg.Pair.Quote) {
+			venue = v.venue(e.Exchange)
+		}
+		if venue >= 0 {
+			books = true
+			v.consumed[venue] = false
+		}
+		v.arrive(e.Timestamp)
+		v.execute(e.Timestamp)
+		if venue >= 0 {
+			v.detect(e.Timestamp)
+		}
+	}
+	if err = v.it.Err(); err != nil {
+		return nil, err
+	}
+	if !books {
+		return nil, errNoBooks
+	}
+	v.report.Incomplete = len(v.inFlight)
+	v.report.summarise(&cfg)
+	return &v.report, nil
+}
+
+// setDefaults validates the config and applies its defaults
+func (c *Config) setDefaults() error {
+	if c.Pair.IsEmpty() {
+		return errInvalidPair
+	}
+	if len(c.Venues) < 2 {
+		return errTooFewVenues
+	}
+	if c.MaxAmount <= 0 {
+		return errInvalidAmount
+	}
+	seen := make(map[string]bool, len(c.Venues))
+	for i := range c.Venues {
+		v := &c.Venues[i]
+		name := strings.ToLower(v.Exchange)
+		if seen[name] {
+			return fmt.Errorf("%s %v", v.Exchange, errDuplicateVenue)
+		}
+		seen[name] = true
+		if v.TakerFee < 0 || v.Latency < 0 || v.TransferTime < 0 ||
+			v.WithdrawFee < 0 || v.Base < 0 || v.Quote < 0 {
+			return fmt.Errorf("%s %v", v.Exchange, errInvalidVenue)
+		}
+	}
+	if c.AssetType == "" {
+		c.AssetType = orderbook.Spot
+	}
+	if c.MinEdgeBps <= 0 {
+		c.MinEdgeBps = DefaultMinEdgeBps
+	}
+	if c.SharpePeriod <= 0 {
+		c.SharpePeriod = DefaultSharpePeriod
+	}
+	if c.MinSharpe <= 0 {
+		c.MinSharpe = DefaultMinSharpe
+	}
+	if c.MinCapture <= 0 {
+		c.MinCapture = DefaultMinCapture
+	}
+	if c.MinTrades <= 0 {
+		c.MinTrades = DefaultMinTrades
+	}
+	return nil
+}
+
+func (v *validator) venue(exchangeName string) int {
+	for i := range v.cfg.Venues {
+		if strings.EqualFold(v.cfg.Venues[i].Exchange, exchangeName) {
+			return i
+		}
+	}
+	return -1
+}
+
+func (v *validator) book(venue int) *wsrecorder.Book {
+	return v.it.BookFor(v.cfg.Venues[venue].Exchange, v.cfg.Pair, v.cfg.AssetType)
+}
+
+// arrive credits the transfers which have completed
+func (v *validator) arrive(t time.Time) {
+	pending := v.transfers[:0]
+	for _, x := range v.transfers {
+		if x.arrives.After(t) {
+			pending = append(pending, x)
+			continue
+		}
+		v.balances[x.venue].base += x.base
+		v.balances[x.venue].quote += x.quote
+	}
+	v.transfers = pending
+}
+
+// detect opens an opportunity on each route without one in flight
+func (v *validator) detect(t time.Time) {
+	for buy := range v.cfg.Venues {
+		for sell := range v.cfg.Venues {
+			route := [2]int{buy, sell}
+			if buy == sell || v.inFlight[route] != nil || v.consumed[buy] || v.consumed[sell] {
+				continue
+			}
+			buyBook, sellBook := v.book(buy), v.book(sell)
+			if buyBook == nil || sellBook == nil {
+				continue
+			}
+			asks, bids := buyBook.Asks(), sellBook.Bids()
+			buyVenue, sellVenue := &v.cfg.Venues[buy], &v.cfg.Venues[sell]
+			amount := math.Min(v.cfg.MaxAmount, edgeAmount(asks, bids,
+				buyVenue.TakerFee, sellVenue.TakerFee, v.cfg.MinEdgeBps/10000))
+			if amount <= 0 {
+				continue
+			}
+
+			b, s := &v.balances[buy], &v.balances[sell]
+			if s.limitBase {
+				amount = math.Min(amount, s.base)
+			}
+			_, cost := walk(asks, amount)
+			if b.limitQuote && cost > b.quote {
+				amount *= b.quote / cost
+				_, cost = walk(asks, amount)
+			}
+			if amount <= 0 {
+				v.report.InventoryLimited++
+				continue
+			}
+			_, proceeds := walk(bids, amount)
+			expected := proceeds*(1-sellVenue.TakerFee) - cost*(1+buyVenue.TakerFee) -
+				buyVenue.WithdrawFee*proceeds/amount
+			if expected <= 0 {
+				continue
+			}
+
+			if b.limitQuote {
+				b.quote -= cost
+			}
+			if s.limitBase {
+				s.base -= amount
+			}
+			v.inFlight[route] = &opportunity{
+				trade: Trade{
+					Detected:    t,
+					Buy:         buyVenue.Exchange,
+					Sell:        sellVenue.Exchange,
+					Amount:      amount,
+					ExpectedPnL: expected,
+				},
+				buy:  leg{venue: buy, executes: t.Add(buyVenue.Latency)},
+				sell: leg{venue: sell, executes: t.Add(sellVenue.Latency)},
+				cost: cost,
+			}
+			v.report.Detected++
+		}
+	}
+}
+
+// execute fills the legs whose latency has elapsed against the current books
+// and completes the opportunities with both legs filled
+func (v *validator) execute(t time.Time) {
+	for route, o := range v.inFlight {
+		if !o.buy.done && !o.buy.executes.After(t) {
+			if book := v.book(o.buy.venue); book != nil {
+				o.buy.filled, o.buy.notional = walk(book.Asks(), o.trade.Amount)
+				o.buy.done = true
+				v.consumed[o.buy.venue] = true
+			}
+		}
+		if !o.sell.done && !o.sell.executes.After(t) {
+			if book := v.book(o.sell.venue); book != nil {
+				o.sell.filled, o.sell.notional = walk(book.Bids(), o.trade.Amount)
+				o.sell.done = true
+				v.consumed[o.sell.venue] = true
+			}
+		}
+		if !o.buy.done || !o.sell.done {
+			continue
+		}
+		delete(v.inFlight, route)
+		v.complete(o, t)
+	}
+}
+
+// complete records the trade's realised profit and transfers the traded
+// inventory back to the venues it left
+func (v *validator) complete(o *opportunity, t time.Time) {
+	buyVenue, sellVenue := &v.cfg.Venues[o.buy.venue], &v.cfg.Venues[o.sell.venue]
+	tr := o.trade
+	tr.Completed = t
+	if o.buy.filled > 0 {
+		tr.BuyPrice = o.buy.notional / o.buy.filled
+	}
+	if o.sell.filled > 0 {
+		tr.SellPrice = o.sell.notional / o.sell.filled
+	}
+	hedged := math.Min(o.buy.filled, o.sell.filled)
+	tr.Unhedged = math.Abs(o.buy.filled - o.sell.filled)
+	tr.RealisedPnL = hedged*(tr.SellPrice*(1-sellVenue.TakerFee)-tr.BuyPrice*(1+buyVenue.TakerFee)) -
+		(o.buy.filled-hedged)*tr.BuyPrice*buyVenue.TakerFee -
+		(o.sell.filled-hedged)*tr.SellPrice*sellVenue.TakerFee -
+		buyVenue.WithdrawFee*tr.SellPrice
+	v.report.Trades = append(v.report.Trades, tr)
+
+	if v.balances[o.sell.venue].limitBase {
+		v.transfers = append(v.transfers, transfer{
+			arrives: t.Add(buyVenue.TransferTime),
+			venue:   o.sell.venue,
+			base:    tr.Amount - buyVenue.WithdrawFee,
+		})
+	}
+	if v.balances[o.buy.venue].limitQuote {
+		v.transfers = append(v.transfers, transfer{
+			arrives: t.Add(sellVenue.TransferTime),
+			venue:   o.buy.venue,
+			quote:   o.cost,
+		})
+	}
+}
+
+// edgeAmount returns the amount tradable buying the asks and selling the bids
+// while each unit clears the fees by the minimum edge
+func edgeAmount(asks, bids []orderbook.Item, buyFee, sellFee, minEdge float64) float64 {
+	if len(asks) == 0 || len(bids) == 0 {
+		return 0
+	}
+	var amount float64
+	var i, j int
+	askLeft, bidLeft := asks[0].Amount, bids[0].Amount
+	for {
+		buy := asks[i].Price * (1 + buyFee)
+		sell := bids[j].Price * (1 - sellFee)
+		if (sell-buy)/buy < minEdge {
+			return amount
+		}
+		step := math.Min(askLeft, bidLeft)
+		amount += step
+		askLeft -= step
+		bidLeft -= step
+		if askLeft <= 0 {
+			if i++; i == len(asks) {
+				return amount
+			}
+			askLeft = asks[i].Amount
+		}
+		if bidLeft <= 0 {
+			if j++; j == len(bids) {
+				return amount
+			}
+			bidLeft = bids[j].Amount
+		}
+	}
+}
+
+// walk returns the amount filled and its notional taking the levels in order
+func walk(levels []orderbook.Item, amount float64) (filled, notional float64) {
+	for i := range levels {
+		if filled >= amount {
+			break
+		}
+		take := math.Min(levels[i].Amount, amount-filled)
+		filled += take
+		notional += take * levels[i].Price
+	}
+	return filled, notional
+}
+
+// summarise totals the trades and decides whether the configuration goes live
+func (r *Report) summarise(cfg *Config) {
+	var wins int
+	for i := range r.Trades {
+		r.ExpectedPnL += r.Trades[i].ExpectedPnL
+		r.RealisedPnL += r.Trades[i].RealisedPnL
+		if r.Trades[i].RealisedPnL > 0 {
+			wins++
+		}
+	}
+	r.Completed = len(r.Trades)
+	if r.Completed > 0 {
+		r.WinRate = float64(wins) / float64(r.Completed)
+	}
+	if r.ExpectedPnL > 0 {
+		r.Capture = r.RealisedPnL / r.ExpectedPnL
+	}
+	r.Sharpe = sharpe(r.Trades, r.Start, r.End, cfg.SharpePeriod)
+
+	if r.Completed < cfg.MinTrades {
+		r.Reasons = append(r.Reasons, fmt.Sprintf("%d trades below minimum %d", r.Completed, cfg.MinTrades))
+	}
+	if r.RealisedPnL <= 0 {
+		r.Reasons = append(r.Reasons, "realised profit not positive")
+	}
+	if r.Capture < cfg.MinCapture {
+		r.Reasons = append(r.Reasons, fmt.Sprintf("capture %.2f below minimum %.2f", r.Capture, cfg.MinCapture))
+	}
+	if r.Sharpe < cfg.MinSharpe {
+		r.Reasons = append(r.Reasons, fmt.Sprintf("sharpe %.2f below minimum %.2f", r.Sharpe, cfg.MinSharpe))
+	}
+	r.Go = len(r.Reasons) == 0
+}
+
+// sharpe returns the annualised Sharpe ratio of the realised profit bucketed
+// by period across the replay, periods without trades count as flat
+func sharpe(trades []Trade, start, end time.Time, period time.Duration) float64 {
+	n := int(end.Sub(start)/period) + 1
+	if n < 2 {
+		return 0
+	}
+	buckets := make([]float64, n)
+	for i := range trades {
+		b := int(trades[i].Completed.Sub(start) / period)
+		if b >= n {
+			b = n - 1
+		}
+		buckets[b] += trades[i].RealisedPnL
+	}
+	var mean float64
+	for i := range buckets {
+		mean += buckets[i]
+	}
+	mean /= float64(n)
+	var variance float64
+	for i := range buckets {
+		variance += (buckets[i] - mean) * (buckets[i] - mean)
+	}
+	std := math.Sqrt(variance / float64(n-1))
+	if std == 0 {
+		return 0
+	}
+	return mean / std * math.Sqrt(float64(time.Hour*24*365)/float64(period))
+}
@@ -0,0 +1,160 @@
+package arbvalidate
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wsrecorder"
+)
+
+var (
+	testPair  = currency.NewPairWithDelimiter("BTC", "USD", "-")
+	testStart = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+type testRecorder struct {
+	t   *testing.T
+	r   *wsrecorder.Recorder
+	buf bytes.Buffer
+}
+
+func newTestRecorder(t *testing.T) *testRecorder {
+	tr := &testRecorder{t: t}
+	var err error
+	tr.r, err = wsrecorder.NewRecorder(&tr.buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return tr
+}
+
+func (tr *testRecorder) book(exch string, bid, ask float64, ts time.Duration) {
+	err := tr.r.RecordOrderbook(&orderbook.Base{
+		ExchangeName: exch,
+		Pair:         testPair,
+		AssetType:    orderbook.Spot,
+		Bids:         []orderbook.Item{{Price: bid, Amount: 1}},
+		Asks:         []orderbook.Item{{Price: ask, Amount: 1}},
+	}, testStart.Add(ts))
+	if err != nil {
+		tr.t.Fatal(err)
+	}
+}
+
+func testConfig() Config {
+	return Config{
+		Pair:      testPair,
+		MaxAmount: 1,
+		Venues: []Venue{
+			{Exchange: "A", TakerFee: 0.001, Latency: time.Second},
+			{Exchange: "B", TakerFee: 0.001, Latency: time.Second},
+		},
+	}
+}
+
+func TestConfig(t *testing.T) {
+	c := testConfig()
+	c.Venues = c.Venues[:1]
+	if _, err := Validate(nil, c); err != errTooFewVenues {
+		t.Errorf("Test Failed - Validate() expected errTooFewVenues, received %v", err)
+	}
+	c = testConfig()
+	c.MaxAmount = 0
+	if _, err := Validate(nil, c); err != errInvalidAmount {
+		t.Errorf("Test Failed - Validate() expected errInvalidAmount, received %v", err)
+	}
+	c = testConfig()
+	c.Venues[1].Exchange = "a"
+	if _, err := Validate(nil, c); err == nil {
+		t.Error("Test Failed - Validate() expected error for duplicate venue")
+	}
+	c = testConfig()
+	c.Venues[0].TakerFee = -1
+	if _, err := Validate(nil, c); err == nil {
+		t.Error("Test Failed - Validate() expected error for negative fee")
+	}
+	if _, err := Validate(&bytes.Buffer{}, testConfig()); err != errNoBooks {
+		t.Errorf("Test Failed - Validate() expected errNoBooks, received %v", err)
+	}
+}
+
+func TestEdgeAmount(t *testing.T) {
+	asks := []orderbook.Item{{Price: 100, Amount: 1}, {Price: 100.5, Amount: 2}, {Price: 102, Amount: 5}}
+	bids := []orderbook.Item{{Price: 101.5, Amount: 0.5}, {Price: 101, Amount: 3}}
+	// 100.5 against 101 clears 10bps after fees, 102 does not
+	if a := edgeAmount(asks, bids, 0.001, 0.001, 0.001); !near(a, 3) {
+		t.Errorf("Test Failed - edgeAmount() expected 3, received %v", a)
+	}
+	if a := edgeAmount(asks, bids, 0.01, 0.01, 0.001); a != 0 {
+		t.Errorf("Test Failed - edgeAmount() expected no edge, received %v", a)
+	}
+	if filled, notional := walk(asks, 2); filled != 2 || !near(notional, 200.5) {
+		t.Errorf("Test Failed - walk() unexpected fill %v %v", filled, notional)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	tr := newTestRecorder(t)
+	tr.book("A", 99, 100, 0)
+	tr.book("B", 101, 102, 0)
+	// B's bid drops before the sell leg arrives
+	tr.book("B", 100.5, 102, time.Second)
+	tr.book("A", 98, 100, time.Second*2)
+	tr.book("B", 101, 102, time.Second*3)
+
+	c := testConfig()
+	c.Venues[1].Base = 1
+	c.Venues[0].TransferTime = time.Hour
+	r, err := Validate(&tr.buf, c)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Detected != 1 || r.Completed != 1 || r.Incomplete != 0 {
+		t.Fatalf("Test Failed - Validate() unexpected counts %+v", r)
+	}
+	tr0 := r.Trades[0]
+	if tr0.Buy != "A" || tr0.Sell != "B" || tr0.Amount != 1 ||
+		!near(tr0.ExpectedPnL, 101*0.999-100*1.001) ||
+		!near(tr0.RealisedPnL, 100.5*0.999-100*1.001) {
+		t.Errorf("Test Failed - Validate() unexpected trade %+v", tr0)
+	}
+	// B's inventory is in transfer for an hour so the repeated edge is missed
+	if r.InventoryLimited == 0 {
+		t.Error("Test Failed - Validate() expected opportunities limited by inventory")
+	}
+	if r.Go || len(r.Reasons) == 0 {
+		t.Errorf("Test Failed - Validate() expected no-go on a single trade %+v", r)
+	}
+	if !near(r.Capture, r.RealisedPnL/r.ExpectedPnL) || r.WinRate != 1 {
+		t.Errorf("Test Failed - Validate() unexpected summary %+v", r)
+	}
+}
+
+func TestValidateGo(t *testing.T) {
+	tr := newTestRecorder(t)
+	// A profitable edge reopens every 10 minutes for a day with a varying
+	// sell price
+	for i := 0; i < 144; i++ {
+		ts := time.Duration(i) * time.Minute * 10
+		bid := 101 + float64(i%4)*0.1
+		tr.book("A", 99, 100, ts)
+		tr.book("B", bid, 103, ts)
+		tr.book("A", 98, 100, ts+time.Second*2)
+		tr.book("B", 99.5, 103, ts+time.Minute)
+	}
+	r, err := Validate(&tr.buf, testConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Go || r.Completed != 144 || !near(r.Capture, 1) || r.Sharpe < DefaultMinSharpe {
+		t.Errorf("Test Failed - Validate() expected go %s", r)
+	}
+}
@@ -0,0 +1,78 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// ErrNetworkSuspended is returned when deposits or withdrawals of an asset are
+// suspended on every network
+var ErrNetworkSuspended = errors.New("asset network suspended")
+
+// NetworkStatus holds whether deposits and withdrawals are enabled on one of
+// an asset's networks
+type NetworkStatus struct {
+	// Network is the chain the asset is transferred on, empty when the
+	// exchange supports a single network
+	Network         string `json:"network,omitempty"`
+	DepositEnabled  bool   `json:"depositEnabled"`
+	WithdrawEnabled bool   `json:"withdrawEnabled"`
+	Reason          string `json:"reason,omitempty"`
+}
+
+// AssetStatus holds the deposit and withdrawal status of an asset on an
+// exchange
+type AssetStatus struct {
+	Exchange string          `json:"exchange"`
+	Currency currency.Code   `json:"currency"`
+	Networks []NetworkStatus `json:"networks"`
+}
+
+// CheckDeposit returns an error wrapping ErrNetworkSuspended when the asset
+// cannot be deposited on any network
+func (a *AssetStatus) CheckDeposit() error {
+	for i := range a.Networks {
+		if a.Networks[i].DepositEnabled {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %s deposits %v%s", a.Exchange, a.Currency, ErrNetworkSuspended, a.reasons())
+}
+
+// CheckWithdraw returns an error wrapping ErrNetworkSuspended when the asset
+// cannot be withdrawn on any network
+func (a *AssetStatus) CheckWithdraw() error {
+	for i := range a.Networks {
+		if a.Networks[i].WithdrawEnabled {
+			return nil
+		}
+	}
+	return fmt.Errorf("%s %s withdrawals %v%s", a.Exchange, a.Currency, ErrNetworkSuspended, a.reasons())
+}
+
+func (a *AssetStatus) reasons() string {
+	var why []string
+	for i := range a.Networks {
+		if a.Networks[i].Reason == "" {
+			continue
+		}
+		if a.Networks[i].Network != "" {
+			why = append(why, a.Networks[i].Network+" "+a.Networks[i].Reason)
+			continue
+		}
+		why = append(why, a.Networks[i].Reason)
+	}
+	if len(why) == 0 {
+		return ""
+	}
+	return ": " + strings.Join(why, ", ")
+}
+
+// AssetStatusChecker is implemented by exchanges which publish whether
+// deposits and withdrawals of each asset are enabled
+type AssetStatusChecker interface {
+	GetAssetStatus(c currency.Code) (AssetStatus, error)
+}
@@ -0,0 +1,382 @@
+// Package balances polls the balances of every exchange on a schedule and
+// aggregates them into a single portfolio snapshot keyed by currency, valued
+// in a fiat currency. Each exchange's last fetched balances are cached and kept
+// when a later fetch fails, the snapshot reports when each exchange was last
+// fetched and whether its balances are stale, so strategies can decide how far
+// to trust an allocation made from them
+package balances
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// DefaultRefreshInterval is the interval balances are fetched at
+const DefaultRefreshInterval = time.Minute
+
+// staleIntervals is the number of refresh intervals balances are kept before
+// they are reported stale when no maximum age is set
+const staleIntervals = 3
+
+var (
+	errNoPriceFunc   = errors.New("no price function supplied")
+	errNoFiat        = errors.New("no fiat currency supplied")
+	errNotRefreshed  = errors.New("balances not yet fetched")
+	errNoPrice       = errors.New("no price found")
+	errUnknownHolder = errors.New("currency not held")
+)
+
+// PriceFunc returns the value of one unit of a currency held on an exchange in
+// the fiat currency
+type PriceFunc func(exchangeName string, c currency.Code) (float64, error)
+
+// Holding holds an exchange's balance of a currency across its accounts
+type Holding struct {
+	Exchange  string  `json:"exchange"`
+	Total     float64 `json:"total"`
+	Hold      float64 `json:"hold"`
+	Available float64 `json:"available"`
+	Price     float64 `json:"price"`
+	Value     float64 `json:"value"`
+	Stale     bool    `json:"stale,omitempty"`
+}
+
+// Balance holds the portfolio's total of a currency
+type Balance struct {
+	Currency  currency.Code `json:"currency"`
+	Total     float64       `json:"total"`
+	Hold      float64       `json:"hold"`
+	Available float64       `json:"available"`
+	// Price is the average value of one unit across the exchanges priced
+	Price float64 `json:"price"`
+	Value float64 `json:"value"`
+	// Priced is unset when no exchange holding the currency could value it,
+	// unpriced holdings are excluded from the values
+	Priced    bool      `json:"priced"`
+	Exchanges []Holding `json:"exchanges"`
+}
+
+// Source reports when an exchange's balances were last fetched
+type Source struct {
+	Exchange string    `json:"exchange"`
+	Updated  time.Time `json:"updated"`
+	Checked  time.Time `json:"checked"`
+	Stale    bool      `json:"stale"`
+	Error    string    `json:"error,omitempty"`
+}
+
+// Snapshot is the portfolio's balances aggregated across every exchange
+type Snapshot struct {
+	Fiat     currency.Code `json:"fiat"`
+	Balances []Balance     `json:"balances"`
+	Value    float64       `json:"value"`
+	Sources  []Source      `json:"sources"`
+	// Updated is when the oldest exchange's balances were fetched, Stale is
+	// set when any exchange's balances are older than the maximum age
+	Updated time.Time `json:"updated"`
+	Stale   bool      `json:"stale"`
+	Time    time.Time `json:"time"`
+}
+
+// Get returns the portfolio's balance of a currency
+func (s *Snapshot) Get(c currency.Code) (Balance, error) {
+	for i := range s.Balances {
+		if s.Balances[i].Currency.Match(c) {
+			return s.Balances[i], nil
+		}
+	}
+	return Balance{}, errUnknownHolder
+}
+
+// entry caches an exchange's last fetched balances
+type entry struct {
+	holdings map[currency.Code]*Holding
+	updated  time.Time
+	checked  time.Time
+	err      string
+}
+
+// Aggregator caches the balances of exchanges and aggregates them
+type Aggregator struct {
+	exchanges []exchange.IBotExchange
+	fiat      currency.Code
+	price     PriceFunc
+	maxAge    time.Duration
+	interval  time.Duration
+	entries   map[string]*entry
+	now       func() time.Time
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+	mtx       sync.Mutex
+}
+
+// New returns an aggregator of the exchanges' balances valued in the fiat
+// currency. Balances are stale once older than the maximum age, zero reports
+// them stale after three refresh intervals
+func New(exchanges []exchange.IBotExchange, fiat currency.Code, price PriceFunc, maxAge time.Duration) (*Aggregator, error) {
+	if price == nil {
+		return nil, errNoPriceFunc
+	}
+	if fiat.IsEmpty() {
+		return nil, errNoFiat
+	}
+	return &Aggregator{
+		exchanges: exchanges,
+		fiat:      fiat,
+		price:     price,
+		maxAge:    maxAge,
+		entries:   make(map[string]*entry),
+		now:       time.Now,
+	}, nil
+}
+
+// Refresh fetches the balances of every exchange, an exchange which fails
+// keeps its last fetched balances
+func (a *Aggregator) Refresh() {
+	for _, e := range a.exchanges {
+		a.refresh(e)
+	}
+}
+
+func (a *Aggregator) refresh(e exchange.IBotExchange) {
+	name := e.GetName()
+	info, err := e.GetAccountInfo()
+	now := a.now()
+
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	c, ok := a.entries[name]
+	if !ok {
+		c = &entry{}
+		a.entries[name] = c
+	}
+	c.checked = now
+	if err != nil {
+		log.Errorf("Balances failed to fetch %s balances: %s", name, err)
+		c.err = err.Error()
+		return
+	}
+
+	holdings := make(map[currency.Code]*Holding)
+	for i := range info.Accounts {
+		for _, b := range info.Accounts[i].Currencies {
+			if b.TotalValue == 0 && b.Hold == 0 {
+				continue
+			}
+			// Codes differing only by case are the same currency
+			code := b.CurrencyName.Upper()
+			h, ok := holdings[code]
+			if !ok {
+				h = &Holding{Exchange: name}
+				holdings[code] = h
+			}
+			h.Total += b.TotalValue
+			h.Hold += b.Hold
+		}
+	}
+	for code, h := range holdings {
+		h.Available = h.Total - h.Hold
+		if h.Available < 0 {
+			h.Available = 0
+		}
+		p, err := a.price(name, code)
+		if err != nil || p <= 0 {
+			continue
+		}
+		h.Price = p
+		h.Value = p * h.Total
+	}
+	c.holdings = holdings
+	c.updated = now
+	c.err = ""
+}
+
+// staleAfter returns the age balances are stale after
+func (a *Aggregator) staleAfter() time.Duration {
+	if a.maxAge > 0 {
+		return a.maxAge
+	}
+	interval := a.interval
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	return interval * staleIntervals
+}
+
+// GetSnapshot aggregates the cached balances of every exchange, staleness is
+// judged at the time of the call
+func (a *Aggregator) GetSnapshot() (Snapshot, error) {
+	a.mtx.Lock()
+	defer a.mtx.Unlock()
+	if len(a.entries) == 0 {
+		return Snapshot{}, errNotRefreshed
+	}
+
+	now := a.now()
+	maxAge := a.staleAfter()
+	s := Snapshot{Fiat: a.fiat, Time: now}
+	balances := make(map[currency.Code]*Balance)
+	first := true
+	for name, c := range a.entries {
+		stale := c.updated.IsZero() || now.Sub(c.updated) > maxAge
+		s.Sources = append(s.Sources, Source{
+			Exchange: name,
+			Updated:  c.updated,
+			Checked:  c.checked,
+			Stale:    stale,
+			Error:    c.err,
+		})
+		if stale {
+			s.Stale = true
+		}
+		if first || c.updated.Before(s.Updated) {
+			s.Updated = c.updated
+			first = false
+		}
+
+		for code, h := range c.holdings {
+			b, ok := balances[code]
+			if !ok {
+				b = &Balance{Currency: code}
+				balances[code] = b
+			}
+			holding := *h
+			holding.Stale = stale
+			b.Exchanges = append(b.Exchanges, holding)
+			b.Total += h.Total
+			b.Hold += h.Hold
+			b.Available += h.Available
+			if h.Price > 0 {
+				b.Priced = true
+				b.Value += h.Value
+			}
+		}
+	}
+
+	for _, b := range balances {
+		var priced float64
+		for i := range b.Exchanges {
+			if b.Exchanges[i].Price > 0 {
+				priced += b.Exchanges[i].Total
+			}
+		}
+		if priced > 0 {
+			b.Price = b.Value / priced
+		}
+		sort.Slice(b.Exchanges, func(i, j int) bool {
+			return b.Exchanges[i].Exchange < b.Exchanges[j].Exchange
+		})
+		s.Value += b.Value
+		s.Balances = append(s.Balances, *b)
+	}
+	sort.Slice(s.Balances, func(i, j int) bool {
+		return s.Balances[i].Currency.String() < s.Balances[j].Currency.String()
+	})
+	sort.Slice(s.Sources, func(i, j int) bool {
+		return s.Sources[i].Exchange < s.Sources[j].Exchange
+	})
+	return s, nil
+}
+
+// FiatValue returns the value of one unit of a currency in the fiat currency.
+// Fiat currencies and stablecoins are converted with the currency storage's FX
+// rates, other currencies are priced by the exchange's ticker of a pair quoted
+// in fiat, preferring the fiat currency itself
+func FiatValue(e exchange.IBotExchange, c, fiat currency.Code) (float64, error) {
+	c = pegged(c)
+	if c.Match(fiat) {
+		return 1, nil
+	}
+	if c.IsFiatCurrency() {
+		return currency.ConvertCurrency(1, c, fiat)
+	}
+
+	var candidates currency.Pairs
+	for _, p := range e.GetEnabledCurrencies() {
+		if !p.Base.Match(c) {
+			continue
+		}
+		if p.Quote.Match(fiat) {
+			candidates = append(currency.Pairs{p}, candidates...)
+			continue
+		}
+		if pegged(p.Quote).IsFiatCurrency() {
+			candidates = append(candidates, p)
+		}
+	}
+	for _, p := range candidates {
+		t, err := ticker.GetTicker(e.GetName(), p, ticker.Spot)
+		if err != nil || t.Last <= 0 {
+			continue
+		}
+		rate, err := FiatValue(e, p.Quote, fiat)
+		if err != nil {
+			continue
+		}
+		return t.Last * rate, nil
+	}
+	return 0, errNoPrice
+}
+
+// pegged returns the currency a stablecoin is pegged to, otherwise the code
+func pegged(c currency.Code) currency.Code {
+	if !c.IsStablecoin() {
+		return c
+	}
+	if peg := c.GetPeggedCurrency(); !peg.IsEmpty() {
+		return peg
+	}
+	return c
+}
+
+// Start fetches balances at the interval until stopped
+func (a *Aggregator) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	a.mtx.Lock()
+	if a.shutdown != nil {
+		a.mtx.Unlock()
+		return
+	}
+	a.shutdown = make(chan struct{})
+	a.interval = interval
+	shutdown := a.shutdown
+	a.mtx.Unlock()
+
+	a.wg.Add(1)
+	go func() {
+		defer a.wg.Done()
+		a.Refresh()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				a.Refresh()
+			}
+		}
+	}()
+}
+
+// Stop stops fetching balances
+func (a *Aggregator) Stop() {
+	a.mtx.Lock()
+	if a.shutdown == nil {
+		a.mtx.Unlock()
+		return
+	}
+	close(a.shutdown)
+	a.shutdown = nil
+	a.mtx.Unlock()
+	a.wg.Wait()
+}
@@ -0,0 +1,144 @@
+package balances
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	name     string
+	accounts []exchange.Account
+	err      error
+}
+
+func (t *testExchange) GetName() string { return t.name }
+
+func (t *testExchange) GetAccountInfo() (exchange.AccountInfo, error) {
+	return exchange.AccountInfo{Exchange: t.name, Accounts: t.accounts}, t.err
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil, currency.USD, nil, 0); err != errNoPriceFunc {
+		t.Error("Test Failed - New() expected no price function error", err)
+	}
+	price := func(string, currency.Code) (float64, error) { return 1, nil }
+	if _, err := New(nil, currency.Code{}, price, 0); err != errNoFiat {
+		t.Error("Test Failed - New() expected no fiat error", err)
+	}
+	a, err := New(nil, currency.USD, price, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = a.GetSnapshot(); err != errNotRefreshed {
+		t.Error("Test Failed - GetSnapshot() expected not refreshed error", err)
+	}
+}
+
+func TestSnapshot(t *testing.T) {
+	binance := &testExchange{name: "Binance", accounts: []exchange.Account{
+		{Currencies: []exchange.AccountCurrencyInfo{
+			{CurrencyName: currency.BTC, TotalValue: 1, Hold: 0.25},
+			{CurrencyName: currency.XRP, TotalValue: 100},
+		}},
+	}}
+	kraken := &testExchange{name: "Kraken", accounts: []exchange.Account{
+		{ID: "spot", Currencies: []exchange.AccountCurrencyInfo{
+			{CurrencyName: currency.BTC.Lower(), TotalValue: 2},
+			{CurrencyName: currency.USD, TotalValue: 500},
+			{CurrencyName: currency.LTC},
+		}},
+		{ID: "margin", Currencies: []exchange.AccountCurrencyInfo{
+			{CurrencyName: currency.USD, TotalValue: 500, Hold: 100},
+		}},
+	}}
+	prices := map[string]float64{"Binance BTC": 10000, "Kraken BTC": 10300, "Kraken USD": 1}
+	a, err := New([]exchange.IBotExchange{binance, kraken}, currency.USD,
+		func(exchName string, c currency.Code) (float64, error) {
+			if p, ok := prices[exchName+" "+c.String()]; ok {
+				return p, nil
+			}
+			return 0, errNoPrice
+		}, time.Minute)
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	a.now = func() time.Time { return start }
+	a.Refresh()
+
+	s, err := a.GetSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(s.Balances) != 3 || s.Stale || len(s.Sources) != 2 || !s.Updated.Equal(start) {
+		t.Fatalf("Test Failed - GetSnapshot() unexpected snapshot %+v", s)
+	}
+	btc, err := s.Get(currency.BTC)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if btc.Total != 3 || btc.Available != 2.75 || btc.Value != 30600 || btc.Price != 10200 || len(btc.Exchanges) != 2 {
+		t.Errorf("Test Failed - GetSnapshot() unexpected BTC balance %+v", btc)
+	}
+	usd, err := s.Get(currency.USD)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if usd.Total != 1000 || usd.Hold != 100 || usd.Value != 1000 {
+		t.Errorf("Test Failed - GetSnapshot() expected accounts combined %+v", usd)
+	}
+	xrp, err := s.Get(currency.XRP)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if xrp.Priced || xrp.Value != 0 {
+		t.Errorf("Test Failed - GetSnapshot() expected XRP unpriced %+v", xrp)
+	}
+	if s.Value != 31600 {
+		t.Errorf("Test Failed - GetSnapshot() expected value 31600, received %v", s.Value)
+	}
+	if _, err = s.Get(currency.LTC); err != errUnknownHolder {
+		t.Error("Test Failed - Get() expected empty balances excluded", err)
+	}
+
+	// Kraken fails, its cached balances are kept and turn stale
+	kraken.err = errors.New("unavailable")
+	a.now = func() time.Time { return start.Add(time.Minute * 2) }
+	a.Refresh()
+	s, err = a.GetSnapshot()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Stale || !s.Updated.Equal(start) || s.Value != 31600 {
+		t.Fatalf("Test Failed - GetSnapshot() expected stale cached balances %+v", s)
+	}
+	if k := s.Sources[1]; k.Exchange != "Kraken" || !k.Stale || k.Error == "" || !k.Checked.After(k.Updated) {
+		t.Errorf("Test Failed - GetSnapshot() unexpected source %+v", k)
+	}
+	if b := s.Sources[0]; b.Stale || b.Error != "" {
+		t.Errorf("Test Failed - GetSnapshot() expected fresh source %+v", b)
+	}
+	if btc, _ = s.Get(currency.BTC); btc.Exchanges[0].Stale || !btc.Exchanges[1].Stale {
+		t.Errorf("Test Failed - GetSnapshot() expected Kraken's holding stale %+v", btc.Exchanges)
+	}
+}
+
+func TestStaleAfter(t *testing.T) {
+	a := &Aggregator{}
+	if d := a.staleAfter(); d != DefaultRefreshInterval*staleIntervals {
+		t.Errorf("Test Failed - staleAfter() expected default, received %v", d)
+	}
+	a.interval = time.Second
+	if d := a.staleAfter(); d != time.Second*staleIntervals {
+		t.Errorf("Test Failed - staleAfter() expected three intervals, received %v", d)
+	}
+	a.maxAge = time.Hour
+	if d := a.staleAfter(); d != time.Hour {
+		t.Errorf("Test Failed - staleAfter() expected the maximum age, received %v", d)
+	}
+}
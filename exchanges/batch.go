@@ -0,0 +1,132 @@
+package exchange
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// DefaultBatchConcurrency is the number of orders submitted concurrently for
+// exchanges without a native bulk order endpoint. Each request still passes
+// through the exchange's rate limiter
+const DefaultBatchConcurrency = 5
+
+var errBatchOrderMissing = errors.New("no result returned for order in batch")
+
+// OrderSubmission defines a single order within a batch
+type OrderSubmission struct {
+	Pair      currency.Pair
+	Side      OrderSide
+	OrderType OrderType
+	Amount    float64
+	Price     float64
+	ClientID  string
+	// AllowDuplicate lets the order repeat one submitted within the duplicate
+	// order window when checked by Native
+	AllowDuplicate bool
+}
+
+// BatchOrderResult holds the outcome of a single order within a batch
+type BatchOrderResult struct {
+	SubmitOrderResponse
+	Err error
+}
+
+// BatchOrderSubmitter is implemented by exchanges with a native bulk order
+// endpoint
+type BatchOrderSubmitter interface {
+	// MaxBatchOrders returns the maximum number of orders accepted per request
+	MaxBatchOrders() int
+	// SubmitBatchOrders submits up to MaxBatchOrders orders, returning a
+	// result for each order in the same order they were supplied
+	SubmitBatchOrders(orders []OrderSubmission) ([]BatchOrderResult, error)
+}
+
+// SubmitOrders submits a set of orders using the exchange's native bulk order
+// endpoint where available, split into chunks of the exchange's maximum batch
+// size. Orders are only batched once they pass the checks of every wrapper
+// guarding the exchange, see Native. Other exchanges have their orders
+// submitted concurrently. A result is returned for every order in the same
+// order they were supplied
+func SubmitOrders(e IBotExchange, orders []OrderSubmission) []BatchOrderResult {
+	results := make([]BatchOrderResult, len(orders))
+	if len(orders) == 0 {
+		return results
+	}
+
+	if b, ok := Underlying(e).(BatchOrderSubmitter); ok && b.MaxBatchOrders() > 0 {
+		if batch, index, ok := checkBatch(e, orders, results); ok {
+			submitBatch(b, batch, index, results)
+			return results
+		}
+	}
+
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, DefaultBatchConcurrency)
+	for i := range orders {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			resp, err := e.SubmitOrder(orders[i].Pair,
+				orders[i].Side,
+				orders[i].OrderType,
+				orders[i].Amount,
+				orders[i].Price,
+				orders[i].ClientID)
+			results[i] = BatchOrderResult{SubmitOrderResponse: resp, Err: err}
+		}(i)
+	}
+	wg.Wait()
+	return results
+}
+
+// checkBatch checks each order against the wrappers guarding the exchange,
+// returning the orders which passed with their index in the batch. Rejected
+// orders have their error stored in the results. False is returned when the
+// orders must be submitted through the wrapped exchange
+func checkBatch(e IBotExchange, orders []OrderSubmission, results []BatchOrderResult) ([]OrderSubmission, []int, bool) {
+	var batch []OrderSubmission
+	var index []int
+	for i := range orders {
+		o := orders[i]
+		native, err := Native(e, &o)
+		if err != nil {
+			results[i].Err = err
+			continue
+		}
+		if native == nil {
+			return nil, nil, false
+		}
+		batch = append(batch, o)
+		index = append(index, i)
+	}
+	return batch, index, true
+}
+
+// submitBatch submits the orders natively in chunks of the exchange's maximum
+// batch size, storing each result at the order's index
+func submitBatch(b BatchOrderSubmitter, orders []OrderSubmission, index []int, results []BatchOrderResult) {
+	size := b.MaxBatchOrders()
+	for start := 0; start < len(orders); start += size {
+		end := start + size
+		if end > len(orders) {
+			end = len(orders)
+		}
+		chunk, err := b.SubmitBatchOrders(orders[start:end])
+		for i := start; i < end; i++ {
+			switch {
+			case i-start < len(chunk):
+				results[index[i]] = chunk[i-start]
+			case err != nil:
+				results[index[i]].Err = err
+			default:
+				results[index[i]].Err = errBatchOrderMissing
+			}
+		}
+	}
+}
@@ -0,0 +1,141 @@
+package exchange
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testSingleOrderExchange struct {
+	IBotExchange
+	mtx   sync.Mutex
+	count int
+}
+
+func (t *testSingleOrderExchange) SubmitOrder(_ currency.Pair, _ OrderSide, _ OrderType, amount, _ float64, _ string) (SubmitOrderResponse, error) {
+	t.mtx.Lock()
+	t.count++
+	t.mtx.Unlock()
+	if amount < 0 {
+		return SubmitOrderResponse{}, errors.New("invalid amount")
+	}
+	return SubmitOrderResponse{
+		IsOrderPlaced: true,
+		OrderID:       strconv.FormatFloat(amount, 'f', -1, 64),
+	}, nil
+}
+
+type testBulkOrderExchange struct {
+	testSingleOrderExchange
+	batches []int
+	failAt  int
+}
+
+func (t *testBulkOrderExchange) MaxBatchOrders() int { return 3 }
+
+func (t *testBulkOrderExchange) SubmitBatchOrders(orders []OrderSubmission) ([]BatchOrderResult, error) {
+	t.batches = append(t.batches, len(orders))
+	if len(t.batches) == t.failAt {
+		return nil, errors.New("batch rejected")
+	}
+	results := make([]BatchOrderResult, len(orders))
+	for i := range orders {
+		results[i].IsOrderPlaced = true
+		results[i].OrderID = strconv.FormatFloat(orders[i].Amount, 'f', -1, 64)
+	}
+	return results, nil
+}
+
+func testOrders(n int) []OrderSubmission {
+	orders := make([]OrderSubmission, n)
+	for i := range orders {
+		orders[i] = OrderSubmission{
+			Pair:      currency.NewPair(currency.BTC, currency.USD),
+			Side:      BuyOrderSide,
+			OrderType: LimitOrderType,
+			Amount:    float64(i + 1),
+			Price:     1,
+		}
+	}
+	return orders
+}
+
+func TestSubmitOrdersConcurrent(t *testing.T) {
+	e := &testSingleOrderExchange{}
+	orders := testOrders(12)
+	orders[4].Amount = -1
+
+	results := SubmitOrders(e, orders)
+	if len(results) != len(orders) || e.count != len(orders) {
+		t.Fatalf("Test Failed - SubmitOrders() expected %d submissions, received %d",
+			len(orders), e.count)
+	}
+	for i := range results {
+		if i == 4 {
+			if results[i].Err == nil {
+				t.Error("Test Failed - SubmitOrders() expected error for invalid order")
+			}
+			continue
+		}
+		if results[i].Err != nil || results[i].OrderID != strconv.Itoa(i+1) {
+			t.Errorf("Test Failed - SubmitOrders() result %d out of order or failed: %+v",
+				i, results[i])
+		}
+	}
+}
+
+func TestSubmitOrdersBulk(t *testing.T) {
+	e := &testBulkOrderExchange{failAt: 2}
+	results := SubmitOrders(e, testOrders(8))
+	if len(e.batches) != 3 ||
+		e.batches[0] != 3 ||
+		e.batches[1] != 3 ||
+		e.batches[2] != 2 {
+		t.Fatalf("Test Failed - SubmitOrders() unexpected chunking %v", e.batches)
+	}
+	if e.count != 0 {
+		t.Error("Test Failed - SubmitOrders() expected native bulk submission only")
+	}
+	for i := range results {
+		failed := i >= 3 && i < 6
+		if failed != (results[i].Err != nil) {
+			t.Errorf("Test Failed - SubmitOrders() result %d unexpected error state %v",
+				i, results[i].Err)
+		}
+		if !failed && results[i].OrderID != strconv.Itoa(i+1) {
+			t.Errorf("Test Failed - SubmitOrders() result %d out of order", i)
+		}
+	}
+
+	if len(SubmitOrders(e, nil)) != 0 {
+		t.Error("Test Failed - SubmitOrders() expected no results")
+	}
+}
+
+func TestSubmitOrdersWrapped(t *testing.T) {
+	e := &testBulkOrderExchange{}
+	orders := testOrders(4)
+	orders[1].Amount = 0.5
+
+	// testChecker halves each amount, rejecting amounts below one
+	results := SubmitOrders(&testChecker{IBotExchange: e}, orders)
+	if len(e.batches) != 1 || e.batches[0] != 3 || e.count != 0 {
+		t.Fatalf("Test Failed - SubmitOrders() expected checked orders batched natively %v", e.batches)
+	}
+	if results[1].Err == nil || results[0].OrderID != "0.5" || results[3].OrderID != "2" {
+		t.Errorf("Test Failed - SubmitOrders() unexpected results %+v", results)
+	}
+
+	results = SubmitOrders(NewReadOnly(e), testOrders(4))
+	if len(e.batches) != 1 {
+		t.Fatal("Test Failed - SubmitOrders() read only orders must not be batched natively")
+	}
+	for i := range results {
+		if results[i].Err != ErrReadOnlyExchange {
+			t.Errorf("Test Failed - SubmitOrders() expected read only error %+v", results[i])
+		}
+	}
+}
@@ -8,6 +8,8 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
@@ -28,8 +30,17 @@ type Binance struct {
 	// Valid string list that is required by the exchange
 	validLimits    []int
 	validIntervals []TimeInterval
+
+	// symbols caches the symbol filters of the exchange information
+	symbols        map[string]SymbolInfo
+	symbolsUpdated time.Time
+	symbolsMtx     sync.Mutex
 }
 
+// symbolsCacheDuration is how long the symbol filters of the exchange
+// information are cached for
+const symbolsCacheDuration = time.Hour
+
 const (
 	apiURL = "https://api.binance.com"
 
@@ -64,6 +75,7 @@ const (
 	dustLog           = "/wapi/v3/userAssetDribbletLog.html"
 	tradeFee          = "/wapi/v3/tradeFee.html"
 	assetDetail       = "/wapi/v3/assetDetail.html"
+	dustTransfer      = "/sapi/v1/asset/dust"
 
 	// binance authenticated and unauthenticated limit rates
 	// to-do
@@ -191,6 +203,73 @@ func (b *Binance) GetExchangeInfo() (ExchangeInfo, error) {
 	return resp, b.SendHTTPRequest(path, &resp)
 }
 
+// getSymbolInfo returns the filters of a symbol, downloading the exchange
+// information once the cached copy expires
+func (b *Binance) getSymbolInfo(symbol string) (SymbolInfo, error) {
+	b.symbolsMtx.Lock()
+	defer b.symbolsMtx.Unlock()
+	if b.symbols == nil || time.Since(b.symbolsUpdated) > symbolsCacheDuration {
+		info, err := b.GetExchangeInfo()
+		if err != nil {
+			return SymbolInfo{}, err
+		}
+		b.symbols = make(map[string]SymbolInfo, len(info.Symbols))
+		for x := range info.Symbols {
+			b.symbols[info.Symbols[x].Symbol] = info.Symbols[x]
+		}
+		b.symbolsUpdated = time.Now()
+	}
+
+	s, ok := b.symbols[symbol]
+	if !ok {
+		return SymbolInfo{}, fmt.Errorf("%s symbol %s not found", b.Name, symbol)
+	}
+	return s, nil
+}
+
+// GetSymbolMinNotional returns the minimum order notional for a symbol
+func (b *Binance) GetSymbolMinNotional(symbol string) (float64, error) {
+	s, err := b.getSymbolInfo(symbol)
+	if err != nil {
+		return 0, err
+	}
+
+	for y := range s.Filters {
+		if s.Filters[y].FilterType == "MIN_NOTIONAL" {
+			return s.Filters[y].MinNotional, nil
+		}
+	}
+	return 0, fmt.Errorf("%s min notional filter not found for %s", b.Name, symbol)
+}
+
+// GetSymbolTradingRules returns the price and quantity increments and minimums
+// of a symbol
+func (b *Binance) GetSymbolTradingRules(symbol string) (exchange.TradingRules, error) {
+	s, err := b.getSymbolInfo(symbol)
+	if err != nil {
+		return exchange.TradingRules{}, err
+	}
+	return s.TradingRules(), nil
+}
+
+// TradingRules returns the symbol's price filter tick size, lot size step and
+// minimums
+func (s *SymbolInfo) TradingRules() exchange.TradingRules {
+	var rules exchange.TradingRules
+	for x := range s.Filters {
+		switch s.Filters[x].FilterType {
+		case "PRICE_FILTER":
+			rules.PriceStep = s.Filters[x].TickSize
+		case "LOT_SIZE":
+			rules.AmountStep = s.Filters[x].StepSize
+			rules.MinAmount = s.Filters[x].MinQty
+		case "MIN_NOTIONAL":
+			rules.MinNotional = s.Filters[x].MinNotional
+		}
+	}
+	return rules
+}
+
 // GetOrderBook returns full orderbook information
 //
 // OrderBookDataRequestParams contains the following members
@@ -613,11 +692,26 @@ func (b *Binance) SendHTTPRequest(path string, result interface{}) error {
 	return b.SendPayload(http.MethodGet, path, nil, nil, result, false, false, b.Verbose, b.HTTPDebugging)
 }
 
+// mutating returns whether the authenticated endpoint changes exchange state.
+// Orders are placed, queried and cancelled through the same path so endpoints
+// are matched by method and path
+func (b *Binance) mutating(method, path string) bool {
+	switch method + " " + strings.TrimPrefix(path, b.APIUrl) {
+	case http.MethodPost + " " + newOrder, http.MethodDelete + " " + cancelOrder,
+		http.MethodPost + " " + withdraw, http.MethodPost + " " + dustTransfer:
+		return true
+	}
+	return false
+}
+
 // SendAuthHTTPRequest sends an authenticated HTTP request
 func (b *Binance) SendAuthHTTPRequest(method, path string, params url.Values, result interface{}) error {
 	if !b.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, b.Name)
 	}
+	if b.DryRunRequest(b.mutating(method, path), path, params) {
+		return nil
+	}
 
 	if params == nil {
 		params = url.Values{}
@@ -809,3 +903,19 @@ func (b *Binance) GetDepositAddressForCurrency(currency string) (string, error)
 	return resp.Address,
 		b.SendAuthHTTPRequest(http.MethodGet, path, params, &resp)
 }
+
+// DustTransfer converts small balances of the supplied assets to BNB
+func (b *Binance) DustTransfer(assets []string) (DustTransferResponse, error) {
+	var resp DustTransferResponse
+	if len(assets) == 0 {
+		return resp, errors.New("no assets supplied for dust transfer")
+	}
+
+	path := fmt.Sprintf("%s%s", b.APIUrl, dustTransfer)
+	params := url.Values{}
+	for x := range assets {
+		params.Add("asset", assets[x])
+	}
+
+	return resp, b.SendAuthHTTPRequest(http.MethodPost, path, params, &resp)
+}
@@ -1,12 +1,15 @@
 package binance
 
 import (
+	"encoding/json"
 	"testing"
+	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 )
 
 // Please supply your own keys here for due diligence testing
@@ -97,6 +100,18 @@ func TestGetSpotKline(t *testing.T) {
 	}
 }
 
+func TestGetHistoricCandles(t *testing.T) {
+	t.Parallel()
+	_, err := b.GetHistoricCandles(currency.NewPairWithDelimiter("BTC", "USDT", "-"), "SPOT", kline.FiveMin, 24)
+	if err != nil {
+		t.Error("Test Failed - Binance GetHistoricCandles() error", err)
+	}
+	_, err = b.GetHistoricCandles(currency.NewPairWithDelimiter("BTC", "USDT", "-"), "SPOT", kline.Interval(time.Minute*7), 24)
+	if err != exchange.ErrCandleIntervalUnsupported {
+		t.Error("Test Failed - Binance GetHistoricCandles() expected unsupported interval error", err)
+	}
+}
+
 func TestGetAveragePrice(t *testing.T) {
 	t.Parallel()
 	_, err := b.GetAveragePrice("BTCUSDT")
@@ -556,3 +571,93 @@ func TestGetDepositAddress(t *testing.T) {
 		}
 	}
 }
+
+func TestGetSymbolMinNotional(t *testing.T) {
+	t.Parallel()
+	_, err := b.GetSymbolMinNotional("BTCUSDT")
+	if err != nil {
+		t.Error("Test Failed - Binance GetSymbolMinNotional() error", err)
+	}
+}
+
+func TestGetSymbolInfoCached(t *testing.T) {
+	t.Parallel()
+	c := Binance{
+		symbols: map[string]SymbolInfo{
+			"LTCBNB": {Symbol: "LTCBNB", Filters: []SymbolFilter{{FilterType: "MIN_NOTIONAL", MinNotional: 1}}},
+		},
+		symbolsUpdated: time.Now(),
+	}
+	v, err := c.GetSymbolMinNotional("LTCBNB")
+	if err != nil || v != 1 {
+		t.Error("Test Failed - Binance GetSymbolMinNotional() expected cached filters", v, err)
+	}
+	if _, err = c.GetSymbolMinNotional("XRPBNB"); err == nil {
+		t.Error("Test Failed - Binance GetSymbolMinNotional() expected symbol not found error")
+	}
+}
+
+func TestDustTransfer(t *testing.T) {
+	t.Parallel()
+	_, err := b.DustTransfer(nil)
+	if err == nil {
+		t.Error("Test Failed - Binance DustTransfer() expected error when no assets supplied")
+	}
+
+	if areTestAPIKeysSet() && !canManipulateRealOrders {
+		t.Skip("API keys set, canManipulateRealOrders false, skipping test")
+	}
+
+	_, err = b.DustTransfer([]string{"ETH"})
+	if !areTestAPIKeysSet() && err == nil {
+		t.Error("Test Failed - Binance DustTransfer() expecting an error when no keys are set")
+	}
+}
+
+func TestSymbolTradingRules(t *testing.T) {
+	t.Parallel()
+	data := []byte(`{"symbol":"BTCUSDT","status":"TRADING","baseAsset":"BTC","quoteAsset":"USDT","filters":[` +
+		`{"filterType":"PRICE_FILTER","minPrice":"0.01000000","maxPrice":"1000000.00000000","tickSize":"0.01000000"},` +
+		`{"filterType":"PERCENT_PRICE","multiplierUp":"5","multiplierDown":"0.2","avgPriceMins":5},` +
+		`{"filterType":"LOT_SIZE","minQty":"0.00000100","maxQty":"9000.00000000","stepSize":"0.00000100"},` +
+		`{"filterType":"MIN_NOTIONAL","minNotional":"10.00000000","applyToMarket":true,"avgPriceMins":5}]}`)
+	var s SymbolInfo
+	err := json.Unmarshal(data, &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rules := s.TradingRules()
+	if rules.PriceStep != 0.01 || rules.AmountStep != 0.000001 ||
+		rules.MinAmount != 0.000001 || rules.MinNotional != 10 {
+		t.Fatalf("Test Failed - Binance TradingRules() unexpected rules %+v", rules)
+	}
+
+	// quantities are truncated to the lot size and buys never rounded above
+	// the requested price
+	amount, price, err := rules.Apply(exchange.BuyOrderSide, exchange.LimitOrderType, 0.0012345678, 9876.549)
+	if err != nil {
+		t.Fatal("Test Failed - Binance Apply() error", err)
+	}
+	if amount != 0.001234 || price != 9876.54 {
+		t.Errorf("Test Failed - Binance Apply() buy got %v @ %v", amount, price)
+	}
+
+	amount, price, err = rules.Apply(exchange.SellOrderSide, exchange.LimitOrderType, 0.0012345678, 9876.541)
+	if err != nil {
+		t.Fatal("Test Failed - Binance Apply() error", err)
+	}
+	if amount != 0.001234 || price != 9876.55 {
+		t.Errorf("Test Failed - Binance Apply() sell got %v @ %v", amount, price)
+	}
+
+	// truncating the quantity can take an order below the minimum notional
+	_, _, err = rules.Apply(exchange.BuyOrderSide, exchange.LimitOrderType, 0.0010009, 9990)
+	if err == nil {
+		t.Error("Test Failed - Binance Apply() expected error below minimum notional")
+	}
+	_, _, err = rules.Apply(exchange.BuyOrderSide, exchange.LimitOrderType, 0.0000009, 9990)
+	if err == nil {
+		t.Error("Test Failed - Binance Apply() expected error below minimum quantity")
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"encoding/json"
 
 	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 )
 
 // Response holds basic binance api response data
@@ -23,34 +24,41 @@ type ExchangeInfo struct {
 		Interval      string `json:"interval"`
 		Limit         int    `json:"limit"`
 	} `json:"rateLimits"`
-	ExchangeFilters interface{} `json:"exchangeFilters"`
-	Symbols         []struct {
-		Symbol             string   `json:"symbol"`
-		Status             string   `json:"status"`
-		BaseAsset          string   `json:"baseAsset"`
-		BaseAssetPrecision int      `json:"baseAssetPrecision"`
-		QuoteAsset         string   `json:"quoteAsset"`
-		QuotePrecision     int      `json:"quotePrecision"`
-		OrderTypes         []string `json:"orderTypes"`
-		IcebergAllowed     bool     `json:"icebergAllowed"`
-		Filters            []struct {
-			FilterType          string  `json:"filterType"`
-			MinPrice            float64 `json:"minPrice,string"`
-			MaxPrice            float64 `json:"maxPrice,string"`
-			TickSize            float64 `json:"tickSize,string"`
-			MultiplierUp        float64 `json:"multiplierUp,string"`
-			MultiplierDown      float64 `json:"multiplierDown,string"`
-			AvgPriceMins        int64   `json:"avgPriceMins"`
-			MinQty              float64 `json:"minQty,string"`
-			MaxQty              float64 `json:"maxQty,string"`
-			StepSize            float64 `json:"stepSize,string"`
-			MinNotional         float64 `json:"minNotional,string"`
-			ApplyToMarket       bool    `json:"applyToMarket"`
-			Limit               int64   `json:"limit"`
-			MaxNumAlgoOrders    int64   `json:"maxNumAlgoOrders"`
-			MaxNumIcebergOrders int64   `json:"maxNumIcebergOrders"`
-		} `json:"filters"`
-	} `json:"symbols"`
+	ExchangeFilters interface{}  `json:"exchangeFilters"`
+	Symbols         []SymbolInfo `json:"symbols"`
+}
+
+// SymbolInfo holds a symbol's trading status and order filters
+type SymbolInfo struct {
+	Symbol             string         `json:"symbol"`
+	Status             string         `json:"status"`
+	BaseAsset          string         `json:"baseAsset"`
+	BaseAssetPrecision int            `json:"baseAssetPrecision"`
+	QuoteAsset         string         `json:"quoteAsset"`
+	QuotePrecision     int            `json:"quotePrecision"`
+	OrderTypes         []string       `json:"orderTypes"`
+	IcebergAllowed     bool           `json:"icebergAllowed"`
+	Filters            []SymbolFilter `json:"filters"`
+}
+
+// SymbolFilter holds a symbol's order filter, only the fields of its filter
+// type are set
+type SymbolFilter struct {
+	FilterType          string  `json:"filterType"`
+	MinPrice            float64 `json:"minPrice,string"`
+	MaxPrice            float64 `json:"maxPrice,string"`
+	TickSize            float64 `json:"tickSize,string"`
+	MultiplierUp        float64 `json:"multiplierUp,string"`
+	MultiplierDown      float64 `json:"multiplierDown,string"`
+	AvgPriceMins        int64   `json:"avgPriceMins"`
+	MinQty              float64 `json:"minQty,string"`
+	MaxQty              float64 `json:"maxQty,string"`
+	StepSize            float64 `json:"stepSize,string"`
+	MinNotional         float64 `json:"minNotional,string"`
+	ApplyToMarket       bool    `json:"applyToMarket"`
+	Limit               int64   `json:"limit"`
+	MaxNumAlgoOrders    int64   `json:"maxNumAlgoOrders"`
+	MaxNumIcebergOrders int64   `json:"maxNumIcebergOrders"`
 }
 
 // OrderBookDataRequestParams represents Klines request data.
@@ -448,6 +456,24 @@ var (
 	TimeIntervalMonth          = TimeInterval("1M")
 )
 
+// binanceCandleIntervals maps candle intervals to their kline interval
+var binanceCandleIntervals = map[kline.Interval]TimeInterval{
+	kline.OneMin:     TimeIntervalMinute,
+	kline.ThreeMin:   TimeIntervalThreeMinutes,
+	kline.FiveMin:    TimeIntervalFiveMinutes,
+	kline.FifteenMin: TimeIntervalFifteenMinutes,
+	kline.ThirtyMin:  TimeIntervalThirtyMinutes,
+	kline.OneHour:    TimeIntervalHour,
+	kline.TwoHour:    TimeIntervalTwoHours,
+	kline.FourHour:   TimeIntervalFourHours,
+	kline.SixHour:    TimeIntervalSixHours,
+	kline.EightHour:  TimeIntervalEightHours,
+	kline.TwelveHour: TimeIntervalTwelveHours,
+	kline.OneDay:     TimeIntervalDay,
+	kline.ThreeDay:   TimeIntervalThreeDays,
+	kline.OneWeek:    TimeIntervalWeek,
+}
+
 // WithdrawalFees the large list of predefined withdrawal fees
 // Prone to change
 var WithdrawalFees = map[currency.Code]float64{
@@ -623,3 +649,17 @@ type WithdrawResponse struct {
 	Msg     string `json:"msg"`
 	ID      int64  `json:"id"`
 }
+
+// DustTransferResponse contains the result of converting small balances to BNB
+type DustTransferResponse struct {
+	TotalServiceCharge float64 `json:"totalServiceCharge,string"`
+	TotalTransfered    float64 `json:"totalTransfered,string"`
+	TransferResult     []struct {
+		Amount              float64 `json:"amount,string"`
+		FromAsset           string  `json:"fromAsset"`
+		OperateTime         int64   `json:"operateTime"`
+		ServiceChargeAmount float64 `json:"serviceChargeAmount,string"`
+		TransferID          int64   `json:"tranId"`
+		TransferedAmount    float64 `json:"transferedAmount,string"`
+	} `json:"transferResult"`
+}
@@ -259,6 +259,10 @@ func (b *Binance) WsHandleData() {
 				wsTicker.OpenPrice, _ = strconv.ParseFloat(t.OpenPrice, 64)
 				wsTicker.HighPrice, _ = strconv.ParseFloat(t.HighPrice, 64)
 				wsTicker.LowPrice, _ = strconv.ParseFloat(t.LowPrice, 64)
+				wsTicker.BidPrice, _ = strconv.ParseFloat(t.BestBidPrice, 64)
+				wsTicker.BidSize, _ = strconv.ParseFloat(t.BestBidQuantity, 64)
+				wsTicker.AskPrice, _ = strconv.ParseFloat(t.BestAskPrice, 64)
+				wsTicker.AskSize, _ = strconv.ParseFloat(t.BestAskQuantity, 64)
 
 				b.Websocket.DataHandler <- wsTicker
 
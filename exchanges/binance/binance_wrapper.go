@@ -11,6 +11,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
@@ -202,6 +203,61 @@ func (b *Binance) GetExchangeHistory(p currency.Pair, assetType string) ([]excha
 	return resp, common.ErrNotYetImplemented
 }
 
+// GetHistoricCandles returns the most recent candles at the interval
+func (b *Binance) GetHistoricCandles(p currency.Pair, _ string, interval kline.Interval, limit int) ([]exchange.Candle, error) {
+	i, ok := binanceCandleIntervals[interval]
+	if !ok {
+		return nil, exchange.ErrCandleIntervalUnsupported
+	}
+
+	klines, err := b.GetSpotKline(KlinesRequestParams{
+		Symbol:   exchange.FormatExchangeCurrency(b.Name, p).String(),
+		Interval: i,
+		Limit:    limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertCandles(klines), nil
+}
+
+// GetHistoricCandlesRange returns up to 500 candles at the interval opening
+// between start and end
+func (b *Binance) GetHistoricCandlesRange(p currency.Pair, _ string, interval kline.Interval, start, end time.Time) ([]exchange.Candle, error) {
+	i, ok := binanceCandleIntervals[interval]
+	if !ok {
+		return nil, exchange.ErrCandleIntervalUnsupported
+	}
+
+	klines, err := b.GetSpotKline(KlinesRequestParams{
+		Symbol:    exchange.FormatExchangeCurrency(b.Name, p).String(),
+		Interval:  i,
+		Limit:     500,
+		StartTime: start.UnixNano() / int64(time.Millisecond),
+		EndTime:   end.UnixNano()/int64(time.Millisecond) - 1,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertCandles(klines), nil
+}
+
+// convertCandles converts klines to candles
+func convertCandles(klines []CandleStick) []exchange.Candle {
+	candles := make([]exchange.Candle, len(klines))
+	for x := range klines {
+		candles[x] = exchange.Candle{
+			Time:   time.Unix(0, int64(klines[x].OpenTime)*int64(time.Millisecond)),
+			Open:   klines[x].Open,
+			High:   klines[x].High,
+			Low:    klines[x].Low,
+			Close:  klines[x].Close,
+			Volume: klines[x].Volume,
+		}
+	}
+	return candles
+}
+
 // SubmitOrder submits a new order
 func (b *Binance) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
@@ -436,3 +492,24 @@ func (b *Binance) GetSubscriptions() ([]wshandler.WebsocketChannelSubscription,
 func (b *Binance) AuthenticateWebsocket() error {
 	return common.ErrFunctionNotSupported
 }
+
+// GetMinNotional returns the minimum order notional for a currency pair
+func (b *Binance) GetMinNotional(p currency.Pair, assetType string) (float64, error) {
+	return b.GetSymbolMinNotional(exchange.FormatExchangeCurrency(b.Name, p).String())
+}
+
+// GetTradingRules returns the price and quantity increments and minimums of a
+// currency pair
+func (b *Binance) GetTradingRules(p currency.Pair) (exchange.TradingRules, error) {
+	return b.GetSymbolTradingRules(exchange.FormatExchangeCurrency(b.Name, p).String())
+}
+
+// ConvertDust converts small balances of the supplied currencies to BNB
+func (b *Binance) ConvertDust(codes []currency.Code) error {
+	assets := make([]string, len(codes))
+	for x := range codes {
+		assets[x] = codes[x].Upper().String()
+	}
+	_, err := b.DustTransfer(assets)
+	return err
+}
@@ -1025,6 +1025,19 @@ func (b *Bitfinex) SendHTTPRequest(path string, result interface{}, verbose bool
 	return b.SendPayload(http.MethodGet, path, nil, nil, result, false, false, verbose, b.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Every
+// endpoint is a POST so they are classified by name
+func mutating(path string) bool {
+	switch path {
+	case bitfinexOrderNew, bitfinexOrderNewMulti, bitfinexOrderCancel, bitfinexOrderCancelMulti,
+		bitfinexOrderCancelAll, bitfinexOrderCancelReplace, bitfinexClaimPosition,
+		bitfinexOfferNew, bitfinexOfferCancel, bitfinexMarginClose, bitfinexTransfer,
+		bitfinexWithdrawal:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an autheticated http request and json
 // unmarshals result to a supplied variable
 func (b *Bitfinex) SendAuthenticatedHTTPRequest(method, path string, params map[string]interface{}, result interface{}) error {
@@ -1032,6 +1045,9 @@ func (b *Bitfinex) SendAuthenticatedHTTPRequest(method, path string, params map[
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet,
 			b.Name)
 	}
+	if b.DryRunRequest(mutating(path), path, params) {
+		return nil
+	}
 
 	n := b.Requester.GetNonce(true)
 
@@ -137,6 +137,18 @@ func TestGetLendbook(t *testing.T) {
 	}
 }
 
+func TestGetBorrowRates(t *testing.T) {
+	t.Parallel()
+
+	rates, err := b.GetBorrowRates(currency.USD)
+	if err != nil {
+		t.Error("Testing Failed - GetBorrowRates() error: ", err)
+	}
+	if len(rates) != 1 || rates[0].Period != exchange.Year {
+		t.Error("Testing Failed - GetBorrowRates() expected an annual rate")
+	}
+}
+
 func TestGetOrderbook(t *testing.T) {
 	t.Parallel()
 
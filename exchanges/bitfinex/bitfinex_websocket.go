@@ -270,6 +270,11 @@ func (b *Bitfinex) WsDataHandler() {
 
 					case "ticker":
 						b.Websocket.DataHandler <- wshandler.TickerData{
+							Timestamp:  time.Now(),
+							BidPrice:   chanData[1].(float64),
+							BidSize:    chanData[2].(float64),
+							AskPrice:   chanData[3].(float64),
+							AskSize:    chanData[4].(float64),
 							Quantity:   chanData[8].(float64),
 							ClosePrice: chanData[7].(float64),
 							HighPrice:  chanData[9].(float64),
@@ -479,3 +479,31 @@ func (b *Bitfinex) GetSubscriptions() ([]wshandler.WebsocketChannelSubscription,
 func (b *Bitfinex) AuthenticateWebsocket() error {
 	return b.WsSendAuth()
 }
+
+// GetBorrowRates returns the lowest rate offered on the margin funding book
+// for the currency
+func (b *Bitfinex) GetBorrowRates(c currency.Code) ([]exchange.BorrowRate, error) {
+	book, err := b.GetLendbook(c.Upper().String(), url.Values{})
+	if err != nil {
+		return nil, err
+	}
+	if len(book.Asks) == 0 {
+		return nil, fmt.Errorf("%s no funding offers for %s", b.Name, c)
+	}
+
+	// Funding rates are quoted as a percentage per year
+	rate := exchange.BorrowRate{
+		Exchange:  b.Name,
+		Currency:  c,
+		Rate:      book.Asks[0].Rate / 100,
+		Period:    exchange.Year,
+		Timestamp: time.Now(),
+	}
+	for i := range book.Asks {
+		if book.Asks[i].Rate/100 < rate.Rate {
+			rate.Rate = book.Asks[i].Rate / 100
+		}
+		rate.Available += book.Asks[i].Amount
+	}
+	return []exchange.BorrowRate{rate}, nil
+}
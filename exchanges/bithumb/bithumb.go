@@ -548,11 +548,25 @@ func (b *Bithumb) SendHTTPRequest(path string, result interface{}) error {
 	return b.SendPayload(http.MethodGet, path, nil, nil, result, false, false, b.Verbose, b.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Every
+// endpoint is a POST so they are classified by name
+func mutating(path string) bool {
+	switch path {
+	case privatePlaceTrade, privateCancelTrade, privateBTCWithdraw, privateKRWWithdraw,
+		privateMarketBuy, privateMarketSell:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP request to bithumb
 func (b *Bithumb) SendAuthenticatedHTTPRequest(path string, params url.Values, result interface{}) error {
 	if !b.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, b.Name)
 	}
+	if b.DryRunRequest(mutating(path), path, params) {
+		return nil
+	}
 
 	if params == nil {
 		params = url.Values{}
@@ -13,6 +13,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/endpoints"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/request"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
@@ -111,6 +112,27 @@ const (
 	ContractUpsideProfit
 )
 
+// Order parameter values
+const (
+	bitmexMarketOrder       = "Market"
+	bitmexLimitOrder        = "Limit"
+	bitmexStopOrder         = "Stop"
+	bitmexGoodTillCancel    = "GoodTillCancel"
+	bitmexImmediateOrCancel = "ImmediateOrCancel"
+	bitmexFillOrKill        = "FillOrKill"
+
+	// bitmexParticipateDoNotInitiate cancels limit orders which would take
+	// liquidity
+	bitmexParticipateDoNotInitiate = "ParticipateDoNotInitiate"
+
+	// Contingent orders share a clOrdLinkID
+	bitmexOneTriggersTheOther = "OneTriggersTheOther"
+	bitmexOneCancelsTheOther  = "OneCancelsTheOther"
+
+	// bitmexMaxBulkOrders is the number of orders sent per bulk request
+	bitmexMaxBulkOrders = 10
+)
+
 // SetDefaults sets the basic defaults for Bitmex
 func (b *Bitmex) SetDefaults() {
 	b.Name = "Bitmex"
@@ -177,6 +199,14 @@ func (b *Bitmex) Setup(exch *config.ExchangeConfig) {
 		if err != nil {
 			log.Fatal(err)
 		}
+		b.Endpoints, err = NewEndpoints()
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = b.SetAPIVersion(exch)
+		if err != nil {
+			log.Fatal(err)
+		}
 		err = b.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
 			log.Fatal(err)
@@ -313,8 +343,7 @@ func (b *Bitmex) GetAccountExecutions(params *GenericRequestParams) ([]Execution
 func (b *Bitmex) GetAccountExecutionTradeHistory(params *GenericRequestParams) ([]Execution, error) {
 	var tradeHistory []Execution
 
-	return tradeHistory, b.SendAuthenticatedHTTPRequest(http.MethodGet,
-		bitmexEndpointExecutionTradeHistory,
+	return tradeHistory, b.SendEndpointRequest(endpoints.Fills,
 		params,
 		&tradeHistory)
 }
@@ -328,6 +357,16 @@ func (b *Bitmex) GetFullFundingHistory() ([]Funding, error) {
 		&fundingHistory)
 }
 
+// GetFundingRateHistory returns funding rate history filtered by the supplied
+// parameters
+func (b *Bitmex) GetFundingRateHistory(params *GenericRequestParams) ([]Funding, error) {
+	var fundingHistory []Funding
+
+	return fundingHistory, b.SendHTTPRequest(bitmexEndpointFundingHistory,
+		params,
+		&fundingHistory)
+}
+
 // GetInstruments returns instrument data
 func (b *Bitmex) GetInstruments(params *GenericRequestParams) ([]Instrument, error) {
 	var instruments []Instrument
@@ -341,7 +380,7 @@ func (b *Bitmex) GetInstruments(params *GenericRequestParams) ([]Instrument, err
 func (b *Bitmex) GetActiveInstruments(params *GenericRequestParams) ([]Instrument, error) {
 	var activeInstruments []Instrument
 
-	return activeInstruments, b.SendHTTPRequest(bitmexEndpointActiveInstruments,
+	return activeInstruments, b.SendEndpointRequest(endpoints.Instruments,
 		params,
 		&activeInstruments)
 }
@@ -374,6 +413,40 @@ func (b *Bitmex) GetCompositeIndex(params *GenericRequestParams) ([]IndexComposi
 		&compositeIndices)
 }
 
+// GetIndexConstituents returns the latest constituents and weights of a
+// composite index such as .BXBT, and the published index price
+func (b *Bitmex) GetIndexConstituents(symbol string) ([]IndexComposite, float64, error) {
+	rows, err := b.GetCompositeIndex(&GenericRequestParams{
+		Symbol:  symbol,
+		Reverse: true,
+		Count:   100,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	// Rows are logged per constituent newest first, only the latest set is
+	// returned
+	var constituents []IndexComposite
+	for i := range rows {
+		if rows[i].Timestamp != rows[0].Timestamp {
+			break
+		}
+		if rows[i].IndexSymbol == symbol {
+			continue
+		}
+		constituents = append(constituents, rows[i])
+	}
+
+	index, err := b.GetInstruments(&GenericRequestParams{Symbol: symbol, Count: 1, Reverse: true})
+	if err != nil {
+		return nil, 0, err
+	}
+	if len(index) == 0 {
+		return nil, 0, fmt.Errorf("index %s not found", symbol)
+	}
+	return constituents, index[0].LastPrice, nil
+}
+
 // GetIndices returns all price indices
 func (b *Bitmex) GetIndices() ([]Instrument, error) {
 	var indices []Instrument
@@ -425,8 +498,7 @@ func (b *Bitmex) GetCurrentNotifications() ([]Notification, error) {
 func (b *Bitmex) GetOrders(params *OrdersRequest) ([]Order, error) {
 	var orders []Order
 
-	return orders, b.SendAuthenticatedHTTPRequest(http.MethodGet,
-		fmt.Sprintf("%v%v", bitmexEndpointOrder, ""),
+	return orders, b.SendEndpointRequest(endpoints.Orders,
 		params,
 		&orders)
 }
@@ -435,8 +507,7 @@ func (b *Bitmex) GetOrders(params *OrdersRequest) ([]Order, error) {
 func (b *Bitmex) AmendOrder(params *OrderAmendParams) (Order, error) {
 	var order Order
 
-	return order, b.SendAuthenticatedHTTPRequest(http.MethodPut,
-		bitmexEndpointOrder,
+	return order, b.SendEndpointRequest(endpoints.AmendOrder,
 		params,
 		&order)
 }
@@ -445,8 +516,7 @@ func (b *Bitmex) AmendOrder(params *OrderAmendParams) (Order, error) {
 func (b *Bitmex) CreateOrder(params *OrderNewParams) (Order, error) {
 	var orderInfo Order
 
-	return orderInfo, b.SendAuthenticatedHTTPRequest(http.MethodPost,
-		bitmexEndpointOrder,
+	return orderInfo, b.SendEndpointRequest(endpoints.PlaceOrder,
 		params,
 		&orderInfo)
 }
@@ -456,8 +526,7 @@ func (b *Bitmex) CreateOrder(params *OrderNewParams) (Order, error) {
 func (b *Bitmex) CancelOrders(params *OrderCancelParams) ([]Order, error) {
 	var cancelledOrders []Order
 
-	return cancelledOrders, b.SendAuthenticatedHTTPRequest(http.MethodDelete,
-		bitmexEndpointOrder,
+	return cancelledOrders, b.SendEndpointRequest(endpoints.CancelOrder,
 		params,
 		&cancelledOrders)
 }
@@ -466,8 +535,7 @@ func (b *Bitmex) CancelOrders(params *OrderCancelParams) ([]Order, error) {
 func (b *Bitmex) CancelAllExistingOrders(params OrderCancelAllParams) ([]Order, error) {
 	var cancelledOrders []Order
 
-	return cancelledOrders, b.SendAuthenticatedHTTPRequest(http.MethodDelete,
-		bitmexEndpointCancelAllOrders,
+	return cancelledOrders, b.SendEndpointRequest(endpoints.CancelAllOrders,
 		params,
 		&cancelledOrders)
 }
@@ -516,7 +584,7 @@ func (b *Bitmex) ClosePosition(params OrderClosePositionParams) ([]Order, error)
 func (b *Bitmex) GetOrderbook(params OrderBookGetL2Params) ([]OrderBookL2, error) {
 	var orderBooks []OrderBookL2
 
-	return orderBooks, b.SendHTTPRequest(bitmexEndpointOrderbookL2,
+	return orderBooks, b.SendEndpointRequest(endpoints.Orderbook,
 		params,
 		&orderBooks)
 }
@@ -525,8 +593,7 @@ func (b *Bitmex) GetOrderbook(params OrderBookGetL2Params) ([]OrderBookL2, error
 func (b *Bitmex) GetPositions(params PositionGetParams) ([]Position, error) {
 	var positions []Position
 
-	return positions, b.SendAuthenticatedHTTPRequest(http.MethodGet,
-		bitmexEndpointPosition,
+	return positions, b.SendEndpointRequest(endpoints.Positions,
 		params,
 		&positions)
 }
@@ -623,14 +690,14 @@ func (b *Bitmex) GetStatSummary() ([]StatsUSD, error) {
 func (b *Bitmex) GetTrade(params *GenericRequestParams) ([]Trade, error) {
 	var trade []Trade
 
-	return trade, b.SendHTTPRequest(bitmexEndpointTrade, params, &trade)
+	return trade, b.SendEndpointRequest(endpoints.Trades, params, &trade)
 }
 
 // GetPreviousTrades previous trade history in time buckets
 func (b *Bitmex) GetPreviousTrades(params *TradeGetBucketedParams) ([]Trade, error) {
 	var trade []Trade
 
-	return trade, b.SendHTTPRequest(bitmexEndpointTradeBucketed,
+	return trade, b.SendEndpointRequest(endpoints.Candles,
 		params,
 		&trade)
 }
@@ -735,8 +802,7 @@ func (b *Bitmex) GetCryptoDepositAddress(cryptoCurrency string) (string, error)
 				cryptoCurrency)
 	}
 
-	return address, b.SendAuthenticatedHTTPRequest(http.MethodGet,
-		bitmexEndpointUserDepositAddress,
+	return address, b.SendEndpointRequest(endpoints.DepositAddress,
 		UserCurrencyParams{Currency: "XBt"},
 		&address)
 }
@@ -773,8 +839,7 @@ func (b *Bitmex) UserLogOutAll() (int64, error) {
 func (b *Bitmex) GetUserMargin(currency string) (UserMargin, error) {
 	var info UserMargin
 
-	return info, b.SendAuthenticatedHTTPRequest(http.MethodGet,
-		bitmexEndpointUserMargin,
+	return info, b.SendEndpointRequest(endpoints.Account,
 		UserCurrencyParams{Currency: currency},
 		&info)
 }
@@ -783,8 +848,7 @@ func (b *Bitmex) GetUserMargin(currency string) (UserMargin, error) {
 func (b *Bitmex) GetAllUserMargin() ([]UserMargin, error) {
 	var info []UserMargin
 
-	return info, b.SendAuthenticatedHTTPRequest(http.MethodGet,
-		bitmexEndpointUserMargin,
+	return info, b.SendEndpointRequest(endpoints.Accounts,
 		UserCurrencyParams{Currency: "all"},
 		&info)
 }
@@ -825,8 +889,7 @@ func (b *Bitmex) EnableTFA(typ string) (bool, error) {
 func (b *Bitmex) UserRequestWithdrawal(params UserRequestWithdrawalParams) (TransactionInfo, error) {
 	var info TransactionInfo
 
-	return info, b.SendAuthenticatedHTTPRequest(http.MethodPost,
-		bitmexEndpointUserRequestWithdraw,
+	return info, b.SendEndpointRequest(endpoints.Withdraw,
 		params,
 		&info)
 }
@@ -885,12 +948,56 @@ func (b *Bitmex) SendHTTPRequest(path string, params Parameter, result interface
 	return b.CaptureError(respCheck, result)
 }
 
+// SendEndpointRequest sends a request to the operation's path in the active API
+// version
+func (b *Bitmex) SendEndpointRequest(op endpoints.Operation, params Parameter, result interface{}) error {
+	if b.Endpoints == nil {
+		return fmt.Errorf("%s endpoints not set up", b.Name)
+	}
+	route, err := b.Endpoints.Resolve(op, nil)
+	if err != nil {
+		return err
+	}
+	if !route.Authenticated {
+		return b.SendHTTPRequest(route.Path, params, result)
+	}
+	return b.sendAuthenticatedHTTPRequest(route.Method, route.Path, route.SignPath, params, result)
+}
+
+// mutating returns whether the authenticated endpoint changes exchange state.
+// Orders, API keys, chat messages and user details are read and changed
+// through the same paths, so those endpoints are matched by method and path
+func mutating(verb, path string) bool {
+	switch path {
+	case bitmexEndpointOrder, bitmexEndpointAPIkeys, bitmexEndpointTrollboxSend, bitmexEndpointUser:
+		return verb != http.MethodGet
+	case bitmexEndpointCancelAllOrders, bitmexEndpointBulk, bitmexEndpointCancelOrderAfter,
+		bitmexEndpointClosePosition, bitmexEndpointIsolatePosition, bitmexEndpointLeveragePosition,
+		bitmexEndpointAdjustRiskLimit, bitmexEndpointTransferMargin, bitmexEndpointDisableAPIkey,
+		bitmexEndpointEnableAPIkey, bitmexEndpointUserCancelWithdraw, bitmexEndpointUserConfirmEmail,
+		bitmexEndpointUserConfirmTFA, bitmexEndpointUserConfirmWithdrawal, bitmexEndpointUserDisableTFA,
+		bitmexEndpointUserLogout, bitmexEndpointUserLogoutAll, bitmexEndpointUserPreferences,
+		bitmexEndpointUserRequestTFA, bitmexEndpointUserRequestWithdraw:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP request to bitmex
 func (b *Bitmex) SendAuthenticatedHTTPRequest(verb, path string, params Parameter, result interface{}) error {
+	return b.sendAuthenticatedHTTPRequest(verb, path, "/api/"+bitmexAPIVersion+path, params, result)
+}
+
+// sendAuthenticatedHTTPRequest sends the request to the path under the API URL
+// signing signPath
+func (b *Bitmex) sendAuthenticatedHTTPRequest(verb, path, signPath string, params Parameter, result interface{}) error {
 	if !b.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet,
 			b.Name)
 	}
+	if b.DryRunRequest(mutating(verb, path), verb+" "+path, params) {
+		return nil
+	}
 
 	timestamp := time.Now().Add(time.Second * 10).UnixNano()
 	timestampStr := strconv.FormatInt(timestamp, 10)
@@ -915,7 +1022,7 @@ func (b *Bitmex) SendAuthenticatedHTTPRequest(verb, path string, params Paramete
 	}
 
 	hmac := common.GetHMAC(common.HashSHA256,
-		[]byte(verb+"/api/v1"+path+timestampNew+payload),
+		[]byte(verb+signPath+timestampNew+payload),
 		[]byte(b.APISecret))
 
 	headers["api-signature"] = common.HexEncodeToString(hmac)
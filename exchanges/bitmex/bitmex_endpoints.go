@@ -0,0 +1,52 @@
+package bitmex
+
+import (
+	"net/http"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/endpoints"
+)
+
+// v1Endpoints holds the paths of the v1 API relative to the API URL
+var v1Endpoints = endpoints.Version{
+	Name:       bitmexAPIVersion,
+	SignPrefix: "/api/" + bitmexAPIVersion,
+	Endpoints: map[endpoints.Operation]endpoints.Endpoint{
+		endpoints.Orderbook:   {Method: http.MethodGet, Path: bitmexEndpointOrderbookL2},
+		endpoints.Trades:      {Method: http.MethodGet, Path: bitmexEndpointTrade},
+		endpoints.Candles:     {Method: http.MethodGet, Path: bitmexEndpointTradeBucketed},
+		endpoints.Instruments: {Method: http.MethodGet, Path: bitmexEndpointActiveInstruments},
+		endpoints.Accounts:    {Method: http.MethodGet, Path: bitmexEndpointUserMargin, Authenticated: true},
+		endpoints.Account:     {Method: http.MethodGet, Path: bitmexEndpointUserMargin, Authenticated: true},
+		endpoints.PlaceOrder:  {Method: http.MethodPost, Path: bitmexEndpointOrder, Authenticated: true},
+		endpoints.AmendOrder:  {Method: http.MethodPut, Path: bitmexEndpointOrder, Authenticated: true},
+		endpoints.CancelOrder: {Method: http.MethodDelete, Path: bitmexEndpointOrder, Authenticated: true},
+		endpoints.CancelAllOrders: {
+			Method:        http.MethodDelete,
+			Path:          bitmexEndpointCancelAllOrders,
+			Authenticated: true,
+		},
+		endpoints.Orders:    {Method: http.MethodGet, Path: bitmexEndpointOrder, Authenticated: true},
+		endpoints.Positions: {Method: http.MethodGet, Path: bitmexEndpointPosition, Authenticated: true},
+		endpoints.Fills: {
+			Method:        http.MethodGet,
+			Path:          bitmexEndpointExecutionTradeHistory,
+			Authenticated: true,
+		},
+		endpoints.DepositAddress: {
+			Method:        http.MethodGet,
+			Path:          bitmexEndpointUserDepositAddress,
+			Authenticated: true,
+		},
+		endpoints.Withdraw: {
+			Method:        http.MethodPost,
+			Path:          bitmexEndpointUserRequestWithdraw,
+			Authenticated: true,
+		},
+	},
+}
+
+// NewEndpoints returns the endpoint registry of the Bitmex REST API versions
+// with v1 active
+func NewEndpoints() (*endpoints.Registry, error) {
+	return endpoints.New(v1Endpoints)
+}
@@ -0,0 +1,334 @@
+package bitmex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// MarginMode defines whether a position uses cross or isolated margin
+type MarginMode int
+
+// Margin modes
+const (
+	CrossMargin MarginMode = iota
+	IsolatedMargin
+)
+
+// String implements the stringer interface
+func (m MarginMode) String() string {
+	if m == IsolatedMargin {
+		return "isolated"
+	}
+	return "cross"
+}
+
+// Margin manager defaults
+const (
+	// DefaultLiquidationDistance is the fractional distance between the mark
+	// price and the liquidation price below which isolated margin is topped up
+	DefaultLiquidationDistance = 0.05
+	// DefaultTopUpFraction is the fraction of the current position margin
+	// transferred in when a top up is triggered and no amount is configured
+	DefaultTopUpFraction = 0.1
+	// DefaultMarginCheckInterval is the delay between margin checks
+	DefaultMarginCheckInterval = time.Minute
+)
+
+var (
+	errMarginSymbolNotSet    = errors.New("margin settings symbol not set")
+	errInvalidMarginLeverage = errors.New("isolated margin requires leverage between 0.01 and 100")
+	errInvalidMarginBuffer   = errors.New("margin buffer cannot be negative")
+	errMarginStateMismatch   = errors.New("margin state could not be verified")
+)
+
+// MarginSettings defines the required margin state of a position
+type MarginSettings struct {
+	Symbol   string
+	Mode     MarginMode
+	Leverage float64
+	// Buffer is the required excess of position margin over maintenance
+	// margin, e.g. 0.5 requires position margin of at least 150% of the
+	// maintenance margin
+	Buffer float64
+	// LiquidationDistance is the fractional distance from mark price to
+	// liquidation price that triggers an automatic top up
+	LiquidationDistance float64
+	// TopUpAmount is the amount in satoshis transferred on each top up, if
+	// zero DefaultTopUpFraction of the position margin is used
+	TopUpAmount int64
+	// MaxTopUp caps the amount in satoshis transferred in a single check
+	MaxTopUp int64
+}
+
+// Validate checks the margin settings
+func (s *MarginSettings) Validate() error {
+	if s.Symbol == "" {
+		return errMarginSymbolNotSet
+	}
+	if s.Mode == IsolatedMargin && (s.Leverage < 0.01 || s.Leverage > 100) {
+		return errInvalidMarginLeverage
+	}
+	if s.Buffer < 0 {
+		return errInvalidMarginBuffer
+	}
+	if s.LiquidationDistance <= 0 {
+		s.LiquidationDistance = DefaultLiquidationDistance
+	}
+	return nil
+}
+
+// MarginAction records a margin transfer performed by the manager
+type MarginAction struct {
+	Symbol           string
+	Amount           int64
+	Reason           string
+	MarkPrice        float64
+	LiquidationPrice float64
+	Timestamp        time.Time
+}
+
+// MarginManager sets and verifies position margin modes and keeps isolated
+// positions away from liquidation by topping up margin
+type MarginManager struct {
+	exch     *Bitmex
+	settings map[string]MarginSettings
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// NewMarginManager returns a new margin manager for the exchange
+func NewMarginManager(b *Bitmex) *MarginManager {
+	return &MarginManager{
+		exch:     b,
+		settings: make(map[string]MarginSettings),
+	}
+}
+
+// Add adds or replaces the required margin settings for a symbol
+func (m *MarginManager) Add(s MarginSettings) error {
+	err := s.Validate()
+	if err != nil {
+		return err
+	}
+	m.mtx.Lock()
+	m.settings[s.Symbol] = s
+	m.mtx.Unlock()
+	return nil
+}
+
+func (m *MarginManager) getSettings() []MarginSettings {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	s := make([]MarginSettings, 0, len(m.settings))
+	for _, v := range m.settings {
+		s = append(s, v)
+	}
+	return s
+}
+
+func (m *MarginManager) getPosition(symbol string) (Position, error) {
+	positions, err := m.exch.GetPositions(PositionGetParams{
+		Filter: fmt.Sprintf("{\"symbol\":%q}", symbol),
+	})
+	if err != nil {
+		return Position{}, err
+	}
+
+	for i := range positions {
+		if positions[i].Symbol == symbol {
+			return positions[i], nil
+		}
+	}
+	// no position has been opened yet, margin defaults to cross
+	return Position{Symbol: symbol, CrossMargin: true}, nil
+}
+
+// Prepare sets the margin mode and leverage of all configured symbols and
+// verifies the state reported by the exchange. It should be called before
+// strategy trading starts.
+func (m *MarginManager) Prepare() error {
+	settings := m.getSettings()
+	for i := range settings {
+		pos, err := m.getPosition(settings[i].Symbol)
+		if err != nil {
+			return err
+		}
+
+		if marginStateMatches(&pos, &settings[i]) {
+			continue
+		}
+
+		switch settings[i].Mode {
+		case IsolatedMargin:
+			pos, err = m.exch.IsolatePosition(PositionIsolateMarginParams{
+				Symbol:  settings[i].Symbol,
+				Enabled: true,
+			})
+			if err != nil {
+				return err
+			}
+			pos, err = m.exch.LeveragePosition(PositionUpdateLeverageParams{
+				Symbol:   settings[i].Symbol,
+				Leverage: settings[i].Leverage,
+			})
+		default:
+			pos, err = m.exch.LeveragePosition(PositionUpdateLeverageParams{
+				Symbol:   settings[i].Symbol,
+				Leverage: 0,
+			})
+		}
+		if err != nil {
+			return err
+		}
+
+		if !marginStateMatches(&pos, &settings[i]) {
+			return fmt.Errorf("%s %s: requested %s margin", errMarginStateMismatch,
+				settings[i].Symbol, settings[i].Mode)
+		}
+		log.Debugf("%s margin manager set %s to %s margin",
+			m.exch.Name, settings[i].Symbol, settings[i].Mode)
+	}
+	return nil
+}
+
+// marginStateMatches returns whether the position margin state matches the
+// settings
+func marginStateMatches(p *Position, s *MarginSettings) bool {
+	if s.Mode == CrossMargin {
+		return p.CrossMargin
+	}
+	return !p.CrossMargin && p.Leverage == s.Leverage
+}
+
+// Check evaluates all configured isolated positions and transfers margin to
+// those breaching their buffer or nearing liquidation
+func (m *MarginManager) Check() ([]MarginAction, error) {
+	var actions []MarginAction
+	settings := m.getSettings()
+	for i := range settings {
+		if settings[i].Mode != IsolatedMargin {
+			continue
+		}
+
+		pos, err := m.getPosition(settings[i].Symbol)
+		if err != nil {
+			return actions, err
+		}
+
+		amount, reason := evaluateMargin(&pos, &settings[i])
+		if amount <= 0 {
+			continue
+		}
+
+		_, err = m.exch.TransferMargin(PositionTransferIsolatedMarginParams{
+			Symbol: settings[i].Symbol,
+			Amount: amount,
+		})
+		if err != nil {
+			return actions, err
+		}
+
+		actions = append(actions, MarginAction{
+			Symbol:           settings[i].Symbol,
+			Amount:           amount,
+			Reason:           reason,
+			MarkPrice:        pos.MarkPrice,
+			LiquidationPrice: pos.LiquidationPrice,
+			Timestamp:        time.Now(),
+		})
+		log.Warnf("%s margin manager topped up %s by %d satoshis: %s",
+			m.exch.Name, settings[i].Symbol, amount, reason)
+	}
+	return actions, nil
+}
+
+// evaluateMargin returns the amount of margin in satoshis to add to a position
+// and the reason for doing so
+func evaluateMargin(p *Position, s *MarginSettings) (int64, string) {
+	if !p.IsOpen || p.CurrentQty == 0 || p.CrossMargin {
+		return 0, ""
+	}
+
+	var amount int64
+	var reason string
+
+	required := int64(math.Ceil(float64(p.MaintMargin) * (1 + s.Buffer)))
+	if p.PosMargin < required {
+		amount = required - p.PosMargin
+		reason = fmt.Sprintf("position margin %d below buffered maintenance margin %d",
+			p.PosMargin, required)
+	}
+
+	if p.MarkPrice > 0 && p.LiquidationPrice > 0 {
+		distance := math.Abs(p.MarkPrice-p.LiquidationPrice) / p.MarkPrice
+		if distance < s.LiquidationDistance {
+			topUp := s.TopUpAmount
+			if topUp == 0 {
+				topUp = int64(float64(p.PosMargin) * DefaultTopUpFraction)
+			}
+			if topUp > amount {
+				amount = topUp
+				reason = fmt.Sprintf("liquidation price %v within %.2f%% of mark price %v",
+					p.LiquidationPrice, distance*100, p.MarkPrice)
+			}
+		}
+	}
+
+	if s.MaxTopUp > 0 && amount > s.MaxTopUp {
+		amount = s.MaxTopUp
+	}
+	return amount, reason
+}
+
+// Start runs margin checks at the supplied interval until Stop is called
+func (m *MarginManager) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultMarginCheckInterval
+	}
+
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				_, err := m.Check()
+				if err != nil {
+					log.Errorf("%s margin manager check failed: %s", m.exch.Name, err)
+				}
+			}
+		}
+	}()
+}
+
+// Stop stops the margin check routine
+func (m *MarginManager) Stop() {
+	m.mtx.Lock()
+	if m.shutdown == nil {
+		m.mtx.Unlock()
+		return
+	}
+	close(m.shutdown)
+	m.shutdown = nil
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
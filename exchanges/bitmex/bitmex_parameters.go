@@ -627,7 +627,7 @@ func (p PositionGetParams) IsNil() bool {
 // endpoint
 type PositionIsolateMarginParams struct {
 	// Enabled - True for isolated margin, false for cross margin.
-	Enabled bool `json:"enabled,omitempty"`
+	Enabled bool `json:"enabled"`
 
 	// Symbol - Position symbol to isolate.
 	Symbol string `json:"symbol,omitempty"`
@@ -654,7 +654,7 @@ func (p PositionIsolateMarginParams) IsNil() bool {
 type PositionUpdateLeverageParams struct {
 	// Leverage - Leverage value. Send a number between 0.01 and 100 to enable
 	// isolated margin with a fixed leverage. Send 0 to enable cross margin.
-	Leverage float64 `json:"leverage,omitempty"`
+	Leverage float64 `json:"leverage"`
 
 	// Symbol - Symbol of position to adjust.
 	Symbol string `json:"symbol,omitempty"`
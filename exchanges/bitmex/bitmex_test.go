@@ -11,6 +11,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/endpoints"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/sharedtestvalues"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
 )
@@ -135,6 +136,17 @@ func TestGetFundingHistory(t *testing.T) {
 	}
 }
 
+func TestGetFundingRateHistory(t *testing.T) {
+	_, err := b.GetFundingRateHistory(&GenericRequestParams{
+		Symbol:  "XBTUSD",
+		Count:   10,
+		Reverse: true,
+	})
+	if err != nil {
+		t.Error("test failed - GetFundingRateHistory() error", err)
+	}
+}
+
 func TestGetInstruments(t *testing.T) {
 	_, err := b.GetInstruments(&GenericRequestParams{})
 	if err != nil {
@@ -170,6 +182,13 @@ func TestGetCompositeIndex(t *testing.T) {
 	}
 }
 
+func TestGetIndexConstituents(t *testing.T) {
+	_, _, err := b.GetIndexConstituents(".BXBT")
+	if err != nil {
+		t.Error("test failed - GetIndexConstituents() error", err)
+	}
+}
+
 func TestGetIndices(t *testing.T) {
 	_, err := b.GetIndices()
 	if err != nil {
@@ -537,6 +556,61 @@ func TestSubmitOrder(t *testing.T) {
 	}
 }
 
+func TestSubmitOrderWithTimeInForce(t *testing.T) {
+	b.SetDefaults()
+	TestSetup(t)
+
+	if areTestAPIKeysSet() && !canManipulateRealOrders {
+		t.Skip("API keys set, canManipulateRealOrders false, skipping test")
+	}
+
+	var p = currency.Pair{
+		Delimiter: "",
+		Base:      currency.XBT,
+		Quote:     currency.USD,
+	}
+	_, err := b.SubmitOrderWithTimeInForce(p, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 10, "", exchange.GoodTillDate, time.Now().Add(time.Hour))
+	if err != exchange.ErrTimeInForceUnsupported {
+		t.Errorf("Test Failed - SubmitOrderWithTimeInForce() expected %v, received %v",
+			exchange.ErrTimeInForceUnsupported, err)
+	}
+
+	response, err := b.SubmitOrderWithTimeInForce(p, exchange.BuyOrderSide, exchange.MarketOrderType, 1, 1, "", exchange.ImmediateOrCancel, time.Time{})
+	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
+		t.Errorf("Order failed to be placed: %v", err)
+	} else if !areTestAPIKeysSet() && err == nil {
+		t.Error("Expecting an error when no keys are set")
+	}
+}
+
+func TestSubmitBatchOrders(t *testing.T) {
+	b.SetDefaults()
+	TestSetup(t)
+
+	if areTestAPIKeysSet() && !canManipulateRealOrders {
+		t.Skip("API keys set, canManipulateRealOrders false, skipping test")
+	}
+
+	var p = currency.Pair{
+		Delimiter: "",
+		Base:      currency.XBT,
+		Quote:     currency.USD,
+	}
+	orders := []exchange.OrderSubmission{
+		{Pair: p, Side: exchange.BuyOrderSide, OrderType: exchange.LimitOrderType, Amount: 1, Price: 1},
+		{Pair: p, Side: exchange.BuyOrderSide, OrderType: exchange.LimitOrderType, Amount: 1.5, Price: 1},
+	}
+	results := exchange.SubmitOrders(&b, orders)
+	if results[1].Err == nil {
+		t.Error("Expecting an error for a fractional contract amount")
+	}
+	if areTestAPIKeysSet() && (results[0].Err != nil || !results[0].IsOrderPlaced) {
+		t.Errorf("Order failed to be placed: %v", results[0].Err)
+	} else if !areTestAPIKeysSet() && results[0].Err == nil {
+		t.Error("Expecting an error when no keys are set")
+	}
+}
+
 func TestCancelExchangeOrder(t *testing.T) {
 	b.SetDefaults()
 	TestSetup(t)
@@ -722,3 +796,101 @@ func TestWsAuth(t *testing.T) {
 	}
 	timer.Stop()
 }
+
+func TestMarginSettingsValidate(t *testing.T) {
+	s := MarginSettings{}
+	if err := s.Validate(); err != errMarginSymbolNotSet {
+		t.Errorf("Test Failed - Validate() expected %v received %v", errMarginSymbolNotSet, err)
+	}
+
+	s = MarginSettings{Symbol: "XBTUSD", Mode: IsolatedMargin}
+	if err := s.Validate(); err != errInvalidMarginLeverage {
+		t.Errorf("Test Failed - Validate() expected %v received %v", errInvalidMarginLeverage, err)
+	}
+
+	s = MarginSettings{Symbol: "XBTUSD", Mode: IsolatedMargin, Leverage: 10}
+	if err := s.Validate(); err != nil {
+		t.Error("Test Failed - Validate() error", err)
+	}
+
+	if s.LiquidationDistance != DefaultLiquidationDistance {
+		t.Error("Test Failed - Validate() default liquidation distance not set")
+	}
+}
+
+func TestMarginStateMatches(t *testing.T) {
+	s := MarginSettings{Symbol: "XBTUSD", Mode: IsolatedMargin, Leverage: 10}
+	p := Position{CrossMargin: true}
+	if marginStateMatches(&p, &s) {
+		t.Error("Test Failed - marginStateMatches() cross position should not match isolated settings")
+	}
+
+	p = Position{Leverage: 10}
+	if !marginStateMatches(&p, &s) {
+		t.Error("Test Failed - marginStateMatches() isolated position should match")
+	}
+
+	s.Mode = CrossMargin
+	if marginStateMatches(&p, &s) {
+		t.Error("Test Failed - marginStateMatches() isolated position should not match cross settings")
+	}
+}
+
+func TestEvaluateMargin(t *testing.T) {
+	s := MarginSettings{
+		Symbol:              "XBTUSD",
+		Mode:                IsolatedMargin,
+		Leverage:            10,
+		Buffer:              0.5,
+		LiquidationDistance: 0.05,
+	}
+
+	p := Position{
+		IsOpen:           true,
+		CurrentQty:       100,
+		MaintMargin:      1000,
+		PosMargin:        2000,
+		MarkPrice:        10000,
+		LiquidationPrice: 9000,
+	}
+	if amount, _ := evaluateMargin(&p, &s); amount != 0 {
+		t.Errorf("Test Failed - evaluateMargin() expected no top up, received %d", amount)
+	}
+
+	p.PosMargin = 1200
+	if amount, _ := evaluateMargin(&p, &s); amount != 300 {
+		t.Errorf("Test Failed - evaluateMargin() expected buffer top up of 300, received %d", amount)
+	}
+
+	p.PosMargin = 20000
+	p.LiquidationPrice = 9600
+	if amount, _ := evaluateMargin(&p, &s); amount != 2000 {
+		t.Errorf("Test Failed - evaluateMargin() expected liquidation top up of 2000, received %d", amount)
+	}
+
+	s.MaxTopUp = 500
+	if amount, _ := evaluateMargin(&p, &s); amount != 500 {
+		t.Errorf("Test Failed - evaluateMargin() expected capped top up of 500, received %d", amount)
+	}
+
+	p.CrossMargin = true
+	if amount, _ := evaluateMargin(&p, &s); amount != 0 {
+		t.Errorf("Test Failed - evaluateMargin() expected no top up for cross margin, received %d", amount)
+	}
+}
+
+func TestEndpoints(t *testing.T) {
+	t.Parallel()
+	r, err := NewEndpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	route, err := r.Resolve(endpoints.PlaceOrder, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.Method != http.MethodPost || route.Path != bitmexEndpointOrder ||
+		route.SignPath != "/api/v1/order" || !route.Authenticated {
+		t.Errorf("Test Failed - NewEndpoints() unexpected place order route %+v", route)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"math"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/currency"
@@ -175,6 +176,47 @@ func (b *Bitmex) GetExchangeHistory(p currency.Pair, assetType string) ([]exchan
 
 // SubmitOrder submits a new order
 func (b *Bitmex) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	return b.submitOrder(p, side, orderType, amount, price, "", "")
+}
+
+// SupportedTimeInForce returns the time in force types Bitmex orders accept
+func (b *Bitmex) SupportedTimeInForce() []exchange.TimeInForce {
+	return []exchange.TimeInForce{
+		exchange.GoodTillCancel,
+		exchange.ImmediateOrCancel,
+		exchange.FillOrKill,
+	}
+}
+
+// SubmitOrderWithTimeInForce submits a new order with the timeInForce
+// parameter set
+func (b *Bitmex) SubmitOrderWithTimeInForce(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string, tif exchange.TimeInForce, expiry time.Time) (exchange.SubmitOrderResponse, error) {
+	err := tif.Validate(orderType, expiry)
+	if err != nil {
+		return exchange.SubmitOrderResponse{}, err
+	}
+
+	var timeInForce string
+	switch tif {
+	case exchange.GoodTillCancel:
+		timeInForce = bitmexGoodTillCancel
+	case exchange.ImmediateOrCancel:
+		timeInForce = bitmexImmediateOrCancel
+	case exchange.FillOrKill:
+		timeInForce = bitmexFillOrKill
+	default:
+		return exchange.SubmitOrderResponse{}, exchange.ErrTimeInForceUnsupported
+	}
+	return b.submitOrder(p, side, orderType, amount, price, timeInForce, "")
+}
+
+// SubmitPostOnlyOrder submits a limit order which is cancelled rather than
+// executed if it would take liquidity
+func (b *Bitmex) SubmitPostOnlyOrder(p currency.Pair, side exchange.OrderSide, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	return b.submitOrder(p, side, exchange.LimitOrderType, amount, price, "", bitmexParticipateDoNotInitiate)
+}
+
+func (b *Bitmex) submitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, timeInForce, execInst string) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
 
 	if math.Mod(amount, 1) != 0 {
@@ -182,16 +224,9 @@ func (b *Bitmex) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType
 			errors.New("contract amount can not have decimals")
 	}
 
-	var orderNewParams = OrderNewParams{
-		OrdType:  side.ToString(),
-		Symbol:   p.String(),
-		OrderQty: amount,
-		Side:     side.ToString(),
-	}
-
-	if orderType == exchange.LimitOrderType {
-		orderNewParams.Price = price
-	}
+	orderNewParams := newOrderParams(p, side, orderType, amount, price)
+	orderNewParams.TimeInForce = timeInForce
+	orderNewParams.ExecInst = execInst
 
 	response, err := b.CreateOrder(&orderNewParams)
 	if response.OrderID != "" {
@@ -205,6 +240,84 @@ func (b *Bitmex) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType
 	return submitOrderResponse, err
 }
 
+// MaxBatchOrders returns the number of orders sent per bulk order request
+func (b *Bitmex) MaxBatchOrders() int {
+	return bitmexMaxBulkOrders
+}
+
+// SubmitBatchOrders submits orders through the bulk order endpoint, one
+// request is sent per symbol
+func (b *Bitmex) SubmitBatchOrders(orders []exchange.OrderSubmission) ([]exchange.BatchOrderResult, error) {
+	results := make([]exchange.BatchOrderResult, len(orders))
+	var symbols []string
+	bySymbol := make(map[string][]int)
+	for i := range orders {
+		if math.Mod(orders[i].Amount, 1) != 0 {
+			results[i].Err = errors.New("contract amount can not have decimals")
+			continue
+		}
+		symbol := orders[i].Pair.String()
+		if _, ok := bySymbol[symbol]; !ok {
+			symbols = append(symbols, symbol)
+		}
+		bySymbol[symbol] = append(bySymbol[symbol], i)
+	}
+
+	for _, symbol := range symbols {
+		indexes := bySymbol[symbol]
+		var params OrderNewBulkParams
+		for _, i := range indexes {
+			params.Orders = append(params.Orders, newOrderParams(orders[i].Pair,
+				orders[i].Side,
+				orders[i].OrderType,
+				orders[i].Amount,
+				orders[i].Price))
+			params.Orders[len(params.Orders)-1].ClOrdID = orders[i].ClientID
+		}
+
+		response, err := b.CreateBulkOrders(params)
+		for x, i := range indexes {
+			switch {
+			case err != nil:
+				results[i].Err = err
+			case x >= len(response):
+				results[i].Err = fmt.Errorf("no bulk order response for %s", symbol)
+			case response[x].OrdRejReason != "":
+				results[i].OrderID = response[x].OrderID
+				results[i].Err = errors.New(response[x].OrdRejReason)
+			default:
+				results[i].OrderID = response[x].OrderID
+				results[i].IsOrderPlaced = true
+			}
+		}
+	}
+	return results, nil
+}
+
+// newOrderParams returns new order parameters for the supplied order
+func newOrderParams(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64) OrderNewParams {
+	params := OrderNewParams{
+		OrdType:  bitmexMarketOrder,
+		Symbol:   p.String(),
+		OrderQty: amount,
+		Side:     bitmexSide(side),
+	}
+
+	if orderType == exchange.LimitOrderType {
+		params.OrdType = bitmexLimitOrder
+		params.Price = price
+	}
+	return params
+}
+
+// bitmexSide converts an order side to the Bitmex casing
+func bitmexSide(side exchange.OrderSide) string {
+	if side == exchange.SellOrderSide {
+		return "Sell"
+	}
+	return "Buy"
+}
+
 // ModifyOrder will allow of changing orderbook placement and limit to
 // market conversion
 func (b *Bitmex) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
@@ -256,10 +369,163 @@ func (b *Bitmex) CancelAllOrders(_ *exchange.OrderCancellation) (exchange.Cancel
 	return cancelAllOrdersResponse, nil
 }
 
-// GetOrderInfo returns information on a current open order
+// CancelOrdersByFilter cancels open orders matching the filter's pairs, side
+// and order type using the cancel all endpoint's native filter
+func (b *Bitmex) CancelOrdersByFilter(f *exchange.OrderFilter) (exchange.CancelOrdersResult, error) {
+	resp := exchange.CancelOrdersResult{
+		Exchange: b.Name,
+		Failed:   make(map[string]string),
+	}
+
+	filter := make(map[string]string)
+	if f.Side != "" && f.Side != exchange.AnyOrderSide {
+		filter["side"] = bitmexSide(f.Side)
+	}
+	switch f.OrderType {
+	case "", exchange.AnyOrderType:
+	case exchange.LimitOrderType:
+		filter["ordType"] = bitmexLimitOrder
+	case exchange.MarketOrderType:
+		filter["ordType"] = bitmexMarketOrder
+	default:
+		return resp, common.ErrFunctionNotSupported
+	}
+
+	var params OrderCancelAllParams
+	if len(filter) > 0 {
+		encoded, err := common.JSONEncode(filter)
+		if err != nil {
+			return resp, err
+		}
+		params.Filter = string(encoded)
+	}
+
+	symbols := []string{""}
+	if len(f.Pairs) > 0 {
+		symbols = symbols[:0]
+		for i := range f.Pairs {
+			symbols = append(symbols, f.Pairs[i].String())
+		}
+	}
+	for i := range symbols {
+		params.Symbol = symbols[i]
+		orders, err := b.CancelAllExistingOrders(params)
+		if err != nil {
+			return resp, err
+		}
+		for j := range orders {
+			if orders[j].OrdRejReason != "" {
+				resp.Failed[orders[j].OrderID] = orders[j].OrdRejReason
+				continue
+			}
+			resp.Cancelled = append(resp.Cancelled, orders[j].OrderID)
+		}
+	}
+	return resp, nil
+}
+
+// GetOrderInfo returns information on an open or closed order
 func (b *Bitmex) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
-	return orderDetail, common.ErrNotYetImplemented
+	resp, err := b.GetOrders(&OrdersRequest{
+		Filter: fmt.Sprintf("{\"orderID\":%q}", orderID),
+	})
+	if err != nil {
+		return orderDetail, err
+	}
+	if len(resp) == 0 {
+		return orderDetail, fmt.Errorf("%s order %s not found", b.Name, orderID)
+	}
+
+	orderType := orderTypeMap[resp[0].OrdType]
+	if orderType == "" {
+		orderType = exchange.UnknownOrderType
+	}
+	return exchange.OrderDetail{
+		Exchange:        b.Name,
+		ID:              resp[0].OrderID,
+		OrderSide:       orderSideMap[resp[0].Side],
+		OrderType:       orderType,
+		Status:          resp[0].OrdStatus,
+		Price:           resp[0].Price,
+		Amount:          float64(resp[0].OrderQty),
+		ExecutedAmount:  float64(resp[0].CumQty),
+		RemainingAmount: float64(resp[0].LeavesQty),
+		CurrencyPair: currency.NewPairWithDelimiter(resp[0].Symbol,
+			resp[0].SettlCurrency,
+			b.ConfigCurrencyPairFormat.Delimiter),
+	}, nil
+}
+
+// SubmitBracketOrder places the entry, take profit and stop loss of a bracket
+// as contingent orders linked by the link ID. The entry triggers the exits once
+// filled and an exit filling cancels the other
+func (b *Bitmex) SubmitBracketOrder(bracket *exchange.BracketOrder, linkID string) (exchange.BracketOrderResponse, error) {
+	var resp exchange.BracketOrderResponse
+	if err := bracket.Validate(); err != nil {
+		return resp, err
+	}
+	if math.Mod(bracket.Amount, 1) != 0 {
+		return resp, errors.New("contract amount can not have decimals")
+	}
+
+	orderType := exchange.LimitOrderType
+	if bracket.EntryPrice == 0 {
+		orderType = exchange.MarketOrderType
+	}
+	entry := newOrderParams(bracket.Pair, bracket.Side, orderType,
+		bracket.Amount, bracket.EntryPrice)
+	entry.ClOrdID = linkID + "-entry"
+	entry.ClOrdLinkID = linkID
+	entry.ContingencyType = bitmexOneTriggersTheOther
+	params := OrderNewBulkParams{Orders: []OrderNewParams{entry}}
+
+	exitSide := bitmexSide(bracket.ExitSide())
+	if bracket.TakeProfit > 0 {
+		params.Orders = append(params.Orders, OrderNewParams{
+			ClOrdID:         linkID + "-tp",
+			ClOrdLinkID:     linkID,
+			ContingencyType: bitmexOneCancelsTheOther,
+			ExecInst:        "ReduceOnly",
+			OrdType:         bitmexLimitOrder,
+			OrderQty:        bracket.Amount,
+			Price:           bracket.TakeProfit,
+			Side:            exitSide,
+			Symbol:          entry.Symbol,
+		})
+	}
+	if bracket.StopLoss > 0 {
+		params.Orders = append(params.Orders, OrderNewParams{
+			ClOrdID:         linkID + "-sl",
+			ClOrdLinkID:     linkID,
+			ContingencyType: bitmexOneCancelsTheOther,
+			ExecInst:        "LastPrice,ReduceOnly",
+			OrdType:         bitmexStopOrder,
+			OrderQty:        bracket.Amount,
+			StopPx:          bracket.StopLoss,
+			Side:            exitSide,
+			Symbol:          entry.Symbol,
+		})
+	}
+
+	orders, err := b.CreateBulkOrders(params)
+	for i := range orders {
+		switch orders[i].ClOrdID {
+		case entry.ClOrdID:
+			resp.EntryID = orders[i].OrderID
+		case linkID + "-tp":
+			resp.TakeProfitID = orders[i].OrderID
+		case linkID + "-sl":
+			resp.StopLossID = orders[i].OrderID
+		}
+	}
+	if err != nil {
+		return resp, err
+	}
+	if resp.EntryID == "" {
+		return resp, fmt.Errorf("%s bracket %s entry order not acknowledged", b.Name, linkID)
+	}
+	return resp, nil
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
@@ -667,11 +667,27 @@ func (b *Bitstamp) SendHTTPRequest(path string, result interface{}) error {
 	return b.SendPayload(http.MethodGet, path, nil, nil, result, false, false, b.Verbose, b.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Every
+// endpoint is a POST so they are classified by name, orders are placed under
+// a path holding the currency pair
+func mutating(path string) bool {
+	switch path {
+	case bitstampAPICancelOrder, bitstampAPICancelAllOrders, bitstampAPIOpenWithdrawal,
+		bitstampAPIBitcoinWithdrawal, bitstampAPILTCWithdrawal, bitstampAPIETHWithdrawal,
+		bitstampAPIXrpWithdrawal, bitstampAPITransferToMain, bitstampAPITransferFromMain:
+		return true
+	}
+	return strings.HasPrefix(path, bitstampAPIBuy+"/") || strings.HasPrefix(path, bitstampAPISell+"/")
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated request
 func (b *Bitstamp) SendAuthenticatedHTTPRequest(path string, v2 bool, values url.Values, result interface{}) error {
 	if !b.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, b.Name)
 	}
+	if b.DryRunRequest(mutating(path), path, values) {
+		return nil
+	}
 
 	n := b.Requester.GetNonce(true).String()
 
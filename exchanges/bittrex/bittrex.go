@@ -6,6 +6,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
@@ -498,12 +499,26 @@ func (b *Bittrex) SendHTTPRequest(path string, result interface{}) error {
 	return b.SendPayload(http.MethodGet, path, nil, nil, result, false, false, b.Verbose, b.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Every
+// endpoint is a GET, including orders and withdrawals, so they are classified
+// by name
+func (b *Bittrex) mutating(path string) bool {
+	switch strings.TrimPrefix(path, b.APIUrl+"/") {
+	case bittrexAPIBuyLimit, bittrexAPISellLimit, bittrexAPICancel, bittrexAPIWithdraw:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated http request to a desired
 // path
 func (b *Bittrex) SendAuthenticatedHTTPRequest(path string, values url.Values, result interface{}) (err error) {
 	if !b.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, b.Name)
 	}
+	if b.DryRunRequest(b.mutating(path), path, values) {
+		return nil
+	}
 
 	n := b.Requester.GetNonce(true).String()
 
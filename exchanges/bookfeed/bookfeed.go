@@ -0,0 +1,510 @@
+// Package bookfeed distributes orderbook updates to internal subscribers such
+// as strategies as compact deltas of the levels changed, numbered by a per book
+// sequence. A subscriber's first update of a book is a snapshot, later updates
+// only carry changed levels. Updates a subscriber has not yet received are
+// conflated, merging their deltas, so slow subscribers and subscribers with a
+// max update rate never block publishing or fall behind the book
+package bookfeed
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+)
+
+// DefaultBuffer is the default number of updates buffered per subscriber
+const DefaultBuffer = 16
+
+var (
+	errFeedNil        = errors.New("orderbook update nil")
+	errExchangeNotSet = errors.New("orderbook exchange not set")
+	errInvalidRate    = errors.New("max update rate cannot be negative")
+	// ErrSequenceGap is returned when a delta does not follow the book's
+	// sequence, the subscriber must wait for a snapshot
+	ErrSequenceGap = errors.New("orderbook update sequence gap")
+)
+
+type key struct {
+	exchange  string
+	pair      string
+	assetType string
+}
+
+func newKey(exchangeName string, p currency.Pair, assetType string) key {
+	if assetType == "" {
+		assetType = orderbook.Spot
+	}
+	return key{
+		exchange:  strings.ToLower(exchangeName),
+		pair:      p.Base.Upper().String() + "-" + p.Quote.Upper().String(),
+		assetType: strings.ToUpper(assetType),
+	}
+}
+
+// levels maps a price to the amount resting at it
+type levels map[float64]float64
+
+// diff returns the levels changed from prev to next, removed levels with a
+// zero amount
+func diff(prev, next levels) levels {
+	d := make(levels)
+	for price, amount := range next {
+		if old, ok := prev[price]; !ok || old != amount {
+			d[price] = amount
+		}
+	}
+	for price := range prev {
+		if _, ok := next[price]; !ok {
+			d[price] = 0
+		}
+	}
+	return d
+}
+
+func toLevels(items []orderbook.Item) levels {
+	l := make(levels, len(items))
+	for i := range items {
+		if items[i].Amount > 0 {
+			l[items[i].Price] += items[i].Amount
+		}
+	}
+	return l
+}
+
+// items returns the levels sorted best first
+func (l levels) items(descending bool) []orderbook.Item {
+	items := make([]orderbook.Item, 0, len(l))
+	for price, amount := range l {
+		items = append(items, orderbook.Item{Price: price, Amount: amount})
+	}
+	sort.Slice(items, func(i, j int) bool {
+		if descending {
+			return items[i].Price > items[j].Price
+		}
+		return items[i].Price < items[j].Price
+	})
+	return items
+}
+
+// book holds the last published levels of a book
+type book struct {
+	exchange  string
+	pair      currency.Pair
+	assetType string
+	seq       uint64
+	bids      levels
+	asks      levels
+}
+
+// Update holds the levels of a book changed since the subscriber's previous
+// update. Levels with a zero amount have been removed. Snapshots hold every
+// level of the book and replace it
+type Update struct {
+	Exchange  string        `json:"exchange"`
+	Pair      currency.Pair `json:"pair"`
+	AssetType string        `json:"assetType"`
+	// Seq is the book's sequence after the update and PrevSeq its sequence
+	// before it, conflated updates span more than one sequence
+	Seq      uint64           `json:"seq"`
+	PrevSeq  uint64           `json:"prevSeq"`
+	Snapshot bool             `json:"snapshot"`
+	Bids     []orderbook.Item `json:"bids"`
+	Asks     []orderbook.Item `json:"asks"`
+	// Conflated is the number of published updates merged into the update
+	Conflated int       `json:"conflated"`
+	Time      time.Time `json:"time"`
+}
+
+// pending holds a book's updates awaiting delivery to a subscriber
+type pending struct {
+	book      *book
+	seq       uint64
+	prevSeq   uint64
+	snapshot  bool
+	bids      levels
+	asks      levels
+	conflated int
+	time      time.Time
+}
+
+// merge applies a delta to the pending update
+func (p *pending) merge(bids, asks levels, seq uint64, t time.Time) {
+	apply := func(dst, delta levels) {
+		for price, amount := range delta {
+			if amount == 0 && p.snapshot {
+				delete(dst, price)
+				continue
+			}
+			dst[price] = amount
+		}
+	}
+	apply(p.bids, bids)
+	apply(p.asks, asks)
+	p.seq = seq
+	p.conflated++
+	p.time = t
+}
+
+func (p *pending) update() Update {
+	return Update{
+		Exchange:  p.book.exchange,
+		Pair:      p.book.pair,
+		AssetType: p.book.assetType,
+		Seq:       p.seq,
+		PrevSeq:   p.prevSeq,
+		Snapshot:  p.snapshot,
+		Bids:      p.bids.items(true),
+		Asks:      p.asks.items(false),
+		Conflated: p.conflated,
+		Time:      p.time,
+	}
+}
+
+// Options defines the books a subscriber receives and how fast
+type Options struct {
+	// Name identifies the subscriber in statistics, such as a strategy name
+	Name string
+	// Exchange, Pair and AssetType filter the books received, empty values
+	// match every book
+	Exchange  string
+	Pair      currency.Pair
+	AssetType string
+	// MaxRate is the most updates per second delivered per book, updates
+	// in between are conflated. Zero delivers updates as fast as they are
+	// received
+	MaxRate float64
+	// Buffer is the number of updates buffered, defaults to DefaultBuffer
+	Buffer int
+}
+
+// matches returns whether the subscriber receives the book
+func (o *Options) matches(b *book) bool {
+	if o.Exchange != "" && !strings.EqualFold(o.Exchange, b.exchange) {
+		return false
+	}
+	if !o.Pair.IsEmpty() && !o.Pair.Base.Match(b.pair.Base) {
+		return false
+	}
+	if !o.Pair.IsEmpty() && !o.Pair.Quote.Match(b.pair.Quote) {
+		return false
+	}
+	return o.AssetType == "" || strings.EqualFold(o.AssetType, b.assetType)
+}
+
+// Stats holds a subscriber's delivery statistics
+type Stats struct {
+	ID        uint64  `json:"id"`
+	Name      string  `json:"name"`
+	MaxRate   float64 `json:"maxRate"`
+	Delivered int64   `json:"delivered"`
+	// Conflated counts published updates merged into another rather than
+	// delivered on their own
+	Conflated int64 `json:"conflated"`
+	Pending   int   `json:"pending"`
+}
+
+// Subscription receives orderbook updates on C until closed
+type Subscription struct {
+	C        <-chan Update
+	c        chan Update
+	id       uint64
+	opts     Options
+	interval time.Duration
+	feed     *Feed
+	pending  map[key]*pending
+	order    []key
+	// known holds the books the subscriber has received a snapshot of
+	known  map[key]bool
+	sent   map[key]time.Time
+	stats  Stats
+	notify chan struct{}
+	done   chan struct{}
+	mtx    sync.Mutex
+}
+
+// queue merges a book's delta into the subscriber's pending updates. Books
+// the subscriber has not received are queued as snapshots
+func (s *Subscription) queue(k key, b *book, bids, asks levels, prevSeq uint64, t time.Time) {
+	s.mtx.Lock()
+	p, ok := s.pending[k]
+	switch {
+	case ok:
+		p.merge(bids, asks, b.seq, t)
+		s.stats.Conflated++
+	default:
+		p = &pending{book: b, prevSeq: prevSeq, bids: make(levels), asks: make(levels)}
+		if !s.known[k] {
+			p.snapshot = true
+			bids, asks = b.bids, b.asks
+		}
+		p.merge(bids, asks, b.seq, t)
+		s.pending[k] = p
+		s.order = append(s.order, k)
+	}
+	s.known[k] = true
+	s.mtx.Unlock()
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the first pending update due for delivery, or the delay until
+// one is due
+func (s *Subscription) next(now time.Time) (*Update, time.Duration) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var wait time.Duration
+	for i, k := range s.order {
+		if s.interval > 0 {
+			if due := s.sent[k].Add(s.interval); now.Before(due) {
+				if d := due.Sub(now); wait == 0 || d < wait {
+					wait = d
+				}
+				continue
+			}
+		}
+		p := s.pending[k]
+		delete(s.pending, k)
+		s.order = append(s.order[:i], s.order[i+1:]...)
+		s.sent[k] = now
+		s.stats.Delivered++
+		u := p.update()
+		return &u, 0
+	}
+	return nil, wait
+}
+
+// run delivers pending updates until the subscription is closed
+func (s *Subscription) run() {
+	for {
+		u, wait := s.next(time.Now())
+		if u != nil {
+			select {
+			case s.c <- *u:
+				continue
+			case <-s.done:
+				return
+			}
+		}
+		var timer *time.Timer
+		var due <-chan time.Time
+		if wait > 0 {
+			timer = time.NewTimer(wait)
+			due = timer.C
+		}
+		select {
+		case <-s.notify:
+		case <-due:
+		case <-s.done:
+		}
+		if timer != nil {
+			timer.Stop()
+		}
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+	}
+}
+
+// Stats returns the subscriber's delivery statistics
+func (s *Subscription) Stats() Stats {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	stats := s.stats
+	stats.Pending = len(s.pending)
+	return stats
+}
+
+// Close stops delivering updates to the subscriber
+func (s *Subscription) Close() {
+	s.feed.unsubscribe(s)
+}
+
+// Feed distributes published orderbooks to subscribers as deltas
+type Feed struct {
+	books  map[key]*book
+	subs   map[uint64]*Subscription
+	nextID uint64
+	mtx    sync.Mutex
+}
+
+// New returns an orderbook feed
+func New() *Feed {
+	return &Feed{
+		books: make(map[key]*book),
+		subs:  make(map[uint64]*Subscription),
+	}
+}
+
+// Publish distributes the levels of an orderbook changed since it was last
+// published to the subscribers of the book. Unchanged books are not
+// distributed
+func (f *Feed) Publish(o *orderbook.Base) error {
+	if o == nil {
+		return errFeedNil
+	}
+	if o.ExchangeName == "" {
+		return errExchangeNotSet
+	}
+	t := o.LastUpdated
+	if t.IsZero() {
+		t = time.Now()
+	}
+	k := newKey(o.ExchangeName, o.Pair, o.AssetType)
+	bids, asks := toLevels(o.Bids), toLevels(o.Asks)
+
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	prev, ok := f.books[k]
+	next := &book{
+		exchange:  o.ExchangeName,
+		pair:      o.Pair,
+		assetType: o.AssetType,
+		bids:      bids,
+		asks:      asks,
+	}
+	var bidDelta, askDelta levels
+	if ok {
+		bidDelta, askDelta = diff(prev.bids, bids), diff(prev.asks, asks)
+		if len(bidDelta) == 0 && len(askDelta) == 0 {
+			return nil
+		}
+		next.seq = prev.seq
+	} else {
+		bidDelta, askDelta = bids, asks
+	}
+	prevSeq := next.seq
+	next.seq++
+	f.books[k] = next
+
+	for _, s := range f.subs {
+		if s.opts.matches(next) {
+			s.queue(k, next, bidDelta, askDelta, prevSeq, t)
+		}
+	}
+	return nil
+}
+
+// Subscribe returns a subscription receiving the books matching the options,
+// starting with a snapshot of each book already published
+func (f *Feed) Subscribe(opts Options) (*Subscription, error) {
+	if opts.MaxRate < 0 {
+		return nil, errInvalidRate
+	}
+	if opts.Buffer <= 0 {
+		opts.Buffer = DefaultBuffer
+	}
+	c := make(chan Update, opts.Buffer)
+	s := &Subscription{
+		C:       c,
+		c:       c,
+		opts:    opts,
+		feed:    f,
+		pending: make(map[key]*pending),
+		known:   make(map[key]bool),
+		sent:    make(map[key]time.Time),
+		notify:  make(chan struct{}, 1),
+		done:    make(chan struct{}),
+	}
+	if opts.MaxRate > 0 {
+		s.interval = time.Duration(float64(time.Second) / opts.MaxRate)
+	}
+
+	f.mtx.Lock()
+	f.nextID++
+	s.id = f.nextID
+	s.stats = Stats{ID: s.id, Name: opts.Name, MaxRate: opts.MaxRate}
+	f.subs[s.id] = s
+	now := time.Now()
+	for k, b := range f.books {
+		if opts.matches(b) {
+			s.queue(k, b, nil, nil, b.seq, now)
+		}
+	}
+	f.mtx.Unlock()
+
+	go s.run()
+	return s, nil
+}
+
+func (f *Feed) unsubscribe(s *Subscription) {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	if _, ok := f.subs[s.id]; !ok {
+		return
+	}
+	delete(f.subs, s.id)
+	close(s.done)
+}
+
+// GetStats returns the delivery statistics of every subscriber
+func (f *Feed) GetStats() []Stats {
+	f.mtx.Lock()
+	subs := make([]*Subscription, 0, len(f.subs))
+	for _, s := range f.subs {
+		subs = append(subs, s)
+	}
+	f.mtx.Unlock()
+
+	stats := make([]Stats, len(subs))
+	for i := range subs {
+		stats[i] = subs[i].Stats()
+	}
+	sort.Slice(stats, func(i, j int) bool {
+		return stats[i].ID < stats[j].ID
+	})
+	return stats
+}
+
+// Book rebuilds an orderbook from a subscription's updates
+type Book struct {
+	Seq  uint64
+	bids levels
+	asks levels
+}
+
+// Apply applies an update to the book. A delta not following the book's
+// sequence returns ErrSequenceGap and is not applied
+func (b *Book) Apply(u *Update) error {
+	if u.Snapshot {
+		b.bids, b.asks = toLevels(u.Bids), toLevels(u.Asks)
+		b.Seq = u.Seq
+		return nil
+	}
+	if b.bids == nil || u.PrevSeq != b.Seq {
+		return ErrSequenceGap
+	}
+	for _, l := range []struct {
+		dst   levels
+		items []orderbook.Item
+	}{{b.bids, u.Bids}, {b.asks, u.Asks}} {
+		for i := range l.items {
+			if l.items[i].Amount == 0 {
+				delete(l.dst, l.items[i].Price)
+				continue
+			}
+			l.dst[l.items[i].Price] = l.items[i].Amount
+		}
+	}
+	b.Seq = u.Seq
+	return nil
+}
+
+// Bids returns the book's bids, best first
+func (b *Book) Bids() []orderbook.Item {
+	return b.bids.items(true)
+}
+
+// Asks returns the book's asks, best first
+func (b *Book) Asks() []orderbook.Item {
+	return b.asks.items(false)
+}
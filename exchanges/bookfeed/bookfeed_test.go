@@ -0,0 +1,155 @@
+package bookfeed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+)
+
+func testBook(exchangeName string, bids, asks []orderbook.Item) *orderbook.Base {
+	return &orderbook.Base{
+		ExchangeName: exchangeName,
+		Pair:         currency.NewPairWithDelimiter("BTC", "USD", "-"),
+		AssetType:    orderbook.Spot,
+		Bids:         bids,
+		Asks:         asks,
+	}
+}
+
+func receive(t *testing.T, s *Subscription) Update {
+	select {
+	case u := <-s.C:
+		return u
+	case <-time.After(time.Second):
+		t.Fatal("Test Failed - no update received")
+	}
+	return Update{}
+}
+
+func TestPublish(t *testing.T) {
+	f := New()
+	if err := f.Publish(nil); err != errFeedNil {
+		t.Error("Test Failed - Publish() expected nil error", err)
+	}
+	err := f.Publish(testBook("bitstamp",
+		[]orderbook.Item{{Price: 99, Amount: 1}, {Price: 98, Amount: 2}},
+		[]orderbook.Item{{Price: 101, Amount: 1}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	s, err := f.Subscribe(Options{Name: "test", Exchange: "Bitstamp"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	u := receive(t, s)
+	if !u.Snapshot || u.Seq != 1 || len(u.Bids) != 2 || u.Bids[0].Price != 99 || len(u.Asks) != 1 {
+		t.Fatalf("Test Failed - Subscribe() expected snapshot of published book %+v", u)
+	}
+	var b Book
+	if err = b.Apply(&u); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only changed levels are sent, removed levels with a zero amount
+	err = f.Publish(testBook("bitstamp",
+		[]orderbook.Item{{Price: 99, Amount: 1}, {Price: 97, Amount: 3}},
+		[]orderbook.Item{{Price: 101, Amount: 1}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	u = receive(t, s)
+	if u.Snapshot || u.Seq != 2 || u.PrevSeq != 1 || len(u.Asks) != 0 || len(u.Bids) != 2 ||
+		u.Bids[0] != (orderbook.Item{Price: 98}) || u.Bids[1] != (orderbook.Item{Price: 97, Amount: 3}) {
+		t.Fatalf("Test Failed - Publish() unexpected delta %+v", u)
+	}
+	if err = b.Apply(&u); err != nil {
+		t.Fatal(err)
+	}
+	bids := b.Bids()
+	if b.Seq != 2 || len(bids) != 2 || bids[1].Price != 97 || len(b.Asks()) != 1 {
+		t.Errorf("Test Failed - Apply() unexpected book %+v", bids)
+	}
+	if err = b.Apply(&Update{Seq: 5, PrevSeq: 4}); err != ErrSequenceGap {
+		t.Error("Test Failed - Apply() expected sequence gap", err)
+	}
+
+	// Unchanged books and books of other exchanges are not sent
+	err = f.Publish(testBook("bitstamp",
+		[]orderbook.Item{{Price: 99, Amount: 1}, {Price: 97, Amount: 3}},
+		[]orderbook.Item{{Price: 101, Amount: 1}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = f.Publish(testBook("kraken", []orderbook.Item{{Price: 99, Amount: 1}}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case u = <-s.C:
+		t.Errorf("Test Failed - Publish() unexpected update %+v", u)
+	case <-time.After(time.Millisecond * 50):
+	}
+}
+
+func TestConflation(t *testing.T) {
+	f := New()
+	s, err := f.Subscribe(Options{MaxRate: 5})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer s.Close()
+
+	err = f.Publish(testBook("bitstamp", []orderbook.Item{{Price: 99, Amount: 1}}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b Book
+	u := receive(t, s)
+	if err = b.Apply(&u); err != nil {
+		t.Fatal(err)
+	}
+
+	// Updates within the rate limit are merged into one delta
+	for _, amount := range []float64{2, 3, 4} {
+		err = f.Publish(testBook("bitstamp", []orderbook.Item{{Price: 99, Amount: amount}, {Price: 98, Amount: 1}}, nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	err = f.Publish(testBook("bitstamp", []orderbook.Item{{Price: 99, Amount: 4}}, nil))
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Now()
+	u = receive(t, s)
+	if time.Since(start) < time.Millisecond*100 {
+		t.Error("Test Failed - Subscribe() update delivered faster than max rate")
+	}
+	if u.Seq != 5 || u.PrevSeq != 1 || u.Conflated != 4 {
+		t.Fatalf("Test Failed - Subscribe() expected conflated update %+v", u)
+	}
+	if err = b.Apply(&u); err != nil {
+		t.Fatal(err)
+	}
+	bids := b.Bids()
+	if len(bids) != 1 || bids[0].Amount != 4 {
+		t.Errorf("Test Failed - Apply() unexpected conflated book %+v", bids)
+	}
+
+	stats := f.GetStats()
+	if len(stats) != 1 || stats[0].Delivered != 2 || stats[0].Conflated != 3 {
+		t.Errorf("Test Failed - GetStats() unexpected stats %+v", stats)
+	}
+	s.Close()
+	if len(f.GetStats()) != 0 {
+		t.Error("Test Failed - Close() subscriber not removed")
+	}
+	if _, err = f.Subscribe(Options{MaxRate: -1}); err != errInvalidRate {
+		t.Error("Test Failed - Subscribe() expected invalid rate error", err)
+	}
+}
@@ -0,0 +1,37 @@
+package exchange
+
+import (
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// Year is the period borrow rates are annualised over
+const Year = time.Hour * 24 * 365
+
+// BorrowRate holds the cost of borrowing a currency for margin trading
+type BorrowRate struct {
+	Exchange string
+	Currency currency.Code
+	// Market is set when the rate only applies to a specific margin market
+	Market string
+	// Rate is the fractional interest charged per Period
+	Rate      float64
+	Period    time.Duration
+	Available float64
+	Timestamp time.Time
+}
+
+// Annualised returns the simple annual interest rate as a fraction
+func (b *BorrowRate) Annualised() float64 {
+	if b.Period <= 0 {
+		return 0
+	}
+	return b.Rate * float64(Year) / float64(b.Period)
+}
+
+// BorrowRateProvider is implemented by exchanges which expose the rates
+// charged to borrow currencies for margin trading
+type BorrowRateProvider interface {
+	GetBorrowRates(c currency.Code) ([]BorrowRate, error)
+}
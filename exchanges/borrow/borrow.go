@@ -0,0 +1,196 @@
+// Package borrow compares the rates charged to borrow currencies for margin
+// short strategies across exchanges and alerts when rates spike above the
+// level a strategy remains profitable at
+package borrow
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// DefaultCheckInterval is the delay between borrow rate checks
+const DefaultCheckInterval = time.Minute * 15
+
+var (
+	errNoProviders      = errors.New("no exchanges provide borrow rates")
+	errNoRates          = errors.New("no borrow rates available")
+	errNoThresholds     = errors.New("no borrow rate thresholds set")
+	errInvalidThreshold = errors.New("borrow rate threshold must be positive")
+	errNilAlertHandler  = errors.New("borrow rate alert handler is nil")
+)
+
+// Compare returns the borrow rates for the currency across all exchanges
+// which provide them, ordered from cheapest to most expensive annualised rate
+func Compare(exchanges []exchange.IBotExchange, c currency.Code) ([]exchange.BorrowRate, error) {
+	var rates []exchange.BorrowRate
+	var providers int
+	for i := range exchanges {
+		p, ok := exchange.Underlying(exchanges[i]).(exchange.BorrowRateProvider)
+		if !ok || !exchanges[i].IsEnabled() {
+			continue
+		}
+		providers++
+		r, err := p.GetBorrowRates(c)
+		if err != nil {
+			log.Warnf("Borrow rates unavailable for %s %s: %s",
+				exchanges[i].GetName(), c, err)
+			continue
+		}
+		rates = append(rates, r...)
+	}
+
+	if providers == 0 {
+		return nil, errNoProviders
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%s %v", c, errNoRates)
+	}
+	sort.SliceStable(rates, func(i, j int) bool {
+		return rates[i].Annualised() < rates[j].Annualised()
+	})
+	return rates, nil
+}
+
+// Alert is raised when a borrow rate rises above its threshold
+type Alert struct {
+	Rate exchange.BorrowRate
+	// Threshold is the maximum annualised rate as a fraction
+	Threshold float64
+}
+
+// String implements the stringer interface
+func (a *Alert) String() string {
+	market := a.Rate.Exchange
+	if a.Rate.Market != "" {
+		market += " " + a.Rate.Market
+	}
+	return fmt.Sprintf("%s %s borrow rate %.2f%% annualised exceeds threshold %.2f%%",
+		market,
+		a.Rate.Currency,
+		a.Rate.Annualised()*100,
+		a.Threshold*100)
+}
+
+// Monitor periodically checks borrow rates against per currency thresholds
+type Monitor struct {
+	exchanges  []exchange.IBotExchange
+	thresholds map[currency.Code]float64
+	onAlert    func(Alert)
+	alerted    map[string]bool
+	latest     map[currency.Code][]exchange.BorrowRate
+	shutdown   chan struct{}
+	wg         sync.WaitGroup
+	mtx        sync.Mutex
+}
+
+// NewMonitor returns a borrow rate monitor. Thresholds are maximum annualised
+// rates expressed as fractions
+func NewMonitor(exchanges []exchange.IBotExchange, thresholds map[currency.Code]float64, onAlert func(Alert)) (*Monitor, error) {
+	if len(thresholds) == 0 {
+		return nil, errNoThresholds
+	}
+	if onAlert == nil {
+		return nil, errNilAlertHandler
+	}
+	t := make(map[currency.Code]float64, len(thresholds))
+	for c, v := range thresholds {
+		if v <= 0 {
+			return nil, fmt.Errorf("%s %v", c, errInvalidThreshold)
+		}
+		t[c] = v
+	}
+	return &Monitor{
+		exchanges:  exchanges,
+		thresholds: t,
+		onAlert:    onAlert,
+		alerted:    make(map[string]bool),
+		latest:     make(map[currency.Code][]exchange.BorrowRate),
+	}, nil
+}
+
+// Check fetches the latest rates and raises an alert for each rate which has
+// risen above its threshold since the previous check
+func (m *Monitor) Check() {
+	var alerts []Alert
+	m.mtx.Lock()
+	for c, threshold := range m.thresholds {
+		rates, err := Compare(m.exchanges, c)
+		if err != nil {
+			log.Warnf("Borrow rate monitor %s: %s", c, err)
+			continue
+		}
+		m.latest[c] = rates
+		for i := range rates {
+			k := strings.ToLower(rates[i].Exchange + "_" + rates[i].Market + "_" + c.String())
+			above := rates[i].Annualised() > threshold
+			if above && !m.alerted[k] {
+				alerts = append(alerts, Alert{Rate: rates[i], Threshold: threshold})
+			}
+			m.alerted[k] = above
+		}
+	}
+	m.mtx.Unlock()
+
+	for i := range alerts {
+		m.onAlert(alerts[i])
+	}
+}
+
+// GetRates returns the rates fetched by the latest check for the currency
+func (m *Monitor) GetRates(c currency.Code) []exchange.BorrowRate {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return append([]exchange.BorrowRate(nil), m.latest[c]...)
+}
+
+// Start checks borrow rates at the supplied interval until stopped
+func (m *Monitor) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		m.Check()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-t.C:
+				m.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops the monitor
+func (m *Monitor) Stop() {
+	m.mtx.Lock()
+	if m.shutdown == nil {
+		m.mtx.Unlock()
+		return
+	}
+	close(m.shutdown)
+	m.shutdown = nil
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
@@ -0,0 +1,129 @@
+package borrow
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	name string
+}
+
+func (t *testExchange) GetName() string { return t.name }
+func (t *testExchange) IsEnabled() bool { return true }
+
+type testProvider struct {
+	testExchange
+	rates []exchange.BorrowRate
+	err   error
+}
+
+func (t *testProvider) GetBorrowRates(c currency.Code) ([]exchange.BorrowRate, error) {
+	return t.rates, t.err
+}
+
+func newTestProvider(name string, rate float64, period time.Duration) *testProvider {
+	return &testProvider{
+		testExchange: testExchange{name: name},
+		rates: []exchange.BorrowRate{{
+			Exchange: name,
+			Currency: currency.BTC,
+			Rate:     rate,
+			Period:   period,
+		}},
+	}
+}
+
+func TestAnnualised(t *testing.T) {
+	r := exchange.BorrowRate{Rate: 0.0001, Period: time.Hour * 24}
+	if a := r.Annualised(); a < 0.0365-1e-9 || a > 0.0365+1e-9 {
+		t.Error("Test Failed - Annualised() unexpected rate", a)
+	}
+	r.Period = 0
+	if r.Annualised() != 0 {
+		t.Error("Test Failed - Annualised() expected zero for unset period")
+	}
+}
+
+func TestCompare(t *testing.T) {
+	_, err := Compare([]exchange.IBotExchange{&testExchange{name: "plain"}}, currency.BTC)
+	if err != errNoProviders {
+		t.Error("Test Failed - Compare() expected no providers error", err)
+	}
+
+	failing := newTestProvider("failing", 0, 0)
+	failing.err = errors.New("unavailable")
+	_, err = Compare([]exchange.IBotExchange{failing}, currency.BTC)
+	if err == nil {
+		t.Error("Test Failed - Compare() expected no rates error")
+	}
+
+	rates, err := Compare([]exchange.IBotExchange{
+		newTestProvider("daily", 0.0003, time.Hour*24), // 10.95%
+		&testExchange{name: "plain"},
+		exchange.NewReadOnly(newTestProvider("yearly", 0.05, exchange.Year)), // 5%
+		failing,
+	}, currency.BTC)
+	if err != nil {
+		t.Fatal("Test Failed - Compare() error", err)
+	}
+	if len(rates) != 2 || rates[0].Exchange != "yearly" || rates[1].Exchange != "daily" {
+		t.Errorf("Test Failed - Compare() unexpected ordering %+v", rates)
+	}
+}
+
+func TestMonitor(t *testing.T) {
+	p := newTestProvider("test", 0.0001, time.Hour*24)
+	var alerts []Alert
+	onAlert := func(a Alert) { alerts = append(alerts, a) }
+
+	if _, err := NewMonitor(nil, nil, onAlert); err != errNoThresholds {
+		t.Error("Test Failed - NewMonitor() expected no thresholds error", err)
+	}
+	if _, err := NewMonitor(nil, map[currency.Code]float64{currency.BTC: -1}, onAlert); err == nil {
+		t.Error("Test Failed - NewMonitor() expected invalid threshold error")
+	}
+	if _, err := NewMonitor(nil, map[currency.Code]float64{currency.BTC: 1}, nil); err != errNilAlertHandler {
+		t.Error("Test Failed - NewMonitor() expected nil handler error", err)
+	}
+
+	m, err := NewMonitor([]exchange.IBotExchange{p}, map[currency.Code]float64{currency.BTC: 0.2}, onAlert)
+	if err != nil {
+		t.Fatal("Test Failed - NewMonitor() error", err)
+	}
+
+	m.Check()
+	if len(alerts) != 0 || len(m.GetRates(currency.BTC)) != 1 {
+		t.Fatal("Test Failed - Check() expected no alert below threshold")
+	}
+
+	// 0.1% daily is 36.5% annualised
+	p.rates[0].Rate = 0.001
+	m.Check()
+	m.Check()
+	if len(alerts) != 1 {
+		t.Fatalf("Test Failed - Check() expected a single alert for a spike, received %d", len(alerts))
+	}
+	if alerts[0].String() != "test BTC borrow rate 36.50% annualised exceeds threshold 20.00%" {
+		t.Error("Test Failed - Alert String() unexpected", alerts[0].String())
+	}
+
+	// Falling back below the threshold rearms the alert
+	p.rates[0].Rate = 0.0001
+	m.Check()
+	p.rates[0].Rate = 0.001
+	m.Check()
+	if len(alerts) != 2 {
+		t.Errorf("Test Failed - Check() expected alert to rearm, received %d alerts", len(alerts))
+	}
+
+	m.Start(time.Hour)
+	m.Start(time.Hour)
+	m.Stop()
+	m.Stop()
+}
@@ -0,0 +1,85 @@
+package exchange
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+var (
+	errBracketSide       = errors.New("bracket side must be buy or sell")
+	errBracketAmount     = errors.New("bracket amount must be positive")
+	errBracketNoExits    = errors.New("bracket requires a take profit or stop loss")
+	errBracketTakeProfit = errors.New("bracket take profit must be beyond the entry price")
+	errBracketStopLoss   = errors.New("bracket stop loss must be behind the entry price")
+	errBracketInverted   = errors.New("bracket stop loss must be behind the take profit")
+)
+
+// BracketOrder defines an entry order with a take profit and stop loss which
+// only activate once the entry fills and cancel each other
+type BracketOrder struct {
+	Pair   currency.Pair `json:"pair"`
+	Side   OrderSide     `json:"side"`
+	Amount float64       `json:"amount"`
+	// EntryPrice is the entry limit price, a market order is placed when zero
+	EntryPrice float64 `json:"entryPrice"`
+	// TakeProfit is the limit price the position is closed at in profit, zero
+	// when not set
+	TakeProfit float64 `json:"takeProfit"`
+	// StopLoss is the trigger price the position is closed at in loss, zero
+	// when not set
+	StopLoss float64 `json:"stopLoss"`
+}
+
+// BracketOrderResponse holds the IDs of the orders placed for a bracket
+type BracketOrderResponse struct {
+	EntryID      string `json:"entryID"`
+	TakeProfitID string `json:"takeProfitID,omitempty"`
+	StopLossID   string `json:"stopLossID,omitempty"`
+}
+
+// BracketSubmitter is implemented by exchanges which link the orders of a
+// bracket natively, the link ID is sent with each order
+type BracketSubmitter interface {
+	SubmitBracketOrder(b *BracketOrder, linkID string) (BracketOrderResponse, error)
+}
+
+// ExitSide returns the side of the take profit and stop loss orders
+func (b *BracketOrder) ExitSide() OrderSide {
+	if b.Side == SellOrderSide {
+		return BuyOrderSide
+	}
+	return SellOrderSide
+}
+
+// Validate checks the bracket is complete and its exit prices lie either side
+// of the entry price
+func (b *BracketOrder) Validate() error {
+	if b.Side != BuyOrderSide && b.Side != SellOrderSide {
+		return errBracketSide
+	}
+	if b.Amount <= 0 {
+		return errBracketAmount
+	}
+	if b.TakeProfit <= 0 && b.StopLoss <= 0 {
+		return errBracketNoExits
+	}
+
+	// Prices are compared as if long, a short bracket mirrors them
+	sign := 1.0
+	if b.Side == SellOrderSide {
+		sign = -1
+	}
+	if b.EntryPrice > 0 {
+		if b.TakeProfit > 0 && sign*(b.TakeProfit-b.EntryPrice) <= 0 {
+			return errBracketTakeProfit
+		}
+		if b.StopLoss > 0 && sign*(b.EntryPrice-b.StopLoss) <= 0 {
+			return errBracketStopLoss
+		}
+	}
+	if b.TakeProfit > 0 && b.StopLoss > 0 && sign*(b.TakeProfit-b.StopLoss) <= 0 {
+		return errBracketInverted
+	}
+	return nil
+}
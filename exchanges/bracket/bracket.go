@@ -0,0 +1,724 @@
+// Package bracket manages bracket orders: an entry order with a take profit
+// and stop loss which activate once the entry fills and cancel each other.
+// Exchanges supporting linked orders manage the bracket natively, elsewhere the
+// take profit is placed as a limit order once the entry fills. The stop loss is
+// then held exchange side where stop orders are supported, falling back to a
+// client side trigger from the last price. Brackets are persisted so open
+// brackets resume management after a restart
+package bracket
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// DefaultCheckInterval is how often open brackets are checked
+const DefaultCheckInterval = time.Second * 10
+
+// State defines the stage of a bracket
+type State string
+
+// Bracket states
+const (
+	// PendingEntry brackets wait for their entry order to fill
+	PendingEntry State = "PENDING_ENTRY"
+	// Active brackets hold a position protected by their exits
+	Active State = "ACTIVE"
+	// StopTriggered brackets have reached their stop loss and retry the exit
+	// until it is placed
+	StopTriggered State = "STOP_TRIGGERED"
+	Closed        State = "CLOSED"
+	Cancelled     State = "CANCELLED"
+)
+
+// Exits which close a bracket
+const (
+	TakeProfit = "TAKE_PROFIT"
+	StopLoss   = "STOP_LOSS"
+)
+
+// Event types emitted by the manager
+const (
+	Activated = "BRACKET_ACTIVATED"
+	Exited    = "BRACKET_CLOSED"
+	Failed    = "BRACKET_FAILED"
+)
+
+var (
+	errNoExchanges       = errors.New("no exchange lookup supplied")
+	errNoPriceFunc       = errors.New("no price function supplied")
+	errExchangeNotLoaded = errors.New("exchange not loaded")
+	errBracketNotFound   = errors.New("bracket not found")
+	errBracketDone       = errors.New("bracket already closed")
+	errNotAcknowledged   = errors.New("order not acknowledged")
+	errNoExchangeStops   = errors.New("exchange no longer supports stop orders")
+)
+
+// Bracket holds a bracket order and the state of its orders
+type Bracket struct {
+	ID       string                `json:"id"`
+	Exchange string                `json:"exchange"`
+	Order    exchange.BracketOrder `json:"order"`
+	// Native brackets are linked and managed by the exchange
+	Native       bool   `json:"native"`
+	State        State  `json:"state"`
+	EntryID      string `json:"entryID"`
+	TakeProfitID string `json:"takeProfitID,omitempty"`
+	StopLossID   string `json:"stopLossID,omitempty"`
+	// ExchangeStop is set while the stop loss is held exchange side as a stop
+	// order for the stop amount
+	ExchangeStop bool    `json:"exchangeStop,omitempty"`
+	StopAmount   float64 `json:"stopAmount,omitempty"`
+	// Filled is the entry amount filled and protected by the exits
+	Filled   float64   `json:"filled"`
+	ClosedBy string    `json:"closedBy,omitempty"`
+	Error    string    `json:"error,omitempty"`
+	Created  time.Time `json:"created"`
+	Updated  time.Time `json:"updated"`
+}
+
+// Open returns whether the bracket is still managed
+func (b *Bracket) Open() bool {
+	return b.State != Closed && b.State != Cancelled
+}
+
+// Event defines a bracket activating, closing or failing
+type Event struct {
+	Type    string
+	Bracket Bracket
+	Detail  string
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	return fmt.Sprintf("%s bracket %s %s %s: %s", e.Bracket.Exchange, e.Bracket.ID,
+		e.Bracket.Order.Pair, e.Type, e.Detail)
+}
+
+// ExchangeFunc returns a loaded exchange by name, nil when not loaded
+type ExchangeFunc func(name string) exchange.IBotExchange
+
+// PriceFunc returns the last price of a pair on an exchange
+type PriceFunc func(exchangeName string, p currency.Pair) (float64, error)
+
+// Manager submits bracket orders and manages them until they close
+type Manager struct {
+	path      string
+	exchanges ExchangeFunc
+	prices    PriceFunc
+	onEvent   func(Event)
+	brackets  map[string]*Bracket
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+	mtx       sync.Mutex
+	// checkMtx serialises the order changes of checks and cancellations
+	checkMtx sync.Mutex
+}
+
+// New returns a bracket manager persisting brackets to path. A missing file
+// starts with no brackets, an empty path keeps brackets in memory only
+func New(path string, exchanges ExchangeFunc, prices PriceFunc, onEvent func(Event)) (*Manager, error) {
+	if exchanges == nil {
+		return nil, errNoExchanges
+	}
+	if prices == nil {
+		return nil, errNoPriceFunc
+	}
+	m := &Manager{
+		path:      path,
+		exchanges: exchanges,
+		prices:    prices,
+		onEvent:   onEvent,
+		brackets:  make(map[string]*Bracket),
+	}
+	if path == "" {
+		return m, nil
+	}
+
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, err
+	}
+	var brackets []*Bracket
+	err = json.Unmarshal(data, &brackets)
+	if err != nil {
+		return nil, err
+	}
+	for i := range brackets {
+		m.brackets[brackets[i].ID] = brackets[i]
+	}
+	return m, nil
+}
+
+// save writes the brackets to the manager's file, the caller must hold the
+// lock
+func (m *Manager) save() error {
+	if m.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(m.list(), "", " ")
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(m.path, data)
+}
+
+func (m *Manager) list() []Bracket {
+	brackets := make([]Bracket, 0, len(m.brackets))
+	for _, b := range m.brackets {
+		brackets = append(brackets, *b)
+	}
+	sort.Slice(brackets, func(i, j int) bool {
+		return brackets[i].Created.Before(brackets[j].Created)
+	})
+	return brackets
+}
+
+// store records the bracket and persists the manager's brackets
+func (m *Manager) store(b *Bracket) {
+	b.Updated = time.Now()
+	stored := *b
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.brackets[b.ID] = &stored
+	if err := m.save(); err != nil {
+		log.Errorf("Bracket manager failed to save brackets: %s", err)
+	}
+}
+
+// Submit places the entry order of a bracket. Exchanges supporting linked
+// orders receive the whole bracket natively
+func (m *Manager) Submit(exchName string, order *exchange.BracketOrder) (Bracket, error) {
+	err := order.Validate()
+	if err != nil {
+		return Bracket{}, err
+	}
+	exch := m.exchanges(exchName)
+	if exch == nil {
+		return Bracket{}, fmt.Errorf("%s %v", exchName, errExchangeNotLoaded)
+	}
+	id, err := common.GetRandomSalt(nil, 8)
+	if err != nil {
+		return Bracket{}, err
+	}
+
+	b := Bracket{
+		ID:       common.HexEncodeToString(id),
+		Exchange: exch.GetName(),
+		Order:    *order,
+		State:    PendingEntry,
+		Created:  time.Now(),
+	}
+	orderType := exchange.LimitOrderType
+	if order.EntryPrice == 0 {
+		orderType = exchange.MarketOrderType
+	}
+	// Brackets are linked natively once the entry passes the checks of every
+	// wrapper guarding the exchange, otherwise they are managed client side
+	var native exchange.IBotExchange
+	if _, ok := exchange.Underlying(exch).(exchange.BracketSubmitter); ok {
+		entry := exchange.OrderSubmission{
+			Pair:      order.Pair,
+			Side:      order.Side,
+			OrderType: orderType,
+			Amount:    order.Amount,
+			Price:     order.EntryPrice,
+			ClientID:  b.ID,
+		}
+		native, err = exchange.Native(exch, &entry)
+		if err != nil {
+			return Bracket{}, err
+		}
+		b.Order.Amount, b.Order.EntryPrice = entry.Amount, entry.Price
+	}
+	if s, ok := native.(exchange.BracketSubmitter); ok {
+		b.Native = true
+		var resp exchange.BracketOrderResponse
+		resp, err = s.SubmitBracketOrder(&b.Order, b.ID)
+		b.EntryID, b.TakeProfitID, b.StopLossID = resp.EntryID, resp.TakeProfitID, resp.StopLossID
+	} else {
+		var resp exchange.SubmitOrderResponse
+		resp, err = exch.SubmitOrder(order.Pair, order.Side, orderType,
+			order.Amount, order.EntryPrice, b.ID)
+		b.EntryID = resp.OrderID
+		if err == nil && b.EntryID == "" {
+			err = errNotAcknowledged
+		}
+	}
+	if err != nil {
+		if b.EntryID == "" {
+			return Bracket{}, err
+		}
+		// The entry was placed so the bracket is still managed
+		b.Error = err.Error()
+	}
+	m.store(&b)
+	log.Debugf("%s bracket %s submitted %s %v %s entry %v take profit %v stop loss %v native %v",
+		b.Exchange, b.ID, order.Side, order.Amount, order.Pair, order.EntryPrice,
+		order.TakeProfit, order.StopLoss, b.Native)
+	return b, nil
+}
+
+// Cancel cancels the bracket's open orders and stops managing it. A filled
+// entry's position is left open without its exits
+func (m *Manager) Cancel(id string) (Bracket, error) {
+	m.checkMtx.Lock()
+	defer m.checkMtx.Unlock()
+
+	b, err := m.Get(id)
+	if err != nil {
+		return Bracket{}, err
+	}
+	if !b.Open() {
+		return b, fmt.Errorf("%s %v", id, errBracketDone)
+	}
+	exch := m.exchanges(b.Exchange)
+	if exch == nil {
+		return b, fmt.Errorf("%s %v", b.Exchange, errExchangeNotLoaded)
+	}
+
+	var orderIDs []string
+	if b.State == PendingEntry {
+		orderIDs = append(orderIDs, b.EntryID)
+	}
+	if b.State == PendingEntry && b.Native || b.State == Active {
+		orderIDs = append(orderIDs, b.TakeProfitID)
+		if !b.ExchangeStop {
+			orderIDs = append(orderIDs, b.StopLossID)
+		}
+	}
+	var errs []string
+	for i := range orderIDs {
+		if orderIDs[i] == "" {
+			continue
+		}
+		err = exch.CancelOrder(&exchange.OrderCancellation{
+			OrderID:      orderIDs[i],
+			CurrencyPair: b.Order.Pair,
+			Side:         b.Order.Side,
+		})
+		if err != nil {
+			errs = append(errs, orderIDs[i]+": "+err.Error())
+		}
+	}
+	if b.ExchangeStop {
+		stopID := b.StopLossID
+		if err = cancelStop(exch, &b); err != nil {
+			errs = append(errs, stopID+": "+err.Error())
+		}
+	}
+	if len(errs) > 0 {
+		b.Error = strings.Join(errs, ", ")
+		m.store(&b)
+		return b, fmt.Errorf("%s bracket %s failed to cancel %s", b.Exchange, id, b.Error)
+	}
+	b.State = Cancelled
+	b.Error = ""
+	m.store(&b)
+	return b, nil
+}
+
+// Get returns a bracket by ID
+func (m *Manager) Get(id string) (Bracket, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	b, ok := m.brackets[id]
+	if !ok {
+		return Bracket{}, fmt.Errorf("%s %v", id, errBracketNotFound)
+	}
+	return *b, nil
+}
+
+// List returns every bracket, oldest first
+func (m *Manager) List() []Bracket {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.list()
+}
+
+// Check advances every open bracket
+func (m *Manager) Check() {
+	m.checkMtx.Lock()
+	defer m.checkMtx.Unlock()
+
+	for _, b := range m.List() {
+		if !b.Open() {
+			continue
+		}
+		exch := m.exchanges(b.Exchange)
+		if exch == nil {
+			log.Debugf("Bracket manager skipping %s bracket %s: %v", b.Exchange, b.ID, errExchangeNotLoaded)
+			continue
+		}
+		prev := b
+		events := m.check(exch, &b)
+		if b != prev {
+			m.store(&b)
+		}
+		for i := range events {
+			events[i].Bracket = b
+			log.Debugln(events[i].String())
+			if m.onEvent != nil {
+				m.onEvent(events[i])
+			}
+		}
+	}
+}
+
+// check advances a bracket, returning the events raised
+func (m *Manager) check(exch exchange.IBotExchange, b *Bracket) []Event {
+	switch b.State {
+	case PendingEntry:
+		return m.checkEntry(exch, b)
+	case Active:
+		if b.Native {
+			return m.checkNativeExits(exch, b)
+		}
+		return m.checkExits(exch, b)
+	case StopTriggered:
+		return m.stop(exch, b, 0)
+	}
+	return nil
+}
+
+func (m *Manager) checkEntry(exch exchange.IBotExchange, b *Bracket) []Event {
+	o, err := exch.GetOrderInfo(b.EntryID)
+	if err != nil {
+		b.Error = err.Error()
+		return nil
+	}
+	b.Error = ""
+	switch {
+	case o.Filled():
+		b.Filled = o.ExecutedAmount
+		if b.Filled == 0 {
+			b.Filled = b.Order.Amount
+		}
+	case o.Closed() && o.ExecutedAmount > 0:
+		// Partially filled entries protect the amount filled
+		b.Filled = o.ExecutedAmount
+	case o.Closed():
+		b.State = Cancelled
+		return []Event{{Type: Failed, Detail: "entry order " + o.Status}}
+	default:
+		return nil
+	}
+
+	b.State = Active
+	events := []Event{{Type: Activated, Detail: fmt.Sprintf("entry filled %v", b.Filled)}}
+	if !b.Native {
+		events = append(events, m.placeTakeProfit(exch, b)...)
+		placeStop(exch, b, b.Filled)
+	}
+	return events
+}
+
+// placeStop holds the stop loss of a client side bracket exchange side where
+// stop orders are supported and pass the checks of every wrapper guarding the
+// exchange, otherwise it stays triggered client side
+func placeStop(exch exchange.IBotExchange, b *Bracket, amount float64) {
+	if _, ok := exchange.Underlying(exch).(exchange.StopOrderSubmitter); !ok || b.Order.StopLoss <= 0 {
+		return
+	}
+	stop := exchange.OrderSubmission{
+		Pair:      b.Order.Pair,
+		Side:      b.Order.ExitSide(),
+		OrderType: exchange.StopOrderType,
+		Amount:    amount,
+		Price:     b.Order.StopLoss,
+		ClientID:  b.ID + "-sl",
+	}
+	native, err := exchange.Native(exch, &stop)
+	if err != nil {
+		log.Warnf("%s bracket %s stop loss triggered client side, exchange stop order rejected: %s",
+			b.Exchange, b.ID, err)
+		return
+	}
+	s, ok := native.(exchange.StopOrderSubmitter)
+	if !ok {
+		return
+	}
+	id, err := s.SubmitStopOrder(stop.Pair, stop.Side, stop.Amount, stop.Price)
+	if err == nil && id == "" {
+		err = errNotAcknowledged
+	}
+	if err != nil {
+		log.Warnf("%s bracket %s stop loss triggered client side, exchange stop order failed: %s",
+			b.Exchange, b.ID, err)
+		return
+	}
+	b.StopLossID = id
+	b.ExchangeStop = true
+	b.StopAmount = stop.Amount
+}
+
+// cancelStop cancels a stop loss held exchange side
+func cancelStop(exch exchange.IBotExchange, b *Bracket) error {
+	s, ok := exchange.Underlying(exch).(exchange.StopOrderSubmitter)
+	if !ok {
+		return errNoExchangeStops
+	}
+	err := s.CancelStopOrder(b.Order.Pair, b.StopLossID)
+	if err != nil {
+		return err
+	}
+	b.StopLossID = ""
+	b.ExchangeStop = false
+	b.StopAmount = 0
+	return nil
+}
+
+// placeTakeProfit places the take profit limit order of a client side bracket
+func (m *Manager) placeTakeProfit(exch exchange.IBotExchange, b *Bracket) []Event {
+	if b.Order.TakeProfit <= 0 || b.TakeProfitID != "" {
+		return nil
+	}
+	resp, err := exch.SubmitOrder(b.Order.Pair, b.Order.ExitSide(), exchange.LimitOrderType,
+		b.Filled, b.Order.TakeProfit, b.ID+"-tp")
+	if err == nil && resp.OrderID == "" {
+		err = errNotAcknowledged
+	}
+	if err != nil {
+		// Retried on the next check, the stop loss still protects the position
+		b.Error = "take profit: " + err.Error()
+		return []Event{{Type: Failed, Detail: b.Error}}
+	}
+	b.TakeProfitID = resp.OrderID
+	return nil
+}
+
+func (m *Manager) checkExits(exch exchange.IBotExchange, b *Bracket) []Event {
+	if b.Order.TakeProfit > 0 && b.TakeProfitID == "" {
+		if events := m.placeTakeProfit(exch, b); len(events) > 0 {
+			return append(events, m.checkStopLoss(exch, b, 0)...)
+		}
+	}
+
+	var exited float64
+	if b.TakeProfitID != "" {
+		o, err := exch.GetOrderInfo(b.TakeProfitID)
+		if err != nil {
+			b.Error = err.Error()
+			return nil
+		}
+		if o.Filled() {
+			if b.ExchangeStop {
+				// Retried until cancelled or the stop would reopen the position
+				if err = cancelStop(exch, b); err != nil {
+					b.Error = "cancel stop loss: " + err.Error()
+					return []Event{{Type: Failed, Detail: b.Error}}
+				}
+			}
+			b.State = Closed
+			b.ClosedBy = TakeProfit
+			b.Error = ""
+			return []Event{{Type: Exited, Detail: fmt.Sprintf("take profit filled at %v", o.Price)}}
+		}
+		exited = o.ExecutedAmount
+	}
+	b.Error = ""
+	return m.checkStopLoss(exch, b, exited)
+}
+
+// checkStopLoss checks the stop loss wherever it is held
+func (m *Manager) checkStopLoss(exch exchange.IBotExchange, b *Bracket, exited float64) []Event {
+	if b.ExchangeStop {
+		return m.checkExchangeStop(exch, b, exited)
+	}
+	return m.checkStop(exch, b, exited)
+}
+
+// checkExchangeStop records a stop loss triggered exchange side, resizing it
+// as the take profit partially fills. A stop cancelled outside the bot falls
+// back to the client side trigger
+func (m *Manager) checkExchangeStop(exch exchange.IBotExchange, b *Bracket, exited float64) []Event {
+	s, ok := exchange.Underlying(exch).(exchange.StopOrderSubmitter)
+	if !ok {
+		b.Error = "stop loss: " + errNoExchangeStops.Error()
+		return nil
+	}
+	stop, err := s.GetStopOrder(b.Order.Pair, b.StopLossID)
+	if err != nil {
+		b.Error = "stop loss: " + err.Error()
+		return nil
+	}
+
+	switch {
+	case stop.Triggered:
+		b.State = Closed
+		b.ClosedBy = StopLoss
+		detail := fmt.Sprintf("stop loss %v triggered exchange side", b.Order.StopLoss)
+		if b.TakeProfitID != "" {
+			err = exch.CancelOrder(&exchange.OrderCancellation{
+				OrderID:      b.TakeProfitID,
+				CurrencyPair: b.Order.Pair,
+				Side:         b.Order.ExitSide(),
+			})
+			if err != nil {
+				b.Error = "cancel take profit: " + err.Error()
+				detail += ", " + b.Error
+			}
+		}
+		return []Event{{Type: Exited, Detail: detail}}
+	case stop.Cancelled:
+		log.Warnf("%s bracket %s exchange stop order %s cancelled, stop loss triggered client side",
+			b.Exchange, b.ID, b.StopLossID)
+		b.StopLossID = ""
+		b.ExchangeStop = false
+		b.StopAmount = 0
+		return m.checkStop(exch, b, exited)
+	}
+
+	// A partially filled take profit leaves less of the position to stop out
+	if remaining := b.Filled - exited; remaining > 0 && remaining < b.StopAmount {
+		err = cancelStop(exch, b)
+		if err != nil {
+			b.Error = "resize stop loss: " + err.Error()
+			return nil
+		}
+		placeStop(exch, b, remaining)
+	}
+	return nil
+}
+
+// checkStop triggers the stop loss once the last price reaches it
+func (m *Manager) checkStop(exch exchange.IBotExchange, b *Bracket, exited float64) []Event {
+	if b.Order.StopLoss <= 0 {
+		return nil
+	}
+	price, err := m.prices(b.Exchange, b.Order.Pair)
+	if err != nil {
+		log.Debugf("Bracket manager %s bracket %s price unavailable: %s", b.Exchange, b.ID, err)
+		return nil
+	}
+	if b.Order.Side == exchange.BuyOrderSide && price > b.Order.StopLoss ||
+		b.Order.Side == exchange.SellOrderSide && price < b.Order.StopLoss {
+		return nil
+	}
+
+	if b.TakeProfitID != "" {
+		// The take profit must be gone before closing at market or both exits
+		// could fill
+		err = exch.CancelOrder(&exchange.OrderCancellation{
+			OrderID:      b.TakeProfitID,
+			CurrencyPair: b.Order.Pair,
+			Side:         b.Order.ExitSide(),
+		})
+		if err != nil {
+			b.Error = "cancel take profit: " + err.Error()
+			return []Event{{Type: Failed, Detail: b.Error}}
+		}
+		if o, err := exch.GetOrderInfo(b.TakeProfitID); err == nil {
+			exited = o.ExecutedAmount
+		}
+	}
+	b.State = StopTriggered
+	return m.stop(exch, b, exited)
+}
+
+// stop closes the remaining position at market
+func (m *Manager) stop(exch exchange.IBotExchange, b *Bracket, exited float64) []Event {
+	remaining := b.Filled - exited
+	if remaining <= 0 {
+		b.State = Closed
+		b.ClosedBy = TakeProfit
+		return []Event{{Type: Exited, Detail: "take profit filled before stop loss"}}
+	}
+	if exited > 0 {
+		// The remainder is recorded so a retried exit sends the same amount
+		b.Filled = remaining
+	}
+	resp, err := exch.SubmitOrder(b.Order.Pair, b.Order.ExitSide(), exchange.MarketOrderType,
+		remaining, 0, b.ID+"-sl")
+	if err != nil {
+		b.Error = "stop loss: " + err.Error()
+		return []Event{{Type: Failed, Detail: b.Error}}
+	}
+	b.StopLossID = resp.OrderID
+	b.State = Closed
+	b.ClosedBy = StopLoss
+	b.Error = ""
+	return []Event{{Type: Exited, Detail: fmt.Sprintf("stop loss %v triggered, closed %v at market",
+		b.Order.StopLoss, remaining)}}
+}
+
+// checkNativeExits records which linked exit filled
+func (m *Manager) checkNativeExits(exch exchange.IBotExchange, b *Bracket) []Event {
+	for _, exit := range []struct {
+		id, name string
+	}{{b.TakeProfitID, TakeProfit}, {b.StopLossID, StopLoss}} {
+		if exit.id == "" {
+			continue
+		}
+		o, err := exch.GetOrderInfo(exit.id)
+		if err != nil {
+			b.Error = err.Error()
+			return nil
+		}
+		if o.Filled() {
+			b.State = Closed
+			b.ClosedBy = exit.name
+			b.Error = ""
+			return []Event{{Type: Exited, Detail: strings.ToLower(strings.Replace(exit.name, "_", " ", -1)) + " filled"}}
+		}
+	}
+	b.Error = ""
+	return nil
+}
+
+// Start checks the open brackets at the interval until stopped
+func (m *Manager) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			m.Check()
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the manager
+func (m *Manager) Stop() {
+	m.mtx.Lock()
+	if m.shutdown == nil {
+		m.mtx.Unlock()
+		return
+	}
+	close(m.shutdown)
+	m.shutdown = nil
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
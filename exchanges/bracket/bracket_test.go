@@ -0,0 +1,402 @@
+package bracket
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	orders    map[string]*exchange.OrderDetail
+	cancelled []string
+}
+
+func newTestExchange() *testExchange {
+	return &testExchange{orders: make(map[string]*exchange.OrderDetail)}
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	id := fmt.Sprintf("%d", len(t.orders)+1)
+	t.orders[id] = &exchange.OrderDetail{
+		ID:           id,
+		CurrencyPair: p,
+		OrderSide:    side,
+		OrderType:    orderType,
+		Price:        price,
+		Amount:       amount,
+	}
+	return exchange.SubmitOrderResponse{OrderID: id, IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	o, ok := t.orders[orderID]
+	if !ok {
+		return exchange.OrderDetail{}, fmt.Errorf("order %s not found", orderID)
+	}
+	return *o, nil
+}
+
+func (t *testExchange) CancelOrder(order *exchange.OrderCancellation) error {
+	t.cancelled = append(t.cancelled, order.OrderID)
+	t.orders[order.OrderID].Status = string(exchange.CancelledOrderStatus)
+	return nil
+}
+
+type testNativeExchange struct {
+	testExchange
+}
+
+func (t *testNativeExchange) SubmitBracketOrder(b *exchange.BracketOrder, _ string) (exchange.BracketOrderResponse, error) {
+	var resp exchange.BracketOrderResponse
+	e, _ := t.SubmitOrder(b.Pair, b.Side, exchange.LimitOrderType, b.Amount, b.EntryPrice, "")
+	tp, _ := t.SubmitOrder(b.Pair, b.ExitSide(), exchange.LimitOrderType, b.Amount, b.TakeProfit, "")
+	sl, _ := t.SubmitOrder(b.Pair, b.ExitSide(), exchange.StopOrderType, b.Amount, b.StopLoss, "")
+	resp.EntryID, resp.TakeProfitID, resp.StopLossID = e.OrderID, tp.OrderID, sl.OrderID
+	return resp, nil
+}
+
+func newTestManager(t *testing.T, exch exchange.IBotExchange, path string, price *float64, events *[]Event) *Manager {
+	m, err := New(path,
+		func(string) exchange.IBotExchange { return exch },
+		func(string, currency.Pair) (float64, error) { return *price, nil },
+		func(e Event) { *events = append(*events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func testOrder() *exchange.BracketOrder {
+	return &exchange.BracketOrder{
+		Pair:       currency.NewPairWithDelimiter("BTC", "USD", "-"),
+		Side:       exchange.BuyOrderSide,
+		Amount:     2,
+		EntryPrice: 100,
+		TakeProfit: 110,
+		StopLoss:   95,
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("", nil, nil, nil); err != errNoExchanges {
+		t.Error("Test Failed - New() expected no exchanges error", err)
+	}
+	exchanges := func(string) exchange.IBotExchange { return nil }
+	if _, err := New("", exchanges, nil, nil); err != errNoPriceFunc {
+		t.Error("Test Failed - New() expected no price func error", err)
+	}
+}
+
+func TestStopLoss(t *testing.T) {
+	dir, err := ioutil.TempDir("", "bracket")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "brackets.json")
+
+	exch := newTestExchange()
+	price := 100.0
+	var events []Event
+	m := newTestManager(t, exch, path, &price, &events)
+
+	b, err := m.Submit("test", testOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.State != PendingEntry || b.Native || b.EntryID != "1" {
+		t.Fatalf("Test Failed - Submit() unexpected bracket %+v", b)
+	}
+
+	// Exits are not placed before the entry fills
+	m.Check()
+	if len(exch.orders) != 1 || len(events) != 0 {
+		t.Fatalf("Test Failed - Check() exits placed before entry filled %+v", events)
+	}
+
+	exch.orders["1"].ExecutedAmount = 2
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Active || b.TakeProfitID != "2" || len(events) != 1 || events[0].Type != Activated {
+		t.Fatalf("Test Failed - Check() expected active bracket with take profit %+v %+v", b, events)
+	}
+	tp := exch.orders["2"]
+	if tp.OrderSide != exchange.SellOrderSide || tp.Price != 110 || tp.Amount != 2 {
+		t.Errorf("Test Failed - Check() unexpected take profit order %+v", tp)
+	}
+
+	// Managed brackets resume from the persisted file
+	m = newTestManager(t, exch, path, &price, &events)
+	tp.ExecutedAmount = 0.5
+	price = 94
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Closed || b.ClosedBy != StopLoss || len(exch.cancelled) != 1 || exch.cancelled[0] != "2" {
+		t.Fatalf("Test Failed - Check() expected stop loss close %+v", b)
+	}
+	sl := exch.orders[b.StopLossID]
+	if sl.OrderType != exchange.MarketOrderType || sl.OrderSide != exchange.SellOrderSide || sl.Amount != 1.5 {
+		t.Errorf("Test Failed - Check() expected market exit of unfilled amount %+v", sl)
+	}
+	if len(events) != 2 || events[1].Type != Exited {
+		t.Errorf("Test Failed - Check() expected close event %+v", events)
+	}
+
+	if _, err = m.Cancel(b.ID); err == nil {
+		t.Error("Test Failed - Cancel() expected closed bracket error")
+	}
+}
+
+func TestTakeProfit(t *testing.T) {
+	exch := newTestExchange()
+	price := 100.0
+	var events []Event
+	m := newTestManager(t, exch, "", &price, &events)
+
+	o := testOrder()
+	o.Side = exchange.SellOrderSide
+	o.TakeProfit, o.StopLoss = 90, 105
+	b, err := m.Submit("test", o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	exch.orders[b.EntryID].ExecutedAmount = 2
+	m.Check()
+	exch.orders["2"].ExecutedAmount = 2
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Closed || b.ClosedBy != TakeProfit || len(exch.orders) != 2 {
+		t.Errorf("Test Failed - Check() expected take profit close %+v", b)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	exch := newTestExchange()
+	price := 100.0
+	var events []Event
+	m := newTestManager(t, exch, "", &price, &events)
+
+	b, err := m.Submit("test", testOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	b, err = m.Cancel(b.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.State != Cancelled || len(exch.cancelled) != 1 {
+		t.Errorf("Test Failed - Cancel() expected cancelled entry %+v", b)
+	}
+	if _, err = m.Cancel("missing"); err == nil {
+		t.Error("Test Failed - Cancel() expected not found error")
+	}
+
+	// An entry cancelled on the exchange without fills closes the bracket
+	b, _ = m.Submit("test", testOrder())
+	exch.orders[b.EntryID].Status = "Canceled"
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Cancelled || len(events) != 1 || events[0].Type != Failed {
+		t.Errorf("Test Failed - Check() expected cancelled entry %+v", b)
+	}
+}
+
+// testGuard is a wrapper rejecting every order while blocked
+type testGuard struct {
+	exchange.IBotExchange
+	blocked bool
+}
+
+func (g *testGuard) Unwrap() exchange.IBotExchange { return g.IBotExchange }
+
+func (g *testGuard) CheckOrder(_ *exchange.OrderSubmission) error {
+	if g.blocked {
+		return errTestBlocked
+	}
+	return nil
+}
+
+var errTestBlocked = errors.New("blocked")
+
+func TestNative(t *testing.T) {
+	exch := &testNativeExchange{testExchange: *newTestExchange()}
+	guard := &testGuard{IBotExchange: exch, blocked: true}
+	price := 100.0
+	var events []Event
+	m := newTestManager(t, guard, "", &price, &events)
+
+	if _, err := m.Submit("test", testOrder()); err != errTestBlocked {
+		t.Fatal("Test Failed - Submit() expected the guard to reject the native bracket", err)
+	}
+	guard.blocked = false
+	b, err := m.Submit("test", testOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !b.Native || b.TakeProfitID != "2" || b.StopLossID != "3" {
+		t.Fatalf("Test Failed - Submit() expected native bracket %+v", b)
+	}
+
+	exch.orders["1"].ExecutedAmount = 2
+	price = 90
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Active || len(exch.orders) != 3 {
+		t.Fatalf("Test Failed - Check() native stop loss must be left to the exchange %+v", b)
+	}
+
+	exch.orders["3"].ExecutedAmount = 2
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Closed || b.ClosedBy != StopLoss {
+		t.Errorf("Test Failed - Check() expected native stop loss close %+v", b)
+	}
+}
+
+func TestNativeDryRun(t *testing.T) {
+	exch := &testNativeExchange{testExchange: *newTestExchange()}
+	price := 100.0
+	var events []Event
+	m := newTestManager(t, exchange.NewDryRun(exch), "", &price, &events)
+
+	b, err := m.Submit("test", testOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.Native || len(exch.orders) != 0 {
+		t.Errorf("Test Failed - Submit() dry run bracket must not reach the exchange %+v", b)
+	}
+}
+
+type testStopExchange struct {
+	testExchange
+	stops map[string]*exchange.StopOrder
+}
+
+func newTestStopExchange() *testStopExchange {
+	return &testStopExchange{
+		testExchange: *newTestExchange(),
+		stops:        make(map[string]*exchange.StopOrder),
+	}
+}
+
+func (t *testStopExchange) SubmitStopOrder(p currency.Pair, side exchange.OrderSide, amount, triggerPrice float64) (string, error) {
+	id := fmt.Sprintf("stop-%d", len(t.stops)+1)
+	t.stops[id] = &exchange.StopOrder{ID: id}
+	return id, nil
+}
+
+func (t *testStopExchange) GetStopOrder(_ currency.Pair, id string) (exchange.StopOrder, error) {
+	s, ok := t.stops[id]
+	if !ok {
+		return exchange.StopOrder{}, fmt.Errorf("stop order %s not found", id)
+	}
+	return *s, nil
+}
+
+func (t *testStopExchange) CancelStopOrder(_ currency.Pair, id string) error {
+	t.stops[id].Cancelled = true
+	return nil
+}
+
+func TestExchangeStop(t *testing.T) {
+	exch := newTestStopExchange()
+	price := 100.0
+	var events []Event
+	m := newTestManager(t, exch, "", &price, &events)
+
+	b, err := m.Submit("test", testOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exch.orders[b.EntryID].ExecutedAmount = 2
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Active || !b.ExchangeStop || b.StopLossID != "stop-1" || b.StopAmount != 2 {
+		t.Fatalf("Test Failed - Check() expected stop loss held exchange side %+v", b)
+	}
+
+	// The client side trigger is not used while the exchange holds the stop
+	price = 90
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Active || len(exch.orders) != 2 {
+		t.Fatalf("Test Failed - Check() exchange stop loss must be left to the exchange %+v", b)
+	}
+
+	// A partially filled take profit resizes the stop
+	price = 100
+	exch.orders[b.TakeProfitID].ExecutedAmount = 0.5
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if !exch.stops["stop-1"].Cancelled || b.StopLossID != "stop-2" || b.StopAmount != 1.5 {
+		t.Fatalf("Test Failed - Check() expected resized stop loss %+v", b)
+	}
+
+	exch.stops["stop-2"].Triggered = true
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Closed || b.ClosedBy != StopLoss || len(exch.cancelled) != 1 || exch.cancelled[0] != b.TakeProfitID {
+		t.Errorf("Test Failed - Check() expected exchange stop loss close %+v", b)
+	}
+}
+
+func TestExchangeStopFallback(t *testing.T) {
+	exch := newTestStopExchange()
+	price := 100.0
+	var events []Event
+	m := newTestManager(t, exch, "", &price, &events)
+
+	b, err := m.Submit("test", testOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	exch.orders[b.EntryID].ExecutedAmount = 2
+	m.Check()
+
+	// A stop cancelled outside the bot falls back to the client side trigger
+	exch.stops["stop-1"].Cancelled = true
+	price = 94
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Closed || b.ClosedBy != StopLoss || b.ExchangeStop {
+		t.Fatalf("Test Failed - Check() expected client side stop loss close %+v", b)
+	}
+	if sl := exch.orders[b.StopLossID]; sl.OrderType != exchange.MarketOrderType || sl.Amount != 2 {
+		t.Errorf("Test Failed - Check() expected market exit %+v", sl)
+	}
+
+	// A filled take profit cancels the exchange stop
+	b, _ = m.Submit("test", testOrder())
+	exch.orders[b.EntryID].ExecutedAmount = 2
+	price = 100
+	m.Check()
+	b, _ = m.Get(b.ID)
+	stopID := b.StopLossID
+	exch.orders[b.TakeProfitID].ExecutedAmount = 2
+	m.Check()
+	b, _ = m.Get(b.ID)
+	if b.State != Closed || b.ClosedBy != TakeProfit || !exch.stops[stopID].Cancelled {
+		t.Errorf("Test Failed - Check() expected take profit close cancelling stop %+v", b)
+	}
+
+	// Cancelling an active bracket cancels the exchange stop
+	b, _ = m.Submit("test", testOrder())
+	exch.orders[b.EntryID].ExecutedAmount = 2
+	m.Check()
+	b, _ = m.Get(b.ID)
+	stopID = b.StopLossID
+	b, err = m.Cancel(b.ID)
+	if err != nil || b.State != Cancelled || !exch.stops[stopID].Cancelled {
+		t.Errorf("Test Failed - Cancel() expected exchange stop cancelled %+v %v", b, err)
+	}
+}
@@ -0,0 +1,29 @@
+package exchange
+
+import "testing"
+
+func TestBracketOrderValidate(t *testing.T) {
+	tests := []struct {
+		order BracketOrder
+		err   error
+	}{
+		{BracketOrder{Side: BuyOrderSide, Amount: 1, EntryPrice: 100, TakeProfit: 110, StopLoss: 95}, nil},
+		{BracketOrder{Side: SellOrderSide, Amount: 1, EntryPrice: 100, TakeProfit: 90, StopLoss: 105}, nil},
+		{BracketOrder{Side: BuyOrderSide, Amount: 1, StopLoss: 95}, nil},
+		{BracketOrder{Side: AnyOrderSide, Amount: 1, TakeProfit: 110}, errBracketSide},
+		{BracketOrder{Side: BuyOrderSide, TakeProfit: 110}, errBracketAmount},
+		{BracketOrder{Side: BuyOrderSide, Amount: 1, EntryPrice: 100}, errBracketNoExits},
+		{BracketOrder{Side: BuyOrderSide, Amount: 1, EntryPrice: 100, TakeProfit: 90}, errBracketTakeProfit},
+		{BracketOrder{Side: SellOrderSide, Amount: 1, EntryPrice: 100, StopLoss: 95}, errBracketStopLoss},
+		{BracketOrder{Side: BuyOrderSide, Amount: 1, TakeProfit: 90, StopLoss: 95}, errBracketInverted},
+	}
+	for i := range tests {
+		if err := tests[i].order.Validate(); err != tests[i].err {
+			t.Errorf("Test Failed - BracketOrder Validate() %+v expected %v, received %v",
+				tests[i].order, tests[i].err, err)
+		}
+	}
+	if (&BracketOrder{Side: SellOrderSide}).ExitSide() != BuyOrderSide {
+		t.Error("Test Failed - BracketOrder ExitSide() expected buy exit for short bracket")
+	}
+}
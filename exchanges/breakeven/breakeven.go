@@ -0,0 +1,594 @@
+// Package breakeven tracks open positions built from fills and computes the
+// exit price at which each position breaks even once entry fees, expected exit
+// fees and funding paid so far are accounted for. Take profit levels should be
+// placed relative to the break-even price rather than the entry price
+package breakeven
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default tracker settings
+const (
+	// DefaultExitFeeRate is the taker fee assumed for exits when the
+	// exchange's fee cannot be determined
+	DefaultExitFeeRate = 0.002
+	// DefaultCheckInterval is the delay between mark price updates
+	DefaultCheckInterval = time.Second * 15
+)
+
+// Event types sent to the alert handler
+const (
+	AboveBreakEven = "ABOVE_BREAKEVEN"
+	BelowBreakEven = "BELOW_BREAKEVEN"
+)
+
+var (
+	errExchangeNotSet   = errors.New("breakeven exchange name not set")
+	errPairNotSet       = errors.New("breakeven currency pair not set")
+	errInvalidAmount    = errors.New("breakeven fill amount must be positive")
+	errInvalidPrice     = errors.New("breakeven fill price must be positive")
+	errInvalidSide      = errors.New("breakeven fill side must be buy or sell")
+	errInvalidFeeRate   = errors.New("breakeven fee rate must be between 0 and 1")
+	errPositionNotFound = errors.New("breakeven position not found")
+	errUnreachable      = errors.New("breakeven price unreachable, costs exceed the position value")
+	errNoMarkPrice      = errors.New("breakeven mark price must be positive")
+	errSettlementType   = errors.New("breakeven settlement type must be SETTLEMENT or DELIVERY")
+)
+
+// FeeRateFunc returns the taker fee rate charged when exiting a position
+type FeeRateFunc func(exchangeName string, p currency.Pair, assetType string) (float64, error)
+
+// PriceFunc returns the current mark price for a pair
+type PriceFunc func(exchangeName string, p currency.Pair, assetType string) (float64, error)
+
+// Fill holds an execution which opens, adds to, reduces or closes a position
+type Fill struct {
+	Exchange string
+	Pair     currency.Pair
+	// AssetType is the contract ID for futures contracts, e.g. BTC-USD-190628,
+	// so their settlements can be applied to the position
+	AssetType string
+	Side      exchange.OrderSide
+	Amount    float64
+	Price     float64
+	// Fee is charged in the quote currency, or the base currency for inverse
+	// contracts. When negative the fee is unknown and estimated using the
+	// exchange's fee rate
+	Fee float64
+	// Inverse is set for contracts margined and settled in the base currency,
+	// where Amount is the position's value in the quote currency
+	Inverse   bool
+	Timestamp time.Time
+}
+
+// Position holds an open position and its break-even exit price. Costs are in
+// the quote currency, or the base currency for inverse contracts
+type Position struct {
+	Exchange  string        `json:"exchange"`
+	Pair      currency.Pair `json:"pair"`
+	AssetType string        `json:"assetType"`
+	Inverse   bool          `json:"inverse"`
+	// Size is positive for long and negative for short positions
+	Size        float64 `json:"size"`
+	EntryPrice  float64 `json:"entryPrice"`
+	EntryFees   float64 `json:"entryFees"`
+	FundingPaid float64 `json:"fundingPaid"`
+	ExitFeeRate float64 `json:"exitFeeRate"`
+	BreakEven   float64 `json:"breakEven"`
+	MarkPrice   float64 `json:"markPrice"`
+	// NetPnL is the profit if the position were closed at the mark price
+	// after all fees and funding
+	NetPnL      float64   `json:"netPnL"`
+	RealisedPnL float64   `json:"realisedPnL"`
+	Opened      time.Time `json:"opened"`
+	Updated     time.Time `json:"updated"`
+	Error       string    `json:"error,omitempty"`
+	// Settled is the time of the last futures settlement applied
+	Settled time.Time `json:"settled,omitempty"`
+}
+
+// IsLong returns whether the position is long
+func (p *Position) IsLong() bool {
+	return p.Size > 0
+}
+
+// CostCurrency returns the currency the position's costs and profit are in
+func (p *Position) CostCurrency() currency.Code {
+	if p.Inverse {
+		return p.Pair.Base
+	}
+	return p.Pair.Quote
+}
+
+// costs returns the fees and funding the exit must recover
+func (p *Position) costs() float64 {
+	return p.EntryFees + p.FundingPaid
+}
+
+// ExitPrice returns the price the position must be closed at to make the
+// target profit after all costs, a zero target returns the break-even price
+func (p *Position) ExitPrice(target float64) (float64, error) {
+	n := math.Abs(p.Size)
+	if n == 0 || p.EntryPrice <= 0 {
+		return 0, errPositionNotFound
+	}
+	r, c := p.ExitFeeRate, p.costs()+target
+
+	var price float64
+	switch {
+	case p.Inverse && p.IsLong():
+		// n(1/E - 1/P) - c - nr/P = 0
+		d := n/p.EntryPrice - c
+		if d <= 0 {
+			return 0, errUnreachable
+		}
+		price = n * (1 + r) / d
+	case p.Inverse:
+		// n(1/P - 1/E) - c - nr/P = 0
+		price = n * (1 - r) / (c + n/p.EntryPrice)
+	case p.IsLong():
+		// n(P - E) - c - nPr = 0
+		price = (n*p.EntryPrice + c) / (n * (1 - r))
+	default:
+		// n(E - P) - c - nPr = 0
+		price = (n*p.EntryPrice - c) / (n * (1 + r))
+	}
+	if price <= 0 || math.IsInf(price, 0) || math.IsNaN(price) {
+		return 0, errUnreachable
+	}
+	return price, nil
+}
+
+// TakeProfitPrice returns the exit price which nets the percentage return on
+// the position's entry value after all costs
+func (p *Position) TakeProfitPrice(percent float64) (float64, error) {
+	notional := math.Abs(p.Size) * p.EntryPrice
+	if p.Inverse && p.EntryPrice > 0 {
+		notional = math.Abs(p.Size) / p.EntryPrice
+	}
+	return p.ExitPrice(notional * percent / 100)
+}
+
+// gross returns the profit before costs of closing an amount at the price
+func (p *Position) gross(closed, price float64) float64 {
+	var gross float64
+	if p.Inverse {
+		gross = closed * (1/p.EntryPrice - 1/price)
+	} else {
+		gross = closed * (price - p.EntryPrice)
+	}
+	if !p.IsLong() {
+		return -gross
+	}
+	return gross
+}
+
+// netPnL returns the profit if closed at the price after all costs
+func (p *Position) netPnL(price float64) float64 {
+	n := math.Abs(p.Size)
+	fee := n * price * p.ExitFeeRate
+	if p.Inverse {
+		fee = n * p.ExitFeeRate / price
+	}
+	return p.gross(n, price) - fee - p.costs()
+}
+
+// Event is sent to the alert handler when a position's mark price crosses its
+// break-even price
+type Event struct {
+	Type     string
+	Position Position
+}
+
+// Tracker tracks positions and their break-even prices
+type Tracker struct {
+	feeRate   FeeRateFunc
+	price     PriceFunc
+	onCross   func(Event)
+	positions map[string]*Position
+	path      string
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+	mtx       sync.Mutex
+}
+
+// New returns a position tracker. The fee rate function, price function and
+// cross handler are optional
+func New(feeRate FeeRateFunc, price PriceFunc, onCross func(Event)) *Tracker {
+	return &Tracker{
+		feeRate:   feeRate,
+		price:     price,
+		onCross:   onCross,
+		positions: make(map[string]*Position),
+	}
+}
+
+// Load restores the positions saved at path, every change to positions is
+// saved there from then on
+func (t *Tracker) Load(path string) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.path = path
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	var positions []Position
+	err = json.Unmarshal(data, &positions)
+	if err != nil {
+		return err
+	}
+	for i := range positions {
+		p := positions[i]
+		t.positions[positionKey(p.Exchange, p.Pair, p.AssetType)] = &p
+	}
+	return nil
+}
+
+// save writes the positions to the tracker's file, the caller must hold the
+// lock
+func (t *Tracker) save() {
+	if t.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(t.list(), "", " ")
+	if err == nil {
+		err = common.WriteFile(t.path, data)
+	}
+	if err != nil {
+		log.Errorf("Break-even tracker failed to save positions: %s", err)
+	}
+}
+
+func positionKey(exchangeName string, p currency.Pair, assetType string) string {
+	return strings.ToLower(exchangeName) + "_" + p.Base.Upper().String() +
+		p.Quote.Upper().String() + "_" + strings.ToLower(assetType)
+}
+
+// exitFeeRate returns the exit fee rate for the pair
+func (t *Tracker) exitFeeRate(exchangeName string, p currency.Pair, assetType string) float64 {
+	if t.feeRate == nil {
+		return DefaultExitFeeRate
+	}
+	r, err := t.feeRate(exchangeName, p, assetType)
+	if err != nil || r < 0 || r >= 1 {
+		return DefaultExitFeeRate
+	}
+	return r
+}
+
+// AddFill applies an execution to its position
+func (t *Tracker) AddFill(f *Fill) error {
+	switch {
+	case f.Exchange == "":
+		return errExchangeNotSet
+	case f.Pair.IsEmpty():
+		return errPairNotSet
+	case f.Amount <= 0:
+		return errInvalidAmount
+	case f.Price <= 0:
+		return errInvalidPrice
+	}
+	var size float64
+	switch f.Side {
+	case exchange.BuyOrderSide, exchange.BidOrderSide:
+		size = f.Amount
+	case exchange.SellOrderSide, exchange.AskOrderSide:
+		size = -f.Amount
+	default:
+		return errInvalidSide
+	}
+
+	rate := t.exitFeeRate(f.Exchange, f.Pair, f.AssetType)
+	fee := f.Fee
+	if fee < 0 {
+		fee = f.Amount * f.Price * rate
+		if f.Inverse {
+			fee = f.Amount * rate / f.Price
+		}
+	}
+	ts := f.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	defer t.save()
+	k := positionKey(f.Exchange, f.Pair, f.AssetType)
+	p, ok := t.positions[k]
+	if !ok {
+		p = &Position{
+			Exchange:  f.Exchange,
+			Pair:      f.Pair,
+			AssetType: f.AssetType,
+			Inverse:   f.Inverse,
+			Opened:    ts,
+		}
+		t.positions[k] = p
+	}
+	p.ExitFeeRate = rate
+	p.Updated = ts
+
+	if p.Size == 0 || (p.Size > 0) == (size > 0) {
+		p.increase(size, f.Price, fee)
+		p.refresh()
+		return nil
+	}
+
+	// Reducing, closing or flipping the position, fees are split pro rata
+	// between the closed and any newly opened amount
+	closed := math.Min(math.Abs(size), math.Abs(p.Size))
+	closeFee := fee * closed / f.Amount
+	p.reduce(closed, f.Price, closeFee)
+	if remainder := math.Abs(size) - closed; remainder > 0 {
+		p.Opened = ts
+		p.increase(math.Copysign(remainder, size), f.Price, fee-closeFee)
+	}
+	if p.Size == 0 {
+		delete(t.positions, k)
+		return nil
+	}
+	p.refresh()
+	return nil
+}
+
+// increase adds to the position averaging the entry price
+func (p *Position) increase(size, price, fee float64) {
+	n, add := math.Abs(p.Size), math.Abs(size)
+	if p.Inverse {
+		// Inverse contracts average entry by harmonic mean
+		held := 0.0
+		if n > 0 {
+			held = n / p.EntryPrice
+		}
+		p.EntryPrice = (n + add) / (held + add/price)
+	} else {
+		p.EntryPrice = (n*p.EntryPrice + add*price) / (n + add)
+	}
+	p.Size += size
+	p.EntryFees += fee
+}
+
+// reduce closes part of the position, realising its share of the costs
+func (p *Position) reduce(closed, price, fee float64) {
+	n := math.Abs(p.Size)
+	share := closed / n
+	p.RealisedPnL += p.gross(closed, price) - fee - p.costs()*share
+	p.EntryFees -= p.EntryFees * share
+	p.FundingPaid -= p.FundingPaid * share
+	if closed >= n {
+		p.Size = 0
+		return
+	}
+	p.Size = math.Copysign(n-closed, p.Size)
+}
+
+// refresh recalculates the break-even price and net PnL at the mark price
+func (p *Position) refresh() {
+	var err error
+	p.BreakEven, err = p.ExitPrice(0)
+	p.Error = ""
+	if err != nil {
+		p.Error = err.Error()
+	}
+	if p.MarkPrice > 0 {
+		p.NetPnL = p.netPnL(p.MarkPrice)
+	}
+}
+
+// AddFunding records a funding payment against a position, positive amounts
+// are paid and negative amounts are received
+func (t *Tracker) AddFunding(exchangeName string, p currency.Pair, assetType string, amount float64) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	pos, ok := t.positions[positionKey(exchangeName, p, assetType)]
+	if !ok {
+		return fmt.Errorf("%s %s %s %v", exchangeName, p, assetType, errPositionNotFound)
+	}
+	pos.FundingPaid += amount
+	pos.Updated = time.Now()
+	pos.refresh()
+	t.save()
+	return nil
+}
+
+// ApplySettlement applies a futures settlement or delivery to the position
+// held in the contract. Settlements realise the position's profit before
+// costs at the settlement price, which becomes its entry price, and
+// deliveries close the position at the delivery price. Settlements before
+// the position opened or its last applied settlement are skipped, so a
+// settlement history can be applied repeatedly. The position is returned
+// along with whether the settlement was applied
+func (t *Tracker) ApplySettlement(s *exchange.Settlement) (Position, bool, error) {
+	if s.Price <= 0 {
+		return Position{}, false, errInvalidPrice
+	}
+	if s.Type != exchange.Settled && s.Type != exchange.Delivered {
+		return Position{}, false, errSettlementType
+	}
+
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	k := positionKey(s.Exchange, s.Pair, s.Contract)
+	pos, ok := t.positions[k]
+	if !ok || !s.Time.After(pos.Opened) || !s.Time.After(pos.Settled) {
+		return Position{}, false, nil
+	}
+	pos.Settled = s.Time
+	pos.Updated = s.Time
+	if s.Type == exchange.Delivered {
+		pos.reduce(math.Abs(pos.Size), s.Price, 0)
+		pos.MarkPrice = s.Price
+		pos.NetPnL = 0
+		delete(t.positions, k)
+	} else {
+		pos.RealisedPnL += pos.gross(math.Abs(pos.Size), s.Price)
+		pos.EntryPrice = s.Price
+		pos.refresh()
+	}
+	t.save()
+	return *pos, true, nil
+}
+
+// SetExitFeeRate overrides the exit fee rate of a position, for example when
+// the position will be closed with a maker order
+func (t *Tracker) SetExitFeeRate(exchangeName string, p currency.Pair, assetType string, rate float64) error {
+	if rate < 0 || rate >= 1 {
+		return errInvalidFeeRate
+	}
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	pos, ok := t.positions[positionKey(exchangeName, p, assetType)]
+	if !ok {
+		return fmt.Errorf("%s %s %s %v", exchangeName, p, assetType, errPositionNotFound)
+	}
+	pos.ExitFeeRate = rate
+	pos.refresh()
+	t.save()
+	return nil
+}
+
+// UpdateMark sets a position's mark price, calling the cross handler when the
+// mark crosses the break-even price
+func (t *Tracker) UpdateMark(exchangeName string, p currency.Pair, assetType string, price float64) error {
+	if price <= 0 {
+		return errNoMarkPrice
+	}
+	t.mtx.Lock()
+	pos, ok := t.positions[positionKey(exchangeName, p, assetType)]
+	if !ok {
+		t.mtx.Unlock()
+		return fmt.Errorf("%s %s %s %v", exchangeName, p, assetType, errPositionNotFound)
+	}
+	// Positions are treated as below break-even until first marked
+	wasProfitable := pos.MarkPrice > 0 && pos.NetPnL > 0
+	pos.MarkPrice = price
+	pos.refresh()
+	snapshot := *pos
+	t.mtx.Unlock()
+
+	profitable := snapshot.NetPnL > 0
+	if t.onCross == nil || profitable == wasProfitable {
+		return nil
+	}
+	e := Event{Type: BelowBreakEven, Position: snapshot}
+	if profitable {
+		e.Type = AboveBreakEven
+	}
+	t.onCross(e)
+	return nil
+}
+
+// GetPosition returns a position
+func (t *Tracker) GetPosition(exchangeName string, p currency.Pair, assetType string) (Position, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	pos, ok := t.positions[positionKey(exchangeName, p, assetType)]
+	if !ok {
+		return Position{}, fmt.Errorf("%s %s %s %v", exchangeName, p, assetType, errPositionNotFound)
+	}
+	return *pos, nil
+}
+
+// GetPositions returns all open positions ordered by exchange and pair
+func (t *Tracker) GetPositions() []Position {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	return t.list()
+}
+
+// list returns the positions ordered by exchange and pair, the caller must
+// hold the lock
+func (t *Tracker) list() []Position {
+	keys := make([]string, 0, len(t.positions))
+	for k := range t.positions {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	positions := make([]Position, len(keys))
+	for i := range keys {
+		positions[i] = *t.positions[keys[i]]
+	}
+	return positions
+}
+
+// Check updates the mark price of every position
+func (t *Tracker) Check() {
+	if t.price == nil {
+		return
+	}
+	positions := t.GetPositions()
+	for i := range positions {
+		p := &positions[i]
+		price, err := t.price(p.Exchange, p.Pair, p.AssetType)
+		if err == nil {
+			err = t.UpdateMark(p.Exchange, p.Pair, p.AssetType, price)
+		}
+		if err != nil {
+			log.Debugf("Break-even tracker failed to update %s %s mark price: %s",
+				p.Exchange, p.Pair, err)
+		}
+	}
+}
+
+// Start periodically updates mark prices until stopped
+func (t *Tracker) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+
+	t.mtx.Lock()
+	if t.shutdown != nil {
+		t.mtx.Unlock()
+		return
+	}
+	t.shutdown = make(chan struct{})
+	shutdown := t.shutdown
+	t.mtx.Unlock()
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			t.Check()
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops updating mark prices
+func (t *Tracker) Stop() {
+	t.mtx.Lock()
+	if t.shutdown == nil {
+		t.mtx.Unlock()
+		return
+	}
+	close(t.shutdown)
+	t.shutdown = nil
+	t.mtx.Unlock()
+	t.wg.Wait()
+}
@@ -0,0 +1,247 @@
+package breakeven
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+const testAsset = "SPOT"
+
+var testPair = currency.NewPairWithDelimiter("BTC", "USDT", "-")
+
+func testFeeRate(_ string, _ currency.Pair, _ string) (float64, error) {
+	return 0.001, nil
+}
+
+func testFill(side exchange.OrderSide, amount, price, fee float64) *Fill {
+	return &Fill{
+		Exchange:  "Test",
+		Pair:      testPair,
+		AssetType: testAsset,
+		Side:      side,
+		Amount:    amount,
+		Price:     price,
+		Fee:       fee,
+	}
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestAddFill(t *testing.T) {
+	tr := New(testFeeRate, nil, nil)
+	invalid := []struct {
+		f   Fill
+		err error
+	}{
+		{Fill{Pair: testPair, Amount: 1, Price: 1, Side: exchange.BuyOrderSide}, errExchangeNotSet},
+		{Fill{Exchange: "Test", Amount: 1, Price: 1, Side: exchange.BuyOrderSide}, errPairNotSet},
+		{Fill{Exchange: "Test", Pair: testPair, Price: 1, Side: exchange.BuyOrderSide}, errInvalidAmount},
+		{Fill{Exchange: "Test", Pair: testPair, Amount: 1, Side: exchange.BuyOrderSide}, errInvalidPrice},
+		{Fill{Exchange: "Test", Pair: testPair, Amount: 1, Price: 1, Side: exchange.AnyOrderSide}, errInvalidSide},
+	}
+	for i := range invalid {
+		if err := tr.AddFill(&invalid[i].f); err != invalid[i].err {
+			t.Errorf("Test Failed - AddFill() test %d expected %v, got %v", i, invalid[i].err, err)
+		}
+	}
+
+	if err := tr.AddFill(testFill(exchange.BuyOrderSide, 1, 10000, 10)); err != nil {
+		t.Fatal("Test Failed - AddFill() error", err)
+	}
+	if err := tr.AddFill(testFill(exchange.BuyOrderSide, 1, 12000, 12)); err != nil {
+		t.Fatal("Test Failed - AddFill() error", err)
+	}
+	p, err := tr.GetPosition("test", testPair, testAsset)
+	if err != nil {
+		t.Fatal("Test Failed - GetPosition() error", err)
+	}
+	if p.Size != 2 || p.EntryPrice != 11000 || p.EntryFees != 22 {
+		t.Errorf("Test Failed - AddFill() unexpected position %+v", p)
+	}
+	// (2 * 11000 + 22) / (2 * 0.999)
+	if !closeTo(p.BreakEven, 11022.022022022) {
+		t.Errorf("Test Failed - AddFill() unexpected break-even %v", p.BreakEven)
+	}
+
+	// Reduce by half at a profit, realising half the entry fees
+	if err = tr.AddFill(testFill(exchange.SellOrderSide, 1, 12000, 12)); err != nil {
+		t.Fatal("Test Failed - AddFill() error", err)
+	}
+	p, _ = tr.GetPosition("Test", testPair, testAsset)
+	if p.Size != 1 || p.EntryFees != 11 || !closeTo(p.RealisedPnL, 1000-12-11) {
+		t.Errorf("Test Failed - AddFill() unexpected reduced position %+v", p)
+	}
+
+	// Flip short, the entry fee is estimated from the fee rate
+	if err = tr.AddFill(testFill(exchange.SellOrderSide, 3, 10000, -1)); err != nil {
+		t.Fatal("Test Failed - AddFill() error", err)
+	}
+	p, _ = tr.GetPosition("Test", testPair, testAsset)
+	if p.Size != -2 || p.EntryPrice != 10000 || !closeTo(p.EntryFees, 20) {
+		t.Errorf("Test Failed - AddFill() unexpected flipped position %+v", p)
+	}
+	// (2 * 10000 - 20) / (2 * 1.001)
+	if !closeTo(p.BreakEven, 19980/2.002) {
+		t.Errorf("Test Failed - AddFill() unexpected short break-even %v", p.BreakEven)
+	}
+
+	if err = tr.AddFill(testFill(exchange.BuyOrderSide, 2, 9000, 18)); err != nil {
+		t.Fatal("Test Failed - AddFill() error", err)
+	}
+	if len(tr.GetPositions()) != 0 {
+		t.Error("Test Failed - AddFill() closed position not removed")
+	}
+}
+
+func TestFundingAndExitFees(t *testing.T) {
+	tr := New(nil, nil, nil)
+	if err := tr.AddFunding("Test", testPair, testAsset, 1); err == nil {
+		t.Error("Test Failed - AddFunding() expected position not found error")
+	}
+	if err := tr.AddFill(testFill(exchange.BuyOrderSide, 1, 10000, 10)); err != nil {
+		t.Fatal("Test Failed - AddFill() error", err)
+	}
+	if err := tr.AddFunding("Test", testPair, testAsset, 5); err != nil {
+		t.Fatal("Test Failed - AddFunding() error", err)
+	}
+	if err := tr.SetExitFeeRate("Test", testPair, testAsset, 1); err != errInvalidFeeRate {
+		t.Error("Test Failed - SetExitFeeRate() expected invalid fee rate error", err)
+	}
+	if err := tr.SetExitFeeRate("Test", testPair, testAsset, 0); err != nil {
+		t.Fatal("Test Failed - SetExitFeeRate() error", err)
+	}
+	p, _ := tr.GetPosition("Test", testPair, testAsset)
+	if !closeTo(p.BreakEven, 10015) {
+		t.Errorf("Test Failed - AddFunding() unexpected break-even %v", p.BreakEven)
+	}
+	tp, err := p.TakeProfitPrice(1)
+	if err != nil || !closeTo(tp, 10115) {
+		t.Errorf("Test Failed - TakeProfitPrice() expected 10115, got %v %v", tp, err)
+	}
+}
+
+func TestInverse(t *testing.T) {
+	tr := New(nil, nil, nil)
+	f := testFill(exchange.BuyOrderSide, 10000, 10000, 0.0005)
+	f.Inverse = true
+	if err := tr.AddFill(f); err != nil {
+		t.Fatal("Test Failed - AddFill() error", err)
+	}
+	p, _ := tr.GetPosition("Test", testPair, testAsset)
+	if p.BreakEven <= p.EntryPrice || !closeTo(p.netPnL(p.BreakEven), 0) {
+		t.Errorf("Test Failed - AddFill() unexpected inverse break-even %v", p.BreakEven)
+	}
+
+	// Costs greater than the position value can never be recovered
+	p.EntryFees = 2
+	if _, err := p.ExitPrice(0); err != errUnreachable {
+		t.Error("Test Failed - ExitPrice() expected unreachable error", err)
+	}
+}
+
+func TestUpdateMark(t *testing.T) {
+	var events []Event
+	tr := New(nil, func(_ string, _ currency.Pair, _ string) (float64, error) {
+		return 0, errors.New("no price")
+	}, func(e Event) {
+		events = append(events, e)
+	})
+	if err := tr.UpdateMark("Test", testPair, testAsset, 0); err != errNoMarkPrice {
+		t.Error("Test Failed - UpdateMark() expected no mark price error", err)
+	}
+	if err := tr.AddFill(testFill(exchange.BuyOrderSide, 1, 10000, 10)); err != nil {
+		t.Fatal("Test Failed - AddFill() error", err)
+	}
+	tr.Check()
+
+	// Above entry but below break-even does not alert
+	for _, price := range []float64{10015, 10050, 10060, 10000} {
+		if err := tr.UpdateMark("Test", testPair, testAsset, price); err != nil {
+			t.Fatal("Test Failed - UpdateMark() error", err)
+		}
+	}
+	if len(events) != 2 || events[0].Type != AboveBreakEven || events[1].Type != BelowBreakEven {
+		t.Errorf("Test Failed - UpdateMark() unexpected events %+v", events)
+	}
+	p, _ := tr.GetPosition("Test", testPair, testAsset)
+	if p.MarkPrice != 10000 || !closeTo(p.NetPnL, -30) {
+		t.Errorf("Test Failed - UpdateMark() unexpected position %+v", p)
+	}
+}
+
+func TestApplySettlement(t *testing.T) {
+	dir, err := ioutil.TempDir("", "breakeven")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "breakeven.json")
+
+	tr := New(nil, nil, nil)
+	if err = tr.Load(path); err != nil {
+		t.Fatal("Test Failed - Load() error", err)
+	}
+	opened := time.Date(2019, 3, 20, 0, 0, 0, 0, time.UTC)
+	f := testFill(exchange.BuyOrderSide, 100, 4000, 0)
+	f.AssetType = "BTC-USD-190329"
+	f.Inverse = true
+	f.Timestamp = opened
+	if err = tr.AddFill(f); err != nil {
+		t.Fatal("Test Failed - AddFill() error", err)
+	}
+
+	settlement := &exchange.Settlement{
+		Exchange: "test",
+		Pair:     testPair,
+		Contract: f.AssetType,
+		Type:     exchange.Settled,
+		Price:    4100,
+		Time:     opened.Add(-time.Hour),
+	}
+	if _, applied, _ := tr.ApplySettlement(settlement); applied {
+		t.Error("Test Failed - ApplySettlement() expected settlements before opening skipped")
+	}
+	settlement.Time = opened.AddDate(0, 0, 2)
+	p, applied, err := tr.ApplySettlement(settlement)
+	if err != nil || !applied {
+		t.Fatal("Test Failed - ApplySettlement() expected settlement applied", err)
+	}
+	if p.EntryPrice != 4100 || p.Size != 100 || !closeTo(p.RealisedPnL, 100*(1/4000.0-1/4100.0)) {
+		t.Errorf("Test Failed - ApplySettlement() unexpected settled position %+v", p)
+	}
+	if _, applied, _ = tr.ApplySettlement(settlement); applied {
+		t.Error("Test Failed - ApplySettlement() expected settlement applied once")
+	}
+
+	// Restarted trackers deliver positions settled before shutting down
+	tr = New(nil, nil, nil)
+	if err = tr.Load(path); err != nil {
+		t.Fatal("Test Failed - Load() error", err)
+	}
+	delivery := *settlement
+	delivery.Type = exchange.Delivered
+	delivery.Price = 4000
+	delivery.Time = opened.AddDate(0, 0, 9)
+	p, applied, err = tr.ApplySettlement(&delivery)
+	if err != nil || !applied {
+		t.Fatal("Test Failed - ApplySettlement() expected delivery applied", err)
+	}
+	if p.Size != 0 || !closeTo(p.RealisedPnL, 0) || len(tr.GetPositions()) != 0 {
+		t.Errorf("Test Failed - ApplySettlement() expected delivery to close the position %+v", p)
+	}
+
+	delivery.Type = "EXPIRY"
+	if _, _, err = tr.ApplySettlement(&delivery); err != errSettlementType {
+		t.Error("Test Failed - ApplySettlement() expected settlement type error", err)
+	}
+}
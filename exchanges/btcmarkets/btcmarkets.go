@@ -436,12 +436,26 @@ func (b *BTCMarkets) SendHTTPRequest(path string, result interface{}) error {
 	return b.SendPayload(http.MethodGet, path, nil, nil, result, false, false, b.Verbose, b.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Order history
+// and details are read through POST requests so endpoints are classified by
+// name
+func mutating(path string) bool {
+	switch path {
+	case btcMarketsOrderCreate, btcMarketsOrderCancel, btcMarketsWithdrawCrypto, btcMarketsWithdrawAud:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedRequest sends an authenticated HTTP request
 func (b *BTCMarkets) SendAuthenticatedRequest(reqType, path string, data, result interface{}) (err error) {
 	if !b.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet,
 			b.Name)
 	}
+	if b.DryRunRequest(mutating(path), path, data) {
+		return nil
+	}
 
 	n := b.Requester.GetNonce(true).String()[0:13]
 
@@ -271,11 +271,24 @@ func (b *BTSE) SendHTTPRequest(method, endpoint string, result interface{}) erro
 	return b.SendPayload(method, p, nil, nil, &result, false, false, b.Verbose, b.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Fills are
+// read through a POST request so endpoints are classified by name
+func mutating(endpoint string) bool {
+	switch endpoint {
+	case btseOrder, btseDeleteOrder, btseDeleteOrders:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP request to the desired endpoint
 func (b *BTSE) SendAuthenticatedHTTPRequest(method, endpoint string, req map[string]interface{}, result interface{}) error {
 	if !b.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, b.Name)
 	}
+	if b.DryRunRequest(mutating(endpoint), endpoint, req) {
+		return nil
+	}
 
 	payload, err := common.JSONEncode(req)
 	if err != nil {
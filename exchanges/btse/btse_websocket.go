@@ -92,11 +92,13 @@ func (b *BTSE) WsHandleData() {
 				}
 
 				b.Websocket.DataHandler <- wshandler.TickerData{
-					Timestamp: time.Now(),
-					Pair:      currency.NewPairDelimiter(t.ProductID, "-"),
-					AssetType: "SPOT",
-					Exchange:  b.GetName(),
-					OpenPrice: price,
+					Timestamp:  time.Now(),
+					Pair:       currency.NewPairDelimiter(t.ProductID, "-"),
+					AssetType:  "SPOT",
+					Exchange:   b.GetName(),
+					ClosePrice: price,
+					BidPrice:   t.BestBids,
+					AskPrice:   t.BestAsk,
 				}
 			case "snapshot":
 				snapshot := websocketOrderbookSnapshot{}
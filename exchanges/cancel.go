@@ -0,0 +1,153 @@
+package exchange
+
+import (
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+var (
+	errNilOrderFilter    = errors.New("order filter is nil")
+	errInvalidPriceRange = errors.New("order filter minimum price exceeds maximum price")
+	errInvalidAgeRange   = errors.New("order filter minimum age exceeds maximum age")
+)
+
+// OrderFilter defines the criteria open orders are matched against, unset
+// criteria match every order
+type OrderFilter struct {
+	Pairs     []currency.Pair
+	Side      OrderSide
+	OrderType OrderType
+	// MinAge matches orders placed at least this long ago and MaxAge orders
+	// placed at most this long ago
+	MinAge   time.Duration
+	MaxAge   time.Duration
+	MinPrice float64
+	MaxPrice float64
+}
+
+// Validate checks the filter ranges
+func (f *OrderFilter) Validate() error {
+	if f == nil {
+		return errNilOrderFilter
+	}
+	if f.MaxPrice > 0 && f.MinPrice > f.MaxPrice {
+		return errInvalidPriceRange
+	}
+	if f.MaxAge > 0 && f.MinAge > f.MaxAge {
+		return errInvalidAgeRange
+	}
+	return nil
+}
+
+// Match returns true if the order meets every criteria of the filter. Orders
+// without a placement date or price never match age or price criteria
+func (f *OrderFilter) Match(o *OrderDetail, now time.Time) bool {
+	if f.Side != "" && f.Side != AnyOrderSide && !strings.EqualFold(string(o.OrderSide), string(f.Side)) {
+		return false
+	}
+	if f.OrderType != "" && f.OrderType != AnyOrderType && !strings.EqualFold(string(o.OrderType), string(f.OrderType)) {
+		return false
+	}
+	if len(f.Pairs) > 0 {
+		var found bool
+		for i := range f.Pairs {
+			if o.CurrencyPair.EqualIncludeReciprocal(f.Pairs[i]) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	if f.MinAge > 0 || f.MaxAge > 0 {
+		if o.OrderDate.IsZero() {
+			return false
+		}
+		age := now.Sub(o.OrderDate)
+		if age < f.MinAge || (f.MaxAge > 0 && age > f.MaxAge) {
+			return false
+		}
+	}
+	if f.MinPrice > 0 || f.MaxPrice > 0 {
+		if o.Price <= 0 || o.Price < f.MinPrice || (f.MaxPrice > 0 && o.Price > f.MaxPrice) {
+			return false
+		}
+	}
+	return true
+}
+
+// NativeOnly returns true if the filter only matches on pair, side and order
+// type, which exchanges can filter natively
+func (f *OrderFilter) NativeOnly() bool {
+	return f.MinAge == 0 && f.MaxAge == 0 && f.MinPrice == 0 && f.MaxPrice == 0
+}
+
+// CancelOrdersResult holds the outcome of cancelling orders by filter
+type CancelOrdersResult struct {
+	Exchange  string
+	Cancelled []string
+	// Failed maps order IDs to the reason their cancellation failed
+	Failed map[string]string
+}
+
+// FilteredCanceller is implemented by exchanges which can cancel orders
+// matching a pair, side and order type natively. Exchanges return
+// common.ErrFunctionNotSupported for criteria they cannot filter on
+type FilteredCanceller interface {
+	CancelOrdersByFilter(f *OrderFilter) (CancelOrdersResult, error)
+}
+
+// CancelOrdersWhere cancels every open order matching the filter. The
+// exchange's native filtered cancel is used when the filter only matches on
+// pair, side and order type, otherwise open orders are fetched and matched
+// before being cancelled individually
+func CancelOrdersWhere(e IBotExchange, f *OrderFilter) (CancelOrdersResult, error) {
+	if err := f.Validate(); err != nil {
+		return CancelOrdersResult{}, err
+	}
+
+	if c, ok := e.(FilteredCanceller); ok && f.NativeOnly() {
+		resp, err := c.CancelOrdersByFilter(f)
+		if err != common.ErrFunctionNotSupported {
+			resp.Exchange = e.GetName()
+			return resp, err
+		}
+	}
+
+	resp := CancelOrdersResult{
+		Exchange: e.GetName(),
+		Failed:   make(map[string]string),
+	}
+	orders, err := e.GetActiveOrders(&GetOrdersRequest{
+		OrderSide:  f.Side,
+		OrderType:  f.OrderType,
+		Currencies: f.Pairs,
+	})
+	if err != nil {
+		return resp, err
+	}
+
+	now := time.Now()
+	for i := range orders {
+		if !f.Match(&orders[i], now) {
+			continue
+		}
+		err = e.CancelOrder(&OrderCancellation{
+			AccountID:    orders[i].AccountID,
+			OrderID:      orders[i].ID,
+			Side:         orders[i].OrderSide,
+			CurrencyPair: orders[i].CurrencyPair,
+		})
+		if err != nil {
+			resp.Failed[orders[i].ID] = err.Error()
+			continue
+		}
+		resp.Cancelled = append(resp.Cancelled, orders[i].ID)
+	}
+	return resp, nil
+}
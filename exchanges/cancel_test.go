@@ -0,0 +1,126 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testCancelExchange struct {
+	IBotExchange
+	orders    []OrderDetail
+	cancelled []string
+}
+
+func (t *testCancelExchange) GetName() string { return "Test" }
+
+func (t *testCancelExchange) GetActiveOrders(_ *GetOrdersRequest) ([]OrderDetail, error) {
+	return t.orders, nil
+}
+
+func (t *testCancelExchange) CancelOrder(o *OrderCancellation) error {
+	if o.OrderID == "fail" {
+		return errors.New("order not found")
+	}
+	t.cancelled = append(t.cancelled, o.OrderID)
+	return nil
+}
+
+type testNativeCancelExchange struct {
+	testCancelExchange
+	native int
+}
+
+func (t *testNativeCancelExchange) CancelOrdersByFilter(f *OrderFilter) (CancelOrdersResult, error) {
+	if f.OrderType == StopOrderType {
+		return CancelOrdersResult{}, common.ErrFunctionNotSupported
+	}
+	t.native++
+	return CancelOrdersResult{Cancelled: []string{"native"}}, nil
+}
+
+func testOpenOrders() []OrderDetail {
+	now := time.Now()
+	btc := currency.NewPair(currency.BTC, currency.USD)
+	eth := currency.NewPair(currency.ETH, currency.USD)
+	return []OrderDetail{
+		{ID: "1", CurrencyPair: btc, OrderSide: BuyOrderSide, OrderType: LimitOrderType, Price: 100, OrderDate: now.Add(-time.Hour)},
+		{ID: "2", CurrencyPair: btc, OrderSide: SellOrderSide, OrderType: LimitOrderType, Price: 200, OrderDate: now.Add(-time.Minute)},
+		{ID: "3", CurrencyPair: eth, OrderSide: BuyOrderSide, OrderType: LimitOrderType, Price: 10, OrderDate: now.Add(-time.Hour)},
+		{ID: "4", CurrencyPair: btc, OrderSide: BuyOrderSide, OrderType: StopOrderType, Price: 150},
+		{ID: "fail", CurrencyPair: btc, OrderSide: BuyOrderSide, OrderType: LimitOrderType, Price: 120, OrderDate: now.Add(-time.Hour)},
+	}
+}
+
+func TestOrderFilterMatch(t *testing.T) {
+	btc := currency.NewPair(currency.BTC, currency.USD)
+	orders := testOpenOrders()
+	now := time.Now()
+	tests := []struct {
+		f    OrderFilter
+		want []string
+	}{
+		{OrderFilter{}, []string{"1", "2", "3", "4", "fail"}},
+		{OrderFilter{Side: "buy"}, []string{"1", "3", "4", "fail"}},
+		{OrderFilter{Pairs: []currency.Pair{btc}, OrderType: LimitOrderType}, []string{"1", "2", "fail"}},
+		{OrderFilter{MinAge: time.Minute * 30}, []string{"1", "3", "fail"}},
+		{OrderFilter{MaxAge: time.Minute * 30}, []string{"2"}},
+		{OrderFilter{MinPrice: 100, MaxPrice: 150}, []string{"1", "4", "fail"}},
+	}
+	for i := range tests {
+		var got []string
+		for j := range orders {
+			if tests[i].f.Match(&orders[j], now) {
+				got = append(got, orders[j].ID)
+			}
+		}
+		if len(got) != len(tests[i].want) {
+			t.Errorf("Test Failed - Match() test %d expected %v, got %v", i, tests[i].want, got)
+			continue
+		}
+		for j := range got {
+			if got[j] != tests[i].want[j] {
+				t.Errorf("Test Failed - Match() test %d expected %v, got %v", i, tests[i].want, got)
+				break
+			}
+		}
+	}
+}
+
+func TestCancelOrdersWhere(t *testing.T) {
+	e := &testCancelExchange{orders: testOpenOrders()}
+	if _, err := CancelOrdersWhere(e, nil); err != errNilOrderFilter {
+		t.Error("Test Failed - CancelOrdersWhere() expected nil filter error", err)
+	}
+	if _, err := CancelOrdersWhere(e, &OrderFilter{MinPrice: 2, MaxPrice: 1}); err != errInvalidPriceRange {
+		t.Error("Test Failed - CancelOrdersWhere() expected invalid price range error", err)
+	}
+	if _, err := CancelOrdersWhere(e, &OrderFilter{MinAge: time.Hour, MaxAge: time.Minute}); err != errInvalidAgeRange {
+		t.Error("Test Failed - CancelOrdersWhere() expected invalid age range error", err)
+	}
+
+	resp, err := CancelOrdersWhere(e, &OrderFilter{Side: BuyOrderSide, MinAge: time.Minute * 30})
+	if err != nil {
+		t.Fatal("Test Failed - CancelOrdersWhere() error", err)
+	}
+	if len(resp.Cancelled) != 2 || resp.Failed["fail"] == "" || resp.Exchange != "Test" {
+		t.Errorf("Test Failed - CancelOrdersWhere() unexpected result %+v", resp)
+	}
+
+	n := &testNativeCancelExchange{testCancelExchange: testCancelExchange{orders: testOpenOrders()}}
+	resp, err = CancelOrdersWhere(n, &OrderFilter{Side: SellOrderSide})
+	if err != nil || n.native != 1 || len(resp.Cancelled) != 1 || resp.Cancelled[0] != "native" {
+		t.Errorf("Test Failed - CancelOrdersWhere() expected native cancel, got %+v %v", resp, err)
+	}
+	// Price criteria and unsupported order types fall back to client side
+	// filtering
+	if resp, _ = CancelOrdersWhere(n, &OrderFilter{MaxPrice: 10}); n.native != 1 || len(resp.Cancelled) != 1 {
+		t.Errorf("Test Failed - CancelOrdersWhere() expected client side cancel, got %+v", resp)
+	}
+	if resp, _ = CancelOrdersWhere(n, &OrderFilter{OrderType: StopOrderType}); n.native != 1 || resp.Cancelled[0] != "4" {
+		t.Errorf("Test Failed - CancelOrdersWhere() expected client side cancel, got %+v", resp)
+	}
+}
@@ -0,0 +1,171 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// Default cancel confirmation settings
+const (
+	DefaultCancelTimeout      = time.Second * 10
+	DefaultCancelPollInterval = time.Second
+	DefaultCancelRetries      = 2
+)
+
+// Cancel outcomes
+const (
+	CancelConfirmed   = "CANCELLED"
+	CancelOrderFilled = "FILLED"
+	CancelUnconfirmed = "UNCONFIRMED"
+)
+
+var (
+	// ErrCancelOrderFilled is returned when a cancel failed because the
+	// order filled before it could be cancelled
+	ErrCancelOrderFilled = errors.New("cancel failed, order filled")
+	// ErrCancelUnconfirmed is returned when an order was still open, or its
+	// state unknown, after every cancel attempt timed out
+	ErrCancelUnconfirmed = errors.New("cancel not confirmed, order may still be open")
+)
+
+// CancelConfig defines how long a cancel is waited on and how often it is
+// retried
+type CancelConfig struct {
+	// Timeout is how long each attempt waits for the order to reach a
+	// terminal state
+	Timeout      time.Duration
+	PollInterval time.Duration
+	// Retries is the number of further cancel requests sent while the order
+	// stays open
+	Retries int
+}
+
+// CancelResult holds the confirmed outcome of cancelling an order
+type CancelResult struct {
+	OrderID  string
+	Outcome  string
+	Attempts int
+	// Filled is the amount executed before the order was cancelled, zero
+	// when the order's fills could not be fetched
+	Filled float64
+	Status string
+}
+
+// orderState fetches the order, falling back to the exchange's open orders
+// when it cannot fetch individual orders. The fallback cannot tell a
+// cancelled order from a filled one, an order no longer open is reported as
+// terminal with found unset
+func orderState(e IBotExchange, c *OrderCancellation) (o OrderDetail, found, terminal, filled bool, err error) {
+	o, err = e.GetOrderInfo(c.OrderID)
+	if err == nil && (o.ID == "" || o.ID == c.OrderID) && o.Status != "" {
+		terminal, filled = o.Closed(), o.Filled()
+		return o, true, terminal, filled, nil
+	}
+
+	req := GetOrdersRequest{}
+	if !c.CurrencyPair.IsEmpty() {
+		req.Currencies = []currency.Pair{c.CurrencyPair}
+	}
+	open, err := e.GetActiveOrders(&req)
+	if err != nil {
+		return OrderDetail{}, false, false, false, err
+	}
+	for i := range open {
+		if open[i].ID == c.OrderID {
+			return open[i], true, false, false, nil
+		}
+	}
+	return OrderDetail{}, false, true, false, nil
+}
+
+// ConfirmCancel cancels an order and waits for it to reach a terminal state,
+// sending the cancel again while the order stays open. An order which filled
+// before it could be cancelled returns ErrCancelOrderFilled, one still open
+// after every attempt returns ErrCancelUnconfirmed
+func ConfirmCancel(e IBotExchange, c *OrderCancellation, cfg CancelConfig) (CancelResult, error) {
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultCancelTimeout
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultCancelPollInterval
+	}
+	if cfg.Retries < 0 {
+		cfg.Retries = 0
+	}
+
+	result := CancelResult{OrderID: c.OrderID, Outcome: CancelUnconfirmed}
+	var lastErr error
+	for result.Attempts <= cfg.Retries {
+		result.Attempts++
+		cancelErr := e.CancelOrder(c)
+
+		deadline := time.Now().Add(cfg.Timeout)
+		for {
+			o, found, terminal, filled, err := orderState(e, c)
+			if err != nil {
+				lastErr = err
+			} else if terminal {
+				result.Filled, result.Status = o.ExecutedAmount, o.Status
+				// An order which is no longer open but refused the cancel
+				// completed before the cancel reached it
+				if filled || (!found && cancelErr != nil) {
+					result.Outcome = CancelOrderFilled
+					return result, ErrCancelOrderFilled
+				}
+				result.Outcome = CancelConfirmed
+				return result, nil
+			} else {
+				result.Filled, result.Status = o.ExecutedAmount, o.Status
+				lastErr = cancelErr
+			}
+			if !time.Now().Before(deadline) {
+				break
+			}
+			time.Sleep(cfg.PollInterval)
+		}
+	}
+	if lastErr != nil {
+		return result, fmt.Errorf("%s order %s %v after %d attempts: %s",
+			e.GetName(), c.OrderID, ErrCancelUnconfirmed, result.Attempts, lastErr)
+	}
+	return result, fmt.Errorf("%s order %s %v after %d attempts",
+		e.GetName(), c.OrderID, ErrCancelUnconfirmed, result.Attempts)
+}
+
+// ConfirmedCancel wraps an exchange so every cancel is confirmed to have
+// reached a terminal state, retrying cancels which silently fail and
+// reporting orders which filled before they could be cancelled
+type ConfirmedCancel struct {
+	IBotExchange
+	cfg      CancelConfig
+	onResult func(CancelResult)
+}
+
+// NewConfirmedCancel returns a wrapper confirming cancels on the supplied
+// exchange, onResult may be nil
+func NewConfirmedCancel(e IBotExchange, cfg CancelConfig, onResult func(CancelResult)) *ConfirmedCancel {
+	return &ConfirmedCancel{IBotExchange: e, cfg: cfg, onResult: onResult}
+}
+
+// Unwrap returns the underlying exchange
+func (c *ConfirmedCancel) Unwrap() IBotExchange {
+	return c.IBotExchange
+}
+
+// CheckOrder accepts every order, only cancels are confirmed
+func (c *ConfirmedCancel) CheckOrder(_ *OrderSubmission) error {
+	return nil
+}
+
+// CancelOrder cancels the order, returning once it is confirmed cancelled.
+// ErrCancelOrderFilled is returned for orders which filled first
+func (c *ConfirmedCancel) CancelOrder(o *OrderCancellation) error {
+	result, err := ConfirmCancel(c.IBotExchange, o, c.cfg)
+	if c.onResult != nil {
+		c.onResult(result)
+	}
+	return err
+}
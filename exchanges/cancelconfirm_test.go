@@ -0,0 +1,94 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testConfirmExchange struct {
+	IBotExchange
+	// statuses are returned by successive order fetches, the last repeating
+	statuses  []OrderDetail
+	fetches   int
+	cancels   int
+	cancelErr error
+	noInfo    bool
+	open      []OrderDetail
+}
+
+func (t *testConfirmExchange) GetName() string { return "Test" }
+
+func (t *testConfirmExchange) CancelOrder(_ *OrderCancellation) error {
+	t.cancels++
+	return t.cancelErr
+}
+
+func (t *testConfirmExchange) GetOrderInfo(orderID string) (OrderDetail, error) {
+	if t.noInfo {
+		return OrderDetail{}, common.ErrNotYetImplemented
+	}
+	o := t.statuses[len(t.statuses)-1]
+	if t.fetches < len(t.statuses) {
+		o = t.statuses[t.fetches]
+	}
+	t.fetches++
+	o.ID = orderID
+	return o, nil
+}
+
+func (t *testConfirmExchange) GetActiveOrders(_ *GetOrdersRequest) ([]OrderDetail, error) {
+	return t.open, nil
+}
+
+func TestConfirmCancel(t *testing.T) {
+	cfg := CancelConfig{Timeout: time.Millisecond * 5, PollInterval: time.Millisecond, Retries: 1}
+	c := &OrderCancellation{OrderID: "1", CurrencyPair: currency.NewPair(currency.BTC, currency.USD)}
+
+	e := &testConfirmExchange{statuses: []OrderDetail{
+		{Status: "PENDING_CANCEL", Amount: 2},
+		{Status: "canceled", Amount: 2, ExecutedAmount: 0.5},
+	}}
+	r, err := ConfirmCancel(e, c, cfg)
+	if err != nil || r.Outcome != CancelConfirmed || r.Filled != 0.5 || r.Attempts != 1 {
+		t.Errorf("Test Failed - ConfirmCancel() expected cancel confirmed %+v %v", r, err)
+	}
+
+	// A cancel racing a fill reports the order filled
+	e = &testConfirmExchange{
+		statuses:  []OrderDetail{{Status: "closed", Amount: 2, ExecutedAmount: 2}},
+		cancelErr: errors.New("EOrder:Unknown order"),
+	}
+	if r, err = ConfirmCancel(e, c, cfg); err != ErrCancelOrderFilled || r.Outcome != CancelOrderFilled {
+		t.Errorf("Test Failed - ConfirmCancel() expected order filled %+v %v", r, err)
+	}
+
+	// A cancel which silently fails is retried
+	e = &testConfirmExchange{statuses: []OrderDetail{{Status: "open", Amount: 2}}}
+	if r, err = ConfirmCancel(e, c, cfg); err == nil || r.Outcome != CancelUnconfirmed || e.cancels != 2 {
+		t.Errorf("Test Failed - ConfirmCancel() expected unconfirmed after retry %+v %v", r, err)
+	}
+
+	// Exchanges which cannot fetch orders are checked against open orders
+	e = &testConfirmExchange{noInfo: true, open: []OrderDetail{{ID: "2"}}}
+	if r, err = ConfirmCancel(e, c, cfg); err != nil || r.Outcome != CancelConfirmed {
+		t.Errorf("Test Failed - ConfirmCancel() expected cancel confirmed from open orders %+v %v", r, err)
+	}
+	e.cancelErr = errors.New("order not found")
+	if _, err = ConfirmCancel(e, c, cfg); err != ErrCancelOrderFilled {
+		t.Error("Test Failed - ConfirmCancel() expected closed order refusing cancel filled", err)
+	}
+
+	var results []CancelResult
+	w := NewConfirmedCancel(&testConfirmExchange{statuses: []OrderDetail{{Status: "CANCELLED"}}}, cfg,
+		func(r CancelResult) { results = append(results, r) })
+	if err = w.CancelOrder(c); err != nil || len(results) != 1 || results[0].OrderID != "1" {
+		t.Errorf("Test Failed - CancelOrder() expected confirmed result %+v %v", results, err)
+	}
+	if Underlying(w).GetName() != "Test" {
+		t.Error("Test Failed - Unwrap() did not return the underlying exchange")
+	}
+}
@@ -0,0 +1,33 @@
+package exchange
+
+import (
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
+)
+
+// ErrCandleIntervalUnsupported is returned when an exchange does not offer
+// candles at the requested interval
+var ErrCandleIntervalUnsupported = kline.ErrUnsupportedInterval
+
+// Candle holds open, high, low, close and volume data for an interval, every
+// exchange returns its candles as kline items
+type Candle = kline.Item
+
+// CandleProvider is implemented by exchanges which provide historic candles
+type CandleProvider interface {
+	// GetHistoricCandles returns up to limit of the most recent candles at the
+	// interval, ordered from oldest to newest
+	GetHistoricCandles(p currency.Pair, assetType string, interval kline.Interval, limit int) ([]Candle, error)
+}
+
+// CandleRangeProvider is implemented by exchanges which provide historic
+// candles between two times
+type CandleRangeProvider interface {
+	// GetHistoricCandlesRange returns candles at the interval opening at or
+	// after start and before end, ordered from oldest to newest. Exchanges
+	// limit the candles returned per request, callers page through ranges by
+	// requesting again from after the last candle returned
+	GetHistoricCandlesRange(p currency.Pair, assetType string, interval kline.Interval, start, end time.Time) ([]Candle, error)
+}
@@ -0,0 +1,340 @@
+// Package candlestore persists exchange candles to a local SQLite or Postgres
+// database. Candles are served from the database and only the gaps missing
+// from it are downloaded, so repeated queries do not hit exchange REST
+// endpoints. Enabled pairs are kept up to date incrementally by a sync loop
+package candlestore
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default candle store settings
+const (
+	DefaultSyncInterval = time.Minute * 5
+	DefaultBackfill     = time.Hour * 24 * 7
+	DefaultAssetType    = "SPOT"
+
+	// maxRecentCandles is the most candles filled from exchanges only
+	// offering their most recent candles
+	maxRecentCandles = 1000
+)
+
+// DefaultIntervals are synced when none are configured
+var DefaultIntervals = []kline.Interval{kline.OneHour}
+
+var (
+	errNoStore           = errors.New("no candle store supplied")
+	errUnsupportedDriver = errors.New("unsupported database driver, expected sqlite3 or postgres")
+	errExchangeNotFound  = errors.New("exchange not found")
+	errNoCandles         = errors.New("exchange does not provide historical candles")
+	errRangeUnsupported  = errors.New("exchange only provides its most recent candles, gap cannot be filled")
+	errInvalidInterval   = errors.New("candle interval must be positive")
+	errInvalidRange      = errors.New("candle range start must be before its end")
+)
+
+// Series identifies the candles of a pair at an interval on an exchange
+type Series struct {
+	Exchange  string
+	Base      string
+	Quote     string
+	AssetType string
+	Interval  kline.Interval
+}
+
+// NewSeries returns the series of a pair, the asset type defaults to spot
+func NewSeries(exchangeName string, p currency.Pair, assetType string, interval kline.Interval) Series {
+	if assetType == "" {
+		assetType = DefaultAssetType
+	}
+	return Series{
+		Exchange:  strings.ToLower(exchangeName),
+		Base:      p.Base.Upper().String(),
+		Quote:     p.Quote.Upper().String(),
+		AssetType: strings.ToUpper(assetType),
+		Interval:  interval,
+	}
+}
+
+func (s *Series) String() string {
+	return fmt.Sprintf("%s %s-%s %s %s", s.Exchange, s.Base, s.Quote, s.AssetType, s.Interval)
+}
+
+// Config defines the pairs synced
+type Config struct {
+	// Intervals are synced for every enabled pair
+	Intervals []kline.Interval
+	// Backfill is how far back candles are downloaded on the first sync
+	Backfill  time.Duration
+	AssetType string
+}
+
+// span is a range of candle open times missing from the store
+type span struct {
+	start time.Time
+	end   time.Time
+}
+
+// Manager serves candles from the store, filling gaps from the exchanges
+type Manager struct {
+	store     Store
+	exchanges []exchange.IBotExchange
+	cfg       Config
+	// listed holds the first candle of series whose history starts after
+	// the ranges requested and empty the gaps the exchange returned no
+	// candles for, neither are requested again
+	listed   map[Series]time.Time
+	empty    map[string]bool
+	now      func() time.Time
+	fill     sync.Mutex
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a candle manager storing the candles of the exchanges
+func New(store Store, exchanges []exchange.IBotExchange, cfg Config) (*Manager, error) {
+	if store == nil {
+		return nil, errNoStore
+	}
+	if len(cfg.Intervals) == 0 {
+		cfg.Intervals = DefaultIntervals
+	}
+	for _, i := range cfg.Intervals {
+		if i <= 0 {
+			return nil, errInvalidInterval
+		}
+	}
+	if cfg.Backfill <= 0 {
+		cfg.Backfill = DefaultBackfill
+	}
+	if cfg.AssetType == "" {
+		cfg.AssetType = DefaultAssetType
+	}
+	return &Manager{
+		store:     store,
+		exchanges: exchanges,
+		cfg:       cfg,
+		listed:    make(map[Series]time.Time),
+		empty:     make(map[string]bool),
+		now:       time.Now,
+	}, nil
+}
+
+// GetCandles returns the closed candles of a pair at the interval opening
+// between from and to, downloading those missing from the store
+func (m *Manager) GetCandles(exchangeName string, p currency.Pair, interval kline.Interval, from, to time.Time) ([]exchange.Candle, error) {
+	if interval <= 0 {
+		return nil, errInvalidInterval
+	}
+	if !from.Before(to) {
+		return nil, errInvalidRange
+	}
+	s := NewSeries(exchangeName, p, m.cfg.AssetType, interval)
+
+	// Candles still open are not stored
+	from = interval.Truncate(from)
+	closed := interval.Truncate(m.now())
+	if to.After(closed) {
+		to = closed
+	}
+	if !from.Before(to) {
+		return nil, nil
+	}
+
+	candles, err := m.store.Candles(s, from, to)
+	if err != nil {
+		return nil, err
+	}
+	gaps := missing(candles, from, to, interval.Duration())
+	if len(gaps) == 0 {
+		return candles, nil
+	}
+
+	m.fill.Lock()
+	filled := 0
+	for i := range gaps {
+		k := s.String() + " " + gaps[i].start.String() + " " + gaps[i].end.String()
+		if l, ok := m.listed[s]; (ok && !gaps[i].end.After(l)) || m.empty[k] {
+			continue
+		}
+		var stored []exchange.Candle
+		stored, err = m.fillGap(s, p, gaps[i], gaps[i].end.Equal(closed))
+		if err != nil {
+			break
+		}
+		switch {
+		case len(stored) == 0:
+			m.empty[k] = true
+		case len(candles) == 0 && i == 0 && stored[0].Time.After(gaps[i].start):
+			// Nothing was stored before the first candle of an empty range
+			// returned, the pair was listed at that candle
+			m.listed[s] = stored[0].Time
+		}
+		filled += len(stored)
+	}
+	m.fill.Unlock()
+	if err != nil {
+		return nil, fmt.Errorf("%s: %v", s.String(), err)
+	}
+	if filled == 0 {
+		return candles, nil
+	}
+	return m.store.Candles(s, from, to)
+}
+
+// missing returns the ranges between from and to not covered by the candles
+func missing(candles []exchange.Candle, from, to time.Time, interval time.Duration) []span {
+	var gaps []span
+	next := from
+	for i := range candles {
+		if candles[i].Time.Sub(next) >= interval {
+			gaps = append(gaps, span{start: next, end: candles[i].Time})
+		}
+		next = candles[i].Time.Add(interval)
+	}
+	if to.Sub(next) >= interval {
+		gaps = append(gaps, span{start: next, end: to})
+	}
+	return gaps
+}
+
+// fillGap downloads and stores the candles of a gap, returning those stored.
+// Gaps reaching the latest closed candle are filled from exchanges
+// only offering their most recent candles
+func (m *Manager) fillGap(s Series, p currency.Pair, gap span, recent bool) ([]exchange.Candle, error) {
+	e := m.getExchange(s.Exchange)
+	if e == nil {
+		return nil, errExchangeNotFound
+	}
+	switch provider := exchange.Underlying(e).(type) {
+	case exchange.CandleRangeProvider:
+		var filled []exchange.Candle
+		for start := gap.start; start.Before(gap.end); {
+			candles, err := provider.GetHistoricCandlesRange(p, s.AssetType, s.Interval, start, gap.end)
+			if err != nil {
+				return filled, err
+			}
+			candles = within(candles, start, gap.end)
+			if len(candles) == 0 {
+				break
+			}
+			if err = m.store.Insert(s, candles); err != nil {
+				return filled, err
+			}
+			filled = append(filled, candles...)
+			start = candles[len(candles)-1].Time.Add(s.Interval.Duration())
+		}
+		return filled, nil
+	case exchange.CandleProvider:
+		n := int(gap.end.Sub(gap.start) / s.Interval.Duration())
+		if !recent || n > maxRecentCandles {
+			return nil, errRangeUnsupported
+		}
+		// The most recent candle may still be open
+		candles, err := provider.GetHistoricCandles(p, s.AssetType, s.Interval, n+1)
+		if err != nil {
+			return nil, err
+		}
+		candles = within(candles, gap.start, gap.end)
+		return candles, m.store.Insert(s, candles)
+	}
+	return nil, errNoCandles
+}
+
+// within returns the candles opening between start and end
+func within(candles []exchange.Candle, start, end time.Time) []exchange.Candle {
+	var resp []exchange.Candle
+	for i := range candles {
+		if !candles[i].Time.Before(start) && candles[i].Time.Before(end) {
+			resp = append(resp, candles[i])
+		}
+	}
+	return resp
+}
+
+func (m *Manager) getExchange(name string) exchange.IBotExchange {
+	for i := range m.exchanges {
+		if strings.EqualFold(m.exchanges[i].GetName(), name) {
+			return m.exchanges[i]
+		}
+	}
+	return nil
+}
+
+// Sync downloads the candles missing over the backfill period for every
+// enabled pair of the exchanges providing candles
+func (m *Manager) Sync() {
+	to := m.now()
+	from := to.Add(-m.cfg.Backfill)
+	for i := range m.exchanges {
+		if !m.exchanges[i].IsEnabled() {
+			continue
+		}
+		switch exchange.Underlying(m.exchanges[i]).(type) {
+		case exchange.CandleRangeProvider, exchange.CandleProvider:
+		default:
+			continue
+		}
+		pairs := m.exchanges[i].GetEnabledCurrencies()
+		for j := range pairs {
+			for _, interval := range m.cfg.Intervals {
+				_, err := m.GetCandles(m.exchanges[i].GetName(), pairs[j], interval, from, to)
+				if err != nil {
+					log.Warnf("Candle store failed to sync: %s", err)
+				}
+			}
+		}
+	}
+}
+
+// Start syncs the enabled pairs at the interval until stopped
+func (m *Manager) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultSyncInterval
+	}
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		m.Sync()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				m.Sync()
+			}
+		}
+	}()
+}
+
+// Stop stops syncing and closes the store
+func (m *Manager) Stop() error {
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		close(m.shutdown)
+		m.shutdown = nil
+	}
+	m.mtx.Unlock()
+	m.wg.Wait()
+	return m.store.Close()
+}
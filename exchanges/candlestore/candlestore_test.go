@@ -0,0 +1,216 @@
+package candlestore
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
+)
+
+type memoryStore struct {
+	candles map[Series]map[int64]exchange.Candle
+}
+
+func (m *memoryStore) Insert(s Series, candles []exchange.Candle) error {
+	if m.candles[s] == nil {
+		m.candles[s] = make(map[int64]exchange.Candle)
+	}
+	for i := range candles {
+		m.candles[s][candles[i].Time.Unix()] = candles[i]
+	}
+	return nil
+}
+
+func (m *memoryStore) Candles(s Series, from, to time.Time) ([]exchange.Candle, error) {
+	var resp []exchange.Candle
+	for t := from.Unix(); t < to.Unix(); t += s.Interval.Seconds() {
+		if c, ok := m.candles[s][t]; ok {
+			resp = append(resp, c)
+		}
+	}
+	return resp, nil
+}
+
+func (m *memoryStore) Close() error { return nil }
+
+// rangeExchange serves hourly candles from its listing time, five per request
+type rangeExchange struct {
+	exchange.IBotExchange
+	listed   time.Time
+	requests int
+	err      error
+}
+
+func (r *rangeExchange) GetName() string { return "Binance" }
+
+func (r *rangeExchange) GetHistoricCandlesRange(_ currency.Pair, _ string, interval kline.Interval, start, end time.Time) ([]exchange.Candle, error) {
+	r.requests++
+	if r.err != nil {
+		return nil, r.err
+	}
+	var candles []exchange.Candle
+	for t := start; t.Before(end) && len(candles) < 5; t = t.Add(interval.Duration()) {
+		if t.Before(r.listed) {
+			continue
+		}
+		candles = append(candles, exchange.Candle{Time: t, Close: float64(t.Hour())})
+	}
+	return candles, nil
+}
+
+// recentExchange only serves its most recent candles
+type recentExchange struct {
+	exchange.IBotExchange
+	now time.Time
+}
+
+func (r *recentExchange) GetName() string { return "Poloniex" }
+
+func (r *recentExchange) GetHistoricCandles(_ currency.Pair, _ string, interval kline.Interval, limit int) ([]exchange.Candle, error) {
+	var candles []exchange.Candle
+	t := interval.Truncate(r.now).Add(-interval.Duration() * time.Duration(limit-1))
+	for ; !t.After(r.now); t = t.Add(interval.Duration()) {
+		candles = append(candles, exchange.Candle{Time: t, Close: 1})
+	}
+	return candles, nil
+}
+
+func TestMissing(t *testing.T) {
+	from := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	at := func(h int) time.Time { return from.Add(time.Hour * time.Duration(h)) }
+	candles := []exchange.Candle{{Time: at(2)}, {Time: at(3)}, {Time: at(6)}}
+	gaps := missing(candles, from, at(10), time.Hour)
+	want := []span{{at(0), at(2)}, {at(4), at(6)}, {at(7), at(10)}}
+	if len(gaps) != len(want) {
+		t.Fatalf("Test Failed - missing() expected %v got %v", want, gaps)
+	}
+	for i := range want {
+		if !gaps[i].start.Equal(want[i].start) || !gaps[i].end.Equal(want[i].end) {
+			t.Errorf("Test Failed - missing() expected %v got %v", want[i], gaps[i])
+		}
+	}
+	if gaps = missing(candles[:2], at(2), at(4), time.Hour); len(gaps) != 0 {
+		t.Errorf("Test Failed - missing() expected no gaps %v", gaps)
+	}
+}
+
+func TestGetCandles(t *testing.T) {
+	if _, err := New(nil, nil, Config{}); err != errNoStore {
+		t.Error("Test Failed - New() expected no store error", err)
+	}
+	store := &memoryStore{candles: make(map[Series]map[int64]exchange.Candle)}
+	now := time.Date(2019, 6, 2, 0, 30, 0, 0, time.UTC)
+	binance := &rangeExchange{listed: now.Add(-time.Hour * 10).Truncate(time.Hour)}
+	poloniex := &recentExchange{now: now}
+	m, err := New(store, []exchange.IBotExchange{binance, poloniex}, Config{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.now = func() time.Time { return now }
+	btc := currency.NewPairFromString("BTCUSDT")
+
+	if _, err = m.GetCandles("Binance", btc, kline.OneHour, now, now.Add(-time.Hour)); err != errInvalidRange {
+		t.Error("Test Failed - GetCandles() expected invalid range error", err)
+	}
+
+	// Candles are paged from the exchange, the open candle is excluded
+	candles, err := m.GetCandles("binance", btc, kline.OneHour, now.Add(-time.Hour*12), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 10 || !candles[9].Time.Equal(now.Truncate(time.Hour).Add(-time.Hour)) {
+		t.Fatalf("Test Failed - GetCandles() expected ten closed candles %+v", candles)
+	}
+	if binance.requests != 2 {
+		t.Errorf("Test Failed - GetCandles() expected two pages got %d", binance.requests)
+	}
+
+	// Stored candles are not downloaded again, nor are ranges before listing
+	if _, err = m.GetCandles("binance", btc, kline.OneHour, now.Add(-time.Hour*12), now); err != nil {
+		t.Fatal(err)
+	}
+	if binance.requests != 2 {
+		t.Errorf("Test Failed - GetCandles() expected stored candles served %d", binance.requests)
+	}
+
+	// Only the new candle is downloaded once the next closes
+	now = now.Add(time.Hour)
+	binance.err = errors.New("rate limited")
+	if _, err = m.GetCandles("binance", btc, kline.OneHour, now.Add(-time.Hour*12), now); err == nil {
+		t.Error("Test Failed - GetCandles() expected exchange error")
+	}
+	binance.err = nil
+	candles, err = m.GetCandles("binance", btc, kline.OneHour, now.Add(-time.Hour*12), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 11 || binance.requests != 4 {
+		t.Errorf("Test Failed - GetCandles() expected the new candle filled %d %d", len(candles), binance.requests)
+	}
+
+	// Exchanges offering recent candles fill gaps reaching the latest candle
+	poloniex.now = now
+	candles, err = m.GetCandles("poloniex", btc, kline.OneHour, now.Add(-time.Hour*4), now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(candles) != 4 {
+		t.Errorf("Test Failed - GetCandles() expected four recent candles %+v", candles)
+	}
+	if _, err = m.GetCandles("poloniex", btc, kline.OneHour, now.Add(-time.Hour*8), now.Add(-time.Hour*6)); err == nil {
+		t.Error("Test Failed - GetCandles() expected range unsupported error")
+	}
+	if _, err = m.GetCandles("kraken", btc, kline.OneHour, now.Add(-time.Hour), now); err == nil {
+		t.Error("Test Failed - GetCandles() expected exchange not found error")
+	}
+}
+
+func TestRebind(t *testing.T) {
+	q := "SELECT a FROM b WHERE c = ? AND d = ?"
+	if r := rebind(SQLite, q); r != q {
+		t.Error("Test Failed - rebind() expected sqlite placeholders unchanged", r)
+	}
+	if r := rebind(Postgres, q); r != "SELECT a FROM b WHERE c = $1 AND d = $2" {
+		t.Error("Test Failed - rebind() expected postgres placeholders", r)
+	}
+	if _, err := Open("mysql", ""); err == nil {
+		t.Error("Test Failed - Open() expected unsupported driver error")
+	}
+}
+
+func TestSQLStore(t *testing.T) {
+	s, err := Open(SQLite, ":memory:")
+	if err != nil {
+		t.Fatal("Test Failed - Open() error", err)
+	}
+	defer s.Close()
+
+	series := Series{Exchange: "Binance", Base: "BTC", Quote: "USDT", AssetType: "SPOT", Interval: kline.OneHour}
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	candles := []exchange.Candle{
+		{Time: start, Open: 1, High: 2, Low: 0.5, Close: 1.5, Volume: 10},
+		{Time: start.Add(time.Hour), Open: 1.5, High: 3, Low: 1, Close: 2, Volume: 20},
+	}
+	if err = s.Insert(series, candles); err != nil {
+		t.Fatal("Test Failed - Insert() error", err)
+	}
+	// Replaces the open candle stored previously
+	candles[1].Close = 2.5
+	if err = s.Insert(series, candles[1:]); err != nil {
+		t.Fatal("Test Failed - Insert() error", err)
+	}
+
+	stored, err := s.Candles(series, start, start.Add(time.Hour*2))
+	if err != nil {
+		t.Fatal("Test Failed - Candles() error", err)
+	}
+	if len(stored) != 2 || !stored[0].Time.Equal(start) || stored[1].Close != 2.5 || stored[1].Volume != 20 {
+		t.Errorf("Test Failed - Candles() unexpected candles %+v", stored)
+	}
+	if stored, err = s.Candles(series, start.Add(time.Hour), start.Add(time.Hour*2)); err != nil || len(stored) != 1 {
+		t.Errorf("Test Failed - Candles() expected one candle %+v %v", stored, err)
+	}
+}
@@ -0,0 +1,8 @@
+package candlestore
+
+import (
+	// Registers the sqlite3 database/sql driver, requires cgo
+	_ "github.com/mattn/go-sqlite3"
+	// Registers the postgres database/sql driver
+	_ "github.com/lib/pq"
+)
@@ -0,0 +1,167 @@
+package candlestore
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// Supported database drivers, both registered by drivers.go
+const (
+	SQLite   = "sqlite3"
+	Postgres = "postgres"
+)
+
+const schema = `CREATE TABLE IF NOT EXISTS candles (
+	exchange TEXT NOT NULL,
+	base TEXT NOT NULL,
+	quote TEXT NOT NULL,
+	asset_type TEXT NOT NULL,
+	interval_seconds BIGINT NOT NULL,
+	open_time BIGINT NOT NULL,
+	open DOUBLE PRECISION NOT NULL,
+	high DOUBLE PRECISION NOT NULL,
+	low DOUBLE PRECISION NOT NULL,
+	close DOUBLE PRECISION NOT NULL,
+	volume DOUBLE PRECISION NOT NULL,
+	PRIMARY KEY (exchange, base, quote, asset_type, interval_seconds, open_time)
+)`
+
+// Upserts replace candles stored while still open, both SQLite 3.24 and
+// Postgres 9.5 onwards support ON CONFLICT
+const insertCandle = `INSERT INTO candles
+	(exchange, base, quote, asset_type, interval_seconds, open_time, open, high, low, close, volume)
+	VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+	ON CONFLICT (exchange, base, quote, asset_type, interval_seconds, open_time) DO UPDATE SET
+	open = excluded.open, high = excluded.high, low = excluded.low, close = excluded.close, volume = excluded.volume`
+
+const selectCandles = `SELECT open_time, open, high, low, close, volume FROM candles
+	WHERE exchange = ? AND base = ? AND quote = ? AND asset_type = ? AND interval_seconds = ?
+	AND open_time >= ? AND open_time < ? ORDER BY open_time`
+
+// Store persists candles to a SQLite or Postgres database
+type Store interface {
+	// Insert stores the candles of a series, replacing those already stored
+	Insert(s Series, candles []exchange.Candle) error
+	// Candles returns the stored candles of a series opening at or after from
+	// and before to, ordered from oldest to newest
+	Candles(s Series, from, to time.Time) ([]exchange.Candle, error)
+	Close() error
+}
+
+// SQLStore stores candles in a database/sql database
+type SQLStore struct {
+	db     *sql.DB
+	driver string
+}
+
+// Open opens the database with the driver and migrates its schema
+func Open(driver, dsn string) (*SQLStore, error) {
+	if driver != SQLite && driver != Postgres {
+		return nil, fmt.Errorf("%v: %s", errUnsupportedDriver, driver)
+	}
+	db, err := sql.Open(driver, dsn)
+	if err != nil {
+		return nil, err
+	}
+	if driver == SQLite {
+		// SQLite allows a single writer and every connection to :memory:
+		// opens a separate database
+		db.SetMaxOpenConns(1)
+	}
+	s, err := NewSQLStore(db, driver)
+	if err != nil {
+		db.Close()
+		return nil, err
+	}
+	return s, nil
+}
+
+// NewSQLStore returns a store using the opened database, creating the candles
+// table if it does not exist
+func NewSQLStore(db *sql.DB, driver string) (*SQLStore, error) {
+	if driver != SQLite && driver != Postgres {
+		return nil, fmt.Errorf("%v: %s", errUnsupportedDriver, driver)
+	}
+	if _, err := db.Exec(schema); err != nil {
+		return nil, err
+	}
+	return &SQLStore{db: db, driver: driver}, nil
+}
+
+// rebind converts ? placeholders to the $n placeholders Postgres expects
+func rebind(driver, query string) string {
+	if driver != Postgres {
+		return query
+	}
+	var b strings.Builder
+	n := 0
+	for _, r := range query {
+		if r != '?' {
+			b.WriteRune(r)
+			continue
+		}
+		n++
+		b.WriteString("$" + strconv.Itoa(n))
+	}
+	return b.String()
+}
+
+// Insert stores the candles of a series in one transaction
+func (s *SQLStore) Insert(series Series, candles []exchange.Candle) error {
+	if len(candles) == 0 {
+		return nil
+	}
+	tx, err := s.db.Begin()
+	if err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(rebind(s.driver, insertCandle))
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	defer stmt.Close()
+	for i := range candles {
+		_, err = stmt.Exec(series.Exchange, series.Base, series.Quote, series.AssetType,
+			series.Interval.Seconds(), candles[i].Time.Unix(),
+			candles[i].Open, candles[i].High, candles[i].Low, candles[i].Close, candles[i].Volume)
+		if err != nil {
+			tx.Rollback()
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// Candles returns the stored candles of a series between from and to
+func (s *SQLStore) Candles(series Series, from, to time.Time) ([]exchange.Candle, error) {
+	rows, err := s.db.Query(rebind(s.driver, selectCandles),
+		series.Exchange, series.Base, series.Quote, series.AssetType,
+		series.Interval.Seconds(), from.Unix(), to.Unix())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var candles []exchange.Candle
+	for rows.Next() {
+		var c exchange.Candle
+		var t int64
+		if err = rows.Scan(&t, &c.Open, &c.High, &c.Low, &c.Close, &c.Volume); err != nil {
+			return nil, err
+		}
+		c.Time = time.Unix(t, 0).UTC()
+		candles = append(candles, c)
+	}
+	return candles, rows.Err()
+}
+
+// Close closes the database
+func (s *SQLStore) Close() error {
+	return s.db.Close()
+}
@@ -0,0 +1,60 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// ErrChainNotFound is returned when an asset cannot be transferred on the
+// requested chain
+var ErrChainNotFound = errors.New("chain not found")
+
+// ChainInfo holds the transfer rules of an asset on one of its chains
+type ChainInfo struct {
+	// Chain is the exchange's name for the chain, used to request deposit
+	// addresses and withdrawals on it
+	Chain           string  `json:"chain"`
+	Network         string  `json:"network,omitempty"`
+	DepositEnabled  bool    `json:"depositEnabled"`
+	WithdrawEnabled bool    `json:"withdrawEnabled"`
+	MinDeposit      float64 `json:"minDeposit"`
+	MinWithdraw     float64 `json:"minWithdraw"`
+	MaxWithdraw     float64 `json:"maxWithdraw,omitempty"`
+	WithdrawFee     float64 `json:"withdrawFee"`
+	// WithdrawPrecision is the number of decimal places withdrawal amounts
+	// are accepted to
+	WithdrawPrecision int   `json:"withdrawPrecision"`
+	Confirmations     int64 `json:"confirmations"`
+}
+
+// ChainDepositAddress holds a deposit address of an asset on a chain
+type ChainDepositAddress struct {
+	Currency currency.Code `json:"currency"`
+	Chain    string        `json:"chain"`
+	Address  string        `json:"address"`
+	// Tag is the memo or destination tag required by some chains
+	Tag string `json:"tag,omitempty"`
+}
+
+// ChainProvider is implemented by exchanges transferring assets on several
+// chains
+type ChainProvider interface {
+	GetChainInfo(c currency.Code) ([]ChainInfo, error)
+	// GetChainDepositAddress returns the deposit address on the chain, the
+	// exchange's default chain is used when empty
+	GetChainDepositAddress(c currency.Code, chain string) (ChainDepositAddress, error)
+}
+
+// FindChain returns the chain matching either its name or network, e.g.
+// usdterc20 or ERC20
+func FindChain(chains []ChainInfo, chain string) (ChainInfo, error) {
+	for i := range chains {
+		if strings.EqualFold(chains[i].Chain, chain) || strings.EqualFold(chains[i].Network, chain) {
+			return chains[i], nil
+		}
+	}
+	return ChainInfo{}, fmt.Errorf("%s %v", chain, ErrChainNotFound)
+}
@@ -0,0 +1,24 @@
+package exchange
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindChain(t *testing.T) {
+	chains := []ChainInfo{
+		{Chain: "usdt", Network: "OMNI"},
+		{Chain: "usdterc20", Network: "ERC20", DepositEnabled: true},
+	}
+	c, err := FindChain(chains, "erc20")
+	if err != nil || c.Chain != "usdterc20" {
+		t.Errorf("Test Failed - FindChain() expected usdterc20 by network %+v %v", c, err)
+	}
+	c, err = FindChain(chains, "USDT")
+	if err != nil || c.Network != "OMNI" {
+		t.Errorf("Test Failed - FindChain() expected usdt by chain %+v %v", c, err)
+	}
+	if _, err = FindChain(chains, "trc20usdt"); err == nil || !strings.Contains(err.Error(), ErrChainNotFound.Error()) {
+		t.Errorf("Test Failed - FindChain() expected chain not found, received %v", err)
+	}
+}
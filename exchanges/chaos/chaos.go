@@ -0,0 +1,313 @@
+// Package chaos injects artificial latency, dropped websocket frames, HTTP
+// error responses and partial outages into the exchange request and websocket
+// layers. It is used to verify reconnect, retry and risk logic behaves
+// correctly before trading live and must never be enabled in production
+package chaos
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var (
+	errInvalidProbability = errors.New("chaos probabilities must be between 0 and 1")
+	errInvalidDuration    = errors.New("chaos durations cannot be negative")
+	errInvalidSetting     = errors.New("chaos settings must be in the format key=value")
+
+	// ErrOutage is returned for requests made during an injected outage
+	ErrOutage = errors.New("chaos injected outage")
+)
+
+// injectedStatuses are the server error codes returned for injected errors
+var injectedStatuses = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// Config defines the faults injected. Probabilities are applied per request
+// or per websocket frame
+type Config struct {
+	// Exchanges limits injection to the named exchanges, when empty every
+	// exchange is affected
+	Exchanges []string `json:"exchanges,omitempty"`
+	// Latency is added to every request and frame, varied by up to
+	// LatencyJitter either side
+	Latency       time.Duration `json:"latency"`
+	LatencyJitter time.Duration `json:"latencyJitter"`
+	// ServerErrorRate is the probability of a HTTP 5xx response
+	ServerErrorRate float64 `json:"serverErrorRate"`
+	// RateLimitRate is the probability of a HTTP 429 response
+	RateLimitRate float64 `json:"rateLimitRate"`
+	// RetryAfter is returned with injected 429 responses
+	RetryAfter time.Duration `json:"retryAfter"`
+	// DropFrameRate is the probability of a websocket frame being dropped
+	DropFrameRate float64 `json:"dropFrameRate"`
+	// OutageRate is the probability of an exchange outage starting, during
+	// which requests fail and websocket connections are dropped
+	OutageRate     float64       `json:"outageRate"`
+	OutageDuration time.Duration `json:"outageDuration"`
+	// Seed makes injection reproducible, a zero seed is time based
+	Seed int64 `json:"seed"`
+}
+
+func (c *Config) validate() error {
+	for _, p := range []float64{c.ServerErrorRate, c.RateLimitRate, c.DropFrameRate, c.OutageRate} {
+		if p < 0 || p > 1 {
+			return errInvalidProbability
+		}
+	}
+	if c.Latency < 0 || c.LatencyJitter < 0 || c.RetryAfter < 0 || c.OutageDuration < 0 {
+		return errInvalidDuration
+	}
+	return nil
+}
+
+// ParseConfig parses a comma separated list of key=value settings, e.g.
+// latency=200ms,jitter=50ms,5xx=0.05,429=0.02,drop=0.01,outage=0.001,
+// outageduration=30s,exchanges=Bitstamp|Kraken
+func ParseConfig(s string) (Config, error) {
+	var c Config
+	for _, setting := range strings.Split(s, ",") {
+		kv := strings.SplitN(strings.TrimSpace(setting), "=", 2)
+		if len(kv) != 2 || kv[1] == "" {
+			return Config{}, errInvalidSetting
+		}
+		var err error
+		switch strings.ToLower(kv[0]) {
+		case "exchanges":
+			c.Exchanges = strings.Split(kv[1], "|")
+		case "latency":
+			c.Latency, err = time.ParseDuration(kv[1])
+		case "jitter":
+			c.LatencyJitter, err = time.ParseDuration(kv[1])
+		case "5xx":
+			c.ServerErrorRate, err = strconv.ParseFloat(kv[1], 64)
+		case "429":
+			c.RateLimitRate, err = strconv.ParseFloat(kv[1], 64)
+		case "retryafter":
+			c.RetryAfter, err = time.ParseDuration(kv[1])
+		case "drop":
+			c.DropFrameRate, err = strconv.ParseFloat(kv[1], 64)
+		case "outage":
+			c.OutageRate, err = strconv.ParseFloat(kv[1], 64)
+		case "outageduration":
+			c.OutageDuration, err = time.ParseDuration(kv[1])
+		case "seed":
+			c.Seed, err = strconv.ParseInt(kv[1], 10, 64)
+		default:
+			return Config{}, fmt.Errorf("unknown chaos setting %s", kv[0])
+		}
+		if err != nil {
+			return Config{}, fmt.Errorf("chaos setting %s: %v", kv[0], err)
+		}
+	}
+	return c, c.validate()
+}
+
+// Stats counts the faults injected
+type Stats struct {
+	Requests       int64
+	Frames         int64
+	ServerErrors   int64
+	RateLimits     int64
+	DroppedFrames  int64
+	Outages        int64
+	OutageFailures int64
+	Disconnects    int64
+}
+
+// Injector decides which faults to inject
+type Injector struct {
+	cfg     Config
+	rnd     *rand.Rand
+	outages map[string]time.Time
+	stats   Stats
+	mtx     sync.Mutex
+}
+
+// New returns a fault injector
+func New(cfg Config) (*Injector, error) {
+	err := cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{
+		cfg:     cfg,
+		rnd:     rand.New(rand.NewSource(seed)),
+		outages: make(map[string]time.Time),
+	}, nil
+}
+
+var (
+	active    *Injector
+	activeMtx sync.RWMutex
+)
+
+// Enable sets the injector used by the request and websocket layers, nil
+// disables injection
+func Enable(i *Injector) {
+	activeMtx.Lock()
+	active = i
+	activeMtx.Unlock()
+	if i != nil {
+		log.Warnf("Chaos fault injection enabled, requests and websocket data will be disrupted")
+	}
+}
+
+// Active returns the enabled injector, or nil when injection is disabled
+func Active() *Injector {
+	activeMtx.RLock()
+	defer activeMtx.RUnlock()
+	return active
+}
+
+// Applies returns whether faults are injected for the exchange
+func (i *Injector) Applies(exchangeName string) bool {
+	if len(i.cfg.Exchanges) == 0 {
+		return true
+	}
+	return common.StringDataCompareInsensitive(i.cfg.Exchanges, exchangeName)
+}
+
+// GetStats returns the faults injected so far
+func (i *Injector) GetStats() Stats {
+	i.mtx.Lock()
+	defer i.mtx.Unlock()
+	return i.stats
+}
+
+// roll returns true with the supplied probability, the caller must hold the
+// lock
+func (i *Injector) roll(p float64) bool {
+	return p > 0 && i.rnd.Float64() < p
+}
+
+// delay returns the latency to inject, the caller must hold the lock
+func (i *Injector) delay() time.Duration {
+	d := i.cfg.Latency
+	if i.cfg.LatencyJitter > 0 {
+		d += time.Duration((2*i.rnd.Float64() - 1) * float64(i.cfg.LatencyJitter))
+	}
+	if d < 0 {
+		return 0
+	}
+	return d
+}
+
+// inOutage returns whether the exchange is in an outage, possibly starting a
+// new one. The caller must hold the lock
+func (i *Injector) inOutage(exchangeName string) bool {
+	k := strings.ToLower(exchangeName)
+	now := time.Now()
+	if until, ok := i.outages[k]; ok {
+		if now.Before(until) {
+			return true
+		}
+		delete(i.outages, k)
+	}
+	if i.cfg.OutageDuration > 0 && i.roll(i.cfg.OutageRate) {
+		i.outages[k] = now.Add(i.cfg.OutageDuration)
+		i.stats.Outages++
+		log.Warnf("Chaos injecting %s outage for %s", exchangeName, i.cfg.OutageDuration)
+		return true
+	}
+	return false
+}
+
+// Do performs a HTTP request through the injector, adding latency and
+// replacing the response with an outage error or injected error response as
+// configured
+func (i *Injector) Do(exchangeName string, req *http.Request, do func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if !i.Applies(exchangeName) {
+		return do(req)
+	}
+
+	i.mtx.Lock()
+	i.stats.Requests++
+	d := i.delay()
+	outage := i.inOutage(exchangeName)
+	var status int
+	switch {
+	case outage:
+		i.stats.OutageFailures++
+	case i.roll(i.cfg.RateLimitRate):
+		status = http.StatusTooManyRequests
+		i.stats.RateLimits++
+	case i.roll(i.cfg.ServerErrorRate):
+		status = injectedStatuses[i.rnd.Intn(len(injectedStatuses))]
+		i.stats.ServerErrors++
+	}
+	i.mtx.Unlock()
+
+	time.Sleep(d)
+	if outage {
+		return nil, fmt.Errorf("%s %s: %v", req.Method, req.URL.Host, ErrOutage)
+	}
+	if status == 0 {
+		return do(req)
+	}
+	return i.response(req, status), nil
+}
+
+// response builds an injected error response
+func (i *Injector) response(req *http.Request, status int) *http.Response {
+	body := fmt.Sprintf(`{"error":"chaos injected %d %s"}`, status, http.StatusText(status))
+	resp := &http.Response{
+		Status:        strconv.Itoa(status) + " " + http.StatusText(status),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        make(http.Header),
+		Body:          ioutil.NopCloser(bytes.NewBufferString(body)),
+		ContentLength: int64(len(body)),
+		Request:       req,
+	}
+	resp.Header.Set("Content-Type", "application/json")
+	if status == http.StatusTooManyRequests && i.cfg.RetryAfter > 0 {
+		resp.Header.Set("Retry-After", strconv.Itoa(int(i.cfg.RetryAfter.Seconds())))
+	}
+	return resp
+}
+
+// Frame applies injection to a received websocket frame. It returns whether
+// the frame should be dropped and whether the connection should be closed to
+// simulate an outage
+func (i *Injector) Frame(exchangeName string) (drop, disconnect bool) {
+	if !i.Applies(exchangeName) {
+		return false, false
+	}
+
+	i.mtx.Lock()
+	i.stats.Frames++
+	d := i.delay()
+	switch {
+	case i.inOutage(exchangeName):
+		disconnect = true
+		i.stats.Disconnects++
+	case i.roll(i.cfg.DropFrameRate):
+		drop = true
+		i.stats.DroppedFrames++
+	}
+	i.mtx.Unlock()
+
+	time.Sleep(d)
+	return drop, disconnect
+}
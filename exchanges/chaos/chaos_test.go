@@ -0,0 +1,175 @@
+package chaos
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func testServer() *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"ok":true}`))
+	}))
+}
+
+func doRequest(t *testing.T, i *Injector, exchangeName, url string) (*http.Response, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := i.Do(exchangeName, req, http.DefaultClient.Do)
+	if resp != nil {
+		resp.Body.Close()
+	}
+	return resp, err
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{ServerErrorRate: 1.1}); err != errInvalidProbability {
+		t.Error("Test Failed - New() expected invalid probability error", err)
+	}
+	if _, err := New(Config{DropFrameRate: -0.1}); err != errInvalidProbability {
+		t.Error("Test Failed - New() expected invalid probability error", err)
+	}
+	if _, err := New(Config{Latency: -time.Second}); err != errInvalidDuration {
+		t.Error("Test Failed - New() expected invalid duration error", err)
+	}
+}
+
+func TestEnable(t *testing.T) {
+	i, err := New(Config{})
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	Enable(i)
+	if Active() != i {
+		t.Error("Test Failed - Enable() injector not active")
+	}
+	Enable(nil)
+	if Active() != nil {
+		t.Error("Test Failed - Enable() injector still active")
+	}
+}
+
+func TestDo(t *testing.T) {
+	s := testServer()
+	defer s.Close()
+
+	i, err := New(Config{})
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	resp, err := doRequest(t, i, "test", s.URL)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Error("Test Failed - Do() expected request to pass through", err)
+	}
+
+	i, err = New(Config{ServerErrorRate: 1})
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	resp, err = doRequest(t, i, "test", s.URL)
+	if err != nil || resp.StatusCode < 500 {
+		t.Error("Test Failed - Do() expected server error response", err)
+	}
+
+	i, err = New(Config{RateLimitRate: 1, RetryAfter: time.Second * 5, Latency: time.Millisecond * 10})
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	start := time.Now()
+	resp, err = doRequest(t, i, "test", s.URL)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests || resp.Header.Get("Retry-After") != "5" {
+		t.Error("Test Failed - Do() expected rate limit response", err)
+	}
+	if time.Since(start) < time.Millisecond*10 {
+		t.Error("Test Failed - Do() latency not injected")
+	}
+
+	stats := i.GetStats()
+	if stats.Requests != 1 || stats.RateLimits != 1 {
+		t.Errorf("Test Failed - GetStats() unexpected stats %+v", stats)
+	}
+}
+
+func TestDoPartialOutage(t *testing.T) {
+	s := testServer()
+	defer s.Close()
+
+	i, err := New(Config{
+		Exchanges:      []string{"Bitstamp"},
+		OutageRate:     1,
+		OutageDuration: time.Hour,
+	})
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+
+	_, err = doRequest(t, i, "bitstamp", s.URL)
+	if err == nil || !strings.Contains(err.Error(), ErrOutage.Error()) {
+		t.Error("Test Failed - Do() expected outage error", err)
+	}
+	// Other exchanges remain reachable during the outage
+	resp, err := doRequest(t, i, "Kraken", s.URL)
+	if err != nil || resp.StatusCode != http.StatusOK {
+		t.Error("Test Failed - Do() unaffected exchange disrupted", err)
+	}
+
+	drop, disconnect := i.Frame("Bitstamp")
+	if drop || !disconnect {
+		t.Error("Test Failed - Frame() expected disconnect during outage")
+	}
+
+	stats := i.GetStats()
+	if stats.Outages != 1 || stats.OutageFailures != 1 || stats.Disconnects != 1 {
+		t.Errorf("Test Failed - GetStats() unexpected stats %+v", stats)
+	}
+}
+
+func TestFrame(t *testing.T) {
+	i, err := New(Config{DropFrameRate: 0.5, Seed: 1})
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	var dropped int
+	for x := 0; x < 1000; x++ {
+		drop, disconnect := i.Frame("test")
+		if disconnect {
+			t.Fatal("Test Failed - Frame() unexpected disconnect")
+		}
+		if drop {
+			dropped++
+		}
+	}
+	if dropped < 400 || dropped > 600 {
+		t.Errorf("Test Failed - Frame() dropped %d of 1000 frames at 50%%", dropped)
+	}
+}
+
+func TestParseConfig(t *testing.T) {
+	c, err := ParseConfig("latency=200ms, jitter=50ms,5xx=0.05,429=0.02,retryafter=2s,drop=0.01,outage=0.001,outageduration=30s,exchanges=Bitstamp|Kraken,seed=7")
+	if err != nil {
+		t.Fatal("Test Failed - ParseConfig() error", err)
+	}
+	if c.Latency != time.Millisecond*200 ||
+		c.LatencyJitter != time.Millisecond*50 ||
+		c.ServerErrorRate != 0.05 ||
+		c.RateLimitRate != 0.02 ||
+		c.RetryAfter != time.Second*2 ||
+		c.DropFrameRate != 0.01 ||
+		c.OutageRate != 0.001 ||
+		c.OutageDuration != time.Second*30 ||
+		len(c.Exchanges) != 2 ||
+		c.Seed != 7 {
+		t.Errorf("Test Failed - ParseConfig() unexpected config %+v", c)
+	}
+
+	for _, s := range []string{"latency", "latency=", "latency=fast", "unknown=1", "drop=2"} {
+		if _, err = ParseConfig(s); err == nil {
+			t.Errorf("Test Failed - ParseConfig() expected error for %q", s)
+		}
+	}
+}
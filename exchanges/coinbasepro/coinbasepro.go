@@ -815,12 +815,32 @@ func (c *CoinbasePro) SendHTTPRequest(path string, result interface{}) error {
 	return c.SendPayload(http.MethodGet, path, nil, nil, result, false, false, c.Verbose, c.HTTPDebugging)
 }
 
+// mutating returns whether the authenticated endpoint changes exchange state.
+// Orders are listed, fetched, placed and cancelled through the same path so
+// order endpoints are matched by method and path
+func mutating(method, path string) bool {
+	if path == coinbaseproOrders || strings.HasPrefix(path, coinbaseproOrders+"/") {
+		return method != http.MethodGet
+	}
+	switch path {
+	case coinbaseproMarginTransfer, coinbaseproFundingRepay, coinbaseproPositionClose,
+		coinbaseproPaymentMethodDeposit, coinbaseproDepositCoinbase,
+		coinbaseproWithdrawalPaymentMethod, coinbaseproWithdrawalCoinbase,
+		coinbaseproWithdrawalCrypto:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP reque
 func (c *CoinbasePro) SendAuthenticatedHTTPRequest(method, path string, params map[string]interface{}, result interface{}) (err error) {
 	if !c.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet,
 			c.Name)
 	}
+	if c.DryRunRequest(mutating(method, path), method+" "+path, params) {
+		return nil
+	}
 
 	payload := []byte("")
 
@@ -95,6 +95,8 @@ func (c *CoinbasePro) WsHandleData() {
 					LowPrice:   ticker.Low24H,
 					ClosePrice: ticker.Price,
 					Quantity:   ticker.Volume24H,
+					BidPrice:   ticker.BestBid,
+					AskPrice:   ticker.BestAsk,
 				}
 
 			case "snapshot":
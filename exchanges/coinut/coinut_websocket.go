@@ -138,8 +138,9 @@ func (c *COINUT) wsProcessResponse(resp []byte) {
 			Timestamp:  time.Unix(0, ticker.Timestamp),
 			Exchange:   c.GetName(),
 			AssetType:  "SPOT",
-			HighPrice:  ticker.HighestBuy,
-			LowPrice:   ticker.LowestSell,
+			Pair:       currency.NewPairFromString(instrumentListByCode[ticker.InstID]),
+			BidPrice:   ticker.HighestBuy,
+			AskPrice:   ticker.LowestSell,
 			ClosePrice: ticker.Last,
 			Quantity:   ticker.Volume,
 		}
@@ -0,0 +1,708 @@
+// Package collateral values margin balances held across derivative venues in a
+// common base currency, tracks maintenance margin utilization per venue and
+// recommends, or optionally executes, collateral moves from venues with spare
+// margin to venues whose utilization has exceeded the rebalance threshold
+package collateral
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Level defines the utilization level of a venue
+type Level int
+
+// Utilization levels in escalating order
+const (
+	Healthy Level = iota
+	Warning
+	Rebalance
+)
+
+// String implements the stringer interface
+func (l Level) String() string {
+	switch l {
+	case Warning:
+		return "WARNING"
+	case Rebalance:
+		return "REBALANCE"
+	}
+	return "HEALTHY"
+}
+
+// Move statuses
+const (
+	Recommended = "RECOMMENDED"
+	Submitted   = "SUBMITTED"
+	Completed   = "COMPLETED"
+	Failed      = "FAILED"
+	// Expired moves were not executed before the destination venue fell
+	// below the rebalance threshold
+	Expired = "EXPIRED"
+)
+
+// Default thresholds expressed as maintenance margin over margin balance
+const (
+	DefaultWarningUtilization   = 0.5
+	DefaultRebalanceUtilization = 0.7
+	// DefaultTargetUtilization is the utilization recommended moves restore
+	// an over utilized venue to, and the utilization a venue must be below
+	// to source collateral
+	DefaultTargetUtilization = 0.35
+	// DefaultCheckInterval is the delay between collateral checks
+	DefaultCheckInterval = time.Minute
+)
+
+var (
+	errNoVenues             = errors.New("no collateral venues supplied")
+	errNilPriceFunc         = errors.New("no price function supplied")
+	errNoBaseCurrency       = errors.New("base currency not set")
+	errInvalidThresholds    = errors.New("utilization thresholds must be between 0 and 1 and target < warning <= rebalance")
+	errVenueNotFound        = errors.New("collateral venue not found")
+	errMoveNotFound         = errors.New("collateral move not found")
+	errMoveNotExecutable    = errors.New("collateral move has already been executed")
+	errTransferNotSupported = errors.New("venue does not support collateral transfers")
+)
+
+// Margin holds the margin balance of a single currency and account on a venue
+type Margin struct {
+	Exchange string
+	// Account distinguishes separate margin accounts on a venue, such as
+	// futures and swap accounts
+	Account           string
+	Currency          currency.Code
+	Balance           float64
+	MaintenanceMargin float64
+	Available         float64
+	// Value, MaintenanceValue and AvailableValue are denominated in the
+	// manager's base currency
+	Value            float64
+	MaintenanceValue float64
+	AvailableValue   float64
+	Unpriced         bool
+}
+
+// Venue provides the margin balances of a derivative exchange
+type Venue interface {
+	GetName() string
+	GetMargins() ([]Margin, error)
+}
+
+// Transferer is implemented by venues which can withdraw collateral to, and
+// receive collateral from, other venues
+type Transferer interface {
+	Venue
+	DepositAddress(c currency.Code) (string, error)
+	Withdraw(c currency.Code, amount float64, address string) (string, error)
+}
+
+// Poster is implemented by venues where deposits are credited to a funding
+// account and must be posted to the margin account once they arrive
+type Poster interface {
+	PostCollateral(c currency.Code, amount float64) error
+}
+
+// PriceFunc returns the value of one unit of a currency in the base currency
+type PriceFunc func(c currency.Code) (float64, error)
+
+// NetworkFunc returns an error when a currency's network is suspended for
+// withdrawals from, or deposits to, an exchange
+type NetworkFunc func(exchangeName string, c currency.Code, withdraw bool) error
+
+// ApproveFunc approves a withdrawal destination before a move is executed
+type ApproveFunc func(exchangeName string, c currency.Code, amount float64, address string) error
+
+// Status holds the valued margin of a venue
+type Status struct {
+	Exchange          string
+	Margins           []Margin
+	Balance           float64
+	MaintenanceMargin float64
+	Available         float64
+	Utilization       float64
+	Level             Level
+	Error             string `json:",omitempty"`
+	Updated           time.Time
+}
+
+// Move is a collateral transfer between two venues
+type Move struct {
+	ID       int64
+	From     string
+	To       string
+	Currency currency.Code
+	Amount   float64
+	// Value is the amount in the base currency
+	Value        float64
+	Reason       string
+	Status       string
+	WithdrawalID string `json:",omitempty"`
+	Error        string `json:",omitempty"`
+	Created      time.Time
+	Updated      time.Time
+}
+
+// String implements the stringer interface
+func (m *Move) String() string {
+	return fmt.Sprintf("%s move %d of %v %s (%f) from %s to %s: %s",
+		strings.ToLower(m.Status), m.ID, m.Amount, m.Currency, m.Value, m.From, m.To, m.Reason)
+}
+
+// Alert is raised when a venue changes utilization level or a move changes
+// status
+type Alert struct {
+	Status Status
+	Move   *Move
+}
+
+// String implements the stringer interface
+func (a *Alert) String() string {
+	if a.Move != nil {
+		return "Collateral " + a.Move.String()
+	}
+	return fmt.Sprintf("%s collateral utilization %s at %.2f%% of %f margin balance",
+		a.Status.Exchange, a.Status.Level, a.Status.Utilization*100, a.Status.Balance)
+}
+
+// Config holds the manager thresholds and options
+type Config struct {
+	Base                 currency.Code
+	WarningUtilization   float64
+	RebalanceUtilization float64
+	TargetUtilization    float64
+	// MinMove is the smallest move recommended in the base currency
+	MinMove float64
+	// Execute submits recommended moves between venues which support
+	// transfers, otherwise moves are only recommended
+	Execute bool
+	// Network, when set, excludes moves whose currency cannot currently be
+	// withdrawn from the source or deposited to the destination
+	Network NetworkFunc
+}
+
+// Manager periodically values venue margin and recommends collateral moves
+type Manager struct {
+	venues   []Venue
+	cfg      Config
+	price    PriceFunc
+	approve  ApproveFunc
+	onAlert  func(Alert)
+	statuses map[string]Status
+	moves    []*Move
+	nextID   int64
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// NewManager returns a collateral manager, unset thresholds use their
+// defaults. Approve is called before every executed move and may be nil
+func NewManager(venues []Venue, cfg Config, price PriceFunc, approve ApproveFunc, onAlert func(Alert)) (*Manager, error) {
+	if len(venues) == 0 {
+		return nil, errNoVenues
+	}
+	if price == nil {
+		return nil, errNilPriceFunc
+	}
+	if cfg.Base.String() == "" {
+		return nil, errNoBaseCurrency
+	}
+	if cfg.WarningUtilization == 0 {
+		cfg.WarningUtilization = DefaultWarningUtilization
+	}
+	if cfg.RebalanceUtilization == 0 {
+		cfg.RebalanceUtilization = DefaultRebalanceUtilization
+	}
+	if cfg.TargetUtilization == 0 {
+		cfg.TargetUtilization = DefaultTargetUtilization
+	}
+	if cfg.TargetUtilization <= 0 || cfg.TargetUtilization >= cfg.WarningUtilization ||
+		cfg.WarningUtilization > cfg.RebalanceUtilization || cfg.RebalanceUtilization >= 1 {
+		return nil, errInvalidThresholds
+	}
+	return &Manager{
+		venues:   venues,
+		cfg:      cfg,
+		price:    price,
+		approve:  approve,
+		onAlert:  onAlert,
+		statuses: make(map[string]Status),
+	}, nil
+}
+
+// level returns the utilization level for the manager thresholds
+func (m *Manager) level(utilization float64) Level {
+	switch {
+	case utilization >= m.cfg.RebalanceUtilization:
+		return Rebalance
+	case utilization >= m.cfg.WarningUtilization:
+		return Warning
+	}
+	return Healthy
+}
+
+// value fetches and values the margin balances of a venue
+func (m *Manager) value(v Venue) Status {
+	s := Status{Exchange: v.GetName(), Updated: time.Now()}
+	margins, err := v.GetMargins()
+	if err != nil {
+		s.Error = err.Error()
+		return s
+	}
+
+	prices := make(map[currency.Code]float64)
+	for i := range margins {
+		c := margins[i].Currency
+		px, ok := prices[c]
+		if !ok {
+			px, err = m.price(c)
+			if err != nil || px <= 0 {
+				px = 0
+			}
+			prices[c] = px
+		}
+		if px == 0 {
+			margins[i].Unpriced = true
+			continue
+		}
+		margins[i].Value = margins[i].Balance * px
+		margins[i].MaintenanceValue = margins[i].MaintenanceMargin * px
+		margins[i].AvailableValue = margins[i].Available * px
+		s.Balance += margins[i].Value
+		s.MaintenanceMargin += margins[i].MaintenanceValue
+		s.Available += margins[i].AvailableValue
+	}
+	s.Margins = margins
+	if s.Balance > 0 {
+		s.Utilization = s.MaintenanceMargin / s.Balance
+	} else if s.MaintenanceMargin > 0 {
+		s.Utilization = 1
+	}
+	s.Level = m.level(s.Utilization)
+	return s
+}
+
+// Check values every venue, alerts on utilization level changes, posts
+// arrived collateral and recommends moves for venues above the rebalance
+// threshold. Recommended moves are executed when enabled
+func (m *Manager) Check() {
+	statuses := make([]Status, len(m.venues))
+	for i := range m.venues {
+		statuses[i] = m.value(m.venues[i])
+	}
+
+	var alerts []Alert
+	m.mtx.Lock()
+	for i := range statuses {
+		if statuses[i].Error != "" {
+			log.Warnf("Collateral manager %s: %s", statuses[i].Exchange, statuses[i].Error)
+		}
+		prev, ok := m.statuses[statuses[i].Exchange]
+		if statuses[i].Error == "" && (ok || statuses[i].Level != Healthy) &&
+			prev.Level != statuses[i].Level {
+			alerts = append(alerts, Alert{Status: statuses[i]})
+		}
+		m.statuses[statuses[i].Exchange] = statuses[i]
+	}
+	m.expire()
+	alerts = append(alerts, m.postPending()...)
+	recs := m.recommend(statuses)
+	m.mtx.Unlock()
+
+	for i := range recs {
+		alerts = append(alerts, Alert{Move: recs[i]})
+		if !m.cfg.Execute {
+			continue
+		}
+		if err := m.Execute(recs[i].ID); err != nil {
+			log.Warnf("Collateral manager failed to execute move %d: %s", recs[i].ID, err)
+		}
+	}
+	m.alert(alerts)
+}
+
+func (m *Manager) alert(alerts []Alert) {
+	if m.onAlert == nil {
+		return
+	}
+	for i := range alerts {
+		m.onAlert(alerts[i])
+	}
+}
+
+func (m *Manager) getVenue(name string) Venue {
+	for i := range m.venues {
+		if strings.EqualFold(m.venues[i].GetName(), name) {
+			return m.venues[i]
+		}
+	}
+	return nil
+}
+
+// expire expires recommended moves to venues no longer above the rebalance
+// threshold
+func (m *Manager) expire() {
+	for i := range m.moves {
+		s := m.statuses[m.moves[i].To]
+		if m.moves[i].Status == Recommended && s.Error == "" && s.Level != Rebalance {
+			m.moves[i].Status = Expired
+			m.moves[i].Updated = time.Now()
+		}
+	}
+}
+
+// outstanding returns true if a move to the venue has not completed
+func (m *Manager) outstanding(to string) bool {
+	for i := range m.moves {
+		if m.moves[i].To == to && (m.moves[i].Status == Recommended || m.moves[i].Status == Submitted) {
+			return true
+		}
+	}
+	return false
+}
+
+// recommend sizes moves which restore each venue above the rebalance
+// threshold to the target utilization, sourced from the venues with the most
+// spare collateral in the same currency
+func (m *Manager) recommend(statuses []Status) []*Move {
+	target := m.cfg.TargetUtilization
+	spare := make(map[string]float64)
+	for i := range statuses {
+		if statuses[i].Error == "" && statuses[i].Utilization < target {
+			spare[statuses[i].Exchange] = statuses[i].Balance - statuses[i].MaintenanceMargin/target
+		}
+	}
+
+	var recs []*Move
+	suspended := make(map[string]error)
+	for i := range statuses {
+		s := &statuses[i]
+		if s.Error != "" || s.Level != Rebalance || m.outstanding(s.Exchange) {
+			continue
+		}
+		deficit := s.MaintenanceMargin/target - s.Balance
+		for _, c := range collateralCurrencies(s.Margins) {
+			for _, src := range sources(statuses, spare, c) {
+				if deficit <= 0 {
+					break
+				}
+				value := deficit
+				if value > spare[src.Exchange] {
+					value = spare[src.Exchange]
+				}
+				if value > src.AvailableValue {
+					value = src.AvailableValue
+				}
+				if value <= 0 || value < m.cfg.MinMove {
+					continue
+				}
+				if err := m.checkRoute(suspended, src.Exchange, s.Exchange, c); err != nil {
+					log.Warnf("Collateral manager skipped move from %s to %s: %s",
+						src.Exchange, s.Exchange, err)
+					continue
+				}
+				m.nextID++
+				move := &Move{
+					ID:       m.nextID,
+					From:     src.Exchange,
+					To:       s.Exchange,
+					Currency: c,
+					Amount:   src.Available * value / src.AvailableValue,
+					Value:    value,
+					Reason: fmt.Sprintf("%s utilization %.2f%% above %.2f%%, %s utilization %.2f%%",
+						s.Exchange, s.Utilization*100, m.cfg.RebalanceUtilization*100,
+						src.Exchange, src.utilization*100),
+					Status:  Recommended,
+					Created: time.Now(),
+				}
+				move.Updated = move.Created
+				m.moves = append(m.moves, move)
+				recs = append(recs, move)
+				spare[src.Exchange] -= value
+				deficit -= value
+			}
+		}
+	}
+	return recs
+}
+
+// checkRoute returns an error when the currency's network is suspended at
+// either end of a move. Results are cached for the check in progress
+func (m *Manager) checkRoute(cache map[string]error, from, to string, c currency.Code) error {
+	if m.cfg.Network == nil {
+		return nil
+	}
+	for _, end := range []struct {
+		exchange string
+		withdraw bool
+	}{{from, true}, {to, false}} {
+		key := fmt.Sprintf("%s %s %t", end.exchange, c, end.withdraw)
+		err, ok := cache[key]
+		if !ok {
+			err = m.cfg.Network(end.exchange, c, end.withdraw)
+			cache[key] = err
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// collateralCurrencies returns the currencies backing maintenance margin,
+// largest first
+func collateralCurrencies(margins []Margin) []currency.Code {
+	values := make(map[currency.Code]float64)
+	for i := range margins {
+		if margins[i].MaintenanceValue > 0 {
+			values[margins[i].Currency] += margins[i].MaintenanceValue
+		}
+	}
+	codes := make([]currency.Code, 0, len(values))
+	for c := range values {
+		codes = append(codes, c)
+	}
+	sort.Slice(codes, func(i, j int) bool {
+		return values[codes[i]] > values[codes[j]]
+	})
+	return codes
+}
+
+type source struct {
+	Exchange       string
+	Available      float64
+	AvailableValue float64
+	utilization    float64
+}
+
+// sources returns venues with spare collateral available in the currency,
+// least utilized first
+func sources(statuses []Status, spare map[string]float64, c currency.Code) []source {
+	var resp []source
+	for i := range statuses {
+		if spare[statuses[i].Exchange] <= 0 {
+			continue
+		}
+		src := source{Exchange: statuses[i].Exchange, utilization: statuses[i].Utilization}
+		for j := range statuses[i].Margins {
+			if statuses[i].Margins[j].Currency.Match(c) && !statuses[i].Margins[j].Unpriced {
+				src.Available += statuses[i].Margins[j].Available
+				src.AvailableValue += statuses[i].Margins[j].AvailableValue
+			}
+		}
+		if src.AvailableValue > 0 {
+			resp = append(resp, src)
+		}
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].utilization < resp[j].utilization
+	})
+	return resp
+}
+
+// Execute withdraws a recommended move from its source venue to the deposit
+// address of its destination venue
+func (m *Manager) Execute(id int64) error {
+	m.mtx.Lock()
+	move := m.getMove(id)
+	if move == nil {
+		m.mtx.Unlock()
+		return errMoveNotFound
+	}
+	if move.Status != Recommended {
+		m.mtx.Unlock()
+		return errMoveNotExecutable
+	}
+	// Mark the move as submitted while the withdrawal is in flight so it
+	// cannot be executed twice
+	move.Status = Submitted
+	m.mtx.Unlock()
+
+	withdrawalID, err := m.transfer(move)
+
+	m.mtx.Lock()
+	move.Updated = time.Now()
+	if err != nil {
+		move.Status = Failed
+		move.Error = err.Error()
+	} else {
+		move.WithdrawalID = withdrawalID
+		if _, ok := m.getVenue(move.To).(Poster); !ok {
+			move.Status = Completed
+		}
+	}
+	alert := Alert{Move: m.copyMove(move)}
+	m.mtx.Unlock()
+
+	m.alert([]Alert{alert})
+	return err
+}
+
+func (m *Manager) transfer(move *Move) (string, error) {
+	from, ok := m.getVenue(move.From).(Transferer)
+	if !ok {
+		return "", fmt.Errorf("%s %v", move.From, errTransferNotSupported)
+	}
+	to, ok := m.getVenue(move.To).(Transferer)
+	if !ok {
+		return "", fmt.Errorf("%s %v", move.To, errTransferNotSupported)
+	}
+	// Networks may have been suspended since the move was recommended
+	err := m.checkRoute(make(map[string]error), move.From, move.To, move.Currency)
+	if err != nil {
+		return "", err
+	}
+	address, err := to.DepositAddress(move.Currency)
+	if err != nil {
+		return "", err
+	}
+	if m.approve != nil {
+		err = m.approve(move.From, move.Currency, move.Amount, address)
+		if err != nil {
+			return "", err
+		}
+	}
+	return from.Withdraw(move.Currency, move.Amount, address)
+}
+
+// postPending posts submitted moves to the destination margin account, a
+// failed post is retried on the next check as the deposit may not have been
+// credited yet
+func (m *Manager) postPending() []Alert {
+	var alerts []Alert
+	for i := range m.moves {
+		if m.moves[i].Status != Submitted || m.moves[i].WithdrawalID == "" {
+			continue
+		}
+		p, ok := m.getVenue(m.moves[i].To).(Poster)
+		if !ok {
+			continue
+		}
+		if err := p.PostCollateral(m.moves[i].Currency, m.moves[i].Amount); err != nil {
+			continue
+		}
+		m.moves[i].Status = Completed
+		m.moves[i].Updated = time.Now()
+		alerts = append(alerts, Alert{Move: m.copyMove(m.moves[i])})
+	}
+	return alerts
+}
+
+func (m *Manager) getMove(id int64) *Move {
+	for i := range m.moves {
+		if m.moves[i].ID == id {
+			return m.moves[i]
+		}
+	}
+	return nil
+}
+
+func (m *Manager) copyMove(move *Move) *Move {
+	c := *move
+	return &c
+}
+
+// GetStatus returns the latest valuation of a venue
+func (m *Manager) GetStatus(exchangeName string) (Status, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	for k, s := range m.statuses {
+		if strings.EqualFold(k, exchangeName) {
+			return s, nil
+		}
+	}
+	return Status{}, errVenueNotFound
+}
+
+// GetStatuses returns the latest valuation of every venue, most utilized
+// first
+func (m *Manager) GetStatuses() []Status {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	resp := make([]Status, 0, len(m.statuses))
+	for _, s := range m.statuses {
+		resp = append(resp, s)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Utilization > resp[j].Utilization
+	})
+	return resp
+}
+
+// GetMoves returns every recommended and executed move
+func (m *Manager) GetMoves() []Move {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	resp := make([]Move, len(m.moves))
+	for i := range m.moves {
+		resp[i] = *m.moves[i]
+	}
+	return resp
+}
+
+// Report holds the latest valuation of every venue and all moves
+type Report struct {
+	Base     currency.Code
+	Statuses []Status
+	Moves    []Move
+}
+
+// GetReport returns the latest valuation of every venue and all moves
+func (m *Manager) GetReport() Report {
+	return Report{
+		Base:     m.cfg.Base,
+		Statuses: m.GetStatuses(),
+		Moves:    m.GetMoves(),
+	}
+}
+
+// Start checks collateral at the supplied interval until stopped
+func (m *Manager) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		m.Check()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-t.C:
+				m.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops the manager
+func (m *Manager) Stop() {
+	m.mtx.Lock()
+	if m.shutdown == nil {
+		m.mtx.Unlock()
+		return
+	}
+	close(m.shutdown)
+	m.shutdown = nil
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
@@ -0,0 +1,293 @@
+package collateral
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testVenue struct {
+	name      string
+	margins   []Margin
+	err       error
+	withdrawn float64
+	posted    float64
+	postErr   error
+}
+
+func (t *testVenue) GetName() string { return t.name }
+
+func (t *testVenue) GetMargins() ([]Margin, error) {
+	return append([]Margin(nil), t.margins...), t.err
+}
+
+func (t *testVenue) DepositAddress(_ currency.Code) (string, error) {
+	return t.name + "-address", nil
+}
+
+func (t *testVenue) Withdraw(_ currency.Code, amount float64, _ string) (string, error) {
+	t.withdrawn += amount
+	return "1337", nil
+}
+
+type testPoster struct {
+	*testVenue
+}
+
+func (t *testPoster) PostCollateral(_ currency.Code, amount float64) error {
+	if t.postErr != nil {
+		return t.postErr
+	}
+	t.posted += amount
+	return nil
+}
+
+func testPrice(c currency.Code) (float64, error) {
+	switch {
+	case c.Match(currency.BTC):
+		return 10000, nil
+	case c.Match(currency.USDT):
+		return 1, nil
+	}
+	return 0, errors.New("no price")
+}
+
+func btcMargin(balance, maint, available float64) Margin {
+	return Margin{Currency: currency.BTC, Balance: balance, MaintenanceMargin: maint, Available: available}
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestNewManager(t *testing.T) {
+	v := []Venue{&testVenue{name: "Test"}}
+	cfg := Config{Base: currency.USD}
+	if _, err := NewManager(nil, cfg, testPrice, nil, nil); err != errNoVenues {
+		t.Error("Test Failed - NewManager() expected no venues error", err)
+	}
+	if _, err := NewManager(v, cfg, nil, nil, nil); err != errNilPriceFunc {
+		t.Error("Test Failed - NewManager() expected nil price error", err)
+	}
+	if _, err := NewManager(v, Config{}, testPrice, nil, nil); err != errNoBaseCurrency {
+		t.Error("Test Failed - NewManager() expected no base currency error", err)
+	}
+	cfg.TargetUtilization = 0.6
+	if _, err := NewManager(v, cfg, testPrice, nil, nil); err != errInvalidThresholds {
+		t.Error("Test Failed - NewManager() expected invalid thresholds error", err)
+	}
+	m, err := NewManager(v, Config{Base: currency.USD}, testPrice, nil, nil)
+	if err != nil {
+		t.Fatal("Test Failed - NewManager() error", err)
+	}
+	if m.cfg.RebalanceUtilization != DefaultRebalanceUtilization {
+		t.Error("Test Failed - NewManager() defaults not set")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	a := &testVenue{name: "A", margins: []Margin{
+		btcMargin(1, 0.2, 0.8),
+		{Currency: currency.USDT, Balance: 10000, MaintenanceMargin: 0, Available: 10000},
+		{Currency: currency.NewCode("XYZ"), Balance: 100, MaintenanceMargin: 100},
+	}}
+	b := &testVenue{name: "B", err: errors.New("unavailable")}
+
+	var alerts []Alert
+	m, err := NewManager([]Venue{a, b}, Config{Base: currency.USD}, testPrice, nil, func(al Alert) {
+		alerts = append(alerts, al)
+	})
+	if err != nil {
+		t.Fatal("Test Failed - NewManager() error", err)
+	}
+	m.Check()
+
+	s, err := m.GetStatus("a")
+	if err != nil {
+		t.Fatal("Test Failed - GetStatus() error", err)
+	}
+	if s.Balance != 20000 || s.MaintenanceMargin != 2000 || s.Utilization != 0.1 || s.Level != Healthy {
+		t.Errorf("Test Failed - Check() unexpected status %+v", s)
+	}
+	if !s.Margins[2].Unpriced {
+		t.Error("Test Failed - Check() expected unpriced margin")
+	}
+	if s, _ = m.GetStatus("B"); s.Error == "" {
+		t.Error("Test Failed - Check() expected venue error")
+	}
+	if _, err = m.GetStatus("C"); err != errVenueNotFound {
+		t.Error("Test Failed - GetStatus() expected venue not found error", err)
+	}
+	if len(alerts) != 0 {
+		t.Errorf("Test Failed - Check() unexpected alerts %+v", alerts)
+	}
+
+	a.margins[0].MaintenanceMargin = 0.6
+	a.margins[1].Balance = 0
+	m.Check()
+	if len(alerts) != 1 || alerts[0].Status.Level != Warning {
+		t.Fatalf("Test Failed - Check() expected warning alert, got %+v", alerts)
+	}
+	m.Check()
+	if len(alerts) != 1 {
+		t.Error("Test Failed - Check() repeated alert for unchanged level")
+	}
+	if statuses := m.GetStatuses(); len(statuses) != 2 || statuses[0].Exchange != "A" {
+		t.Errorf("Test Failed - GetStatuses() unexpected statuses %+v", statuses)
+	}
+}
+
+func TestRecommendAndExecute(t *testing.T) {
+	a := &testVenue{name: "A", margins: []Margin{btcMargin(1, 0.8, 0.1)}}
+	b := &testPoster{&testVenue{name: "B", margins: []Margin{btcMargin(4, 0.2, 3.5)}}}
+	c := &testVenue{name: "C", margins: []Margin{btcMargin(1, 0.01, 0.9)}}
+
+	var alerts []Alert
+	m, err := NewManager([]Venue{a, b, c}, Config{Base: currency.USD, MinMove: 100},
+		testPrice, nil, func(al Alert) {
+			alerts = append(alerts, al)
+		})
+	if err != nil {
+		t.Fatal("Test Failed - NewManager() error", err)
+	}
+	m.Check()
+
+	// A requires 0.8 / 0.35 - 1 BTC, C has the lowest utilization and the
+	// remainder is sourced from B
+	moves := m.GetMoves()
+	if len(moves) != 2 {
+		t.Fatalf("Test Failed - Check() expected two moves, got %+v", moves)
+	}
+	want := 0.8/DefaultTargetUtilization - 1
+	if moves[0].From != "C" || moves[0].To != "A" || moves[0].Status != Recommended ||
+		!closeTo(moves[0].Amount, 0.9) {
+		t.Errorf("Test Failed - Check() unexpected move %+v", moves[0])
+	}
+	if moves[1].From != "B" || !closeTo(moves[1].Amount, want-0.9) || !closeTo(moves[1].Value, (want-0.9)*10000) {
+		t.Errorf("Test Failed - Check() unexpected move %+v", moves[1])
+	}
+	if len(alerts) != 3 || alerts[0].Status.Level != Rebalance || alerts[1].Move == nil {
+		t.Errorf("Test Failed - Check() unexpected alerts %+v", alerts)
+	}
+
+	// An unexecuted move blocks further recommendations and expires once the
+	// venue falls below the rebalance threshold
+	m.Check()
+	if len(m.GetMoves()) != 2 {
+		t.Error("Test Failed - Check() recommended a move while one is outstanding")
+	}
+	a.margins[0].MaintenanceMargin = 0.3
+	m.Check()
+	if moves = m.GetMoves(); moves[0].Status != Expired || moves[1].Status != Expired {
+		t.Errorf("Test Failed - Check() expected expired moves, got %+v", moves)
+	}
+	if err = m.Execute(moves[0].ID); err != errMoveNotExecutable {
+		t.Error("Test Failed - Execute() expected not executable error", err)
+	}
+	if err = m.Execute(99); err != errMoveNotFound {
+		t.Error("Test Failed - Execute() expected move not found error", err)
+	}
+
+	// B is sourced once C has no available collateral
+	c.margins[0].Available = 0
+	m.cfg.Execute = true
+	a.margins[0].MaintenanceMargin = 0.8
+	m.Check()
+	moves = m.GetMoves()
+	if len(moves) != 3 || moves[2].From != "B" || !closeTo(moves[2].Amount, want) {
+		t.Fatalf("Test Failed - Check() unexpected moves %+v", moves)
+	}
+	if moves[2].Status != Completed || moves[2].WithdrawalID != "1337" || !closeTo(b.withdrawn, want) {
+		t.Errorf("Test Failed - Execute() unexpected move %+v", moves[2])
+	}
+}
+
+func TestSuspendedNetwork(t *testing.T) {
+	a := &testVenue{name: "A", margins: []Margin{btcMargin(1, 0.8, 0.1)}}
+	b := &testVenue{name: "B", margins: []Margin{btcMargin(4, 0.2, 3.5)}}
+	c := &testVenue{name: "C", margins: []Margin{btcMargin(1, 0.01, 0.9)}}
+
+	suspended := map[string]bool{"C": true}
+	network := func(exchangeName string, _ currency.Code, withdraw bool) error {
+		if withdraw && suspended[exchangeName] {
+			return errors.New("withdrawals suspended")
+		}
+		return nil
+	}
+	m, err := NewManager([]Venue{a, b, c}, Config{Base: currency.USD, Network: network}, testPrice, nil, nil)
+	if err != nil {
+		t.Fatal("Test Failed - NewManager() error", err)
+	}
+	m.Check()
+
+	// C has the lowest utilization but its withdrawals are suspended
+	moves := m.GetMoves()
+	if len(moves) != 1 || moves[0].From != "B" || !closeTo(moves[0].Amount, 0.8/DefaultTargetUtilization-1) {
+		t.Fatalf("Test Failed - Check() expected move avoiding suspended network %+v", moves)
+	}
+
+	suspended["B"] = true
+	if err = m.Execute(moves[0].ID); err == nil || b.withdrawn != 0 {
+		t.Error("Test Failed - Execute() expected suspended network error")
+	}
+}
+
+func TestPostPending(t *testing.T) {
+	a := &testVenue{name: "A", margins: []Margin{btcMargin(4, 0.2, 3.5)}}
+	b := &testPoster{&testVenue{name: "B", margins: []Margin{btcMargin(1, 0.8, 0.1)}, postErr: errors.New("insufficient balance")}}
+	approved := true
+	m, err := NewManager([]Venue{a, b}, Config{Base: currency.USD}, testPrice,
+		func(_ string, _ currency.Code, _ float64, address string) error {
+			if !approved || address != "B-address" {
+				return errors.New("not approved")
+			}
+			return nil
+		}, nil)
+	if err != nil {
+		t.Fatal("Test Failed - NewManager() error", err)
+	}
+	m.Check()
+	if err = m.Execute(1); err != nil {
+		t.Fatal("Test Failed - Execute() error", err)
+	}
+	if moves := m.GetMoves(); moves[0].Status != Submitted {
+		t.Errorf("Test Failed - Execute() expected submitted move awaiting post, got %+v", moves[0])
+	}
+	m.Check()
+	if moves := m.GetMoves(); moves[0].Status != Submitted || len(moves) != 1 {
+		t.Errorf("Test Failed - Check() unexpected moves %+v", moves)
+	}
+	b.postErr = nil
+	m.Check()
+	if moves := m.GetMoves(); moves[0].Status != Completed || !closeTo(b.posted, moves[0].Amount) {
+		t.Errorf("Test Failed - Check() expected posted move, got %+v", moves[0])
+	}
+
+	approved = false
+	m.Check()
+	moves := m.GetMoves()
+	if err = m.Execute(moves[len(moves)-1].ID); err == nil {
+		t.Error("Test Failed - Execute() expected approval error")
+	}
+	if moves = m.GetMoves(); moves[len(moves)-1].Status != Failed {
+		t.Errorf("Test Failed - Execute() expected failed move, got %+v", moves[len(moves)-1])
+	}
+}
+
+func TestOKEXMargin(t *testing.T) {
+	if _, ok := okexMargin("OKEX", "futures", currency.BTC, "0", "0", "0", okexDefaultMMR); ok {
+		t.Error("Test Failed - okexMargin() expected empty account to be skipped")
+	}
+	m, ok := okexMargin("OKEX", "futures", currency.BTC, "2", "0.5", "1", 0.01)
+	if !ok || m.Balance != 2 || m.Available != 1 || !closeTo(m.MaintenanceMargin, 0.04) {
+		t.Errorf("Test Failed - okexMargin() unexpected margin %+v", m)
+	}
+	if c := swapCurrency("BTC-USDT-SWAP"); !c.Match(currency.USDT) {
+		t.Errorf("Test Failed - swapCurrency() expected USDT, got %s", c)
+	}
+	if c := swapCurrency("eth-usd-swap"); !c.Match(currency.ETH) {
+		t.Errorf("Test Failed - swapCurrency() expected ETH, got %s", c)
+	}
+}
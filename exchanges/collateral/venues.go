@@ -0,0 +1,236 @@
+package collateral
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okgroup"
+)
+
+const (
+	satoshisPerBTC = 1e8
+
+	// okexDefaultMMR is used to derive OKEX maintenance margin as it is not
+	// returned with account balances
+	okexDefaultMMR = 0.005
+
+	okexFuturesAccount = 3
+	okexWalletAccount  = 6
+	// okexExternalAddress is the withdrawal destination type for addresses
+	// outside of OKEX and OKCoin
+	okexExternalAddress = 4
+)
+
+var (
+	errWithdrawalRejected = errors.New("withdrawal rejected")
+	errNoDepositAddress   = errors.New("no deposit address")
+)
+
+// BitmexVenue provides BitMEX XBT margin to the collateral manager, XBT
+// margin is reported as BTC so it can be moved between venues
+type BitmexVenue struct {
+	Exchange *bitmex.Bitmex
+}
+
+// GetName returns the exchange name
+func (b *BitmexVenue) GetName() string {
+	return b.Exchange.GetName()
+}
+
+// GetMargins returns the BitMEX XBT margin balance
+func (b *BitmexVenue) GetMargins() ([]Margin, error) {
+	margins, err := b.Exchange.GetAllUserMargin()
+	if err != nil {
+		return nil, err
+	}
+
+	var resp []Margin
+	for i := range margins {
+		if !strings.EqualFold(margins[i].Currency, "XBt") {
+			continue
+		}
+		resp = append(resp, Margin{
+			Exchange:          b.GetName(),
+			Account:           "margin",
+			Currency:          currency.BTC,
+			Balance:           float64(margins[i].MarginBalance) / satoshisPerBTC,
+			MaintenanceMargin: float64(margins[i].MaintMargin) / satoshisPerBTC,
+			Available:         float64(margins[i].AvailableMargin) / satoshisPerBTC,
+		})
+	}
+	return resp, nil
+}
+
+// DepositAddress returns the BitMEX bitcoin deposit address
+func (b *BitmexVenue) DepositAddress(c currency.Code) (string, error) {
+	if !c.Match(currency.BTC) && !c.Match(currency.XBT) {
+		return "", fmt.Errorf("%s %v", c, errTransferNotSupported)
+	}
+	return b.Exchange.GetCryptoDepositAddress(currency.XBT.String())
+}
+
+// Withdraw requests a bitcoin withdrawal from BitMEX margin. Withdrawals
+// requiring a 2FA token will be rejected by the exchange
+func (b *BitmexVenue) Withdraw(c currency.Code, amount float64, address string) (string, error) {
+	if !c.Match(currency.BTC) && !c.Match(currency.XBT) {
+		return "", fmt.Errorf("%s %v", c, errTransferNotSupported)
+	}
+	resp, err := b.Exchange.UserRequestWithdrawal(bitmex.UserRequestWithdrawalParams{
+		Address:  address,
+		Amount:   amount * satoshisPerBTC,
+		Currency: "XBt",
+	})
+	if err != nil {
+		return "", err
+	}
+	return resp.TransactID, nil
+}
+
+// OKEXVenue provides OKEX futures and swap margin per currency to the
+// collateral manager. Collateral is moved through the futures account
+type OKEXVenue struct {
+	Exchange *okex.OKEX
+	// MaintenanceMarginRate overrides the default maintenance margin rate
+	MaintenanceMarginRate float64
+	// TradePassword is the fund password required for withdrawals
+	TradePassword string
+}
+
+// GetName returns the exchange name
+func (o *OKEXVenue) GetName() string {
+	return o.Exchange.GetName()
+}
+
+// GetMargins returns the OKEX futures and swap margin balances
+func (o *OKEXVenue) GetMargins() ([]Margin, error) {
+	mmr := o.MaintenanceMarginRate
+	if mmr == 0 {
+		mmr = okexDefaultMMR
+	}
+
+	futures, err := o.Exchange.GetFuturesAccountOfAllCurrencies()
+	if err != nil {
+		return nil, err
+	}
+	var resp []Margin
+	for c, data := range futures.Info.Currency {
+		if m, ok := okexMargin(o.GetName(), "futures", currency.NewCode(c).Upper(),
+			data.Equity, data.MarginRatio, data.TotalAvailBalance, mmr); ok {
+			resp = append(resp, m)
+		}
+	}
+
+	swaps, err := o.Exchange.GetSwapAccountOfAllCurrency()
+	if err != nil {
+		return nil, err
+	}
+	for i := range swaps.Info {
+		s := &swaps.Info[i]
+		if m, ok := okexMargin(o.GetName(), "swap", swapCurrency(s.InstrumentID),
+			s.Equity, s.MarginRatio, s.TotalAvailBalance, mmr); ok {
+			resp = append(resp, m)
+		}
+	}
+	return resp, nil
+}
+
+// okexMargin converts an OKEX account balance. The margin ratio is equity over
+// position value and positions are liquidated when it falls to the
+// maintenance margin rate, so maintenance margin is the position value
+// multiplied by the rate
+func okexMargin(exch, account string, c currency.Code, equity, marginRatio, available string, mmr float64) (Margin, bool) {
+	e, _ := strconv.ParseFloat(equity, 64)
+	if e == 0 {
+		return Margin{}, false
+	}
+	m := Margin{
+		Exchange: exch,
+		Account:  account,
+		Currency: c,
+		Balance:  e,
+	}
+	m.Available, _ = strconv.ParseFloat(available, 64)
+	if ratio, _ := strconv.ParseFloat(marginRatio, 64); ratio > 0 {
+		m.MaintenanceMargin = e / ratio * mmr
+	}
+	return m, true
+}
+
+// swapCurrency returns the margin currency of a swap instrument such as
+// BTC-USD-SWAP or BTC-USDT-SWAP
+func swapCurrency(instrumentID string) currency.Code {
+	parts := strings.Split(strings.ToUpper(instrumentID), "-")
+	if len(parts) > 1 && parts[1] == currency.USDT.String() {
+		return currency.USDT
+	}
+	return currency.NewCode(parts[0])
+}
+
+// DepositAddress returns the OKEX funding wallet deposit address
+func (o *OKEXVenue) DepositAddress(c currency.Code) (string, error) {
+	addresses, err := o.Exchange.GetAccountDepositAddressForCurrency(c.Lower().String())
+	if err != nil {
+		return "", err
+	}
+	if len(addresses) == 0 {
+		return "", fmt.Errorf("%s %s %v", o.GetName(), c, errNoDepositAddress)
+	}
+	return addresses[0].Address, nil
+}
+
+// Withdraw transfers collateral and the minimum network fee from the futures
+// account to the funding wallet and withdraws it
+func (o *OKEXVenue) Withdraw(c currency.Code, amount float64, address string) (string, error) {
+	code := c.Lower().String()
+	var fee float64
+	fees, err := o.Exchange.GetAccountWithdrawalFee(code)
+	if err != nil {
+		return "", err
+	}
+	if len(fees) > 0 {
+		fee = fees[0].MinFee
+	}
+
+	_, err = o.Exchange.TransferAccountFunds(okgroup.TransferAccountFundsRequest{
+		Currency: code,
+		Amount:   amount + fee,
+		From:     okexFuturesAccount,
+		To:       okexWalletAccount,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := o.Exchange.AccountWithdraw(okgroup.AccountWithdrawRequest{
+		Amount:      amount,
+		Currency:    code,
+		Destination: okexExternalAddress,
+		Fee:         fee,
+		ToAddress:   address,
+		TradePwd:    o.TradePassword,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Result {
+		return "", fmt.Errorf("%s %v %s %v", o.GetName(), amount, c, errWithdrawalRejected)
+	}
+	return strconv.FormatInt(resp.WithdrawalID, 10), nil
+}
+
+// PostCollateral transfers a deposit credited to the funding wallet to the
+// futures account
+func (o *OKEXVenue) PostCollateral(c currency.Code, amount float64) error {
+	_, err := o.Exchange.TransferAccountFunds(okgroup.TransferAccountFundsRequest{
+		Currency: c.Lower().String(),
+		Amount:   amount,
+		From:     okexWalletAccount,
+		To:       okexFuturesAccount,
+	})
+	return err
+}
@@ -0,0 +1,358 @@
+// Package compliance blocks trading and transfers of blacklisted assets and
+// venues, such as privacy coins on exchanges in restricted jurisdictions, and
+// withdrawals to sanctioned addresses. Rules are enforced by guarded exchanges
+// so every order and withdrawal path is covered, and each blocked action is
+// emitted as an event for the audit log
+package compliance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// Blocked is the type of event emitted when an action breaches a rule
+const Blocked = "COMPLIANCE_BLOCKED"
+
+// Blocked actions
+const (
+	Order    = "order"
+	Amend    = "amend"
+	Withdraw = "withdraw"
+)
+
+var (
+	// ErrBlocked is returned when an order or withdrawal breaches a
+	// compliance rule
+	ErrBlocked = errors.New("blocked by compliance rule")
+
+	errAssetNotSet    = errors.New("compliance asset rule currency not set")
+	errVenueNotSet    = errors.New("compliance venue rule exchange not set")
+	errAddressNotSet  = errors.New("compliance address rule address not set")
+	errNilWithdrawReq = errors.New("compliance withdraw request is nil")
+)
+
+// AssetRule blacklists trading and transfers of a currency
+type AssetRule struct {
+	Currency currency.Code `json:"currency"`
+	// Exchanges lists the exchanges the rule applies to, when empty it
+	// applies to every exchange
+	Exchanges []string `json:"exchanges,omitempty"`
+	Reason    string   `json:"reason"`
+}
+
+// VenueRule blacklists all trading and transfers on an exchange
+type VenueRule struct {
+	Exchange string `json:"exchange"`
+	Reason   string `json:"reason"`
+}
+
+// AddressRule blacklists withdrawals to an address
+type AddressRule struct {
+	Address string `json:"address"`
+	// Currency limits the rule to withdrawals of the currency, when empty
+	// withdrawals of any currency to the address are blocked
+	Currency currency.Code `json:"currency,omitempty"`
+	Reason   string        `json:"reason"`
+}
+
+// matches returns whether the address is the rule's, hex addresses are
+// compared case insensitively as their casing is only a checksum
+func (a *AddressRule) matches(c currency.Code, address string) bool {
+	if a.Currency.String() != "" && !a.Currency.Match(c) {
+		return false
+	}
+	address = strings.TrimSpace(address)
+	if strings.HasPrefix(strings.ToLower(address), "0x") {
+		return strings.EqualFold(a.Address, address)
+	}
+	return a.Address == address
+}
+
+// Rules holds the compliance blacklists
+type Rules struct {
+	Assets    []AssetRule   `json:"assets"`
+	Venues    []VenueRule   `json:"venues"`
+	Addresses []AddressRule `json:"addresses"`
+}
+
+// validate trims the rules and checks their required fields are set
+func (r *Rules) validate() error {
+	for i := range r.Assets {
+		if r.Assets[i].Currency.String() == "" {
+			return errAssetNotSet
+		}
+	}
+	for i := range r.Venues {
+		if r.Venues[i].Exchange == "" {
+			return errVenueNotSet
+		}
+	}
+	for i := range r.Addresses {
+		r.Addresses[i].Address = strings.TrimSpace(r.Addresses[i].Address)
+		if r.Addresses[i].Address == "" {
+			return errAddressNotSet
+		}
+	}
+	return nil
+}
+
+// Event is emitted when an action is blocked
+type Event struct {
+	Type     string        `json:"type"`
+	Action   string        `json:"action"`
+	Exchange string        `json:"exchange"`
+	Pair     currency.Pair `json:"pair,omitempty"`
+	Currency currency.Code `json:"currency,omitempty"`
+	Address  string        `json:"address,omitempty"`
+	Amount   float64       `json:"amount,omitempty"`
+	// Rule describes the rule breached
+	Rule string    `json:"rule"`
+	Time time.Time `json:"time"`
+}
+
+func (e *Event) String() string {
+	switch e.Action {
+	case Withdraw:
+		return fmt.Sprintf("%s withdrawal of %v %s to %s blocked: %s",
+			e.Exchange, e.Amount, e.Currency, e.Address, e.Rule)
+	default:
+		return fmt.Sprintf("%s %s %s %v blocked: %s",
+			e.Exchange, e.Pair, e.Action, e.Amount, e.Rule)
+	}
+}
+
+// Filter holds the compliance rules orders and withdrawals are checked
+// against
+type Filter struct {
+	rules   Rules
+	blocked int64
+	onEvent func(Event)
+	mtx     sync.Mutex
+}
+
+// New returns a filter enforcing the rules. onEvent receives each blocked
+// action and may be nil
+func New(rules Rules, onEvent func(Event)) (*Filter, error) {
+	err := rules.validate()
+	if err != nil {
+		return nil, err
+	}
+	return &Filter{rules: rules, onEvent: onEvent}, nil
+}
+
+// Load returns a filter enforcing the rules stored at path
+func Load(path string, onEvent func(Event)) (*Filter, error) {
+	data, err := common.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var rules Rules
+	err = json.Unmarshal(data, &rules)
+	if err != nil {
+		return nil, err
+	}
+	return New(rules, onEvent)
+}
+
+// GetRules returns the rules enforced
+func (f *Filter) GetRules() Rules {
+	return f.rules
+}
+
+// GetBlocked returns the number of actions blocked
+func (f *Filter) GetBlocked() int64 {
+	f.mtx.Lock()
+	defer f.mtx.Unlock()
+	return f.blocked
+}
+
+// venueRule returns the rule blacklisting the exchange
+func (f *Filter) venueRule(exchangeName string) (string, bool) {
+	for i := range f.rules.Venues {
+		if strings.EqualFold(f.rules.Venues[i].Exchange, exchangeName) {
+			return "venue " + f.rules.Venues[i].Exchange + " blacklisted, " + f.rules.Venues[i].Reason, true
+		}
+	}
+	return "", false
+}
+
+// assetRule returns the rule blacklisting the currency on the exchange
+func (f *Filter) assetRule(exchangeName string, c currency.Code) (string, bool) {
+	for i := range f.rules.Assets {
+		r := &f.rules.Assets[i]
+		if !r.Currency.Match(c) {
+			continue
+		}
+		if len(r.Exchanges) > 0 && !common.StringDataCompareInsensitive(r.Exchanges, exchangeName) {
+			continue
+		}
+		return "asset " + r.Currency.Upper().String() + " blacklisted, " + r.Reason, true
+	}
+	return "", false
+}
+
+// SanctionedAddress returns the rule blacklisting withdrawals of the
+// currency to the address
+func (f *Filter) SanctionedAddress(c currency.Code, address string) (AddressRule, bool) {
+	for i := range f.rules.Addresses {
+		if f.rules.Addresses[i].matches(c, address) {
+			return f.rules.Addresses[i], true
+		}
+	}
+	return AddressRule{}, false
+}
+
+// CheckPair returns the rule an order for the pair on the exchange breaches
+func (f *Filter) CheckPair(exchangeName string, p currency.Pair) (string, bool) {
+	if rule, ok := f.venueRule(exchangeName); ok {
+		return rule, true
+	}
+	if rule, ok := f.assetRule(exchangeName, p.Base); ok {
+		return rule, true
+	}
+	return f.assetRule(exchangeName, p.Quote)
+}
+
+// CheckWithdrawal returns the rule a withdrawal from the exchange breaches
+func (f *Filter) CheckWithdrawal(exchangeName string, req *exchange.WithdrawRequest) (string, bool) {
+	if rule, ok := f.venueRule(exchangeName); ok {
+		return rule, true
+	}
+	if rule, ok := f.assetRule(exchangeName, req.Currency); ok {
+		return rule, true
+	}
+	if req.Address == "" {
+		return "", false
+	}
+	if r, ok := f.SanctionedAddress(req.Currency, req.Address); ok {
+		return "address " + r.Address + " sanctioned, " + r.Reason, true
+	}
+	return "", false
+}
+
+// block records and emits a blocked action
+func (f *Filter) block(e Event) error {
+	f.mtx.Lock()
+	f.blocked++
+	f.mtx.Unlock()
+
+	e.Type = Blocked
+	e.Time = time.Now()
+	if f.onEvent != nil {
+		f.onEvent(e)
+	}
+	return fmt.Errorf("%s %v: %s", e.Exchange, ErrBlocked, e.Rule)
+}
+
+// Guard wraps an exchange so orders and withdrawals breaching the rules are
+// rejected
+func (f *Filter) Guard(e exchange.IBotExchange) exchange.IBotExchange {
+	return &Guarded{IBotExchange: e, filter: f}
+}
+
+// Guarded is an exchange whose orders and withdrawals are checked against
+// the compliance rules
+type Guarded struct {
+	exchange.IBotExchange
+	filter *Filter
+}
+
+// Unwrap returns the underlying exchange
+func (g *Guarded) Unwrap() exchange.IBotExchange {
+	return g.IBotExchange
+}
+
+// SubmitOrder rejects orders for blacklisted assets and venues
+func (g *Guarded) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	if err := g.checkPair(p, amount); err != nil {
+		return exchange.SubmitOrderResponse{}, err
+	}
+	return g.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+}
+
+// CheckOrder rejects orders placed natively for blacklisted assets and venues
+func (g *Guarded) CheckOrder(o *exchange.OrderSubmission) error {
+	return g.checkPair(o.Pair, o.Amount)
+}
+
+func (g *Guarded) checkPair(p currency.Pair, amount float64) error {
+	rule, ok := g.filter.CheckPair(g.GetName(), p)
+	if !ok {
+		return nil
+	}
+	return g.filter.block(Event{
+		Action:   Order,
+		Exchange: g.GetName(),
+		Pair:     p,
+		Amount:   amount,
+		Rule:     rule,
+	})
+}
+
+// ModifyOrder rejects amendments to orders of blacklisted assets and venues,
+// existing orders can still be cancelled
+func (g *Guarded) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
+	if rule, ok := g.filter.CheckPair(g.GetName(), action.CurrencyPair); ok {
+		return "", g.filter.block(Event{
+			Action:   Amend,
+			Exchange: g.GetName(),
+			Pair:     action.CurrencyPair,
+			Amount:   action.Amount,
+			Rule:     rule,
+		})
+	}
+	return g.IBotExchange.ModifyOrder(action)
+}
+
+// checkWithdrawal returns the error of a withdrawal breaching the rules
+func (g *Guarded) checkWithdrawal(req *exchange.WithdrawRequest) error {
+	if req == nil {
+		return errNilWithdrawReq
+	}
+	if rule, ok := g.filter.CheckWithdrawal(g.GetName(), req); ok {
+		return g.filter.block(Event{
+			Action:   Withdraw,
+			Exchange: g.GetName(),
+			Currency: req.Currency,
+			Address:  req.Address,
+			Amount:   req.Amount,
+			Rule:     rule,
+		})
+	}
+	return nil
+}
+
+// WithdrawCryptocurrencyFunds rejects withdrawals of blacklisted assets,
+// from blacklisted venues or to sanctioned addresses
+func (g *Guarded) WithdrawCryptocurrencyFunds(req *exchange.WithdrawRequest) (string, error) {
+	if err := g.checkWithdrawal(req); err != nil {
+		return "", err
+	}
+	return g.IBotExchange.WithdrawCryptocurrencyFunds(req)
+}
+
+// WithdrawFiatFunds rejects withdrawals of blacklisted assets or from
+// blacklisted venues
+func (g *Guarded) WithdrawFiatFunds(req *exchange.WithdrawRequest) (string, error) {
+	if err := g.checkWithdrawal(req); err != nil {
+		return "", err
+	}
+	return g.IBotExchange.WithdrawFiatFunds(req)
+}
+
+// WithdrawFiatFundsToInternationalBank rejects withdrawals of blacklisted
+// assets or from blacklisted venues
+func (g *Guarded) WithdrawFiatFundsToInternationalBank(req *exchange.WithdrawRequest) (string, error) {
+	if err := g.checkWithdrawal(req); err != nil {
+		return "", err
+	}
+	return g.IBotExchange.WithdrawFiatFundsToInternationalBank(req)
+}
@@ -0,0 +1,151 @@
+package compliance
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	name      string
+	submitted int
+	withdrawn int
+}
+
+func (t *testExchange) GetName() string { return t.name }
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.submitted++
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) ModifyOrder(_ *exchange.ModifyOrder) (string, error) {
+	return "1", nil
+}
+
+func (t *testExchange) WithdrawCryptocurrencyFunds(_ *exchange.WithdrawRequest) (string, error) {
+	t.withdrawn++
+	return "1", nil
+}
+
+var testRules = Rules{
+	Assets: []AssetRule{
+		{Currency: currency.XMR, Exchanges: []string{"Kraken"}, Reason: "privacy coin"},
+		{Currency: currency.NewCode("ZEC"), Reason: "privacy coin"},
+	},
+	Venues: []VenueRule{{Exchange: "Bitmex", Reason: "jurisdiction"}},
+	Addresses: []AddressRule{
+		{Address: "0xAbC123", Reason: "sanctioned"},
+		{Address: "1BadBtc", Currency: currency.BTC, Reason: "sanctioned"},
+	},
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Rules{Assets: []AssetRule{{}}}, nil); err != errAssetNotSet {
+		t.Error("Test Failed - New() expected asset not set error", err)
+	}
+	if _, err := New(Rules{Venues: []VenueRule{{}}}, nil); err != errVenueNotSet {
+		t.Error("Test Failed - New() expected venue not set error", err)
+	}
+	if _, err := New(Rules{Addresses: []AddressRule{{Address: " "}}}, nil); err != errAddressNotSet {
+		t.Error("Test Failed - New() expected address not set error", err)
+	}
+}
+
+func TestLoad(t *testing.T) {
+	dir, err := ioutil.TempDir("", "compliance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "compliance.json")
+	if _, err = Load(path, nil); err == nil {
+		t.Error("Test Failed - Load() expected missing file error")
+	}
+
+	data := `{"assets":[{"currency":"xmr","reason":"privacy coin"}],"addresses":[{"address":" 1BadBtc ","reason":"sanctioned"}]}`
+	if err = ioutil.WriteFile(path, []byte(data), 0600); err != nil {
+		t.Fatal(err)
+	}
+	f, err := Load(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, ok := f.CheckPair("Bitstamp", currency.NewPair(currency.XMR, currency.BTC)); !ok {
+		t.Error("Test Failed - Load() asset rule not loaded")
+	}
+	if _, ok := f.SanctionedAddress(currency.BTC, "1BadBtc"); !ok {
+		t.Error("Test Failed - Load() address rule not loaded")
+	}
+}
+
+func TestGuard(t *testing.T) {
+	var events []Event
+	f, err := New(testRules, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	kraken := &testExchange{name: "Kraken"}
+	bitstamp := &testExchange{name: "Bitstamp"}
+	bitmex := &testExchange{name: "Bitmex"}
+
+	for _, tc := range []struct {
+		exch    *testExchange
+		pair    currency.Pair
+		blocked bool
+	}{
+		{kraken, currency.NewPair(currency.XMR, currency.EUR), true},
+		{bitstamp, currency.NewPair(currency.XMR, currency.EUR), false},
+		{bitstamp, currency.NewPair(currency.BTC, currency.NewCode("ZEC")), true},
+		{bitmex, currency.NewPair(currency.BTC, currency.USD), true},
+		{kraken, currency.NewPair(currency.BTC, currency.USD), false},
+	} {
+		_, err = f.Guard(tc.exch).SubmitOrder(tc.pair, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 1, "")
+		if (err != nil) != tc.blocked {
+			t.Errorf("Test Failed - SubmitOrder() %s %s expected blocked %v, got %v", tc.exch.name, tc.pair, tc.blocked, err)
+		}
+	}
+	if kraken.submitted != 1 || bitstamp.submitted != 1 || bitmex.submitted != 0 {
+		t.Errorf("Test Failed - SubmitOrder() blocked orders reached the exchange %d %d %d",
+			kraken.submitted, bitstamp.submitted, bitmex.submitted)
+	}
+
+	g := f.Guard(kraken)
+	if _, err = g.ModifyOrder(&exchange.ModifyOrder{CurrencyPair: currency.NewPair(currency.XMR, currency.EUR)}); err == nil {
+		t.Error("Test Failed - ModifyOrder() expected blacklisted asset blocked")
+	}
+	for _, tc := range []struct {
+		req     exchange.WithdrawRequest
+		blocked bool
+	}{
+		{exchange.WithdrawRequest{Currency: currency.ETH, Address: "0xabc123", Amount: 1}, true},
+		{exchange.WithdrawRequest{Currency: currency.BTC, Address: "1BadBtc", Amount: 1}, true},
+		{exchange.WithdrawRequest{Currency: currency.LTC, Address: "1BadBtc", Amount: 1}, false},
+		{exchange.WithdrawRequest{Currency: currency.XMR, Address: "4Good", Amount: 1}, true},
+		{exchange.WithdrawRequest{Currency: currency.BTC, Address: "1Good", Amount: 1}, false},
+	} {
+		req := tc.req
+		if _, err = g.WithdrawCryptocurrencyFunds(&req); (err != nil) != tc.blocked {
+			t.Errorf("Test Failed - WithdrawCryptocurrencyFunds() %s to %s expected blocked %v, got %v",
+				req.Currency, req.Address, tc.blocked, err)
+		}
+	}
+	if _, err = g.WithdrawCryptocurrencyFunds(nil); err != errNilWithdrawReq {
+		t.Error("Test Failed - WithdrawCryptocurrencyFunds() expected nil request error", err)
+	}
+	if kraken.withdrawn != 2 {
+		t.Errorf("Test Failed - WithdrawCryptocurrencyFunds() expected 2 withdrawals, got %d", kraken.withdrawn)
+	}
+
+	if f.GetBlocked() != 7 || len(events) != 7 {
+		t.Fatalf("Test Failed - Guard() expected 7 blocked actions, got %d %d", f.GetBlocked(), len(events))
+	}
+	if events[6].Type != Blocked || events[6].Action != Withdraw || events[6].Exchange != "Kraken" {
+		t.Errorf("Test Failed - Guard() unexpected event %+v", events[6])
+	}
+}
@@ -0,0 +1,346 @@
+// Package compositeindex tracks the constituents and weights of composite
+// indices such as Bitmex's .BXBT over time, recomputes each index locally from
+// the constituent exchanges' prices and alerts when the local value diverges
+// from the published index, as happens during constituent exchange outages
+package compositeindex
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Event types emitted by the tracker
+const (
+	Divergence   = "INDEX_DIVERGENCE"
+	WeightChange = "INDEX_WEIGHT_CHANGE"
+)
+
+// Default tracker settings
+const (
+	DefaultCheckInterval = time.Minute
+	// DefaultThreshold is the percentage divergence which is alerted
+	DefaultThreshold = 0.5
+	// DefaultHistory is the number of snapshots kept per index
+	DefaultHistory = 1440
+)
+
+// DefaultSymbol is the Bitmex XBT/USD index
+const DefaultSymbol = ".BXBT"
+
+var (
+	errNoSource        = errors.New("no index source supplied")
+	errNoPriceFunc     = errors.New("no price function supplied")
+	errNoLocalPrices   = errors.New("no constituent prices available locally")
+	errUnknownPair     = errors.New("no pair configured for index")
+	errIndexNotTracked = errors.New("index not tracked")
+)
+
+// References maps Bitmex constituent references to exchange names
+var References = map[string]string{
+	"BSTP": "Bitstamp",
+	"GDAX": "CoinbasePro",
+	"KRAK": "Kraken",
+	"GMNI": "Gemini",
+	"ITBT": "ITBIT",
+	"BTRX": "Bittrex",
+}
+
+// Pairs maps indices to the pair their constituents are priced in
+var Pairs = map[string]currency.Pair{
+	".BXBT": currency.NewPair(currency.BTC, currency.USD),
+	".BETH": currency.NewPair(currency.ETH, currency.USD),
+}
+
+// Constituent holds an exchange's weight and price within an index
+type Constituent struct {
+	Reference string  `json:"reference"`
+	Exchange  string  `json:"exchange,omitempty"`
+	Weight    float64 `json:"weight"`
+	// Price is the constituent price used by the index publisher
+	Price float64 `json:"price"`
+	// LocalPrice is the price observed locally, zero when unavailable
+	LocalPrice float64 `json:"localPrice"`
+}
+
+// Snapshot holds an index's published and locally computed values
+type Snapshot struct {
+	Symbol       string        `json:"symbol"`
+	Time         time.Time     `json:"time"`
+	Published    float64       `json:"published"`
+	Local        float64       `json:"local"`
+	Divergence   float64       `json:"divergence"`
+	Constituents []Constituent `json:"constituents"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// Source returns the published value and constituents of an index
+type Source interface {
+	GetComposite(symbol string) (Snapshot, error)
+}
+
+// PriceFunc returns the locally observed price of a pair on an exchange
+type PriceFunc func(exchangeName string, p currency.Pair) (float64, error)
+
+// Event defines a divergence or weight change
+type Event struct {
+	Type     string
+	Snapshot Snapshot
+	Detail   string
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	return fmt.Sprintf("%s %s: %s", e.Snapshot.Symbol, e.Type, e.Detail)
+}
+
+// Config defines the tracker settings
+type Config struct {
+	// Symbols are the indices tracked, DefaultSymbol when empty
+	Symbols []string
+	// Threshold is the percentage divergence between the local and published
+	// index which is alerted
+	Threshold float64
+	// History is the number of snapshots kept per index
+	History int
+}
+
+// Tracker records index constituents and compares the published index with
+// its local computation
+type Tracker struct {
+	cfg      Config
+	source   Source
+	prices   PriceFunc
+	onEvent  func(Event)
+	history  map[string][]Snapshot
+	diverged map[string]bool
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a composite index tracker
+func New(cfg Config, source Source, prices PriceFunc, onEvent func(Event)) (*Tracker, error) {
+	if source == nil {
+		return nil, errNoSource
+	}
+	if prices == nil {
+		return nil, errNoPriceFunc
+	}
+	if len(cfg.Symbols) == 0 {
+		cfg.Symbols = []string{DefaultSymbol}
+	}
+	if cfg.Threshold <= 0 {
+		cfg.Threshold = DefaultThreshold
+	}
+	if cfg.History <= 0 {
+		cfg.History = DefaultHistory
+	}
+	return &Tracker{
+		cfg:      cfg,
+		source:   source,
+		prices:   prices,
+		onEvent:  onEvent,
+		history:  make(map[string][]Snapshot),
+		diverged: make(map[string]bool),
+	}, nil
+}
+
+// Compute returns the index value from the constituents' local prices. The
+// weights of constituents without a local price are redistributed across the
+// rest, as the publisher does when a constituent is excluded
+func Compute(constituents []Constituent) (float64, error) {
+	var value, weight float64
+	for i := range constituents {
+		if constituents[i].LocalPrice <= 0 || constituents[i].Weight <= 0 {
+			continue
+		}
+		value += constituents[i].LocalPrice * constituents[i].Weight
+		weight += constituents[i].Weight
+	}
+	if weight == 0 {
+		return 0, errNoLocalPrices
+	}
+	return value / weight, nil
+}
+
+// Check fetches every tracked index and compares it with its local value
+func (t *Tracker) Check() {
+	for i := range t.cfg.Symbols {
+		err := t.check(t.cfg.Symbols[i])
+		if err != nil {
+			log.Errorf("Composite index tracker failed to check %s: %s", t.cfg.Symbols[i], err)
+		}
+	}
+}
+
+func (t *Tracker) check(symbol string) error {
+	p, ok := Pairs[symbol]
+	if !ok {
+		return fmt.Errorf("%s %v", symbol, errUnknownPair)
+	}
+	s, err := t.source.GetComposite(symbol)
+	if err != nil {
+		return err
+	}
+	s.Symbol = symbol
+	if s.Time.IsZero() {
+		s.Time = time.Now()
+	}
+
+	for i := range s.Constituents {
+		c := &s.Constituents[i]
+		if c.Exchange == "" {
+			c.Exchange = References[c.Reference]
+		}
+		if c.Exchange == "" {
+			continue
+		}
+		c.LocalPrice, err = t.prices(c.Exchange, p)
+		if err != nil {
+			log.Debugf("Composite index tracker %s %s price unavailable: %s",
+				symbol, c.Exchange, err)
+			c.LocalPrice = 0
+		}
+	}
+	sort.Slice(s.Constituents, func(i, j int) bool {
+		return s.Constituents[i].Reference < s.Constituents[j].Reference
+	})
+
+	s.Local, err = Compute(s.Constituents)
+	if err != nil {
+		s.Error = err.Error()
+	} else if s.Published > 0 {
+		s.Divergence = (s.Local - s.Published) / s.Published * 100
+	}
+
+	t.mtx.Lock()
+	var events []Event
+	history := t.history[symbol]
+	if len(history) > 0 {
+		if detail := weightChanges(history[len(history)-1].Constituents, s.Constituents); detail != "" {
+			events = append(events, Event{Type: WeightChange, Snapshot: s, Detail: detail})
+		}
+	}
+	diverged := s.Error == "" && s.Published > 0 && math.Abs(s.Divergence) >= t.cfg.Threshold
+	if diverged && !t.diverged[symbol] {
+		events = append(events, Event{Type: Divergence, Snapshot: s, Detail: fmt.Sprintf(
+			"local %.2f diverges %+.3f%% from published %.2f, threshold %.3f%%",
+			s.Local, s.Divergence, s.Published, t.cfg.Threshold)})
+	} else if !diverged && t.diverged[symbol] {
+		log.Debugf("Composite index %s local value converged with published index", symbol)
+	}
+	t.diverged[symbol] = diverged
+	history = append(history, s)
+	if excess := len(history) - t.cfg.History; excess > 0 {
+		history = history[excess:]
+	}
+	t.history[symbol] = history
+	t.mtx.Unlock()
+
+	for i := range events {
+		log.Warnf("Composite index tracker: %s", events[i].String())
+		if t.onEvent != nil {
+			t.onEvent(events[i])
+		}
+	}
+	return nil
+}
+
+// weightChanges describes constituents added, removed or reweighted
+func weightChanges(prev, cur []Constituent) string {
+	weights := make(map[string]float64, len(prev))
+	for i := range prev {
+		weights[prev[i].Reference] = prev[i].Weight
+	}
+	var changes []string
+	for i := range cur {
+		w, ok := weights[cur[i].Reference]
+		switch {
+		case !ok:
+			changes = append(changes, fmt.Sprintf("%s added at %v", cur[i].Reference, cur[i].Weight))
+		case w != cur[i].Weight:
+			changes = append(changes, fmt.Sprintf("%s %v to %v", cur[i].Reference, w, cur[i].Weight))
+		}
+		delete(weights, cur[i].Reference)
+	}
+	for ref := range weights {
+		changes = append(changes, ref+" removed")
+	}
+	sort.Strings(changes)
+	return strings.Join(changes, ", ")
+}
+
+// GetHistory returns the snapshots recorded for an index, oldest first
+func (t *Tracker) GetHistory(symbol string) ([]Snapshot, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	history, ok := t.history[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%s %v", symbol, errIndexNotTracked)
+	}
+	return append([]Snapshot(nil), history...), nil
+}
+
+// GetLatest returns the latest snapshot of every tracked index
+func (t *Tracker) GetLatest() []Snapshot {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var resp []Snapshot
+	for i := range t.cfg.Symbols {
+		if h := t.history[t.cfg.Symbols[i]]; len(h) > 0 {
+			resp = append(resp, h[len(h)-1])
+		}
+	}
+	return resp
+}
+
+// Start checks the indices at the interval until stopped
+func (t *Tracker) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	t.mtx.Lock()
+	if t.shutdown != nil {
+		t.mtx.Unlock()
+		return
+	}
+	t.shutdown = make(chan struct{})
+	shutdown := t.shutdown
+	t.mtx.Unlock()
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			t.Check()
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the tracker
+func (t *Tracker) Stop() {
+	t.mtx.Lock()
+	if t.shutdown == nil {
+		t.mtx.Unlock()
+		return
+	}
+	close(t.shutdown)
+	t.shutdown = nil
+	t.mtx.Unlock()
+	t.wg.Wait()
+}
@@ -0,0 +1,115 @@
+package compositeindex
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testSource struct {
+	snapshot Snapshot
+}
+
+func (t *testSource) GetComposite(_ string) (Snapshot, error) {
+	s := t.snapshot
+	s.Constituents = append([]Constituent(nil), t.snapshot.Constituents...)
+	return s, nil
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{}, nil, nil, nil); err != errNoSource {
+		t.Error("Test Failed - New() expected no source error", err)
+	}
+	if _, err := New(Config{}, &testSource{}, nil, nil); err != errNoPriceFunc {
+		t.Error("Test Failed - New() expected no price func error", err)
+	}
+}
+
+func TestCompute(t *testing.T) {
+	if _, err := Compute(nil); err != errNoLocalPrices {
+		t.Error("Test Failed - Compute() expected no local prices error", err)
+	}
+	v, err := Compute([]Constituent{
+		{Weight: 0.5, LocalPrice: 100},
+		{Weight: 0.25, LocalPrice: 200},
+		// Missing prices redistribute their weight
+		{Weight: 0.25},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(v-400.0/3) > 1e-9 {
+		t.Errorf("Test Failed - Compute() expected 133.33, got %v", v)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s := &testSource{snapshot: Snapshot{
+		Published: 100,
+		Constituents: []Constituent{
+			{Reference: "BSTP", Weight: 0.5, Price: 100},
+			{Reference: "KRAK", Weight: 0.5, Price: 100},
+		},
+	}}
+	prices := map[string]float64{"Bitstamp": 100, "Kraken": 100}
+	priceFunc := func(exchangeName string, _ currency.Pair) (float64, error) {
+		p, ok := prices[exchangeName]
+		if !ok {
+			return 0, errors.New("no ticker")
+		}
+		return p, nil
+	}
+	var events []Event
+	tr, err := New(Config{Threshold: 1}, s, priceFunc, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tr.Check()
+	if len(events) != 0 {
+		t.Fatalf("Test Failed - Check() unexpected events %+v", events)
+	}
+
+	// A constituent outage leaves the local value on the stale price
+	prices["Kraken"] = 110
+	tr.Check()
+	if len(events) != 1 || events[0].Type != Divergence {
+		t.Fatalf("Test Failed - Check() expected divergence, got %+v", events)
+	}
+	if math.Abs(events[0].Snapshot.Divergence-5) > 1e-9 {
+		t.Errorf("Test Failed - Check() expected 5%% divergence, got %v", events[0].Snapshot.Divergence)
+	}
+	// Divergence is alerted once until it recovers
+	tr.Check()
+	if len(events) != 1 {
+		t.Fatalf("Test Failed - Check() expected single divergence alert, got %+v", events)
+	}
+
+	delete(prices, "Kraken")
+	s.snapshot.Constituents = []Constituent{
+		{Reference: "BSTP", Weight: 0.6, Price: 100},
+		{Reference: "GMNI", Weight: 0.4, Price: 100},
+	}
+	tr.Check()
+	if len(events) != 2 || events[1].Type != WeightChange ||
+		events[1].Detail != "BSTP 0.5 to 0.6, GMNI added at 0.4, KRAK removed" {
+		t.Fatalf("Test Failed - Check() expected weight change, got %+v", events)
+	}
+
+	history, err := tr.GetHistory(DefaultSymbol)
+	if err != nil {
+		t.Fatal(err)
+	}
+	latest := history[len(history)-1]
+	if len(history) != 4 || latest.Local != 100 || latest.Constituents[1].Exchange != "Gemini" {
+		t.Errorf("Test Failed - GetHistory() unexpected history %+v", history)
+	}
+	if _, err = tr.GetHistory(".BETH"); err == nil {
+		t.Error("Test Failed - GetHistory() expected untracked index error")
+	}
+	if len(tr.GetLatest()) != 1 {
+		t.Error("Test Failed - GetLatest() expected one index")
+	}
+}
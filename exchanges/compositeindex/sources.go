@@ -0,0 +1,32 @@
+package compositeindex
+
+import (
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+)
+
+// BitmexComposite reports Bitmex composite indices
+type BitmexComposite struct {
+	Exchange *bitmex.Bitmex
+}
+
+// GetComposite returns the published index price and its constituents
+func (b *BitmexComposite) GetComposite(symbol string) (Snapshot, error) {
+	rows, published, err := b.Exchange.GetIndexConstituents(symbol)
+	if err != nil {
+		return Snapshot{}, err
+	}
+	s := Snapshot{Symbol: symbol, Published: published}
+	for i := range rows {
+		if s.Time.IsZero() {
+			s.Time, _ = time.Parse(time.RFC3339, rows[i].Timestamp)
+		}
+		s.Constituents = append(s.Constituents, Constituent{
+			Reference: rows[i].Reference,
+			Weight:    rows[i].Weight,
+			Price:     rows[i].LastPrice,
+		})
+	}
+	return s, nil
+}
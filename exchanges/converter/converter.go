@@ -0,0 +1,297 @@
+// Package converter converts an amount of one asset into another on a single
+// exchange by finding the best market route, either directly or through a
+// liquid intermediary asset, and executing each leg as a market order
+package converter
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// DefaultFeeRate is the taker fee rate applied to each leg when estimating
+// the output of a route
+const DefaultFeeRate = 0.002
+
+var (
+	errNilExchange       = errors.New("converter exchange is nil")
+	errInvalidAmount     = errors.New("converter amount must be positive")
+	errSameCurrency      = errors.New("converter from and to currencies are the same")
+	errNoRoute           = errors.New("converter no route available")
+	errInsufficientDepth = errors.New("converter insufficient orderbook depth")
+	errOrderNotPlaced    = errors.New("converter order was not placed")
+)
+
+// Intermediaries are the assets routes may pass through when no direct pair
+// exists, in order of preference
+var Intermediaries = []currency.Code{
+	currency.BTC,
+	currency.USDT,
+	currency.USD,
+	currency.ETH,
+}
+
+// Leg is a single market order within a route
+type Leg struct {
+	Pair      currency.Pair
+	Side      exchange.OrderSide
+	From      currency.Code
+	To        currency.Code
+	AmountIn  float64
+	AmountOut float64
+	// Price is the volume weighted average price of the leg
+	Price   float64
+	OrderID string
+}
+
+// Route is an ordered set of legs converting one asset into another
+type Route struct {
+	Legs []Leg
+}
+
+// String implements the stringer interface
+func (r Route) String() string {
+	if len(r.Legs) == 0 {
+		return ""
+	}
+	s := r.Legs[0].From.String()
+	for i := range r.Legs {
+		s += " -> " + r.Legs[i].To.String()
+	}
+	return s
+}
+
+// Result details a quoted or executed conversion
+type Result struct {
+	Exchange      string
+	From          currency.Code
+	To            currency.Code
+	AmountIn      float64
+	AmountOut     float64
+	EffectiveRate float64
+	Route         Route
+	Executed      bool
+}
+
+// BookFunc returns the orderbook for a pair
+type BookFunc func(p currency.Pair) (orderbook.Base, error)
+
+// FindRoutes returns every direct and single intermediary route between two
+// currencies using the supplied pairs
+func FindRoutes(pairs currency.Pairs, from, to currency.Code) []Route {
+	var routes []Route
+	if leg, ok := findLeg(pairs, from, to); ok {
+		routes = append(routes, Route{Legs: []Leg{leg}})
+	}
+	for i := range Intermediaries {
+		if Intermediaries[i].Match(from) || Intermediaries[i].Match(to) {
+			continue
+		}
+		first, ok := findLeg(pairs, from, Intermediaries[i])
+		if !ok {
+			continue
+		}
+		second, ok := findLeg(pairs, Intermediaries[i], to)
+		if !ok {
+			continue
+		}
+		routes = append(routes, Route{Legs: []Leg{first, second}})
+	}
+	return routes
+}
+
+// findLeg returns the leg which converts from into to using the supplied pairs
+func findLeg(pairs currency.Pairs, from, to currency.Code) (Leg, bool) {
+	for i := range pairs {
+		switch {
+		case pairs[i].Base.Match(from) && pairs[i].Quote.Match(to):
+			return Leg{Pair: pairs[i], Side: exchange.SellOrderSide, From: from, To: to}, true
+		case pairs[i].Base.Match(to) && pairs[i].Quote.Match(from):
+			return Leg{Pair: pairs[i], Side: exchange.BuyOrderSide, From: from, To: to}, true
+		}
+	}
+	return Leg{}, false
+}
+
+// QuoteRoute walks the orderbook of each leg to estimate the output of
+// converting amount through the route, net of the supplied fee rate
+func QuoteRoute(r Route, amount, feeRate float64, book BookFunc) (Route, error) {
+	if amount <= 0 {
+		return r, errInvalidAmount
+	}
+	quoted := Route{Legs: make([]Leg, len(r.Legs))}
+	copy(quoted.Legs, r.Legs)
+	in := amount
+	for i := range quoted.Legs {
+		ob, err := book(quoted.Legs[i].Pair)
+		if err != nil {
+			return r, err
+		}
+		var out float64
+		if quoted.Legs[i].Side == exchange.SellOrderSide {
+			out, err = sellBase(ob.Bids, in)
+		} else {
+			out, err = buyBase(ob.Asks, in)
+		}
+		if err != nil {
+			return r, fmt.Errorf("%s %v", quoted.Legs[i].Pair, err)
+		}
+		out *= 1 - feeRate
+		quoted.Legs[i].AmountIn = in
+		quoted.Legs[i].AmountOut = out
+		if quoted.Legs[i].Side == exchange.SellOrderSide {
+			quoted.Legs[i].Price = out / in
+		} else {
+			quoted.Legs[i].Price = in / out
+		}
+		in = out
+	}
+	return quoted, nil
+}
+
+// sellBase returns the quote received by selling amount of base into bids
+func sellBase(bids []orderbook.Item, amount float64) (float64, error) {
+	var received float64
+	remaining := amount
+	for i := range bids {
+		if remaining <= 0 {
+			break
+		}
+		fill := bids[i].Amount
+		if fill > remaining {
+			fill = remaining
+		}
+		received += fill * bids[i].Price
+		remaining -= fill
+	}
+	if remaining > 0 {
+		return 0, errInsufficientDepth
+	}
+	return received, nil
+}
+
+// buyBase returns the base received by spending amount of quote into asks
+func buyBase(asks []orderbook.Item, amount float64) (float64, error) {
+	var received float64
+	remaining := amount
+	for i := range asks {
+		if remaining <= 0 {
+			break
+		}
+		if asks[i].Price <= 0 {
+			continue
+		}
+		cost := asks[i].Amount * asks[i].Price
+		if cost > remaining {
+			cost = remaining
+		}
+		received += cost / asks[i].Price
+		remaining -= cost
+	}
+	if remaining > 0 {
+		return 0, errInsufficientDepth
+	}
+	return received, nil
+}
+
+// Quote finds the route that yields the most of the to currency when
+// converting amount of the from currency on the supplied exchange
+func Quote(e exchange.IBotExchange, from, to currency.Code, amount, feeRate float64) (Result, error) {
+	if e == nil {
+		return Result{}, errNilExchange
+	}
+	if amount <= 0 {
+		return Result{}, errInvalidAmount
+	}
+	if from.Match(to) {
+		return Result{}, errSameCurrency
+	}
+
+	routes := FindRoutes(e.GetEnabledCurrencies(), from, to)
+	if len(routes) == 0 {
+		return Result{}, fmt.Errorf("%s %s to %s %v", e.GetName(), from, to, errNoRoute)
+	}
+
+	books := make(map[string]orderbook.Base)
+	book := func(p currency.Pair) (orderbook.Base, error) {
+		if ob, ok := books[p.String()]; ok {
+			return ob, nil
+		}
+		ob, err := e.UpdateOrderbook(p, ticker.Spot)
+		if err != nil {
+			return ob, err
+		}
+		books[p.String()] = ob
+		return ob, nil
+	}
+
+	var best Route
+	var bestOut float64
+	for i := range routes {
+		quoted, err := QuoteRoute(routes[i], amount, feeRate, book)
+		if err != nil {
+			log.Debugf("Converter %s route %s unavailable: %v", e.GetName(), routes[i], err)
+			continue
+		}
+		out := quoted.Legs[len(quoted.Legs)-1].AmountOut
+		if out > bestOut {
+			best, bestOut = quoted, out
+		}
+	}
+	if bestOut == 0 {
+		return Result{}, fmt.Errorf("%s %s to %s %v", e.GetName(), from, to, errNoRoute)
+	}
+
+	return Result{
+		Exchange:      e.GetName(),
+		From:          from,
+		To:            to,
+		AmountIn:      amount,
+		AmountOut:     bestOut,
+		EffectiveRate: bestOut / amount,
+		Route:         best,
+	}, nil
+}
+
+// Convert quotes the best route and executes each leg as a market order. Buy
+// legs are sized in base currency using the quoted output of the previous
+// leg. Execution stops at the first failed leg and the partially executed
+// result is returned alongside the error
+func Convert(e exchange.IBotExchange, from, to currency.Code, amount, feeRate float64) (Result, error) {
+	result, err := Quote(e, from, to, amount, feeRate)
+	if err != nil {
+		return result, err
+	}
+
+	for i := range result.Route.Legs {
+		leg := &result.Route.Legs[i]
+		size := leg.AmountIn
+		if leg.Side == exchange.BuyOrderSide {
+			size = leg.AmountOut
+		}
+		resp, err := e.SubmitOrder(leg.Pair,
+			leg.Side,
+			exchange.MarketOrderType,
+			size,
+			leg.Price,
+			"")
+		if err != nil {
+			return result, fmt.Errorf("%s leg %d %s %s %v",
+				result.Exchange, i+1, leg.Side, leg.Pair, err)
+		}
+		if !resp.IsOrderPlaced {
+			return result, fmt.Errorf("%s leg %d %s %s %v",
+				result.Exchange, i+1, leg.Side, leg.Pair, errOrderNotPlaced)
+		}
+		leg.OrderID = resp.OrderID
+		log.Debugf("Converter %s leg %d %s %f %s placed order %s",
+			result.Exchange, i+1, leg.Side, size, leg.Pair, resp.OrderID)
+	}
+	result.Executed = true
+	return result, nil
+}
@@ -0,0 +1,199 @@
+package converter
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	pairs  currency.Pairs
+	books  map[string]orderbook.Base
+	orders []exchange.OrderSide
+	sizes  []float64
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) GetEnabledCurrencies() currency.Pairs { return t.pairs }
+
+func (t *testExchange) UpdateOrderbook(p currency.Pair, _ string) (orderbook.Base, error) {
+	ob, ok := t.books[p.String()]
+	if !ok {
+		return ob, errors.New("no orderbook")
+	}
+	return ob, nil
+}
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, side exchange.OrderSide, _ exchange.OrderType, amount, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.orders = append(t.orders, side)
+	t.sizes = append(t.sizes, amount)
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true, OrderID: "1"}, nil
+}
+
+func newTestExchange() *testExchange {
+	ltcbtc := currency.NewPairWithDelimiter("LTC", "BTC", "-")
+	ltcusdt := currency.NewPairWithDelimiter("LTC", "USDT", "-")
+	btcusdt := currency.NewPairWithDelimiter("BTC", "USDT", "-")
+	return &testExchange{
+		pairs: currency.Pairs{ltcbtc, ltcusdt, btcusdt},
+		books: map[string]orderbook.Base{
+			ltcbtc.String(): {
+				Bids: []orderbook.Item{{Price: 0.007, Amount: 100}},
+				Asks: []orderbook.Item{{Price: 0.0101, Amount: 100}},
+			},
+			ltcusdt.String(): {
+				Bids: []orderbook.Item{{Price: 40, Amount: 5}, {Price: 30, Amount: 100}},
+				Asks: []orderbook.Item{{Price: 41, Amount: 100}},
+			},
+			btcusdt.String(): {
+				Bids: []orderbook.Item{{Price: 5000, Amount: 10}},
+				Asks: []orderbook.Item{{Price: 5001, Amount: 10}},
+			},
+		},
+	}
+}
+
+func TestFindRoutes(t *testing.T) {
+	e := newTestExchange()
+	routes := FindRoutes(e.pairs, currency.LTC, currency.USDT)
+	if len(routes) != 2 {
+		t.Fatalf("Test Failed - FindRoutes() expected 2 routes, received %d", len(routes))
+	}
+	if routes[0].String() != "LTC -> USDT" {
+		t.Errorf("Test Failed - FindRoutes() unexpected direct route %s", routes[0])
+	}
+	if routes[1].String() != "LTC -> BTC -> USDT" {
+		t.Errorf("Test Failed - FindRoutes() unexpected indirect route %s", routes[1])
+	}
+	if routes[1].Legs[0].Side != exchange.SellOrderSide ||
+		routes[1].Legs[1].Side != exchange.SellOrderSide {
+		t.Error("Test Failed - FindRoutes() expected sell legs")
+	}
+
+	routes = FindRoutes(e.pairs, currency.USDT, currency.LTC)
+	if len(routes) != 2 || routes[1].Legs[0].Side != exchange.BuyOrderSide {
+		t.Error("Test Failed - FindRoutes() expected buy leg into BTC")
+	}
+
+	if routes = FindRoutes(e.pairs, currency.LTC, currency.XRP); len(routes) != 0 {
+		t.Error("Test Failed - FindRoutes() expected no routes")
+	}
+}
+
+func TestQuoteRoute(t *testing.T) {
+	e := newTestExchange()
+	routes := FindRoutes(e.pairs, currency.LTC, currency.USDT)
+	book := func(p currency.Pair) (orderbook.Base, error) {
+		return e.UpdateOrderbook(p, "")
+	}
+
+	quoted, err := QuoteRoute(routes[0], 10, 0, book)
+	if err != nil {
+		t.Fatal("Test Failed - QuoteRoute() error", err)
+	}
+	if quoted.Legs[0].AmountOut != 350 {
+		t.Errorf("Test Failed - QuoteRoute() expected 350, received %f",
+			quoted.Legs[0].AmountOut)
+	}
+	if quoted.Legs[0].Price != 35 {
+		t.Errorf("Test Failed - QuoteRoute() expected average price 35, received %f",
+			quoted.Legs[0].Price)
+	}
+	if routes[0].Legs[0].AmountOut != 0 {
+		t.Error("Test Failed - QuoteRoute() modified the supplied route")
+	}
+
+	_, err = QuoteRoute(routes[0], 1000, 0, book)
+	if err == nil {
+		t.Error("Test Failed - QuoteRoute() expected insufficient depth error")
+	}
+
+	_, err = QuoteRoute(routes[0], 0, 0, book)
+	if err != errInvalidAmount {
+		t.Errorf("Test Failed - QuoteRoute() expected %v, received %v",
+			errInvalidAmount, err)
+	}
+}
+
+func TestQuote(t *testing.T) {
+	e := newTestExchange()
+	_, err := Quote(nil, currency.LTC, currency.USDT, 1, 0)
+	if err != errNilExchange {
+		t.Errorf("Test Failed - Quote() expected %v, received %v", errNilExchange, err)
+	}
+	_, err = Quote(e, currency.LTC, currency.LTC, 1, 0)
+	if err != errSameCurrency {
+		t.Errorf("Test Failed - Quote() expected %v, received %v", errSameCurrency, err)
+	}
+
+	// Small amounts fill at the top of the direct book
+	r, err := Quote(e, currency.LTC, currency.USDT, 1, 0)
+	if err != nil {
+		t.Fatal("Test Failed - Quote() error", err)
+	}
+	if len(r.Route.Legs) != 1 || r.AmountOut != 40 {
+		t.Errorf("Test Failed - Quote() expected direct route returning 40, received %s returning %f",
+			r.Route, r.AmountOut)
+	}
+
+	// Larger amounts exhaust the direct book and route through BTC
+	r, err = Quote(e, currency.LTC, currency.USDT, 50, 0)
+	if err != nil {
+		t.Fatal("Test Failed - Quote() error", err)
+	}
+	if len(r.Route.Legs) != 2 || math.Abs(r.AmountOut-1750) > 1e-9 {
+		t.Errorf("Test Failed - Quote() expected BTC route returning 1750, received %s returning %f",
+			r.Route, r.AmountOut)
+	}
+	if math.Abs(r.EffectiveRate-35) > 1e-9 {
+		t.Errorf("Test Failed - Quote() expected effective rate 35, received %f",
+			r.EffectiveRate)
+	}
+
+	r, err = Quote(e, currency.LTC, currency.USDT, 1, 0.01)
+	if err != nil {
+		t.Fatal("Test Failed - Quote() error", err)
+	}
+	if math.Abs(r.AmountOut-39.6) > 1e-9 {
+		t.Errorf("Test Failed - Quote() expected fee adjusted 39.6, received %f",
+			r.AmountOut)
+	}
+
+	_, err = Quote(e, currency.LTC, currency.XRP, 1, 0)
+	if err == nil {
+		t.Error("Test Failed - Quote() expected no route error")
+	}
+}
+
+func TestConvert(t *testing.T) {
+	e := newTestExchange()
+	r, err := Convert(e, currency.USDT, currency.LTC, 1000, 0)
+	if err != nil {
+		t.Fatal("Test Failed - Convert() error", err)
+	}
+	if !r.Executed {
+		t.Error("Test Failed - Convert() expected executed result")
+	}
+	if len(e.orders) != len(r.Route.Legs) {
+		t.Fatalf("Test Failed - Convert() expected %d orders, received %d",
+			len(r.Route.Legs), len(e.orders))
+	}
+	for i := range r.Route.Legs {
+		if e.orders[i] != r.Route.Legs[i].Side {
+			t.Errorf("Test Failed - Convert() leg %d side mismatch", i)
+		}
+		if r.Route.Legs[i].Side == exchange.BuyOrderSide &&
+			e.sizes[i] != r.Route.Legs[i].AmountOut {
+			t.Errorf("Test Failed - Convert() leg %d buy not sized in base", i)
+		}
+		if r.Route.Legs[i].OrderID != "1" {
+			t.Errorf("Test Failed - Convert() leg %d order ID not set", i)
+		}
+	}
+}
@@ -0,0 +1,380 @@
+// Package deposits tracks inbound exchange deposits and their blockchain
+// confirmations against the count each exchange requires before crediting
+// them, so strategies know when incoming funds become tradeable
+package deposits
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Event types emitted by the tracker
+const (
+	Detected = "DEPOSIT_DETECTED"
+	Credited = "DEPOSIT_CREDITED"
+)
+
+// Default tracker settings
+const (
+	DefaultCheckInterval = time.Minute
+	// DefaultLookback is how far back deposits are fetched and tracked
+	DefaultLookback = time.Hour * 24
+	// requiredTTL is how long required confirmation counts are cached
+	requiredTTL = time.Hour
+)
+
+var (
+	errNoSources      = errors.New("no deposit sources supplied")
+	errSourceNotFound = errors.New("deposit source not found")
+	errNoBlockTime    = errors.New("no block time known for currency")
+	// ErrNotExposed is returned by sources for exchanges which do not publish
+	// the confirmations they require
+	ErrNotExposed = errors.New("required confirmations not exposed by exchange")
+)
+
+// BlockTimes holds the average block interval of common currencies, used to
+// estimate when pending deposits are credited
+var BlockTimes = map[string]time.Duration{
+	"BTC":  time.Minute * 10,
+	"XBT":  time.Minute * 10,
+	"BCH":  time.Minute * 10,
+	"BSV":  time.Minute * 10,
+	"LTC":  time.Second * 150,
+	"DOGE": time.Minute,
+	"DASH": time.Second * 150,
+	"ZEC":  time.Second * 75,
+	"ETH":  time.Second * 13,
+	"ETC":  time.Second * 13,
+	"USDT": time.Second * 13,
+	"XMR":  time.Minute * 2,
+	"XRP":  time.Second * 4,
+	"XLM":  time.Second * 5,
+	"EOS":  time.Second / 2,
+	"XTZ":  time.Minute,
+	"ADA":  time.Second * 20,
+	"DOT":  time.Second * 6,
+}
+
+// Deposit holds an inbound deposit and its confirmations
+type Deposit struct {
+	Exchange      string        `json:"exchange"`
+	Currency      currency.Code `json:"currency"`
+	Amount        float64       `json:"amount"`
+	TxID          string        `json:"txid"`
+	Address       string        `json:"address,omitempty"`
+	Status        string        `json:"status"`
+	Confirmations int           `json:"confirmations"`
+	// Required is the confirmations the exchange requires before crediting
+	// the deposit, zero when the exchange does not expose it
+	Required int `json:"required"`
+	// Credited is set once the funds are available to trade
+	Credited  bool      `json:"credited"`
+	Timestamp time.Time `json:"timestamp"`
+	// EstimatedCredit is when a pending deposit is expected to be credited,
+	// zero when unknown
+	EstimatedCredit time.Time `json:"estimatedCredit,omitempty"`
+}
+
+// Remaining returns the confirmations still required, zero when credited or
+// unknown
+func (d *Deposit) Remaining() int {
+	if d.Credited || d.Required <= d.Confirmations {
+		return 0
+	}
+	return d.Required - d.Confirmations
+}
+
+// key returns the identifier of the deposit
+func (d *Deposit) key() string {
+	return strings.ToLower(d.Exchange) + "/" + d.Currency.Upper().String() + "/" + d.TxID
+}
+
+// Source is implemented by exchanges which report inbound deposits
+type Source interface {
+	GetName() string
+	// GetRequiredConfirmations returns the confirmations the exchange
+	// requires before crediting deposits of the currency
+	GetRequiredConfirmations(c currency.Code) (int, error)
+	// GetDeposits returns deposits made since the time
+	GetDeposits(since time.Time) ([]Deposit, error)
+}
+
+// Event defines a deposit detected or credited
+type Event struct {
+	Type    string
+	Deposit Deposit
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	d := &e.Deposit
+	if e.Type == Credited {
+		return fmt.Sprintf("%s deposit of %v %s credited, txid %s",
+			d.Exchange, d.Amount, d.Currency, d.TxID)
+	}
+	msg := fmt.Sprintf("%s deposit of %v %s detected with %d confirmations",
+		d.Exchange, d.Amount, d.Currency, d.Confirmations)
+	if d.Required > 0 {
+		msg += fmt.Sprintf(" of %d required", d.Required)
+	}
+	if !d.EstimatedCredit.IsZero() {
+		msg += ", expected to be credited at " + d.EstimatedCredit.Format(time.RFC3339)
+	}
+	return msg
+}
+
+type required struct {
+	count   int
+	fetched time.Time
+}
+
+// Tracker polls sources for deposits and tracks them until credited
+type Tracker struct {
+	sources  []Source
+	onEvent  func(Event)
+	lookback time.Duration
+	deposits map[string]*Deposit
+	required map[string]required
+	seeded   map[string]bool
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a deposit tracker for the sources, onEvent is called for each
+// deposit detected and credited
+func New(sources []Source, onEvent func(Event)) (*Tracker, error) {
+	if len(sources) == 0 {
+		return nil, errNoSources
+	}
+	return &Tracker{
+		sources:  sources,
+		onEvent:  onEvent,
+		lookback: DefaultLookback,
+		deposits: make(map[string]*Deposit),
+		required: make(map[string]required),
+		seeded:   make(map[string]bool),
+	}, nil
+}
+
+func (t *Tracker) source(exchangeName string) (Source, error) {
+	for i := range t.sources {
+		if strings.EqualFold(t.sources[i].GetName(), exchangeName) {
+			return t.sources[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s %v", exchangeName, errSourceNotFound)
+}
+
+// requiredConfirmations returns the cached confirmation count, the caller
+// must hold the lock
+func (t *Tracker) requiredConfirmations(s Source, c currency.Code) (int, error) {
+	k := strings.ToLower(s.GetName()) + "/" + c.Upper().String()
+	if r, ok := t.required[k]; ok && time.Since(r.fetched) < requiredTTL {
+		return r.count, nil
+	}
+	n, err := s.GetRequiredConfirmations(c)
+	if err != nil && err != ErrNotExposed {
+		return 0, err
+	}
+	// Unexposed counts are cached as unknown so they are not refetched
+	t.required[k] = required{count: n, fetched: time.Now()}
+	return n, nil
+}
+
+// RequiredConfirmations returns the confirmations an exchange requires before
+// crediting deposits of the currency, zero when it is not exposed
+func (t *Tracker) RequiredConfirmations(exchangeName string, c currency.Code) (int, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	s, err := t.source(exchangeName)
+	if err != nil {
+		return 0, err
+	}
+	return t.requiredConfirmations(s, c)
+}
+
+// EstimateCredit returns how long a new deposit of the currency takes to be
+// credited after it is broadcast, for estimating transfer times between
+// exchanges
+func (t *Tracker) EstimateCredit(exchangeName string, c currency.Code) (time.Duration, error) {
+	n, err := t.RequiredConfirmations(exchangeName, c)
+	if err != nil {
+		return 0, err
+	}
+	if n == 0 {
+		return 0, fmt.Errorf("%s %s %v", exchangeName, c, ErrNotExposed)
+	}
+	blockTime, ok := BlockTimes[c.Upper().String()]
+	if !ok {
+		return 0, fmt.Errorf("%s %v", c, errNoBlockTime)
+	}
+	return blockTime * time.Duration(n), nil
+}
+
+// Check polls every source for deposits
+func (t *Tracker) Check() {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	for i := range t.sources {
+		err := t.check(t.sources[i])
+		if err != nil {
+			log.Errorf("Deposit tracker failed to check %s: %s", t.sources[i].GetName(), err)
+		}
+	}
+	t.prune()
+}
+
+// check updates the deposits of a single source, the caller must hold the
+// lock. Deposits found on the first check are recorded without events
+func (t *Tracker) check(s Source) error {
+	deposits, err := s.GetDeposits(time.Now().Add(-t.lookback))
+	if err != nil {
+		return err
+	}
+	name := strings.ToLower(s.GetName())
+	seeded := t.seeded[name]
+	t.seeded[name] = true
+
+	for i := range deposits {
+		d := deposits[i]
+		if d.Exchange == "" {
+			d.Exchange = s.GetName()
+		}
+		if !d.Credited && d.Required == 0 {
+			d.Required, err = t.requiredConfirmations(s, d.Currency)
+			if err != nil {
+				log.Warnf("Deposit tracker %s %s required confirmations unavailable: %s",
+					s.GetName(), d.Currency, err)
+			}
+		}
+		estimate(&d)
+
+		prev, known := t.deposits[d.key()]
+		t.deposits[d.key()] = &d
+		switch {
+		case !seeded:
+		case !known && !d.Credited:
+			t.emit(Detected, &d)
+		case d.Credited && (!known || !prev.Credited):
+			t.emit(Credited, &d)
+		}
+	}
+	return nil
+}
+
+// estimate sets when a pending deposit is expected to be credited
+func estimate(d *Deposit) {
+	d.EstimatedCredit = time.Time{}
+	if d.Credited || d.Required == 0 {
+		return
+	}
+	blockTime, ok := BlockTimes[d.Currency.Upper().String()]
+	if !ok {
+		return
+	}
+	d.EstimatedCredit = time.Now().Add(blockTime * time.Duration(d.Remaining()))
+}
+
+// prune removes deposits older than the lookback which sources no longer
+// return, the caller must hold the lock
+func (t *Tracker) prune() {
+	cutoff := time.Now().Add(-t.lookback)
+	for k, d := range t.deposits {
+		if d.Timestamp.Before(cutoff) {
+			delete(t.deposits, k)
+		}
+	}
+}
+
+// emit publishes an event, the caller must hold the lock
+func (t *Tracker) emit(eventType string, d *Deposit) {
+	if t.onEvent != nil {
+		t.onEvent(Event{Type: eventType, Deposit: *d})
+	}
+}
+
+// GetDeposits returns tracked deposits on the exchange, or on every exchange
+// when the name is empty, newest first. Credited deposits are only returned
+// when all is set
+func (t *Tracker) GetDeposits(exchangeName string, all bool) []Deposit {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var resp []Deposit
+	for _, d := range t.deposits {
+		if exchangeName != "" && !strings.EqualFold(d.Exchange, exchangeName) {
+			continue
+		}
+		if d.Credited && !all {
+			continue
+		}
+		resp = append(resp, *d)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Timestamp.After(resp[j].Timestamp)
+	})
+	return resp
+}
+
+// GetPendingAmount returns the amount of a currency deposited to the exchange
+// but not yet credited
+func (t *Tracker) GetPendingAmount(exchangeName string, c currency.Code) float64 {
+	var total float64
+	for _, d := range t.GetDeposits(exchangeName, false) {
+		if d.Currency.Match(c) {
+			total += d.Amount
+		}
+	}
+	return total
+}
+
+// Start checks the sources at the interval until stopped
+func (t *Tracker) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	t.mtx.Lock()
+	if t.shutdown != nil {
+		t.mtx.Unlock()
+		return
+	}
+	t.shutdown = make(chan struct{})
+	shutdown := t.shutdown
+	t.mtx.Unlock()
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		t.Check()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				t.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops the tracker
+func (t *Tracker) Stop() {
+	t.mtx.Lock()
+	if t.shutdown == nil {
+		t.mtx.Unlock()
+		return
+	}
+	close(t.shutdown)
+	t.shutdown = nil
+	t.mtx.Unlock()
+	t.wg.Wait()
+}
@@ -0,0 +1,131 @@
+package deposits
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testSource struct {
+	deposits      []Deposit
+	required      map[string]int
+	requiredCalls int
+	err           error
+}
+
+func (t *testSource) GetName() string { return "Test" }
+
+func (t *testSource) GetRequiredConfirmations(c currency.Code) (int, error) {
+	t.requiredCalls++
+	n, ok := t.required[c.String()]
+	if !ok {
+		return 0, ErrNotExposed
+	}
+	return n, nil
+}
+
+func (t *testSource) GetDeposits(_ time.Time) ([]Deposit, error) {
+	return t.deposits, t.err
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil, nil); err != errNoSources {
+		t.Error("Test Failed - New() expected no sources error", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	s := &testSource{
+		required: map[string]int{"BTC": 3},
+		deposits: []Deposit{
+			{Currency: currency.BTC, Amount: 0.5, TxID: "old", Credited: true, Timestamp: now.Add(-time.Hour)},
+		},
+	}
+	var events []Event
+	tr, err := New([]Source{s}, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Deposits found on the first check are not alerted
+	tr.Check()
+	if len(events) != 0 || len(tr.GetDeposits("", true)) != 1 || len(tr.GetDeposits("", false)) != 0 {
+		t.Fatalf("Test Failed - Check() unexpected seed %+v", events)
+	}
+
+	s.deposits = append(s.deposits, Deposit{Currency: currency.BTC, Amount: 1, TxID: "new", Confirmations: 1, Timestamp: now})
+	tr.Check()
+	if len(events) != 1 || events[0].Type != Detected {
+		t.Fatalf("Test Failed - Check() expected detected event, got %+v", events)
+	}
+	pending := tr.GetDeposits("test", false)
+	if len(pending) != 1 || pending[0].Required != 3 || pending[0].Remaining() != 2 || pending[0].Exchange != "Test" {
+		t.Fatalf("Test Failed - GetDeposits() unexpected pending %+v", pending)
+	}
+	// Two BTC blocks remain
+	eta := time.Until(pending[0].EstimatedCredit)
+	if eta < time.Minute*19 || eta > time.Minute*20 {
+		t.Errorf("Test Failed - Check() unexpected estimated credit in %v", eta)
+	}
+	if !strings.Contains(events[0].String(), "1 confirmations of 3 required") {
+		t.Errorf("Test Failed - Event.String() unexpected %s", events[0].String())
+	}
+	if tr.GetPendingAmount("Test", currency.BTC) != 1 {
+		t.Error("Test Failed - GetPendingAmount() expected 1")
+	}
+
+	// Confirmations progress without further events until credited
+	s.deposits[1].Confirmations = 2
+	tr.Check()
+	if len(events) != 1 || tr.GetDeposits("", false)[0].Confirmations != 2 {
+		t.Error("Test Failed - Check() expected confirmations updated without event")
+	}
+	s.deposits[1].Credited = true
+	tr.Check()
+	if len(events) != 2 || events[1].Type != Credited || len(tr.GetDeposits("", false)) != 0 {
+		t.Errorf("Test Failed - Check() expected credited event, got %+v", events)
+	}
+	if s.requiredCalls != 1 {
+		t.Errorf("Test Failed - Check() expected required confirmations cached, fetched %d times", s.requiredCalls)
+	}
+
+	// Deposits older than the lookback are pruned
+	s.deposits = []Deposit{{Currency: currency.ETH, TxID: "stale", Timestamp: now.Add(-DefaultLookback * 2)}}
+	tr.Check()
+	for _, d := range tr.GetDeposits("", true) {
+		if d.TxID == "stale" {
+			t.Error("Test Failed - Check() expected stale deposit pruned")
+		}
+	}
+
+	s.err = errors.New("exchange unavailable")
+	tr.Check()
+	if len(tr.GetDeposits("", true)) != 2 {
+		t.Error("Test Failed - Check() expected deposits kept when a source fails")
+	}
+}
+
+func TestEstimateCredit(t *testing.T) {
+	s := &testSource{required: map[string]int{"BTC": 2, "ABC": 5}}
+	tr, _ := New([]Source{s}, nil)
+	d, err := tr.EstimateCredit("test", currency.BTC)
+	if err != nil || d != time.Minute*20 {
+		t.Error("Test Failed - EstimateCredit() expected 20m", d, err)
+	}
+	if _, err = tr.EstimateCredit("test", currency.NewCode("ABC")); err == nil {
+		t.Error("Test Failed - EstimateCredit() expected no block time error")
+	}
+	if _, err = tr.EstimateCredit("test", currency.ETH); err == nil {
+		t.Error("Test Failed - EstimateCredit() expected not exposed error")
+	}
+	if _, err = tr.EstimateCredit("other", currency.BTC); err == nil {
+		t.Error("Test Failed - EstimateCredit() expected source not found error")
+	}
+	if n, err := tr.RequiredConfirmations("Test", currency.ETH); err != nil || n != 0 {
+		t.Error("Test Failed - RequiredConfirmations() expected unknown count", n, err)
+	}
+}
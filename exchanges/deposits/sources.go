@@ -0,0 +1,138 @@
+package deposits
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/poloniex"
+)
+
+const (
+	poloniexComplete = "COMPLETE"
+
+	krakenSuccess = "Success"
+	krakenSettled = "Settled"
+	krakenFailure = "Failure"
+	// krakenStakedSuffix marks Kraken balances held in staking which cannot
+	// be deposited
+	krakenStakedSuffix = ".S"
+)
+
+// PoloniexDeposits reports Poloniex deposits, which include their
+// confirmations, and the minimum confirmations of each currency
+type PoloniexDeposits struct {
+	Exchange *poloniex.Poloniex
+}
+
+// GetName returns the exchange name
+func (p *PoloniexDeposits) GetName() string {
+	return p.Exchange.GetName()
+}
+
+// GetRequiredConfirmations returns the currency's minimum confirmations
+func (p *PoloniexDeposits) GetRequiredConfirmations(c currency.Code) (int, error) {
+	currencies, err := p.Exchange.GetCurrencies()
+	if err != nil {
+		return 0, err
+	}
+	info, ok := currencies[c.Upper().String()]
+	if !ok {
+		return 0, ErrNotExposed
+	}
+	return info.MinConfirmations, nil
+}
+
+// GetDeposits returns deposits made since the time
+func (p *PoloniexDeposits) GetDeposits(since time.Time) ([]Deposit, error) {
+	resp, err := p.Exchange.GetDepositsWithdrawals(strconv.FormatInt(since.Unix(), 10), "")
+	if err != nil {
+		return nil, err
+	}
+	deposits := make([]Deposit, 0, len(resp.Deposits))
+	for i := range resp.Deposits {
+		d := &resp.Deposits[i]
+		deposits = append(deposits, Deposit{
+			Exchange:      p.GetName(),
+			Currency:      currency.NewCode(d.Currency),
+			Amount:        d.Amount,
+			TxID:          d.TransactionID,
+			Address:       d.Address,
+			Status:        d.Status,
+			Confirmations: d.Confirmations,
+			Credited:      strings.HasPrefix(d.Status, poloniexComplete),
+			Timestamp:     time.Unix(d.Timestamp, 0),
+		})
+	}
+	return deposits, nil
+}
+
+// KrakenDeposits reports Kraken deposits. Kraken does not publish required
+// confirmations or the confirmations of pending deposits, so deposits are
+// tracked by status only
+type KrakenDeposits struct {
+	Exchange *kraken.Kraken
+	// Assets are the Kraken assets polled for deposits, empty polls every
+	// asset held in the account
+	Assets []string
+}
+
+// GetName returns the exchange name
+func (k *KrakenDeposits) GetName() string {
+	return k.Exchange.GetName()
+}
+
+// GetRequiredConfirmations returns ErrNotExposed, Kraken's deposit methods
+// do not include confirmation counts
+func (k *KrakenDeposits) GetRequiredConfirmations(_ currency.Code) (int, error) {
+	return 0, ErrNotExposed
+}
+
+// GetDeposits returns deposits made since the time, failed deposits are
+// omitted
+func (k *KrakenDeposits) GetDeposits(since time.Time) ([]Deposit, error) {
+	assets := k.Assets
+	if len(assets) == 0 {
+		balances, err := k.Exchange.GetBalance()
+		if err != nil {
+			return nil, err
+		}
+		for asset := range balances {
+			if !strings.HasSuffix(asset, krakenStakedSuffix) {
+				assets = append(assets, asset)
+			}
+		}
+	}
+
+	var deposits []Deposit
+	for i := range assets {
+		resp, err := k.Exchange.GetDepositStatus(assets[i])
+		if err != nil {
+			return nil, err
+		}
+		for j := range resp {
+			d := &resp[j]
+			ts := time.Unix(int64(d.Time), 0)
+			if d.Status == krakenFailure || ts.Before(since) {
+				continue
+			}
+			txid := d.TxID
+			if txid == "" {
+				txid = d.ReferenceID
+			}
+			deposits = append(deposits, Deposit{
+				Exchange:  k.GetName(),
+				Currency:  kraken.AssetCode(d.Asset),
+				Amount:    d.Amount,
+				TxID:      txid,
+				Address:   d.Info,
+				Status:    d.Status,
+				Credited:  d.Status == krakenSuccess || d.Status == krakenSettled,
+				Timestamp: ts,
+			})
+		}
+	}
+	return deposits, nil
+}
@@ -0,0 +1,462 @@
+// Package drawdown provides a global circuit breaker which monitors account
+// equity over a rolling window, and when the drawdown from the window's peak
+// exceeds a configured percentage cancels all open orders, optionally flattens
+// positions and locks trading until it is manually reset
+package drawdown
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default breaker settings
+const (
+	DefaultWindow        = time.Hour * 24
+	DefaultCheckInterval = time.Minute
+)
+
+var (
+	// ErrTradingLocked is returned when an order is attempted while the
+	// circuit breaker is tripped
+	ErrTradingLocked = errors.New("trading locked by drawdown circuit breaker, manual reset required")
+
+	errInvalidDrawdown = errors.New("maximum drawdown must be between 0 and 100 percent")
+	errNoEquityFunc    = errors.New("no equity function supplied")
+	errNoExchanges     = errors.New("no exchanges supplied")
+	errNotTripped      = errors.New("circuit breaker is not tripped")
+	errNoPrice         = errors.New("no price available")
+)
+
+// EquityFunc returns the current total account equity
+type EquityFunc func() (float64, error)
+
+// Flattener is implemented by exchanges which can close all of their open
+// positions, exchanges which do not implement it are flattened by selling
+// their spot balances into the quote currency
+type Flattener interface {
+	FlattenPositions() error
+}
+
+// Config defines the circuit breaker settings
+type Config struct {
+	// MaxDrawdown is the percentage fall from the window's peak equity which
+	// trips the breaker
+	MaxDrawdown float64
+	Window      time.Duration
+	// Flatten closes positions on all venues once tripped
+	Flatten bool
+	// Quote is the currency spot balances are sold into when flattening
+	Quote currency.Code
+}
+
+// Validate checks the configuration and sets defaults
+func (c *Config) Validate() error {
+	if c.MaxDrawdown <= 0 || c.MaxDrawdown >= 100 {
+		return errInvalidDrawdown
+	}
+	if c.Window <= 0 {
+		c.Window = DefaultWindow
+	}
+	if c.Quote.IsEmpty() {
+		c.Quote = currency.USDT
+	}
+	return nil
+}
+
+// Status is a snapshot of the circuit breaker state
+type Status struct {
+	Tripped     bool      `json:"tripped"`
+	TrippedAt   time.Time `json:"trippedAt,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	Peak        float64   `json:"peak"`
+	Equity      float64   `json:"equity"`
+	Drawdown    float64   `json:"drawdown"`
+	MaxDrawdown float64   `json:"maxDrawdown"`
+	Errors      []string  `json:"errors,omitempty"`
+}
+
+type sample struct {
+	equity float64
+	time   time.Time
+}
+
+// Breaker is a drawdown circuit breaker across a set of exchanges
+type Breaker struct {
+	cfg       Config
+	equity    EquityFunc
+	exchanges []exchange.IBotExchange
+	onTrip    func(Status)
+	samples   []sample
+	status    Status
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+	mtx       sync.Mutex
+}
+
+// New returns a new circuit breaker, onTrip is optional and is called once
+// each time the breaker trips
+func New(cfg Config, equity EquityFunc, exchanges []exchange.IBotExchange, onTrip func(Status)) (*Breaker, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if equity == nil {
+		return nil, errNoEquityFunc
+	}
+	if len(exchanges) == 0 {
+		return nil, errNoExchanges
+	}
+	return &Breaker{
+		cfg:       cfg,
+		equity:    equity,
+		exchanges: exchanges,
+		onTrip:    onTrip,
+		status:    Status{MaxDrawdown: cfg.MaxDrawdown},
+	}, nil
+}
+
+// Record adds an equity sample and returns whether the drawdown from the
+// rolling window's peak exceeds the configured maximum
+func (b *Breaker) Record(equity float64, t time.Time) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.record(equity, t)
+}
+
+func (b *Breaker) record(equity float64, t time.Time) bool {
+	b.samples = append(b.samples, sample{equity: equity, time: t})
+	cutoff := t.Add(-b.cfg.Window)
+	var i int
+	for i < len(b.samples)-1 && b.samples[i].time.Before(cutoff) {
+		i++
+	}
+	b.samples = b.samples[i:]
+
+	var peak float64
+	for i := range b.samples {
+		if b.samples[i].equity > peak {
+			peak = b.samples[i].equity
+		}
+	}
+
+	b.status.Peak = peak
+	b.status.Equity = equity
+	b.status.Drawdown = 0
+	if peak > 0 {
+		b.status.Drawdown = (peak - equity) / peak * 100
+	}
+	return b.status.Drawdown >= b.cfg.MaxDrawdown
+}
+
+// Check samples equity and trips the breaker if the maximum drawdown has been
+// exceeded. Checks are skipped while the breaker is tripped
+func (b *Breaker) Check() error {
+	if b.IsTripped() {
+		return nil
+	}
+
+	equity, err := b.equity()
+	if err != nil {
+		return err
+	}
+
+	b.mtx.Lock()
+	breached := b.record(equity, time.Now())
+	status := b.status
+	b.mtx.Unlock()
+	if !breached {
+		return nil
+	}
+
+	b.Trip(fmt.Sprintf("drawdown %.2f%% from peak %f exceeds maximum %.2f%%",
+		status.Drawdown,
+		status.Peak,
+		b.cfg.MaxDrawdown))
+	return nil
+}
+
+// Trip locks trading, cancels all open orders and flattens positions when
+// configured. It can be called directly to halt trading manually
+func (b *Breaker) Trip(reason string) {
+	b.mtx.Lock()
+	if b.status.Tripped {
+		b.mtx.Unlock()
+		return
+	}
+	b.status.Tripped = true
+	b.status.TrippedAt = time.Now()
+	b.status.Reason = reason
+	b.status.Errors = nil
+	b.mtx.Unlock()
+
+	log.Warnf("Drawdown circuit breaker tripped: %s", reason)
+
+	var errs []string
+	for i := range b.exchanges {
+		if b.exchanges[i] == nil || !b.exchanges[i].IsEnabled() {
+			continue
+		}
+		_, err := b.exchanges[i].CancelAllOrders(&exchange.OrderCancellation{})
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s cancel all orders: %s",
+				b.exchanges[i].GetName(), err))
+		}
+		if !b.cfg.Flatten {
+			continue
+		}
+		err = Flatten(b.exchanges[i], b.cfg.Quote)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s flatten: %s",
+				b.exchanges[i].GetName(), err))
+		}
+	}
+	for i := range errs {
+		log.Errorf("Drawdown circuit breaker: %s", errs[i])
+	}
+
+	b.mtx.Lock()
+	b.status.Errors = errs
+	status := b.status
+	b.mtx.Unlock()
+
+	if b.onTrip != nil {
+		b.onTrip(status)
+	}
+}
+
+// Reset unlocks trading and clears the equity history so the new peak starts
+// from the next sample
+func (b *Breaker) Reset() error {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	if !b.status.Tripped {
+		return errNotTripped
+	}
+	b.samples = nil
+	b.status = Status{MaxDrawdown: b.cfg.MaxDrawdown}
+	log.Debugf("Drawdown circuit breaker reset, trading unlocked")
+	return nil
+}
+
+// IsTripped returns whether trading is locked
+func (b *Breaker) IsTripped() bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	return b.status.Tripped
+}
+
+// GetStatus returns the current breaker status
+func (b *Breaker) GetStatus() Status {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	s := b.status
+	s.Errors = append([]string(nil), b.status.Errors...)
+	return s
+}
+
+// Start starts the equity check routine
+func (b *Breaker) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+
+	b.mtx.Lock()
+	if b.shutdown != nil {
+		b.mtx.Unlock()
+		return
+	}
+	b.shutdown = make(chan struct{})
+	shutdown := b.shutdown
+	b.mtx.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			err := b.Check()
+			if err != nil {
+				log.Errorf("Drawdown circuit breaker check failed: %s", err)
+			}
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the equity check routine
+func (b *Breaker) Stop() {
+	b.mtx.Lock()
+	if b.shutdown == nil {
+		b.mtx.Unlock()
+		return
+	}
+	close(b.shutdown)
+	b.shutdown = nil
+	b.mtx.Unlock()
+	b.wg.Wait()
+}
+
+// Guard wraps an exchange so new and modified orders are rejected while the
+// breaker is tripped. Cancellations remain available
+func (b *Breaker) Guard(e exchange.IBotExchange) exchange.IBotExchange {
+	return &Guarded{IBotExchange: e, breaker: b}
+}
+
+// Guarded is an exchange whose order entry is locked by a circuit breaker
+type Guarded struct {
+	exchange.IBotExchange
+	breaker *Breaker
+}
+
+// Unwrap returns the underlying exchange
+func (g *Guarded) Unwrap() exchange.IBotExchange {
+	return g.IBotExchange
+}
+
+// SubmitOrder rejects orders while trading is locked
+func (g *Guarded) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	if g.breaker.IsTripped() {
+		return exchange.SubmitOrderResponse{}, ErrTradingLocked
+	}
+	return g.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+}
+
+// CheckOrder rejects orders placed natively while trading is locked
+func (g *Guarded) CheckOrder(_ *exchange.OrderSubmission) error {
+	if g.breaker.IsTripped() {
+		return ErrTradingLocked
+	}
+	return nil
+}
+
+// ModifyOrder rejects order amendments while trading is locked
+func (g *Guarded) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
+	if g.breaker.IsTripped() {
+		return "", ErrTradingLocked
+	}
+	return g.IBotExchange.ModifyOrder(action)
+}
+
+// Flatten closes all positions on an exchange, either through the exchange's
+// Flattener implementation or by selling every spot balance which trades
+// against the quote currency at market
+func Flatten(e exchange.IBotExchange, quote currency.Code) error {
+	if f, ok := e.(Flattener); ok {
+		return f.FlattenPositions()
+	}
+
+	acc, err := e.GetAccountInfo()
+	if err != nil {
+		return err
+	}
+
+	pairs := e.GetEnabledCurrencies()
+	var errs []string
+	for i := range acc.Accounts {
+		for j := range acc.Accounts[i].Currencies {
+			c := acc.Accounts[i].Currencies[j]
+			if c.TotalValue <= 0 || c.CurrencyName.Match(quote) {
+				continue
+			}
+			p, ok := findPair(pairs, c.CurrencyName, quote)
+			if !ok {
+				continue
+			}
+			_, err = e.SubmitOrder(p,
+				exchange.SellOrderSide,
+				exchange.MarketOrderType,
+				c.TotalValue,
+				0,
+				"")
+			if err != nil {
+				errs = append(errs, fmt.Sprintf("%s: %s", p, err))
+			}
+		}
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// AccountEquity returns an EquityFunc which values every balance across the
+// supplied exchanges in the quote currency using the latest stored tickers.
+// Stablecoins pegged to the quote currency are valued at par
+func AccountEquity(exchanges []exchange.IBotExchange, quote currency.Code) EquityFunc {
+	return func() (float64, error) {
+		var total float64
+		var valued bool
+		for i := range exchanges {
+			if exchanges[i] == nil || !exchanges[i].IsEnabled() {
+				continue
+			}
+			acc, err := exchanges[i].GetAccountInfo()
+			if err != nil {
+				return 0, fmt.Errorf("%s %v", exchanges[i].GetName(), err)
+			}
+			for j := range acc.Accounts {
+				for k := range acc.Accounts[j].Currencies {
+					c := acc.Accounts[j].Currencies[k]
+					if c.TotalValue == 0 {
+						continue
+					}
+					price, err := ValueInQuote(exchanges[i], c.CurrencyName, quote)
+					if err != nil {
+						log.Debugf("Drawdown circuit breaker skipping %s %s balance: %s",
+							exchanges[i].GetName(), c.CurrencyName, err)
+						continue
+					}
+					total += c.TotalValue * price
+					valued = true
+				}
+			}
+		}
+		if !valued {
+			return 0, errors.New("no balances could be valued")
+		}
+		return total, nil
+	}
+}
+
+// ValueInQuote returns the price of one unit of the currency in the quote
+// currency using the exchange's latest stored ticker. Stablecoins pegged to the
+// quote currency are valued at par
+func ValueInQuote(e exchange.IBotExchange, c, quote currency.Code) (float64, error) {
+	if c.Match(quote) || (c.IsStablecoin() && c.GetPeggedCurrency().Match(quote)) {
+		return 1, nil
+	}
+	p, ok := findPair(e.GetEnabledCurrencies(), c, quote)
+	if !ok {
+		return 0, errNoPrice
+	}
+	t, err := ticker.GetTicker(e.GetName(), p, ticker.Spot)
+	if err != nil {
+		return 0, err
+	}
+	if t.Last <= 0 {
+		return 0, errNoPrice
+	}
+	return t.Last, nil
+}
+
+func findPair(pairs currency.Pairs, base, quote currency.Code) (currency.Pair, bool) {
+	for i := range pairs {
+		if pairs[i].Base.Match(base) && pairs[i].Quote.Match(quote) {
+			return pairs[i], true
+		}
+	}
+	return currency.Pair{}, false
+}
@@ -0,0 +1,219 @@
+package drawdown
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	cancelled int
+	sold      []currency.Pair
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) IsEnabled() bool { return true }
+
+func (t *testExchange) GetEnabledCurrencies() currency.Pairs {
+	return currency.Pairs{
+		currency.NewPairWithDelimiter("BTC", "USDT", "-"),
+		currency.NewPairWithDelimiter("LTC", "BTC", "-"),
+	}
+}
+
+func (t *testExchange) GetAccountInfo() (exchange.AccountInfo, error) {
+	return exchange.AccountInfo{
+		Exchange: "test",
+		Accounts: []exchange.Account{{
+			Currencies: []exchange.AccountCurrencyInfo{
+				{CurrencyName: currency.BTC, TotalValue: 1},
+				{CurrencyName: currency.LTC, TotalValue: 10},
+				{CurrencyName: currency.USDT, TotalValue: 100},
+			},
+		}},
+	}, nil
+}
+
+func (t *testExchange) CancelAllOrders(_ *exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error) {
+	t.cancelled++
+	return exchange.CancelAllOrdersResponse{}, nil
+}
+
+func (t *testExchange) SubmitOrder(p currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.sold = append(t.sold, p)
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) ModifyOrder(_ *exchange.ModifyOrder) (string, error) {
+	return "1", nil
+}
+
+func TestNew(t *testing.T) {
+	e := []exchange.IBotExchange{&testExchange{}}
+	equity := func() (float64, error) { return 100, nil }
+
+	_, err := New(Config{MaxDrawdown: 0}, equity, e, nil)
+	if err != errInvalidDrawdown {
+		t.Errorf("Test Failed - New() expected %v, received %v", errInvalidDrawdown, err)
+	}
+	_, err = New(Config{MaxDrawdown: 10}, nil, e, nil)
+	if err != errNoEquityFunc {
+		t.Errorf("Test Failed - New() expected %v, received %v", errNoEquityFunc, err)
+	}
+	_, err = New(Config{MaxDrawdown: 10}, equity, nil, nil)
+	if err != errNoExchanges {
+		t.Errorf("Test Failed - New() expected %v, received %v", errNoExchanges, err)
+	}
+
+	b, err := New(Config{MaxDrawdown: 10}, equity, e, nil)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	if b.cfg.Window != DefaultWindow || !b.cfg.Quote.Match(currency.USDT) {
+		t.Error("Test Failed - New() defaults not set")
+	}
+}
+
+func TestRecord(t *testing.T) {
+	b, err := New(Config{MaxDrawdown: 10, Window: time.Hour},
+		func() (float64, error) { return 0, nil },
+		[]exchange.IBotExchange{&testExchange{}},
+		nil)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+
+	now := time.Now()
+	if b.Record(1000, now) {
+		t.Error("Test Failed - Record() tripped on first sample")
+	}
+	if b.Record(950, now.Add(time.Minute)) {
+		t.Error("Test Failed - Record() tripped below maximum drawdown")
+	}
+	if !b.Record(900, now.Add(time.Minute*2)) {
+		t.Error("Test Failed - Record() expected breach at 10% drawdown")
+	}
+
+	// The old peak falls out of the rolling window
+	if b.Record(890, now.Add(time.Hour*2)) {
+		t.Error("Test Failed - Record() expected expired peak to be ignored")
+	}
+	if s := b.GetStatus(); s.Peak != 890 {
+		t.Errorf("Test Failed - Record() expected peak 890, received %f", s.Peak)
+	}
+}
+
+func TestCheckTripAndReset(t *testing.T) {
+	e := &testExchange{}
+	equity := []float64{1000, 800}
+	var trips int
+	b, err := New(Config{MaxDrawdown: 10, Flatten: true},
+		func() (float64, error) {
+			v := equity[0]
+			if len(equity) > 1 {
+				equity = equity[1:]
+			}
+			return v, nil
+		},
+		[]exchange.IBotExchange{e},
+		func(Status) { trips++ })
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	guarded := b.Guard(e)
+
+	err = b.Check()
+	if err != nil {
+		t.Fatal("Test Failed - Check() error", err)
+	}
+	if b.IsTripped() {
+		t.Fatal("Test Failed - Check() tripped without drawdown")
+	}
+	_, err = guarded.SubmitOrder(currency.Pair{}, exchange.BuyOrderSide, exchange.MarketOrderType, 1, 0, "")
+	if err != nil {
+		t.Error("Test Failed - Guard() rejected order while untripped", err)
+	}
+	e.sold = nil
+
+	err = b.Check()
+	if err != nil {
+		t.Fatal("Test Failed - Check() error", err)
+	}
+	if !b.IsTripped() || trips != 1 {
+		t.Fatal("Test Failed - Check() expected breaker to trip once")
+	}
+	if e.cancelled != 1 {
+		t.Errorf("Test Failed - Check() expected orders to be cancelled once, received %d",
+			e.cancelled)
+	}
+	if len(e.sold) != 1 || e.sold[0].Base != currency.BTC {
+		t.Errorf("Test Failed - Check() expected BTC to be flattened, received %v", e.sold)
+	}
+
+	_, err = guarded.SubmitOrder(currency.Pair{}, exchange.BuyOrderSide, exchange.MarketOrderType, 1, 0, "")
+	if err != ErrTradingLocked {
+		t.Errorf("Test Failed - Guard() expected %v, received %v", ErrTradingLocked, err)
+	}
+	_, err = guarded.ModifyOrder(&exchange.ModifyOrder{})
+	if err != ErrTradingLocked {
+		t.Errorf("Test Failed - Guard() expected %v, received %v", ErrTradingLocked, err)
+	}
+
+	// Tripped breakers stay locked without further action
+	err = b.Check()
+	if err != nil || trips != 1 || e.cancelled != 1 {
+		t.Error("Test Failed - Check() expected no further action while tripped")
+	}
+
+	err = b.Reset()
+	if err != nil {
+		t.Fatal("Test Failed - Reset() error", err)
+	}
+	if b.IsTripped() {
+		t.Error("Test Failed - Reset() expected trading to be unlocked")
+	}
+	err = b.Reset()
+	if err != errNotTripped {
+		t.Errorf("Test Failed - Reset() expected %v, received %v", errNotTripped, err)
+	}
+}
+
+func TestCheckEquityError(t *testing.T) {
+	b, err := New(Config{MaxDrawdown: 10},
+		func() (float64, error) { return 0, errors.New("no equity") },
+		[]exchange.IBotExchange{&testExchange{}},
+		nil)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	if b.Check() == nil {
+		t.Error("Test Failed - Check() expected equity error")
+	}
+}
+
+func TestAccountEquity(t *testing.T) {
+	e := &testExchange{}
+	equity := AccountEquity([]exchange.IBotExchange{e}, currency.USDT)
+	v, err := equity()
+	if err != nil {
+		t.Fatal("Test Failed - AccountEquity() error", err)
+	}
+	// Without stored tickers only the USDT balance can be valued
+	if v != 100 {
+		t.Errorf("Test Failed - AccountEquity() expected 100, received %f", v)
+	}
+
+	equity = AccountEquity([]exchange.IBotExchange{e}, currency.USD)
+	v, err = equity()
+	if err != nil {
+		t.Fatal("Test Failed - AccountEquity() error", err)
+	}
+	if v != 100 {
+		t.Errorf("Test Failed - AccountEquity() expected USDT valued at par, received %f", v)
+	}
+}
@@ -0,0 +1,123 @@
+package exchange
+
+import (
+	"fmt"
+	"strconv"
+	"sync/atomic"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// DryRunIDPrefix prefixes the IDs of simulated orders and withdrawals
+const DryRunIDPrefix = "dryrun-"
+
+var dryRunID int64
+
+// NewDryRunID returns a unique ID for a simulated order or withdrawal
+func NewDryRunID() string {
+	return DryRunIDPrefix + strconv.FormatInt(atomic.AddInt64(&dryRunID, 1), 10)
+}
+
+// SetDryRun sets whether mutating requests are logged instead of sent
+func (e *Base) SetDryRun(enabled bool) {
+	e.DryRun = enabled
+}
+
+// IsDryRun returns true if mutating requests are logged instead of sent
+func (e *Base) IsDryRun() bool {
+	return e.DryRun
+}
+
+// DryRunRequest returns true when an authenticated request to an endpoint
+// which changes exchange state is made in dry run mode, logging it in place of
+// sending it. Exchanges classify their own endpoints as many send reads as
+// POST requests. Exchanges check it before signing, the result is left unset
+// as a simulated success
+func (e *Base) DryRunRequest(mutating bool, endpoint string, data interface{}) bool {
+	if !e.DryRun || !mutating {
+		return false
+	}
+	var payload []byte
+	if data != nil {
+		payload, _ = common.JSONEncode(data)
+	}
+	log.Infof("%s dry run, %s not sent: %s", e.Name, endpoint, payload)
+	return true
+}
+
+// DryRun wraps an exchange and logs all calls that would place, modify or
+// cancel orders or move funds, returning simulated successes instead of
+// sending them. All other calls are passed through to the underlying exchange
+// so a full configuration can be tested against production keys
+type DryRun struct {
+	IBotExchange
+}
+
+// NewDryRun returns a dry run wrapper around the supplied exchange
+func NewDryRun(e IBotExchange) *DryRun {
+	return &DryRun{IBotExchange: e}
+}
+
+// Unwrap returns the underlying exchange
+func (d *DryRun) Unwrap() IBotExchange {
+	return d.IBotExchange
+}
+
+func (d *DryRun) log(call, details string) {
+	log.Infof("%s dry run, %s not sent: %s", d.GetName(), call, details)
+}
+
+// SubmitOrder logs the order and returns it as placed with a simulated ID
+func (d *DryRun) SubmitOrder(p currency.Pair, side OrderSide, orderType OrderType, amount, price float64, clientID string) (SubmitOrderResponse, error) {
+	id := NewDryRunID()
+	d.log("SubmitOrder", fmt.Sprintf("%s %s %s %v @ %v client ID %q, simulated ID %s",
+		side, orderType, p, amount, price, clientID, id))
+	return SubmitOrderResponse{IsOrderPlaced: true, OrderID: id}, nil
+}
+
+// ModifyOrder logs the modification and returns the order's ID
+func (d *DryRun) ModifyOrder(action *ModifyOrder) (string, error) {
+	d.log("ModifyOrder", fmt.Sprintf("%s %s %v @ %v",
+		action.OrderID, action.CurrencyPair, action.Amount, action.Price))
+	return action.OrderID, nil
+}
+
+// CancelOrder logs the cancellation
+func (d *DryRun) CancelOrder(order *OrderCancellation) error {
+	d.log("CancelOrder", fmt.Sprintf("%s %s", order.OrderID, order.CurrencyPair))
+	return nil
+}
+
+// CancelAllOrders logs the cancellation and returns no failed cancellations
+func (d *DryRun) CancelAllOrders(order *OrderCancellation) (CancelAllOrdersResponse, error) {
+	d.log("CancelAllOrders", order.CurrencyPair.String())
+	return CancelAllOrdersResponse{OrderStatus: make(map[string]string)}, nil
+}
+
+// WithdrawCryptocurrencyFunds logs the withdrawal and returns a simulated ID
+func (d *DryRun) WithdrawCryptocurrencyFunds(w *WithdrawRequest) (string, error) {
+	return d.withdraw("WithdrawCryptocurrencyFunds", w), nil
+}
+
+// WithdrawFiatFunds logs the withdrawal and returns a simulated ID
+func (d *DryRun) WithdrawFiatFunds(w *WithdrawRequest) (string, error) {
+	return d.withdraw("WithdrawFiatFunds", w), nil
+}
+
+// WithdrawFiatFundsToInternationalBank logs the withdrawal and returns a
+// simulated ID
+func (d *DryRun) WithdrawFiatFundsToInternationalBank(w *WithdrawRequest) (string, error) {
+	return d.withdraw("WithdrawFiatFundsToInternationalBank", w), nil
+}
+
+func (d *DryRun) withdraw(call string, w *WithdrawRequest) string {
+	id := NewDryRunID()
+	dest := w.Address
+	if dest == "" {
+		dest = fmt.Sprintf("%s account %v", w.BankName, w.BankAccountNumber)
+	}
+	d.log(call, fmt.Sprintf("%v %s to %s, simulated ID %s", w.Amount, w.Currency, dest, id))
+	return id
+}
@@ -0,0 +1,269 @@
+// Package duplicates blocks near identical orders submitted within a short
+// window of each other, protecting against strategy bugs and retry storms
+// which would otherwise double the intended exposure. Orders which are meant
+// to be repeated are submitted with SubmitOrder and allowDuplicate set
+package duplicates
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// Blocked is the type of event emitted when a duplicate order is blocked
+const Blocked = "DUPLICATE_ORDER_BLOCKED"
+
+// tolerance is the relative difference under which prices and amounts are
+// considered identical, absorbing float noise from strategy calculations
+const tolerance = 1e-9
+
+var (
+	// ErrDuplicateOrder is returned when an order matches one submitted
+	// within the detection window
+	ErrDuplicateOrder = errors.New("duplicate order blocked")
+
+	errInvalidWindow = errors.New("duplicate order window must be positive")
+)
+
+// Order holds a submitted order
+type Order struct {
+	Exchange  string
+	Pair      currency.Pair
+	Side      exchange.OrderSide
+	OrderType exchange.OrderType
+	Amount    float64
+	Price     float64
+	ClientID  string
+	Submitted time.Time
+}
+
+// matches returns whether the orders are for the same pair, side, price and
+// amount on the same exchange
+func (o *Order) matches(other *Order) bool {
+	return strings.EqualFold(o.Exchange, other.Exchange) &&
+		o.Pair.Base.Match(other.Pair.Base) &&
+		o.Pair.Quote.Match(other.Pair.Quote) &&
+		strings.EqualFold(string(o.Side), string(other.Side)) &&
+		equal(o.Amount, other.Amount) &&
+		equal(o.Price, other.Price)
+}
+
+func equal(a, b float64) bool {
+	return math.Abs(a-b) <= tolerance*math.Max(math.Abs(a), math.Abs(b))
+}
+
+// Event is emitted when a duplicate order is blocked
+type Event struct {
+	Type string
+	// Order is the blocked order and Previous the order it repeats
+	Order    Order
+	Previous Order
+}
+
+func (e *Event) String() string {
+	return fmt.Sprintf("%s %s %s %v @ %v blocked, repeats order submitted %s ago",
+		e.Order.Exchange, e.Order.Pair, e.Order.Side, e.Order.Amount, e.Order.Price,
+		e.Order.Submitted.Sub(e.Previous.Submitted).Round(time.Millisecond))
+}
+
+// allowance identifies orders submitted with allowDuplicate set. Amounts and
+// prices are left out as guards wrapping the detector may round them
+type allowance struct {
+	exchange  string
+	pair      string
+	side      exchange.OrderSide
+	orderType exchange.OrderType
+	clientID  string
+}
+
+func newAllowance(o *Order) allowance {
+	return allowance{
+		exchange:  strings.ToLower(o.Exchange),
+		pair:      o.Pair.String(),
+		side:      o.Side,
+		orderType: o.OrderType,
+		clientID:  o.ClientID,
+	}
+}
+
+// Detector remembers orders submitted through guarded exchanges for the
+// length of its window
+type Detector struct {
+	window  time.Duration
+	orders  []Order
+	blocked int64
+	// allowed counts the orders being submitted with allowDuplicate set
+	allowed map[allowance]int
+	onEvent func(Event)
+	mtx     sync.Mutex
+}
+
+// New returns a detector blocking orders which repeat an order submitted
+// within the window
+func New(window time.Duration, onEvent func(Event)) (*Detector, error) {
+	if window <= 0 {
+		return nil, errInvalidWindow
+	}
+	return &Detector{
+		window:  window,
+		allowed: make(map[allowance]int),
+		onEvent: onEvent,
+	}, nil
+}
+
+// allow lets the order through the detector's guards until the returned
+// function is called
+func (d *Detector) allow(o *Order) func() {
+	a := newAllowance(o)
+	d.mtx.Lock()
+	d.allowed[a]++
+	d.mtx.Unlock()
+	return func() {
+		d.mtx.Lock()
+		if d.allowed[a]--; d.allowed[a] <= 0 {
+			delete(d.allowed, a)
+		}
+		d.mtx.Unlock()
+	}
+}
+
+// isAllowed returns whether the order is being submitted with allowDuplicate
+// set
+func (d *Detector) isAllowed(o *Order) bool {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.allowed[newAllowance(o)] > 0
+}
+
+// Check records the order, returning the order it repeats when one was
+// submitted within the window. Every order is recorded whether or not it is
+// later accepted, as a submission which timed out may still have been placed
+func (d *Detector) Check(o Order) (Order, bool) {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+
+	cutoff := o.Submitted.Add(-d.window)
+	recent := d.orders[:0]
+	for i := range d.orders {
+		if d.orders[i].Submitted.After(cutoff) {
+			recent = append(recent, d.orders[i])
+		}
+	}
+	d.orders = recent
+
+	for i := len(d.orders) - 1; i >= 0; i-- {
+		if d.orders[i].matches(&o) {
+			d.blocked++
+			return d.orders[i], true
+		}
+	}
+	d.orders = append(d.orders, o)
+	return Order{}, false
+}
+
+// GetBlocked returns the number of orders blocked
+func (d *Detector) GetBlocked() int64 {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	return d.blocked
+}
+
+// Guard wraps an exchange so orders repeating one submitted within the
+// window are blocked
+func (d *Detector) Guard(e exchange.IBotExchange) exchange.IBotExchange {
+	return &Guarded{IBotExchange: e, detector: d}
+}
+
+// Guarded is an exchange whose duplicate orders are blocked
+type Guarded struct {
+	exchange.IBotExchange
+	detector *Detector
+}
+
+// Unwrap returns the underlying exchange
+func (g *Guarded) Unwrap() exchange.IBotExchange {
+	return g.IBotExchange
+}
+
+// SubmitOrder rejects orders repeating an order submitted within the window,
+// unless submitted with allowDuplicate set
+func (g *Guarded) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	err := g.check(Order{
+		Exchange:  g.GetName(),
+		Pair:      p,
+		Side:      side,
+		OrderType: orderType,
+		Amount:    amount,
+		Price:     price,
+		ClientID:  clientID,
+		Submitted: time.Now(),
+	})
+	if err != nil {
+		return exchange.SubmitOrderResponse{}, err
+	}
+	return g.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+}
+
+// CheckOrder rejects orders placed natively which repeat an order submitted
+// within the window, unless the order allows duplicates
+func (g *Guarded) CheckOrder(o *exchange.OrderSubmission) error {
+	if o.AllowDuplicate {
+		return nil
+	}
+	return g.check(Order{
+		Exchange:  g.GetName(),
+		Pair:      o.Pair,
+		Side:      o.Side,
+		OrderType: o.OrderType,
+		Amount:    o.Amount,
+		Price:     o.Price,
+		ClientID:  o.ClientID,
+		Submitted: time.Now(),
+	})
+}
+
+func (g *Guarded) check(o Order) error {
+	if g.detector.isAllowed(&o) {
+		return nil
+	}
+	prev, ok := g.detector.Check(o)
+	if !ok {
+		return nil
+	}
+	if g.detector.onEvent != nil {
+		g.detector.onEvent(Event{Type: Blocked, Order: o, Previous: prev})
+	}
+	return fmt.Errorf("%s %s %v", g.GetName(), o.Pair, ErrDuplicateOrder)
+}
+
+// SubmitOrder submits the order through the exchange. Orders submitted with
+// allowDuplicate set pass the duplicate order guards wrapping the exchange,
+// for orders which are meant to repeat such as grid levels and probes
+func SubmitOrder(e exchange.IBotExchange, p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string, allowDuplicate bool) (exchange.SubmitOrderResponse, error) {
+	if allowDuplicate {
+		o := Order{
+			Exchange:  e.GetName(),
+			Pair:      p,
+			Side:      side,
+			OrderType: orderType,
+			ClientID:  clientID,
+		}
+		for w := e; w != nil; {
+			if g, ok := w.(*Guarded); ok {
+				defer g.detector.allow(&o)()
+			}
+			u, ok := w.(exchange.Wrapper)
+			if !ok {
+				break
+			}
+			w = u.Unwrap()
+		}
+	}
+	return e.SubmitOrder(p, side, orderType, amount, price, clientID)
+}
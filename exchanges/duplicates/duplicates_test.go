@@ -0,0 +1,129 @@
+package duplicates
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	name      string
+	submitted int
+}
+
+func (t *testExchange) GetName() string { return t.name }
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.submitted++
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+// testWrapper rounds amounts before passing orders to the wrapped exchange
+type testWrapper struct {
+	exchange.IBotExchange
+}
+
+func (t *testWrapper) Unwrap() exchange.IBotExchange { return t.IBotExchange }
+
+func (t *testWrapper) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	return t.IBotExchange.SubmitOrder(p, side, orderType, math.Floor(amount*100)/100, price, clientID)
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(0, nil); err != errInvalidWindow {
+		t.Error("Test Failed - New() expected invalid window error", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	d, err := New(time.Second, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	o := Order{
+		Exchange:  "Test",
+		Pair:      currency.NewPair(currency.BTC, currency.USD),
+		Side:      exchange.BuyOrderSide,
+		Amount:    0.3,
+		Price:     100,
+		Submitted: now,
+	}
+	if _, ok := d.Check(o); ok {
+		t.Fatal("Test Failed - Check() first order reported as duplicate")
+	}
+
+	dup := o
+	dup.Amount = 0.1 + 0.2
+	dup.Pair = currency.NewPairWithDelimiter("btc", "usd", "-")
+	dup.Submitted = now.Add(time.Millisecond * 500)
+	if _, ok := d.Check(dup); !ok {
+		t.Error("Test Failed - Check() near identical order not detected")
+	}
+
+	different := []Order{o, o, o, o}
+	different[0].Side = exchange.SellOrderSide
+	different[1].Price = 100.01
+	different[2].Amount = 0.31
+	different[3].Exchange = "Other"
+	for i := range different {
+		different[i].Submitted = now.Add(time.Millisecond * 600)
+		if _, ok := d.Check(different[i]); ok {
+			t.Errorf("Test Failed - Check() different order %+v reported as duplicate", different[i])
+		}
+	}
+
+	expired := o
+	expired.Submitted = now.Add(time.Second * 2)
+	if _, ok := d.Check(expired); ok {
+		t.Error("Test Failed - Check() order outside the window reported as duplicate")
+	}
+	if d.GetBlocked() != 1 {
+		t.Errorf("Test Failed - GetBlocked() expected 1, got %d", d.GetBlocked())
+	}
+}
+
+func TestGuard(t *testing.T) {
+	var events []Event
+	d, err := New(time.Minute, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := &testExchange{name: "test"}
+	g := d.Guard(e)
+	p := currency.NewPair(currency.BTC, currency.USD)
+
+	_, err = g.SubmitOrder(p, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = g.SubmitOrder(p, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 100, "retry")
+	if err == nil || e.submitted != 1 {
+		t.Fatal("Test Failed - SubmitOrder() duplicate order was submitted")
+	}
+	if len(events) != 1 || events[0].Type != Blocked || events[0].Previous.Amount != 1 {
+		t.Errorf("Test Failed - SubmitOrder() unexpected events %+v", events)
+	}
+
+	_, err = SubmitOrder(g, p, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 100, "", true)
+	if err != nil || e.submitted != 2 {
+		t.Error("Test Failed - SubmitOrder() allowed duplicate order was blocked", err)
+	}
+	if _, err = SubmitOrder(g, p, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 100, "", false); err == nil {
+		t.Error("Test Failed - SubmitOrder() duplicate order submitted without the allow flag")
+	}
+
+	// The flag reaches guards beneath other wrappers, which may round the order
+	w := &testWrapper{IBotExchange: g}
+	_, err = SubmitOrder(w, p, exchange.BuyOrderSide, exchange.LimitOrderType, 1.004, 100, "", true)
+	if err != nil || e.submitted != 3 {
+		t.Error("Test Failed - SubmitOrder() allowed duplicate order was blocked beneath a wrapper", err)
+	}
+	if len(g.(*Guarded).detector.allowed) != 0 {
+		t.Error("Test Failed - SubmitOrder() allowance not released")
+	}
+}
@@ -0,0 +1,324 @@
+// Package dust identifies balances that are below an exchange's minimum order
+// notional and cleans them up, either through an exchange dust conversion
+// endpoint or by tracking them until their value meets a threshold at which
+// they can be sold
+package dust
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// const values for the dust package
+const (
+	// DefaultMinNotional is used when an exchange does not publish a minimum
+	// order notional for a pair
+	DefaultMinNotional = 10
+	// DefaultCleanupMultiplier is the multiple of the minimum notional the
+	// value of a tracked dust balance must reach before it is sold
+	DefaultCleanupMultiplier = 1.05
+	// DefaultCleanupInterval is the delay between dust cleanups
+	DefaultCleanupInterval = time.Hour * 24
+)
+
+// ErrNothingToClean is returned by Cleanup when an exchange holds no dust
+var ErrNothingToClean = errors.New("dust nothing to clean up")
+
+var (
+	errNilExchange     = errors.New("dust exchange is nil")
+	errQuoteNotSet     = errors.New("dust quote currency not set")
+	errNoPriceForDust  = errors.New("dust no price available")
+	errInvalidMultiple = errors.New("dust cleanup multiplier must be at least 1")
+)
+
+// Converter is implemented by exchanges which support converting small
+// balances into another asset through a dedicated endpoint
+type Converter interface {
+	ConvertDust(codes []currency.Code) error
+}
+
+// MinNotionaler is implemented by exchanges which publish a minimum order
+// notional per pair
+type MinNotionaler interface {
+	GetMinNotional(p currency.Pair, assetType string) (float64, error)
+}
+
+// Holding is a balance identified as dust. Pending holdings keep the amount
+// identified as dust, which is all that is ever sold
+type Holding struct {
+	Exchange    string
+	Currency    currency.Code
+	Pair        currency.Pair
+	Amount      float64
+	Price       float64
+	Notional    float64
+	MinNotional float64
+}
+
+// Result details the outcome of a cleanup
+type Result struct {
+	Exchange  string
+	Converted []currency.Code
+	Sold      []Holding
+	Pending   []Holding
+}
+
+// Manager tracks dust balances per exchange and performs cleanups
+type Manager struct {
+	Quote              currency.Code
+	AssetType          string
+	CleanupMultiplier  float64
+	DefaultMinNotional float64
+
+	pending map[string]map[currency.Code]Holding
+	mtx     sync.Mutex
+}
+
+// New returns a new dust manager which values dust in the supplied quote
+// currency
+func New(quote currency.Code, cleanupMultiplier float64) (*Manager, error) {
+	if quote.String() == "" {
+		return nil, errQuoteNotSet
+	}
+
+	if cleanupMultiplier == 0 {
+		cleanupMultiplier = DefaultCleanupMultiplier
+	}
+
+	if cleanupMultiplier < 1 {
+		return nil, errInvalidMultiple
+	}
+
+	return &Manager{
+		Quote:              quote,
+		AssetType:          ticker.Spot,
+		CleanupMultiplier:  cleanupMultiplier,
+		DefaultMinNotional: DefaultMinNotional,
+		pending:            make(map[string]map[currency.Code]Holding),
+	}, nil
+}
+
+// Identify returns all balances on an exchange whose value in the quote
+// currency is below the exchange minimum order notional
+func (m *Manager) Identify(e exchange.IBotExchange) ([]Holding, error) {
+	if e == nil {
+		return nil, errNilExchange
+	}
+
+	acc, err := e.GetAccountInfo()
+	if err != nil {
+		return nil, err
+	}
+
+	return filterDust(m.value(e, &acc, m.lastPrice)), nil
+}
+
+func filterDust(holdings []Holding) []Holding {
+	var dust []Holding
+	for i := range holdings {
+		if holdings[i].IsDust() {
+			dust = append(dust, holdings[i])
+		}
+	}
+	return dust
+}
+
+// IsDust returns whether the holding is below its minimum order notional
+func (h *Holding) IsDust() bool {
+	return h.Notional < h.MinNotional
+}
+
+func (m *Manager) lastPrice(exch string, p currency.Pair) (float64, error) {
+	t, err := ticker.GetTicker(exch, p, m.AssetType)
+	if err != nil {
+		return 0, err
+	}
+
+	if t.Last == 0 {
+		return 0, errNoPriceForDust
+	}
+	return t.Last, nil
+}
+
+func (m *Manager) minNotional(e exchange.IBotExchange, p currency.Pair) float64 {
+	if mn, ok := exchange.Underlying(e).(MinNotionaler); ok {
+		v, err := mn.GetMinNotional(p, m.AssetType)
+		if err == nil && v > 0 {
+			return v
+		}
+	}
+	return m.DefaultMinNotional
+}
+
+// value prices every free balance on an exchange in the quote currency
+func (m *Manager) value(e exchange.IBotExchange, acc *exchange.AccountInfo, price func(string, currency.Pair) (float64, error)) []Holding {
+	balances := make(map[currency.Code]float64)
+	for i := range acc.Accounts {
+		for j := range acc.Accounts[i].Currencies {
+			c := acc.Accounts[i].Currencies[j]
+			balances[c.CurrencyName] += c.TotalValue - c.Hold
+		}
+	}
+
+	name := e.GetName()
+	var holdings []Holding
+	for code, amount := range balances {
+		if amount <= 0 || code.Upper() == m.Quote.Upper() {
+			continue
+		}
+
+		p := currency.NewPair(code, m.Quote)
+		last, err := price(name, p)
+		if err != nil {
+			log.Debugf("Dust: %s unable to value %s balance. Error: %s",
+				name, code, err)
+			continue
+		}
+
+		holdings = append(holdings, Holding{
+			Exchange:    name,
+			Currency:    code,
+			Pair:        p,
+			Amount:      amount,
+			Price:       last,
+			Notional:    amount * last,
+			MinNotional: m.minNotional(e, p),
+		})
+	}
+	return holdings
+}
+
+// Cleanup identifies dust on the exchange and either converts it through the
+// exchange dust endpoint or, when unsupported, keeps tracking it. A tracked
+// balance whose value has risen past the minimum notional multiplied by the
+// cleanup multiplier without the balance growing is sold in a market order.
+// Balances which have grown, such as through a buy, are no longer dust and
+// stop being tracked rather than being sold
+func (m *Manager) Cleanup(e exchange.IBotExchange) (Result, error) {
+	if e == nil {
+		return Result{}, errNilExchange
+	}
+
+	acc, err := e.GetAccountInfo()
+	if err != nil {
+		return Result{}, err
+	}
+	return m.cleanup(e, m.value(e, &acc, m.lastPrice))
+}
+
+func (m *Manager) cleanup(e exchange.IBotExchange, holdings []Holding) (Result, error) {
+	result := Result{Exchange: e.GetName()}
+	dust := filterDust(holdings)
+
+	if c, ok := exchange.Underlying(e).(Converter); ok {
+		if len(dust) == 0 {
+			return result, ErrNothingToClean
+		}
+
+		codes := make([]currency.Code, len(dust))
+		for i := range dust {
+			codes[i] = dust[i].Currency
+		}
+
+		err := c.ConvertDust(codes)
+		if err != nil {
+			return result, err
+		}
+		result.Converted = codes
+		m.clearPending(result.Exchange)
+		return result, nil
+	}
+
+	m.mtx.Lock()
+	pending, ok := m.pending[result.Exchange]
+	if !ok {
+		pending = make(map[currency.Code]Holding)
+		m.pending[result.Exchange] = pending
+	}
+
+	held := make(map[currency.Code]bool)
+	var ready []Holding
+	for i := range holdings {
+		h := holdings[i]
+		held[h.Currency] = true
+		tracked, ok := pending[h.Currency]
+		if h.IsDust() {
+			if !ok || h.Amount != tracked.Amount {
+				pending[h.Currency] = h
+			}
+			result.Pending = append(result.Pending, h)
+			continue
+		}
+
+		if !ok {
+			continue
+		}
+
+		if h.Amount > tracked.Amount {
+			// The balance grew into a position, it is no longer dust
+			delete(pending, h.Currency)
+			continue
+		}
+
+		if h.Notional >= h.MinNotional*m.CleanupMultiplier {
+			ready = append(ready, h)
+			delete(pending, h.Currency)
+			continue
+		}
+		result.Pending = append(result.Pending, tracked)
+	}
+	for code := range pending {
+		if !held[code] {
+			delete(pending, code)
+		}
+	}
+	m.mtx.Unlock()
+
+	if len(ready) == 0 && len(result.Pending) == 0 {
+		return result, ErrNothingToClean
+	}
+
+	var errs []error
+	for i := range ready {
+		_, err := e.SubmitOrder(ready[i].Pair,
+			exchange.SellOrderSide,
+			exchange.MarketOrderType,
+			ready[i].Amount,
+			0,
+			"dust")
+		if err != nil {
+			errs = append(errs, fmt.Errorf("%s: %s", ready[i].Currency, err))
+			continue
+		}
+		result.Sold = append(result.Sold, ready[i])
+	}
+
+	if len(errs) > 0 {
+		return result, fmt.Errorf("dust cleanup failed for %v", errs)
+	}
+	return result, nil
+}
+
+// GetPending returns the dust holdings being tracked for an exchange
+func (m *Manager) GetPending(exch string) []Holding {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	var h []Holding
+	for _, v := range m.pending[exch] {
+		h = append(h, v)
+	}
+	return h
+}
+
+func (m *Manager) clearPending(exch string) {
+	m.mtx.Lock()
+	delete(m.pending, exch)
+	m.mtx.Unlock()
+}
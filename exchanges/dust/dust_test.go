@@ -0,0 +1,197 @@
+package dust
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	orders []currency.Pair
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) SubmitOrder(p currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.orders = append(t.orders, p)
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+type testConverter struct {
+	testExchange
+	converted []currency.Code
+}
+
+func (t *testConverter) ConvertDust(codes []currency.Code) error {
+	t.converted = codes
+	return nil
+}
+
+// guarded wraps an exchange as order guards do
+type guarded struct {
+	exchange.IBotExchange
+}
+
+func (g *guarded) Unwrap() exchange.IBotExchange { return g.IBotExchange }
+
+var prices = map[string]float64{
+	"BTC": 5000,
+	"LTC": 50,
+	"XRP": 0.3,
+}
+
+func testPrice(_ string, p currency.Pair) (float64, error) {
+	v, ok := prices[p.Base.Upper().String()]
+	if !ok {
+		return 0, errors.New("no price")
+	}
+	return v, nil
+}
+
+func testAccount(btc, ltc, xrp float64) *exchange.AccountInfo {
+	return &exchange.AccountInfo{
+		Exchange: "test",
+		Accounts: []exchange.Account{{
+			Currencies: []exchange.AccountCurrencyInfo{
+				{CurrencyName: currency.BTC, TotalValue: btc},
+				{CurrencyName: currency.LTC, TotalValue: ltc},
+				{CurrencyName: currency.XRP, TotalValue: xrp},
+				{CurrencyName: currency.USDT, TotalValue: 100},
+				{CurrencyName: currency.DOGE, TotalValue: 1000},
+			},
+		}},
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(currency.Code{}, 0)
+	if err != errQuoteNotSet {
+		t.Errorf("Test Failed - New() expected %v but received %v", errQuoteNotSet, err)
+	}
+
+	_, err = New(currency.USDT, 0.5)
+	if err != errInvalidMultiple {
+		t.Errorf("Test Failed - New() expected %v but received %v", errInvalidMultiple, err)
+	}
+
+	m, err := New(currency.USDT, 0)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+
+	if m.CleanupMultiplier != DefaultCleanupMultiplier {
+		t.Error("Test Failed - New() default cleanup multiplier not set")
+	}
+}
+
+func TestIdentify(t *testing.T) {
+	m, err := New(currency.USDT, 0)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+
+	_, err = m.Identify(nil)
+	if err != errNilExchange {
+		t.Errorf("Test Failed - Identify() expected %v but received %v", errNilExchange, err)
+	}
+
+	e := &testExchange{}
+	dust := filterDust(m.value(e, testAccount(1, 0.1, 10), testPrice))
+	if len(dust) != 2 {
+		t.Fatalf("Test Failed - Identify() expected 2 dust balances, received %v", dust)
+	}
+
+	for i := range dust {
+		if dust[i].Currency == currency.BTC {
+			t.Error("Test Failed - Identify() BTC balance is not dust")
+		}
+		if dust[i].MinNotional != DefaultMinNotional {
+			t.Error("Test Failed - Identify() expected default min notional")
+		}
+	}
+}
+
+func TestCleanupAggregates(t *testing.T) {
+	m, err := New(currency.USDT, 0)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+
+	e := &testExchange{}
+	r, err := m.cleanup(e, m.value(e, testAccount(1, 0.1, 10), testPrice))
+	if err != nil {
+		t.Fatal("Test Failed - Cleanup() error", err)
+	}
+
+	if len(r.Pending) != 2 || len(r.Sold) != 0 {
+		t.Fatalf("Test Failed - Cleanup() expected 2 pending holdings, received %+v", r)
+	}
+
+	// A buy grows LTC into a position which is not sold
+	r, err = m.cleanup(e, m.value(e, testAccount(1, 0.5, 10), testPrice))
+	if err != nil {
+		t.Fatal("Test Failed - Cleanup() error", err)
+	}
+
+	if len(r.Sold) != 0 || len(e.orders) != 0 {
+		t.Errorf("Test Failed - Cleanup() expected no LTC order, received %+v %v", r, e.orders)
+	}
+
+	if p := m.GetPending("test"); len(p) != 1 || p[0].Currency != currency.XRP {
+		t.Errorf("Test Failed - GetPending() expected XRP pending, received %v", p)
+	}
+
+	// XRP rising past the threshold sells the dust amount
+	risen := func(exch string, p currency.Pair) (float64, error) {
+		if p.Base == currency.XRP {
+			return 1.1, nil
+		}
+		return testPrice(exch, p)
+	}
+	r, err = m.cleanup(e, m.value(e, testAccount(1, 0.5, 10), risen))
+	if err != nil {
+		t.Fatal("Test Failed - Cleanup() error", err)
+	}
+
+	if len(r.Sold) != 1 || r.Sold[0].Currency != currency.XRP || r.Sold[0].Amount != 10 {
+		t.Errorf("Test Failed - Cleanup() expected XRP to be sold, received %+v", r)
+	}
+
+	if len(e.orders) != 1 || e.orders[0].Base != currency.XRP {
+		t.Errorf("Test Failed - Cleanup() expected a single XRP order, received %v", e.orders)
+	}
+
+	if p := m.GetPending("test"); len(p) != 0 {
+		t.Errorf("Test Failed - GetPending() expected nothing pending, received %v", p)
+	}
+}
+
+func TestCleanupConverter(t *testing.T) {
+	m, err := New(currency.USDT, 0)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+
+	e := &testConverter{}
+	g := &guarded{IBotExchange: e}
+	_, err = m.cleanup(g, m.value(g, testAccount(1, 1, 100), testPrice))
+	if err != ErrNothingToClean {
+		t.Errorf("Test Failed - Cleanup() expected %v but received %v", ErrNothingToClean, err)
+	}
+
+	r, err := m.cleanup(g, m.value(g, testAccount(1, 0.1, 10), testPrice))
+	if err != nil {
+		t.Fatal("Test Failed - Cleanup() error", err)
+	}
+
+	if len(r.Converted) != 2 || len(e.converted) != 2 {
+		t.Errorf("Test Failed - Cleanup() expected 2 converted currencies, received %v", r.Converted)
+	}
+
+	if len(e.orders) != 0 {
+		t.Error("Test Failed - Cleanup() should not place orders when converting")
+	}
+}
@@ -0,0 +1,197 @@
+// Package endpoints registers the REST paths of an exchange's API versions by
+// logical operation. Exchange methods resolve their operation against the
+// active version instead of hardcoding paths, so supporting a new API version
+// means registering its paths and adding parsers for its responses
+package endpoints
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Operation names a logical API operation independent of its path
+type Operation string
+
+// Operations shared across exchanges
+const (
+	Ticker          Operation = "ticker"
+	Tickers         Operation = "tickers"
+	Orderbook       Operation = "orderbook"
+	Trades          Operation = "trades"
+	Candles         Operation = "candles"
+	Instruments     Operation = "instruments"
+	Accounts        Operation = "accounts"
+	Account         Operation = "account"
+	PlaceOrder      Operation = "placeOrder"
+	AmendOrder      Operation = "amendOrder"
+	CancelOrder     Operation = "cancelOrder"
+	CancelAllOrders Operation = "cancelAllOrders"
+	Order           Operation = "order"
+	Orders          Operation = "orders"
+	OpenOrders      Operation = "openOrders"
+	Fills           Operation = "fills"
+	Positions       Operation = "positions"
+	DepositAddress  Operation = "depositAddress"
+	Withdraw        Operation = "withdraw"
+)
+
+var (
+	// ErrVersionNotFound is returned when selecting an unregistered version
+	ErrVersionNotFound = errors.New("API version not found")
+	// ErrOperationUnsupported is returned when the active version has no
+	// path registered for an operation
+	ErrOperationUnsupported = errors.New("operation unsupported by API version")
+
+	errNoVersions       = errors.New("no API versions supplied")
+	errDuplicateVersion = errors.New("API version already registered")
+	errMissingVariable  = errors.New("missing path variable")
+)
+
+// Endpoint holds the path of an operation. Path variables are enclosed in
+// braces, e.g. instruments/{instrument}/ticker
+type Endpoint struct {
+	Method        string
+	Path          string
+	Authenticated bool
+}
+
+// Version holds the endpoints of an API version
+type Version struct {
+	Name string
+	// SignPrefix is prepended to a path when signing it, exchanges sign the
+	// full request path while paths are registered relative to the API URL
+	SignPrefix string
+	Endpoints  map[Operation]Endpoint
+}
+
+// Route holds an operation resolved against the active version
+type Route struct {
+	Version       string
+	Method        string
+	Path          string
+	SignPath      string
+	Authenticated bool
+}
+
+// Registry holds an exchange's API versions and the version in use
+type Registry struct {
+	versions map[string]*Version
+	active   string
+	mtx      sync.RWMutex
+}
+
+// New returns a registry of the versions with the first version active
+func New(versions ...Version) (*Registry, error) {
+	if len(versions) == 0 {
+		return nil, errNoVersions
+	}
+	r := &Registry{versions: make(map[string]*Version)}
+	for i := range versions {
+		err := r.Register(versions[i])
+		if err != nil {
+			return nil, err
+		}
+	}
+	r.active = versions[0].Name
+	return r, nil
+}
+
+// Register adds a version to the registry without making it active
+func (r *Registry) Register(v Version) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.versions[v.Name]; ok {
+		return fmt.Errorf("%s %v", v.Name, errDuplicateVersion)
+	}
+	r.versions[v.Name] = &v
+	return nil
+}
+
+// Use makes the version active
+func (r *Registry) Use(version string) error {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if _, ok := r.versions[version]; !ok {
+		return fmt.Errorf("%s %v", version, ErrVersionNotFound)
+	}
+	r.active = version
+	return nil
+}
+
+// Active returns the name of the active version
+func (r *Registry) Active() string {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	return r.active
+}
+
+// Versions returns the names of the registered versions
+func (r *Registry) Versions() []string {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	resp := make([]string, 0, len(r.versions))
+	for name := range r.versions {
+		resp = append(resp, name)
+	}
+	sort.Strings(resp)
+	return resp
+}
+
+// Supports returns true if the active version has a path for the operation
+func (r *Registry) Supports(op Operation) bool {
+	r.mtx.RLock()
+	defer r.mtx.RUnlock()
+	_, ok := r.versions[r.active].Endpoints[op]
+	return ok
+}
+
+// Resolve returns the operation's route in the active version with its path
+// variables substituted
+func (r *Registry) Resolve(op Operation, vars map[string]string) (Route, error) {
+	r.mtx.RLock()
+	v := r.versions[r.active]
+	r.mtx.RUnlock()
+	e, ok := v.Endpoints[op]
+	if !ok {
+		return Route{}, fmt.Errorf("%s %v %s", op, ErrOperationUnsupported, v.Name)
+	}
+	path, err := expand(e.Path, vars)
+	if err != nil {
+		return Route{}, fmt.Errorf("%s %v", op, err)
+	}
+	return Route{
+		Version:       v.Name,
+		Method:        e.Method,
+		Path:          path,
+		SignPath:      v.SignPrefix + path,
+		Authenticated: e.Authenticated,
+	}, nil
+}
+
+// expand substitutes the path variables
+func expand(path string, vars map[string]string) (string, error) {
+	var b strings.Builder
+	for {
+		start := strings.IndexByte(path, '{')
+		if start < 0 {
+			b.WriteString(path)
+			return b.String(), nil
+		}
+		end := strings.IndexByte(path[start:], '}')
+		if end < 0 {
+			b.WriteString(path)
+			return b.String(), nil
+		}
+		name := path[start+1 : start+end]
+		value, ok := vars[name]
+		if !ok || value == "" {
+			return "", fmt.Errorf("%v %s", errMissingVariable, name)
+		}
+		b.WriteString(path[:start])
+		b.WriteString(value)
+		path = path[start+end+1:]
+	}
+}
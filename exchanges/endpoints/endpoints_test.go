@@ -0,0 +1,80 @@
+package endpoints
+
+import (
+	"net/http"
+	"strings"
+	"testing"
+)
+
+var (
+	v3 = Version{
+		Name:       "v3",
+		SignPrefix: "/api/",
+		Endpoints: map[Operation]Endpoint{
+			Ticker:     {Method: http.MethodGet, Path: "spot/v3/instruments/{instrument}/ticker"},
+			PlaceOrder: {Method: http.MethodPost, Path: "spot/v3/orders", Authenticated: true},
+		},
+	}
+	v5 = Version{
+		Name:       "v5",
+		SignPrefix: "/api/",
+		Endpoints: map[Operation]Endpoint{
+			Ticker: {Method: http.MethodGet, Path: "v5/market/ticker"},
+		},
+	}
+)
+
+func TestNew(t *testing.T) {
+	if _, err := New(); err != errNoVersions {
+		t.Errorf("Test Failed - New() expected errNoVersions, received %v", err)
+	}
+	if _, err := New(v3, v3); err == nil {
+		t.Error("Test Failed - New() expected error for duplicate version")
+	}
+	r, err := New(v3, v5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r.Active() != "v3" {
+		t.Errorf("Test Failed - New() expected first version active, received %s", r.Active())
+	}
+	if v := r.Versions(); len(v) != 2 || v[0] != "v3" || v[1] != "v5" {
+		t.Errorf("Test Failed - Versions() unexpected versions %v", v)
+	}
+}
+
+func TestResolve(t *testing.T) {
+	r, err := New(v3, v5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	route, err := r.Resolve(Ticker, map[string]string{"instrument": "BTC-USDT"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.Version != "v3" || route.Method != http.MethodGet ||
+		route.Path != "spot/v3/instruments/BTC-USDT/ticker" ||
+		route.SignPath != "/api/spot/v3/instruments/BTC-USDT/ticker" || route.Authenticated {
+		t.Errorf("Test Failed - Resolve() unexpected route %+v", route)
+	}
+	if _, err = r.Resolve(Ticker, nil); err == nil || !strings.Contains(err.Error(), "instrument") {
+		t.Errorf("Test Failed - Resolve() expected missing variable error, received %v", err)
+	}
+
+	if err = r.Use("v4"); err == nil || !strings.Contains(err.Error(), ErrVersionNotFound.Error()) {
+		t.Errorf("Test Failed - Use() expected ErrVersionNotFound, received %v", err)
+	}
+	if err = r.Use("v5"); err != nil {
+		t.Fatal(err)
+	}
+	if route, err = r.Resolve(Ticker, nil); err != nil || route.Path != "v5/market/ticker" {
+		t.Errorf("Test Failed - Resolve() unexpected v5 route %+v %v", route, err)
+	}
+	if r.Supports(PlaceOrder) {
+		t.Error("Test Failed - Supports() expected place order unsupported on v5")
+	}
+	if _, err = r.Resolve(PlaceOrder, nil); err == nil ||
+		!strings.Contains(err.Error(), ErrOperationUnsupported.Error()) {
+		t.Errorf("Test Failed - Resolve() expected ErrOperationUnsupported, received %v", err)
+	}
+}
@@ -0,0 +1,450 @@
+// Package errorstorm provides a circuit breaker per exchange which trips when
+// the exchange's authenticated request error rate or websocket disconnects
+// exceed their thresholds within a window. A tripped exchange rejects new
+// orders while existing orders can still be queried and cancelled, and resumes
+// once a cool down has elapsed and a health check passes
+package errorstorm
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default breaker settings
+const (
+	DefaultWindow         = time.Minute
+	DefaultMaxErrorRate   = 0.5
+	DefaultMinRequests    = 10
+	DefaultMaxDisconnects = 3
+	DefaultCooldown       = time.Minute * 5
+	DefaultCheckInterval  = time.Second * 10
+)
+
+// Event types emitted by the breaker
+const (
+	Tripped = "EXCHANGE_BREAKER_TRIPPED"
+	Resumed = "EXCHANGE_BREAKER_RESUMED"
+)
+
+var (
+	// ErrOrderFlowPaused is returned when an order is sent to an exchange
+	// whose breaker is tripped
+	ErrOrderFlowPaused = errors.New("order flow paused by exchange error storm breaker")
+
+	errInvalidErrorRate = errors.New("maximum error rate must be between 0 and 1")
+	errNoExchanges      = errors.New("no exchanges supplied")
+	errUnknownExchange  = errors.New("exchange not monitored")
+)
+
+// Config defines the breaker thresholds
+type Config struct {
+	// Window is the period errors and disconnects are counted over
+	Window time.Duration
+	// MaxErrorRate is the fraction of authenticated requests erroring which
+	// trips the breaker
+	MaxErrorRate float64
+	// MinRequests is the number of authenticated requests in the window before
+	// the error rate is considered
+	MinRequests int
+	// MaxDisconnects is the number of websocket disconnects which trips the
+	// breaker
+	MaxDisconnects int
+	// Cooldown is the time a tripped exchange waits before its health check
+	Cooldown time.Duration
+}
+
+// Validate checks the configuration and sets defaults
+func (c *Config) Validate() error {
+	if c.MaxErrorRate < 0 || c.MaxErrorRate > 1 {
+		return errInvalidErrorRate
+	}
+	if c.Window <= 0 {
+		c.Window = DefaultWindow
+	}
+	if c.MaxErrorRate == 0 {
+		c.MaxErrorRate = DefaultMaxErrorRate
+	}
+	if c.MinRequests <= 0 {
+		c.MinRequests = DefaultMinRequests
+	}
+	if c.MaxDisconnects <= 0 {
+		c.MaxDisconnects = DefaultMaxDisconnects
+	}
+	if c.Cooldown <= 0 {
+		c.Cooldown = DefaultCooldown
+	}
+	return nil
+}
+
+// HealthFunc checks an exchange can be traded again, returning an error when
+// it remains unhealthy
+type HealthFunc func(e exchange.IBotExchange) error
+
+// AccountHealth checks the exchange's authenticated API by fetching balances
+func AccountHealth(e exchange.IBotExchange) error {
+	_, err := e.GetAccountInfo()
+	return err
+}
+
+// Status is a snapshot of an exchange's breaker
+type Status struct {
+	Exchange    string    `json:"exchange"`
+	Tripped     bool      `json:"tripped"`
+	TrippedAt   time.Time `json:"trippedAt,omitempty"`
+	Reason      string    `json:"reason,omitempty"`
+	Requests    int       `json:"requests"`
+	Errors      int       `json:"errors"`
+	Disconnects int       `json:"disconnects"`
+	LastError   string    `json:"lastError,omitempty"`
+}
+
+// Event defines an exchange tripping or resuming
+type Event struct {
+	Type   string
+	Status Status
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	if e.Type == Resumed {
+		return fmt.Sprintf("%s order flow resumed after health check", e.Status.Exchange)
+	}
+	return fmt.Sprintf("%s order flow paused: %s", e.Status.Exchange, e.Status.Reason)
+}
+
+// requestObserver is implemented by exchanges reporting each request's result
+type requestObserver interface {
+	SetRequestObserver(f func(authenticated bool, err error))
+}
+
+// websocketResets is implemented by exchanges counting websocket resets
+type websocketResets interface {
+	GetWebsocketResets() int64
+}
+
+type result struct {
+	time   time.Time
+	failed bool
+}
+
+// monitor holds the recent activity of an exchange
+type monitor struct {
+	exch        exchange.IBotExchange
+	results     []result
+	disconnects []time.Time
+	resets      int64
+	status      Status
+}
+
+// prune drops activity older than the window
+func (m *monitor) prune(cutoff time.Time) {
+	i := 0
+	for i < len(m.results) && m.results[i].time.Before(cutoff) {
+		i++
+	}
+	m.results = m.results[i:]
+	i = 0
+	for i < len(m.disconnects) && m.disconnects[i].Before(cutoff) {
+		i++
+	}
+	m.disconnects = m.disconnects[i:]
+}
+
+// Breaker monitors each exchange's error rate and disconnects
+type Breaker struct {
+	cfg      Config
+	health   HealthFunc
+	onEvent  func(Event)
+	monitors map[string]*monitor
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a breaker monitoring the exchanges, registering itself to
+// observe their requests. The health func defaults to AccountHealth
+func New(cfg Config, exchanges []exchange.IBotExchange, health HealthFunc, onEvent func(Event)) (*Breaker, error) {
+	err := cfg.Validate()
+	if err != nil {
+		return nil, err
+	}
+	if len(exchanges) == 0 {
+		return nil, errNoExchanges
+	}
+	if health == nil {
+		health = AccountHealth
+	}
+
+	b := &Breaker{
+		cfg:      cfg,
+		health:   health,
+		onEvent:  onEvent,
+		monitors: make(map[string]*monitor),
+	}
+	for i := range exchanges {
+		name := exchanges[i].GetName()
+		m := &monitor{exch: exchanges[i], status: Status{Exchange: name}}
+		if w, ok := exchange.Underlying(exchanges[i]).(websocketResets); ok {
+			m.resets = w.GetWebsocketResets()
+		}
+		b.monitors[name] = m
+		if o, ok := exchange.Underlying(exchanges[i]).(requestObserver); ok {
+			o.SetRequestObserver(func(authenticated bool, err error) {
+				if authenticated {
+					b.RecordRequest(name, err)
+				}
+			})
+		}
+	}
+	return b, nil
+}
+
+// RecordRequest records the result of an authenticated request, tripping the
+// exchange's breaker when its error rate exceeds the threshold
+func (b *Breaker) RecordRequest(exchangeName string, err error) {
+	b.mtx.Lock()
+	m, ok := b.monitors[exchangeName]
+	if !ok {
+		b.mtx.Unlock()
+		return
+	}
+	now := time.Now()
+	m.results = append(m.results, result{time: now, failed: err != nil})
+	if err != nil {
+		m.status.LastError = err.Error()
+	}
+	events := b.evaluate(m, now)
+	b.mtx.Unlock()
+	b.emit(events)
+}
+
+// RecordDisconnects records websocket disconnects of an exchange
+func (b *Breaker) RecordDisconnects(exchangeName string, n int) {
+	b.mtx.Lock()
+	m, ok := b.monitors[exchangeName]
+	if !ok {
+		b.mtx.Unlock()
+		return
+	}
+	now := time.Now()
+	for i := 0; i < n; i++ {
+		m.disconnects = append(m.disconnects, now)
+	}
+	events := b.evaluate(m, now)
+	b.mtx.Unlock()
+	b.emit(events)
+}
+
+// evaluate trips the monitor when its activity exceeds a threshold, the
+// caller must hold the lock
+func (b *Breaker) evaluate(m *monitor, now time.Time) []Event {
+	m.prune(now.Add(-b.cfg.Window))
+	var failed int
+	for i := range m.results {
+		if m.results[i].failed {
+			failed++
+		}
+	}
+	m.status.Requests = len(m.results)
+	m.status.Errors = failed
+	m.status.Disconnects = len(m.disconnects)
+	if m.status.Tripped {
+		return nil
+	}
+
+	var reason string
+	switch {
+	case len(m.results) >= b.cfg.MinRequests &&
+		float64(failed)/float64(len(m.results)) >= b.cfg.MaxErrorRate:
+		reason = fmt.Sprintf("%d of %d authenticated requests failed within %s, last error: %s",
+			failed, len(m.results), b.cfg.Window, m.status.LastError)
+	case len(m.disconnects) >= b.cfg.MaxDisconnects:
+		reason = fmt.Sprintf("websocket disconnected %d times within %s",
+			len(m.disconnects), b.cfg.Window)
+	default:
+		return nil
+	}
+	m.status.Tripped = true
+	m.status.TrippedAt = now
+	m.status.Reason = reason
+	return []Event{{Type: Tripped, Status: m.status}}
+}
+
+func (b *Breaker) emit(events []Event) {
+	for i := range events {
+		log.Warnf("Exchange error storm breaker: %s", events[i].String())
+		if b.onEvent != nil {
+			b.onEvent(events[i])
+		}
+	}
+}
+
+// Check records websocket disconnects since the last check and health checks
+// tripped exchanges whose cool down has elapsed, resuming them when healthy
+func (b *Breaker) Check() {
+	b.mtx.Lock()
+	var events []Event
+	var due []*monitor
+	now := time.Now()
+	for _, m := range b.monitors {
+		if w, ok := exchange.Underlying(m.exch).(websocketResets); ok {
+			resets := w.GetWebsocketResets()
+			for i := m.resets; i < resets; i++ {
+				m.disconnects = append(m.disconnects, now)
+			}
+			m.resets = resets
+		}
+		events = append(events, b.evaluate(m, now)...)
+		if m.status.Tripped && now.Sub(m.status.TrippedAt) >= b.cfg.Cooldown {
+			due = append(due, m)
+		}
+	}
+	b.mtx.Unlock()
+	b.emit(events)
+
+	for _, m := range due {
+		err := b.health(m.exch)
+		b.mtx.Lock()
+		if err != nil {
+			// The cool down restarts until the exchange is healthy
+			m.status.TrippedAt = time.Now()
+			m.status.LastError = err.Error()
+			b.mtx.Unlock()
+			log.Warnf("Exchange error storm breaker: %s health check failed: %s",
+				m.status.Exchange, err)
+			continue
+		}
+		m.results = nil
+		m.disconnects = nil
+		m.status = Status{Exchange: m.status.Exchange}
+		resumed := Event{Type: Resumed, Status: m.status}
+		b.mtx.Unlock()
+		b.emit([]Event{resumed})
+	}
+}
+
+// IsTripped returns whether the exchange's order flow is paused
+func (b *Breaker) IsTripped(exchangeName string) bool {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	m, ok := b.monitors[exchangeName]
+	return ok && m.status.Tripped
+}
+
+// GetStatus returns the breaker status of every monitored exchange
+func (b *Breaker) GetStatus() []Status {
+	b.mtx.Lock()
+	defer b.mtx.Unlock()
+	resp := make([]Status, 0, len(b.monitors))
+	for _, m := range b.monitors {
+		resp = append(resp, m.status)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Exchange < resp[j].Exchange
+	})
+	return resp
+}
+
+// Reset resumes an exchange's order flow without waiting for its health check
+func (b *Breaker) Reset(exchangeName string) error {
+	b.mtx.Lock()
+	m, ok := b.monitors[exchangeName]
+	if !ok {
+		b.mtx.Unlock()
+		return fmt.Errorf("%s %v", exchangeName, errUnknownExchange)
+	}
+	m.results = nil
+	m.disconnects = nil
+	m.status = Status{Exchange: exchangeName}
+	b.mtx.Unlock()
+	return nil
+}
+
+// Start checks the exchanges at the interval until stopped
+func (b *Breaker) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	b.mtx.Lock()
+	if b.shutdown != nil {
+		b.mtx.Unlock()
+		return
+	}
+	b.shutdown = make(chan struct{})
+	shutdown := b.shutdown
+	b.mtx.Unlock()
+
+	b.wg.Add(1)
+	go func() {
+		defer b.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				b.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops the breaker and its request observers
+func (b *Breaker) Stop() {
+	b.mtx.Lock()
+	for _, m := range b.monitors {
+		if o, ok := exchange.Underlying(m.exch).(requestObserver); ok {
+			o.SetRequestObserver(nil)
+		}
+	}
+	if b.shutdown == nil {
+		b.mtx.Unlock()
+		return
+	}
+	close(b.shutdown)
+	b.shutdown = nil
+	b.mtx.Unlock()
+	b.wg.Wait()
+}
+
+// Guard wraps an exchange so new orders are rejected while its breaker is
+// tripped. Existing orders can still be amended, queried and cancelled
+func (b *Breaker) Guard(e exchange.IBotExchange) exchange.IBotExchange {
+	return &Guarded{IBotExchange: e, breaker: b}
+}
+
+// Guarded is an exchange whose new order flow is paused by the breaker
+type Guarded struct {
+	exchange.IBotExchange
+	breaker *Breaker
+}
+
+// Unwrap returns the underlying exchange
+func (g *Guarded) Unwrap() exchange.IBotExchange {
+	return g.IBotExchange
+}
+
+// SubmitOrder rejects orders while the exchange's breaker is tripped
+func (g *Guarded) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	if g.breaker.IsTripped(g.GetName()) {
+		return exchange.SubmitOrderResponse{}, fmt.Errorf("%s %v", g.GetName(), ErrOrderFlowPaused)
+	}
+	return g.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+}
+
+// CheckOrder rejects orders placed natively while the exchange's breaker is
+// tripped
+func (g *Guarded) CheckOrder(_ *exchange.OrderSubmission) error {
+	if g.breaker.IsTripped(g.GetName()) {
+		return fmt.Errorf("%s %v", g.GetName(), ErrOrderFlowPaused)
+	}
+	return nil
+}
@@ -0,0 +1,134 @@
+package errorstorm
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	observer  func(bool, error)
+	resets    int64
+	submitted int
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) SetRequestObserver(f func(bool, error)) { t.observer = f }
+
+func (t *testExchange) GetWebsocketResets() int64 { return t.resets }
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.submitted++
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) CancelOrder(_ *exchange.OrderCancellation) error {
+	return nil
+}
+
+func newTestBreaker(t *testing.T, exch *testExchange, health HealthFunc, events *[]Event) *Breaker {
+	b, err := New(Config{MinRequests: 4, MaxDisconnects: 2, Cooldown: time.Nanosecond},
+		[]exchange.IBotExchange{exch}, health, func(e Event) { *events = append(*events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{MaxErrorRate: 2}, []exchange.IBotExchange{&testExchange{}}, nil, nil); err != errInvalidErrorRate {
+		t.Error("Test Failed - New() expected invalid error rate error", err)
+	}
+	if _, err := New(Config{}, nil, nil, nil); err != errNoExchanges {
+		t.Error("Test Failed - New() expected no exchanges error", err)
+	}
+	exch := &testExchange{}
+	b, err := New(Config{}, []exchange.IBotExchange{exch}, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if b.cfg.Window != DefaultWindow || b.cfg.MinRequests != DefaultMinRequests || exch.observer == nil {
+		t.Errorf("Test Failed - New() expected defaults and observer set %+v", b.cfg)
+	}
+	b.Stop()
+	if exch.observer != nil {
+		t.Error("Test Failed - Stop() expected observer removed")
+	}
+}
+
+func TestErrorRate(t *testing.T) {
+	exch := &testExchange{}
+	var events []Event
+	health := errors.New("still down")
+	b := newTestBreaker(t, exch, func(exchange.IBotExchange) error { return health }, &events)
+	g := b.Guard(exch)
+
+	// Unauthenticated requests are not counted
+	for i := 0; i < 4; i++ {
+		exch.observer(false, errors.New("rate limited"))
+	}
+	exch.observer(true, nil)
+	exch.observer(true, errors.New("invalid nonce"))
+	exch.observer(true, nil)
+	if b.IsTripped("test") {
+		t.Fatal("Test Failed - RecordRequest() tripped below minimum requests")
+	}
+	exch.observer(true, errors.New("invalid nonce"))
+	if !b.IsTripped("test") || len(events) != 1 || events[0].Type != Tripped {
+		t.Fatalf("Test Failed - RecordRequest() expected trip %+v", events)
+	}
+
+	_, err := g.SubmitOrder(currency.NewPair(currency.BTC, currency.USD), exchange.BuyOrderSide, exchange.LimitOrderType, 1, 1, "")
+	if err == nil || exch.submitted != 0 {
+		t.Error("Test Failed - SubmitOrder() expected order flow paused")
+	}
+	if err = g.CancelOrder(&exchange.OrderCancellation{OrderID: "1"}); err != nil {
+		t.Error("Test Failed - CancelOrder() expected cancels to pass while tripped", err)
+	}
+
+	// Failed health checks keep the exchange paused
+	b.Check()
+	if !b.IsTripped("test") || len(events) != 1 {
+		t.Fatalf("Test Failed - Check() expected exchange to remain paused %+v", events)
+	}
+
+	health = nil
+	b.Check()
+	if b.IsTripped("test") || len(events) != 2 || events[1].Type != Resumed {
+		t.Fatalf("Test Failed - Check() expected resume %+v", events)
+	}
+	if _, err = g.SubmitOrder(currency.NewPair(currency.BTC, currency.USD), exchange.BuyOrderSide, exchange.LimitOrderType, 1, 1, ""); err != nil {
+		t.Error("Test Failed - SubmitOrder() expected order flow resumed", err)
+	}
+}
+
+func TestDisconnects(t *testing.T) {
+	exch := &testExchange{resets: 5}
+	var events []Event
+	b := newTestBreaker(t, exch, func(exchange.IBotExchange) error { return nil }, &events)
+
+	// Resets before the breaker started are ignored
+	exch.resets = 6
+	b.Check()
+	if b.IsTripped("test") {
+		t.Fatal("Test Failed - Check() tripped below maximum disconnects")
+	}
+	exch.resets = 7
+	b.Check()
+	s := b.GetStatus()
+	if len(s) != 1 || !s[0].Tripped || s[0].Disconnects != 2 || len(events) != 1 {
+		t.Fatalf("Test Failed - Check() expected disconnect trip %+v", s)
+	}
+
+	if err := b.Reset("test"); err != nil || b.IsTripped("test") {
+		t.Error("Test Failed - Reset() expected exchange resumed", err)
+	}
+	if err := b.Reset("missing"); err == nil {
+		t.Error("Test Failed - Reset() expected unknown exchange error")
+	}
+}
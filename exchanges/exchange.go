@@ -13,6 +13,8 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/endpoints"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/httprecorder"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/request"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
@@ -264,6 +266,7 @@ type Base struct {
 	Name                                       string
 	Enabled                                    bool
 	Verbose                                    bool
+	DryRun                                     bool
 	RESTPollingDelay                           time.Duration
 	WebsocketResponseCheckTimeout              time.Duration
 	WebsocketResponseMaxLimit                  time.Duration
@@ -291,6 +294,9 @@ type Base struct {
 	RequestCurrencyPairFormat                  config.CurrencyPairFormatConfig
 	ConfigCurrencyPairFormat                   config.CurrencyPairFormatConfig
 	Websocket                                  *wshandler.Websocket
+	// Endpoints holds the REST paths of the exchange's API versions, nil when
+	// the exchange has a single hardcoded version
+	Endpoints *endpoints.Registry
 	*request.Requester
 }
 
@@ -358,6 +364,49 @@ func (e *Base) SetHTTPClientTimeout(t time.Duration) {
 	e.Requester.HTTPClient.Timeout = t
 }
 
+// RecordRequestTag counts a call made under a request tag
+func (e *Base) RecordRequestTag(tag string, err error) {
+	if e.Requester == nil {
+		return
+	}
+	e.Requester.RecordTag(tag, err)
+}
+
+// GetRequestTagStats returns the requests sent under each request tag
+func (e *Base) GetRequestTagStats() map[string]request.TagStats {
+	if e.Requester == nil {
+		return nil
+	}
+	return e.Requester.GetTagStats()
+}
+
+// GetRequestRateLimits returns the exchange's unauthenticated and
+// authenticated request rate limits
+func (e *Base) GetRequestRateLimits() (unauth, auth *request.RateLimit) {
+	if e.Requester == nil {
+		return nil, nil
+	}
+	return e.Requester.UnauthLimit, e.Requester.AuthLimit
+}
+
+// SetRequestObserver sets a function notified of the result of every request
+// the exchange sends
+func (e *Base) SetRequestObserver(f func(authenticated bool, err error)) {
+	if e.Requester == nil {
+		return
+	}
+	e.Requester.SetObserver(f)
+}
+
+// GetWebsocketResets returns the number of times the exchange's websocket has
+// been reset after a disconnect
+func (e *Base) GetWebsocketResets() int64 {
+	if e.Websocket == nil {
+		return 0
+	}
+	return e.Websocket.GetResetCount()
+}
+
 // SetHTTPClient sets exchanges HTTP client
 func (e *Base) SetHTTPClient(h *http.Client) {
 	if e.Requester == nil {
@@ -707,6 +756,7 @@ func (e *Base) SetAPIKeys(apiKey, apiSecret, clientID string, b64Decode bool) {
 	} else {
 		e.APISecret = apiSecret
 	}
+	httprecorder.RegisterSecrets(e.APIKey, e.APISecret, e.ClientID)
 }
 
 // SetCurrencies sets the exchange currency pairs for either enabledPairs or
@@ -884,6 +934,27 @@ func (e *Base) SetAPIURL(ec *config.ExchangeConfig) error {
 	return nil
 }
 
+// SetAPIVersion sets the REST API version in use from the config, leaving the
+// default version active when none is configured
+func (e *Base) SetAPIVersion(ec *config.ExchangeConfig) error {
+	if ec.APIVersion == "" {
+		return nil
+	}
+	if e.Endpoints == nil {
+		return fmt.Errorf("%s does not support selecting API version %s", e.Name, ec.APIVersion)
+	}
+	return e.Endpoints.Use(ec.APIVersion)
+}
+
+// GetAPIVersion returns the REST API version in use, empty when the exchange
+// has a single hardcoded version
+func (e *Base) GetAPIVersion() string {
+	if e.Endpoints == nil {
+		return ""
+	}
+	return e.Endpoints.Active()
+}
+
 // GetAPIURL returns the set API URL
 func (e *Base) GetAPIURL() string {
 	return e.APIUrl
@@ -9,6 +9,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/endpoints"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/request"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
@@ -885,6 +886,31 @@ func TestSetAPIURL(t *testing.T) {
 	}
 }
 
+func TestSetAPIVersion(t *testing.T) {
+	tester := Base{Name: "test"}
+	test := config.ExchangeConfig{}
+	if err := tester.SetAPIVersion(&test); err != nil || tester.GetAPIVersion() != "" {
+		t.Errorf("test failed - expected no version set %v", err)
+	}
+	test.APIVersion = "v2"
+	if err := tester.SetAPIVersion(&test); err == nil {
+		t.Error("test failed - expected error without endpoint registry")
+	}
+
+	var err error
+	tester.Endpoints, err = endpoints.New(endpoints.Version{Name: "v1"}, endpoints.Version{Name: "v2"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = tester.SetAPIVersion(&test); err != nil || tester.GetAPIVersion() != "v2" {
+		t.Errorf("test failed - expected v2 set %s %v", tester.GetAPIVersion(), err)
+	}
+	test.APIVersion = "v3"
+	if err = tester.SetAPIVersion(&test); err == nil {
+		t.Error("test failed - expected error for unregistered version")
+	}
+}
+
 func BenchmarkSetAPIURL(b *testing.B) {
 	tester := Base{Name: "test"}
 
@@ -0,0 +1,448 @@
+// Package execution works large parent orders into the market as a series of
+// smaller child orders using TWAP or iceberg execution. Child order sizes and
+// submission intervals can optionally be randomised within bounds so the
+// resulting flow is less predictable to other market participants
+package execution
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Strategy defines how a parent order is worked
+type Strategy string
+
+// Supported execution strategies
+const (
+	// TWAP splits the parent order into a fixed number of slices submitted
+	// at regular intervals
+	TWAP Strategy = "TWAP"
+	// Iceberg shows a single child order of the display amount at a time,
+	// submitting the next once the previous has filled
+	Iceberg Strategy = "ICEBERG"
+)
+
+// Default execution settings
+const (
+	DefaultPollInterval = time.Second
+	DefaultFillTimeout  = time.Minute * 5
+	// MaxVariance is the largest fraction sizes and intervals can be varied by
+	MaxVariance = 0.9
+)
+
+var (
+	errNilExchange        = errors.New("execution exchange is nil")
+	errInvalidStrategy    = errors.New("execution strategy must be TWAP or ICEBERG")
+	errInvalidAmount      = errors.New("execution amount must be positive")
+	errInvalidSlices      = errors.New("execution TWAP slices must be positive")
+	errInvalidDisplay     = errors.New("execution iceberg display amount must be positive")
+	errInvalidVariance    = errors.New("execution variance must be between 0 and 0.9")
+	errInvalidPrecision   = errors.New("execution amount precision cannot be negative")
+	errChildNotPlaced     = errors.New("execution child order not placed")
+	errChildNotFilled     = errors.New("execution child order not filled before timeout")
+	errExecutionCancelled = errors.New("execution cancelled")
+)
+
+// Randomisation bounds the random variation applied to child orders. Each
+// variance is a fraction of the base value, so a size variance of 0.2 varies
+// child sizes between 80% and 120% of the base size
+type Randomisation struct {
+	SizeVariance     float64
+	IntervalVariance float64
+	// Seed makes the variation reproducible, a zero seed is time based
+	Seed int64
+}
+
+func (r *Randomisation) validate() error {
+	if r.SizeVariance < 0 || r.SizeVariance > MaxVariance ||
+		r.IntervalVariance < 0 || r.IntervalVariance > MaxVariance {
+		return errInvalidVariance
+	}
+	return nil
+}
+
+// Config defines a parent order and how it is worked
+type Config struct {
+	Strategy  Strategy
+	Pair      currency.Pair
+	Side      exchange.OrderSide
+	OrderType exchange.OrderType
+	Price     float64
+	// Amount is the total parent order amount
+	Amount float64
+	// Slices is the number of TWAP child orders
+	Slices int
+	// DisplayAmount is the size of each visible iceberg child order
+	DisplayAmount float64
+	// Interval is the delay between TWAP child orders, or between an iceberg
+	// child filling and the next being shown
+	Interval time.Duration
+	// AmountPrecision is the number of decimal places child amounts are
+	// rounded down to, zero leaves amounts unrounded
+	AmountPrecision int
+	// MinChildAmount merges any child smaller than it into its neighbour
+	MinChildAmount float64
+	PollInterval   time.Duration
+	FillTimeout    time.Duration
+	// Randomise enables size and interval randomisation when set
+	Randomise *Randomisation
+}
+
+func (c *Config) validate() error {
+	switch {
+	case c.Strategy != TWAP && c.Strategy != Iceberg:
+		return errInvalidStrategy
+	case c.Amount <= 0:
+		return errInvalidAmount
+	case c.Strategy == TWAP && c.Slices <= 0:
+		return errInvalidSlices
+	case c.Strategy == Iceberg && c.DisplayAmount <= 0:
+		return errInvalidDisplay
+	case c.AmountPrecision < 0:
+		return errInvalidPrecision
+	}
+	if c.Randomise != nil {
+		return c.Randomise.validate()
+	}
+	return nil
+}
+
+// Child defines a planned child order
+type Child struct {
+	Amount float64
+	// Delay is the wait before the child order is submitted
+	Delay time.Duration
+}
+
+// ChildResult holds the outcome of a submitted child order
+type ChildResult struct {
+	OrderID   string
+	Amount    float64
+	Filled    float64
+	Submitted time.Time
+	Err       error
+}
+
+// Report summarises a completed execution
+type Report struct {
+	Strategy Strategy
+	Pair     currency.Pair
+	Side     exchange.OrderSide
+	Amount   float64
+	// Placed is the total amount of child orders accepted by the exchange
+	Placed float64
+	// Filled is only tracked for iceberg execution, TWAP child orders are
+	// left to rest once placed
+	Filled   float64
+	Children []ChildResult
+	Started  time.Time
+	Finished time.Time
+}
+
+// String implements the stringer interface
+func (r *Report) String() string {
+	return fmt.Sprintf("%s %s %s %v/%v placed %v filled across %d child orders in %s",
+		r.Strategy,
+		r.Side,
+		r.Pair,
+		r.Placed,
+		r.Amount,
+		r.Filled,
+		len(r.Children),
+		r.Finished.Sub(r.Started))
+}
+
+// Executor works a parent order on an exchange
+type Executor struct {
+	exch exchange.IBotExchange
+	cfg  Config
+	rnd  *rand.Rand
+	mtx  sync.Mutex
+}
+
+// New returns an executor for the parent order
+func New(e exchange.IBotExchange, cfg Config) (*Executor, error) {
+	if e == nil {
+		return nil, errNilExchange
+	}
+	err := cfg.validate()
+	if err != nil {
+		return nil, err
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.FillTimeout <= 0 {
+		cfg.FillTimeout = DefaultFillTimeout
+	}
+
+	seed := time.Now().UnixNano()
+	if cfg.Randomise != nil && cfg.Randomise.Seed != 0 {
+		seed = cfg.Randomise.Seed
+	}
+	return &Executor{
+		exch: e,
+		cfg:  cfg,
+		rnd:  rand.New(rand.NewSource(seed)),
+	}, nil
+}
+
+// vary returns the value randomly varied by up to the variance either side
+func (e *Executor) vary(v, variance float64) float64 {
+	if variance <= 0 {
+		return v
+	}
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return v * (1 + variance*(2*e.rnd.Float64()-1))
+}
+
+func (e *Executor) sizeVariance() float64 {
+	if e.cfg.Randomise == nil {
+		return 0
+	}
+	return e.cfg.Randomise.SizeVariance
+}
+
+// interval returns the delay before the next child order
+func (e *Executor) interval() time.Duration {
+	if e.cfg.Randomise == nil {
+		return e.cfg.Interval
+	}
+	return time.Duration(e.vary(float64(e.cfg.Interval), e.cfg.Randomise.IntervalVariance))
+}
+
+// round rounds an amount down to the configured precision
+func (e *Executor) round(amount float64) float64 {
+	if e.cfg.AmountPrecision == 0 {
+		return amount
+	}
+	p := math.Pow(10, float64(e.cfg.AmountPrecision))
+	// Nudge before flooring so representation error doesn't drop a step
+	return math.Floor(amount*p+1e-9) / p
+}
+
+// Plan returns the TWAP child orders for the parent order. Child sizes always
+// sum to the parent amount, the final child absorbing any rounding
+func (e *Executor) Plan() []Child {
+	if e.cfg.Strategy != TWAP {
+		return nil
+	}
+
+	slices := e.cfg.Slices
+	if e.cfg.MinChildAmount > 0 {
+		if n := int(e.cfg.Amount / e.cfg.MinChildAmount); n < slices {
+			slices = n
+		}
+		if slices < 1 {
+			slices = 1
+		}
+	}
+
+	weights := make([]float64, slices)
+	var total float64
+	for i := range weights {
+		weights[i] = e.vary(1, e.sizeVariance())
+		total += weights[i]
+	}
+
+	var children []Child
+	remaining := e.cfg.Amount
+	for i := range weights {
+		amount := remaining
+		if i < len(weights)-1 {
+			amount = math.Min(e.round(e.cfg.Amount*weights[i]/total), remaining)
+		}
+		if amount <= 0 {
+			continue
+		}
+		remaining -= amount
+		var delay time.Duration
+		if len(children) > 0 {
+			delay = e.interval()
+		}
+		children = append(children, Child{Amount: amount, Delay: delay})
+	}
+	return e.mergeSmall(children)
+}
+
+// mergeSmall merges children below the minimum child amount into the
+// preceding child
+func (e *Executor) mergeSmall(children []Child) []Child {
+	if e.cfg.MinChildAmount <= 0 {
+		return children
+	}
+	var merged []Child
+	for i := range children {
+		if len(merged) > 0 && children[i].Amount < e.cfg.MinChildAmount {
+			merged[len(merged)-1].Amount += children[i].Amount
+			continue
+		}
+		merged = append(merged, children[i])
+	}
+	if len(merged) > 1 && merged[0].Amount < e.cfg.MinChildAmount {
+		merged[1].Amount += merged[0].Amount
+		merged[1].Delay = 0
+		merged = merged[1:]
+	}
+	return merged
+}
+
+// nextIcebergChild returns the next iceberg child amount from what remains
+func (e *Executor) nextIcebergChild(remaining float64) float64 {
+	amount := e.round(e.vary(e.cfg.DisplayAmount, e.sizeVariance()))
+	if amount <= 0 || amount >= remaining {
+		return remaining
+	}
+	// Avoid leaving a remainder too small to be worked on its own
+	floor := e.cfg.MinChildAmount
+	if floor <= 0 {
+		floor = e.cfg.DisplayAmount * (1 - e.sizeVariance())
+	}
+	if remaining-amount >= floor {
+		return amount
+	}
+	if remaining <= e.cfg.DisplayAmount*(1+e.sizeVariance()) {
+		return remaining
+	}
+	// Split what is left rather than showing more than the display bounds
+	return e.round(remaining / 2)
+}
+
+// Run works the parent order until it is complete, a child order fails or the
+// shutdown channel is closed. The report covers every child submitted
+func (e *Executor) Run(shutdown <-chan struct{}) (Report, error) {
+	r := Report{
+		Strategy: e.cfg.Strategy,
+		Pair:     e.cfg.Pair,
+		Side:     e.cfg.Side,
+		Amount:   e.cfg.Amount,
+		Started:  time.Now(),
+	}
+	var err error
+	if e.cfg.Strategy == TWAP {
+		err = e.runTWAP(&r, shutdown)
+	} else {
+		err = e.runIceberg(&r, shutdown)
+	}
+	r.Finished = time.Now()
+	return r, err
+}
+
+func (e *Executor) runTWAP(r *Report, shutdown <-chan struct{}) error {
+	children := e.Plan()
+	for i := range children {
+		if !wait(children[i].Delay, shutdown) {
+			return errExecutionCancelled
+		}
+		res := e.submit(children[i].Amount)
+		r.Children = append(r.Children, res)
+		if res.Err != nil {
+			return res.Err
+		}
+		r.Placed += res.Amount
+	}
+	return nil
+}
+
+func (e *Executor) runIceberg(r *Report, shutdown <-chan struct{}) error {
+	remaining := e.cfg.Amount
+	for remaining > 0 {
+		if len(r.Children) > 0 && !wait(e.interval(), shutdown) {
+			return errExecutionCancelled
+		}
+		res := e.submit(e.nextIcebergChild(remaining))
+		if res.Err == nil {
+			r.Placed += res.Amount
+			res.Filled, res.Err = e.waitFill(res.OrderID, res.Amount, shutdown)
+		}
+		r.Children = append(r.Children, res)
+		r.Filled += res.Filled
+		remaining -= res.Filled
+		if res.Err != nil {
+			return res.Err
+		}
+	}
+	return nil
+}
+
+// submit places a single child order
+func (e *Executor) submit(amount float64) ChildResult {
+	res := ChildResult{Amount: amount, Submitted: time.Now()}
+	resp, err := e.exch.SubmitOrder(e.cfg.Pair, e.cfg.Side, e.cfg.OrderType, amount, e.cfg.Price, "")
+	switch {
+	case err != nil:
+		res.Err = err
+	case !resp.IsOrderPlaced:
+		res.Err = errChildNotPlaced
+	default:
+		res.OrderID = resp.OrderID
+	}
+	if res.Err != nil {
+		log.Errorf("%s %s child order of %v failed: %s",
+			e.exch.GetName(), e.cfg.Strategy, amount, res.Err)
+	}
+	return res
+}
+
+// waitFill polls a child order until it has filled, cancelling it if it has
+// not filled before the timeout
+func (e *Executor) waitFill(orderID string, amount float64, shutdown <-chan struct{}) (float64, error) {
+	deadline := time.Now().Add(e.cfg.FillTimeout)
+	var filled float64
+	for {
+		detail, err := e.exch.GetOrderInfo(orderID)
+		if err != nil {
+			return filled, err
+		}
+		filled = detail.ExecutedAmount
+		if filled >= amount || (filled > 0 && detail.RemainingAmount <= 0) {
+			return filled, nil
+		}
+		if time.Now().After(deadline) {
+			break
+		}
+		if !wait(e.cfg.PollInterval, shutdown) {
+			return filled, errExecutionCancelled
+		}
+	}
+
+	err := e.exch.CancelOrder(&exchange.OrderCancellation{
+		OrderID:      orderID,
+		Side:         e.cfg.Side,
+		CurrencyPair: e.cfg.Pair,
+	})
+	if err != nil {
+		log.Errorf("%s failed to cancel unfilled iceberg child order %s: %s",
+			e.exch.GetName(), orderID, err)
+	}
+	return filled, errChildNotFilled
+}
+
+// wait sleeps for the duration, returning false if shutdown is closed first
+func wait(d time.Duration, shutdown <-chan struct{}) bool {
+	if d <= 0 {
+		select {
+		case <-shutdown:
+			return false
+		default:
+			return true
+		}
+	}
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-shutdown:
+		return false
+	case <-t.C:
+		return true
+	}
+}
@@ -0,0 +1,271 @@
+package execution
+
+import (
+	"errors"
+	"math"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	orders    map[string]float64
+	submitted []float64
+	cancelled []string
+	noFill    bool
+	submitErr error
+	mtx       sync.Mutex
+}
+
+func newTestExchange() *testExchange {
+	return &testExchange{orders: make(map[string]float64)}
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, amount, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	if t.submitErr != nil {
+		return exchange.SubmitOrderResponse{}, t.submitErr
+	}
+	id := strconv.Itoa(len(t.submitted))
+	t.submitted = append(t.submitted, amount)
+	t.orders[id] = amount
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true, OrderID: id}, nil
+}
+
+func (t *testExchange) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	amount := t.orders[orderID]
+	if t.noFill {
+		return exchange.OrderDetail{ID: orderID, Amount: amount, RemainingAmount: amount}, nil
+	}
+	return exchange.OrderDetail{ID: orderID, Amount: amount, ExecutedAmount: amount}, nil
+}
+
+func (t *testExchange) CancelOrder(o *exchange.OrderCancellation) error {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.cancelled = append(t.cancelled, o.OrderID)
+	return nil
+}
+
+func testConfig(s Strategy) Config {
+	return Config{
+		Strategy:      s,
+		Pair:          currency.NewPairWithDelimiter("BTC", "USD", "-"),
+		Side:          exchange.BuyOrderSide,
+		OrderType:     exchange.LimitOrderType,
+		Price:         1000,
+		Amount:        10,
+		Slices:        5,
+		DisplayAmount: 2,
+		Interval:      time.Millisecond,
+		PollInterval:  time.Millisecond,
+		FillTimeout:   time.Millisecond * 20,
+	}
+}
+
+func sum(amounts []float64) float64 {
+	var total float64
+	for i := range amounts {
+		total += amounts[i]
+	}
+	return total
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil, testConfig(TWAP)); err != errNilExchange {
+		t.Error("Test Failed - New() expected nil exchange error", err)
+	}
+
+	tests := []struct {
+		mod func(c *Config)
+		err error
+	}{
+		{func(c *Config) { c.Strategy = "VWAP" }, errInvalidStrategy},
+		{func(c *Config) { c.Amount = 0 }, errInvalidAmount},
+		{func(c *Config) { c.Slices = 0 }, errInvalidSlices},
+		{func(c *Config) { c.AmountPrecision = -1 }, errInvalidPrecision},
+		{func(c *Config) { c.Randomise = &Randomisation{SizeVariance: 1} }, errInvalidVariance},
+		{func(c *Config) { c.Randomise = &Randomisation{IntervalVariance: -0.1} }, errInvalidVariance},
+	}
+	for i := range tests {
+		cfg := testConfig(TWAP)
+		tests[i].mod(&cfg)
+		if _, err := New(newTestExchange(), cfg); err != tests[i].err {
+			t.Errorf("Test Failed - New() test %d expected %v, got %v", i, tests[i].err, err)
+		}
+	}
+
+	cfg := testConfig(Iceberg)
+	cfg.DisplayAmount = 0
+	if _, err := New(newTestExchange(), cfg); err != errInvalidDisplay {
+		t.Error("Test Failed - New() expected invalid display error", err)
+	}
+}
+
+func TestPlan(t *testing.T) {
+	e, err := New(newTestExchange(), testConfig(TWAP))
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	children := e.Plan()
+	if len(children) != 5 {
+		t.Fatalf("Test Failed - Plan() expected 5 children, got %d", len(children))
+	}
+	for i := range children {
+		if children[i].Amount != 2 {
+			t.Errorf("Test Failed - Plan() expected even split, got %v", children[i].Amount)
+		}
+		if (i == 0 && children[i].Delay != 0) || (i > 0 && children[i].Delay != time.Millisecond) {
+			t.Errorf("Test Failed - Plan() unexpected delay %s", children[i].Delay)
+		}
+	}
+
+	cfg := testConfig(TWAP)
+	cfg.Slices = 20
+	cfg.Interval = time.Second
+	cfg.AmountPrecision = 3
+	cfg.Randomise = &Randomisation{SizeVariance: 0.3, IntervalVariance: 0.5, Seed: 42}
+	e, err = New(newTestExchange(), cfg)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	children = e.Plan()
+	var amounts []float64
+	var varied bool
+	for i := range children {
+		amounts = append(amounts, children[i].Amount)
+		if children[i].Amount != children[0].Amount {
+			varied = true
+		}
+		// Normalising the weights can stretch the bounds slightly
+		if children[i].Amount < 0.5*0.6 || children[i].Amount > 0.5*1.6 {
+			t.Errorf("Test Failed - Plan() child amount %v outside bounds", children[i].Amount)
+		}
+		if i > 0 && (children[i].Delay < time.Millisecond*500 || children[i].Delay > time.Millisecond*1500) {
+			t.Errorf("Test Failed - Plan() child delay %s outside bounds", children[i].Delay)
+		}
+	}
+	if !varied {
+		t.Error("Test Failed - Plan() child sizes not randomised")
+	}
+	if math.Abs(sum(amounts)-10) > 1e-9 {
+		t.Errorf("Test Failed - Plan() children sum to %v", sum(amounts))
+	}
+
+	// The same seed produces the same plan
+	e2, err := New(newTestExchange(), cfg)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	again := e2.Plan()
+	for i := range again {
+		if again[i] != children[i] {
+			t.Fatal("Test Failed - Plan() not reproducible with seed")
+		}
+	}
+}
+
+func TestPlanMinChildAmount(t *testing.T) {
+	cfg := testConfig(TWAP)
+	cfg.Amount = 1
+	cfg.Slices = 4
+	cfg.MinChildAmount = 0.3
+	e, err := New(newTestExchange(), cfg)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	children := e.Plan()
+	if len(children) != 3 {
+		t.Fatalf("Test Failed - Plan() slices not reduced to meet minimum %+v", children)
+	}
+	for i := range children {
+		if children[i].Amount < cfg.MinChildAmount {
+			t.Errorf("Test Failed - Plan() child %v below minimum", children[i].Amount)
+		}
+	}
+}
+
+func TestRunTWAP(t *testing.T) {
+	exch := newTestExchange()
+	e, err := New(exch, testConfig(TWAP))
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	r, err := e.Run(nil)
+	if err != nil {
+		t.Fatal("Test Failed - Run() error", err)
+	}
+	if len(exch.submitted) != 5 || r.Placed != 10 || len(r.Children) != 5 {
+		t.Errorf("Test Failed - Run() unexpected report %s", r.String())
+	}
+
+	exch.submitErr = errors.New("rejected")
+	r, err = e.Run(nil)
+	if err != exch.submitErr {
+		t.Error("Test Failed - Run() expected submit error", err)
+	}
+	if len(r.Children) != 1 || r.Placed != 0 {
+		t.Errorf("Test Failed - Run() unexpected report after error %s", r.String())
+	}
+
+	exch.submitErr = nil
+	cfg := testConfig(TWAP)
+	cfg.Interval = time.Hour
+	e, err = New(exch, cfg)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	shutdown := make(chan struct{})
+	close(shutdown)
+	if _, err = e.Run(shutdown); err != errExecutionCancelled {
+		t.Error("Test Failed - Run() expected cancellation", err)
+	}
+}
+
+func TestRunIceberg(t *testing.T) {
+	exch := newTestExchange()
+	cfg := testConfig(Iceberg)
+	cfg.Amount = 7
+	cfg.Randomise = &Randomisation{SizeVariance: 0.25, IntervalVariance: 0.5, Seed: 7}
+	cfg.AmountPrecision = 2
+	e, err := New(exch, cfg)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	r, err := e.Run(nil)
+	if err != nil {
+		t.Fatal("Test Failed - Run() error", err)
+	}
+	if math.Abs(r.Filled-7) > 1e-9 || math.Abs(sum(exch.submitted)-7) > 1e-9 {
+		t.Errorf("Test Failed - Run() unexpected report %s", r.String())
+	}
+	for i := range exch.submitted {
+		if exch.submitted[i] > 2.5+1e-9 {
+			t.Errorf("Test Failed - Run() child %v above display bounds", exch.submitted[i])
+		}
+	}
+
+	exch = newTestExchange()
+	exch.noFill = true
+	e, err = New(exch, testConfig(Iceberg))
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	r, err = e.Run(nil)
+	if err != errChildNotFilled {
+		t.Error("Test Failed - Run() expected not filled error", err)
+	}
+	if len(r.Children) != 1 || len(exch.cancelled) != 1 {
+		t.Error("Test Failed - Run() unfilled child not cancelled")
+	}
+}
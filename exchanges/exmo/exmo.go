@@ -376,12 +376,25 @@ func (e *EXMO) SendHTTPRequest(path string, result interface{}) error {
 	return e.SendPayload(http.MethodGet, path, nil, nil, result, false, false, e.Verbose, e.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Every
+// endpoint is a POST so they are classified by name
+func mutating(endpoint string) bool {
+	switch endpoint {
+	case exmoOrderCreate, exmoOrderCancel, exmoWithdrawCrypt, exmoExcodeCreate, exmoExcodeLoad:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP request
 func (e *EXMO) SendAuthenticatedHTTPRequest(method, endpoint string, vals url.Values, result interface{}) error {
 	if !e.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet,
 			e.Name)
 	}
+	if e.DryRunRequest(mutating(endpoint), endpoint, vals) {
+		return nil
+	}
 
 	n := e.Requester.GetNonce(true).String()
 	vals.Set("nonce", n)
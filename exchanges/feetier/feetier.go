@@ -0,0 +1,417 @@
+// Package feetier tracks each exchange's account fee tier, the maker and taker
+// fees charged and the trading volume they are based on, persisting them and
+// the tier changes seen. When the volume is close to the threshold of a tier
+// which would meaningfully reduce fees an alert is raised, once per
+// threshold. Exchanges reporting only their fees and volume have their next
+// tier found from a configured fee schedule
+package feetier
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default tracker settings
+const (
+	DefaultCheckInterval = time.Hour * 6
+	// DefaultProximity is the fraction of the next tier's volume threshold
+	// within which an alert is raised
+	DefaultProximity = 0.1
+	// DefaultMinReduction is the fraction the next tier must reduce fees by
+	// to be worth an alert
+	DefaultMinReduction = 0.1
+	// maxChanges is the number of tier changes kept
+	maxChanges = 100
+)
+
+// Event types emitted by the tracker
+const (
+	Changed     = "FEE_TIER_CHANGED"
+	Approaching = "FEE_TIER_APPROACHING"
+)
+
+var (
+	errNoSources       = errors.New("no fee tier sources supplied")
+	errInvalidSettings = errors.New("fee tier proximity and minimum reduction must be between 0 and 1")
+	errInvalidSchedule = errors.New("fee schedule levels must have increasing volumes and non negative fees")
+)
+
+// Tier holds an account's fee tier on an exchange. Fees are fractions of the
+// notional traded
+type Tier struct {
+	Exchange string `json:"exchange"`
+	// Level names the tier where the exchange reports one
+	Level    string  `json:"level,omitempty"`
+	MakerFee float64 `json:"makerFee"`
+	TakerFee float64 `json:"takerFee"`
+	// Volume is the trading volume the tier is based on, typically over 30
+	// days, in VolumeCurrency
+	Volume         float64 `json:"volume"`
+	VolumeCurrency string  `json:"volumeCurrency,omitempty"`
+	// NextVolume is the volume threshold of the next tier and NextMakerFee
+	// and NextTakerFee its fees, unset when the account is on the best tier
+	// or the next tier is unknown
+	NextVolume   float64   `json:"nextVolume,omitempty"`
+	NextMakerFee float64   `json:"nextMakerFee,omitempty"`
+	NextTakerFee float64   `json:"nextTakerFee,omitempty"`
+	Updated      time.Time `json:"updated"`
+}
+
+// Reduction returns the fraction the next tier reduces taker fees by
+func (t *Tier) Reduction() float64 {
+	if t.NextVolume <= 0 || t.TakerFee <= 0 {
+		return 0
+	}
+	return (t.TakerFee - t.NextTakerFee) / t.TakerFee
+}
+
+// Saving returns the taker fees the next tier would have saved on the volume
+func (t *Tier) Saving() float64 {
+	if t.NextVolume <= 0 {
+		return 0
+	}
+	return t.Volume * (t.TakerFee - t.NextTakerFee)
+}
+
+// amount formats a volume in the volume currency
+func (t *Tier) amount(v float64) string {
+	return strings.TrimSpace(fmt.Sprintf("%v %s", v, t.VolumeCurrency))
+}
+
+// Level defines a tier of an exchange's fee schedule
+type Level struct {
+	Name     string  `json:"name,omitempty"`
+	Volume   float64 `json:"volume"`
+	MakerFee float64 `json:"makerFee"`
+	TakerFee float64 `json:"takerFee"`
+}
+
+// Config defines the alerts raised and the fee schedules of exchanges which
+// do not report their next tier
+type Config struct {
+	Proximity    float64 `json:"proximity"`
+	MinReduction float64 `json:"minReduction"`
+	// Schedules maps exchange names to their fee schedule
+	Schedules map[string][]Level `json:"schedules"`
+}
+
+// Validate checks the settings and schedules
+func (c *Config) Validate() error {
+	if c.Proximity < 0 || c.Proximity >= 1 || c.MinReduction < 0 || c.MinReduction >= 1 {
+		return errInvalidSettings
+	}
+	for name, levels := range c.Schedules {
+		for i := range levels {
+			if levels[i].MakerFee < 0 || levels[i].TakerFee < 0 ||
+				(i > 0 && levels[i].Volume <= levels[i-1].Volume) {
+				return fmt.Errorf("%s %v", name, errInvalidSchedule)
+			}
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads the config from a JSON file
+func LoadConfig(path string) (Config, error) {
+	var c Config
+	data, err := common.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	return c, json.Unmarshal(data, &c)
+}
+
+// Change records a tier change
+type Change struct {
+	From Tier      `json:"from"`
+	To   Tier      `json:"to"`
+	Time time.Time `json:"time"`
+}
+
+// Event defines a tier change or an account approaching a better tier
+type Event struct {
+	Type string
+	Tier Tier
+	// Previous is the tier changed from
+	Previous *Tier
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	if e.Type == Changed && e.Previous != nil {
+		return fmt.Sprintf("%s %s fee tier changed from %v/%v to %v/%v maker/taker on volume %s",
+			e.Type, e.Tier.Exchange, e.Previous.MakerFee, e.Previous.TakerFee, e.Tier.MakerFee,
+			e.Tier.TakerFee, e.Tier.amount(e.Tier.Volume))
+	}
+	return fmt.Sprintf("%s %s volume %v of %s for the next fee tier, reducing taker fees %.1f%% from %v to %v and saving %s on the current volume",
+		e.Type, e.Tier.Exchange, e.Tier.Volume, e.Tier.amount(e.Tier.NextVolume),
+		e.Tier.Reduction()*100, e.Tier.TakerFee, e.Tier.NextTakerFee, e.Tier.amount(e.Tier.Saving()))
+}
+
+// Source reports an exchange's fee tier
+type Source interface {
+	GetName() string
+	GetFeeTier() (Tier, error)
+}
+
+// state is persisted between restarts
+type state struct {
+	Tiers   map[string]Tier `json:"tiers"`
+	Changes []Change        `json:"changes"`
+	// Alerted maps exchanges to the volume threshold last alerted
+	Alerted map[string]float64 `json:"alerted"`
+}
+
+// Tracker tracks the fee tiers of its sources
+type Tracker struct {
+	cfg      Config
+	path     string
+	sources  []Source
+	onEvent  func(Event)
+	state    state
+	errors   map[string]string
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a tracker of the sources' fee tiers persisting to the path,
+// tiers persisted by a previous run are loaded
+func New(cfg Config, path string, sources []Source, onEvent func(Event)) (*Tracker, error) {
+	if len(sources) == 0 {
+		return nil, errNoSources
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if cfg.Proximity == 0 {
+		cfg.Proximity = DefaultProximity
+	}
+	if cfg.MinReduction == 0 {
+		cfg.MinReduction = DefaultMinReduction
+	}
+	t := &Tracker{
+		cfg:     cfg,
+		path:    path,
+		sources: sources,
+		onEvent: onEvent,
+		state: state{
+			Tiers:   make(map[string]Tier),
+			Alerted: make(map[string]float64),
+		},
+		errors: make(map[string]string),
+	}
+	if path == "" {
+		return t, nil
+	}
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return t, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &t.state); err != nil {
+		return nil, err
+	}
+	if t.state.Tiers == nil {
+		t.state.Tiers = make(map[string]Tier)
+	}
+	if t.state.Alerted == nil {
+		t.state.Alerted = make(map[string]float64)
+	}
+	return t, nil
+}
+
+// save writes the state to the file, the caller must hold the lock
+func (t *Tracker) save() error {
+	if t.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(t.state, "", " ")
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(t.path, data)
+}
+
+// schedule returns the exchange's configured fee schedule
+func (t *Tracker) schedule(exchName string) []Level {
+	for name, levels := range t.cfg.Schedules {
+		if strings.EqualFold(name, exchName) {
+			return levels
+		}
+	}
+	return nil
+}
+
+// next sets the tier's next tier from the schedule when the source does not
+// report one
+func (t *Tracker) next(tier *Tier) {
+	if tier.NextVolume > 0 {
+		return
+	}
+	levels := t.schedule(tier.Exchange)
+	named := tier.Level != ""
+	for i := range levels {
+		if levels[i].Volume <= tier.Volume {
+			if !named {
+				tier.Level = levels[i].Name
+			}
+			continue
+		}
+		tier.NextVolume = levels[i].Volume
+		tier.NextMakerFee = levels[i].MakerFee
+		tier.NextTakerFee = levels[i].TakerFee
+		return
+	}
+}
+
+// Check fetches every source's fee tier
+func (t *Tracker) Check() {
+	for _, s := range t.sources {
+		tier, err := s.GetFeeTier()
+		t.mtx.Lock()
+		if err != nil {
+			t.errors[strings.ToLower(s.GetName())] = err.Error()
+			t.mtx.Unlock()
+			log.Warnf("Fee tier %s check failed: %s", s.GetName(), err)
+			continue
+		}
+		delete(t.errors, strings.ToLower(s.GetName()))
+		if tier.Exchange == "" {
+			tier.Exchange = s.GetName()
+		}
+		if tier.Updated.IsZero() {
+			tier.Updated = time.Now()
+		}
+		t.next(&tier)
+		events := t.update(&tier)
+		if err = t.save(); err != nil {
+			log.Errorf("Fee tier failed to persist %s tier: %s", tier.Exchange, err)
+		}
+		t.mtx.Unlock()
+
+		for i := range events {
+			log.Debugln(events[i].String())
+			if t.onEvent != nil {
+				t.onEvent(events[i])
+			}
+		}
+	}
+}
+
+// update records the tier, returning the events it raises. The caller must
+// hold the lock
+func (t *Tracker) update(tier *Tier) []Event {
+	var events []Event
+	k := strings.ToLower(tier.Exchange)
+	prev, ok := t.state.Tiers[k]
+	t.state.Tiers[k] = *tier
+	if ok && (prev.MakerFee != tier.MakerFee || prev.TakerFee != tier.TakerFee || prev.Level != tier.Level) {
+		t.state.Changes = append(t.state.Changes, Change{From: prev, To: *tier, Time: tier.Updated})
+		if len(t.state.Changes) > maxChanges {
+			t.state.Changes = t.state.Changes[len(t.state.Changes)-maxChanges:]
+		}
+		delete(t.state.Alerted, k)
+		p := prev
+		events = append(events, Event{Type: Changed, Tier: *tier, Previous: &p})
+	}
+
+	if tier.NextVolume <= 0 || tier.Volume >= tier.NextVolume ||
+		tier.Volume < tier.NextVolume*(1-t.cfg.Proximity) ||
+		tier.Reduction() < t.cfg.MinReduction || t.state.Alerted[k] == tier.NextVolume {
+		return events
+	}
+	t.state.Alerted[k] = tier.NextVolume
+	return append(events, Event{Type: Approaching, Tier: *tier})
+}
+
+// Status holds an exchange's fee tier and its latest check error
+type Status struct {
+	Tier
+	Error string `json:"error,omitempty"`
+}
+
+// GetTiers returns every exchange's latest fee tier, sorted by exchange
+func (t *Tracker) GetTiers() []Status {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	var resp []Status
+	for k, tier := range t.state.Tiers {
+		resp = append(resp, Status{Tier: tier, Error: t.errors[k]})
+	}
+	for _, s := range t.sources {
+		k := strings.ToLower(s.GetName())
+		if _, ok := t.state.Tiers[k]; !ok && t.errors[k] != "" {
+			resp = append(resp, Status{Tier: Tier{Exchange: s.GetName()}, Error: t.errors[k]})
+		}
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Exchange < resp[j].Exchange
+	})
+	return resp
+}
+
+// GetChanges returns the tier changes recorded, newest first
+func (t *Tracker) GetChanges() []Change {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	resp := make([]Change, len(t.state.Changes))
+	for i := range t.state.Changes {
+		resp[len(resp)-1-i] = t.state.Changes[i]
+	}
+	return resp
+}
+
+// Start checks fee tiers at the interval until stopped
+func (t *Tracker) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	t.mtx.Lock()
+	if t.shutdown != nil {
+		t.mtx.Unlock()
+		return
+	}
+	t.shutdown = make(chan struct{})
+	shutdown := t.shutdown
+	t.mtx.Unlock()
+
+	t.wg.Add(1)
+	go func() {
+		defer t.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			t.Check()
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the tracker
+func (t *Tracker) Stop() {
+	t.mtx.Lock()
+	if t.shutdown == nil {
+		t.mtx.Unlock()
+		return
+	}
+	close(t.shutdown)
+	t.shutdown = nil
+	t.mtx.Unlock()
+	t.wg.Wait()
+}
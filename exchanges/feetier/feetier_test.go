@@ -0,0 +1,105 @@
+package feetier
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+type testSource struct {
+	tier Tier
+	err  error
+}
+
+func (t *testSource) GetName() string { return "test" }
+
+func (t *testSource) GetFeeTier() (Tier, error) {
+	return t.tier, t.err
+}
+
+var testSchedule = []Level{
+	{Name: "VIP0", Volume: 0, MakerFee: 0.002, TakerFee: 0.002},
+	{Name: "VIP1", Volume: 100, MakerFee: 0.0015, TakerFee: 0.0017},
+	{Name: "VIP2", Volume: 1000, MakerFee: 0.001, TakerFee: 0.0016},
+}
+
+func TestValidate(t *testing.T) {
+	c := Config{Proximity: 1}
+	if err := c.Validate(); err != errInvalidSettings {
+		t.Error("Test Failed - Validate() expected invalid settings error", err)
+	}
+	c = Config{Schedules: map[string][]Level{"test": {testSchedule[1], testSchedule[0]}}}
+	if err := c.Validate(); err == nil {
+		t.Error("Test Failed - Validate() expected invalid schedule error")
+	}
+	c.Schedules["test"] = testSchedule
+	if err := c.Validate(); err != nil {
+		t.Error("Test Failed - Validate() error", err)
+	}
+}
+
+func TestTracker(t *testing.T) {
+	dir, err := ioutil.TempDir("", "feetier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "feetiers.json")
+
+	src := &testSource{tier: Tier{MakerFee: 0.002, TakerFee: 0.002, Volume: 50}}
+	cfg := Config{Schedules: map[string][]Level{"Test": testSchedule}}
+	var events []Event
+	tr, err := New(cfg, path, []Source{src}, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The next tier is found from the schedule, far from its threshold
+	tr.Check()
+	tiers := tr.GetTiers()
+	if len(tiers) != 1 || tiers[0].Level != "VIP0" || tiers[0].NextVolume != 100 || tiers[0].NextTakerFee != 0.0017 {
+		t.Fatalf("Test Failed - Check() unexpected tier %+v", tiers)
+	}
+	if len(events) != 0 {
+		t.Fatalf("Test Failed - Check() expected no events %+v", events)
+	}
+
+	// Within 10% of a tier reducing fees by 15% alerts once
+	src.tier.Volume = 95
+	tr.Check()
+	tr.Check()
+	if len(events) != 1 || events[0].Type != Approaching || math.Abs(events[0].Tier.Saving()-95*0.0003) > 1e-12 {
+		t.Fatalf("Test Failed - Check() expected one approaching alert %+v", events)
+	}
+
+	// The alert state and tiers persist across restarts
+	tr, err = New(cfg, path, []Source{src}, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	tr.Check()
+	if len(events) != 1 {
+		t.Fatalf("Test Failed - Check() expected no repeated alert %+v", events)
+	}
+
+	// Reaching the tier records the change, the next tier's reduction is too
+	// small to alert on
+	src.tier = Tier{MakerFee: 0.0015, TakerFee: 0.0017, Volume: 950}
+	tr.Check()
+	if len(events) != 2 || events[1].Type != Changed || events[1].Previous.TakerFee != 0.002 {
+		t.Fatalf("Test Failed - Check() expected a tier change %+v", events)
+	}
+	changes := tr.GetChanges()
+	if len(changes) != 1 || changes[0].To.Level != "VIP1" {
+		t.Errorf("Test Failed - GetChanges() unexpected %+v", changes)
+	}
+
+	src.err = errors.New("unavailable")
+	tr.Check()
+	if tiers = tr.GetTiers(); tiers[0].Error == "" || tiers[0].TakerFee != 0.0017 {
+		t.Errorf("Test Failed - Check() expected the error recorded with the last tier %+v", tiers)
+	}
+}
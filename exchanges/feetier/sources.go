@@ -0,0 +1,140 @@
+package feetier
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/huobi"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/poloniex"
+)
+
+var errNoEnabledPairs = errors.New("no enabled pairs to query fees for")
+
+// BitmexTier reports Bitmex's commission, which does not depend on volume
+type BitmexTier struct {
+	Exchange *bitmex.Bitmex
+}
+
+// GetName returns the exchange name
+func (b *BitmexTier) GetName() string {
+	return b.Exchange.GetName()
+}
+
+// GetFeeTier returns the account's fee tier
+func (b *BitmexTier) GetFeeTier() (Tier, error) {
+	c, err := b.Exchange.GetUserCommision()
+	if err != nil {
+		return Tier{}, err
+	}
+	return Tier{
+		Exchange: b.GetName(),
+		MakerFee: c.MakerFee,
+		TakerFee: c.TakerFee,
+	}, nil
+}
+
+// HuobiTier reports the fee rates Huobi applies to the account's first
+// enabled pair. Huobi does not report the account's volume
+type HuobiTier struct {
+	Exchange *huobi.HUOBI
+}
+
+// GetName returns the exchange name
+func (h *HuobiTier) GetName() string {
+	return h.Exchange.GetName()
+}
+
+// GetFeeTier returns the account's fee tier
+func (h *HuobiTier) GetFeeTier() (Tier, error) {
+	pairs := h.Exchange.GetEnabledCurrencies()
+	if len(pairs) == 0 {
+		return Tier{}, errNoEnabledPairs
+	}
+	symbol := exchange.FormatExchangeCurrency(h.GetName(), pairs[0]).String()
+	rates, err := h.Exchange.GetTransactFeeRates([]string{symbol})
+	if err != nil {
+		return Tier{}, err
+	}
+	for i := range rates {
+		if strings.EqualFold(rates[i].Symbol, symbol) {
+			return Tier{
+				Exchange: h.GetName(),
+				MakerFee: rates[i].MakerFeeRate,
+				TakerFee: rates[i].TakerFeeRate,
+			}, nil
+		}
+	}
+	return Tier{}, fmt.Errorf("fee rate not found for %s", symbol)
+}
+
+// KrakenTier reports Kraken's 30 day volume and the fees and next tier of the
+// account's first enabled pair
+type KrakenTier struct {
+	Exchange *kraken.Kraken
+}
+
+// GetName returns the exchange name
+func (k *KrakenTier) GetName() string {
+	return k.Exchange.GetName()
+}
+
+// GetFeeTier returns the account's fee tier
+func (k *KrakenTier) GetFeeTier() (Tier, error) {
+	pairs := k.Exchange.GetEnabledCurrencies()
+	if len(pairs) == 0 {
+		return Tier{}, errNoEnabledPairs
+	}
+	p := pairs[0]
+	resp, err := k.Exchange.GetTradeVolume(true, p.Base.String()+p.Quote.String())
+	if err != nil {
+		return Tier{}, err
+	}
+	t := Tier{
+		Exchange:       k.GetName(),
+		Volume:         resp.Volume,
+		VolumeCurrency: strings.TrimPrefix(resp.Currency, "Z"),
+	}
+	// The response is keyed by Kraken's name of the pair, only the pair
+	// queried is returned. Fees are percentages
+	for _, f := range resp.Fees {
+		t.TakerFee = f.Fee / 100
+		t.NextTakerFee = f.NextFee / 100
+		t.NextVolume = f.NextVolume
+		t.Level = fmt.Sprintf("%v", f.TierVolume)
+	}
+	for _, f := range resp.FeesMaker {
+		t.MakerFee = f.Fee / 100
+		t.NextMakerFee = f.NextFee / 100
+	}
+	return t, nil
+}
+
+// PoloniexTier reports Poloniex's fees and 30 day volume in BTC, its next tier
+// is found from the configured fee schedule
+type PoloniexTier struct {
+	Exchange *poloniex.Poloniex
+}
+
+// GetName returns the exchange name
+func (p *PoloniexTier) GetName() string {
+	return p.Exchange.GetName()
+}
+
+// GetFeeTier returns the account's fee tier
+func (p *PoloniexTier) GetFeeTier() (Tier, error) {
+	f, err := p.Exchange.GetFeeInfo()
+	if err != nil {
+		return Tier{}, err
+	}
+	return Tier{
+		Exchange:       p.GetName(),
+		MakerFee:       f.MakerFee,
+		TakerFee:       f.TakerFee,
+		Volume:         f.ThirtyDayVolume,
+		VolumeCurrency: "BTC",
+	}, nil
+}
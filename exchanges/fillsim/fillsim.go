@@ -0,0 +1,327 @@
+// Package fillsim simulates order fills against recorded orderbook and trade
+// data for backtesting. Resting orders track their position in the queue at
+// their price level so that maker strategies only fill once enough volume has
+// traded ahead of them
+package fillsim
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wsrecorder"
+)
+
+var (
+	errOrderIDNotSet     = errors.New("fill simulator order ID not set")
+	errDuplicateOrderID  = errors.New("fill simulator order ID already exists")
+	errOrderNotFound     = errors.New("fill simulator order not found")
+	errInvalidOrderSide  = errors.New("fill simulator order side must be buy or sell")
+	errInvalidOrderPrice = errors.New("fill simulator order price must be positive")
+	errInvalidAmount     = errors.New("fill simulator order amount must be positive")
+	errNilModel          = errors.New("fill simulator model is nil")
+	errNilIterator       = errors.New("fill simulator iterator is nil")
+)
+
+// Order defines a simulated limit order
+type Order struct {
+	ID        string
+	Exchange  string
+	Pair      currency.Pair
+	AssetType string
+	Side      exchange.OrderSide
+	Price     float64
+	Amount    float64
+	Filled    float64
+	Placed    time.Time
+	// QueueAhead is the resting volume at the order's price level which must
+	// trade before the order begins to fill
+	QueueAhead float64
+}
+
+// Remaining returns the unfilled amount of the order
+func (o *Order) Remaining() float64 {
+	return o.Amount - o.Filled
+}
+
+// IsBuy returns whether the order is a buy order
+func (o *Order) IsBuy() bool {
+	return o.Side == exchange.BuyOrderSide || o.Side == exchange.BidOrderSide
+}
+
+// matches returns whether the event relates to the order's orderbook
+func (o *Order) matches(e *wsrecorder.Event) bool {
+	return strings.EqualFold(o.Exchange, e.Exchange) &&
+		strings.EqualFold(o.AssetType, e.AssetType) &&
+		o.Pair.Base.Match(e.Pair.Base) &&
+		o.Pair.Quote.Match(e.Pair.Quote)
+}
+
+// Fill defines a simulated execution
+type Fill struct {
+	OrderID   string
+	Timestamp time.Time
+	Price     float64
+	Amount    float64
+	Maker     bool
+}
+
+// Model determines when resting orders fill
+type Model interface {
+	// QueueAhead returns the volume ahead of a newly resting order
+	QueueAhead(o *Order, book *wsrecorder.Book) float64
+	// TradeFill returns the amount of a resting order filled by a trade,
+	// updating the order's queue position
+	TradeFill(o *Order, trade *wsrecorder.Event) float64
+	// BookUpdate adjusts a resting order's queue position following an
+	// orderbook update
+	BookUpdate(o *Order, book *wsrecorder.Book)
+}
+
+// NaiveModel fills resting orders in full as soon as a trade prints at or
+// through their price
+type NaiveModel struct{}
+
+// QueueAhead implements the Model interface
+func (NaiveModel) QueueAhead(*Order, *wsrecorder.Book) float64 { return 0 }
+
+// TradeFill implements the Model interface
+func (NaiveModel) TradeFill(o *Order, trade *wsrecorder.Event) float64 {
+	if !tradeReaches(o, trade) {
+		return 0
+	}
+	return o.Remaining()
+}
+
+// BookUpdate implements the Model interface
+func (NaiveModel) BookUpdate(*Order, *wsrecorder.Book) {}
+
+// QueueModel places resting orders at the back of the queue at their price
+// level. Trades at the order's price consume the volume ahead before filling
+// the order, trades through the order's price fill it in full and
+// cancellations at the level move the order forward
+type QueueModel struct{}
+
+// QueueAhead implements the Model interface
+func (QueueModel) QueueAhead(o *Order, book *wsrecorder.Book) float64 {
+	if book == nil {
+		return 0
+	}
+	if o.IsBuy() {
+		return book.BidAmount(o.Price)
+	}
+	return book.AskAmount(o.Price)
+}
+
+// TradeFill implements the Model interface
+func (QueueModel) TradeFill(o *Order, trade *wsrecorder.Event) float64 {
+	if !tradeReaches(o, trade) {
+		return 0
+	}
+	if trade.Price != o.Price {
+		// The level was swept so everything resting at it has traded
+		o.QueueAhead = 0
+		return o.Remaining()
+	}
+
+	volume := trade.Amount
+	if o.QueueAhead > 0 {
+		consumed := math.Min(o.QueueAhead, volume)
+		o.QueueAhead -= consumed
+		volume -= consumed
+	}
+	return math.Min(volume, o.Remaining())
+}
+
+// BookUpdate implements the Model interface
+func (QueueModel) BookUpdate(o *Order, book *wsrecorder.Book) {
+	var level float64
+	if o.IsBuy() {
+		level = book.BidAmount(o.Price)
+	} else {
+		level = book.AskAmount(o.Price)
+	}
+	// Volume can only leave the queue ahead, volume added at the level joins
+	// behind the order
+	if level < o.QueueAhead {
+		o.QueueAhead = level
+	}
+}
+
+// tradeReaches returns whether the trade printed at or through the order's
+// price on the opposing side
+func tradeReaches(o *Order, trade *wsrecorder.Event) bool {
+	if o.IsBuy() {
+		return trade.Price <= o.Price
+	}
+	return trade.Price >= o.Price
+}
+
+// Simulator matches simulated orders against recorded market data
+type Simulator struct {
+	model  Model
+	orders map[string]*Order
+	fills  []Fill
+	mtx    sync.Mutex
+}
+
+// New returns a new fill simulator using the supplied model
+func New(m Model) (*Simulator, error) {
+	if m == nil {
+		return nil, errNilModel
+	}
+	return &Simulator{model: m, orders: make(map[string]*Order)}, nil
+}
+
+// Submit places an order against the current book. Any part of the order
+// which crosses the book fills immediately as a taker against the resting
+// levels, the remainder rests at the back of the queue at its price
+func (s *Simulator) Submit(o Order, book *wsrecorder.Book, t time.Time) ([]Fill, error) {
+	switch {
+	case o.ID == "":
+		return nil, errOrderIDNotSet
+	case !o.IsBuy() && o.Side != exchange.SellOrderSide && o.Side != exchange.AskOrderSide:
+		return nil, errInvalidOrderSide
+	case o.Price <= 0:
+		return nil, errInvalidOrderPrice
+	case o.Amount <= 0:
+		return nil, errInvalidAmount
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.orders[o.ID]; ok {
+		return nil, errDuplicateOrderID
+	}
+
+	o.Filled = 0
+	o.Placed = t
+	var fills []Fill
+	if book != nil {
+		fills = s.cross(&o, book, t)
+	}
+	if o.Remaining() > 0 {
+		o.QueueAhead = s.model.QueueAhead(&o, book)
+		s.orders[o.ID] = &o
+	}
+	s.fills = append(s.fills, fills...)
+	return fills, nil
+}
+
+// cross fills the marketable part of an order against the opposing side of
+// the book
+func (s *Simulator) cross(o *Order, book *wsrecorder.Book, t time.Time) []Fill {
+	levels := book.Asks()
+	if !o.IsBuy() {
+		levels = book.Bids()
+	}
+
+	var fills []Fill
+	for i := range levels {
+		if o.Remaining() <= 0 ||
+			(o.IsBuy() && levels[i].Price > o.Price) ||
+			(!o.IsBuy() && levels[i].Price < o.Price) {
+			break
+		}
+		amount := math.Min(levels[i].Amount, o.Remaining())
+		o.Filled += amount
+		fills = append(fills, Fill{
+			OrderID:   o.ID,
+			Timestamp: t,
+			Price:     levels[i].Price,
+			Amount:    amount,
+		})
+	}
+	return fills
+}
+
+// Cancel removes a resting order
+func (s *Simulator) Cancel(id string) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if _, ok := s.orders[id]; !ok {
+		return fmt.Errorf("%s %v", id, errOrderNotFound)
+	}
+	delete(s.orders, id)
+	return nil
+}
+
+// GetOrder returns a resting order
+func (s *Simulator) GetOrder(id string) (Order, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	o, ok := s.orders[id]
+	if !ok {
+		return Order{}, fmt.Errorf("%s %v", id, errOrderNotFound)
+	}
+	return *o, nil
+}
+
+// GetFills returns all simulated fills
+func (s *Simulator) GetFills() []Fill {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return append([]Fill(nil), s.fills...)
+}
+
+// Process applies a recorded event to the resting orders on its book and
+// returns any resulting fills. Book is the reconstructed state after the
+// event was applied
+func (s *Simulator) Process(e *wsrecorder.Event, book *wsrecorder.Book) []Fill {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+
+	var fills []Fill
+	for id, o := range s.orders {
+		if !o.matches(e) {
+			continue
+		}
+		switch e.Type {
+		case wsrecorder.Trade:
+			amount := s.model.TradeFill(o, e)
+			if amount <= 0 {
+				continue
+			}
+			o.Filled += amount
+			fills = append(fills, Fill{
+				OrderID:   id,
+				Timestamp: e.Timestamp,
+				Price:     o.Price,
+				Amount:    amount,
+				Maker:     true,
+			})
+			if o.Remaining() <= 0 {
+				delete(s.orders, id)
+			}
+		case wsrecorder.Snapshot, wsrecorder.Delta:
+			if book != nil {
+				s.model.BookUpdate(o, book)
+			}
+		}
+	}
+	s.fills = append(s.fills, fills...)
+	return fills
+}
+
+// Replay feeds every event from the iterator through the simulator. The
+// handler is called after each event with its reconstructed book and fills so
+// that strategies can submit and cancel orders as the data is replayed
+func Replay(it *wsrecorder.Iterator, s *Simulator, handler func(e *wsrecorder.Event, book *wsrecorder.Book, fills []Fill)) error {
+	if it == nil {
+		return errNilIterator
+	}
+	for it.Next() {
+		e := it.Event()
+		book := it.Book()
+		fills := s.Process(&e, book)
+		if handler != nil {
+			handler(&e, book, fills)
+		}
+	}
+	return it.Err()
+}
@@ -0,0 +1,192 @@
+package fillsim
+
+import (
+	"bytes"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wsrecorder"
+)
+
+var (
+	testPair  = currency.NewPairWithDelimiter("BTC", "USD", "-")
+	testStart = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// recordTestStream records a book with 5 resting at the 100 bid, followed by
+// trades at the bid, cancellations at the bid and a trade through the bid
+func recordTestStream(t *testing.T) *bytes.Buffer {
+	var buf bytes.Buffer
+	r, err := wsrecorder.NewRecorder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	book := func(bidAmount float64, ts time.Duration) {
+		err = r.RecordOrderbook(&orderbook.Base{
+			ExchangeName: "test",
+			Pair:         testPair,
+			AssetType:    orderbook.Spot,
+			Bids:         []orderbook.Item{{Price: 100, Amount: bidAmount}, {Price: 99, Amount: 10}},
+			Asks:         []orderbook.Item{{Price: 101, Amount: 1}},
+		}, testStart.Add(ts))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	trade := func(price, amount float64, ts time.Duration) {
+		err = r.RecordTrade(&wshandler.TradeData{
+			Timestamp:    testStart.Add(ts),
+			Exchange:     "test",
+			CurrencyPair: testPair,
+			AssetType:    orderbook.Spot,
+			Price:        price,
+			Amount:       amount,
+			Side:         "SELL",
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	book(5, 0)
+	trade(100, 3, time.Second)
+	book(2, time.Second*2)
+	book(1, time.Second*3)
+	trade(100, 1.5, time.Second*4)
+	trade(99, 0.1, time.Second*5)
+	return &buf
+}
+
+func runTestStrategy(t *testing.T, m Model) []Fill {
+	s, err := New(m)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	var placed bool
+	err = Replay(wsrecorder.NewIterator(recordTestStream(t)), s, func(e *wsrecorder.Event, book *wsrecorder.Book, _ []Fill) {
+		if placed || e.Type != wsrecorder.Snapshot {
+			return
+		}
+		placed = true
+		_, err = s.Submit(Order{
+			ID:        "1",
+			Exchange:  "test",
+			Pair:      testPair,
+			AssetType: orderbook.Spot,
+			Side:      exchange.BuyOrderSide,
+			Price:     book.BestBid(),
+			Amount:    1,
+		}, book, e.Timestamp)
+		if err != nil {
+			t.Fatal("Test Failed - Submit() error", err)
+		}
+	})
+	if err != nil {
+		t.Fatal("Test Failed - Replay() error", err)
+	}
+	return s.GetFills()
+}
+
+func TestQueueModel(t *testing.T) {
+	fills := runTestStrategy(t, QueueModel{})
+	if len(fills) != 2 {
+		t.Fatalf("Test Failed - QueueModel expected 2 fills, received %d", len(fills))
+	}
+	// 3 traded ahead, 1 cancelled ahead, leaving 1 ahead of the 1.5 traded
+	if math.Abs(fills[0].Amount-0.5) > 1e-9 ||
+		!fills[0].Timestamp.Equal(testStart.Add(time.Second*4)) ||
+		!fills[0].Maker {
+		t.Errorf("Test Failed - QueueModel unexpected first fill %+v", fills[0])
+	}
+	// The trade through the level fills the remainder at the order price
+	if math.Abs(fills[1].Amount-0.5) > 1e-9 || fills[1].Price != 100 {
+		t.Errorf("Test Failed - QueueModel unexpected second fill %+v", fills[1])
+	}
+}
+
+func TestNaiveModel(t *testing.T) {
+	fills := runTestStrategy(t, NaiveModel{})
+	if len(fills) != 1 ||
+		fills[0].Amount != 1 ||
+		!fills[0].Timestamp.Equal(testStart.Add(time.Second)) {
+		t.Errorf("Test Failed - NaiveModel expected immediate fill on first trade %+v", fills)
+	}
+}
+
+func TestSubmit(t *testing.T) {
+	if _, err := New(nil); err != errNilModel {
+		t.Error("Test Failed - New() expected nil model error")
+	}
+	s, err := New(QueueModel{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	it := wsrecorder.NewIterator(recordTestStream(t))
+	if !it.Next() {
+		t.Fatal("Test Failed - expected snapshot")
+	}
+	book := it.Book()
+	o := Order{
+		ID:        "buy",
+		Exchange:  "test",
+		Pair:      testPair,
+		AssetType: orderbook.Spot,
+		Side:      exchange.BuyOrderSide,
+		Price:     101.5,
+		Amount:    2,
+	}
+
+	// Crosses the 1 offered at 101 and rests the remainder
+	fills, err := s.Submit(o, book, testStart)
+	if err != nil {
+		t.Fatal("Test Failed - Submit() error", err)
+	}
+	if len(fills) != 1 || fills[0].Price != 101 || fills[0].Amount != 1 || fills[0].Maker {
+		t.Errorf("Test Failed - Submit() unexpected taker fills %+v", fills)
+	}
+	resting, err := s.GetOrder("buy")
+	if err != nil || resting.Remaining() != 1 || resting.QueueAhead != 0 {
+		t.Errorf("Test Failed - Submit() unexpected resting order %+v %v", resting, err)
+	}
+	if _, err = s.Submit(o, book, testStart); err != errDuplicateOrderID {
+		t.Error("Test Failed - Submit() expected duplicate order error", err)
+	}
+
+	o.ID = "sell"
+	o.Side = exchange.SellOrderSide
+	o.Price = 100
+	fills, err = s.Submit(o, book, testStart)
+	if err != nil || len(fills) != 1 || fills[0].Amount != 2 {
+		t.Errorf("Test Failed - Submit() expected sell to fill against bids %+v %v", fills, err)
+	}
+	if _, err = s.GetOrder("sell"); err == nil {
+		t.Error("Test Failed - Submit() expected filled order not to rest")
+	}
+
+	o.ID = "invalid"
+	o.Amount = 0
+	if _, err = s.Submit(o, book, testStart); err != errInvalidAmount {
+		t.Error("Test Failed - Submit() expected invalid amount error", err)
+	}
+	o.Amount = 1
+	o.Side = "SIDEWAYS"
+	if _, err = s.Submit(o, book, testStart); err != errInvalidOrderSide {
+		t.Error("Test Failed - Submit() expected invalid side error", err)
+	}
+
+	if err = s.Cancel("buy"); err != nil {
+		t.Error("Test Failed - Cancel() error", err)
+	}
+	if err = s.Cancel("buy"); err == nil {
+		t.Error("Test Failed - Cancel() expected order not found error")
+	}
+	if len(s.GetFills()) != 2 {
+		t.Error("Test Failed - GetFills() expected 2 fills")
+	}
+}
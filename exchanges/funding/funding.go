@@ -0,0 +1,311 @@
+// Package funding forecasts the funding cost of holding a perpetual swap
+// position over the coming funding periods from the current, predicted and
+// time weighted historical funding rates, and compares it against the basis
+// paid to hold the equivalent dated future to expiry
+package funding
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Defaults used by the forecaster
+const (
+	// DefaultHalfLife is the number of periods after which a historical rate
+	// carries half the weight of the latest rate in the long run mean
+	DefaultHalfLife = 9
+	// DefaultDecay is the fraction of the distance between the predicted rate
+	// and the long run mean which remains after each forecast period
+	DefaultDecay = 0.8
+	// DefaultPeriods is the number of periods forecast when none are
+	// requested
+	DefaultPeriods = 9
+	// MaxPeriods is the maximum number of periods forecast
+	MaxPeriods = 1095
+
+	year = time.Hour * 24 * 365
+)
+
+// Carry instruments
+const (
+	Perpetual = "perpetual"
+	Futures   = "futures"
+)
+
+var (
+	errSourceNotFound     = errors.New("funding source not found")
+	errInvalidPeriods     = errors.New("forecast periods must be between 1 and 1095")
+	errInvalidInterval    = errors.New("funding interval must be positive")
+	errInvalidHalfLife    = errors.New("half life must be positive")
+	errInvalidDecay       = errors.New("decay must be between 0 and 1")
+	errInvalidPrice       = errors.New("futures and index prices must be positive")
+	errExpired            = errors.New("futures contract has expired")
+	errInsufficientPeriod = errors.New("forecast does not cover the futures expiry")
+)
+
+// Rate holds the funding rates of a perpetual swap
+type Rate struct {
+	Exchange   string
+	Instrument string
+	// Current is the rate charged at the next funding time
+	Current float64
+	// Predicted is the estimated rate of the following period, zero when the
+	// exchange does not publish one
+	Predicted    float64
+	HasPredicted bool
+	Interval     time.Duration
+	NextFunding  time.Time
+	// History holds realised rates, newest first
+	History []float64
+}
+
+// Source provides the funding rates of an exchange's perpetual swaps
+type Source interface {
+	GetName() string
+	GetFundingRate(instrument string) (*Rate, error)
+}
+
+// Period holds the forecast rate of a single funding period
+type Period struct {
+	Time time.Time
+	Rate float64
+	// Estimated is false for rates published by the exchange
+	Estimated bool
+}
+
+// Forecast holds the expected funding rates over the coming periods. Positive
+// rates are paid by long positions and received by short positions
+type Forecast struct {
+	Exchange   string
+	Instrument string
+	Interval   time.Duration
+	Periods    []Period
+	// Mean is the time weighted historical rate forecasts revert to
+	Mean       float64
+	Cumulative float64
+	Annualised float64
+}
+
+// Cost returns the funding paid by a position of the notional value over the
+// forecast, a negative cost is received
+func (f *Forecast) Cost(notional float64, long bool) float64 {
+	if long {
+		return notional * f.Cumulative
+	}
+	return -notional * f.Cumulative
+}
+
+// Until returns the cumulative rate of periods funded at or before t
+func (f *Forecast) Until(t time.Time) float64 {
+	var total float64
+	for i := range f.Periods {
+		if f.Periods[i].Time.After(t) {
+			break
+		}
+		total += f.Periods[i].Rate
+	}
+	return total
+}
+
+// Carry compares the cost of holding a position to a dated future's expiry
+// through the perpetual swap against holding the dated future, costs are
+// fractions of notional and negative costs are received
+type Carry struct {
+	Expiry        time.Time
+	Periods       int
+	PerpetualCost float64
+	// FuturesCost is the basis paid over the index price
+	FuturesCost float64
+	Preferred   string
+	Saving      float64
+}
+
+// String implements the stringer interface
+func (c *Carry) String() string {
+	return fmt.Sprintf("%s preferred until %s over %d periods, perpetual cost %.4f%% futures cost %.4f%%",
+		c.Preferred, c.Expiry.Format(time.RFC3339), c.Periods, c.PerpetualCost*100, c.FuturesCost*100)
+}
+
+// CompareCarry compares the forecast funding cost of holding a perpetual
+// position until expiry against the basis of the dated future
+func CompareCarry(f *Forecast, futuresPrice, indexPrice float64, expiry time.Time, long bool) (Carry, error) {
+	if futuresPrice <= 0 || indexPrice <= 0 {
+		return Carry{}, errInvalidPrice
+	}
+	if !expiry.After(time.Now()) {
+		return Carry{}, errExpired
+	}
+	if len(f.Periods) == 0 || f.Periods[len(f.Periods)-1].Time.Add(f.Interval).Before(expiry) {
+		return Carry{}, errInsufficientPeriod
+	}
+
+	c := Carry{
+		Expiry:        expiry,
+		PerpetualCost: f.Until(expiry),
+		FuturesCost:   (futuresPrice - indexPrice) / indexPrice,
+	}
+	for i := range f.Periods {
+		if !f.Periods[i].Time.After(expiry) {
+			c.Periods++
+		}
+	}
+	if !long {
+		c.PerpetualCost = -c.PerpetualCost
+		c.FuturesCost = -c.FuturesCost
+	}
+	c.Preferred = Perpetual
+	c.Saving = c.FuturesCost - c.PerpetualCost
+	if c.FuturesCost < c.PerpetualCost {
+		c.Preferred = Futures
+		c.Saving = -c.Saving
+	}
+	return c, nil
+}
+
+// Forecaster forecasts funding costs from the rates of its sources
+type Forecaster struct {
+	sources  []Source
+	halfLife float64
+	decay    float64
+	mtx      sync.RWMutex
+}
+
+// New returns a forecaster using the default half life and decay
+func New(sources ...Source) *Forecaster {
+	return &Forecaster{
+		sources:  sources,
+		halfLife: DefaultHalfLife,
+		decay:    DefaultDecay,
+	}
+}
+
+// SetWeighting sets the half life of historical rates in periods and the
+// per period decay of the predicted rate towards the historical mean
+func (f *Forecaster) SetWeighting(halfLife, decay float64) error {
+	if halfLife <= 0 {
+		return errInvalidHalfLife
+	}
+	if decay < 0 || decay > 1 {
+		return errInvalidDecay
+	}
+	f.mtx.Lock()
+	f.halfLife = halfLife
+	f.decay = decay
+	f.mtx.Unlock()
+	return nil
+}
+
+func (f *Forecaster) getRate(exchangeName, instrument string) (*Rate, error) {
+	for i := range f.sources {
+		if strings.EqualFold(f.sources[i].GetName(), exchangeName) {
+			return f.sources[i].GetFundingRate(instrument)
+		}
+	}
+	return nil, fmt.Errorf("%s %v", exchangeName, errSourceNotFound)
+}
+
+// Forecast fetches the latest funding rates of an instrument and forecasts
+// them over the number of periods
+func (f *Forecaster) Forecast(exchangeName, instrument string, periods int) (*Forecast, error) {
+	rate, err := f.getRate(exchangeName, instrument)
+	if err != nil {
+		return nil, err
+	}
+	f.mtx.RLock()
+	defer f.mtx.RUnlock()
+	return forecast(rate, periods, f.halfLife, f.decay)
+}
+
+// CompareCarry forecasts the funding of a perpetual swap until a dated
+// future's expiry and compares it against the future's basis
+func (f *Forecaster) CompareCarry(exchangeName, perpetual string, futuresPrice, indexPrice float64, expiry time.Time, long bool) (Carry, error) {
+	if !expiry.After(time.Now()) {
+		return Carry{}, errExpired
+	}
+	rate, err := f.getRate(exchangeName, perpetual)
+	if err != nil {
+		return Carry{}, err
+	}
+	if rate.Interval <= 0 {
+		return Carry{}, errInvalidInterval
+	}
+	periods := int(time.Until(expiry)/rate.Interval) + 1
+	if periods > MaxPeriods {
+		periods = MaxPeriods
+	}
+
+	f.mtx.RLock()
+	fc, err := forecast(rate, periods, f.halfLife, f.decay)
+	f.mtx.RUnlock()
+	if err != nil {
+		return Carry{}, err
+	}
+	return CompareCarry(fc, futuresPrice, indexPrice, expiry, long)
+}
+
+// TimeWeightedMean returns the mean of rates ordered newest first, weighting
+// each rate by half for every half life periods it is older than the latest
+func TimeWeightedMean(rates []float64, halfLife float64) float64 {
+	var sum, weights float64
+	for i := range rates {
+		w := math.Pow(0.5, float64(i)/halfLife)
+		sum += rates[i] * w
+		weights += w
+	}
+	if weights == 0 {
+		return 0
+	}
+	return sum / weights
+}
+
+// forecast projects the rate over the periods. The current and predicted
+// rates are used as published, subsequent periods revert from the latest
+// known rate towards the time weighted historical mean
+func forecast(r *Rate, periods int, halfLife, decay float64) (*Forecast, error) {
+	if periods <= 0 || periods > MaxPeriods {
+		return nil, errInvalidPeriods
+	}
+	if r.Interval <= 0 {
+		return nil, errInvalidInterval
+	}
+
+	known := []float64{r.Current}
+	if r.HasPredicted {
+		known = append(known, r.Predicted)
+	}
+	latest := known[len(known)-1]
+	mean := latest
+	if len(r.History) > 0 {
+		mean = TimeWeightedMean(r.History, halfLife)
+	}
+
+	next := r.NextFunding
+	if next.IsZero() {
+		next = time.Now().Truncate(r.Interval).Add(r.Interval)
+	}
+
+	fc := &Forecast{
+		Exchange:   r.Exchange,
+		Instrument: r.Instrument,
+		Interval:   r.Interval,
+		Periods:    make([]Period, periods),
+		Mean:       mean,
+	}
+	for i := 0; i < periods; i++ {
+		p := Period{Time: next.Add(r.Interval * time.Duration(i))}
+		if i < len(known) {
+			p.Rate = known[i]
+		} else {
+			p.Rate = mean + (latest-mean)*math.Pow(decay, float64(i-len(known)+1))
+			p.Estimated = true
+		}
+		fc.Periods[i] = p
+		fc.Cumulative += p.Rate
+	}
+	fc.Annualised = fc.Cumulative / float64(periods) * float64(year) / float64(r.Interval)
+	return fc, nil
+}
@@ -0,0 +1,168 @@
+package funding
+
+import (
+	"errors"
+	"math"
+	"testing"
+	"time"
+)
+
+type testSource struct {
+	rate *Rate
+	err  error
+}
+
+func (t *testSource) GetName() string { return "Test" }
+
+func (t *testSource) GetFundingRate(_ string) (*Rate, error) {
+	return t.rate, t.err
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func testRate() *Rate {
+	return &Rate{
+		Exchange:     "Test",
+		Instrument:   "BTC-USD-SWAP",
+		Current:      0.001,
+		Predicted:    0.0008,
+		HasPredicted: true,
+		Interval:     time.Hour * 8,
+		NextFunding:  time.Now().Add(time.Hour),
+		History:      []float64{0.0001, 0.0001, 0.0001},
+	}
+}
+
+func TestTimeWeightedMean(t *testing.T) {
+	if TimeWeightedMean(nil, DefaultHalfLife) != 0 {
+		t.Error("Test Failed - TimeWeightedMean() expected 0 for no rates")
+	}
+	// The second rate carries half the weight of the first
+	if m := TimeWeightedMean([]float64{0.003, 0}, 1); !closeTo(m, 0.002) {
+		t.Errorf("Test Failed - TimeWeightedMean() expected 0.002, got %v", m)
+	}
+}
+
+func TestForecast(t *testing.T) {
+	r := testRate()
+	if _, err := forecast(r, 0, DefaultHalfLife, DefaultDecay); err != errInvalidPeriods {
+		t.Error("Test Failed - forecast() expected invalid periods error", err)
+	}
+	r.Interval = 0
+	if _, err := forecast(r, 1, DefaultHalfLife, DefaultDecay); err != errInvalidInterval {
+		t.Error("Test Failed - forecast() expected invalid interval error", err)
+	}
+
+	r = testRate()
+	f, err := forecast(r, 4, DefaultHalfLife, 0.5)
+	if err != nil {
+		t.Fatal("Test Failed - forecast() error", err)
+	}
+	want := []float64{0.001, 0.0008, 0.00045, 0.000275}
+	for i := range want {
+		if !closeTo(f.Periods[i].Rate, want[i]) || f.Periods[i].Estimated != (i > 1) {
+			t.Errorf("Test Failed - forecast() period %d unexpected %+v", i, f.Periods[i])
+		}
+		if !f.Periods[i].Time.Equal(r.NextFunding.Add(r.Interval * time.Duration(i))) {
+			t.Errorf("Test Failed - forecast() period %d unexpected time %v", i, f.Periods[i].Time)
+		}
+	}
+	if !closeTo(f.Cumulative, 0.002525) || !closeTo(f.Annualised, 0.002525/4*1095) {
+		t.Errorf("Test Failed - forecast() unexpected totals %+v", f)
+	}
+	if !closeTo(f.Cost(1000, true), 2.525) || !closeTo(f.Cost(1000, false), -2.525) {
+		t.Error("Test Failed - Cost() unexpected cost")
+	}
+	if !closeTo(f.Until(r.NextFunding.Add(r.Interval)), 0.0018) {
+		t.Error("Test Failed - Until() unexpected cumulative rate")
+	}
+
+	// Without a predicted rate or history the current rate is held
+	r.HasPredicted = false
+	r.History = nil
+	f, _ = forecast(r, 3, DefaultHalfLife, DefaultDecay)
+	if !closeTo(f.Cumulative, 0.003) {
+		t.Errorf("Test Failed - forecast() expected current rate held, got %v", f.Cumulative)
+	}
+}
+
+func TestCompareCarry(t *testing.T) {
+	f, err := forecast(testRate(), 9, DefaultHalfLife, DefaultDecay)
+	if err != nil {
+		t.Fatal("Test Failed - forecast() error", err)
+	}
+	expiry := time.Now().Add(time.Hour * 48)
+	if _, err = CompareCarry(f, 0, 100, expiry, true); err != errInvalidPrice {
+		t.Error("Test Failed - CompareCarry() expected invalid price error", err)
+	}
+	if _, err = CompareCarry(f, 101, 100, time.Now().Add(-time.Hour), true); err != errExpired {
+		t.Error("Test Failed - CompareCarry() expected expired error", err)
+	}
+	if _, err = CompareCarry(f, 101, 100, time.Now().Add(time.Hour*24*7), true); err != errInsufficientPeriod {
+		t.Error("Test Failed - CompareCarry() expected insufficient periods error", err)
+	}
+
+	c, err := CompareCarry(f, 100.1, 100, expiry, true)
+	if err != nil {
+		t.Fatal("Test Failed - CompareCarry() error", err)
+	}
+	if c.Periods != 6 || !closeTo(c.FuturesCost, 0.001) || c.Preferred != Futures ||
+		!closeTo(c.Saving, c.PerpetualCost-c.FuturesCost) {
+		t.Errorf("Test Failed - CompareCarry() unexpected long carry %+v", c)
+	}
+	c, _ = CompareCarry(f, 100.1, 100, expiry, false)
+	if c.Preferred != Perpetual || c.PerpetualCost >= 0 {
+		t.Errorf("Test Failed - CompareCarry() unexpected short carry %+v", c)
+	}
+}
+
+func TestForecaster(t *testing.T) {
+	src := &testSource{rate: testRate()}
+	f := New(src)
+	if _, err := f.Forecast("Other", "BTC-USD-SWAP", 1); err == nil {
+		t.Error("Test Failed - Forecast() expected source not found error")
+	}
+	if err := f.SetWeighting(0, 0.5); err != errInvalidHalfLife {
+		t.Error("Test Failed - SetWeighting() expected invalid half life error", err)
+	}
+	if err := f.SetWeighting(1, 2); err != errInvalidDecay {
+		t.Error("Test Failed - SetWeighting() expected invalid decay error", err)
+	}
+	if err := f.SetWeighting(1, 0); err != nil {
+		t.Fatal("Test Failed - SetWeighting() error", err)
+	}
+	fc, err := f.Forecast("test", "BTC-USD-SWAP", 3)
+	if err != nil {
+		t.Fatal("Test Failed - Forecast() error", err)
+	}
+	if !closeTo(fc.Periods[2].Rate, 0.0001) {
+		t.Errorf("Test Failed - Forecast() expected reversion to mean, got %+v", fc.Periods[2])
+	}
+
+	c, err := f.CompareCarry("Test", "BTC-USD-SWAP", 100.1, 100, time.Now().Add(time.Hour*24*30), true)
+	if err != nil {
+		t.Fatal("Test Failed - CompareCarry() error", err)
+	}
+	if c.Periods != 90 && c.Periods != 91 {
+		t.Errorf("Test Failed - CompareCarry() unexpected periods %d", c.Periods)
+	}
+	if _, err = f.CompareCarry("Test", "BTC-USD-SWAP", 100.1, 100, time.Now(), true); err != errExpired {
+		t.Error("Test Failed - CompareCarry() expected expired error", err)
+	}
+
+	src.err = errors.New("unavailable")
+	if _, err = f.Forecast("Test", "BTC-USD-SWAP", 3); err != src.err {
+		t.Error("Test Failed - Forecast() expected source error", err)
+	}
+}
+
+func TestBitmexInterval(t *testing.T) {
+	if d := bitmexInterval("2000-01-01T08:00:00.000Z"); d != time.Hour*8 {
+		t.Errorf("Test Failed - bitmexInterval() expected 8h, got %v", d)
+	}
+	if d := bitmexInterval(""); d != 0 {
+		t.Errorf("Test Failed - bitmexInterval() expected 0, got %v", d)
+	}
+}
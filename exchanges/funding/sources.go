@@ -0,0 +1,129 @@
+package funding
+
+import (
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okgroup"
+)
+
+const (
+	historyLimit = 30
+
+	okexFundingInterval = time.Hour * 8
+	// bitmexIntervalEpoch is subtracted from BitMEX funding intervals, which
+	// are returned as timestamps such as 2000-01-01T08:00:00.000Z
+	bitmexIntervalEpoch = "2000-01-01T00:00:00.000Z"
+)
+
+var errInstrumentNotFound = errors.New("instrument not found")
+
+// BitmexSource provides BitMEX perpetual swap funding rates
+type BitmexSource struct {
+	Exchange *bitmex.Bitmex
+}
+
+// GetName returns the exchange name
+func (b *BitmexSource) GetName() string {
+	return b.Exchange.GetName()
+}
+
+// GetFundingRate returns the funding rates of a BitMEX perpetual swap such as
+// XBTUSD
+func (b *BitmexSource) GetFundingRate(instrument string) (*Rate, error) {
+	instruments, err := b.Exchange.GetInstruments(&bitmex.GenericRequestParams{
+		Symbol: instrument,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(instruments) == 0 || instruments[0].FundingInterval == "" {
+		return nil, errInstrumentNotFound
+	}
+
+	i := &instruments[0]
+	r := &Rate{
+		Exchange:     b.GetName(),
+		Instrument:   i.Symbol,
+		Current:      i.FundingRate,
+		Predicted:    i.IndicativeFundingRate,
+		HasPredicted: true,
+		Interval:     bitmexInterval(i.FundingInterval),
+	}
+	r.NextFunding, _ = time.Parse(time.RFC3339, i.FundingTimestamp)
+
+	history, err := b.Exchange.GetFundingRateHistory(&bitmex.GenericRequestParams{
+		Symbol:  i.Symbol,
+		Count:   historyLimit,
+		Reverse: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for j := range history {
+		r.History = append(r.History, history[j].FundingRate)
+	}
+	return r, nil
+}
+
+func bitmexInterval(interval string) time.Duration {
+	epoch, _ := time.Parse(time.RFC3339, bitmexIntervalEpoch)
+	t, err := time.Parse(time.RFC3339, interval)
+	if err != nil {
+		return 0
+	}
+	return t.Sub(epoch)
+}
+
+// OKEXSource provides OKEX perpetual swap funding rates
+type OKEXSource struct {
+	Exchange *okex.OKEX
+}
+
+// GetName returns the exchange name
+func (o *OKEXSource) GetName() string {
+	return o.Exchange.GetName()
+}
+
+// GetFundingRate returns the funding rates of an OKEX perpetual swap such as
+// BTC-USD-SWAP
+func (o *OKEXSource) GetFundingRate(instrument string) (*Rate, error) {
+	resp, err := o.Exchange.GetSwapNextSettlementTime(instrument)
+	if err != nil {
+		return nil, err
+	}
+	if resp.InstrumentID == "" {
+		return nil, errInstrumentNotFound
+	}
+
+	r := &Rate{
+		Exchange:   o.GetName(),
+		Instrument: resp.InstrumentID,
+		Interval:   okexFundingInterval,
+	}
+	r.Current, _ = strconv.ParseFloat(resp.FundingRate, 64)
+	if resp.EstimatedRate != "" {
+		r.Predicted, err = strconv.ParseFloat(resp.EstimatedRate, 64)
+		r.HasPredicted = err == nil
+	}
+	r.NextFunding, _ = time.Parse(time.RFC3339, resp.FundingTime)
+
+	history, err := o.Exchange.GetSwapFundingRateHistory(okgroup.GetSwapFundingRateHistoryRequest{
+		InstrumentID: resp.InstrumentID,
+		Limit:        historyLimit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	for i := range history {
+		rate := history[i].RealizedRate
+		if rate == 0 {
+			rate = history[i].FundingRate
+		}
+		r.History = append(r.History, rate)
+	}
+	return r, nil
+}
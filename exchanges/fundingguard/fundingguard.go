@@ -0,0 +1,625 @@
+// Package fundingguard avoids paying unfavourable perpetual swap funding.
+// Shortly before each funding time every open position is evaluated against
+// the coming funding rate. A position due to pay funding above the minimum
+// rate, where the funding cost exceeds the estimated cost of trading out of
+// and back into the position, is closed at market and reopened once funding
+// has passed, or hedged on another instrument until then when a hedge is
+// configured. Actions are persisted so closed positions are still reopened
+// after a restart
+package fundingguard
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/funding"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default guard settings
+const (
+	DefaultCheckInterval = time.Second * 15
+	// DefaultLead is how long before funding positions are evaluated
+	DefaultLead = time.Minute * 2
+	// DefaultDelay is how long after funding positions are restored, leaving
+	// time for the exchange to settle funding
+	DefaultDelay = time.Minute
+	// DefaultMinRate is the smallest funding rate acted upon
+	DefaultMinRate = 0.0005
+	// DefaultFeeRate is the taker fee paid per trade as a fraction of
+	// notional
+	DefaultFeeRate = 0.00075
+	// DefaultSlippageBps is the slippage in basis points expected per trade
+	DefaultSlippageBps = 2
+)
+
+// State defines the stage of an action
+type State string
+
+// Action states
+const (
+	// Closed positions are reopened once funding has passed
+	Closed State = "CLOSED"
+	// Hedged positions have their hedge closed once funding has passed
+	Hedged   State = "HEDGED"
+	Reopened State = "REOPENED"
+	Unhedged State = "UNHEDGED"
+)
+
+// Event types emitted by the guard
+const (
+	Closing   = "FUNDING_POSITION_CLOSED"
+	Hedging   = "FUNDING_POSITION_HEDGED"
+	Restoring = "FUNDING_POSITION_RESTORED"
+	Failed    = "FUNDING_GUARD_FAILED"
+)
+
+var (
+	errNoVenues        = errors.New("no venues supplied")
+	errNoExchanges     = errors.New("no exchange lookup supplied")
+	errInvalidConfig   = errors.New("lead, delay, minimum rate, fee rate and slippage cannot be negative")
+	errInvalidHedge    = errors.New("hedges require an exchange, instrument, hedge exchange and pair")
+	errVenueNotFound   = errors.New("venue not found")
+	errNotLoaded       = errors.New("exchange not loaded")
+	errNoMarkPrice     = errors.New("position has no mark price to size the hedge")
+	errNotAcknowledged = errors.New("order not acknowledged")
+)
+
+// Position holds an open perpetual swap position
+type Position struct {
+	Exchange   string `json:"exchange"`
+	Instrument string `json:"instrument"`
+	// Size is the position size in contracts, negative when short
+	Size float64 `json:"size"`
+	// Notional is the position value in the quote currency
+	Notional  float64 `json:"notional"`
+	MarkPrice float64 `json:"markPrice"`
+}
+
+// Long returns whether the position is long
+func (p *Position) Long() bool {
+	return p.Size > 0
+}
+
+// Venue provides the positions and funding rates of an exchange's perpetual
+// swaps and trades them at market
+type Venue interface {
+	funding.Source
+	GetPositions() ([]Position, error)
+	// ClosePosition closes the position, returning the order ID
+	ClosePosition(p *Position) (string, error)
+	// OpenPosition opens a position of the same size and side, returning the
+	// order ID
+	OpenPosition(p *Position) (string, error)
+}
+
+// Hedge defines the instrument a perpetual swap position is hedged on
+// instead of being closed
+type Hedge struct {
+	Exchange      string        `json:"exchange"`
+	Instrument    string        `json:"instrument"`
+	HedgeExchange string        `json:"hedgeExchange"`
+	Pair          currency.Pair `json:"pair"`
+	// FeeRate overrides the taker fee rate of the hedge instrument
+	FeeRate float64 `json:"feeRate,omitempty"`
+}
+
+// Config defines when the guard acts
+type Config struct {
+	Lead    time.Duration
+	Delay   time.Duration
+	MinRate float64
+	// FeeRate and SlippageBps estimate the cost of each market trade
+	FeeRate     float64
+	SlippageBps float64
+	Hedges      []Hedge
+}
+
+// Decision holds the evaluation of a position against the coming funding
+type Decision struct {
+	Position    Position  `json:"position"`
+	Rate        float64   `json:"rate"`
+	FundingTime time.Time `json:"fundingTime"`
+	// FundingCost is the funding the position pays, negative when it
+	// receives funding
+	FundingCost float64 `json:"fundingCost"`
+	// ExecutionCost is the estimated cost of closing and reopening the
+	// position, or of opening and closing its hedge
+	ExecutionCost float64   `json:"executionCost"`
+	Hedge         *Hedge    `json:"hedge,omitempty"`
+	Act           bool      `json:"act"`
+	Evaluated     time.Time `json:"evaluated"`
+}
+
+// Action holds a position closed or hedged over a funding time
+type Action struct {
+	ID       string   `json:"id"`
+	Decision Decision `json:"decision"`
+	State    State    `json:"state"`
+	OrderID  string   `json:"orderID"`
+	// HedgeSide and HedgeAmount are the hedge order placed
+	HedgeSide      exchange.OrderSide `json:"hedgeSide,omitempty"`
+	HedgeAmount    float64            `json:"hedgeAmount,omitempty"`
+	RestoreAt      time.Time          `json:"restoreAt"`
+	RestoreOrderID string             `json:"restoreOrderID,omitempty"`
+	Error          string             `json:"error,omitempty"`
+	Created        time.Time          `json:"created"`
+	Updated        time.Time          `json:"updated"`
+}
+
+// Open returns whether the position is still to be restored
+func (a *Action) Open() bool {
+	return a.State == Closed || a.State == Hedged
+}
+
+// Event defines a position being closed, hedged, restored or failing to be
+type Event struct {
+	Type   string
+	Action Action
+	Detail string
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	p := &e.Action.Decision.Position
+	return fmt.Sprintf("funding guard %s %s %v at rate %v %s: %s", p.Exchange,
+		p.Instrument, p.Size, e.Action.Decision.Rate, e.Type, e.Detail)
+}
+
+// Status holds the latest decision for each position and every action
+type Status struct {
+	Decisions []Decision `json:"decisions"`
+	Actions   []Action   `json:"actions"`
+}
+
+// ExchangeFunc returns a loaded exchange by name, nil when not loaded
+type ExchangeFunc func(name string) exchange.IBotExchange
+
+// Manager closes or hedges positions ahead of unfavourable funding
+type Manager struct {
+	path      string
+	cfg       Config
+	venues    []Venue
+	exchanges ExchangeFunc
+	onEvent   func(Event)
+	actions   map[string]*Action
+	decisions map[string]Decision
+	// next caches each instrument's funding time so rates are only fetched
+	// within the lead, errs the last failure reported per instrument
+	next     map[string]time.Time
+	errs     map[string]string
+	now      func() time.Time
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+	checkMtx sync.Mutex
+}
+
+// New returns a funding guard persisting its actions to path. A missing file
+// starts with no actions, an empty path keeps actions in memory only
+func New(path string, cfg Config, venues []Venue, exchanges ExchangeFunc, onEvent func(Event)) (*Manager, error) {
+	if len(venues) == 0 {
+		return nil, errNoVenues
+	}
+	if exchanges == nil {
+		return nil, errNoExchanges
+	}
+	if cfg.Lead < 0 || cfg.Delay < 0 || cfg.MinRate < 0 || cfg.FeeRate < 0 || cfg.SlippageBps < 0 {
+		return nil, errInvalidConfig
+	}
+	for i := range cfg.Hedges {
+		h := &cfg.Hedges[i]
+		if h.Exchange == "" || h.Instrument == "" || h.HedgeExchange == "" || h.Pair.IsEmpty() || h.FeeRate < 0 {
+			return nil, errInvalidHedge
+		}
+	}
+	if cfg.Lead == 0 {
+		cfg.Lead = DefaultLead
+	}
+	if cfg.Delay == 0 {
+		cfg.Delay = DefaultDelay
+	}
+	if cfg.MinRate == 0 {
+		cfg.MinRate = DefaultMinRate
+	}
+	if cfg.FeeRate == 0 {
+		cfg.FeeRate = DefaultFeeRate
+	}
+	if cfg.SlippageBps == 0 {
+		cfg.SlippageBps = DefaultSlippageBps
+	}
+
+	m := &Manager{
+		path:      path,
+		cfg:       cfg,
+		venues:    venues,
+		exchanges: exchanges,
+		onEvent:   onEvent,
+		actions:   make(map[string]*Action),
+		decisions: make(map[string]Decision),
+		next:      make(map[string]time.Time),
+		errs:      make(map[string]string),
+		now:       time.Now,
+	}
+	if path == "" {
+		return m, nil
+	}
+
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, err
+	}
+	var actions []*Action
+	err = json.Unmarshal(data, &actions)
+	if err != nil {
+		return nil, err
+	}
+	for i := range actions {
+		m.actions[actions[i].ID] = actions[i]
+	}
+	return m, nil
+}
+
+func key(exchangeName, instrument string) string {
+	return strings.ToLower(exchangeName) + " " + strings.ToUpper(instrument)
+}
+
+// hedge returns the hedge configured for a position, nil when it is closed
+// instead
+func (m *Manager) hedge(p *Position) *Hedge {
+	for i := range m.cfg.Hedges {
+		h := &m.cfg.Hedges[i]
+		if strings.EqualFold(h.Exchange, p.Exchange) && strings.EqualFold(h.Instrument, p.Instrument) {
+			return h
+		}
+	}
+	return nil
+}
+
+// Evaluate decides whether a position should be closed or hedged over the
+// funding rate's next funding time. Positive rates are paid by longs
+func (m *Manager) Evaluate(p *Position, r *funding.Rate) Decision {
+	d := Decision{
+		Position:    *p,
+		Rate:        r.Current,
+		FundingTime: r.NextFunding,
+		Hedge:       m.hedge(p),
+		Evaluated:   m.now(),
+	}
+	notional := math.Abs(p.Notional)
+	d.FundingCost = r.Current * notional
+	if !p.Long() {
+		d.FundingCost = -d.FundingCost
+	}
+	feeRate := m.cfg.FeeRate
+	if d.Hedge != nil && d.Hedge.FeeRate > 0 {
+		feeRate = d.Hedge.FeeRate
+	}
+	d.ExecutionCost = 2 * (feeRate + m.cfg.SlippageBps/10000) * notional
+	d.Act = d.FundingCost > 0 && math.Abs(r.Current) >= m.cfg.MinRate &&
+		d.FundingCost > d.ExecutionCost
+	return d
+}
+
+// save writes the actions to the manager's file, the caller must hold the
+// lock
+func (m *Manager) save() error {
+	if m.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(m.list(), "", " ")
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(m.path, data)
+}
+
+func (m *Manager) list() []Action {
+	actions := make([]Action, 0, len(m.actions))
+	for _, a := range m.actions {
+		actions = append(actions, *a)
+	}
+	sort.Slice(actions, func(i, j int) bool {
+		return actions[i].Created.Before(actions[j].Created)
+	})
+	return actions
+}
+
+// store records the action and persists the manager's actions
+func (m *Manager) store(a *Action) {
+	a.Updated = m.now()
+	stored := *a
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.actions[a.ID] = &stored
+	if err := m.save(); err != nil {
+		log.Errorf("Funding guard failed to save actions: %s", err)
+	}
+}
+
+// List returns every action, oldest first
+func (m *Manager) List() []Action {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.list()
+}
+
+// GetStatus returns the latest decision for each position evaluated and
+// every action taken
+func (m *Manager) GetStatus() Status {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	s := Status{Actions: m.list()}
+	for _, d := range m.decisions {
+		s.Decisions = append(s.Decisions, d)
+	}
+	sort.Slice(s.Decisions, func(i, j int) bool {
+		return key(s.Decisions[i].Position.Exchange, s.Decisions[i].Position.Instrument) <
+			key(s.Decisions[j].Position.Exchange, s.Decisions[j].Position.Instrument)
+	})
+	return s
+}
+
+func (m *Manager) venue(name string) Venue {
+	for _, v := range m.venues {
+		if strings.EqualFold(v.GetName(), name) {
+			return v
+		}
+	}
+	return nil
+}
+
+func (m *Manager) emit(e Event) {
+	log.Debugln(e.String())
+	if m.onEvent != nil {
+		m.onEvent(e)
+	}
+}
+
+// fail reports a failure, repeats of the last failure of the instrument are
+// only logged
+func (m *Manager) fail(a *Action, err error) {
+	k := key(a.Decision.Position.Exchange, a.Decision.Position.Instrument)
+	if m.errs[k] == err.Error() {
+		log.Debugf("Funding guard %s: %s", k, err)
+		return
+	}
+	m.errs[k] = err.Error()
+	m.emit(Event{Type: Failed, Action: *a, Detail: err.Error()})
+}
+
+// Check restores positions whose funding has passed, then evaluates the
+// positions of every venue due funding within the lead
+func (m *Manager) Check() {
+	m.checkMtx.Lock()
+	defer m.checkMtx.Unlock()
+
+	guarded := make(map[string]bool)
+	for _, a := range m.List() {
+		if !a.Open() {
+			continue
+		}
+		k := key(a.Decision.Position.Exchange, a.Decision.Position.Instrument)
+		if m.now().Before(a.RestoreAt) {
+			guarded[k] = true
+			continue
+		}
+		prev := a
+		err := m.restore(&a)
+		if a != prev {
+			m.store(&a)
+		}
+		if err != nil {
+			guarded[k] = true
+			m.fail(&a, err)
+			continue
+		}
+		delete(m.errs, k)
+		m.emit(Event{Type: Restoring, Action: a, Detail: "restored with order " + a.RestoreOrderID})
+	}
+
+	for _, v := range m.venues {
+		positions, err := v.GetPositions()
+		if err != nil {
+			log.Warnf("Funding guard failed to get %s positions: %s", v.GetName(), err)
+			continue
+		}
+		for i := range positions {
+			k := key(v.GetName(), positions[i].Instrument)
+			if !guarded[k] {
+				m.checkPosition(v, &positions[i])
+			}
+		}
+	}
+}
+
+// checkPosition closes or hedges the position when it is due to pay costly
+// funding within the lead
+func (m *Manager) checkPosition(v Venue, p *Position) {
+	k := key(v.GetName(), p.Instrument)
+	now := m.now()
+	if next, ok := m.next[k]; ok && now.Before(next.Add(-m.cfg.Lead)) {
+		return
+	}
+	r, err := v.GetFundingRate(p.Instrument)
+	if err != nil {
+		m.fail(&Action{Decision: Decision{Position: *p}}, err)
+		return
+	}
+	m.next[k] = r.NextFunding
+	if !r.NextFunding.After(now) || r.NextFunding.Sub(now) > m.cfg.Lead {
+		return
+	}
+
+	d := m.Evaluate(p, r)
+	m.mtx.Lock()
+	m.decisions[k] = d
+	m.mtx.Unlock()
+	if !d.Act {
+		return
+	}
+
+	id, err := common.GetRandomSalt(nil, 8)
+	if err != nil {
+		log.Errorf("Funding guard failed to generate action ID: %s", err)
+		return
+	}
+	a := Action{
+		ID:        common.HexEncodeToString(id),
+		Decision:  d,
+		RestoreAt: d.FundingTime.Add(m.cfg.Delay),
+		Created:   now,
+	}
+	detail := fmt.Sprintf("paying %v funding against %v estimated execution cost", d.FundingCost, d.ExecutionCost)
+	if d.Hedge == nil {
+		a.OrderID, err = v.ClosePosition(p)
+		if err != nil {
+			m.fail(&a, fmt.Errorf("close position: %s", err))
+			return
+		}
+		a.State = Closed
+		m.store(&a)
+		delete(m.errs, k)
+		m.emit(Event{Type: Closing, Action: a, Detail: detail})
+		return
+	}
+
+	if err = m.placeHedge(&a); err != nil {
+		m.fail(&a, fmt.Errorf("hedge on %s %s: %s", d.Hedge.HedgeExchange, d.Hedge.Pair, err))
+		return
+	}
+	a.State = Hedged
+	m.store(&a)
+	delete(m.errs, k)
+	m.emit(Event{Type: Hedging, Action: a, Detail: detail})
+}
+
+// placeHedge trades the hedge instrument at market against the position,
+// the hedge amount being the position's notional at its mark price
+func (m *Manager) placeHedge(a *Action) error {
+	h := a.Decision.Hedge
+	p := &a.Decision.Position
+	if p.MarkPrice <= 0 {
+		return errNoMarkPrice
+	}
+	exch := m.exchanges(h.HedgeExchange)
+	if exch == nil {
+		return errNotLoaded
+	}
+	side := exchange.SellOrderSide
+	if !p.Long() {
+		side = exchange.BuyOrderSide
+	}
+	amount := math.Abs(p.Notional) / p.MarkPrice
+	resp, err := exch.SubmitOrder(h.Pair, side, exchange.MarketOrderType, amount, 0, a.ID)
+	if err == nil && resp.OrderID == "" {
+		err = errNotAcknowledged
+	}
+	if err != nil {
+		return err
+	}
+	a.OrderID = resp.OrderID
+	a.HedgeSide = side
+	a.HedgeAmount = amount
+	return nil
+}
+
+// restore reopens a closed position or closes a position's hedge
+func (m *Manager) restore(a *Action) error {
+	p := &a.Decision.Position
+	if a.State == Closed {
+		v := m.venue(p.Exchange)
+		if v == nil {
+			a.Error = errVenueNotFound.Error()
+			return fmt.Errorf("reopen position: %s %v", p.Exchange, errVenueNotFound)
+		}
+		id, err := v.OpenPosition(p)
+		if err != nil {
+			a.Error = err.Error()
+			return fmt.Errorf("reopen position: %s", err)
+		}
+		a.RestoreOrderID = id
+		a.State = Reopened
+		a.Error = ""
+		return nil
+	}
+
+	h := a.Decision.Hedge
+	exch := m.exchanges(h.HedgeExchange)
+	if exch == nil {
+		a.Error = errNotLoaded.Error()
+		return fmt.Errorf("close hedge: %s %v", h.HedgeExchange, errNotLoaded)
+	}
+	side := exchange.BuyOrderSide
+	if a.HedgeSide == exchange.BuyOrderSide {
+		side = exchange.SellOrderSide
+	}
+	resp, err := exch.SubmitOrder(h.Pair, side, exchange.MarketOrderType, a.HedgeAmount, 0, a.ID+"-r")
+	if err == nil && resp.OrderID == "" {
+		err = errNotAcknowledged
+	}
+	if err != nil {
+		a.Error = err.Error()
+		return fmt.Errorf("close hedge: %s", err)
+	}
+	a.RestoreOrderID = resp.OrderID
+	a.State = Unhedged
+	a.Error = ""
+	return nil
+}
+
+// Start checks positions at the interval until stopped
+func (m *Manager) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			m.Check()
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the manager
+func (m *Manager) Stop() {
+	m.mtx.Lock()
+	if m.shutdown == nil {
+		m.mtx.Unlock()
+		return
+	}
+	close(m.shutdown)
+	m.shutdown = nil
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
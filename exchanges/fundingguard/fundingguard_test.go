@@ -0,0 +1,254 @@
+package fundingguard
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/funding"
+)
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+type testVenue struct {
+	positions []Position
+	rate      funding.Rate
+	rates     int
+	orders    []string
+	fail      bool
+}
+
+func (v *testVenue) GetName() string { return "Bitmex" }
+
+func (v *testVenue) GetFundingRate(instrument string) (*funding.Rate, error) {
+	v.rates++
+	r := v.rate
+	return &r, nil
+}
+
+func (v *testVenue) GetPositions() ([]Position, error) {
+	return v.positions, nil
+}
+
+func (v *testVenue) ClosePosition(p *Position) (string, error) {
+	if v.fail {
+		return "", errors.New("system overloaded")
+	}
+	v.positions = nil
+	v.orders = append(v.orders, fmt.Sprintf("close %v", p.Size))
+	return fmt.Sprintf("%d", len(v.orders)), nil
+}
+
+func (v *testVenue) OpenPosition(p *Position) (string, error) {
+	if v.fail {
+		return "", errors.New("system overloaded")
+	}
+	v.positions = []Position{*p}
+	v.orders = append(v.orders, fmt.Sprintf("open %v", p.Size))
+	return fmt.Sprintf("%d", len(v.orders)), nil
+}
+
+type testExchange struct {
+	exchange.IBotExchange
+	orders []exchange.OrderDetail
+}
+
+func (t *testExchange) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.orders = append(t.orders, exchange.OrderDetail{
+		CurrencyPair: p,
+		OrderSide:    side,
+		OrderType:    orderType,
+		Amount:       amount,
+	})
+	return exchange.SubmitOrderResponse{OrderID: fmt.Sprintf("%d", len(t.orders)), IsOrderPlaced: true}, nil
+}
+
+func testPosition(size float64) Position {
+	return Position{Exchange: "Bitmex", Instrument: "XBTUSD", Size: size, Notional: 100000, MarkPrice: 10000}
+}
+
+func newTestManager(t *testing.T, path string, cfg Config, v *testVenue, exch *testExchange, now *time.Time, events *[]Event) *Manager {
+	m, err := New(path, cfg, []Venue{v},
+		func(name string) exchange.IBotExchange {
+			if name == "Binance" {
+				return exch
+			}
+			return nil
+		},
+		func(e Event) { *events = append(*events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.now = func() time.Time { return *now }
+	return m
+}
+
+func TestNew(t *testing.T) {
+	v := &testVenue{}
+	exchanges := func(string) exchange.IBotExchange { return nil }
+	if _, err := New("", Config{}, nil, exchanges, nil); err != errNoVenues {
+		t.Error("Test Failed - New() expected no venues error", err)
+	}
+	if _, err := New("", Config{}, []Venue{v}, nil, nil); err != errNoExchanges {
+		t.Error("Test Failed - New() expected no exchanges error", err)
+	}
+	if _, err := New("", Config{MinRate: -1}, []Venue{v}, exchanges, nil); err != errInvalidConfig {
+		t.Error("Test Failed - New() expected invalid config error", err)
+	}
+	_, err := New("", Config{Hedges: []Hedge{{Exchange: "Bitmex", Instrument: "XBTUSD"}}}, []Venue{v}, exchanges, nil)
+	if err != errInvalidHedge {
+		t.Error("Test Failed - New() expected invalid hedge error", err)
+	}
+	m, err := New("", Config{}, []Venue{v}, exchanges, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if m.cfg.Lead != DefaultLead || m.cfg.MinRate != DefaultMinRate || m.cfg.FeeRate != DefaultFeeRate {
+		t.Errorf("Test Failed - New() expected defaults %+v", m.cfg)
+	}
+}
+
+func TestEvaluate(t *testing.T) {
+	m, err := New("", Config{}, []Venue{&testVenue{}}, func(string) exchange.IBotExchange { return nil }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	r := &funding.Rate{Current: 0.002}
+
+	// Longs pay positive funding of 200 against a round trip cost of 190
+	long := testPosition(100000)
+	d := m.Evaluate(&long, r)
+	if !closeTo(d.FundingCost, 200) || !closeTo(d.ExecutionCost, 190) || !d.Act {
+		t.Errorf("Test Failed - Evaluate() expected long to act %+v", d)
+	}
+	short := testPosition(-100000)
+	if d = m.Evaluate(&short, r); !closeTo(d.FundingCost, -200) || d.Act {
+		t.Errorf("Test Failed - Evaluate() expected short receiving funding to hold %+v", d)
+	}
+	r.Current = -0.002
+	if d = m.Evaluate(&short, r); !closeTo(d.FundingCost, 200) || !d.Act {
+		t.Errorf("Test Failed - Evaluate() expected short to act %+v", d)
+	}
+
+	// Funding cheaper than execution or below the minimum rate is paid
+	r.Current = 0.0015
+	if d = m.Evaluate(&long, r); d.Act {
+		t.Errorf("Test Failed - Evaluate() expected execution cost to exceed funding %+v", d)
+	}
+	m.cfg.FeeRate, m.cfg.SlippageBps, m.cfg.MinRate = 0.0001, 0.1, 0.002
+	if d = m.Evaluate(&long, r); d.Act {
+		t.Errorf("Test Failed - Evaluate() expected rate below minimum to hold %+v", d)
+	}
+}
+
+func TestClose(t *testing.T) {
+	dir, err := ioutil.TempDir("", "fundingguard")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fundingguard.json")
+
+	now := time.Date(2019, 7, 1, 3, 0, 0, 0, time.UTC)
+	next := time.Date(2019, 7, 1, 4, 0, 0, 0, time.UTC)
+	v := &testVenue{
+		positions: []Position{testPosition(100000)},
+		rate:      funding.Rate{Current: 0.003, NextFunding: next},
+	}
+	var events []Event
+	m := newTestManager(t, path, Config{}, v, nil, &now, &events)
+
+	// Positions are not evaluated, nor rates refetched, until the lead
+	m.Check()
+	m.Check()
+	if v.rates != 1 || len(v.orders) != 0 || len(m.GetStatus().Decisions) != 0 {
+		t.Fatalf("Test Failed - Check() acted outside the lead %v %v", v.rates, v.orders)
+	}
+
+	now = next.Add(-time.Minute)
+	v.fail = true
+	m.Check()
+	m.Check()
+	if len(events) != 1 || events[0].Type != Failed || len(m.List()) != 0 {
+		t.Fatalf("Test Failed - Check() expected a single failure event %+v", events)
+	}
+	v.fail = false
+	m.Check()
+	actions := m.List()
+	if len(v.orders) != 1 || v.orders[0] != "close 100000" || len(actions) != 1 ||
+		actions[0].State != Closed || !actions[0].RestoreAt.Equal(next.Add(DefaultDelay)) {
+		t.Fatalf("Test Failed - Check() expected position closed %v %+v", v.orders, actions)
+	}
+	if len(events) != 2 || events[1].Type != Closing {
+		t.Errorf("Test Failed - Check() expected close event %+v", events)
+	}
+
+	// Closed positions are reopened after funding, including after a restart
+	now = next.Add(time.Second * 30)
+	m = newTestManager(t, path, Config{}, v, nil, &now, &events)
+	m.Check()
+	if len(v.orders) != 1 {
+		t.Fatalf("Test Failed - Check() reopened before the delay %v", v.orders)
+	}
+	now = next.Add(DefaultDelay)
+	v.rate.NextFunding = next.Add(time.Hour * 8)
+	m.Check()
+	actions = m.List()
+	if len(v.orders) != 2 || v.orders[1] != "open 100000" || actions[0].State != Reopened ||
+		actions[0].RestoreOrderID != "2" {
+		t.Fatalf("Test Failed - Check() expected position reopened %v %+v", v.orders, actions)
+	}
+	if events[len(events)-1].Type != Restoring {
+		t.Errorf("Test Failed - Check() expected restore event %+v", events)
+	}
+}
+
+func TestHedge(t *testing.T) {
+	now := time.Date(2019, 7, 1, 3, 59, 0, 0, time.UTC)
+	next := time.Date(2019, 7, 1, 4, 0, 0, 0, time.UTC)
+	v := &testVenue{
+		positions: []Position{testPosition(-100000)},
+		rate:      funding.Rate{Current: -0.003, NextFunding: next},
+	}
+	exch := &testExchange{}
+	var events []Event
+	m := newTestManager(t, "", Config{Hedges: []Hedge{{
+		Exchange:      "bitmex",
+		Instrument:    "xbtusd",
+		HedgeExchange: "Binance",
+		Pair:          currency.NewPairWithDelimiter("BTC", "USDT", "-"),
+		FeeRate:       0.001,
+	}}}, v, exch, &now, &events)
+
+	m.Check()
+	// Hedged positions stay open and are not evaluated again
+	m.Check()
+	actions := m.List()
+	if len(exch.orders) != 1 || exch.orders[0].OrderSide != exchange.BuyOrderSide ||
+		exch.orders[0].Amount != 10 || len(v.orders) != 0 {
+		t.Fatalf("Test Failed - Check() expected short hedged with a buy of 10 %+v", exch.orders)
+	}
+	if len(actions) != 1 || actions[0].State != Hedged || !closeTo(actions[0].Decision.ExecutionCost, 240) {
+		t.Fatalf("Test Failed - Check() unexpected hedge action %+v", actions)
+	}
+
+	now = next.Add(DefaultDelay)
+	m.Check()
+	actions = m.List()
+	if len(exch.orders) != 2 || exch.orders[1].OrderSide != exchange.SellOrderSide ||
+		exch.orders[1].Amount != 10 || actions[0].State != Unhedged {
+		t.Errorf("Test Failed - Check() expected hedge closed %+v %+v", exch.orders, actions)
+	}
+	if len(events) != 2 || events[0].Type != Hedging || events[1].Type != Restoring {
+		t.Errorf("Test Failed - Check() unexpected events %+v", events)
+	}
+}
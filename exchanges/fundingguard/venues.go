@@ -0,0 +1,200 @@
+package fundingguard
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/funding"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okgroup"
+)
+
+const (
+	// bitmexPerpetual is the BitMEX instrument type of perpetual contracts
+	bitmexPerpetual = "FFWCSX"
+
+	okexOpenLong   = 1
+	okexOpenShort  = 2
+	okexCloseLong  = 3
+	okexCloseShort = 4
+)
+
+var errOrderRejected = errors.New("order rejected")
+
+// BitmexVenue closes and reopens BitMEX perpetual swap positions
+type BitmexVenue struct {
+	funding.BitmexSource
+	// perpetuals caches whether each symbol is a perpetual, positions in
+	// dated futures pay no funding
+	perpetuals map[string]bool
+}
+
+// GetPositions returns the open BitMEX perpetual swap positions
+func (b *BitmexVenue) GetPositions() ([]Position, error) {
+	positions, err := b.Exchange.GetPositions(bitmex.PositionGetParams{
+		Filter: `{"isOpen":true}`,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	var resp []Position
+	for i := range positions {
+		p := &positions[i]
+		if !p.IsOpen || p.CurrentQty == 0 {
+			continue
+		}
+		perpetual, err := b.perpetual(p.Symbol)
+		if err != nil {
+			return nil, err
+		}
+		if !perpetual {
+			continue
+		}
+		resp = append(resp, Position{
+			Exchange:   b.GetName(),
+			Instrument: p.Symbol,
+			Size:       float64(p.CurrentQty),
+			Notional:   math.Abs(p.ForeignNotional),
+			MarkPrice:  p.MarkPrice,
+		})
+	}
+	return resp, nil
+}
+
+// perpetual returns whether the symbol is a perpetual, refreshing the active
+// instruments when the symbol has not been seen
+func (b *BitmexVenue) perpetual(symbol string) (bool, error) {
+	if perpetual, ok := b.perpetuals[symbol]; ok {
+		return perpetual, nil
+	}
+	instruments, err := b.Exchange.GetActiveInstruments(&bitmex.GenericRequestParams{})
+	if err != nil {
+		return false, err
+	}
+	b.perpetuals = make(map[string]bool, len(instruments)+1)
+	for i := range instruments {
+		b.perpetuals[instruments[i].Symbol] = instruments[i].Typ == bitmexPerpetual
+	}
+	return b.perpetuals[symbol], nil
+}
+
+// ClosePosition closes the position with a reduce only market order
+func (b *BitmexVenue) ClosePosition(p *Position) (string, error) {
+	side := "Sell"
+	if !p.Long() {
+		side = "Buy"
+	}
+	return b.trade(p.Instrument, side, math.Abs(p.Size), "ReduceOnly")
+}
+
+// OpenPosition opens the position with a market order
+func (b *BitmexVenue) OpenPosition(p *Position) (string, error) {
+	side := "Buy"
+	if !p.Long() {
+		side = "Sell"
+	}
+	return b.trade(p.Instrument, side, math.Abs(p.Size), "")
+}
+
+func (b *BitmexVenue) trade(symbol, side string, qty float64, execInst string) (string, error) {
+	resp, err := b.Exchange.CreateOrder(&bitmex.OrderNewParams{
+		Symbol:   symbol,
+		Side:     side,
+		OrderQty: qty,
+		OrdType:  "Market",
+		ExecInst: execInst,
+	})
+	if err != nil {
+		return "", err
+	}
+	if resp.OrderID == "" {
+		return "", errNotAcknowledged
+	}
+	return resp.OrderID, nil
+}
+
+// OKEXVenue closes and reopens OKEX perpetual swap positions
+type OKEXVenue struct {
+	funding.OKEXSource
+}
+
+// GetPositions returns the open OKEX perpetual swap positions, valued at
+// their contract size
+func (o *OKEXVenue) GetPositions() ([]Position, error) {
+	swaps, err := o.Exchange.GetSwapPostions()
+	if err != nil {
+		return nil, err
+	}
+	contracts, err := o.Exchange.GetSwapContractInformation()
+	if err != nil {
+		return nil, err
+	}
+	contractVal := make(map[string]float64, len(contracts))
+	for i := range contracts {
+		contractVal[contracts[i].InstrumentID] = contracts[i].ContractVal
+	}
+
+	var resp []Position
+	for i := range swaps {
+		for j := range swaps[i].Holding {
+			h := &swaps[i].Holding[j]
+			size, _ := strconv.ParseFloat(h.Position, 64)
+			if size == 0 {
+				continue
+			}
+			if h.Side == "short" {
+				size = -size
+			}
+			mark, err := o.Exchange.GetSwapMarkPrice(h.InstrumentID)
+			if err != nil {
+				return nil, err
+			}
+			p := Position{
+				Exchange:   o.GetName(),
+				Instrument: h.InstrumentID,
+				Size:       size,
+				Notional:   math.Abs(size) * contractVal[h.InstrumentID],
+			}
+			p.MarkPrice, _ = strconv.ParseFloat(mark.MarkPrice, 64)
+			resp = append(resp, p)
+		}
+	}
+	return resp, nil
+}
+
+// ClosePosition closes the position at the best counter party price
+func (o *OKEXVenue) ClosePosition(p *Position) (string, error) {
+	orderType := int64(okexCloseLong)
+	if !p.Long() {
+		orderType = okexCloseShort
+	}
+	return o.trade(p, orderType)
+}
+
+// OpenPosition opens the position at the best counter party price
+func (o *OKEXVenue) OpenPosition(p *Position) (string, error) {
+	orderType := int64(okexOpenLong)
+	if !p.Long() {
+		orderType = okexOpenShort
+	}
+	return o.trade(p, orderType)
+}
+
+func (o *OKEXVenue) trade(p *Position, orderType int64) (string, error) {
+	resp, err := o.Exchange.PlaceSwapOrder(okgroup.PlaceSwapOrderRequest{
+		InstrumentID: p.Instrument,
+		Size:         math.Abs(p.Size),
+		Type:         orderType,
+		MatchPrice:   1,
+	})
+	if err != nil {
+		return "", err
+	}
+	if !resp.Result {
+		return "", fmt.Errorf("%v %d: %s", errOrderRejected, resp.ErrorCode, resp.ErrorMessage)
+	}
+	return resp.OrderID, nil
+}
@@ -324,7 +324,8 @@ func (g *Gateio) GetSpotKline(arg KlinesRequestParams) ([]*KLineResponse, error)
 		if err != nil {
 			return nil, fmt.Errorf("cannot parse Kline.OpenTime. Err: %s", err)
 		}
-		_vol, err := common.FloatFromString(k[1])
+		// Intervals without trades can report an empty volume
+		_vol, err := common.FloatFromStringFormat(k[1], common.NumberEmptyAsZero)
 		if err != nil {
 			return nil, fmt.Errorf("cannot parse Kline.Volume. Err: %s", err)
 		}
@@ -486,6 +487,18 @@ func (g *Gateio) GenerateSignature(message string) []byte {
 	return common.GetHMAC(common.HashSHA512, []byte(message), []byte(g.APISecret))
 }
 
+// mutating returns whether the endpoint changes exchange state. Every
+// endpoint is a POST so they are classified by name
+func mutating(endpoint string) bool {
+	switch endpoint {
+	case gateioOrder + "/" + string(SpotNewOrderRequestParamsTypeBuy),
+		gateioOrder + "/" + string(SpotNewOrderRequestParamsTypeSell),
+		gateioCancelOrder, gateioCancelAllOrders, gateioWithdraw:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends authenticated requests to the Gateio API
 // To use this you must setup an APIKey and APISecret from the exchange
 func (g *Gateio) SendAuthenticatedHTTPRequest(method, endpoint, param string, result interface{}) error {
@@ -493,6 +506,9 @@ func (g *Gateio) SendAuthenticatedHTTPRequest(method, endpoint, param string, re
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet,
 			g.Name)
 	}
+	if g.DryRunRequest(mutating(endpoint), endpoint, param) {
+		return nil
+	}
 
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
@@ -506,12 +506,26 @@ func (g *Gemini) SendHTTPRequest(path string, result interface{}) error {
 	return g.SendPayload(http.MethodGet, path, nil, nil, result, false, false, g.Verbose, g.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Every
+// endpoint is a POST so they are classified by name, withdrawals are made
+// under a path holding the currency
+func mutating(path string) bool {
+	switch path {
+	case geminiOrderNew, geminiOrderCancel, geminiOrderCancelSession, geminiOrderCancelAll:
+		return true
+	}
+	return strings.HasPrefix(path, geminiWithdraw)
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP request to the
 // exchange and returns an error
 func (g *Gemini) SendAuthenticatedHTTPRequest(method, path string, params map[string]interface{}, result interface{}) (err error) {
 	if !g.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, g.Name)
 	}
+	if g.DryRunRequest(mutating(path), path, params) {
+		return nil
+	}
 
 	headers := make(map[string]string)
 	req := make(map[string]interface{})
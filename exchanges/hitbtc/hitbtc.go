@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
@@ -617,12 +618,30 @@ func (h *HitBTC) SendHTTPRequest(path string, result interface{}) error {
 	return h.SendPayload(http.MethodGet, path, nil, nil, result, false, false, h.Verbose, h.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Trade history
+// is read through a POST request so endpoints are classified by name, orders
+// are listed, placed and cancelled through the same path so it is matched by
+// method
+func mutating(method, endpoint string) bool {
+	switch endpoint {
+	case orderMove, apiV2CryptoWithdraw, transferBalance:
+		return true
+	}
+	if endpoint == orderBuy || strings.HasPrefix(endpoint, orderBuy+"/") {
+		return method != http.MethodGet
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated http request
 func (h *HitBTC) SendAuthenticatedHTTPRequest(method, endpoint string, values url.Values, result interface{}) error {
 	if !h.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet,
 			h.Name)
 	}
+	if h.DryRunRequest(mutating(method, endpoint), method+" "+endpoint, values) {
+		return nil
+	}
 	headers := make(map[string]string)
 	headers["Authorization"] = "Basic " + common.Base64Encode([]byte(h.APIKey+":"+h.APISecret))
 
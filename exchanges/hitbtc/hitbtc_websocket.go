@@ -113,14 +113,17 @@ func (h *HitBTC) handleSubscriptionUpdates(resp wshandler.WebsocketResponse, ini
 			return
 		}
 		h.Websocket.DataHandler <- wshandler.TickerData{
-			Exchange:  h.GetName(),
-			AssetType: "SPOT",
-			Pair:      currency.NewPairFromString(ticker.Params.Symbol),
-			Quantity:  ticker.Params.Volume,
-			Timestamp: ts,
-			OpenPrice: ticker.Params.Open,
-			HighPrice: ticker.Params.High,
-			LowPrice:  ticker.Params.Low,
+			Exchange:   h.GetName(),
+			AssetType:  "SPOT",
+			Pair:       currency.NewPairFromString(ticker.Params.Symbol),
+			Quantity:   ticker.Params.Volume,
+			Timestamp:  ts,
+			ClosePrice: ticker.Params.Last,
+			OpenPrice:  ticker.Params.Open,
+			HighPrice:  ticker.Params.High,
+			LowPrice:   ticker.Params.Low,
+			BidPrice:   ticker.Params.Bid,
+			AskPrice:   ticker.Params.Ask,
 		}
 	case "snapshotOrderbook":
 		var obSnapshot WsOrderbook
@@ -0,0 +1,350 @@
+// Package httprecorder records full HTTP request and response pairs,
+// including signatures, for exchanges with HTTP debugging enabled. API keys,
+// secrets and passphrases are redacted before anything is written, and the
+// recorded stream can be loaded as fixtures and replayed through a HTTP client
+// to reproduce signature mismatches and write mocked tests
+package httprecorder
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Redacted replaces secret values in recorded requests
+const Redacted = "[REDACTED]"
+
+// minSecretLength prevents short values such as client IDs being redacted
+// throughout unrelated data
+const minSecretLength = 8
+
+var (
+	errNilWriter      = errors.New("httprecorder writer is nil")
+	errNilRequest     = errors.New("httprecorder request is nil")
+	errNoFixtureFound = errors.New("httprecorder no recorded response for request")
+)
+
+// sensitiveNames matches header, query and body field names which hold
+// credentials. Signatures are retained as they are needed to debug signature
+// mismatches and cannot be reused once the nonce or timestamp has passed
+var sensitiveNames = regexp.MustCompile(`(?i)(key|secret|passphrase|password|token|authorization|cookie)`)
+
+// jsonField matches string valued JSON fields
+var jsonField = regexp.MustCompile(`"([^"]+)"(\s*:\s*)"([^"]*)"`)
+
+var (
+	secrets   = make(map[string]struct{})
+	secretMtx sync.RWMutex
+)
+
+// RegisterSecrets adds values which are redacted wherever they appear in a
+// recording, such as API secrets and PEM keys
+func RegisterSecrets(values ...string) {
+	secretMtx.Lock()
+	defer secretMtx.Unlock()
+	for i := range values {
+		if len(values[i]) >= minSecretLength {
+			secrets[values[i]] = struct{}{}
+		}
+	}
+}
+
+// Redact replaces registered secrets in the string
+func Redact(s string) string {
+	secretMtx.RLock()
+	defer secretMtx.RUnlock()
+	for v := range secrets {
+		s = strings.Replace(s, v, Redacted, -1)
+	}
+	return s
+}
+
+// redactValues redacts sensitive parameters of an encoded query or form body
+func redactValues(s string) string {
+	vals, err := url.ParseQuery(s)
+	if err != nil || len(vals) == 0 {
+		return s
+	}
+	var changed bool
+	for k := range vals {
+		if sensitiveNames.MatchString(k) {
+			vals[k] = []string{Redacted}
+			changed = true
+		}
+	}
+	if !changed {
+		return s
+	}
+	return vals.Encode()
+}
+
+// redactBody redacts sensitive JSON fields or form parameters of a body
+func redactBody(body string) string {
+	body = Redact(body)
+	trimmed := strings.TrimSpace(body)
+	if strings.HasPrefix(trimmed, "{") || strings.HasPrefix(trimmed, "[") {
+		return jsonField.ReplaceAllStringFunc(body, func(m string) string {
+			parts := jsonField.FindStringSubmatch(m)
+			if !sensitiveNames.MatchString(parts[1]) {
+				return m
+			}
+			return `"` + parts[1] + `"` + parts[2] + `"` + Redacted + `"`
+		})
+	}
+	if strings.Contains(trimmed, "=") && !strings.ContainsAny(trimmed, " \n") {
+		return redactValues(body)
+	}
+	return body
+}
+
+func redactHeaders(h http.Header) map[string]string {
+	if len(h) == 0 {
+		return nil
+	}
+	headers := make(map[string]string, len(h))
+	for k := range h {
+		v := strings.Join(h[k], ",")
+		if sensitiveNames.MatchString(k) {
+			v = Redacted
+		}
+		headers[k] = Redact(v)
+	}
+	return headers
+}
+
+func redactURL(u *url.URL) string {
+	c := *u
+	c.RawQuery = redactValues(c.RawQuery)
+	return Redact(c.String())
+}
+
+// RedactDump redacts credentials from a request or response dump produced by
+// the net/http/httputil package
+func RedactDump(dump []byte) string {
+	lines := strings.Split(string(dump), "\n")
+	for i := 1; i < len(lines); i++ {
+		line := strings.TrimRight(lines[i], "\r")
+		if line == "" {
+			if i+1 < len(lines) {
+				lines[i+1] = redactBody(strings.Join(lines[i+1:], "\n"))
+				lines = lines[:i+2]
+			}
+			break
+		}
+		if kv := strings.SplitN(line, ":", 2); len(kv) == 2 && sensitiveNames.MatchString(kv[0]) {
+			lines[i] = kv[0] + ": " + Redacted
+		}
+	}
+	if len(lines) > 0 {
+		if parts := strings.SplitN(lines[0], "?", 2); len(parts) == 2 {
+			if q := strings.SplitN(parts[1], " ", 2); len(q) == 2 {
+				lines[0] = parts[0] + "?" + redactValues(q[0]) + " " + q[1]
+			}
+		}
+	}
+	return Redact(strings.Join(lines, "\n"))
+}
+
+// Record holds a recorded request and response pair
+type Record struct {
+	Timestamp       time.Time         `json:"timestamp"`
+	Exchange        string            `json:"exchange"`
+	Method          string            `json:"method"`
+	URL             string            `json:"url"`
+	RequestHeaders  map[string]string `json:"requestHeaders,omitempty"`
+	RequestBody     string            `json:"requestBody,omitempty"`
+	StatusCode      int               `json:"statusCode,omitempty"`
+	ResponseHeaders map[string]string `json:"responseHeaders,omitempty"`
+	ResponseBody    string            `json:"responseBody,omitempty"`
+	Duration        time.Duration     `json:"duration"`
+	Error           string            `json:"error,omitempty"`
+}
+
+// Recorder writes redacted request and response pairs as newline delimited
+// JSON records
+type Recorder struct {
+	enc *json.Encoder
+	mtx sync.Mutex
+}
+
+// NewRecorder returns a recorder writing to w
+func NewRecorder(w io.Writer) (*Recorder, error) {
+	if w == nil {
+		return nil, errNilWriter
+	}
+	return &Recorder{enc: json.NewEncoder(w)}, nil
+}
+
+var (
+	active    *Recorder
+	activeMtx sync.RWMutex
+)
+
+// Enable sets the recorder used by the request layer, nil disables recording
+func Enable(r *Recorder) {
+	activeMtx.Lock()
+	active = r
+	activeMtx.Unlock()
+}
+
+// Active returns the enabled recorder, or nil when recording is disabled
+func Active() *Recorder {
+	activeMtx.RLock()
+	defer activeMtx.RUnlock()
+	return active
+}
+
+// RequestBody returns a copy of the request body without consuming it
+func RequestBody(req *http.Request) string {
+	if req == nil || req.GetBody == nil {
+		return ""
+	}
+	b, err := req.GetBody()
+	if err != nil {
+		return ""
+	}
+	defer b.Close()
+	data, err := ioutil.ReadAll(b)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// Record writes a redacted request and response pair. The response and its
+// body are optional so failed requests can be recorded
+func (r *Recorder) Record(exchangeName string, req *http.Request, reqBody string, resp *http.Response, respBody []byte, d time.Duration, reqErr error) error {
+	if req == nil {
+		return errNilRequest
+	}
+	rec := Record{
+		Timestamp:      time.Now(),
+		Exchange:       exchangeName,
+		Method:         req.Method,
+		URL:            redactURL(req.URL),
+		RequestHeaders: redactHeaders(req.Header),
+		RequestBody:    redactBody(reqBody),
+		ResponseBody:   redactBody(string(respBody)),
+		Duration:       d,
+	}
+	if resp != nil {
+		rec.StatusCode = resp.StatusCode
+		rec.ResponseHeaders = redactHeaders(resp.Header)
+	}
+	if reqErr != nil {
+		rec.Error = Redact(reqErr.Error())
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.enc.Encode(&rec)
+}
+
+// LoadFixtures reads records written by a recorder
+func LoadFixtures(rd io.Reader) ([]Record, error) {
+	var records []Record
+	dec := json.NewDecoder(rd)
+	for {
+		var rec Record
+		err := dec.Decode(&rec)
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		records = append(records, rec)
+	}
+}
+
+// Replayer is a HTTP round tripper which serves recorded responses. Requests
+// are matched on method, host and path, as query strings and bodies carry
+// timestamps and signatures which differ on every request. Matching records
+// are served in the order recorded
+type Replayer struct {
+	records []Record
+	used    []bool
+	mtx     sync.Mutex
+}
+
+// NewReplayer returns a replayer serving the records
+func NewReplayer(records []Record) *Replayer {
+	return &Replayer{
+		records: records,
+		used:    make([]bool, len(records)),
+	}
+}
+
+// Client returns a HTTP client which replays recorded responses
+func (r *Replayer) Client() *http.Client {
+	return &http.Client{Transport: r}
+}
+
+func matchKey(method, rawURL string) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return method + " " + rawURL
+	}
+	return method + " " + u.Host + u.Path
+}
+
+// RoundTrip implements the http.RoundTripper interface
+func (r *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	k := matchKey(req.Method, req.URL.String())
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	for i := range r.records {
+		if r.used[i] || matchKey(r.records[i].Method, r.records[i].URL) != k {
+			continue
+		}
+		r.used[i] = true
+		rec := &r.records[i]
+		if rec.Error != "" {
+			return nil, errors.New(rec.Error)
+		}
+		resp := &http.Response{
+			Status:        http.StatusText(rec.StatusCode),
+			StatusCode:    rec.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        make(http.Header),
+			Body:          ioutil.NopCloser(bytes.NewBufferString(rec.ResponseBody)),
+			ContentLength: int64(len(rec.ResponseBody)),
+			Request:       req,
+		}
+		for h, v := range rec.ResponseHeaders {
+			// Bodies are recorded decompressed
+			if strings.EqualFold(h, "Content-Encoding") {
+				continue
+			}
+			resp.Header.Set(h, v)
+		}
+		return resp, nil
+	}
+	return nil, fmt.Errorf("%v %s", errNoFixtureFound, k)
+}
+
+// Remaining returns the match keys of records which have not been replayed
+func (r *Replayer) Remaining() []string {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	var keys []string
+	for i := range r.records {
+		if !r.used[i] {
+			keys = append(keys, matchKey(r.records[i].Method, r.records[i].URL))
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,156 @@
+package httprecorder
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+const (
+	testKey    = "testAPIKey12345"
+	testSecret = "testAPISecret67890"
+)
+
+func testRequest(t *testing.T) *http.Request {
+	body := `{"apiKey":"` + testKey + `","amount":"1.5","passphrase":"hunter22"}`
+	req, err := http.NewRequest(http.MethodPost,
+		"https://api.test.com/v1/order?AccessKeyId="+testKey+"&Signature=abc123&Timestamp=1",
+		strings.NewReader(body))
+	if err != nil {
+		t.Fatal(err)
+	}
+	req.Header.Set("X-API-KEY", testKey)
+	req.Header.Set("X-Sign", "signature")
+	req.Header.Set("Content-Type", "application/json")
+	return req
+}
+
+func TestRedact(t *testing.T) {
+	RegisterSecrets(testSecret, "short")
+	if Redact("signed with "+testSecret) != "signed with "+Redacted {
+		t.Error("Test Failed - Redact() registered secret not redacted")
+	}
+	if Redact("short") != "short" {
+		t.Error("Test Failed - Redact() short value redacted")
+	}
+
+	body := redactBody(`{"secretKey":"abc","price":"100"}`)
+	if strings.Contains(body, "abc") || !strings.Contains(body, `"price":"100"`) {
+		t.Errorf("Test Failed - redactBody() unexpected JSON body %s", body)
+	}
+	body = redactBody("api_key=abc&nonce=1&signature=def")
+	if strings.Contains(body, "abc") || !strings.Contains(body, "signature=def") {
+		t.Errorf("Test Failed - redactBody() unexpected form body %s", body)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	if _, err := NewRecorder(nil); err != errNilWriter {
+		t.Error("Test Failed - NewRecorder() expected nil writer error", err)
+	}
+	var buf bytes.Buffer
+	r, err := NewRecorder(&buf)
+	if err != nil {
+		t.Fatal("Test Failed - NewRecorder() error", err)
+	}
+	if err = r.Record("Test", nil, "", nil, nil, 0, nil); err != errNilRequest {
+		t.Error("Test Failed - Record() expected nil request error", err)
+	}
+
+	req := testRequest(t)
+	reqBody := RequestBody(req)
+	if !strings.Contains(reqBody, testKey) {
+		t.Fatal("Test Failed - RequestBody() body not copied")
+	}
+	resp := &http.Response{StatusCode: http.StatusUnauthorized, Header: http.Header{}}
+	resp.Header.Set("Content-Type", "application/json")
+	err = r.Record("Test", req, reqBody, resp, []byte(`{"error":"invalid signature"}`), time.Millisecond, nil)
+	if err != nil {
+		t.Fatal("Test Failed - Record() error", err)
+	}
+	err = r.Record("Test", req, reqBody, nil, nil, time.Millisecond, errors.New("timeout"))
+	if err != nil {
+		t.Fatal("Test Failed - Record() error", err)
+	}
+
+	out := buf.String()
+	for _, secret := range []string{testKey, "hunter22"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Test Failed - Record() secret %s not redacted", secret)
+		}
+	}
+	for _, kept := range []string{"abc123", "signature", "invalid signature", `\"amount\":\"1.5\"`} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("Test Failed - Record() expected %s to be recorded", kept)
+		}
+	}
+
+	records, err := LoadFixtures(&buf)
+	if err != nil {
+		t.Fatal("Test Failed - LoadFixtures() error", err)
+	}
+	if len(records) != 2 || records[0].StatusCode != http.StatusUnauthorized || records[1].Error != "timeout" {
+		t.Errorf("Test Failed - LoadFixtures() unexpected records %+v", records)
+	}
+	if _, err = LoadFixtures(strings.NewReader("{")); err == nil {
+		t.Error("Test Failed - LoadFixtures() expected error for invalid fixtures")
+	}
+}
+
+func TestReplayer(t *testing.T) {
+	r := NewReplayer([]Record{
+		{Method: http.MethodGet, URL: "https://api.test.com/v1/ticker?symbol=btcusd&nonce=1", StatusCode: 200, ResponseBody: `{"last":1}`},
+		{Method: http.MethodGet, URL: "https://api.test.com/v1/ticker?symbol=btcusd&nonce=2", StatusCode: 200, ResponseBody: `{"last":2}`},
+		{Method: http.MethodPost, URL: "https://api.test.com/v1/order", Error: "connection reset"},
+	})
+	c := r.Client()
+
+	for _, want := range []string{`{"last":1}`, `{"last":2}`} {
+		resp, err := c.Get("https://api.test.com/v1/ticker?symbol=btcusd&nonce=99")
+		if err != nil {
+			t.Fatal("Test Failed - RoundTrip() error", err)
+		}
+		body, _ := ioutil.ReadAll(resp.Body)
+		resp.Body.Close()
+		if string(body) != want {
+			t.Errorf("Test Failed - RoundTrip() expected %s, got %s", want, body)
+		}
+	}
+	if _, err := c.Get("https://api.test.com/v1/ticker"); err == nil {
+		t.Error("Test Failed - RoundTrip() expected no fixture error once replayed")
+	}
+	if remaining := r.Remaining(); len(remaining) != 1 || remaining[0] != "POST api.test.com/v1/order" {
+		t.Errorf("Test Failed - Remaining() unexpected %v", remaining)
+	}
+	if _, err := c.Post("https://api.test.com/v1/order", "application/json", nil); err == nil {
+		t.Error("Test Failed - RoundTrip() expected recorded error")
+	}
+}
+
+func TestRedactDump(t *testing.T) {
+	dump := "POST /v1/order?api_key=abc&nonce=1 HTTP/1.1\r\n" +
+		"Host: api.test.com\r\n" +
+		"X-Mbx-Apikey: abc\r\n" +
+		"X-Signature: def\r\n" +
+		"\r\n" +
+		`{"secret":"ghi","amount":"1"}`
+	out := RedactDump([]byte(dump))
+	for _, secret := range []string{"abc", "ghi"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("Test Failed - RedactDump() %s not redacted in %s", secret, out)
+		}
+	}
+	for _, kept := range []string{"nonce=1", "X-Signature: def", "Host: api.test.com", `"amount":"1"`} {
+		if !strings.Contains(out, kept) {
+			t.Errorf("Test Failed - RedactDump() expected %s in %s", kept, out)
+		}
+	}
+
+	if out = RedactDump([]byte("GET / HTTP/1.1\r\nX-Api-Key: abc\r\n")); strings.Contains(out, "abc") {
+		t.Errorf("Test Failed - RedactDump() key not redacted in dump without body %s", out)
+	}
+}
@@ -10,10 +10,12 @@ import (
 	"errors"
 	"fmt"
 	"io/ioutil"
+	"math"
 	"net/http"
 	"net/url"
 	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
@@ -27,8 +29,9 @@ import (
 )
 
 const (
-	huobiAPIURL     = "https://api.huobi.pro"
-	huobiAPIVersion = "1"
+	huobiAPIURL      = "https://api.huobi.pro"
+	huobiAPIVersion  = "1"
+	huobiAPIVersion2 = "2"
 
 	huobiMarketHistoryKline    = "market/history/kline"
 	huobiMarketDetail          = "market/detail"
@@ -36,6 +39,7 @@ const (
 	huobiMarketDepth           = "market/depth"
 	huobiMarketTrade           = "market/trade"
 	huobiMarketTradeHistory    = "market/history/trade"
+	huobiMarketETP             = "market/etp"
 	huobiSymbols               = "common/symbols"
 	huobiCurrencies            = "common/currencys"
 	huobiTimestamp             = "common/timestamp"
@@ -60,6 +64,20 @@ const (
 	huobiWithdrawCreate        = "dw/withdraw/api/create"
 	huobiWithdrawCancel        = "dw/withdraw-virtual/%s/cancel"
 
+	// v2 endpoints
+	huobiTransactFeeRate    = "reference/transact-fee-rate"
+	huobiFeeDeductionInfo   = "account/user/deduction-info"
+	huobiFeeDeductionSwitch = "account/switch/user/deduction"
+	huobiPointAccount       = "point/account"
+	huobiETPReference       = "etp/reference"
+	huobiETPCreation        = "etp/creation"
+	huobiETPRedemption      = "etp/redemption"
+	huobiETPTransactions    = "etp/transactions"
+	huobiETPTransaction     = "etp/transaction"
+	huobiETPCancel          = "etp/%d/cancel"
+	huobiCurrencyChains     = "reference/currencies"
+	huobiDepositAddress     = "account/deposit/address"
+
 	huobiAuthRate   = 100
 	huobiUnauthRate = 100
 )
@@ -68,10 +86,26 @@ const (
 type HUOBI struct {
 	exchange.Base
 	AccountID                  string
+	FeeDeduction               FeeDeductionMode
 	WebsocketConn              *wshandler.WebsocketConnection
 	AuthenticatedWebsocketConn *wshandler.WebsocketConnection
+
+	// feeRates caches the actual fee rates of each symbol
+	feeRates           map[string]cachedFeeRate
+	feeDeductionSynced FeeDeductionMode
+	feeMtx             sync.Mutex
+}
+
+// cachedFeeRate holds a fee rate and when it was retrieved
+type cachedFeeRate struct {
+	rate    TransactFeeRate
+	updated time.Time
 }
 
+// feeRateCacheDuration is how long the actual fee rate of a symbol is cached
+// for
+const feeRateCacheDuration = time.Minute * 30
+
 // SetDefaults sets default values for the exchange
 func (h *HUOBI) SetDefaults() {
 	h.Name = "Huobi"
@@ -127,7 +161,12 @@ func (h *HUOBI) Setup(exch *config.ExchangeConfig) {
 		h.BaseCurrencies = exch.BaseCurrencies
 		h.AvailablePairs = exch.AvailablePairs
 		h.EnabledPairs = exch.EnabledPairs
-		err := h.SetCurrencyPairFormat()
+		mode, err := ParseFeeDeductionMode(exch.FeeDeduction)
+		if err != nil {
+			log.Fatal(err)
+		}
+		h.FeeDeduction = mode
+		err = h.SetCurrencyPairFormat()
 		if err != nil {
 			log.Fatal(err)
 		}
@@ -349,6 +388,15 @@ func (h *HUOBI) GetSymbols() ([]Symbol, error) {
 	return result.Symbols, err
 }
 
+// TradingRules returns the symbol's price and amount increments derived from
+// its precisions
+func (s *Symbol) TradingRules() exchange.TradingRules {
+	return exchange.TradingRules{
+		PriceStep:  math.Pow10(-s.PricePrecision),
+		AmountStep: math.Pow10(-s.AmountPrecision),
+	}
+}
+
 // GetCurrencies returns a list of currencies supported by Huobi
 func (h *HUOBI) GetCurrencies() ([]string, error) {
 	type response struct {
@@ -366,6 +414,30 @@ func (h *HUOBI) GetCurrencies() ([]string, error) {
 	return result.Currencies, err
 }
 
+// GetCurrencyChains returns the deposit and withdrawal status of each chain a
+// currency can be transferred on. All currencies are returned when the
+// currency is empty
+func (h *HUOBI) GetCurrencyChains(c string) ([]CurrencyChains, error) {
+	type response struct {
+		ResponseV2
+		Data []CurrencyChains `json:"data"`
+	}
+
+	vals := url.Values{}
+	if c != "" {
+		vals.Set("currency", strings.ToLower(c))
+	}
+
+	var result response
+	urlPath := fmt.Sprintf("%s/v%s/%s", h.APIUrl, huobiAPIVersion2, huobiCurrencyChains)
+
+	err := h.SendHTTPRequest(common.EncodeURLValues(urlPath, vals), &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, result.Error()
+}
+
 // GetTimestamp returns the Huobi server time
 func (h *HUOBI) GetTimestamp() (int64, error) {
 	type response struct {
@@ -885,16 +957,310 @@ func (h *HUOBI) CancelWithdraw(withdrawID int64) (int64, error) {
 	return result.WithdrawID, err
 }
 
+// GetDepositAddresses returns the deposit addresses of a currency on each of
+// its chains
+func (h *HUOBI) GetDepositAddresses(c string) ([]DepositAddress, error) {
+	if c == "" {
+		return nil, errors.New("currency must be supplied")
+	}
+
+	type response struct {
+		ResponseV2
+		Data []DepositAddress `json:"data"`
+	}
+
+	vals := url.Values{}
+	vals.Set("currency", strings.ToLower(c))
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequestV2(http.MethodGet, huobiDepositAddress, vals, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, result.Error()
+}
+
+// GetTransactFeeRates returns the maker and taker fee rates applied to the
+// user for the supplied symbols, including any deduction discounts
+func (h *HUOBI) GetTransactFeeRates(symbols []string) ([]TransactFeeRate, error) {
+	if len(symbols) == 0 {
+		return nil, errors.New("at least one symbol must be supplied")
+	}
+
+	type response struct {
+		ResponseV2
+		Data []TransactFeeRate `json:"data"`
+	}
+
+	vals := url.Values{}
+	vals.Set("symbols", strings.Join(symbols, ","))
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequestV2(http.MethodGet, huobiTransactFeeRate, vals, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, result.Error()
+}
+
+// GetFeeDeductionInfo returns the current fee deduction settings for the user
+func (h *HUOBI) GetFeeDeductionInfo() (FeeDeductionInfo, error) {
+	type response struct {
+		ResponseV2
+		Data FeeDeductionInfo `json:"data"`
+	}
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequestV2(http.MethodGet, huobiFeeDeductionInfo, url.Values{}, nil, &result)
+	if err != nil {
+		return FeeDeductionInfo{}, err
+	}
+	return result.Data, result.Error()
+}
+
+// SetFeeDeduction switches the fee deduction currency used by the account
+func (h *HUOBI) SetFeeDeduction(mode FeeDeductionMode) error {
+	if mode != FeeDeductionHT && mode != FeeDeductionPoint && mode != FeeDeductionNone {
+		return fmt.Errorf("%s invalid fee deduction mode %v", h.Name, mode)
+	}
+
+	data := struct {
+		DeductionType int `json:"deductionType"`
+	}{
+		DeductionType: mode.deductionType(),
+	}
+
+	var result ResponseV2
+	err := h.SendAuthenticatedHTTPRequestV2(http.MethodPost, huobiFeeDeductionSwitch, nil, data, &result)
+	if err != nil {
+		return err
+	}
+	return result.Error()
+}
+
+// GetPointAccount returns the point card balance of the account
+func (h *HUOBI) GetPointAccount() (PointAccount, error) {
+	type response struct {
+		ResponseV2
+		Data PointAccount `json:"data"`
+	}
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequestV2(http.MethodGet, huobiPointAccount, url.Values{}, nil, &result)
+	if err != nil {
+		return PointAccount{}, err
+	}
+	return result.Data, result.Error()
+}
+
+// GetETPReference returns the reference data of an exchange traded product
+// such as a leveraged token, including its creation and redemption limits and
+// fee rates
+func (h *HUOBI) GetETPReference(etpName string) (ETPReference, error) {
+	type response struct {
+		ResponseV2
+		Data ETPReference `json:"data"`
+	}
+
+	vals := url.Values{}
+	vals.Set("etpName", etpName)
+
+	var result response
+	urlPath := fmt.Sprintf("%s/v%s/%s", h.APIUrl, huobiAPIVersion2, huobiETPReference)
+
+	err := h.SendHTTPRequest(common.EncodeURLValues(urlPath, vals), &result)
+	if err != nil {
+		return ETPReference{}, err
+	}
+	return result.Data, result.Error()
+}
+
+// GetETPNetValue returns the real time net asset value and underlying basket
+// of an exchange traded product
+//
+// symbol: the ETP's trading symbol, e.g. btc3lusdt
+func (h *HUOBI) GetETPNetValue(symbol string) (ETPNetValue, error) {
+	vals := url.Values{}
+	vals.Set("symbol", symbol)
+
+	type response struct {
+		Response
+		Tick ETPNetValue `json:"tick"`
+	}
+
+	var result response
+	urlPath := fmt.Sprintf("%s/%s", h.APIUrl, huobiMarketETP)
+
+	err := h.SendHTTPRequest(common.EncodeURLValues(urlPath, vals), &result)
+	if result.ErrorMessage != "" {
+		return result.Tick, errors.New(result.ErrorMessage)
+	}
+	return result.Tick, err
+}
+
+// CreateETP subscribes an amount of the currency into new units of the ETP at
+// its net asset value
+func (h *HUOBI) CreateETP(etpName string, c currency.Code, value float64) (ETPTransactionResponse, error) {
+	if value <= 0 {
+		return ETPTransactionResponse{}, fmt.Errorf("%s ETP creation value must be positive", h.Name)
+	}
+
+	data := struct {
+		ETPName  string  `json:"etpName"`
+		Value    float64 `json:"value"`
+		Currency string  `json:"currency"`
+	}{
+		ETPName:  etpName,
+		Value:    value,
+		Currency: c.Lower().String(),
+	}
+
+	type response struct {
+		ResponseV2
+		Data ETPTransactionResponse `json:"data"`
+	}
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequestV2(http.MethodPost, huobiETPCreation, nil, data, &result)
+	if err != nil {
+		return ETPTransactionResponse{}, err
+	}
+	return result.Data, result.Error()
+}
+
+// RedeemETP redeems units of the ETP into the currency at its net asset value
+func (h *HUOBI) RedeemETP(etpName string, c currency.Code, amount float64) (ETPTransactionResponse, error) {
+	if amount <= 0 {
+		return ETPTransactionResponse{}, fmt.Errorf("%s ETP redemption amount must be positive", h.Name)
+	}
+
+	data := struct {
+		ETPName  string  `json:"etpName"`
+		Currency string  `json:"currency"`
+		Amount   float64 `json:"amount"`
+	}{
+		ETPName:  etpName,
+		Currency: c.Lower().String(),
+		Amount:   amount,
+	}
+
+	type response struct {
+		ResponseV2
+		Data ETPTransactionResponse `json:"data"`
+	}
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequestV2(http.MethodPost, huobiETPRedemption, nil, data, &result)
+	if err != nil {
+		return ETPTransactionResponse{}, err
+	}
+	return result.Data, result.Error()
+}
+
+// GetETPTransactions returns the creation and redemption history of the
+// supplied ETPs
+//
+// transactType: creation or redemption, empty returns both
+// limit: maximum number of records, up to 50
+func (h *HUOBI) GetETPTransactions(etpNames []string, transactType string, start, end time.Time, limit int) ([]ETPTransaction, error) {
+	if len(etpNames) == 0 {
+		return nil, errors.New("at least one ETP name must be supplied")
+	}
+
+	vals := url.Values{}
+	vals.Set("etpNames", strings.Join(etpNames, ","))
+	if transactType != "" {
+		vals.Set("transactTypes", transactType)
+	}
+	if !start.IsZero() {
+		vals.Set("startTime", strconv.FormatInt(start.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if !end.IsZero() {
+		vals.Set("endTime", strconv.FormatInt(end.UnixNano()/int64(time.Millisecond), 10))
+	}
+	if limit > 0 {
+		vals.Set("limit", strconv.Itoa(limit))
+	}
+
+	type response struct {
+		ResponseV2
+		Data []ETPTransaction `json:"data"`
+	}
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequestV2(http.MethodGet, huobiETPTransactions, vals, nil, &result)
+	if err != nil {
+		return nil, err
+	}
+	return result.Data, result.Error()
+}
+
+// GetETPTransaction returns a single creation or redemption
+func (h *HUOBI) GetETPTransaction(transactID int64) (ETPTransaction, error) {
+	vals := url.Values{}
+	vals.Set("transactId", strconv.FormatInt(transactID, 10))
+
+	type response struct {
+		ResponseV2
+		Data ETPTransaction `json:"data"`
+	}
+
+	var result response
+	err := h.SendAuthenticatedHTTPRequestV2(http.MethodGet, huobiETPTransaction, vals, nil, &result)
+	if err != nil {
+		return ETPTransaction{}, err
+	}
+	return result.Data, result.Error()
+}
+
+// CancelETPTransaction cancels a pending creation or redemption
+func (h *HUOBI) CancelETPTransaction(transactID int64) error {
+	var result ResponseV2
+	err := h.SendAuthenticatedHTTPRequestV2(http.MethodPost,
+		fmt.Sprintf(huobiETPCancel, transactID), nil, nil, &result)
+	if err != nil {
+		return err
+	}
+	return result.Error()
+}
+
 // SendHTTPRequest sends an unauthenticated HTTP request
 func (h *HUOBI) SendHTTPRequest(path string, result interface{}) error {
 	return h.SendPayload(http.MethodGet, path, nil, nil, result, false, false, h.Verbose, h.HTTPDebugging)
 }
 
+// mutating returns whether the authenticated endpoint changes exchange state.
+// Endpoints holding order, withdrawal and transaction IDs are matched by the
+// action they end with
+func mutating(endpoint string) bool {
+	switch endpoint {
+	case huobiOrderPlace, huobiOrderCancelBatch, huobiBatchCancelOpenOrders,
+		huobiMarginTransferIn, huobiMarginTransferOut, huobiMarginOrders,
+		huobiWithdrawCreate, huobiFeeDeductionSwitch, huobiETPCreation, huobiETPRedemption:
+		return true
+	}
+	return strings.HasSuffix(endpoint, "/submitcancel") || strings.HasSuffix(endpoint, "/repay") ||
+		strings.HasSuffix(endpoint, "/cancel")
+}
+
 // SendAuthenticatedHTTPRequest sends authenticated requests to the HUOBI API
 func (h *HUOBI) SendAuthenticatedHTTPRequest(method, endpoint string, values url.Values, data, result interface{}) error {
+	return h.sendAuthenticatedHTTPRequest(method, huobiAPIVersion, endpoint, values, data, result)
+}
+
+// SendAuthenticatedHTTPRequestV2 sends authenticated requests to the HUOBI v2
+// API
+func (h *HUOBI) SendAuthenticatedHTTPRequestV2(method, endpoint string, values url.Values, data, result interface{}) error {
+	return h.sendAuthenticatedHTTPRequest(method, huobiAPIVersion2, endpoint, values, data, result)
+}
+
+func (h *HUOBI) sendAuthenticatedHTTPRequest(method, version, endpoint string, values url.Values, data, result interface{}) error {
 	if !h.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, h.Name)
 	}
+	if h.DryRunRequest(mutating(endpoint), endpoint, data) {
+		return nil
+	}
 
 	if values == nil {
 		values = url.Values{}
@@ -905,7 +1271,7 @@ func (h *HUOBI) SendAuthenticatedHTTPRequest(method, endpoint string, values url
 	values.Set("SignatureVersion", "2")
 	values.Set("Timestamp", time.Now().UTC().Format("2006-01-02T15:04:05"))
 
-	endpoint = fmt.Sprintf("/v%s/%s", huobiAPIVersion, endpoint)
+	endpoint = fmt.Sprintf("/v%s/%s", version, endpoint)
 	payload := fmt.Sprintf("%s\napi.huobi.pro\n%s\n%s",
 		method, endpoint, values.Encode())
 
@@ -970,7 +1336,17 @@ func (h *HUOBI) SendAuthenticatedHTTPRequest(method, endpoint string, values url
 // GetFee returns an estimate of fee based on type of transaction
 func (h *HUOBI) GetFee(feeBuilder *exchange.FeeBuilder) (float64, error) {
 	var fee float64
-	if feeBuilder.FeeType == exchange.OfflineTradeFee || feeBuilder.FeeType == exchange.CryptocurrencyTradeFee {
+	switch feeBuilder.FeeType {
+	case exchange.CryptocurrencyTradeFee:
+		rate, err := h.getActualFeeRate(feeBuilder.Pair, feeBuilder.IsMaker)
+		if err != nil {
+			log.Debugf("%s unable to retrieve fee rate, using offline estimate. Error: %s",
+				h.Name, err)
+			fee = calculateTradingFee(feeBuilder.Pair, feeBuilder.PurchasePrice, feeBuilder.Amount)
+			break
+		}
+		fee = rate * feeBuilder.PurchasePrice * feeBuilder.Amount
+	case exchange.OfflineTradeFee:
 		fee = calculateTradingFee(feeBuilder.Pair, feeBuilder.PurchasePrice, feeBuilder.Amount)
 	}
 	if fee < 0 {
@@ -986,3 +1362,66 @@ func calculateTradingFee(c currency.Pair, price, amount float64) float64 {
 	}
 	return 0.002 * price * amount
 }
+
+// getActualFeeRate returns the fee rate charged after deductions for a pair,
+// retrieving the rates of the symbol once the cached copy expires
+func (h *HUOBI) getActualFeeRate(p currency.Pair, isMaker bool) (float64, error) {
+	symbol := exchange.FormatExchangeCurrency(h.Name, p).String()
+	h.feeMtx.Lock()
+	defer h.feeMtx.Unlock()
+	c, ok := h.feeRates[symbol]
+	if !ok || time.Since(c.updated) > feeRateCacheDuration {
+		rates, err := h.GetTransactFeeRates([]string{symbol})
+		if err != nil {
+			return 0, err
+		}
+
+		ok = false
+		for i := range rates {
+			if rates[i].Symbol != symbol {
+				continue
+			}
+			if h.feeRates == nil {
+				h.feeRates = make(map[string]cachedFeeRate)
+			}
+			c = cachedFeeRate{rate: rates[i], updated: time.Now()}
+			h.feeRates[symbol] = c
+			ok = true
+			break
+		}
+		if !ok {
+			return 0, fmt.Errorf("%s fee rate not found for %s", h.Name, symbol)
+		}
+	}
+
+	if isMaker {
+		return c.rate.ActualMakerRate, nil
+	}
+	return c.rate.ActualTakerRate, nil
+}
+
+// syncFeeDeduction ensures the account fee deduction setting matches the
+// configured preference before an order is placed. Cached fee rates are
+// dropped when the setting is switched as the deduction discount changes.
+func (h *HUOBI) syncFeeDeduction() error {
+	h.feeMtx.Lock()
+	defer h.feeMtx.Unlock()
+	if h.FeeDeduction == FeeDeductionUnset || h.FeeDeduction == h.feeDeductionSynced {
+		return nil
+	}
+
+	info, err := h.GetFeeDeductionInfo()
+	if err != nil {
+		return err
+	}
+
+	if info.Mode() != h.FeeDeduction {
+		err = h.SetFeeDeduction(h.FeeDeduction)
+		if err != nil {
+			return err
+		}
+		h.feeRates = nil
+	}
+	h.feeDeductionSynced = h.FeeDeduction
+	return nil
+}
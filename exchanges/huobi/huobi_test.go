@@ -9,12 +9,14 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/sharedtestvalues"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
 )
@@ -101,6 +103,31 @@ func TestGetSpotKline(t *testing.T) {
 	}
 }
 
+func TestGetHistoricCandles(t *testing.T) {
+	t.Parallel()
+	_, err := h.GetHistoricCandles(currency.NewPairWithDelimiter("BTC", "USDT", "-"), "SPOT", kline.FourHour, 24)
+	if err != nil {
+		t.Error("Test Failed - Huobi GetHistoricCandles() error", err)
+	}
+	_, err = h.GetHistoricCandles(currency.NewPairWithDelimiter("BTC", "USDT", "-"), "SPOT", kline.TwoHour, 24)
+	if err != exchange.ErrCandleIntervalUnsupported {
+		t.Error("Test Failed - Huobi GetHistoricCandles() expected unsupported interval error", err)
+	}
+}
+
+func TestConvertCandles(t *testing.T) {
+	candles := convertCandles([]KlineItem{
+		{ID: 1559376000, Close: 2, Amount: 5},
+		{ID: 1559372400, Close: 1, Amount: 4},
+	})
+	if len(candles) != 2 || candles[0].Close != 1 || candles[1].Volume != 5 {
+		t.Fatalf("Test Failed - convertCandles() expected candles oldest first %+v", candles)
+	}
+	if candles[0].Time.Unix() != 1559372400 {
+		t.Error("Test Failed - convertCandles() unexpected open time", candles[0].Time)
+	}
+}
+
 func TestGetMarketDetailMerged(t *testing.T) {
 	t.Parallel()
 	_, err := h.GetMarketDetailMerged(testSymbol)
@@ -161,6 +188,46 @@ func TestGetSymbols(t *testing.T) {
 	}
 }
 
+func TestSymbolTradingRules(t *testing.T) {
+	t.Parallel()
+	s := Symbol{BaseCurrency: "btc", QuoteCurrency: "usdt", PricePrecision: 2, AmountPrecision: 4}
+	rules := s.TradingRules()
+	if rules.PriceStep != 0.01 || rules.AmountStep != 0.0001 {
+		t.Fatalf("Test failed - Huobi TradingRules() unexpected rules %+v", rules)
+	}
+
+	amount, price, err := rules.Apply(exchange.BuyOrderSide, exchange.LimitOrderType, 0.12349, 10000.999)
+	if err != nil {
+		t.Fatal("Test failed - Huobi Apply() error", err)
+	}
+	if amount != 0.1234 || price != 10000.99 {
+		t.Errorf("Test failed - Huobi Apply() buy got %v @ %v", amount, price)
+	}
+
+	amount, price, err = rules.Apply(exchange.SellOrderSide, exchange.LimitOrderType, 0.12349, 10000.001)
+	if err != nil {
+		t.Fatal("Test failed - Huobi Apply() error", err)
+	}
+	if amount != 0.1234 || price != 10000.01 {
+		t.Errorf("Test failed - Huobi Apply() sell got %v @ %v", amount, price)
+	}
+
+	// zero precision symbols trade whole units
+	s = Symbol{BaseCurrency: "trx", QuoteCurrency: "btc", PricePrecision: 10, AmountPrecision: 0}
+	rules = s.TradingRules()
+	amount, price, err = rules.Apply(exchange.SellOrderSide, exchange.LimitOrderType, 1500.9, 0.00000153214)
+	if err != nil {
+		t.Fatal("Test failed - Huobi Apply() error", err)
+	}
+	if amount != 1500 || price != 0.0000015322 {
+		t.Errorf("Test failed - Huobi Apply() whole units got %v @ %v", amount, price)
+	}
+	_, _, err = rules.Apply(exchange.SellOrderSide, exchange.LimitOrderType, 0.9, 0.0000015)
+	if err == nil {
+		t.Error("Test failed - Huobi Apply() expected error when amount truncates to zero")
+	}
+}
+
 func TestGetCurrencies(t *testing.T) {
 	t.Parallel()
 	_, err := h.GetCurrencies()
@@ -322,6 +389,192 @@ func TestPEMLoadAndSign(t *testing.T) {
 	}
 }
 
+func TestGetTransactFeeRates(t *testing.T) {
+	t.Parallel()
+
+	_, err := h.GetTransactFeeRates(nil)
+	if err == nil {
+		t.Error("Test failed - Huobi GetTransactFeeRates() expected error when no symbols supplied")
+	}
+
+	if h.APIKey == "" || h.APISecret == "" || h.APIAuthPEMKey == "" {
+		t.Skip()
+	}
+
+	_, err = h.GetTransactFeeRates([]string{"btcusdt"})
+	if err != nil {
+		t.Errorf("Test failed - Huobi GetTransactFeeRates: %s", err)
+	}
+}
+
+func TestGetFeeDeductionInfo(t *testing.T) {
+	t.Parallel()
+
+	if h.APIKey == "" || h.APISecret == "" || h.APIAuthPEMKey == "" {
+		t.Skip()
+	}
+
+	_, err := h.GetFeeDeductionInfo()
+	if err != nil {
+		t.Errorf("Test failed - Huobi GetFeeDeductionInfo: %s", err)
+	}
+}
+
+func TestGetPointAccount(t *testing.T) {
+	t.Parallel()
+
+	if h.APIKey == "" || h.APISecret == "" || h.APIAuthPEMKey == "" {
+		t.Skip()
+	}
+
+	_, err := h.GetPointAccount()
+	if err != nil {
+		t.Errorf("Test failed - Huobi GetPointAccount: %s", err)
+	}
+}
+
+func TestSetFeeDeduction(t *testing.T) {
+	t.Parallel()
+
+	err := h.SetFeeDeduction(FeeDeductionUnset)
+	if err == nil {
+		t.Error("Test failed - Huobi SetFeeDeduction() expected error for unset mode")
+	}
+}
+
+func TestGetETPReference(t *testing.T) {
+	t.Parallel()
+
+	_, err := h.GetETPReference("btc3l")
+	if err != nil {
+		t.Errorf("Test failed - Huobi GetETPReference: %s", err)
+	}
+}
+
+func TestGetETPNetValue(t *testing.T) {
+	t.Parallel()
+
+	_, err := h.GetETPNetValue("btc3lusdt")
+	if err != nil {
+		t.Errorf("Test failed - Huobi GetETPNetValue: %s", err)
+	}
+}
+
+func TestCreateAndRedeemETP(t *testing.T) {
+	t.Parallel()
+
+	_, err := h.CreateETP("btc3l", currency.USDT, 0)
+	if err == nil {
+		t.Error("Test failed - Huobi CreateETP() expected error for zero value")
+	}
+	_, err = h.RedeemETP("btc3l", currency.USDT, -1)
+	if err == nil {
+		t.Error("Test failed - Huobi RedeemETP() expected error for negative amount")
+	}
+	_, err = h.GetETPTransactions(nil, "", time.Time{}, time.Time{}, 0)
+	if err == nil {
+		t.Error("Test failed - Huobi GetETPTransactions() expected error when no ETPs supplied")
+	}
+
+	if h.APIKey == "" || h.APISecret == "" || h.APIAuthPEMKey == "" {
+		t.Skip()
+	}
+
+	_, err = h.GetETPTransactions([]string{"btc3l"}, "", time.Time{}, time.Time{}, 10)
+	if err != nil {
+		t.Errorf("Test failed - Huobi GetETPTransactions: %s", err)
+	}
+}
+
+func TestETPReference(t *testing.T) {
+	t.Parallel()
+
+	ref := ETPReference{
+		ETPStatus:           ETPStatusNormal,
+		CreationMinAmount:   10,
+		CreationMaxAmount:   1000,
+		RedemptionMinAmount: 1,
+	}
+	if !ref.CanCreate(100) || ref.CanCreate(5) || ref.CanCreate(5000) {
+		t.Error("Test failed - ETPReference.CanCreate() unexpected result")
+	}
+	if !ref.CanRedeem(5000) || ref.CanRedeem(0.5) {
+		t.Error("Test failed - ETPReference.CanRedeem() unexpected result")
+	}
+	ref.ETPStatus = ETPStatusRebalancing
+	if ref.CanCreate(100) || ref.CanRedeem(5) {
+		t.Error("Test failed - ETPReference expected no creation or redemption while rebalancing")
+	}
+
+	nav := ETPNetValue{NAV: 2}
+	if nav.Premium(2.1) <= 0 || nav.Premium(1.9) >= 0 {
+		t.Error("Test failed - ETPNetValue.Premium() unexpected result")
+	}
+}
+
+func TestFeeDeductionMode(t *testing.T) {
+	t.Parallel()
+
+	modes := map[int]FeeDeductionMode{
+		0: FeeDeductionNone,
+		1: FeeDeductionHT,
+		2: FeeDeductionPoint,
+	}
+	for k, v := range modes {
+		info := FeeDeductionInfo{DeductSwitch: k}
+		if info.Mode() != v {
+			t.Errorf("Test failed - FeeDeductionInfo.Mode() expected %v received %v", v, info.Mode())
+		}
+		if v.deductionType() != k {
+			t.Errorf("Test failed - deductionType() expected %v received %v", k, v.deductionType())
+		}
+	}
+}
+
+func TestParseFeeDeductionMode(t *testing.T) {
+	t.Parallel()
+
+	modes := map[string]FeeDeductionMode{
+		"":      FeeDeductionUnset,
+		"none":  FeeDeductionNone,
+		"HT":    FeeDeductionHT,
+		"point": FeeDeductionPoint,
+	}
+	for k, v := range modes {
+		mode, err := ParseFeeDeductionMode(k)
+		if err != nil || mode != v {
+			t.Errorf("Test failed - ParseFeeDeductionMode(%q) expected %v received %v %v", k, v, mode, err)
+		}
+	}
+	if _, err := ParseFeeDeductionMode("bnb"); err == nil {
+		t.Error("Test failed - ParseFeeDeductionMode() expected error for invalid mode")
+	}
+}
+
+func TestGetActualFeeRateCached(t *testing.T) {
+	t.Parallel()
+
+	p := currency.NewPair(currency.BTC, currency.USDT)
+	symbol := exchange.FormatExchangeCurrency(h.Name, p).String()
+	c := HUOBI{
+		feeRates: map[string]cachedFeeRate{
+			symbol: {
+				rate:    TransactFeeRate{Symbol: symbol, ActualMakerRate: 0.001, ActualTakerRate: 0.002},
+				updated: time.Now(),
+			},
+		},
+	}
+	c.Name = h.Name
+	rate, err := c.getActualFeeRate(p, true)
+	if err != nil || rate != 0.001 {
+		t.Error("Test failed - Huobi getActualFeeRate() expected cached maker rate", rate, err)
+	}
+	rate, err = c.getActualFeeRate(p, false)
+	if err != nil || rate != 0.002 {
+		t.Error("Test failed - Huobi getActualFeeRate() expected cached taker rate", rate, err)
+	}
+}
+
 func setFeeBuilder() *exchange.FeeBuilder {
 	return &exchange.FeeBuilder{
 		Amount:  1,
@@ -688,3 +941,81 @@ func TestWsGetOrderDetails(t *testing.T) {
 		t.Error(resp.ErrorMessage)
 	}
 }
+
+func TestChainNetworkStatuses(t *testing.T) {
+	t.Parallel()
+	c := CurrencyChains{
+		Currency:   "usdt",
+		InstStatus: "normal",
+		Chains: []Chain{
+			{Chain: "usdterc20", DisplayName: "ERC20", DepositStatus: ChainStatusAllowed, WithdrawStatus: ChainStatusAllowed},
+			{Chain: "trc20usdt", DepositStatus: ChainStatusAllowed, WithdrawStatus: ChainStatusProhibited},
+		},
+	}
+	s := ChainNetworkStatuses(&c)
+	if len(s) != 2 || s[0].Network != "ERC20" || !s[0].DepositEnabled || !s[0].WithdrawEnabled {
+		t.Fatalf("Test Failed - ChainNetworkStatuses() unexpected statuses %+v", s)
+	}
+	if s[1].Network != "trc20usdt" || s[1].WithdrawEnabled || s[1].Reason != "deposits allowed, withdrawals prohibited" {
+		t.Errorf("Test Failed - ChainNetworkStatuses() unexpected status %+v", s[1])
+	}
+
+	c.InstStatus = CurrencyDelisted
+	if s = ChainNetworkStatuses(&c); s[0].DepositEnabled || !s[0].WithdrawEnabled {
+		t.Errorf("Test Failed - ChainNetworkStatuses() expected delisted deposits disabled %+v", s[0])
+	}
+}
+
+func TestChainInfos(t *testing.T) {
+	t.Parallel()
+	c := CurrencyChains{
+		Currency:   "usdt",
+		InstStatus: "normal",
+		Chains: []Chain{
+			{Chain: "usdterc20", DisplayName: "ERC20", DepositStatus: ChainStatusAllowed,
+				WithdrawStatus: ChainStatusProhibited, MinDepositAmt: 1, MinWithdrawAmt: 10,
+				MaxWithdrawAmt: 1000, TransactFeeWithdraw: 5, WithdrawPrecision: 6, NumOfConfirmations: 12},
+		},
+	}
+	info := ChainInfos(&c)
+	if len(info) != 1 {
+		t.Fatalf("Test Failed - ChainInfos() unexpected chains %+v", info)
+	}
+	expected := exchange.ChainInfo{Chain: "usdterc20", Network: "ERC20", DepositEnabled: true,
+		MinDeposit: 1, MinWithdraw: 10, MaxWithdraw: 1000, WithdrawFee: 5, WithdrawPrecision: 6, Confirmations: 12}
+	if info[0] != expected {
+		t.Errorf("Test Failed - ChainInfos() expected %+v received %+v", expected, info[0])
+	}
+}
+
+func TestGetDepositAddresses(t *testing.T) {
+	t.Parallel()
+	if _, err := h.GetDepositAddresses(""); err == nil {
+		t.Error("Test Failed - GetDepositAddresses() expected error without a currency")
+	}
+	if !areTestAPIKeysSet() {
+		if _, err := h.GetDepositAddresses("usdt"); err == nil {
+			t.Error("Test Failed - GetDepositAddresses() expected error without API keys")
+		}
+	}
+}
+
+func TestSelectDepositAddress(t *testing.T) {
+	t.Parallel()
+	addresses := []DepositAddress{
+		{Currency: "usdt", Address: "omni", Chain: "usdt"},
+		{Currency: "usdt", Address: "erc20", Chain: "usdterc20"},
+	}
+	if a, ok := SelectDepositAddress(addresses, currency.USDT, ""); !ok || a.Address != "omni" {
+		t.Errorf("Test Failed - SelectDepositAddress() expected default chain %+v", a)
+	}
+	if a, ok := SelectDepositAddress(addresses, currency.USDT, "USDTERC20"); !ok || a.Address != "erc20" {
+		t.Errorf("Test Failed - SelectDepositAddress() expected erc20 chain %+v", a)
+	}
+	if _, ok := SelectDepositAddress(addresses, currency.USDT, "trc20usdt"); ok {
+		t.Error("Test Failed - SelectDepositAddress() unexpected address for unknown chain")
+	}
+	if a, ok := SelectDepositAddress(addresses[1:], currency.USDT, ""); !ok || a.Address != "erc20" {
+		t.Errorf("Test Failed - SelectDepositAddress() expected only address as default %+v", a)
+	}
+}
@@ -1,6 +1,12 @@
 package huobi
 
-import "github.com/thrasher-corp/gocryptotrader/currency"
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
+)
 
 // Response stores the Huobi response information
 type Response struct {
@@ -11,6 +17,21 @@ type Response struct {
 	ErrorMessage string `json:"err-msg"`
 }
 
+// ResponseV2 stores the common response fields of the v2 API
+type ResponseV2 struct {
+	Code    int64  `json:"code"`
+	Message string `json:"message"`
+	Success bool   `json:"success"`
+}
+
+// Error returns an error if the v2 response code indicates a failure
+func (r *ResponseV2) Error() error {
+	if r.Code != 0 && r.Code != 200 {
+		return fmt.Errorf("error code %d: %s", r.Code, r.Message)
+	}
+	return nil
+}
+
 // KlineItem stores a kline item
 type KlineItem struct {
 	ID     int64   `json:"id"`
@@ -262,12 +283,28 @@ var (
 	TimeIntervalFifteenMinutes = TimeInterval("15min")
 	TimeIntervalThirtyMinutes  = TimeInterval("30min")
 	TimeIntervalHour           = TimeInterval("60min")
+	TimeIntervalFourHours      = TimeInterval("4hour")
 	TimeIntervalDay            = TimeInterval("1day")
 	TimeIntervalWeek           = TimeInterval("1week")
 	TimeIntervalMohth          = TimeInterval("1mon")
 	TimeIntervalYear           = TimeInterval("1year")
 )
 
+// maxKlineSize is the most klines returned per request
+const maxKlineSize = 2000
+
+// huobiCandleIntervals maps candle intervals to their kline period
+var huobiCandleIntervals = map[kline.Interval]TimeInterval{
+	kline.OneMin:     TimeIntervalMinute,
+	kline.FiveMin:    TimeIntervalFiveMinutes,
+	kline.FifteenMin: TimeIntervalFifteenMinutes,
+	kline.ThirtyMin:  TimeIntervalThirtyMinutes,
+	kline.OneHour:    TimeIntervalHour,
+	kline.FourHour:   TimeIntervalFourHours,
+	kline.OneDay:     TimeIntervalDay,
+	kline.OneWeek:    TimeIntervalWeek,
+}
+
 // WsRequest defines a request data structure
 type WsRequest struct {
 	Topic       string `json:"req,omitempty"`
@@ -540,3 +577,216 @@ type WsAuthenticatedOrderDetailResponse struct {
 type WsPong struct {
 	Pong int64 `json:"pong"`
 }
+
+// FeeDeductionMode defines which currency is used to deduct trading fees
+type FeeDeductionMode int
+
+// Fee deduction modes, FeeDeductionUnset leaves the account setting untouched
+const (
+	FeeDeductionUnset FeeDeductionMode = iota
+	FeeDeductionNone
+	FeeDeductionHT
+	FeeDeductionPoint
+)
+
+// ParseFeeDeductionMode returns the fee deduction mode of a config value. An
+// empty value leaves the account setting untouched.
+func ParseFeeDeductionMode(mode string) (FeeDeductionMode, error) {
+	switch strings.ToLower(mode) {
+	case "":
+		return FeeDeductionUnset, nil
+	case "none":
+		return FeeDeductionNone, nil
+	case "ht":
+		return FeeDeductionHT, nil
+	case "point":
+		return FeeDeductionPoint, nil
+	}
+	return FeeDeductionUnset, fmt.Errorf("invalid fee deduction mode %q, expected none, ht or point", mode)
+}
+
+// deductionType returns the API value for the fee deduction mode
+func (f FeeDeductionMode) deductionType() int {
+	switch f {
+	case FeeDeductionHT:
+		return 1
+	case FeeDeductionPoint:
+		return 2
+	}
+	return 0
+}
+
+// FeeDeductionInfo holds the current fee deduction settings of the account
+type FeeDeductionInfo struct {
+	DeductSwitch   int    `json:"deductSwitch"` // 0: off, 1: HT, 2: point card
+	DeductCurrency string `json:"deductCurrency"`
+}
+
+// Mode returns the fee deduction mode of the settings
+func (f *FeeDeductionInfo) Mode() FeeDeductionMode {
+	switch f.DeductSwitch {
+	case 1:
+		return FeeDeductionHT
+	case 2:
+		return FeeDeductionPoint
+	}
+	return FeeDeductionNone
+}
+
+// TransactFeeRate holds the fee rates applied to a symbol. Actual rates take
+// fee deductions into account.
+type TransactFeeRate struct {
+	Symbol          string  `json:"symbol"`
+	MakerFeeRate    float64 `json:"makerFeeRate,string"`
+	TakerFeeRate    float64 `json:"takerFeeRate,string"`
+	ActualMakerRate float64 `json:"actualMakerRate,string"`
+	ActualTakerRate float64 `json:"actualTakerRate,string"`
+}
+
+// PointAccount holds the point card balance of an account
+type PointAccount struct {
+	AccountID      string  `json:"accountId"`
+	AccountStatus  string  `json:"accountStatus"`
+	AccountBalance float64 `json:"acctBalance,string"`
+	GroupIDs       []struct {
+		GroupID    int64   `json:"groupId"`
+		ExpiryDate int64   `json:"expiryDate"`
+		RemainAmt  float64 `json:"remainAmt,string"`
+	} `json:"groupIds"`
+}
+
+// Currency chain status values
+const (
+	ChainStatusAllowed    = "allowed"
+	ChainStatusProhibited = "prohibited"
+	CurrencyDelisted      = "delisted"
+)
+
+// CurrencyChains holds the chains a currency can be transferred on
+type CurrencyChains struct {
+	Currency string `json:"currency"`
+	// InstStatus is normal or delisted
+	InstStatus string  `json:"instStatus"`
+	Chains     []Chain `json:"chains"`
+}
+
+// Chain holds the deposit and withdrawal status of a currency on a chain
+type Chain struct {
+	Chain                  string  `json:"chain"`
+	DisplayName            string  `json:"displayName"`
+	BaseChain              string  `json:"baseChain"`
+	BaseChainProtocol      string  `json:"baseChainProtocol"`
+	NumOfConfirmations     int64   `json:"numOfConfirmations"`
+	NumOfFastConfirmations int64   `json:"numOfFastConfirmations"`
+	DepositStatus          string  `json:"depositStatus"`
+	MinDepositAmt          float64 `json:"minDepositAmt,string"`
+	WithdrawStatus         string  `json:"withdrawStatus"`
+	MinWithdrawAmt         float64 `json:"minWithdrawAmt,string"`
+	MaxWithdrawAmt         float64 `json:"maxWithdrawAmt,string"`
+	WithdrawPrecision      int64   `json:"withdrawPrecision"`
+	WithdrawFeeType        string  `json:"withdrawFeeType"`
+	TransactFeeWithdraw    float64 `json:"transactFeeWithdraw,string"`
+}
+
+// DepositAddress holds a deposit address of a currency on a chain
+type DepositAddress struct {
+	UserID     int64  `json:"userId"`
+	Currency   string `json:"currency"`
+	Address    string `json:"address"`
+	AddressTag string `json:"addressTag"`
+	Chain      string `json:"chain"`
+}
+
+// ETP status values
+const (
+	ETPStatusNormal      = "normal"
+	ETPStatusRebalancing = "rebalancing-start"
+	ETPStatusHalted      = "creation-and-redemption-suspend"
+)
+
+// ETPReference holds the reference data of an exchange traded product
+type ETPReference struct {
+	ETPName             string  `json:"etpName"`
+	DisplayName         string  `json:"displayName"`
+	Value               float64 `json:"value"`
+	ETPStatus           string  `json:"etpStatus"`
+	CreationMaxAmount   float64 `json:"creationMaxAmount"`
+	CreationMinAmount   float64 `json:"creationMinAmount"`
+	RedemptionMaxAmount float64 `json:"redemptionMaxAmount"`
+	RedemptionMinAmount float64 `json:"redemptionMinAmount"`
+	CreationFeeRate     float64 `json:"creationFeeRate"`
+	RedemptionFeeRate   float64 `json:"redemptionFeeRate"`
+	ManagementFeeRate   float64 `json:"managementFeeRate"`
+	TargetLeverage      float64 `json:"targetLeverage"`
+	Currencies          []struct {
+		Currency string `json:"currency"`
+		Status   string `json:"currencyStatus"`
+	} `json:"currencies"`
+}
+
+// CanCreate returns whether new units can be created for the value
+func (e *ETPReference) CanCreate(value float64) bool {
+	return e.ETPStatus == ETPStatusNormal &&
+		value >= e.CreationMinAmount &&
+		(e.CreationMaxAmount == 0 || value <= e.CreationMaxAmount)
+}
+
+// CanRedeem returns whether the amount of units can be redeemed
+func (e *ETPReference) CanRedeem(amount float64) bool {
+	return e.ETPStatus == ETPStatusNormal &&
+		amount >= e.RedemptionMinAmount &&
+		(e.RedemptionMaxAmount == 0 || amount <= e.RedemptionMaxAmount)
+}
+
+// ETPNetValue holds the real time net asset value of an exchange traded
+// product
+type ETPNetValue struct {
+	Symbol         string  `json:"symbol"`
+	NAV            float64 `json:"nav"`
+	NAVTime        int64   `json:"navTime"`
+	Outstanding    float64 `json:"outstanding"`
+	ActualLeverage float64 `json:"actualLeverage"`
+	Basket         []struct {
+		Currency string  `json:"currency"`
+		Amount   float64 `json:"amount"`
+	} `json:"basket"`
+}
+
+// Premium returns the fractional premium of the market price over the net
+// asset value, negative when trading at a discount
+func (e *ETPNetValue) Premium(price float64) float64 {
+	if e.NAV <= 0 {
+		return 0
+	}
+	return (price - e.NAV) / e.NAV
+}
+
+// ETPTransactionResponse holds the ID of a submitted creation or redemption
+type ETPTransactionResponse struct {
+	TransactID   int64 `json:"transactId"`
+	TransactTime int64 `json:"transactTime"`
+}
+
+// ETPTransaction holds a creation or redemption of an exchange traded product
+type ETPTransaction struct {
+	TransactID      int64  `json:"transactId"`
+	ETPName         string `json:"etpName"`
+	TransactType    string `json:"transactType"`
+	TransactStatus  string `json:"transactStatus"`
+	TransactRequest struct {
+		Currency string  `json:"currency"`
+		Amount   float64 `json:"amount"`
+	} `json:"transactRequest"`
+	TransactResult struct {
+		Currency string  `json:"currency"`
+		Amount   float64 `json:"amount"`
+	} `json:"transactResult"`
+	TransactPrice float64 `json:"transactPrice"`
+	TransactFee   struct {
+		Currency string  `json:"feeCurrency"`
+		Fee      float64 `json:"fee"`
+		FeeRate  float64 `json:"feeRate"`
+	} `json:"transactFee"`
+	CreatedTime int64 `json:"createdTime"`
+	UpdatedTime int64 `json:"updatedTime"`
+}
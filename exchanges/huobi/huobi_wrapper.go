@@ -12,6 +12,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
@@ -257,6 +258,24 @@ func (h *HUOBI) GetAccountInfo() (exchange.AccountInfo, error) {
 	return info, nil
 }
 
+// GetSubAccountBalances returns the balances of all sub-users aggregated as a
+// single account
+func (h *HUOBI) GetSubAccountBalances() ([]exchange.Account, error) {
+	balances, err := h.GetAggregatedBalance()
+	if err != nil {
+		return nil, err
+	}
+
+	acc := exchange.Account{ID: "subusers"}
+	for _, balance := range balances {
+		acc.Currencies = append(acc.Currencies, exchange.AccountCurrencyInfo{
+			CurrencyName: currency.NewCode(balance.Currency),
+			TotalValue:   balance.Balance,
+		})
+	}
+	return []exchange.Account{acc}, nil
+}
+
 // GetFundingHistory returns funding history, deposits and
 // withdrawals
 func (h *HUOBI) GetFundingHistory() ([]exchange.FundHistory, error) {
@@ -271,8 +290,79 @@ func (h *HUOBI) GetExchangeHistory(p currency.Pair, assetType string) ([]exchang
 	return resp, common.ErrNotYetImplemented
 }
 
+// GetHistoricCandles returns up to 2000 of the most recent candles at the
+// interval
+func (h *HUOBI) GetHistoricCandles(p currency.Pair, _ string, interval kline.Interval, limit int) ([]exchange.Candle, error) {
+	period, ok := huobiCandleIntervals[interval]
+	if !ok {
+		return nil, exchange.ErrCandleIntervalUnsupported
+	}
+	if limit > maxKlineSize {
+		limit = maxKlineSize
+	}
+
+	klines, err := h.GetSpotKline(KlinesRequestParams{
+		Symbol: exchange.FormatExchangeCurrency(h.Name, p).String(),
+		Period: period,
+		Size:   limit,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return convertCandles(klines), nil
+}
+
+// convertCandles converts klines, which are returned newest first, to candles
+// ordered from oldest to newest
+func convertCandles(klines []KlineItem) []exchange.Candle {
+	candles := make([]exchange.Candle, len(klines))
+	for x := range klines {
+		candles[len(klines)-1-x] = exchange.Candle{
+			Time:   time.Unix(klines[x].ID, 0),
+			Open:   klines[x].Open,
+			High:   klines[x].High,
+			Low:    klines[x].Low,
+			Close:  klines[x].Close,
+			Volume: klines[x].Amount,
+		}
+	}
+	return candles
+}
+
 // SubmitOrder submits a new order
 func (h *HUOBI) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	if side == exchange.BuyOrderSide && orderType == exchange.MarketOrderType {
+		// Huobi sizes market buys in the quote currency, so the base amount
+		// is converted at the cost of filling it against the book
+		ob, err := h.UpdateOrderbook(p, orderbook.Spot)
+		if err != nil {
+			return exchange.SubmitOrderResponse{}, err
+		}
+		amount, err = exchange.BaseToQuote(&ob, side, amount)
+		if err != nil {
+			return exchange.SubmitOrderResponse{}, err
+		}
+	}
+	return h.submitOrder(p, side, orderType, amount, price, clientID)
+}
+
+// SupportsQuoteAmount returns whether the order can be sized in the quote
+// currency, which Huobi accepts for market buys
+func (h *HUOBI) SupportsQuoteAmount(side exchange.OrderSide, orderType exchange.OrderType) bool {
+	return side == exchange.BuyOrderSide && orderType == exchange.MarketOrderType
+}
+
+// SubmitQuoteOrder submits a market buy spending the quote amount
+func (h *HUOBI) SubmitQuoteOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, quoteAmount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	if !h.SupportsQuoteAmount(side, orderType) {
+		return exchange.SubmitOrderResponse{}, common.ErrFunctionNotSupported
+	}
+	return h.submitOrder(p, side, orderType, quoteAmount, price, clientID)
+}
+
+// submitOrder submits the order with the amount as Huobi expects it, in the
+// quote currency for market buys and the base currency otherwise
+func (h *HUOBI) submitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
 	accountID, err := strconv.ParseInt(clientID, 10, 64)
 	if err != nil {
@@ -303,6 +393,11 @@ func (h *HUOBI) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType
 	}
 
 	params.Type = formattedType
+	err = h.syncFeeDeduction()
+	if err != nil {
+		return submitOrderResponse, err
+	}
+
 	response, err := h.SpotNewOrder(params)
 	if response > 0 {
 		submitOrderResponse.OrderID = fmt.Sprintf("%v", response)
@@ -361,9 +456,134 @@ func (h *HUOBI) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	return orderDetail, common.ErrNotYetImplemented
 }
 
-// GetDepositAddress returns a deposit address for a specified currency
+// GetDepositAddress returns a deposit address for a specified currency on its
+// default chain
 func (h *HUOBI) GetDepositAddress(cryptocurrency currency.Code, accountID string) (string, error) {
-	return "", common.ErrFunctionNotSupported
+	a, err := h.GetChainDepositAddress(cryptocurrency, "")
+	if err != nil {
+		return "", err
+	}
+	return a.Address, nil
+}
+
+// ChainInfos returns the transfer rules of each of a currency's chains
+func ChainInfos(c *CurrencyChains) []exchange.ChainInfo {
+	statuses := ChainNetworkStatuses(c)
+	resp := make([]exchange.ChainInfo, 0, len(c.Chains))
+	for i := range c.Chains {
+		resp = append(resp, exchange.ChainInfo{
+			Chain:             c.Chains[i].Chain,
+			Network:           statuses[i].Network,
+			DepositEnabled:    statuses[i].DepositEnabled,
+			WithdrawEnabled:   statuses[i].WithdrawEnabled,
+			MinDeposit:        c.Chains[i].MinDepositAmt,
+			MinWithdraw:       c.Chains[i].MinWithdrawAmt,
+			MaxWithdraw:       c.Chains[i].MaxWithdrawAmt,
+			WithdrawFee:       c.Chains[i].TransactFeeWithdraw,
+			WithdrawPrecision: int(c.Chains[i].WithdrawPrecision),
+			Confirmations:     c.Chains[i].NumOfConfirmations,
+		})
+	}
+	return resp
+}
+
+// GetChainInfo returns the transfer rules of each of a currency's chains
+func (h *HUOBI) GetChainInfo(c currency.Code) ([]exchange.ChainInfo, error) {
+	chains, err := h.GetCurrencyChains(c.String())
+	if err != nil {
+		return nil, err
+	}
+	for i := range chains {
+		if strings.EqualFold(chains[i].Currency, c.String()) {
+			return ChainInfos(&chains[i]), nil
+		}
+	}
+	return nil, fmt.Errorf("%s %s %v", h.Name, c, exchange.ErrChainNotFound)
+}
+
+// GetChainDepositAddress returns the deposit address of a currency on the
+// chain. The currency's default chain, named after the currency, is used when
+// the chain is empty
+func (h *HUOBI) GetChainDepositAddress(c currency.Code, chain string) (exchange.ChainDepositAddress, error) {
+	addresses, err := h.GetDepositAddresses(c.String())
+	if err != nil {
+		return exchange.ChainDepositAddress{}, err
+	}
+	a, ok := SelectDepositAddress(addresses, c, chain)
+	if !ok {
+		return exchange.ChainDepositAddress{}, fmt.Errorf("%s %s %s %v", h.Name, c, chain, exchange.ErrChainNotFound)
+	}
+	return exchange.ChainDepositAddress{
+		Currency: c,
+		Chain:    a.Chain,
+		Address:  a.Address,
+		Tag:      a.AddressTag,
+	}, nil
+}
+
+// SelectDepositAddress returns the address on the chain, or on the currency's
+// default chain when the chain is empty
+func SelectDepositAddress(addresses []DepositAddress, c currency.Code, chain string) (DepositAddress, bool) {
+	if len(addresses) == 0 {
+		return DepositAddress{}, false
+	}
+	match := chain
+	if chain == "" {
+		match = c.String()
+	}
+	for i := range addresses {
+		if strings.EqualFold(addresses[i].Chain, match) {
+			return addresses[i], true
+		}
+	}
+	if chain == "" {
+		return addresses[0], true
+	}
+	return DepositAddress{}, false
+}
+
+// ChainNetworkStatuses returns the funding status of each of a currency's
+// chains. Deposits to delisted currencies are disabled on every chain
+func ChainNetworkStatuses(c *CurrencyChains) []exchange.NetworkStatus {
+	resp := make([]exchange.NetworkStatus, 0, len(c.Chains))
+	for i := range c.Chains {
+		s := exchange.NetworkStatus{
+			Network:         c.Chains[i].DisplayName,
+			DepositEnabled:  c.Chains[i].DepositStatus == ChainStatusAllowed,
+			WithdrawEnabled: c.Chains[i].WithdrawStatus == ChainStatusAllowed,
+		}
+		if s.Network == "" {
+			s.Network = c.Chains[i].Chain
+		}
+		if c.InstStatus == CurrencyDelisted {
+			s.DepositEnabled = false
+			s.Reason = CurrencyDelisted
+		} else if !s.DepositEnabled || !s.WithdrawEnabled {
+			s.Reason = fmt.Sprintf("deposits %s, withdrawals %s",
+				c.Chains[i].DepositStatus, c.Chains[i].WithdrawStatus)
+		}
+		resp = append(resp, s)
+	}
+	return resp
+}
+
+// GetAssetStatus returns whether deposits and withdrawals of a currency are
+// enabled on each of its chains
+func (h *HUOBI) GetAssetStatus(c currency.Code) (exchange.AssetStatus, error) {
+	chains, err := h.GetCurrencyChains(c.String())
+	if err != nil {
+		return exchange.AssetStatus{}, err
+	}
+	for i := range chains {
+		if strings.EqualFold(chains[i].Currency, c.String()) {
+			return exchange.AssetStatus{
+				Exchange: h.Name,
+				Currency: c,
+				Networks: ChainNetworkStatuses(&chains[i]),
+			}, nil
+		}
+	}
+	return exchange.AssetStatus{}, fmt.Errorf("%s currency %s not found", h.Name, c)
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal is
@@ -534,3 +754,19 @@ func (h *HUOBI) GetSubscriptions() ([]wshandler.WebsocketChannelSubscription, er
 func (h *HUOBI) AuthenticateWebsocket() error {
 	return h.wsLogin()
 }
+
+// GetTradingRules returns the price and amount increments of a currency pair
+func (h *HUOBI) GetTradingRules(p currency.Pair) (exchange.TradingRules, error) {
+	symbols, err := h.GetSymbols()
+	if err != nil {
+		return exchange.TradingRules{}, err
+	}
+
+	for x := range symbols {
+		if strings.EqualFold(symbols[x].BaseCurrency, p.Base.String()) &&
+			strings.EqualFold(symbols[x].QuoteCurrency, p.Quote.String()) {
+			return symbols[x].TradingRules(), nil
+		}
+	}
+	return exchange.TradingRules{}, fmt.Errorf("%s symbol %s not found", h.Name, p)
+}
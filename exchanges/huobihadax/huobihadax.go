@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
@@ -885,11 +886,28 @@ func (h *HUOBIHADAX) SendHTTPRequest(path string, result interface{}) error {
 	return h.SendPayload(http.MethodGet, path, nil, nil, result, false, false, h.Verbose, h.HTTPDebugging)
 }
 
+// mutating returns whether the authenticated endpoint changes exchange state.
+// Endpoints holding order and withdrawal IDs are matched by the action they
+// end with, orders are placed under the HADAX API name
+func mutating(endpoint string) bool {
+	switch strings.TrimPrefix(endpoint, huobihadaxAPIName+"/") {
+	case huobihadaxOrderPlace, huobihadaxOrderCancelBatch, huobiHadaxBatchCancelOpenOrders,
+		huobihadaxMarginTransferIn, huobihadaxMarginTransferOut, huobihadaxMarginOrders,
+		huobihadaxWithdrawCreate:
+		return true
+	}
+	return strings.HasSuffix(endpoint, "/submitcancel") || strings.HasSuffix(endpoint, "/repay") ||
+		strings.HasSuffix(endpoint, "/cancel")
+}
+
 // SendAuthenticatedHTTPPostRequest sends authenticated requests to the HUOBI API
 func (h *HUOBIHADAX) SendAuthenticatedHTTPPostRequest(method, endpoint, postBodyValues string, result interface{}) error {
 	if !h.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, h.Name)
 	}
+	if h.DryRunRequest(mutating(endpoint), endpoint, postBodyValues) {
+		return nil
+	}
 
 	signatureParams := url.Values{}
 	signatureParams.Set("AccessKeyId", h.APIKey)
@@ -917,6 +935,9 @@ func (h *HUOBIHADAX) SendAuthenticatedHTTPRequest(method, endpoint string, value
 	if !h.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, h.Name)
 	}
+	if h.DryRunRequest(mutating(endpoint), endpoint, values) {
+		return nil
+	}
 
 	values.Set("AccessKeyId", h.APIKey)
 	values.Set("SignatureMethod", "HmacSHA256")
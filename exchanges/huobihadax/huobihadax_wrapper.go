@@ -211,6 +211,24 @@ func (h *HUOBIHADAX) GetAccountInfo() (exchange.AccountInfo, error) {
 	return info, nil
 }
 
+// GetSubAccountBalances returns the balances of all sub-users aggregated as a
+// single account
+func (h *HUOBIHADAX) GetSubAccountBalances() ([]exchange.Account, error) {
+	balances, err := h.GetAggregatedBalance()
+	if err != nil {
+		return nil, err
+	}
+
+	acc := exchange.Account{ID: "subusers"}
+	for _, balance := range balances {
+		acc.Currencies = append(acc.Currencies, exchange.AccountCurrencyInfo{
+			CurrencyName: currency.NewCode(balance.Currency),
+			TotalValue:   balance.Balance,
+		})
+	}
+	return []exchange.Account{acc}, nil
+}
+
 // GetFundingHistory returns funding history, deposits and
 // withdrawals
 func (h *HUOBIHADAX) GetFundingHistory() ([]exchange.FundHistory, error) {
@@ -0,0 +1,96 @@
+package exchange
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// ImbalanceTrigger defines what an imbalance order does once the order book
+// imbalance crosses its threshold
+type ImbalanceTrigger string
+
+// Imbalance order triggers
+const (
+	// ImbalancePlace rests nothing until the imbalance crosses the threshold,
+	// then places the limit order
+	ImbalancePlace ImbalanceTrigger = "PLACE"
+	// ImbalanceAggress rests the limit order passively and replaces its
+	// remainder with a market order once the imbalance crosses the threshold
+	ImbalanceAggress ImbalanceTrigger = "AGGRESS"
+)
+
+// DefaultImbalanceLevels is the number of levels per side the imbalance is
+// measured over when not set
+const DefaultImbalanceLevels = 5
+
+var (
+	errImbalanceSide      = errors.New("imbalance order side must be buy or sell")
+	errImbalanceAmount    = errors.New("imbalance order amount must be positive")
+	errImbalancePrice     = errors.New("imbalance order price cannot be negative")
+	errImbalanceTrigger   = errors.New("imbalance order trigger must be PLACE or AGGRESS")
+	errImbalanceThreshold = errors.New("imbalance order threshold must be above 0 and at most 1")
+	errImbalanceLevels    = errors.New("imbalance order levels cannot be negative")
+)
+
+// ImbalanceOrder defines a limit order which is only placed, or turned
+// aggressive, once the order book imbalance on its side crosses a threshold.
+// It suits passive execution which chases the market only on momentum
+type ImbalanceOrder struct {
+	Pair      currency.Pair `json:"pair"`
+	AssetType string        `json:"assetType,omitempty"`
+	Side      OrderSide     `json:"side"`
+	Amount    float64       `json:"amount"`
+	// Price is the limit price, the order joins the best bid or ask when it is
+	// placed if zero
+	Price   float64          `json:"price"`
+	Trigger ImbalanceTrigger `json:"trigger"`
+	// Threshold is the imbalance in favour of the order's side which triggers
+	// it, e.g. 0.4 triggers a buy once bids outweigh asks 70:30 and a sell
+	// once asks outweigh bids 70:30
+	Threshold float64 `json:"threshold"`
+	// Levels is the number of levels per side the imbalance is measured over,
+	// DefaultImbalanceLevels when zero
+	Levels int `json:"levels,omitempty"`
+}
+
+// Validate checks the imbalance order is complete
+func (o *ImbalanceOrder) Validate() error {
+	if o.Side != BuyOrderSide && o.Side != SellOrderSide {
+		return errImbalanceSide
+	}
+	if o.Amount <= 0 {
+		return errImbalanceAmount
+	}
+	if o.Price < 0 {
+		return errImbalancePrice
+	}
+	if o.Trigger != ImbalancePlace && o.Trigger != ImbalanceAggress {
+		return errImbalanceTrigger
+	}
+	if o.Threshold <= 0 || o.Threshold > 1 {
+		return errImbalanceThreshold
+	}
+	if o.Levels < 0 {
+		return errImbalanceLevels
+	}
+	return nil
+}
+
+// Triggered returns whether the imbalance crosses the order's threshold in
+// favour of its side. Imbalances range from -1 when only asks rest to 1 when
+// only bids rest
+func (o *ImbalanceOrder) Triggered(imbalance float64) bool {
+	if o.Side == SellOrderSide {
+		return imbalance <= -o.Threshold
+	}
+	return imbalance >= o.Threshold
+}
+
+// GetLevels returns the number of levels the imbalance is measured over
+func (o *ImbalanceOrder) GetLevels() int {
+	if o.Levels > 0 {
+		return o.Levels
+	}
+	return DefaultImbalanceLevels
+}
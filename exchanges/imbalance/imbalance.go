@@ -0,0 +1,549 @@
+// Package imbalance works imbalance orders: limit orders which are only placed,
+// or turned aggressive, once the order book imbalance on their side crosses a
+// threshold. Place orders rest nothing until the book leans their way. Aggress
+// orders rest passively and replace their unfilled remainder with a market
+// order once the book leans their way, chasing the market only on momentum.
+// Orders are persisted so open orders resume after a restart
+package imbalance
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// DefaultCheckInterval is how often the order book of open orders is checked
+const DefaultCheckInterval = time.Second
+
+// State defines the stage of an imbalance order
+type State string
+
+// Imbalance order states
+const (
+	// Armed orders wait for the imbalance before placing their limit order
+	Armed State = "ARMED"
+	// Resting orders have their limit order on the book
+	Resting State = "RESTING"
+	// Aggressing orders have cancelled their limit order and retry the
+	// market order for its remainder until it is placed
+	Aggressing State = "AGGRESSING"
+	// Aggressed orders have sent their remainder as a market order
+	Aggressed State = "AGGRESSED"
+	Filled    State = "FILLED"
+	Cancelled State = "CANCELLED"
+)
+
+// Event types emitted by the manager
+const (
+	Triggered = "IMBALANCE_TRIGGERED"
+	Completed = "IMBALANCE_FILLED"
+	Failed    = "IMBALANCE_FAILED"
+)
+
+var (
+	errNoExchanges       = errors.New("no exchange lookup supplied")
+	errNoBookFunc        = errors.New("no orderbook function supplied")
+	errExchangeNotLoaded = errors.New("exchange not loaded")
+	errOrderNotFound     = errors.New("imbalance order not found")
+	errOrderDone         = errors.New("imbalance order already completed")
+	errOrderAggressed    = errors.New("imbalance order already sent to market")
+	errNotAcknowledged   = errors.New("order not acknowledged")
+	errEmptyBook         = errors.New("orderbook side empty, no price to join")
+)
+
+// Order holds an imbalance order and the state of the orders placed for it
+type Order struct {
+	ID       string                  `json:"id"`
+	Exchange string                  `json:"exchange"`
+	Order    exchange.ImbalanceOrder `json:"order"`
+	State    State                   `json:"state"`
+	// Price is the limit price the resting order was placed at
+	Price        float64 `json:"price,omitempty"`
+	RestingID    string  `json:"restingID,omitempty"`
+	AggressiveID string  `json:"aggressiveID,omitempty"`
+	// Passive is the amount the resting order filled, Aggressive the
+	// remainder sent to market
+	Passive    float64 `json:"passive"`
+	Aggressive float64 `json:"aggressive"`
+	// Imbalance is the last imbalance measured on the order's book
+	Imbalance float64   `json:"imbalance"`
+	Triggered time.Time `json:"triggered,omitempty"`
+	Error     string    `json:"error,omitempty"`
+	Created   time.Time `json:"created"`
+	Updated   time.Time `json:"updated"`
+}
+
+// Open returns whether the order is still worked
+func (o *Order) Open() bool {
+	return o.State != Filled && o.State != Cancelled
+}
+
+// Event defines an imbalance order triggering, filling or failing
+type Event struct {
+	Type   string
+	Order  Order
+	Detail string
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	return fmt.Sprintf("%s imbalance order %s %s %s %s: %s", e.Order.Exchange, e.Order.ID,
+		e.Order.Order.Side, e.Order.Order.Pair, e.Type, e.Detail)
+}
+
+// ExchangeFunc returns a loaded exchange by name, nil when not loaded
+type ExchangeFunc func(name string) exchange.IBotExchange
+
+// BookFunc returns the current orderbook of a pair on an exchange
+type BookFunc func(exchangeName string, p currency.Pair, assetType string) (orderbook.Base, error)
+
+// Manager submits imbalance orders and works them until they fill
+type Manager struct {
+	path      string
+	exchanges ExchangeFunc
+	books     BookFunc
+	onEvent   func(Event)
+	orders    map[string]*Order
+	// via holds the exchanges orders submitted through a wrapped exchange,
+	// such as a strategy's, are placed with while the bot runs
+	via      map[string]exchange.IBotExchange
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+	// checkMtx serialises the order changes of checks and cancellations
+	checkMtx sync.Mutex
+}
+
+// New returns an imbalance order manager persisting orders to path. A missing
+// file starts with no orders, an empty path keeps orders in memory only
+func New(path string, exchanges ExchangeFunc, books BookFunc, onEvent func(Event)) (*Manager, error) {
+	if exchanges == nil {
+		return nil, errNoExchanges
+	}
+	if books == nil {
+		return nil, errNoBookFunc
+	}
+	m := &Manager{
+		path:      path,
+		exchanges: exchanges,
+		books:     books,
+		onEvent:   onEvent,
+		orders:    make(map[string]*Order),
+		via:       make(map[string]exchange.IBotExchange),
+	}
+	if path == "" {
+		return m, nil
+	}
+
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, err
+	}
+	var orders []*Order
+	err = json.Unmarshal(data, &orders)
+	if err != nil {
+		return nil, err
+	}
+	for i := range orders {
+		m.orders[orders[i].ID] = orders[i]
+	}
+	return m, nil
+}
+
+// save writes the orders to the manager's file, the caller must hold the lock
+func (m *Manager) save() error {
+	if m.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(m.list(), "", " ")
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(m.path, data)
+}
+
+func (m *Manager) list() []Order {
+	orders := make([]Order, 0, len(m.orders))
+	for _, o := range m.orders {
+		orders = append(orders, *o)
+	}
+	sort.Slice(orders, func(i, j int) bool {
+		return orders[i].Created.Before(orders[j].Created)
+	})
+	return orders
+}
+
+// store records the order and persists the manager's orders
+func (m *Manager) store(o *Order) {
+	o.Updated = time.Now()
+	stored := *o
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.orders[o.ID] = &stored
+	if !o.Open() {
+		delete(m.via, o.ID)
+	}
+	if err := m.save(); err != nil {
+		log.Errorf("Imbalance order manager failed to save orders: %s", err)
+	}
+}
+
+// orderExchange returns the exchange an order is placed with
+func (m *Manager) orderExchange(o *Order) exchange.IBotExchange {
+	m.mtx.Lock()
+	exch, ok := m.via[o.ID]
+	m.mtx.Unlock()
+	if ok {
+		return exch
+	}
+	return m.exchanges(o.Exchange)
+}
+
+// Submit starts working an imbalance order on the named exchange
+func (m *Manager) Submit(exchName string, order *exchange.ImbalanceOrder) (Order, error) {
+	exch := m.exchanges(exchName)
+	if exch == nil {
+		return Order{}, fmt.Errorf("%s %v", exchName, errExchangeNotLoaded)
+	}
+	return m.submit(exch, order, false)
+}
+
+// SubmitVia starts working an imbalance order placed through the exchange,
+// so the orders of a strategy pass through its limits. Orders resumed after a
+// restart are placed with the named exchange
+func (m *Manager) SubmitVia(exch exchange.IBotExchange, order *exchange.ImbalanceOrder) (Order, error) {
+	if exch == nil {
+		return Order{}, errExchangeNotLoaded
+	}
+	return m.submit(exch, order, true)
+}
+
+func (m *Manager) submit(exch exchange.IBotExchange, order *exchange.ImbalanceOrder, via bool) (Order, error) {
+	err := order.Validate()
+	if err != nil {
+		return Order{}, err
+	}
+	id, err := common.GetRandomSalt(nil, 8)
+	if err != nil {
+		return Order{}, err
+	}
+	o := Order{
+		ID:       common.HexEncodeToString(id),
+		Exchange: exch.GetName(),
+		Order:    *order,
+		State:    Armed,
+		Created:  time.Now(),
+	}
+
+	m.checkMtx.Lock()
+	defer m.checkMtx.Unlock()
+	if order.Trigger == exchange.ImbalanceAggress {
+		// Aggress orders rest passively from the start
+		if err = m.place(exch, &o); err != nil {
+			return Order{}, err
+		}
+	}
+	if via {
+		m.mtx.Lock()
+		m.via[o.ID] = exch
+		m.mtx.Unlock()
+	}
+	m.store(&o)
+	log.Debugf("%s imbalance order %s submitted %s %v %s %s at imbalance %v",
+		o.Exchange, o.ID, order.Side, order.Amount, order.Pair, order.Trigger, order.Threshold)
+	return o, nil
+}
+
+// place places the order's resting limit order, joining the best price on its
+// side when no price is set
+func (m *Manager) place(exch exchange.IBotExchange, o *Order) error {
+	price := o.Order.Price
+	if price == 0 {
+		book, err := m.books(o.Exchange, o.Order.Pair, o.Order.AssetType)
+		if err != nil {
+			return err
+		}
+		side := book.Bids
+		if o.Order.Side == exchange.SellOrderSide {
+			side = book.Asks
+		}
+		if len(side) == 0 {
+			return errEmptyBook
+		}
+		price = side[0].Price
+	}
+	resp, err := exch.SubmitOrder(o.Order.Pair, o.Order.Side, exchange.LimitOrderType,
+		o.Order.Amount, price, o.ID)
+	if err == nil && resp.OrderID == "" {
+		err = errNotAcknowledged
+	}
+	if err != nil {
+		return err
+	}
+	o.RestingID = resp.OrderID
+	o.Price = price
+	o.State = Resting
+	return nil
+}
+
+// Cancel stops working an order, cancelling its resting limit order. Orders
+// already sent to market cannot be cancelled
+func (m *Manager) Cancel(id string) (Order, error) {
+	m.checkMtx.Lock()
+	defer m.checkMtx.Unlock()
+
+	o, err := m.Get(id)
+	if err != nil {
+		return Order{}, err
+	}
+	switch o.State {
+	case Filled, Cancelled:
+		return o, fmt.Errorf("%s %v", id, errOrderDone)
+	case Aggressing, Aggressed:
+		return o, fmt.Errorf("%s %v", id, errOrderAggressed)
+	case Resting:
+		exch := m.orderExchange(&o)
+		if exch == nil {
+			return o, fmt.Errorf("%s %v", o.Exchange, errExchangeNotLoaded)
+		}
+		err = exch.CancelOrder(&exchange.OrderCancellation{
+			OrderID:      o.RestingID,
+			CurrencyPair: o.Order.Pair,
+			Side:         o.Order.Side,
+		})
+		if err != nil {
+			o.Error = err.Error()
+			m.store(&o)
+			return o, fmt.Errorf("%s imbalance order %s failed to cancel %s", o.Exchange, id, o.Error)
+		}
+	}
+	o.State = Cancelled
+	o.Error = ""
+	m.store(&o)
+	return o, nil
+}
+
+// Get returns an order by ID
+func (m *Manager) Get(id string) (Order, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	o, ok := m.orders[id]
+	if !ok {
+		return Order{}, fmt.Errorf("%s %v", id, errOrderNotFound)
+	}
+	return *o, nil
+}
+
+// List returns every order, oldest first
+func (m *Manager) List() []Order {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.list()
+}
+
+// Check measures the imbalance of every open order's book and advances it
+func (m *Manager) Check() {
+	m.checkMtx.Lock()
+	defer m.checkMtx.Unlock()
+
+	for _, o := range m.List() {
+		if !o.Open() {
+			continue
+		}
+		exch := m.orderExchange(&o)
+		if exch == nil {
+			log.Debugf("Imbalance order manager skipping %s order %s: %v", o.Exchange, o.ID, errExchangeNotLoaded)
+			continue
+		}
+		prev := o
+		events := m.check(exch, &o)
+		if o != prev {
+			m.store(&o)
+		}
+		for i := range events {
+			events[i].Order = o
+			log.Debugln(events[i].String())
+			if m.onEvent != nil {
+				m.onEvent(events[i])
+			}
+		}
+	}
+}
+
+// check advances an order, returning the events raised
+func (m *Manager) check(exch exchange.IBotExchange, o *Order) []Event {
+	switch o.State {
+	case Armed:
+		if !m.triggered(o) {
+			return nil
+		}
+		if err := m.place(exch, o); err != nil {
+			o.Error = err.Error()
+			return []Event{{Type: Failed, Detail: "place: " + o.Error}}
+		}
+		o.Triggered = time.Now()
+		o.Error = ""
+		return []Event{{Type: Triggered, Detail: fmt.Sprintf("imbalance %.2f, resting at %v", o.Imbalance, o.Price)}}
+	case Resting:
+		return m.checkResting(exch, o)
+	case Aggressing:
+		return m.aggress(exch, o)
+	case Aggressed:
+		return m.checkAggressive(exch, o)
+	}
+	return nil
+}
+
+// triggered measures the imbalance of the order's book, returning whether it
+// crosses the order's threshold
+func (m *Manager) triggered(o *Order) bool {
+	book, err := m.books(o.Exchange, o.Order.Pair, o.Order.AssetType)
+	if err != nil {
+		log.Debugf("Imbalance order manager %s order %s orderbook unavailable: %s", o.Exchange, o.ID, err)
+		return false
+	}
+	o.Imbalance = book.Imbalance(o.Order.GetLevels())
+	return o.Order.Triggered(o.Imbalance)
+}
+
+// checkResting records the resting order's fills, replacing its remainder
+// with a market order once an aggress order's imbalance triggers
+func (m *Manager) checkResting(exch exchange.IBotExchange, o *Order) []Event {
+	info, err := exch.GetOrderInfo(o.RestingID)
+	if err != nil {
+		o.Error = err.Error()
+		return nil
+	}
+	o.Error = ""
+	o.Passive = info.ExecutedAmount
+	switch {
+	case info.Filled():
+		o.Passive = o.Order.Amount
+		o.State = Filled
+		return []Event{{Type: Completed, Detail: fmt.Sprintf("filled passively at %v", o.Price)}}
+	case info.Closed():
+		o.State = Cancelled
+		return []Event{{Type: Failed, Detail: "resting order " + info.Status}}
+	}
+	if o.Order.Trigger != exchange.ImbalanceAggress || !m.triggered(o) {
+		return nil
+	}
+
+	// The resting order must be gone before its remainder is sent to market
+	// or both could fill
+	err = exch.CancelOrder(&exchange.OrderCancellation{
+		OrderID:      o.RestingID,
+		CurrencyPair: o.Order.Pair,
+		Side:         o.Order.Side,
+	})
+	if err != nil {
+		o.Error = "cancel resting order: " + err.Error()
+		return []Event{{Type: Failed, Detail: o.Error}}
+	}
+	if info, err = exch.GetOrderInfo(o.RestingID); err == nil {
+		o.Passive = info.ExecutedAmount
+	}
+	o.Triggered = time.Now()
+	o.State = Aggressing
+	return m.aggress(exch, o)
+}
+
+// aggress sends the order's remainder to market
+func (m *Manager) aggress(exch exchange.IBotExchange, o *Order) []Event {
+	remaining := o.Order.Amount - o.Passive
+	if remaining <= 0 {
+		o.State = Filled
+		return []Event{{Type: Completed, Detail: "filled passively before aggressing"}}
+	}
+	resp, err := exch.SubmitOrder(o.Order.Pair, o.Order.Side, exchange.MarketOrderType,
+		remaining, 0, o.ID+"-mkt")
+	if err == nil && resp.OrderID == "" {
+		err = errNotAcknowledged
+	}
+	if err != nil {
+		o.Error = "aggress: " + err.Error()
+		return []Event{{Type: Failed, Detail: o.Error}}
+	}
+	o.AggressiveID = resp.OrderID
+	o.Aggressive = remaining
+	o.State = Aggressed
+	o.Error = ""
+	return []Event{{Type: Triggered, Detail: fmt.Sprintf("imbalance %.2f, sent %v to market after %v filled passively",
+		o.Imbalance, remaining, o.Passive)}}
+}
+
+// checkAggressive records the market order filling
+func (m *Manager) checkAggressive(exch exchange.IBotExchange, o *Order) []Event {
+	info, err := exch.GetOrderInfo(o.AggressiveID)
+	if err != nil {
+		o.Error = err.Error()
+		return nil
+	}
+	o.Error = ""
+	switch {
+	case info.Filled():
+		o.State = Filled
+		return []Event{{Type: Completed, Detail: fmt.Sprintf("%v filled passively, %v aggressively",
+			o.Passive, o.Aggressive)}}
+	case info.Closed():
+		o.State = Cancelled
+		return []Event{{Type: Failed, Detail: "market order " + info.Status}}
+	}
+	return nil
+}
+
+// Start checks the open orders at the interval until stopped
+func (m *Manager) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			m.Check()
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the manager
+func (m *Manager) Stop() {
+	m.mtx.Lock()
+	if m.shutdown == nil {
+		m.mtx.Unlock()
+		return
+	}
+	close(m.shutdown)
+	m.shutdown = nil
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
@@ -0,0 +1,207 @@
+package imbalance
+
+import (
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	orders    map[string]*exchange.OrderDetail
+	cancelled []string
+}
+
+func newTestExchange() *testExchange {
+	return &testExchange{orders: make(map[string]*exchange.OrderDetail)}
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	id := fmt.Sprintf("%d", len(t.orders)+1)
+	t.orders[id] = &exchange.OrderDetail{
+		ID:           id,
+		CurrencyPair: p,
+		OrderSide:    side,
+		OrderType:    orderType,
+		Price:        price,
+		Amount:       amount,
+	}
+	return exchange.SubmitOrderResponse{OrderID: id, IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	o, ok := t.orders[orderID]
+	if !ok {
+		return exchange.OrderDetail{}, fmt.Errorf("order %s not found", orderID)
+	}
+	return *o, nil
+}
+
+func (t *testExchange) CancelOrder(order *exchange.OrderCancellation) error {
+	t.cancelled = append(t.cancelled, order.OrderID)
+	t.orders[order.OrderID].Status = string(exchange.CancelledOrderStatus)
+	return nil
+}
+
+// testBook returns a book whose best level imbalance is (bid-ask)/(bid+ask)
+func testBook(bid, ask float64) orderbook.Base {
+	return orderbook.Base{
+		Bids: []orderbook.Item{{Price: 100, Amount: bid}},
+		Asks: []orderbook.Item{{Price: 101, Amount: ask}},
+	}
+}
+
+func newTestManager(t *testing.T, exch exchange.IBotExchange, path string, book *orderbook.Base, events *[]Event) *Manager {
+	m, err := New(path,
+		func(string) exchange.IBotExchange { return exch },
+		func(string, currency.Pair, string) (orderbook.Base, error) { return *book, nil },
+		func(e Event) { *events = append(*events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func testOrder(trigger exchange.ImbalanceTrigger) *exchange.ImbalanceOrder {
+	return &exchange.ImbalanceOrder{
+		Pair:      currency.NewPairWithDelimiter("BTC", "USD", "-"),
+		Side:      exchange.BuyOrderSide,
+		Amount:    2,
+		Trigger:   trigger,
+		Threshold: 0.5,
+		Levels:    1,
+	}
+}
+
+func TestPlaceOnImbalance(t *testing.T) {
+	exch := newTestExchange()
+	book := testBook(1, 1)
+	var events []Event
+	m := newTestManager(t, exch, "", &book, &events)
+
+	o, err := m.Submit("test", testOrder(exchange.ImbalancePlace))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.State != Armed || len(exch.orders) != 0 {
+		t.Fatalf("Test Failed - Submit() expected nothing placed until triggered %+v", o)
+	}
+
+	// Bids outweighing asks 70:30 stay below the threshold
+	book = testBook(7, 3)
+	m.Check()
+	if len(exch.orders) != 0 {
+		t.Fatal("Test Failed - Check() expected no order below the threshold")
+	}
+
+	book = testBook(8, 2)
+	m.Check()
+	o, _ = m.Get(o.ID)
+	if o.State != Resting || o.Price != 100 || len(events) != 1 || events[0].Type != Triggered {
+		t.Fatalf("Test Failed - Check() expected the order placed at the best bid %+v %+v", o, events)
+	}
+	if exch.orders[o.RestingID].OrderType != exchange.LimitOrderType {
+		t.Error("Test Failed - Check() expected a limit order")
+	}
+
+	exch.orders[o.RestingID].ExecutedAmount = 2
+	m.Check()
+	o, _ = m.Get(o.ID)
+	if o.State != Filled || o.Passive != 2 || len(events) != 2 || events[1].Type != Completed {
+		t.Errorf("Test Failed - Check() expected the order filled %+v", o)
+	}
+}
+
+func TestAggressOnImbalance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "imbalance")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "imbalance.json")
+
+	exch := newTestExchange()
+	book := testBook(1, 1)
+	var events []Event
+	m := newTestManager(t, exch, path, &book, &events)
+
+	order := testOrder(exchange.ImbalanceAggress)
+	order.Price = 99
+	o, err := m.Submit("test", order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.State != Resting || exch.orders[o.RestingID].Price != 99 {
+		t.Fatalf("Test Failed - Submit() expected the order resting at its price %+v", o)
+	}
+
+	// The order rests passively, partially filling, until the book leans
+	exch.orders[o.RestingID].ExecutedAmount = 0.5
+	m.Check()
+	if o, _ = m.Get(o.ID); o.State != Resting || o.Passive != 0.5 {
+		t.Fatalf("Test Failed - Check() expected the order to keep resting %+v", o)
+	}
+
+	// Restarted managers resume the order
+	m = newTestManager(t, exch, path, &book, &events)
+	book = testBook(9, 1)
+	m.Check()
+	o, _ = m.Get(o.ID)
+	if o.State != Aggressed || len(exch.cancelled) != 1 || exch.cancelled[0] != o.RestingID {
+		t.Fatalf("Test Failed - Check() expected the resting order replaced %+v", o)
+	}
+	mkt := exch.orders[o.AggressiveID]
+	if mkt.OrderType != exchange.MarketOrderType || mkt.Amount != 1.5 || o.Aggressive != 1.5 {
+		t.Errorf("Test Failed - Check() expected the remainder sent to market %+v", mkt)
+	}
+	if _, err = m.Cancel(o.ID); err == nil {
+		t.Error("Test Failed - Cancel() expected an aggressed order not to cancel")
+	}
+
+	mkt.Status = string(exchange.FilledOrderStatus)
+	m.Check()
+	if o, _ = m.Get(o.ID); o.State != Filled || events[len(events)-1].Type != Completed {
+		t.Errorf("Test Failed - Check() expected the order filled %+v", o)
+	}
+}
+
+func TestCancel(t *testing.T) {
+	exch := newTestExchange()
+	book := orderbook.Base{}
+	var events []Event
+	m := newTestManager(t, exch, "", &book, &events)
+
+	order := testOrder(exchange.ImbalanceAggress)
+	if _, err := m.Submit("test", order); err != errEmptyBook {
+		t.Error("Test Failed - Submit() expected empty book error", err)
+	}
+	order.Threshold = 0
+	if _, err := m.Submit("test", order); err == nil {
+		t.Error("Test Failed - Submit() expected invalid threshold error")
+	}
+
+	book = testBook(1, 1)
+	order.Threshold = 0.5
+	order.Side = exchange.SellOrderSide
+	o, err := m.Submit("test", order)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if o.Price != 101 {
+		t.Errorf("Test Failed - Submit() expected a sell to join the best ask %+v", o)
+	}
+	if o, err = m.Cancel(o.ID); err != nil || o.State != Cancelled || len(exch.cancelled) != 1 {
+		t.Errorf("Test Failed - Cancel() expected the resting order cancelled %+v %v", o, err)
+	}
+	if _, err = m.Cancel(o.ID); err == nil {
+		t.Error("Test Failed - Cancel() expected already completed error")
+	}
+}
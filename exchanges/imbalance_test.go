@@ -0,0 +1,37 @@
+package exchange
+
+import "testing"
+
+func TestImbalanceOrderValidate(t *testing.T) {
+	tests := []struct {
+		order ImbalanceOrder
+		err   error
+	}{
+		{ImbalanceOrder{Side: BuyOrderSide, Amount: 1, Trigger: ImbalancePlace, Threshold: 0.4}, nil},
+		{ImbalanceOrder{Side: SellOrderSide, Amount: 1, Price: 100, Trigger: ImbalanceAggress, Threshold: 1, Levels: 10}, nil},
+		{ImbalanceOrder{Side: AnyOrderSide, Amount: 1, Trigger: ImbalancePlace, Threshold: 0.4}, errImbalanceSide},
+		{ImbalanceOrder{Side: BuyOrderSide, Trigger: ImbalancePlace, Threshold: 0.4}, errImbalanceAmount},
+		{ImbalanceOrder{Side: BuyOrderSide, Amount: 1, Price: -1, Trigger: ImbalancePlace, Threshold: 0.4}, errImbalancePrice},
+		{ImbalanceOrder{Side: BuyOrderSide, Amount: 1, Trigger: "CHASE", Threshold: 0.4}, errImbalanceTrigger},
+		{ImbalanceOrder{Side: BuyOrderSide, Amount: 1, Trigger: ImbalancePlace, Threshold: 1.5}, errImbalanceThreshold},
+		{ImbalanceOrder{Side: BuyOrderSide, Amount: 1, Trigger: ImbalancePlace, Threshold: 0.4, Levels: -1}, errImbalanceLevels},
+	}
+	for i := range tests {
+		if err := tests[i].order.Validate(); err != tests[i].err {
+			t.Errorf("Test Failed - ImbalanceOrder Validate() %+v expected %v, received %v",
+				tests[i].order, tests[i].err, err)
+		}
+	}
+
+	buy := ImbalanceOrder{Side: BuyOrderSide, Threshold: 0.4}
+	sell := ImbalanceOrder{Side: SellOrderSide, Threshold: 0.4}
+	if !buy.Triggered(0.4) || buy.Triggered(-0.6) {
+		t.Error("Test Failed - ImbalanceOrder Triggered() expected buys triggered by bids outweighing asks")
+	}
+	if !sell.Triggered(-0.5) || sell.Triggered(0.6) {
+		t.Error("Test Failed - ImbalanceOrder Triggered() expected sells triggered by asks outweighing bids")
+	}
+	if buy.GetLevels() != DefaultImbalanceLevels {
+		t.Error("Test Failed - ImbalanceOrder GetLevels() expected default levels")
+	}
+}
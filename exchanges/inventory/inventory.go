@@ -0,0 +1,205 @@
+// Package inventory skews a market maker's quotes on each venue by where its
+// inventory of the traded asset sits. A venue holding more than its target
+// share of the inventory lowers its quotes and shrinks its bids so fills there
+// tend to sell, while a venue holding less raises its quotes and shrinks its
+// offers. Inventory is rebalanced through trading rather than cross-venue
+// transfers
+package inventory
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// DefaultMaxSkewBps is the price skew in basis points applied on a venue
+// holding the entire inventory above its target share
+const DefaultMaxSkewBps = 10
+
+var (
+	errNoInventoryFunc = errors.New("no inventory function supplied")
+	errInvalidSkew     = errors.New("max skew cannot be negative and size skew must be between 0 and 1")
+	errInvalidTarget   = errors.New("venue target shares must be positive")
+	errVenueNotQuoted  = errors.New("venue not quoting the asset")
+)
+
+// Holding holds the inventory of an asset on a venue, balances and positions
+// combined
+type Holding struct {
+	Exchange string
+	Amount   float64
+}
+
+// InventoryFunc returns the inventory of an asset held on each venue
+type InventoryFunc func(c currency.Code) ([]Holding, error)
+
+// Config defines how far quotes are skewed
+type Config struct {
+	// MaxSkewBps is the price skew applied when a venue's excess over its
+	// target reaches the whole inventory
+	MaxSkewBps float64
+	// MaxSizeSkew is the fraction the accumulating side's quote size shrinks
+	// by at the same excess, zero leaves sizes unchanged
+	MaxSizeSkew float64
+	// Targets holds each quoting venue's target share of the inventory,
+	// inventory is split equally between the venues holding it when empty
+	Targets map[string]float64
+}
+
+// Skew holds the skew applied to a venue's quotes of an asset
+type Skew struct {
+	Exchange string        `json:"exchange"`
+	Currency currency.Code `json:"currency"`
+	Holding  float64       `json:"holding"`
+	Target   float64       `json:"target"`
+	Total    float64       `json:"total"`
+	// Excess is the venue's holding above, or below when negative, its
+	// target as a fraction of the total inventory
+	Excess float64 `json:"excess"`
+	// PriceBps is added to both quotes, negative when the venue is long
+	PriceBps float64 `json:"priceBps"`
+	// BidSize and AskSize scale the quote sizes
+	BidSize float64 `json:"bidSize"`
+	AskSize float64 `json:"askSize"`
+}
+
+// Quote holds a two sided quote
+type Quote struct {
+	Bid       float64
+	BidAmount float64
+	Ask       float64
+	AskAmount float64
+}
+
+// Apply returns the quote skewed
+func (s *Skew) Apply(q Quote) Quote {
+	m := 1 + s.PriceBps/10000
+	return Quote{
+		Bid:       q.Bid * m,
+		BidAmount: q.BidAmount * s.BidSize,
+		Ask:       q.Ask * m,
+		AskAmount: q.AskAmount * s.AskSize,
+	}
+}
+
+// Skewer skews quotes by inventory location
+type Skewer struct {
+	cfg       Config
+	targets   map[string]float64
+	inventory InventoryFunc
+}
+
+// New returns a skewer reading inventory from the supplied function
+func New(cfg Config, inventory InventoryFunc) (*Skewer, error) {
+	if inventory == nil {
+		return nil, errNoInventoryFunc
+	}
+	if cfg.MaxSkewBps < 0 || cfg.MaxSizeSkew < 0 || cfg.MaxSizeSkew > 1 {
+		return nil, errInvalidSkew
+	}
+	if cfg.MaxSkewBps == 0 {
+		cfg.MaxSkewBps = DefaultMaxSkewBps
+	}
+	var sum float64
+	for venue, share := range cfg.Targets {
+		if share <= 0 {
+			return nil, fmt.Errorf("%s %v", venue, errInvalidTarget)
+		}
+		sum += share
+	}
+	targets := make(map[string]float64, len(cfg.Targets))
+	for venue, share := range cfg.Targets {
+		targets[venue] = share / sum
+	}
+	return &Skewer{cfg: cfg, targets: targets, inventory: inventory}, nil
+}
+
+// shares returns each venue's holding and target share keyed by exchange
+func (s *Skewer) shares(holdings []Holding) (held, targets map[string]float64) {
+	held = make(map[string]float64)
+	for i := range holdings {
+		venue := holdings[i].Exchange
+		for target := range s.targets {
+			if strings.EqualFold(target, venue) {
+				venue = target
+			}
+		}
+		held[venue] += holdings[i].Amount
+	}
+	if len(s.targets) > 0 {
+		return held, s.targets
+	}
+	targets = make(map[string]float64, len(held))
+	for venue := range held {
+		targets[venue] = 1 / float64(len(held))
+	}
+	return held, targets
+}
+
+// GetSkews returns the skew of every venue quoting the asset ordered by
+// exchange
+func (s *Skewer) GetSkews(c currency.Code) ([]Skew, error) {
+	holdings, err := s.inventory(c)
+	if err != nil {
+		return nil, err
+	}
+	held, targets := s.shares(holdings)
+
+	var total float64
+	for venue := range targets {
+		total += held[venue]
+	}
+	resp := make([]Skew, 0, len(targets))
+	for venue, share := range targets {
+		k := Skew{
+			Exchange: venue,
+			Currency: c,
+			Holding:  held[venue],
+			Target:   total * share,
+			Total:    total,
+			BidSize:  1,
+			AskSize:  1,
+		}
+		if total > 0 {
+			k.Excess = math.Max(-1, math.Min(1, (k.Holding-k.Target)/total))
+			k.PriceBps = -k.Excess * s.cfg.MaxSkewBps
+			if k.Excess > 0 {
+				k.BidSize = 1 - k.Excess*s.cfg.MaxSizeSkew
+			} else {
+				k.AskSize = 1 + k.Excess*s.cfg.MaxSizeSkew
+			}
+		}
+		resp = append(resp, k)
+	}
+	sort.Slice(resp, func(i, j int) bool { return resp[i].Exchange < resp[j].Exchange })
+	return resp, nil
+}
+
+// GetSkew returns the skew of a venue's quotes of the asset
+func (s *Skewer) GetSkew(exchangeName string, c currency.Code) (Skew, error) {
+	skews, err := s.GetSkews(c)
+	if err != nil {
+		return Skew{}, err
+	}
+	for i := range skews {
+		if strings.EqualFold(skews[i].Exchange, exchangeName) {
+			return skews[i], nil
+		}
+	}
+	return Skew{}, fmt.Errorf("%s %s %v", exchangeName, c, errVenueNotQuoted)
+}
+
+// Apply returns a venue's quote of the pair skewed by where the pair's base
+// asset is held. The quote is returned unchanged with an error when the venue
+// neither holds the asset nor has a target share
+func (s *Skewer) Apply(exchangeName string, p currency.Pair, q Quote) (Quote, error) {
+	k, err := s.GetSkew(exchangeName, p.Base)
+	if err != nil {
+		return q, err
+	}
+	return k.Apply(q), nil
+}
@@ -0,0 +1,112 @@
+package inventory
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+func holdings(h ...Holding) InventoryFunc {
+	return func(_ currency.Code) ([]Holding, error) {
+		return h, nil
+	}
+}
+
+func near(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{}, nil); err != errNoInventoryFunc {
+		t.Errorf("Test Failed - New() expected errNoInventoryFunc, received %v", err)
+	}
+	if _, err := New(Config{MaxSizeSkew: 2}, holdings()); err != errInvalidSkew {
+		t.Errorf("Test Failed - New() expected errInvalidSkew, received %v", err)
+	}
+	if _, err := New(Config{Targets: map[string]float64{"Binance": 0}}, holdings()); err == nil {
+		t.Error("Test Failed - New() expected error for zero target share")
+	}
+	s, err := New(Config{}, holdings())
+	if err != nil || s.cfg.MaxSkewBps != DefaultMaxSkewBps {
+		t.Errorf("Test Failed - New() expected default skew %+v %v", s, err)
+	}
+}
+
+func TestGetSkews(t *testing.T) {
+	s, err := New(Config{MaxSkewBps: 20, MaxSizeSkew: 0.5}, holdings(
+		Holding{Exchange: "Binance", Amount: 6},
+		Holding{Exchange: "Binance", Amount: 2},
+		Holding{Exchange: "Kraken", Amount: 2},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skews, err := s.GetSkews(currency.BTC)
+	if err != nil || len(skews) != 2 {
+		t.Fatalf("Test Failed - GetSkews() unexpected skews %+v %v", skews, err)
+	}
+	// Binance holds 8 of 10 against a target of 5
+	b := skews[0]
+	if b.Exchange != "Binance" || !near(b.Target, 5) || !near(b.Excess, 0.3) ||
+		!near(b.PriceBps, -6) || !near(b.BidSize, 0.85) || b.AskSize != 1 {
+		t.Errorf("Test Failed - GetSkews() unexpected long venue skew %+v", b)
+	}
+	k := skews[1]
+	if k.Exchange != "Kraken" || !near(k.Excess, -0.3) || !near(k.PriceBps, 6) ||
+		k.BidSize != 1 || !near(k.AskSize, 0.85) {
+		t.Errorf("Test Failed - GetSkews() unexpected short venue skew %+v", k)
+	}
+
+	q := b.Apply(Quote{Bid: 10000, BidAmount: 1, Ask: 10010, AskAmount: 1})
+	if !near(q.Bid, 9994) || !near(q.Ask, 10003.994) || !near(q.BidAmount, 0.85) || q.AskAmount != 1 {
+		t.Errorf("Test Failed - Apply() unexpected quote %+v", q)
+	}
+}
+
+func TestTargets(t *testing.T) {
+	s, err := New(Config{Targets: map[string]float64{"Binance": 3, "Kraken": 1}}, holdings(
+		Holding{Exchange: "binance", Amount: 3},
+		Holding{Exchange: "Kraken", Amount: 1},
+		Holding{Exchange: "Bitstamp", Amount: 5},
+	))
+	if err != nil {
+		t.Fatal(err)
+	}
+	skews, err := s.GetSkews(currency.BTC)
+	if err != nil || len(skews) != 2 {
+		t.Fatalf("Test Failed - GetSkews() expected only target venues %+v %v", skews, err)
+	}
+	for i := range skews {
+		if skews[i].Excess != 0 || skews[i].PriceBps != 0 || !near(skews[i].Total, 4) {
+			t.Errorf("Test Failed - GetSkews() expected venue on target %+v", skews[i])
+		}
+	}
+
+	p := currency.NewPair(currency.BTC, currency.USD)
+	q := Quote{Bid: 1, BidAmount: 1, Ask: 2, AskAmount: 1}
+	if resp, err := s.Apply("Bitstamp", p, q); err == nil || resp != q {
+		t.Errorf("Test Failed - Apply() expected venue without target unchanged %+v %v", resp, err)
+	}
+	if _, err = s.Apply("BINANCE", p, q); err != nil {
+		t.Errorf("Test Failed - Apply() expected case insensitive venue, received %v", err)
+	}
+}
+
+func TestNoInventory(t *testing.T) {
+	s, err := New(Config{}, holdings(Holding{Exchange: "Binance"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	k, err := s.GetSkew("Binance", currency.BTC)
+	if err != nil || k.PriceBps != 0 || k.BidSize != 1 || k.AskSize != 1 {
+		t.Errorf("Test Failed - GetSkew() expected no skew without inventory %+v %v", k, err)
+	}
+
+	errInventory := errors.New("balances unavailable")
+	s.inventory = func(_ currency.Code) ([]Holding, error) { return nil, errInventory }
+	if _, err = s.GetSkews(currency.BTC); err != errInventory {
+		t.Errorf("Test Failed - GetSkews() expected inventory error, received %v", err)
+	}
+}
@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"strings"
 	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
@@ -348,11 +349,29 @@ func (i *ItBit) SendHTTPRequest(path string, result interface{}) error {
 	return i.SendPayload(http.MethodGet, path, nil, nil, result, false, false, i.Verbose, i.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Deposit
+// addresses are fetched through a POST request so endpoints are classified by
+// path, wallets and orders are listed and changed through the same paths so
+// those are matched by method
+func mutating(method, path string) bool {
+	p := strings.SplitN(path, "?", 2)[0]
+	switch {
+	case strings.HasSuffix(p, "/"+itbitWalletTransfer):
+		return true
+	case p == "/"+itbitWallets, strings.Contains(p, "/"+itbitOrders):
+		return method != http.MethodGet
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated request to itBit
 func (i *ItBit) SendAuthenticatedHTTPRequest(method, path string, params map[string]interface{}, result interface{}) error {
 	if !i.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, i.Name)
 	}
+	if i.DryRunRequest(mutating(method, path), method+" "+path, params) {
+		return nil
+	}
 
 	if i.ClientID == "" {
 		return errors.New("client ID not set")
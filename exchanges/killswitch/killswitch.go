@@ -0,0 +1,226 @@
+// Package killswitch disables trading of individual currency pairs across
+// every exchange. New orders and amendments to a disabled pair are rejected by
+// guarded exchanges until the pair is enabled again, while existing orders can
+// still be queried and cancelled
+package killswitch
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Event types emitted by the kill switch
+const (
+	Tripped = "PAIR_KILL_SWITCH_TRIPPED"
+	Reset   = "PAIR_KILL_SWITCH_RESET"
+)
+
+var (
+	// ErrPairDisabled is returned when an order is sent for a pair whose
+	// trading has been disabled
+	ErrPairDisabled = errors.New("pair trading disabled by kill switch")
+
+	errPairNotSet      = errors.New("kill switch currency pair not set")
+	errPairNotDisabled = errors.New("pair trading not disabled")
+)
+
+// Halt holds a pair whose trading is disabled
+type Halt struct {
+	Pair    currency.Pair
+	Reason  string
+	Created time.Time
+}
+
+// Event is emitted when a pair's trading is disabled or enabled
+type Event struct {
+	Type string
+	Halt Halt
+}
+
+func (e *Event) String() string {
+	if e.Type == Reset {
+		return fmt.Sprintf("%s trading enabled", e.Halt.Pair)
+	}
+	return fmt.Sprintf("%s trading disabled: %s", e.Halt.Pair, e.Halt.Reason)
+}
+
+// Switch holds the pairs whose trading is disabled
+type Switch struct {
+	path    string
+	halts   map[string]Halt
+	onEvent func(Event)
+	mtx     sync.Mutex
+}
+
+// key returns the lookup of a pair regardless of its delimiter and casing
+func key(p currency.Pair) string {
+	return p.Base.Upper().String() + "-" + p.Quote.Upper().String()
+}
+
+// New returns a kill switch persisting disabled pairs to path. A missing file
+// starts with every pair enabled, an empty path keeps disabled pairs in memory
+// only
+func New(path string, onEvent func(Event)) (*Switch, error) {
+	s := &Switch{
+		path:    path,
+		halts:   make(map[string]Halt),
+		onEvent: onEvent,
+	}
+	if path == "" {
+		return s, nil
+	}
+
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	var halts []Halt
+	err = json.Unmarshal(data, &halts)
+	if err != nil {
+		return nil, err
+	}
+	for i := range halts {
+		s.halts[key(halts[i].Pair)] = halts[i]
+	}
+	return s, nil
+}
+
+// save writes the disabled pairs to the switch's file, the caller must hold
+// the lock
+func (s *Switch) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.list(), "", " ")
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(s.path, data)
+}
+
+func (s *Switch) list() []Halt {
+	halts := make([]Halt, 0, len(s.halts))
+	for _, h := range s.halts {
+		halts = append(halts, h)
+	}
+	sort.Slice(halts, func(i, j int) bool {
+		return halts[i].Created.Before(halts[j].Created)
+	})
+	return halts
+}
+
+// Disable disables trading of the pair on every guarded exchange. Disabling
+// an already disabled pair returns its existing halt
+func (s *Switch) Disable(p currency.Pair, reason string) (Halt, error) {
+	if p.Base.IsEmpty() || p.Quote.IsEmpty() {
+		return Halt{}, errPairNotSet
+	}
+
+	k := key(p)
+	s.mtx.Lock()
+	if h, ok := s.halts[k]; ok {
+		s.mtx.Unlock()
+		return h, nil
+	}
+	h := Halt{Pair: p, Reason: reason, Created: time.Now()}
+	s.halts[k] = h
+	if err := s.save(); err != nil {
+		log.Errorf("Kill switch failed to save disabled pairs: %s", err)
+	}
+	s.mtx.Unlock()
+
+	if s.onEvent != nil {
+		s.onEvent(Event{Type: Tripped, Halt: h})
+	}
+	return h, nil
+}
+
+// Enable enables trading of a disabled pair
+func (s *Switch) Enable(p currency.Pair) error {
+	k := key(p)
+	s.mtx.Lock()
+	h, ok := s.halts[k]
+	if !ok {
+		s.mtx.Unlock()
+		return fmt.Errorf("%s %v", p, errPairNotDisabled)
+	}
+	delete(s.halts, k)
+	if err := s.save(); err != nil {
+		log.Errorf("Kill switch failed to save disabled pairs: %s", err)
+	}
+	s.mtx.Unlock()
+
+	if s.onEvent != nil {
+		s.onEvent(Event{Type: Reset, Halt: h})
+	}
+	return nil
+}
+
+// IsDisabled returns whether trading of the pair is disabled
+func (s *Switch) IsDisabled(p currency.Pair) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	_, ok := s.halts[key(p)]
+	return ok
+}
+
+// GetHalts returns the disabled pairs ordered by when they were disabled
+func (s *Switch) GetHalts() []Halt {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.list()
+}
+
+// Guard wraps an exchange so new orders and amendments to disabled pairs are
+// rejected
+func (s *Switch) Guard(e exchange.IBotExchange) exchange.IBotExchange {
+	return &Guarded{IBotExchange: e, killSwitch: s}
+}
+
+// Guarded is an exchange whose trading of disabled pairs is blocked
+type Guarded struct {
+	exchange.IBotExchange
+	killSwitch *Switch
+}
+
+// Unwrap returns the underlying exchange
+func (g *Guarded) Unwrap() exchange.IBotExchange {
+	return g.IBotExchange
+}
+
+// SubmitOrder rejects orders for disabled pairs
+func (g *Guarded) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	if g.killSwitch.IsDisabled(p) {
+		return exchange.SubmitOrderResponse{}, fmt.Errorf("%s %s %v", g.GetName(), p, ErrPairDisabled)
+	}
+	return g.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+}
+
+// CheckOrder rejects orders placed natively for disabled pairs
+func (g *Guarded) CheckOrder(o *exchange.OrderSubmission) error {
+	if g.killSwitch.IsDisabled(o.Pair) {
+		return fmt.Errorf("%s %s %v", g.GetName(), o.Pair, ErrPairDisabled)
+	}
+	return nil
+}
+
+// ModifyOrder rejects amendments to orders of disabled pairs
+func (g *Guarded) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
+	if g.killSwitch.IsDisabled(action.CurrencyPair) {
+		return "", fmt.Errorf("%s %s %v", g.GetName(), action.CurrencyPair, ErrPairDisabled)
+	}
+	return g.IBotExchange.ModifyOrder(action)
+}
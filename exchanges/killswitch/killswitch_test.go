@@ -0,0 +1,97 @@
+package killswitch
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	submitted int
+	modified  int
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.submitted++
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) ModifyOrder(_ *exchange.ModifyOrder) (string, error) {
+	t.modified++
+	return "1", nil
+}
+
+func TestKillSwitch(t *testing.T) {
+	dir, err := ioutil.TempDir("", "killswitch")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "killswitch.json")
+
+	var events []Event
+	s, err := New(path, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = s.Disable(currency.Pair{}, ""); err != errPairNotSet {
+		t.Error("Test Failed - Disable() expected pair not set error", err)
+	}
+
+	btc := currency.NewPairFromStrings("BTC", "USD")
+	exch := &testExchange{}
+	g := s.Guard(exch)
+	if exchange.Underlying(g) != exch {
+		t.Error("Test Failed - Unwrap() expected underlying exchange")
+	}
+
+	if _, err = s.Disable(currency.NewPairDelimiter("btc_usd", "_"), "exchange incident"); err != nil {
+		t.Fatal(err)
+	}
+	h, err := s.Disable(btc, "again")
+	if err != nil || h.Reason != "exchange incident" || len(events) != 1 || events[0].Type != Tripped {
+		t.Errorf("Test Failed - Disable() expected existing halt and one event %+v %+v", h, events)
+	}
+
+	if _, err = g.SubmitOrder(btc, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 1, ""); err == nil {
+		t.Error("Test Failed - SubmitOrder() expected pair disabled error")
+	}
+	if _, err = g.ModifyOrder(&exchange.ModifyOrder{CurrencyPair: btc}); err == nil {
+		t.Error("Test Failed - ModifyOrder() expected pair disabled error")
+	}
+	eth := currency.NewPairFromStrings("ETH", "USD")
+	if _, err = g.SubmitOrder(eth, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 1, ""); err != nil {
+		t.Error("Test Failed - SubmitOrder() error", err)
+	}
+	if exch.submitted != 1 || exch.modified != 0 {
+		t.Errorf("Test Failed - Guarded expected only the enabled pair traded %+v", exch)
+	}
+
+	loaded, err := New(path, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !loaded.IsDisabled(btc) || len(loaded.GetHalts()) != 1 {
+		t.Error("Test Failed - New() expected disabled pair loaded from file")
+	}
+
+	if err = s.Enable(eth); err == nil {
+		t.Error("Test Failed - Enable() expected pair not disabled error")
+	}
+	if err = s.Enable(btc); err != nil {
+		t.Fatal(err)
+	}
+	if s.IsDisabled(btc) || len(events) != 2 || events[1].Type != Reset {
+		t.Errorf("Test Failed - Enable() expected pair enabled and reset event %+v", events)
+	}
+	if _, err = g.SubmitOrder(btc, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 1, ""); err != nil {
+		t.Error("Test Failed - SubmitOrder() error", err)
+	}
+}
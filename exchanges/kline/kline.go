@@ -0,0 +1,121 @@
+// Package kline defines the candle intervals callers request candles at and
+// the candle items exchanges return. Each exchange wrapper translates an
+// interval to its own period, such as Huobi's period strings, Kraken's
+// interval minutes or the Poloniex and OKEX period seconds
+package kline
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Interval defines the period of a candle
+type Interval time.Duration
+
+// Candle intervals offered by at least one exchange
+const (
+	OneMin     = Interval(time.Minute)
+	ThreeMin   = 3 * OneMin
+	FiveMin    = 5 * OneMin
+	FifteenMin = 15 * OneMin
+	ThirtyMin  = 30 * OneMin
+	OneHour    = Interval(time.Hour)
+	TwoHour    = 2 * OneHour
+	FourHour   = 4 * OneHour
+	SixHour    = 6 * OneHour
+	EightHour  = 8 * OneHour
+	TwelveHour = 12 * OneHour
+	OneDay     = 24 * OneHour
+	ThreeDay   = 3 * OneDay
+	OneWeek    = 7 * OneDay
+	FifteenDay = 15 * OneDay
+)
+
+// ErrUnsupportedInterval is returned when an exchange does not offer candles
+// at the requested interval
+var ErrUnsupportedInterval = errors.New("candle interval unsupported")
+
+var errInvalidInterval = errors.New("candle interval must be positive")
+
+// units holds the interval suffixes from largest to smallest
+var units = []struct {
+	suffix string
+	d      time.Duration
+}{
+	{"w", time.Hour * 24 * 7},
+	{"d", time.Hour * 24},
+	{"h", time.Hour},
+	{"m", time.Minute},
+	{"s", time.Second},
+}
+
+// Item holds the open, high, low, close and volume of a candle opening at
+// Time
+type Item struct {
+	Time   time.Time
+	Open   float64
+	High   float64
+	Low    float64
+	Close  float64
+	Volume float64
+}
+
+// ParseInterval parses an interval such as 1m, 4h, 1d or 1w. Durations
+// accepted by time.ParseDuration such as 1h30m are also parsed
+func ParseInterval(s string) (Interval, error) {
+	s = strings.TrimSpace(s)
+	for _, u := range units {
+		if !strings.HasSuffix(s, u.suffix) {
+			continue
+		}
+		n, err := strconv.ParseInt(strings.TrimSuffix(s, u.suffix), 10, 64)
+		if err != nil {
+			break
+		}
+		if n <= 0 {
+			return 0, errInvalidInterval
+		}
+		return Interval(time.Duration(n) * u.d), nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid candle interval %q", s)
+	}
+	if d <= 0 {
+		return 0, errInvalidInterval
+	}
+	return Interval(d), nil
+}
+
+// Duration returns the interval as a duration
+func (i Interval) Duration() time.Duration {
+	return time.Duration(i)
+}
+
+// Seconds returns the interval in whole seconds
+func (i Interval) Seconds() int64 {
+	return int64(i.Duration() / time.Second)
+}
+
+// Minutes returns the interval in whole minutes
+func (i Interval) Minutes() int64 {
+	return int64(i.Duration() / time.Minute)
+}
+
+// String returns the interval in its largest whole unit, such as 15m or 1d
+func (i Interval) String() string {
+	for _, u := range units {
+		if i > 0 && i.Duration()%u.d == 0 {
+			return strconv.FormatInt(int64(i.Duration()/u.d), 10) + u.suffix
+		}
+	}
+	return i.Duration().String()
+}
+
+// Truncate returns t rounded down to the open of the interval it falls in
+func (i Interval) Truncate(t time.Time) time.Time {
+	return t.Truncate(i.Duration())
+}
@@ -0,0 +1,57 @@
+package kline
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseInterval(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Interval
+	}{
+		{"1m", OneMin},
+		{"15m", FifteenMin},
+		{"4h", FourHour},
+		{"1d", OneDay},
+		{" 1w", OneWeek},
+		{"90s", Interval(time.Second * 90)},
+		{"1h30m", Interval(time.Minute * 90)},
+	}
+	for _, test := range tests {
+		i, err := ParseInterval(test.s)
+		if err != nil || i != test.want {
+			t.Errorf("Test Failed - ParseInterval(%q) expected %v, received %v %v", test.s, test.want, i, err)
+		}
+	}
+	for _, s := range []string{"", "0m", "-1h", "1x", "d"} {
+		if _, err := ParseInterval(s); err == nil {
+			t.Errorf("Test Failed - ParseInterval(%q) expected error", s)
+		}
+	}
+}
+
+func TestIntervalString(t *testing.T) {
+	tests := map[Interval]string{
+		OneMin:                         "1m",
+		ThirtyMin:                      "30m",
+		TwelveHour:                     "12h",
+		ThreeDay:                       "3d",
+		OneWeek:                        "1w",
+		FifteenDay:                     "15d",
+		Interval(time.Minute * 90):     "90m",
+		Interval(time.Millisecond * 5): "5ms",
+	}
+	for i, want := range tests {
+		if i.String() != want {
+			t.Errorf("Test Failed - Interval String() expected %s, received %s", want, i)
+		}
+	}
+	if OneHour.Seconds() != 3600 || OneDay.Minutes() != 1440 {
+		t.Error("Test Failed - Interval Seconds() Minutes() unexpected values")
+	}
+	at := time.Date(2019, 6, 1, 13, 47, 0, 0, time.UTC)
+	if !FourHour.Truncate(at).Equal(time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)) {
+		t.Error("Test Failed - Interval Truncate() expected the open of the interval", FourHour.Truncate(at))
+	}
+}
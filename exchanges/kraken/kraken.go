@@ -44,12 +44,19 @@ const (
 	krakenTradeVolume      = "TradeVolume"
 	krakenOrderCancel      = "CancelOrder"
 	krakenOrderPlace       = "AddOrder"
+	krakenOrderEdit        = "EditOrder"
 	krakenWithdrawInfo     = "WithdrawInfo"
 	krakenWithdraw         = "Withdraw"
 	krakenDepositMethods   = "DepositMethods"
+	krakenDepositStatus    = "DepositStatus"
 	krakenDepositAddresses = "DepositAddresses"
 	krakenWithdrawStatus   = "WithdrawStatus"
 	krakenWithdrawCancel   = "WithdrawCancel"
+	krakenStake            = "Stake"
+	krakenUnstake          = "Unstake"
+	krakenStakingAssets    = "Staking/Assets"
+	krakenStakingPending   = "Staking/Pending"
+	krakenStakingHistory   = "Staking/Transactions"
 
 	krakenAuthRate   = 0
 	krakenUnauthRate = 0
@@ -61,6 +68,15 @@ type Kraken struct {
 	WebsocketConn      *wshandler.WebsocketConnection
 	CryptoFee, FiatFee float64
 	wsRequestMtx       sync.Mutex
+	// wsBookDepth holds the book depth set per pair and wsSubscribedDepth
+	// the depth each book was last subscribed at
+	wsBookDepth       map[string]int64
+	wsSubscribedDepth map[string]int64
+	wsDepthMtx        sync.Mutex
+	// pairAltnames maps Kraken's pair names to the alternative names pairs
+	// are requested by, e.g. XXBTZUSD to XBTUSD
+	pairAltnames map[string]string
+	pairMtx      sync.Mutex
 }
 
 // SetDefaults sets current default settings
@@ -249,6 +265,9 @@ func (k *Kraken) GetTicker(symbol string) (Ticker, error) {
 	return tick, nil
 }
 
+// krakenTickerBatchSize is the number of pairs requested per ticker request
+const krakenTickerBatchSize = 30
+
 // GetTickers supports fetching multiple tickers from Kraken
 // pairList must be in the format pairs separated by commas
 // ("LTCUSD,ETCUSD")
@@ -291,10 +310,14 @@ func (k *Kraken) GetTickers(pairList string) (Tickers, error) {
 	return tickers, nil
 }
 
-// GetOHLC returns an array of open high low close values of a currency pair
-func (k *Kraken) GetOHLC(symbol string) ([]OpenHighLowClose, error) {
+// GetOHLC returns an array of open high low close values of a currency pair.
+// The interval is in minutes, Kraken defaults to 1 when zero
+func (k *Kraken) GetOHLC(symbol string, interval int) ([]OpenHighLowClose, error) {
 	values := url.Values{}
 	values.Set("pair", symbol)
+	if interval > 0 {
+		values.Set("interval", strconv.Itoa(interval))
+	}
 
 	type Response struct {
 		Error []interface{}          `json:"error"`
@@ -315,7 +338,17 @@ func (k *Kraken) GetOHLC(symbol string) ([]OpenHighLowClose, error) {
 		return OHLC, fmt.Errorf("getOHLC error: %s", result.Error)
 	}
 
-	for _, y := range result.Data[symbol].([]interface{}) {
+	// The result is keyed by Kraken's own pair name, which may differ from
+	// the symbol requested, alongside the id of the last candle
+	var data []interface{}
+	for key, v := range result.Data {
+		if key == "last" {
+			continue
+		}
+		data, _ = v.([]interface{})
+	}
+
+	for _, y := range data {
 		o := OpenHighLowClose{}
 		for i, x := range y.([]interface{}) {
 			switch i {
@@ -495,16 +528,17 @@ func (k *Kraken) GetBalance() (map[string]float64, error) {
 	return result, GetError(response.Error)
 }
 
-// GetWithdrawInfo gets withdrawal fees
-func (k *Kraken) GetWithdrawInfo(currency string, amount float64) (WithdrawInformation, error) {
+// GetWithdrawInfo gets the fee and the maximum amount which can currently be
+// withdrawn to a withdrawal key
+func (k *Kraken) GetWithdrawInfo(currency, key string, amount float64) (WithdrawInformation, error) {
 	var response struct {
 		Error  []string            `json:"error"`
 		Result WithdrawInformation `json:"result"`
 	}
 	params := url.Values{}
-	params.Set("asset ", currency)
-	params.Set("key  ", "")
-	params.Set("amount ", fmt.Sprintf("%f", amount))
+	params.Set("asset", currency)
+	params.Set("key", key)
+	params.Set("amount", fmt.Sprintf("%f", amount))
 
 	if err := k.SendAuthenticatedHTTPRequest(krakenWithdrawInfo, params, &response); err != nil {
 		return response.Result, err
@@ -548,6 +582,23 @@ func (k *Kraken) GetDepositMethods(currency string) ([]DepositMethods, error) {
 	return response.Result, GetError(response.Error)
 }
 
+// GetDepositStatus returns the status of recent deposits of an asset
+func (k *Kraken) GetDepositStatus(asset string) ([]DepositStatus, error) {
+	var response struct {
+		Error  []string        `json:"error"`
+		Result []DepositStatus `json:"result"`
+	}
+	params := url.Values{}
+	params.Set("asset", asset)
+
+	err := k.SendAuthenticatedHTTPRequest(krakenDepositStatus, params, &response)
+	if err != nil {
+		return response.Result, err
+	}
+
+	return response.Result, GetError(response.Error)
+}
+
 // GetTradeBalance returns full information about your trades on Kraken
 func (k *Kraken) GetTradeBalance(args ...TradeBalanceOptions) (TradeBalanceInfo, error) {
 	params := url.Values{}
@@ -762,23 +813,23 @@ func (k *Kraken) GetLedgers(args ...GetLedgersOptions) (Ledgers, error) {
 	params := url.Values{}
 
 	if args != nil {
-		if args[0].Aclass == "" {
+		if args[0].Aclass != "" {
 			params.Set("aclass", args[0].Aclass)
 		}
 
-		if args[0].Asset == "" {
+		if args[0].Asset != "" {
 			params.Set("asset", args[0].Asset)
 		}
 
-		if args[0].Type == "" {
+		if args[0].Type != "" {
 			params.Set("type", args[0].Type)
 		}
 
-		if args[0].Start == "" {
+		if args[0].Start != "" {
 			params.Set("start", args[0].Start)
 		}
 
-		if args[0].End == "" {
+		if args[0].End != "" {
 			params.Set("end", args[0].End)
 		}
 
@@ -866,20 +917,20 @@ func (k *Kraken) AddOrder(symbol, side, orderType string, volume, price, price2,
 		params.Set("leverage", strconv.FormatFloat(leverage, 'f', -1, 64))
 	}
 
-	if args.Oflags == "" {
+	if args.Oflags != "" {
 		params.Set("oflags", args.Oflags)
 	}
 
-	if args.StartTm == "" {
+	if args.StartTm != "" {
 		params.Set("starttm", args.StartTm)
 	}
 
-	if args.ExpireTm == "" {
+	if args.ExpireTm != "" {
 		params.Set("expiretm", args.ExpireTm)
 	}
 
 	if args.CloseOrderType != "" {
-		params.Set("close[ordertype]", args.ExpireTm)
+		params.Set("close[ordertype]", args.CloseOrderType)
 	}
 
 	if args.ClosePrice != 0 {
@@ -906,6 +957,35 @@ func (k *Kraken) AddOrder(symbol, side, orderType string, volume, price, price2,
 	return response.Result, GetError(response.Error)
 }
 
+// EditOrder amends the volume and price of an open order. Kraken cancels the
+// original order and returns the transaction ID of its replacement, zero
+// values leave the volume or price unchanged
+func (k *Kraken) EditOrder(txid, symbol string, volume, price float64) (EditOrderResponse, error) {
+	params := url.Values{
+		"txid": {txid},
+		"pair": {symbol},
+	}
+
+	if volume > 0 {
+		params.Set("volume", strconv.FormatFloat(volume, 'f', -1, 64))
+	}
+
+	if price > 0 {
+		params.Set("price", strconv.FormatFloat(price, 'f', -1, 64))
+	}
+
+	var response struct {
+		Error  []string          `json:"error"`
+		Result EditOrderResponse `json:"result"`
+	}
+
+	if err := k.SendAuthenticatedHTTPRequest(krakenOrderEdit, params, &response); err != nil {
+		return response.Result, err
+	}
+
+	return response.Result, GetError(response.Error)
+}
+
 // CancelExistingOrder cancels order by orderID
 func (k *Kraken) CancelExistingOrder(txid string) (CancelOrderResponse, error) {
 	values := url.Values{
@@ -948,6 +1028,17 @@ func (k *Kraken) SendHTTPRequest(path string, result interface{}) error {
 	return k.SendPayload(http.MethodGet, path, nil, nil, result, false, false, k.Verbose, k.HTTPDebugging)
 }
 
+// mutating returns whether the private endpoint changes exchange state. Every
+// private endpoint is a POST so they are classified by name
+func mutating(method string) bool {
+	switch method {
+	case krakenOrderPlace, krakenOrderEdit, krakenOrderCancel, krakenWithdraw,
+		krakenWithdrawCancel, krakenStake, krakenUnstake:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP request
 func (k *Kraken) SendAuthenticatedHTTPRequest(method string, params url.Values, result interface{}) (err error) {
 	if !k.AuthenticatedAPISupport {
@@ -955,6 +1046,10 @@ func (k *Kraken) SendAuthenticatedHTTPRequest(method string, params url.Values,
 			k.Name)
 	}
 
+	if k.DryRunRequest(mutating(method), method, params) {
+		return nil
+	}
+
 	path := fmt.Sprintf("/%s/private/%s", krakenAPIVersion, method)
 
 	n := k.Requester.GetNonce(true).String()
@@ -981,7 +1076,15 @@ func (k *Kraken) SendAuthenticatedHTTPRequest(method string, params url.Values,
 	headers["API-Key"] = k.APIKey
 	headers["API-Sign"] = signature
 
-	return k.SendPayload(http.MethodPost,
+	// Every private endpoint is a POST so only order changes are prioritised
+	priority := request.Normal
+	switch method {
+	case krakenOrderPlace, krakenOrderEdit, krakenOrderCancel:
+		priority = request.Critical
+	}
+
+	return k.SendPayloadPriority(priority,
+		http.MethodPost,
 		k.APIUrl+path,
 		headers,
 		strings.NewReader(encoded),
@@ -1095,7 +1198,7 @@ func (k *Kraken) WithdrawStatus(c currency.Code, method string) ([]WithdrawStatu
 	}
 
 	params := url.Values{}
-	params.Set("asset ", c.String())
+	params.Set("asset", AssetName(c))
 	if method != "" {
 		params.Set("method", method)
 	}
@@ -1115,7 +1218,7 @@ func (k *Kraken) WithdrawCancel(c currency.Code, refID string) (bool, error) {
 	}
 
 	params := url.Values{}
-	params.Set("asset ", c.String())
+	params.Set("asset", AssetName(c))
 	params.Set("refid", refID)
 
 	if err := k.SendAuthenticatedHTTPRequest(krakenWithdrawCancel, params, &response); err != nil {
@@ -1124,3 +1227,91 @@ func (k *Kraken) WithdrawCancel(c currency.Code, refID string) (bool, error) {
 
 	return response.Result, GetError(response.Error)
 }
+
+// Stake stakes an amount of an asset using a staking method returned by
+// GetStakeableAssets, the reference ID of the staking transaction is returned
+func (k *Kraken) Stake(asset, method string, amount float64) (string, error) {
+	var response struct {
+		Error  []string `json:"error"`
+		Result struct {
+			ReferenceID string `json:"refid"`
+		} `json:"result"`
+	}
+
+	params := url.Values{}
+	params.Set("asset", asset)
+	params.Set("method", method)
+	params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
+
+	if err := k.SendAuthenticatedHTTPRequest(krakenStake, params, &response); err != nil {
+		return response.Result.ReferenceID, err
+	}
+
+	return response.Result.ReferenceID, GetError(response.Error)
+}
+
+// Unstake unstakes an amount of a staked asset such as XTZ.S, the reference ID
+// of the unstaking transaction is returned
+func (k *Kraken) Unstake(asset string, amount float64) (string, error) {
+	var response struct {
+		Error  []string `json:"error"`
+		Result struct {
+			ReferenceID string `json:"refid"`
+		} `json:"result"`
+	}
+
+	params := url.Values{}
+	params.Set("asset", asset)
+	params.Set("amount", strconv.FormatFloat(amount, 'f', -1, 64))
+
+	if err := k.SendAuthenticatedHTTPRequest(krakenUnstake, params, &response); err != nil {
+		return response.Result.ReferenceID, err
+	}
+
+	return response.Result.ReferenceID, GetError(response.Error)
+}
+
+// GetStakeableAssets returns the assets which can be staked and their staking
+// methods and rewards
+func (k *Kraken) GetStakeableAssets() ([]StakeableAsset, error) {
+	var response struct {
+		Error  []string         `json:"error"`
+		Result []StakeableAsset `json:"result"`
+	}
+
+	if err := k.SendAuthenticatedHTTPRequest(krakenStakingAssets, url.Values{}, &response); err != nil {
+		return response.Result, err
+	}
+
+	return response.Result, GetError(response.Error)
+}
+
+// GetPendingStakingTransactions returns staking and unstaking transactions
+// which have not yet completed
+func (k *Kraken) GetPendingStakingTransactions() ([]StakingTransaction, error) {
+	var response struct {
+		Error  []string             `json:"error"`
+		Result []StakingTransaction `json:"result"`
+	}
+
+	if err := k.SendAuthenticatedHTTPRequest(krakenStakingPending, url.Values{}, &response); err != nil {
+		return response.Result, err
+	}
+
+	return response.Result, GetError(response.Error)
+}
+
+// GetStakingTransactions returns the history of staking, unstaking and reward
+// transactions
+func (k *Kraken) GetStakingTransactions() ([]StakingTransaction, error) {
+	var response struct {
+		Error  []string             `json:"error"`
+		Result []StakingTransaction `json:"result"`
+	}
+
+	if err := k.SendAuthenticatedHTTPRequest(krakenStakingHistory, url.Values{}, &response); err != nil {
+		return response.Result, err
+	}
+
+	return response.Result, GetError(response.Error)
+}
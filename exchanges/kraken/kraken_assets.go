@@ -0,0 +1,89 @@
+package kraken
+
+import (
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// Kraken lists its original assets under legacy codes prefixed with X for
+// cryptocurrencies and Z for fiat, with bitcoin as XBT and dogecoin as XDG.
+// Balances, ledgers, deposits and withdrawals report these codes, newer assets
+// are listed under their standard code
+var assetCodes = map[string]currency.Code{
+	"XXBT": currency.BTC,
+	"XBT":  currency.BTC,
+	"XXDG": currency.DOGE,
+	"XDG":  currency.DOGE,
+	"XETH": currency.ETH,
+	"XETC": currency.ETC,
+	"XLTC": currency.LTC,
+	"XXRP": currency.XRP,
+	"XXLM": currency.XLM,
+	"XXMR": currency.XMR,
+	"XZEC": currency.ZEC,
+	"XREP": currency.REP,
+	"XMLN": currency.MLN,
+	"XICN": currency.ICN,
+	"XDAO": currency.NewCode("DAO"),
+	"XNMC": currency.NMC,
+	"XXVN": currency.NewCode("XVN"),
+	"ZUSD": currency.USD,
+	"ZEUR": currency.EUR,
+	"ZGBP": currency.GBP,
+	"ZJPY": currency.JPY,
+	"ZCAD": currency.CAD,
+	"ZKRW": currency.KRW,
+	"ZAUD": currency.AUD,
+}
+
+// assetNames maps standard codes back to Kraken's legacy asset codes
+var assetNames = func() map[string]string {
+	m := make(map[string]string, len(assetCodes))
+	for asset, c := range assetCodes {
+		// Prefer the legacy code over the alternative name
+		if prev, ok := m[c.String()]; ok && len(prev) > len(asset) {
+			continue
+		}
+		m[c.String()] = asset
+	}
+	return m
+}()
+
+// AssetCode returns the standard currency of a Kraken asset code, e.g. XXBT
+// and XBT are BTC and ZUSD is USD. Suffixes marking staked or opt-in reward
+// balances, such as XTZ.S, are kept so they are not mistaken for the spot
+// balance. Unknown assets are returned unchanged
+func AssetCode(asset string) currency.Code {
+	asset = strings.ToUpper(asset)
+	var suffix string
+	if i := strings.Index(asset, "."); i > 0 {
+		asset, suffix = asset[:i], asset[i:]
+	}
+	c, ok := assetCodes[asset]
+	if !ok {
+		return currency.NewCode(asset + suffix)
+	}
+	if suffix != "" {
+		return currency.NewCode(c.String() + suffix)
+	}
+	return c
+}
+
+// AssetName returns the Kraken asset code of a currency, e.g. XXBT for BTC or
+// XBT and ZUSD for USD. Currencies without a legacy code are returned in upper
+// case
+func AssetName(c currency.Code) string {
+	code := c.Upper().String()
+	var suffix string
+	if i := strings.Index(code, "."); i > 0 {
+		code, suffix = code[:i], code[i:]
+	}
+	if std, ok := assetCodes[code]; ok {
+		code = std.String()
+	}
+	if asset, ok := assetNames[code]; ok {
+		return asset + suffix
+	}
+	return code + suffix
+}
@@ -5,12 +5,14 @@ import (
 	"net/http"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/gorilla/websocket"
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/sharedtestvalues"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
 )
@@ -96,12 +98,25 @@ func TestGetTickers(t *testing.T) {
 // TestGetOHLC API endpoint test
 func TestGetOHLC(t *testing.T) {
 	t.Parallel()
-	_, err := k.GetOHLC("BCHEUR")
+	_, err := k.GetOHLC("BCHEUR", 0)
 	if err != nil {
 		t.Error("Test Failed - GetOHLC() error", err)
 	}
 }
 
+// TestGetHistoricCandles API endpoint test
+func TestGetHistoricCandles(t *testing.T) {
+	t.Parallel()
+	_, err := k.GetHistoricCandles(currency.NewPairWithDelimiter("XBT", "USD", ""), "SPOT", kline.FifteenDay, 24)
+	if err != nil {
+		t.Error("Test Failed - GetHistoricCandles() error", err)
+	}
+	_, err = k.GetHistoricCandles(currency.NewPairWithDelimiter("XBT", "USD", ""), "SPOT", kline.TwoHour, 24)
+	if err != exchange.ErrCandleIntervalUnsupported {
+		t.Error("Test Failed - GetHistoricCandles() expected unsupported interval error", err)
+	}
+}
+
 // TestGetDepth API endpoint test
 func TestGetDepth(t *testing.T) {
 	t.Parallel()
@@ -441,7 +456,50 @@ func TestSubmitOrder(t *testing.T) {
 	}
 }
 
+// TestValidateOrder wrapper test
+func TestValidateOrder(t *testing.T) {
+	k.SetDefaults()
+	TestSetup(t)
+
+	var p = currency.Pair{
+		Delimiter: "",
+		Base:      currency.XBT,
+		Quote:     currency.CAD,
+	}
+	err := k.ValidateOrder(p, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 10)
+	if !areTestAPIKeysSet() && err == nil {
+		t.Error("Expecting an error when no keys are set")
+	}
+}
+
 // TestCancelExchangeOrder wrapper test
+func TestSubmitOrderWithTimeInForce(t *testing.T) {
+	k.SetDefaults()
+	TestSetup(t)
+
+	if areTestAPIKeysSet() && !canManipulateRealOrders {
+		t.Skip("API keys set, canManipulateRealOrders false, skipping test")
+	}
+
+	var p = currency.Pair{
+		Delimiter: "",
+		Base:      currency.XBT,
+		Quote:     currency.CAD,
+	}
+	_, err := k.SubmitOrderWithTimeInForce(p, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 10, "", exchange.ImmediateOrCancel, time.Time{})
+	if err != exchange.ErrTimeInForceUnsupported {
+		t.Errorf("Test Failed - SubmitOrderWithTimeInForce() expected %v, received %v",
+			exchange.ErrTimeInForceUnsupported, err)
+	}
+
+	response, err := k.SubmitOrderWithTimeInForce(p, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 10, "", exchange.GoodTillDate, time.Now().Add(time.Hour))
+	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
+		t.Errorf("Order failed to be placed: %v", err)
+	} else if !areTestAPIKeysSet() && err == nil {
+		t.Error("Expecting an error when no keys are set")
+	}
+}
+
 func TestCancelExchangeOrder(t *testing.T) {
 	k.SetDefaults()
 	TestSetup(t)
@@ -645,6 +703,46 @@ func TestWithdrawCancel(t *testing.T) {
 	}
 }
 
+// TestStaking API endpoint test
+func TestStaking(t *testing.T) {
+	k.SetDefaults()
+	TestSetup(t)
+	if areTestAPIKeysSet() && !canManipulateRealOrders {
+		t.Skip("API keys set, canManipulateRealOrders false, skipping test")
+	}
+
+	_, err := k.GetStakeableAssets()
+	if !areTestAPIKeysSet() && err == nil {
+		t.Error("Test Failed - GetStakeableAssets() expecting an error when no keys are set")
+	}
+	_, err = k.GetPendingStakingTransactions()
+	if !areTestAPIKeysSet() && err == nil {
+		t.Error("Test Failed - GetPendingStakingTransactions() expecting an error when no keys are set")
+	}
+	_, err = k.GetStakingTransactions()
+	if !areTestAPIKeysSet() && err == nil {
+		t.Error("Test Failed - GetStakingTransactions() expecting an error when no keys are set")
+	}
+	_, err = k.Stake("XTZ", "tezos-staked", 1)
+	if !areTestAPIKeysSet() && err == nil {
+		t.Error("Test Failed - Stake() expecting an error when no keys are set")
+	}
+	_, err = k.Unstake("XTZ.S", 1)
+	if !areTestAPIKeysSet() && err == nil {
+		t.Error("Test Failed - Unstake() expecting an error when no keys are set")
+	}
+}
+
+// TestGetDepositStatus API endpoint test
+func TestGetDepositStatus(t *testing.T) {
+	k.SetDefaults()
+	TestSetup(t)
+	_, err := k.GetDepositStatus("XBT")
+	if !areTestAPIKeysSet() && err == nil {
+		t.Error("Test Failed - GetDepositStatus() expecting an error when no keys are set")
+	}
+}
+
 // ---------------------------- Websocket tests -----------------------------------------
 
 // TestOrderbookBufferReset websocket test
@@ -748,6 +846,35 @@ func TestOrderBookOutOfOrder(t *testing.T) {
 	}
 }
 
+// TestWsProcessTickers websocket test
+func TestWsProcessTickers(t *testing.T) {
+	if k.Name == "" {
+		k.SetDefaults()
+		TestSetup(t)
+	}
+	if !k.Websocket.IsEnabled() {
+		t.Skip("Websocket not enabled, skipping")
+	}
+	tick := `[0,{"a":["5525.40000",1,"1.000"],"b":["5525.10000",1,"2.000"],"c":["5525.10000","0.00398963"],"v":["2634.11501494","3591.17907851"],"p":["5631.44067","5653.78939"],"t":[11493,16267],"l":["5505.00000","5505.00000"],"h":["5783.00000","5783.00000"],"o":["5760.70000","5763.40000"]}]`
+	k.Websocket.DataHandler = sharedtestvalues.GetWebsocketInterfaceChannelOverride()
+	var dataResponse WebsocketDataResponse
+	err := common.JSONDecode([]byte(tick), &dataResponse)
+	if err != nil {
+		t.Fatalf("Could not parse, %v", err)
+	}
+	k.wsProcessTickers(&WebsocketChannelData{
+		Subscription: "ticker",
+		Pair:         currency.NewPairWithDelimiter("XBT", "USD", "-"),
+	}, dataResponse[1])
+
+	resp := (<-k.Websocket.DataHandler).(wshandler.TickerData)
+	if resp.BidPrice != 5525.1 || resp.BidSize != 2 ||
+		resp.AskPrice != 5525.4 || resp.AskSize != 1 ||
+		resp.ClosePrice != 5525.1 || resp.Quantity != 2634.11501494 {
+		t.Errorf("Test Failed - wsProcessTickers() unexpected ticker %+v", resp)
+	}
+}
+
 func setupWsTests(t *testing.T) {
 	if wsSetupRan {
 		return
@@ -786,3 +913,107 @@ func TestWebsocketSubscribe(t *testing.T) {
 		t.Error(err)
 	}
 }
+
+func TestAssetNetworkStatus(t *testing.T) {
+	t.Parallel()
+	assets := map[string]Asset{
+		"XXBT": {Altname: "XBT", Status: "enabled"},
+		"DOT":  {Altname: "DOT", Status: "withdrawal_only"},
+		"XXLM": {Altname: "XLM", Status: "funding_temporarily_disabled"},
+	}
+	s, ok := AssetNetworkStatus(assets, currency.BTC)
+	if !ok || !s.DepositEnabled || !s.WithdrawEnabled {
+		t.Errorf("Test Failed - AssetNetworkStatus() expected BTC enabled via XBT %+v", s)
+	}
+	s, _ = AssetNetworkStatus(assets, currency.NewCode("DOT"))
+	if s.DepositEnabled || !s.WithdrawEnabled || s.Reason != "withdrawal_only" {
+		t.Errorf("Test Failed - AssetNetworkStatus() expected DOT withdrawal only %+v", s)
+	}
+	s, _ = AssetNetworkStatus(assets, currency.XLM)
+	if s.DepositEnabled || s.WithdrawEnabled {
+		t.Errorf("Test Failed - AssetNetworkStatus() expected XLM funding disabled %+v", s)
+	}
+	if _, ok = AssetNetworkStatus(assets, currency.LTC); ok {
+		t.Error("Test Failed - AssetNetworkStatus() expected LTC not found")
+	}
+}
+
+func TestAssetCode(t *testing.T) {
+	t.Parallel()
+	for asset, want := range map[string]string{
+		"XXBT": "BTC", "XBT": "BTC", "XXDG": "DOGE", "XDG": "DOGE",
+		"XETH": "ETH", "XETC": "ETC", "XLTC": "LTC", "XXRP": "XRP",
+		"XXLM": "XLM", "XXMR": "XMR", "XZEC": "ZEC", "XREP": "REP",
+		"XMLN": "MLN", "XICN": "ICN", "XDAO": "DAO", "XNMC": "NMC",
+		"XXVN": "XVN", "ZUSD": "USD", "ZEUR": "EUR", "ZGBP": "GBP",
+		"ZJPY": "JPY", "ZCAD": "CAD", "ZKRW": "KRW", "ZAUD": "AUD",
+		"xxbt": "BTC", "DOT": "DOT", "USDT": "USDT", "XTZ": "XTZ",
+		"XTZ.S": "XTZ.S", "XXBT.M": "BTC.M", "ZUSD.HOLD": "USD.HOLD",
+	} {
+		if got := AssetCode(asset); got.String() != want {
+			t.Errorf("Test Failed - AssetCode(%s) expected %s received %s", asset, want, got)
+		}
+	}
+}
+
+func TestAssetName(t *testing.T) {
+	t.Parallel()
+	for code, want := range map[string]string{
+		"BTC": "XXBT", "XBT": "XXBT", "DOGE": "XXDG", "XDG": "XXDG",
+		"ETH": "XETH", "ETC": "XETC", "LTC": "XLTC", "XRP": "XXRP",
+		"XLM": "XXLM", "XMR": "XXMR", "ZEC": "XZEC", "REP": "XREP",
+		"MLN": "XMLN", "ICN": "XICN", "DAO": "XDAO", "NMC": "XNMC",
+		"XVN": "XXVN", "USD": "ZUSD", "EUR": "ZEUR", "GBP": "ZGBP",
+		"JPY": "ZJPY", "CAD": "ZCAD", "KRW": "ZKRW", "AUD": "ZAUD",
+		"btc": "XXBT", "XXBT": "XXBT", "DOT": "DOT", "USDT": "USDT",
+		"XTZ.S": "XTZ.S", "BTC.M": "XXBT.M",
+	} {
+		if got := AssetName(currency.NewCode(code)); got != want {
+			t.Errorf("Test Failed - AssetName(%s) expected %s received %s", code, want, got)
+		}
+	}
+	for asset := range assetCodes {
+		if got := AssetCode(AssetName(AssetCode(asset))); !got.Match(AssetCode(asset)) {
+			t.Errorf("Test Failed - AssetName(%s) did not round trip, received %s", asset, got)
+		}
+	}
+}
+
+func TestMatchTickers(t *testing.T) {
+	t.Parallel()
+	btcusd := currency.NewPair(currency.XBT, currency.USD)
+	dotusd := currency.NewPair(currency.NewCode("DOT"), currency.USD)
+	symbols := map[string]currency.Pair{"XBTUSD": btcusd, "DOTUSD": dotusd}
+	altnames := map[string]string{"XXBTZUSD": "XBTUSD", "DOTUSD": "DOTUSD"}
+	tickers := Tickers{
+		"XXBTZUSD": {Last: 10000},
+		"DOTUSD":   {Last: 5},
+		"XETHZUSD": {Last: 200},
+	}
+	resp := matchTickers(tickers, symbols, altnames)
+	if len(resp) != 2 {
+		t.Fatalf("Test Failed - matchTickers() expected 2 tickers, received %d", len(resp))
+	}
+	for i := range resp {
+		switch {
+		case resp[i].Pair.Equal(btcusd) && resp[i].Last == 10000:
+		case resp[i].Pair.Equal(dotusd) && resp[i].Last == 5:
+		default:
+			t.Errorf("Test Failed - matchTickers() unexpected ticker %+v", resp[i])
+		}
+	}
+}
+
+func TestDryRunStake(t *testing.T) {
+	t.Parallel()
+	var d Kraken
+	d.SetDefaults()
+	d.AuthenticatedAPISupport = true
+	d.SetDryRun(true)
+	if _, err := d.Stake("XTZ", "tezos-staked", 1); err != nil {
+		t.Error("Test Failed - Stake() expected dry run to skip request", err)
+	}
+	if mutating(krakenBalance) || !mutating(krakenStake) {
+		t.Error("Test Failed - mutating() expected only state changing endpoints")
+	}
+}
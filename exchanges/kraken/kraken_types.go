@@ -1,6 +1,9 @@
 package kraken
 
-import "github.com/thrasher-corp/gocryptotrader/currency"
+import (
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
+)
 
 // TimeResponse type
 type TimeResponse struct {
@@ -14,6 +17,9 @@ type Asset struct {
 	AclassBase      string `json:"aclass_base"`
 	Decimals        int    `json:"decimals"`
 	DisplayDecimals int    `json:"display_decimals"`
+	// Status is enabled, deposit_only, withdrawal_only or
+	// funding_temporarily_disabled
+	Status string `json:"status"`
 }
 
 // AssetPairs holds asset pair information
@@ -77,6 +83,19 @@ type OpenHighLowClose struct {
 	Count  float64
 }
 
+// krakenCandleIntervals maps candle intervals to their OHLC interval minutes
+var krakenCandleIntervals = map[kline.Interval]int{
+	kline.OneMin:     1,
+	kline.FiveMin:    5,
+	kline.FifteenMin: 15,
+	kline.ThirtyMin:  30,
+	kline.OneHour:    60,
+	kline.FourHour:   240,
+	kline.OneDay:     1440,
+	kline.OneWeek:    10080,
+	kline.FifteenDay: 21600,
+}
+
 // RecentTrades holds recent trade data
 type RecentTrades struct {
 	Price         float64
@@ -290,6 +309,18 @@ type AddOrderResponse struct {
 	TransactionIds []string         `json:"txid"`
 }
 
+// EditOrderResponse type
+type EditOrderResponse struct {
+	Description     OrderDescription `json:"descr"`
+	TransactionID   string           `json:"txid"`
+	OriginalTxID    string           `json:"originaltxid"`
+	Volume          float64          `json:"volume,string"`
+	Price           float64          `json:"price,string"`
+	OrdersCancelled int64            `json:"orders_cancelled"`
+	Status          string           `json:"status"`
+	ErrorMessage    string           `json:"error_message"`
+}
+
 // WithdrawInformation Used to check withdrawal fees
 type WithdrawInformation struct {
 	Method string  `json:"method"`
@@ -305,6 +336,22 @@ type DepositMethods struct {
 	AddressSetupFee float64     `json:"address-setup-fee,string"`
 }
 
+// DepositStatus holds the status of a deposit
+type DepositStatus struct {
+	Method      string  `json:"method"`
+	AssetClass  string  `json:"aclass"`
+	Asset       string  `json:"asset"`
+	ReferenceID string  `json:"refid"`
+	TxID        string  `json:"txid"`
+	Info        string  `json:"info"`
+	Amount      float64 `json:"amount,string"`
+	Fee         float64 `json:"fee,string"`
+	Time        float64 `json:"time"`
+	Status      string  `json:"status"`
+	// StatusProp flags deposits on hold or returned as "onhold" or "return"
+	StatusProp string `json:"status-prop"`
+}
+
 // OrderDescription represents an orders description
 type OrderDescription struct {
 	Close string `json:"close"`
@@ -387,6 +434,50 @@ type WithdrawStatusResponse struct {
 	Status string  `json:"status"`
 }
 
+// StakingMinimum defines the minimum amounts which can be staked and unstaked
+type StakingMinimum struct {
+	Staking   float64 `json:"staking,string"`
+	Unstaking float64 `json:"unstaking,string"`
+}
+
+// StakingReward defines the reward paid for staking an asset
+type StakingReward struct {
+	Reward float64 `json:"reward,string"`
+	// Type is percentage for a yearly percentage reward
+	Type string `json:"type"`
+}
+
+// StakeableAsset defines an asset which can be staked
+type StakeableAsset struct {
+	Method string `json:"method"`
+	Asset  string `json:"asset"`
+	// StakingAsset is the asset balance staked funds are held in, such as XTZ.S
+	StakingAsset   string         `json:"staking_asset"`
+	Rewards        StakingReward  `json:"rewards"`
+	OnChain        bool           `json:"on_chain"`
+	CanStake       bool           `json:"can_stake"`
+	CanUnstake     bool           `json:"can_unstake"`
+	MinimumAmount  StakingMinimum `json:"minimum_amount"`
+	EnabledForUser bool           `json:"enabled_for_user"`
+	Disabled       bool           `json:"disabled"`
+}
+
+// StakingTransaction defines a staking, unstaking or reward transaction
+type StakingTransaction struct {
+	Method string  `json:"method"`
+	Aclass string  `json:"aclass"`
+	Asset  string  `json:"asset"`
+	Refid  string  `json:"refid"`
+	Amount float64 `json:"amount,string"`
+	Fee    float64 `json:"fee,string"`
+	Time   float64 `json:"time"`
+	Status string  `json:"status"`
+	// Type is bonding, reward or unbonding
+	Type      string  `json:"type"`
+	BondStart float64 `json:"bond_start"`
+	BondEnd   float64 `json:"bond_end"`
+}
+
 // WebsocketSubscriptionEventRequest handles WS subscription events
 type WebsocketSubscriptionEventRequest struct {
 	Event        string                    `json:"event"`           // subscribe
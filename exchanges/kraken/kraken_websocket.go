@@ -57,6 +57,9 @@ var krakenOrderBooks map[int64]orderbook.Base
 var orderbookBuffer map[int64][]orderbook.Base
 var subscribeToDefaultChannels = true
 
+// krakenWsBookDepths are the book depths Kraken accepts, shallowest first
+var krakenWsBookDepths = []int64{10, 25, 100, 500, 1000}
+
 // Channels require a topic and a currency
 // Format [[ticker,but-t4u],[orderbook,nce-btt]]
 var defaultSubscribedChannels = []string{krakenWsTicker, krakenWsTrade, krakenWsOrderbook, krakenWsOHLC, krakenWsSpread}
@@ -270,11 +273,17 @@ func (k *Kraken) wsProcessTickers(channelData *WebsocketChannelData, data interf
 	lowData := tickerData["l"].([]interface{})
 	highData := tickerData["h"].([]interface{})
 	volumeData := tickerData["v"].([]interface{})
+	askData := tickerData["a"].([]interface{})
+	bidData := tickerData["b"].([]interface{})
 	closePrice, _ := strconv.ParseFloat(closeData[0].(string), 64)
 	openPrice, _ := strconv.ParseFloat(openData[0].(string), 64)
 	highPrice, _ := strconv.ParseFloat(highData[0].(string), 64)
 	lowPrice, _ := strconv.ParseFloat(lowData[0].(string), 64)
 	quantity, _ := strconv.ParseFloat(volumeData[0].(string), 64)
+	askPrice, _ := strconv.ParseFloat(askData[0].(string), 64)
+	askSize, _ := strconv.ParseFloat(askData[2].(string), 64)
+	bidPrice, _ := strconv.ParseFloat(bidData[0].(string), 64)
+	bidSize, _ := strconv.ParseFloat(bidData[2].(string), 64)
 
 	k.Websocket.DataHandler <- wshandler.TickerData{
 		Timestamp:  time.Now(),
@@ -286,6 +295,10 @@ func (k *Kraken) wsProcessTickers(channelData *WebsocketChannelData, data interf
 		HighPrice:  highPrice,
 		LowPrice:   lowPrice,
 		Quantity:   quantity,
+		BidPrice:   bidPrice,
+		BidSize:    bidSize,
+		AskPrice:   askPrice,
+		AskSize:    askSize,
 	}
 }
 
@@ -715,6 +728,9 @@ func (k *Kraken) Subscribe(channelToSubscribe wshandler.WebsocketChannelSubscrip
 		},
 		RequestID: k.WebsocketConn.GenerateMessageID(true),
 	}
+	if channelToSubscribe.Channel == krakenWsOrderbook {
+		resp.Subscription.Depth = k.subscribeBookDepth(channelToSubscribe.Currency)
+	}
 	_, err := k.WebsocketConn.SendMessageReturnResponse(resp.RequestID, resp)
 	return err
 }
@@ -729,6 +745,60 @@ func (k *Kraken) Unsubscribe(channelToSubscribe wshandler.WebsocketChannelSubscr
 		},
 		RequestID: k.WebsocketConn.GenerateMessageID(true),
 	}
+	if channelToSubscribe.Channel == krakenWsOrderbook {
+		// Books must be unsubscribed at the depth they were subscribed at
+		k.wsDepthMtx.Lock()
+		resp.Subscription.Depth = k.wsSubscribedDepth[channelToSubscribe.Currency.String()]
+		k.wsDepthMtx.Unlock()
+	}
 	_, err := k.WebsocketConn.SendMessageReturnResponse(resp.RequestID, resp)
 	return err
 }
+
+// subscribeBookDepth returns the depth a pair's book is to be subscribed at,
+// zero for Kraken's default, and records it for unsubscribing
+func (k *Kraken) subscribeBookDepth(p currency.Pair) int64 {
+	k.wsDepthMtx.Lock()
+	defer k.wsDepthMtx.Unlock()
+	if k.wsSubscribedDepth == nil {
+		k.wsSubscribedDepth = make(map[string]int64)
+	}
+	depth := k.wsBookDepth[p.String()]
+	k.wsSubscribedDepth[p.String()] = depth
+	return depth
+}
+
+// SetOrderbookDepth sets the number of levels each side subscribed for a
+// pair's websocket book, rounded up to a depth Kraken accepts. A subscribed
+// book is resubscribed at the new depth
+func (k *Kraken) SetOrderbookDepth(p currency.Pair, depth int) error {
+	if depth <= 0 {
+		return errors.New("orderbook depth must be positive")
+	}
+	levels := krakenWsBookDepths[len(krakenWsBookDepths)-1]
+	for _, d := range krakenWsBookDepths {
+		if int64(depth) <= d {
+			levels = d
+			break
+		}
+	}
+	p.Delimiter = "/"
+
+	k.wsDepthMtx.Lock()
+	if k.wsBookDepth == nil {
+		k.wsBookDepth = make(map[string]int64)
+	}
+	current, ok := k.wsBookDepth[p.String()]
+	k.wsBookDepth[p.String()] = levels
+	_, subscribed := k.wsSubscribedDepth[p.String()]
+	k.wsDepthMtx.Unlock()
+
+	if (ok && current == levels) || !subscribed || !k.Websocket.IsConnected() {
+		return nil
+	}
+	k.Websocket.ResubscribeToChannel(wshandler.WebsocketChannelSubscription{
+		Channel:  krakenWsOrderbook,
+		Currency: p,
+	})
+	return nil
+}
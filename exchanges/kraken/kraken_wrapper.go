@@ -3,6 +3,7 @@ package kraken
 import (
 	"errors"
 	"fmt"
+	"strconv"
 	"strings"
 	"sync"
 	"time"
@@ -10,6 +11,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
@@ -36,6 +38,7 @@ func (k *Kraken) Run() {
 	if err != nil {
 		log.Errorf("%s Failed to get available symbols.\n", k.GetName())
 	} else {
+		k.setPairAltnames(assetPairs)
 		forceUpgrade := false
 		if !common.StringDataContains(k.EnabledPairs.Strings(), "-") ||
 			!common.StringDataContains(k.AvailablePairs.Strings(), "-") {
@@ -84,37 +87,113 @@ func (k *Kraken) Run() {
 	}
 }
 
-// UpdateTicker updates and returns the ticker for a currency pair
+// UpdateTicker updates and returns the ticker for a currency pair, updating
+// the tickers of every enabled pair
 func (k *Kraken) UpdateTicker(p currency.Pair, assetType string) (ticker.Price, error) {
-	var tickerPrice ticker.Price
-	pairs := k.GetEnabledCurrencies()
-	pairsCollated, err := exchange.GetAndFormatExchangeCurrencies(k.Name, pairs)
+	tickers, err := k.GetTickersBatched(nil)
+	if err != nil && len(tickers) == 0 {
+		return ticker.Price{}, err
+	}
+	for i := range tickers {
+		ticker.ProcessTicker(k.GetName(), &tickers[i], assetType)
+	}
+	if err != nil {
+		log.Warnf("%s failed to update all tickers: %s", k.GetName(), err)
+	}
+	return ticker.GetTicker(k.GetName(), p, assetType)
+}
+
+// setPairAltnames stores the alternative name of each of Kraken's pairs
+func (k *Kraken) setPairAltnames(assetPairs map[string]AssetPairs) {
+	altnames := make(map[string]string, len(assetPairs))
+	for name := range assetPairs {
+		altnames[name] = assetPairs[name].Altname
+	}
+	k.pairMtx.Lock()
+	k.pairAltnames = altnames
+	k.pairMtx.Unlock()
+}
+
+// getPairAltnames returns the alternative name of each of Kraken's pairs,
+// fetching them when not yet stored
+func (k *Kraken) getPairAltnames() (map[string]string, error) {
+	k.pairMtx.Lock()
+	altnames := k.pairAltnames
+	k.pairMtx.Unlock()
+	if altnames != nil {
+		return altnames, nil
+	}
+	assetPairs, err := k.GetAssetPairs()
 	if err != nil {
-		return tickerPrice, err
+		return nil, err
+	}
+	k.setPairAltnames(assetPairs)
+	return k.getPairAltnames()
+}
+
+// GetTickersBatched fetches the tickers of the pairs, or of every enabled pair
+// when none are supplied, splitting them into batches of krakenTickerBatchSize
+// pairs. A failed batch does not stop the remaining batches, the tickers
+// fetched are returned with the first error
+func (k *Kraken) GetTickersBatched(pairs []currency.Pair) ([]ticker.Price, error) {
+	if len(pairs) == 0 {
+		pairs = k.GetEnabledCurrencies()
 	}
-	tickers, err := k.GetTickers(pairsCollated)
+	altnames, err := k.getPairAltnames()
 	if err != nil {
-		return tickerPrice, err
+		return nil, err
 	}
 
-	for _, x := range pairs {
-		for y, z := range tickers {
-			if !common.StringContains(y, x.Base.Upper().String()) ||
-				!common.StringContains(y, x.Quote.Upper().String()) {
+	var resp []ticker.Price
+	var batchErr error
+	for start := 0; start < len(pairs); start += krakenTickerBatchSize {
+		end := start + krakenTickerBatchSize
+		if end > len(pairs) {
+			end = len(pairs)
+		}
+		symbols := make(map[string]currency.Pair, end-start)
+		list := make([]string, 0, end-start)
+		for i := start; i < end; i++ {
+			s := pairs[i].Format(k.RequestCurrencyPairFormat.Delimiter,
+				k.RequestCurrencyPairFormat.Uppercase).String()
+			symbols[s] = pairs[i]
+			list = append(list, s)
+		}
+		tickers, err := k.GetTickers(strings.Join(list, k.RequestCurrencyPairFormat.Separator))
+		if err != nil {
+			if batchErr == nil {
+				batchErr = err
+			}
+			continue
+		}
+		resp = append(resp, matchTickers(tickers, symbols, altnames)...)
+	}
+	return resp, batchErr
+}
+
+// matchTickers returns the tickers matched to the pairs they were requested
+// for. Kraken names tickers by their pair name, e.g. XXBTZUSD, or by the
+// alternative name requested, e.g. XBTUSD
+func matchTickers(tickers Tickers, symbols map[string]currency.Pair, altnames map[string]string) []ticker.Price {
+	resp := make([]ticker.Price, 0, len(tickers))
+	for name, t := range tickers {
+		p, ok := symbols[name]
+		if !ok {
+			if p, ok = symbols[altnames[name]]; !ok {
 				continue
 			}
-			var tp ticker.Price
-			tp.Pair = x
-			tp.Last = z.Last
-			tp.Ask = z.Ask
-			tp.Bid = z.Bid
-			tp.High = z.High
-			tp.Low = z.Low
-			tp.Volume = z.Volume
-			ticker.ProcessTicker(k.GetName(), &tp, assetType)
 		}
+		resp = append(resp, ticker.Price{
+			Pair:   p,
+			Last:   t.Last,
+			Ask:    t.Ask,
+			Bid:    t.Bid,
+			High:   t.High,
+			Low:    t.Low,
+			Volume: t.Volume,
+		})
 	}
-	return ticker.GetTicker(k.GetName(), p, assetType)
+	return resp
 }
 
 // GetTickerPrice returns the ticker for a currency pair
@@ -177,7 +256,7 @@ func (k *Kraken) GetAccountInfo() (exchange.AccountInfo, error) {
 	var balances []exchange.AccountCurrencyInfo
 	for key, data := range bal {
 		balances = append(balances, exchange.AccountCurrencyInfo{
-			CurrencyName: currency.NewCode(key),
+			CurrencyName: AssetCode(key),
 			TotalValue:   data,
 		})
 	}
@@ -203,10 +282,82 @@ func (k *Kraken) GetExchangeHistory(p currency.Pair, assetType string) ([]exchan
 	return resp, common.ErrNotYetImplemented
 }
 
+// GetHistoricCandles returns the most recent candles at the interval, Kraken
+// only serves the last 720 candles of each interval
+func (k *Kraken) GetHistoricCandles(p currency.Pair, _ string, interval kline.Interval, limit int) ([]exchange.Candle, error) {
+	minutes, ok := krakenCandleIntervals[interval]
+	if !ok {
+		return nil, exchange.ErrCandleIntervalUnsupported
+	}
+
+	ohlc, err := k.GetOHLC(exchange.FormatExchangeCurrency(k.Name, p).String(), minutes)
+	if err != nil {
+		return nil, err
+	}
+	candles := make([]exchange.Candle, len(ohlc))
+	for x := range ohlc {
+		candles[x] = exchange.Candle{
+			Time:   time.Unix(int64(ohlc[x].Time), 0),
+			Open:   ohlc[x].Open,
+			High:   ohlc[x].High,
+			Low:    ohlc[x].Low,
+			Close:  ohlc[x].Close,
+			Volume: ohlc[x].Volume,
+		}
+	}
+	if limit > 0 && len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return candles, nil
+}
+
 // SubmitOrder submits a new order
 func (k *Kraken) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	return k.submitOrder(p, side, orderType, amount, price, &AddOrderOptions{})
+}
+
+// SupportedTimeInForce returns the time in force types Kraken orders accept
+func (k *Kraken) SupportedTimeInForce() []exchange.TimeInForce {
+	return []exchange.TimeInForce{
+		exchange.GoodTillCancel,
+		exchange.GoodTillDate,
+	}
+}
+
+// SubmitOrderWithTimeInForce submits a new order, good till date orders are
+// placed with an expiretm of the expiry's unix timestamp
+func (k *Kraken) SubmitOrderWithTimeInForce(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string, tif exchange.TimeInForce, expiry time.Time) (exchange.SubmitOrderResponse, error) {
+	err := tif.Validate(orderType, expiry)
+	if err != nil {
+		return exchange.SubmitOrderResponse{}, err
+	}
+
+	var args AddOrderOptions
+	switch tif {
+	case exchange.GoodTillCancel:
+	case exchange.GoodTillDate:
+		args.ExpireTm = strconv.FormatInt(expiry.Unix(), 10)
+	default:
+		return exchange.SubmitOrderResponse{}, exchange.ErrTimeInForceUnsupported
+	}
+	return k.submitOrder(p, side, orderType, amount, price, &args)
+}
+
+// ValidateOrder validates an order with Kraken without placing it
+func (k *Kraken) ValidateOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64) error {
+	_, err := k.AddOrder(p.String(),
+		side.ToString(),
+		orderType.ToString(),
+		amount,
+		price,
+		0,
+		0,
+		&AddOrderOptions{Validate: true})
+	return err
+}
+
+func (k *Kraken) submitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, args *AddOrderOptions) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	var args = AddOrderOptions{}
 
 	response, err := k.AddOrder(p.String(),
 		side.ToString(),
@@ -215,7 +366,7 @@ func (k *Kraken) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType
 		price,
 		0,
 		0,
-		&args)
+		args)
 
 	if len(response.TransactionIds) > 0 {
 		submitOrderResponse.OrderID = strings.Join(response.TransactionIds, ", ")
@@ -231,7 +382,18 @@ func (k *Kraken) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType
 // ModifyOrder will allow of changing orderbook placement and limit to
 // market conversion
 func (k *Kraken) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
-	return "", common.ErrFunctionNotSupported
+	resp, err := k.EditOrder(action.OrderID,
+		exchange.FormatExchangeCurrency(k.Name, action.CurrencyPair).String(),
+		action.Amount,
+		action.Price)
+	if err != nil {
+		return "", err
+	}
+	if resp.Status == "err" {
+		return "", errors.New(resp.ErrorMessage)
+	}
+
+	return resp.TransactionID, nil
 }
 
 // CancelOrder cancels an order by its corresponding ID number
@@ -265,12 +427,35 @@ func (k *Kraken) CancelAllOrders(_ *exchange.OrderCancellation) (exchange.Cancel
 // GetOrderInfo returns information on a current open order
 func (k *Kraken) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
 	var orderDetail exchange.OrderDetail
-	return orderDetail, common.ErrNotYetImplemented
+	resp, err := k.QueryOrdersInfo(OrderInfoOptions{}, orderID)
+	if err != nil {
+		return orderDetail, err
+	}
+
+	o, ok := resp[orderID]
+	if !ok {
+		return orderDetail, fmt.Errorf("order %s not found", orderID)
+	}
+
+	return exchange.OrderDetail{
+		Exchange:        k.Name,
+		ID:              orderID,
+		CurrencyPair:    currency.NewPairDelimiter(o.Descr.Pair, k.ConfigCurrencyPairFormat.Delimiter),
+		OrderSide:       exchange.OrderSide(strings.ToUpper(o.Descr.Type)),
+		OrderType:       exchange.OrderType(strings.ToUpper(o.Descr.OrderType)),
+		OrderDate:       time.Unix(int64(o.OpenTm), 0),
+		Status:          o.Status,
+		Price:           o.Descr.Price,
+		Amount:          o.Vol,
+		ExecutedAmount:  o.VolExec,
+		RemainingAmount: o.Vol - o.VolExec,
+		Fee:             o.Fee,
+	}, nil
 }
 
 // GetDepositAddress returns a deposit address for a specified currency
 func (k *Kraken) GetDepositAddress(cryptocurrency currency.Code, _ string) (string, error) {
-	methods, err := k.GetDepositMethods(cryptocurrency.String())
+	methods, err := k.GetDepositMethods(AssetName(cryptocurrency))
 	if err != nil {
 		return "", err
 	}
@@ -284,13 +469,13 @@ func (k *Kraken) GetDepositAddress(cryptocurrency currency.Code, _ string) (stri
 		return "", errors.New("method not found")
 	}
 
-	return k.GetCryptoDepositAddress(method, cryptocurrency.String())
+	return k.GetCryptoDepositAddress(method, AssetName(cryptocurrency))
 }
 
 // WithdrawCryptocurrencyFunds returns a withdrawal ID when a withdrawal
 // Populate exchange.WithdrawRequest.TradePassword with withdrawal key name, as set up on your account
 func (k *Kraken) WithdrawCryptocurrencyFunds(withdrawRequest *exchange.WithdrawRequest) (string, error) {
-	return k.Withdraw(withdrawRequest.Currency.String(), withdrawRequest.TradePassword, withdrawRequest.Amount)
+	return k.Withdraw(AssetName(withdrawRequest.Currency), withdrawRequest.TradePassword, withdrawRequest.Amount)
 }
 
 // WithdrawFiatFunds returns a withdrawal ID when a
@@ -419,3 +604,43 @@ func (k *Kraken) GetSubscriptions() ([]wshandler.WebsocketChannelSubscription, e
 func (k *Kraken) AuthenticateWebsocket() error {
 	return common.ErrFunctionNotSupported
 }
+
+// AssetNetworkStatus returns the funding status of a currency from Kraken's
+// asset info, matching either the asset's code or its alternative name
+func AssetNetworkStatus(assets map[string]Asset, c currency.Code) (exchange.NetworkStatus, bool) {
+	want := AssetCode(c.String())
+	for code, a := range assets {
+		if !AssetCode(code).Match(want) && !AssetCode(a.Altname).Match(want) {
+			continue
+		}
+		s := exchange.NetworkStatus{Reason: a.Status}
+		switch a.Status {
+		case "", "enabled":
+			s.DepositEnabled, s.WithdrawEnabled, s.Reason = true, true, ""
+		case "deposit_only":
+			s.DepositEnabled = true
+		case "withdrawal_only":
+			s.WithdrawEnabled = true
+		}
+		return s, true
+	}
+	return exchange.NetworkStatus{}, false
+}
+
+// GetAssetStatus returns whether deposits and withdrawals of a currency are
+// enabled
+func (k *Kraken) GetAssetStatus(c currency.Code) (exchange.AssetStatus, error) {
+	assets, err := k.GetAssets()
+	if err != nil {
+		return exchange.AssetStatus{}, err
+	}
+	s, ok := AssetNetworkStatus(assets, c)
+	if !ok {
+		return exchange.AssetStatus{}, fmt.Errorf("%s asset %s not found", k.Name, c)
+	}
+	return exchange.AssetStatus{
+		Exchange: k.Name,
+		Currency: c,
+		Networks: []exchange.NetworkStatus{s},
+	}, nil
+}
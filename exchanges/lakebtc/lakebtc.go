@@ -339,11 +339,24 @@ func (l *LakeBTC) SendHTTPRequest(path string, result interface{}) error {
 	return l.SendPayload(http.MethodGet, path, nil, nil, result, false, false, l.Verbose, l.HTTPDebugging)
 }
 
+// mutating returns whether the method changes exchange state. Every method
+// is a POST so they are classified by name
+func mutating(method string) bool {
+	switch method {
+	case lakeBTCBuyOrder, lakeBTCSellOrder, lakeBTCCancelOrder, lakeBTCCreateWithdraw:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an autheticated HTTP request to a LakeBTC
 func (l *LakeBTC) SendAuthenticatedHTTPRequest(method, params string, result interface{}) (err error) {
 	if !l.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, l.Name)
 	}
+	if l.DryRunRequest(mutating(method), method, params) {
+		return nil
+	}
 
 	n := l.Requester.GetNonce(true).String()
 
@@ -0,0 +1,384 @@
+// Package latency benchmarks how quickly exchanges report order fills by
+// placing small orders and timing the websocket execution notification
+// against detection through REST polling
+package latency
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default benchmark settings
+const (
+	DefaultSamples      = 10
+	DefaultInterval     = time.Second * 5
+	DefaultTimeout      = time.Second * 30
+	DefaultPollInterval = time.Millisecond * 250
+
+	// maxBufferedMessages bounds the websocket messages kept while waiting
+	// for an order ID to be returned
+	maxBufferedMessages = 1000
+)
+
+var (
+	errNilExchange   = errors.New("latency exchange is nil")
+	errInvalidAmount = errors.New("latency order amount must be positive")
+	errOrderRejected = errors.New("latency order was not placed")
+)
+
+// Config defines the orders placed by a benchmark
+type Config struct {
+	Pair      currency.Pair
+	Side      exchange.OrderSide
+	OrderType exchange.OrderType
+	Amount    float64
+	Price     float64
+	Samples   int
+	// Interval is the delay between orders
+	Interval time.Duration
+	// Timeout is how long a fill is waited for on each channel
+	Timeout time.Duration
+	// PollInterval is the delay between REST order status requests
+	PollInterval time.Duration
+}
+
+// Sample holds the timings of a single benchmark order
+type Sample struct {
+	OrderID           string
+	Submitted         time.Time
+	SubmitLatency     time.Duration
+	WebsocketLatency  time.Duration
+	RESTLatency       time.Duration
+	WebsocketDetected bool
+	RESTDetected      bool
+	Err               error
+}
+
+// Distribution summarises a set of latencies
+type Distribution struct {
+	Count int
+	Min   time.Duration
+	Mean  time.Duration
+	P50   time.Duration
+	P90   time.Duration
+	P99   time.Duration
+	Max   time.Duration
+}
+
+// String implements the stringer interface
+func (d Distribution) String() string {
+	if d.Count == 0 {
+		return "no samples"
+	}
+	return fmt.Sprintf("n=%d min=%s mean=%s p50=%s p90=%s p99=%s max=%s",
+		d.Count, d.Min, d.Mean, d.P50, d.P90, d.P99, d.Max)
+}
+
+// NewDistribution returns the distribution of the supplied latencies
+func NewDistribution(latencies []time.Duration) Distribution {
+	if len(latencies) == 0 {
+		return Distribution{}
+	}
+	sorted := make([]time.Duration, len(latencies))
+	copy(sorted, latencies)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i] < sorted[j] })
+
+	var total time.Duration
+	for i := range sorted {
+		total += sorted[i]
+	}
+	return Distribution{
+		Count: len(sorted),
+		Min:   sorted[0],
+		Mean:  total / time.Duration(len(sorted)),
+		P50:   percentile(sorted, 50),
+		P90:   percentile(sorted, 90),
+		P99:   percentile(sorted, 99),
+		Max:   sorted[len(sorted)-1],
+	}
+}
+
+// percentile returns the nearest rank percentile of sorted latencies
+func percentile(sorted []time.Duration, p float64) time.Duration {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// Report holds the benchmark results for an exchange
+type Report struct {
+	Exchange        string
+	Samples         []Sample
+	Submit          Distribution
+	Websocket       Distribution
+	REST            Distribution
+	WebsocketMisses int
+	RESTMisses      int
+	Failures        int
+}
+
+// String implements the stringer interface
+func (r *Report) String() string {
+	return fmt.Sprintf("%s\n\tsubmit:    %s\n\twebsocket: %s (missed %d)\n\tREST:      %s (missed %d)\n\tfailed orders: %d",
+		r.Exchange,
+		r.Submit,
+		r.Websocket, r.WebsocketMisses,
+		r.REST, r.RESTMisses,
+		r.Failures)
+}
+
+type message struct {
+	received time.Time
+	payload  []byte
+}
+
+// Benchmark places orders on an exchange and times their fill notifications
+type Benchmark struct {
+	exch     exchange.IBotExchange
+	cfg      Config
+	ws       *wshandler.Websocket
+	messages []message
+	notify   chan struct{}
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a new benchmark. The benchmark consumes the exchange's
+// websocket data handler while running so it must not be shared with other
+// consumers
+func New(e exchange.IBotExchange, cfg Config) (*Benchmark, error) {
+	if e == nil {
+		return nil, errNilExchange
+	}
+	if cfg.Amount <= 0 {
+		return nil, errInvalidAmount
+	}
+	if cfg.Samples <= 0 {
+		cfg.Samples = DefaultSamples
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = DefaultTimeout
+	}
+	if cfg.PollInterval <= 0 {
+		cfg.PollInterval = DefaultPollInterval
+	}
+	if cfg.OrderType == "" {
+		cfg.OrderType = exchange.MarketOrderType
+	}
+	if cfg.Side == "" {
+		cfg.Side = exchange.BuyOrderSide
+	}
+
+	b := &Benchmark{exch: e, cfg: cfg, notify: make(chan struct{}, 1)}
+	ws, err := e.GetWebsocket()
+	if err == nil && ws != nil && ws.IsEnabled() {
+		b.ws = ws
+	}
+	return b, nil
+}
+
+// Run places the configured number of orders and returns the latency report
+func (b *Benchmark) Run() Report {
+	if b.ws != nil {
+		b.shutdown = make(chan struct{})
+		b.wg.Add(1)
+		go b.listen()
+		defer func() {
+			close(b.shutdown)
+			b.wg.Wait()
+		}()
+	}
+
+	r := Report{Exchange: b.exch.GetName()}
+	for i := 0; i < b.cfg.Samples; i++ {
+		if i > 0 {
+			time.Sleep(b.cfg.Interval)
+		}
+		s := b.measure()
+		if s.Err != nil {
+			log.Warnf("Latency %s sample %d failed: %s", r.Exchange, i+1, s.Err)
+		}
+		r.Samples = append(r.Samples, s)
+	}
+	r.summarise()
+	return r
+}
+
+// summarise builds the report distributions from its samples
+func (r *Report) summarise() {
+	var submit, ws, rest []time.Duration
+	for i := range r.Samples {
+		if r.Samples[i].OrderID == "" {
+			r.Failures++
+			continue
+		}
+		submit = append(submit, r.Samples[i].SubmitLatency)
+		if r.Samples[i].WebsocketDetected {
+			ws = append(ws, r.Samples[i].WebsocketLatency)
+		} else {
+			r.WebsocketMisses++
+		}
+		if r.Samples[i].RESTDetected {
+			rest = append(rest, r.Samples[i].RESTLatency)
+		} else {
+			r.RESTMisses++
+		}
+	}
+	r.Submit = NewDistribution(submit)
+	r.Websocket = NewDistribution(ws)
+	r.REST = NewDistribution(rest)
+}
+
+// measure places a single order and waits for both fill notifications
+func (b *Benchmark) measure() Sample {
+	var s Sample
+	s.Submitted = time.Now()
+	resp, err := b.exch.SubmitOrder(b.cfg.Pair,
+		b.cfg.Side,
+		b.cfg.OrderType,
+		b.cfg.Amount,
+		b.cfg.Price,
+		"")
+	s.SubmitLatency = time.Since(s.Submitted)
+	if err != nil {
+		s.Err = err
+		return s
+	}
+	if !resp.IsOrderPlaced || resp.OrderID == "" {
+		s.Err = errOrderRejected
+		return s
+	}
+	s.OrderID = resp.OrderID
+
+	var wg sync.WaitGroup
+	if b.ws != nil {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var received time.Time
+			received, s.WebsocketDetected = b.waitWebsocket(s.OrderID, s.Submitted)
+			if s.WebsocketDetected {
+				s.WebsocketLatency = received.Sub(s.Submitted)
+			}
+		}()
+	}
+
+	var restErr error
+	var detected time.Time
+	detected, s.RESTDetected, restErr = b.pollREST(s.OrderID)
+	if s.RESTDetected {
+		s.RESTLatency = detected.Sub(s.Submitted)
+	}
+	wg.Wait()
+	if restErr != nil {
+		s.Err = restErr
+	}
+	return s
+}
+
+// listen buffers websocket messages with their receipt time
+func (b *Benchmark) listen() {
+	defer b.wg.Done()
+	for {
+		select {
+		case <-b.shutdown:
+			return
+		case <-b.ws.Connected:
+		case <-b.ws.Disconnected:
+		case data := <-b.ws.DataHandler:
+			received := time.Now()
+			payload, err := json.Marshal(data)
+			if err != nil {
+				payload = []byte(fmt.Sprint(data))
+			}
+			b.mtx.Lock()
+			b.messages = append(b.messages, message{received: received, payload: payload})
+			if len(b.messages) > maxBufferedMessages {
+				b.messages = b.messages[len(b.messages)-maxBufferedMessages:]
+			}
+			b.mtx.Unlock()
+			select {
+			case b.notify <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// waitWebsocket waits for a websocket message referencing the order ID which
+// was received after the order was submitted
+func (b *Benchmark) waitWebsocket(orderID string, since time.Time) (time.Time, bool) {
+	id := []byte(orderID)
+	timeout := time.NewTimer(b.cfg.Timeout)
+	defer timeout.Stop()
+	for {
+		b.mtx.Lock()
+		for i := range b.messages {
+			if b.messages[i].received.Before(since) {
+				continue
+			}
+			if bytes.Contains(b.messages[i].payload, id) {
+				received := b.messages[i].received
+				b.mtx.Unlock()
+				return received, true
+			}
+		}
+		b.mtx.Unlock()
+
+		select {
+		case <-timeout.C:
+			return time.Time{}, false
+		case <-b.notify:
+		}
+	}
+}
+
+// pollREST polls the order until it reports an executed amount, falling back
+// to searching order history when order info is not supported
+func (b *Benchmark) pollREST(orderID string) (time.Time, bool, error) {
+	deadline := time.Now().Add(b.cfg.Timeout)
+	useHistory := false
+	for time.Now().Before(deadline) {
+		if !useHistory {
+			detail, err := b.exch.GetOrderInfo(orderID)
+			switch {
+			case err == common.ErrNotYetImplemented || err == common.ErrFunctionNotSupported:
+				useHistory = true
+				continue
+			case err == nil && detail.ExecutedAmount > 0:
+				return time.Now(), true, nil
+			}
+		} else {
+			history, err := b.exch.GetOrderHistory(&exchange.GetOrdersRequest{
+				Currencies: []currency.Pair{b.cfg.Pair},
+			})
+			if err == nil {
+				for i := range history {
+					if history[i].ID == orderID {
+						return time.Now(), true, nil
+					}
+				}
+			}
+		}
+		time.Sleep(b.cfg.PollInterval)
+	}
+	return time.Time{}, false, nil
+}
@@ -0,0 +1,184 @@
+package latency
+
+import (
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
+)
+
+type testFill struct {
+	OrderID string
+	Amount  float64
+}
+
+type testExchange struct {
+	exchange.IBotExchange
+	ws          *wshandler.Websocket
+	orders      int
+	infoCalls   int
+	noOrderInfo bool
+	silent      bool
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) GetWebsocket() (*wshandler.Websocket, error) {
+	if t.ws == nil {
+		return nil, common.ErrFunctionNotSupported
+	}
+	return t.ws, nil
+}
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, amount, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.orders++
+	id := strconv.Itoa(1000 + t.orders)
+	if t.ws != nil && !t.silent {
+		// Notification arrives before the REST response is returned
+		t.ws.DataHandler <- testFill{OrderID: id, Amount: amount}
+	}
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true, OrderID: id}, nil
+}
+
+func (t *testExchange) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	if t.noOrderInfo {
+		return exchange.OrderDetail{}, common.ErrNotYetImplemented
+	}
+	t.infoCalls++
+	if t.infoCalls%2 == 1 {
+		return exchange.OrderDetail{ID: orderID}, nil
+	}
+	return exchange.OrderDetail{ID: orderID, ExecutedAmount: 1}, nil
+}
+
+func (t *testExchange) GetOrderHistory(_ *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	return []exchange.OrderDetail{{ID: strconv.Itoa(1000 + t.orders)}}, nil
+}
+
+func testWebsocket(t *testing.T) *wshandler.Websocket {
+	ws := wshandler.New()
+	err := ws.Setup(func() error { return nil },
+		nil,
+		nil,
+		"test",
+		true,
+		false,
+		"",
+		"",
+		false)
+	if err != nil {
+		t.Fatal("Test Failed - Websocket Setup() error", err)
+	}
+	return ws
+}
+
+func testConfig() Config {
+	return Config{
+		Pair:         currency.NewPair(currency.BTC, currency.USD),
+		Amount:       0.001,
+		Samples:      3,
+		Interval:     time.Millisecond,
+		Timeout:      time.Millisecond * 200,
+		PollInterval: time.Millisecond,
+	}
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, testConfig())
+	if err != errNilExchange {
+		t.Error("Test Failed - New() expected nil exchange error", err)
+	}
+
+	cfg := testConfig()
+	cfg.Amount = 0
+	_, err = New(&testExchange{}, cfg)
+	if err != errInvalidAmount {
+		t.Error("Test Failed - New() expected invalid amount error", err)
+	}
+
+	b, err := New(&testExchange{}, Config{Amount: 1})
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	if b.cfg.Samples != DefaultSamples ||
+		b.cfg.Timeout != DefaultTimeout ||
+		b.cfg.OrderType != exchange.MarketOrderType ||
+		b.cfg.Side != exchange.BuyOrderSide {
+		t.Error("Test Failed - New() defaults not applied")
+	}
+	if b.ws != nil {
+		t.Error("Test Failed - New() expected no websocket")
+	}
+}
+
+func TestNewDistribution(t *testing.T) {
+	if d := NewDistribution(nil); d.Count != 0 || d.String() != "no samples" {
+		t.Error("Test Failed - NewDistribution() expected empty distribution")
+	}
+
+	var latencies []time.Duration
+	for i := 100; i > 0; i-- {
+		latencies = append(latencies, time.Duration(i)*time.Millisecond)
+	}
+	d := NewDistribution(latencies)
+	if d.Count != 100 ||
+		d.Min != time.Millisecond ||
+		d.Max != 100*time.Millisecond ||
+		d.P50 != 50*time.Millisecond ||
+		d.P90 != 90*time.Millisecond ||
+		d.P99 != 99*time.Millisecond ||
+		d.Mean != 50500*time.Microsecond {
+		t.Errorf("Test Failed - NewDistribution() unexpected result %s", d)
+	}
+	if latencies[0] != 100*time.Millisecond {
+		t.Error("Test Failed - NewDistribution() modified supplied latencies")
+	}
+}
+
+func TestRun(t *testing.T) {
+	e := &testExchange{ws: testWebsocket(t)}
+	b, err := New(e, testConfig())
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	r := b.Run()
+	if r.Exchange != "test" || len(r.Samples) != 3 || r.Failures != 0 {
+		t.Fatalf("Test Failed - Run() unexpected report %+v", r)
+	}
+	if r.Websocket.Count != 3 || r.WebsocketMisses != 0 {
+		t.Errorf("Test Failed - Run() expected all websocket fills detected, %s", r.Websocket)
+	}
+	if r.REST.Count != 3 || r.RESTMisses != 0 {
+		t.Errorf("Test Failed - Run() expected all REST fills detected, %s", r.REST)
+	}
+	if e.infoCalls != 6 {
+		t.Errorf("Test Failed - Run() expected order info to be polled until filled, received %d calls",
+			e.infoCalls)
+	}
+	for i := range r.Samples {
+		if r.Samples[i].RESTLatency < r.Samples[i].WebsocketLatency {
+			t.Error("Test Failed - Run() REST fill detected before websocket fill")
+		}
+	}
+}
+
+func TestRunFallbacks(t *testing.T) {
+	e := &testExchange{ws: testWebsocket(t), noOrderInfo: true, silent: true}
+	cfg := testConfig()
+	cfg.Samples = 1
+	b, err := New(e, cfg)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	r := b.Run()
+	if r.WebsocketMisses != 1 {
+		t.Error("Test Failed - Run() expected websocket miss")
+	}
+	if r.REST.Count != 1 {
+		t.Error("Test Failed - Run() expected order history fallback to detect fill")
+	}
+}
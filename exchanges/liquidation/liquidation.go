@@ -0,0 +1,419 @@
+// Package liquidation estimates liquidation prices for leveraged derivative
+// positions, compares them against live mark prices and escalates alerts, and
+// optionally deleverages positions through reduce only orders
+package liquidation
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// MarginMode defines the margin mode of a position
+type MarginMode string
+
+// Margin modes
+const (
+	Isolated MarginMode = "isolated"
+	Cross    MarginMode = "cross"
+)
+
+// Level defines the severity of a liquidation alert
+type Level int
+
+// Alert levels in escalating order
+const (
+	Safe Level = iota
+	Warning
+	Critical
+	Deleverage
+)
+
+// String implements the stringer interface
+func (l Level) String() string {
+	switch l {
+	case Warning:
+		return "WARNING"
+	case Critical:
+		return "CRITICAL"
+	case Deleverage:
+		return "DELEVERAGE"
+	}
+	return "SAFE"
+}
+
+// Default alert thresholds expressed as the fractional distance between mark
+// price and liquidation price
+const (
+	DefaultWarningDistance    = 0.10
+	DefaultCriticalDistance   = 0.05
+	DefaultDeleverageDistance = 0.02
+	// DefaultDeleverageFraction is the fraction of the position closed on
+	// each deleverage
+	DefaultDeleverageFraction = 0.25
+	// DefaultCheckInterval is the delay between position checks
+	DefaultCheckInterval = time.Second * 30
+)
+
+var (
+	errInvalidSize       = errors.New("position size cannot be zero")
+	errInvalidEntryPrice = errors.New("position entry price must be positive")
+	errInvalidLeverage   = errors.New("position leverage must be positive")
+	errInvalidMMR        = errors.New("maintenance margin rate must be between 0 and 1")
+	errInvalidThresholds = errors.New("alert distances must be positive and in descending order")
+	errNoSources         = errors.New("no position sources supplied")
+	errNoAlertHandler    = errors.New("no alert handler supplied")
+)
+
+// Position holds the details required to estimate a liquidation price
+type Position struct {
+	Exchange string
+	Symbol   string
+	// Size is the position size in contracts, positive for long and negative
+	// for short positions
+	Size       float64
+	EntryPrice float64
+	MarkPrice  float64
+	Leverage   float64
+	MarginMode MarginMode
+	// MaintenanceMarginRate is the fraction of position value that must be
+	// held as maintenance margin
+	MaintenanceMarginRate float64
+	// Inverse is true for contracts margined and settled in the base currency
+	// such as BitMEX XBTUSD and OKEX coin margined futures
+	Inverse bool
+	// ContractValue is the value of a single contract, in quote currency for
+	// inverse contracts and base currency for linear contracts
+	ContractValue float64
+	// AvailableMargin is the additional free collateral backing a cross margin
+	// position, in the margin currency
+	AvailableMargin float64
+	// ExchangeLiquidationPrice is the liquidation price reported by the
+	// exchange, if any
+	ExchangeLiquidationPrice float64
+}
+
+// IsLong returns whether the position is long
+func (p *Position) IsLong() bool {
+	return p.Size > 0
+}
+
+func (p *Position) validate() error {
+	if p.Size == 0 {
+		return errInvalidSize
+	}
+	if p.EntryPrice <= 0 {
+		return errInvalidEntryPrice
+	}
+	if p.Leverage <= 0 {
+		return errInvalidLeverage
+	}
+	if p.MaintenanceMarginRate < 0 || p.MaintenanceMarginRate >= 1 {
+		return errInvalidMMR
+	}
+	return nil
+}
+
+// Calculate estimates the liquidation price of the position. Isolated margin
+// positions are backed only by their initial margin, cross margin positions
+// are additionally backed by the available margin of the account.
+func Calculate(p *Position) (float64, error) {
+	err := p.validate()
+	if err != nil {
+		return 0, err
+	}
+
+	contractValue := p.ContractValue
+	if contractValue <= 0 {
+		contractValue = 1
+	}
+
+	// marginRatio is the margin backing the position as a fraction of its
+	// value at entry
+	marginRatio := 1 / p.Leverage
+	if p.MarginMode == Cross && p.AvailableMargin > 0 {
+		var value float64
+		if p.Inverse {
+			value = math.Abs(p.Size) * contractValue / p.EntryPrice
+		} else {
+			value = math.Abs(p.Size) * contractValue * p.EntryPrice
+		}
+		marginRatio += p.AvailableMargin / value
+	}
+
+	mmr := p.MaintenanceMarginRate
+	var liq float64
+	switch {
+	case p.Inverse && p.IsLong():
+		liq = p.EntryPrice / (1 + marginRatio - mmr)
+	case p.Inverse:
+		d := 1 - marginRatio + mmr
+		if d <= 0 {
+			// margin exceeds the position value, a short inverse position
+			// cannot be liquidated
+			return math.Inf(1), nil
+		}
+		liq = p.EntryPrice / d
+	case p.IsLong():
+		liq = p.EntryPrice * (1 - marginRatio + mmr)
+	default:
+		liq = p.EntryPrice * (1 + marginRatio - mmr)
+	}
+
+	if liq < 0 {
+		liq = 0
+	}
+	return liq, nil
+}
+
+// Distance returns the fractional distance between the mark price and the
+// liquidation price, a value of zero or less means the position is at or
+// beyond its liquidation price
+func Distance(p *Position, liquidationPrice float64) float64 {
+	if p.MarkPrice <= 0 || math.IsInf(liquidationPrice, 1) {
+		return math.Inf(1)
+	}
+	if p.IsLong() {
+		return (p.MarkPrice - liquidationPrice) / p.MarkPrice
+	}
+	return (liquidationPrice - p.MarkPrice) / p.MarkPrice
+}
+
+// Thresholds defines the distances at which each alert level is raised
+type Thresholds struct {
+	Warning    float64
+	Critical   float64
+	Deleverage float64
+	// DeleverageFraction is the fraction of the position to close when the
+	// deleverage level is reached, zero disables auto deleveraging
+	DeleverageFraction float64
+}
+
+// DefaultThresholds returns the default alert thresholds
+func DefaultThresholds() Thresholds {
+	return Thresholds{
+		Warning:            DefaultWarningDistance,
+		Critical:           DefaultCriticalDistance,
+		Deleverage:         DefaultDeleverageDistance,
+		DeleverageFraction: DefaultDeleverageFraction,
+	}
+}
+
+// Validate checks the thresholds
+func (t *Thresholds) Validate() error {
+	if t.Deleverage <= 0 || t.Critical <= t.Deleverage || t.Warning <= t.Critical {
+		return errInvalidThresholds
+	}
+	if t.DeleverageFraction < 0 || t.DeleverageFraction > 1 {
+		return errors.New("deleverage fraction must be between 0 and 1")
+	}
+	return nil
+}
+
+// Level returns the alert level for the supplied distance
+func (t *Thresholds) Level(distance float64) Level {
+	switch {
+	case distance <= t.Deleverage:
+		return Deleverage
+	case distance <= t.Critical:
+		return Critical
+	case distance <= t.Warning:
+		return Warning
+	}
+	return Safe
+}
+
+// Alert is emitted when a position's alert level escalates
+type Alert struct {
+	Position         Position
+	Level            Level
+	LiquidationPrice float64
+	Distance         float64
+	Deleveraged      float64
+	Timestamp        time.Time
+}
+
+// String implements the stringer interface
+func (a *Alert) String() string {
+	return fmt.Sprintf("%s %s %s size %v mark %v liquidation %v distance %.2f%%",
+		a.Level,
+		a.Position.Exchange,
+		a.Position.Symbol,
+		a.Position.Size,
+		a.Position.MarkPrice,
+		a.LiquidationPrice,
+		a.Distance*100)
+}
+
+// Source provides positions from an exchange and can reduce them
+type Source interface {
+	GetName() string
+	GetPositions() ([]Position, error)
+	// ReducePosition closes the supplied amount of contracts using a reduce
+	// only order
+	ReducePosition(p *Position, amount float64) error
+}
+
+// Monitor periodically checks positions from its sources and emits alerts
+// when a position's alert level escalates
+type Monitor struct {
+	sources    []Source
+	thresholds Thresholds
+	handler    func(Alert)
+	levels     map[string]Level
+	shutdown   chan struct{}
+	wg         sync.WaitGroup
+	mtx        sync.Mutex
+}
+
+// NewMonitor returns a new liquidation monitor
+func NewMonitor(sources []Source, t Thresholds, handler func(Alert)) (*Monitor, error) {
+	if len(sources) == 0 {
+		return nil, errNoSources
+	}
+	if handler == nil {
+		return nil, errNoAlertHandler
+	}
+	err := t.Validate()
+	if err != nil {
+		return nil, err
+	}
+	return &Monitor{
+		sources:    sources,
+		thresholds: t,
+		handler:    handler,
+		levels:     make(map[string]Level),
+	}, nil
+}
+
+func positionKey(p *Position) string {
+	side := "long"
+	if !p.IsLong() {
+		side = "short"
+	}
+	return p.Exchange + ":" + p.Symbol + ":" + side
+}
+
+// Check evaluates all positions once
+func (m *Monitor) Check() {
+	seen := make(map[string]bool)
+	for i := range m.sources {
+		positions, err := m.sources[i].GetPositions()
+		if err != nil {
+			log.Errorf("Liquidation monitor: %s failed to get positions. Error: %s",
+				m.sources[i].GetName(), err)
+			continue
+		}
+
+		for j := range positions {
+			seen[positionKey(&positions[j])] = true
+			m.evaluate(m.sources[i], &positions[j])
+		}
+	}
+
+	// reset levels for positions which have been closed
+	m.mtx.Lock()
+	for k := range m.levels {
+		if !seen[k] {
+			delete(m.levels, k)
+		}
+	}
+	m.mtx.Unlock()
+}
+
+func (m *Monitor) evaluate(s Source, p *Position) {
+	liq := p.ExchangeLiquidationPrice
+	if liq <= 0 {
+		var err error
+		liq, err = Calculate(p)
+		if err != nil {
+			log.Debugf("Liquidation monitor: %s %s unable to calculate liquidation price. Error: %s",
+				p.Exchange, p.Symbol, err)
+			return
+		}
+	}
+
+	distance := Distance(p, liq)
+	level := m.thresholds.Level(distance)
+	key := positionKey(p)
+
+	m.mtx.Lock()
+	previous := m.levels[key]
+	m.levels[key] = level
+	m.mtx.Unlock()
+
+	// alerts are only raised when the level escalates, deleverage is retried
+	// on every check while the position remains within the threshold
+	if level <= previous && level != Deleverage {
+		return
+	}
+
+	alert := Alert{
+		Position:         *p,
+		Level:            level,
+		LiquidationPrice: liq,
+		Distance:         distance,
+		Timestamp:        time.Now(),
+	}
+
+	if level == Deleverage && m.thresholds.DeleverageFraction > 0 {
+		amount := math.Ceil(math.Abs(p.Size) * m.thresholds.DeleverageFraction)
+		err := s.ReducePosition(p, amount)
+		if err != nil {
+			log.Errorf("Liquidation monitor: %s %s failed to deleverage. Error: %s",
+				p.Exchange, p.Symbol, err)
+		} else {
+			alert.Deleveraged = amount
+		}
+	}
+
+	m.handler(alert)
+}
+
+// Start runs position checks at the supplied interval until Stop is called
+func (m *Monitor) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				m.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops the position check routine
+func (m *Monitor) Stop() {
+	m.mtx.Lock()
+	if m.shutdown == nil {
+		m.mtx.Unlock()
+		return
+	}
+	close(m.shutdown)
+	m.shutdown = nil
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
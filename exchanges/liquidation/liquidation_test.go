@@ -0,0 +1,199 @@
+package liquidation
+
+import (
+	"math"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okgroup"
+)
+
+func almostEqual(a, b float64) bool {
+	return math.Abs(a-b) < 1e-6
+}
+
+func TestCalculate(t *testing.T) {
+	_, err := Calculate(&Position{})
+	if err != errInvalidSize {
+		t.Errorf("Test Failed - Calculate() expected %v received %v", errInvalidSize, err)
+	}
+
+	_, err = Calculate(&Position{Size: 1, EntryPrice: 100})
+	if err != errInvalidLeverage {
+		t.Errorf("Test Failed - Calculate() expected %v received %v", errInvalidLeverage, err)
+	}
+
+	tests := []struct {
+		name     string
+		position Position
+		expected float64
+	}{
+		{"linear long", Position{Size: 10, EntryPrice: 100, Leverage: 10, MaintenanceMarginRate: 0.005}, 90.5},
+		{"linear short", Position{Size: -10, EntryPrice: 100, Leverage: 10, MaintenanceMarginRate: 0.005}, 109.5},
+		{"inverse long", Position{Size: 100, EntryPrice: 10000, Leverage: 10, MaintenanceMarginRate: 0.005, Inverse: true}, 10000 / 1.095},
+		{"inverse short", Position{Size: -100, EntryPrice: 10000, Leverage: 10, MaintenanceMarginRate: 0.005, Inverse: true}, 10000 / 0.905},
+		{"cross linear long", Position{Size: 10, EntryPrice: 100, Leverage: 10, MaintenanceMarginRate: 0.005, MarginMode: Cross, AvailableMargin: 100}, 80.5},
+	}
+
+	for i := range tests {
+		liq, err := Calculate(&tests[i].position)
+		if err != nil {
+			t.Errorf("Test Failed - Calculate() %s error %v", tests[i].name, err)
+			continue
+		}
+		if !almostEqual(liq, tests[i].expected) {
+			t.Errorf("Test Failed - Calculate() %s expected %v received %v",
+				tests[i].name, tests[i].expected, liq)
+		}
+	}
+
+	liq, err := Calculate(&Position{Size: -1, EntryPrice: 100, Leverage: 0.5, Inverse: true})
+	if err != nil || !math.IsInf(liq, 1) {
+		t.Errorf("Test Failed - Calculate() expected infinite liquidation price, received %v %v", liq, err)
+	}
+}
+
+func TestDistanceAndLevels(t *testing.T) {
+	th := DefaultThresholds()
+	if err := th.Validate(); err != nil {
+		t.Fatal("Test Failed - Validate() error", err)
+	}
+
+	bad := Thresholds{Warning: 0.01, Critical: 0.05, Deleverage: 0.02}
+	if err := bad.Validate(); err != errInvalidThresholds {
+		t.Errorf("Test Failed - Validate() expected %v received %v", errInvalidThresholds, err)
+	}
+
+	long := Position{Size: 1, MarkPrice: 100}
+	short := Position{Size: -1, MarkPrice: 100}
+	if d := Distance(&long, 92); !almostEqual(d, 0.08) || th.Level(d) != Warning {
+		t.Errorf("Test Failed - long distance %v level %v", d, th.Level(d))
+	}
+
+	if d := Distance(&short, 104); !almostEqual(d, 0.04) || th.Level(d) != Critical {
+		t.Errorf("Test Failed - short distance %v level %v", d, th.Level(d))
+	}
+
+	if d := Distance(&long, 99); th.Level(d) != Deleverage {
+		t.Errorf("Test Failed - expected deleverage level, received %v", th.Level(d))
+	}
+
+	if d := Distance(&long, 50); th.Level(d) != Safe {
+		t.Errorf("Test Failed - expected safe level, received %v", th.Level(d))
+	}
+}
+
+type testSource struct {
+	positions []Position
+	reduced   float64
+}
+
+func (t *testSource) GetName() string                   { return "test" }
+func (t *testSource) GetPositions() ([]Position, error) { return t.positions, nil }
+func (t *testSource) ReducePosition(_ *Position, amount float64) error {
+	t.reduced += amount
+	return nil
+}
+
+func TestMonitor(t *testing.T) {
+	_, err := NewMonitor(nil, DefaultThresholds(), func(Alert) {})
+	if err != errNoSources {
+		t.Errorf("Test Failed - NewMonitor() expected %v received %v", errNoSources, err)
+	}
+
+	src := &testSource{}
+	var alerts []Alert
+	m, err := NewMonitor([]Source{src}, DefaultThresholds(), func(a Alert) {
+		alerts = append(alerts, a)
+	})
+	if err != nil {
+		t.Fatal("Test Failed - NewMonitor() error", err)
+	}
+
+	pos := Position{
+		Exchange:                 "test",
+		Symbol:                   "XBTUSD",
+		Size:                     100,
+		Leverage:                 10,
+		EntryPrice:               100,
+		MarkPrice:                100,
+		ExchangeLiquidationPrice: 92,
+	}
+	src.positions = []Position{pos}
+
+	m.Check()
+	m.Check()
+	if len(alerts) != 1 || alerts[0].Level != Warning {
+		t.Fatalf("Test Failed - expected a single warning alert, received %v", alerts)
+	}
+
+	src.positions[0].MarkPrice = 96
+	m.Check()
+	if len(alerts) != 2 || alerts[1].Level != Critical {
+		t.Fatalf("Test Failed - expected critical alert, received %v", alerts)
+	}
+
+	src.positions[0].MarkPrice = 93
+	m.Check()
+	if len(alerts) != 3 || alerts[2].Level != Deleverage || alerts[2].Deleveraged != 25 {
+		t.Fatalf("Test Failed - expected deleverage alert, received %v", alerts)
+	}
+
+	if src.reduced != 25 {
+		t.Errorf("Test Failed - expected 25 contracts reduced, received %v", src.reduced)
+	}
+
+	// closed positions reset their alert level
+	src.positions = nil
+	m.Check()
+	src.positions = []Position{pos}
+	m.Check()
+	if len(alerts) != 4 || alerts[3].Level != Warning {
+		t.Errorf("Test Failed - expected warning after position reopened, received %v", alerts)
+	}
+}
+
+func TestConvertBitmexPosition(t *testing.T) {
+	p := convertBitmexPosition("Bitmex", &bitmex.Position{
+		Symbol:           "XBTUSD",
+		CurrentQty:       -100,
+		AvgEntryPrice:    10000,
+		Leverage:         5,
+		CrossMargin:      true,
+		Currency:         "XBt",
+		QuoteCurrency:    "USD",
+		MaintMarginReq:   0.005,
+		LiquidationPrice: 12000,
+	}, 0.5)
+
+	if !p.Inverse || p.MarginMode != Cross || p.AvailableMargin != 0.5 || p.IsLong() {
+		t.Errorf("Test Failed - convertBitmexPosition() unexpected position %+v", p)
+	}
+}
+
+func TestConvertOKEXPosition(t *testing.T) {
+	p := convertOKEXPosition("OKEX", &okgroup.GetFuturePostionsDetails{
+		InstrumentID:    "BTC-USD-190628",
+		MarginMode:      "fixed",
+		LongQty:         "10",
+		LongAvgCost:     "8000",
+		LongLeverage:    "10",
+		LongLiquiPrice:  "7300",
+		ShortQty:        "5",
+		ShortAvgCost:    "8100",
+		ShortLeverage:   "20",
+		ShortLiquiPrice: "8500",
+	}, 8050, 0.005)
+
+	if len(p) != 2 {
+		t.Fatalf("Test Failed - convertOKEXPosition() expected 2 positions, received %v", len(p))
+	}
+
+	if !p[0].IsLong() || p[0].ContractValue != okexBTCContractValue || p[0].ExchangeLiquidationPrice != 7300 {
+		t.Errorf("Test Failed - convertOKEXPosition() unexpected long position %+v", p[0])
+	}
+
+	if p[1].IsLong() || p[1].Leverage != 20 || p[1].MarginMode != Isolated {
+		t.Errorf("Test Failed - convertOKEXPosition() unexpected short position %+v", p[1])
+	}
+}
@@ -0,0 +1,218 @@
+package liquidation
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okgroup"
+)
+
+const (
+	satoshisPerBTC = 1e8
+
+	okexBTCContractValue     = 100
+	okexDefaultContractValue = 10
+	// okexDefaultMMR is used for OKEX futures positions as the maintenance
+	// margin rate is not returned with positions
+	okexDefaultMMR = 0.005
+
+	okexCloseLong  = 3
+	okexCloseShort = 4
+)
+
+var errReduceAmount = errors.New("reduce amount must be positive")
+
+// BitmexSource provides BitMEX positions to the liquidation monitor
+type BitmexSource struct {
+	Exchange *bitmex.Bitmex
+}
+
+// GetName returns the exchange name
+func (b *BitmexSource) GetName() string {
+	return b.Exchange.GetName()
+}
+
+// GetPositions returns all open BitMEX positions
+func (b *BitmexSource) GetPositions() ([]Position, error) {
+	positions, err := b.Exchange.GetPositions(bitmex.PositionGetParams{})
+	if err != nil {
+		return nil, err
+	}
+
+	var available float64
+	margins, err := b.Exchange.GetAllUserMargin()
+	if err == nil {
+		for i := range margins {
+			if strings.EqualFold(margins[i].Currency, "XBt") {
+				available = float64(margins[i].AvailableMargin) / satoshisPerBTC
+			}
+		}
+	}
+
+	var resp []Position
+	for i := range positions {
+		if !positions[i].IsOpen || positions[i].CurrentQty == 0 {
+			continue
+		}
+		resp = append(resp, convertBitmexPosition(b.GetName(), &positions[i], available))
+	}
+	return resp, nil
+}
+
+func convertBitmexPosition(exch string, p *bitmex.Position, available float64) Position {
+	pos := Position{
+		Exchange:                 exch,
+		Symbol:                   p.Symbol,
+		Size:                     float64(p.CurrentQty),
+		EntryPrice:               p.AvgEntryPrice,
+		MarkPrice:                p.MarkPrice,
+		Leverage:                 p.Leverage,
+		MarginMode:               Isolated,
+		MaintenanceMarginRate:    p.MaintMarginReq,
+		Inverse:                  strings.EqualFold(p.Currency, "XBt") && strings.EqualFold(p.QuoteCurrency, "USD"),
+		ContractValue:            1,
+		ExchangeLiquidationPrice: p.LiquidationPrice,
+	}
+	if p.CrossMargin {
+		pos.MarginMode = Cross
+		pos.AvailableMargin = available
+	}
+	return pos
+}
+
+// ReducePosition closes part of a BitMEX position with a reduce only market
+// order
+func (b *BitmexSource) ReducePosition(p *Position, amount float64) error {
+	if amount <= 0 {
+		return errReduceAmount
+	}
+
+	side := "Sell"
+	if !p.IsLong() {
+		side = "Buy"
+	}
+
+	_, err := b.Exchange.CreateOrder(&bitmex.OrderNewParams{
+		Symbol:   p.Symbol,
+		Side:     side,
+		OrderQty: amount,
+		OrdType:  "Market",
+		ExecInst: "ReduceOnly",
+	})
+	return err
+}
+
+// OKEXFuturesSource provides OKEX futures positions to the liquidation monitor
+type OKEXFuturesSource struct {
+	Exchange *okex.OKEX
+	// MaintenanceMarginRate overrides the default maintenance margin rate
+	MaintenanceMarginRate float64
+}
+
+// GetName returns the exchange name
+func (o *OKEXFuturesSource) GetName() string {
+	return o.Exchange.GetName()
+}
+
+// GetPositions returns all open OKEX futures positions
+func (o *OKEXFuturesSource) GetPositions() ([]Position, error) {
+	resp, err := o.Exchange.GetFuturesPostions()
+	if err != nil {
+		return nil, err
+	}
+
+	mmr := o.MaintenanceMarginRate
+	if mmr == 0 {
+		mmr = okexDefaultMMR
+	}
+
+	var positions []Position
+	for i := range resp.Holding {
+		for j := range resp.Holding[i] {
+			h := &resp.Holding[i][j]
+			mark, err := o.Exchange.GetFuturesCurrentMarkPrice(h.InstrumentID)
+			if err != nil {
+				return nil, err
+			}
+			positions = append(positions, convertOKEXPosition(o.GetName(), h, mark.MarkPrice, mmr)...)
+		}
+	}
+	return positions, nil
+}
+
+func convertOKEXPosition(exch string, h *okgroup.GetFuturePostionsDetails, mark, mmr float64) []Position {
+	mode := Isolated
+	if h.MarginMode == "crossed" {
+		mode = Cross
+	}
+
+	contractValue := float64(okexDefaultContractValue)
+	if strings.HasPrefix(strings.ToUpper(h.InstrumentID), "BTC-") {
+		contractValue = okexBTCContractValue
+	}
+
+	var positions []Position
+	sides := []struct {
+		qty, cost, leverage, liq string
+		sign                     float64
+	}{
+		{h.LongQty, h.LongAvgCost, h.LongLeverage, h.LongLiquiPrice, 1},
+		{h.ShortQty, h.ShortAvgCost, h.ShortLeverage, h.ShortLiquiPrice, -1},
+	}
+	for i := range sides {
+		qty, _ := strconv.ParseFloat(sides[i].qty, 64)
+		if qty == 0 {
+			continue
+		}
+		entry, _ := strconv.ParseFloat(sides[i].cost, 64)
+		leverage, _ := strconv.ParseFloat(sides[i].leverage, 64)
+		if leverage == 0 {
+			leverage, _ = strconv.ParseFloat(h.Leverage, 64)
+		}
+		liq, _ := strconv.ParseFloat(sides[i].liq, 64)
+		if liq == 0 {
+			liq, _ = strconv.ParseFloat(h.LiquidationPrice, 64)
+		}
+
+		positions = append(positions, Position{
+			Exchange:                 exch,
+			Symbol:                   h.InstrumentID,
+			Size:                     qty * sides[i].sign,
+			EntryPrice:               entry,
+			MarkPrice:                mark,
+			Leverage:                 leverage,
+			MarginMode:               mode,
+			MaintenanceMarginRate:    mmr,
+			Inverse:                  true,
+			ContractValue:            contractValue,
+			ExchangeLiquidationPrice: liq,
+		})
+	}
+	return positions
+}
+
+// ReducePosition closes part of an OKEX futures position at the best counter
+// party price. Close orders can only reduce a position.
+func (o *OKEXFuturesSource) ReducePosition(p *Position, amount float64) error {
+	if amount <= 0 {
+		return errReduceAmount
+	}
+
+	orderType := int64(okexCloseLong)
+	if !p.IsLong() {
+		orderType = okexCloseShort
+	}
+
+	_, err := o.Exchange.PlaceFuturesOrder(okgroup.PlaceFuturesOrderRequest{
+		InstrumentID: p.Symbol,
+		Type:         orderType,
+		Size:         int64(amount),
+		MatchPrice:   1,
+		Price:        p.MarkPrice,
+		Leverage:     int64(p.Leverage),
+	})
+	return err
+}
@@ -731,12 +731,35 @@ func (l *LocalBitcoins) SendHTTPRequest(path string, result interface{}) error {
 	return l.SendPayload(http.MethodGet, path, nil, nil, result, false, false, l.Verbose, l.HTTPDebugging)
 }
 
+// mutating returns whether the endpoint changes exchange state. Reads such as
+// messages and notifications are also sent as POST requests so endpoints are
+// classified by name, matching on prefix as IDs are appended to the path
+func mutating(path string) bool {
+	for _, p := range []string{
+		localbitcoinsAPIAdEdit, localbitcoinsAPIAdCreate, localbitcoinsAPIUpdateEquation,
+		localbitcoinsAPIDeleteAd, localbitcoinsAPIRelease, localbitcoinsAPIReleaseByPin,
+		localbitcoinsAPIMarkAsPaid, localbitcoinsAPISendMessage, localbitcoinsAPIDispute,
+		localbitcoinsAPICancelTrade, localbitcoinsAPIFundTrade, localbitcoinsAPIConfirmRealName,
+		localbitcoinsAPIVerifyIdentity, localbitcoinsAPIInitiateTrade, localbitcoinsAPIFeedback,
+		localbitcoinsAPILogout, localbitcoinsAPICreateInvoice, localbitcoinsAPIMarkNotification,
+		localbitcoinsAPIWalletSend, localbitcoinsAPIWalletSendPin,
+	} {
+		if strings.HasPrefix(path, p) {
+			return true
+		}
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP request to
 // localbitcoins
 func (l *LocalBitcoins) SendAuthenticatedHTTPRequest(method, path string, params url.Values, result interface{}) (err error) {
 	if !l.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, l.Name)
 	}
+	if l.DryRunRequest(mutating(path), path, params) {
+		return nil
+	}
 
 	n := l.Requester.GetNonce(true).String()
 
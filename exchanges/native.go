@@ -0,0 +1,40 @@
+package exchange
+
+// OrderChecker is implemented by wrappers whose order submission only checks
+// or adjusts the order, so orders may be placed beneath them through the
+// optional capabilities of the underlying exchange, such as bracket, stop,
+// post only and batch orders, once they pass the wrapper's checks
+type OrderChecker interface {
+	// CheckOrder returns the error SubmitOrder would reject the order with,
+	// adjusting the order as SubmitOrder would
+	CheckOrder(o *OrderSubmission) error
+}
+
+// Native checks the order against every wrapper of the exchange, returning
+// the underlying exchange for the order to be placed through its optional
+// capabilities once it passes. Nil is returned without checking the order
+// when any wrapper is not an OrderChecker, such as read only, dry run and
+// wrappers recording the orders submitted through them, in which case the
+// order must be submitted through the wrapped exchange
+func Native(e IBotExchange, o *OrderSubmission) (IBotExchange, error) {
+	var checkers []OrderChecker
+	for {
+		w, ok := e.(Wrapper)
+		if !ok {
+			break
+		}
+		c, ok := e.(OrderChecker)
+		if !ok {
+			return nil, nil
+		}
+		checkers = append(checkers, c)
+		e = w.Unwrap()
+	}
+	for i := range checkers {
+		err := checkers[i].CheckOrder(o)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return e, nil
+}
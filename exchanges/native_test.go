@@ -0,0 +1,52 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// testChecker halves the amount of every order it checks, rejecting orders
+// once the amount is below one
+type testChecker struct {
+	IBotExchange
+	checked int
+}
+
+func (c *testChecker) Unwrap() IBotExchange { return c.IBotExchange }
+
+func (c *testChecker) CheckOrder(o *OrderSubmission) error {
+	c.checked++
+	if o.Amount < 1 {
+		return errors.New("amount too small")
+	}
+	o.Amount /= 2
+	return nil
+}
+
+func TestNative(t *testing.T) {
+	inner := &testTIFExchange{}
+	o := OrderSubmission{Pair: currency.NewPair(currency.BTC, currency.USD), Amount: 4}
+	if e, err := Native(inner, &o); err != nil || e != inner {
+		t.Error("Test Failed - Native() expected unwrapped exchange", err)
+	}
+
+	outer := &testChecker{IBotExchange: inner}
+	e, err := Native(&testChecker{IBotExchange: outer}, &o)
+	if err != nil || e != inner || o.Amount != 1 {
+		t.Errorf("Test Failed - Native() expected checked exchange, received %v %v %v", e, o.Amount, err)
+	}
+	if _, err = Native(outer, &OrderSubmission{Amount: 0.5}); err == nil {
+		t.Error("Test Failed - Native() expected rejected order")
+	}
+
+	// Orders are left unchecked beneath wrappers which must see them
+	checked := outer.checked
+	if e, err = Native(NewDryRun(outer), &o); err != nil || e != nil || outer.checked != checked {
+		t.Error("Test Failed - Native() dry run orders must be submitted through the wrapper", err)
+	}
+	if e, _ = Native(&testChecker{IBotExchange: NewReadOnly(inner)}, &o); e != nil {
+		t.Error("Test Failed - Native() read only orders must be submitted through the wrapper")
+	}
+}
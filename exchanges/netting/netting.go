@@ -0,0 +1,458 @@
+// Package netting aggregates the target positions strategies emit for the same
+// pair on an exchange and trades only the net difference from the position
+// already traded. Opposing targets are netted internally, so strategies
+// trading against each other do not cross their own orders and pay fees on
+// both sides. Targets are accumulated between rebalances, and the targets and
+// traded positions are persisted so a restart does not trade again to reach
+// targets already reached
+package netting
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// DefaultRebalanceInterval is the interval net differences are traded at
+const DefaultRebalanceInterval = time.Second * 10
+
+// Rebalanced is the type of events emitted when a net difference is traded
+const Rebalanced = "NETTING_REBALANCED"
+
+// dust is the smallest net difference traded when no minimum amount is set,
+// ignoring float error left by summing targets
+const dust = 1e-9
+
+var (
+	errNoExchangeFunc   = errors.New("no exchange function supplied")
+	errInvalidMinAmount = errors.New("minimum amount cannot be negative")
+	errNoStrategy       = errors.New("target strategy not set")
+	errNoExchange       = errors.New("target exchange not set")
+	errNoPair           = errors.New("target pair not set")
+	errExchangeNotFound = errors.New("exchange not found")
+	errOrderNotPlaced   = errors.New("order not placed")
+)
+
+// ExchangeFunc returns the exchange net differences are traded through
+type ExchangeFunc func(exchangeName string) exchange.IBotExchange
+
+// Config holds the netting settings
+type Config struct {
+	// MinAmount is the smallest net difference traded, smaller differences
+	// wait for further target changes
+	MinAmount float64
+	// OrderType is the type of orders placed, market when unset
+	OrderType exchange.OrderType
+}
+
+// Target is a strategy's target position in a pair, positive long and
+// negative short
+type Target struct {
+	Strategy string  `json:"strategy"`
+	Position float64 `json:"position"`
+}
+
+// Book holds the targets of every strategy trading a pair on an exchange
+type Book struct {
+	Exchange string        `json:"exchange"`
+	Pair     currency.Pair `json:"pair"`
+	Targets  []Target      `json:"targets"`
+	// Net is the sum of the targets, Position the position traded and
+	// Pending the difference still to be traded
+	Net      float64 `json:"net"`
+	Position float64 `json:"position"`
+	Pending  float64 `json:"pending"`
+	// Gross is the total change of the targets traded, Netted the part offset
+	// internally and Sent the part sent to the market
+	Gross      float64   `json:"gross"`
+	Netted     float64   `json:"netted"`
+	Sent       float64   `json:"sent"`
+	Error      string    `json:"error,omitempty"`
+	Rebalanced time.Time `json:"rebalanced,omitempty"`
+}
+
+// Event is emitted when a net difference is traded or fails to trade
+type Event struct {
+	Type     string             `json:"type"`
+	Exchange string             `json:"exchange"`
+	Pair     currency.Pair      `json:"pair"`
+	Side     exchange.OrderSide `json:"side"`
+	Amount   float64            `json:"amount"`
+	// Netted is the change of targets offset internally by the rebalance
+	Netted  float64   `json:"netted"`
+	OrderID string    `json:"orderID,omitempty"`
+	Error   string    `json:"error,omitempty"`
+	Time    time.Time `json:"time"`
+}
+
+// String returns a description of the event
+func (e *Event) String() string {
+	if e.Error != "" {
+		return fmt.Sprintf("%s %s net %s of %v failed: %s",
+			e.Exchange, e.Pair, e.Side, e.Amount, e.Error)
+	}
+	return fmt.Sprintf("%s %s net %s of %v placed, %v netted internally",
+		e.Exchange, e.Pair, e.Side, e.Amount, e.Netted)
+}
+
+// book is a pair's persisted targets and traded position
+type book struct {
+	Exchange string             `json:"exchange"`
+	Pair     currency.Pair      `json:"pair"`
+	Targets  map[string]float64 `json:"targets"`
+	// Traded holds the targets as of the last rebalance
+	Traded     map[string]float64 `json:"traded"`
+	Position   float64            `json:"position"`
+	Gross      float64            `json:"gross"`
+	Netted     float64            `json:"netted"`
+	Sent       float64            `json:"sent"`
+	Error      string             `json:"error,omitempty"`
+	Rebalanced time.Time          `json:"rebalanced,omitempty"`
+}
+
+func (b *book) net() float64 {
+	var net float64
+	for _, t := range b.Targets {
+		net += t
+	}
+	return net
+}
+
+// gross returns the total change of the targets since the last rebalance
+func (b *book) gross() float64 {
+	var gross float64
+	for s, t := range b.Targets {
+		gross += math.Abs(t - b.Traded[s])
+	}
+	for s, t := range b.Traded {
+		if _, ok := b.Targets[s]; !ok {
+			gross += math.Abs(t)
+		}
+	}
+	return gross
+}
+
+// rebalance is a net difference to trade
+type rebalance struct {
+	key     string
+	diff    float64
+	gross   float64
+	targets map[string]float64
+}
+
+// Netter nets the targets of strategies and trades the difference
+type Netter struct {
+	cfg      Config
+	path     string
+	exchange ExchangeFunc
+	onEvent  func(Event)
+	books    map[string]*book
+	now      func() time.Time
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	// trading serialises rebalances, which place orders without holding mtx
+	trading sync.Mutex
+	mtx     sync.Mutex
+}
+
+// New returns a netter trading through the exchanges returned by exch,
+// persisting to path when set
+func New(cfg Config, path string, exch ExchangeFunc, onEvent func(Event)) (*Netter, error) {
+	if exch == nil {
+		return nil, errNoExchangeFunc
+	}
+	if cfg.MinAmount < 0 {
+		return nil, errInvalidMinAmount
+	}
+	if cfg.MinAmount == 0 {
+		cfg.MinAmount = dust
+	}
+	if cfg.OrderType == "" {
+		cfg.OrderType = exchange.MarketOrderType
+	}
+	n := &Netter{
+		cfg:      cfg,
+		path:     path,
+		exchange: exch,
+		onEvent:  onEvent,
+		books:    make(map[string]*book),
+		now:      time.Now,
+	}
+	if path == "" {
+		return n, nil
+	}
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return n, nil
+		}
+		return nil, err
+	}
+	if err = json.Unmarshal(data, &n.books); err != nil {
+		return nil, err
+	}
+	for _, b := range n.books {
+		if b.Targets == nil {
+			b.Targets = make(map[string]float64)
+		}
+		if b.Traded == nil {
+			b.Traded = make(map[string]float64)
+		}
+	}
+	return n, nil
+}
+
+// save writes the books to the file, the caller must hold the lock
+func (n *Netter) save() {
+	if n.path == "" {
+		return
+	}
+	data, err := json.MarshalIndent(n.books, "", " ")
+	if err == nil {
+		err = common.WriteFile(n.path, data)
+	}
+	if err != nil {
+		log.Errorf("Netting failed to save to %s: %s", n.path, err)
+	}
+}
+
+func key(exchName string, p currency.Pair) string {
+	return strings.ToLower(exchName) + " " + strings.ToUpper(p.Base.String()+p.Quote.String())
+}
+
+// SetTarget sets a strategy's target position in a pair, traded at the next
+// rebalance net of the other strategies' targets
+func (n *Netter) SetTarget(strategy, exchName string, p currency.Pair, position float64) error {
+	switch {
+	case strategy == "":
+		return errNoStrategy
+	case exchName == "":
+		return errNoExchange
+	case p.Base.IsEmpty() || p.Quote.IsEmpty():
+		return errNoPair
+	}
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	k := key(exchName, p)
+	b, ok := n.books[k]
+	if !ok {
+		b = &book{
+			Exchange: exchName,
+			Pair:     p,
+			Targets:  make(map[string]float64),
+			Traded:   make(map[string]float64),
+		}
+		n.books[k] = b
+	}
+	b.Targets[strategy] = position
+	return nil
+}
+
+// RemoveStrategy removes a strategy's targets, its positions are traded out
+// at the next rebalance unless offset by other strategies
+func (n *Netter) RemoveStrategy(strategy string) {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	for _, b := range n.books {
+		delete(b.Targets, strategy)
+	}
+}
+
+// Rebalance trades the net difference of each pair's targets from its traded
+// position. A failed order is retried at the next rebalance
+func (n *Netter) Rebalance() {
+	n.trading.Lock()
+	defer n.trading.Unlock()
+
+	n.mtx.Lock()
+	var pending []rebalance
+	for k, b := range n.books {
+		gross := b.gross()
+		if gross == 0 && math.Abs(b.net()-b.Position) < n.cfg.MinAmount {
+			continue
+		}
+		targets := make(map[string]float64, len(b.Targets))
+		for s, t := range b.Targets {
+			targets[s] = t
+		}
+		pending = append(pending, rebalance{
+			key:     k,
+			diff:    b.net() - b.Position,
+			gross:   gross,
+			targets: targets,
+		})
+	}
+	n.mtx.Unlock()
+
+	var events []Event
+	for i := range pending {
+		if e, ok := n.trade(&pending[i]); ok {
+			events = append(events, e)
+		}
+	}
+
+	n.mtx.Lock()
+	if len(pending) > 0 {
+		n.save()
+	}
+	n.mtx.Unlock()
+	if n.onEvent != nil {
+		for i := range events {
+			n.onEvent(events[i])
+		}
+	}
+}
+
+// trade places the order for a net difference, returning an event when an
+// order was attempted
+func (n *Netter) trade(r *rebalance) (Event, bool) {
+	n.mtx.Lock()
+	b := n.books[r.key]
+	exchName, p := b.Exchange, b.Pair
+	n.mtx.Unlock()
+
+	amount := math.Abs(r.diff)
+	if amount < n.cfg.MinAmount {
+		// The targets changed without moving the net enough to trade, the
+		// remainder is traded with later changes
+		n.mtx.Lock()
+		b.Gross += r.gross
+		b.Netted += r.gross
+		b.Traded = r.targets
+		n.mtx.Unlock()
+		return Event{}, false
+	}
+
+	side := exchange.BuyOrderSide
+	if r.diff < 0 {
+		side = exchange.SellOrderSide
+	}
+	e := Event{
+		Type:     Rebalanced,
+		Exchange: exchName,
+		Pair:     p,
+		Side:     side,
+		Amount:   amount,
+	}
+	var resp exchange.SubmitOrderResponse
+	exch := n.exchange(exchName)
+	err := errExchangeNotFound
+	if exch != nil {
+		resp, err = exch.SubmitOrder(p, side, n.cfg.OrderType, amount, 0, "")
+		if err == nil && !resp.IsOrderPlaced {
+			err = errOrderNotPlaced
+		}
+	}
+
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	e.Time = n.now()
+	if err != nil {
+		e.Error = err.Error()
+		b.Error = e.Error
+		return e, true
+	}
+	e.OrderID = resp.OrderID
+	if r.gross > amount {
+		e.Netted = r.gross - amount
+	}
+	b.Position += r.diff
+	b.Traded = r.targets
+	b.Gross += r.gross
+	b.Netted += e.Netted
+	b.Sent += amount
+	b.Error = ""
+	b.Rebalanced = e.Time
+	return e, true
+}
+
+// GetBooks returns the targets and traded position of every pair, sorted by
+// exchange and pair
+func (n *Netter) GetBooks() []Book {
+	n.mtx.Lock()
+	defer n.mtx.Unlock()
+	resp := make([]Book, 0, len(n.books))
+	for _, b := range n.books {
+		net := b.net()
+		x := Book{
+			Exchange:   b.Exchange,
+			Pair:       b.Pair,
+			Net:        net,
+			Position:   b.Position,
+			Pending:    net - b.Position,
+			Gross:      b.Gross,
+			Netted:     b.Netted,
+			Sent:       b.Sent,
+			Error:      b.Error,
+			Rebalanced: b.Rebalanced,
+		}
+		for s, t := range b.Targets {
+			x.Targets = append(x.Targets, Target{Strategy: s, Position: t})
+		}
+		sort.Slice(x.Targets, func(i, j int) bool {
+			return x.Targets[i].Strategy < x.Targets[j].Strategy
+		})
+		resp = append(resp, x)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		if resp[i].Exchange != resp[j].Exchange {
+			return resp[i].Exchange < resp[j].Exchange
+		}
+		return resp[i].Pair.String() < resp[j].Pair.String()
+	})
+	return resp
+}
+
+// Start rebalances at the interval until stopped
+func (n *Netter) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRebalanceInterval
+	}
+	n.mtx.Lock()
+	if n.shutdown != nil {
+		n.mtx.Unlock()
+		return
+	}
+	n.shutdown = make(chan struct{})
+	shutdown := n.shutdown
+	n.mtx.Unlock()
+
+	n.wg.Add(1)
+	go func() {
+		defer n.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				n.Rebalance()
+			}
+		}
+	}()
+}
+
+// Stop stops rebalancing
+func (n *Netter) Stop() {
+	n.mtx.Lock()
+	if n.shutdown == nil {
+		n.mtx.Unlock()
+		return
+	}
+	close(n.shutdown)
+	n.shutdown = nil
+	n.mtx.Unlock()
+	n.wg.Wait()
+}
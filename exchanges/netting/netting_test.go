@@ -0,0 +1,146 @@
+package netting
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testOrder struct {
+	side   exchange.OrderSide
+	amount float64
+}
+
+type testExchange struct {
+	exchange.IBotExchange
+	orders []testOrder
+	err    error
+}
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, side exchange.OrderSide, _ exchange.OrderType, amount, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	if t.err != nil {
+		return exchange.SubmitOrderResponse{}, t.err
+	}
+	t.orders = append(t.orders, testOrder{side, amount})
+	return exchange.SubmitOrderResponse{OrderID: "1", IsOrderPlaced: true}, nil
+}
+
+func TestSetTarget(t *testing.T) {
+	if _, err := New(Config{}, "", nil, nil); err != errNoExchangeFunc {
+		t.Error("Test Failed - New() expected no exchange function error", err)
+	}
+	exch := func(string) exchange.IBotExchange { return nil }
+	if _, err := New(Config{MinAmount: -1}, "", exch, nil); err != errInvalidMinAmount {
+		t.Error("Test Failed - New() expected invalid minimum amount error", err)
+	}
+	n, err := New(Config{}, "", exch, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	btc := currency.NewPairWithDelimiter("BTC", "USD", "-")
+	if err = n.SetTarget("", "Bitmex", btc, 1); err != errNoStrategy {
+		t.Error("Test Failed - SetTarget() expected no strategy error", err)
+	}
+	if err = n.SetTarget("trend", "Bitmex", currency.Pair{}, 1); err != errNoPair {
+		t.Error("Test Failed - SetTarget() expected no pair error", err)
+	}
+	// Pairs are matched regardless of their delimiter and case
+	if err = n.SetTarget("trend", "Bitmex", btc, 1); err != nil {
+		t.Fatal(err)
+	}
+	if err = n.SetTarget("hedge", "bitmex", currency.NewPairFromString("btcusd"), -1); err != nil {
+		t.Fatal(err)
+	}
+	if b := n.GetBooks(); len(b) != 1 || len(b[0].Targets) != 2 || b[0].Net != 0 {
+		t.Errorf("Test Failed - GetBooks() expected one netted book %+v", b)
+	}
+}
+
+func TestRebalance(t *testing.T) {
+	dir, err := ioutil.TempDir("", "netting")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "netting.json")
+
+	bitmex := &testExchange{}
+	exch := func(string) exchange.IBotExchange { return bitmex }
+	var events []Event
+	onEvent := func(e Event) { events = append(events, e) }
+	n, err := New(Config{MinAmount: 0.01}, path, exch, onEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	btc := currency.NewPairWithDelimiter("BTC", "USD", "-")
+
+	// Opposing targets are netted, only the difference is traded
+	n.SetTarget("trend", "Bitmex", btc, 3)
+	n.SetTarget("meanrevert", "Bitmex", btc, -2)
+	n.Rebalance()
+	if len(bitmex.orders) != 1 || bitmex.orders[0].side != exchange.BuyOrderSide || bitmex.orders[0].amount != 1 {
+		t.Fatalf("Test Failed - Rebalance() expected a net buy of 1 %+v", bitmex.orders)
+	}
+	if len(events) != 1 || events[0].Netted != 4 || events[0].OrderID != "1" {
+		t.Errorf("Test Failed - Rebalance() unexpected events %+v", events)
+	}
+
+	// Offsetting changes send nothing to the market
+	n.SetTarget("trend", "Bitmex", btc, 4)
+	n.SetTarget("meanrevert", "Bitmex", btc, -3)
+	n.Rebalance()
+	if len(bitmex.orders) != 1 || len(events) != 1 {
+		t.Errorf("Test Failed - Rebalance() expected offsetting changes netted %+v", bitmex.orders)
+	}
+
+	// Differences below the minimum wait for further changes
+	n.SetTarget("trend", "Bitmex", btc, 4.005)
+	n.Rebalance()
+	if len(bitmex.orders) != 1 {
+		t.Errorf("Test Failed - Rebalance() expected no order below the minimum %+v", bitmex.orders)
+	}
+
+	// Failed orders are retried
+	bitmex.err = errors.New("overloaded")
+	n.SetTarget("trend", "Bitmex", btc, 3.5)
+	n.Rebalance()
+	if len(events) != 2 || events[1].Error == "" {
+		t.Fatalf("Test Failed - Rebalance() expected failure event %+v", events)
+	}
+	b := n.GetBooks()[0]
+	if b.Error == "" || b.Position != 1 || math.Abs(b.Pending+0.5) > 1e-9 {
+		t.Errorf("Test Failed - GetBooks() expected the difference pending %+v", b)
+	}
+
+	// The targets and position persist, a restart does not trade again
+	bitmex.err = nil
+	n, err = New(Config{MinAmount: 0.01}, path, exch, onEvent)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n.Rebalance()
+	if len(bitmex.orders) != 2 || bitmex.orders[1].side != exchange.SellOrderSide || math.Abs(bitmex.orders[1].amount-0.5) > 1e-9 {
+		t.Fatalf("Test Failed - Rebalance() expected the pending sell retried %+v", bitmex.orders)
+	}
+	n.Rebalance()
+	if len(bitmex.orders) != 2 {
+		t.Errorf("Test Failed - Rebalance() expected no further orders %+v", bitmex.orders)
+	}
+
+	// A removed strategy's position is traded out
+	n.RemoveStrategy("meanrevert")
+	n.Rebalance()
+	if len(bitmex.orders) != 3 || bitmex.orders[2].side != exchange.BuyOrderSide || bitmex.orders[2].amount != 3 {
+		t.Errorf("Test Failed - Rebalance() expected the removed short bought back %+v", bitmex.orders)
+	}
+	b = n.GetBooks()[0]
+	if b.Position != 3.5 || b.Sent != 4.5 || len(b.Targets) != 1 {
+		t.Errorf("Test Failed - GetBooks() unexpected book %+v", b)
+	}
+}
@@ -32,10 +32,12 @@ const (
 	okGroupFutureLeverage = "leverage"
 	okGroupFutureOrder    = "order"
 	okGroupFutureHolds    = "holds"
+	okGroupCancelAlgos    = "cancel_algos"
 	okGroupIndices        = "index"
 	okGroupRate           = "rate"
 	okGroupEsimtatedPrice = "estimated_price"
 	okGroupOpenInterest   = "open_interest"
+	okGroupSettlement     = "settlement/history"
 	// Perpetual swap based endpoints
 	okGroupSettings              = "settings"
 	okGroupDepth                 = "depth"
@@ -90,6 +92,26 @@ func (o *OKEX) SetDefaults() {
 
 }
 
+// GetMarginLeverage Get the leverage of a margin trading pair.
+func (o *OKEX) GetMarginLeverage(instrumentID string) (resp okgroup.GetMarginLeverageResponse, _ error) {
+	requestURL := fmt.Sprintf("%v/%v/%v", okgroup.OKGroupAccounts, instrumentID, okGroupFutureLeverage)
+	return resp, o.SendHTTPRequest(http.MethodGet, okgroup.OKGroupMarginTradingSubsection, requestURL, nil, &resp, true)
+}
+
+// SetMarginLeverage Set the leverage of a margin trading pair. Leverage can only
+// be changed when there are no outstanding loans on the pair.
+func (o *OKEX) SetMarginLeverage(request okgroup.SetMarginLeverageRequest) (resp okgroup.GetMarginLeverageResponse, _ error) {
+	requestURL := fmt.Sprintf("%v/%v/%v", okgroup.OKGroupAccounts, request.InstrumentID, okGroupFutureLeverage)
+	return resp, o.SendHTTPRequest(http.MethodPost, okgroup.OKGroupMarginTradingSubsection, requestURL, request, &resp, true)
+}
+
+// GetMarginMarkPrice Get the mark price of a margin trading pair. This is a
+// public endpoint, no identity verification is needed.
+func (o *OKEX) GetMarginMarkPrice(instrumentID string) (resp okgroup.GetMarginMarkPriceResponse, _ error) {
+	requestURL := fmt.Sprintf("%v/%v/%v", okgroup.OKGroupInstruments, instrumentID, okgroup.OKGroupMarkPrice)
+	return resp, o.SendHTTPRequest(http.MethodGet, okgroup.OKGroupMarginTradingSubsection, requestURL, nil, &resp, false)
+}
+
 // GetFuturesPostions Get the information of all holding positions in futures trading.
 // Due to high energy consumption, you are advised to capture data with the "Futures Account of a Currency" API instead.
 func (o *OKEX) GetFuturesPostions() (resp okgroup.GetFuturesPositionsResponse, _ error) {
@@ -159,6 +181,23 @@ func (o *OKEX) CancelFuturesOrderBatch(request okgroup.CancelMultipleSpotOrdersR
 	return resp, o.SendHTTPRequest(http.MethodPost, okGroupFuturesSubsection, requestURL, request, &resp, true)
 }
 
+// PlaceFuturesAlgoOrder places a trigger, trail, iceberg or TWAP futures order
+// held exchange side until its conditions are met
+func (o *OKEX) PlaceFuturesAlgoOrder(request *okgroup.PlaceContractAlgoOrderRequest) (okgroup.AlgoOrderResponse, error) {
+	return o.placeContractAlgoOrder(okGroupFuturesSubsection, request)
+}
+
+// CancelFuturesAlgoOrders cancels up to 10 futures algo orders of one order type
+func (o *OKEX) CancelFuturesAlgoOrders(request okgroup.CancelAlgoOrdersRequest) (okgroup.AlgoOrderResponse, error) {
+	return o.cancelContractAlgoOrders(okGroupFuturesSubsection, request)
+}
+
+// GetFuturesAlgoOrders returns futures algo orders of one order type, either
+// those with the status or those with the IDs
+func (o *OKEX) GetFuturesAlgoOrders(request okgroup.GetAlgoOrdersRequest) ([]okgroup.AlgoOrder, error) {
+	return o.getContractAlgoOrders(okGroupFuturesSubsection, request)
+}
+
 // GetFuturesOrderList List your orders. Cursor pagination is used.
 // All paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKEX) GetFuturesOrderList(request okgroup.GetFuturesOrdersListRequest) (resp okgroup.GetFuturesOrderListResponse, _ error) {
@@ -204,22 +243,22 @@ func (o *OKEX) GetFuturesOrderBook(request okgroup.GetFuturesOrderBookRequest) (
 		var processedOB []okgroup.FuturesOrderbookItem
 		for x := range ob {
 			price, convErr := strconv.ParseFloat(ob[x][0], 64)
-			if err != nil {
+			if convErr != nil {
 				return nil, convErr
 			}
 
 			size, convErr := strconv.ParseInt(ob[x][1], 10, 64)
-			if err != nil {
+			if convErr != nil {
 				return nil, convErr
 			}
 
 			liqOrders, convErr := strconv.ParseInt(ob[x][2], 10, 64)
-			if err != nil {
+			if convErr != nil {
 				return nil, convErr
 			}
 
 			numOrders, convErr := strconv.ParseInt(ob[x][3], 10, 64)
-			if err != nil {
+			if convErr != nil {
 				return nil, convErr
 			}
 
@@ -296,6 +335,13 @@ func (o *OKEX) GetFuturesEstimatedDeliveryPrice(instrumentID string) (resp okgro
 	return resp, o.SendHTTPRequest(http.MethodGet, okGroupFuturesSubsection, requestURL, nil, &resp, false)
 }
 
+// GetFuturesSettlementHistory returns the weekly settlements and deliveries of futures contracts, newest first.
+// This is a public endpoint, no identity verification is needed.
+func (o *OKEX) GetFuturesSettlementHistory(request okgroup.GetFuturesSettlementHistoryRequest) (resp []okgroup.GetFuturesSettlementHistoryResponse, _ error) {
+	requestURL := fmt.Sprintf("%v%v", okGroupSettlement, okgroup.FormatParameters(request))
+	return resp, o.SendHTTPRequest(http.MethodGet, okGroupFuturesSubsection, requestURL, nil, &resp, false)
+}
+
 // GetFuturesOpenInterests Get the open interest of a contract. This is a public endpoint, no identity verification is needed.
 func (o *OKEX) GetFuturesOpenInterests(instrumentID string) (resp okgroup.GetFuturesOpenInterestsResponse, _ error) {
 	requestURL := fmt.Sprintf("%v/%v/%v", okgroup.OKGroupInstruments, instrumentID, okGroupOpenInterest)
@@ -392,6 +438,56 @@ func (o *OKEX) CancelMultipleSwapOrders(request okgroup.CancelMultipleSwapOrders
 	return resp, o.SendHTTPRequest(http.MethodPost, okGroupSwapSubsection, requestURL, request, &resp, true)
 }
 
+// PlaceSwapAlgoOrder places a trigger, trail, iceberg or TWAP swap order held
+// exchange side until its conditions are met
+func (o *OKEX) PlaceSwapAlgoOrder(request *okgroup.PlaceContractAlgoOrderRequest) (okgroup.AlgoOrderResponse, error) {
+	return o.placeContractAlgoOrder(okGroupSwapSubsection, request)
+}
+
+// CancelSwapAlgoOrders cancels up to 10 swap algo orders of one order type
+func (o *OKEX) CancelSwapAlgoOrders(request okgroup.CancelAlgoOrdersRequest) (okgroup.AlgoOrderResponse, error) {
+	return o.cancelContractAlgoOrders(okGroupSwapSubsection, request)
+}
+
+// GetSwapAlgoOrders returns swap algo orders of one order type, either those
+// with the status or those with the IDs
+func (o *OKEX) GetSwapAlgoOrders(request okgroup.GetAlgoOrdersRequest) ([]okgroup.AlgoOrder, error) {
+	return o.getContractAlgoOrders(okGroupSwapSubsection, request)
+}
+
+// placeContractAlgoOrder places a futures or swap algo order, contract algo
+// responses are wrapped in a data object
+func (o *OKEX) placeContractAlgoOrder(subsection string, request *okgroup.PlaceContractAlgoOrderRequest) (okgroup.AlgoOrderResponse, error) {
+	var resp struct {
+		Data okgroup.AlgoOrderResponse `json:"data"`
+	}
+	err := request.AlgoOrderParameters.Validate(request.OrderType)
+	if err != nil {
+		return resp.Data, err
+	}
+	err = o.SendHTTPRequest(http.MethodPost, subsection, okgroup.OKGroupAlgoOrder, request, &resp, true)
+	if err == nil && resp.Data.AlgoID == "" {
+		err = okgroup.ErrAlgoNotAcknowledged
+	}
+	return resp.Data, err
+}
+
+func (o *OKEX) cancelContractAlgoOrders(subsection string, request okgroup.CancelAlgoOrdersRequest) (okgroup.AlgoOrderResponse, error) {
+	var resp struct {
+		Data okgroup.AlgoOrderResponse `json:"data"`
+	}
+	if len(request.AlgoIDs) > okgroup.MaxAlgoCancellations {
+		return resp.Data, fmt.Errorf("maximum %d algo order cancellations", okgroup.MaxAlgoCancellations)
+	}
+	return resp.Data, o.SendHTTPRequest(http.MethodPost, subsection, okGroupCancelAlgos, request, &resp, true)
+}
+
+func (o *OKEX) getContractAlgoOrders(subsection string, request okgroup.GetAlgoOrdersRequest) ([]okgroup.AlgoOrder, error) {
+	var resp okgroup.AlgoOrderList
+	requestURL := fmt.Sprintf("%v/%v%v", okgroup.OKGroupAlgoOrder, request.InstrumentID, okgroup.FormatParameters(request))
+	return resp.Orders, o.SendHTTPRequest(http.MethodGet, subsection, requestURL, nil, &resp, true)
+}
+
 // GetSwapOrderList List your orders. Cursor pagination is used.
 // All paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKEX) GetSwapOrderList(request okgroup.GetSwapOrderListRequest) (resp okgroup.GetSwapOrderListResponse, _ error) {
@@ -458,7 +554,7 @@ func (o *OKEX) GetSwapExchangeRates() (resp okgroup.GetSwapExchangeRatesResponse
 }
 
 // GetSwapOpenInterest Get the open interest of a contract.
-func (o *OKEX) GetSwapOpenInterest(instrumentID string) (resp okgroup.GetSwapExchangeRatesResponse, _ error) {
+func (o *OKEX) GetSwapOpenInterest(instrumentID string) (resp okgroup.GetSwapOpenInterestResponse, _ error) {
 	requestURL := fmt.Sprintf("%v/%v/%v", okgroup.OKGroupInstruments, instrumentID, okGroupOpenInterest)
 	return resp, o.SendHTTPRequest(http.MethodGet, okGroupSwapSubsection, requestURL, nil, &resp, false)
 }
@@ -481,7 +577,8 @@ func (o *OKEX) GetSwapOnHoldAmountForOpenOrders(instrumentID string) (resp okgro
 	return resp, o.SendHTTPRequest(http.MethodGet, okGroupSwapSubsection, requestURL, nil, &resp, true)
 }
 
-// GetSwapNextSettlementTime Get the time of next settlement.
+// GetSwapNextSettlementTime Get the time of next settlement, the funding rate
+// charged at it and the estimated rate of the following period.
 func (o *OKEX) GetSwapNextSettlementTime(instrumentID string) (resp okgroup.GetSwapNextSettlementTimeResponse, _ error) {
 	requestURL := fmt.Sprintf("%v/%v/%v", okgroup.OKGroupInstruments, instrumentID, okGroupFundingTime)
 	return resp, o.SendHTTPRequest(http.MethodGet, okGroupSwapSubsection, requestURL, nil, &resp, false)
@@ -522,7 +619,7 @@ func (o *OKEX) GetETTBillsDetails(currency string) (resp []okgroup.GetETTBillsDe
 // the amount will be put on hold in the order lifecycle.
 // The assets and amount on hold depends on the order's specific type and parameters.
 func (o *OKEX) PlaceETTOrder(request *okgroup.PlaceETTOrderRequest) (resp okgroup.PlaceETTOrderResponse, _ error) {
-	return resp, o.SendHTTPRequest(http.MethodPost, okGroupETTSubsection, okgroup.OKGroupOrders, nil, &resp, true)
+	return resp, o.SendHTTPRequest(http.MethodPost, okGroupETTSubsection, okgroup.OKGroupOrders, request, &resp, true)
 }
 
 // CancelETTOrder Cancel an unfilled order.
@@ -0,0 +1,619 @@
+package okex
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/config"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/endpoints"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/httprecorder"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okgroup"
+)
+
+// mockFixtures holds responses recorded from the OKEX v3 spot candle, futures,
+// swap and ETT endpoints, with credentials redacted
+const mockFixtures = "testdata/http_fixtures.json"
+
+const (
+	mockKey        = "mockAPIKey"
+	mockSecret     = "mockAPISecret"
+	mockPassphrase = "mockPassphrase"
+
+	mockFutures = "BTC-USD-190329"
+	mockSwap    = "BTC-USD-SWAP"
+	mockETT     = "ok06ett"
+)
+
+// mockServer serves recorded responses to requests matching a fixture's
+// method, path and query, checking the request body and signature against
+// the recording
+type mockServer struct {
+	t        *testing.T
+	fixtures map[string]*httprecorder.Record
+	served   map[string]bool
+	mtx      sync.Mutex
+}
+
+func fixtureKey(method, requestURI string) string {
+	return method + " " + requestURI
+}
+
+func newMockOKEX(t *testing.T) (*OKEX, *mockServer, func()) {
+	f, err := os.Open(mockFixtures)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	records, err := httprecorder.LoadFixtures(f)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	m := &mockServer{
+		t:        t,
+		fixtures: make(map[string]*httprecorder.Record),
+		served:   make(map[string]bool),
+	}
+	for i := range records {
+		u, err := url.Parse(records[i].URL)
+		if err != nil {
+			t.Fatal(err)
+		}
+		m.fixtures[fixtureKey(records[i].Method, u.RequestURI())] = &records[i]
+	}
+	s := httptest.NewServer(m)
+
+	var mock OKEX
+	mock.SetDefaults()
+	mock.APIUrl = s.URL + "/" + okExAPIPath
+	mock.AuthenticatedAPISupport = true
+	mock.APIKey = mockKey
+	mock.APISecret = mockSecret
+	mock.ClientID = mockPassphrase
+	mock.Endpoints, err = okgroup.NewEndpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &mock, m, s.Close
+}
+
+func (m *mockServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	k := fixtureKey(r.Method, r.URL.RequestURI())
+	m.mtx.Lock()
+	rec, ok := m.fixtures[k]
+	m.served[k] = true
+	m.mtx.Unlock()
+	if !ok {
+		m.t.Errorf("Test Failed - no fixture recorded for %s", k)
+		w.WriteHeader(http.StatusNotFound)
+		return
+	}
+
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		m.t.Error(err)
+	}
+	if !jsonEqual(string(body), rec.RequestBody) {
+		m.t.Errorf("Test Failed - %s body %s, expected %s", k, body, rec.RequestBody)
+	}
+
+	_, signed := rec.RequestHeaders["Ok-Access-Sign"]
+	sign := r.Header.Get("OK-ACCESS-SIGN")
+	switch {
+	case signed:
+		ts := r.Header.Get("OK-ACCESS-TIMESTAMP")
+		expected := common.Base64Encode(common.GetHMAC(common.HashSHA256,
+			[]byte(ts+r.Method+r.URL.RequestURI()+string(body)), []byte(mockSecret)))
+		if ts == "" || sign != expected {
+			m.t.Errorf("Test Failed - %s signature %s, expected %s", k, sign, expected)
+		}
+		if r.Header.Get("OK-ACCESS-KEY") != mockKey ||
+			r.Header.Get("OK-ACCESS-PASSPHRASE") != mockPassphrase {
+			m.t.Errorf("Test Failed - %s credentials not sent", k)
+		}
+	case sign != "":
+		m.t.Errorf("Test Failed - %s public request was signed", k)
+	}
+
+	for h, v := range rec.ResponseHeaders {
+		w.Header().Set(h, v)
+	}
+	w.WriteHeader(rec.StatusCode)
+	w.Write([]byte(rec.ResponseBody))
+}
+
+// unserved returns the fixtures which were never requested
+func (m *mockServer) unserved() []string {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	var keys []string
+	for k := range m.fixtures {
+		if !m.served[k] {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
+func jsonEqual(a, b string) bool {
+	if a == "" || b == "" {
+		return a == b
+	}
+	var x, y interface{}
+	if json.Unmarshal([]byte(a), &x) != nil || json.Unmarshal([]byte(b), &y) != nil {
+		return false
+	}
+	return reflect.DeepEqual(x, y)
+}
+
+func TestMockedEndpoints(t *testing.T) {
+	mock, server, closeServer := newMockOKEX(t)
+	defer closeServer()
+	// Wrapper requests format pairs by the exchange config
+	err := config.GetConfig().LoadConfig("../../testdata/configtest.json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	btc := currency.NewPairWithDelimiter("BTC", "USDT", "-")
+	rangeStart := time.Date(2019, 3, 14, 5, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name string
+		call func() (bool, error)
+	}{
+		// Margin
+		{"GetMarginLeverage", func() (bool, error) {
+			r, err := mock.GetMarginLeverage("BTC-USDT")
+			return r.Leverage == 3 && r.Result, err
+		}},
+		{"SetMarginLeverage", func() (bool, error) {
+			r, err := mock.SetMarginLeverage(okgroup.SetMarginLeverageRequest{InstrumentID: "BTC-USDT", Leverage: 5})
+			return r.Leverage == 5, err
+		}},
+		{"GetMarginMarkPrice", func() (bool, error) {
+			r, err := mock.GetMarginMarkPrice("BTC-USDT")
+			return r.MarkPrice == 3880.7 && !r.Timestamp.IsZero(), err
+		}},
+
+		// Spot candles
+		{"GetHistoricCandles", func() (bool, error) {
+			r, err := mock.GetHistoricCandles(btc, "SPOT", kline.OneHour, 2)
+			return len(r) == 2 && r[0].Close == 3882.4 && r[1].Time.Hour() == 8, err
+		}},
+		{"GetHistoricCandlesRange", func() (bool, error) {
+			r, err := mock.GetHistoricCandlesRange(btc, "SPOT", kline.OneHour, rangeStart, rangeStart.Add(time.Hour*3))
+			return len(r) == 3 && r[0].Time.Equal(rangeStart) && r[0].Volume == 44.1876, err
+		}},
+
+		// Futures
+		{"GetFuturesPostions", func() (bool, error) {
+			r, err := mock.GetFuturesPostions()
+			return len(r.Holding) == 1 && len(r.Holding[0]) == 1 && r.Holding[0][0].LongQty == "2", err
+		}},
+		{"GetFuturesPostionsForCurrency", func() (bool, error) {
+			r, err := mock.GetFuturesPostionsForCurrency(mockFutures)
+			return len(r.Holding) == 1 && r.Holding[0].InstrumentID == mockFutures, err
+		}},
+		{"GetFuturesAccountOfAllCurrencies", func() (bool, error) {
+			r, err := mock.GetFuturesAccountOfAllCurrencies()
+			return len(r.Info.Currency) == 2 && r.Info.Currency["btc"].MarginRatio == "19.74", err
+		}},
+		{"GetFuturesAccountOfACurrency", func() (bool, error) {
+			r, err := mock.GetFuturesAccountOfACurrency("btc")
+			return r.Equity == "0.1021", err
+		}},
+		{"GetFuturesLeverage", func() (bool, error) {
+			r, err := mock.GetFuturesLeverage("btc")
+			return r.Leverage == 10 && r.MarginMode == "crossed", err
+		}},
+		{"SetFuturesLeverage", func() (bool, error) {
+			r, err := mock.SetFuturesLeverage(okgroup.SetFuturesLeverageRequest{Currency: "btc", Leverage: 20})
+			return r.Leverage == 20 && r.Result == "true", err
+		}},
+		{"GetFuturesBillDetails", func() (bool, error) {
+			r, err := mock.GetFuturesBillDetails(okgroup.GetSpotBillDetailsForCurrencyRequest{Currency: "btc", Limit: 2})
+			return len(r) == 1 && r[0].Details.InstrumentID == mockFutures, err
+		}},
+		{"PlaceFuturesOrder", func() (bool, error) {
+			r, err := mock.PlaceFuturesOrder(okgroup.PlaceFuturesOrderRequest{
+				ClientOid:    "fut1",
+				InstrumentID: mockFutures,
+				Type:         1,
+				Price:        3800,
+				Size:         2,
+				Leverage:     10,
+			})
+			return r.OrderID == "2510789768709120" && r.Result, err
+		}},
+		{"PlaceFuturesOrderBatch", func() (bool, error) {
+			r, err := mock.PlaceFuturesOrderBatch(okgroup.PlaceFuturesOrderBatchRequest{
+				InstrumentID: mockFutures,
+				Leverage:     10,
+				OrdersData: []okgroup.PlaceFuturesOrderBatchRequestDetails{
+					{ClientOid: "fut2", MatchPrice: "0", Price: "3700", Size: "1", Type: "1"},
+				},
+			})
+			return len(r.OrderInfo) == 1 && r.OrderInfo[0].ClientOid == "fut2", err
+		}},
+		{"CancelFuturesOrder", func() (bool, error) {
+			r, err := mock.CancelFuturesOrder(okgroup.CancelFuturesOrderRequest{InstrumentID: mockFutures, OrderID: "2510789768709120"})
+			return r.Result && r.OrderID == "2510789768709120", err
+		}},
+		{"CancelFuturesOrderBatch", func() (bool, error) {
+			r, err := mock.CancelFuturesOrderBatch(okgroup.CancelMultipleSpotOrdersRequest{
+				InstrumentID: mockFutures,
+				OrderIDs:     []int64{2510832677159936, 2510832677225472},
+			})
+			return r.Result, err
+		}},
+		{"GetFuturesOrderList", func() (bool, error) {
+			r, err := mock.GetFuturesOrderList(okgroup.GetFuturesOrdersListRequest{InstrumentID: mockFutures, Status: 2, Limit: 2})
+			return len(r.OrderInfo) == 1 && r.OrderInfo[0].PriceAvg == 3799.5, err
+		}},
+		{"GetFuturesOrderDetails", func() (bool, error) {
+			r, err := mock.GetFuturesOrderDetails(okgroup.GetFuturesOrderDetailsRequest{InstrumentID: mockFutures, OrderID: 2510789768709120})
+			return r.OrderID == 2510789768709120 && r.Status == 2, err
+		}},
+		{"GetFuturesTransactionDetails", func() (bool, error) {
+			r, err := mock.GetFuturesTransactionDetails(okgroup.GetFuturesTransactionDetailsRequest{InstrumentID: mockFutures, OrderID: 2510789768709120})
+			return len(r) == 1 && r[0].TradeID == "2510789768840192", err
+		}},
+		{"GetFuturesContractInformation", func() (bool, error) {
+			r, err := mock.GetFuturesContractInformation()
+			return len(r) == 1 && r[0].ContractVal == 100 && r[0].TickSize == 0.01, err
+		}},
+		{"GetFuturesOrderBook", func() (bool, error) {
+			r, err := mock.GetFuturesOrderBook(okgroup.GetFuturesOrderBookRequest{InstrumentID: mockFutures, Size: 2})
+			return len(r.Asks) == 2 && len(r.Bids) == 1 &&
+				r.Asks[0].Price == 3880.76 && r.Asks[0].Size == 57 && r.Asks[0].NumberOrders == 3 &&
+				r.Bids[0].ForceLiquidatedOrders == 1, err
+		}},
+		{"GetAllFuturesTokenInfo", func() (bool, error) {
+			r, err := mock.GetAllFuturesTokenInfo()
+			return len(r) == 1 && r[0].Volume24h == 1251736, err
+		}},
+		{"GetFuturesTokenInfoForCurrency", func() (bool, error) {
+			r, err := mock.GetFuturesTokenInfoForCurrency(mockFutures)
+			return r.BestBid == 3880.52 && r.BestAsk == 3880.76, err
+		}},
+		{"GetFuturesFilledOrder", func() (bool, error) {
+			r, err := mock.GetFuturesFilledOrder(okgroup.GetFuturesFilledOrderRequest{InstrumentID: mockFutures, Limit: 1})
+			return len(r) == 1 && r[0].Qty == 4, err
+		}},
+		{"GetFuturesMarketData", func() (bool, error) {
+			r, err := mock.GetFuturesMarketData(okgroup.GetFuturesMarketDateRequest{InstrumentID: mockFutures, Granularity: 60})
+			return len(r) == 1, err
+		}},
+		{"GetFuturesHoldAmount", func() (bool, error) {
+			r, err := mock.GetFuturesHoldAmount(mockFutures)
+			return r.Amount == 0.02, err
+		}},
+		{"GetFuturesIndices", func() (bool, error) {
+			r, err := mock.GetFuturesIndices(mockFutures)
+			return r.Index == 3878.9, err
+		}},
+		{"GetFuturesExchangeRates", func() (bool, error) {
+			r, err := mock.GetFuturesExchangeRates()
+			return r.Rate == 6.7098, err
+		}},
+		{"GetFuturesEstimatedDeliveryPrice", func() (bool, error) {
+			r, err := mock.GetFuturesEstimatedDeliveryPrice(mockFutures)
+			return r.SettlementPrice == 3879.2, err
+		}},
+		{"GetFuturesSettlementHistory", func() (bool, error) {
+			r, err := mock.GetFuturesSettlementHistory(okgroup.GetFuturesSettlementHistoryRequest{Underlying: "BTC-USD", Limit: 100})
+			return len(r) == 2 && r[0].Type == okgroup.FuturesDelivery && r[1].Price == 3991.25, err
+		}},
+		{"GetSettlementHistory", func() (bool, error) {
+			r, err := mock.GetSettlementHistory(currency.NewPairWithDelimiter("BTC", "USD", "-"), time.Time{})
+			return len(r) == 2 && r[0].Type == exchange.Settled && r[1].Type == exchange.Delivered &&
+				r[1].Contract == mockFutures && r[1].Price == 4051.67, err
+		}},
+		{"GetFuturesOpenInterests", func() (bool, error) {
+			r, err := mock.GetFuturesOpenInterests(mockFutures)
+			return r.Amount == 1108124, err
+		}},
+		{"GetFuturesCurrentPriceLimit", func() (bool, error) {
+			r, err := mock.GetFuturesCurrentPriceLimit(mockFutures)
+			return r.Highest == 3996.96 && r.Lowest == 3764.76, err
+		}},
+		{"GetFuturesCurrentMarkPrice", func() (bool, error) {
+			r, err := mock.GetFuturesCurrentMarkPrice(mockFutures)
+			return r.MarkPrice == 3880.61, err
+		}},
+		{"GetFuturesForceLiquidatedOrders", func() (bool, error) {
+			r, err := mock.GetFuturesForceLiquidatedOrders(okgroup.GetFuturesForceLiquidatedOrdersRequest{InstrumentID: mockFutures, Status: "1", Limit: 1})
+			return len(r) == 1 && r[0].Size == 12 && r[0].Type == 3, err
+		}},
+
+		// Perpetual swap
+		{"GetSwapPostions", func() (bool, error) {
+			r, err := mock.GetSwapPostions()
+			return len(r) == 1 && len(r[0].Holding) == 1 && r[0].Holding[0].Position == "3", err
+		}},
+		{"GetSwapPostionsForContract", func() (bool, error) {
+			r, err := mock.GetSwapPostionsForContract(mockSwap)
+			return len(r.Holding) == 1 && r.Holding[0].InstrumentID == mockSwap, err
+		}},
+		{"GetSwapAccountOfAllCurrency", func() (bool, error) {
+			r, err := mock.GetSwapAccountOfAllCurrency()
+			return len(r.Info) == 1 && r.Info[0].MarginRatio == "13.45", err
+		}},
+		{"GetSwapAccountSettingsOfAContract", func() (bool, error) {
+			r, err := mock.GetSwapAccountSettingsOfAContract(mockSwap)
+			return r.LongLeverage == 20 && r.ShortLeverage == 20, err
+		}},
+		{"SetSwapLeverageLevelOfAContract", func() (bool, error) {
+			r, err := mock.SetSwapLeverageLevelOfAContract(okgroup.SetSwapLeverageLevelOfAContractRequest{InstrumentID: mockSwap, Leverage: 10, Side: 3})
+			return r.LongLeverage == 10 && r.InstrumentID == mockSwap, err
+		}},
+		{"GetSwapBillDetails", func() (bool, error) {
+			r, err := mock.GetSwapBillDetails(okgroup.GetSpotBillDetailsForCurrencyRequest{Currency: mockSwap, Limit: 1})
+			return len(r) == 1 && r[0].Type == "fee", err
+		}},
+		{"PlaceSwapOrder", func() (bool, error) {
+			r, err := mock.PlaceSwapOrder(okgroup.PlaceSwapOrderRequest{
+				ClientOID:    "swap1",
+				InstrumentID: mockSwap,
+				Type:         1,
+				Price:        3850,
+				Size:         1,
+			})
+			return r.OrderID == "175421362373197825" && r.ClientOID == "swap1" && r.Result, err
+		}},
+		{"PlaceMultipleSwapOrders", func() (bool, error) {
+			r, err := mock.PlaceMultipleSwapOrders(okgroup.PlaceMultipleSwapOrdersRequest{
+				InstrumentID: mockSwap,
+				Leverage:     10,
+				OrdersData: []okgroup.PlaceMultipleSwapOrderData{
+					{ClientOID: "swap2", Type: "1", Price: "3840", Size: "1", MatchPrice: "0"},
+				},
+			})
+			return r.Result && len(r.OrderInfo) == 1 && r.OrderInfo[0].OrderID == "175421362373197826", err
+		}},
+		{"CancelSwapOrder", func() (bool, error) {
+			r, err := mock.CancelSwapOrder(okgroup.CancelSwapOrderRequest{InstrumentID: mockSwap, OrderID: "175421362373197825"})
+			return r.Result, err
+		}},
+		{"CancelMultipleSwapOrders", func() (bool, error) {
+			r, err := mock.CancelMultipleSwapOrders(okgroup.CancelMultipleSwapOrdersRequest{
+				InstrumentID: mockSwap,
+				OrderIDs:     []int64{175421362373197825, 175421362373197826},
+			})
+			return r.Result && len(r.OrderIDS) == 2, err
+		}},
+		{"GetSwapOrderList", func() (bool, error) {
+			r, err := mock.GetSwapOrderList(okgroup.GetSwapOrderListRequest{InstrumentID: mockSwap, Status: 2})
+			return len(r.OrderInfo) == 1 && r.OrderInfo[0].FilledQty == 1, err
+		}},
+		{"GetSwapOrderDetails", func() (bool, error) {
+			r, err := mock.GetSwapOrderDetails(okgroup.GetSwapOrderDetailsRequest{InstrumentID: mockSwap, OrderID: "175421362373197825"})
+			return r.OrderID == 175421362373197825 && r.PriceAvg == 3849.7, err
+		}},
+		{"GetSwapTransactionDetails", func() (bool, error) {
+			r, err := mock.GetSwapTransactionDetails(okgroup.GetSwapTransactionDetailsRequest{InstrumentID: mockSwap, OrderID: "175421362373197825"})
+			return len(r) == 1 && r[0].Price == "3849.7", err
+		}},
+		{"GetSwapContractInformation", func() (bool, error) {
+			r, err := mock.GetSwapContractInformation()
+			return len(r) == 1 && r[0].TickSize == 0.1 && r[0].Coin == "BTC", err
+		}},
+		{"GetSwapOrderBook", func() (bool, error) {
+			r, err := mock.GetSwapOrderBook(okgroup.GetSwapOrderBookRequest{InstrumentID: mockSwap, Size: 2})
+			return len(r.Asks) == 1 && len(r.Bids) == 2, err
+		}},
+		{"GetAllSwapTokensInformation", func() (bool, error) {
+			r, err := mock.GetAllSwapTokensInformation()
+			return len(r) == 1 && r[0].Volume24H == 4181267, err
+		}},
+		{"GetSwapTokensInformationForCurrency", func() (bool, error) {
+			r, err := mock.GetSwapTokensInformationForCurrency(mockSwap)
+			return r.Last == 3874.8, err
+		}},
+		{"GetSwapFilledOrdersData", func() (bool, error) {
+			r, err := mock.GetSwapFilledOrdersData(&okgroup.GetSwapFilledOrdersDataRequest{InstrumentID: mockSwap, Limit: 1})
+			return len(r) == 1 && r[0].Size == 6, err
+		}},
+		{"GetSwapMarketData", func() (bool, error) {
+			r, err := mock.GetSwapMarketData(okgroup.GetSwapMarketDataRequest{InstrumentID: mockSwap, Granularity: 60})
+			return len(r) == 1, err
+		}},
+		{"GetSwapIndices", func() (bool, error) {
+			r, err := mock.GetSwapIndices(mockSwap)
+			return r.Index == 3876.1, err
+		}},
+		{"GetSwapExchangeRates", func() (bool, error) {
+			r, err := mock.GetSwapExchangeRates()
+			return r.Rate == 6.7098, err
+		}},
+		{"GetSwapOpenInterest", func() (bool, error) {
+			r, err := mock.GetSwapOpenInterest(mockSwap)
+			return r.Amount == 1782139, err
+		}},
+		{"GetSwapCurrentPriceLimits", func() (bool, error) {
+			r, err := mock.GetSwapCurrentPriceLimits(mockSwap)
+			return r.Highest == 3991.2 && r.Lowest == 3758.5, err
+		}},
+		{"GetSwapForceLiquidatedOrders", func() (bool, error) {
+			r, err := mock.GetSwapForceLiquidatedOrders(okgroup.GetSwapForceLiquidatedOrdersRequest{InstrumentID: mockSwap, Status: "1"})
+			return len(r) == 1 && r[0].Size == 40, err
+		}},
+		{"GetSwapOnHoldAmountForOpenOrders", func() (bool, error) {
+			r, err := mock.GetSwapOnHoldAmountForOpenOrders(mockSwap)
+			return r.Amount == 0.0013, err
+		}},
+		{"GetSwapNextSettlementTime", func() (bool, error) {
+			r, err := mock.GetSwapNextSettlementTime(mockSwap)
+			return r.FundingRate == "0.00011" && r.EstimatedRate == "0.00009", err
+		}},
+		{"GetSwapMarkPrice", func() (bool, error) {
+			r, err := mock.GetSwapMarkPrice(mockSwap)
+			return r.MarkPrice == "3875.26" && r.Timestamp != "", err
+		}},
+		{"GetSwapFundingRateHistory", func() (bool, error) {
+			r, err := mock.GetSwapFundingRateHistory(okgroup.GetSwapFundingRateHistoryRequest{InstrumentID: mockSwap, Limit: 1})
+			return len(r) == 1 && r[0].RealizedRate == 0.00010941, err
+		}},
+
+		// ETT
+		{"GetETT", func() (bool, error) {
+			r, err := mock.GetETT()
+			return len(r) == 1 && r[0].Available == 120.5, err
+		}},
+		{"GetETTAccountInformationForCurrency", func() (bool, error) {
+			r, err := mock.GetETTAccountInformationForCurrency("usdt")
+			return r.Balance == 120.5, err
+		}},
+		{"GetETTBillsDetails", func() (bool, error) {
+			r, err := mock.GetETTBillsDetails("usdt")
+			return len(r) == 1 && r[0].Details == 888, err
+		}},
+		{"PlaceETTOrder", func() (bool, error) {
+			r, err := mock.PlaceETTOrder(&okgroup.PlaceETTOrderRequest{
+				ClientOID:     "ett1",
+				Type:          1,
+				QuoteCurrency: "usdt",
+				Amount:        20,
+				ETT:           mockETT,
+			})
+			return r.OrderID == "888" && r.Result, err
+		}},
+		{"CancelETTOrder", func() (bool, error) {
+			r, err := mock.CancelETTOrder("888")
+			return r.OrderID == "888" && r.Result, err
+		}},
+		{"GetETTOrderList", func() (bool, error) {
+			r, err := mock.GetETTOrderList(okgroup.GetETTOrderListRequest{ETT: mockETT, Type: 1})
+			return len(r) == 1 && r[0].Ett == mockETT, err
+		}},
+		{"GetETTOrderDetails", func() (bool, error) {
+			r, err := mock.GetETTOrderDetails("888")
+			return r.Status == "2", err
+		}},
+		{"GetETTConstituents", func() (bool, error) {
+			r, err := mock.GetETTConstituents(mockETT)
+			return r.NetValue == 0.98 && len(r.Constituents) == 2, err
+		}},
+		{"GetETTSettlementPriceHistory", func() (bool, error) {
+			r, err := mock.GetETTSettlementPriceHistory(mockETT)
+			return len(r) == 1 && r[0].Price == 0.97, err
+		}},
+	}
+
+	for i := range tests {
+		ok, err := tests[i].call()
+		if err != nil {
+			t.Errorf("Test Failed - %s() error: %v", tests[i].name, err)
+			continue
+		}
+		if !ok {
+			t.Errorf("Test Failed - %s() response not parsed", tests[i].name)
+		}
+	}
+
+	if keys := server.unserved(); len(keys) > 0 {
+		t.Errorf("Test Failed - fixtures never requested %v", keys)
+	}
+}
+
+func TestMockedUnsupportedEndpoints(t *testing.T) {
+	mock, _, closeServer := newMockOKEX(t)
+	defer closeServer()
+
+	if _, err := mock.GetFuturesTagPrice(mockFutures); err != common.ErrNotYetImplemented {
+		t.Error("Test Failed - GetFuturesTagPrice() expected not yet implemented error", err)
+	}
+	btc := currency.NewPairWithDelimiter("BTC", "USDT", "-")
+	if _, err := mock.GetHistoricCandles(btc, "SPOT", kline.EightHour, 10); err != kline.ErrUnsupportedInterval {
+		t.Error("Test Failed - GetHistoricCandles() expected unsupported interval error", err)
+	}
+
+	mock.AuthenticatedAPISupport = false
+	if _, err := mock.GetFuturesPostions(); err == nil {
+		t.Error("Test Failed - GetFuturesPostions() expected authenticated request without credentials error")
+	}
+}
+
+func TestMockedEndpointVersion(t *testing.T) {
+	var requested []string
+	var mtx sync.Mutex
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		requested = append(requested, fixtureKey(r.Method, r.URL.RequestURI()))
+		mtx.Unlock()
+		if r.URL.Path == "/"+okExAPIPath+"margin/v3/fills" {
+			if r.Header.Get("OK-ACCESS-SIGN") == "" {
+				t.Error("Test Failed - GetMarginTransactionDetails() request not signed")
+			}
+			w.Write([]byte("[]"))
+			return
+		}
+		w.Write([]byte("{}"))
+	}))
+	defer s.Close()
+
+	var mock OKEX
+	mock.SetDefaults()
+	mock.APIUrl = s.URL + "/" + okExAPIPath
+	mock.AuthenticatedAPISupport = true
+	mock.APIKey = mockKey
+	mock.APISecret = mockSecret
+	mock.ClientID = mockPassphrase
+	var err error
+	mock.Endpoints, err = okgroup.NewEndpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = mock.Endpoints.Register(endpoints.Version{
+		Name:       "v5",
+		SignPrefix: "/" + okExAPIPath,
+		Endpoints: map[endpoints.Operation]endpoints.Endpoint{
+			endpoints.Ticker: {Method: http.MethodGet, Path: "v5/market/ticker/{instrument}"},
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = mock.GetSpotAllTokenPairsInformationForCurrency("BTC-USDT"); err != nil {
+		t.Error("Test Failed - GetSpotAllTokenPairsInformationForCurrency() error", err)
+	}
+	_, err = mock.GetMarginTransactionDetails(okgroup.GetSpotTransactionDetailsRequest{InstrumentID: "BTC-USDT", OrderID: 1})
+	if err != nil {
+		t.Error("Test Failed - GetMarginTransactionDetails() error", err)
+	}
+	if err = mock.Endpoints.Use("v5"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = mock.GetSpotAllTokenPairsInformationForCurrency("BTC-USDT"); err != nil {
+		t.Error("Test Failed - GetSpotAllTokenPairsInformationForCurrency() error", err)
+	}
+	_, err = mock.GetMarginTransactionDetails(okgroup.GetSpotTransactionDetailsRequest{InstrumentID: "BTC-USDT", OrderID: 1})
+	if err == nil || !strings.Contains(err.Error(), endpoints.ErrOperationUnsupported.Error()) {
+		t.Error("Test Failed - GetMarginTransactionDetails() expected operation unsupported by v5", err)
+	}
+
+	expected := []string{
+		"GET /api/spot/v3/instruments/BTC-USDT/ticker",
+		"GET /api/margin/v3/fills?instrument_id=BTC-USDT&order_id=1",
+		"GET /api/v5/market/ticker/BTC-USDT",
+	}
+	mtx.Lock()
+	defer mtx.Unlock()
+	if !reflect.DeepEqual(requested, expected) {
+		t.Errorf("Test Failed - requested %v, expected %v", requested, expected)
+	}
+}
@@ -13,6 +13,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/endpoints"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/okgroup"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/sharedtestvalues"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
@@ -623,6 +624,118 @@ func TestGetMarginAccountSettingsForCurrency(t *testing.T) {
 	testStandardErrorHandling(t, err)
 }
 
+// TestGetBorrowRates API endpoint test
+func TestGetBorrowRates(t *testing.T) {
+	TestSetDefaults(t)
+	t.Parallel()
+	_, err := o.GetBorrowRates(currency.BTC)
+	testStandardErrorHandling(t, err)
+}
+
+// TestGetMarginLoanHistory API endpoint test
+func TestGetMarginLoanHistory(t *testing.T) {
+	TestSetDefaults(t)
+	t.Parallel()
+	request := okgroup.GetMarginLoanHistoryRequest{
+		InstrumentID: spotCurrency,
+		Limit:        10,
+	}
+
+	_, err := o.GetMarginLoanHistory(request)
+	testStandardErrorHandling(t, err)
+}
+
+// TestGetOutstandingMarginLoans wrapper test
+func TestGetOutstandingMarginLoans(t *testing.T) {
+	TestSetDefaults(t)
+	t.Parallel()
+	_, err := o.GetOutstandingMarginLoans(currency.NewPair(currency.BTC, currency.USDT))
+	testStandardErrorHandling(t, err)
+}
+
+// TestGetMarginLeverage API endpoint test
+func TestGetMarginLeverage(t *testing.T) {
+	TestSetDefaults(t)
+	t.Parallel()
+	_, err := o.GetMarginLeverage(spotCurrency)
+	testStandardErrorHandling(t, err)
+}
+
+// TestSetMarginLeverage API endpoint test
+func TestSetMarginLeverage(t *testing.T) {
+	TestSetRealOrderDefaults(t)
+	t.Parallel()
+	_, err := o.SetMarginLeverage(okgroup.SetMarginLeverageRequest{
+		InstrumentID: spotCurrency,
+		Leverage:     2,
+	})
+	testStandardErrorHandling(t, err)
+}
+
+// TestGetMarginMarkPrice API endpoint test
+func TestGetMarginMarkPrice(t *testing.T) {
+	TestSetDefaults(t)
+	t.Parallel()
+	_, err := o.GetMarginMarkPrice(spotCurrency)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+// TestGetMarginAccountInfo wrapper test
+func TestGetMarginAccountInfo(t *testing.T) {
+	TestSetDefaults(t)
+	t.Parallel()
+	_, err := o.GetMarginAccountInfo()
+	testStandardErrorHandling(t, err)
+}
+
+// TestMarginAccountsUnmarshal logic test
+func TestMarginAccountsUnmarshal(t *testing.T) {
+	t.Parallel()
+	data := []byte(`[{"instrument_id":"BTC-USDT","liquidation_price":"0","product_id":"BTC-USDT","risk_rate":"","currency:BTC":{"available":"0.5","balance":"1","borrowed":"0.2","frozen":"0","hold":"0.5","holds":"0","lending_fee":"0.0001"},"currency:USDT":{"available":"100","balance":"100","borrowed":"0","frozen":"0","hold":"0","holds":"0","lending_fee":"0"}}]`)
+
+	var resp []okgroup.GetMarginAccountsResponse
+	err := common.JSONDecode(data, &resp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(resp) != 1 || resp[0].InstrumentID != "BTC-USDT" {
+		t.Fatalf("Test Failed - unexpected margin account response %+v", resp)
+	}
+
+	btc, ok := resp[0].Currencies["BTC"]
+	if !ok || btc.Balance != 1 || btc.Borrowed != 0.2 {
+		t.Errorf("Test Failed - unexpected BTC margin balance %+v", btc)
+	}
+
+	if len(resp[0].Currencies) != 2 {
+		t.Errorf("Test Failed - expected 2 margin currencies, received %v", len(resp[0].Currencies))
+	}
+}
+
+// TestSubmitMarginOrder wrapper test
+func TestSubmitMarginOrder(t *testing.T) {
+	TestSetRealOrderDefaults(t)
+	t.Parallel()
+	_, err := o.SubmitMarginOrder(currency.NewPair(currency.BTC, currency.USDT),
+		exchange.BuyOrderSide, exchange.LimitOrderType, 0.001, 100, "")
+	testStandardErrorHandling(t, err)
+}
+
+// TestBorrowAndRepayMargin wrapper test
+func TestBorrowAndRepayMargin(t *testing.T) {
+	TestSetRealOrderDefaults(t)
+	t.Parallel()
+	p := currency.NewPair(currency.BTC, currency.USDT)
+	id, err := o.BorrowMargin(p, currency.USDT, 10)
+	testStandardErrorHandling(t, err)
+
+	_, err = o.RepayMargin(p, currency.USDT, 10, id)
+	testStandardErrorHandling(t, err)
+}
+
 // TestOpenMarginLoan API endpoint test
 func TestOpenMarginLoan(t *testing.T) {
 	TestSetRealOrderDefaults(t)
@@ -1837,6 +1950,30 @@ func TestSubmitOrder(t *testing.T) {
 	}
 }
 
+// TestSubmitBatchOrders Wrapper test
+func TestSubmitBatchOrders(t *testing.T) {
+	TestSetRealOrderDefaults(t)
+	t.Parallel()
+	p := currency.NewPairWithDelimiter(currency.BTC.String(), currency.USDT.String(), "-")
+	orders := []exchange.OrderSubmission{
+		{Pair: p, Side: exchange.BuyOrderSide, OrderType: exchange.LimitOrderType, Amount: 1, Price: 10},
+		{Pair: p, Side: exchange.BuyOrderSide, OrderType: exchange.LimitOrderType, Amount: 1, Price: 11},
+	}
+	results := exchange.SubmitOrders(&o, orders)
+	for i := range results {
+		if areTestAPIKeysSet() && (results[i].Err != nil || !results[i].IsOrderPlaced) {
+			t.Errorf("Order failed to be placed: %v", results[i].Err)
+		} else if !areTestAPIKeysSet() && results[i].Err == nil {
+			t.Error("Expecting an error when no keys are set")
+		}
+	}
+
+	_, err := o.SubmitBatchOrders(make([]exchange.OrderSubmission, o.MaxBatchOrders()+1))
+	if err == nil {
+		t.Error("Expecting an error when exceeding the batch size")
+	}
+}
+
 // TestCancelExchangeOrder Wrapper test
 func TestCancelExchangeOrder(t *testing.T) {
 	TestSetRealOrderDefaults(t)
@@ -1929,3 +2066,82 @@ func TestWithdrawInternationalBank(t *testing.T) {
 		t.Errorf("Expected '%v', received: '%v'", common.ErrFunctionNotSupported, err)
 	}
 }
+
+func TestEndpoints(t *testing.T) {
+	t.Parallel()
+	r, err := okgroup.NewEndpoints()
+	if err != nil {
+		t.Fatal(err)
+	}
+	route, err := r.Resolve(endpoints.Ticker, map[string]string{"instrument": spotCurrency})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if route.Path != "spot/v3/instruments/"+spotCurrency+"/ticker" ||
+		route.SignPath != "/api/spot/v3/instruments/"+spotCurrency+"/ticker" || route.Authenticated {
+		t.Errorf("Test Failed - NewEndpoints() unexpected ticker route %+v", route)
+	}
+	if route, err = r.Resolve(endpoints.Fills, nil); err != nil || !route.Authenticated {
+		t.Errorf("Test Failed - NewEndpoints() expected authenticated fills route %+v %v", route, err)
+	}
+}
+
+// TestPlaceSpotAlgoOrder API endpoint test
+func TestPlaceSpotAlgoOrder(t *testing.T) {
+	TestSetRealOrderDefaults(t)
+	t.Parallel()
+	request := okgroup.PlaceSpotAlgoOrderRequest{
+		InstrumentID: spotCurrency,
+		Mode:         1,
+		OrderType:    okgroup.AlgoTrigger,
+		Size:         1,
+		Side:         "sell",
+		AlgoOrderParameters: okgroup.AlgoOrderParameters{
+			TriggerPrice: 100,
+			AlgoType:     okgroup.AlgoMarket,
+		},
+	}
+
+	_, err := o.PlaceSpotAlgoOrder(&request)
+	testStandardErrorHandling(t, err)
+}
+
+// TestCancelSwapAlgoOrders API endpoint test
+func TestCancelSwapAlgoOrders(t *testing.T) {
+	TestSetDefaults(t)
+	t.Parallel()
+	request := okgroup.CancelAlgoOrdersRequest{
+		InstrumentID: "BTC-USD-SWAP",
+		AlgoIDs:      make([]string, okgroup.MaxAlgoCancellations+1),
+		OrderType:    okgroup.AlgoTrigger,
+	}
+	if _, err := o.CancelSwapAlgoOrders(request); err == nil {
+		t.Error("Test Failed - CancelSwapAlgoOrders() expected maximum cancellations error")
+	}
+}
+
+func TestAlgoOrderParametersValidate(t *testing.T) {
+	t.Parallel()
+	var tests = []struct {
+		orderType int64
+		params    okgroup.AlgoOrderParameters
+		valid     bool
+	}{
+		{okgroup.AlgoTrigger, okgroup.AlgoOrderParameters{TriggerPrice: 100, AlgoType: okgroup.AlgoMarket}, true},
+		{okgroup.AlgoTrigger, okgroup.AlgoOrderParameters{TriggerPrice: 100, AlgoType: okgroup.AlgoLimit}, false},
+		{okgroup.AlgoTrail, okgroup.AlgoOrderParameters{TriggerPrice: 100, CallbackRate: 0.01}, true},
+		{okgroup.AlgoTrail, okgroup.AlgoOrderParameters{TriggerPrice: 100, CallbackRate: 0.1}, false},
+		{okgroup.AlgoIceberg, okgroup.AlgoOrderParameters{AvgAmount: 1, PriceLimit: 100, AlgoVariance: 0.001}, true},
+		{okgroup.AlgoIceberg, okgroup.AlgoOrderParameters{AvgAmount: 1, PriceLimit: 100}, false},
+		{okgroup.AlgoTWAP, okgroup.AlgoOrderParameters{SweepRange: 0.01, SweepRatio: 0.1, SingleLimit: 1, PriceLimit: 100, TimeInterval: 5}, true},
+		{okgroup.AlgoTWAP, okgroup.AlgoOrderParameters{SweepRange: 0.01, SweepRatio: 0.1, SingleLimit: 1, PriceLimit: 100, TimeInterval: 1}, false},
+		{5, okgroup.AlgoOrderParameters{TriggerPrice: 100}, false},
+	}
+	for i := range tests {
+		err := tests[i].params.Validate(tests[i].orderType)
+		if (err == nil) != tests[i].valid {
+			t.Errorf("Test Failed - Validate() order type %d %+v unexpected result %v",
+				tests[i].orderType, tests[i].params, err)
+		}
+	}
+}
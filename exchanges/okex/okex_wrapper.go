@@ -0,0 +1,237 @@
+package okex
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okgroup"
+)
+
+const (
+	// marginOrder is the margin_trading value used for leveraged orders
+	marginOrder = "2"
+	// loanOutstanding is the loan history status for loans yet to be repaid
+	loanOutstanding int64 = 0
+	// spotAlgoMode is the algo order mode of the spot account
+	spotAlgoMode = 1
+)
+
+// SubmitStopOrder holds a stop market order on the exchange as a spot trigger
+// algo order and returns its algo ID
+func (o *OKEX) SubmitStopOrder(p currency.Pair, side exchange.OrderSide, amount, triggerPrice float64) (string, error) {
+	resp, err := o.PlaceSpotAlgoOrder(&okgroup.PlaceSpotAlgoOrderRequest{
+		InstrumentID: exchange.FormatExchangeCurrency(o.Name, p).String(),
+		Mode:         spotAlgoMode,
+		OrderType:    okgroup.AlgoTrigger,
+		Size:         amount,
+		Side:         strings.ToLower(side.ToString()),
+		AlgoOrderParameters: okgroup.AlgoOrderParameters{
+			TriggerPrice: triggerPrice,
+			AlgoType:     okgroup.AlgoMarket,
+		},
+	})
+	return resp.AlgoID, err
+}
+
+// GetStopOrder returns the state of a stop order placed by SubmitStopOrder
+func (o *OKEX) GetStopOrder(p currency.Pair, id string) (exchange.StopOrder, error) {
+	orders, err := o.GetSpotAlgoOrders(okgroup.GetAlgoOrdersRequest{
+		InstrumentID: exchange.FormatExchangeCurrency(o.Name, p).String(),
+		OrderType:    okgroup.AlgoTrigger,
+		AlgoIDs:      id,
+	})
+	if err != nil {
+		return exchange.StopOrder{}, err
+	}
+	for i := range orders {
+		if orders[i].AlgoID == id {
+			return stopOrder(&orders[i]), nil
+		}
+	}
+	return exchange.StopOrder{}, errors.New("stop order " + id + " not found")
+}
+
+// CancelStopOrder cancels a stop order placed by SubmitStopOrder
+func (o *OKEX) CancelStopOrder(p currency.Pair, id string) error {
+	_, err := o.CancelSpotAlgoOrders(okgroup.CancelAlgoOrdersRequest{
+		InstrumentID: exchange.FormatExchangeCurrency(o.Name, p).String(),
+		AlgoIDs:      []string{id},
+		OrderType:    okgroup.AlgoTrigger,
+	})
+	return err
+}
+
+// stopOrder converts a trigger algo order
+func stopOrder(a *okgroup.AlgoOrder) exchange.StopOrder {
+	return exchange.StopOrder{
+		ID:        a.AlgoID,
+		Triggered: a.Status == okgroup.AlgoEffective || a.Status == okgroup.AlgoPartiallyEffective,
+		Cancelled: a.Status == okgroup.AlgoCancelled || a.Status == okgroup.AlgoFailed,
+		OrderID:   a.OrderID,
+	}
+}
+
+// SubmitMarginOrder submits a new leveraged order against the margin account
+// for the supplied pair
+func (o *OKEX) SubmitMarginOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (resp exchange.SubmitOrderResponse, err error) {
+	request := okgroup.PlaceSpotOrderRequest{
+		ClientOID:     clientID,
+		InstrumentID:  exchange.FormatExchangeCurrency(o.Name, p).String(),
+		Side:          strings.ToLower(side.ToString()),
+		Type:          strings.ToLower(orderType.ToString()),
+		MarginTrading: marginOrder,
+		Size:          strconv.FormatFloat(amount, 'f', -1, 64),
+	}
+	if orderType == exchange.LimitOrderType {
+		request.Price = strconv.FormatFloat(price, 'f', -1, 64)
+	}
+
+	orderResponse, err := o.PlaceMarginOrder(&request)
+	if err != nil {
+		return
+	}
+
+	resp.IsOrderPlaced = orderResponse.Result
+	resp.OrderID = orderResponse.OrderID
+	return
+}
+
+// CancelMarginOrderByID cancels a leveraged order by its ID
+func (o *OKEX) CancelMarginOrderByID(p currency.Pair, orderID string) error {
+	id, err := strconv.ParseInt(orderID, 10, 64)
+	if err != nil {
+		return err
+	}
+
+	resp, err := o.CancelMarginOrder(okgroup.CancelSpotOrderRequest{
+		InstrumentID: exchange.FormatExchangeCurrency(o.Name, p).String(),
+		OrderID:      id,
+	})
+	if err != nil {
+		return err
+	}
+
+	if !resp.Result {
+		return errors.New("failed to cancel margin order " + orderID)
+	}
+	return nil
+}
+
+// BorrowMargin borrows the supplied currency into the margin account of a pair
+// and returns the borrow ID
+func (o *OKEX) BorrowMargin(p currency.Pair, c currency.Code, amount float64) (int64, error) {
+	resp, err := o.OpenMarginLoan(okgroup.OpenMarginLoanRequest{
+		InstrumentID:  exchange.FormatExchangeCurrency(o.Name, p).String(),
+		QuoteCurrency: c.Lower().String(),
+		Amount:        amount,
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if !resp.Result {
+		return 0, errors.New("margin borrow request was not accepted")
+	}
+	return resp.BorrowID, nil
+}
+
+// RepayMargin repays borrowed currency in the margin account of a pair. If
+// borrowID is zero, all loans for the currency under the pair are repaid.
+func (o *OKEX) RepayMargin(p currency.Pair, c currency.Code, amount float64, borrowID int64) (int64, error) {
+	resp, err := o.RepayMarginLoan(okgroup.RepayMarginLoanRequest{
+		InstrumentID:  exchange.FormatExchangeCurrency(o.Name, p).String(),
+		QuoteCurrency: c.Lower().String(),
+		Amount:        amount,
+		BorrowID:      float64(borrowID),
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	if !resp.Result {
+		return 0, errors.New("margin repayment request was not accepted")
+	}
+	return resp.RepaymentID, nil
+}
+
+// GetOutstandingMarginLoans returns all outstanding loans, optionally filtered
+// by pair
+func (o *OKEX) GetOutstandingMarginLoans(p currency.Pair) ([]okgroup.GetMarginLoanHistoryResponse, error) {
+	request := okgroup.GetMarginLoanHistoryRequest{
+		Status: loanOutstanding,
+	}
+	if !p.IsEmpty() {
+		request.InstrumentID = exchange.FormatExchangeCurrency(o.Name, p).String()
+	}
+	return o.GetMarginLoanHistory(request)
+}
+
+// GetMarginAccountInfo retrieves balances held in all margin accounts. Each
+// margin pair is returned as a separate account with the instrument ID as its
+// account ID and the borrowed amount included in the amount on hold.
+func (o *OKEX) GetMarginAccountInfo() (resp exchange.AccountInfo, err error) {
+	resp.Exchange = o.Name
+	accounts, err := o.GetMarginTradingAccounts()
+	if err != nil {
+		return
+	}
+
+	for i := range accounts {
+		account := exchange.Account{ID: accounts[i].InstrumentID}
+		for c, info := range accounts[i].Currencies {
+			account.Currencies = append(account.Currencies, exchange.AccountCurrencyInfo{
+				CurrencyName: currency.NewCode(c),
+				TotalValue:   info.Balance,
+				Hold:         info.Hold + info.Borrowed,
+			})
+		}
+		resp.Accounts = append(resp.Accounts, account)
+	}
+	return
+}
+
+// GetSettlementHistory returns the settlements and deliveries of the futures
+// contracts on the underlying pair since start, up to the 100 most recent
+func (o *OKEX) GetSettlementHistory(p currency.Pair, start time.Time) ([]exchange.Settlement, error) {
+	req := okgroup.GetFuturesSettlementHistoryRequest{
+		Underlying: p.Base.Upper().String() + "-" + p.Quote.Upper().String(),
+		Limit:      100,
+	}
+	if !start.IsZero() {
+		req.Start = start.UTC().Format(time.RFC3339)
+	}
+	resp, err := o.GetFuturesSettlementHistory(req)
+	if err != nil {
+		return nil, err
+	}
+
+	settlements := make([]exchange.Settlement, 0, len(resp))
+	for i := len(resp) - 1; i >= 0; i-- {
+		if resp[i].Timestamp.Before(start) {
+			continue
+		}
+		s := exchange.Settlement{
+			Exchange: o.Name,
+			Pair:     p,
+			Contract: resp[i].InstrumentID,
+			Price:    resp[i].Price,
+			Time:     resp[i].Timestamp,
+		}
+		switch resp[i].Type {
+		case okgroup.FuturesSettlement:
+			s.Type = exchange.Settled
+		case okgroup.FuturesDelivery:
+			s.Type = exchange.Delivered
+		default:
+			return nil, fmt.Errorf("%s unknown settlement type %q for %s",
+				o.Name, resp[i].Type, resp[i].InstrumentID)
+		}
+		settlements = append(settlements, s)
+	}
+	return settlements, nil
+}
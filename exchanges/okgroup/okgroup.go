@@ -16,6 +16,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/config"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/endpoints"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
 	log "github.com/thrasher-corp/gocryptotrader/logger"
 )
@@ -23,12 +24,17 @@ import (
 const (
 	okGroupAuthRate   = 0
 	okGroupUnauthRate = 0
+	// okGroupMaxBatchOrders is the number of orders sent per batch request
+	okGroupMaxBatchOrders = 4
+	// MaxAlgoCancellations is the number of algo orders cancelled per request
+	MaxAlgoCancellations = 10
 	// OKGroupAPIPath const to help with api url formatting
 	OKGroupAPIPath = "api/"
 	// API subsections
-	okGroupAccountSubsection       = "account"
-	okGroupTokenSubsection         = "spot"
-	okGroupMarginTradingSubsection = "margin"
+	okGroupAccountSubsection = "account"
+	okGroupTokenSubsection   = "spot"
+	// OKGroupMarginTradingSubsection margin trading api subsection
+	OKGroupMarginTradingSubsection = "margin"
 	// OKGroupAccounts common api endpoint
 	OKGroupAccounts = "accounts"
 	// OKGroupLedger common api endpoint
@@ -53,6 +59,15 @@ const (
 	OKGroupInstruments = "instruments"
 	// OKGroupLiquidation common api endpoint
 	OKGroupLiquidation = "liquidation"
+	// OKGroupAlgoOrder common api endpoint
+	OKGroupAlgoOrder = "order_algo"
+	// Spot algo endpoints
+	okGroupCancelAlgoOrders = "cancel_batch_algos"
+	okGroupAlgoOrders       = "algo"
+	// Futures and swap order endpoints, classified by mutating
+	okGroupFuturesOrder      = "order"
+	okGroupFuturesCancelAlgo = "cancel_algos"
+	okGroupFuturesLeverage   = "leverage"
 	// OKGroupMarkPrice common api endpoint
 	OKGroupMarkPrice = "mark_price"
 	// OKGroupGetAccountDepositHistory common api endpoint
@@ -78,9 +93,21 @@ const (
 	okGroupGetLoanHistory        = "borrowed"
 	okGroupGetLoan               = "borrow"
 	okGroupGetRepayment          = "repayment"
+	marginCurrencyPrefix         = "currency:"
 )
 
-var errMissValue = errors.New("warning - resp value is missing from exchange")
+var (
+	// ErrAlgoNotAcknowledged is returned when a placed algo order has no ID
+	ErrAlgoNotAcknowledged = errors.New("algo order not acknowledged")
+
+	errMissValue = errors.New("warning - resp value is missing from exchange")
+
+	errAlgoOrderType = errors.New("unknown algo order type")
+	errAlgoTrigger   = errors.New("trigger orders require a trigger price and an algo price unless placed at market")
+	errAlgoTrail     = errors.New("trail orders require a trigger price and a callback rate from 0.001 to 0.05")
+	errAlgoIceberg   = errors.New("iceberg orders require an average amount, a price limit and a variance from 0.0001 to 0.01")
+	errAlgoTWAP      = errors.New("TWAP orders require a sweep range, sweep ratio, single limit, price limit and a time interval from 5 to 120 seconds")
+)
 
 // OKGroup is the overaching type across the all of OKEx's exchange methods
 type OKGroup struct {
@@ -135,6 +162,14 @@ func (o *OKGroup) Setup(exch *config.ExchangeConfig) {
 		if err != nil {
 			log.Fatal(err)
 		}
+		o.Endpoints, err = NewEndpoints()
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = o.SetAPIVersion(exch)
+		if err != nil {
+			log.Fatal(err)
+		}
 		err = o.SetClientProxyAddress(exch.ProxyAddress)
 		if err != nil {
 			log.Fatal(err)
@@ -187,7 +222,7 @@ func (o *OKGroup) TransferAccountFunds(request TransferAccountFundsRequest) (res
 
 // AccountWithdraw withdrawal of tokens to OKCoin International, other OKEx accounts or other addresses.
 func (o *OKGroup) AccountWithdraw(request AccountWithdrawRequest) (resp AccountWithdrawResponse, _ error) {
-	return resp, o.SendHTTPRequest(http.MethodPost, okGroupAccountSubsection, okGroupWithdraw, request, &resp, true)
+	return resp, o.SendEndpointRequest(endpoints.Withdraw, nil, "", request, &resp)
 }
 
 // GetAccountWithdrawalFee retrieves the information about the recommended network transaction fee for withdrawals to digital asset addresses. The higher the fees are, the sooner the confirmations you will get.
@@ -225,8 +260,7 @@ func (o *OKGroup) GetAccountBillDetails(request GetAccountBillDetailsRequest) (r
 func (o *OKGroup) GetAccountDepositAddressForCurrency(currency string) (resp []GetDepositAddressResponse, _ error) {
 	urlValues := url.Values{}
 	urlValues.Set("currency", currency)
-	requestURL := fmt.Sprintf("%v?%v", okGroupGetDepositAddress, urlValues.Encode())
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupAccountSubsection, requestURL, nil, &resp, true)
+	return resp, o.SendEndpointRequest(endpoints.DepositAddress, nil, "?"+urlValues.Encode(), nil, &resp)
 }
 
 // GetAccountDepositHistory retrieves the deposit history of all tokens.100 recent records will be returned at maximum
@@ -242,13 +276,12 @@ func (o *OKGroup) GetAccountDepositHistory(currency string) (resp []GetAccountDe
 
 // GetSpotTradingAccounts retrieves the list of assets(only show pairs with balance larger than 0), the balances, amount available/on hold in spot accounts.
 func (o *OKGroup) GetSpotTradingAccounts() (resp []GetSpotTradingAccountResponse, _ error) {
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, OKGroupAccounts, nil, &resp, true)
+	return resp, o.SendEndpointRequest(endpoints.Accounts, nil, "", nil, &resp)
 }
 
 // GetSpotTradingAccountForCurrency This endpoint supports getting the balance, amount available/on hold of a token in spot account.
 func (o *OKGroup) GetSpotTradingAccountForCurrency(currency string) (resp GetSpotTradingAccountResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v", OKGroupAccounts, currency)
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, requestURL, nil, &resp, true)
+	return resp, o.SendEndpointRequest(endpoints.Account, map[string]string{"currency": currency}, "", nil, &resp)
 }
 
 // GetSpotBillDetailsForCurrency This endpoint supports getting the balance, amount available/on hold of a token in spot account.
@@ -261,7 +294,7 @@ func (o *OKGroup) GetSpotBillDetailsForCurrency(request GetSpotBillDetailsForCur
 // You can place an order only if you have enough funds.
 // Once your order is placed, the amount will be put on hold.
 func (o *OKGroup) PlaceSpotOrder(request *PlaceSpotOrderRequest) (resp PlaceSpotOrderResponse, _ error) {
-	return resp, o.SendHTTPRequest(http.MethodPost, okGroupTokenSubsection, OKGroupOrders, request, &resp, true)
+	return resp, o.SendEndpointRequest(endpoints.PlaceOrder, nil, "", request, &resp)
 }
 
 // PlaceMultipleSpotOrders supports placing multiple orders for specific trading pairs
@@ -300,10 +333,65 @@ func (o *OKGroup) PlaceMultipleSpotOrders(request []PlaceSpotOrderRequest) (map[
 	return resp, orderErrors
 }
 
+// PlaceSpotAlgoOrder places a trigger, trail, iceberg or TWAP order held
+// exchange side until its conditions are met
+func (o *OKGroup) PlaceSpotAlgoOrder(request *PlaceSpotAlgoOrderRequest) (resp AlgoOrderResponse, err error) {
+	err = request.AlgoOrderParameters.Validate(request.OrderType)
+	if err != nil {
+		return
+	}
+	err = o.SendEndpointRequest(placeAlgoOrder, nil, "", request, &resp)
+	if err == nil && resp.AlgoID == "" {
+		err = ErrAlgoNotAcknowledged
+	}
+	return
+}
+
+// CancelSpotAlgoOrders cancels up to 10 algo orders of one order type
+func (o *OKGroup) CancelSpotAlgoOrders(request CancelAlgoOrdersRequest) (resp AlgoOrderResponse, _ error) {
+	if len(request.AlgoIDs) > MaxAlgoCancellations {
+		return resp, fmt.Errorf("maximum %d algo order cancellations", MaxAlgoCancellations)
+	}
+	return resp, o.SendEndpointRequest(cancelAlgoOrders, nil, "", request, &resp)
+}
+
+// GetSpotAlgoOrders returns algo orders of one order type, either those with
+// the status or those with the IDs
+func (o *OKGroup) GetSpotAlgoOrders(request GetAlgoOrdersRequest) ([]AlgoOrder, error) {
+	var resp AlgoOrderList
+	return resp.Orders, o.SendEndpointRequest(algoOrders, nil, FormatParameters(request), nil, &resp)
+}
+
+// Validate checks the parameters required by the algo order type are set and
+// within the ranges OKEX accepts
+func (a *AlgoOrderParameters) Validate(orderType int64) error {
+	switch orderType {
+	case AlgoTrigger:
+		if a.TriggerPrice <= 0 || a.AlgoPrice <= 0 && a.AlgoType != AlgoMarket {
+			return errAlgoTrigger
+		}
+	case AlgoTrail:
+		if a.TriggerPrice <= 0 || a.CallbackRate < 0.001 || a.CallbackRate > 0.05 {
+			return errAlgoTrail
+		}
+	case AlgoIceberg:
+		if a.AvgAmount <= 0 || a.PriceLimit <= 0 || a.AlgoVariance < 0.0001 || a.AlgoVariance > 0.01 {
+			return errAlgoIceberg
+		}
+	case AlgoTWAP:
+		if a.SweepRange <= 0 || a.SweepRatio <= 0 || a.SingleLimit <= 0 || a.PriceLimit <= 0 ||
+			a.TimeInterval < 5 || a.TimeInterval > 120 {
+			return errAlgoTWAP
+		}
+	default:
+		return fmt.Errorf("%v %d", errAlgoOrderType, orderType)
+	}
+	return nil
+}
+
 // CancelSpotOrder Cancelling an unfilled order.
 func (o *OKGroup) CancelSpotOrder(request CancelSpotOrderRequest) (resp CancelSpotOrderResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v", OKGroupCancelOrders, request.OrderID)
-	return resp, o.SendHTTPRequest(http.MethodPost, okGroupTokenSubsection, requestURL, request, &resp, true)
+	return resp, o.SendEndpointRequest(endpoints.CancelOrder, map[string]string{"order": strconv.FormatInt(request.OrderID, 10)}, "", request, &resp)
 }
 
 // CancelMultipleSpotOrders Cancelling multiple unfilled orders.
@@ -340,128 +428,175 @@ func (o *OKGroup) CancelMultipleSpotOrders(request CancelMultipleSpotOrdersReque
 // GetSpotOrders List your orders. Cursor pagination is used.
 // All paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKGroup) GetSpotOrders(request GetSpotOrdersRequest) (resp []GetSpotOrderResponse, _ error) {
-	requestURL := fmt.Sprintf("%v%v", OKGroupOrders, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, requestURL, nil, &resp, true)
+	return resp, o.SendEndpointRequest(endpoints.Orders, nil, FormatParameters(request), nil, &resp)
 }
 
 // GetSpotOpenOrders List all your current open orders. Cursor pagination is used.
 // All paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKGroup) GetSpotOpenOrders(request GetSpotOpenOrdersRequest) (resp []GetSpotOrderResponse, _ error) {
-	requestURL := fmt.Sprintf("%v%v", OKGroupPendingOrders, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, requestURL, nil, &resp, true)
+	return resp, o.SendEndpointRequest(endpoints.OpenOrders, nil, FormatParameters(request), nil, &resp)
 }
 
 // GetSpotOrder Get order details by order ID.
 func (o *OKGroup) GetSpotOrder(request GetSpotOrderRequest) (resp GetSpotOrderResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v%v", OKGroupOrders, request.OrderID, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, requestURL, request, &resp, true)
+	return resp, o.SendEndpointRequest(endpoints.Order, map[string]string{"order": request.OrderID}, FormatParameters(request), request, &resp)
 }
 
 // GetSpotTransactionDetails Get details of the recent filled orders. Cursor pagination is used.
 // All paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKGroup) GetSpotTransactionDetails(request GetSpotTransactionDetailsRequest) (resp []GetSpotTransactionDetailsResponse, _ error) {
-	requestURL := fmt.Sprintf("%v%v", OKGroupGetSpotTransactionDetails, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, requestURL, nil, &resp, false)
+	return resp, o.SendEndpointRequest(endpoints.Fills, nil, FormatParameters(request), nil, &resp)
 }
 
 // GetSpotTokenPairDetails Get market data. This endpoint provides the snapshots of market data and can be used without verifications.
 // List trading pairs and get the trading limit, price, and more information of different trading pairs.
 func (o *OKGroup) GetSpotTokenPairDetails() (resp []GetSpotTokenPairDetailsResponse, _ error) {
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, OKGroupInstruments, nil, &resp, false)
+	return resp, o.SendEndpointRequest(endpoints.Instruments, nil, "", nil, &resp)
 }
 
 // GetSpotOrderBook Getting the order book of a trading pair. Pagination is not supported here.
 // The whole book will be returned for one request. Websocket is recommended here.
 func (o *OKGroup) GetSpotOrderBook(request GetSpotOrderBookRequest) (resp GetSpotOrderBookResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v/%v%v", OKGroupInstruments, request.InstrumentID, OKGroupGetSpotOrderBook, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, requestURL, nil, &resp, false)
+	return resp, o.SendEndpointRequest(endpoints.Orderbook, map[string]string{"instrument": request.InstrumentID}, FormatParameters(request), nil, &resp)
 }
 
 // GetSpotAllTokenPairsInformation Get the last traded price, best bid/ask price, 24 hour trading volume and more info of all trading pairs.
 func (o *OKGroup) GetSpotAllTokenPairsInformation() (resp []GetSpotTokenPairsInformationResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v", OKGroupInstruments, OKGroupTicker)
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, requestURL, nil, &resp, false)
+	return resp, o.SendEndpointRequest(endpoints.Tickers, nil, "", nil, &resp)
 }
 
 // GetSpotAllTokenPairsInformationForCurrency Get the last traded price, best bid/ask price, 24 hour trading volume and more info of a currency
 func (o *OKGroup) GetSpotAllTokenPairsInformationForCurrency(currency string) (resp GetSpotTokenPairsInformationResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v/%v", OKGroupInstruments, currency, OKGroupTicker)
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, requestURL, nil, &resp, false)
+	return resp, o.SendEndpointRequest(endpoints.Ticker, map[string]string{"instrument": currency}, "", nil, &resp)
 }
 
 // GetSpotFilledOrdersInformation Get the recent 60 transactions of all trading pairs.
 // Cursor pagination is used. All paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKGroup) GetSpotFilledOrdersInformation(request GetSpotFilledOrdersInformationRequest) (resp []GetSpotFilledOrdersInformationResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v/%v%v", OKGroupInstruments, request.InstrumentID, OKGroupTrades, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, requestURL, nil, &resp, false)
+	return resp, o.SendEndpointRequest(endpoints.Trades, map[string]string{"instrument": request.InstrumentID}, FormatParameters(request), nil, &resp)
 }
 
 // GetSpotMarketData Get the charts of the trading pairs. Charts are returned in grouped buckets based on requested granularity.
 func (o *OKGroup) GetSpotMarketData(request GetSpotMarketDataRequest) (resp GetSpotMarketDataResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v/%v%v", OKGroupInstruments, request.InstrumentID, OKGroupGetSpotMarketData, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupTokenSubsection, requestURL, nil, &resp, false)
+	return resp, o.SendEndpointRequest(endpoints.Candles, map[string]string{"instrument": request.InstrumentID}, FormatParameters(request), nil, &resp)
 }
 
 // GetMarginTradingAccounts List all assets under token margin trading account, including information such as balance, amount on hold and more.
 func (o *OKGroup) GetMarginTradingAccounts() (resp []GetMarginAccountsResponse, _ error) {
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupMarginTradingSubsection, OKGroupAccounts, nil, &resp, true)
+	return resp, o.SendEndpointRequest(marginAccounts, nil, "", nil, &resp)
 }
 
 // GetMarginTradingAccountsForCurrency Get the balance, amount on hold and more useful information.
 func (o *OKGroup) GetMarginTradingAccountsForCurrency(currency string) (resp GetMarginAccountsResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v", OKGroupAccounts, currency)
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupMarginTradingSubsection, requestURL, nil, &resp, true)
+	return resp, o.SendEndpointRequest(marginAccount, map[string]string{"currency": currency}, "", nil, &resp)
+}
+
+// UnmarshalJSON parses margin account responses where each currency balance is
+// returned under a "currency:<code>" key
+func (g *GetMarginAccountsResponse) UnmarshalJSON(data []byte) error {
+	type marginAccount GetMarginAccountsResponse
+	var account marginAccount
+	err := common.JSONDecode(data, &account)
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	err = common.JSONDecode(data, &raw)
+	if err != nil {
+		return err
+	}
+
+	account.Currencies = make(map[string]MarginAccountInfo)
+	for k, v := range raw {
+		if !strings.HasPrefix(k, marginCurrencyPrefix) {
+			continue
+		}
+		var info MarginAccountInfo
+		err = common.JSONDecode(v, &info)
+		if err != nil {
+			return err
+		}
+		account.Currencies[strings.ToUpper(strings.TrimPrefix(k, marginCurrencyPrefix))] = info
+	}
+
+	*g = GetMarginAccountsResponse(account)
+	return nil
 }
 
 // GetMarginBillDetails List all bill details. Pagination is used here.
 // before and after cursor arguments should not be confused with before and after in chronological time.
 // Most paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKGroup) GetMarginBillDetails(request GetMarginBillDetailsRequest) (resp []GetSpotBillDetailsForCurrencyResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v/%v%v", OKGroupAccounts, request.InstrumentID, OKGroupLedger, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupMarginTradingSubsection, requestURL, nil, &resp, true)
+	return resp, o.SendEndpointRequest(marginLedger, map[string]string{"instrument": request.InstrumentID}, FormatParameters(request), nil, &resp)
 }
 
 // GetMarginAccountSettings Get all information of the margin trading account,
 // including the maximum loan amount, interest rate, and maximum leverage.
 func (o *OKGroup) GetMarginAccountSettings(currency string) (resp []GetMarginAccountSettingsResponse, _ error) {
-	var requestURL string
 	if currency != "" {
-		requestURL = fmt.Sprintf("%v/%v/%v", OKGroupAccounts, currency, okGroupGetMarketAvailability)
-	} else {
-		requestURL = fmt.Sprintf("%v/%v", OKGroupAccounts, okGroupGetMarketAvailability)
+		return resp, o.SendEndpointRequest(marginCurrencySettings, map[string]string{"currency": currency}, "", nil, &resp)
+	}
+	return resp, o.SendEndpointRequest(marginSettings, nil, "", nil, &resp)
+}
+
+// UnmarshalJSON parses margin account settings where each currency is returned
+// under a "currency:<code>" key
+func (g *GetMarginAccountSettingsResponse) UnmarshalJSON(data []byte) error {
+	type marginSettings GetMarginAccountSettingsResponse
+	var settings marginSettings
+	err := common.JSONDecode(data, &settings)
+	if err != nil {
+		return err
+	}
+
+	var raw map[string]json.RawMessage
+	err = common.JSONDecode(data, &raw)
+	if err != nil {
+		return err
 	}
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupMarginTradingSubsection, requestURL, nil, &resp, true)
+
+	settings.Currencies = make(map[string]MarginAccountSettingsInfo)
+	for k, v := range raw {
+		if !strings.HasPrefix(k, marginCurrencyPrefix) {
+			continue
+		}
+		var info MarginAccountSettingsInfo
+		err = common.JSONDecode(v, &info)
+		if err != nil {
+			return err
+		}
+		settings.Currencies[strings.ToUpper(strings.TrimPrefix(k, marginCurrencyPrefix))] = info
+	}
+
+	*g = GetMarginAccountSettingsResponse(settings)
+	return nil
 }
 
 // GetMarginLoanHistory Get loan history of the margin trading account.
 // Pagination is used here. before and after cursor arguments should not be confused with before and after in chronological time.
 // Most paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKGroup) GetMarginLoanHistory(request GetMarginLoanHistoryRequest) (resp []GetMarginLoanHistoryResponse, _ error) {
-	var requestURL string
 	if len(request.InstrumentID) > 0 {
-		requestURL = fmt.Sprintf("%v/%v/%v", OKGroupAccounts, request.InstrumentID, okGroupGetLoan)
-	} else {
-		requestURL = fmt.Sprintf("%v/%v", OKGroupAccounts, okGroupGetLoan)
+		return resp, o.SendEndpointRequest(marginInstrumentLoans, map[string]string{"instrument": request.InstrumentID}, FormatParameters(request), nil, &resp)
 	}
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupMarginTradingSubsection, requestURL, nil, &resp, true)
+	return resp, o.SendEndpointRequest(marginLoans, nil, FormatParameters(request), nil, &resp)
 }
 
 // OpenMarginLoan Borrowing tokens in a margin trading account.
 func (o *OKGroup) OpenMarginLoan(request OpenMarginLoanRequest) (resp OpenMarginLoanResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v", OKGroupAccounts, okGroupGetLoan)
-	return resp, o.SendHTTPRequest(http.MethodPost, okGroupMarginTradingSubsection, requestURL, request, &resp, true)
+	return resp, o.SendEndpointRequest(marginBorrow, nil, "", request, &resp)
 }
 
 // RepayMarginLoan Repaying tokens in a margin trading account.
 func (o *OKGroup) RepayMarginLoan(request RepayMarginLoanRequest) (resp RepayMarginLoanResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v", OKGroupAccounts, okGroupGetRepayment)
-	return resp, o.SendHTTPRequest(http.MethodPost, okGroupMarginTradingSubsection, requestURL, request, &resp, true)
+	return resp, o.SendEndpointRequest(marginRepay, nil, "", request, &resp)
 }
 
 // PlaceMarginOrder OKEx API only supports limit and market orders (more orders will become available in the future).
 // You can place an order only if you have enough funds. Once your order is placed, the amount will be put on hold.
 func (o *OKGroup) PlaceMarginOrder(request *PlaceSpotOrderRequest) (resp PlaceSpotOrderResponse, _ error) {
-	return resp, o.SendHTTPRequest(http.MethodPost, okGroupMarginTradingSubsection, OKGroupOrders, request, &resp, true)
+	return resp, o.SendEndpointRequest(marginPlaceOrder, nil, "", request, &resp)
 }
 
 // PlaceMultipleMarginOrders Place multiple orders for specific trading pairs (up to 4 trading pairs, maximum 4 orders each)
@@ -480,7 +615,7 @@ func (o *OKGroup) PlaceMultipleMarginOrders(request []PlaceSpotOrderRequest) (ma
 		}
 	}
 
-	err := o.SendHTTPRequest(http.MethodPost, okGroupMarginTradingSubsection, OKGroupBatchOrders, request, &resp, true)
+	err := o.SendEndpointRequest(marginPlaceOrders, nil, "", request, &resp)
 	if err != nil {
 		return resp, []error{err}
 	}
@@ -499,8 +634,7 @@ func (o *OKGroup) PlaceMultipleMarginOrders(request []PlaceSpotOrderRequest) (ma
 
 // CancelMarginOrder Cancelling an unfilled order.
 func (o *OKGroup) CancelMarginOrder(request CancelSpotOrderRequest) (resp CancelSpotOrderResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v", OKGroupCancelOrders, request.OrderID)
-	return resp, o.SendHTTPRequest(http.MethodPost, okGroupMarginTradingSubsection, requestURL, request, &resp, true)
+	return resp, o.SendEndpointRequest(marginCancelOrder, map[string]string{"order": strconv.FormatInt(request.OrderID, 10)}, "", request, &resp)
 }
 
 // CancelMultipleMarginOrders Cancelling multiple unfilled orders.
@@ -510,7 +644,7 @@ func (o *OKGroup) CancelMultipleMarginOrders(request CancelMultipleSpotOrdersReq
 		return resp, []error{errors.New("maximum 4 order cancellations for each pair")}
 	}
 
-	err := o.SendHTTPRequest(http.MethodPost, okGroupMarginTradingSubsection, OKGroupCancelBatchOrders, []CancelMultipleSpotOrdersRequest{request}, &resp, true)
+	err := o.SendEndpointRequest(marginCancelOrders, nil, "", []CancelMultipleSpotOrdersRequest{request}, &resp)
 	if err != nil {
 		return resp, []error{err}
 	}
@@ -529,27 +663,23 @@ func (o *OKGroup) CancelMultipleMarginOrders(request CancelMultipleSpotOrdersReq
 
 // GetMarginOrders List your orders. Cursor pagination is used. All paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKGroup) GetMarginOrders(request GetSpotOrdersRequest) (resp []GetSpotOrderResponse, _ error) {
-	requestURL := fmt.Sprintf("%v%v", OKGroupOrders, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupMarginTradingSubsection, requestURL, nil, &resp, true)
+	return resp, o.SendEndpointRequest(marginOrders, nil, FormatParameters(request), nil, &resp)
 }
 
 // GetMarginOpenOrders List all your current open orders. Cursor pagination is used. All paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKGroup) GetMarginOpenOrders(request GetSpotOpenOrdersRequest) (resp []GetSpotOrderResponse, _ error) {
-	requestURL := fmt.Sprintf("%v%v", OKGroupPendingOrders, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupMarginTradingSubsection, requestURL, nil, &resp, true)
+	return resp, o.SendEndpointRequest(marginOpenOrders, nil, FormatParameters(request), nil, &resp)
 }
 
 // GetMarginOrder Get order details by order ID.
 func (o *OKGroup) GetMarginOrder(request GetSpotOrderRequest) (resp GetSpotOrderResponse, _ error) {
-	requestURL := fmt.Sprintf("%v/%v%v", OKGroupOrders, request.OrderID, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupMarginTradingSubsection, requestURL, request, &resp, true)
+	return resp, o.SendEndpointRequest(marginOrder, map[string]string{"order": request.OrderID}, FormatParameters(request), request, &resp)
 }
 
 // GetMarginTransactionDetails Get details of the recent filled orders. Cursor pagination is used.
 // All paginated requests return the latest information (newest) as the first page sorted by newest (in chronological time) first.
 func (o *OKGroup) GetMarginTransactionDetails(request GetSpotTransactionDetailsRequest) (resp []GetSpotTransactionDetailsResponse, _ error) {
-	requestURL := fmt.Sprintf("%v%v", OKGroupGetSpotTransactionDetails, FormatParameters(request))
-	return resp, o.SendHTTPRequest(http.MethodGet, okGroupMarginTradingSubsection, requestURL, nil, &resp, true)
+	return resp, o.SendEndpointRequest(marginFills, nil, FormatParameters(request), nil, &resp)
 }
 
 // FormatParameters Formats URL parameters, useful for optional parameters due to OKEX signature check
@@ -588,10 +718,61 @@ func (o *OKGroup) GetErrorCode(code interface{}) error {
 // SendHTTPRequest sends an authenticated http request to a desired
 // path with a JSON payload (of present)
 // URL arguments must be in the request path and not as url.URL values
-func (o *OKGroup) SendHTTPRequest(httpMethod, requestType, requestPath string, data, result interface{}, authenticated bool) (err error) {
+func (o *OKGroup) SendHTTPRequest(httpMethod, requestType, requestPath string, data, result interface{}, authenticated bool) error {
+	path := requestType + o.APIVersion + requestPath
+	return o.sendRequest(httpMethod, path, "/"+OKGroupAPIPath+path, data, result, authenticated)
+}
+
+// SendEndpointRequest sends a request to the operation's path in the active
+// API version, URL arguments are appended to the path as params
+func (o *OKGroup) SendEndpointRequest(op endpoints.Operation, vars map[string]string, params string, data, result interface{}) error {
+	if o.Endpoints == nil {
+		return fmt.Errorf("%s endpoints not set up", o.Name)
+	}
+	route, err := o.Endpoints.Resolve(op, vars)
+	if err != nil {
+		return err
+	}
+	return o.sendRequest(route.Method, route.Path+params, route.SignPath+params, data, result, route.Authenticated)
+}
+
+// mutating returns whether the authenticated endpoint changes exchange state.
+// Endpoints are matched by their path beneath the subsection and API version.
+// Orders and leverage are read and changed through the same paths so those
+// are also matched by method
+func mutating(httpMethod, requestPath string) bool {
+	parts := strings.SplitN(strings.SplitN(requestPath, "?", 2)[0], "/", 3)
+	if len(parts) < 3 {
+		return false
+	}
+	path := parts[2]
+	switch path {
+	case okGroupFundsTransfer, okGroupWithdraw, OKGroupBatchOrders, OKGroupAlgoOrder,
+		okGroupCancelAlgoOrders, okGroupFuturesOrder, okGroupFuturesCancelAlgo,
+		OKGroupAccounts + "/" + okGroupGetLoan, OKGroupAccounts + "/" + okGroupGetRepayment:
+		return true
+	}
+	if path == OKGroupOrders || strings.HasPrefix(path, OKGroupOrders+"/") ||
+		strings.HasSuffix(path, "/"+okGroupFuturesLeverage) {
+		return httpMethod != http.MethodGet
+	}
+	for _, action := range []string{OKGroupCancelOrders, OKGroupCancelOrder, OKGroupCancelBatchOrders} {
+		if path == action || strings.HasPrefix(path, action+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// sendRequest sends a request to the path under the API URL, signing signPath
+// when authenticated
+func (o *OKGroup) sendRequest(httpMethod, requestPath, signPath string, data, result interface{}, authenticated bool) (err error) {
 	if authenticated && !o.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, o.Name)
 	}
+	if authenticated && o.DryRunRequest(mutating(httpMethod, requestPath), httpMethod+" "+requestPath, data) {
+		return nil
+	}
 
 	utcTime := time.Now().UTC()
 	iso := utcTime.String()
@@ -610,15 +791,14 @@ func (o *OKGroup) SendHTTPRequest(httpMethod, requestType, requestPath string, d
 		}
 	}
 
-	path := o.APIUrl + requestType + o.APIVersion + requestPath
+	path := o.APIUrl + requestPath
 	if o.Verbose {
-		log.Debugf("Sending %v request to %s \n", requestType, path)
+		log.Debugf("Sending %v request to %s \n", httpMethod, path)
 	}
 
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/json"
 	if authenticated {
-		signPath := fmt.Sprintf("/%v%v%v%v", OKGroupAPIPath, requestType, o.APIVersion, requestPath)
 		hmac := common.GetHMAC(common.HashSHA256, []byte(iso+httpMethod+signPath+string(payload)), []byte(o.APISecret))
 		base64 := common.Base64Encode(hmac)
 		headers["OK-ACCESS-KEY"] = o.APIKey
@@ -851,3 +1031,16 @@ func (o *OKGroup) SetErrorDefaults() {
 		"35061": errors.New("invalid instrument_id"),
 	}
 }
+
+// UnmarshalJSON decodes the account info, which is keyed directly by token
+func (f *FuturesAccountForAllCurrenciesResponse) UnmarshalJSON(data []byte) error {
+	var resp struct {
+		Info map[string]FuturesCurrencyData `json:"info"`
+	}
+	err := json.Unmarshal(data, &resp)
+	if err != nil {
+		return err
+	}
+	f.Info.Currency = resp.Info
+	return nil
+}
@@ -0,0 +1,228 @@
+package okgroup
+
+import (
+	"net/http"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/endpoints"
+)
+
+// Operations of the algo order and margin trading APIs, which are particular
+// to OKGroup
+const (
+	placeAlgoOrder         endpoints.Operation = "placeAlgoOrder"
+	cancelAlgoOrders       endpoints.Operation = "cancelAlgoOrders"
+	algoOrders             endpoints.Operation = "algoOrders"
+	marginAccounts         endpoints.Operation = "marginAccounts"
+	marginAccount          endpoints.Operation = "marginAccount"
+	marginLedger           endpoints.Operation = "marginLedger"
+	marginSettings         endpoints.Operation = "marginSettings"
+	marginCurrencySettings endpoints.Operation = "marginCurrencySettings"
+	marginLoans            endpoints.Operation = "marginLoans"
+	marginInstrumentLoans  endpoints.Operation = "marginInstrumentLoans"
+	marginBorrow           endpoints.Operation = "marginBorrow"
+	marginRepay            endpoints.Operation = "marginRepay"
+	marginPlaceOrder       endpoints.Operation = "marginPlaceOrder"
+	marginPlaceOrders      endpoints.Operation = "marginPlaceOrders"
+	marginCancelOrder      endpoints.Operation = "marginCancelOrder"
+	marginCancelOrders     endpoints.Operation = "marginCancelOrders"
+	marginOrders           endpoints.Operation = "marginOrders"
+	marginOpenOrders       endpoints.Operation = "marginOpenOrders"
+	marginOrder            endpoints.Operation = "marginOrder"
+	marginFills            endpoints.Operation = "marginFills"
+)
+
+// okGroupV3 is the name of the v3 API, whose paths are prefixed by their
+// subsection, e.g. spot/v3/orders
+const okGroupV3 = "v3"
+
+func v3Path(subsection, path string) string {
+	return subsection + "/" + okGroupV3 + "/" + path
+}
+
+// v3Endpoints holds the paths of the v3 spot, margin and account API
+var v3Endpoints = endpoints.Version{
+	Name:       okGroupV3,
+	SignPrefix: "/" + OKGroupAPIPath,
+	Endpoints: map[endpoints.Operation]endpoints.Endpoint{
+		endpoints.Accounts: {
+			Method:        http.MethodGet,
+			Path:          v3Path(okGroupTokenSubsection, OKGroupAccounts),
+			Authenticated: true,
+		},
+		endpoints.Account: {
+			Method:        http.MethodGet,
+			Path:          v3Path(okGroupTokenSubsection, OKGroupAccounts+"/{currency}"),
+			Authenticated: true,
+		},
+		endpoints.PlaceOrder: {
+			Method:        http.MethodPost,
+			Path:          v3Path(okGroupTokenSubsection, OKGroupOrders),
+			Authenticated: true,
+		},
+		endpoints.CancelOrder: {
+			Method:        http.MethodPost,
+			Path:          v3Path(okGroupTokenSubsection, OKGroupCancelOrders+"/{order}"),
+			Authenticated: true,
+		},
+		endpoints.Orders: {
+			Method:        http.MethodGet,
+			Path:          v3Path(okGroupTokenSubsection, OKGroupOrders),
+			Authenticated: true,
+		},
+		endpoints.OpenOrders: {
+			Method:        http.MethodGet,
+			Path:          v3Path(okGroupTokenSubsection, OKGroupPendingOrders),
+			Authenticated: true,
+		},
+		endpoints.Order: {
+			Method:        http.MethodGet,
+			Path:          v3Path(okGroupTokenSubsection, OKGroupOrders+"/{order}"),
+			Authenticated: true,
+		},
+		endpoints.Fills: {
+			Method:        http.MethodGet,
+			Path:          v3Path(okGroupTokenSubsection, OKGroupGetSpotTransactionDetails),
+			Authenticated: true,
+		},
+		endpoints.Instruments: {
+			Method: http.MethodGet,
+			Path:   v3Path(okGroupTokenSubsection, OKGroupInstruments),
+		},
+		endpoints.Orderbook: {
+			Method: http.MethodGet,
+			Path:   v3Path(okGroupTokenSubsection, OKGroupInstruments+"/{instrument}/"+OKGroupGetSpotOrderBook),
+		},
+		endpoints.Tickers: {
+			Method: http.MethodGet,
+			Path:   v3Path(okGroupTokenSubsection, OKGroupInstruments+"/"+OKGroupTicker),
+		},
+		endpoints.Ticker: {
+			Method: http.MethodGet,
+			Path:   v3Path(okGroupTokenSubsection, OKGroupInstruments+"/{instrument}/"+OKGroupTicker),
+		},
+		endpoints.Trades: {
+			Method: http.MethodGet,
+			Path:   v3Path(okGroupTokenSubsection, OKGroupInstruments+"/{instrument}/"+OKGroupTrades),
+		},
+		endpoints.Candles: {
+			Method: http.MethodGet,
+			Path:   v3Path(okGroupTokenSubsection, OKGroupInstruments+"/{instrument}/"+OKGroupGetSpotMarketData),
+		},
+		placeAlgoOrder: {
+			Method:        http.MethodPost,
+			Path:          v3Path(okGroupTokenSubsection, OKGroupAlgoOrder),
+			Authenticated: true,
+		},
+		cancelAlgoOrders: {
+			Method:        http.MethodPost,
+			Path:          v3Path(okGroupTokenSubsection, okGroupCancelAlgoOrders),
+			Authenticated: true,
+		},
+		algoOrders: {
+			Method:        http.MethodGet,
+			Path:          v3Path(okGroupTokenSubsection, okGroupAlgoOrders),
+			Authenticated: true,
+		},
+		marginAccounts: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupAccounts),
+			Authenticated: true,
+		},
+		marginAccount: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupAccounts+"/{currency}"),
+			Authenticated: true,
+		},
+		marginLedger: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupAccounts+"/{instrument}/"+OKGroupLedger),
+			Authenticated: true,
+		},
+		marginSettings: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupAccounts+"/"+okGroupGetMarketAvailability),
+			Authenticated: true,
+		},
+		marginCurrencySettings: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupAccounts+"/{currency}/"+okGroupGetMarketAvailability),
+			Authenticated: true,
+		},
+		marginLoans: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupAccounts+"/"+okGroupGetLoanHistory),
+			Authenticated: true,
+		},
+		marginInstrumentLoans: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupAccounts+"/{instrument}/"+okGroupGetLoanHistory),
+			Authenticated: true,
+		},
+		marginBorrow: {
+			Method:        http.MethodPost,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupAccounts+"/"+okGroupGetLoan),
+			Authenticated: true,
+		},
+		marginRepay: {
+			Method:        http.MethodPost,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupAccounts+"/"+okGroupGetRepayment),
+			Authenticated: true,
+		},
+		marginPlaceOrder: {
+			Method:        http.MethodPost,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupOrders),
+			Authenticated: true,
+		},
+		marginPlaceOrders: {
+			Method:        http.MethodPost,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupBatchOrders),
+			Authenticated: true,
+		},
+		marginCancelOrder: {
+			Method:        http.MethodPost,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupCancelOrders+"/{order}"),
+			Authenticated: true,
+		},
+		marginCancelOrders: {
+			Method:        http.MethodPost,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupCancelBatchOrders),
+			Authenticated: true,
+		},
+		marginOrders: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupOrders),
+			Authenticated: true,
+		},
+		marginOpenOrders: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupPendingOrders),
+			Authenticated: true,
+		},
+		marginOrder: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupOrders+"/{order}"),
+			Authenticated: true,
+		},
+		marginFills: {
+			Method:        http.MethodGet,
+			Path:          v3Path(OKGroupMarginTradingSubsection, OKGroupGetSpotTransactionDetails),
+			Authenticated: true,
+		},
+		endpoints.DepositAddress: {
+			Method:        http.MethodGet,
+			Path:          v3Path(okGroupAccountSubsection, okGroupGetDepositAddress),
+			Authenticated: true,
+		},
+		endpoints.Withdraw: {
+			Method:        http.MethodPost,
+			Path:          v3Path(okGroupAccountSubsection, okGroupWithdraw),
+			Authenticated: true,
+		},
+	},
+}
+
+// NewEndpoints returns the endpoint registry of the OKGroup REST API versions
+// with v3 active
+func NewEndpoints() (*endpoints.Registry, error) {
+	return endpoints.New(v3Endpoints)
+}
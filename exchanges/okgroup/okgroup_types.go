@@ -2,6 +2,8 @@ package okgroup
 
 import (
 	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 )
 
 // GetAccountCurrenciesResponse response data for GetAccountCurrencies
@@ -335,13 +337,33 @@ type GetSpotMarketDataRequest struct {
 // volume 	string 	Trading volume
 type GetSpotMarketDataResponse []interface{}
 
+// maxCandles is the most candles returned per market data request
+const maxCandles = 200
+
+// okGroupCandleIntervals holds the market data granularities supported, the
+// granularity is sent in seconds
+var okGroupCandleIntervals = map[kline.Interval]bool{
+	kline.OneMin:     true,
+	kline.ThreeMin:   true,
+	kline.FiveMin:    true,
+	kline.FifteenMin: true,
+	kline.ThirtyMin:  true,
+	kline.OneHour:    true,
+	kline.TwoHour:    true,
+	kline.FourHour:   true,
+	kline.SixHour:    true,
+	kline.TwelveHour: true,
+	kline.OneDay:     true,
+	kline.OneWeek:    true,
+}
+
 // GetMarginAccountsResponse response data for GetMarginAccounts
 type GetMarginAccountsResponse struct {
-	InstrumentID     string `json:"instrument_id,omitempty"`
-	LiquidationPrice string `json:"liquidation_price"`
-	ProductID        string `json:"product_id,omitempty"`
-	RiskRate         string `json:"risk_rate"`
-	Currencies       map[string]MarginAccountInfo
+	InstrumentID     string                       `json:"instrument_id,omitempty"`
+	LiquidationPrice string                       `json:"liquidation_price"`
+	ProductID        string                       `json:"product_id,omitempty"`
+	RiskRate         string                       `json:"risk_rate"`
+	Currencies       map[string]MarginAccountInfo `json:"-"`
 }
 
 // MarginAccountInfo contains individual currency information
@@ -381,11 +403,11 @@ type MarginAccountSettingsInfo struct {
 
 // GetMarginLoanHistoryRequest request data for GetMarginLoanHistory
 type GetMarginLoanHistoryRequest struct {
-	InstrumentID string // [optional] Used when a specific currency response is desired
-	Status       int64  `json:"status,string,omitempty"` // [optional] status(0: outstanding 1: repaid)
-	From         int64  `json:"from,string,omitempty"`   // [optional] request page from(newer) this id.
-	To           int64  `json:"to,string,omitempty"`     // [optional] request page to(older) this id.
-	Limit        int64  `json:"limit,string,omitempty"`  // [optional] number of results per request. Maximum 100.(default 100)
+	InstrumentID string `url:"-"`               // [optional] Used when a specific currency response is desired
+	Status       int64  `url:"status"`          // status(0: outstanding 1: repaid), always sent
+	From         int64  `url:"from,omitempty"`  // [optional] request page from(newer) this id.
+	To           int64  `url:"to,omitempty"`    // [optional] request page to(older) this id.
+	Limit        int64  `url:"limit,omitempty"` // [optional] number of results per request. Maximum 100.(default 100)
 }
 
 // GetMarginLoanHistoryResponse response data for GetMarginLoanHistory
@@ -434,6 +456,26 @@ type RepayMarginLoanResponse struct {
 	Result      bool  `json:"result"`
 }
 
+// GetMarginLeverageResponse response data for GetMarginLeverage
+type GetMarginLeverageResponse struct {
+	InstrumentID string  `json:"instrument_id"`
+	Leverage     float64 `json:"leverage,string"`
+	Result       bool    `json:"result"`
+}
+
+// SetMarginLeverageRequest request data for SetMarginLeverage
+type SetMarginLeverageRequest struct {
+	InstrumentID string  `json:"-"`               // [required] trading pair eg btc-usdt
+	Leverage     float64 `json:"leverage,string"` // [required] leverage, 2 to the maximum leverage of the pair
+}
+
+// GetMarginMarkPriceResponse response data for GetMarginMarkPrice
+type GetMarginMarkPriceResponse struct {
+	InstrumentID string    `json:"instrument_id"`
+	MarkPrice    float64   `json:"mark_price,string"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
 // GetFuturesPositionsResponse response data for GetFuturesPositions
 type GetFuturesPositionsResponse struct {
 	Holding [][]GetFuturePostionsDetails `json:"holding"`
@@ -519,10 +561,10 @@ type GetFuturesLeverageData struct {
 
 // SetFuturesLeverageRequest request data for SetFuturesLeverage
 type SetFuturesLeverageRequest struct {
-	Direction    string `json:"direction,omitempty"`     // opening side (long or short)
-	InstrumentID string `json:"instrument_id,omitempty"` //  	Contract ID, e.g. "BTC-USD-180213"
-	Leverage     int64  `json:"leverage,omitempty"`      //  	10x or 20x leverage
-	Currency     string `json:"currency,omitempty"`
+	Direction    string `json:"direction,omitempty"`       // opening side (long or short)
+	InstrumentID string `json:"instrument_id,omitempty"`   //  	Contract ID, e.g. "BTC-USD-180213"
+	Leverage     int64  `json:"leverage,string,omitempty"` //  	10x or 20x leverage
+	Currency     string `json:"-"`                         // [required] token, e.g. "btc", sent in the URL
 }
 
 // SetFuturesLeverageResponse returned data for SetFuturesLeverage
@@ -643,12 +685,12 @@ type GetFuturesOrderDetailsRequest struct {
 
 // GetFuturesTransactionDetailsRequest request data for GetFuturesTransactionDetails
 type GetFuturesTransactionDetailsRequest struct {
-	OrderID      int64  `json:"order_id,string"`        // [required] Order ID
-	InstrumentID string `json:"instrument_id"`          // [required] Contract ID, e.g. "BTC-USD-180213"
-	Status       int64  `json:"status,string"`          // [required] Order Status （-1 canceled; 0: pending, 1: partially filled, 2: fully filled, 6: open (pending partially + fully filled), 7: completed (canceled + fully filled))
-	From         int64  `json:"from,string,omitempty"`  // [optional] Request paging content for this page number.（Example: 1,2,3,4,5. From 4 we only have 4, to 4 we only have 3）
-	To           int64  `json:"to,string,omitempty"`    // [optional] Request page after (older) this pagination id. （Example: 1,2,3,4,5. From 4 we only have 4, to 4 we only have 3）
-	Limit        int64  `json:"limit,string,omitempty"` // [optional]  	Number of results per request. Maximum 100. (default 100)
+	OrderID      int64  `url:"order_id,string"`        // [required] Order ID
+	InstrumentID string `url:"instrument_id"`          // [required] Contract ID, e.g. "BTC-USD-180213"
+	Status       int64  `url:"status,omitempty"`       // [optional] Order Status （-1 canceled; 0: pending, 1: partially filled, 2: fully filled, 6: open (pending partially + fully filled), 7: completed (canceled + fully filled))
+	From         int64  `url:"from,string,omitempty"`  // [optional] Request paging content for this page number.（Example: 1,2,3,4,5. From 4 we only have 4, to 4 we only have 3）
+	To           int64  `url:"to,string,omitempty"`    // [optional] Request page after (older) this pagination id. （Example: 1,2,3,4,5. From 4 we only have 4, to 4 we only have 3）
+	Limit        int64  `url:"limit,string,omitempty"` // [optional]  	Number of results per request. Maximum 100. (default 100)
 }
 
 // GetFuturesTransactionDetailsResponse response data for GetFuturesTransactionDetails
@@ -773,6 +815,29 @@ type GetFuturesEstimatedDeliveryPriceResponse struct {
 	Timestamp       time.Time `json:"timestamp"`
 }
 
+// Futures settlement history types
+const (
+	FuturesSettlement = "1"
+	FuturesDelivery   = "2"
+)
+
+// GetFuturesSettlementHistoryRequest request data for GetFuturesSettlementHistory
+type GetFuturesSettlementHistoryRequest struct {
+	InstrumentID string `url:"instrument_id,omitempty"` // [optional] Contract ID, e.g. "BTC-USD-190329". Either the contract ID or underlying is required
+	Underlying   string `url:"underlying,omitempty"`    // [optional] Underlying index, e.g. "BTC-USD"
+	Start        string `url:"start,omitempty"`         // [optional] Start time in ISO 8601
+	End          string `url:"end,omitempty"`           // [optional] End time in ISO 8601
+	Limit        int64  `url:"limit,string,omitempty"`  // [optional] Number of results per request. Maximum 100. (default 100)
+}
+
+// GetFuturesSettlementHistoryResponse response data for GetFuturesSettlementHistory
+type GetFuturesSettlementHistoryResponse struct {
+	InstrumentID string    `json:"instrument_id"`
+	Type         string    `json:"type"` // 1: settlement 2: delivery
+	Price        float64   `json:"price,string"`
+	Timestamp    time.Time `json:"timestamp"`
+}
+
 // GetFuturesOpenInterestsResponse response data for GetFuturesOpenInterests
 type GetFuturesOpenInterestsResponse struct {
 	Amount       float64   `json:"amount,string"`
@@ -908,7 +973,7 @@ type PlaceSwapOrderRequest struct {
 // PlaceSwapOrderResponse response data for PlaceSwapOrder
 type PlaceSwapOrderResponse struct {
 	OrderID      string `json:"order_id"`
-	ClientOID    int64  `json:"client_oid,string"`
+	ClientOID    string `json:"client_oid"`
 	ErrorCode    int64  `json:"error_code,string"`
 	ErrorMessage string `json:"error_message"`
 	Result       bool   `json:"result,string"`
@@ -1009,11 +1074,11 @@ type GetSwapOrderDetailsRequest struct {
 
 // GetSwapTransactionDetailsRequest request data for GetSwapTransactionDetails
 type GetSwapTransactionDetailsRequest struct {
-	InstrumentID string `json:"instrument_id"`          // [required] Contract ID, e.g. BTC-USD-SWAP
-	OrderID      string `json:"order_id"`               // [required] Order ID
-	From         int64  `json:"from,string,omitempty"`  // [optional] Request paging content for this page number.（Example: 1,2,3,4,5. From 4 we only have 4, to 4 we only have 3）
-	To           int64  `json:"to,string,omitempty"`    // [optional] Request page after (older) this pagination id. （Example: 1,2,3,4,5. From 4 we only have 4, to 4 we only have 3）
-	Limit        int64  `json:"limit,string,omitempty"` // [optional] number of results per request. Maximum 100. (default 100)
+	InstrumentID string `url:"instrument_id"`          // [required] Contract ID, e.g. BTC-USD-SWAP
+	OrderID      string `url:"order_id"`               // [required] Order ID
+	From         int64  `url:"from,string,omitempty"`  // [optional] Request paging content for this page number.（Example: 1,2,3,4,5. From 4 we only have 4, to 4 we only have 3）
+	To           int64  `url:"to,string,omitempty"`    // [optional] Request page after (older) this pagination id. （Example: 1,2,3,4,5. From 4 we only have 4, to 4 we only have 3）
+	Limit        int64  `url:"limit,string,omitempty"` // [optional] number of results per request. Maximum 100. (default 100)
 }
 
 // GetSwapTransactionDetailsResponse response data for GetSwapTransactionDetails
@@ -1160,20 +1225,24 @@ type GetSwapOnHoldAmountForOpenOrdersResponse struct {
 
 // GetSwapNextSettlementTimeResponse response data for GetSwapNextSettlementTime
 type GetSwapNextSettlementTimeResponse struct {
-	InstrumentID string `json:"instrument_id"`
-	FundingTime  string `json:"funding_time"`
+	InstrumentID   string `json:"instrument_id"`
+	FundingTime    string `json:"funding_time"`
+	FundingRate    string `json:"funding_rate"`
+	EstimatedRate  string `json:"estimated_rate"`
+	InterestRate   string `json:"interest_rate"`
+	SettlementTime string `json:"settlement_time"`
 }
 
 // GetSwapMarkPriceResponse response data for GetSwapMarkPrice
 type GetSwapMarkPriceResponse struct {
 	InstrumentID string `json:"instrument_id"`
 	MarkPrice    string `json:"mark_price"`
-	Timstamp     string `json:"timstamp"`
+	Timestamp    string `json:"timestamp"`
 }
 
 // GetSwapFundingRateHistoryRequest request data for GetSwapFundingRateHistory
 type GetSwapFundingRateHistoryRequest struct {
-	InstrumentID string `url:"ins-trument_id"`         // [required] Contract ID, e.g. "BTC-USD-SWAP
+	InstrumentID string `url:"-"`                      // [required] Contract ID, e.g. "BTC-USD-SWAP
 	From         int64  `url:"from,string,omitempty"`  // [optional] Request paging content for this page number.（Example: 1,2,3,4,5. From 4 we only have 4, to 4 we only have 3）
 	To           int64  `url:"to,string,omitempty"`    // [optional] Request page after (older) this pagination id. （Example: 1,2,3,4,5. From 4 we only have 4, to 4 we only have 3）
 	Limit        int64  `url:"limit,string,omitempty"` // [optional] Number of results per request. Maximum 100.
@@ -1221,8 +1290,8 @@ type PlaceETTOrderRequest struct {
 // PlaceETTOrderResponse  response data for PlaceETTOrder
 type PlaceETTOrderResponse struct {
 	ClientOID string `json:"client_oid"`
-	OrderID   string `json:"type"`
-	Result    bool   `json:"quote_currency"`
+	OrderID   string `json:"order_id"`
+	Result    bool   `json:"result"`
 }
 
 // GetETTOrderListRequest request data for GetETTOrderList
@@ -1522,3 +1591,121 @@ type WebsocketErrorResponse struct {
 	Message   string `json:"message"`
 	ErrorCode int64  `json:"errorCode"`
 }
+
+// Algo order types
+const (
+	AlgoTrigger int64 = iota + 1
+	AlgoTrail
+	AlgoIceberg
+	AlgoTWAP
+)
+
+// Algo order statuses
+const (
+	AlgoPending int64 = iota + 1
+	AlgoEffective
+	AlgoCancelled
+	AlgoPartiallyEffective
+	AlgoPaused
+	AlgoFailed
+)
+
+// Trigger order execution types
+const (
+	AlgoLimit  int64 = 1
+	AlgoMarket int64 = 2
+)
+
+// AlgoOrderParameters holds the parameters of each algo order type, only those
+// of the order type are set
+type AlgoOrderParameters struct {
+	// Trigger orders place an order at the algo price, or at market, once the
+	// trigger price is reached. Trail orders activate at the trigger price
+	TriggerPrice float64 `json:"trigger_price,string,omitempty"`
+	AlgoPrice    float64 `json:"algo_price,string,omitempty"`
+	AlgoType     int64   `json:"algo_type,string,omitempty"` // 1: limit 2: market
+	// Trail orders place a market order once the price retraces the callback
+	// rate, 0.001 to 0.05
+	CallbackRate float64 `json:"callback_rate,string,omitempty"`
+	// Iceberg orders place orders of the average amount varied by the
+	// variance, 0.0001 to 0.01, within the price limit
+	AlgoVariance float64 `json:"algo_variance,string,omitempty"`
+	AvgAmount    float64 `json:"avg_amount,string,omitempty"`
+	PriceLimit   float64 `json:"price_limit,string,omitempty"`
+	// TWAP orders sweep the book within the sweep range every time interval,
+	// 5 to 120 seconds, taking the sweep ratio up to the single limit
+	SweepRange   float64 `json:"sweep_range,string,omitempty"`
+	SweepRatio   float64 `json:"sweep_ratio,string,omitempty"`
+	SingleLimit  float64 `json:"single_limit,string,omitempty"`
+	TimeInterval int64   `json:"time_interval,string,omitempty"`
+}
+
+// PlaceSpotAlgoOrderRequest request data for PlaceSpotAlgoOrder
+type PlaceSpotAlgoOrderRequest struct {
+	InstrumentID string  `json:"instrument_id"`     // [required] Trading pair, e.g. "BTC-USDT"
+	Mode         int64   `json:"mode,string"`       // [required] 1: spot 2: margin
+	OrderType    int64   `json:"order_type,string"` // [required] 1: trigger 2: trail 3: iceberg 4: TWAP
+	Size         float64 `json:"size,string"`       // [required] Total amount
+	Side         string  `json:"side"`              // [required] buy or sell
+	AlgoOrderParameters
+}
+
+// PlaceContractAlgoOrderRequest request data for placing futures and swap algo
+// orders
+type PlaceContractAlgoOrderRequest struct {
+	InstrumentID string  `json:"instrument_id"`     // [required] Contract ID, e.g. "BTC-USD-190628" or "BTC-USD-SWAP"
+	Type         int64   `json:"type,string"`       // [required] 1: open long 2: open short 3: close long 4: close short
+	OrderType    int64   `json:"order_type,string"` // [required] 1: trigger 2: trail 3: iceberg 4: TWAP
+	Size         float64 `json:"size,string"`       // [required] Number of contracts
+	AlgoOrderParameters
+}
+
+// CancelAlgoOrdersRequest request data for cancelling algo orders
+type CancelAlgoOrdersRequest struct {
+	InstrumentID string   `json:"instrument_id"`     // [required]
+	AlgoIDs      []string `json:"algo_ids"`          // [required] Up to 10 algo order IDs
+	OrderType    int64    `json:"order_type,string"` // [required] 1: trigger 2: trail 3: iceberg 4: TWAP
+}
+
+// AlgoOrderResponse response data for placing and cancelling algo orders
+type AlgoOrderResponse struct {
+	AlgoID       string   `json:"algo_id"`
+	AlgoIDs      []string `json:"algo_ids"`
+	InstrumentID string   `json:"instrument_id"`
+	OrderType    string   `json:"order_type"`
+	Result       string   `json:"result"`
+}
+
+// GetAlgoOrdersRequest request data for listing algo orders, either the status
+// or algo IDs are required
+type GetAlgoOrdersRequest struct {
+	InstrumentID string `url:"instrument_id"`          // [required] Contract IDs are sent in the path
+	OrderType    int64  `url:"order_type"`             // [required] 1: trigger 2: trail 3: iceberg 4: TWAP
+	Status       int64  `url:"status,omitempty"`       // [optional] 1: pending 2: effective 3: cancelled 4: partially effective 5: paused 6: failed
+	AlgoIDs      string `url:"algo_ids,omitempty"`     // [optional] Comma separated, up to 20
+	After        string `url:"after,omitempty"`        // [optional] Pagination of algo IDs before this ID
+	Before       string `url:"before,omitempty"`       // [optional] Pagination of algo IDs after this ID
+	Limit        int64  `url:"limit,string,omitempty"` // [optional] Number of results per request, maximum 100
+}
+
+// AlgoOrderList response data for listing algo orders
+type AlgoOrderList struct {
+	Orders []AlgoOrder `json:"orderStrategyVOS"`
+}
+
+// AlgoOrder holds an algo order and the orders it has placed
+type AlgoOrder struct {
+	AlgoID       string    `json:"algo_id"`
+	InstrumentID string    `json:"instrument_id"`
+	OrderType    int64     `json:"order_type,string"`
+	Status       int64     `json:"status,string"`
+	Side         string    `json:"side"`
+	Type         string    `json:"type"`
+	Size         float64   `json:"size,string"`
+	TriggerPrice string    `json:"trigger_price"`
+	AlgoPrice    string    `json:"algo_price"`
+	CallbackRate string    `json:"callback_rate"`
+	RealAmount   string    `json:"real_amount"`
+	OrderID      string    `json:"order_id"`
+	Timestamp    time.Time `json:"timestamp"`
+}
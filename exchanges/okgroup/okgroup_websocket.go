@@ -363,6 +363,9 @@ func (o *OKGroup) wsProcessTickers(response *WebsocketDataResponse) {
 			HighPrice:  response.Data[i].High24H,
 			LowPrice:   response.Data[i].Low24H,
 			ClosePrice: response.Data[i].Last,
+			Quantity:   response.Data[i].Volume24H,
+			BidPrice:   response.Data[i].BestBid,
+			AskPrice:   response.Data[i].BestAsk,
 			Pair:       instrument,
 		}
 	}
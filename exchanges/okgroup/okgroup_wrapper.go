@@ -5,10 +5,12 @@ import (
 	"strconv"
 	"strings"
 	"sync"
+	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
@@ -218,6 +220,101 @@ func (o *OKGroup) GetExchangeHistory(p currency.Pair, assetType string) ([]excha
 	return nil, common.ErrNotYetImplemented
 }
 
+// GetHistoricCandles returns up to 200 of the most recent spot candles at the
+// interval
+func (o *OKGroup) GetHistoricCandles(p currency.Pair, _ string, interval kline.Interval, limit int) ([]exchange.Candle, error) {
+	if !okGroupCandleIntervals[interval] {
+		return nil, exchange.ErrCandleIntervalUnsupported
+	}
+
+	resp, err := o.GetSpotMarketData(GetSpotMarketDataRequest{
+		InstrumentID: exchange.FormatExchangeCurrency(o.Name, p).String(),
+		Granularity:  interval.Seconds(),
+	})
+	if err != nil {
+		return nil, err
+	}
+	candles, err := convertCandles(resp)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return candles, nil
+}
+
+// GetHistoricCandlesRange returns up to 200 spot candles at the interval
+// opening between start and end
+func (o *OKGroup) GetHistoricCandlesRange(p currency.Pair, _ string, interval kline.Interval, start, end time.Time) ([]exchange.Candle, error) {
+	if !okGroupCandleIntervals[interval] {
+		return nil, exchange.ErrCandleIntervalUnsupported
+	}
+
+	// The newest candles of a range are returned first, the range is limited
+	// to a single page so pages are returned from its start
+	if last := start.Add(interval.Duration() * (maxCandles - 1)); last.Before(end) {
+		end = last
+	}
+	resp, err := o.GetSpotMarketData(GetSpotMarketDataRequest{
+		InstrumentID: exchange.FormatExchangeCurrency(o.Name, p).String(),
+		Granularity:  interval.Seconds(),
+		Start:        start.UTC().Format(time.RFC3339),
+		End:          end.UTC().Format(time.RFC3339),
+	})
+	if err != nil {
+		return nil, err
+	}
+	candles, err := convertCandles(resp)
+	if err != nil {
+		return nil, err
+	}
+	// The end of the range is inclusive
+	for len(candles) > 0 && !candles[len(candles)-1].Time.Before(end) {
+		candles = candles[:len(candles)-1]
+	}
+	return candles, nil
+}
+
+// convertCandles converts market data, returned newest first as arrays of
+// time, open, high, low, close and volume strings, to candles ordered from
+// oldest to newest
+func convertCandles(resp GetSpotMarketDataResponse) ([]exchange.Candle, error) {
+	candles := make([]exchange.Candle, len(resp))
+	for x := range resp {
+		data, ok := resp[x].([]interface{})
+		if !ok || len(data) < 6 {
+			return nil, fmt.Errorf("unexpected candle %v", resp[x])
+		}
+		var fields [6]string
+		for i := range fields {
+			if fields[i], ok = data[i].(string); !ok {
+				return nil, fmt.Errorf("unexpected candle %v", resp[x])
+			}
+		}
+		t, err := time.Parse(time.RFC3339, fields[0])
+		if err != nil {
+			return nil, err
+		}
+		var values [5]float64
+		for i := range values {
+			values[i], err = strconv.ParseFloat(fields[i+1], 64)
+			if err != nil {
+				return nil, err
+			}
+		}
+		candles[len(resp)-1-x] = exchange.Candle{
+			Time:   t,
+			Open:   values[0],
+			High:   values[1],
+			Low:    values[2],
+			Close:  values[3],
+			Volume: values[4],
+		}
+	}
+	return candles, nil
+}
+
 // SubmitOrder submits a new order
 func (o *OKGroup) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (resp exchange.SubmitOrderResponse, err error) {
 	request := PlaceSpotOrderRequest{
@@ -241,6 +338,66 @@ func (o *OKGroup) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderTyp
 	return
 }
 
+// MaxBatchOrders returns the number of orders sent per batch order request,
+// which keeps every batch within the limit of four pairs and four orders per
+// pair
+func (o *OKGroup) MaxBatchOrders() int {
+	return okGroupMaxBatchOrders
+}
+
+// SubmitBatchOrders submits orders through the batch order endpoint
+func (o *OKGroup) SubmitBatchOrders(orders []exchange.OrderSubmission) ([]exchange.BatchOrderResult, error) {
+	if len(orders) > okGroupMaxBatchOrders {
+		return nil, fmt.Errorf("maximum of %d orders per batch", okGroupMaxBatchOrders)
+	}
+
+	request := make([]PlaceSpotOrderRequest, len(orders))
+	for i := range orders {
+		request[i] = PlaceSpotOrderRequest{
+			ClientOID:    orders[i].ClientID,
+			InstrumentID: exchange.FormatExchangeCurrency(o.Name, orders[i].Pair).String(),
+			Side:         strings.ToLower(orders[i].Side.ToString()),
+			Type:         strings.ToLower(orders[i].OrderType.ToString()),
+			Size:         strconv.FormatFloat(orders[i].Amount, 'f', -1, 64),
+		}
+		if orders[i].OrderType == exchange.LimitOrderType {
+			request[i].Price = strconv.FormatFloat(orders[i].Price, 'f', -1, 64)
+		}
+	}
+
+	resp, errs := o.PlaceMultipleSpotOrders(request)
+	if len(resp) == 0 && len(errs) > 0 {
+		return nil, errs[0]
+	}
+
+	// Responses are grouped by instrument in the order they were requested
+	results := make([]exchange.BatchOrderResult, len(orders))
+	used := make(map[string]int)
+	for i := range request {
+		var instrumentResp []PlaceSpotOrderResponse
+		for instrument := range resp {
+			if strings.EqualFold(instrument, request[i].InstrumentID) {
+				instrumentResp = resp[instrument]
+				break
+			}
+		}
+		x := used[request[i].InstrumentID]
+		used[request[i].InstrumentID]++
+		if x >= len(instrumentResp) {
+			results[i].Err = fmt.Errorf("no batch order response for %s",
+				request[i].InstrumentID)
+			continue
+		}
+		results[i].OrderID = instrumentResp[x].OrderID
+		results[i].IsOrderPlaced = instrumentResp[x].Result
+		if !instrumentResp[x].Result {
+			results[i].Err = fmt.Errorf("order for currency %v failed to be placed",
+				request[i].InstrumentID)
+		}
+	}
+	return results, nil
+}
+
 // ModifyOrder will allow of changing orderbook placement and limit to
 // market conversion
 func (o *OKGroup) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
@@ -456,3 +613,33 @@ func (o *OKGroup) GetSubscriptions() ([]wshandler.WebsocketChannelSubscription,
 func (o *OKGroup) AuthenticateWebsocket() error {
 	return o.WsLogin()
 }
+
+// GetBorrowRates returns the daily margin loan rate for the currency on each
+// margin market it can be borrowed on
+func (o *OKGroup) GetBorrowRates(c currency.Code) ([]exchange.BorrowRate, error) {
+	settings, err := o.GetMarginAccountSettings("")
+	if err != nil {
+		return nil, err
+	}
+
+	var rates []exchange.BorrowRate
+	for i := range settings {
+		info, ok := settings[i].Currencies[c.Upper().String()]
+		if !ok {
+			continue
+		}
+		rates = append(rates, exchange.BorrowRate{
+			Exchange:  o.Name,
+			Currency:  c,
+			Market:    settings[i].InstrumentID,
+			Rate:      info.Rate,
+			Period:    time.Hour * 24,
+			Available: info.Available,
+			Timestamp: time.Now(),
+		})
+	}
+	if len(rates) == 0 {
+		return nil, fmt.Errorf("%s %s cannot be borrowed", o.Name, c)
+	}
+	return rates, nil
+}
@@ -0,0 +1,145 @@
+package orderbook
+
+import (
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// DefaultDepthLevels is the number of levels per side returned in a depth
+// snapshot when not set
+const DefaultDepthLevels = 20
+
+var errNoOrderbooks = errors.New("no orderbooks to consolidate")
+
+// DepthLevel holds the amount resting at a price. Consolidated levels hold the
+// amount resting on each exchange
+type DepthLevel struct {
+	Price     float64            `json:"price"`
+	Amount    float64            `json:"amount"`
+	Exchanges map[string]float64 `json:"exchanges,omitempty"`
+}
+
+// Depth is a snapshot of the best levels of one exchange's orderbook or the
+// orderbooks of several exchanges consolidated by price
+type Depth struct {
+	Pair      currency.Pair `json:"pair"`
+	AssetType string        `json:"assetType"`
+	Exchanges []string      `json:"exchanges"`
+	Bids      []DepthLevel  `json:"bids"`
+	Asks      []DepthLevel  `json:"asks"`
+	// LastUpdated is the update time of the oldest orderbook in the
+	// snapshot
+	LastUpdated time.Time `json:"lastUpdated"`
+	// Crossed is set when a consolidated bid is at or above a consolidated
+	// ask, which happens when exchanges' prices diverge
+	Crossed bool `json:"crossed,omitempty"`
+}
+
+// Depth returns the best levels of each side of the orderbook, every level
+// when levels is not positive
+func (o *Base) Depth(levels int) Depth {
+	d := Depth{
+		Pair:        o.Pair,
+		AssetType:   o.AssetType,
+		Exchanges:   []string{o.ExchangeName},
+		Bids:        depthLevels(o.Bids, levels),
+		Asks:        depthLevels(o.Asks, levels),
+		LastUpdated: o.LastUpdated,
+	}
+	d.Crossed = len(d.Bids) > 0 && len(d.Asks) > 0 && d.Bids[0].Price >= d.Asks[0].Price
+	return d
+}
+
+// Imbalance returns the order book imbalance over the best levels of each
+// side, every level when levels is not positive. It ranges from -1 when only
+// asks rest to 1 when only bids rest, a positive imbalance indicating buying
+// pressure. An empty book has no imbalance
+func (o *Base) Imbalance(levels int) float64 {
+	var bids, asks float64
+	for i := range o.Bids {
+		if levels > 0 && i >= levels {
+			break
+		}
+		bids += o.Bids[i].Amount
+	}
+	for i := range o.Asks {
+		if levels > 0 && i >= levels {
+			break
+		}
+		asks += o.Asks[i].Amount
+	}
+	if bids+asks == 0 {
+		return 0
+	}
+	return (bids - asks) / (bids + asks)
+}
+
+func depthLevels(items []Item, levels int) []DepthLevel {
+	if levels > 0 && len(items) > levels {
+		items = items[:levels]
+	}
+	resp := make([]DepthLevel, len(items))
+	for i := range items {
+		resp[i] = DepthLevel{Price: items[i].Price, Amount: items[i].Amount}
+	}
+	return resp
+}
+
+// Consolidate merges the orderbooks of several exchanges by price and returns
+// the best levels of each side, every level when levels is not positive. The
+// orderbooks are expected to be for the same pair and asset type
+func Consolidate(books []Base, levels int) (Depth, error) {
+	if len(books) == 0 {
+		return Depth{}, errNoOrderbooks
+	}
+	d := Depth{
+		Pair:        books[0].Pair,
+		AssetType:   books[0].AssetType,
+		LastUpdated: books[0].LastUpdated,
+	}
+	bids := make(map[float64]*DepthLevel)
+	asks := make(map[float64]*DepthLevel)
+	for i := range books {
+		d.Exchanges = append(d.Exchanges, books[i].ExchangeName)
+		if books[i].LastUpdated.Before(d.LastUpdated) {
+			d.LastUpdated = books[i].LastUpdated
+		}
+		mergeLevels(bids, books[i].ExchangeName, books[i].Bids)
+		mergeLevels(asks, books[i].ExchangeName, books[i].Asks)
+	}
+	sort.Strings(d.Exchanges)
+	d.Bids = sortedLevels(bids, levels, func(a, b float64) bool { return a > b })
+	d.Asks = sortedLevels(asks, levels, func(a, b float64) bool { return a < b })
+	d.Crossed = len(d.Bids) > 0 && len(d.Asks) > 0 && d.Bids[0].Price >= d.Asks[0].Price
+	return d, nil
+}
+
+func mergeLevels(levels map[float64]*DepthLevel, exchangeName string, items []Item) {
+	for i := range items {
+		if items[i].Amount <= 0 {
+			continue
+		}
+		l, ok := levels[items[i].Price]
+		if !ok {
+			l = &DepthLevel{Price: items[i].Price, Exchanges: make(map[string]float64)}
+			levels[items[i].Price] = l
+		}
+		l.Amount += items[i].Amount
+		l.Exchanges[exchangeName] += items[i].Amount
+	}
+}
+
+func sortedLevels(levels map[float64]*DepthLevel, limit int, better func(a, b float64) bool) []DepthLevel {
+	resp := make([]DepthLevel, 0, len(levels))
+	for _, l := range levels {
+		resp = append(resp, *l)
+	}
+	sort.Slice(resp, func(i, j int) bool { return better(resp[i].Price, resp[j].Price) })
+	if limit > 0 && len(resp) > limit {
+		resp = resp[:limit]
+	}
+	return resp
+}
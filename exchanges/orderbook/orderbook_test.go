@@ -386,3 +386,83 @@ func TestProcessOrderbook(t *testing.T) {
 
 	wg.Wait()
 }
+
+func TestDepth(t *testing.T) {
+	t.Parallel()
+	base := Base{
+		Pair:         currency.NewPairFromStrings("BTC", "USD"),
+		Bids:         []Item{{Price: 100, Amount: 1}, {Price: 99, Amount: 2}, {Price: 98, Amount: 3}},
+		Asks:         []Item{{Price: 101, Amount: 1}},
+		AssetType:    Spot,
+		ExchangeName: "Bitstamp",
+	}
+	d := base.Depth(2)
+	if len(d.Bids) != 2 || d.Bids[1].Price != 99 || len(d.Asks) != 1 || d.Crossed {
+		t.Errorf("Test failed. Depth() unexpected snapshot %+v", d)
+	}
+	if d = base.Depth(0); len(d.Bids) != 3 {
+		t.Errorf("Test failed. Depth() expected every level, got %d", len(d.Bids))
+	}
+}
+
+func TestImbalance(t *testing.T) {
+	t.Parallel()
+	base := Base{
+		Bids: []Item{{Price: 100, Amount: 3}, {Price: 99, Amount: 5}},
+		Asks: []Item{{Price: 101, Amount: 1}, {Price: 102, Amount: 1}},
+	}
+	if i := base.Imbalance(1); i != 0.5 {
+		t.Errorf("Test failed. Imbalance() expected 0.5 over the best level, got %v", i)
+	}
+	if i := base.Imbalance(0); i != 0.6 {
+		t.Errorf("Test failed. Imbalance() expected 0.6 over every level, got %v", i)
+	}
+	if i := (&Base{}).Imbalance(5); i != 0 {
+		t.Errorf("Test failed. Imbalance() expected an empty book balanced, got %v", i)
+	}
+}
+
+func TestConsolidate(t *testing.T) {
+	t.Parallel()
+	if _, err := Consolidate(nil, 10); err != errNoOrderbooks {
+		t.Error("Test failed. Consolidate() expected no orderbooks error", err)
+	}
+
+	p := currency.NewPairFromStrings("BTC", "USD")
+	now := time.Now()
+	books := []Base{
+		{
+			Pair:         p,
+			Bids:         []Item{{Price: 100, Amount: 1}, {Price: 99, Amount: 2}},
+			Asks:         []Item{{Price: 101, Amount: 1}, {Price: 102, Amount: 2}},
+			AssetType:    Spot,
+			ExchangeName: "Kraken",
+			LastUpdated:  now,
+		},
+		{
+			Pair:         p,
+			Bids:         []Item{{Price: 100, Amount: 3}, {Price: 98, Amount: 1}},
+			Asks:         []Item{{Price: 100, Amount: 1}, {Price: 101, Amount: 0}},
+			AssetType:    Spot,
+			ExchangeName: "Bitstamp",
+			LastUpdated:  now.Add(-time.Second),
+		},
+	}
+	d, err := Consolidate(books, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Exchanges) != 2 || d.Exchanges[0] != "Bitstamp" || !d.LastUpdated.Equal(now.Add(-time.Second)) {
+		t.Errorf("Test failed. Consolidate() unexpected snapshot %+v", d)
+	}
+	if len(d.Bids) != 2 || d.Bids[0].Price != 100 || d.Bids[0].Amount != 4 ||
+		d.Bids[0].Exchanges["Kraken"] != 1 || d.Bids[0].Exchanges["Bitstamp"] != 3 || d.Bids[1].Price != 99 {
+		t.Errorf("Test failed. Consolidate() unexpected bids %+v", d.Bids)
+	}
+	if len(d.Asks) != 2 || d.Asks[0].Price != 100 || d.Asks[1].Price != 101 || d.Asks[1].Amount != 1 {
+		t.Errorf("Test failed. Consolidate() unexpected asks %+v", d.Asks)
+	}
+	if !d.Crossed {
+		t.Error("Test failed. Consolidate() expected crossed book")
+	}
+}
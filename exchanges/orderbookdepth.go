@@ -0,0 +1,11 @@
+package exchange
+
+import "github.com/thrasher-corp/gocryptotrader/currency"
+
+// OrderbookDepthSetter is implemented by exchanges whose websocket orderbook
+// depth can be set per pair
+type OrderbookDepthSetter interface {
+	// SetOrderbookDepth sets the number of levels each side streamed for the
+	// pair, resubscribing its book if already subscribed
+	SetOrderbookDepth(p currency.Pair, depth int) error
+}
@@ -0,0 +1,543 @@
+// Package ordermanager tracks the bot's orders across every exchange in one
+// place. Orders submitted through tracked exchanges are held as a common
+// Order whatever order types and status strings the exchange reports, and
+// open orders are reconciled with each exchange's active orders on a schedule
+// so fills, cancellations and orders placed outside the bot are picked up
+package ordermanager
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default manager settings
+const (
+	DefaultCheckInterval = time.Minute
+	// DefaultRetention is how long closed orders are kept
+	DefaultRetention = time.Hour * 24
+)
+
+// Event types emitted by the manager
+const (
+	Placed     = "ORDER_PLACED"
+	Discovered = "ORDER_DISCOVERED"
+	Updated    = "ORDER_UPDATED"
+	Closed     = "ORDER_CLOSED"
+)
+
+var errNoExchangeFunc = errors.New("order manager no exchange func supplied")
+
+// Order is an order normalised across exchanges
+type Order struct {
+	Exchange  string             `json:"exchange"`
+	ID        string             `json:"id"`
+	ClientID  string             `json:"clientID,omitempty"`
+	Pair      currency.Pair      `json:"pair"`
+	Side      exchange.OrderSide `json:"side"`
+	Type      exchange.OrderType `json:"type"`
+	Price     float64            `json:"price"`
+	Amount    float64            `json:"amount"`
+	Filled    float64            `json:"filled"`
+	Remaining float64            `json:"remaining"`
+	Fee       float64            `json:"fee"`
+	// Status is normalised from ExchangeStatus, the status as reported
+	Status         exchange.OrderStatus `json:"status"`
+	ExchangeStatus string               `json:"exchangeStatus,omitempty"`
+	// External is set on orders found when reconciling rather than submitted
+	// through the bot
+	External bool      `json:"external,omitempty"`
+	Placed   time.Time `json:"placed"`
+	Updated  time.Time `json:"updated"`
+	Closed   time.Time `json:"closed,omitempty"`
+}
+
+// Open returns whether the order can still fill
+func (o *Order) Open() bool {
+	return o.Closed.IsZero()
+}
+
+// Event defines an order placed, discovered, updated or closed
+type Event struct {
+	Type  string
+	Order Order
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	return fmt.Sprintf("%s %s %s order %s %s %v @ %v %s, filled %v", e.Type, e.Order.Exchange, e.Order.Pair,
+		e.Order.ID, e.Order.Side, e.Order.Amount, e.Order.Price, e.Order.Status, e.Order.Filled)
+}
+
+// Filter selects orders, empty criteria match every order
+type Filter struct {
+	Exchange string
+	Pair     currency.Pair
+	Side     exchange.OrderSide
+	Status   exchange.OrderStatus
+	// OpenOnly excludes closed orders
+	OpenOnly bool
+}
+
+// Match returns whether the order meets every criteria of the filter
+func (f *Filter) Match(o *Order) bool {
+	if f == nil {
+		return true
+	}
+	if f.Exchange != "" && !strings.EqualFold(f.Exchange, o.Exchange) {
+		return false
+	}
+	if !f.Pair.IsEmpty() && !f.Pair.EqualIncludeReciprocal(o.Pair) {
+		return false
+	}
+	if f.Side != "" && f.Side != exchange.AnyOrderSide && normaliseSide(f.Side) != o.Side {
+		return false
+	}
+	if f.Status != "" && f.Status != exchange.AnyOrderStatus && f.Status != o.Status {
+		return false
+	}
+	return !f.OpenOnly || o.Open()
+}
+
+// Reconciliation holds the result of an exchange's latest reconciliation
+type Reconciliation struct {
+	Exchange string    `json:"exchange"`
+	Time     time.Time `json:"time"`
+	Open     int       `json:"open"`
+	// Unresolved counts tracked orders missing from the active orders whose
+	// final status could not be fetched, they are retried next time
+	Unresolved int    `json:"unresolved"`
+	Error      string `json:"error,omitempty"`
+}
+
+// ExchangeFunc returns the named exchange orders are reconciled and cancelled
+// through
+type ExchangeFunc func(name string) exchange.IBotExchange
+
+// Manager tracks orders across exchanges
+type Manager struct {
+	exch     ExchangeFunc
+	onEvent  func(Event)
+	tracked  map[string]exchange.IBotExchange
+	orders   map[string]*Order
+	statuses map[string]Reconciliation
+	now      func() time.Time
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+	checkMtx sync.Mutex
+}
+
+// New returns a manager reconciling and cancelling orders through the
+// exchanges returned by exch
+func New(exch ExchangeFunc, onEvent func(Event)) (*Manager, error) {
+	if exch == nil {
+		return nil, errNoExchangeFunc
+	}
+	return &Manager{
+		exch:     exch,
+		onEvent:  onEvent,
+		tracked:  make(map[string]exchange.IBotExchange),
+		orders:   make(map[string]*Order),
+		statuses: make(map[string]Reconciliation),
+		now:      time.Now,
+	}, nil
+}
+
+func key(exchName, id string) string {
+	return strings.ToLower(exchName) + " " + id
+}
+
+// lookup returns the named exchange, falling back to the tracked exchange
+func (m *Manager) lookup(name string) exchange.IBotExchange {
+	if e := m.exch(name); e != nil {
+		return e
+	}
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.tracked[strings.ToLower(name)]
+}
+
+// emit passes the events to the handler
+func (m *Manager) emit(events []Event) {
+	for i := range events {
+		log.Debugln(events[i].String())
+		if m.onEvent != nil {
+			m.onEvent(events[i])
+		}
+	}
+}
+
+// placed records an order submitted through a tracked exchange
+func (m *Manager) placed(o *Order) {
+	m.mtx.Lock()
+	m.orders[key(o.Exchange, o.ID)] = o
+	e := Event{Type: Placed, Order: *o}
+	m.mtx.Unlock()
+	m.emit([]Event{e})
+}
+
+// cancelled closes an open order as cancelled
+func (m *Manager) cancelled(exchName string, ids ...string) {
+	var events []Event
+	now := m.now()
+	m.mtx.Lock()
+	for i := range ids {
+		o, ok := m.orders[key(exchName, ids[i])]
+		if !ok || !o.Open() {
+			continue
+		}
+		o.Status = exchange.CancelledOrderStatus
+		o.Updated = now
+		o.Closed = now
+		events = append(events, Event{Type: Closed, Order: *o})
+	}
+	m.mtx.Unlock()
+	m.emit(events)
+}
+
+// observe updates known orders from the orders fetched from the exchange.
+// Active orders not yet known are added and those assumed closed are reopened
+func (m *Manager) observe(exchName string, orders []exchange.OrderDetail, active bool) {
+	var events []Event
+	now := m.now()
+	m.mtx.Lock()
+	for i := range orders {
+		if orders[i].ID == "" {
+			continue
+		}
+		k := key(exchName, orders[i].ID)
+		o, ok := m.orders[k]
+		if !ok {
+			if !active {
+				continue
+			}
+			o = &Order{
+				Exchange: exchName,
+				ID:       orders[i].ID,
+				External: true,
+				Placed:   orders[i].OrderDate,
+			}
+			if o.Placed.IsZero() {
+				o.Placed = now
+			}
+			m.orders[k] = o
+		}
+		if update(o, &orders[i], active, now) || !ok {
+			events = append(events, m.event(o, ok))
+		}
+	}
+	m.mtx.Unlock()
+	m.emit(events)
+}
+
+// event returns the event for an order changed, the caller must hold the lock
+func (m *Manager) event(o *Order, known bool) Event {
+	switch {
+	case !known:
+		return Event{Type: Discovered, Order: *o}
+	case !o.Open():
+		return Event{Type: Closed, Order: *o}
+	}
+	return Event{Type: Updated, Order: *o}
+}
+
+// update applies the exchange's view of the order, returning whether it
+// changed. Missing fields leave the order's values unchanged
+func update(o *Order, d *exchange.OrderDetail, active bool, now time.Time) bool {
+	before := *o
+	if !d.CurrencyPair.IsEmpty() {
+		o.Pair = d.CurrencyPair
+	}
+	if d.OrderSide != "" {
+		o.Side = normaliseSide(d.OrderSide)
+	}
+	if d.OrderType != "" {
+		o.Type = exchange.OrderType(strings.ToUpper(string(d.OrderType)))
+	}
+	if d.Price > 0 {
+		o.Price = d.Price
+	}
+	if d.Amount > 0 {
+		o.Amount = d.Amount
+	}
+	if d.ExecutedAmount > o.Filled {
+		o.Filled = d.ExecutedAmount
+	}
+	if d.Fee > o.Fee {
+		o.Fee = d.Fee
+	}
+	o.Remaining = d.RemainingAmount
+	if o.Remaining <= 0 && o.Amount > o.Filled {
+		o.Remaining = o.Amount - o.Filled
+	}
+	if d.Status != "" {
+		o.ExchangeStatus = d.Status
+	}
+	o.Status = exchange.NormaliseStatus(d.Status, o.Filled, o.Amount)
+	switch {
+	case o.Open() && o.Status.Closed():
+		o.Closed = now
+	case !o.Open() && !o.Status.Closed() && (active || d.Status != ""):
+		// Orders assumed cancelled are reopened when still reported open
+		o.Closed = time.Time{}
+	}
+
+	changed := o.Pair != before.Pair || o.Side != before.Side || o.Type != before.Type ||
+		o.Price != before.Price || o.Amount != before.Amount || o.Filled != before.Filled ||
+		o.Fee != before.Fee || o.Status != before.Status || o.Closed != before.Closed
+	if changed {
+		o.Updated = now
+	}
+	return changed
+}
+
+// Check reconciles every tracked exchange's open orders
+func (m *Manager) Check() {
+	m.checkMtx.Lock()
+	defer m.checkMtx.Unlock()
+	m.mtx.Lock()
+	var names []string
+	for name := range m.tracked {
+		names = append(names, name)
+	}
+	m.mtx.Unlock()
+	sort.Strings(names)
+
+	for i := range names {
+		e := m.lookup(names[i])
+		if e == nil {
+			continue
+		}
+		status := m.reconcile(e)
+		if status.Error != "" {
+			log.Warnf("Order manager %s reconciliation failed: %s", status.Exchange, status.Error)
+		}
+		m.mtx.Lock()
+		m.statuses[names[i]] = status
+		m.mtx.Unlock()
+	}
+	m.prune()
+}
+
+// reconcile updates the exchange's orders from its active orders. Open
+// orders it no longer reports are fetched for their final status, orders the
+// exchange cannot fetch are closed with an unknown status
+func (m *Manager) reconcile(e exchange.IBotExchange) Reconciliation {
+	start := m.now()
+	name := e.GetName()
+	status := Reconciliation{Exchange: name, Time: start}
+	active, err := e.GetActiveOrders(&exchange.GetOrdersRequest{
+		Currencies: e.GetEnabledCurrencies(),
+	})
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	m.observe(name, active, true)
+
+	reported := make(map[string]bool)
+	for i := range active {
+		reported[active[i].ID] = true
+	}
+	var missing []string
+	m.mtx.Lock()
+	for _, o := range m.orders {
+		if !o.Open() || !strings.EqualFold(o.Exchange, name) || reported[o.ID] {
+			continue
+		}
+		// Orders placed during the request may not have been reported yet
+		if o.Placed.Before(start) {
+			missing = append(missing, o.ID)
+		}
+	}
+	m.mtx.Unlock()
+
+	for _, id := range missing {
+		detail, err := e.GetOrderInfo(id)
+		switch {
+		case err == common.ErrNotYetImplemented || err == common.ErrFunctionNotSupported:
+			m.unknown(name, id)
+		case err != nil:
+			status.Unresolved++
+			log.Warnf("Order manager %s order %s status failed: %s", name, id, err)
+		default:
+			// Orders still reported open by ID, such as on a pair no longer
+			// enabled, are left open
+			detail.ID = id
+			m.observe(name, []exchange.OrderDetail{detail}, false)
+		}
+	}
+
+	m.mtx.Lock()
+	for _, o := range m.orders {
+		if o.Open() && strings.EqualFold(o.Exchange, name) {
+			status.Open++
+		}
+	}
+	m.mtx.Unlock()
+	return status
+}
+
+// unknown closes an order no longer active whose final status the exchange
+// cannot report
+func (m *Manager) unknown(exchName, id string) {
+	now := m.now()
+	m.mtx.Lock()
+	o, ok := m.orders[key(exchName, id)]
+	if !ok || !o.Open() {
+		m.mtx.Unlock()
+		return
+	}
+	o.Status = exchange.UnknownOrderStatus
+	o.Updated = now
+	o.Closed = now
+	e := Event{Type: Closed, Order: *o}
+	m.mtx.Unlock()
+	m.emit([]Event{e})
+}
+
+// prune forgets orders closed longer than the retention
+func (m *Manager) prune() {
+	cutoff := m.now().Add(-DefaultRetention)
+	m.mtx.Lock()
+	for k, o := range m.orders {
+		if !o.Open() && o.Closed.Before(cutoff) {
+			delete(m.orders, k)
+		}
+	}
+	m.mtx.Unlock()
+}
+
+// List returns the orders matching the filter, oldest first
+func (m *Manager) List(f *Filter) []Order {
+	m.mtx.Lock()
+	var resp []Order
+	for _, o := range m.orders {
+		if f.Match(o) {
+			resp = append(resp, *o)
+		}
+	}
+	m.mtx.Unlock()
+	sort.Slice(resp, func(i, j int) bool {
+		if !resp[i].Placed.Equal(resp[j].Placed) {
+			return resp[i].Placed.Before(resp[j].Placed)
+		}
+		if resp[i].Exchange != resp[j].Exchange {
+			return resp[i].Exchange < resp[j].Exchange
+		}
+		return resp[i].ID < resp[j].ID
+	})
+	return resp
+}
+
+// GetReconciliations returns every exchange's latest reconciliation
+func (m *Manager) GetReconciliations() []Reconciliation {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	var resp []Reconciliation
+	for _, s := range m.statuses {
+		resp = append(resp, s)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Exchange < resp[j].Exchange
+	})
+	return resp
+}
+
+// CancelAll cancels every open order matching the filter, returning a result
+// for each exchange with matching orders. Orders which filled before they
+// could be cancelled are updated when next reconciled
+func (m *Manager) CancelAll(f *Filter) []exchange.CancelOrdersResult {
+	cf := Filter{OpenOnly: true}
+	if f != nil {
+		cf = *f
+		cf.OpenOnly = true
+	}
+	orders := m.List(&cf)
+
+	results := make(map[string]*exchange.CancelOrdersResult)
+	var names []string
+	for i := range orders {
+		name := orders[i].Exchange
+		r, ok := results[strings.ToLower(name)]
+		if !ok {
+			r = &exchange.CancelOrdersResult{Exchange: name, Failed: make(map[string]string)}
+			results[strings.ToLower(name)] = r
+			names = append(names, strings.ToLower(name))
+		}
+		e := m.lookup(name)
+		if e == nil {
+			r.Failed[orders[i].ID] = "exchange not loaded"
+			continue
+		}
+		err := e.CancelOrder(&exchange.OrderCancellation{
+			OrderID:      orders[i].ID,
+			Side:         orders[i].Side,
+			CurrencyPair: orders[i].Pair,
+		})
+		if err != nil {
+			r.Failed[orders[i].ID] = err.Error()
+			continue
+		}
+		r.Cancelled = append(r.Cancelled, orders[i].ID)
+		m.cancelled(name, orders[i].ID)
+	}
+
+	var resp []exchange.CancelOrdersResult
+	for i := range names {
+		resp = append(resp, *results[names[i]])
+	}
+	return resp
+}
+
+// Start reconciles open orders at the interval until stopped
+func (m *Manager) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			m.Check()
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops reconciling
+func (m *Manager) Stop() {
+	m.mtx.Lock()
+	if m.shutdown == nil {
+		m.mtx.Unlock()
+		return
+	}
+	close(m.shutdown)
+	m.shutdown = nil
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
@@ -0,0 +1,193 @@
+package ordermanager
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	name    string
+	active  map[string]*exchange.OrderDetail
+	history map[string]*exchange.OrderDetail
+	infoErr error
+	nextID  int
+}
+
+func newTestExchange(name string) *testExchange {
+	return &testExchange{
+		name:    name,
+		active:  make(map[string]*exchange.OrderDetail),
+		history: make(map[string]*exchange.OrderDetail),
+	}
+}
+
+func (t *testExchange) GetName() string { return t.name }
+
+func (t *testExchange) GetEnabledCurrencies() currency.Pairs { return nil }
+
+func (t *testExchange) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.nextID++
+	id := fmt.Sprintf("%d", t.nextID)
+	t.active[id] = &exchange.OrderDetail{
+		ID:           id,
+		CurrencyPair: p,
+		OrderSide:    side,
+		OrderType:    orderType,
+		Price:        price,
+		Amount:       amount,
+		Status:       "open",
+	}
+	return exchange.SubmitOrderResponse{OrderID: id, IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) CancelOrder(o *exchange.OrderCancellation) error {
+	d, ok := t.active[o.OrderID]
+	if !ok {
+		return errors.New("order not found")
+	}
+	delete(t.active, o.OrderID)
+	d.Status = "canceled"
+	t.history[o.OrderID] = d
+	return nil
+}
+
+func (t *testExchange) CancelAllOrders(_ *exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error) {
+	return exchange.CancelAllOrdersResponse{}, nil
+}
+
+func (t *testExchange) GetActiveOrders(_ *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	var orders []exchange.OrderDetail
+	for _, o := range t.active {
+		orders = append(orders, *o)
+	}
+	return orders, nil
+}
+
+func (t *testExchange) GetOrderInfo(id string) (exchange.OrderDetail, error) {
+	if t.infoErr != nil {
+		return exchange.OrderDetail{}, t.infoErr
+	}
+	if o, ok := t.history[id]; ok {
+		return *o, nil
+	}
+	if o, ok := t.active[id]; ok {
+		return *o, nil
+	}
+	return exchange.OrderDetail{}, errors.New("order not found")
+}
+
+func TestManager(t *testing.T) {
+	kraken, huobi := newTestExchange("Kraken"), newTestExchange("Huobi")
+	exchanges := make(map[string]exchange.IBotExchange)
+	var events []Event
+	m, err := New(func(name string) exchange.IBotExchange { return exchanges[name] },
+		func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	exchanges["kraken"] = m.Track(kraken)
+	exchanges["huobi"] = m.Track(huobi)
+	m.now = func() time.Time { return time.Now().Add(-time.Second) }
+
+	btc := currency.NewPairWithDelimiter("BTC", "USD", "-")
+	for _, e := range []exchange.IBotExchange{exchanges["kraken"], exchanges["huobi"]} {
+		for _, side := range []exchange.OrderSide{exchange.BuyOrderSide, exchange.AskOrderSide} {
+			if _, err = e.SubmitOrder(btc, side, exchange.LimitOrderType, 1, 100, ""); err != nil {
+				t.Fatal(err)
+			}
+		}
+	}
+	if orders := m.List(&Filter{Side: exchange.SellOrderSide}); len(orders) != 2 || orders[0].Side != exchange.SellOrderSide {
+		t.Fatalf("Test Failed - List() expected 2 normalised sell orders %+v", orders)
+	}
+	m.now = time.Now
+
+	// Kraken's order 1 partially fills, order 2 is cancelled elsewhere and an
+	// order placed outside the bot appears
+	kraken.active["1"].ExecutedAmount = 0.4
+	kraken.active["1"].Status = "partial-filled"
+	kraken.history["2"] = kraken.active["2"]
+	kraken.history["2"].Status = "canceled"
+	delete(kraken.active, "2")
+	kraken.active["x"] = &exchange.OrderDetail{ID: "x", CurrencyPair: btc, OrderSide: exchange.BidOrderSide, Amount: 3, Price: 90}
+	// Huobi cannot report orders by ID, its missing order closes as unknown
+	delete(huobi.active, "2")
+	huobi.infoErr = common.ErrNotYetImplemented
+	events = nil
+	m.Check()
+
+	k := m.List(&Filter{Exchange: "kraken"})
+	if len(k) != 3 {
+		t.Fatalf("Test Failed - Check() expected 3 kraken orders %+v", k)
+	}
+	if k[0].Status != exchange.PartiallyFilledOrderStatus || k[0].Filled != 0.4 || k[0].Remaining != 0.6 || !k[0].Open() {
+		t.Errorf("Test Failed - Check() expected a partial fill %+v", k[0])
+	}
+	if k[1].Status != exchange.CancelledOrderStatus || k[1].Open() {
+		t.Errorf("Test Failed - Check() expected a cancelled order %+v", k[1])
+	}
+	if !k[2].External || k[2].Side != exchange.BuyOrderSide || k[2].Status != exchange.ActiveOrderStatus {
+		t.Errorf("Test Failed - Check() expected an external order discovered %+v", k[2])
+	}
+	h := m.List(&Filter{Exchange: "huobi", Status: exchange.UnknownOrderStatus})
+	if len(h) != 1 || h[0].ID != "2" || h[0].Open() {
+		t.Errorf("Test Failed - Check() expected huobi order closed as unknown %+v", h)
+	}
+	if len(events) != 4 {
+		t.Errorf("Test Failed - Check() expected 4 events, received %d", len(events))
+	}
+	r := m.GetReconciliations()
+	if len(r) != 2 || r[0].Exchange != "Huobi" || r[0].Open != 1 || r[1].Open != 2 {
+		t.Errorf("Test Failed - GetReconciliations() unexpected %+v", r)
+	}
+
+	// A second reconciliation finds nothing changed
+	events = nil
+	m.Check()
+	if len(events) != 0 {
+		t.Errorf("Test Failed - Check() expected no events %+v", events)
+	}
+
+	results := m.CancelAll(&Filter{Pair: btc})
+	if len(results) != 2 || len(results[0].Cancelled)+len(results[1].Cancelled) != 3 {
+		t.Fatalf("Test Failed - CancelAll() unexpected results %+v", results)
+	}
+	if open := m.List(&Filter{OpenOnly: true}); len(open) != 0 || len(kraken.active) != 0 {
+		t.Errorf("Test Failed - CancelAll() expected every order cancelled %+v", open)
+	}
+}
+
+func TestTrackedCancelAll(t *testing.T) {
+	exch := newTestExchange("test")
+	m, err := New(func(string) exchange.IBotExchange { return nil }, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	e := m.Track(exch)
+	btc := currency.NewPairWithDelimiter("BTC", "USD", "-")
+	if _, err = e.SubmitOrder(btc, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 100, "c1"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = e.SubmitOrder(btc, exchange.SellOrderSide, exchange.LimitOrderType, 1, 110, ""); err != nil {
+		t.Fatal(err)
+	}
+	if _, err = e.CancelAllOrders(&exchange.OrderCancellation{CurrencyPair: btc}); err != nil {
+		t.Fatal(err)
+	}
+	// The embedded exchange cancels nothing, so its orders are reopened
+	if open := m.List(&Filter{OpenOnly: true}); len(open) != 0 {
+		t.Fatalf("Test Failed - CancelAllOrders() expected orders closed %+v", open)
+	}
+	m.Check()
+	open := m.List(&Filter{OpenOnly: true})
+	if len(open) != 2 || open[0].ClientID != "c1" {
+		t.Errorf("Test Failed - Check() expected orders still active reopened %+v", open)
+	}
+}
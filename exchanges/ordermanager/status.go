@@ -0,0 +1,19 @@
+package ordermanager
+
+import (
+	"strings"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// normaliseSide maps bids to buys and asks to sells
+func normaliseSide(s exchange.OrderSide) exchange.OrderSide {
+	side := exchange.OrderSide(strings.ToUpper(string(s)))
+	switch side {
+	case exchange.BidOrderSide:
+		return exchange.BuyOrderSide
+	case exchange.AskOrderSide:
+		return exchange.SellOrderSide
+	}
+	return side
+}
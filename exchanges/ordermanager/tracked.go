@@ -0,0 +1,150 @@
+package ordermanager
+
+import (
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// Track wraps an exchange so the orders submitted through it are tracked and
+// its open orders are reconciled
+func (m *Manager) Track(e exchange.IBotExchange) exchange.IBotExchange {
+	t := &Tracked{IBotExchange: e, manager: m}
+	m.mtx.Lock()
+	m.tracked[strings.ToLower(e.GetName())] = t
+	m.mtx.Unlock()
+	return t
+}
+
+// Tracked is an exchange whose orders are tracked by a manager
+type Tracked struct {
+	exchange.IBotExchange
+	manager *Manager
+}
+
+// Unwrap returns the underlying exchange
+func (t *Tracked) Unwrap() exchange.IBotExchange {
+	return t.IBotExchange
+}
+
+// CheckOrder accepts orders placed natively beneath the wrapper, which are
+// picked up as orders placed outside the bot when reconciled
+func (t *Tracked) CheckOrder(_ *exchange.OrderSubmission) error {
+	return nil
+}
+
+// SubmitOrder submits the order, tracking it once placed
+func (t *Tracked) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	resp, err := t.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+	if err != nil || !resp.IsOrderPlaced || resp.OrderID == "" {
+		return resp, err
+	}
+	now := t.manager.now()
+	t.manager.placed(&Order{
+		Exchange:  t.GetName(),
+		ID:        resp.OrderID,
+		ClientID:  clientID,
+		Pair:      p,
+		Side:      normaliseSide(side),
+		Type:      exchange.OrderType(strings.ToUpper(string(orderType))),
+		Price:     price,
+		Amount:    amount,
+		Remaining: amount,
+		Status:    exchange.ActiveOrderStatus,
+		Placed:    now,
+		Updated:   now,
+	})
+	return resp, nil
+}
+
+// ModifyOrder modifies the order, following it when replaced under a new ID
+func (t *Tracked) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
+	id, err := t.IBotExchange.ModifyOrder(action)
+	if err != nil {
+		return id, err
+	}
+	m := t.manager
+	now := m.now()
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	o, ok := m.orders[key(t.GetName(), action.OrderID)]
+	if !ok {
+		return id, nil
+	}
+	if action.Price > 0 {
+		o.Price = action.Price
+	}
+	if action.Amount > 0 {
+		o.Amount = action.Amount
+		o.Remaining = o.Amount - o.Filled
+	}
+	o.Updated = now
+	if id != "" && id != action.OrderID {
+		delete(m.orders, key(t.GetName(), action.OrderID))
+		o.ID = id
+		o.Filled, o.Fee, o.Remaining = 0, 0, o.Amount
+		m.orders[key(t.GetName(), id)] = o
+	}
+	return id, nil
+}
+
+// CancelOrder cancels the order, closing it once cancelled. An order which
+// filled before it could be cancelled is updated when next reconciled
+func (t *Tracked) CancelOrder(cancel *exchange.OrderCancellation) error {
+	err := t.IBotExchange.CancelOrder(cancel)
+	if err == nil {
+		t.manager.cancelled(t.GetName(), cancel.OrderID)
+	}
+	return err
+}
+
+// CancelAllOrders cancels the orders, closing every open order for the pair,
+// or all pairs when none is set, unless reported in the response. Orders
+// still open are reopened when next reconciled
+func (t *Tracked) CancelAllOrders(cancel *exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error) {
+	resp, err := t.IBotExchange.CancelAllOrders(cancel)
+	if err != nil {
+		return resp, err
+	}
+	var ids []string
+	for _, o := range t.manager.List(&Filter{Exchange: t.GetName(), Pair: cancel.CurrencyPair, OpenOnly: true}) {
+		if _, ok := resp.OrderStatus[o.ID]; !ok {
+			ids = append(ids, o.ID)
+		}
+	}
+	t.manager.cancelled(t.GetName(), ids...)
+	return resp, nil
+}
+
+// GetOrderInfo fetches the order, updating it when tracked
+func (t *Tracked) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	detail, err := t.IBotExchange.GetOrderInfo(orderID)
+	if err == nil {
+		d := detail
+		if d.ID == "" {
+			d.ID = orderID
+		}
+		t.manager.observe(t.GetName(), []exchange.OrderDetail{d}, false)
+	}
+	return detail, err
+}
+
+// GetActiveOrders fetches open orders, updating those tracked and tracking
+// those not yet known
+func (t *Tracked) GetActiveOrders(req *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	orders, err := t.IBotExchange.GetActiveOrders(req)
+	if err == nil {
+		t.manager.observe(t.GetName(), orders, true)
+	}
+	return orders, err
+}
+
+// GetOrderHistory fetches order history, updating the orders tracked
+func (t *Tracked) GetOrderHistory(req *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	orders, err := t.IBotExchange.GetOrderHistory(req)
+	if err == nil {
+		t.manager.observe(t.GetName(), orders, false)
+	}
+	return orders, err
+}
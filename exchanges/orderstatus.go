@@ -0,0 +1,77 @@
+package exchange
+
+import "strings"
+
+// NormaliseStatus maps an exchange's order status to a common status. Status
+// strings are matched loosely as exchanges spell them differently, such as
+// partial-filled, PartiallyFilled and PARTIALLY FILLED @ 100. Orders without
+// a status are taken as active unless their amounts show them filled, and
+// unrecognised statuses are unknown
+func NormaliseStatus(status string, filled, amount float64) OrderStatus {
+	s := strings.NewReplacer("-", "", "_", "", " ", "").Replace(strings.ToUpper(status))
+	switch {
+	case strings.Contains(s, "PENDINGCANCEL") || strings.Contains(s, "CANCELING") ||
+		strings.Contains(s, "CANCELLING"):
+		return PendingCancelOrderStatus
+	case strings.Contains(s, "CANCEL"):
+		if amount > 0 && filled >= amount {
+			return FilledOrderStatus
+		}
+		return CancelledOrderStatus
+	case strings.Contains(s, "REJECT") || strings.Contains(s, "FAIL"):
+		return RejectedOrderStatus
+	case strings.Contains(s, "EXPIRE"):
+		return ExpiredOrderStatus
+	case strings.Contains(s, "PART"):
+		return PartiallyFilledOrderStatus
+	case strings.Contains(s, "UNFILLED") || strings.Contains(s, "NOTFILLED"):
+		return active(filled)
+	case strings.Contains(s, "FILL") || strings.Contains(s, "CLOSED") || strings.Contains(s, "DONE") ||
+		strings.Contains(s, "EXECUTED") || strings.Contains(s, "COMPLETE") || strings.Contains(s, "FINISHED"):
+		return FilledOrderStatus
+	case strings.Contains(s, "HIDDEN"):
+		return HiddenOrderStatus
+	case strings.Contains(s, "NEW") || strings.Contains(s, "OPEN") || strings.Contains(s, "ACTIVE") ||
+		strings.Contains(s, "LIVE") || strings.Contains(s, "PENDING") || strings.Contains(s, "SUBMIT") ||
+		strings.Contains(s, "WAIT") || strings.Contains(s, "ACCEPT"):
+		return active(filled)
+	case s == "":
+		if amount > 0 && filled >= amount {
+			return FilledOrderStatus
+		}
+		return active(filled)
+	}
+	return UnknownOrderStatus
+}
+
+func active(filled float64) OrderStatus {
+	if filled > 0 {
+		return PartiallyFilledOrderStatus
+	}
+	return ActiveOrderStatus
+}
+
+// Closed returns whether an order of the status can no longer fill
+func (o OrderStatus) Closed() bool {
+	switch o {
+	case FilledOrderStatus, CancelledOrderStatus, RejectedOrderStatus, ExpiredOrderStatus:
+		return true
+	}
+	return false
+}
+
+// Filled returns whether the order has fully executed. Order statuses are not
+// consistent across exchanges so amounts are preferred where reported
+func (o *OrderDetail) Filled() bool {
+	if o.Amount > 0 && o.ExecutedAmount >= o.Amount {
+		return true
+	}
+	return NormaliseStatus(o.Status, o.ExecutedAmount, o.Amount) == FilledOrderStatus
+}
+
+// Closed returns whether the order can no longer fill, having fully executed
+// or been cancelled, rejected or expired. Orders pending cancellation are
+// still open
+func (o *OrderDetail) Closed() bool {
+	return o.Filled() || NormaliseStatus(o.Status, o.ExecutedAmount, o.Amount).Closed()
+}
@@ -0,0 +1,56 @@
+package exchange
+
+import "testing"
+
+func TestNormaliseStatus(t *testing.T) {
+	for _, tc := range []struct {
+		status         string
+		filled, amount float64
+		expected       OrderStatus
+	}{
+		{"New", 0, 1, ActiveOrderStatus},
+		{"open", 0.5, 1, PartiallyFilledOrderStatus},
+		{"partial-filled", 0.5, 1, PartiallyFilledOrderStatus},
+		{"PARTIALLY FILLED @ 100", 0.5, 1, PartiallyFilledOrderStatus},
+		{"partial-canceled", 0.5, 1, CancelledOrderStatus},
+		{"EXECUTED @ 100", 1, 1, FilledOrderStatus},
+		{"closed", 1, 1, FilledOrderStatus},
+		{"PendingCancel", 0, 1, PendingCancelOrderStatus},
+		{"Rejected", 0, 1, RejectedOrderStatus},
+		{"expired", 0, 1, ExpiredOrderStatus},
+		{"unfilled", 0, 1, ActiveOrderStatus},
+		{"", 1, 1, FilledOrderStatus},
+		{"", 0, 1, ActiveOrderStatus},
+		{"-1", 0, 1, UnknownOrderStatus},
+	} {
+		if s := NormaliseStatus(tc.status, tc.filled, tc.amount); s != tc.expected {
+			t.Errorf("Test Failed - NormaliseStatus(%q) expected %s, received %s", tc.status, tc.expected, s)
+		}
+	}
+}
+
+func TestOrderDetailClosed(t *testing.T) {
+	for _, tc := range []struct {
+		o              OrderDetail
+		filled, closed bool
+	}{
+		{OrderDetail{Status: "NEW", Amount: 1}, false, false},
+		{OrderDetail{Status: "PARTIALLY_FILLED", Amount: 1, ExecutedAmount: 0.5}, false, false},
+		{OrderDetail{Status: "PARTIALLY_FILLED", Amount: 1, ExecutedAmount: 1}, true, true},
+		{OrderDetail{Status: "Filled"}, true, true},
+		{OrderDetail{Status: "done", Amount: 1, ExecutedAmount: 1}, true, true},
+		{OrderDetail{Status: "PENDING_CANCEL", Amount: 1}, false, false},
+		{OrderDetail{Status: "Cancelled", Amount: 1, ExecutedAmount: 0.5}, false, true},
+		{OrderDetail{Status: "canceled", Amount: 1, ExecutedAmount: 1}, true, true},
+		{OrderDetail{Status: "REJECTED", Amount: 1}, false, true},
+		{OrderDetail{Status: "Expired", Amount: 1}, false, true},
+		{OrderDetail{Status: "-1", Amount: 1}, false, false},
+	} {
+		if f := tc.o.Filled(); f != tc.filled {
+			t.Errorf("Test Failed - Filled() %q expected %v, received %v", tc.o.Status, tc.filled, f)
+		}
+		if c := tc.o.Closed(); c != tc.closed {
+			t.Errorf("Test Failed - Closed() %q expected %v, received %v", tc.o.Status, tc.closed, c)
+		}
+	}
+}
@@ -0,0 +1,171 @@
+// Package pairstats aggregates the bot's own trading activity per exchange
+// and pair for the current UTC day. Orders submitted, filled and cancelled,
+// the notional traded, fees paid and the net position change are counted from
+// the order events published by tracked exchanges, which observe fills and
+// cancellations as their orders are fetched
+package pairstats
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// Event types published by tracked exchanges
+const (
+	Submitted = "ORDER_SUBMITTED"
+	Filled    = "ORDER_FILLED"
+	Cancelled = "ORDER_CANCELLED"
+)
+
+// Event defines an order submitted, an amount of an order filled or an order
+// cancelled
+type Event struct {
+	Type     string             `json:"type"`
+	Exchange string             `json:"exchange"`
+	OrderID  string             `json:"orderID"`
+	Pair     currency.Pair      `json:"pair"`
+	Side     exchange.OrderSide `json:"side"`
+	Price    float64            `json:"price"`
+	Amount   float64            `json:"amount"`
+	Fee      float64            `json:"fee,omitempty"`
+	// Complete is set on the fill completing an order
+	Complete  bool      `json:"complete,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+}
+
+// Stats holds an exchange pair's activity for the day
+type Stats struct {
+	Exchange  string        `json:"exchange"`
+	Pair      currency.Pair `json:"pair"`
+	Day       time.Time     `json:"day"`
+	Submitted int64         `json:"submitted"`
+	// Filled counts orders completely filled and Fills every fill observed,
+	// including partial fills
+	Filled    int64   `json:"filled"`
+	Fills     int64   `json:"fills"`
+	Cancelled int64   `json:"cancelled"`
+	Bought    float64 `json:"bought"`
+	Sold      float64 `json:"sold"`
+	// Notional is traded in the quote currency and fees are as reported by
+	// the exchange
+	Notional float64 `json:"notional"`
+	Fees     float64 `json:"fees"`
+	// NetPosition is the amount bought less the amount sold
+	NetPosition  float64   `json:"netPosition"`
+	LastActivity time.Time `json:"lastActivity"`
+}
+
+// Tracker aggregates order events into the day's stats, starting over at
+// midnight UTC
+type Tracker struct {
+	day     time.Time
+	stats   map[string]*Stats
+	onEvent func(Event)
+	mtx     sync.Mutex
+}
+
+// New returns a tracker which passes each event recorded to the handler
+// once counted
+func New(onEvent func(Event)) *Tracker {
+	return &Tracker{
+		stats:   make(map[string]*Stats),
+		onEvent: onEvent,
+	}
+}
+
+func day(t time.Time) time.Time {
+	return t.UTC().Truncate(time.Hour * 24)
+}
+
+func key(exchName string, p currency.Pair) string {
+	return strings.ToUpper(exchName + " " + p.Base.String() + "-" + p.Quote.String())
+}
+
+// roll starts over when the time is on a later day than the stats
+func (t *Tracker) roll(now time.Time) {
+	if d := day(now); d.After(t.day) {
+		t.day = d
+		t.stats = make(map[string]*Stats)
+	}
+}
+
+// Record counts the event towards its pair's stats. Events from a previous
+// day are not counted
+func (t *Tracker) Record(e *Event) {
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	t.mtx.Lock()
+	t.roll(e.Timestamp)
+	if day(e.Timestamp).Before(t.day) {
+		t.mtx.Unlock()
+		return
+	}
+	k := key(e.Exchange, e.Pair)
+	s, ok := t.stats[k]
+	if !ok {
+		s = &Stats{Exchange: e.Exchange, Pair: e.Pair, Day: t.day}
+		t.stats[k] = s
+	}
+	switch e.Type {
+	case Submitted:
+		s.Submitted++
+	case Cancelled:
+		s.Cancelled++
+	case Filled:
+		s.Fills++
+		if e.Complete {
+			s.Filled++
+		}
+		if isBuy(e.Side) {
+			s.Bought += e.Amount
+			s.NetPosition += e.Amount
+		} else {
+			s.Sold += e.Amount
+			s.NetPosition -= e.Amount
+		}
+		s.Notional += e.Amount * e.Price
+		s.Fees += e.Fee
+	}
+	if e.Timestamp.After(s.LastActivity) {
+		s.LastActivity = e.Timestamp
+	}
+	t.mtx.Unlock()
+
+	if t.onEvent != nil {
+		t.onEvent(*e)
+	}
+}
+
+// List returns the day's stats of every pair traded on the named exchange, or
+// on every exchange when the name is empty, sorted by exchange and pair
+func (t *Tracker) List(exchName string) []Stats {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.roll(time.Now())
+
+	var resp []Stats
+	for _, s := range t.stats {
+		if exchName != "" && !strings.EqualFold(s.Exchange, exchName) {
+			continue
+		}
+		resp = append(resp, *s)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		if resp[i].Exchange != resp[j].Exchange {
+			return resp[i].Exchange < resp[j].Exchange
+		}
+		return resp[i].Pair.String() < resp[j].Pair.String()
+	})
+	return resp
+}
+
+func isBuy(s exchange.OrderSide) bool {
+	return strings.EqualFold(string(s), string(exchange.BuyOrderSide)) ||
+		strings.EqualFold(string(s), string(exchange.BidOrderSide))
+}
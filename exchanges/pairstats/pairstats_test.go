@@ -0,0 +1,147 @@
+package pairstats
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	orders map[string]*exchange.OrderDetail
+}
+
+func newTestExchange() *testExchange {
+	return &testExchange{orders: make(map[string]*exchange.OrderDetail)}
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	id := fmt.Sprintf("%d", len(t.orders)+1)
+	t.orders[id] = &exchange.OrderDetail{
+		ID:           id,
+		CurrencyPair: p,
+		OrderSide:    side,
+		OrderType:    orderType,
+		Price:        price,
+		Amount:       amount,
+	}
+	return exchange.SubmitOrderResponse{OrderID: id, IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	o, ok := t.orders[orderID]
+	if !ok {
+		return exchange.OrderDetail{}, fmt.Errorf("order %s not found", orderID)
+	}
+	return *o, nil
+}
+
+func (t *testExchange) GetActiveOrders(_ *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	var orders []exchange.OrderDetail
+	for _, o := range t.orders {
+		orders = append(orders, *o)
+	}
+	return orders, nil
+}
+
+func (t *testExchange) CancelOrder(_ *exchange.OrderCancellation) error {
+	return nil
+}
+
+func (t *testExchange) CancelAllOrders(_ *exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error) {
+	return exchange.CancelAllOrdersResponse{OrderStatus: map[string]string{"3": "failed"}}, nil
+}
+
+func TestTracked(t *testing.T) {
+	var events []Event
+	tracker := New(func(e Event) { events = append(events, e) })
+	exch := newTestExchange()
+	e := tracker.Track(exch)
+	btc := currency.NewPairWithDelimiter("BTC", "USD", "-")
+	eth := currency.NewPairWithDelimiter("ETH", "USD", "-")
+
+	for _, side := range []exchange.OrderSide{exchange.BuyOrderSide, exchange.SellOrderSide, exchange.BuyOrderSide} {
+		if _, err := e.SubmitOrder(btc, side, exchange.LimitOrderType, 2, 100, ""); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if _, err := e.SubmitOrder(eth, exchange.SellOrderSide, exchange.LimitOrderType, 1, 10, ""); err != nil {
+		t.Fatal(err)
+	}
+
+	// Fills are counted as the amount executed grows
+	exch.orders["1"].ExecutedAmount = 1
+	exch.orders["1"].Fee = 0.1
+	if _, err := e.GetOrderInfo("1"); err != nil {
+		t.Fatal(err)
+	}
+	exch.orders["1"].ExecutedAmount = 2
+	exch.orders["1"].Price = 99
+	exch.orders["1"].Fee = 0.3
+	exch.orders["2"].ExecutedAmount = 0.5
+	exch.orders["4"].Status = string(exchange.CancelledOrderStatus)
+	if _, err := e.GetActiveOrders(&exchange.GetOrdersRequest{}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := e.GetOrderInfo("1"); err != nil {
+		t.Fatal(err)
+	}
+
+	err := e.CancelOrder(&exchange.OrderCancellation{OrderID: "2", CurrencyPair: btc})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Orders the cancel all response reports are not counted as cancelled
+	if _, err = e.CancelAllOrders(&exchange.OrderCancellation{CurrencyPair: btc}); err != nil {
+		t.Fatal(err)
+	}
+
+	stats := tracker.List("")
+	if len(stats) != 2 || stats[0].Pair.String() != "BTC-USD" {
+		t.Fatalf("Test Failed - List() unexpected stats %+v", stats)
+	}
+	s := stats[0]
+	if s.Submitted != 3 || s.Filled != 1 || s.Fills != 3 || s.Cancelled != 1 {
+		t.Errorf("Test Failed - List() unexpected BTC-USD order counts %+v", s)
+	}
+	if s.Bought != 2 || s.Sold != 0.5 || s.NetPosition != 1.5 {
+		t.Errorf("Test Failed - List() unexpected BTC-USD amounts %+v", s)
+	}
+	if s.Notional != 100+99+50 || s.Fees != 0.3 {
+		t.Errorf("Test Failed - List() unexpected BTC-USD notional or fees %+v", s)
+	}
+	if s.Day != time.Now().UTC().Truncate(time.Hour*24) {
+		t.Errorf("Test Failed - List() unexpected day %v", s.Day)
+	}
+	if stats[1].Submitted != 1 || stats[1].Cancelled != 1 || stats[1].Fills != 0 {
+		t.Errorf("Test Failed - List() unexpected ETH-USD stats %+v", stats[1])
+	}
+	if len(events) != 9 {
+		t.Errorf("Test Failed - Record() expected 9 events, received %d", len(events))
+	}
+	if len(tracker.List("other")) != 0 {
+		t.Error("Test Failed - List() expected no stats for other exchanges")
+	}
+}
+
+func TestRoll(t *testing.T) {
+	tracker := New(nil)
+	p := currency.NewPairWithDelimiter("BTC", "USD", "-")
+	yesterday := time.Now().Add(-time.Hour * 24)
+	tracker.Record(&Event{Type: Submitted, Exchange: "test", Pair: p, Timestamp: yesterday})
+	if stats := tracker.List(""); len(stats) != 0 {
+		t.Errorf("Test Failed - List() expected the previous day's stats cleared %+v", stats)
+	}
+
+	tracker.Record(&Event{Type: Filled, Exchange: "test", Pair: p, Side: exchange.SellOrderSide, Amount: 1, Price: 10})
+	tracker.Record(&Event{Type: Submitted, Exchange: "test", Pair: p, Timestamp: yesterday})
+	stats := tracker.List("TEST")
+	if len(stats) != 1 || stats[0].Submitted != 0 || stats[0].NetPosition != -1 || stats[0].Notional != 10 {
+		t.Errorf("Test Failed - Record() expected only today's events counted %+v", stats)
+	}
+}
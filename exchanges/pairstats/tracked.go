@@ -0,0 +1,235 @@
+package pairstats
+
+import (
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// order holds an order submitted through a tracked exchange and the amount and
+// fee observed so far
+type order struct {
+	pair   currency.Pair
+	side   exchange.OrderSide
+	price  float64
+	amount float64
+	filled float64
+	fee    float64
+}
+
+// Track wraps an exchange so its orders are counted in the tracker's stats
+func (t *Tracker) Track(e exchange.IBotExchange) exchange.IBotExchange {
+	return &Tracked{
+		IBotExchange: e,
+		tracker:      t,
+		orders:       make(map[string]*order),
+	}
+}
+
+// Tracked is an exchange whose orders are counted in a tracker's stats
+type Tracked struct {
+	exchange.IBotExchange
+	tracker *Tracker
+	orders  map[string]*order
+	mtx     sync.Mutex
+}
+
+// Unwrap returns the underlying exchange
+func (t *Tracked) Unwrap() exchange.IBotExchange {
+	return t.IBotExchange
+}
+
+func (t *Tracked) record(eventType, id string, o *order) {
+	t.tracker.Record(&Event{
+		Type:      eventType,
+		Exchange:  t.GetName(),
+		OrderID:   id,
+		Pair:      o.pair,
+		Side:      o.side,
+		Price:     o.price,
+		Amount:    o.amount,
+		Timestamp: time.Now(),
+	})
+}
+
+// SubmitOrder submits the order, counting it once placed
+func (t *Tracked) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	resp, err := t.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+	if err != nil || !resp.IsOrderPlaced {
+		return resp, err
+	}
+	o := &order{pair: p, side: side, price: price, amount: amount}
+	if resp.OrderID != "" {
+		t.mtx.Lock()
+		t.orders[resp.OrderID] = o
+		t.mtx.Unlock()
+	}
+	t.record(Submitted, resp.OrderID, o)
+	return resp, nil
+}
+
+// ModifyOrder modifies the order, following it when replaced under a new ID
+func (t *Tracked) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
+	id, err := t.IBotExchange.ModifyOrder(action)
+	if err != nil {
+		return id, err
+	}
+	t.mtx.Lock()
+	if o, ok := t.orders[action.OrderID]; ok {
+		if action.Price > 0 {
+			o.price = action.Price
+		}
+		if action.Amount > 0 {
+			o.amount = action.Amount
+		}
+		if id != "" && id != action.OrderID {
+			delete(t.orders, action.OrderID)
+			o.filled, o.fee = 0, 0
+			t.orders[id] = o
+		}
+	}
+	t.mtx.Unlock()
+	return id, nil
+}
+
+// CancelOrder cancels the order, counting it once cancelled. An order which
+// filled before it could be cancelled is fetched so its fill is counted
+func (t *Tracked) CancelOrder(cancel *exchange.OrderCancellation) error {
+	err := t.IBotExchange.CancelOrder(cancel)
+	switch err {
+	case nil:
+		t.cancelled(cancel.OrderID)
+	case exchange.ErrCancelOrderFilled:
+		// Errors are ignored, the fill is counted when next fetched
+		_, _ = t.GetOrderInfo(cancel.OrderID)
+	}
+	return err
+}
+
+// CancelAllOrders cancels the orders, counting every tracked order for the
+// pair, or all pairs when none is set, as cancelled unless reported in the
+// response. Reported orders are counted when next fetched
+func (t *Tracked) CancelAllOrders(cancel *exchange.OrderCancellation) (exchange.CancelAllOrdersResponse, error) {
+	resp, err := t.IBotExchange.CancelAllOrders(cancel)
+	if err != nil {
+		return resp, err
+	}
+	var ids []string
+	t.mtx.Lock()
+	for id, o := range t.orders {
+		if _, ok := resp.OrderStatus[id]; ok {
+			continue
+		}
+		if cancel.CurrencyPair.IsEmpty() || key("", cancel.CurrencyPair) == key("", o.pair) {
+			ids = append(ids, id)
+		}
+	}
+	t.mtx.Unlock()
+	for i := range ids {
+		t.cancelled(ids[i])
+	}
+	return resp, nil
+}
+
+func (t *Tracked) cancelled(id string) {
+	t.mtx.Lock()
+	o, ok := t.orders[id]
+	delete(t.orders, id)
+	t.mtx.Unlock()
+	if ok {
+		t.record(Cancelled, id, o)
+	}
+}
+
+// GetOrderInfo fetches the order, counting any new fill
+func (t *Tracked) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	detail, err := t.IBotExchange.GetOrderInfo(orderID)
+	if err == nil {
+		if detail.ID == "" {
+			detail.ID = orderID
+		}
+		t.observe([]exchange.OrderDetail{detail})
+	}
+	return detail, err
+}
+
+// GetActiveOrders fetches open orders, counting any new fills
+func (t *Tracked) GetActiveOrders(req *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	orders, err := t.IBotExchange.GetActiveOrders(req)
+	if err == nil {
+		t.observe(orders)
+	}
+	return orders, err
+}
+
+// GetOrderHistory fetches order history, counting any new fills and
+// cancellations
+func (t *Tracked) GetOrderHistory(req *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	orders, err := t.IBotExchange.GetOrderHistory(req)
+	if err == nil {
+		t.observe(orders)
+	}
+	return orders, err
+}
+
+// observe counts the amount and fee each tracked order has filled since it
+// was last observed, forgetting orders once they can no longer fill
+func (t *Tracked) observe(orders []exchange.OrderDetail) {
+	var events []Event
+	now := time.Now()
+	t.mtx.Lock()
+	for i := range orders {
+		o, ok := t.orders[orders[i].ID]
+		if !ok {
+			continue
+		}
+		if orders[i].ExecutedAmount > o.filled {
+			price := orders[i].Price
+			if price <= 0 {
+				price = o.price
+			}
+			var fee float64
+			if orders[i].Fee > o.fee {
+				fee = orders[i].Fee - o.fee
+				o.fee = orders[i].Fee
+			}
+			e := Event{
+				Type:      Filled,
+				Exchange:  t.GetName(),
+				OrderID:   orders[i].ID,
+				Pair:      o.pair,
+				Side:      o.side,
+				Price:     price,
+				Amount:    orders[i].ExecutedAmount - o.filled,
+				Fee:       fee,
+				Timestamp: now,
+			}
+			o.filled = orders[i].ExecutedAmount
+			if o.filled >= o.amount {
+				e.Complete = true
+				delete(t.orders, orders[i].ID)
+			}
+			events = append(events, e)
+		}
+		if _, ok = t.orders[orders[i].ID]; ok && orders[i].Closed() && !orders[i].Filled() {
+			delete(t.orders, orders[i].ID)
+			events = append(events, Event{
+				Type:      Cancelled,
+				Exchange:  t.GetName(),
+				OrderID:   orders[i].ID,
+				Pair:      o.pair,
+				Side:      o.side,
+				Price:     o.price,
+				Amount:    o.amount,
+				Timestamp: now,
+			})
+		}
+	}
+	t.mtx.Unlock()
+
+	for i := range events {
+		t.tracker.Record(&events[i])
+	}
+}
@@ -0,0 +1,476 @@
+// Package pingorder provides a deep health check which periodically places a
+// tiny limit order far from the market on each target exchange and cancels it
+// immediately, verifying the authenticated order path works end to end rather
+// than only the public endpoints
+package pingorder
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/duplicates"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default ping order settings
+const (
+	DefaultInterval    = time.Hour
+	DefaultOffset      = 0.5
+	DefaultMaxNotional = 25
+	DefaultMaxPerDay   = 48
+)
+
+// Event types emitted by the checker
+const (
+	Failed    = "PING_ORDER_FAILED"
+	Recovered = "PING_ORDER_RECOVERED"
+)
+
+var (
+	errInvalidOffset     = errors.New("ping order offset must be between 0 and 1")
+	errNoTargets         = errors.New("no ping order targets supplied")
+	errInvalidAmount     = errors.New("ping order amount must be positive")
+	errUnknownExchange   = errors.New("exchange not loaded")
+	errNotAuthenticated  = errors.New("exchange authenticated API support not enabled")
+	errUnknownTarget     = errors.New("exchange has no ping order target")
+	errNoBid             = errors.New("ticker has no bid to price the ping order from")
+	errNotionalCap       = errors.New("ping order value exceeds the maximum notional")
+	errDailyCap          = errors.New("daily ping order cap reached")
+	errNotPlaced         = errors.New("ping order not placed")
+	errStrandedOrder     = errors.New("previous ping order could not be cancelled")
+	errDuplicateExchange = errors.New("exchange has more than one ping order target")
+)
+
+// Config defines the ping order schedule and safety caps
+type Config struct {
+	// Interval is the time between ping orders on each exchange
+	Interval time.Duration
+	// Offset is the fraction below the best bid the ping buy order is priced
+	Offset float64
+	// MaxNotional is the maximum value of a ping order in the quote currency
+	MaxNotional float64
+	// MaxPerDay is the maximum ping orders placed per exchange per day
+	MaxPerDay int
+}
+
+// Validate checks the configuration and sets defaults
+func (c *Config) Validate() error {
+	if c.Offset < 0 || c.Offset >= 1 {
+		return errInvalidOffset
+	}
+	if c.Interval <= 0 {
+		c.Interval = DefaultInterval
+	}
+	if c.Offset == 0 {
+		c.Offset = DefaultOffset
+	}
+	if c.MaxNotional <= 0 {
+		c.MaxNotional = DefaultMaxNotional
+	}
+	if c.MaxPerDay <= 0 {
+		c.MaxPerDay = DefaultMaxPerDay
+	}
+	return nil
+}
+
+// Target defines the pair and amount a ping order is placed with on an
+// exchange
+type Target struct {
+	Exchange string
+	Pair     currency.Pair
+	Amount   float64
+}
+
+// Result holds the outcome of a ping order
+type Result struct {
+	Exchange  string
+	Pair      currency.Pair
+	OrderID   string
+	Price     float64
+	Amount    float64
+	PostOnly  bool
+	Placed    bool
+	Cancelled bool
+	// SubmitLatency and CancelLatency are the round trip times of the order
+	// submission and cancellation
+	SubmitLatency time.Duration
+	CancelLatency time.Duration
+	Error         string
+	Timestamp     time.Time
+}
+
+// Healthy returns whether the ping order was placed and cancelled
+func (r *Result) Healthy() bool {
+	return r.Placed && r.Cancelled && r.Error == ""
+}
+
+func (r *Result) String() string {
+	if r.Healthy() {
+		return fmt.Sprintf("%s %s ping order %s placed in %s and cancelled in %s",
+			r.Exchange, r.Pair, r.OrderID, r.SubmitLatency, r.CancelLatency)
+	}
+	return fmt.Sprintf("%s %s ping order failed: %s", r.Exchange, r.Pair, r.Error)
+}
+
+// Status holds the ping order health of an exchange
+type Status struct {
+	Exchange string
+	Pair     currency.Pair
+	Healthy  bool
+	// ConsecutiveFailures is the number of ping orders failed since the last
+	// healthy ping
+	ConsecutiveFailures int
+	PingsToday          int
+	// Stranded holds ping orders which were placed but could not be
+	// cancelled. No further ping orders are placed until they are cancelled
+	Stranded   []string
+	LastResult *Result
+}
+
+// Event is emitted when an exchange's ping orders start failing or recover
+type Event struct {
+	Type   string
+	Result Result
+}
+
+func (e *Event) String() string {
+	return fmt.Sprintf("%s: %s", e.Type, e.Result.String())
+}
+
+type state struct {
+	target   Target
+	exch     exchange.IBotExchange
+	failures int
+	day      time.Time
+	today    int
+	stranded []string
+	last     *Result
+	// pinging serialises scheduled and requested pings of the exchange
+	pinging sync.Mutex
+}
+
+// Checker places ping orders on each target exchange
+type Checker struct {
+	cfg      Config
+	states   map[string]*state
+	onEvent  func(Event)
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a ping order checker for the targets. Each target exchange must
+// be loaded with authenticated API support enabled
+func New(cfg Config, exchanges []exchange.IBotExchange, targets []Target, onEvent func(Event)) (*Checker, error) {
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	if len(targets) == 0 {
+		return nil, errNoTargets
+	}
+
+	c := &Checker{
+		cfg:     cfg,
+		states:  make(map[string]*state),
+		onEvent: onEvent,
+	}
+	for i := range targets {
+		if targets[i].Amount <= 0 {
+			return nil, fmt.Errorf("%s %v", targets[i].Exchange, errInvalidAmount)
+		}
+		var exch exchange.IBotExchange
+		for j := range exchanges {
+			if strings.EqualFold(exchanges[j].GetName(), targets[i].Exchange) {
+				exch = exchanges[j]
+				break
+			}
+		}
+		if exch == nil {
+			return nil, fmt.Errorf("%s %v", targets[i].Exchange, errUnknownExchange)
+		}
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			return nil, fmt.Errorf("%s %v", exch.GetName(), errNotAuthenticated)
+		}
+		k := strings.ToLower(exch.GetName())
+		if _, ok := c.states[k]; ok {
+			return nil, fmt.Errorf("%s %v", exch.GetName(), errDuplicateExchange)
+		}
+		t := targets[i]
+		t.Exchange = exch.GetName()
+		c.states[k] = &state{target: t, exch: exch}
+	}
+	return c, nil
+}
+
+// Ping places and cancels a ping order on the exchange
+func (c *Checker) Ping(exchName string) (Result, error) {
+	c.mtx.Lock()
+	s, ok := c.states[strings.ToLower(exchName)]
+	c.mtx.Unlock()
+	if !ok {
+		return Result{}, fmt.Errorf("%s %v", exchName, errUnknownTarget)
+	}
+
+	return c.run(s), nil
+}
+
+// run pings the exchange and records the result
+func (c *Checker) run(s *state) Result {
+	s.pinging.Lock()
+	defer s.pinging.Unlock()
+	r := c.ping(s)
+	c.record(s, &r)
+	return r
+}
+
+// ping places and cancels a ping order, the exchange's stranded ping orders
+// are cancelled first
+func (c *Checker) ping(s *state) Result {
+	r := Result{
+		Exchange:  s.target.Exchange,
+		Pair:      s.target.Pair,
+		Amount:    s.target.Amount,
+		Timestamp: time.Now(),
+	}
+
+	c.mtx.Lock()
+	stranded := append([]string(nil), s.stranded...)
+	if day := r.Timestamp.UTC().Truncate(time.Hour * 24); !day.Equal(s.day) {
+		s.day = day
+		s.today = 0
+	}
+	today := s.today
+	c.mtx.Unlock()
+
+	remaining := stranded[:0]
+	for _, id := range stranded {
+		if err := c.cancel(s, id); err != nil {
+			remaining = append(remaining, id)
+		}
+	}
+	c.mtx.Lock()
+	s.stranded = remaining
+	c.mtx.Unlock()
+	if len(remaining) > 0 {
+		r.Error = fmt.Sprintf("%v: %s", errStrandedOrder, strings.Join(remaining, ", "))
+		return r
+	}
+
+	if today >= c.cfg.MaxPerDay {
+		r.Error = errDailyCap.Error()
+		return r
+	}
+
+	t, err := s.exch.UpdateTicker(s.target.Pair, ticker.Spot)
+	if err != nil {
+		r.Error = err.Error()
+		return r
+	}
+	if t.Bid <= 0 {
+		r.Error = errNoBid.Error()
+		return r
+	}
+	r.Price = t.Bid * (1 - c.cfg.Offset)
+	if r.Price*r.Amount > c.cfg.MaxNotional {
+		r.Error = fmt.Sprintf("%v: %f > %f", errNotionalCap, r.Price*r.Amount, c.cfg.MaxNotional)
+		return r
+	}
+
+	c.mtx.Lock()
+	s.today++
+	c.mtx.Unlock()
+
+	// Pings at an unchanged bid repeat the previous ping
+	var resp exchange.SubmitOrderResponse
+	start := time.Now()
+	p, err := postOnly(s, &r)
+	switch {
+	case err != nil:
+	case p != nil:
+		r.PostOnly = true
+		resp, err = p.SubmitPostOnlyOrder(s.target.Pair, exchange.BuyOrderSide, r.Amount, r.Price, "")
+	default:
+		resp, err = duplicates.SubmitOrder(s.exch, s.target.Pair, exchange.BuyOrderSide, exchange.LimitOrderType, r.Amount, r.Price, "", true)
+	}
+	r.SubmitLatency = time.Since(start)
+	r.OrderID = resp.OrderID
+	if err != nil {
+		r.Error = err.Error()
+		if resp.OrderID != "" {
+			c.strand(s, resp.OrderID)
+		}
+		return r
+	}
+	if !resp.IsOrderPlaced || resp.OrderID == "" {
+		r.Error = errNotPlaced.Error()
+		return r
+	}
+	r.Placed = true
+
+	start = time.Now()
+	err = c.cancel(s, resp.OrderID)
+	r.CancelLatency = time.Since(start)
+	if err != nil {
+		r.Error = err.Error()
+		c.strand(s, resp.OrderID)
+		return r
+	}
+	r.Cancelled = true
+	return r
+}
+
+// postOnly returns the exchange beneath the wrappers when it places post only
+// orders and the ping passes the checks of every wrapper guarding it, nil when
+// the ping is submitted through the wrapped exchange
+func postOnly(s *state, r *Result) (exchange.PostOnlySubmitter, error) {
+	if _, ok := exchange.Underlying(s.exch).(exchange.PostOnlySubmitter); !ok {
+		return nil, nil
+	}
+	o := exchange.OrderSubmission{
+		Pair:           s.target.Pair,
+		Side:           exchange.BuyOrderSide,
+		OrderType:      exchange.LimitOrderType,
+		Amount:         r.Amount,
+		Price:          r.Price,
+		AllowDuplicate: true,
+	}
+	native, err := exchange.Native(s.exch, &o)
+	if err != nil {
+		return nil, err
+	}
+	r.Amount, r.Price = o.Amount, o.Price
+	p, _ := native.(exchange.PostOnlySubmitter)
+	return p, nil
+}
+
+func (c *Checker) cancel(s *state, orderID string) error {
+	return s.exch.CancelOrder(&exchange.OrderCancellation{
+		OrderID:      orderID,
+		CurrencyPair: s.target.Pair,
+		Side:         exchange.BuyOrderSide,
+	})
+}
+
+func (c *Checker) strand(s *state, orderID string) {
+	log.Errorf("%s ping order %s could not be cancelled, no further ping orders will be placed until it is",
+		s.target.Exchange, orderID)
+	c.mtx.Lock()
+	s.stranded = append(s.stranded, orderID)
+	c.mtx.Unlock()
+}
+
+// record stores the result, emitting an event when the exchange's ping orders
+// start failing or recover
+func (c *Checker) record(s *state, r *Result) {
+	c.mtx.Lock()
+	var evt string
+	if r.Healthy() {
+		if s.failures > 0 {
+			evt = Recovered
+		}
+		s.failures = 0
+	} else {
+		if s.failures == 0 {
+			evt = Failed
+		}
+		s.failures++
+	}
+	s.last = r
+	c.mtx.Unlock()
+
+	if r.Healthy() {
+		log.Debugf("%s", r.String())
+	} else {
+		log.Warnf("%s", r.String())
+	}
+	if evt != "" && c.onEvent != nil {
+		c.onEvent(Event{Type: evt, Result: *r})
+	}
+}
+
+// Check pings every target exchange
+func (c *Checker) Check() {
+	c.mtx.Lock()
+	states := make([]*state, 0, len(c.states))
+	for _, s := range c.states {
+		states = append(states, s)
+	}
+	c.mtx.Unlock()
+
+	for _, s := range states {
+		c.run(s)
+	}
+}
+
+// GetStatus returns the ping order health of each exchange ordered by name
+func (c *Checker) GetStatus() []Status {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	resp := make([]Status, 0, len(c.states))
+	for _, s := range c.states {
+		st := Status{
+			Exchange:            s.target.Exchange,
+			Pair:                s.target.Pair,
+			Healthy:             s.last != nil && s.last.Healthy(),
+			ConsecutiveFailures: s.failures,
+			PingsToday:          s.today,
+			Stranded:            append([]string(nil), s.stranded...),
+		}
+		if s.last != nil {
+			last := *s.last
+			st.LastResult = &last
+		}
+		resp = append(resp, st)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Exchange < resp[j].Exchange
+	})
+	return resp
+}
+
+// Start pings every target exchange each interval until stopped
+func (c *Checker) Start() {
+	c.mtx.Lock()
+	if c.shutdown != nil {
+		c.mtx.Unlock()
+		return
+	}
+	c.shutdown = make(chan struct{})
+	shutdown := c.shutdown
+	c.mtx.Unlock()
+
+	c.wg.Add(1)
+	go func() {
+		defer c.wg.Done()
+		t := time.NewTicker(c.cfg.Interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-t.C:
+				c.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops placing ping orders
+func (c *Checker) Stop() {
+	c.mtx.Lock()
+	if c.shutdown == nil {
+		c.mtx.Unlock()
+		return
+	}
+	close(c.shutdown)
+	c.shutdown = nil
+	c.mtx.Unlock()
+	c.wg.Wait()
+}
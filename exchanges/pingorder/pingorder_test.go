@@ -0,0 +1,189 @@
+package pingorder
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+)
+
+var testPair = currency.NewPairFromStrings("BTC", "USD")
+
+type testExchange struct {
+	exchange.IBotExchange
+	auth      bool
+	bid       float64
+	price     float64
+	cancelErr error
+	open      map[string]bool
+	orders    int
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) GetAuthenticatedAPISupport(_ uint8) bool { return t.auth }
+
+func (t *testExchange) UpdateTicker(p currency.Pair, _ string) (ticker.Price, error) {
+	return ticker.Price{Pair: p, Bid: t.bid, Ask: t.bid + 1}, nil
+}
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.orders++
+	t.price = price
+	id := strconv.Itoa(t.orders)
+	t.open[id] = true
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true, OrderID: id}, nil
+}
+
+func (t *testExchange) CancelOrder(o *exchange.OrderCancellation) error {
+	if t.cancelErr != nil {
+		return t.cancelErr
+	}
+	delete(t.open, o.OrderID)
+	return nil
+}
+
+func TestNew(t *testing.T) {
+	exch := &testExchange{open: make(map[string]bool)}
+	exchanges := []exchange.IBotExchange{exch}
+	target := []Target{{Exchange: "TEST", Pair: testPair, Amount: 0.001}}
+	if _, err := New(Config{Offset: 1}, exchanges, target, nil); err != errInvalidOffset {
+		t.Error("Test Failed - New() expected invalid offset error", err)
+	}
+	if _, err := New(Config{}, exchanges, nil, nil); err != errNoTargets {
+		t.Error("Test Failed - New() expected no targets error", err)
+	}
+	if _, err := New(Config{}, exchanges, []Target{{Exchange: "other", Amount: 1}}, nil); err == nil {
+		t.Error("Test Failed - New() expected unknown exchange error")
+	}
+	if _, err := New(Config{}, exchanges, target, nil); err == nil {
+		t.Error("Test Failed - New() expected unauthenticated exchange error")
+	}
+	exch.auth = true
+	c, err := New(Config{}, exchanges, target, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if c.cfg.Interval != DefaultInterval || c.cfg.MaxPerDay != DefaultMaxPerDay {
+		t.Errorf("Test Failed - New() expected defaults %+v", c.cfg)
+	}
+}
+
+func TestPing(t *testing.T) {
+	exch := &testExchange{auth: true, bid: 10000, open: make(map[string]bool)}
+	var events []Event
+	c, err := New(Config{MaxPerDay: 3, MaxNotional: 10},
+		[]exchange.IBotExchange{exch},
+		[]Target{{Exchange: "test", Pair: testPair, Amount: 0.001}},
+		func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = c.Ping("other"); err == nil {
+		t.Error("Test Failed - Ping() expected unknown target error")
+	}
+	r, err := c.Ping("test")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !r.Healthy() || exch.price != 5000 || len(exch.open) != 0 {
+		t.Errorf("Test Failed - Ping() expected order at half the bid cancelled %+v", r)
+	}
+
+	exch.cancelErr = errors.New("cancel failed")
+	if r, _ = c.Ping("test"); r.Healthy() || !r.Placed {
+		t.Errorf("Test Failed - Ping() expected failed cancel %+v", r)
+	}
+	orders := exch.orders
+	if r, _ = c.Ping("test"); r.Healthy() || exch.orders != orders {
+		t.Errorf("Test Failed - Ping() expected no order while one is stranded %+v", r)
+	}
+	if len(events) != 1 || events[0].Type != Failed {
+		t.Errorf("Test Failed - Ping() expected one failed event %+v", events)
+	}
+
+	exch.cancelErr = nil
+	if r, _ = c.Ping("test"); !r.Healthy() || len(exch.open) != 0 {
+		t.Errorf("Test Failed - Ping() expected stranded order cancelled %+v", r)
+	}
+	if len(events) != 2 || events[1].Type != Recovered {
+		t.Errorf("Test Failed - Ping() expected recovered event %+v", events)
+	}
+
+	if r, _ = c.Ping("test"); r.Error != errDailyCap.Error() {
+		t.Errorf("Test Failed - Ping() expected daily cap %+v", r)
+	}
+	s := c.GetStatus()
+	if len(s) != 1 || s[0].PingsToday != 3 || s[0].ConsecutiveFailures != 1 || s[0].Healthy {
+		t.Errorf("Test Failed - GetStatus() unexpected status %+v", s)
+	}
+
+	c.mtx.Lock()
+	c.states["test"].today = 0
+	c.mtx.Unlock()
+	exch.bid = 1000000
+	if r, _ = c.Ping("test"); r.Placed {
+		t.Errorf("Test Failed - Ping() expected notional cap %+v", r)
+	}
+}
+
+type testPostOnlyExchange struct {
+	testExchange
+	postOnly int
+}
+
+func (t *testPostOnlyExchange) SubmitPostOnlyOrder(p currency.Pair, side exchange.OrderSide, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	t.postOnly++
+	return t.SubmitOrder(p, side, exchange.LimitOrderType, amount, price, clientID)
+}
+
+// testGuard is a wrapper rejecting every order while blocked
+type testGuard struct {
+	exchange.IBotExchange
+	blocked bool
+}
+
+func (g *testGuard) Unwrap() exchange.IBotExchange { return g.IBotExchange }
+
+func (g *testGuard) CheckOrder(_ *exchange.OrderSubmission) error {
+	if g.blocked {
+		return errors.New("blocked")
+	}
+	return nil
+}
+
+func TestPingPostOnly(t *testing.T) {
+	exch := &testPostOnlyExchange{testExchange: testExchange{auth: true, bid: 10000, open: make(map[string]bool)}}
+	guard := &testGuard{IBotExchange: exch, blocked: true}
+	c, err := New(Config{MaxNotional: 10},
+		[]exchange.IBotExchange{guard},
+		[]Target{{Exchange: "test", Pair: testPair, Amount: 0.001}},
+		nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if r, _ := c.Ping("test"); r.Placed || exch.orders != 0 {
+		t.Errorf("Test Failed - Ping() expected the guard to reject the ping %+v", r)
+	}
+	guard.blocked = false
+	if r, _ := c.Ping("test"); !r.Healthy() || !r.PostOnly || exch.postOnly != 1 {
+		t.Errorf("Test Failed - Ping() expected post only ping beneath the guard %+v", r)
+	}
+
+	// Wrappers which must see the order keep the ping on SubmitOrder
+	c, err = New(Config{MaxNotional: 10},
+		[]exchange.IBotExchange{exchange.NewDryRun(exch)},
+		[]Target{{Exchange: "test", Pair: testPair, Amount: 0.001}},
+		nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if r, _ := c.Ping("test"); r.PostOnly || exch.postOnly != 1 {
+		t.Errorf("Test Failed - Ping() dry run ping must not reach the exchange %+v", r)
+	}
+}
@@ -0,0 +1,358 @@
+// Package polling adapts how often each pair's REST ticker and orderbook are
+// polled, and the websocket orderbook depth subscribed, to the pair's short
+// term realized volatility. Each exchange's polling budget, the requests its
+// pairs would make at the base interval, is shared out in proportion to
+// volatility so volatile pairs refresh faster and quiet pairs slower without
+// spending more of the exchange's rate limit
+package polling
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// Default scheduler settings
+const (
+	// DefaultBaseInterval is the polling interval of a pair with the median
+	// volatility of its exchange
+	DefaultBaseInterval = time.Second * 10
+	DefaultMinInterval  = time.Second * 2
+	DefaultMaxInterval  = time.Minute
+	// DefaultWindow is the realized volatility window pairs are compared on
+	DefaultWindow = time.Hour
+	// DefaultMaxWeight caps how many times the median budget share a pair
+	// receives, and its inverse the smallest share
+	DefaultMaxWeight = 4
+	// DefaultCheckInterval is the delay between schedule updates
+	DefaultCheckInterval = time.Minute * 5
+
+	// Pairs weighted at or above volatileWeight subscribe the deepest book
+	// and at or below quietWeight the shallowest
+	volatileWeight = 2
+	quietWeight    = 0.5
+)
+
+// Polled data kinds
+const (
+	Ticker    = "ticker"
+	Orderbook = "orderbook"
+)
+
+// DefaultDepths are the websocket orderbook depths subscribed by quiet,
+// normal and volatile pairs
+var DefaultDepths = []int{10, 25, 100}
+
+var (
+	errNoExchanges     = errors.New("no exchanges supplied")
+	errNilVolatility   = errors.New("no volatility function supplied")
+	errInvalidInterval = errors.New("polling intervals must be positive with min <= base <= max")
+	errInvalidWeight   = errors.New("polling max weight must be at least 1")
+	errPairNotTracked  = errors.New("pair polling not scheduled")
+)
+
+// VolatilityFunc returns the annualized realized volatility of a pair over a
+// window
+type VolatilityFunc func(exchangeName string, p currency.Pair, window time.Duration) (float64, error)
+
+// DepthFunc is called when a pair's websocket orderbook depth changes
+type DepthFunc func(exchangeName string, p currency.Pair, depth int)
+
+// Config defines the scheduler settings, zero values are defaulted
+type Config struct {
+	Base      time.Duration
+	Min       time.Duration
+	Max       time.Duration
+	Window    time.Duration
+	MaxWeight float64
+	// Depths are the websocket orderbook depths of quiet, normal and
+	// volatile pairs, shallowest first
+	Depths []int
+}
+
+// Schedule holds a pair's polling interval and orderbook depth
+type Schedule struct {
+	Exchange string        `json:"exchange"`
+	Pair     currency.Pair `json:"pair"`
+	// Volatility is zero when the pair's volatility is unknown, such pairs
+	// are polled at the base interval
+	Volatility float64       `json:"volatility"`
+	Weight     float64       `json:"weight"`
+	Interval   time.Duration `json:"interval"`
+	Depth      int           `json:"depth"`
+	Updated    time.Time     `json:"updated"`
+	next       map[string]time.Time
+}
+
+type key struct {
+	exchange string
+	pair     string
+}
+
+func newKey(exchangeName string, p currency.Pair) key {
+	return key{
+		exchange: strings.ToLower(exchangeName),
+		pair:     p.Base.Upper().String() + "-" + p.Quote.Upper().String(),
+	}
+}
+
+// Scheduler periodically reschedules the pairs of every enabled exchange
+type Scheduler struct {
+	cfg       Config
+	exchanges []exchange.IBotExchange
+	vol       VolatilityFunc
+	onDepth   DepthFunc
+	schedules map[key]*Schedule
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+	mtx       sync.Mutex
+}
+
+// New returns a polling scheduler. onDepth receives orderbook depth changes
+// and may be nil
+func New(exchanges []exchange.IBotExchange, vol VolatilityFunc, cfg Config, onDepth DepthFunc) (*Scheduler, error) {
+	if len(exchanges) == 0 {
+		return nil, errNoExchanges
+	}
+	if vol == nil {
+		return nil, errNilVolatility
+	}
+	if cfg.Base == 0 {
+		cfg.Base = DefaultBaseInterval
+	}
+	if cfg.Min == 0 {
+		cfg.Min = DefaultMinInterval
+	}
+	if cfg.Max == 0 {
+		cfg.Max = DefaultMaxInterval
+	}
+	if cfg.Window == 0 {
+		cfg.Window = DefaultWindow
+	}
+	if cfg.MaxWeight == 0 {
+		cfg.MaxWeight = DefaultMaxWeight
+	}
+	if len(cfg.Depths) == 0 {
+		cfg.Depths = DefaultDepths
+	}
+	if cfg.Min <= 0 || cfg.Min > cfg.Base || cfg.Base > cfg.Max {
+		return nil, errInvalidInterval
+	}
+	if cfg.MaxWeight < 1 {
+		return nil, errInvalidWeight
+	}
+	cfg.Depths = append([]int(nil), cfg.Depths...)
+	sort.Ints(cfg.Depths)
+	return &Scheduler{
+		cfg:       cfg,
+		exchanges: exchanges,
+		vol:       vol,
+		onDepth:   onDepth,
+		schedules: make(map[key]*Schedule),
+	}, nil
+}
+
+// MinInterval returns the shortest interval a pair is polled at, polling
+// loops should check which pairs are due at least this often
+func (s *Scheduler) MinInterval() time.Duration {
+	return s.cfg.Min
+}
+
+// depth returns the orderbook depth of a pair's weight
+func (s *Scheduler) depth(weight float64) int {
+	switch {
+	case weight >= volatileWeight:
+		return s.cfg.Depths[len(s.cfg.Depths)-1]
+	case weight <= quietWeight:
+		return s.cfg.Depths[0]
+	default:
+		return s.cfg.Depths[len(s.cfg.Depths)/2]
+	}
+}
+
+// Update reschedules an exchange's pairs. Each pair is weighted by its
+// volatility relative to the exchange's median, capped at the max weight,
+// and polled at an interval sharing the base interval budget of all pairs by
+// weight. Intervals are then limited to the min and max intervals
+func (s *Scheduler) Update(exchangeName string, pairs []currency.Pair) {
+	now := time.Now()
+	schedules := make([]Schedule, len(pairs))
+	var known []float64
+	for i := range pairs {
+		schedules[i] = Schedule{Exchange: exchangeName, Pair: pairs[i], Weight: 1, Updated: now}
+		v, err := s.vol(exchangeName, pairs[i], s.cfg.Window)
+		if err == nil && v > 0 {
+			schedules[i].Volatility = v
+			known = append(known, v)
+		}
+	}
+
+	if len(known) > 0 {
+		sort.Float64s(known)
+		median := known[len(known)/2]
+		if len(known)%2 == 0 {
+			median = (known[len(known)/2-1] + known[len(known)/2]) / 2
+		}
+		for i := range schedules {
+			if schedules[i].Volatility > 0 {
+				w := schedules[i].Volatility / median
+				schedules[i].Weight = math.Max(1/s.cfg.MaxWeight, math.Min(s.cfg.MaxWeight, w))
+			}
+		}
+	}
+	var total float64
+	for i := range schedules {
+		total += schedules[i].Weight
+	}
+
+	type change struct {
+		pair  currency.Pair
+		depth int
+	}
+	var changes []change
+	s.mtx.Lock()
+	for i := range schedules {
+		sc := &schedules[i]
+		// The budget of len(pairs) polls per base interval is shared by
+		// weight, a pair receiving weight/total of it
+		interval := time.Duration(float64(s.cfg.Base) * total / (float64(len(schedules)) * sc.Weight))
+		if interval < s.cfg.Min {
+			interval = s.cfg.Min
+		}
+		if interval > s.cfg.Max {
+			interval = s.cfg.Max
+		}
+		sc.Interval = interval
+		sc.Depth = s.depth(sc.Weight)
+
+		k := newKey(exchangeName, sc.Pair)
+		prev, ok := s.schedules[k]
+		if ok {
+			sc.next = prev.next
+			// Pulls the next poll forward when the interval shortens
+			for kind, t := range sc.next {
+				if latest := now.Add(interval); t.After(latest) {
+					sc.next[kind] = latest
+				}
+			}
+		} else {
+			sc.next = make(map[string]time.Time)
+		}
+		if !ok || prev.Depth != sc.Depth {
+			changes = append(changes, change{pair: sc.Pair, depth: sc.Depth})
+		}
+		s.schedules[k] = sc
+	}
+	s.mtx.Unlock()
+
+	if s.onDepth != nil {
+		for i := range changes {
+			s.onDepth(exchangeName, changes[i].pair, changes[i].depth)
+		}
+	}
+}
+
+// Due returns whether a pair's data of the kind should be polled now, and if
+// so schedules its next poll. Pairs not yet scheduled are always due
+func (s *Scheduler) Due(exchangeName string, p currency.Pair, kind string, now time.Time) bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sc, ok := s.schedules[newKey(exchangeName, p)]
+	if !ok {
+		return true
+	}
+	if next, ok := sc.next[kind]; ok && now.Before(next) {
+		return false
+	}
+	sc.next[kind] = now.Add(sc.Interval)
+	return true
+}
+
+// Get returns a pair's schedule
+func (s *Scheduler) Get(exchangeName string, p currency.Pair) (Schedule, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	sc, ok := s.schedules[newKey(exchangeName, p)]
+	if !ok {
+		return Schedule{}, fmt.Errorf("%s %s %v", exchangeName, p, errPairNotTracked)
+	}
+	return *sc, nil
+}
+
+// GetAll returns every pair's schedule sorted by exchange and pair
+func (s *Scheduler) GetAll() []Schedule {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	resp := make([]Schedule, 0, len(s.schedules))
+	for _, sc := range s.schedules {
+		resp = append(resp, *sc)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		if resp[i].Exchange != resp[j].Exchange {
+			return resp[i].Exchange < resp[j].Exchange
+		}
+		return resp[i].Pair.String() < resp[j].Pair.String()
+	})
+	return resp
+}
+
+// Check reschedules the enabled pairs of every enabled exchange
+func (s *Scheduler) Check() {
+	for i := range s.exchanges {
+		if !s.exchanges[i].IsEnabled() {
+			continue
+		}
+		s.Update(s.exchanges[i].GetName(), s.exchanges[i].GetEnabledCurrencies())
+	}
+}
+
+// Start reschedules pairs at the interval until stopped
+func (s *Scheduler) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	s.mtx.Lock()
+	if s.shutdown != nil {
+		s.mtx.Unlock()
+		return
+	}
+	s.shutdown = make(chan struct{})
+	shutdown := s.shutdown
+	s.mtx.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.Check()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				s.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops rescheduling pairs
+func (s *Scheduler) Stop() {
+	s.mtx.Lock()
+	if s.shutdown == nil {
+		s.mtx.Unlock()
+		return
+	}
+	close(s.shutdown)
+	s.shutdown = nil
+	s.mtx.Unlock()
+	s.wg.Wait()
+}
@@ -0,0 +1,140 @@
+package polling
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+var (
+	btc = currency.NewPair(currency.BTC, currency.USD)
+	eth = currency.NewPair(currency.ETH, currency.USD)
+	ltc = currency.NewPair(currency.LTC, currency.USD)
+	xrp = currency.NewPair(currency.XRP, currency.USD)
+)
+
+type fakeExchange struct {
+	exchange.IBotExchange
+}
+
+func (f *fakeExchange) GetName() string { return "Kraken" }
+
+func testVolatility(vols map[string]float64) VolatilityFunc {
+	return func(_ string, p currency.Pair, _ time.Duration) (float64, error) {
+		v, ok := vols[p.Base.String()]
+		if !ok {
+			return 0, errors.New("no candles")
+		}
+		return v, nil
+	}
+}
+
+func TestNew(t *testing.T) {
+	exchanges := []exchange.IBotExchange{&fakeExchange{}}
+	vol := testVolatility(nil)
+	s, err := New(exchanges, vol, Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.cfg.Base != DefaultBaseInterval || s.MinInterval() != DefaultMinInterval ||
+		s.cfg.Max != DefaultMaxInterval || s.cfg.MaxWeight != DefaultMaxWeight || len(s.cfg.Depths) != 3 {
+		t.Errorf("Test Failed - New() config not defaulted %+v", s.cfg)
+	}
+	if _, err = New(nil, vol, Config{}, nil); err != errNoExchanges {
+		t.Error("Test Failed - New() expected no exchanges error", err)
+	}
+	if _, err = New(exchanges, nil, Config{}, nil); err != errNilVolatility {
+		t.Error("Test Failed - New() expected nil volatility error", err)
+	}
+	if _, err = New(exchanges, vol, Config{Min: time.Minute}, nil); err != errInvalidInterval {
+		t.Error("Test Failed - New() expected invalid interval error", err)
+	}
+	if _, err = New(exchanges, vol, Config{MaxWeight: 0.5}, nil); err != errInvalidWeight {
+		t.Error("Test Failed - New() expected invalid weight error", err)
+	}
+}
+
+func TestUpdate(t *testing.T) {
+	depths := make(map[string]int)
+	vols := map[string]float64{"BTC": 0.8, "ETH": 1, "LTC": 4}
+	s, err := New([]exchange.IBotExchange{&fakeExchange{}}, testVolatility(vols),
+		Config{Base: time.Second * 10, Min: time.Second, Max: time.Minute},
+		func(_ string, p currency.Pair, depth int) { depths[p.Base.String()] = depth })
+	if err != nil {
+		t.Fatal(err)
+	}
+	s.Update("Kraken", []currency.Pair{btc, eth, ltc, xrp})
+
+	// The median of 0.8, 1 and 4 is 1 and XRP's unknown volatility is
+	// weighted as the median, 6.8 weight sharing 4 polls per 10s
+	for _, tc := range []struct {
+		pair     currency.Pair
+		weight   float64
+		interval time.Duration
+		depth    int
+	}{
+		{btc, 0.8, time.Millisecond * 21250, 25},
+		{eth, 1, time.Second * 17, 25},
+		{ltc, 4, time.Millisecond * 4250, 100},
+		{xrp, 1, time.Second * 17, 25},
+	} {
+		sc, err := s.Get("kraken", tc.pair)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if sc.Weight != tc.weight || sc.Interval != tc.interval || sc.Depth != tc.depth {
+			t.Errorf("Test Failed - Update() %s unexpected schedule %+v", tc.pair, sc)
+		}
+		if depths[tc.pair.Base.String()] != tc.depth {
+			t.Errorf("Test Failed - Update() %s depth not applied", tc.pair)
+		}
+	}
+
+	// LTC quietens to the weight floor, moving the median to 0.8, and only
+	// its depth change is applied
+	vols["LTC"] = 0.1
+	depths = make(map[string]int)
+	s.Update("Kraken", []currency.Pair{btc, eth, ltc, xrp})
+	sc, err := s.Get("Kraken", ltc)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sc.Weight != 0.25 || sc.Interval != time.Second*35 || sc.Depth != 10 {
+		t.Errorf("Test Failed - Update() unexpected quiet schedule %+v", sc)
+	}
+	if len(depths) != 1 || depths["LTC"] != 10 {
+		t.Errorf("Test Failed - Update() unexpected depth changes %v", depths)
+	}
+
+	if all := s.GetAll(); len(all) != 4 || all[0].Pair != btc {
+		t.Errorf("Test Failed - GetAll() unexpected schedules %+v", all)
+	}
+	if _, err = s.Get("Bitstamp", btc); err == nil {
+		t.Error("Test Failed - Get() expected pair not scheduled error")
+	}
+}
+
+func TestDue(t *testing.T) {
+	s, err := New([]exchange.IBotExchange{&fakeExchange{}}, testVolatility(map[string]float64{"BTC": 1}), Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if !s.Due("Kraken", btc, Ticker, now) {
+		t.Error("Test Failed - Due() unscheduled pair should be due")
+	}
+
+	s.Update("Kraken", []currency.Pair{btc})
+	if !s.Due("Kraken", btc, Ticker, now) || s.Due("Kraken", btc, Ticker, now.Add(time.Second)) {
+		t.Error("Test Failed - Due() ticker should be due once per interval")
+	}
+	if !s.Due("Kraken", btc, Orderbook, now) {
+		t.Error("Test Failed - Due() kinds should be scheduled separately")
+	}
+	if !s.Due("Kraken", btc, Ticker, now.Add(DefaultBaseInterval)) {
+		t.Error("Test Failed - Due() ticker should be due after its interval")
+	}
+}
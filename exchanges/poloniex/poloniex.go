@@ -8,6 +8,7 @@ import (
 	"net/http"
 	"net/url"
 	"strconv"
+	"sync"
 	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
@@ -58,10 +59,16 @@ const (
 	poloniexDateLayout = "2006-01-02 15:04:05"
 )
 
+// ErrNoOpenLoanOffers is returned when the account has no open loan offers
+var ErrNoOpenLoanOffers = errors.New("there are no open loan offers")
+
 // Poloniex is the overarching type across the poloniex package
 type Poloniex struct {
 	exchange.Base
 	WebsocketConn *wshandler.WebsocketConnection
+	markets       marketStatus
+	wsOrders      map[int64]*wsOrder
+	wsOrderMtx    sync.Mutex
 }
 
 // SetDefaults sets default settings for poloniex
@@ -92,7 +99,9 @@ func (p *Poloniex) SetDefaults() {
 		wshandler.WebsocketTickerSupported |
 		wshandler.WebsocketSubscribeSupported |
 		wshandler.WebsocketUnsubscribeSupported |
-		wshandler.WebsocketAuthenticatedEndpointsSupported
+		wshandler.WebsocketAuthenticatedEndpointsSupported |
+		wshandler.WebsocketAccountDataSupported
+	p.wsOrders = make(map[int64]*wsOrder)
 	p.WebsocketResponseMaxLimit = exchange.DefaultWebsocketResponseMaxLimit
 	p.WebsocketResponseCheckTimeout = exchange.DefaultWebsocketResponseCheckTimeout
 }
@@ -633,6 +642,32 @@ func (p *Poloniex) GetFeeInfo() (Fee, error) {
 	return result, p.SendAuthenticatedHTTPRequest(http.MethodPost, poloniexFeeInfo, url.Values{}, &result)
 }
 
+// GetAvailableBalances returns the available balances of each account, such
+// as exchange, margin and lending. An empty account returns every account
+func (p *Poloniex) GetAvailableBalances(account string) (map[string]map[string]float64, error) {
+	values := url.Values{}
+	if account != "" {
+		values.Set("account", account)
+	}
+
+	var result map[string]map[string]interface{}
+	err := p.SendAuthenticatedHTTPRequest(http.MethodPost, poloniexAvailableBalances, values, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	balances := make(map[string]map[string]float64)
+	for x, y := range result {
+		balances[x] = make(map[string]float64)
+		for z, w := range y {
+			if v, ok := w.(string); ok {
+				balances[x][z], _ = strconv.ParseFloat(v, 64)
+			}
+		}
+	}
+	return balances, nil
+}
+
 // GetTradableBalances returns tradable balances
 func (p *Poloniex) GetTradableBalances() (map[string]map[string]float64, error) {
 	type Response struct {
@@ -816,7 +851,7 @@ func (p *Poloniex) GetOpenLoanOffers() (map[string][]LoanOffer, error) {
 	}
 
 	if result.Data == nil {
-		return nil, errors.New("there are no open loan offers")
+		return nil, ErrNoOpenLoanOffers
 	}
 
 	return result.Data, nil
@@ -887,12 +922,28 @@ func (p *Poloniex) SendHTTPRequest(path string, result interface{}) error {
 		p.HTTPDebugging)
 }
 
+// mutating returns whether the trading API command changes exchange state.
+// Every command is a POST so they are classified by name
+func mutating(command string) bool {
+	switch command {
+	case poloniexOrderBuy, poloniexOrderSell, poloniexOrderCancel, poloniexOrderMove,
+		poloniexWithdraw, poloniexTransferBalance, poloniexMarginBuy, poloniexMarginSell,
+		poloniexMarginPositionClose, poloniexCreateLoanOffer, poloniexCancelLoanOffer,
+		poloniexAutoRenew:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP request
 func (p *Poloniex) SendAuthenticatedHTTPRequest(method, endpoint string, values url.Values, result interface{}) error {
 	if !p.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet,
 			p.Name)
 	}
+	if p.DryRunRequest(mutating(endpoint), endpoint, values) {
+		return nil
+	}
 	headers := make(map[string]string)
 	headers["Content-Type"] = "application/x-www-form-urlencoded"
 	headers["Key"] = p.APIKey
@@ -0,0 +1,151 @@
+package poloniex
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// marketStatusTTL is how long market statuses are used before being refetched
+const marketStatusTTL = time.Minute * 5
+
+// marketStatus caches the markets which cannot be traded
+type marketStatus struct {
+	untradeable map[string]string
+	updated     time.Time
+	mtx         sync.Mutex
+}
+
+// UntradeableMarkets returns the reason each market cannot be traded, from the
+// currency disabled, delisted and frozen flags and the ticker frozen flag
+func UntradeableMarkets(currencies map[string]Currencies, tickers map[string]Ticker) map[string]string {
+	reasons := make(map[string]string)
+	for symbol := range tickers {
+		var why []string
+		if tickers[symbol].IsFrozen != 0 {
+			why = append(why, "market frozen")
+		}
+		for _, code := range strings.Split(symbol, "_") {
+			c, ok := currencies[code]
+			if !ok {
+				continue
+			}
+			if c.Delisted != 0 {
+				why = append(why, code+" delisted")
+			}
+			if c.Frozen != 0 {
+				why = append(why, code+" frozen")
+			}
+			if c.Disabled != 0 {
+				why = append(why, code+" disabled")
+			}
+		}
+		if len(why) > 0 {
+			sort.Strings(why)
+			reasons[symbol] = strings.Join(why, ", ")
+		}
+	}
+	return reasons
+}
+
+// UpdateMarketStatus fetches the markets which cannot currently be traded
+func (p *Poloniex) UpdateMarketStatus() error {
+	currencies, err := p.GetCurrencies()
+	if err != nil {
+		return err
+	}
+	tickers, err := p.GetTicker()
+	if err != nil {
+		return err
+	}
+	untradeable := UntradeableMarkets(currencies, tickers)
+
+	p.markets.mtx.Lock()
+	p.markets.untradeable = untradeable
+	p.markets.updated = time.Now()
+	p.markets.mtx.Unlock()
+	return nil
+}
+
+// GetUntradeablePairs returns the reason each frozen, disabled or delisted
+// market cannot be traded, refetching statuses older than five minutes
+func (p *Poloniex) GetUntradeablePairs() (map[string]string, error) {
+	p.markets.mtx.Lock()
+	stale := time.Since(p.markets.updated) > marketStatusTTL
+	p.markets.mtx.Unlock()
+	if stale {
+		if err := p.UpdateMarketStatus(); err != nil {
+			return nil, err
+		}
+	}
+
+	p.markets.mtx.Lock()
+	defer p.markets.mtx.Unlock()
+	resp := make(map[string]string, len(p.markets.untradeable))
+	for k, v := range p.markets.untradeable {
+		resp[k] = v
+	}
+	return resp, nil
+}
+
+// CheckTradeable returns an error when the pair's market is frozen, disabled
+// or delisted. Orders are allowed when statuses cannot be fetched, as the
+// exchange still rejects orders to frozen markets itself
+func (p *Poloniex) CheckTradeable(pair currency.Pair) error {
+	untradeable, err := p.GetUntradeablePairs()
+	if err != nil {
+		p.markets.mtx.Lock()
+		untradeable = p.markets.untradeable
+		p.markets.mtx.Unlock()
+		if untradeable == nil {
+			log.Warnf("%s market status unavailable, order to %s not checked: %s",
+				p.Name, pair, err)
+			return nil
+		}
+	}
+	symbol := exchange.FormatExchangeCurrency(p.Name, pair).String()
+	if reason, ok := untradeable[symbol]; ok {
+		return fmt.Errorf("%s %s %v: %s", p.Name, symbol, exchange.ErrPairNotTradeable, reason)
+	}
+	return nil
+}
+
+// CurrencyNetworkStatus returns the funding status of a currency. Disabled
+// currencies cannot be deposited or withdrawn, delisted currencies can only be
+// withdrawn and frozen currencies only halt trading
+func CurrencyNetworkStatus(c *Currencies) exchange.NetworkStatus {
+	s := exchange.NetworkStatus{DepositEnabled: true, WithdrawEnabled: true}
+	if c.Delisted != 0 {
+		s.DepositEnabled = false
+		s.Reason = "delisted"
+	}
+	if c.Disabled != 0 {
+		s.DepositEnabled, s.WithdrawEnabled = false, false
+		s.Reason = "disabled"
+	}
+	return s
+}
+
+// GetAssetStatus returns whether deposits and withdrawals of a currency are
+// enabled
+func (p *Poloniex) GetAssetStatus(c currency.Code) (exchange.AssetStatus, error) {
+	currencies, err := p.GetCurrencies()
+	if err != nil {
+		return exchange.AssetStatus{}, err
+	}
+	info, ok := currencies[c.Upper().String()]
+	if !ok {
+		return exchange.AssetStatus{}, fmt.Errorf("%s currency %s not found", p.Name, c)
+	}
+	return exchange.AssetStatus{
+		Exchange: p.Name,
+		Currency: c,
+		Networks: []exchange.NetworkStatus{CurrencyNetworkStatus(&info)},
+	}, nil
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/sharedtestvalues"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
 )
@@ -83,6 +84,18 @@ func TestGetChartData(t *testing.T) {
 	}
 }
 
+func TestGetHistoricCandles(t *testing.T) {
+	t.Parallel()
+	_, err := p.GetHistoricCandles(currency.NewPairWithDelimiter("BTC", "XMR", "_"), "SPOT", kline.FiveMin, 24)
+	if err != nil {
+		t.Error("Test faild - Poloniex GetHistoricCandles() error", err)
+	}
+	_, err = p.GetHistoricCandles(currency.NewPairWithDelimiter("BTC", "XMR", "_"), "SPOT", kline.OneMin, 24)
+	if err != exchange.ErrCandleIntervalUnsupported {
+		t.Error("Test faild - Poloniex GetHistoricCandles() expected unsupported interval error", err)
+	}
+}
+
 func TestGetCurrencies(t *testing.T) {
 	t.Parallel()
 	_, err := p.GetCurrencies()
@@ -91,6 +104,62 @@ func TestGetCurrencies(t *testing.T) {
 	}
 }
 
+func TestUntradeableMarkets(t *testing.T) {
+	t.Parallel()
+	currencies := map[string]Currencies{
+		"BTC": {},
+		"ETH": {},
+		"XYZ": {Delisted: 1, Disabled: 1},
+		"LTC": {Frozen: 1},
+	}
+	tickers := map[string]Ticker{
+		"BTC_ETH":  {},
+		"BTC_XYZ":  {},
+		"BTC_LTC":  {},
+		"BTC_DOGE": {IsFrozen: 1},
+	}
+	resp := UntradeableMarkets(currencies, tickers)
+	if len(resp) != 3 {
+		t.Fatalf("Test Failed - UntradeableMarkets() expected 3 markets, got %v", resp)
+	}
+	if resp["BTC_XYZ"] != "XYZ delisted, XYZ disabled" ||
+		resp["BTC_LTC"] != "LTC frozen" ||
+		resp["BTC_DOGE"] != "market frozen" {
+		t.Errorf("Test Failed - UntradeableMarkets() unexpected reasons %v", resp)
+	}
+}
+
+func TestCurrencyNetworkStatus(t *testing.T) {
+	t.Parallel()
+	s := CurrencyNetworkStatus(&Currencies{Frozen: 1})
+	if !s.DepositEnabled || !s.WithdrawEnabled {
+		t.Errorf("Test Failed - CurrencyNetworkStatus() frozen currencies can be transferred %+v", s)
+	}
+	s = CurrencyNetworkStatus(&Currencies{Delisted: 1})
+	if s.DepositEnabled || !s.WithdrawEnabled || s.Reason != "delisted" {
+		t.Errorf("Test Failed - CurrencyNetworkStatus() expected delisted withdrawals only %+v", s)
+	}
+	s = CurrencyNetworkStatus(&Currencies{Delisted: 1, Disabled: 1})
+	if s.DepositEnabled || s.WithdrawEnabled {
+		t.Errorf("Test Failed - CurrencyNetworkStatus() expected disabled %+v", s)
+	}
+}
+
+func TestCheckTradeable(t *testing.T) {
+	var e Poloniex
+	e.Name = p.Name
+	e.markets.untradeable = map[string]string{"BTC_XYZ": "XYZ delisted"}
+	e.markets.updated = time.Now()
+
+	err := e.CheckTradeable(currency.NewPairWithDelimiter("BTC", "XYZ", "_"))
+	if err == nil || err.Error() != "Poloniex BTC_XYZ market is not tradeable: XYZ delisted" {
+		t.Error("Test Failed - CheckTradeable() expected not tradeable error", err)
+	}
+	if err = e.CheckTradeable(currency.NewPairWithDelimiter("BTC", "ETH", "_")); err != nil {
+		t.Error("Test Failed - CheckTradeable() unexpected error", err)
+	}
+}
+
 func TestGetLoanOrders(t *testing.T) {
 	t.Parallel()
 	_, err := p.GetLoanOrders("BTC")
@@ -99,6 +168,17 @@ func TestGetLoanOrders(t *testing.T) {
 	}
 }
 
+func TestGetBorrowRates(t *testing.T) {
+	t.Parallel()
+	rates, err := p.GetBorrowRates(currency.BTC)
+	if err != nil {
+		t.Error("Test Failed - Poloniex GetBorrowRates() error", err)
+	}
+	if len(rates) != 1 || rates[0].Period != time.Hour*24 {
+		t.Error("Test Failed - Poloniex GetBorrowRates() expected a daily rate")
+	}
+}
+
 func setFeeBuilder() *exchange.FeeBuilder {
 	return &exchange.FeeBuilder{
 		Amount:  1,
@@ -282,6 +362,33 @@ func TestSubmitOrder(t *testing.T) {
 	}
 }
 
+func TestSubmitOrderWithTimeInForce(t *testing.T) {
+	t.Parallel()
+	TestSetup(t)
+
+	if areTestAPIKeysSet() && !canManipulateRealOrders {
+		t.Skip("API keys set, canManipulateRealOrders false, skipping test")
+	}
+
+	var pair = currency.Pair{
+		Delimiter: "_",
+		Base:      currency.BTC,
+		Quote:     currency.LTC,
+	}
+	_, err := p.SubmitOrderWithTimeInForce(pair, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 10, "", exchange.GoodTillDate, time.Now().Add(time.Hour))
+	if err != exchange.ErrTimeInForceUnsupported {
+		t.Errorf("Test Failed - SubmitOrderWithTimeInForce() expected %v, received %v",
+			exchange.ErrTimeInForceUnsupported, err)
+	}
+
+	response, err := p.SubmitOrderWithTimeInForce(pair, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 10, "", exchange.ImmediateOrCancel, time.Time{})
+	if areTestAPIKeysSet() && (err != nil || !response.IsOrderPlaced) {
+		t.Errorf("Order failed to be placed: %v", err)
+	} else if !areTestAPIKeysSet() && err == nil {
+		t.Error("Expecting an error when no keys are set")
+	}
+}
+
 func TestCancelExchangeOrder(t *testing.T) {
 	t.Parallel()
 	TestSetup(t)
@@ -420,23 +527,82 @@ func TestGetDepositAddress(t *testing.T) {
 	}
 }
 
+func TestGetAvailableBalances(t *testing.T) {
+	t.Parallel()
+	TestSetup(t)
+
+	_, err := p.GetAvailableBalances("lending")
+	if areTestAPIKeysSet() && err != nil {
+		t.Error("Test Failed - GetAvailableBalances()", err)
+	} else if !areTestAPIKeysSet() && err == nil {
+		t.Error("Test Failed - GetAvailableBalances() expecting an error when no keys are set")
+	}
+}
+
 func TestWsHandleAccountData(t *testing.T) {
 	t.Parallel()
 	TestSetup(t)
 	p.Websocket.DataHandler = sharedtestvalues.GetWebsocketInterfaceChannelOverride()
+	if CurrencyIDMap == nil {
+		CurrencyIDMap = map[string]int{"ETH": 267}
+	}
 	jsons := []string{
 		`[["n",225,807230187,0,"1000.00000000","0.10000000","2018-11-07 16:42:42"],["b",267,"e","-0.10000000"]]`,
-		`[["o",807230187,"0.00000000"],["b",267,"e","0.10000000"]]`,
+		`[["o",807230187,"0.00000000"],["t",12345,"0.03000000","0.10000000","0.00250000",0,807230187,"0.00025000","2018-11-07 16:42:43"],["b",267,"e","0.10000000"]]`,
 		`[["t", 12345, "0.03000000", "0.50000000", "0.00250000", 0, 6083059, "0.00000375", "2018-09-08 05:54:09"]]`,
 	}
+	var updates []wshandler.OrderUpdate
+	var balances []wshandler.BalanceUpdate
 	for i := range jsons {
-		var result [][]interface{}
+		var result []interface{}
 		err := common.JSONDecode([]byte(jsons[i]), &result)
 		if err != nil {
 			t.Error(err)
 		}
 		p.wsHandleAccountData(result)
+		for len(p.Websocket.DataHandler) > 0 {
+			switch d := (<-p.Websocket.DataHandler).(type) {
+			case wshandler.OrderUpdate:
+				updates = append(updates, d)
+			case wshandler.BalanceUpdate:
+				balances = append(balances, d)
+			case error:
+				t.Error(d)
+			}
+		}
+	}
+
+	if len(updates) != 4 {
+		t.Fatalf("Test Failed - wsHandleAccountData() expected 4 order updates got %+v", updates)
+	}
+	placed, closed, trade := updates[0], updates[1], updates[2]
+	if placed.Status != string(exchange.NewOrderStatus) ||
+		placed.Side != string(exchange.SellOrderSide) ||
+		placed.Pair.String() != "USDC_ETH" ||
+		placed.Remaining != 0.1 {
+		t.Errorf("Test Failed - wsHandleAccountData() unexpected new order %+v", placed)
+	}
+	if closed.Status != string(exchange.FilledOrderStatus) || closed.Pair.String() != "USDC_ETH" {
+		t.Errorf("Test Failed - wsHandleAccountData() unexpected order update %+v", closed)
+	}
+	if trade.Status != string(exchange.FilledOrderStatus) ||
+		trade.Filled != 0.1 ||
+		trade.FillPrice != 0.03 ||
+		trade.Fee != 0.00025 ||
+		trade.FeeCurrency != currency.USDC {
+		t.Errorf("Test Failed - wsHandleAccountData() unexpected trade %+v", trade)
 	}
+	if updates[3].Status != string(exchange.UnknownOrderStatus) || updates[3].OrderID != "6083059" {
+		t.Errorf("Test Failed - wsHandleAccountData() expected unknown order trade %+v", updates[3])
+	}
+	if len(balances) != 2 || balances[0].Change != -0.1 || balances[0].Wallet != "exchange" {
+		t.Errorf("Test Failed - wsHandleAccountData() unexpected balance updates %+v", balances)
+	}
+	p.wsOrderMtx.Lock()
+	if len(p.wsOrders) != 0 {
+		t.Errorf("Test Failed - wsHandleAccountData() expected filled orders removed %v", p.wsOrders)
+	}
+	p.wsOrderMtx.Unlock()
 }
 
 // TestWsAuth dials websocket, sends login request.
@@ -473,3 +639,17 @@ func TestWsAuth(t *testing.T) {
 	}
 	timer.Stop()
 }
+
+func TestDryRunTransferBalance(t *testing.T) {
+	t.Parallel()
+	var d Poloniex
+	d.SetDefaults()
+	d.AuthenticatedAPISupport = true
+	d.SetDryRun(true)
+	if ok, err := d.TransferBalance("BTC", "exchange", "lending", 1); err != nil || !ok {
+		t.Error("Test Failed - TransferBalance() expected dry run to skip request", err)
+	}
+	if mutating(poloniexBalances) || !mutating(poloniexCreateLoanOffer) {
+		t.Error("Test Failed - mutating() expected only state changing commands")
+	}
+}
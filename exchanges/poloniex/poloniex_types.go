@@ -257,7 +257,9 @@ type MarginPosition struct {
 
 // LoanOffer holds loan offer information
 type LoanOffer struct {
-	ID        int64   `json:"id"`
+	ID int64 `json:"id"`
+	// Currency is only set on active loans
+	Currency  string  `json:"currency,omitempty"`
 	Rate      float64 `json:"rate,string"`
 	Amount    float64 `json:"amount,string"`
 	Duration  int     `json:"duration"`
@@ -408,37 +410,44 @@ var WithdrawalFees = map[currency.Code]float64{
 
 // WsAccountBalanceUpdateResponse Authenticated Ws Account data
 type WsAccountBalanceUpdateResponse struct {
-	currencyID float64
-	wallet     string
-	amount     float64
+	CurrencyID int64
+	// Wallet is e for exchange, m for margin and l for lending
+	Wallet string
+	Amount float64
 }
 
 // WsNewLimitOrderResponse Authenticated Ws Account data
 type WsNewLimitOrderResponse struct {
-	currencyID  float64
-	orderNumber float64
-	orderType   float64
-	rate        float64
-	amount      float64
-	date        time.Time
+	CurrencyPairID int64
+	OrderNumber    int64
+	// OrderType is 1 for buy and 0 for sell
+	OrderType int64
+	Rate      float64
+	Amount    float64
+	Date      time.Time
 }
 
 // WsOrderUpdateResponse Authenticated Ws Account data
 type WsOrderUpdateResponse struct {
-	OrderNumber float64
-	NewAmount   string
+	OrderNumber int64
+	NewAmount   float64
+	// UpdateType is f for a fill, c for a cancel and s for a self trade
+	// prevention cancel, empty when not sent
+	UpdateType string
 }
 
 // WsTradeNotificationResponse Authenticated Ws Account data
 type WsTradeNotificationResponse struct {
-	TradeID       float64
+	TradeID       int64
 	Rate          float64
 	Amount        float64
 	FeeMultiplier float64
-	FundingType   float64
-	OrderNumber   float64
-	TotalFee      float64
-	Date          time.Time
+	// FundingType is 0 for exchange, 1 for borrowed, 2 for margin and 3 for
+	// lending wallet funds
+	FundingType int64
+	OrderNumber int64
+	TotalFee    float64
+	Date        time.Time
 }
 
 // WsAuthorisationRequest Authenticated Ws Account data request
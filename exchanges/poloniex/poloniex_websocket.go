@@ -13,6 +13,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
 	log "github.com/thrasher-corp/gocryptotrader/logger"
 )
@@ -23,8 +24,19 @@ const (
 	wsTickerDataID           = 1002
 	ws24HourExchangeVolumeID = 1003
 	wsHeartbeat              = 1010
+	// wsDateLayout is the layout of UTC dates in account notifications
+	wsDateLayout = "2006-01-02 15:04:05"
 )
 
+var errWsNotificationFields = errors.New("missing notification fields")
+
+// wsWallets maps the wallets of balance notifications to their names
+var wsWallets = map[string]string{
+	"e": "exchange",
+	"m": "margin",
+	"l": "lending",
+}
+
 var (
 	// CurrencyIDMap stores a map of currencies associated with their ID
 	CurrencyIDMap map[string]int
@@ -114,7 +126,12 @@ func (p *Poloniex) WsHandleData() {
 
 				switch chanID {
 				case wsAccountNotificationID:
-					p.wsHandleAccountData(data[2].([][]interface{}))
+					notifications, ok := data[2].([]interface{})
+					if !ok {
+						p.Websocket.DataHandler <- fmt.Errorf("%s websocket unexpected account data %v", p.Name, data[2])
+						continue
+					}
+					p.wsHandleAccountData(notifications)
 				case wsTickerDataID:
 					p.wsHandleTickerData(data)
 				case ws24HourExchangeVolumeID:
@@ -215,66 +232,234 @@ func (p *Poloniex) wsHandleTickerData(data []interface{}) {
 	t.LowestTradePrice24H, _ = strconv.ParseFloat(tickerData[9].(string), 64)
 
 	p.Websocket.DataHandler <- wshandler.TickerData{
-		Timestamp: time.Now(),
-		Exchange:  p.GetName(),
-		AssetType: "SPOT",
-		LowPrice:  t.LowestAsk,
-		HighPrice: t.HighestBid,
+		Timestamp:  time.Now(),
+		Exchange:   p.GetName(),
+		AssetType:  "SPOT",
+		Pair:       currency.NewPairFromString(CurrencyPairID[int(tickerData[0].(float64))]),
+		ClosePrice: t.LastPrice,
+		Quantity:   t.BaseCurrencyVolume24H,
+		HighPrice:  t.HighestTradeIn24H,
+		LowPrice:   t.LowestTradePrice24H,
+		BidPrice:   t.HighestBid,
+		AskPrice:   t.LowestAsk,
 	}
 }
 
-// wsHandleAccountData Parses account data and sends to datahandler
-func (p *Poloniex) wsHandleAccountData(accountData [][]interface{}) {
-	for i := range accountData {
-		switch accountData[i][0].(string) {
+// wsOrder holds an open order of the account, trade notifications only
+// reference the order number
+type wsOrder struct {
+	pair      currency.Pair
+	side      exchange.OrderSide
+	price     float64
+	remaining float64
+	closed    bool
+}
+
+// wsHandleAccountData parses a batch of account notifications into order and
+// balance updates
+func (p *Poloniex) wsHandleAccountData(notifications []interface{}) {
+	for i := range notifications {
+		n, ok := notifications[i].([]interface{})
+		if !ok || len(n) == 0 {
+			p.Websocket.DataHandler <- fmt.Errorf("%s websocket unexpected account notification %v",
+				p.Name, notifications[i])
+			continue
+		}
+		var err error
+		switch n[0] {
 		case "b":
-			amount, _ := strconv.ParseFloat(accountData[i][3].(string), 64)
-			response := WsAccountBalanceUpdateResponse{
-				currencyID: accountData[i][1].(float64),
-				wallet:     accountData[i][2].(string),
-				amount:     amount,
-			}
-			p.Websocket.DataHandler <- response
+			err = p.wsHandleBalanceUpdate(n)
 		case "n":
-			timeParse, _ := time.Parse("2006-01-02 15:04:05", accountData[i][6].(string))
-			rate, _ := strconv.ParseFloat(accountData[i][4].(string), 64)
-			amount, _ := strconv.ParseFloat(accountData[i][5].(string), 64)
-
-			response := WsNewLimitOrderResponse{
-				currencyID:  accountData[i][1].(float64),
-				orderNumber: accountData[i][2].(float64),
-				orderType:   accountData[i][3].(float64),
-				rate:        rate,
-				amount:      amount,
-				date:        timeParse,
-			}
-			p.Websocket.DataHandler <- response
+			err = p.wsHandleNewOrder(n)
 		case "o":
-			response := WsOrderUpdateResponse{
-				OrderNumber: accountData[i][1].(float64),
-				NewAmount:   accountData[i][2].(string),
-			}
-			p.Websocket.DataHandler <- response
+			err = p.wsHandleOrderUpdate(n)
 		case "t":
-			timeParse, _ := time.Parse("2006-01-02 15:04:05", accountData[i][8].(string))
-			rate, _ := strconv.ParseFloat(accountData[i][2].(string), 64)
-			amount, _ := strconv.ParseFloat(accountData[i][3].(string), 64)
-			feeMultiplier, _ := strconv.ParseFloat(accountData[i][4].(string), 64)
-			totalFee, _ := strconv.ParseFloat(accountData[i][7].(string), 64)
-
-			response := WsTradeNotificationResponse{
-				TradeID:       accountData[i][1].(float64),
-				Rate:          rate,
-				Amount:        amount,
-				FeeMultiplier: feeMultiplier,
-				FundingType:   accountData[i][5].(float64),
-				OrderNumber:   accountData[i][6].(float64),
-				TotalFee:      totalFee,
-				Date:          timeParse,
+			err = p.wsHandleTrade(n)
+		}
+		if err != nil {
+			p.Websocket.DataHandler <- fmt.Errorf("%s websocket account notification %v error: %s",
+				p.Name, n[0], err)
+		}
+	}
+
+	// Trades follow the update closing their order within the batch, so
+	// closed orders are forgotten once the batch is handled
+	p.wsOrderMtx.Lock()
+	for id, o := range p.wsOrders {
+		if o.closed {
+			delete(p.wsOrders, id)
+		}
+	}
+	p.wsOrderMtx.Unlock()
+}
+
+func (p *Poloniex) wsHandleBalanceUpdate(n []interface{}) error {
+	b, err := parseWsBalanceUpdate(n)
+	if err != nil {
+		return err
+	}
+	code, ok := currencyByID(b.CurrencyID)
+	if !ok {
+		return fmt.Errorf("unknown currency ID %d", b.CurrencyID)
+	}
+	wallet, ok := wsWallets[b.Wallet]
+	if !ok {
+		wallet = b.Wallet
+	}
+	p.Websocket.DataHandler <- wshandler.BalanceUpdate{
+		Timestamp: time.Now(),
+		Exchange:  p.Name,
+		Currency:  currency.NewCode(code),
+		Wallet:    wallet,
+		Change:    b.Amount,
+	}
+	return nil
+}
+
+func (p *Poloniex) wsHandleNewOrder(n []interface{}) error {
+	o, err := parseWsNewLimitOrder(n)
+	if err != nil {
+		return err
+	}
+	symbol, ok := CurrencyPairID[int(o.CurrencyPairID)]
+	if !ok {
+		return fmt.Errorf("unknown currency pair ID %d", o.CurrencyPairID)
+	}
+	order := &wsOrder{
+		pair:      currency.NewPairFromString(symbol),
+		side:      exchange.SellOrderSide,
+		price:     o.Rate,
+		remaining: o.Amount,
+	}
+	if o.OrderType == 1 {
+		order.side = exchange.BuyOrderSide
+	}
+	p.wsOrderMtx.Lock()
+	p.wsOrders[o.OrderNumber] = order
+	p.wsOrderMtx.Unlock()
+
+	p.Websocket.DataHandler <- wshandler.OrderUpdate{
+		Timestamp: o.Date,
+		Pair:      order.pair,
+		AssetType: ticker.Spot,
+		Exchange:  p.Name,
+		OrderID:   strconv.FormatInt(o.OrderNumber, 10),
+		Side:      string(order.side),
+		Status:    string(exchange.NewOrderStatus),
+		Price:     o.Rate,
+		Remaining: o.Amount,
+	}
+	return nil
+}
+
+func (p *Poloniex) wsHandleOrderUpdate(n []interface{}) error {
+	u, err := parseWsOrderUpdate(n)
+	if err != nil {
+		return err
+	}
+	update := wshandler.OrderUpdate{
+		Timestamp: time.Now(),
+		AssetType: ticker.Spot,
+		Exchange:  p.Name,
+		OrderID:   strconv.FormatInt(u.OrderNumber, 10),
+		Status:    string(exchange.PartiallyFilledOrderStatus),
+		Remaining: u.NewAmount,
+	}
+	switch {
+	case u.UpdateType == "c" || u.UpdateType == "s":
+		update.Status = string(exchange.CancelledOrderStatus)
+	case u.NewAmount == 0:
+		update.Status = string(exchange.FilledOrderStatus)
+	}
+
+	p.wsOrderMtx.Lock()
+	if o, ok := p.wsOrders[u.OrderNumber]; ok {
+		o.remaining = u.NewAmount
+		o.closed = update.Status != string(exchange.PartiallyFilledOrderStatus)
+		update.Pair = o.pair
+		update.Side = string(o.side)
+		update.Price = o.price
+	}
+	p.wsOrderMtx.Unlock()
+
+	p.Websocket.DataHandler <- update
+	return nil
+}
+
+func (p *Poloniex) wsHandleTrade(n []interface{}) error {
+	t, err := parseWsTrade(n)
+	if err != nil {
+		return err
+	}
+	update := wshandler.OrderUpdate{
+		Timestamp: t.Date,
+		AssetType: ticker.Spot,
+		Exchange:  p.Name,
+		OrderID:   strconv.FormatInt(t.OrderNumber, 10),
+		Status:    string(exchange.UnknownOrderStatus),
+		Filled:    t.Amount,
+		FillPrice: t.Rate,
+		Fee:       t.TotalFee,
+		TradeID:   strconv.FormatInt(t.TradeID, 10),
+	}
+
+	p.wsOrderMtx.Lock()
+	if o, ok := p.wsOrders[t.OrderNumber]; ok {
+		update.Pair = o.pair
+		update.Side = string(o.side)
+		update.Price = o.price
+		update.Remaining = o.remaining
+		update.Status = string(exchange.PartiallyFilledOrderStatus)
+		if o.remaining == 0 {
+			update.Status = string(exchange.FilledOrderStatus)
+		}
+		// Fees are charged in the currency received
+		update.FeeCurrency = o.pair.Base
+		if o.side == exchange.BuyOrderSide {
+			update.FeeCurrency = o.pair.Quote
+		}
+	}
+	p.wsOrderMtx.Unlock()
+
+	p.Websocket.DataHandler <- update
+	return nil
+}
+
+// wsLoadOpenOrders remembers the orders open before subscribing to account
+// notifications so their trades can be attributed
+func (p *Poloniex) wsLoadOpenOrders() error {
+	resp, err := p.GetOpenOrdersForAllCurrencies()
+	if err != nil {
+		return err
+	}
+	p.wsOrderMtx.Lock()
+	defer p.wsOrderMtx.Unlock()
+	for symbol, orders := range resp.Data {
+		pair := currency.NewPairFromString(symbol)
+		for i := range orders {
+			o := &wsOrder{
+				pair:      pair,
+				side:      exchange.SellOrderSide,
+				price:     orders[i].Rate,
+				remaining: orders[i].Amount,
+			}
+			if orders[i].Type == "buy" {
+				o.side = exchange.BuyOrderSide
 			}
-			p.Websocket.DataHandler <- response
+			p.wsOrders[orders[i].OrderNumber] = o
+		}
+	}
+	return nil
+}
+
+// currencyByID returns the currency code of a Poloniex currency ID
+func currencyByID(id int64) (string, bool) {
+	for code, v := range CurrencyIDMap {
+		if int64(v) == id {
+			return code, true
 		}
 	}
+	return "", false
 }
 
 // WsProcessOrderbookSnapshot processes a new orderbook snapshot into a local
@@ -501,7 +686,14 @@ func (p *Poloniex) Subscribe(channelToSubscribe wshandler.WebsocketChannelSubscr
 	}
 	switch {
 	case strings.EqualFold(fmt.Sprintf("%v", wsAccountNotificationID), channelToSubscribe.Channel):
-		return p.wsSendAuthorisedCommand("subscribe")
+		err := p.wsSendAuthorisedCommand("subscribe")
+		if err != nil {
+			return err
+		}
+		if err = p.wsLoadOpenOrders(); err != nil {
+			log.Errorf("%s websocket failed to load open orders: %s", p.Name, err)
+		}
+		return nil
 	case strings.EqualFold(fmt.Sprintf("%v", wsTickerDataID), channelToSubscribe.Channel):
 		subscriptionRequest.Channel = wsTickerDataID
 	default:
@@ -538,3 +730,140 @@ func (p *Poloniex) wsSendAuthorisedCommand(command string) error {
 	}
 	return p.WebsocketConn.SendMessage(request)
 }
+
+// wsField returns a notification field, failing if the notification is short
+func wsField(n []interface{}, i int) (interface{}, error) {
+	if i >= len(n) {
+		return nil, errWsNotificationFields
+	}
+	return n[i], nil
+}
+
+// wsInt returns a notification field sent as a number or numeric string
+func wsInt(n []interface{}, i int) (int64, error) {
+	v, err := wsField(n, i)
+	if err != nil {
+		return 0, err
+	}
+	switch f := v.(type) {
+	case float64:
+		return int64(f), nil
+	case string:
+		return strconv.ParseInt(f, 10, 64)
+	}
+	return 0, fmt.Errorf("field %d unexpected type %T", i, v)
+}
+
+// wsFloat returns a notification field sent as a number or numeric string
+func wsFloat(n []interface{}, i int) (float64, error) {
+	v, err := wsField(n, i)
+	if err != nil {
+		return 0, err
+	}
+	switch f := v.(type) {
+	case float64:
+		return f, nil
+	case string:
+		return strconv.ParseFloat(f, 64)
+	}
+	return 0, fmt.Errorf("field %d unexpected type %T", i, v)
+}
+
+// wsString returns a notification field sent as a string
+func wsString(n []interface{}, i int) (string, error) {
+	v, err := wsField(n, i)
+	if err != nil {
+		return "", err
+	}
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("field %d unexpected type %T", i, v)
+	}
+	return s, nil
+}
+
+// wsTime returns a notification field sent as a UTC date
+func wsTime(n []interface{}, i int) (time.Time, error) {
+	s, err := wsString(n, i)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return time.Parse(wsDateLayout, s)
+}
+
+// parseWsBalanceUpdate parses ["b", currencyID, wallet, amount]
+func parseWsBalanceUpdate(n []interface{}) (b WsAccountBalanceUpdateResponse, err error) {
+	if b.CurrencyID, err = wsInt(n, 1); err != nil {
+		return
+	}
+	if b.Wallet, err = wsString(n, 2); err != nil {
+		return
+	}
+	b.Amount, err = wsFloat(n, 3)
+	return
+}
+
+// parseWsNewLimitOrder parses ["n", currencyPairID, orderNumber, orderType,
+// rate, amount, date]
+func parseWsNewLimitOrder(n []interface{}) (o WsNewLimitOrderResponse, err error) {
+	if o.CurrencyPairID, err = wsInt(n, 1); err != nil {
+		return
+	}
+	if o.OrderNumber, err = wsInt(n, 2); err != nil {
+		return
+	}
+	if o.OrderType, err = wsInt(n, 3); err != nil {
+		return
+	}
+	if o.Rate, err = wsFloat(n, 4); err != nil {
+		return
+	}
+	if o.Amount, err = wsFloat(n, 5); err != nil {
+		return
+	}
+	o.Date, err = wsTime(n, 6)
+	return
+}
+
+// parseWsOrderUpdate parses ["o", orderNumber, newAmount] optionally followed
+// by the update type
+func parseWsOrderUpdate(n []interface{}) (o WsOrderUpdateResponse, err error) {
+	if o.OrderNumber, err = wsInt(n, 1); err != nil {
+		return
+	}
+	if o.NewAmount, err = wsFloat(n, 2); err != nil {
+		return
+	}
+	if len(n) > 3 {
+		o.UpdateType, _ = n[3].(string)
+	}
+	return
+}
+
+// parseWsTrade parses ["t", tradeID, rate, amount, feeMultiplier,
+// fundingType, orderNumber, totalFee, date]
+func parseWsTrade(n []interface{}) (t WsTradeNotificationResponse, err error) {
+	if t.TradeID, err = wsInt(n, 1); err != nil {
+		return
+	}
+	if t.Rate, err = wsFloat(n, 2); err != nil {
+		return
+	}
+	if t.Amount, err = wsFloat(n, 3); err != nil {
+		return
+	}
+	if t.FeeMultiplier, err = wsFloat(n, 4); err != nil {
+		return
+	}
+	if t.FundingType, err = wsInt(n, 5); err != nil {
+		return
+	}
+	if t.OrderNumber, err = wsInt(n, 6); err != nil {
+		return
+	}
+	if t.TotalFee, err = wsFloat(n, 7); err != nil {
+		return
+	}
+	t.Date, err = wsTime(n, 8)
+	return
+}
@@ -10,6 +10,7 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
@@ -55,6 +56,18 @@ func (p *Poloniex) Run() {
 			log.Errorf("%s Failed to update available currencies %s.\n", p.GetName(), err)
 		}
 	}
+
+	untradeable, err := p.GetUntradeablePairs()
+	if err != nil {
+		log.Errorf("%s Failed to get market statuses %s.\n", p.GetName(), err)
+		return
+	}
+	for _, x := range p.GetEnabledCurrencies() {
+		symbol := exchange.FormatExchangeCurrency(p.Name, x).String()
+		if reason, ok := untradeable[symbol]; ok {
+			log.Warnf("%s enabled pair %s is not tradeable: %s.\n", p.GetName(), symbol, reason)
+		}
+	}
 }
 
 // UpdateTicker updates and returns the ticker for a currency pair
@@ -186,15 +199,47 @@ func (p *Poloniex) GetExchangeHistory(currencyPair currency.Pair, assetType stri
 
 // SubmitOrder submits a new order
 func (p *Poloniex) SubmitOrder(currencyPair currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	var tif exchange.TimeInForce
+	if orderType == exchange.MarketOrderType {
+		tif = exchange.FillOrKill
+	}
+	return p.placeOrder(currencyPair, side, amount, price, tif)
+}
+
+// SupportedTimeInForce returns the time in force types Poloniex orders accept
+func (p *Poloniex) SupportedTimeInForce() []exchange.TimeInForce {
+	return []exchange.TimeInForce{
+		exchange.GoodTillCancel,
+		exchange.ImmediateOrCancel,
+		exchange.FillOrKill,
+	}
+}
+
+// SubmitOrderWithTimeInForce submits a new order using the immediateOrCancel
+// and fillOrKill flags
+func (p *Poloniex) SubmitOrderWithTimeInForce(currencyPair currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string, tif exchange.TimeInForce, expiry time.Time) (exchange.SubmitOrderResponse, error) {
+	err := tif.Validate(orderType, expiry)
+	if err != nil {
+		return exchange.SubmitOrderResponse{}, err
+	}
+	if tif == exchange.GoodTillDate {
+		return exchange.SubmitOrderResponse{}, exchange.ErrTimeInForceUnsupported
+	}
+	return p.placeOrder(currencyPair, side, amount, price, tif)
+}
+
+func (p *Poloniex) placeOrder(currencyPair currency.Pair, side exchange.OrderSide, amount, price float64, tif exchange.TimeInForce) (exchange.SubmitOrderResponse, error) {
 	var submitOrderResponse exchange.SubmitOrderResponse
-	fillOrKill := orderType == exchange.MarketOrderType
+	if err := p.CheckTradeable(currencyPair); err != nil {
+		return submitOrderResponse, err
+	}
 	isBuyOrder := side == exchange.BuyOrderSide
 
 	response, err := p.PlaceOrder(currencyPair.String(),
 		price,
 		amount,
-		false,
-		fillOrKill,
+		tif == exchange.ImmediateOrCancel,
+		tif == exchange.FillOrKill,
 		isBuyOrder)
 
 	if response.OrderNumber > 0 {
@@ -418,3 +463,105 @@ func (p *Poloniex) GetSubscriptions() ([]wshandler.WebsocketChannelSubscription,
 func (p *Poloniex) AuthenticateWebsocket() error {
 	return common.ErrFunctionNotSupported
 }
+
+// GetBorrowRates returns the lowest daily rate offered on the margin lending
+// book for the currency
+func (p *Poloniex) GetBorrowRates(c currency.Code) ([]exchange.BorrowRate, error) {
+	loans, err := p.GetLoanOrders(c.Upper().String())
+	if err != nil {
+		return nil, err
+	}
+	if len(loans.Offers) == 0 {
+		return nil, fmt.Errorf("%s no loan offers for %s", p.Name, c)
+	}
+
+	rate := exchange.BorrowRate{
+		Exchange:  p.Name,
+		Currency:  c,
+		Rate:      loans.Offers[0].Rate,
+		Period:    time.Hour * 24,
+		Timestamp: time.Now(),
+	}
+	for i := range loans.Offers {
+		if loans.Offers[i].Rate < rate.Rate {
+			rate.Rate = loans.Offers[i].Rate
+		}
+		rate.Available += loans.Offers[i].Amount
+	}
+	return []exchange.BorrowRate{rate}, nil
+}
+
+// poloniexCandleIntervals holds the chart data periods Poloniex supports, the
+// period is sent in seconds
+var poloniexCandleIntervals = map[kline.Interval]bool{
+	kline.FiveMin:    true,
+	kline.FifteenMin: true,
+	kline.ThirtyMin:  true,
+	kline.TwoHour:    true,
+	kline.FourHour:   true,
+	kline.OneDay:     true,
+}
+
+// GetHistoricCandles returns the most recent candles at the interval
+func (p *Poloniex) GetHistoricCandles(currencyPair currency.Pair, _ string, interval kline.Interval, limit int) ([]exchange.Candle, error) {
+	if !poloniexCandleIntervals[interval] {
+		return nil, exchange.ErrCandleIntervalUnsupported
+	}
+
+	end := time.Now()
+	start := end.Add(-interval.Duration() * time.Duration(limit))
+	candles, err := p.getCandles(currencyPair, interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+	if limit > 0 && len(candles) > limit {
+		candles = candles[len(candles)-limit:]
+	}
+	return candles, nil
+}
+
+// GetHistoricCandlesRange returns the candles at the interval opening between
+// start and end
+func (p *Poloniex) GetHistoricCandlesRange(currencyPair currency.Pair, _ string, interval kline.Interval, start, end time.Time) ([]exchange.Candle, error) {
+	if !poloniexCandleIntervals[interval] {
+		return nil, exchange.ErrCandleIntervalUnsupported
+	}
+
+	candles, err := p.getCandles(currencyPair, interval, start, end)
+	if err != nil {
+		return nil, err
+	}
+	// The end of the chart data range is inclusive
+	for len(candles) > 0 && !candles[len(candles)-1].Time.Before(end) {
+		candles = candles[:len(candles)-1]
+	}
+	return candles, nil
+}
+
+// getCandles returns the chart data between start and end as candles
+func (p *Poloniex) getCandles(currencyPair currency.Pair, interval kline.Interval, start, end time.Time) ([]exchange.Candle, error) {
+	chart, err := p.GetChartData(exchange.FormatExchangeCurrency(p.Name, currencyPair).String(),
+		strconv.FormatInt(start.Unix(), 10),
+		strconv.FormatInt(end.Unix(), 10),
+		strconv.FormatInt(interval.Seconds(), 10))
+	if err != nil {
+		return nil, err
+	}
+
+	candles := make([]exchange.Candle, 0, len(chart))
+	for i := range chart {
+		// Ranges without trades are returned as a single zero dated entry
+		if chart[i].Date == 0 {
+			continue
+		}
+		candles = append(candles, exchange.Candle{
+			Time:   time.Unix(int64(chart[i].Date), 0),
+			Open:   chart[i].Open,
+			High:   chart[i].High,
+			Low:    chart[i].Low,
+			Close:  chart[i].Close,
+			Volume: chart[i].Volume,
+		})
+	}
+	return candles, nil
+}
@@ -0,0 +1,12 @@
+package exchange
+
+import (
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// PostOnlySubmitter is implemented by exchanges which can place limit orders
+// that are cancelled or rejected rather than executed if they would take
+// liquidity
+type PostOnlySubmitter interface {
+	SubmitPostOnlyOrder(p currency.Pair, side OrderSide, amount, price float64, clientID string) (SubmitOrderResponse, error)
+}
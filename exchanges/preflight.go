@@ -0,0 +1,58 @@
+package exchange
+
+import (
+	"fmt"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// OrderValidator is implemented by exchanges which can validate an order
+// exchange side without placing it
+type OrderValidator interface {
+	ValidateOrder(p currency.Pair, side OrderSide, orderType OrderType, amount, price float64) error
+}
+
+// Preflight wraps an exchange so orders are validated by the exchange before
+// they are submitted. Orders rejected during validation are never sent, so
+// they do not count against the exchange's order rate limits. Exchanges which
+// cannot validate orders submit them directly
+type Preflight struct {
+	IBotExchange
+}
+
+// NewPreflight returns a wrapper validating orders on the supplied exchange
+// before submission
+func NewPreflight(e IBotExchange) *Preflight {
+	return &Preflight{IBotExchange: e}
+}
+
+// Unwrap returns the underlying exchange
+func (p *Preflight) Unwrap() IBotExchange {
+	return p.IBotExchange
+}
+
+// SubmitOrder validates the order and submits it once accepted
+func (p *Preflight) SubmitOrder(pair currency.Pair, side OrderSide, orderType OrderType, amount, price float64, clientID string) (SubmitOrderResponse, error) {
+	err := p.validate(pair, side, orderType, amount, price)
+	if err != nil {
+		return SubmitOrderResponse{}, err
+	}
+	return p.IBotExchange.SubmitOrder(pair, side, orderType, amount, price, clientID)
+}
+
+// CheckOrder validates an order placed natively beneath the wrapper
+func (p *Preflight) CheckOrder(o *OrderSubmission) error {
+	return p.validate(o.Pair, o.Side, o.OrderType, o.Amount, o.Price)
+}
+
+func (p *Preflight) validate(pair currency.Pair, side OrderSide, orderType OrderType, amount, price float64) error {
+	v, ok := Underlying(p.IBotExchange).(OrderValidator)
+	if !ok {
+		return nil
+	}
+	err := v.ValidateOrder(pair, side, orderType, amount, price)
+	if err != nil {
+		return fmt.Errorf("%s %s order failed validation: %v", p.GetName(), pair, err)
+	}
+	return nil
+}
@@ -0,0 +1,50 @@
+package exchange
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testValidator struct {
+	testTIFExchange
+	validated int
+	err       error
+}
+
+func (t *testValidator) ValidateOrder(_ currency.Pair, _ OrderSide, _ OrderType, _, _ float64) error {
+	t.validated++
+	return t.err
+}
+
+func TestPreflight(t *testing.T) {
+	p := currency.NewPair(currency.BTC, currency.USD)
+	v := &testValidator{}
+	e := NewPreflight(v)
+	if _, err := e.SubmitOrder(p, BuyOrderSide, LimitOrderType, 1, 1, ""); err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if v.validated != 1 || !v.plain {
+		t.Errorf("Test Failed - SubmitOrder() expected order validated then submitted, validated %d submitted %v",
+			v.validated, v.plain)
+	}
+
+	v.plain = false
+	v.err = errors.New("EOrder:Insufficient funds")
+	if _, err := e.SubmitOrder(p, BuyOrderSide, LimitOrderType, 1, 1, ""); err == nil {
+		t.Error("Test Failed - SubmitOrder() expected validation error")
+	}
+	if v.plain {
+		t.Error("Test Failed - SubmitOrder() order failing validation was submitted")
+	}
+
+	// exchanges which cannot validate orders submit them directly
+	plain := &testTIFExchange{}
+	if _, err := NewPreflight(plain).SubmitOrder(p, BuyOrderSide, LimitOrderType, 1, 1, ""); err != nil || !plain.plain {
+		t.Error("Test Failed - SubmitOrder() expected order submitted without validation", err)
+	}
+	if Underlying(NewPreflight(plain)) != plain {
+		t.Error("Test Failed - Unwrap() did not return the underlying exchange")
+	}
+}
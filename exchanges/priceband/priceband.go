@@ -0,0 +1,358 @@
+// Package priceband protects strategies from acting on single venue price
+// manipulation and flash crash wicks. Orders are rejected while the
+// exchange's last price deviates from an index, the median last price of the
+// other venues quoting the pair, by more than the band. A breached pair can be
+// kept paused until its price has stayed back within the band for a while.
+// Orders are allowed when too few venues quote the pair to form an index
+package priceband
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default band settings
+const (
+	DefaultCheckInterval = time.Second * 10
+	// DefaultBand is the fraction the last price may deviate from the index
+	DefaultBand = 0.02
+	// DefaultMinVenues is the number of other venues required to form an
+	// index
+	DefaultMinVenues = 2
+	// DefaultMaxAge is the age after which a venue's price is left out of
+	// the index
+	DefaultMaxAge = time.Minute
+	// DefaultPause is how long a breached pair stays paused after its price
+	// returns within the band
+	DefaultPause = time.Minute
+)
+
+// Event types emitted by the guard
+const (
+	Breached = "PRICE_BAND_BREACHED"
+	Restored = "PRICE_BAND_RESTORED"
+)
+
+var (
+	// ErrOutsideBand is returned for orders on a pair whose last price is
+	// outside the band or which is paused after a breach
+	ErrOutsideBand = errors.New("last price outside index band, order rejected")
+
+	errNoPriceFunc     = errors.New("no price function supplied")
+	errInvalidBand     = errors.New("band must be between 0 and 1")
+	errInvalidSettings = errors.New("minimum venues, max age and pause cannot be negative")
+	errNoIndex         = errors.New("too few venues to form an index")
+	errNoLastPrice     = errors.New("no recent last price")
+)
+
+// Price holds a venue's last price of a pair
+type Price struct {
+	Exchange string
+	Last     float64
+	Updated  time.Time
+}
+
+// PricesFunc returns every venue's stored last price of a pair
+type PricesFunc func(p currency.Pair, assetType string) []Price
+
+// Config defines the band and how the index is formed
+type Config struct {
+	Band      float64
+	MinVenues int
+	MaxAge    time.Duration
+	// Pause keeps a breached pair paused for this long after its price
+	// returns within the band, zero only rejects orders while outside it
+	Pause time.Duration
+}
+
+// Status holds a pair's last price against the index
+type Status struct {
+	Exchange  string        `json:"exchange"`
+	Pair      currency.Pair `json:"pair"`
+	AssetType string        `json:"assetType"`
+	Last      float64       `json:"last"`
+	Index     float64       `json:"index"`
+	Venues    int           `json:"venues"`
+	// Deviation is the fraction the last price is above, or below when
+	// negative, the index
+	Deviation float64 `json:"deviation"`
+	Outside   bool    `json:"outside"`
+	// Paused is set while orders on the pair are rejected
+	Paused bool `json:"paused"`
+	// BreachedAt is when the price left the band and PausedUntil when a
+	// breached pair is allowed to trade again
+	BreachedAt  time.Time `json:"breachedAt,omitempty"`
+	PausedUntil time.Time `json:"pausedUntil,omitempty"`
+	Rejected    int64     `json:"rejected"`
+	Updated     time.Time `json:"updated"`
+}
+
+// Event defines a pair breaching the band or trading being restored
+type Event struct {
+	Type   string
+	Status Status
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	s := &e.Status
+	return fmt.Sprintf("%s %s %s last price %v %+.2f%% from index %v of %d venues",
+		e.Type, s.Exchange, s.Pair, s.Last, s.Deviation*100, s.Index, s.Venues)
+}
+
+// Guard checks orders against the band
+type Guard struct {
+	cfg      Config
+	prices   PricesFunc
+	onEvent  func(Event)
+	statuses map[string]*Status
+	now      func() time.Time
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a guard comparing prices against the band
+func New(cfg Config, prices PricesFunc, onEvent func(Event)) (*Guard, error) {
+	if prices == nil {
+		return nil, errNoPriceFunc
+	}
+	if cfg.Band < 0 || cfg.Band >= 1 {
+		return nil, errInvalidBand
+	}
+	if cfg.MinVenues < 0 || cfg.MaxAge < 0 || cfg.Pause < 0 {
+		return nil, errInvalidSettings
+	}
+	if cfg.Band == 0 {
+		cfg.Band = DefaultBand
+	}
+	if cfg.MinVenues == 0 {
+		cfg.MinVenues = DefaultMinVenues
+	}
+	if cfg.MaxAge == 0 {
+		cfg.MaxAge = DefaultMaxAge
+	}
+	return &Guard{
+		cfg:      cfg,
+		prices:   prices,
+		onEvent:  onEvent,
+		statuses: make(map[string]*Status),
+		now:      time.Now,
+	}, nil
+}
+
+func key(exchangeName string, p currency.Pair, assetType string) string {
+	return strings.ToLower(exchangeName) + " " + p.Base.Upper().String() + "-" +
+		p.Quote.Upper().String() + " " + assetType
+}
+
+// index returns the exchange's last price and the median last price of the
+// other venues with recent prices
+func (g *Guard) index(exchangeName string, p currency.Pair, assetType string, now time.Time) (last, index float64, venues int, err error) {
+	var others []float64
+	for _, price := range g.prices(p, assetType) {
+		if price.Last <= 0 || now.Sub(price.Updated) > g.cfg.MaxAge {
+			continue
+		}
+		if strings.EqualFold(price.Exchange, exchangeName) {
+			last = price.Last
+			continue
+		}
+		others = append(others, price.Last)
+	}
+	if last == 0 {
+		return 0, 0, 0, errNoLastPrice
+	}
+	if len(others) < g.cfg.MinVenues {
+		return last, 0, len(others), errNoIndex
+	}
+	sort.Float64s(others)
+	mid := len(others) / 2
+	index = others[mid]
+	if len(others)%2 == 0 {
+		index = (others[mid-1] + others[mid]) / 2
+	}
+	return last, index, len(others), nil
+}
+
+// Evaluate compares the exchange's last price of the pair with the index,
+// recording breaches and restorations. An error is returned when no index
+// could be formed
+func (g *Guard) Evaluate(exchangeName string, p currency.Pair, assetType string) (Status, error) {
+	now := g.now()
+	last, index, venues, err := g.index(exchangeName, p, assetType, now)
+	if err != nil {
+		return Status{}, fmt.Errorf("%s %s %v", exchangeName, p, err)
+	}
+
+	g.mtx.Lock()
+	k := key(exchangeName, p, assetType)
+	s, ok := g.statuses[k]
+	if !ok {
+		s = &Status{Exchange: exchangeName, Pair: p, AssetType: assetType}
+		g.statuses[k] = s
+	}
+	wasPaused, wasOutside := s.Paused, s.Outside
+	s.Last, s.Index, s.Venues, s.Updated = last, index, venues, now
+	s.Deviation = last/index - 1
+	s.Outside = math.Abs(s.Deviation) > g.cfg.Band
+	if s.Outside {
+		if !wasOutside {
+			s.BreachedAt = now
+		}
+		s.PausedUntil = now.Add(g.cfg.Pause)
+	}
+	s.Paused = s.Outside || now.Before(s.PausedUntil)
+	status := *s
+	g.mtx.Unlock()
+
+	if status.Outside && !wasOutside {
+		g.emit(Event{Type: Breached, Status: status})
+	} else if wasPaused && !status.Paused {
+		g.emit(Event{Type: Restored, Status: status})
+	}
+	return status, nil
+}
+
+func (g *Guard) emit(e Event) {
+	log.Debugln(e.String())
+	if g.onEvent != nil {
+		g.onEvent(e)
+	}
+}
+
+// Allow returns ErrOutsideBand when orders on the pair must be rejected,
+// counting the rejection
+func (g *Guard) Allow(exchangeName string, p currency.Pair, assetType string) error {
+	s, err := g.Evaluate(exchangeName, p, assetType)
+	if err != nil {
+		log.Debugf("Price band allowing order: %s", err)
+		return nil
+	}
+	if !s.Paused {
+		return nil
+	}
+	g.mtx.Lock()
+	g.statuses[key(exchangeName, p, assetType)].Rejected++
+	g.mtx.Unlock()
+	return fmt.Errorf("%s %s last price %v deviates %+.2f%% from index %v: %v",
+		exchangeName, p, s.Last, s.Deviation*100, s.Index, ErrOutsideBand)
+}
+
+// Check re-evaluates paused pairs so trading is restored once their price has
+// stayed within the band for the pause
+func (g *Guard) Check() {
+	var paused []Status
+	g.mtx.Lock()
+	for _, s := range g.statuses {
+		if s.Paused {
+			paused = append(paused, *s)
+		}
+	}
+	g.mtx.Unlock()
+	for i := range paused {
+		_, err := g.Evaluate(paused[i].Exchange, paused[i].Pair, paused[i].AssetType)
+		if err != nil {
+			log.Debugf("Price band failed to evaluate paused pair: %s", err)
+		}
+	}
+}
+
+// GetStatus returns the last evaluation of every pair ordered
+func (g *Guard) GetStatus() []Status {
+	g.mtx.Lock()
+	defer g.mtx.Unlock()
+	resp := make([]Status, 0, len(g.statuses))
+	for _, s := range g.statuses {
+		resp = append(resp, *s)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return key(resp[i].Exchange, resp[i].Pair, resp[i].AssetType) <
+			key(resp[j].Exchange, resp[j].Pair, resp[j].AssetType)
+	})
+	return resp
+}
+
+// Guard wraps an exchange so orders are rejected while the exchange's last
+// price is outside the band
+func (g *Guard) Guard(e exchange.IBotExchange) exchange.IBotExchange {
+	return &Guarded{IBotExchange: e, guard: g}
+}
+
+// Guarded is an exchange whose orders are checked against the band
+type Guarded struct {
+	exchange.IBotExchange
+	guard *Guard
+}
+
+// Unwrap returns the underlying exchange
+func (g *Guarded) Unwrap() exchange.IBotExchange {
+	return g.IBotExchange
+}
+
+// SubmitOrder rejects orders while the pair's last price is outside the band
+func (g *Guarded) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	if err := g.guard.Allow(g.GetName(), p, ticker.Spot); err != nil {
+		return exchange.SubmitOrderResponse{}, err
+	}
+	return g.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+}
+
+// CheckOrder rejects orders placed natively while the pair's last price is
+// outside the band
+func (g *Guarded) CheckOrder(o *exchange.OrderSubmission) error {
+	return g.guard.Allow(g.GetName(), o.Pair, ticker.Spot)
+}
+
+// Start re-evaluates paused pairs at the interval until stopped
+func (g *Guard) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	g.mtx.Lock()
+	if g.shutdown != nil {
+		g.mtx.Unlock()
+		return
+	}
+	g.shutdown = make(chan struct{})
+	shutdown := g.shutdown
+	g.mtx.Unlock()
+
+	g.wg.Add(1)
+	go func() {
+		defer g.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				g.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops re-evaluating paused pairs
+func (g *Guard) Stop() {
+	g.mtx.Lock()
+	if g.shutdown == nil {
+		g.mtx.Unlock()
+		return
+	}
+	close(g.shutdown)
+	g.shutdown = nil
+	g.mtx.Unlock()
+	g.wg.Wait()
+}
@@ -0,0 +1,113 @@
+package priceband
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+)
+
+var testPair = currency.NewPairWithDelimiter("BTC", "USD", "-")
+
+type testExchange struct {
+	exchange.IBotExchange
+	submitted int
+}
+
+func (t *testExchange) GetName() string { return "Bitstamp" }
+
+func (t *testExchange) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	t.submitted++
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{}, nil, nil); err != errNoPriceFunc {
+		t.Error("Test Failed - New() expected no price function error", err)
+	}
+	prices := func(currency.Pair, string) []Price { return nil }
+	if _, err := New(Config{Band: 1}, prices, nil); err != errInvalidBand {
+		t.Error("Test Failed - New() expected invalid band error", err)
+	}
+	if _, err := New(Config{Pause: -1}, prices, nil); err != errInvalidSettings {
+		t.Error("Test Failed - New() expected invalid settings error", err)
+	}
+	g, err := New(Config{}, prices, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if g.cfg.Band != DefaultBand || g.cfg.MinVenues != DefaultMinVenues || g.cfg.MaxAge != DefaultMaxAge {
+		t.Errorf("Test Failed - New() expected defaults %+v", g.cfg)
+	}
+}
+
+func TestGuard(t *testing.T) {
+	now := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	last := 10000.0
+	prices := func(p currency.Pair, assetType string) []Price {
+		return []Price{
+			{Exchange: "Bitstamp", Last: last, Updated: now},
+			{Exchange: "Kraken", Last: 10010, Updated: now},
+			{Exchange: "Bitfinex", Last: 9990, Updated: now},
+			{Exchange: "Coinbase", Last: 10050, Updated: now},
+			// Stale prices are left out of the index
+			{Exchange: "Gemini", Last: 5000, Updated: now.Add(-DefaultMaxAge * 2)},
+		}
+	}
+	var events []Event
+	g, err := New(Config{Pause: time.Minute}, prices, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	g.now = func() time.Time { return now }
+	exch := &testExchange{}
+	guarded := g.Guard(exch)
+	if guarded.(*Guarded).Unwrap() != exch {
+		t.Error("Test Failed - Unwrap() expected underlying exchange")
+	}
+
+	if _, err = guarded.SubmitOrder(testPair, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 10000, ""); err != nil {
+		t.Fatal(err)
+	}
+	s := g.GetStatus()
+	if len(s) != 1 || s[0].Index != 10010 || s[0].Venues != 3 || s[0].Paused {
+		t.Fatalf("Test Failed - GetStatus() unexpected status %+v", s)
+	}
+
+	// A wick on the exchange breaches the band and rejects orders
+	last = 9500
+	_, err = guarded.SubmitOrder(testPair, exchange.SellOrderSide, exchange.MarketOrderType, 1, 0, "")
+	if err == nil || exch.submitted != 1 {
+		t.Fatal("Test Failed - SubmitOrder() expected order outside band rejected")
+	}
+	if len(events) != 1 || events[0].Type != Breached {
+		t.Errorf("Test Failed - SubmitOrder() expected breach event %+v", events)
+	}
+
+	// The pair stays paused for the pause after returning within the band
+	last = 10000
+	now = now.Add(time.Second * 30)
+	g.Check()
+	if err = g.Allow("Bitstamp", testPair, ticker.Spot); err == nil {
+		t.Error("Test Failed - Allow() expected pair paused after breach")
+	}
+	if s = g.GetStatus(); s[0].Outside || !s[0].Paused || s[0].Rejected != 2 {
+		t.Errorf("Test Failed - GetStatus() unexpected paused status %+v", s)
+	}
+	now = now.Add(time.Minute)
+	g.Check()
+	if len(events) != 2 || events[1].Type != Restored {
+		t.Errorf("Test Failed - Check() expected restored event %+v", events)
+	}
+	if _, err = guarded.SubmitOrder(testPair, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 10000, ""); err != nil {
+		t.Error("Test Failed - SubmitOrder() expected order allowed after pause", err)
+	}
+
+	// Orders are allowed when too few venues form an index
+	now = now.Add(DefaultMaxAge * 2)
+	if err = g.Allow("Bitstamp", testPair, ticker.Spot); err != nil {
+		t.Error("Test Failed - Allow() expected order allowed without index", err)
+	}
+}
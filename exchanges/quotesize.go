@@ -0,0 +1,130 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+)
+
+// DefaultQuoteSlippage is the margin market buys sized in the quote currency
+// are reduced by, allowing for the book moving before the order fills
+const DefaultQuoteSlippage = 0.005
+
+var (
+	errQuoteAmountInvalid = errors.New("quote amount must be positive")
+	errSlippageInvalid    = errors.New("slippage margin must be between 0 and 1")
+	errQuoteLimitPrice    = errors.New("limit orders sized in the quote currency require a price")
+	errQuoteDepth         = errors.New("insufficient orderbook depth to size order")
+	errQuoteOrderType     = errors.New("orders sized in the quote currency must be market or limit orders")
+)
+
+// QuoteOrderSubmitter is implemented by exchanges which natively accept order
+// sizes in the quote currency for some orders, such as Huobi market buys
+type QuoteOrderSubmitter interface {
+	SupportsQuoteAmount(side OrderSide, orderType OrderType) bool
+	SubmitQuoteOrder(p currency.Pair, side OrderSide, orderType OrderType, quoteAmount, price float64, clientID string) (SubmitOrderResponse, error)
+}
+
+// QuoteToBase returns the base amount worth the quote amount when filled
+// against the book. Buys walk the asks and are sized at the average fill
+// price raised by the slippage margin, so the quote amount is not exceeded if
+// the order fills worse than the book. Sells walk the bids and are sized at
+// the average fill price
+func QuoteToBase(ob *orderbook.Base, side OrderSide, quoteAmount, slippage float64) (float64, error) {
+	levels := ob.Bids
+	if side == BuyOrderSide {
+		levels = ob.Asks
+	}
+
+	var base float64
+	remaining := quoteAmount
+	for i := range levels {
+		if levels[i].Price <= 0 {
+			continue
+		}
+		value := levels[i].Amount * levels[i].Price
+		if value >= remaining {
+			base += remaining / levels[i].Price
+			remaining = 0
+			break
+		}
+		base += levels[i].Amount
+		remaining -= value
+	}
+	if remaining > 0 || base == 0 {
+		return 0, fmt.Errorf("%s %s %v", ob.Pair, side, errQuoteDepth)
+	}
+
+	if side == BuyOrderSide {
+		base /= 1 + slippage
+	}
+	return base, nil
+}
+
+// BaseToQuote returns the quote amount needed to fill the base amount against
+// the book, walking the asks for buys and the bids for sells
+func BaseToQuote(ob *orderbook.Base, side OrderSide, baseAmount float64) (float64, error) {
+	levels := ob.Bids
+	if side == BuyOrderSide {
+		levels = ob.Asks
+	}
+
+	var quote float64
+	remaining := baseAmount
+	for i := range levels {
+		if levels[i].Amount >= remaining {
+			quote += remaining * levels[i].Price
+			remaining = 0
+			break
+		}
+		quote += levels[i].Amount * levels[i].Price
+		remaining -= levels[i].Amount
+	}
+	if remaining > 0 {
+		return 0, fmt.Errorf("%s %s %v", ob.Pair, side, errQuoteDepth)
+	}
+	return quote, nil
+}
+
+// SubmitQuoteOrder submits an order sized in the quote currency, such as
+// buying 500 USD of BTC. Exchanges accepting the quote amount natively for
+// the order are sent it as is. Otherwise limit orders are sized at their
+// price and market orders against the live orderbook using the slippage
+// margin. Native support is only used when the exchange is not wrapped, so
+// orders sent through guards are always converted and checked in the base
+// currency
+func SubmitQuoteOrder(e IBotExchange, p currency.Pair, side OrderSide, orderType OrderType, quoteAmount, price, slippage float64, clientID string) (SubmitOrderResponse, error) {
+	if quoteAmount <= 0 {
+		return SubmitOrderResponse{}, errQuoteAmountInvalid
+	}
+	if slippage < 0 || slippage >= 1 {
+		return SubmitOrderResponse{}, errSlippageInvalid
+	}
+
+	if s, ok := e.(QuoteOrderSubmitter); ok && s.SupportsQuoteAmount(side, orderType) {
+		return s.SubmitQuoteOrder(p, side, orderType, quoteAmount, price, clientID)
+	}
+
+	var amount float64
+	switch orderType {
+	case LimitOrderType:
+		if price <= 0 {
+			return SubmitOrderResponse{}, errQuoteLimitPrice
+		}
+		amount = quoteAmount / price
+	case MarketOrderType:
+		ob, err := e.UpdateOrderbook(p, orderbook.Spot)
+		if err != nil {
+			return SubmitOrderResponse{}, err
+		}
+		amount, err = QuoteToBase(&ob, side, quoteAmount, slippage)
+		if err != nil {
+			return SubmitOrderResponse{}, fmt.Errorf("%s %v", e.GetName(), err)
+		}
+	default:
+		return SubmitOrderResponse{}, fmt.Errorf("%s %v", orderType, errQuoteOrderType)
+	}
+	return e.SubmitOrder(p, side, orderType, amount, price, clientID)
+}
@@ -0,0 +1,130 @@
+package exchange
+
+import (
+	"math"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+)
+
+var testQuoteBook = orderbook.Base{
+	Pair: currency.NewPair(currency.BTC, currency.USD),
+	Bids: []orderbook.Item{{Price: 99, Amount: 1}, {Price: 98, Amount: 2}},
+	Asks: []orderbook.Item{{Price: 100, Amount: 1}, {Price: 102, Amount: 2}},
+}
+
+type testQuoteExchange struct {
+	IBotExchange
+	amount float64
+}
+
+func (t *testQuoteExchange) GetName() string { return "test" }
+
+func (t *testQuoteExchange) UpdateOrderbook(_ currency.Pair, _ string) (orderbook.Base, error) {
+	return testQuoteBook, nil
+}
+
+func (t *testQuoteExchange) SubmitOrder(_ currency.Pair, _ OrderSide, _ OrderType, amount, _ float64, _ string) (SubmitOrderResponse, error) {
+	t.amount = amount
+	return SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+type testNativeQuoteExchange struct {
+	testQuoteExchange
+	quoteAmount float64
+}
+
+func (t *testNativeQuoteExchange) SupportsQuoteAmount(side OrderSide, orderType OrderType) bool {
+	return side == BuyOrderSide && orderType == MarketOrderType
+}
+
+func (t *testNativeQuoteExchange) SubmitQuoteOrder(_ currency.Pair, _ OrderSide, _ OrderType, quoteAmount, _ float64, _ string) (SubmitOrderResponse, error) {
+	t.quoteAmount = quoteAmount
+	return SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func TestQuoteToBase(t *testing.T) {
+	// 100 USD at 100 then 104 USD at 102
+	base, err := QuoteToBase(&testQuoteBook, BuyOrderSide, 204, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(base-2.0196078431) > 1e-9 {
+		t.Errorf("Test Failed - QuoteToBase() expected 2.0196078431, got %v", base)
+	}
+
+	base, err = QuoteToBase(&testQuoteBook, BuyOrderSide, 50, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(base-0.5/1.01) > 1e-12 {
+		t.Errorf("Test Failed - QuoteToBase() slippage not applied, got %v", base)
+	}
+
+	base, err = QuoteToBase(&testQuoteBook, SellOrderSide, 99+49, 0.01)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(base-1.5) > 1e-12 {
+		t.Errorf("Test Failed - QuoteToBase() expected 1.5, got %v", base)
+	}
+
+	if _, err = QuoteToBase(&testQuoteBook, BuyOrderSide, 1000, 0); err == nil {
+		t.Error("Test Failed - QuoteToBase() expected insufficient depth error")
+	}
+}
+
+func TestBaseToQuote(t *testing.T) {
+	quote, err := BaseToQuote(&testQuoteBook, BuyOrderSide, 1.5)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if quote != 151 {
+		t.Errorf("Test Failed - BaseToQuote() expected 151, got %v", quote)
+	}
+	if _, err = BaseToQuote(&testQuoteBook, SellOrderSide, 4); err == nil {
+		t.Error("Test Failed - BaseToQuote() expected insufficient depth error")
+	}
+}
+
+func TestSubmitQuoteOrder(t *testing.T) {
+	p := currency.NewPair(currency.BTC, currency.USD)
+	e := &testQuoteExchange{}
+	if _, err := SubmitQuoteOrder(e, p, BuyOrderSide, MarketOrderType, 0, 0, 0, ""); err != errQuoteAmountInvalid {
+		t.Error("Test Failed - SubmitQuoteOrder() expected invalid amount error", err)
+	}
+	if _, err := SubmitQuoteOrder(e, p, BuyOrderSide, MarketOrderType, 100, 0, 1, ""); err != errSlippageInvalid {
+		t.Error("Test Failed - SubmitQuoteOrder() expected invalid slippage error", err)
+	}
+	if _, err := SubmitQuoteOrder(e, p, BuyOrderSide, LimitOrderType, 100, 0, 0, ""); err != errQuoteLimitPrice {
+		t.Error("Test Failed - SubmitQuoteOrder() expected limit price error", err)
+	}
+
+	_, err := SubmitQuoteOrder(e, p, BuyOrderSide, LimitOrderType, 500, 125, 0, "")
+	if err != nil || e.amount != 4 {
+		t.Errorf("Test Failed - SubmitQuoteOrder() limit order sized %v, expected 4. Error: %v", e.amount, err)
+	}
+
+	_, err = SubmitQuoteOrder(e, p, SellOrderSide, MarketOrderType, 99, 0, DefaultQuoteSlippage, "")
+	if err != nil || e.amount != 1 {
+		t.Errorf("Test Failed - SubmitQuoteOrder() market order sized %v, expected 1. Error: %v", e.amount, err)
+	}
+
+	n := &testNativeQuoteExchange{}
+	_, err = SubmitQuoteOrder(n, p, BuyOrderSide, MarketOrderType, 500, 0, DefaultQuoteSlippage, "")
+	if err != nil || n.quoteAmount != 500 || n.amount != 0 {
+		t.Errorf("Test Failed - SubmitQuoteOrder() native quote order not used %+v. Error: %v", n, err)
+	}
+	_, err = SubmitQuoteOrder(n, p, SellOrderSide, MarketOrderType, 99, 0, DefaultQuoteSlippage, "")
+	if err != nil || n.amount != 1 {
+		t.Errorf("Test Failed - SubmitQuoteOrder() unsupported native order not converted %+v. Error: %v", n, err)
+	}
+
+	// wrapped exchanges are converted so guards see the base amount
+	n = &testNativeQuoteExchange{}
+	_, err = SubmitQuoteOrder(NewPreflight(n), p, BuyOrderSide, MarketOrderType, 100, 0, 0, "")
+	if err != nil || n.quoteAmount != 0 || n.amount != 1 {
+		t.Errorf("Test Failed - SubmitQuoteOrder() wrapped exchange used native quote order %+v. Error: %v", n, err)
+	}
+}
@@ -0,0 +1,391 @@
+// Package quoting maintains a market maker's two sided quote of a pair from
+// its orderbook feed. Each quote records the time of the book data it is based
+// on, and once that data is older than the latency budget the quote is pulled
+// rather than left resting on stale prices, either because the update arrived
+// late or because updates stopped arriving. Quoting resumes as soon as fresh
+// data arrives
+package quoting
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bookfeed"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/inventory"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default quoting settings
+const (
+	DefaultLatencyBudget = time.Millisecond * 500
+	DefaultSpreadBps     = 10
+	// DefaultRequoteBps is how far the mid price moves before a quote is
+	// replaced
+	DefaultRequoteBps = 1
+)
+
+// Event types emitted by a quoter
+const (
+	Pulled   = "QUOTE_PULLED"
+	Requoted = "QUOTE_RESUMED"
+)
+
+var (
+	errNoExchange    = errors.New("quoter no exchange supplied")
+	errNoPair        = errors.New("quoter pair not set")
+	errInvalidAmount = errors.New("quote amount must be positive")
+	errInvalidSpread = errors.New("quote spread and requote threshold cannot be negative")
+	errInvalidBudget = errors.New("latency budget cannot be negative")
+	errFeedClosed    = errors.New("orderbook feed closed")
+	errEmptyBook     = errors.New("orderbook has no bids or asks")
+	errNotPlaced     = errors.New("order not acknowledged")
+)
+
+// SkewFunc returns the quote skewed, such as by inventory location
+type SkewFunc func(q inventory.Quote) (inventory.Quote, error)
+
+// Config defines the quote maintained
+type Config struct {
+	Pair      currency.Pair
+	AssetType string
+	Amount    float64
+	// SpreadBps is the distance between the bid and ask around the mid
+	// price
+	SpreadBps float64
+	// RequoteBps is how far the mid price moves before the quote is
+	// replaced, smaller moves leave the quote resting
+	RequoteBps float64
+	// LatencyBudget is the oldest the book data a resting quote is based on
+	// may be
+	LatencyBudget time.Duration
+	Skew          SkewFunc
+}
+
+// Status holds a quoter's quote and how fresh the data behind it is
+type Status struct {
+	Exchange string          `json:"exchange"`
+	Pair     currency.Pair   `json:"pair"`
+	Quote    inventory.Quote `json:"quote"`
+	Live     bool            `json:"live"`
+	// DataTime is when the book data the quote is based on was published
+	DataTime time.Time `json:"dataTime"`
+	// PulledReason explains why the quote is pulled
+	PulledReason string `json:"pulledReason,omitempty"`
+	Quotes       int64  `json:"quotes"`
+	Pulls        int64  `json:"pulls"`
+	// StaleUpdates counts book updates older than the budget on arrival
+	StaleUpdates int64         `json:"staleUpdates"`
+	MaxAge       time.Duration `json:"maxAge"`
+	Error        string        `json:"error,omitempty"`
+}
+
+// Event is emitted when a quote is pulled or resumed
+type Event struct {
+	Type   string
+	Status Status
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	if e.Type == Pulled {
+		return fmt.Sprintf("%s %s %s quote pulled: %s", e.Type, e.Status.Exchange, e.Status.Pair,
+			e.Status.PulledReason)
+	}
+	return fmt.Sprintf("%s %s %s quoting %v / %v on data %s old", e.Type, e.Status.Exchange, e.Status.Pair,
+		e.Status.Quote.Bid, e.Status.Quote.Ask, time.Since(e.Status.DataTime).Round(time.Millisecond))
+}
+
+// Quoter maintains a two sided quote of a pair on an exchange
+type Quoter struct {
+	exch    exchange.IBotExchange
+	cfg     Config
+	onEvent func(Event)
+
+	book   bookfeed.Book
+	mid    float64
+	bidID  string
+	askID  string
+	status Status
+	events []Event
+	mtx    sync.Mutex
+}
+
+// New returns a quoter of the pair on the exchange
+func New(e exchange.IBotExchange, cfg Config, onEvent func(Event)) (*Quoter, error) {
+	switch {
+	case e == nil:
+		return nil, errNoExchange
+	case cfg.Pair.IsEmpty():
+		return nil, errNoPair
+	case cfg.Amount <= 0:
+		return nil, errInvalidAmount
+	case cfg.SpreadBps < 0 || cfg.RequoteBps < 0:
+		return nil, errInvalidSpread
+	case cfg.LatencyBudget < 0:
+		return nil, errInvalidBudget
+	}
+	if cfg.SpreadBps == 0 {
+		cfg.SpreadBps = DefaultSpreadBps
+	}
+	if cfg.RequoteBps == 0 {
+		cfg.RequoteBps = DefaultRequoteBps
+	}
+	if cfg.LatencyBudget == 0 {
+		cfg.LatencyBudget = DefaultLatencyBudget
+	}
+	return &Quoter{
+		exch:    e,
+		cfg:     cfg,
+		onEvent: onEvent,
+		status:  Status{Exchange: e.GetName(), Pair: cfg.Pair},
+	}, nil
+}
+
+// GetStatus returns the quote and the age of the data behind it
+func (q *Quoter) GetStatus() Status {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	return q.status
+}
+
+// locked runs the function holding the lock, then emits the events it raised
+func (q *Quoter) locked(f func()) {
+	q.mtx.Lock()
+	f()
+	events := q.events
+	q.events = nil
+	q.mtx.Unlock()
+	for i := range events {
+		q.onEvent(events[i])
+	}
+}
+
+// Run quotes from the subscription's updates until shutdown, pulling the
+// quote when stopped. The quote's data age is checked between updates so a
+// feed which goes quiet pulls the quote within the budget. A sequence gap
+// pulls the quote and returns bookfeed.ErrSequenceGap, a new subscription
+// starts from a snapshot
+func (q *Quoter) Run(sub *bookfeed.Subscription, shutdown <-chan struct{}) error {
+	check := time.NewTicker(q.cfg.LatencyBudget / 4)
+	defer check.Stop()
+	for {
+		select {
+		case <-shutdown:
+			q.Pull("quoting stopped")
+			return nil
+		case u, ok := <-sub.C:
+			if !ok {
+				q.Pull(errFeedClosed.Error())
+				return errFeedClosed
+			}
+			if err := q.Update(&u, time.Now()); err != nil {
+				return err
+			}
+		case now := <-check.C:
+			q.Check(now)
+		}
+	}
+}
+
+// Update applies a book update, pulling the quote when the update is older
+// than the budget and otherwise quoting around the new mid price. Updates not
+// following the book's sequence pull the quote and are returned as an error
+func (q *Quoter) Update(u *bookfeed.Update, now time.Time) (err error) {
+	q.locked(func() {
+		if err = q.book.Apply(u); err != nil {
+			q.pull(err.Error())
+			return
+		}
+		q.update(u, now)
+	})
+	return err
+}
+
+// update quotes from the updated book, the caller must hold the lock
+func (q *Quoter) update(u *bookfeed.Update, now time.Time) {
+	age := now.Sub(u.Time)
+	if age > q.status.MaxAge {
+		q.status.MaxAge = age
+	}
+	if age > q.cfg.LatencyBudget {
+		q.status.StaleUpdates++
+		q.pull(fmt.Sprintf("book data %s old exceeds the %s latency budget",
+			age.Round(time.Millisecond), q.cfg.LatencyBudget))
+		return
+	}
+
+	bids, asks := q.book.Bids(), q.book.Asks()
+	if len(bids) == 0 || len(asks) == 0 {
+		q.pull(errEmptyBook.Error())
+		return
+	}
+	mid := (bids[0].Price + asks[0].Price) / 2
+	if q.status.Live && math.Abs(mid-q.mid)/q.mid*10000 < q.cfg.RequoteBps {
+		// The resting quote is confirmed by fresh data
+		q.status.DataTime = u.Time
+		return
+	}
+	q.quote(mid, u.Time)
+}
+
+// Check pulls the quote once the data it is based on is older than the
+// budget, retrying cancels which failed while pulled
+func (q *Quoter) Check(now time.Time) {
+	q.locked(func() {
+		if !q.status.Live {
+			q.cancel()
+			return
+		}
+		if age := now.Sub(q.status.DataTime); age > q.cfg.LatencyBudget {
+			q.pull(fmt.Sprintf("no book data for %s, exceeding the %s latency budget",
+				age.Round(time.Millisecond), q.cfg.LatencyBudget))
+		}
+	})
+}
+
+// Pull cancels the quote
+func (q *Quoter) Pull(reason string) {
+	q.locked(func() {
+		q.pull(reason)
+	})
+}
+
+// pull cancels both sides of the quote, the caller must hold the lock
+func (q *Quoter) pull(reason string) {
+	wasLive := q.status.Live
+	q.cancel()
+	q.status.Live = false
+	if !wasLive {
+		return
+	}
+	q.status.Pulls++
+	q.status.PulledReason = reason
+	q.emit(Pulled)
+}
+
+// cancel cancels the resting orders, those which fail to cancel are retried
+// on the next pull or quote
+func (q *Quoter) cancel() {
+	for _, id := range []*string{&q.bidID, &q.askID} {
+		if *id == "" {
+			continue
+		}
+		err := q.exch.CancelOrder(&exchange.OrderCancellation{
+			OrderID:      *id,
+			CurrencyPair: q.cfg.Pair,
+		})
+		if err != nil && err != exchange.ErrCancelOrderFilled {
+			q.status.Error = fmt.Sprintf("cancel %s: %v", *id, err)
+			log.Errorf("Quoter %s %s failed to cancel order %s: %s", q.status.Exchange, q.cfg.Pair, *id, err)
+			continue
+		}
+		*id = ""
+	}
+}
+
+// quote replaces the quote with one around the mid price, the caller must
+// hold the lock
+func (q *Quoter) quote(mid float64, dataTime time.Time) {
+	wasLive := q.status.Live
+	q.cancel()
+	if q.bidID != "" || q.askID != "" {
+		q.status.Live = false
+		return
+	}
+
+	half := q.cfg.SpreadBps / 2 / 10000
+	quote := inventory.Quote{
+		Bid:       mid * (1 - half),
+		BidAmount: q.cfg.Amount,
+		Ask:       mid * (1 + half),
+		AskAmount: q.cfg.Amount,
+	}
+	if q.cfg.Skew != nil {
+		// Quotes are left unskewed when no skew is available
+		if skewed, err := q.cfg.Skew(quote); err == nil {
+			quote = skewed
+		}
+	}
+
+	err := q.submit(quote)
+	if err != nil {
+		q.status.Error = err.Error()
+		q.cancel()
+		q.status.Live = false
+		return
+	}
+
+	q.mid = mid
+	q.status.Quote = quote
+	q.status.DataTime = dataTime
+	q.status.Live = true
+	q.status.Quotes++
+	q.status.Error = ""
+	if !wasLive && q.status.PulledReason != "" {
+		q.emit(Requoted)
+	}
+	q.status.PulledReason = ""
+}
+
+// submit places both sides of the quote together, in one request on
+// exchanges with a bulk order endpoint, storing the IDs of the sides placed.
+// The caller must hold the lock
+func (q *Quoter) submit(quote inventory.Quote) error {
+	sides := []struct {
+		id     *string
+		side   exchange.OrderSide
+		amount float64
+		price  float64
+	}{
+		{&q.bidID, exchange.BuyOrderSide, quote.BidAmount, quote.Bid},
+		{&q.askID, exchange.SellOrderSide, quote.AskAmount, quote.Ask},
+	}
+	var orders []exchange.OrderSubmission
+	var ids []*string
+	for i := range sides {
+		if sides[i].amount <= 0 {
+			continue
+		}
+		orders = append(orders, exchange.OrderSubmission{
+			Pair:      q.cfg.Pair,
+			Side:      sides[i].side,
+			OrderType: exchange.LimitOrderType,
+			Amount:    sides[i].amount,
+			Price:     sides[i].price,
+		})
+		ids = append(ids, sides[i].id)
+	}
+
+	var errs []string
+	results := exchange.SubmitOrders(q.exch, orders)
+	for i := range results {
+		err := results[i].Err
+		if err == nil && (!results[i].IsOrderPlaced || results[i].OrderID == "") {
+			err = errNotPlaced
+		}
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s quote: %v", orders[i].Side, err))
+			continue
+		}
+		*ids[i] = results[i].OrderID
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// emit queues the event with the current status, the caller must hold the
+// lock
+func (q *Quoter) emit(eventType string) {
+	if q.onEvent == nil {
+		return
+	}
+	q.events = append(q.events, Event{Type: eventType, Status: q.status})
+}
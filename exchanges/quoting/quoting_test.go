@@ -0,0 +1,225 @@
+package quoting
+
+import (
+	"fmt"
+	"math"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bookfeed"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	open      map[string]float64
+	submitted int
+	cancelErr error
+	mtx       sync.Mutex
+}
+
+func newTestExchange() *testExchange {
+	return &testExchange{open: make(map[string]float64)}
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.mtx.Lock()
+	defer t.mtx.Unlock()
+	t.submitted++
+	id := fmt.Sprintf("%d", t.submitted)
+	t.open[id] = price
+	return exchange.SubmitOrderResponse{OrderID: id, IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) CancelOrder(o *exchange.OrderCancellation) error {
+	if t.cancelErr != nil {
+		return t.cancelErr
+	}
+	delete(t.open, o.OrderID)
+	return nil
+}
+
+func update(seq uint64, snapshot bool, bid, ask float64, published time.Time) *bookfeed.Update {
+	return &bookfeed.Update{
+		Exchange: "test",
+		Seq:      seq,
+		PrevSeq:  seq - 1,
+		Snapshot: snapshot,
+		Bids:     []orderbook.Item{{Price: bid, Amount: 1}},
+		Asks:     []orderbook.Item{{Price: ask, Amount: 1}},
+		Time:     published,
+	}
+}
+
+func testQuoter(t *testing.T, exch *testExchange, events *[]Event) *Quoter {
+	q, err := New(exch, Config{
+		Pair:   currency.NewPairWithDelimiter("BTC", "USD", "-"),
+		Amount: 1,
+	}, func(e Event) { *events = append(*events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return q
+}
+
+func TestNew(t *testing.T) {
+	p := currency.NewPairWithDelimiter("BTC", "USD", "-")
+	if _, err := New(newTestExchange(), Config{Pair: p}, nil); err != errInvalidAmount {
+		t.Error("Test Failed - New() expected invalid amount error", err)
+	}
+	if _, err := New(newTestExchange(), Config{Pair: p, Amount: 1, LatencyBudget: -1}, nil); err != errInvalidBudget {
+		t.Error("Test Failed - New() expected invalid budget error", err)
+	}
+	q, err := New(newTestExchange(), Config{Pair: p, Amount: 1}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if q.cfg.LatencyBudget != DefaultLatencyBudget || q.cfg.SpreadBps != DefaultSpreadBps {
+		t.Errorf("Test Failed - New() expected defaults applied %+v", q.cfg)
+	}
+}
+
+func TestLatencyBudget(t *testing.T) {
+	exch := newTestExchange()
+	var events []Event
+	q := testQuoter(t, exch, &events)
+	now := time.Now()
+
+	// Stale data is never quoted on
+	if err := q.Update(update(1, true, 99, 101, now.Add(-time.Second)), now); err != nil {
+		t.Fatal(err)
+	}
+	s := q.GetStatus()
+	if s.Live || len(exch.open) != 0 || s.StaleUpdates != 1 || s.MaxAge != time.Second {
+		t.Fatalf("Test Failed - Update() expected stale data not quoted %+v", s)
+	}
+
+	if err := q.Update(update(2, false, 99, 101, now), now); err != nil {
+		t.Fatal(err)
+	}
+	s = q.GetStatus()
+	if !s.Live || len(exch.open) != 2 || math.Abs(s.Quote.Bid-99.95) > 1e-9 || math.Abs(s.Quote.Ask-100.05) > 1e-9 {
+		t.Fatalf("Test Failed - Update() expected a quote around the mid %+v", s)
+	}
+
+	// Moves smaller than the requote threshold leave the quote resting
+	later := now.Add(time.Millisecond * 300)
+	if err := q.Update(update(3, false, 99, 101.01, later), later); err != nil {
+		t.Fatal(err)
+	}
+	s = q.GetStatus()
+	if exch.submitted != 2 || s.Quotes != 1 || !s.DataTime.Equal(later) {
+		t.Fatalf("Test Failed - Update() expected the resting quote confirmed %+v", s)
+	}
+
+	// The quote is pulled once no fresh data arrives within the budget
+	q.Check(later.Add(time.Millisecond * 400))
+	if !q.GetStatus().Live {
+		t.Fatal("Test Failed - Check() pulled a quote within the budget")
+	}
+	q.Check(later.Add(time.Millisecond * 600))
+	s = q.GetStatus()
+	if s.Live || len(exch.open) != 0 || s.Pulls != 1 || s.PulledReason == "" {
+		t.Fatalf("Test Failed - Check() expected the quote pulled %+v", s)
+	}
+
+	// Fresh data quotes again
+	fresh := later.Add(time.Second)
+	if err := q.Update(update(4, true, 199, 201, fresh), fresh); err != nil {
+		t.Fatal(err)
+	}
+	s = q.GetStatus()
+	if !s.Live || len(exch.open) != 2 || s.Quotes != 2 || math.Abs(s.Quote.Bid-199.9) > 1e-9 {
+		t.Fatalf("Test Failed - Update() expected a requote on fresh data %+v", s)
+	}
+	if len(events) != 2 || events[0].Type != Pulled || events[1].Type != Requoted {
+		t.Errorf("Test Failed - expected pulled and requoted events %+v", events)
+	}
+
+	// A late update pulls the quote
+	if err := q.Update(update(5, false, 199, 201, fresh), fresh.Add(time.Second)); err != nil {
+		t.Fatal(err)
+	}
+	if s = q.GetStatus(); s.Live || s.StaleUpdates != 2 || len(exch.open) != 0 {
+		t.Errorf("Test Failed - Update() expected a late update to pull the quote %+v", s)
+	}
+}
+
+func TestSequenceGap(t *testing.T) {
+	exch := newTestExchange()
+	var events []Event
+	q := testQuoter(t, exch, &events)
+	now := time.Now()
+
+	if err := q.Update(update(1, true, 99, 101, now), now); err != nil {
+		t.Fatal(err)
+	}
+	if err := q.Update(update(3, false, 99, 101, now), now); err != bookfeed.ErrSequenceGap {
+		t.Fatal("Test Failed - Update() expected sequence gap error", err)
+	}
+	if s := q.GetStatus(); s.Live || len(exch.open) != 0 {
+		t.Errorf("Test Failed - Update() expected a gap to pull the quote %+v", s)
+	}
+}
+
+func TestCancelRetry(t *testing.T) {
+	exch := newTestExchange()
+	var events []Event
+	q := testQuoter(t, exch, &events)
+	now := time.Now()
+
+	if err := q.Update(update(1, true, 99, 101, now), now); err != nil {
+		t.Fatal(err)
+	}
+	exch.cancelErr = fmt.Errorf("exchange unavailable")
+	q.Pull("test")
+	if s := q.GetStatus(); s.Live || s.Error == "" || len(exch.open) != 2 {
+		t.Fatalf("Test Failed - Pull() expected failed cancels recorded %+v", s)
+	}
+
+	exch.cancelErr = nil
+	q.Check(now)
+	if len(exch.open) != 0 {
+		t.Errorf("Test Failed - Check() expected failed cancels retried %v", exch.open)
+	}
+}
+
+type testBulkExchange struct {
+	*testExchange
+	batches int
+}
+
+func (t *testBulkExchange) MaxBatchOrders() int { return 10 }
+
+func (t *testBulkExchange) SubmitBatchOrders(orders []exchange.OrderSubmission) ([]exchange.BatchOrderResult, error) {
+	t.batches++
+	results := make([]exchange.BatchOrderResult, len(orders))
+	for i := range orders {
+		results[i].SubmitOrderResponse, results[i].Err = t.SubmitOrder(orders[i].Pair,
+			orders[i].Side, orders[i].OrderType, orders[i].Amount, orders[i].Price, orders[i].ClientID)
+	}
+	return results, nil
+}
+
+func TestBulkQuote(t *testing.T) {
+	exch := &testBulkExchange{testExchange: newTestExchange()}
+	q, err := New(exch, Config{
+		Pair:   currency.NewPairWithDelimiter("BTC", "USD", "-"),
+		Amount: 1,
+	}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now()
+	if err = q.Update(update(1, true, 99, 101, now), now); err != nil {
+		t.Fatal(err)
+	}
+	if s := q.GetStatus(); !s.Live || exch.batches != 1 || len(exch.open) != 2 {
+		t.Errorf("Test Failed - Update() expected both sides quoted in one batch %+v", s)
+	}
+}
@@ -0,0 +1,97 @@
+package exchange
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// ErrReadOnlyExchange is returned when a mutating call is attempted on an
+// exchange that has been wrapped as read only
+var ErrReadOnlyExchange = errors.New("exchange is in read only mode, mutating requests are disabled")
+
+// Wrapper is implemented by exchanges which wrap another exchange to restrict
+// its behaviour. Wrappers do not expose the optional capabilities of the
+// exchange they wrap, so read only capabilities are looked up on the
+// underlying exchange
+type Wrapper interface {
+	Unwrap() IBotExchange
+}
+
+// Underlying returns the innermost exchange beneath any wrappers
+func Underlying(e IBotExchange) IBotExchange {
+	for {
+		w, ok := e.(Wrapper)
+		if !ok {
+			return e
+		}
+		e = w.Unwrap()
+	}
+}
+
+// IsReadOnly returns whether the exchange or any exchange it wraps is a read
+// only wrapper
+func IsReadOnly(e IBotExchange) bool {
+	for {
+		if _, ok := e.(*ReadOnly); ok {
+			return true
+		}
+		w, ok := e.(Wrapper)
+		if !ok {
+			return false
+		}
+		e = w.Unwrap()
+	}
+}
+
+// ReadOnly wraps an exchange and rejects all calls that would place, modify or
+// cancel orders or move funds. All other calls are passed through to the
+// underlying exchange.
+type ReadOnly struct {
+	IBotExchange
+}
+
+// NewReadOnly returns a read only wrapper around the supplied exchange
+func NewReadOnly(e IBotExchange) *ReadOnly {
+	return &ReadOnly{IBotExchange: e}
+}
+
+// Unwrap returns the underlying exchange
+func (r *ReadOnly) Unwrap() IBotExchange {
+	return r.IBotExchange
+}
+
+// SubmitOrder is disabled in read only mode
+func (r *ReadOnly) SubmitOrder(_ currency.Pair, _ OrderSide, _ OrderType, _, _ float64, _ string) (SubmitOrderResponse, error) {
+	return SubmitOrderResponse{}, ErrReadOnlyExchange
+}
+
+// ModifyOrder is disabled in read only mode
+func (r *ReadOnly) ModifyOrder(_ *ModifyOrder) (string, error) {
+	return "", ErrReadOnlyExchange
+}
+
+// CancelOrder is disabled in read only mode
+func (r *ReadOnly) CancelOrder(_ *OrderCancellation) error {
+	return ErrReadOnlyExchange
+}
+
+// CancelAllOrders is disabled in read only mode
+func (r *ReadOnly) CancelAllOrders(_ *OrderCancellation) (CancelAllOrdersResponse, error) {
+	return CancelAllOrdersResponse{}, ErrReadOnlyExchange
+}
+
+// WithdrawCryptocurrencyFunds is disabled in read only mode
+func (r *ReadOnly) WithdrawCryptocurrencyFunds(_ *WithdrawRequest) (string, error) {
+	return "", ErrReadOnlyExchange
+}
+
+// WithdrawFiatFunds is disabled in read only mode
+func (r *ReadOnly) WithdrawFiatFunds(_ *WithdrawRequest) (string, error) {
+	return "", ErrReadOnlyExchange
+}
+
+// WithdrawFiatFundsToInternationalBank is disabled in read only mode
+func (r *ReadOnly) WithdrawFiatFundsToInternationalBank(_ *WithdrawRequest) (string, error) {
+	return "", ErrReadOnlyExchange
+}
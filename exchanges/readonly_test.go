@@ -0,0 +1,89 @@
+package exchange
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+func TestReadOnly(t *testing.T) {
+	r := NewReadOnly(nil)
+	p := currency.NewPair(currency.BTC, currency.USD)
+	if _, err := r.SubmitOrder(p, BuyOrderSide, LimitOrderType, 1, 1, ""); err != ErrReadOnlyExchange {
+		t.Error("Test Failed - SubmitOrder() expected read only error")
+	}
+
+	if _, err := r.ModifyOrder(&ModifyOrder{}); err != ErrReadOnlyExchange {
+		t.Error("Test Failed - ModifyOrder() expected read only error")
+	}
+
+	if err := r.CancelOrder(&OrderCancellation{}); err != ErrReadOnlyExchange {
+		t.Error("Test Failed - CancelOrder() expected read only error")
+	}
+
+	if _, err := r.CancelAllOrders(&OrderCancellation{}); err != ErrReadOnlyExchange {
+		t.Error("Test Failed - CancelAllOrders() expected read only error")
+	}
+
+	if _, err := r.WithdrawCryptocurrencyFunds(&WithdrawRequest{}); err != ErrReadOnlyExchange {
+		t.Error("Test Failed - WithdrawCryptocurrencyFunds() expected read only error")
+	}
+}
+
+func TestUnderlying(t *testing.T) {
+	inner := &testTIFExchange{}
+	if Underlying(inner) != inner {
+		t.Error("Test Failed - Underlying() expected unwrapped exchange to be returned")
+	}
+	if Underlying(NewReadOnly(NewReadOnly(inner))) != inner {
+		t.Error("Test Failed - Underlying() expected nested wrappers to be removed")
+	}
+}
+
+func TestIsReadOnly(t *testing.T) {
+	inner := &testTIFExchange{}
+	if IsReadOnly(inner) || IsReadOnly(NewDryRun(inner)) {
+		t.Error("Test Failed - IsReadOnly() expected exchange not to be read only")
+	}
+	if !IsReadOnly(NewReadOnly(inner)) || !IsReadOnly(NewDryRun(NewReadOnly(inner))) {
+		t.Error("Test Failed - IsReadOnly() expected read only wrapper to be found")
+	}
+}
+
+func TestDryRun(t *testing.T) {
+	d := NewDryRun(&testTIFExchange{})
+	p := currency.NewPair(currency.BTC, currency.USD)
+	r, err := d.SubmitOrder(p, BuyOrderSide, LimitOrderType, 1, 1, "")
+	if err != nil || !r.IsOrderPlaced || !strings.HasPrefix(r.OrderID, DryRunIDPrefix) {
+		t.Errorf("Test Failed - SubmitOrder() expected simulated order, received %+v %v", r, err)
+	}
+	if id, err := d.ModifyOrder(&ModifyOrder{OrderID: r.OrderID}); err != nil || id != r.OrderID {
+		t.Errorf("Test Failed - ModifyOrder() expected order ID returned, received %s %v", id, err)
+	}
+	if err = d.CancelOrder(&OrderCancellation{OrderID: r.OrderID}); err != nil {
+		t.Error("Test Failed - CancelOrder() error", err)
+	}
+	if _, err = d.CancelAllOrders(&OrderCancellation{}); err != nil {
+		t.Error("Test Failed - CancelAllOrders() error", err)
+	}
+	id, err := d.WithdrawCryptocurrencyFunds(&WithdrawRequest{Amount: 1, Currency: currency.BTC})
+	if err != nil || !strings.HasPrefix(id, DryRunIDPrefix) || id == r.OrderID {
+		t.Errorf("Test Failed - WithdrawCryptocurrencyFunds() expected simulated ID, received %s %v", id, err)
+	}
+	if Underlying(d).GetName() != "test" {
+		t.Error("Test Failed - Underlying() expected dry run wrapper removed")
+	}
+
+	var b Base
+	if b.DryRunRequest(true, "/order", nil) {
+		t.Error("Test Failed - DryRunRequest() expected request sent with dry run disabled")
+	}
+	b.SetDryRun(true)
+	if !b.IsDryRun() || !b.DryRunRequest(true, "/order", nil) {
+		t.Error("Test Failed - DryRunRequest() expected mutating request not sent")
+	}
+	if b.DryRunRequest(false, "/orders", nil) {
+		t.Error("Test Failed - DryRunRequest() expected read sent in dry run mode")
+	}
+}
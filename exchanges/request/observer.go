@@ -0,0 +1,18 @@
+package request
+
+// SetObserver sets a function notified of the result of every request sent,
+// used to monitor an exchange's error rate. A nil function removes it
+func (r *Requester) SetObserver(f func(authenticated bool, err error)) {
+	r.obsMtx.Lock()
+	r.observer = f
+	r.obsMtx.Unlock()
+}
+
+func (r *Requester) notifyObserver(authenticated bool, err error) {
+	r.obsMtx.RLock()
+	f := r.observer
+	r.obsMtx.RUnlock()
+	if f != nil {
+		f(authenticated, err)
+	}
+}
@@ -0,0 +1,177 @@
+package request
+
+import (
+	"net/http"
+	"sync"
+	"time"
+)
+
+// Priority defines the order rate limited requests are sent in
+type Priority uint8
+
+// Request priorities, lowest first
+const (
+	// Background requests such as ticker polling and history backfills
+	Background Priority = iota
+	// Normal requests such as account balance and order queries
+	Normal
+	// Critical requests such as order placements and cancellations
+	Critical
+)
+
+// DefaultCriticalReserve is the fraction of each rate limit cycle background
+// requests cannot use, leaving headroom for trading requests
+const DefaultCriticalReserve = 0.2
+
+const priorityLevels = int(Critical) + 1
+
+// String implements the stringer interface
+func (p Priority) String() string {
+	switch p {
+	case Background:
+		return "background"
+	case Normal:
+		return "normal"
+	case Critical:
+		return "critical"
+	}
+	return "unknown"
+}
+
+// DefaultPriority returns the priority of a request without an explicit
+// priority. Unauthenticated requests are market data polling, authenticated
+// requests which are not GETs modify orders or funds
+func DefaultPriority(method string, authRequest bool) Priority {
+	if !authRequest {
+		return Background
+	}
+	if method == http.MethodGet || method == http.MethodHead {
+		return Normal
+	}
+	return Critical
+}
+
+// jobQueue holds pending rate limited jobs by priority
+type jobQueue struct {
+	jobs   [priorityLevels][]*Job
+	seq    uint64
+	notify chan struct{}
+	mtx    sync.Mutex
+}
+
+// push adds a job to the back of its priority queue and wakes the worker
+func (q *jobQueue) push(j *Job) {
+	q.mtx.Lock()
+	if q.notify == nil {
+		q.notify = make(chan struct{}, 1)
+	}
+	q.seq++
+	j.seq = q.seq
+	q.jobs[j.Priority] = append(q.jobs[j.Priority], j)
+	notify := q.notify
+	q.mtx.Unlock()
+
+	select {
+	case notify <- struct{}{}:
+	default:
+	}
+}
+
+// len returns the number of pending jobs
+func (q *jobQueue) len() int {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	var n int
+	for i := range q.jobs {
+		n += len(q.jobs[i])
+	}
+	return n
+}
+
+// wait returns a channel signalled when a job is added
+func (q *jobQueue) wait() <-chan struct{} {
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+	if q.notify == nil {
+		q.notify = make(chan struct{}, 1)
+	}
+	return q.notify
+}
+
+// oldestNonce returns the earliest queued job signed with a nonce, the
+// caller must hold the lock
+func (q *jobQueue) oldestNonce() (*Job, int, int) {
+	var oldest *Job
+	var priority, index int
+	for p := range q.jobs {
+		for i := range q.jobs[p] {
+			j := q.jobs[p][i]
+			if j.nonce && (oldest == nil || j.seq < oldest.seq) {
+				oldest, priority, index = j, p, i
+			}
+			if j.nonce {
+				// Jobs within a priority are queued in order
+				break
+			}
+		}
+	}
+	return oldest, priority, index
+}
+
+// allowed returns whether the rate limiter permits the job now
+func (r *Requester) allowed(j *Job) bool {
+	if r.IsRateLimited(j.AuthRequest) {
+		return false
+	}
+	if j.Priority != Background || r.CriticalReserve <= 0 {
+		return true
+	}
+	limit := r.GetRateLimit(j.AuthRequest)
+	rate := limit.GetRate()
+	reserved := int(float64(rate) * r.CriticalReserve)
+	return limit.GetRequests() < rate-reserved
+}
+
+// nextJob removes and returns the highest priority job the rate limiter
+// allows, counting it against the limit. When no job can be sent the time
+// until the rate limit cycle ends is returned, zero if the queue is empty.
+// Nonce signed jobs are sent in the order they were queued, so a higher
+// priority nonce job sends any earlier nonce jobs ahead of itself
+func (r *Requester) nextJob() (*Job, time.Duration) {
+	q := &r.queue
+	q.mtx.Lock()
+	defer q.mtx.Unlock()
+
+	var pending bool
+	for p := priorityLevels - 1; p >= 0; p-- {
+		if len(q.jobs[p]) == 0 {
+			continue
+		}
+		pending = true
+		j, priority, index := q.jobs[p][0], p, 0
+		if j.nonce {
+			j, priority, index = q.oldestNonce()
+		}
+		if !r.allowed(j) {
+			continue
+		}
+		q.jobs[priority] = append(q.jobs[priority][:index], q.jobs[priority][index+1:]...)
+		r.IncrementRequests(j.AuthRequest)
+		return j, 0
+	}
+	if !pending {
+		return nil, 0
+	}
+
+	wait := time.Duration(-1)
+	for _, limit := range []*RateLimit{r.AuthLimit, r.UnauthLimit} {
+		if d := limit.GetDuration(); limit.GetRate() > 0 && (wait < 0 || d < wait) {
+			wait = d
+		}
+	}
+	wait -= time.Since(r.Cycle)
+	if wait < time.Millisecond {
+		wait = time.Millisecond
+	}
+	return nil, wait
+}
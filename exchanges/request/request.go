@@ -14,6 +14,8 @@ import (
 	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/chaos"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/httprecorder"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/nonce"
 	log "github.com/thrasher-corp/gocryptotrader/logger"
 )
@@ -37,11 +39,25 @@ type Requester struct {
 	Cycle                time.Time
 	timeoutRetryAttempts int
 	m                    sync.Mutex
-	Jobs                 chan Job
+	queue                jobQueue
 	disengage            chan struct{}
 	WorkerStarted        bool
 	Nonce                nonce.Nonce
 	fifoLock             sync.Mutex
+	// CriticalReserve is the fraction of each rate limit cycle background
+	// requests cannot use
+	CriticalReserve float64
+	// Jobs queues jobs sent directly to the worker.
+	//
+	// Deprecated: use SendPayloadPriority. Jobs received are queued at their
+	// Priority alongside requests sent with SendPayload.
+	Jobs chan Job
+	// TagMode defines how tags passed to SendPayloadTagged are sent
+	TagMode  TagMode
+	tagStats map[string]*TagStats
+	tagMtx   sync.Mutex
+	observer func(authenticated bool, err error)
+	obsMtx   sync.RWMutex
 }
 
 // RateLimit struct
@@ -70,6 +86,10 @@ type Job struct {
 	AuthRequest   bool
 	Verbose       bool
 	HTTPDebugging bool
+	Priority      Priority
+	Tag           string
+	nonce         bool
+	seq           uint64
 }
 
 // NewRateLimit creates a new RateLimit
@@ -222,6 +242,7 @@ func New(name string, authLimit, unauthLimit *RateLimit, httpRequester *http.Cli
 		Jobs:                 make(chan Job, maxRequestJobs),
 		disengage:            make(chan struct{}, 1),
 		timeoutRetryAttempts: defaultTimeoutRetryAttempts,
+		CriticalReserve:      DefaultCriticalReserve,
 	}
 }
 
@@ -273,10 +294,22 @@ func (r *Requester) DoRequest(req *http.Request, path string, body io.Reader, re
 		log.Debug(body)
 	}
 
+	// Request bodies are read before sending so signed payloads can be
+	// recorded alongside their responses
+	recorder := httprecorder.Active()
+	var reqBody string
+	if httpDebug && recorder != nil {
+		reqBody = httprecorder.RequestBody(req)
+	} else {
+		recorder = nil
+	}
+
 	var timeoutError error
 	for i := 0; i < r.timeoutRetryAttempts+1; i++ {
-		resp, err := r.HTTPClient.Do(req)
+		start := time.Now()
+		resp, err := r.do(req)
 		if err != nil {
+			r.record(recorder, req, reqBody, nil, nil, start, err)
 			if timeoutErr, ok := err.(net.Error); ok && timeoutErr.Timeout() {
 				if verbose {
 					log.Errorf("%s request has timed-out retrying request, count %d",
@@ -327,15 +360,15 @@ func (r *Requester) DoRequest(req *http.Request, path string, body io.Reader, re
 		if err != nil {
 			return err
 		}
+		r.record(recorder, req, reqBody, resp, contents, start, nil)
 
 		if resp.StatusCode != 200 && resp.StatusCode != 201 && resp.StatusCode != 202 {
-			err = fmt.Errorf("unsuccessful HTTP status code: %d", resp.StatusCode)
+			statusErr := &StatusError{StatusCode: resp.StatusCode}
 			if verbose {
-				err = fmt.Errorf("%s\n%s", err.Error(),
-					fmt.Sprintf("%s exchange raw response: %s", r.Name, string(contents)))
+				statusErr.Response = fmt.Sprintf("%s exchange raw response: %s", r.Name, string(contents))
 			}
 
-			return err
+			return statusErr
 		}
 
 		if httpDebug {
@@ -343,8 +376,8 @@ func (r *Requester) DoRequest(req *http.Request, path string, body io.Reader, re
 			if err != nil {
 				log.Errorf("DumpResponse invalid response: %v:", err)
 			}
-			log.Debugf("DumpResponse Headers (%v):\n%s", path, dump)
-			log.Debugf("DumpResponse Body (%v):\n %s", path, string(contents))
+			log.Debugf("DumpResponse Headers (%v):\n%s", path, httprecorder.RedactDump(dump))
+			log.Debugf("DumpResponse Body (%v):\n %s", path, httprecorder.Redact(string(contents)))
 		}
 
 		resp.Body.Close()
@@ -365,50 +398,94 @@ func (r *Requester) DoRequest(req *http.Request, path string, body io.Reader, re
 		timeoutError)
 }
 
+// do sends the request, passing it through the chaos fault injector when
+// enabled
+func (r *Requester) do(req *http.Request) (*http.Response, error) {
+	if inj := chaos.Active(); inj != nil {
+		return inj.Do(r.Name, req, r.HTTPClient.Do)
+	}
+	return r.HTTPClient.Do(req)
+}
+
+// record writes the request and response pair to the HTTP debug recorder
+func (r *Requester) record(recorder *httprecorder.Recorder, req *http.Request, reqBody string, resp *http.Response, contents []byte, start time.Time, reqErr error) {
+	if recorder == nil {
+		return
+	}
+	err := recorder.Record(r.Name, req, reqBody, resp, contents, time.Since(start), reqErr)
+	if err != nil {
+		log.Errorf("%s failed to record HTTP request: %s", r.Name, err)
+	}
+}
+
+// forwardJobs queues jobs sent directly on the deprecated Jobs channel
+func (r *Requester) forwardJobs() {
+	for x := range r.Jobs {
+		j := x
+		if j.Priority > Critical {
+			j.JobResult <- &JobResult{
+				Error: fmt.Errorf("invalid request priority %d", j.Priority),
+			}
+			continue
+		}
+		r.queue.push(&j)
+	}
+}
+
+// worker sends queued jobs in priority order as the rate limiter allows
 func (r *Requester) worker() {
 	for {
-		for x := range r.Jobs {
-			if !r.IsRateLimited(x.AuthRequest) {
-				r.IncrementRequests(x.AuthRequest)
-
-				err := r.DoRequest(x.Request, x.Path, x.Body, x.Result, x.AuthRequest, x.Verbose, x.HTTPDebugging)
-				x.JobResult <- &JobResult{
-					Error:  err,
-					Result: x.Result,
-				}
-			} else {
-				limit := r.GetRateLimit(x.AuthRequest)
-				diff := limit.GetDuration() - time.Since(r.Cycle)
-				if x.Verbose {
-					log.Debugf("%s request. Rate limited! Sleeping for %v", r.Name, diff)
-				}
-				time.Sleep(diff)
-
-				for {
-					if r.IsRateLimited(x.AuthRequest) {
-						time.Sleep(time.Millisecond)
-						continue
-					}
-					r.IncrementRequests(x.AuthRequest)
-
-					if x.Verbose {
-						log.Debugf("%s request. No longer rate limited! Doing request", r.Name)
-					}
-
-					err := r.DoRequest(x.Request, x.Path, x.Body, x.Result, x.AuthRequest, x.Verbose, x.HTTPDebugging)
-					x.JobResult <- &JobResult{
-						Error:  err,
-						Result: x.Result,
-					}
-					break
-				}
+		x, wait := r.nextJob()
+		if x == nil {
+			if wait == 0 {
+				<-r.queue.wait()
+				continue
 			}
+			timer := time.NewTimer(wait)
+			select {
+			case <-timer.C:
+			case <-r.queue.wait():
+				timer.Stop()
+			}
+			continue
+		}
+
+		err := r.DoRequest(x.Request, x.Path, x.Body, x.Result, x.AuthRequest, x.Verbose, x.HTTPDebugging)
+		x.JobResult <- &JobResult{
+			Error:  err,
+			Result: x.Result,
 		}
 	}
 }
 
-// SendPayload handles sending HTTP/HTTPS requests
+// SendPayload handles sending HTTP/HTTPS requests at the default priority for
+// the method
 func (r *Requester) SendPayload(method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, nonceEnabled, verbose, httpDebugging bool) error {
+	return r.SendPayloadPriority(DefaultPriority(method, authRequest), method, path, headers, body, result, authRequest, nonceEnabled, verbose, httpDebugging)
+}
+
+// SendPayloadPriority handles sending HTTP/HTTPS requests, when rate limited
+// higher priority requests are sent first
+func (r *Requester) SendPayloadPriority(priority Priority, method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, nonceEnabled, verbose, httpDebugging bool) error {
+	return r.SendPayloadTagged("", priority, method, path, headers, body, result, authRequest, nonceEnabled, verbose, httpDebugging)
+}
+
+// SendPayloadTagged handles sending HTTP/HTTPS requests tagged with the
+// supplied tag, sent according to the TagMode and counted in the tag stats.
+// An empty tag sends the request untagged
+func (r *Requester) SendPayloadTagged(tag string, priority Priority, method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, nonceEnabled, verbose, httpDebugging bool) error {
+	if r == nil {
+		return errors.New("not initiliased, SetDefaults() called before making request?")
+	}
+
+	err := r.sendPayload(priority, tag, method, path, headers, body, result, authRequest, nonceEnabled, verbose, httpDebugging)
+	r.RecordTag(tag, err)
+	r.notifyObserver(authRequest, err)
+	r.recordUsage(priority, authRequest, err)
+	return err
+}
+
+func (r *Requester) sendPayload(priority Priority, tag, method, path string, headers map[string]string, body io.Reader, result interface{}, authRequest, nonceEnabled, verbose, httpDebugging bool) error {
 	if !nonceEnabled {
 		r.lock()
 	}
@@ -428,7 +505,7 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 		return errors.New("invalid path")
 	}
 
-	req, err := r.checkRequest(method, path, body, headers)
+	req, err := r.checkRequest(method, path, body, r.applyTag(headers, tag))
 	if err != nil {
 		r.unlock()
 		return err
@@ -439,7 +516,7 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 		if err != nil {
 			log.Errorf("DumpRequest invalid response %v:", err)
 		}
-		log.Debugf("DumpRequest:\n%s", dump)
+		log.Debugf("DumpRequest:\n%s", httprecorder.RedactDump(dump))
 	}
 
 	if !r.RequiresRateLimiter() {
@@ -447,7 +524,12 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 		return r.DoRequest(req, path, body, result, authRequest, verbose, httpDebugging)
 	}
 
-	if len(r.Jobs) == maxRequestJobs {
+	if priority > Critical {
+		r.unlock()
+		return fmt.Errorf("invalid request priority %d", priority)
+	}
+
+	if r.queue.len() >= maxRequestJobs {
 		r.unlock()
 		return errors.New("max request jobs reached")
 	}
@@ -457,12 +539,15 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 		r.StartCycle()
 		r.WorkerStarted = true
 		go r.worker()
+		if r.Jobs != nil {
+			go r.forwardJobs()
+		}
 	}
 	r.m.Unlock()
 
 	jobResult := make(chan *JobResult)
 
-	newJob := Job{
+	newJob := &Job{
 		Request:       req,
 		Method:        method,
 		Path:          path,
@@ -473,12 +558,15 @@ func (r *Requester) SendPayload(method, path string, headers map[string]string,
 		AuthRequest:   authRequest,
 		Verbose:       verbose,
 		HTTPDebugging: httpDebugging,
+		Priority:      priority,
+		Tag:           tag,
+		nonce:         nonceEnabled,
 	}
 
 	if verbose {
-		log.Debugf("%s request. Attaching new job.", r.Name)
+		log.Debugf("%s request. Attaching new %s job, tag %q.", r.Name, priority, tag)
 	}
-	r.Jobs <- newJob
+	r.queue.push(newJob)
 	r.unlock()
 
 	if verbose {
@@ -1,10 +1,18 @@
 package request
 
 import (
+	"bytes"
 	"net/http"
+	"net/http/httptest"
 	"net/url"
+	"strings"
+	"sync"
 	"testing"
 	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/apiusage"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/chaos"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/httprecorder"
 )
 
 func TestNewRateLimit(t *testing.T) {
@@ -330,3 +338,353 @@ func BenchmarkRequestLockMech(b *testing.B) {
 		r.SendPayload(http.MethodGet, "127.0.0.1", nil, nil, &meep, false, false, false, false)
 	}
 }
+
+func TestDoRequestChaos(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	inj, err := chaos.New(chaos.Config{Exchanges: []string{"chaos"}, ServerErrorRate: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	chaos.Enable(inj)
+	defer chaos.Enable(nil)
+
+	r := New("chaos", NewRateLimit(time.Second*10, 5), NewRateLimit(time.Second*20, 100), new(http.Client))
+	err = r.SendPayload(http.MethodGet, s.URL, nil, nil, nil, false, false, false, false)
+	if err == nil {
+		t.Fatal("expected injected server error")
+	}
+
+	r.Name = "unaffected"
+	err = r.SendPayload(http.MethodGet, s.URL, nil, nil, nil, false, false, false, false)
+	if err != nil {
+		t.Fatal("unexpected error for exchange excluded from injection", err)
+	}
+}
+
+func TestDoRequestHTTPRecorder(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusUnauthorized)
+		w.Write([]byte(`{"error":"invalid signature"}`))
+	}))
+	defer s.Close()
+
+	var buf bytes.Buffer
+	rec, err := httprecorder.NewRecorder(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	httprecorder.Enable(rec)
+	defer httprecorder.Enable(nil)
+
+	r := New("recorder", NewRateLimit(time.Second*10, 5), NewRateLimit(time.Second*20, 100), new(http.Client))
+	headers := map[string]string{"X-API-KEY": "secretkey", "X-SIGN": "signature"}
+	err = r.SendPayload(http.MethodPost, s.URL, headers, strings.NewReader(`{"nonce":1}`), nil, true, false, false, false)
+	if err == nil {
+		t.Fatal("expected unauthorised error")
+	}
+	if buf.Len() != 0 {
+		t.Fatal("request recorded without HTTP debugging enabled")
+	}
+
+	err = r.SendPayload(http.MethodPost, s.URL, headers, strings.NewReader(`{"nonce":2}`), nil, true, false, false, true)
+	if err == nil {
+		t.Fatal("expected unauthorised error")
+	}
+	records, err := httprecorder.LoadFixtures(&buf)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(records) != 1 ||
+		records[0].RequestBody != `{"nonce":2}` ||
+		records[0].RequestHeaders["X-Api-Key"] != httprecorder.Redacted ||
+		records[0].RequestHeaders["X-Sign"] != "signature" ||
+		records[0].StatusCode != http.StatusUnauthorized {
+		t.Fatalf("unexpected records %+v", records)
+	}
+
+	// Recorded fixtures replay through the requester
+	r.HTTPClient = httprecorder.NewReplayer(records).Client()
+	err = r.SendPayload(http.MethodPost, s.URL, nil, nil, nil, true, false, false, false)
+	if err == nil || !strings.Contains(err.Error(), "401") {
+		t.Fatal("expected replayed unauthorised error", err)
+	}
+}
+
+func TestDefaultPriority(t *testing.T) {
+	if DefaultPriority(http.MethodGet, false) != Background ||
+		DefaultPriority(http.MethodGet, true) != Normal ||
+		DefaultPriority(http.MethodPost, true) != Critical ||
+		DefaultPriority(http.MethodDelete, true) != Critical {
+		t.Fatal("unexpected default priorities")
+	}
+	if Critical.String() != "critical" || Priority(9).String() != "unknown" {
+		t.Fatal("unexpected priority strings")
+	}
+}
+
+func TestNextJob(t *testing.T) {
+	r := New("priority", NewRateLimit(time.Second*10, 10), NewRateLimit(time.Second*10, 10), new(http.Client))
+	r.StartCycle()
+	if j, wait := r.nextJob(); j != nil || wait != 0 {
+		t.Fatal("expected empty queue")
+	}
+
+	r.queue.push(&Job{Path: "ticker", Priority: Background})
+	r.queue.push(&Job{Path: "balance", Priority: Normal, AuthRequest: true})
+	r.queue.push(&Job{Path: "order", Priority: Critical, AuthRequest: true})
+	r.queue.push(&Job{Path: "history", Priority: Background})
+	for _, path := range []string{"order", "balance", "ticker", "history"} {
+		j, _ := r.nextJob()
+		if j == nil || j.Path != path {
+			t.Fatalf("expected %s job, got %+v", path, j)
+		}
+	}
+
+	// Background jobs cannot use the reserved part of the cycle
+	r.UnauthLimit.SetRequests(8)
+	r.queue.push(&Job{Path: "ticker", Priority: Background})
+	if j, wait := r.nextJob(); j != nil || wait <= 0 {
+		t.Fatal("expected background job held back by reserve")
+	}
+	r.queue.push(&Job{Path: "cancel", Priority: Critical})
+	if j, _ := r.nextJob(); j == nil || j.Path != "cancel" {
+		t.Fatalf("expected critical job to use reserve, got %+v", j)
+	}
+	r.StartCycle()
+	if j, _ := r.nextJob(); j == nil || j.Path != "ticker" {
+		t.Fatalf("expected background job after cycle reset, got %+v", j)
+	}
+	if r.UnauthLimit.GetRequests() != 1 {
+		t.Fatal("expected job counted against rate limit")
+	}
+}
+
+func TestNextJobNonceOrder(t *testing.T) {
+	r := New("nonce", NewRateLimit(time.Second*10, 10), NewRateLimit(time.Second*10, 10), new(http.Client))
+	r.StartCycle()
+	r.queue.push(&Job{Path: "balance", Priority: Normal, AuthRequest: true, nonce: true})
+	r.queue.push(&Job{Path: "ticker", Priority: Background})
+	r.queue.push(&Job{Path: "order", Priority: Critical, AuthRequest: true, nonce: true})
+	// Earlier nonces are sent before the critical job so they are not
+	// rejected
+	for _, path := range []string{"balance", "order", "ticker"} {
+		j, _ := r.nextJob()
+		if j == nil || j.Path != path {
+			t.Fatalf("expected %s job, got %+v", path, j)
+		}
+	}
+}
+
+func TestSendPayloadPriority(t *testing.T) {
+	var mtx sync.Mutex
+	var paths []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		paths = append(paths, r.URL.Path)
+		mtx.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	r := New("priority", NewRateLimit(time.Millisecond*200, 1), NewRateLimit(time.Millisecond*200, 1), new(http.Client))
+	err := r.SendPayloadPriority(Critical+1, http.MethodGet, s.URL, nil, nil, nil, false, false, false, false)
+	if err == nil {
+		t.Fatal("expected invalid priority error")
+	}
+	err = r.SendPayload(http.MethodGet, s.URL+"/first", nil, nil, nil, false, false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Both wait for the next cycle, the critical request is sent first
+	var wg sync.WaitGroup
+	send := func(p Priority, path string) {
+		defer wg.Done()
+		err := r.SendPayloadPriority(p, http.MethodGet, s.URL+path, nil, nil, nil, false, false, false, false)
+		if err != nil {
+			t.Error(err)
+		}
+	}
+	wg.Add(1)
+	go send(Background, "/ticker")
+	time.Sleep(time.Millisecond * 20)
+	wg.Add(1)
+	go send(Critical, "/order")
+	wg.Wait()
+
+	if len(paths) != 3 || paths[1] != "/order" || paths[2] != "/ticker" {
+		t.Fatalf("unexpected request order %v", paths)
+	}
+}
+
+func TestSendPayloadJobs(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	var nilRequester *Requester
+	err := nilRequester.SendPayload(http.MethodGet, s.URL, nil, nil, nil, false, false, false, false)
+	if err == nil {
+		t.Fatal("expected uninitialised requester error")
+	}
+
+	r := New("jobs", NewRateLimit(time.Millisecond*200, 1), NewRateLimit(time.Millisecond*200, 1), new(http.Client))
+	err = r.SendPayload(http.MethodGet, s.URL, nil, nil, nil, false, false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, s.URL+"/job", nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	results := make(chan *JobResult, 1)
+	r.Jobs <- Job{Request: req, Path: s.URL + "/job", JobResult: results, Priority: Critical + 1}
+	if res := <-results; res.Error == nil {
+		t.Error("expected invalid priority error")
+	}
+	r.Jobs <- Job{Request: req, Path: s.URL + "/job", JobResult: results}
+	if res := <-results; res.Error != nil {
+		t.Error(res.Error)
+	}
+}
+
+func TestSendPayloadTagged(t *testing.T) {
+	var mtx sync.Mutex
+	var agents, headers []string
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		mtx.Lock()
+		agents = append(agents, r.Header.Get("User-Agent"))
+		headers = append(headers, r.Header.Get(TagHeader))
+		mtx.Unlock()
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	r := New("tag", NewRateLimit(time.Second, 10), NewRateLimit(time.Second, 10), new(http.Client))
+	r.UserAgent = "bot"
+	err := r.SendPayload(http.MethodGet, s.URL, nil, nil, nil, false, false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	send := func(path string) error {
+		return r.SendPayloadTagged("strategy/twap", DefaultPriority(http.MethodGet, false), http.MethodGet, s.URL+path, nil, nil, nil, false, false, false, false)
+	}
+	err = send("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = send("/fail")
+	if err == nil {
+		t.Fatal("expected request error")
+	}
+	r.TagMode = TagInHeader
+	err = send("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	r.RecordTag("strategy/twap", nil)
+
+	if agents[0] != "bot" || agents[1] != "bot (strategy/twap)" || agents[3] != "bot" {
+		t.Errorf("unexpected user agents %v", agents)
+	}
+	if headers[1] != "" || headers[3] != "strategy/twap" {
+		t.Errorf("unexpected tag headers %v", headers)
+	}
+
+	stats := r.GetTagStats()
+	if len(stats) != 1 || stats["strategy/twap"].Requests != 4 || stats["strategy/twap"].Errors != 1 {
+		t.Errorf("unexpected tag stats %+v", stats)
+	}
+}
+
+func TestSendPayloadObserver(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	var requests, errs, auth int
+	r := New("observer", NewRateLimit(time.Second, 10), NewRateLimit(time.Second, 10), new(http.Client))
+	r.SetObserver(func(authenticated bool, err error) {
+		requests++
+		if err != nil {
+			errs++
+		}
+		if authenticated {
+			auth++
+		}
+	})
+	err := r.SendPayload(http.MethodGet, s.URL, nil, nil, nil, true, false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.SendPayload(http.MethodGet, s.URL+"/fail", nil, nil, nil, false, false, false, false)
+	if err == nil {
+		t.Fatal("expected request error")
+	}
+	if requests != 2 || errs != 1 || auth != 1 {
+		t.Errorf("unexpected observations requests %d errors %d authenticated %d", requests, errs, auth)
+	}
+
+	r.SetObserver(nil)
+	err = r.SendPayload(http.MethodGet, s.URL, nil, nil, nil, false, false, false, false)
+	if err != nil || requests != 2 {
+		t.Errorf("expected observer removed, requests %d err %v", requests, err)
+	}
+}
+
+func TestSendPayloadUsage(t *testing.T) {
+	s := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/limited" {
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	defer s.Close()
+
+	tracker := apiusage.New()
+	apiusage.Enable(tracker)
+	defer apiusage.Enable(nil)
+
+	r := New("usage", NewRateLimit(time.Second, 10), NewRateLimit(time.Second, 20), new(http.Client))
+	err := r.SendPayload(http.MethodGet, s.URL, nil, nil, nil, false, false, false, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = r.SendPayload(http.MethodPost, s.URL+"/limited", nil, nil, nil, true, false, false, false)
+	if statusErr, ok := err.(*StatusError); !ok || !statusErr.RateLimited() {
+		t.Fatalf("expected rate limited status error, received %v", err)
+	}
+
+	report := tracker.Report("usage", apiusage.Limit{}, apiusage.Limit{})
+	if report.Classes[apiusage.MarketData].Requests != 1 || report.Public.Requests != 1 {
+		t.Errorf("unexpected market data usage %+v", report)
+	}
+	trading := report.Classes[apiusage.Trading]
+	if trading.Requests != 1 || trading.Errors != 1 || trading.RateLimited != 1 || report.Private.Requests != 1 {
+		t.Errorf("unexpected trading usage %+v", report)
+	}
+}
@@ -0,0 +1,86 @@
+package request
+
+// TagHeader is the header requests are tagged with in TagInHeader mode
+const TagHeader = "X-GCT-Tag"
+
+// TagMode defines how request tags are sent to the exchange
+type TagMode uint8
+
+// Request tag modes
+const (
+	// TagInUserAgent appends the tag to the User-Agent
+	TagInUserAgent TagMode = iota
+	// TagInHeader sends the tag in the TagHeader header, for exchanges which
+	// accept custom headers
+	TagInHeader
+	// TagNotSent only records the tag in logs and statistics, for exchanges
+	// which reject modified requests
+	TagNotSent
+)
+
+// TagStats holds the requests sent under a tag, attributing rate limit
+// consumption and errors to the strategy which made them
+type TagStats struct {
+	Requests  int64  `json:"requests"`
+	Errors    int64  `json:"errors"`
+	LastError string `json:"lastError,omitempty"`
+}
+
+// applyTag adds the tag to the request headers according to the tag mode
+func (r *Requester) applyTag(headers map[string]string, tag string) map[string]string {
+	if tag == "" || r.TagMode == TagNotSent {
+		return headers
+	}
+	tagged := make(map[string]string, len(headers)+1)
+	for k, v := range headers {
+		tagged[k] = v
+	}
+	if r.TagMode == TagInHeader {
+		tagged[TagHeader] = tag
+		return tagged
+	}
+	ua, ok := tagged["User-Agent"]
+	if !ok {
+		ua = r.UserAgent
+	}
+	if ua == "" {
+		ua = "GoCryptoTrader"
+	}
+	tagged["User-Agent"] = ua + " (" + tag + ")"
+	return tagged
+}
+
+// RecordTag counts a request sent under the tag. Requests sent with
+// SendPayloadTagged are counted automatically, wrappers use it to attribute
+// exchange calls which cannot carry a tag to the request layer
+func (r *Requester) RecordTag(tag string, err error) {
+	if tag == "" {
+		return
+	}
+	r.tagMtx.Lock()
+	defer r.tagMtx.Unlock()
+	if r.tagStats == nil {
+		r.tagStats = make(map[string]*TagStats)
+	}
+	s, ok := r.tagStats[tag]
+	if !ok {
+		s = &TagStats{}
+		r.tagStats[tag] = s
+	}
+	s.Requests++
+	if err != nil {
+		s.Errors++
+		s.LastError = err.Error()
+	}
+}
+
+// GetTagStats returns the requests sent under each tag
+func (r *Requester) GetTagStats() map[string]TagStats {
+	r.tagMtx.Lock()
+	defer r.tagMtx.Unlock()
+	stats := make(map[string]TagStats, len(r.tagStats))
+	for k, v := range r.tagStats {
+		stats[k] = *v
+	}
+	return stats
+}
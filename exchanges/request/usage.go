@@ -0,0 +1,50 @@
+package request
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/apiusage"
+)
+
+// StatusError is returned when an exchange responds with an unsuccessful
+// HTTP status code
+type StatusError struct {
+	StatusCode int
+	// Response holds the raw response when requests are verbose
+	Response string
+}
+
+func (e *StatusError) Error() string {
+	if e.Response != "" {
+		return fmt.Sprintf("unsuccessful HTTP status code: %d\n%s", e.StatusCode, e.Response)
+	}
+	return fmt.Sprintf("unsuccessful HTTP status code: %d", e.StatusCode)
+}
+
+// RateLimited returns whether the exchange rejected the request for
+// exceeding its rate limit, some exchanges ban with 418 after repeated 429s
+func (e *StatusError) RateLimited() bool {
+	return e.StatusCode == http.StatusTooManyRequests || e.StatusCode == http.StatusTeapot
+}
+
+// usageClass returns the API usage endpoint class of a request priority
+func usageClass(priority Priority) string {
+	switch priority {
+	case Normal:
+		return apiusage.Account
+	case Critical:
+		return apiusage.Trading
+	}
+	return apiusage.MarketData
+}
+
+// recordUsage counts the request with the API usage tracker when enabled
+func (r *Requester) recordUsage(priority Priority, authRequest bool, err error) {
+	t := apiusage.Active()
+	if t == nil {
+		return
+	}
+	statusErr, ok := err.(*StatusError)
+	t.Request(r.Name, usageClass(priority), authRequest, err != nil, ok && statusErr.RateLimited())
+}
@@ -0,0 +1,492 @@
+// Package rollup aggregates balances across every exchange account and
+// sub-account into groupings such as desk or owner, generalising the
+// aggregated sub-user balances offered by Huobi to the whole engine. Each
+// grouping assigns accounts to named groups, the balances of accounts no group
+// claims are reported as unassigned. Balances allocated to strategies are
+// split out of their exchange accounts for the strategy grouping
+package rollup
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/allocation"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// DefaultRefreshInterval is the interval balances are fetched at
+const DefaultRefreshInterval = time.Minute * 5
+
+// Built in groupings
+const (
+	ByExchange = "exchange"
+	ByAccount  = "account"
+	ByStrategy = "strategy"
+)
+
+// Groups balances are reported in when no group claims them
+const (
+	Unassigned  = "unassigned"
+	Unallocated = "unallocated"
+)
+
+// DefaultAccount names accounts the exchange does not identify
+const DefaultAccount = "main"
+
+// strategyPrefix marks a group entry selecting a strategy's allocation
+const strategyPrefix = "strategy:"
+
+var (
+	errNoPriceFunc      = errors.New("no price function supplied")
+	errReservedGrouping = errors.New("grouping name is reserved")
+	errEmptyGroup       = errors.New("group names and entries cannot be empty")
+	errDuplicateEntry   = errors.New("entry is assigned to more than one group")
+	errUnknownGrouping  = errors.New("unknown grouping")
+	errNotRefreshed     = errors.New("balances not yet fetched")
+)
+
+// PriceFunc returns the value of one unit of a currency on an exchange in the
+// reporting currency
+type PriceFunc func(exchangeName string, c currency.Code) (float64, error)
+
+// StrategiesFunc returns the balances allocated to each strategy
+type StrategiesFunc func() []allocation.Report
+
+// Groupings maps a grouping, such as desk or owner, to its groups and the
+// entries each holds. An entry selects an exchange, an exchange account as
+// Exchange:Account or a strategy's allocation as strategy:Name. The most
+// specific entry matching a balance decides its group
+type Groupings map[string]map[string][]string
+
+// Holding holds an account's balance of a currency
+type Holding struct {
+	Exchange string `json:"exchange"`
+	Account  string `json:"account"`
+	// SubAccount is set on balances held in sub-accounts
+	SubAccount bool `json:"subAccount,omitempty"`
+	// Strategy is set on the part of the balance allocated to a strategy
+	Strategy string        `json:"strategy,omitempty"`
+	Currency currency.Code `json:"currency"`
+	Total    float64       `json:"total"`
+	Hold     float64       `json:"hold"`
+	Value    float64       `json:"value"`
+	Priced   bool          `json:"priced"`
+}
+
+// Balance holds a group's total of a currency
+type Balance struct {
+	Currency currency.Code `json:"currency"`
+	Total    float64       `json:"total"`
+	Hold     float64       `json:"hold"`
+	Value    float64       `json:"value"`
+}
+
+// Group holds the balances rolled up into a group
+type Group struct {
+	Name     string    `json:"name"`
+	Balances []Balance `json:"balances"`
+	Value    float64   `json:"value"`
+	// Accounts lists the Exchange:Account accounts contributing balances
+	Accounts []string `json:"accounts"`
+}
+
+// Report holds the balances of every group in a grouping
+type Report struct {
+	Grouping string  `json:"grouping"`
+	Groups   []Group `json:"groups"`
+	Value    float64 `json:"value"`
+	// Unpriced lists balances which could not be valued and are excluded
+	// from the values
+	Unpriced []string `json:"unpriced,omitempty"`
+	// Errors holds the exchanges whose balances could not be fetched
+	Errors  map[string]string `json:"errors,omitempty"`
+	Updated time.Time         `json:"updated"`
+}
+
+// Rollup aggregates exchange balances into groupings
+type Rollup struct {
+	groupings  Groupings
+	exchanges  []exchange.IBotExchange
+	price      PriceFunc
+	strategies StrategiesFunc
+	holdings   []Holding
+	errs       map[string]string
+	updated    time.Time
+	now        func() time.Time
+	shutdown   chan struct{}
+	wg         sync.WaitGroup
+	mtx        sync.Mutex
+}
+
+// Load returns a roll-up of the exchanges' balances into the groupings stored
+// as JSON at path. An empty path only reports the built in groupings
+func Load(path string, exchanges []exchange.IBotExchange, price PriceFunc, strategies StrategiesFunc) (*Rollup, error) {
+	var g Groupings
+	if path != "" {
+		data, err := common.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		err = json.Unmarshal(data, &g)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return New(g, exchanges, price, strategies)
+}
+
+// New returns a roll-up of the exchanges' balances into the groupings.
+// Strategies may be nil when balances are not allocated to strategies
+func New(groupings Groupings, exchanges []exchange.IBotExchange, price PriceFunc, strategies StrategiesFunc) (*Rollup, error) {
+	if price == nil {
+		return nil, errNoPriceFunc
+	}
+	for name, groups := range groupings {
+		switch strings.ToLower(name) {
+		case ByExchange, ByAccount, ByStrategy:
+			return nil, fmt.Errorf("%s %v", name, errReservedGrouping)
+		}
+		seen := make(map[string]string)
+		for group, entries := range groups {
+			if group == "" {
+				return nil, fmt.Errorf("%s %v", name, errEmptyGroup)
+			}
+			for _, entry := range entries {
+				if strings.TrimSpace(entry) == "" {
+					return nil, fmt.Errorf("%s %s %v", name, group, errEmptyGroup)
+				}
+				k := strings.ToLower(entry)
+				if prev, ok := seen[k]; ok {
+					return nil, fmt.Errorf("%s %s in %s and %s %v", name, entry, prev, group, errDuplicateEntry)
+				}
+				seen[k] = group
+			}
+		}
+	}
+	return &Rollup{
+		groupings:  groupings,
+		exchanges:  exchanges,
+		price:      price,
+		strategies: strategies,
+		now:        time.Now,
+	}, nil
+}
+
+// Refresh fetches the balances of every exchange account and sub-account
+func (r *Rollup) Refresh() {
+	var holdings []Holding
+	errs := make(map[string]string)
+	for _, e := range r.exchanges {
+		name := e.GetName()
+		info, err := e.GetAccountInfo()
+		if err != nil {
+			log.Errorf("Roll-up failed to fetch %s balances: %s", name, err)
+			errs[name] = err.Error()
+			continue
+		}
+		holdings = append(holdings, accountHoldings(name, info.Accounts, false)...)
+
+		p, ok := exchange.Underlying(e).(exchange.SubAccountProvider)
+		if !ok {
+			continue
+		}
+		subAccounts, err := p.GetSubAccountBalances()
+		if err != nil {
+			log.Errorf("Roll-up failed to fetch %s sub-account balances: %s", name, err)
+			errs[name] = err.Error()
+			continue
+		}
+		holdings = append(holdings, accountHoldings(name, subAccounts, true)...)
+	}
+
+	if r.strategies != nil {
+		holdings = splitStrategies(holdings, r.strategies())
+	}
+	for i := range holdings {
+		v, err := r.price(holdings[i].Exchange, holdings[i].Currency)
+		if err == nil {
+			holdings[i].Value = v * holdings[i].Total
+			holdings[i].Priced = true
+		}
+	}
+
+	r.mtx.Lock()
+	r.holdings = holdings
+	r.errs = errs
+	r.updated = r.now()
+	r.mtx.Unlock()
+}
+
+func accountHoldings(exchangeName string, accounts []exchange.Account, subAccount bool) []Holding {
+	var resp []Holding
+	for i := range accounts {
+		id := accounts[i].ID
+		if id == "" {
+			id = DefaultAccount
+		}
+		for _, c := range accounts[i].Currencies {
+			if c.TotalValue == 0 && c.Hold == 0 {
+				continue
+			}
+			resp = append(resp, Holding{
+				Exchange:   exchangeName,
+				Account:    id,
+				SubAccount: subAccount,
+				Currency:   c.CurrencyName,
+				Total:      c.TotalValue,
+				Hold:       c.Hold,
+			})
+		}
+	}
+	return resp
+}
+
+// splitStrategies moves the balances allocated to strategies out of the
+// exchange's accounts, in the order the accounts were returned. Sub-accounts
+// are left whole as strategies trade the exchange's own accounts
+func splitStrategies(holdings []Holding, reports []allocation.Report) []Holding {
+	for i := range reports {
+		for _, b := range reports[i].Balances {
+			if b.Total <= 0 {
+				continue
+			}
+			remaining := b.Total
+			var account string
+			for j := range holdings {
+				h := &holdings[j]
+				if h.SubAccount || h.Strategy != "" ||
+					!strings.EqualFold(h.Exchange, b.Exchange) || !h.Currency.Match(b.Currency) {
+					continue
+				}
+				if account == "" {
+					account = h.Account
+				}
+				moved := remaining
+				if moved > h.Total {
+					moved = h.Total
+				}
+				h.Total -= moved
+				remaining -= moved
+				if remaining <= 0 {
+					break
+				}
+			}
+			if account == "" {
+				account = DefaultAccount
+			}
+			holdings = append(holdings, Holding{
+				Exchange: b.Exchange,
+				Account:  account,
+				Strategy: reports[i].Strategy,
+				Currency: b.Currency,
+				Total:    b.Total,
+				Hold:     b.Reserved,
+			})
+		}
+	}
+	return holdings
+}
+
+// group returns the group a holding is rolled up into
+func (r *Rollup) group(grouping string, h *Holding) string {
+	switch grouping {
+	case ByExchange:
+		return h.Exchange
+	case ByAccount:
+		return h.Exchange + ":" + h.Account
+	case ByStrategy:
+		if h.Strategy == "" {
+			return Unallocated
+		}
+		return h.Strategy
+	}
+
+	// Strategies are matched before accounts, accounts before exchanges
+	var candidates []string
+	if h.Strategy != "" {
+		candidates = append(candidates, strategyPrefix+h.Strategy)
+	}
+	candidates = append(candidates, h.Exchange+":"+h.Account, h.Exchange)
+	for _, candidate := range candidates {
+		for group, entries := range r.groupings[grouping] {
+			for _, entry := range entries {
+				if strings.EqualFold(entry, candidate) {
+					return group
+				}
+			}
+		}
+	}
+	return Unassigned
+}
+
+// GetGroupings returns the names of every grouping reported
+func (r *Rollup) GetGroupings() []string {
+	resp := []string{ByExchange, ByAccount}
+	if r.strategies != nil {
+		resp = append(resp, ByStrategy)
+	}
+	var configured []string
+	for name := range r.groupings {
+		configured = append(configured, name)
+	}
+	sort.Strings(configured)
+	return append(resp, configured...)
+}
+
+// GetReport rolls the latest balances up into the grouping's groups
+func (r *Rollup) GetReport(grouping string) (Report, error) {
+	name := ""
+	for _, g := range r.GetGroupings() {
+		if strings.EqualFold(g, grouping) {
+			name = g
+			break
+		}
+	}
+	if name == "" {
+		return Report{}, fmt.Errorf("%s %v", grouping, errUnknownGrouping)
+	}
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if r.updated.IsZero() {
+		return Report{}, errNotRefreshed
+	}
+
+	resp := Report{Grouping: name, Updated: r.updated}
+	if len(r.errs) > 0 {
+		resp.Errors = make(map[string]string, len(r.errs))
+		for k, v := range r.errs {
+			resp.Errors[k] = v
+		}
+	}
+	groups := make(map[string]*Group)
+	accounts := make(map[string]map[string]bool)
+	unpriced := make(map[string]bool)
+	for i := range r.holdings {
+		h := &r.holdings[i]
+		if h.Total == 0 && h.Hold == 0 {
+			continue
+		}
+		groupName := r.group(name, h)
+		g, ok := groups[groupName]
+		if !ok {
+			g = &Group{Name: groupName}
+			groups[groupName] = g
+			accounts[groupName] = make(map[string]bool)
+		}
+		account := h.Exchange + ":" + h.Account
+		if !accounts[groupName][account] {
+			accounts[groupName][account] = true
+			g.Accounts = append(g.Accounts, account)
+		}
+
+		var b *Balance
+		for j := range g.Balances {
+			if g.Balances[j].Currency.Match(h.Currency) {
+				b = &g.Balances[j]
+				break
+			}
+		}
+		if b == nil {
+			g.Balances = append(g.Balances, Balance{Currency: h.Currency})
+			b = &g.Balances[len(g.Balances)-1]
+		}
+		b.Total += h.Total
+		b.Hold += h.Hold
+		if !h.Priced {
+			if k := account + " " + h.Currency.String(); !unpriced[k] {
+				unpriced[k] = true
+				resp.Unpriced = append(resp.Unpriced, k)
+			}
+			continue
+		}
+		b.Value += h.Value
+		g.Value += h.Value
+		resp.Value += h.Value
+	}
+
+	for _, g := range groups {
+		sort.Slice(g.Balances, func(i, j int) bool {
+			return g.Balances[i].Currency.String() < g.Balances[j].Currency.String()
+		})
+		sort.Strings(g.Accounts)
+		resp.Groups = append(resp.Groups, *g)
+	}
+	sort.Slice(resp.Groups, func(i, j int) bool {
+		return resp.Groups[i].Name < resp.Groups[j].Name
+	})
+	return resp, nil
+}
+
+// GetReports returns a report of every grouping
+func (r *Rollup) GetReports() ([]Report, error) {
+	groupings := r.GetGroupings()
+	resp := make([]Report, 0, len(groupings))
+	for i := range groupings {
+		report, err := r.GetReport(groupings[i])
+		if err != nil {
+			return nil, err
+		}
+		resp = append(resp, report)
+	}
+	return resp, nil
+}
+
+// GetHoldings returns the latest balance of every account
+func (r *Rollup) GetHoldings() []Holding {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return append([]Holding(nil), r.holdings...)
+}
+
+// Start fetches balances at the interval until stopped
+func (r *Rollup) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultRefreshInterval
+	}
+	r.mtx.Lock()
+	if r.shutdown != nil {
+		r.mtx.Unlock()
+		return
+	}
+	r.shutdown = make(chan struct{})
+	shutdown := r.shutdown
+	r.mtx.Unlock()
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+		r.Refresh()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				r.Refresh()
+			}
+		}
+	}()
+}
+
+// Stop stops fetching balances
+func (r *Rollup) Stop() {
+	r.mtx.Lock()
+	if r.shutdown == nil {
+		r.mtx.Unlock()
+		return
+	}
+	close(r.shutdown)
+	r.shutdown = nil
+	r.mtx.Unlock()
+	r.wg.Wait()
+}
@@ -0,0 +1,139 @@
+package rollup
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/allocation"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	name     string
+	accounts []exchange.Account
+	err      error
+}
+
+func (t *testExchange) GetName() string { return t.name }
+
+func (t *testExchange) GetAccountInfo() (exchange.AccountInfo, error) {
+	return exchange.AccountInfo{Exchange: t.name, Accounts: t.accounts}, t.err
+}
+
+type testSubAccounts struct {
+	testExchange
+}
+
+func (t *testSubAccounts) GetSubAccountBalances() ([]exchange.Account, error) {
+	return []exchange.Account{{ID: "subusers", Currencies: []exchange.AccountCurrencyInfo{
+		{CurrencyName: currency.BTC, TotalValue: 1},
+	}}}, nil
+}
+
+func testPrices(_ string, c currency.Code) (float64, error) {
+	switch c {
+	case currency.BTC:
+		return 10000, nil
+	case currency.USD:
+		return 1, nil
+	}
+	return 0, errors.New("no price")
+}
+
+func testExchanges() []exchange.IBotExchange {
+	return []exchange.IBotExchange{
+		&testExchange{name: "Bitstamp", accounts: []exchange.Account{{Currencies: []exchange.AccountCurrencyInfo{
+			{CurrencyName: currency.BTC, TotalValue: 2, Hold: 0.5},
+			{CurrencyName: currency.USD, TotalValue: 5000},
+			{CurrencyName: currency.XRP, TotalValue: 100},
+		}}}},
+		&testSubAccounts{testExchange{name: "Huobi", accounts: []exchange.Account{{ID: "1", Currencies: []exchange.AccountCurrencyInfo{
+			{CurrencyName: currency.USD, TotalValue: 1000},
+		}}}}},
+		&testExchange{name: "Kraken", err: errors.New("invalid key")},
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(nil, nil, nil, nil); err != errNoPriceFunc {
+		t.Error("Test Failed - New() expected no price function error", err)
+	}
+	if _, err := New(Groupings{"Exchange": {}}, nil, testPrices, nil); err == nil {
+		t.Error("Test Failed - New() expected reserved grouping error")
+	}
+	_, err := New(Groupings{"desk": {"spot": {"Bitstamp"}, "otc": {"bitstamp"}}}, nil, testPrices, nil)
+	if err == nil {
+		t.Error("Test Failed - New() expected duplicate entry error")
+	}
+	if _, err = Load("", nil, testPrices, nil); err != nil {
+		t.Error("Test Failed - Load() error", err)
+	}
+}
+
+func TestGetReport(t *testing.T) {
+	strategies := func() []allocation.Report {
+		return []allocation.Report{{Strategy: "grid", Balances: []allocation.Balance{
+			{Exchange: "Bitstamp", Currency: currency.BTC, Total: 0.5, Reserved: 0.1},
+		}}}
+	}
+	r, err := New(Groupings{"desk": {
+		"spot":   {"Bitstamp"},
+		"otc":    {"Huobi:subusers"},
+		"quant":  {"strategy:grid"},
+		"wallet": {"Huobi"},
+	}}, testExchanges(), testPrices, strategies)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.GetReport("desk"); err != errNotRefreshed {
+		t.Error("Test Failed - GetReport() expected not refreshed error", err)
+	}
+	r.Refresh()
+
+	if g := r.GetGroupings(); len(g) != 4 || g[2] != ByStrategy || g[3] != "desk" {
+		t.Errorf("Test Failed - GetGroupings() unexpected groupings %v", g)
+	}
+	if _, err = r.GetReport("owner"); err == nil {
+		t.Error("Test Failed - GetReport() expected unknown grouping error")
+	}
+
+	report, err := r.GetReport("Desk")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Value != 36000 || len(report.Groups) != 4 || report.Errors["Kraken"] != "invalid key" {
+		t.Fatalf("Test Failed - GetReport() unexpected report %+v", report)
+	}
+	otc, quant, spot, wallet := report.Groups[0], report.Groups[1], report.Groups[2], report.Groups[3]
+	if otc.Name != "otc" || otc.Value != 10000 || otc.Accounts[0] != "Huobi:subusers" {
+		t.Errorf("Test Failed - GetReport() unexpected sub-account group %+v", otc)
+	}
+	if quant.Value != 5000 || quant.Balances[0].Hold != 0.1 {
+		t.Errorf("Test Failed - GetReport() unexpected strategy group %+v", quant)
+	}
+	if spot.Value != 20000 || spot.Balances[0].Total != 1.5 || spot.Accounts[0] != "Bitstamp:main" {
+		t.Errorf("Test Failed - GetReport() unexpected exchange group %+v", spot)
+	}
+	if wallet.Value != 1000 || len(report.Unpriced) != 1 || report.Unpriced[0] != "Bitstamp:main XRP" {
+		t.Errorf("Test Failed - GetReport() unexpected account group %+v %v", wallet, report.Unpriced)
+	}
+
+	report, err = r.GetReport(ByStrategy)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.Groups) != 2 || report.Groups[0].Name != "grid" || report.Groups[1].Name != Unallocated ||
+		report.Groups[1].Value != 31000 {
+		t.Errorf("Test Failed - GetReport() unexpected strategy report %+v", report.Groups)
+	}
+
+	reports, err := r.GetReports()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reports) != 4 || len(reports[0].Groups) != 2 || len(reports[1].Groups) != 3 {
+		t.Errorf("Test Failed - GetReports() unexpected reports %+v", reports)
+	}
+}
@@ -0,0 +1,187 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// RoundingMode defines how an order value is rounded to an exchange step
+type RoundingMode string
+
+// Rounding modes
+const (
+	// RoundPassive rounds prices away from the market, down for buys and up
+	// for sells, so rounding never makes an order more aggressive
+	RoundPassive RoundingMode = "passive"
+	// RoundNearest rounds to the nearest step
+	RoundNearest RoundingMode = "nearest"
+	// RoundDown truncates to the step below
+	RoundDown RoundingMode = "down"
+	// RoundUp rounds to the step above
+	RoundUp RoundingMode = "up"
+)
+
+// stepEpsilon absorbs float error when a value is already on a step, so 0.3
+// with a step of 0.1 is not truncated to 0.2
+const stepEpsilon = 1e-9
+
+var (
+	// ErrBelowMinimum is returned when an order is below the exchange's
+	// minimum amount or notional once rounded
+	ErrBelowMinimum = errors.New("order below exchange minimum")
+
+	errUnknownRoundingMode = errors.New("unknown rounding mode")
+	errInvalidOrderAmount  = errors.New("order amount must be greater than zero")
+	errInvalidOrderPrice   = errors.New("order price must be greater than zero")
+)
+
+// TradingRules holds an exchange's price and size increments and minimums for
+// a currency pair. Zero values are not enforced
+type TradingRules struct {
+	PriceStep      float64
+	AmountStep     float64
+	MinAmount      float64
+	MinNotional    float64
+	PriceRounding  RoundingMode
+	AmountRounding RoundingMode
+}
+
+// TradingRulesProvider is implemented by exchanges which publish the price and
+// size increments of their currency pairs
+type TradingRulesProvider interface {
+	GetTradingRules(p currency.Pair) (TradingRules, error)
+}
+
+// ParseRoundingMode returns the rounding mode matching s
+func ParseRoundingMode(s string) (RoundingMode, error) {
+	m := RoundingMode(strings.ToLower(strings.TrimSpace(s)))
+	switch m {
+	case RoundPassive, RoundNearest, RoundDown, RoundUp:
+		return m, nil
+	case "truncate":
+		return RoundDown, nil
+	}
+	return "", fmt.Errorf("%s %v", s, errUnknownRoundingMode)
+}
+
+// Override returns the rules with every non zero field of o replacing the
+// exchange's value
+func (r TradingRules) Override(o TradingRules) TradingRules {
+	if o.PriceStep != 0 {
+		r.PriceStep = o.PriceStep
+	}
+	if o.AmountStep != 0 {
+		r.AmountStep = o.AmountStep
+	}
+	if o.MinAmount != 0 {
+		r.MinAmount = o.MinAmount
+	}
+	if o.MinNotional != 0 {
+		r.MinNotional = o.MinNotional
+	}
+	if o.PriceRounding != "" {
+		r.PriceRounding = o.PriceRounding
+	}
+	if o.AmountRounding != "" {
+		r.AmountRounding = o.AmountRounding
+	}
+	return r
+}
+
+// RoundPrice rounds the price to the price step. Prices are rounded towards
+// the passive side unless another mode is set
+func (r *TradingRules) RoundPrice(side OrderSide, price float64) float64 {
+	mode := r.PriceRounding
+	if mode == "" {
+		mode = RoundPassive
+	}
+	return RoundToStep(price, r.PriceStep, mode, side)
+}
+
+// RoundAmount rounds the amount to the amount step. Amounts are truncated
+// unless another mode is set
+func (r *TradingRules) RoundAmount(side OrderSide, amount float64) float64 {
+	mode := r.AmountRounding
+	if mode == "" {
+		mode = RoundDown
+	}
+	return RoundToStep(amount, r.AmountStep, mode, side)
+}
+
+// Apply rounds the order's amount and price and checks them against the
+// minimums. Market order prices are left as supplied since they are not sent
+// to the exchange as a limit
+func (r *TradingRules) Apply(side OrderSide, orderType OrderType, amount, price float64) (float64, float64, error) {
+	if amount <= 0 {
+		return 0, 0, errInvalidOrderAmount
+	}
+	if orderType != MarketOrderType {
+		if price <= 0 {
+			return 0, 0, errInvalidOrderPrice
+		}
+		price = r.RoundPrice(side, price)
+		if price <= 0 {
+			return 0, 0, fmt.Errorf("price rounds to zero with step %v: %v",
+				r.PriceStep, ErrBelowMinimum)
+		}
+	}
+
+	rounded := r.RoundAmount(side, amount)
+	if rounded <= 0 {
+		return 0, 0, fmt.Errorf("amount %v rounds to zero with step %v: %v",
+			amount, r.AmountStep, ErrBelowMinimum)
+	}
+	if r.MinAmount > 0 && rounded < r.MinAmount-stepEpsilon*r.MinAmount {
+		return 0, 0, fmt.Errorf("amount %v below minimum %v: %v",
+			rounded, r.MinAmount, ErrBelowMinimum)
+	}
+	if r.MinNotional > 0 && price > 0 &&
+		rounded*price < r.MinNotional-stepEpsilon*r.MinNotional {
+		return 0, 0, fmt.Errorf("notional %v below minimum %v: %v",
+			rounded*price, r.MinNotional, ErrBelowMinimum)
+	}
+	return rounded, price, nil
+}
+
+// RoundToStep rounds the value to a multiple of step using the mode. The side
+// is only used by passive rounding, a zero step leaves the value unchanged
+func RoundToStep(value, step float64, mode RoundingMode, side OrderSide) float64 {
+	if step <= 0 {
+		return value
+	}
+
+	n := value / step
+	switch mode {
+	case RoundPassive:
+		if side == SellOrderSide || side == AskOrderSide {
+			n = math.Ceil(n - stepEpsilon)
+		} else {
+			n = math.Floor(n + stepEpsilon)
+		}
+	case RoundNearest:
+		n = math.Round(n)
+	case RoundUp:
+		n = math.Ceil(n - stepEpsilon)
+	default:
+		n = math.Floor(n + stepEpsilon)
+	}
+
+	// Trim the float error of the multiplication to the step's precision so
+	// 3 steps of 0.1 are sent as 0.3 rather than 0.30000000000000004
+	scale := math.Pow(10, float64(stepDecimals(step)))
+	return math.Round(n*step*scale) / scale
+}
+
+func stepDecimals(step float64) int {
+	s := strconv.FormatFloat(step, 'f', -1, 64)
+	i := strings.IndexByte(s, '.')
+	if i == -1 {
+		return 0
+	}
+	return len(s) - i - 1
+}
@@ -0,0 +1,140 @@
+package exchange
+
+import (
+	"testing"
+)
+
+func TestRoundToStep(t *testing.T) {
+	tests := []struct {
+		value float64
+		step  float64
+		mode  RoundingMode
+		side  OrderSide
+		want  float64
+	}{
+		{100.123, 0.01, RoundPassive, BuyOrderSide, 100.12},
+		{100.123, 0.01, RoundPassive, SellOrderSide, 100.13},
+		{100.123, 0.01, RoundPassive, BidOrderSide, 100.12},
+		{100.123, 0.01, RoundPassive, AskOrderSide, 100.13},
+		{100.125, 0.01, RoundNearest, BuyOrderSide, 100.13},
+		{100.129, 0.01, RoundDown, SellOrderSide, 100.12},
+		{100.121, 0.01, RoundUp, BuyOrderSide, 100.13},
+		// values already on a step are not moved by float error
+		{0.3, 0.1, RoundDown, BuyOrderSide, 0.3},
+		{0.3, 0.1, RoundUp, BuyOrderSide, 0.3},
+		{0.3, 0.1, RoundPassive, SellOrderSide, 0.3},
+		{1.15, 0.05, RoundPassive, BuyOrderSide, 1.15},
+		{0.00300000, 0.00000100, RoundDown, BuyOrderSide, 0.003},
+		// steps greater than one
+		{10525, 50, RoundPassive, BuyOrderSide, 10500},
+		{10525, 50, RoundPassive, SellOrderSide, 10550},
+		{0.5, 1, RoundDown, BuyOrderSide, 0},
+		// no step leaves the value unchanged
+		{100.123456, 0, RoundDown, BuyOrderSide, 100.123456},
+	}
+
+	for i := range tests {
+		got := RoundToStep(tests[i].value, tests[i].step, tests[i].mode, tests[i].side)
+		if got != tests[i].want {
+			t.Errorf("Test Failed - RoundToStep() %v step %v %s %s got %v, expected %v",
+				tests[i].value, tests[i].step, tests[i].mode, tests[i].side, got, tests[i].want)
+		}
+	}
+}
+
+func TestParseRoundingMode(t *testing.T) {
+	m, err := ParseRoundingMode(" Passive")
+	if err != nil || m != RoundPassive {
+		t.Errorf("Test Failed - ParseRoundingMode() got %s %v", m, err)
+	}
+	m, err = ParseRoundingMode("truncate")
+	if err != nil || m != RoundDown {
+		t.Errorf("Test Failed - ParseRoundingMode() got %s %v", m, err)
+	}
+	_, err = ParseRoundingMode("sideways")
+	if err == nil {
+		t.Error("Test Failed - ParseRoundingMode() expected error for unknown mode")
+	}
+}
+
+func TestTradingRulesApply(t *testing.T) {
+	rules := TradingRules{
+		PriceStep:   0.01,
+		AmountStep:  0.001,
+		MinAmount:   0.001,
+		MinNotional: 10,
+	}
+
+	amount, price, err := rules.Apply(BuyOrderSide, LimitOrderType, 0.0129, 1000.019)
+	if err != nil {
+		t.Fatal("Test Failed - Apply() error", err)
+	}
+	if amount != 0.012 || price != 1000.01 {
+		t.Errorf("Test Failed - Apply() buy got %v @ %v", amount, price)
+	}
+
+	amount, price, err = rules.Apply(SellOrderSide, LimitOrderType, 0.0129, 1000.011)
+	if err != nil {
+		t.Fatal("Test Failed - Apply() error", err)
+	}
+	if amount != 0.012 || price != 1000.02 {
+		t.Errorf("Test Failed - Apply() sell got %v @ %v", amount, price)
+	}
+
+	// market order prices are not rounded
+	amount, price, err = rules.Apply(BuyOrderSide, MarketOrderType, 0.0129, 1000.019)
+	if err != nil {
+		t.Fatal("Test Failed - Apply() error", err)
+	}
+	if amount != 0.012 || price != 1000.019 {
+		t.Errorf("Test Failed - Apply() market got %v @ %v", amount, price)
+	}
+
+	_, _, err = rules.Apply(BuyOrderSide, LimitOrderType, 0.0009, 1000)
+	if err == nil {
+		t.Error("Test Failed - Apply() expected error when amount truncates to zero")
+	}
+	_, _, err = rules.Apply(BuyOrderSide, LimitOrderType, 0.0099, 1000)
+	if err == nil {
+		t.Error("Test Failed - Apply() expected error below minimum notional")
+	}
+	_, _, err = rules.Apply(BuyOrderSide, LimitOrderType, 0.01, 1000)
+	if err != nil {
+		t.Error("Test Failed - Apply() order at minimum notional rejected", err)
+	}
+	_, _, err = rules.Apply(BuyOrderSide, LimitOrderType, 1, 0.001)
+	if err == nil {
+		t.Error("Test Failed - Apply() expected error when buy price rounds to zero")
+	}
+	_, _, err = rules.Apply(BuyOrderSide, LimitOrderType, 1, 0)
+	if err == nil {
+		t.Error("Test Failed - Apply() expected error for limit order without price")
+	}
+
+	rules.MinAmount = 0.05
+	rules.MinNotional = 0
+	_, _, err = rules.Apply(BuyOrderSide, LimitOrderType, 0.0499, 1000)
+	if err == nil {
+		t.Error("Test Failed - Apply() expected error below minimum amount")
+	}
+}
+
+func TestTradingRulesOverride(t *testing.T) {
+	rules := TradingRules{PriceStep: 0.01, AmountStep: 0.001, MinNotional: 10}
+	rules = rules.Override(TradingRules{
+		AmountStep:     0.01,
+		PriceRounding:  RoundNearest,
+		AmountRounding: RoundUp,
+	})
+	if rules.PriceStep != 0.01 || rules.AmountStep != 0.01 || rules.MinNotional != 10 {
+		t.Errorf("Test Failed - Override() unexpected rules %+v", rules)
+	}
+
+	amount, price, err := rules.Apply(BuyOrderSide, LimitOrderType, 0.123, 100.006)
+	if err != nil {
+		t.Fatal("Test Failed - Apply() error", err)
+	}
+	if amount != 0.13 || price != 100.01 {
+		t.Errorf("Test Failed - Apply() overridden got %v @ %v", amount, price)
+	}
+}
@@ -0,0 +1,39 @@
+package exchange
+
+import (
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// SettlementType defines how a futures contract was settled
+type SettlementType string
+
+// Futures settlement types
+const (
+	// Settled contracts realise their profit and loss at the settlement price,
+	// positions remain open with the settlement price as their entry price
+	Settled SettlementType = "SETTLEMENT"
+	// Delivered contracts have expired, closing every position at the
+	// delivery price
+	Delivered SettlementType = "DELIVERY"
+)
+
+// Settlement holds a futures contract settlement or delivery
+type Settlement struct {
+	Exchange string         `json:"exchange"`
+	Pair     currency.Pair  `json:"pair"`
+	Contract string         `json:"contract"`
+	Type     SettlementType `json:"type"`
+	Price    float64        `json:"price"`
+	Time     time.Time      `json:"time"`
+}
+
+// SettlementProvider is implemented by exchanges which report the settlement
+// and delivery history of their futures contracts
+type SettlementProvider interface {
+	// GetSettlementHistory returns the settlements and deliveries of the
+	// futures contracts on the underlying pair since start, ordered from
+	// oldest to newest
+	GetSettlementHistory(p currency.Pair, start time.Time) ([]Settlement, error)
+}
@@ -0,0 +1,717 @@
+// Package spread works two leg spread orders such as futures calendar spreads
+// or perpetual against dated futures. The lean leg rests as a limit order
+// priced off the hedge leg at the target spread and every lean fill is hedged
+// at market on the hedge leg. Unhedged exposure is limited: the lean order is
+// withdrawn while the leg imbalance exceeds its limit, and unhedged lean fills
+// are unwound at market when the imbalance persists. Spreads are persisted so
+// working spreads resume management after a restart
+package spread
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default spread settings
+const (
+	DefaultCheckInterval = time.Second * 2
+	// DefaultImbalanceTimeout is how long lean fills may remain unhedged
+	// before they are unwound
+	DefaultImbalanceTimeout = time.Minute
+	// DefaultRequoteBps is how far in basis points the lean order's target
+	// price may move before the order is replaced
+	DefaultRequoteBps = 5
+
+	// dust is the amount below which legs are considered balanced
+	dust = 1e-9
+)
+
+// State defines the stage of a spread
+type State string
+
+// Spread states
+const (
+	// Working spreads rest a lean order and hedge its fills
+	Working State = "WORKING"
+	// Paused spreads have withdrawn their lean order until the imbalance
+	// is back within its limit
+	Paused State = "PAUSED"
+	// Closing spreads have withdrawn their lean order for good and hedge the
+	// remaining imbalance before they are cancelled
+	Closing   State = "CLOSING"
+	Completed State = "COMPLETED"
+	Cancelled State = "CANCELLED"
+	// Unwound spreads closed their unhedged lean fills at market after the
+	// imbalance persisted
+	Unwound State = "UNWOUND"
+)
+
+// Event types emitted by the manager
+const (
+	Done       = "SPREAD_COMPLETED"
+	Imbalanced = "SPREAD_IMBALANCED"
+	Unwinding  = "SPREAD_UNWOUND"
+	Failed     = "SPREAD_FAILED"
+)
+
+var (
+	errNoExchanges       = errors.New("no exchange lookup supplied")
+	errNoPriceFunc       = errors.New("no price function supplied")
+	errExchangeNotLoaded = errors.New("exchange not loaded")
+	errSpreadNotFound    = errors.New("spread not found")
+	errSpreadDone        = errors.New("spread already closed")
+	errNotAcknowledged   = errors.New("order not acknowledged")
+	errLegNotSet         = errors.New("spread legs require an exchange and pair")
+	errSameLeg           = errors.New("spread legs must differ")
+	errSpreadSide        = errors.New("spread side must be buy or sell")
+	errSpreadAmount      = errors.New("spread amount must be positive")
+	errSpreadRatio       = errors.New("spread hedge ratio cannot be negative")
+	errSpreadImbalance   = errors.New("spread imbalance limit and timeout cannot be negative")
+)
+
+// Leg defines the instrument one side of a spread trades
+type Leg struct {
+	Exchange  string        `json:"exchange"`
+	Pair      currency.Pair `json:"pair"`
+	AssetType string        `json:"assetType,omitempty"`
+}
+
+func (l *Leg) String() string {
+	return l.Exchange + " " + l.Pair.String()
+}
+
+// Order defines a two leg spread. The lean leg is bought or sold by side at
+// the hedge leg's price plus the spread and the hedge leg traded the opposite
+// way, so a calendar spread buying the far contract 20 over the near contract
+// leans on the far contract with a spread of 20
+type Order struct {
+	Lean   Leg                `json:"lean"`
+	Hedge  Leg                `json:"hedge"`
+	Side   exchange.OrderSide `json:"side"`
+	Amount float64            `json:"amount"`
+	// Ratio is the hedge amount traded per lean amount, defaults to 1
+	Ratio  float64 `json:"ratio"`
+	Spread float64 `json:"spread"`
+	// MaxImbalance is the unhedged hedge leg amount at which the lean order
+	// is withdrawn, defaults to the spread's full hedge amount
+	MaxImbalance float64 `json:"maxImbalance"`
+	// ImbalanceTimeout is how long the legs may stay imbalanced before
+	// unhedged lean fills are unwound
+	ImbalanceTimeout time.Duration `json:"imbalanceTimeout"`
+	// RequoteBps is the move in the lean order's target price in basis
+	// points at which it is replaced
+	RequoteBps float64 `json:"requoteBps"`
+}
+
+// Validate checks the order is complete, defaulting unset limits
+func (o *Order) Validate() error {
+	if o.Lean.Exchange == "" || o.Lean.Pair.IsEmpty() || o.Hedge.Exchange == "" || o.Hedge.Pair.IsEmpty() {
+		return errLegNotSet
+	}
+	if strings.EqualFold(o.Lean.Exchange, o.Hedge.Exchange) && o.Lean.Pair.Equal(o.Hedge.Pair) &&
+		o.Lean.AssetType == o.Hedge.AssetType {
+		return errSameLeg
+	}
+	if o.Side != exchange.BuyOrderSide && o.Side != exchange.SellOrderSide {
+		return errSpreadSide
+	}
+	if o.Amount <= 0 {
+		return errSpreadAmount
+	}
+	if o.Ratio < 0 {
+		return errSpreadRatio
+	}
+	if o.MaxImbalance < 0 || o.ImbalanceTimeout < 0 {
+		return errSpreadImbalance
+	}
+	if o.Ratio == 0 {
+		o.Ratio = 1
+	}
+	if o.MaxImbalance == 0 {
+		o.MaxImbalance = o.Amount * o.Ratio
+	}
+	if o.ImbalanceTimeout == 0 {
+		o.ImbalanceTimeout = DefaultImbalanceTimeout
+	}
+	if o.RequoteBps <= 0 {
+		o.RequoteBps = DefaultRequoteBps
+	}
+	return nil
+}
+
+// HedgeSide returns the side the hedge leg is traded
+func (o *Order) HedgeSide() exchange.OrderSide {
+	if o.Side == exchange.SellOrderSide {
+		return exchange.BuyOrderSide
+	}
+	return exchange.SellOrderSide
+}
+
+// Spread holds a spread order and the state of its legs
+type Spread struct {
+	ID    string `json:"id"`
+	Order Order  `json:"order"`
+	State State  `json:"state"`
+	// LeanID is the resting lean order, empty while none rests
+	LeanID    string  `json:"leanID,omitempty"`
+	LeanPrice float64 `json:"leanPrice,omitempty"`
+	// LeanDone is the amount filled by lean orders no longer resting and
+	// LeanFilled includes the resting order's fills
+	LeanDone   float64 `json:"leanDone"`
+	LeanFilled float64 `json:"leanFilled"`
+	// HedgeID is the hedge order awaiting its fill
+	HedgeID     string  `json:"hedgeID,omitempty"`
+	HedgeAmount float64 `json:"hedgeAmount,omitempty"`
+	Hedged      float64 `json:"hedged"`
+	Hedges      int     `json:"hedges"`
+	// ImbalanceSince is when the legs became imbalanced
+	ImbalanceSince time.Time `json:"imbalanceSince,omitempty"`
+	Unwound        float64   `json:"unwound,omitempty"`
+	Error          string    `json:"error,omitempty"`
+	Created        time.Time `json:"created"`
+	Updated        time.Time `json:"updated"`
+}
+
+// Open returns whether the spread is still managed
+func (s *Spread) Open() bool {
+	return s.State == Working || s.State == Paused || s.State == Closing
+}
+
+// Imbalance returns the hedge leg amount owed for lean fills not yet hedged
+func (s *Spread) Imbalance() float64 {
+	return s.LeanFilled*s.Order.Ratio - s.Hedged
+}
+
+// Event defines a spread completing, imbalancing, unwinding or failing
+type Event struct {
+	Type   string
+	Spread Spread
+	Detail string
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	return fmt.Sprintf("spread %s %s %s/%s %s: %s", e.Spread.ID, e.Spread.Order.Side,
+		e.Spread.Order.Lean.String(), e.Spread.Order.Hedge.String(), e.Type, e.Detail)
+}
+
+// ExchangeFunc returns a loaded exchange by name, nil when not loaded
+type ExchangeFunc func(name string) exchange.IBotExchange
+
+// PriceFunc returns the last price of an instrument on an exchange
+type PriceFunc func(exchangeName string, p currency.Pair, assetType string) (float64, error)
+
+// Manager submits spread orders and works them until they close
+type Manager struct {
+	path      string
+	exchanges ExchangeFunc
+	prices    PriceFunc
+	onEvent   func(Event)
+	spreads   map[string]*Spread
+	now       func() time.Time
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+	mtx       sync.Mutex
+	// checkMtx serialises the order changes of checks and cancellations
+	checkMtx sync.Mutex
+}
+
+// New returns a spread manager persisting spreads to path. A missing file
+// starts with no spreads, an empty path keeps spreads in memory only
+func New(path string, exchanges ExchangeFunc, prices PriceFunc, onEvent func(Event)) (*Manager, error) {
+	if exchanges == nil {
+		return nil, errNoExchanges
+	}
+	if prices == nil {
+		return nil, errNoPriceFunc
+	}
+	m := &Manager{
+		path:      path,
+		exchanges: exchanges,
+		prices:    prices,
+		onEvent:   onEvent,
+		spreads:   make(map[string]*Spread),
+		now:       time.Now,
+	}
+	if path == "" {
+		return m, nil
+	}
+
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return m, nil
+		}
+		return nil, err
+	}
+	var spreads []*Spread
+	err = json.Unmarshal(data, &spreads)
+	if err != nil {
+		return nil, err
+	}
+	for i := range spreads {
+		m.spreads[spreads[i].ID] = spreads[i]
+	}
+	return m, nil
+}
+
+// save writes the spreads to the manager's file, the caller must hold the
+// lock
+func (m *Manager) save() error {
+	if m.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(m.list(), "", " ")
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(m.path, data)
+}
+
+func (m *Manager) list() []Spread {
+	spreads := make([]Spread, 0, len(m.spreads))
+	for _, s := range m.spreads {
+		spreads = append(spreads, *s)
+	}
+	sort.Slice(spreads, func(i, j int) bool {
+		return spreads[i].Created.Before(spreads[j].Created)
+	})
+	return spreads
+}
+
+// store records the spread and persists the manager's spreads
+func (m *Manager) store(s *Spread) {
+	s.Updated = m.now()
+	stored := *s
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	m.spreads[s.ID] = &stored
+	if err := m.save(); err != nil {
+		log.Errorf("Spread manager failed to save spreads: %s", err)
+	}
+}
+
+// legs returns the exchanges of a spread's legs
+func (m *Manager) legs(o *Order) (lean, hedge exchange.IBotExchange, err error) {
+	lean = m.exchanges(o.Lean.Exchange)
+	if lean == nil {
+		return nil, nil, fmt.Errorf("%s %v", o.Lean.Exchange, errExchangeNotLoaded)
+	}
+	hedge = m.exchanges(o.Hedge.Exchange)
+	if hedge == nil {
+		return nil, nil, fmt.Errorf("%s %v", o.Hedge.Exchange, errExchangeNotLoaded)
+	}
+	return lean, hedge, nil
+}
+
+// Submit starts working a spread, its lean order is placed on the next check
+func (m *Manager) Submit(order *Order) (Spread, error) {
+	o := *order
+	err := o.Validate()
+	if err != nil {
+		return Spread{}, err
+	}
+	if _, _, err = m.legs(&o); err != nil {
+		return Spread{}, err
+	}
+	id, err := common.GetRandomSalt(nil, 8)
+	if err != nil {
+		return Spread{}, err
+	}
+
+	s := Spread{
+		ID:      common.HexEncodeToString(id),
+		Order:   o,
+		State:   Working,
+		Created: m.now(),
+	}
+	m.store(&s)
+	log.Debugf("Spread %s submitted %s %v %s against %s at %v ratio %v",
+		s.ID, o.Side, o.Amount, o.Lean.String(), o.Hedge.String(), o.Spread, o.Ratio)
+	return s, nil
+}
+
+// Cancel withdraws the spread's lean order. Lean fills not yet hedged are
+// hedged before the spread is cancelled
+func (m *Manager) Cancel(id string) (Spread, error) {
+	m.checkMtx.Lock()
+	defer m.checkMtx.Unlock()
+
+	s, err := m.Get(id)
+	if err != nil {
+		return Spread{}, err
+	}
+	if !s.Open() {
+		return s, fmt.Errorf("%s %v", id, errSpreadDone)
+	}
+	lean, _, err := m.legs(&s.Order)
+	if err != nil {
+		return s, err
+	}
+	if err = m.withdrawLean(lean, &s); err != nil {
+		s.Error = "cancel lean: " + err.Error()
+		m.store(&s)
+		return s, fmt.Errorf("spread %s failed to cancel lean order: %s", id, err)
+	}
+	s.State = Closing
+	if s.Imbalance() <= dust && s.HedgeID == "" {
+		s.State = Cancelled
+	}
+	s.Error = ""
+	m.store(&s)
+	return s, nil
+}
+
+// Get returns a spread by ID
+func (m *Manager) Get(id string) (Spread, error) {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	s, ok := m.spreads[id]
+	if !ok {
+		return Spread{}, fmt.Errorf("%s %v", id, errSpreadNotFound)
+	}
+	return *s, nil
+}
+
+// List returns every spread, oldest first
+func (m *Manager) List() []Spread {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	return m.list()
+}
+
+// Check advances every open spread
+func (m *Manager) Check() {
+	m.checkMtx.Lock()
+	defer m.checkMtx.Unlock()
+
+	for _, s := range m.List() {
+		if !s.Open() {
+			continue
+		}
+		lean, hedge, err := m.legs(&s.Order)
+		if err != nil {
+			log.Debugf("Spread manager skipping spread %s: %s", s.ID, err)
+			continue
+		}
+		prev := s
+		events := m.check(lean, hedge, &s)
+		if s != prev {
+			m.store(&s)
+		}
+		for i := range events {
+			events[i].Spread = s
+			log.Debugln(events[i].String())
+			if m.onEvent != nil {
+				m.onEvent(events[i])
+			}
+		}
+	}
+}
+
+// check advances a spread, returning the events raised
+func (m *Manager) check(lean, hedge exchange.IBotExchange, s *Spread) []Event {
+	if err := m.updateLean(lean, s); err != nil {
+		s.Error = "lean: " + err.Error()
+		return nil
+	}
+	if err := m.updateHedge(hedge, s); err != nil {
+		s.Error = "hedge: " + err.Error()
+		return nil
+	}
+	// Failures retried every check are only reported when they change
+	prevErr := s.Error
+	s.Error = ""
+	var events []Event
+	fail := func(detail string) {
+		s.Error = detail
+		if detail != prevErr {
+			events = append(events, Event{Type: Failed, Detail: detail})
+		}
+	}
+
+	imbalance := s.Imbalance()
+	if imbalance > dust {
+		if s.ImbalanceSince.IsZero() {
+			s.ImbalanceSince = m.now()
+		}
+		if s.HedgeID == "" {
+			if err := m.placeHedge(hedge, s, imbalance); err != nil {
+				fail("hedge: " + err.Error())
+			}
+		}
+	} else {
+		s.ImbalanceSince = time.Time{}
+	}
+
+	if !s.ImbalanceSince.IsZero() && m.now().Sub(s.ImbalanceSince) > s.Order.ImbalanceTimeout {
+		unwound, err := m.unwind(lean, hedge, s)
+		if err != nil {
+			// Retried on the next check
+			fail(err.Error())
+			return events
+		}
+		s.Unwound = unwound
+		s.State = Unwound
+		return append(events, Event{Type: Unwinding, Detail: fmt.Sprintf(
+			"legs imbalanced for over %s, unwound %v lean at market", s.Order.ImbalanceTimeout, unwound)})
+	}
+
+	switch {
+	case s.State == Working && imbalance > s.Order.MaxImbalance:
+		if err := m.withdrawLean(lean, s); err != nil {
+			fail("withdraw lean: " + err.Error())
+			return events
+		}
+		s.State = Paused
+		return append(events, Event{Type: Imbalanced, Detail: fmt.Sprintf(
+			"%v unhedged exceeds limit %v, lean order withdrawn", imbalance, s.Order.MaxImbalance)})
+	case s.State == Paused && imbalance <= s.Order.MaxImbalance:
+		s.State = Working
+	}
+
+	balanced := imbalance <= dust && s.HedgeID == ""
+	switch s.State {
+	case Working:
+		if s.Order.Amount-s.LeanFilled <= dust && s.LeanID == "" {
+			if balanced {
+				s.State = Completed
+				events = append(events, Event{Type: Done, Detail: fmt.Sprintf(
+					"%v lean filled and %v hedged in %d orders", s.LeanFilled, s.Hedged, s.Hedges)})
+			}
+			return events
+		}
+		if err := m.quoteLean(lean, s); err != nil {
+			fail("lean: " + err.Error())
+		}
+	case Closing:
+		if balanced {
+			s.State = Cancelled
+		}
+	}
+	return events
+}
+
+// updateLean records the resting lean order's fills
+func (m *Manager) updateLean(lean exchange.IBotExchange, s *Spread) error {
+	if s.LeanID == "" {
+		s.LeanFilled = s.LeanDone
+		return nil
+	}
+	o, err := lean.GetOrderInfo(s.LeanID)
+	if err != nil {
+		return err
+	}
+	executed := o.ExecutedAmount
+	if o.Filled() && executed == 0 {
+		executed = s.Order.Amount - s.LeanDone
+	}
+	s.LeanFilled = s.LeanDone + executed
+	if o.Closed() {
+		s.LeanDone = s.LeanFilled
+		s.LeanID = ""
+		if !o.Filled() && s.State == Working {
+			// A lean order cancelled or rejected outside the manager ends
+			// the spread once its fills are hedged
+			s.State = Closing
+		}
+	}
+	return nil
+}
+
+// updateHedge records the pending hedge order's fills once it completes
+func (m *Manager) updateHedge(hedge exchange.IBotExchange, s *Spread) error {
+	if s.HedgeID == "" {
+		return nil
+	}
+	o, err := hedge.GetOrderInfo(s.HedgeID)
+	if err != nil {
+		return err
+	}
+	if !o.Closed() {
+		return nil
+	}
+	executed := o.ExecutedAmount
+	if o.Filled() && executed == 0 {
+		executed = s.HedgeAmount
+	}
+	s.Hedged += executed
+	s.HedgeID = ""
+	s.HedgeAmount = 0
+	return nil
+}
+
+// placeHedge hedges the imbalance at market
+func (m *Manager) placeHedge(hedge exchange.IBotExchange, s *Spread, amount float64) error {
+	s.Hedges++
+	resp, err := hedge.SubmitOrder(s.Order.Hedge.Pair, s.Order.HedgeSide(), exchange.MarketOrderType,
+		amount, 0, s.ID+"-h"+strconv.Itoa(s.Hedges))
+	if err == nil && resp.OrderID == "" {
+		err = errNotAcknowledged
+	}
+	if err != nil {
+		return err
+	}
+	s.HedgeID = resp.OrderID
+	s.HedgeAmount = amount
+	return nil
+}
+
+// quoteLean places the lean order at the hedge leg's price plus the spread,
+// replacing a resting order whose target price has moved
+func (m *Manager) quoteLean(lean exchange.IBotExchange, s *Spread) error {
+	ref, err := m.prices(s.Order.Hedge.Exchange, s.Order.Hedge.Pair, s.Order.Hedge.AssetType)
+	if err != nil {
+		return err
+	}
+	target := ref + s.Order.Spread
+	if target <= 0 {
+		return fmt.Errorf("target price %v not positive", target)
+	}
+	if s.LeanID != "" {
+		if math.Abs(target-s.LeanPrice) <= s.LeanPrice*s.Order.RequoteBps/10000 {
+			return nil
+		}
+		if err = m.withdrawLean(lean, s); err != nil {
+			return err
+		}
+	}
+	remaining := s.Order.Amount - s.LeanFilled
+	if remaining <= dust {
+		return nil
+	}
+	resp, err := lean.SubmitOrder(s.Order.Lean.Pair, s.Order.Side, exchange.LimitOrderType,
+		remaining, target, s.ID+"-l")
+	if err == nil && resp.OrderID == "" {
+		err = errNotAcknowledged
+	}
+	if err != nil {
+		return err
+	}
+	s.LeanID = resp.OrderID
+	s.LeanPrice = target
+	return nil
+}
+
+// withdrawLean cancels the resting lean order and records its fills
+func (m *Manager) withdrawLean(lean exchange.IBotExchange, s *Spread) error {
+	if s.LeanID == "" {
+		return nil
+	}
+	err := lean.CancelOrder(&exchange.OrderCancellation{
+		OrderID:      s.LeanID,
+		CurrencyPair: s.Order.Lean.Pair,
+		Side:         s.Order.Side,
+	})
+	if err != nil {
+		return err
+	}
+	// Fills racing the cancellation must be hedged
+	if o, err := lean.GetOrderInfo(s.LeanID); err == nil {
+		executed := o.ExecutedAmount
+		if o.Filled() && executed == 0 {
+			executed = s.Order.Amount - s.LeanDone
+		}
+		s.LeanFilled = s.LeanDone + executed
+	}
+	s.LeanDone = s.LeanFilled
+	s.LeanID = ""
+	return nil
+}
+
+// unwind withdraws the lean order and the pending hedge, closing the lean
+// fills left unhedged at market. It returns the lean amount unwound
+func (m *Manager) unwind(lean, hedge exchange.IBotExchange, s *Spread) (float64, error) {
+	if err := m.withdrawLean(lean, s); err != nil {
+		return 0, fmt.Errorf("unwind lean: %s", err)
+	}
+	if s.HedgeID != "" {
+		err := hedge.CancelOrder(&exchange.OrderCancellation{
+			OrderID:      s.HedgeID,
+			CurrencyPair: s.Order.Hedge.Pair,
+			Side:         s.Order.HedgeSide(),
+		})
+		if err != nil {
+			return 0, fmt.Errorf("unwind hedge: %s", err)
+		}
+		if o, err := hedge.GetOrderInfo(s.HedgeID); err == nil {
+			s.Hedged += o.ExecutedAmount
+		}
+		s.HedgeID = ""
+		s.HedgeAmount = 0
+	}
+
+	unhedged := s.Imbalance() / s.Order.Ratio
+	if unhedged <= dust {
+		return 0, nil
+	}
+	exitSide := exchange.BuyOrderSide
+	if s.Order.Side == exchange.BuyOrderSide {
+		exitSide = exchange.SellOrderSide
+	}
+	resp, err := lean.SubmitOrder(s.Order.Lean.Pair, exitSide, exchange.MarketOrderType,
+		unhedged, 0, s.ID+"-u")
+	if err == nil && resp.OrderID == "" {
+		err = errNotAcknowledged
+	}
+	if err != nil {
+		return 0, fmt.Errorf("unwind: %s", err)
+	}
+	return unhedged, nil
+}
+
+// Start checks the open spreads at the interval until stopped
+func (m *Manager) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			m.Check()
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the manager
+func (m *Manager) Stop() {
+	m.mtx.Lock()
+	if m.shutdown == nil {
+		m.mtx.Unlock()
+		return
+	}
+	close(m.shutdown)
+	m.shutdown = nil
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
@@ -0,0 +1,273 @@
+package spread
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	name      string
+	orders    map[string]*exchange.OrderDetail
+	cancelled []string
+	fail      bool
+}
+
+func newTestExchange(name string) *testExchange {
+	return &testExchange{name: name, orders: make(map[string]*exchange.OrderDetail)}
+}
+
+func (t *testExchange) GetName() string { return t.name }
+
+func (t *testExchange) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	if t.fail {
+		return exchange.SubmitOrderResponse{}, errors.New("insufficient margin")
+	}
+	id := fmt.Sprintf("%d", len(t.orders)+1)
+	t.orders[id] = &exchange.OrderDetail{
+		ID:           id,
+		CurrencyPair: p,
+		OrderSide:    side,
+		OrderType:    orderType,
+		Price:        price,
+		Amount:       amount,
+	}
+	return exchange.SubmitOrderResponse{OrderID: id, IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	o, ok := t.orders[orderID]
+	if !ok {
+		return exchange.OrderDetail{}, fmt.Errorf("order %s not found", orderID)
+	}
+	return *o, nil
+}
+
+func (t *testExchange) CancelOrder(order *exchange.OrderCancellation) error {
+	t.cancelled = append(t.cancelled, order.OrderID)
+	t.orders[order.OrderID].Status = string(exchange.CancelledOrderStatus)
+	return nil
+}
+
+func newTestManager(t *testing.T, lean, hedge *testExchange, path string, price *float64, events *[]Event) *Manager {
+	m, err := New(path,
+		func(name string) exchange.IBotExchange {
+			switch name {
+			case lean.name:
+				return lean
+			case hedge.name:
+				return hedge
+			}
+			return nil
+		},
+		func(string, currency.Pair, string) (float64, error) { return *price, nil },
+		func(e Event) { *events = append(*events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func testOrder() *Order {
+	return &Order{
+		Lean:   Leg{Exchange: "okex", Pair: currency.NewPairWithDelimiter("BTC", "USD", "_"), AssetType: "quarter"},
+		Hedge:  Leg{Exchange: "bitmex", Pair: currency.NewPairWithDelimiter("XBT", "USD", ""), AssetType: "perpetual"},
+		Side:   exchange.BuyOrderSide,
+		Amount: 2,
+		Spread: 20,
+	}
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New("", nil, nil, nil); err != errNoExchanges {
+		t.Error("Test Failed - New() expected no exchanges error", err)
+	}
+	exchanges := func(string) exchange.IBotExchange { return nil }
+	if _, err := New("", exchanges, nil, nil); err != errNoPriceFunc {
+		t.Error("Test Failed - New() expected no price func error", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	o := testOrder()
+	if err := o.Validate(); err != nil {
+		t.Fatal(err)
+	}
+	if o.Ratio != 1 || o.MaxImbalance != 2 || o.ImbalanceTimeout != DefaultImbalanceTimeout ||
+		o.RequoteBps != DefaultRequoteBps {
+		t.Errorf("Test Failed - Validate() expected defaults %+v", o)
+	}
+	if o.HedgeSide() != exchange.SellOrderSide {
+		t.Error("Test Failed - HedgeSide() expected sell hedge of buy lean")
+	}
+
+	o = testOrder()
+	o.Hedge = o.Lean
+	if err := o.Validate(); err != errSameLeg {
+		t.Error("Test Failed - Validate() expected same leg error", err)
+	}
+	o = testOrder()
+	o.Amount = 0
+	if err := o.Validate(); err != errSpreadAmount {
+		t.Error("Test Failed - Validate() expected amount error", err)
+	}
+	o = testOrder()
+	o.Side = exchange.AnyOrderSide
+	if err := o.Validate(); err != errSpreadSide {
+		t.Error("Test Failed - Validate() expected side error", err)
+	}
+}
+
+func TestSpread(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spread")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spreads.json")
+
+	lean, hedge := newTestExchange("okex"), newTestExchange("bitmex")
+	price := 100.0
+	var events []Event
+	m := newTestManager(t, lean, hedge, path, &price, &events)
+
+	s, err := m.Submit(testOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Check()
+	o := lean.orders["1"]
+	if o == nil || o.OrderType != exchange.LimitOrderType || o.OrderSide != exchange.BuyOrderSide ||
+		o.Price != 120 || o.Amount != 2 {
+		t.Fatalf("Test Failed - Check() expected lean limit at hedge price plus spread %+v", o)
+	}
+
+	// Moves within the requote threshold leave the lean order resting
+	price = 100.02
+	m.Check()
+	if len(lean.orders) != 1 {
+		t.Fatal("Test Failed - Check() requoted within threshold")
+	}
+	price = 101
+	m.Check()
+	s, _ = m.Get(s.ID)
+	if s.LeanID != "2" || s.LeanPrice != 121 || len(lean.cancelled) != 1 {
+		t.Fatalf("Test Failed - Check() expected requote %+v", s)
+	}
+
+	lean.orders["2"].ExecutedAmount = 1
+	m.Check()
+	s, _ = m.Get(s.ID)
+	h := hedge.orders["1"]
+	if h == nil || h.OrderType != exchange.MarketOrderType || h.OrderSide != exchange.SellOrderSide || h.Amount != 1 {
+		t.Fatalf("Test Failed - Check() expected market hedge of lean fill %+v", h)
+	}
+	if s.State != Working || s.Imbalance() != 1 || s.HedgeID != "1" {
+		t.Fatalf("Test Failed - Check() unexpected spread %+v", s)
+	}
+
+	// Managed spreads resume from the persisted file
+	m = newTestManager(t, lean, hedge, path, &price, &events)
+	h.ExecutedAmount = 1
+	lean.orders["2"].ExecutedAmount = 2
+	m.Check()
+	s, _ = m.Get(s.ID)
+	if s.Hedged != 1 || s.LeanFilled != 2 || s.LeanID != "" || s.HedgeID != "2" || s.State != Working {
+		t.Fatalf("Test Failed - Check() expected second hedge %+v", s)
+	}
+	hedge.orders["2"].ExecutedAmount = 1
+	m.Check()
+	s, _ = m.Get(s.ID)
+	if s.State != Completed || s.Hedged != 2 || s.Hedges != 2 {
+		t.Errorf("Test Failed - Check() expected completed spread %+v", s)
+	}
+	if len(events) != 1 || events[0].Type != Done {
+		t.Errorf("Test Failed - Check() expected completed event %+v", events)
+	}
+	if _, err = m.Cancel(s.ID); err == nil {
+		t.Error("Test Failed - Cancel() expected completed spread error")
+	}
+}
+
+func TestUnwind(t *testing.T) {
+	lean, hedge := newTestExchange("okex"), newTestExchange("bitmex")
+	price := 100.0
+	var events []Event
+	m := newTestManager(t, lean, hedge, "", &price, &events)
+	now := time.Now()
+	m.now = func() time.Time { return now }
+
+	s, err := m.Submit(testOrder())
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Check()
+	hedge.fail = true
+	lean.orders["1"].ExecutedAmount = 1.5
+	m.Check()
+	s, _ = m.Get(s.ID)
+	if s.Error == "" || s.ImbalanceSince.IsZero() || len(events) != 1 || events[0].Type != Failed {
+		t.Fatalf("Test Failed - Check() expected failed hedge %+v %+v", s, events)
+	}
+
+	now = now.Add(DefaultImbalanceTimeout + time.Second)
+	m.Check()
+	s, _ = m.Get(s.ID)
+	if s.State != Unwound || s.Unwound != 1.5 || len(lean.cancelled) != 1 {
+		t.Fatalf("Test Failed - Check() expected unwound spread %+v", s)
+	}
+	u := lean.orders["2"]
+	if u == nil || u.OrderType != exchange.MarketOrderType || u.OrderSide != exchange.SellOrderSide || u.Amount != 1.5 {
+		t.Errorf("Test Failed - Check() expected market unwind of unhedged lean %+v", u)
+	}
+	if events[len(events)-1].Type != Unwinding {
+		t.Errorf("Test Failed - Check() expected unwound event %+v", events)
+	}
+}
+
+func TestPause(t *testing.T) {
+	lean, hedge := newTestExchange("okex"), newTestExchange("bitmex")
+	price := 100.0
+	var events []Event
+	m := newTestManager(t, lean, hedge, "", &price, &events)
+
+	o := testOrder()
+	o.MaxImbalance = 0.5
+	s, err := m.Submit(o)
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Check()
+	lean.orders["1"].ExecutedAmount = 1
+	m.Check()
+	s, _ = m.Get(s.ID)
+	if s.State != Paused || s.LeanID != "" || s.HedgeID != "1" || len(events) != 1 || events[0].Type != Imbalanced {
+		t.Fatalf("Test Failed - Check() expected paused spread %+v %+v", s, events)
+	}
+
+	hedge.orders["1"].ExecutedAmount = 1
+	m.Check()
+	s, _ = m.Get(s.ID)
+	if s.State != Working || s.LeanID != "2" || lean.orders["2"].Amount != 1 {
+		t.Fatalf("Test Failed - Check() expected resumed lean for remaining amount %+v", s)
+	}
+
+	s, err = m.Cancel(s.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.State != Cancelled || len(lean.cancelled) != 2 {
+		t.Errorf("Test Failed - Cancel() expected cancelled spread %+v", s)
+	}
+	if _, err = m.Cancel("missing"); err == nil {
+		t.Error("Test Failed - Cancel() expected not found error")
+	}
+}
@@ -0,0 +1,554 @@
+// Package spreadhistory records the spread between configured pairs of
+// instruments, across venues or contract types such as a perpetual swap
+// against spot, at each spread's own frequency. Samples are appended to a JSON
+// lines file and expired past the spread's retention. Percentile statistics of
+// the recorded spread are used to set the entry and exit bands of pairs and
+// basis strategies
+package spreadhistory
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default recorder settings
+const (
+	DefaultFrequency = time.Minute
+	DefaultRetention = time.Hour * 24 * 30
+)
+
+// Measures a spread is recorded in
+const (
+	// Difference records the first leg's price less the second's
+	Difference = "difference"
+	// BasisPoints records the difference in basis points of the second leg
+	BasisPoints = "bps"
+	// Ratio records the first leg's price over the second's
+	Ratio = "ratio"
+)
+
+// DefaultPercentiles are reported when none are requested
+var DefaultPercentiles = []float64{1, 5, 10, 25, 50, 75, 90, 95, 99}
+
+var (
+	errNoPriceFunc       = errors.New("no price function supplied")
+	errNoSpreads         = errors.New("no spreads configured")
+	errNoName            = errors.New("spread name not set")
+	errDuplicateName     = errors.New("spread name configured more than once")
+	errInvalidLeg        = errors.New("spread leg exchange and pair must be set")
+	errInvalidMeasure    = errors.New("spread measure must be difference, bps or ratio")
+	errInvalidDuration   = errors.New("spread frequency and retention must be positive durations")
+	errInvalidPrice      = errors.New("leg price must be positive")
+	errSpreadNotFound    = errors.New("spread not recorded")
+	errNoSamples         = errors.New("no samples recorded in window")
+	errInvalidPercentile = errors.New("percentiles must be between 0 and 100")
+)
+
+// PriceFunc returns an instrument's current price
+type PriceFunc func(exchangeName string, p currency.Pair, assetType string) (float64, error)
+
+// Leg is one instrument of a spread
+type Leg struct {
+	Exchange string `json:"exchange"`
+	Pair     string `json:"pair"`
+	// AssetType selects contracts such as perpetual or futures, spot when
+	// unset
+	AssetType string `json:"assetType,omitempty"`
+}
+
+// String returns the leg as Exchange:Pair or Exchange:Pair:AssetType
+func (l *Leg) String() string {
+	if l.AssetType == "" {
+		return l.Exchange + ":" + l.Pair
+	}
+	return l.Exchange + ":" + l.Pair + ":" + l.AssetType
+}
+
+// Spread configures the spread recorded between two instruments
+type Spread struct {
+	Name string `json:"name"`
+	A    Leg    `json:"a"`
+	B    Leg    `json:"b"`
+	// Measure is difference, bps or ratio, difference when unset
+	Measure string `json:"measure,omitempty"`
+	// Frequency and Retention are durations such as 30s and 720h
+	Frequency string `json:"frequency,omitempty"`
+	Retention string `json:"retention,omitempty"`
+}
+
+func (s *Spread) validate() error {
+	if s.Name == "" {
+		return errNoName
+	}
+	if s.A.Exchange == "" || s.A.Pair == "" || s.B.Exchange == "" || s.B.Pair == "" {
+		return fmt.Errorf("%s %v", s.Name, errInvalidLeg)
+	}
+	switch strings.ToLower(s.Measure) {
+	case "", Difference, BasisPoints, Ratio:
+	default:
+		return fmt.Errorf("%s %v", s.Name, errInvalidMeasure)
+	}
+	for _, d := range []string{s.Frequency, s.Retention} {
+		if d == "" {
+			continue
+		}
+		if v, err := time.ParseDuration(d); err != nil || v <= 0 {
+			return fmt.Errorf("%s %v: %q", s.Name, errInvalidDuration, d)
+		}
+	}
+	return nil
+}
+
+func (s *Spread) frequency() time.Duration {
+	if d, err := time.ParseDuration(s.Frequency); err == nil && d > 0 {
+		return d
+	}
+	return DefaultFrequency
+}
+
+func (s *Spread) retention() time.Duration {
+	if d, err := time.ParseDuration(s.Retention); err == nil && d > 0 {
+		return d
+	}
+	return DefaultRetention
+}
+
+// value returns the spread between the leg prices in the spread's measure
+func (s *Spread) value(a, b float64) float64 {
+	switch strings.ToLower(s.Measure) {
+	case BasisPoints:
+		return (a - b) / b * 10000
+	case Ratio:
+		return a / b
+	}
+	return a - b
+}
+
+// Config holds the spreads recorded
+type Config struct {
+	Spreads []Spread `json:"spreads"`
+}
+
+// Validate checks the spreads are fully configured with unique names
+func (c *Config) Validate() error {
+	if len(c.Spreads) == 0 {
+		return errNoSpreads
+	}
+	names := make(map[string]bool)
+	for i := range c.Spreads {
+		if err := c.Spreads[i].validate(); err != nil {
+			return err
+		}
+		k := strings.ToLower(c.Spreads[i].Name)
+		if names[k] {
+			return fmt.Errorf("%s %v", c.Spreads[i].Name, errDuplicateName)
+		}
+		names[k] = true
+	}
+	return nil
+}
+
+// LoadConfig reads and validates the spreads configured in the JSON file
+func LoadConfig(path string) (Config, error) {
+	var c Config
+	data, err := common.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	if err = json.Unmarshal(data, &c); err != nil {
+		return c, err
+	}
+	return c, c.Validate()
+}
+
+// Sample holds the leg prices and the spread between them at a time
+type Sample struct {
+	Spread string    `json:"spread"`
+	Time   time.Time `json:"time"`
+	A      float64   `json:"a"`
+	B      float64   `json:"b"`
+	Value  float64   `json:"value"`
+}
+
+// Percentile holds the spread value at or below which a percentage of samples
+// fall
+type Percentile struct {
+	Percentile float64 `json:"percentile"`
+	Value      float64 `json:"value"`
+}
+
+// Stats holds percentile statistics of a spread over a window
+type Stats struct {
+	Spread  string    `json:"spread"`
+	Measure string    `json:"measure"`
+	From    time.Time `json:"from"`
+	To      time.Time `json:"to"`
+	Count   int       `json:"count"`
+	Last    float64   `json:"last"`
+	Min     float64   `json:"min"`
+	Max     float64   `json:"max"`
+	Mean    float64   `json:"mean"`
+	StdDev  float64   `json:"stdDev"`
+	// ZScore is the last value's deviation from the mean in standard
+	// deviations, and Rank the percentage of samples at or below it
+	ZScore      float64      `json:"zScore"`
+	Rank        float64      `json:"rank"`
+	Percentiles []Percentile `json:"percentiles"`
+}
+
+// Status reports a spread's recording
+type Status struct {
+	Spread    Spread    `json:"spread"`
+	Samples   int       `json:"samples"`
+	LastValue float64   `json:"lastValue"`
+	Updated   time.Time `json:"updated"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// series holds a spread's samples, oldest first
+type series struct {
+	spread  Spread
+	samples []Sample
+	err     string
+}
+
+// Recorder samples and stores the configured spreads
+type Recorder struct {
+	path     string
+	price    PriceFunc
+	series   []*series
+	now      func() time.Time
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a recorder of the spreads appending samples to path, loading
+// the samples it already holds and dropping those past their retention. An
+// empty path keeps samples in memory only
+func New(cfg Config, path string, price PriceFunc) (*Recorder, error) {
+	if price == nil {
+		return nil, errNoPriceFunc
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	r := &Recorder{path: path, price: price, now: time.Now}
+	for i := range cfg.Spreads {
+		r.series = append(r.series, &series{spread: cfg.Spreads[i]})
+	}
+	if path == "" {
+		return r, nil
+	}
+	if err := r.load(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// load reads the stored samples and rewrites the file without the samples of
+// spreads no longer configured or past their retention
+func (r *Recorder) load() error {
+	f, err := os.Open(r.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	now := r.now()
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var s Sample
+		if err = json.Unmarshal(scanner.Bytes(), &s); err != nil {
+			f.Close()
+			return err
+		}
+		x := r.find(s.Spread)
+		if x == nil || now.Sub(s.Time) > x.spread.retention() {
+			continue
+		}
+		x.samples = append(x.samples, s)
+	}
+	f.Close()
+	if err = scanner.Err(); err != nil {
+		return err
+	}
+
+	var data []byte
+	for _, x := range r.series {
+		for i := range x.samples {
+			line, err := json.Marshal(&x.samples[i])
+			if err != nil {
+				return err
+			}
+			data = append(append(data, line...), '\n')
+		}
+	}
+	return common.WriteFile(r.path, data)
+}
+
+// find returns the series of a spread
+func (r *Recorder) find(name string) *series {
+	for _, x := range r.series {
+		if strings.EqualFold(x.spread.Name, name) {
+			return x
+		}
+	}
+	return nil
+}
+
+// Record samples a spread's leg prices and stores the spread between them
+func (r *Recorder) Record(name string) (Sample, error) {
+	r.mtx.Lock()
+	x := r.find(name)
+	r.mtx.Unlock()
+	if x == nil {
+		return Sample{}, fmt.Errorf("%s %v", name, errSpreadNotFound)
+	}
+
+	s, err := r.sample(&x.spread)
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	if err != nil {
+		x.err = err.Error()
+		return Sample{}, err
+	}
+	x.err = ""
+	if err = r.append(&s); err != nil {
+		log.Errorf("Spread history failed to save %s sample: %s", s.Spread, err)
+	}
+	x.samples = append(x.samples, s)
+	cutoff := s.Time.Add(-x.spread.retention())
+	i := 0
+	for i < len(x.samples) && x.samples[i].Time.Before(cutoff) {
+		i++
+	}
+	x.samples = x.samples[i:]
+	return s, nil
+}
+
+func (r *Recorder) sample(s *Spread) (Sample, error) {
+	a, err := r.legPrice(&s.A)
+	if err != nil {
+		return Sample{}, err
+	}
+	b, err := r.legPrice(&s.B)
+	if err != nil {
+		return Sample{}, err
+	}
+	return Sample{
+		Spread: s.Name,
+		Time:   r.now(),
+		A:      a,
+		B:      b,
+		Value:  s.value(a, b),
+	}, nil
+}
+
+func (r *Recorder) legPrice(l *Leg) (float64, error) {
+	p, err := r.price(l.Exchange, currency.NewPairFromString(l.Pair), l.AssetType)
+	if err != nil {
+		return 0, fmt.Errorf("%s: %v", l.String(), err)
+	}
+	if p <= 0 {
+		return 0, fmt.Errorf("%s: %v", l.String(), errInvalidPrice)
+	}
+	return p, nil
+}
+
+// append appends a sample to the file, the caller must hold the lock
+func (r *Recorder) append(s *Sample) error {
+	if r.path == "" {
+		return nil
+	}
+	data, err := json.Marshal(s)
+	if err != nil {
+		return err
+	}
+	file, err := os.OpenFile(r.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(append(data, '\n'))
+	if cerr := file.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// GetSamples returns a spread's samples within the window before now, every
+// sample retained when the window is zero
+func (r *Recorder) GetSamples(name string, window time.Duration) ([]Sample, error) {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	x := r.find(name)
+	if x == nil {
+		return nil, fmt.Errorf("%s %v", name, errSpreadNotFound)
+	}
+	if window <= 0 {
+		return append([]Sample(nil), x.samples...), nil
+	}
+	cutoff := r.now().Add(-window)
+	i := sort.Search(len(x.samples), func(i int) bool {
+		return !x.samples[i].Time.Before(cutoff)
+	})
+	return append([]Sample(nil), x.samples[i:]...), nil
+}
+
+// GetStats returns a spread's statistics within the window before now, zero
+// covers every sample retained. The default percentiles are reported when none
+// are supplied
+func (r *Recorder) GetStats(name string, window time.Duration, percentiles []float64) (Stats, error) {
+	for _, p := range percentiles {
+		if p < 0 || p > 100 {
+			return Stats{}, errInvalidPercentile
+		}
+	}
+	if len(percentiles) == 0 {
+		percentiles = DefaultPercentiles
+	}
+	samples, err := r.GetSamples(name, window)
+	if err != nil {
+		return Stats{}, err
+	}
+	if len(samples) == 0 {
+		return Stats{}, fmt.Errorf("%s %v", name, errNoSamples)
+	}
+
+	r.mtx.Lock()
+	x := r.find(name)
+	measure := strings.ToLower(x.spread.Measure)
+	r.mtx.Unlock()
+	if measure == "" {
+		measure = Difference
+	}
+
+	values := make([]float64, len(samples))
+	var total float64
+	for i := range samples {
+		values[i] = samples[i].Value
+		total += values[i]
+	}
+	last := values[len(values)-1]
+	mean := total / float64(len(values))
+	var variance float64
+	for _, v := range values {
+		variance += (v - mean) * (v - mean)
+	}
+	stdDev := math.Sqrt(variance / float64(len(values)))
+	sort.Float64s(values)
+
+	s := Stats{
+		Spread:  x.spread.Name,
+		Measure: measure,
+		From:    samples[0].Time,
+		To:      samples[len(samples)-1].Time,
+		Count:   len(values),
+		Last:    last,
+		Min:     values[0],
+		Max:     values[len(values)-1],
+		Mean:    mean,
+		StdDev:  stdDev,
+	}
+	if stdDev > 0 {
+		s.ZScore = (last - mean) / stdDev
+	}
+	below := sort.Search(len(values), func(i int) bool { return values[i] > last })
+	s.Rank = float64(below) / float64(len(values)) * 100
+	for _, p := range percentiles {
+		s.Percentiles = append(s.Percentiles, Percentile{
+			Percentile: p,
+			Value:      percentile(values, p),
+		})
+	}
+	return s, nil
+}
+
+// percentile returns the nearest rank percentile of sorted values
+func percentile(sorted []float64, p float64) float64 {
+	rank := int(math.Ceil(p / 100 * float64(len(sorted))))
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
+
+// GetStatus returns the recording status of every spread
+func (r *Recorder) GetStatus() []Status {
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	resp := make([]Status, 0, len(r.series))
+	for _, x := range r.series {
+		s := Status{
+			Spread:  x.spread,
+			Samples: len(x.samples),
+			Error:   x.err,
+		}
+		if n := len(x.samples); n > 0 {
+			s.LastValue = x.samples[n-1].Value
+			s.Updated = x.samples[n-1].Time
+		}
+		resp = append(resp, s)
+	}
+	return resp
+}
+
+// Start records each spread at its frequency until stopped
+func (r *Recorder) Start() {
+	r.mtx.Lock()
+	if r.shutdown != nil {
+		r.mtx.Unlock()
+		return
+	}
+	r.shutdown = make(chan struct{})
+	shutdown := r.shutdown
+	r.mtx.Unlock()
+
+	for _, x := range r.series {
+		r.wg.Add(1)
+		go func(name string, frequency time.Duration) {
+			defer r.wg.Done()
+			tick := time.NewTicker(frequency)
+			defer tick.Stop()
+			for {
+				select {
+				case <-shutdown:
+					return
+				case <-tick.C:
+					if _, err := r.Record(name); err != nil {
+						log.Debugf("Spread history failed to record %s: %s", name, err)
+					}
+				}
+			}
+		}(x.spread.Name, x.spread.frequency())
+	}
+}
+
+// Stop stops recording
+func (r *Recorder) Stop() {
+	r.mtx.Lock()
+	if r.shutdown == nil {
+		r.mtx.Unlock()
+		return
+	}
+	close(r.shutdown)
+	r.shutdown = nil
+	r.mtx.Unlock()
+	r.wg.Wait()
+}
@@ -0,0 +1,187 @@
+package spreadhistory
+
+import (
+	"errors"
+	"io/ioutil"
+	"math"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+func basis() Config {
+	return Config{Spreads: []Spread{{
+		Name:      "btc-basis",
+		A:         Leg{Exchange: "OKEX", Pair: "BTC-USD", AssetType: "perpetual"},
+		B:         Leg{Exchange: "Bitstamp", Pair: "BTCUSD"},
+		Measure:   BasisPoints,
+		Retention: "1h",
+	}}}
+}
+
+func TestConfigValidate(t *testing.T) {
+	c := Config{}
+	if err := c.Validate(); err != errNoSpreads {
+		t.Error("Test Failed - Validate() expected no spreads error", err)
+	}
+	c = basis()
+	c.Spreads[0].Measure = "percent"
+	if err := c.Validate(); err == nil {
+		t.Error("Test Failed - Validate() expected invalid measure error")
+	}
+	c = basis()
+	c.Spreads[0].Frequency = "-1m"
+	if err := c.Validate(); err == nil {
+		t.Error("Test Failed - Validate() expected invalid frequency error")
+	}
+	c = basis()
+	c.Spreads = append(c.Spreads, c.Spreads[0])
+	c.Spreads[1].Name = "BTC-Basis"
+	if err := c.Validate(); err == nil {
+		t.Error("Test Failed - Validate() expected duplicate name error")
+	}
+	c = basis()
+	if err := c.Validate(); err != nil {
+		t.Error("Test Failed - Validate() error", err)
+	}
+}
+
+func TestRecord(t *testing.T) {
+	dir, err := ioutil.TempDir("", "spreadhistory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "spreadhistory.jsonl")
+
+	prices := map[string]float64{"OKEX": 101, "Bitstamp": 100}
+	var priceErr error
+	price := func(exch string, _ currency.Pair, _ string) (float64, error) {
+		return prices[exch], priceErr
+	}
+	if _, err = New(basis(), path, nil); err != errNoPriceFunc {
+		t.Error("Test Failed - New() expected no price function error", err)
+	}
+	r, err := New(basis(), path, price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Now().Add(-time.Minute * 70)
+	r.now = func() time.Time { return now }
+
+	s, err := r.Record("BTC-BASIS")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Value != 100 || s.A != 101 || s.B != 100 {
+		t.Errorf("Test Failed - Record() expected a 100bps spread %+v", s)
+	}
+	if _, err = r.Record("eth-basis"); err == nil {
+		t.Error("Test Failed - Record() expected spread not found error")
+	}
+
+	priceErr = errors.New("timeout")
+	if _, err = r.Record("btc-basis"); err == nil {
+		t.Error("Test Failed - Record() expected price error")
+	}
+	if st := r.GetStatus(); len(st) != 1 || st[0].Error == "" || st[0].Samples != 1 {
+		t.Errorf("Test Failed - GetStatus() expected the price error %+v", st)
+	}
+	priceErr = nil
+	prices["OKEX"] = 0
+	if _, err = r.Record("btc-basis"); err == nil {
+		t.Error("Test Failed - Record() expected invalid price error")
+	}
+
+	prices["OKEX"] = 100.5
+	now = now.Add(time.Minute * 50)
+	if _, err = r.Record("btc-basis"); err != nil {
+		t.Fatal(err)
+	}
+
+	// Samples past their retention are dropped when reloaded
+	r, err = New(basis(), path, price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	samples, err := r.GetSamples("btc-basis", 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(samples) != 1 || samples[0].Value != 50 {
+		t.Errorf("Test Failed - GetSamples() expected the expired sample dropped %+v", samples)
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := len(data); n == 0 || data[n-1] != '\n' {
+		t.Errorf("Test Failed - load() expected the file rewritten %q", data)
+	}
+}
+
+func TestGetStats(t *testing.T) {
+	var value float64
+	price := func(exch string, _ currency.Pair, _ string) (float64, error) {
+		if exch == "OKEX" {
+			return 100 + value, nil
+		}
+		return 100, nil
+	}
+	c := basis()
+	c.Spreads[0].Measure = ""
+	r, err := New(c, "", price)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err = r.GetStats("btc-basis", 0, nil); err == nil {
+		t.Error("Test Failed - GetStats() expected no samples error")
+	}
+
+	now := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	r.now = func() time.Time { return now }
+	for i := 1; i <= 10; i++ {
+		value = float64(i)
+		if _, err = r.Record("btc-basis"); err != nil {
+			t.Fatal(err)
+		}
+		now = now.Add(time.Minute)
+	}
+	value = 3
+	if _, err = r.Record("btc-basis"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = r.GetStats("btc-basis", 0, []float64{101}); err != errInvalidPercentile {
+		t.Error("Test Failed - GetStats() expected invalid percentile error", err)
+	}
+	s, err := r.GetStats("btc-basis", 0, []float64{0, 50, 90, 100})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Count != 11 || s.Min != 1 || s.Max != 10 || s.Last != 3 || s.Measure != Difference {
+		t.Errorf("Test Failed - GetStats() unexpected stats %+v", s)
+	}
+	want := []float64{1, 5, 9, 10}
+	for i := range want {
+		if s.Percentiles[i].Value != want[i] {
+			t.Errorf("Test Failed - GetStats() percentile %v expected %v got %v",
+				s.Percentiles[i].Percentile, want[i], s.Percentiles[i].Value)
+		}
+	}
+	if math.Abs(s.Mean-58.0/11) > 1e-9 || s.ZScore >= 0 || math.Abs(s.Rank-400.0/11) > 1e-9 {
+		t.Errorf("Test Failed - GetStats() unexpected mean, z-score or rank %+v", s)
+	}
+
+	// The window limits the samples to the most recent
+	s, err = r.GetStats("btc-basis", time.Minute*2, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.Count != 3 || s.Min != 3 || s.Max != 10 || len(s.Percentiles) != len(DefaultPercentiles) {
+		t.Errorf("Test Failed - GetStats() expected the window's samples %+v", s)
+	}
+}
@@ -0,0 +1,25 @@
+package exchange
+
+import "github.com/thrasher-corp/gocryptotrader/currency"
+
+// StopOrder holds the state of a stop order held exchange side
+type StopOrder struct {
+	ID string
+	// Triggered is set once the trigger price was reached and the stop placed
+	// its order
+	Triggered bool
+	// Cancelled is set when the stop was cancelled or failed before it
+	// triggered
+	Cancelled bool
+	// OrderID is the ID of the order placed when triggered
+	OrderID string
+}
+
+// StopOrderSubmitter is implemented by exchanges which hold stop market orders
+// exchange side, so a stop triggers while the bot is offline instead of
+// relying on a client side trigger
+type StopOrderSubmitter interface {
+	SubmitStopOrder(p currency.Pair, side OrderSide, amount, triggerPrice float64) (string, error)
+	GetStopOrder(p currency.Pair, id string) (StopOrder, error)
+	CancelStopOrder(p currency.Pair, id string) error
+}
@@ -0,0 +1,9 @@
+package exchange
+
+// SubAccountProvider is implemented by exchanges which expose the balances
+// held in sub-accounts of the authenticated account
+type SubAccountProvider interface {
+	// GetSubAccountBalances returns the balances of each sub-account, or of
+	// all sub-accounts aggregated when the exchange does not break them down
+	GetSubAccountBalances() ([]Account, error)
+}
@@ -0,0 +1,25 @@
+package sweep
+
+import (
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+)
+
+// KrakenLimits reports the amount Kraken currently permits withdrawing to a
+// target's withdrawal key, within the account's verification tier limits
+type KrakenLimits struct {
+	Exchange *kraken.Kraken
+}
+
+// GetName returns the exchange name
+func (k *KrakenLimits) GetName() string {
+	return k.Exchange.GetName()
+}
+
+// GetWithdrawalLimit returns the most of the amount which can be withdrawn
+func (k *KrakenLimits) GetWithdrawalLimit(t *Target, amount float64) (float64, error) {
+	info, err := k.Exchange.GetWithdrawInfo(kraken.AssetName(t.Currency), t.Key, amount)
+	if err != nil {
+		return 0, err
+	}
+	return info.Limit, nil
+}
@@ -0,0 +1,617 @@
+// Package sweep moves balances above a floor from exchanges to cold storage on
+// a schedule while keeping within each exchange's withdrawal limits. Limits
+// are configured per currency or account wide for a verification tier, or
+// queried from exchanges which report them. A sweep larger than the remaining
+// allowance is split, the rest is swept as the rolling 24 hour window frees
+// up, and sweeps the limits block are reported
+package sweep
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default sweeper settings
+const (
+	DefaultCheckInterval = time.Hour
+	// Window is the period withdrawal limits apply to. Exchanges reset
+	// their limits either daily or on a rolling basis, a rolling window
+	// keeps within both
+	Window = time.Hour * 24
+	// retention is how long sweeps are kept once outside the window
+	retention = time.Hour * 24 * 7
+)
+
+// Event types emitted by the sweeper
+const (
+	Swept   = "SWEEP_WITHDRAWN"
+	Split   = "SWEEP_SPLIT"
+	Blocked = "SWEEP_BLOCKED"
+	Failed  = "SWEEP_FAILED"
+)
+
+var (
+	errNoExchanges     = errors.New("no exchange lookup supplied")
+	errNoTargets       = errors.New("no sweep targets configured")
+	errTargetExchange  = errors.New("sweep target exchange not set")
+	errTargetCurrency  = errors.New("sweep target currency not set")
+	errTargetAddress   = errors.New("sweep target address or withdrawal key not set")
+	errTargetKeep      = errors.New("sweep target keep and minimum amount cannot be negative")
+	errLimitExchange   = errors.New("withdrawal limit exchange not set")
+	errLimitCurrency   = errors.New("withdrawal limit currency not set")
+	errLimitDaily      = errors.New("withdrawal limit must be positive unless withdrawals are disabled")
+	errExchangeMissing = errors.New("exchange not loaded")
+	errNoValueFunc     = errors.New("no value lookup supplied for account wide limits")
+)
+
+// Target defines an exchange balance swept to cold storage
+type Target struct {
+	Exchange   string        `json:"exchange"`
+	Currency   currency.Code `json:"currency"`
+	Address    string        `json:"address"`
+	AddressTag string        `json:"addressTag,omitempty"`
+	// Key names the destination on exchanges which withdraw to
+	// preconfigured keys, such as Kraken
+	Key string `json:"key,omitempty"`
+	// Keep is the balance left on the exchange, the available balance
+	// above it is swept
+	Keep float64 `json:"keep"`
+	// MinAmount is the smallest withdrawal made, smaller amounts wait until
+	// the balance or allowance grows
+	MinAmount float64 `json:"minAmount,omitempty"`
+}
+
+func (t *Target) validate() error {
+	switch {
+	case t.Exchange == "":
+		return errTargetExchange
+	case t.Currency.String() == "":
+		return errTargetCurrency
+	case t.Address == "" && t.Key == "":
+		return errTargetAddress
+	case t.Keep < 0 || t.MinAmount < 0:
+		return errTargetKeep
+	}
+	return nil
+}
+
+func (t *Target) String() string {
+	return t.Exchange + " " + t.Currency.String()
+}
+
+// Limit defines an exchange's withdrawal limit over the window
+type Limit struct {
+	Exchange string `json:"exchange"`
+	// Currency is the currency limited, or the currency the limit is valued
+	// in when it applies account wide
+	Currency currency.Code `json:"currency"`
+	Daily    float64       `json:"daily"`
+	// Account limits apply to the value of withdrawals in every currency,
+	// as verification tiers usually do
+	Account bool `json:"account,omitempty"`
+	// Tier names the verification tier the limit belongs to
+	Tier string `json:"tier,omitempty"`
+	// Disabled is set when the tier does not permit withdrawals
+	Disabled bool `json:"disabled,omitempty"`
+}
+
+func (l *Limit) validate() error {
+	switch {
+	case l.Exchange == "":
+		return errLimitExchange
+	case l.Currency.String() == "":
+		return errLimitCurrency
+	case l.Daily <= 0 && !l.Disabled:
+		return errLimitDaily
+	}
+	return nil
+}
+
+// applies returns whether the limit covers withdrawals of the currency on the
+// exchange
+func (l *Limit) applies(exchName string, c currency.Code) bool {
+	return strings.EqualFold(l.Exchange, exchName) && (l.Account || l.Currency.Match(c))
+}
+
+// Config holds the sweep targets and the configured withdrawal limits
+type Config struct {
+	Targets []Target `json:"targets"`
+	Limits  []Limit  `json:"limits"`
+}
+
+// Validate checks the targets and limits are complete
+func (c *Config) Validate() error {
+	if len(c.Targets) == 0 {
+		return errNoTargets
+	}
+	for i := range c.Targets {
+		c.Targets[i].Currency = c.Targets[i].Currency.Upper()
+		if err := c.Targets[i].validate(); err != nil {
+			return fmt.Errorf("%s %v", c.Targets[i].String(), err)
+		}
+	}
+	for i := range c.Limits {
+		c.Limits[i].Currency = c.Limits[i].Currency.Upper()
+		if err := c.Limits[i].validate(); err != nil {
+			return fmt.Errorf("%s %v", c.Limits[i].Exchange, err)
+		}
+	}
+	return nil
+}
+
+// LoadConfig reads the sweep configuration from a JSON file, it is validated
+// when the sweeper is created
+func LoadConfig(path string) (Config, error) {
+	var c Config
+	data, err := common.ReadFile(path)
+	if err != nil {
+		return c, err
+	}
+	return c, json.Unmarshal(data, &c)
+}
+
+// Record holds a withdrawal made by a sweep
+type Record struct {
+	Exchange string        `json:"exchange"`
+	Currency currency.Code `json:"currency"`
+	Amount   float64       `json:"amount"`
+	ID       string        `json:"id"`
+	Time     time.Time     `json:"time"`
+}
+
+// Plan holds a target's pending sweep and what the limits allow of it
+type Plan struct {
+	Target  Target  `json:"target"`
+	Balance float64 `json:"balance"`
+	// Excess is the available balance above the amount kept
+	Excess float64 `json:"excess"`
+	// Allowance is the amount of the currency the limits permit withdrawing
+	// now, it is unset when no limits apply
+	Allowance *float64 `json:"allowance,omitempty"`
+	// Daily is the most the tightest limit permits over the window
+	Daily *float64 `json:"daily,omitempty"`
+	// Next is the amount withdrawn by the next sweep and Remaining the
+	// amount left for later windows
+	Next      float64 `json:"next"`
+	Remaining float64 `json:"remaining"`
+	// Days is the number of windows needed to sweep the excess, zero when
+	// no configured limit gives the amount permitted per window
+	Days int `json:"days"`
+	// Blocked explains why the limits prevent sweeping, and FreesAt is
+	// when the earliest withdrawal counting against them leaves the window
+	Blocked   string    `json:"blocked,omitempty"`
+	FreesAt   time.Time `json:"freesAt,omitempty"`
+	LastSwept time.Time `json:"lastSwept,omitempty"`
+	Checked   time.Time `json:"checked"`
+	Error     string    `json:"error,omitempty"`
+}
+
+// Event defines a sweep made, split or blocked
+type Event struct {
+	Type   string
+	Plan   Plan
+	Record *Record
+	Detail string
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	s := fmt.Sprintf("%s %s", e.Type, e.Plan.Target.String())
+	switch {
+	case e.Record != nil:
+		s += fmt.Sprintf(" swept %v to cold storage, withdrawal %s", e.Record.Amount, e.Record.ID)
+	case e.Type == Split && e.Plan.Days > 1:
+		s += fmt.Sprintf(" excess %v exceeds the withdrawal allowance, sweeping %v now and %v over %d more days",
+			e.Plan.Excess, e.Plan.Next, e.Plan.Remaining, e.Plan.Days-1)
+	case e.Type == Split:
+		s += fmt.Sprintf(" excess %v exceeds the withdrawal allowance, sweeping %v now and %v later",
+			e.Plan.Excess, e.Plan.Next, e.Plan.Remaining)
+	}
+	if e.Detail != "" {
+		s += ": " + e.Detail
+	}
+	return s
+}
+
+// ExchangeFunc returns a loaded exchange by name, or nil
+type ExchangeFunc func(name string) exchange.IBotExchange
+
+// ValueFunc returns the price of one unit of a currency in the quote currency
+// on the exchange, valuing withdrawals against account wide limits
+type ValueFunc func(exchName string, c, quote currency.Code) (float64, error)
+
+// LimitSource reports the amount of a currency an exchange currently permits
+// withdrawing, within its own limits
+type LimitSource interface {
+	GetName() string
+	GetWithdrawalLimit(t *Target, amount float64) (float64, error)
+}
+
+// Sweeper withdraws excess balances to cold storage within withdrawal limits
+type Sweeper struct {
+	cfg       Config
+	path      string
+	exchanges ExchangeFunc
+	value     ValueFunc
+	sources   map[string]LimitSource
+	onEvent   func(Event)
+
+	records []Record
+	plans   map[string]*Plan
+
+	checkMtx sync.Mutex
+	mtx      sync.Mutex
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+}
+
+// New returns a sweeper for the configured targets. Sweeps made are persisted
+// to the path so limits are respected across restarts, an empty path keeps
+// them in memory only
+func New(cfg Config, path string, exchanges ExchangeFunc, value ValueFunc, sources []LimitSource, onEvent func(Event)) (*Sweeper, error) {
+	if exchanges == nil {
+		return nil, errNoExchanges
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	for i := range cfg.Limits {
+		if cfg.Limits[i].Account && value == nil {
+			return nil, errNoValueFunc
+		}
+	}
+	s := &Sweeper{
+		cfg:       cfg,
+		path:      path,
+		exchanges: exchanges,
+		value:     value,
+		sources:   make(map[string]LimitSource),
+		onEvent:   onEvent,
+		plans:     make(map[string]*Plan),
+	}
+	for i := range sources {
+		s.sources[strings.ToLower(sources[i].GetName())] = sources[i]
+	}
+	if path == "" {
+		return s, nil
+	}
+	data, err := common.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	return s, json.Unmarshal(data, &s.records)
+}
+
+// save writes the sweeps made to the file, the caller must hold the lock
+func (s *Sweeper) save() error {
+	if s.path == "" {
+		return nil
+	}
+	data, err := json.MarshalIndent(s.records, "", " ")
+	if err != nil {
+		return err
+	}
+	return common.WriteFile(s.path, data)
+}
+
+// record stores a sweep made, dropping sweeps past their retention
+func (s *Sweeper) record(r *Record) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	cutoff := r.Time.Add(-retention)
+	kept := s.records[:0]
+	for i := range s.records {
+		if s.records[i].Time.After(cutoff) {
+			kept = append(kept, s.records[i])
+		}
+	}
+	s.records = append(kept, *r)
+	if err := s.save(); err != nil {
+		log.Errorf("Sweeper failed to persist %s %s withdrawal %s: %s", r.Exchange, r.Currency, r.ID, err)
+	}
+}
+
+// GetRecords returns the sweeps made within the retention period, newest
+// first
+func (s *Sweeper) GetRecords() []Record {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	records := make([]Record, len(s.records))
+	for i := range s.records {
+		records[len(records)-1-i] = s.records[i]
+	}
+	return records
+}
+
+// GetPlans returns each target's latest plan ordered by exchange and
+// currency
+func (s *Sweeper) GetPlans() []Plan {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	plans := make([]Plan, 0, len(s.plans))
+	for _, p := range s.plans {
+		plans = append(plans, *p)
+	}
+	sort.Slice(plans, func(i, j int) bool {
+		return plans[i].Target.String() < plans[j].Target.String()
+	})
+	return plans
+}
+
+// Check plans and makes the sweep of every target
+func (s *Sweeper) Check() {
+	s.checkMtx.Lock()
+	defer s.checkMtx.Unlock()
+	for i := range s.cfg.Targets {
+		s.check(&s.cfg.Targets[i], time.Now())
+	}
+}
+
+func (s *Sweeper) check(t *Target, now time.Time) {
+	s.mtx.Lock()
+	prev, ok := s.plans[t.String()]
+	s.mtx.Unlock()
+	if !ok {
+		prev = &Plan{}
+	}
+
+	p, err := s.plan(t, now)
+	p.LastSwept = prev.LastSwept
+	var events []Event
+	switch {
+	case err != nil:
+		p.Error = err.Error()
+	case p.Blocked != "":
+		// Reported when first blocked rather than on every check
+		if prev.Blocked == "" {
+			events = append(events, Event{Type: Blocked, Plan: *p, Detail: p.Blocked})
+		}
+	case p.Next > 0:
+		if p.Remaining > 0 {
+			events = append(events, Event{Type: Split, Plan: *p})
+		}
+		r, err := s.sweep(t, p.Next, now)
+		if err != nil {
+			p.Error = err.Error()
+			events = append(events, Event{Type: Failed, Plan: *p, Detail: p.Error})
+			break
+		}
+		p.LastSwept = now
+		events = append(events, Event{Type: Swept, Plan: *p, Record: r})
+	}
+
+	s.mtx.Lock()
+	s.plans[t.String()] = p
+	s.mtx.Unlock()
+	for i := range events {
+		if s.onEvent != nil {
+			s.onEvent(events[i])
+		}
+	}
+}
+
+// plan works out how much of the target's excess balance the limits permit
+// sweeping now
+func (s *Sweeper) plan(t *Target, now time.Time) (*Plan, error) {
+	p := &Plan{Target: *t, Checked: now}
+	exch := s.exchanges(t.Exchange)
+	if exch == nil {
+		return p, fmt.Errorf("%s %v", t.Exchange, errExchangeMissing)
+	}
+	info, err := exch.GetAccountInfo()
+	if err != nil {
+		return p, err
+	}
+	for i := range info.Accounts {
+		for j := range info.Accounts[i].Currencies {
+			c := &info.Accounts[i].Currencies[j]
+			if c.CurrencyName.Match(t.Currency) {
+				p.Balance += c.TotalValue - c.Hold
+			}
+		}
+	}
+	p.Excess = math.Max(p.Balance-t.Keep, 0)
+	if p.Excess == 0 || p.Excess < t.MinAmount {
+		return p, nil
+	}
+
+	allowance, daily, err := s.allowance(t, p, now)
+	if err != nil {
+		return p, err
+	}
+	if src, ok := s.sources[strings.ToLower(t.Exchange)]; ok {
+		limit, err := src.GetWithdrawalLimit(t, p.Excess)
+		if err != nil {
+			return p, fmt.Errorf("withdrawal limit: %v", err)
+		}
+		if p.Allowance == nil || limit < allowance {
+			allowance = limit
+			p.Allowance = &allowance
+		}
+	}
+
+	p.Next = p.Excess
+	if p.Allowance != nil {
+		p.Next = math.Min(p.Excess, math.Max(allowance, 0))
+	}
+	if p.Next < t.MinAmount || p.Next <= 0 {
+		if p.Blocked == "" {
+			p.Blocked = fmt.Sprintf("withdrawal allowance %v is below the minimum sweep of %v", allowance, t.MinAmount)
+		}
+		p.Next = 0
+	}
+	p.Remaining = p.Excess - p.Next
+	p.Days = 1
+	if p.Remaining > 0 {
+		p.Days = 0
+		if daily > 0 {
+			p.Days = 1 + int(math.Ceil(p.Remaining/daily))
+		}
+	}
+	if daily > 0 {
+		p.Daily = &daily
+	}
+	return p, nil
+}
+
+// allowance returns the amount of the target's currency the configured limits
+// permit withdrawing now and over a full window. Blocking limits are
+// explained in the plan
+func (s *Sweeper) allowance(t *Target, p *Plan, now time.Time) (allowance, daily float64, err error) {
+	since := now.Add(-Window)
+	s.mtx.Lock()
+	var recent []Record
+	for i := range s.records {
+		if strings.EqualFold(s.records[i].Exchange, t.Exchange) && s.records[i].Time.After(since) {
+			recent = append(recent, s.records[i])
+		}
+	}
+	s.mtx.Unlock()
+
+	for i := range s.cfg.Limits {
+		l := &s.cfg.Limits[i]
+		if !l.applies(t.Exchange, t.Currency) {
+			continue
+		}
+		if l.Disabled {
+			p.Blocked = fmt.Sprintf("%s tier %s does not permit %s withdrawals", l.Exchange, l.Tier, t.Currency)
+			zero := 0.0
+			p.Allowance = &zero
+			return 0, 0, nil
+		}
+
+		// price converts the limit currency into the target currency
+		price := 1.0
+		if !l.Currency.Match(t.Currency) {
+			price, err = s.value(t.Exchange, t.Currency, l.Currency)
+			if err != nil {
+				return 0, 0, fmt.Errorf("valuing %s against %s %s limit: %v", t.Currency, l.Exchange, l.Currency, err)
+			}
+		}
+		var used float64
+		var oldest time.Time
+		for j := range recent {
+			r := &recent[j]
+			if !l.Account && !r.Currency.Match(t.Currency) {
+				continue
+			}
+			amount := r.Amount
+			if !r.Currency.Match(l.Currency) {
+				var v float64
+				v, err = s.value(r.Exchange, r.Currency, l.Currency)
+				if err != nil {
+					return 0, 0, fmt.Errorf("valuing %s against %s %s limit: %v", r.Currency, l.Exchange, l.Currency, err)
+				}
+				amount *= v
+			}
+			used += amount
+			if oldest.IsZero() || r.Time.Before(oldest) {
+				oldest = r.Time
+			}
+		}
+
+		remaining := (l.Daily - used) / price
+		if p.Allowance == nil || remaining < allowance {
+			allowance = remaining
+			p.Allowance = &allowance
+			if remaining <= 0 || remaining < t.MinAmount {
+				p.Blocked = fmt.Sprintf("%v of %s %v %s limit used in the last %s",
+					used, l.Exchange, l.Daily, l.Currency, Window)
+				if !oldest.IsZero() {
+					p.FreesAt = oldest.Add(Window)
+				}
+			}
+		}
+		if d := l.Daily / price; daily == 0 || d < daily {
+			daily = d
+		}
+	}
+	return allowance, daily, nil
+}
+
+// sweep withdraws the amount to the target's cold storage address
+func (s *Sweeper) sweep(t *Target, amount float64, now time.Time) (*Record, error) {
+	exch := s.exchanges(t.Exchange)
+	if exch == nil {
+		return nil, fmt.Errorf("%s %v", t.Exchange, errExchangeMissing)
+	}
+	id, err := exch.WithdrawCryptocurrencyFunds(&exchange.WithdrawRequest{
+		Description:   "sweep to cold storage",
+		Currency:      t.Currency,
+		Amount:        amount,
+		Address:       t.Address,
+		AddressTag:    t.AddressTag,
+		TradePassword: t.Key,
+	})
+	if err != nil {
+		return nil, err
+	}
+	r := &Record{
+		Exchange: t.Exchange,
+		Currency: t.Currency,
+		Amount:   amount,
+		ID:       id,
+		Time:     now,
+	}
+	s.record(r)
+	return r, nil
+}
+
+// Start checks every target at the interval until stopped
+func (s *Sweeper) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	s.mtx.Lock()
+	if s.shutdown != nil {
+		s.mtx.Unlock()
+		return
+	}
+	s.shutdown = make(chan struct{})
+	shutdown := s.shutdown
+	s.mtx.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		s.Check()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				s.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops the sweeper
+func (s *Sweeper) Stop() {
+	s.mtx.Lock()
+	if s.shutdown == nil {
+		s.mtx.Unlock()
+		return
+	}
+	close(s.shutdown)
+	s.shutdown = nil
+	s.mtx.Unlock()
+	s.wg.Wait()
+}
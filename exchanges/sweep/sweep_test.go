@@ -0,0 +1,197 @@
+package sweep
+
+import (
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	balances  map[currency.Code]float64
+	withdrawn []exchange.WithdrawRequest
+	err       error
+}
+
+func newTestExchange() *testExchange {
+	return &testExchange{balances: make(map[currency.Code]float64)}
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) GetAccountInfo() (exchange.AccountInfo, error) {
+	var a exchange.Account
+	for c, v := range t.balances {
+		a.Currencies = append(a.Currencies, exchange.AccountCurrencyInfo{CurrencyName: c, TotalValue: v})
+	}
+	return exchange.AccountInfo{Exchange: "test", Accounts: []exchange.Account{a}}, nil
+}
+
+func (t *testExchange) WithdrawCryptocurrencyFunds(w *exchange.WithdrawRequest) (string, error) {
+	if t.err != nil {
+		return "", t.err
+	}
+	t.withdrawn = append(t.withdrawn, *w)
+	t.balances[w.Currency] -= w.Amount
+	return fmt.Sprintf("w%d", len(t.withdrawn)), nil
+}
+
+type testLimits struct {
+	limit float64
+}
+
+func (t *testLimits) GetName() string { return "test" }
+
+func (t *testLimits) GetWithdrawalLimit(_ *Target, _ float64) (float64, error) {
+	return t.limit, nil
+}
+
+func testSweeper(t *testing.T, cfg Config, path string, exch *testExchange, sources []LimitSource, events *[]Event) *Sweeper {
+	s, err := New(cfg, path,
+		func(string) exchange.IBotExchange { return exch },
+		func(_ string, c, quote currency.Code) (float64, error) {
+			if c.Match(currency.ETH) && quote.Match(currency.BTC) {
+				return 0.05, nil
+			}
+			return 0, errors.New("no price")
+		},
+		sources,
+		func(e Event) { *events = append(*events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	return s
+}
+
+func TestConfigValidate(t *testing.T) {
+	cfg := Config{}
+	if err := cfg.Validate(); err != errNoTargets {
+		t.Error("Test Failed - Validate() expected no targets error", err)
+	}
+	cfg.Targets = []Target{{Exchange: "test", Currency: currency.BTC}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Test Failed - Validate() expected missing address error")
+	}
+	cfg.Targets[0].Address = "addr"
+	cfg.Limits = []Limit{{Exchange: "test", Currency: currency.BTC}}
+	if err := cfg.Validate(); err == nil {
+		t.Error("Test Failed - Validate() expected limit error")
+	}
+	cfg.Limits[0].Disabled = true
+	if err := cfg.Validate(); err != nil {
+		t.Error("Test Failed - Validate() error", err)
+	}
+}
+
+func TestSplit(t *testing.T) {
+	dir, err := ioutil.TempDir("", "sweep")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "sweeps.json")
+
+	exch := newTestExchange()
+	exch.balances[currency.BTC] = 6
+	cfg := Config{
+		Targets: []Target{{Exchange: "test", Currency: currency.BTC, Address: "cold", Keep: 1, MinAmount: 0.1}},
+		Limits:  []Limit{{Exchange: "test", Currency: currency.BTC, Daily: 2}},
+	}
+	var events []Event
+	s := testSweeper(t, cfg, path, exch, nil, &events)
+
+	s.Check()
+	if len(exch.withdrawn) != 1 || exch.withdrawn[0].Amount != 2 || exch.withdrawn[0].Address != "cold" {
+		t.Fatalf("Test Failed - Check() expected a sweep of the daily limit %+v", exch.withdrawn)
+	}
+	if len(events) != 2 || events[0].Type != Split || events[1].Type != Swept {
+		t.Fatalf("Test Failed - Check() expected split and swept events %+v", events)
+	}
+	p := events[0].Plan
+	if p.Excess != 5 || p.Next != 2 || p.Remaining != 3 || p.Days != 3 {
+		t.Errorf("Test Failed - Check() unexpected plan %+v", p)
+	}
+
+	// The limit is used up for the window, and persists across restarts
+	s = testSweeper(t, cfg, path, exch, nil, &events)
+	s.Check()
+	s.Check()
+	plans := s.GetPlans()
+	if len(exch.withdrawn) != 1 || len(plans) != 1 || plans[0].Blocked == "" || plans[0].FreesAt.IsZero() {
+		t.Fatalf("Test Failed - Check() expected the sweep blocked by the limit %+v", plans)
+	}
+	if len(events) != 3 || events[2].Type != Blocked {
+		t.Errorf("Test Failed - Check() expected one blocked event %+v", events)
+	}
+
+	// The remainder is swept once the window frees up
+	s.check(&s.cfg.Targets[0], time.Now().Add(Window+time.Minute))
+	if len(exch.withdrawn) != 2 || exch.withdrawn[1].Amount != 2 {
+		t.Errorf("Test Failed - check() expected the next day's sweep %+v", exch.withdrawn)
+	}
+	if len(s.GetRecords()) != 2 {
+		t.Errorf("Test Failed - GetRecords() expected 2 records %+v", s.GetRecords())
+	}
+}
+
+func TestAccountLimit(t *testing.T) {
+	exch := newTestExchange()
+	exch.balances[currency.BTC] = 1.5
+	exch.balances[currency.ETH] = 40
+	cfg := Config{
+		Targets: []Target{
+			{Exchange: "test", Currency: currency.BTC, Address: "cold"},
+			{Exchange: "test", Currency: currency.ETH, Address: "cold"},
+		},
+		Limits: []Limit{{Exchange: "test", Currency: currency.BTC, Daily: 2, Account: true, Tier: "1"}},
+	}
+	var events []Event
+	s := testSweeper(t, cfg, "", exch, nil, &events)
+
+	// ETH is limited to the 0.5 BTC of the account limit the BTC sweep leaves
+	s.Check()
+	if len(exch.withdrawn) != 2 || exch.withdrawn[0].Amount != 1.5 || exch.withdrawn[1].Amount != 10 {
+		t.Fatalf("Test Failed - Check() unexpected sweeps %+v", exch.withdrawn)
+	}
+	plans := s.GetPlans()
+	if plans[1].Target.Currency != currency.ETH || plans[1].Remaining != 30 || plans[1].Days != 2 {
+		t.Errorf("Test Failed - GetPlans() unexpected ETH plan %+v", plans[1])
+	}
+
+	cfg.Limits = append(cfg.Limits, Limit{Exchange: "test", Currency: currency.ETH, Disabled: true, Tier: "1"})
+	events = nil
+	s = testSweeper(t, cfg, "", exch, nil, &events)
+	s.Check()
+	if len(exch.withdrawn) != 2 || len(events) != 1 || events[0].Type != Blocked {
+		t.Errorf("Test Failed - Check() expected disabled withdrawals blocked %+v", events)
+	}
+}
+
+func TestLimitSource(t *testing.T) {
+	exch := newTestExchange()
+	exch.balances[currency.BTC] = 3
+	cfg := Config{Targets: []Target{{Exchange: "test", Currency: currency.BTC, Key: "cold"}}}
+	var events []Event
+	s := testSweeper(t, cfg, "", exch, []LimitSource{&testLimits{limit: 1}}, &events)
+
+	s.Check()
+	if len(exch.withdrawn) != 1 || exch.withdrawn[0].Amount != 1 || exch.withdrawn[0].TradePassword != "cold" {
+		t.Fatalf("Test Failed - Check() expected a sweep of the reported limit %+v", exch.withdrawn)
+	}
+	if p := s.GetPlans()[0]; p.Days != 0 || p.Remaining != 2 {
+		t.Errorf("Test Failed - Check() unexpected plan %+v", p)
+	}
+
+	exch.err = errors.New("withdrawals suspended")
+	s.Check()
+	if p := s.GetPlans()[0]; p.Error == "" || events[len(events)-1].Type != Failed {
+		t.Errorf("Test Failed - Check() expected failed sweep %+v", p)
+	}
+}
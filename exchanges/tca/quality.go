@@ -0,0 +1,388 @@
+package tca
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// const values for strategy fill quality
+const (
+	// DefaultAdverseHorizon is how long after a fill the mid price is compared
+	// against the mid at the fill to measure adverse selection
+	DefaultAdverseHorizon = time.Minute
+	// DefaultRecentWindow is the window of recent fills compared against the
+	// fills before it to detect execution quality regressions
+	DefaultRecentWindow = time.Hour * 24
+	// DefaultQualityCheckInterval is the interval strategy fill quality is
+	// checked for regressions
+	DefaultQualityCheckInterval = time.Minute * 15
+)
+
+// Regression metrics
+const (
+	MetricMakerRatio       = "maker_ratio"
+	MetricSpreadCapture    = "spread_capture"
+	MetricAdverseSelection = "adverse_selection"
+)
+
+// FillQuality holds the maker/taker split and fill quality of a set of
+// executions
+type FillQuality struct {
+	Executions  int
+	Volume      float64
+	MakerVolume float64
+	TakerVolume float64
+	MakerRatio  float64
+	// AvgSpreadCaptureBps is the notional weighted distance of fills from the
+	// mid price at the fill, positive when buying below or selling above mid
+	AvgSpreadCaptureBps float64
+	// AvgAdverseSelectionBps is the notional weighted move of the mid price
+	// against each fill after the horizon, positive when the market moved
+	// against us
+	AvgAdverseSelectionBps float64
+	// AdverseSamples is the amount of fills with a mid price observed after
+	// the horizon
+	AdverseSamples int
+}
+
+// StrategyReport holds the fill quality of a strategy's executions overall,
+// within the recent window and before it
+type StrategyReport struct {
+	Strategy string
+	FillQuality
+	Baseline      FillQuality
+	Recent        FillQuality
+	LastExecution time.Time
+}
+
+// Thresholds define how far a strategy's recent fill quality may degrade from
+// its baseline before it is reported as a regression
+type Thresholds struct {
+	// MinExecutions is the amount of fills required in both the recent window
+	// and the baseline before they are compared
+	MinExecutions           int
+	MakerRatioDrop          float64
+	SpreadCaptureDropBps    float64
+	AdverseSelectionRiseBps float64
+}
+
+// DefaultThresholds are the default regression thresholds
+var DefaultThresholds = Thresholds{
+	MinExecutions:           10,
+	MakerRatioDrop:          0.2,
+	SpreadCaptureDropBps:    5,
+	AdverseSelectionRiseBps: 5,
+}
+
+// Regression holds a fill quality metric which has degraded
+type Regression struct {
+	Strategy string
+	Metric   string
+	Baseline float64
+	Recent   float64
+}
+
+func (r *Regression) String() string {
+	return fmt.Sprintf("strategy %s %s regressed from %.4f to %.4f",
+		r.Strategy, r.Metric, r.Baseline, r.Recent)
+}
+
+// IsMaker returns whether a limit order at the price would rest on the book
+// against the quote rather than crossing it
+func IsMaker(side string, price float64, s *SpreadSample) bool {
+	if isBuy(side) {
+		return price < s.Ask
+	}
+	return price > s.Bid
+}
+
+// GetSpreadAt returns the latest spread sample observed at or before the time
+func GetSpreadAt(exchange string, p currency.Pair, assetType string, t time.Time) (SpreadSample, error) {
+	m.Lock()
+	defer m.Unlock()
+	s := spreads[newKey(exchange, p, assetType)]
+	i := sort.Search(len(s), func(i int) bool { return s[i].Timestamp.After(t) })
+	if i == 0 {
+		return SpreadSample{}, errors.New(errNoArrivalMid)
+	}
+	return s[i-1], nil
+}
+
+// SpreadCapture returns the distance of the execution price from the mid in
+// basis points, positive when buying below or selling above the mid
+func (e *Execution) SpreadCapture(mid float64) float64 {
+	if mid == 0 {
+		return 0
+	}
+	if isBuy(e.Side) {
+		return (mid - e.ExecutionPrice) / mid * bps
+	}
+	return (e.ExecutionPrice - mid) / mid * bps
+}
+
+// AdverseSelection returns the move of the mid price against the execution
+// in basis points, positive when the mid moved against us
+func (e *Execution) AdverseSelection(mid, after float64) float64 {
+	if mid == 0 {
+		return 0
+	}
+	if isBuy(e.Side) {
+		return (mid - after) / mid * bps
+	}
+	return (after - mid) / mid * bps
+}
+
+// fillQuality aggregates executions into their fill quality
+type fillQuality struct {
+	FillQuality
+	capture, adverse, notional, adverseNotional float64
+}
+
+// add adds an execution using the spread samples observed for its venue to
+// find the mid at the fill and after the horizon
+func (q *fillQuality) add(e *Execution, s []SpreadSample, horizon time.Duration) {
+	q.Executions++
+	q.Volume += e.Amount
+	if e.Maker {
+		q.MakerVolume += e.Amount
+	} else {
+		q.TakerVolume += e.Amount
+	}
+
+	mid := e.ArrivalMid
+	i := sort.Search(len(s), func(i int) bool { return s[i].Timestamp.After(e.Timestamp) })
+	if i > 0 {
+		mid = s[i-1].Mid
+	}
+	n := e.ExecutionPrice * e.Amount
+	q.capture += e.SpreadCapture(mid) * n
+	q.notional += n
+
+	after := e.Timestamp.Add(horizon)
+	j := sort.Search(len(s), func(i int) bool { return !s[i].Timestamp.Before(after) })
+	if j < len(s) {
+		q.adverse += e.AdverseSelection(mid, s[j].Mid) * n
+		q.adverseNotional += n
+		q.AdverseSamples++
+	}
+}
+
+func (q *fillQuality) result() FillQuality {
+	r := q.FillQuality
+	if r.Volume > 0 {
+		r.MakerRatio = r.MakerVolume / r.Volume
+	}
+	if q.notional > 0 {
+		r.AvgSpreadCaptureBps = q.capture / q.notional
+	}
+	if q.adverseNotional > 0 {
+		r.AvgAdverseSelectionBps = q.adverse / q.adverseNotional
+	}
+	return r
+}
+
+// GetStrategyReports returns the maker/taker split, spread capture and
+// adverse selection of each strategy's executions, measuring adverse
+// selection over the horizon and splitting fills within the recent window
+// from the baseline before it. Reports are ordered by strategy name
+func GetStrategyReports(horizon, recent time.Duration) []StrategyReport {
+	type aggregate struct {
+		overall, baseline, recent fillQuality
+		last                      time.Time
+	}
+	since := time.Now().Add(-recent)
+
+	m.Lock()
+	aggregates := make(map[string]*aggregate)
+	for k, e := range executions {
+		for i := range e {
+			if e[i].Strategy == "" {
+				continue
+			}
+			a, ok := aggregates[e[i].Strategy]
+			if !ok {
+				a = &aggregate{}
+				aggregates[e[i].Strategy] = a
+			}
+			a.overall.add(&e[i], spreads[k], horizon)
+			if e[i].Timestamp.Before(since) {
+				a.baseline.add(&e[i], spreads[k], horizon)
+			} else {
+				a.recent.add(&e[i], spreads[k], horizon)
+			}
+			if e[i].Timestamp.After(a.last) {
+				a.last = e[i].Timestamp
+			}
+		}
+	}
+	m.Unlock()
+
+	reports := make([]StrategyReport, 0, len(aggregates))
+	for name, a := range aggregates {
+		reports = append(reports, StrategyReport{
+			Strategy:      name,
+			FillQuality:   a.overall.result(),
+			Baseline:      a.baseline.result(),
+			Recent:        a.recent.result(),
+			LastExecution: a.last,
+		})
+	}
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].Strategy < reports[j].Strategy
+	})
+	return reports
+}
+
+// DetectRegressions compares a strategy's recent fill quality against its
+// baseline, returning each metric degraded beyond the thresholds
+func DetectRegressions(r *StrategyReport, t Thresholds) []Regression {
+	if r.Recent.Executions < t.MinExecutions || r.Baseline.Executions < t.MinExecutions {
+		return nil
+	}
+
+	var resp []Regression
+	if t.MakerRatioDrop > 0 &&
+		r.Baseline.MakerRatio-r.Recent.MakerRatio > t.MakerRatioDrop {
+		resp = append(resp, Regression{
+			Strategy: r.Strategy,
+			Metric:   MetricMakerRatio,
+			Baseline: r.Baseline.MakerRatio,
+			Recent:   r.Recent.MakerRatio,
+		})
+	}
+	if t.SpreadCaptureDropBps > 0 &&
+		r.Baseline.AvgSpreadCaptureBps-r.Recent.AvgSpreadCaptureBps > t.SpreadCaptureDropBps {
+		resp = append(resp, Regression{
+			Strategy: r.Strategy,
+			Metric:   MetricSpreadCapture,
+			Baseline: r.Baseline.AvgSpreadCaptureBps,
+			Recent:   r.Recent.AvgSpreadCaptureBps,
+		})
+	}
+	if t.AdverseSelectionRiseBps > 0 &&
+		r.Recent.AdverseSamples >= t.MinExecutions &&
+		r.Baseline.AdverseSamples >= t.MinExecutions &&
+		r.Recent.AvgAdverseSelectionBps-r.Baseline.AvgAdverseSelectionBps > t.AdverseSelectionRiseBps {
+		resp = append(resp, Regression{
+			Strategy: r.Strategy,
+			Metric:   MetricAdverseSelection,
+			Baseline: r.Baseline.AvgAdverseSelectionBps,
+			Recent:   r.Recent.AvgAdverseSelectionBps,
+		})
+	}
+	return resp
+}
+
+// Monitor periodically checks strategy fill quality, alerting once when a
+// metric regresses and again only after it has recovered
+type Monitor struct {
+	horizon    time.Duration
+	recent     time.Duration
+	thresholds Thresholds
+	onAlert    func(Regression)
+	alerted    map[string]bool
+	shutdown   chan struct{}
+	wg         sync.WaitGroup
+	mtx        sync.Mutex
+}
+
+// NewMonitor returns a fill quality monitor, non positive durations use the
+// defaults
+func NewMonitor(horizon, recent time.Duration, t Thresholds, onAlert func(Regression)) *Monitor {
+	if horizon <= 0 {
+		horizon = DefaultAdverseHorizon
+	}
+	if recent <= 0 {
+		recent = DefaultRecentWindow
+	}
+	return &Monitor{
+		horizon:    horizon,
+		recent:     recent,
+		thresholds: t,
+		onAlert:    onAlert,
+		alerted:    make(map[string]bool),
+	}
+}
+
+// GetReports returns the strategy reports measured with the monitor's horizon
+// and recent window
+func (q *Monitor) GetReports() []StrategyReport {
+	return GetStrategyReports(q.horizon, q.recent)
+}
+
+// Check returns the current regressions, alerting those not already alerted
+func (q *Monitor) Check() []Regression {
+	reports := q.GetReports()
+	var regressions []Regression
+	for i := range reports {
+		regressions = append(regressions, DetectRegressions(&reports[i], q.thresholds)...)
+	}
+
+	q.mtx.Lock()
+	current := make(map[string]bool, len(regressions))
+	var alerts []Regression
+	for i := range regressions {
+		k := regressions[i].Strategy + "/" + regressions[i].Metric
+		current[k] = true
+		if !q.alerted[k] {
+			alerts = append(alerts, regressions[i])
+		}
+	}
+	q.alerted = current
+	q.mtx.Unlock()
+
+	if q.onAlert != nil {
+		for i := range alerts {
+			q.onAlert(alerts[i])
+		}
+	}
+	return regressions
+}
+
+// Start checks fill quality every interval until stopped
+func (q *Monitor) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultQualityCheckInterval
+	}
+	q.mtx.Lock()
+	if q.shutdown != nil {
+		q.mtx.Unlock()
+		return
+	}
+	q.shutdown = make(chan struct{})
+	shutdown := q.shutdown
+	q.mtx.Unlock()
+
+	q.wg.Add(1)
+	go func() {
+		defer q.wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-t.C:
+				q.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops checking fill quality
+func (q *Monitor) Stop() {
+	q.mtx.Lock()
+	if q.shutdown == nil {
+		q.mtx.Unlock()
+		return
+	}
+	close(q.shutdown)
+	q.shutdown = nil
+	q.mtx.Unlock()
+	q.wg.Wait()
+}
@@ -0,0 +1,322 @@
+// Package tca records quoted spreads and realised execution prices so that
+// transaction costs can be analysed per pair and per venue
+package tca
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// const values for the tca package
+const (
+	// MaxSpreadHistory is the maximum amount of spread samples retained per
+	// exchange, pair and asset type
+	MaxSpreadHistory = 1000
+	// MaxExecutionHistory is the maximum amount of executions retained per
+	// exchange, pair and asset type
+	MaxExecutionHistory = 5000
+
+	bps = 10000
+
+	errExchangeNotSet = "tca exchange name not set"
+	errPairNotSet     = "tca currency pair not set"
+	errAssetNotSet    = "tca asset type not set"
+	errInvalidPrice   = "tca invalid price"
+	errInvalidAmount  = "tca invalid amount"
+	errNoArrivalMid   = "tca arrival mid price could not be determined"
+)
+
+// Vars for the tca package
+var (
+	spreads    = make(map[key][]SpreadSample)
+	executions = make(map[key][]Execution)
+	m          sync.Mutex
+)
+
+// key defines a unique exchange, pair and asset type lookup
+type key struct {
+	Exchange string
+	Base     string
+	Quote    string
+	Asset    string
+}
+
+// SpreadSample holds a quoted spread observation
+type SpreadSample struct {
+	Timestamp time.Time
+	Bid       float64
+	Ask       float64
+	Mid       float64
+	SpreadBps float64
+}
+
+// Execution holds a realised fill alongside the mid price observed when the
+// order was submitted. Strategy and Maker attribute the fill to the strategy
+// which submitted it and whether it provided liquidity
+type Execution struct {
+	Exchange       string
+	Strategy       string
+	Pair           currency.Pair
+	AssetType      string
+	OrderID        string
+	Side           string
+	ArrivalMid     float64
+	ExecutionPrice float64
+	Amount         float64
+	Fee            float64
+	Maker          bool
+	Timestamp      time.Time
+}
+
+// VenueReport holds aggregated transaction cost analysis for a venue
+type VenueReport struct {
+	Exchange           string
+	Pair               currency.Pair
+	AssetType          string
+	Executions         int
+	Volume             float64
+	AvgSpreadBps       float64
+	AvgShortfallBps    float64
+	AvgFeeBps          float64
+	AvgTotalCostBps    float64
+	WorstShortfallBps  float64
+	LastExecution      time.Time
+	SpreadObservations int
+}
+
+func newKey(exchange string, p currency.Pair, assetType string) key {
+	return key{
+		Exchange: strings.ToLower(exchange),
+		Base:     p.Base.Upper().String(),
+		Quote:    p.Quote.Upper().String(),
+		Asset:    strings.ToUpper(assetType),
+	}
+}
+
+func validate(exchange string, p currency.Pair, assetType string) error {
+	if exchange == "" {
+		return errors.New(errExchangeNotSet)
+	}
+	if p.Base.IsEmpty() || p.Quote.IsEmpty() {
+		return errors.New(errPairNotSet)
+	}
+	if assetType == "" {
+		return errors.New(errAssetNotSet)
+	}
+	return nil
+}
+
+// AddSpread records a quoted spread for an exchange, pair and asset type
+func AddSpread(exchange string, p currency.Pair, assetType string, bid, ask float64) error {
+	err := validate(exchange, p, assetType)
+	if err != nil {
+		return err
+	}
+
+	if bid <= 0 || ask <= 0 || ask < bid {
+		return errors.New(errInvalidPrice)
+	}
+
+	mid := (bid + ask) / 2
+	s := SpreadSample{
+		Timestamp: time.Now(),
+		Bid:       bid,
+		Ask:       ask,
+		Mid:       mid,
+		SpreadBps: (ask - bid) / mid * bps,
+	}
+
+	k := newKey(exchange, p, assetType)
+	m.Lock()
+	spreads[k] = append(spreads[k], s)
+	if len(spreads[k]) > MaxSpreadHistory {
+		spreads[k] = spreads[k][len(spreads[k])-MaxSpreadHistory:]
+	}
+	m.Unlock()
+	return nil
+}
+
+// GetSpreadHistory returns a copy of the recorded spread samples
+func GetSpreadHistory(exchange string, p currency.Pair, assetType string) []SpreadSample {
+	m.Lock()
+	defer m.Unlock()
+	s := spreads[newKey(exchange, p, assetType)]
+	return append([]SpreadSample(nil), s...)
+}
+
+// GetLatestMid returns the most recently observed mid price
+func GetLatestMid(exchange string, p currency.Pair, assetType string) (float64, error) {
+	m.Lock()
+	defer m.Unlock()
+	s := spreads[newKey(exchange, p, assetType)]
+	if len(s) == 0 {
+		return 0, errors.New(errNoArrivalMid)
+	}
+	return s[len(s)-1].Mid, nil
+}
+
+// AddExecution records an execution, if the arrival mid is not set the latest
+// observed mid price is used
+func AddExecution(e *Execution) error {
+	err := validate(e.Exchange, e.Pair, e.AssetType)
+	if err != nil {
+		return err
+	}
+
+	if e.ExecutionPrice <= 0 {
+		return errors.New(errInvalidPrice)
+	}
+
+	if e.Amount <= 0 {
+		return errors.New(errInvalidAmount)
+	}
+
+	if e.ArrivalMid <= 0 {
+		e.ArrivalMid, err = GetLatestMid(e.Exchange, e.Pair, e.AssetType)
+		if err != nil {
+			return err
+		}
+	}
+
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+
+	k := newKey(e.Exchange, e.Pair, e.AssetType)
+	m.Lock()
+	executions[k] = append(executions[k], *e)
+	if len(executions[k]) > MaxExecutionHistory {
+		executions[k] = executions[k][len(executions[k])-MaxExecutionHistory:]
+	}
+	m.Unlock()
+	return nil
+}
+
+// isBuy returns if the side string is a buy side
+func isBuy(side string) bool {
+	switch strings.ToUpper(side) {
+	case "BUY", "BID":
+		return true
+	}
+	return false
+}
+
+// ImplementationShortfall returns the price slippage versus the arrival mid
+// in basis points, positive values are a cost
+func (e *Execution) ImplementationShortfall() float64 {
+	if e.ArrivalMid == 0 {
+		return 0
+	}
+	if isBuy(e.Side) {
+		return (e.ExecutionPrice - e.ArrivalMid) / e.ArrivalMid * bps
+	}
+	return (e.ArrivalMid - e.ExecutionPrice) / e.ArrivalMid * bps
+}
+
+// FeeBps returns the fee paid relative to the notional in basis points
+func (e *Execution) FeeBps() float64 {
+	notional := e.ExecutionPrice * e.Amount
+	if notional == 0 {
+		return 0
+	}
+	return e.Fee / notional * bps
+}
+
+// GetExecutions returns a copy of the recorded executions
+func GetExecutions(exchange string, p currency.Pair, assetType string) []Execution {
+	m.Lock()
+	defer m.Unlock()
+	e := executions[newKey(exchange, p, assetType)]
+	return append([]Execution(nil), e...)
+}
+
+// GetVenueReports returns transaction cost reports for all venues that have
+// recorded executions for the pair and asset type, ordered from cheapest to
+// most expensive
+func GetVenueReports(p currency.Pair, assetType string) []VenueReport {
+	m.Lock()
+	defer m.Unlock()
+
+	var reports []VenueReport
+	for k, e := range executions {
+		if k.Base != p.Base.Upper().String() ||
+			k.Quote != p.Quote.Upper().String() ||
+			k.Asset != strings.ToUpper(assetType) ||
+			len(e) == 0 {
+			continue
+		}
+
+		r := VenueReport{
+			Exchange:          e[0].Exchange,
+			Pair:              p,
+			AssetType:         assetType,
+			Executions:        len(e),
+			WorstShortfallBps: e[0].ImplementationShortfall(),
+		}
+
+		var weightedShortfall, weightedFee, notional float64
+		for i := range e {
+			n := e[i].ExecutionPrice * e[i].Amount
+			s := e[i].ImplementationShortfall()
+			weightedShortfall += s * n
+			weightedFee += e[i].FeeBps() * n
+			notional += n
+			r.Volume += e[i].Amount
+			if s > r.WorstShortfallBps {
+				r.WorstShortfallBps = s
+			}
+			if e[i].Timestamp.After(r.LastExecution) {
+				r.LastExecution = e[i].Timestamp
+			}
+		}
+
+		if notional > 0 {
+			r.AvgShortfallBps = weightedShortfall / notional
+			r.AvgFeeBps = weightedFee / notional
+		}
+		r.AvgTotalCostBps = r.AvgShortfallBps + r.AvgFeeBps
+
+		s := spreads[k]
+		r.SpreadObservations = len(s)
+		for i := range s {
+			r.AvgSpreadBps += s[i].SpreadBps
+		}
+		if len(s) > 0 {
+			r.AvgSpreadBps /= float64(len(s))
+		}
+
+		reports = append(reports, r)
+	}
+
+	sort.Slice(reports, func(i, j int) bool {
+		return reports[i].AvgTotalCostBps < reports[j].AvgTotalCostBps
+	})
+	return reports
+}
+
+// GetVenueScores returns a normalised score between 0 and 1 per exchange for
+// the pair and asset type, the cheapest venue by total cost scores 1. This is
+// intended to be consumed by order routing logic when selecting a venue.
+func GetVenueScores(p currency.Pair, assetType string) map[string]float64 {
+	reports := GetVenueReports(p, assetType)
+	scores := make(map[string]float64)
+	if len(reports) == 0 {
+		return scores
+	}
+
+	best := reports[0].AvgTotalCostBps
+	worst := reports[len(reports)-1].AvgTotalCostBps
+	for i := range reports {
+		if worst == best {
+			scores[reports[i].Exchange] = 1
+			continue
+		}
+		scores[reports[i].Exchange] = 1 - (reports[i].AvgTotalCostBps-best)/(worst-best)
+	}
+	return scores
+}
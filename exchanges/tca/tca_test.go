@@ -0,0 +1,204 @@
+package tca
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+var testPair = currency.NewPairFromStrings("BTC", "USD")
+
+func TestAddSpread(t *testing.T) {
+	err := AddSpread("", testPair, "SPOT", 1, 2)
+	if err == nil {
+		t.Error("Test Failed - AddSpread() error cannot be nil")
+	}
+
+	err = AddSpread("spreadtest", testPair, "SPOT", 101, 100)
+	if err == nil {
+		t.Error("Test Failed - AddSpread() crossed quote should error")
+	}
+
+	err = AddSpread("spreadtest", testPair, "SPOT", 99, 101)
+	if err != nil {
+		t.Fatal("Test Failed - AddSpread() error", err)
+	}
+
+	h := GetSpreadHistory("spreadtest", testPair, "SPOT")
+	if len(h) != 1 || h[0].Mid != 100 || h[0].SpreadBps != 200 {
+		t.Error("Test Failed - GetSpreadHistory() unexpected values", h)
+	}
+
+	for i := 0; i < MaxSpreadHistory+10; i++ {
+		AddSpread("spreadtest", testPair, "SPOT", 99, 101)
+	}
+	if len(GetSpreadHistory("spreadtest", testPair, "SPOT")) != MaxSpreadHistory {
+		t.Error("Test Failed - AddSpread() history not capped")
+	}
+}
+
+func TestImplementationShortfall(t *testing.T) {
+	e := Execution{Side: "BUY", ArrivalMid: 100, ExecutionPrice: 101, Amount: 1, Fee: 0.101}
+	if s := e.ImplementationShortfall(); math.Abs(s-100) > 1e-9 {
+		t.Errorf("Test Failed - ImplementationShortfall() expected 100 received %v", s)
+	}
+	if f := e.FeeBps(); math.Abs(f-10) > 1e-9 {
+		t.Errorf("Test Failed - FeeBps() expected 10 received %v", f)
+	}
+
+	e.Side = "SELL"
+	if s := e.ImplementationShortfall(); math.Abs(s+100) > 1e-9 {
+		t.Errorf("Test Failed - ImplementationShortfall() expected -100 received %v", s)
+	}
+}
+
+func TestVenueReports(t *testing.T) {
+	p := currency.NewPairFromStrings("LTC", "USD")
+	err := AddExecution(&Execution{
+		Exchange:       "cheap",
+		Pair:           p,
+		AssetType:      "SPOT",
+		Side:           "BUY",
+		ExecutionPrice: 100,
+		Amount:         1,
+	})
+	if err == nil {
+		t.Error("Test Failed - AddExecution() without arrival mid should error")
+	}
+
+	AddSpread("cheap", p, "SPOT", 99.9, 100.1)
+	err = AddExecution(&Execution{
+		Exchange:       "cheap",
+		Pair:           p,
+		AssetType:      "SPOT",
+		Side:           "BUY",
+		ExecutionPrice: 100,
+		Amount:         1,
+	})
+	if err != nil {
+		t.Fatal("Test Failed - AddExecution() error", err)
+	}
+
+	err = AddExecution(&Execution{
+		Exchange:       "expensive",
+		Pair:           p,
+		AssetType:      "SPOT",
+		Side:           "BUY",
+		ArrivalMid:     100,
+		ExecutionPrice: 101,
+		Amount:         1,
+		Fee:            1,
+	})
+	if err != nil {
+		t.Fatal("Test Failed - AddExecution() error", err)
+	}
+
+	r := GetVenueReports(p, "SPOT")
+	if len(r) != 2 || r[0].Exchange != "cheap" {
+		t.Fatal("Test Failed - GetVenueReports() unexpected ordering", r)
+	}
+
+	scores := GetVenueScores(p, "SPOT")
+	if scores["cheap"] != 1 || scores["expensive"] != 0 {
+		t.Error("Test Failed - GetVenueScores() unexpected scores", scores)
+	}
+}
+
+func TestStrategyReports(t *testing.T) {
+	p := currency.NewPairFromStrings("XRP", "USD")
+	now := time.Now()
+	old := now.Add(-DefaultRecentWindow * 2)
+
+	k := newKey("quality", p, "SPOT")
+	m.Lock()
+	spreads[k] = []SpreadSample{
+		{Timestamp: old, Bid: 99, Ask: 101, Mid: 100},
+		{Timestamp: old.Add(time.Minute), Bid: 99, Ask: 101, Mid: 100},
+		{Timestamp: now.Add(-time.Hour), Bid: 99, Ask: 101, Mid: 100},
+		{Timestamp: now.Add(-time.Hour + time.Minute), Bid: 97, Ask: 99, Mid: 98},
+	}
+	m.Unlock()
+
+	s, err := GetSpreadAt("quality", p, "SPOT", old.Add(time.Second))
+	if err != nil || s.Timestamp != old {
+		t.Error("Test Failed - GetSpreadAt() unexpected sample", s, err)
+	}
+	if !IsMaker("BUY", 100, &s) || IsMaker("BUY", 101, &s) || !IsMaker("SELL", 100, &s) {
+		t.Error("Test Failed - IsMaker() unexpected result")
+	}
+
+	for i := 0; i < 2; i++ {
+		err = AddExecution(&Execution{
+			Exchange:       "quality",
+			Strategy:       "maker",
+			Pair:           p,
+			AssetType:      "SPOT",
+			Side:           "BUY",
+			ArrivalMid:     100,
+			ExecutionPrice: 99,
+			Amount:         1,
+			Maker:          true,
+			Timestamp:      old,
+		})
+		if err != nil {
+			t.Fatal("Test Failed - AddExecution() error", err)
+		}
+	}
+	for i := 0; i < 2; i++ {
+		err = AddExecution(&Execution{
+			Exchange:       "quality",
+			Strategy:       "maker",
+			Pair:           p,
+			AssetType:      "SPOT",
+			Side:           "BUY",
+			ArrivalMid:     100,
+			ExecutionPrice: 101,
+			Amount:         1,
+			Timestamp:      now.Add(-time.Hour),
+		})
+		if err != nil {
+			t.Fatal("Test Failed - AddExecution() error", err)
+		}
+	}
+
+	r := GetStrategyReports(DefaultAdverseHorizon, DefaultRecentWindow)
+	if len(r) != 1 || r[0].Strategy != "maker" {
+		t.Fatal("Test Failed - GetStrategyReports() unexpected reports", r)
+	}
+	if r[0].Executions != 4 || r[0].MakerRatio != 0.5 ||
+		r[0].Baseline.MakerRatio != 1 || r[0].Recent.MakerRatio != 0 {
+		t.Error("Test Failed - GetStrategyReports() unexpected maker ratio", r[0])
+	}
+	if math.Abs(r[0].Baseline.AvgSpreadCaptureBps-100) > 1e-9 ||
+		math.Abs(r[0].Recent.AvgSpreadCaptureBps+100) > 1e-9 {
+		t.Error("Test Failed - GetStrategyReports() unexpected spread capture", r[0])
+	}
+	if r[0].Baseline.AvgAdverseSelectionBps != 0 ||
+		math.Abs(r[0].Recent.AvgAdverseSelectionBps-200) > 1e-9 ||
+		r[0].Recent.AdverseSamples != 2 {
+		t.Error("Test Failed - GetStrategyReports() unexpected adverse selection", r[0])
+	}
+
+	regressions := DetectRegressions(&r[0], DefaultThresholds)
+	if len(regressions) != 0 {
+		t.Error("Test Failed - DetectRegressions() compared too few executions", regressions)
+	}
+	thresholds := DefaultThresholds
+	thresholds.MinExecutions = 2
+	regressions = DetectRegressions(&r[0], thresholds)
+	if len(regressions) != 3 {
+		t.Fatal("Test Failed - DetectRegressions() expected 3 regressions", regressions)
+	}
+
+	var alerts []Regression
+	q := NewMonitor(0, 0, thresholds, func(r Regression) {
+		alerts = append(alerts, r)
+	})
+	q.Check()
+	q.Check()
+	if len(alerts) != 3 {
+		t.Error("Test Failed - Monitor.Check() expected regressions alerted once", alerts)
+	}
+}
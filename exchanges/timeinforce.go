@@ -0,0 +1,113 @@
+package exchange
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// TimeInForce defines how long an order remains active before it is executed
+// or expires
+type TimeInForce string
+
+// TimeInForce types
+const (
+	GoodTillCancel    TimeInForce = "GTC"
+	ImmediateOrCancel TimeInForce = "IOC"
+	FillOrKill        TimeInForce = "FOK"
+	GoodTillDate      TimeInForce = "GTD"
+)
+
+var (
+	// ErrTimeInForceUnsupported is returned when an exchange cannot honour the
+	// requested time in force
+	ErrTimeInForceUnsupported = errors.New("time in force unsupported")
+
+	errUnknownTimeInForce     = errors.New("unknown time in force")
+	errExpiryRequired         = errors.New("good till date orders require an expiry")
+	errExpiryElapsed          = errors.New("order expiry has already elapsed")
+	errExpiryNotApplicable    = errors.New("expiry is only applicable to good till date orders")
+	errMarketOrderTimeInForce = errors.New("market orders only support immediate or cancel and fill or kill")
+)
+
+// TimeInForceSubmitter is implemented by exchanges which support placing
+// orders with a time in force other than their default
+type TimeInForceSubmitter interface {
+	SupportedTimeInForce() []TimeInForce
+	SubmitOrderWithTimeInForce(p currency.Pair, side OrderSide, orderType OrderType, amount, price float64, clientID string, tif TimeInForce, expiry time.Time) (SubmitOrderResponse, error)
+}
+
+// Validate checks that the time in force is known and compatible with the
+// order type and expiry
+func (t TimeInForce) Validate(orderType OrderType, expiry time.Time) error {
+	switch t {
+	case GoodTillCancel, ImmediateOrCancel, FillOrKill:
+		if !expiry.IsZero() {
+			return errExpiryNotApplicable
+		}
+	case GoodTillDate:
+		if expiry.IsZero() {
+			return errExpiryRequired
+		}
+		if !expiry.After(time.Now()) {
+			return errExpiryElapsed
+		}
+	default:
+		return fmt.Errorf("%s %v", t, errUnknownTimeInForce)
+	}
+
+	if orderType == MarketOrderType &&
+		t != ImmediateOrCancel &&
+		t != FillOrKill {
+		return errMarketOrderTimeInForce
+	}
+	return nil
+}
+
+// SupportsTimeInForce returns whether the exchange can place orders with the
+// supplied time in force. Limit orders are good till cancelled by default on
+// all exchanges
+func SupportsTimeInForce(e IBotExchange, t TimeInForce) bool {
+	if s, ok := e.(TimeInForceSubmitter); ok {
+		supported := s.SupportedTimeInForce()
+		for i := range supported {
+			if supported[i] == t {
+				return true
+			}
+		}
+	}
+	return t == GoodTillCancel
+}
+
+// SubmitOrderWithTimeInForce validates the time in force and submits the order
+// through the exchange's native mapping. An empty time in force submits the
+// order with the exchange's default behaviour
+func SubmitOrderWithTimeInForce(e IBotExchange, p currency.Pair, side OrderSide, orderType OrderType, amount, price float64, clientID string, tif TimeInForce, expiry time.Time) (SubmitOrderResponse, error) {
+	if tif == "" {
+		return e.SubmitOrder(p, side, orderType, amount, price, clientID)
+	}
+
+	err := tif.Validate(orderType, expiry)
+	if err != nil {
+		return SubmitOrderResponse{}, err
+	}
+
+	if !SupportsTimeInForce(e, tif) {
+		return SubmitOrderResponse{}, fmt.Errorf("%s %s %v",
+			e.GetName(), tif, ErrTimeInForceUnsupported)
+	}
+
+	if s, ok := e.(TimeInForceSubmitter); ok {
+		return s.SubmitOrderWithTimeInForce(p,
+			side,
+			orderType,
+			amount,
+			price,
+			clientID,
+			tif,
+			expiry)
+	}
+	return e.SubmitOrder(p, side, orderType, amount, price, clientID)
+}
@@ -0,0 +1,97 @@
+package exchange
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testTIFExchange struct {
+	IBotExchange
+	submitted TimeInForce
+	plain     bool
+}
+
+func (t *testTIFExchange) GetName() string { return "test" }
+
+func (t *testTIFExchange) SubmitOrder(_ currency.Pair, _ OrderSide, _ OrderType, _, _ float64, _ string) (SubmitOrderResponse, error) {
+	t.plain = true
+	return SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+type testTIFSubmitter struct {
+	testTIFExchange
+}
+
+func (t *testTIFSubmitter) SupportedTimeInForce() []TimeInForce {
+	return []TimeInForce{GoodTillCancel, ImmediateOrCancel}
+}
+
+func (t *testTIFSubmitter) SubmitOrderWithTimeInForce(_ currency.Pair, _ OrderSide, _ OrderType, _, _ float64, _ string, tif TimeInForce, _ time.Time) (SubmitOrderResponse, error) {
+	t.submitted = tif
+	return SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func TestTimeInForceValidate(t *testing.T) {
+	future := time.Now().Add(time.Hour)
+	tests := []struct {
+		tif       TimeInForce
+		orderType OrderType
+		expiry    time.Time
+		valid     bool
+	}{
+		{GoodTillCancel, LimitOrderType, time.Time{}, true},
+		{ImmediateOrCancel, MarketOrderType, time.Time{}, true},
+		{FillOrKill, LimitOrderType, time.Time{}, true},
+		{GoodTillDate, LimitOrderType, future, true},
+		{GoodTillDate, LimitOrderType, time.Time{}, false},
+		{GoodTillDate, LimitOrderType, time.Now().Add(-time.Hour), false},
+		{GoodTillCancel, LimitOrderType, future, false},
+		{GoodTillCancel, MarketOrderType, time.Time{}, false},
+		{GoodTillDate, MarketOrderType, future, false},
+		{"DAY", LimitOrderType, time.Time{}, false},
+	}
+
+	for i := range tests {
+		err := tests[i].tif.Validate(tests[i].orderType, tests[i].expiry)
+		if (err == nil) != tests[i].valid {
+			t.Errorf("Test Failed - TimeInForce Validate() %s %s expected valid %v, received %v",
+				tests[i].tif, tests[i].orderType, tests[i].valid, err)
+		}
+	}
+}
+
+func TestSubmitOrderWithTimeInForce(t *testing.T) {
+	p := currency.NewPair(currency.BTC, currency.USD)
+
+	plain := &testTIFExchange{}
+	_, err := SubmitOrderWithTimeInForce(plain, p, BuyOrderSide, LimitOrderType, 1, 1, "", GoodTillCancel, time.Time{})
+	if err != nil || !plain.plain {
+		t.Error("Test Failed - SubmitOrderWithTimeInForce() expected GTC to use SubmitOrder", err)
+	}
+
+	_, err = SubmitOrderWithTimeInForce(plain, p, BuyOrderSide, LimitOrderType, 1, 1, "", ImmediateOrCancel, time.Time{})
+	if err == nil {
+		t.Error("Test Failed - SubmitOrderWithTimeInForce() expected unsupported error")
+	}
+
+	native := &testTIFSubmitter{}
+	_, err = SubmitOrderWithTimeInForce(native, p, BuyOrderSide, MarketOrderType, 1, 0, "", ImmediateOrCancel, time.Time{})
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrderWithTimeInForce() error", err)
+	}
+	if native.submitted != ImmediateOrCancel || native.plain {
+		t.Error("Test Failed - SubmitOrderWithTimeInForce() expected native submission")
+	}
+
+	_, err = SubmitOrderWithTimeInForce(native, p, BuyOrderSide, LimitOrderType, 1, 1, "", GoodTillDate, time.Now().Add(time.Hour))
+	if err == nil {
+		t.Error("Test Failed - SubmitOrderWithTimeInForce() expected unsupported GTD error")
+	}
+
+	_, err = SubmitOrderWithTimeInForce(NewReadOnly(native), p, BuyOrderSide, LimitOrderType, 1, 1, "", ImmediateOrCancel, time.Time{})
+	if err == nil {
+		t.Error("Test Failed - SubmitOrderWithTimeInForce() expected read only wrapper to reject order")
+	}
+}
@@ -0,0 +1,22 @@
+package exchange
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// ErrPairNotTradeable is returned when an order is sent to a market the
+// exchange has frozen, disabled or delisted
+var ErrPairNotTradeable = errors.New("market is not tradeable")
+
+// Tradeability is implemented by exchanges which publish markets that are
+// frozen, disabled or delisted
+type Tradeability interface {
+	// CheckTradeable returns an error wrapping ErrPairNotTradeable when the
+	// pair cannot currently be traded
+	CheckTradeable(p currency.Pair) error
+	// GetUntradeablePairs returns the reason each untradeable pair cannot be
+	// traded, keyed by the exchange's pair symbol
+	GetUntradeablePairs() (map[string]string, error)
+}
@@ -0,0 +1,142 @@
+package tradehistory
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+var (
+	errExchangeNotSet = errors.New("fill exchange name not set")
+	errOrderIDNotSet  = errors.New("fill order ID not set")
+	errInvalidAmount  = errors.New("fill amount must be positive")
+	errInvalidPrice   = errors.New("fill price cannot be negative")
+)
+
+// Fill holds an execution of one of our orders
+type Fill struct {
+	Exchange string             `json:"exchange"`
+	OrderID  string             `json:"orderID"`
+	TradeID  string             `json:"tradeID,omitempty"`
+	Pair     currency.Pair      `json:"pair"`
+	Side     exchange.OrderSide `json:"side"`
+	// Price is zero when a backfilled fill's price is unknown
+	Price     float64   `json:"price"`
+	Amount    float64   `json:"amount"`
+	Fee       float64   `json:"fee,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	// Backfilled fills were recovered from exchange history rather than
+	// recorded as they happened
+	Backfilled bool `json:"backfilled,omitempty"`
+}
+
+// Store persists fills as JSON lines, appending each fill as it is added
+type Store struct {
+	path  string
+	fills []Fill
+	mtx   sync.Mutex
+}
+
+// NewStore returns a store appending fills to path, loading the fills it
+// already holds. An empty path keeps fills in memory only
+func NewStore(path string) (*Store, error) {
+	s := &Store{path: path}
+	if path == "" {
+		return s, nil
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+		var fill Fill
+		if err = json.Unmarshal(scanner.Bytes(), &fill); err != nil {
+			return nil, err
+		}
+		s.fills = append(s.fills, fill)
+	}
+	return s, scanner.Err()
+}
+
+// Add validates and persists a fill
+func (s *Store) Add(f *Fill) error {
+	if f.Exchange == "" {
+		return errExchangeNotSet
+	}
+	if f.OrderID == "" {
+		return errOrderIDNotSet
+	}
+	if f.Amount <= 0 {
+		return errInvalidAmount
+	}
+	if f.Price < 0 {
+		return errInvalidPrice
+	}
+
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.path != "" {
+		data, err := json.Marshal(f)
+		if err != nil {
+			return err
+		}
+		file, err := os.OpenFile(s.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return err
+		}
+		_, err = file.Write(append(data, '\n'))
+		if cerr := file.Close(); err == nil {
+			err = cerr
+		}
+		if err != nil {
+			return err
+		}
+	}
+	s.fills = append(s.fills, *f)
+	return nil
+}
+
+// Fills returns the exchange's fills at or after start, oldest first as
+// added
+func (s *Store) Fills(exchangeName string, start time.Time) []Fill {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var resp []Fill
+	for i := range s.fills {
+		if strings.EqualFold(s.fills[i].Exchange, exchangeName) && !s.fills[i].Timestamp.Before(start) {
+			resp = append(resp, s.fills[i])
+		}
+	}
+	return resp
+}
+
+// Latest returns the time of the exchange's latest fill, zero when none are
+// recorded
+func (s *Store) Latest(exchangeName string) time.Time {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	var latest time.Time
+	for i := range s.fills {
+		if strings.EqualFold(s.fills[i].Exchange, exchangeName) && s.fills[i].Timestamp.After(latest) {
+			latest = s.fills[i].Timestamp
+		}
+	}
+	return latest
+}
@@ -0,0 +1,465 @@
+// Package tradehistory persists the bot's fills and detects gaps between them
+// and each exchange's order history on a schedule. Executions the exchange
+// reports beyond the fills recorded, such as fills received while the bot was
+// down, are backfilled. Recorded fills the exchange history contradicts, by
+// exceeding the amount it executed or by side or pair, are flagged as
+// irreconcilable
+package tradehistory
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default detector settings
+const (
+	DefaultCheckInterval = time.Minute * 15
+	// DefaultLookback is how far back history is compared when no fills are
+	// recorded, and the furthest back any check reaches
+	DefaultLookback = time.Hour * 24 * 7
+	// DefaultOverlap is how far before the previous check history is compared
+	// again, as exchanges can report executions late
+	DefaultOverlap = time.Hour
+	// DefaultTolerance is the fraction of an order's amount by which the
+	// recorded and executed amounts may differ
+	DefaultTolerance = 0.0001
+)
+
+// Event types emitted by the detector
+const (
+	Backfilled     = "TRADE_HISTORY_BACKFILLED"
+	Irreconcilable = "TRADE_HISTORY_IRRECONCILABLE"
+)
+
+var (
+	errNoStore         = errors.New("no fill store supplied")
+	errNoSources       = errors.New("no history sources supplied")
+	errInvalidSettings = errors.New("lookback, overlap and tolerance cannot be negative")
+)
+
+// Source provides an exchange's order history. Exchange wrappers implement
+// it
+type Source interface {
+	GetName() string
+	GetEnabledCurrencies() currency.Pairs
+	GetOrderHistory(req *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error)
+	GetActiveOrders(req *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error)
+}
+
+// Config defines the history compared and the differences tolerated
+type Config struct {
+	Lookback  time.Duration
+	Overlap   time.Duration
+	Tolerance float64
+}
+
+// Discrepancy holds an order whose recorded fills the exchange history
+// cannot explain
+type Discrepancy struct {
+	Exchange string             `json:"exchange"`
+	OrderID  string             `json:"orderID"`
+	Pair     currency.Pair      `json:"pair"`
+	Side     exchange.OrderSide `json:"side"`
+	Recorded float64            `json:"recorded"`
+	Executed float64            `json:"executed"`
+	Reason   string             `json:"reason"`
+	Detected time.Time          `json:"detected"`
+}
+
+// Status holds the result of an exchange's latest comparison
+type Status struct {
+	Exchange   string    `json:"exchange"`
+	Start      time.Time `json:"start"`
+	End        time.Time `json:"end"`
+	Orders     int       `json:"orders"`
+	Backfilled int       `json:"backfilled"`
+	Error      string    `json:"error,omitempty"`
+}
+
+// Report holds every exchange's latest comparison and the discrepancies
+// found
+type Report struct {
+	Exchanges     []Status      `json:"exchanges"`
+	Discrepancies []Discrepancy `json:"discrepancies"`
+}
+
+// Event defines a fill backfilled or a discrepancy flagged
+type Event struct {
+	Type        string
+	Fill        *Fill
+	Discrepancy *Discrepancy
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	if e.Discrepancy != nil {
+		d := e.Discrepancy
+		return fmt.Sprintf("%s %s %s order %s %s: recorded %v, exchange executed %v: %s",
+			e.Type, d.Exchange, d.Pair, d.OrderID, d.Side, d.Recorded, d.Executed, d.Reason)
+	}
+	f := e.Fill
+	return fmt.Sprintf("%s %s %s order %s %s %v @ %v", e.Type, f.Exchange, f.Pair,
+		f.OrderID, f.Side, f.Amount, f.Price)
+}
+
+// recorded aggregates an order's recorded fills
+type recorded struct {
+	amount float64
+	sides  map[exchange.OrderSide]bool
+	pairs  []currency.Pair
+}
+
+func (r *recorded) add(f *Fill) {
+	r.amount += f.Amount
+	r.sides[side(f.Side)] = true
+	for i := range r.pairs {
+		if r.pairs[i].Equal(f.Pair) {
+			return
+		}
+	}
+	r.pairs = append(r.pairs, f.Pair)
+}
+
+// side normalises bids to buys and asks to sells
+func side(s exchange.OrderSide) exchange.OrderSide {
+	switch exchange.OrderSide(strings.ToUpper(string(s))) {
+	case exchange.BuyOrderSide, exchange.BidOrderSide:
+		return exchange.BuyOrderSide
+	case exchange.SellOrderSide, exchange.AskOrderSide:
+		return exchange.SellOrderSide
+	}
+	return s
+}
+
+// Detector compares the recorded fills with each exchange's order history
+type Detector struct {
+	store   *Store
+	sources []Source
+	cfg     Config
+	onEvent func(Event)
+	// ends holds the end of each exchange's previous comparison
+	ends          map[string]time.Time
+	statuses      map[string]Status
+	discrepancies []Discrepancy
+	flagged       map[string]bool
+	now           func() time.Time
+	shutdown      chan struct{}
+	wg            sync.WaitGroup
+	mtx           sync.Mutex
+	checkMtx      sync.Mutex
+}
+
+// New returns a detector backfilling the store from the sources' history
+func New(store *Store, sources []Source, cfg Config, onEvent func(Event)) (*Detector, error) {
+	if store == nil {
+		return nil, errNoStore
+	}
+	if len(sources) == 0 {
+		return nil, errNoSources
+	}
+	if cfg.Lookback < 0 || cfg.Overlap < 0 || cfg.Tolerance < 0 {
+		return nil, errInvalidSettings
+	}
+	if cfg.Lookback == 0 {
+		cfg.Lookback = DefaultLookback
+	}
+	if cfg.Overlap == 0 {
+		cfg.Overlap = DefaultOverlap
+	}
+	if cfg.Tolerance == 0 {
+		cfg.Tolerance = DefaultTolerance
+	}
+	return &Detector{
+		store:    store,
+		sources:  sources,
+		cfg:      cfg,
+		onEvent:  onEvent,
+		ends:     make(map[string]time.Time),
+		statuses: make(map[string]Status),
+		flagged:  make(map[string]bool),
+		now:      time.Now,
+	}, nil
+}
+
+// start returns the start of the exchange's next comparison: the overlap
+// before its previous comparison, or before its latest recorded fill so fills
+// missed while the bot was down are found, capped at the lookback
+func (d *Detector) start(exchangeName string, now time.Time) time.Time {
+	last, ok := d.ends[strings.ToLower(exchangeName)]
+	if !ok {
+		last = d.store.Latest(exchangeName)
+	}
+	earliest := now.Add(-d.cfg.Lookback)
+	if last.IsZero() {
+		return earliest
+	}
+	start := last.Add(-d.cfg.Overlap)
+	if start.Before(earliest) {
+		return earliest
+	}
+	return start
+}
+
+// Check compares every source's history since its previous comparison
+func (d *Detector) Check() {
+	d.checkMtx.Lock()
+	defer d.checkMtx.Unlock()
+	for _, s := range d.sources {
+		status, events := d.check(s)
+		if status.Error != "" {
+			log.Warnf("Trade history %s comparison failed: %s", status.Exchange, status.Error)
+		}
+		d.mtx.Lock()
+		d.statuses[strings.ToLower(status.Exchange)] = status
+		d.mtx.Unlock()
+		for i := range events {
+			log.Debugln(events[i].String())
+			if d.onEvent != nil {
+				d.onEvent(events[i])
+			}
+		}
+	}
+}
+
+// check compares a source's history, backfilling and flagging its orders
+func (d *Detector) check(s Source) (Status, []Event) {
+	now := d.now()
+	status := Status{
+		Exchange: s.GetName(),
+		Start:    d.start(s.GetName(), now),
+		End:      now,
+	}
+	req := &exchange.GetOrdersRequest{
+		OrderType:  exchange.AnyOrderType,
+		OrderSide:  exchange.AnyOrderSide,
+		StartTicks: status.Start,
+		EndTicks:   status.End,
+		Currencies: s.GetEnabledCurrencies(),
+	}
+	history, err := s.GetOrderHistory(req)
+	if err != nil {
+		status.Error = err.Error()
+		return status, nil
+	}
+	// Partially filled orders still resting are not in the order history
+	active, err := s.GetActiveOrders(req)
+	if err != nil {
+		status.Error = err.Error()
+		return status, nil
+	}
+	d.ends[strings.ToLower(s.GetName())] = now
+
+	orders := make(map[string]exchange.OrderDetail)
+	for _, o := range append(history, active...) {
+		if o.ID == "" {
+			continue
+		}
+		if prev, ok := orders[o.ID]; ok && prev.ExecutedAmount >= o.ExecutedAmount {
+			continue
+		}
+		orders[o.ID] = o
+	}
+
+	fills := make(map[string]*recorded)
+	for _, f := range d.store.Fills(s.GetName(), time.Time{}) {
+		r, ok := fills[f.OrderID]
+		if !ok {
+			r = &recorded{sides: make(map[exchange.OrderSide]bool)}
+			fills[f.OrderID] = r
+		}
+		r.add(&f)
+	}
+
+	ids := make([]string, 0, len(orders))
+	for id := range orders {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var events []Event
+	for _, id := range ids {
+		o := orders[id]
+		if o.Exchange == "" {
+			o.Exchange = s.GetName()
+		}
+		status.Orders++
+		r := fills[id]
+		if r == nil {
+			r = &recorded{}
+		}
+		if e := d.reconcile(&o, r, now); e != nil {
+			events = append(events, *e)
+			continue
+		}
+		backfilled := d.backfill(&o, r.amount)
+		status.Backfilled += len(backfilled)
+		events = append(events, backfilled...)
+	}
+	return status, events
+}
+
+// reconcile flags an order whose recorded fills differ from the exchange's
+// by side, pair or exceeding its executed amount. Each difference is flagged
+// once
+func (d *Detector) reconcile(o *exchange.OrderDetail, r *recorded, now time.Time) *Event {
+	var reason string
+	tolerance := d.cfg.Tolerance * math.Max(o.Amount, o.ExecutedAmount)
+	switch {
+	case len(r.sides) > 1 || (len(r.sides) == 1 && !r.sides[side(o.OrderSide)]):
+		reason = "recorded side differs from the exchange"
+	case len(r.pairs) > 1 || (len(r.pairs) == 1 && !r.pairs[0].Equal(o.CurrencyPair)):
+		reason = "recorded pair differs from the exchange"
+	case r.amount-o.ExecutedAmount > tolerance:
+		reason = "recorded amount exceeds the amount executed"
+	default:
+		return nil
+	}
+
+	key := o.Exchange + " " + o.ID + " " + reason
+	if d.flagged[key] {
+		return nil
+	}
+	d.flagged[key] = true
+	disc := Discrepancy{
+		Exchange: o.Exchange,
+		OrderID:  o.ID,
+		Pair:     o.CurrencyPair,
+		Side:     o.OrderSide,
+		Recorded: r.amount,
+		Executed: o.ExecutedAmount,
+		Reason:   reason,
+		Detected: now,
+	}
+	d.mtx.Lock()
+	d.discrepancies = append(d.discrepancies, disc)
+	d.mtx.Unlock()
+	return &Event{Type: Irreconcilable, Discrepancy: &disc}
+}
+
+// backfill records the amount the exchange executed beyond the recorded
+// fills. Orders with no recorded fills are backfilled trade by trade when the
+// exchange reports their trades, the remaining amount is backfilled at the
+// order price as of the order date
+func (d *Detector) backfill(o *exchange.OrderDetail, recordedAmount float64) []Event {
+	tolerance := d.cfg.Tolerance * math.Max(o.Amount, o.ExecutedAmount)
+	missing := o.ExecutedAmount - recordedAmount
+	if missing <= tolerance {
+		return nil
+	}
+
+	var fills []Fill
+	if recordedAmount == 0 {
+		for i := range o.Trades {
+			t := &o.Trades[i]
+			if t.Amount <= 0 || t.Amount > missing+tolerance {
+				continue
+			}
+			fills = append(fills, Fill{
+				TradeID:   strconv.FormatInt(t.TID, 10),
+				Price:     t.Price,
+				Amount:    t.Amount,
+				Fee:       t.Fee,
+				Timestamp: t.Timestamp,
+			})
+			missing -= t.Amount
+		}
+	}
+	if missing > tolerance {
+		f := Fill{Price: o.Price, Amount: missing, Timestamp: o.OrderDate}
+		// Order fees cannot be apportioned to part of an order
+		if recordedAmount == 0 && len(fills) == 0 {
+			f.Fee = o.Fee
+		}
+		fills = append(fills, f)
+	}
+
+	var events []Event
+	for i := range fills {
+		f := &fills[i]
+		f.Exchange = o.Exchange
+		f.OrderID = o.ID
+		f.Pair = o.CurrencyPair
+		f.Side = o.OrderSide
+		f.Backfilled = true
+		if f.Timestamp.IsZero() {
+			f.Timestamp = d.now()
+		}
+		if err := d.store.Add(f); err != nil {
+			log.Errorf("Trade history failed to backfill %s order %s: %s", o.Exchange, o.ID, err)
+			continue
+		}
+		events = append(events, Event{Type: Backfilled, Fill: f})
+	}
+	return events
+}
+
+// GetReport returns every exchange's latest comparison and the discrepancies
+// flagged
+func (d *Detector) GetReport() Report {
+	d.mtx.Lock()
+	defer d.mtx.Unlock()
+	r := Report{
+		Exchanges:     make([]Status, 0, len(d.statuses)),
+		Discrepancies: append([]Discrepancy(nil), d.discrepancies...),
+	}
+	for _, s := range d.statuses {
+		r.Exchanges = append(r.Exchanges, s)
+	}
+	sort.Slice(r.Exchanges, func(i, j int) bool {
+		return r.Exchanges[i].Exchange < r.Exchanges[j].Exchange
+	})
+	return r
+}
+
+// Start compares history at the interval until stopped
+func (d *Detector) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	d.mtx.Lock()
+	if d.shutdown != nil {
+		d.mtx.Unlock()
+		return
+	}
+	d.shutdown = make(chan struct{})
+	shutdown := d.shutdown
+	d.mtx.Unlock()
+
+	d.wg.Add(1)
+	go func() {
+		defer d.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			d.Check()
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+			}
+		}
+	}()
+}
+
+// Stop stops the detector
+func (d *Detector) Stop() {
+	d.mtx.Lock()
+	if d.shutdown == nil {
+		d.mtx.Unlock()
+		return
+	}
+	close(d.shutdown)
+	d.shutdown = nil
+	d.mtx.Unlock()
+	d.wg.Wait()
+}
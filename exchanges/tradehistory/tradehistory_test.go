@@ -0,0 +1,195 @@
+package tradehistory
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+var testPair = currency.NewPairWithDelimiter("BTC", "USD", "-")
+
+type testSource struct {
+	history  []exchange.OrderDetail
+	active   []exchange.OrderDetail
+	requests []exchange.GetOrdersRequest
+	err      error
+}
+
+func (t *testSource) GetName() string { return "Bitstamp" }
+
+func (t *testSource) GetEnabledCurrencies() currency.Pairs {
+	return currency.Pairs{testPair}
+}
+
+func (t *testSource) GetOrderHistory(req *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	t.requests = append(t.requests, *req)
+	return t.history, t.err
+}
+
+func (t *testSource) GetActiveOrders(req *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	return t.active, t.err
+}
+
+func testOrder(id string, side exchange.OrderSide, executed float64) exchange.OrderDetail {
+	return exchange.OrderDetail{
+		ID:             id,
+		CurrencyPair:   testPair,
+		OrderSide:      side,
+		OrderDate:      time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC),
+		Price:          10000,
+		Amount:         2,
+		ExecutedAmount: executed,
+		Fee:            5,
+	}
+}
+
+func testFill(orderID string, side exchange.OrderSide, amount float64, t time.Time) *Fill {
+	return &Fill{
+		Exchange:  "Bitstamp",
+		OrderID:   orderID,
+		Pair:      testPair,
+		Side:      side,
+		Price:     10000,
+		Amount:    amount,
+		Timestamp: t,
+	}
+}
+
+func TestStore(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tradehistory")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "fills.log")
+
+	s, err := NewStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Add(&Fill{Exchange: "Bitstamp"}); err != errOrderIDNotSet {
+		t.Error("Test Failed - Add() expected order ID error", err)
+	}
+	if err = s.Add(testFill("1", exchange.BuyOrderSide, 0, time.Now())); err != errInvalidAmount {
+		t.Error("Test Failed - Add() expected invalid amount error", err)
+	}
+	first := time.Date(2019, 7, 1, 0, 0, 0, 0, time.UTC)
+	for i, amount := range []float64{1, 0.5} {
+		err = s.Add(testFill("1", exchange.BuyOrderSide, amount, first.Add(time.Hour*time.Duration(i))))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	s, err = NewStore(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fills := s.Fills("bitstamp", time.Time{}); len(fills) != 2 || fills[1].Amount != 0.5 {
+		t.Errorf("Test Failed - NewStore() expected persisted fills %+v", fills)
+	}
+	if fills := s.Fills("Bitstamp", first.Add(time.Minute)); len(fills) != 1 {
+		t.Errorf("Test Failed - Fills() expected fills after start %+v", fills)
+	}
+	if !s.Latest("Bitstamp").Equal(first.Add(time.Hour)) || !s.Latest("Kraken").IsZero() {
+		t.Error("Test Failed - Latest() unexpected latest fill time")
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s, err := NewStore("")
+	if err != nil {
+		t.Fatal(err)
+	}
+	now := time.Date(2019, 7, 1, 12, 0, 0, 0, time.UTC)
+	recorded := now.Add(-time.Hour * 3)
+	for _, f := range []*Fill{
+		testFill("partial", exchange.BuyOrderSide, 1, recorded),
+		testFill("complete", exchange.BuyOrderSide, 2, recorded),
+		testFill("overfilled", exchange.SellOrderSide, 2, recorded),
+		testFill("side", exchange.BuyOrderSide, 1, recorded),
+	} {
+		if err = s.Add(f); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	missed := testOrder("missed", exchange.SellOrderSide, 1.5)
+	missed.Trades = []exchange.TradeHistory{
+		{TID: 7, Price: 10010, Amount: 1, Timestamp: recorded.Add(time.Minute)},
+	}
+	src := &testSource{
+		history: []exchange.OrderDetail{
+			testOrder("complete", exchange.BidOrderSide, 2),
+			testOrder("overfilled", exchange.AskOrderSide, 1),
+			testOrder("side", exchange.SellOrderSide, 1),
+			missed,
+		},
+		active: []exchange.OrderDetail{testOrder("partial", exchange.BuyOrderSide, 1.5)},
+	}
+	var events []Event
+	d, err := New(s, []Source{src}, Config{}, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	d.now = func() time.Time { return now }
+
+	// The first comparison starts an overlap before the latest recorded fill
+	d.Check()
+	if len(src.requests) != 1 || !src.requests[0].StartTicks.Equal(recorded.Add(-DefaultOverlap)) {
+		t.Fatalf("Test Failed - Check() unexpected history request %+v", src.requests)
+	}
+
+	fills := s.Fills("Bitstamp", time.Time{})
+	var backfilled []Fill
+	for i := range fills {
+		if fills[i].Backfilled {
+			backfilled = append(backfilled, fills[i])
+		}
+	}
+	if len(backfilled) != 3 {
+		t.Fatalf("Test Failed - Check() expected 3 backfilled fills %+v", backfilled)
+	}
+	if backfilled[0].OrderID != "missed" || backfilled[0].TradeID != "7" || backfilled[0].Price != 10010 ||
+		backfilled[1].OrderID != "missed" || backfilled[1].Amount != 0.5 || backfilled[1].Fee != 0 {
+		t.Errorf("Test Failed - Check() expected missed order backfilled by trade %+v", backfilled)
+	}
+	if backfilled[2].OrderID != "partial" || backfilled[2].Amount != 0.5 || backfilled[2].Side != exchange.BuyOrderSide {
+		t.Errorf("Test Failed - Check() expected partial fill backfilled %+v", backfilled[2])
+	}
+
+	r := d.GetReport()
+	if len(r.Exchanges) != 1 || r.Exchanges[0].Orders != 5 || r.Exchanges[0].Backfilled != 3 {
+		t.Errorf("Test Failed - GetReport() unexpected status %+v", r.Exchanges)
+	}
+	if len(r.Discrepancies) != 2 || r.Discrepancies[0].OrderID != "overfilled" ||
+		r.Discrepancies[1].OrderID != "side" {
+		t.Fatalf("Test Failed - GetReport() expected overfill and side discrepancies %+v", r.Discrepancies)
+	}
+	if len(events) != 5 {
+		t.Errorf("Test Failed - Check() expected 5 events %+v", events)
+	}
+
+	// Later comparisons start an overlap before the previous one, without
+	// backfilling or flagging again
+	now = now.Add(time.Minute * 15)
+	d.Check()
+	if !src.requests[1].StartTicks.Equal(now.Add(-time.Minute * 15).Add(-DefaultOverlap)) {
+		t.Errorf("Test Failed - Check() unexpected history request %+v", src.requests[1])
+	}
+	if len(events) != 5 || len(s.Fills("Bitstamp", time.Time{})) != 7 {
+		t.Errorf("Test Failed - Check() repeated events %+v", events)
+	}
+
+	src.err = errors.New("rate limited")
+	d.Check()
+	if r = d.GetReport(); r.Exchanges[0].Error != "rate limited" {
+		t.Errorf("Test Failed - Check() expected error status %+v", r.Exchanges)
+	}
+}
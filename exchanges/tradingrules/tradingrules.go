@@ -0,0 +1,161 @@
+// Package tradingrules rounds order prices and amounts to each exchange's
+// increments before they are submitted. Rules published by the exchange can
+// be overridden per exchange or per pair, and exchanges which do not publish
+// their rules are only rounded when an override is configured
+package tradingrules
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// DefaultCacheDuration is how long fetched exchange rules are reused before
+// they are requested again
+const DefaultCacheDuration = time.Hour
+
+type cachedRules struct {
+	rules   exchange.TradingRules
+	fetched time.Time
+}
+
+// Enforcer rounds orders of guarded exchanges to their trading rules
+type Enforcer struct {
+	cacheDuration time.Duration
+	overrides     map[string]exchange.TradingRules
+	cache         map[string]cachedRules
+	mtx           sync.Mutex
+}
+
+// New returns an enforcer applying the overrides on top of each exchange's
+// rules. Overrides are keyed by exchange name or by exchange name and pair in
+// the format Binance:BTC-USDT, pair overrides take precedence
+func New(overrides map[string]exchange.TradingRules) *Enforcer {
+	e := &Enforcer{
+		cacheDuration: DefaultCacheDuration,
+		overrides:     make(map[string]exchange.TradingRules),
+		cache:         make(map[string]cachedRules),
+	}
+	for k, v := range overrides {
+		e.overrides[strings.ToLower(k)] = v
+	}
+	return e
+}
+
+func pairKey(exchName string, p currency.Pair) string {
+	return strings.ToLower(exchName + ":" + p.Base.String() + "-" + p.Quote.String())
+}
+
+// GetRules returns the rules applied to orders of the pair on the exchange
+func (e *Enforcer) GetRules(ex exchange.IBotExchange, p currency.Pair) (exchange.TradingRules, error) {
+	name := ex.GetName()
+	k := pairKey(name, p)
+
+	var rules exchange.TradingRules
+	if provider, ok := exchange.Underlying(ex).(exchange.TradingRulesProvider); ok {
+		e.mtx.Lock()
+		c, ok := e.cache[k]
+		e.mtx.Unlock()
+		if ok && time.Since(c.fetched) < e.cacheDuration {
+			rules = c.rules
+		} else {
+			var err error
+			rules, err = provider.GetTradingRules(p)
+			if err != nil {
+				return exchange.TradingRules{}, err
+			}
+			e.mtx.Lock()
+			e.cache[k] = cachedRules{rules: rules, fetched: time.Now()}
+			e.mtx.Unlock()
+		}
+	}
+
+	if o, ok := e.overrides[strings.ToLower(name)]; ok {
+		rules = rules.Override(o)
+	}
+	if o, ok := e.overrides[k]; ok {
+		rules = rules.Override(o)
+	}
+	return rules, nil
+}
+
+// Guard wraps an exchange so submitted orders are rounded to its trading
+// rules
+func (e *Enforcer) Guard(ex exchange.IBotExchange) exchange.IBotExchange {
+	return &Guarded{IBotExchange: ex, enforcer: e}
+}
+
+// Guarded is an exchange whose orders are rounded to its trading rules
+type Guarded struct {
+	exchange.IBotExchange
+	enforcer *Enforcer
+}
+
+// Unwrap returns the underlying exchange
+func (g *Guarded) Unwrap() exchange.IBotExchange {
+	return g.IBotExchange
+}
+
+// SubmitOrder rounds the order's price and amount before submitting it.
+// Orders are submitted unchanged when the exchange's rules cannot be fetched
+func (g *Guarded) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	rules, err := g.enforcer.GetRules(g.IBotExchange, p)
+	if err != nil {
+		log.Warnf("%s %s trading rules unavailable, submitting order unrounded. Error: %s",
+			g.GetName(), p, err)
+		return g.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+	}
+
+	roundedAmount, roundedPrice, err := rules.Apply(side, orderType, amount, price)
+	if err != nil {
+		return exchange.SubmitOrderResponse{}, fmt.Errorf("%s %s %v", g.GetName(), p, err)
+	}
+	if roundedAmount != amount || roundedPrice != price {
+		log.Debugf("%s %s %s order rounded from %v @ %v to %v @ %v",
+			g.GetName(), p, side, amount, price, roundedAmount, roundedPrice)
+	}
+	return g.IBotExchange.SubmitOrder(p, side, orderType, roundedAmount, roundedPrice, clientID)
+}
+
+// CheckOrder rounds an order placed natively beneath the guard. Orders are
+// left unchanged when the exchange's rules cannot be fetched
+func (g *Guarded) CheckOrder(o *exchange.OrderSubmission) error {
+	rules, err := g.enforcer.GetRules(g.IBotExchange, o.Pair)
+	if err != nil {
+		log.Warnf("%s %s trading rules unavailable, submitting order unrounded. Error: %s",
+			g.GetName(), o.Pair, err)
+		return nil
+	}
+	amount, price, err := rules.Apply(o.Side, o.OrderType, o.Amount, o.Price)
+	if err != nil {
+		return fmt.Errorf("%s %s %v", g.GetName(), o.Pair, err)
+	}
+	o.Amount, o.Price = amount, price
+	return nil
+}
+
+// ModifyOrder rounds the amended price and amount. Passive price rounding
+// falls back to the nearest step when the amendment does not set the side
+func (g *Guarded) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
+	rules, err := g.enforcer.GetRules(g.IBotExchange, action.CurrencyPair)
+	if err == nil {
+		side := action.OrderSide
+		if side == "" && (rules.PriceRounding == "" || rules.PriceRounding == exchange.RoundPassive) {
+			rules.PriceRounding = exchange.RoundNearest
+		}
+		amended := *action
+		if amended.Price > 0 {
+			amended.Price = rules.RoundPrice(side, amended.Price)
+		}
+		if amended.Amount > 0 {
+			amended.Amount = rules.RoundAmount(side, amended.Amount)
+		}
+		action = &amended
+	}
+	return g.IBotExchange.ModifyOrder(action)
+}
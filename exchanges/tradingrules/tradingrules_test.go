@@ -0,0 +1,153 @@
+package tradingrules
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	amount float64
+	price  float64
+	action exchange.ModifyOrder
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, amount, price float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.amount = amount
+	t.price = price
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func (t *testExchange) ModifyOrder(action *exchange.ModifyOrder) (string, error) {
+	t.action = *action
+	return "1", nil
+}
+
+type testProvider struct {
+	testExchange
+	rules   exchange.TradingRules
+	err     error
+	fetches int
+}
+
+func (t *testProvider) GetTradingRules(_ currency.Pair) (exchange.TradingRules, error) {
+	t.fetches++
+	return t.rules, t.err
+}
+
+func TestGuardedSubmitOrder(t *testing.T) {
+	btc := currency.NewPairFromStrings("BTC", "USDT")
+	exch := &testProvider{rules: exchange.TradingRules{
+		PriceStep:   0.01,
+		AmountStep:  0.0001,
+		MinNotional: 10,
+	}}
+	e := New(nil)
+	g := e.Guard(exch)
+
+	_, err := g.SubmitOrder(btc, exchange.BuyOrderSide, exchange.LimitOrderType, 0.12345, 9000.019, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if exch.amount != 0.1234 || exch.price != 9000.01 {
+		t.Errorf("Test Failed - SubmitOrder() buy sent %v @ %v", exch.amount, exch.price)
+	}
+
+	_, err = g.SubmitOrder(btc, exchange.SellOrderSide, exchange.LimitOrderType, 0.12345, 9000.011, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if exch.amount != 0.1234 || exch.price != 9000.02 {
+		t.Errorf("Test Failed - SubmitOrder() sell sent %v @ %v", exch.amount, exch.price)
+	}
+	if exch.fetches != 1 {
+		t.Errorf("Test Failed - GetRules() expected cached rules, fetched %d times", exch.fetches)
+	}
+
+	exch.amount = 0
+	_, err = g.SubmitOrder(btc, exchange.BuyOrderSide, exchange.LimitOrderType, 0.001, 9000, "")
+	if err == nil {
+		t.Error("Test Failed - SubmitOrder() expected error below minimum notional")
+	}
+	if exch.amount != 0 {
+		t.Error("Test Failed - SubmitOrder() order below minimum was submitted")
+	}
+
+	_, err = g.ModifyOrder(&exchange.ModifyOrder{
+		CurrencyPair: btc,
+		Price:        9000.016,
+		Amount:       0.12345,
+	})
+	if err != nil {
+		t.Fatal("Test Failed - ModifyOrder() error", err)
+	}
+	if exch.action.Price != 9000.02 || exch.action.Amount != 0.1234 {
+		t.Errorf("Test Failed - ModifyOrder() sent %v @ %v",
+			exch.action.Amount, exch.action.Price)
+	}
+
+	if exchange.Underlying(g) != exch {
+		t.Error("Test Failed - Unwrap() did not return the underlying exchange")
+	}
+}
+
+func TestGuardedOverrides(t *testing.T) {
+	btc := currency.NewPairFromStrings("BTC", "USDT")
+	eth := currency.NewPairFromStrings("ETH", "USDT")
+	exch := &testProvider{rules: exchange.TradingRules{
+		PriceStep:  0.01,
+		AmountStep: 0.0001,
+	}}
+	e := New(map[string]exchange.TradingRules{
+		"TEST": {PriceRounding: exchange.RoundNearest},
+		"test:eth-usdt": {
+			AmountStep:     0.1,
+			AmountRounding: exchange.RoundUp,
+		},
+	})
+	g := e.Guard(exch)
+
+	_, err := g.SubmitOrder(btc, exchange.BuyOrderSide, exchange.LimitOrderType, 1, 100.006, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if exch.price != 100.01 {
+		t.Errorf("Test Failed - SubmitOrder() exchange override sent price %v", exch.price)
+	}
+
+	_, err = g.SubmitOrder(eth, exchange.BuyOrderSide, exchange.LimitOrderType, 1.01, 100.004, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if exch.amount != 1.1 || exch.price != 100 {
+		t.Errorf("Test Failed - SubmitOrder() pair override sent %v @ %v", exch.amount, exch.price)
+	}
+
+	// exchanges without published rules are only rounded by overrides
+	plain := &testExchange{}
+	g = e.Guard(plain)
+	_, err = g.SubmitOrder(btc, exchange.BuyOrderSide, exchange.LimitOrderType, 1.23456, 100.006, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if plain.amount != 1.23456 || plain.price != 100.006 {
+		t.Errorf("Test Failed - SubmitOrder() without rules sent %v @ %v", plain.amount, plain.price)
+	}
+
+	// orders are submitted unrounded when the rules cannot be fetched
+	failing := &testProvider{err: errors.New("exchange info unavailable")}
+	g = New(nil).Guard(failing)
+	_, err = g.SubmitOrder(btc, exchange.BuyOrderSide, exchange.LimitOrderType, 1.23456, 100.006, "")
+	if err != nil {
+		t.Fatal("Test Failed - SubmitOrder() error", err)
+	}
+	if failing.amount != 1.23456 || failing.price != 100.006 {
+		t.Errorf("Test Failed - SubmitOrder() with failing rules sent %v @ %v",
+			failing.amount, failing.price)
+	}
+}
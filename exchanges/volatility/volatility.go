@@ -0,0 +1,328 @@
+// Package volatility computes the realized volatility of each enabled pair
+// over several windows from stored candles, forming a term structure which is
+// refreshed periodically and streamed to subscribers. Estimates feed
+// volatility targeted order sizing and volatility scaled quoting spreads
+package volatility
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// Year is the period volatility is annualized over, crypto markets trade
+// continuously
+const Year = time.Hour * 24 * 365
+
+// Default service settings
+const (
+	DefaultCheckInterval = time.Minute * 5
+	// minReturns is the fewest returns an estimate is made from
+	minReturns = 2
+)
+
+// Window is a realized volatility window and the candle interval it is
+// measured with
+type Window struct {
+	Period   time.Duration
+	Interval time.Duration
+}
+
+// DefaultWindows measures 1h, 24h, 7d and 30d realized volatility
+var DefaultWindows = []Window{
+	{Period: time.Hour, Interval: time.Minute},
+	{Period: time.Hour * 24, Interval: time.Minute * 15},
+	{Period: time.Hour * 24 * 7, Interval: time.Hour},
+	{Period: time.Hour * 24 * 30, Interval: time.Hour * 4},
+}
+
+var (
+	errNoExchanges      = errors.New("no exchanges supplied")
+	errNilCandleFunc    = errors.New("no candle function supplied")
+	errInsufficientData = errors.New("insufficient candles for volatility estimate")
+	errPairNotTracked   = errors.New("pair volatility not tracked")
+	errWindowNotFound   = errors.New("volatility window not measured")
+)
+
+// Point is the realized volatility over one window
+type Point struct {
+	Window time.Duration `json:"window"`
+	// Annualized is the standard deviation of log returns scaled to a year
+	Annualized float64 `json:"annualized"`
+	// Period is the volatility over the window itself
+	Period  float64 `json:"period"`
+	Returns int     `json:"returns"`
+	Error   string  `json:"error,omitempty"`
+}
+
+// TermStructure holds a pair's realized volatility across windows, shortest
+// first
+type TermStructure struct {
+	Exchange string        `json:"exchange"`
+	Pair     currency.Pair `json:"pair"`
+	Points   []Point       `json:"points"`
+	Updated  time.Time     `json:"updated"`
+}
+
+// Get returns the annualized volatility of a window
+func (t *TermStructure) Get(window time.Duration) (float64, error) {
+	for i := range t.Points {
+		if t.Points[i].Window == window && t.Points[i].Error == "" {
+			return t.Points[i].Annualized, nil
+		}
+	}
+	return 0, fmt.Errorf("%s %s %s %v", t.Exchange, t.Pair, window, errWindowNotFound)
+}
+
+// Realized returns the realized volatility of the candles closing within the
+// window before the last candle. Candles must be ordered oldest first
+func Realized(candles []exchange.Candle, window time.Duration) (Point, error) {
+	p := Point{Window: window}
+	if len(candles) == 0 {
+		return p, errInsufficientData
+	}
+	start := candles[len(candles)-1].Time.Add(-window)
+	first := sort.Search(len(candles), func(i int) bool {
+		return !candles[i].Time.Before(start)
+	})
+	// The last candle before the window opens the first return
+	if first > 0 && candles[first].Time.After(start) {
+		first--
+	}
+	candles = candles[first:]
+
+	var returns []float64
+	for i := 1; i < len(candles); i++ {
+		if candles[i-1].Close <= 0 || candles[i].Close <= 0 {
+			continue
+		}
+		returns = append(returns, math.Log(candles[i].Close/candles[i-1].Close))
+	}
+	p.Returns = len(returns)
+	if len(returns) < minReturns {
+		return p, errInsufficientData
+	}
+
+	var mean float64
+	for i := range returns {
+		mean += returns[i]
+	}
+	mean /= float64(len(returns))
+	var variance float64
+	for i := range returns {
+		variance += (returns[i] - mean) * (returns[i] - mean)
+	}
+	variance /= float64(len(returns) - 1)
+
+	elapsed := candles[len(candles)-1].Time.Sub(candles[0].Time)
+	interval := elapsed / time.Duration(len(candles)-1)
+	if interval <= 0 {
+		return p, errInsufficientData
+	}
+	sd := math.Sqrt(variance)
+	p.Annualized = sd * math.Sqrt(float64(Year)/float64(interval))
+	p.Period = sd * math.Sqrt(float64(window)/float64(interval))
+	return p, nil
+}
+
+// Spread returns the fractional half spread of a quote which covers the
+// expected price move over the horizon a quote rests for, scaled by the
+// multiplier
+func Spread(annualized float64, horizon time.Duration, multiplier float64) float64 {
+	return annualized * math.Sqrt(float64(horizon)/float64(Year)) * multiplier
+}
+
+// TargetSize scales an order amount so the position carries the target
+// annualized volatility, capped at max times the amount when max is positive
+func TargetSize(amount, target, annualized, max float64) float64 {
+	if annualized <= 0 || target <= 0 {
+		return amount
+	}
+	scale := target / annualized
+	if max > 0 && scale > max {
+		scale = max
+	}
+	return amount * scale
+}
+
+// CandleFunc returns up to limit stored candles of a pair at the interval,
+// ordered oldest first
+type CandleFunc func(exchangeName string, p currency.Pair, interval time.Duration, limit int) ([]exchange.Candle, error)
+
+type key struct {
+	exchange string
+	pair     string
+}
+
+func newKey(exchangeName string, p currency.Pair) key {
+	return key{
+		exchange: strings.ToLower(exchangeName),
+		pair:     p.Base.Upper().String() + "-" + p.Quote.Upper().String(),
+	}
+}
+
+// Service periodically computes the term structure of every enabled pair
+type Service struct {
+	exchanges []exchange.IBotExchange
+	windows   []Window
+	candles   CandleFunc
+	onUpdate  func(TermStructure)
+	terms     map[key]TermStructure
+	shutdown  chan struct{}
+	wg        sync.WaitGroup
+	mtx       sync.Mutex
+}
+
+// New returns a volatility service measuring the windows, the default windows
+// are used when none are supplied. onUpdate receives every refreshed term
+// structure and may be nil
+func New(exchanges []exchange.IBotExchange, windows []Window, candles CandleFunc, onUpdate func(TermStructure)) (*Service, error) {
+	if len(exchanges) == 0 {
+		return nil, errNoExchanges
+	}
+	if candles == nil {
+		return nil, errNilCandleFunc
+	}
+	if len(windows) == 0 {
+		windows = DefaultWindows
+	}
+	windows = append([]Window(nil), windows...)
+	sort.Slice(windows, func(i, j int) bool {
+		return windows[i].Period < windows[j].Period
+	})
+	return &Service{
+		exchanges: exchanges,
+		windows:   windows,
+		candles:   candles,
+		onUpdate:  onUpdate,
+		terms:     make(map[key]TermStructure),
+	}, nil
+}
+
+// Measure computes the term structure of a pair. Windows which cannot be
+// measured are reported with their error
+func (s *Service) Measure(exchangeName string, p currency.Pair) TermStructure {
+	t := TermStructure{Exchange: exchangeName, Pair: p, Updated: time.Now()}
+	for _, w := range s.windows {
+		limit := int(w.Period/w.Interval) + 1
+		candles, err := s.candles(exchangeName, p, w.Interval, limit)
+		point := Point{Window: w.Period}
+		if err == nil {
+			point, err = Realized(candles, w.Period)
+		}
+		if err != nil {
+			point.Error = err.Error()
+		}
+		t.Points = append(t.Points, point)
+	}
+	return t
+}
+
+// Check refreshes the term structure of every enabled pair
+func (s *Service) Check() {
+	for i := range s.exchanges {
+		if !s.exchanges[i].IsEnabled() {
+			continue
+		}
+		name := s.exchanges[i].GetName()
+		pairs := s.exchanges[i].GetEnabledCurrencies()
+		for j := range pairs {
+			t := s.Measure(name, pairs[j])
+			s.mtx.Lock()
+			s.terms[newKey(name, pairs[j])] = t
+			s.mtx.Unlock()
+			if s.onUpdate != nil {
+				s.onUpdate(t)
+			}
+		}
+	}
+}
+
+// Get returns the latest term structure of a pair
+func (s *Service) Get(exchangeName string, p currency.Pair) (TermStructure, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	t, ok := s.terms[newKey(exchangeName, p)]
+	if !ok {
+		return TermStructure{}, fmt.Errorf("%s %s %v", exchangeName, p, errPairNotTracked)
+	}
+	return t, nil
+}
+
+// Annualized returns the latest annualized volatility of a pair over a window
+func (s *Service) Annualized(exchangeName string, p currency.Pair, window time.Duration) (float64, error) {
+	t, err := s.Get(exchangeName, p)
+	if err != nil {
+		return 0, err
+	}
+	return t.Get(window)
+}
+
+// GetAll returns the latest term structure of every pair sorted by exchange
+// and pair
+func (s *Service) GetAll() []TermStructure {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	resp := make([]TermStructure, 0, len(s.terms))
+	for _, t := range s.terms {
+		resp = append(resp, t)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		if resp[i].Exchange != resp[j].Exchange {
+			return resp[i].Exchange < resp[j].Exchange
+		}
+		return resp[i].Pair.String() < resp[j].Pair.String()
+	})
+	return resp
+}
+
+// Start refreshes term structures at the interval until stopped
+func (s *Service) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	s.mtx.Lock()
+	if s.shutdown != nil {
+		s.mtx.Unlock()
+		return
+	}
+	s.shutdown = make(chan struct{})
+	shutdown := s.shutdown
+	s.mtx.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		s.Check()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				s.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops refreshing term structures
+func (s *Service) Stop() {
+	s.mtx.Lock()
+	if s.shutdown == nil {
+		s.mtx.Unlock()
+		return
+	}
+	close(s.shutdown)
+	s.shutdown = nil
+	s.mtx.Unlock()
+	s.wg.Wait()
+}
@@ -0,0 +1,132 @@
+package volatility
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+}
+
+func (t *testExchange) GetName() string { return "test" }
+
+func (t *testExchange) IsEnabled() bool { return true }
+
+func (t *testExchange) GetEnabledCurrencies() currency.Pairs {
+	return currency.Pairs{currency.NewPairWithDelimiter("BTC", "USD", "-")}
+}
+
+// alternating returns candles whose closes alternate up and down by the same
+// log return
+func alternating(n int, interval time.Duration, r float64) []exchange.Candle {
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	candles := make([]exchange.Candle, n)
+	price := 100.0
+	for i := range candles {
+		if i > 0 {
+			if i%2 == 1 {
+				price *= math.Exp(r)
+			} else {
+				price *= math.Exp(-r)
+			}
+		}
+		candles[i] = exchange.Candle{Time: start.Add(interval * time.Duration(i)), Close: price}
+	}
+	return candles
+}
+
+func closeTo(a, b float64) bool {
+	return math.Abs(a-b) < 1e-9
+}
+
+func TestRealized(t *testing.T) {
+	candles := alternating(25, time.Hour, 0.01)
+	p, err := Realized(candles, time.Hour*24)
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 24 returns alternating +-1% have a sample deviation of 0.01*sqrt(24/23)
+	sd := 0.01 * math.Sqrt(24.0/23.0)
+	if p.Returns != 24 || !closeTo(p.Period, sd*math.Sqrt(24)) ||
+		!closeTo(p.Annualized, sd*math.Sqrt(24*365)) {
+		t.Errorf("Test Failed - Realized() unexpected estimate %+v", p)
+	}
+
+	// Only candles within the window are used
+	p, err = Realized(candles, time.Hour*4)
+	if err != nil || p.Returns != 4 {
+		t.Errorf("Test Failed - Realized() expected 4 returns %+v %v", p, err)
+	}
+	if _, err = Realized(candles[:2], time.Hour); err != errInsufficientData {
+		t.Error("Test Failed - Realized() expected insufficient data error", err)
+	}
+}
+
+func TestSpreadAndTargetSize(t *testing.T) {
+	if s := Spread(0.8, time.Hour*24*365/4, 1.5); !closeTo(s, 0.6) {
+		t.Errorf("Test Failed - Spread() expected 0.6, got %v", s)
+	}
+	if a := TargetSize(2, 0.4, 0.8, 0); a != 1 {
+		t.Errorf("Test Failed - TargetSize() expected 1, got %v", a)
+	}
+	if a := TargetSize(2, 0.8, 0.1, 2); a != 4 {
+		t.Errorf("Test Failed - TargetSize() expected scale capped at 2, got %v", a)
+	}
+	if a := TargetSize(2, 0.4, 0, 0); a != 2 {
+		t.Errorf("Test Failed - TargetSize() expected unscaled amount, got %v", a)
+	}
+}
+
+func TestService(t *testing.T) {
+	if _, err := New(nil, nil, nil, nil); err != errNoExchanges {
+		t.Error("Test Failed - New() expected no exchanges error", err)
+	}
+	exchanges := []exchange.IBotExchange{&testExchange{}}
+	if _, err := New(exchanges, nil, nil, nil); err != errNilCandleFunc {
+		t.Error("Test Failed - New() expected nil candle func error", err)
+	}
+
+	candles := func(_ string, _ currency.Pair, interval time.Duration, limit int) ([]exchange.Candle, error) {
+		if interval == time.Minute {
+			return nil, exchange.ErrCandleIntervalUnsupported
+		}
+		return alternating(limit, interval, 0.01), nil
+	}
+	var updates []TermStructure
+	s, err := New(exchanges, []Window{
+		{Period: time.Hour * 24, Interval: time.Hour},
+		{Period: time.Hour, Interval: time.Minute},
+	}, candles, func(t TermStructure) { updates = append(updates, t) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	p := currency.NewPairWithDelimiter("BTC", "USD", "-")
+	if _, err = s.Get("test", p); err == nil {
+		t.Error("Test Failed - Get() expected untracked pair error")
+	}
+
+	s.Check()
+	term, err := s.Get("TEST", p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(updates) != 1 || len(term.Points) != 2 || term.Points[0].Window != time.Hour ||
+		term.Points[0].Error == "" || term.Points[1].Returns != 24 {
+		t.Fatalf("Test Failed - Check() unexpected term structure %+v", term)
+	}
+	if _, err = s.Annualized("test", p, time.Hour); err == nil {
+		t.Error("Test Failed - Annualized() expected unmeasured window error")
+	}
+	vol, err := s.Annualized("test", p, time.Hour*24)
+	if err != nil || vol != term.Points[1].Annualized {
+		t.Error("Test Failed - Annualized() unexpected volatility", vol, err)
+	}
+	if all := s.GetAll(); len(all) != 1 {
+		t.Errorf("Test Failed - GetAll() expected one term structure %+v", all)
+	}
+}
@@ -0,0 +1,344 @@
+// Package volumeprofile aggregates traded volume by price bucket per session
+// for each pair from the normalised trade stream. Each session's point of
+// control, the price traded most, and its value area, the range around it
+// holding most of the session's volume, are exposed to strategies as support
+// and resistance levels
+package volumeprofile
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
+)
+
+// Default profile settings
+const (
+	// DefaultSession profiles each UTC day
+	DefaultSession = time.Hour * 24
+	// DefaultValueArea is the fraction of session volume the value area holds
+	DefaultValueArea = 0.7
+	// DefaultBucketBps is the bucket width in basis points of the price of
+	// the session's first trade
+	DefaultBucketBps = 10
+	// DefaultSessions is the number of completed sessions kept per pair
+	DefaultSessions = 5
+)
+
+var (
+	errInvalidSession   = errors.New("volume profile session must be positive")
+	errInvalidValueArea = errors.New("volume profile value area must be between 0 and 1")
+	errInvalidBucket    = errors.New("volume profile bucket width must be positive")
+	errInvalidSessions  = errors.New("volume profile sessions kept cannot be negative")
+	errInvalidTrade     = errors.New("volume profile trade price and amount must be positive")
+	errPairNotTracked   = errors.New("pair volume profile not tracked")
+)
+
+// Config defines how trades are aggregated, zero values are defaulted
+type Config struct {
+	// Session is the length of each profile, sessions are aligned to UTC
+	// midnight
+	Session   time.Duration
+	ValueArea float64
+	BucketBps float64
+	// Sessions is the number of completed sessions kept per pair
+	Sessions int
+}
+
+// Level holds the volume traded within a price bucket
+type Level struct {
+	// Price is the bucket's lower bound
+	Price      float64 `json:"price"`
+	Volume     float64 `json:"volume"`
+	BuyVolume  float64 `json:"buyVolume"`
+	SellVolume float64 `json:"sellVolume"`
+}
+
+// Profile holds the volume traded by price over a session
+type Profile struct {
+	Exchange   string        `json:"exchange"`
+	Pair       currency.Pair `json:"pair"`
+	Session    time.Time     `json:"session"`
+	End        time.Time     `json:"end"`
+	BucketSize float64       `json:"bucketSize"`
+	// Levels are ordered by price, lowest first
+	Levels []Level `json:"levels"`
+	Volume float64 `json:"volume"`
+	Trades int64   `json:"trades"`
+	// POC is the middle of the bucket with the most volume
+	POC float64 `json:"poc"`
+	// ValueAreaLow and ValueAreaHigh bound the buckets around the point of
+	// control holding the value area fraction of the session's volume
+	ValueAreaLow  float64   `json:"valueAreaLow"`
+	ValueAreaHigh float64   `json:"valueAreaHigh"`
+	Updated       time.Time `json:"updated"`
+}
+
+// String returns the profile's key levels
+func (p *Profile) String() string {
+	return fmt.Sprintf("%s %s session %s POC %v value area %v-%v volume %v",
+		p.Exchange, p.Pair, p.Session.Format(time.RFC3339), p.POC, p.ValueAreaLow, p.ValueAreaHigh, p.Volume)
+}
+
+// InValueArea returns whether the price is within the value area
+func (p *Profile) InValueArea(price float64) bool {
+	return p.Volume > 0 && price >= p.ValueAreaLow && price <= p.ValueAreaHigh
+}
+
+// BucketSize returns a round bucket width near the basis points of the price,
+// a one, two or five multiple of a power of ten
+func BucketSize(price, bps float64) float64 {
+	width := price * bps / 10000
+	if width <= 0 {
+		return 0
+	}
+	magnitude := math.Pow(10, math.Floor(math.Log10(width)))
+	for _, step := range []float64{1, 2, 5} {
+		if width <= step*magnitude {
+			return step * magnitude
+		}
+	}
+	return 10 * magnitude
+}
+
+// session aggregates the trades of a pair within a session
+type session struct {
+	exchange   string
+	pair       currency.Pair
+	start      time.Time
+	bucketSize float64
+	levels     map[int64]*Level
+	volume     float64
+	trades     int64
+	updated    time.Time
+}
+
+func (s *session) add(price, amount float64, side string) {
+	i := int64(math.Floor(price / s.bucketSize))
+	l, ok := s.levels[i]
+	if !ok {
+		l = &Level{Price: float64(i) * s.bucketSize}
+		s.levels[i] = l
+	}
+	l.Volume += amount
+	switch strings.ToLower(side) {
+	case "buy", "bid":
+		l.BuyVolume += amount
+	case "sell", "ask":
+		l.SellVolume += amount
+	}
+	s.volume += amount
+	s.trades++
+}
+
+// profile returns the session's profile and its value area
+func (s *session) profile(length time.Duration, valueArea float64) Profile {
+	p := Profile{
+		Exchange:   s.exchange,
+		Pair:       s.pair,
+		Session:    s.start,
+		End:        s.start.Add(length),
+		BucketSize: s.bucketSize,
+		Levels:     make([]Level, 0, len(s.levels)),
+		Volume:     s.volume,
+		Trades:     s.trades,
+		Updated:    s.updated,
+	}
+	for _, l := range s.levels {
+		p.Levels = append(p.Levels, *l)
+	}
+	sort.Slice(p.Levels, func(i, j int) bool { return p.Levels[i].Price < p.Levels[j].Price })
+	if len(p.Levels) == 0 {
+		return p
+	}
+
+	poc := 0
+	for i := range p.Levels {
+		if p.Levels[i].Volume > p.Levels[poc].Volume {
+			poc = i
+		}
+	}
+	// The value area grows from the point of control towards whichever
+	// neighbouring bucket traded more until it holds enough volume
+	low, high := poc, poc
+	volume := p.Levels[poc].Volume
+	for volume < valueArea*p.Volume && (low > 0 || high < len(p.Levels)-1) {
+		var below, above float64
+		if low > 0 {
+			below = p.Levels[low-1].Volume
+		}
+		if high < len(p.Levels)-1 {
+			above = p.Levels[high+1].Volume
+		}
+		if high < len(p.Levels)-1 && (above >= below || low == 0) {
+			high++
+			volume += above
+		} else {
+			low--
+			volume += below
+		}
+	}
+	p.POC = p.Levels[poc].Price + s.bucketSize/2
+	p.ValueAreaLow = p.Levels[low].Price
+	p.ValueAreaHigh = p.Levels[high].Price + s.bucketSize
+	return p
+}
+
+type key struct {
+	exchange string
+	pair     string
+}
+
+func newKey(exchangeName string, p currency.Pair) key {
+	return key{
+		exchange: strings.ToLower(exchangeName),
+		pair:     p.Base.Upper().String() + "-" + p.Quote.Upper().String(),
+	}
+}
+
+// pairProfiles holds the current session of a pair and its completed
+// sessions, newest first
+type pairProfiles struct {
+	current   *session
+	completed []Profile
+}
+
+// Service aggregates the trade stream into volume profiles
+type Service struct {
+	cfg      Config
+	onClose  func(Profile)
+	profiles map[key]*pairProfiles
+	mtx      sync.Mutex
+}
+
+// New returns a volume profile service. onClose receives each session's
+// profile once a trade from a later session arrives and may be nil
+func New(cfg Config, onClose func(Profile)) (*Service, error) {
+	if cfg.Session == 0 {
+		cfg.Session = DefaultSession
+	}
+	if cfg.ValueArea == 0 {
+		cfg.ValueArea = DefaultValueArea
+	}
+	if cfg.BucketBps == 0 {
+		cfg.BucketBps = DefaultBucketBps
+	}
+	if cfg.Sessions == 0 {
+		cfg.Sessions = DefaultSessions
+	}
+	switch {
+	case cfg.Session < 0:
+		return nil, errInvalidSession
+	case cfg.ValueArea < 0 || cfg.ValueArea > 1:
+		return nil, errInvalidValueArea
+	case cfg.BucketBps < 0:
+		return nil, errInvalidBucket
+	case cfg.Sessions < 0:
+		return nil, errInvalidSessions
+	}
+	return &Service{
+		cfg:      cfg,
+		onClose:  onClose,
+		profiles: make(map[key]*pairProfiles),
+	}, nil
+}
+
+// Add aggregates a trade into its pair's session profile. Trades belonging to
+// a session already closed are ignored
+func (s *Service) Add(t *wshandler.TradeData) error {
+	if t.Price <= 0 || t.Amount <= 0 {
+		return fmt.Errorf("%s %s %v", t.Exchange, t.CurrencyPair, errInvalidTrade)
+	}
+	ts := t.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	start := ts.UTC().Truncate(s.cfg.Session)
+
+	s.mtx.Lock()
+	k := newKey(t.Exchange, t.CurrencyPair)
+	p, ok := s.profiles[k]
+	if !ok {
+		p = &pairProfiles{}
+		s.profiles[k] = p
+	}
+	var closed *Profile
+	if p.current != nil && start.Before(p.current.start) {
+		s.mtx.Unlock()
+		return nil
+	}
+	if p.current == nil || start.After(p.current.start) {
+		if p.current != nil {
+			prev := p.current.profile(s.cfg.Session, s.cfg.ValueArea)
+			closed = &prev
+			p.completed = append([]Profile{prev}, p.completed...)
+			if len(p.completed) > s.cfg.Sessions {
+				p.completed = p.completed[:s.cfg.Sessions]
+			}
+		}
+		p.current = &session{
+			exchange:   t.Exchange,
+			pair:       t.CurrencyPair,
+			start:      start,
+			bucketSize: BucketSize(t.Price, s.cfg.BucketBps),
+			levels:     make(map[int64]*Level),
+		}
+	}
+	p.current.add(t.Price, t.Amount, t.Side)
+	p.current.updated = ts
+	s.mtx.Unlock()
+
+	if closed != nil && s.onClose != nil {
+		s.onClose(*closed)
+	}
+	return nil
+}
+
+// Get returns the profile of a pair's current session
+func (s *Service) Get(exchangeName string, p currency.Pair) (Profile, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	pp, ok := s.profiles[newKey(exchangeName, p)]
+	if !ok {
+		return Profile{}, fmt.Errorf("%s %s %v", exchangeName, p, errPairNotTracked)
+	}
+	return pp.current.profile(s.cfg.Session, s.cfg.ValueArea), nil
+}
+
+// GetSessions returns the profiles of a pair's current session followed by
+// its completed sessions, newest first
+func (s *Service) GetSessions(exchangeName string, p currency.Pair) ([]Profile, error) {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	pp, ok := s.profiles[newKey(exchangeName, p)]
+	if !ok {
+		return nil, fmt.Errorf("%s %s %v", exchangeName, p, errPairNotTracked)
+	}
+	resp := make([]Profile, 0, len(pp.completed)+1)
+	resp = append(resp, pp.current.profile(s.cfg.Session, s.cfg.ValueArea))
+	return append(resp, pp.completed...), nil
+}
+
+// GetAll returns the current session profile of every pair sorted by
+// exchange and pair
+func (s *Service) GetAll() []Profile {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	resp := make([]Profile, 0, len(s.profiles))
+	for _, pp := range s.profiles {
+		resp = append(resp, pp.current.profile(s.cfg.Session, s.cfg.ValueArea))
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		if resp[i].Exchange != resp[j].Exchange {
+			return resp[i].Exchange < resp[j].Exchange
+		}
+		return resp[i].Pair.String() < resp[j].Pair.String()
+	})
+	return resp
+}
@@ -0,0 +1,142 @@
+package volumeprofile
+
+import (
+	"math"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
+)
+
+var testPair = currency.NewPair(currency.BTC, currency.USD)
+
+func testTrade(ts time.Time, price, amount float64, side string) *wshandler.TradeData {
+	return &wshandler.TradeData{
+		Timestamp:    ts,
+		CurrencyPair: testPair,
+		Exchange:     "Bitstamp",
+		Price:        price,
+		Amount:       amount,
+		Side:         side,
+	}
+}
+
+func TestBucketSize(t *testing.T) {
+	for _, tc := range []struct {
+		price, bps, expected float64
+	}{
+		{8000, 10, 10},
+		{8000, 5, 5},
+		{0.03, 10, 0.00005},
+		{250, 10, 0.5},
+		{1100, 10, 2},
+		{0, 10, 0},
+	} {
+		if size := BucketSize(tc.price, tc.bps); math.Abs(size-tc.expected) > tc.expected*1e-9 {
+			t.Errorf("Test Failed - BucketSize(%v, %v) expected %v, got %v", tc.price, tc.bps, tc.expected, size)
+		}
+	}
+}
+
+func TestNew(t *testing.T) {
+	s, err := New(Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s.cfg.Session != DefaultSession || s.cfg.ValueArea != DefaultValueArea ||
+		s.cfg.BucketBps != DefaultBucketBps || s.cfg.Sessions != DefaultSessions {
+		t.Errorf("Test Failed - New() config not defaulted %+v", s.cfg)
+	}
+	if _, err = New(Config{ValueArea: 1.5}, nil); err != errInvalidValueArea {
+		t.Error("Test Failed - New() expected invalid value area error", err)
+	}
+	if _, err = New(Config{Session: -time.Hour}, nil); err != errInvalidSession {
+		t.Error("Test Failed - New() expected invalid session error", err)
+	}
+}
+
+func TestProfile(t *testing.T) {
+	s, err := New(Config{}, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	day := time.Date(2019, 6, 1, 9, 0, 0, 0, time.UTC)
+	// 10 USD buckets from the first trade at 8000
+	for _, tr := range []struct {
+		price, amount float64
+		side          string
+	}{
+		{8000, 1, "buy"},
+		{8005, 5, "sell"},
+		{8012, 3, "buy"},
+		{7995, 2, "sell"},
+		{8025, 0.5, ""},
+		{7981, 0.5, "buy"},
+	} {
+		if err = s.Add(testTrade(day, tr.price, tr.amount, tr.side)); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if err = s.Add(testTrade(day, 8000, 0, "buy")); err == nil {
+		t.Error("Test Failed - Add() expected invalid trade error")
+	}
+
+	p, err := s.Get("bitstamp", currency.NewPairWithDelimiter("btc", "usd", "-"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(p.Levels) != 5 || p.Volume != 12 || p.Trades != 6 || p.BucketSize != 10 {
+		t.Fatalf("Test Failed - Get() unexpected profile %+v", p)
+	}
+	if p.Levels[2].Price != 8000 || p.Levels[2].Volume != 6 || p.Levels[2].BuyVolume != 1 || p.Levels[2].SellVolume != 5 {
+		t.Errorf("Test Failed - Get() unexpected level %+v", p.Levels[2])
+	}
+	// 6 at 8000 then the 3 above reaches 70% of 12
+	if p.POC != 8005 || p.ValueAreaLow != 8000 || p.ValueAreaHigh != 8020 {
+		t.Errorf("Test Failed - Get() unexpected key levels %s", p.String())
+	}
+	if !p.InValueArea(8015) || p.InValueArea(8021) {
+		t.Error("Test Failed - InValueArea() unexpected result")
+	}
+	if !p.Session.Equal(time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Test Failed - Get() session not aligned to the day %s", p.Session)
+	}
+
+	if _, err = s.Get("Bitstamp", currency.NewPair(currency.ETH, currency.USD)); err == nil {
+		t.Error("Test Failed - Get() expected pair not tracked error")
+	}
+}
+
+func TestSessions(t *testing.T) {
+	var closed []Profile
+	s, err := New(Config{Session: time.Hour, Sessions: 2}, func(p Profile) { closed = append(closed, p) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	start := time.Date(2019, 6, 1, 0, 30, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		err = s.Add(testTrade(start.Add(time.Hour*time.Duration(i)), 8000+float64(i)*100, 1, "buy"))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	// late trades for closed sessions are ignored
+	if err = s.Add(testTrade(start, 1, 1, "buy")); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(closed) != 3 || closed[0].POC != 8005 {
+		t.Fatalf("Test Failed - New() unexpected closed sessions %+v", closed)
+	}
+	sessions, err := s.GetSessions("Bitstamp", testPair)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(sessions) != 3 || sessions[0].Volume != 1 || sessions[0].POC != 8305 || sessions[2].POC != 8105 {
+		t.Errorf("Test Failed - GetSessions() unexpected sessions %+v", sessions)
+	}
+	if all := s.GetAll(); len(all) != 1 || all[0].POC != 8305 {
+		t.Errorf("Test Failed - GetAll() unexpected profiles %+v", all)
+	}
+}
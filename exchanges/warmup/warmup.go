@@ -0,0 +1,289 @@
+// Package warmup coordinates the cold start of market data before strategies
+// activate. Historical candles are preloaded for every enabled pair, used to
+// prime registered indicators and websocket feeds are verified as connected
+// before a single ready event is emitted
+package warmup
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default warmup settings
+const (
+	DefaultCandles     = 200
+	DefaultInterval    = time.Minute * 5
+	DefaultAssetType   = "SPOT"
+	DefaultFeedTimeout = time.Second * 30
+
+	feedCheckInterval = time.Millisecond * 250
+)
+
+var (
+	errNoExchanges      = errors.New("warmup no exchanges supplied")
+	errFeedsUnhealthy   = errors.New("warmup websocket feeds unhealthy")
+	errNotReady         = errors.New("warmup timed out waiting for ready")
+	errNilIndicator     = errors.New("warmup indicator is nil")
+	errAlreadyCompleted = errors.New("warmup already completed")
+)
+
+// Config defines the warmup behaviour
+type Config struct {
+	// Candles is the number of historical candles preloaded per pair
+	Candles   int
+	Interval  time.Duration
+	AssetType string
+	// FeedTimeout is how long websocket feeds are given to connect
+	FeedTimeout time.Duration
+}
+
+// Indicator is primed with historical candles during warmup
+type Indicator interface {
+	Update(c exchange.Candle)
+}
+
+// PairStatus holds the candles preloaded for a pair
+type PairStatus struct {
+	Exchange string
+	Pair     currency.Pair
+	Candles  int
+	Error    string `json:",omitempty"`
+}
+
+// FeedStatus holds the health of an exchange websocket feed
+type FeedStatus struct {
+	Exchange string
+	Healthy  bool
+}
+
+// Status holds the outcome of the warmup
+type Status struct {
+	Ready     bool
+	Completed time.Time
+	Pairs     []PairStatus
+	Feeds     []FeedStatus
+}
+
+type key struct {
+	exchange string
+	base     string
+	quote    string
+}
+
+func newKey(exchangeName string, p currency.Pair) key {
+	return key{
+		exchange: strings.ToLower(exchangeName),
+		base:     p.Base.Upper().String(),
+		quote:    p.Quote.Upper().String(),
+	}
+}
+
+// Coordinator runs the warmup and signals strategies once market data is ready
+type Coordinator struct {
+	exchanges  []exchange.IBotExchange
+	cfg        Config
+	onReady    func(Status)
+	indicators map[key][]Indicator
+	candles    map[key][]exchange.Candle
+	status     Status
+	ready      chan struct{}
+	mtx        sync.Mutex
+}
+
+// New returns a warmup coordinator. onReady is called once when the warmup
+// completes and may be nil
+func New(exchanges []exchange.IBotExchange, cfg Config, onReady func(Status)) (*Coordinator, error) {
+	if len(exchanges) == 0 {
+		return nil, errNoExchanges
+	}
+	if cfg.Candles <= 0 {
+		cfg.Candles = DefaultCandles
+	}
+	if cfg.Interval <= 0 {
+		cfg.Interval = DefaultInterval
+	}
+	if cfg.AssetType == "" {
+		cfg.AssetType = DefaultAssetType
+	}
+	if cfg.FeedTimeout <= 0 {
+		cfg.FeedTimeout = DefaultFeedTimeout
+	}
+	return &Coordinator{
+		exchanges:  exchanges,
+		cfg:        cfg,
+		onReady:    onReady,
+		indicators: make(map[key][]Indicator),
+		candles:    make(map[key][]exchange.Candle),
+		ready:      make(chan struct{}),
+	}, nil
+}
+
+// AddIndicator registers an indicator to be primed with the pair's candles.
+// Indicators added after the warmup completes are primed immediately
+func (c *Coordinator) AddIndicator(exchangeName string, p currency.Pair, ind Indicator) error {
+	if ind == nil {
+		return errNilIndicator
+	}
+	k := newKey(exchangeName, p)
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	c.indicators[k] = append(c.indicators[k], ind)
+	if c.status.Ready {
+		for i := range c.candles[k] {
+			ind.Update(c.candles[k][i])
+		}
+	}
+	return nil
+}
+
+// Run preloads candles, primes indicators and verifies websocket feeds. The
+// ready event is emitted once every enabled websocket feed is connected.
+// Pairs on exchanges without historical candles are reported but do not
+// prevent the warmup from completing
+func (c *Coordinator) Run() (Status, error) {
+	c.mtx.Lock()
+	if c.status.Ready {
+		c.mtx.Unlock()
+		return c.GetStatus(), errAlreadyCompleted
+	}
+	c.mtx.Unlock()
+
+	var pairs []PairStatus
+	for i := range c.exchanges {
+		if !c.exchanges[i].IsEnabled() {
+			continue
+		}
+		enabled := c.exchanges[i].GetEnabledCurrencies()
+		for j := range enabled {
+			pairs = append(pairs, c.loadCandles(c.exchanges[i], enabled[j]))
+		}
+	}
+
+	feeds, healthy := c.checkFeeds()
+
+	c.mtx.Lock()
+	c.status.Pairs = pairs
+	c.status.Feeds = feeds
+	if !healthy {
+		c.mtx.Unlock()
+		return c.GetStatus(), errFeedsUnhealthy
+	}
+	for k, candles := range c.candles {
+		for _, ind := range c.indicators[k] {
+			for i := range candles {
+				ind.Update(candles[i])
+			}
+		}
+	}
+	c.status.Ready = true
+	c.status.Completed = time.Now()
+	close(c.ready)
+	c.mtx.Unlock()
+
+	status := c.GetStatus()
+	if c.onReady != nil {
+		c.onReady(status)
+	}
+	return status, nil
+}
+
+// loadCandles preloads the historical candles for a pair
+func (c *Coordinator) loadCandles(e exchange.IBotExchange, p currency.Pair) PairStatus {
+	s := PairStatus{Exchange: e.GetName(), Pair: p}
+	provider, ok := exchange.Underlying(e).(exchange.CandleProvider)
+	if !ok {
+		s.Error = "historical candles not supported"
+		return s
+	}
+
+	candles, err := provider.GetHistoricCandles(p, c.cfg.AssetType, kline.Interval(c.cfg.Interval), c.cfg.Candles)
+	if err != nil {
+		log.Warnf("Warmup failed to load %s %s candles: %s", s.Exchange, p, err)
+		s.Error = err.Error()
+		return s
+	}
+	if len(candles) > c.cfg.Candles {
+		candles = candles[len(candles)-c.cfg.Candles:]
+	}
+	s.Candles = len(candles)
+
+	c.mtx.Lock()
+	c.candles[newKey(s.Exchange, p)] = candles
+	c.mtx.Unlock()
+	return s
+}
+
+// checkFeeds waits for every enabled websocket feed to connect
+func (c *Coordinator) checkFeeds() ([]FeedStatus, bool) {
+	var feeds []FeedStatus
+	deadline := time.Now().Add(c.cfg.FeedTimeout)
+	healthy := true
+	for i := range c.exchanges {
+		if !c.exchanges[i].IsEnabled() {
+			continue
+		}
+		ws, err := c.exchanges[i].GetWebsocket()
+		if err != nil || ws == nil || !ws.IsEnabled() {
+			continue
+		}
+		for !ws.IsConnected() && time.Now().Before(deadline) {
+			time.Sleep(feedCheckInterval)
+		}
+		s := FeedStatus{Exchange: c.exchanges[i].GetName(), Healthy: ws.IsConnected()}
+		if !s.Healthy {
+			log.Warnf("Warmup %s websocket feed is not connected", s.Exchange)
+			healthy = false
+		}
+		feeds = append(feeds, s)
+	}
+	return feeds, healthy
+}
+
+// Ready returns a channel which is closed once the warmup completes
+func (c *Coordinator) Ready() <-chan struct{} {
+	return c.ready
+}
+
+// Wait blocks until the warmup completes or the timeout elapses
+func (c *Coordinator) Wait(timeout time.Duration) error {
+	t := time.NewTimer(timeout)
+	defer t.Stop()
+	select {
+	case <-c.ready:
+		return nil
+	case <-t.C:
+		return fmt.Errorf("%v after %s", errNotReady, timeout)
+	}
+}
+
+// IsReady returns whether the warmup has completed
+func (c *Coordinator) IsReady() bool {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return c.status.Ready
+}
+
+// GetCandles returns the candles preloaded for a pair
+func (c *Coordinator) GetCandles(exchangeName string, p currency.Pair) []exchange.Candle {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	return append([]exchange.Candle(nil), c.candles[newKey(exchangeName, p)]...)
+}
+
+// GetStatus returns the warmup status
+func (c *Coordinator) GetStatus() Status {
+	c.mtx.Lock()
+	defer c.mtx.Unlock()
+	s := c.status
+	s.Pairs = append([]PairStatus(nil), c.status.Pairs...)
+	s.Feeds = append([]FeedStatus(nil), c.status.Feeds...)
+	return s
+}
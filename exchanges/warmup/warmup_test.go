@@ -0,0 +1,187 @@
+package warmup
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
+)
+
+var testPair = currency.NewPairWithDelimiter("BTC", "USD", "-")
+
+type testExchange struct {
+	exchange.IBotExchange
+	name string
+	ws   *wshandler.Websocket
+}
+
+func (t *testExchange) GetName() string { return t.name }
+func (t *testExchange) IsEnabled() bool { return true }
+func (t *testExchange) GetEnabledCurrencies() currency.Pairs {
+	return currency.Pairs{testPair}
+}
+
+func (t *testExchange) GetWebsocket() (*wshandler.Websocket, error) {
+	if t.ws == nil {
+		return nil, common.ErrFunctionNotSupported
+	}
+	return t.ws, nil
+}
+
+type testProvider struct {
+	testExchange
+	candles []exchange.Candle
+	err     error
+}
+
+func (t *testProvider) GetHistoricCandles(p currency.Pair, assetType string, interval kline.Interval, limit int) ([]exchange.Candle, error) {
+	return t.candles, t.err
+}
+
+func newTestProvider(name string, n int) *testProvider {
+	p := &testProvider{testExchange: testExchange{name: name}}
+	start := time.Now().Add(-time.Duration(n) * time.Minute)
+	for i := 0; i < n; i++ {
+		p.candles = append(p.candles, exchange.Candle{
+			Time:  start.Add(time.Duration(i) * time.Minute),
+			Close: float64(i + 1),
+		})
+	}
+	return p
+}
+
+type testIndicator struct {
+	closes []float64
+}
+
+func (t *testIndicator) Update(c exchange.Candle) {
+	t.closes = append(t.closes, c.Close)
+}
+
+func TestNew(t *testing.T) {
+	_, err := New(nil, Config{}, nil)
+	if err != errNoExchanges {
+		t.Error("Test Failed - New() expected no exchanges error", err)
+	}
+
+	c, err := New([]exchange.IBotExchange{&testExchange{name: "test"}}, Config{}, nil)
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+	if c.cfg.Candles != DefaultCandles ||
+		c.cfg.Interval != DefaultInterval ||
+		c.cfg.AssetType != DefaultAssetType ||
+		c.cfg.FeedTimeout != DefaultFeedTimeout {
+		t.Errorf("Test Failed - New() defaults not applied %+v", c.cfg)
+	}
+}
+
+func TestRun(t *testing.T) {
+	failing := newTestProvider("failing", 0)
+	failing.err = errors.New("unavailable")
+	exchanges := []exchange.IBotExchange{
+		exchange.NewReadOnly(newTestProvider("candles", 10)),
+		&testExchange{name: "plain"},
+		failing,
+	}
+
+	var readyCalls int
+	c, err := New(exchanges, Config{Candles: 5}, func(Status) { readyCalls++ })
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+
+	ind := &testIndicator{}
+	if err = c.AddIndicator("CANDLES", testPair, ind); err != nil {
+		t.Fatal("Test Failed - AddIndicator() error", err)
+	}
+	if err = c.AddIndicator("candles", testPair, nil); err != errNilIndicator {
+		t.Error("Test Failed - AddIndicator() expected nil indicator error", err)
+	}
+	if c.Wait(time.Millisecond) == nil {
+		t.Error("Test Failed - Wait() expected timeout before warmup")
+	}
+
+	status, err := c.Run()
+	if err != nil {
+		t.Fatal("Test Failed - Run() error", err)
+	}
+	if !status.Ready || !c.IsReady() || readyCalls != 1 {
+		t.Error("Test Failed - Run() warmup not marked ready")
+	}
+	if err = c.Wait(time.Millisecond); err != nil {
+		t.Error("Test Failed - Wait() error", err)
+	}
+	if len(status.Pairs) != 3 {
+		t.Fatalf("Test Failed - Run() expected 3 pair statuses, got %d", len(status.Pairs))
+	}
+	if status.Pairs[0].Candles != 5 || status.Pairs[1].Error == "" || status.Pairs[2].Error == "" {
+		t.Errorf("Test Failed - Run() unexpected pair statuses %+v", status.Pairs)
+	}
+
+	// Only the most recent candles are kept and indicators receive them in
+	// order
+	candles := c.GetCandles("candles", testPair)
+	if len(candles) != 5 || candles[0].Close != 6 || candles[4].Close != 10 {
+		t.Errorf("Test Failed - GetCandles() unexpected candles %+v", candles)
+	}
+	if len(ind.closes) != 5 || ind.closes[0] != 6 {
+		t.Errorf("Test Failed - Run() indicator not primed %v", ind.closes)
+	}
+
+	late := &testIndicator{}
+	if err = c.AddIndicator("candles", testPair, late); err != nil {
+		t.Fatal("Test Failed - AddIndicator() error", err)
+	}
+	if len(late.closes) != 5 {
+		t.Error("Test Failed - AddIndicator() late indicator not primed")
+	}
+
+	if _, err = c.Run(); err != errAlreadyCompleted {
+		t.Error("Test Failed - Run() expected already completed error", err)
+	}
+	if readyCalls != 1 {
+		t.Error("Test Failed - Run() ready emitted more than once")
+	}
+}
+
+func TestRunUnhealthyFeed(t *testing.T) {
+	ws := wshandler.New()
+	err := ws.Setup(func() error { return nil },
+		nil,
+		nil,
+		"test",
+		true,
+		false,
+		"",
+		"",
+		false)
+	if err != nil {
+		t.Fatal("Test Failed - Websocket Setup() error", err)
+	}
+
+	p := newTestProvider("test", 3)
+	p.ws = ws
+	c, err := New([]exchange.IBotExchange{p},
+		Config{FeedTimeout: time.Millisecond * 10},
+		func(Status) { t.Error("Test Failed - Run() ready emitted with unhealthy feed") })
+	if err != nil {
+		t.Fatal("Test Failed - New() error", err)
+	}
+
+	status, err := c.Run()
+	if err != errFeedsUnhealthy {
+		t.Error("Test Failed - Run() expected unhealthy feeds error", err)
+	}
+	if status.Ready || c.IsReady() {
+		t.Error("Test Failed - Run() warmup marked ready with unhealthy feed")
+	}
+	if len(status.Feeds) != 1 || status.Feeds[0].Healthy {
+		t.Errorf("Test Failed - Run() unexpected feed statuses %+v", status.Feeds)
+	}
+}
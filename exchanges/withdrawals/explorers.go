@@ -0,0 +1,115 @@
+package withdrawals
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+)
+
+const (
+	blockstreamAPIURL = "https://blockstream.info/api"
+	// blockstreamFeeTarget is the confirmation target in blocks whose fee
+	// estimate is used as the network fee rate
+	blockstreamFeeTarget = "6"
+
+	ethplorerAPIURL = "https://api.ethplorer.io"
+)
+
+// DefaultExplorers returns the explorers of the currencies which can be
+// followed on chain
+func DefaultExplorers() map[string]Explorer {
+	return map[string]Explorer{
+		"BTC": &Blockstream{},
+		"ETH": &Ethplorer{},
+	}
+}
+
+// Blockstream looks up bitcoin transactions on the Blockstream explorer
+type Blockstream struct {
+	// APIURL overrides the explorer URL, for testnet or a self hosted
+	// instance
+	APIURL string
+}
+
+func (b *Blockstream) url() string {
+	if b.APIURL != "" {
+		return b.APIURL
+	}
+	return blockstreamAPIURL
+}
+
+// GetTransaction returns the transaction's confirmations and fee per virtual
+// byte along with the fee rate currently needed to confirm within six blocks
+func (b *Blockstream) GetTransaction(txid string) (Transaction, error) {
+	var tx struct {
+		Fee    float64 `json:"fee"`
+		Weight float64 `json:"weight"`
+		Status struct {
+			Confirmed   bool  `json:"confirmed"`
+			BlockHeight int64 `json:"block_height"`
+		} `json:"status"`
+	}
+	err := common.SendHTTPGetRequest(fmt.Sprintf("%s/tx/%s", b.url(), txid), true, false, &tx)
+	if err != nil {
+		if strings.Contains(err.Error(), "404") {
+			return Transaction{}, ErrTxNotFound
+		}
+		return Transaction{}, err
+	}
+
+	var resp Transaction
+	if tx.Weight > 0 {
+		resp.FeeRate = tx.Fee / (tx.Weight / 4)
+	}
+	if tx.Status.Confirmed {
+		var tip int64
+		err = common.SendHTTPGetRequest(b.url()+"/blocks/tip/height", true, false, &tip)
+		if err != nil {
+			return Transaction{}, err
+		}
+		resp.Confirmations = int(tip-tx.Status.BlockHeight) + 1
+		return resp, nil
+	}
+
+	var estimates map[string]float64
+	err = common.SendHTTPGetRequest(b.url()+"/fee-estimates", true, false, &estimates)
+	if err != nil {
+		return Transaction{}, err
+	}
+	resp.NetworkFeeRate = estimates[blockstreamFeeTarget]
+	return resp, nil
+}
+
+// Ethplorer looks up ethereum transactions on the Ethplorer explorer, which
+// reports confirmations but not fee rates
+type Ethplorer struct {
+	APIKey string
+}
+
+// GetTransaction returns the transaction's confirmations
+func (e *Ethplorer) GetTransaction(txid string) (Transaction, error) {
+	key := e.APIKey
+	if key == "" {
+		key = "freekey"
+	}
+	var tx struct {
+		Confirmations int `json:"confirmations"`
+		Error         struct {
+			Code    int    `json:"code"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	err := common.SendHTTPGetRequest(fmt.Sprintf("%s/getTxInfo/%s?apiKey=%s",
+		ethplorerAPIURL, txid, key), true, false, &tx)
+	if err != nil {
+		return Transaction{}, err
+	}
+	if tx.Error.Message != "" {
+		if strings.Contains(strings.ToLower(tx.Error.Message), "not found") {
+			return Transaction{}, ErrTxNotFound
+		}
+		return Transaction{}, fmt.Errorf("ethplorer: %s", tx.Error.Message)
+	}
+	return Transaction{Confirmations: tx.Confirmations}, nil
+}
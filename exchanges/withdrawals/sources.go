@@ -0,0 +1,133 @@
+package withdrawals
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/poloniex"
+)
+
+const (
+	// poloniexComplete prefixes the status of broadcast Poloniex
+	// withdrawals, followed by the transaction hash
+	poloniexComplete = "COMPLETE"
+	poloniexCanceled = "CANCELED"
+
+	krakenFailure = "Failure"
+	// krakenStakedSuffix marks Kraken balances held in staking which cannot
+	// be withdrawn
+	krakenStakedSuffix = ".S"
+)
+
+// PoloniexWithdrawals reports Poloniex withdrawals, whose transaction hash is
+// included in their status once broadcast
+type PoloniexWithdrawals struct {
+	Exchange *poloniex.Poloniex
+}
+
+// GetName returns the exchange name
+func (p *PoloniexWithdrawals) GetName() string {
+	return p.Exchange.GetName()
+}
+
+// GetWithdrawals returns withdrawals made since the time
+func (p *PoloniexWithdrawals) GetWithdrawals(since time.Time) ([]Withdrawal, error) {
+	resp, err := p.Exchange.GetDepositsWithdrawals(strconv.FormatInt(since.Unix(), 10), "")
+	if err != nil {
+		return nil, err
+	}
+	withdrawals := make([]Withdrawal, 0, len(resp.Withdrawals))
+	for i := range resp.Withdrawals {
+		w := &resp.Withdrawals[i]
+		txid := w.TransactionID
+		if txid == "" && strings.HasPrefix(w.Status, poloniexComplete+":") {
+			txid = strings.TrimSpace(strings.TrimPrefix(w.Status, poloniexComplete+":"))
+		}
+		state := StatePending
+		switch {
+		case strings.HasPrefix(w.Status, poloniexCanceled):
+			state = StateFailed
+		case txid != "":
+			state = StateBroadcast
+		}
+		withdrawals = append(withdrawals, Withdrawal{
+			Exchange:      p.GetName(),
+			ID:            strconv.FormatInt(w.WithdrawalNumber, 10),
+			Currency:      currency.NewCode(w.Currency),
+			Amount:        w.Amount,
+			Address:       w.Address,
+			TxID:          txid,
+			Status:        w.Status,
+			State:         state,
+			Confirmations: w.Confirmations,
+			Timestamp:     time.Unix(w.Timestamp, 0),
+		})
+	}
+	return withdrawals, nil
+}
+
+// KrakenWithdrawals reports Kraken's recent withdrawals
+type KrakenWithdrawals struct {
+	Exchange *kraken.Kraken
+	// Assets are the Kraken assets polled for withdrawals, empty polls every
+	// asset held in the account
+	Assets []string
+}
+
+// GetName returns the exchange name
+func (k *KrakenWithdrawals) GetName() string {
+	return k.Exchange.GetName()
+}
+
+// GetWithdrawals returns withdrawals made since the time
+func (k *KrakenWithdrawals) GetWithdrawals(since time.Time) ([]Withdrawal, error) {
+	assets := k.Assets
+	if len(assets) == 0 {
+		balances, err := k.Exchange.GetBalance()
+		if err != nil {
+			return nil, err
+		}
+		for asset := range balances {
+			if !strings.HasSuffix(asset, krakenStakedSuffix) {
+				assets = append(assets, asset)
+			}
+		}
+	}
+
+	var withdrawals []Withdrawal
+	for i := range assets {
+		resp, err := k.Exchange.WithdrawStatus(currency.NewCode(assets[i]), "")
+		if err != nil {
+			return nil, err
+		}
+		for j := range resp {
+			w := &resp[j]
+			ts := time.Unix(int64(w.Time), 0)
+			if ts.Before(since) {
+				continue
+			}
+			state := StatePending
+			switch {
+			case w.Status == krakenFailure:
+				state = StateFailed
+			case w.TxID != "":
+				state = StateBroadcast
+			}
+			withdrawals = append(withdrawals, Withdrawal{
+				Exchange:  k.GetName(),
+				ID:        w.Refid,
+				Currency:  kraken.AssetCode(w.Asset),
+				Amount:    w.Amount,
+				Address:   w.Info,
+				TxID:      w.TxID,
+				Status:    w.Status,
+				State:     state,
+				Timestamp: ts,
+			})
+		}
+	}
+	return withdrawals, nil
+}
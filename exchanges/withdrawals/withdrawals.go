@@ -0,0 +1,410 @@
+// Package withdrawals tracks outbound exchange withdrawals from submission
+// through to their blockchain confirmation. Once an exchange reports a
+// withdrawal's transaction hash its confirmations are followed on public
+// explorers, alerting when the transaction stalls or is stuck paying a fee
+// below the network rate
+package withdrawals
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Event types emitted by the monitor
+const (
+	Broadcast = "WITHDRAWAL_BROADCAST"
+	Confirmed = "WITHDRAWAL_CONFIRMED"
+	Stalled   = "WITHDRAWAL_STALLED"
+	LowFee    = "WITHDRAWAL_LOW_FEE"
+	Failed    = "WITHDRAWAL_FAILED"
+)
+
+// Default monitor settings
+const (
+	DefaultCheckInterval = time.Minute * 2
+	// DefaultLookback is how far back withdrawals are fetched and tracked
+	DefaultLookback = time.Hour * 48
+	// DefaultConfirmations is the confirmations after which a withdrawal is
+	// treated as final
+	DefaultConfirmations = 6
+	// DefaultStallAfter is how long a withdrawal can go without its first
+	// confirmation before it is reported as stalled
+	DefaultStallAfter = time.Hour
+)
+
+// State is a withdrawal's position in the pipeline
+type State string
+
+// Withdrawal states
+const (
+	// StatePending withdrawals have been submitted but not broadcast
+	StatePending State = "pending"
+	// StateBroadcast withdrawals have a transaction hash with no confirmations
+	StateBroadcast State = "broadcast"
+	// StateConfirming withdrawals are mined but below the final confirmations
+	StateConfirming State = "confirming"
+	// StateConfirmed withdrawals have reached the final confirmations
+	StateConfirmed State = "confirmed"
+	// StateFailed withdrawals were cancelled or rejected by the exchange
+	StateFailed State = "failed"
+)
+
+var (
+	errNoSources = errors.New("no withdrawal sources supplied")
+	// ErrTxNotFound is returned by explorers which do not know the
+	// transaction yet
+	ErrTxNotFound = errors.New("transaction not found")
+)
+
+// Withdrawal holds an outbound withdrawal and its confirmations
+type Withdrawal struct {
+	Exchange string        `json:"exchange"`
+	ID       string        `json:"id"`
+	Currency currency.Code `json:"currency"`
+	Amount   float64       `json:"amount"`
+	Address  string        `json:"address,omitempty"`
+	TxID     string        `json:"txid,omitempty"`
+	// Status is the exchange's own status of the withdrawal
+	Status        string `json:"status"`
+	State         State  `json:"state"`
+	Confirmations int    `json:"confirmations"`
+	// FeeRate is the fee the transaction pays per virtual byte and
+	// NetworkFeeRate the rate currently needed to confirm within a few
+	// blocks, both zero when the explorer does not report them
+	FeeRate        float64   `json:"feeRate,omitempty"`
+	NetworkFeeRate float64   `json:"networkFeeRate,omitempty"`
+	Timestamp      time.Time `json:"timestamp"`
+	BroadcastAt    time.Time `json:"broadcastAt,omitempty"`
+	ConfirmedAt    time.Time `json:"confirmedAt,omitempty"`
+	// Alerted is set once a stall has been reported
+	Alerted bool `json:"alerted,omitempty"`
+}
+
+// key returns the identifier of the withdrawal
+func (w *Withdrawal) key() string {
+	return strings.ToLower(w.Exchange) + "/" + w.Currency.Upper().String() + "/" + w.ID
+}
+
+// Source is implemented by exchanges which report outbound withdrawals
+type Source interface {
+	GetName() string
+	// GetWithdrawals returns withdrawals made since the time. Sources set
+	// the state to failed for withdrawals the exchange cancelled and to
+	// pending or broadcast otherwise
+	GetWithdrawals(since time.Time) ([]Withdrawal, error)
+}
+
+// Transaction holds a transaction's confirmations and fee
+type Transaction struct {
+	Confirmations  int
+	FeeRate        float64
+	NetworkFeeRate float64
+}
+
+// Explorer looks up transactions on a public blockchain explorer
+type Explorer interface {
+	GetTransaction(txid string) (Transaction, error)
+}
+
+// Config holds the monitor settings, zero values use the defaults
+type Config struct {
+	Confirmations int
+	StallAfter    time.Duration
+	Lookback      time.Duration
+}
+
+// Event defines a change in a withdrawal's state
+type Event struct {
+	Type       string
+	Withdrawal Withdrawal
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	w := &e.Withdrawal
+	switch e.Type {
+	case Broadcast:
+		return fmt.Sprintf("%s withdrawal of %v %s broadcast, txid %s",
+			w.Exchange, w.Amount, w.Currency, w.TxID)
+	case Confirmed:
+		return fmt.Sprintf("%s withdrawal of %v %s confirmed with %d confirmations, txid %s",
+			w.Exchange, w.Amount, w.Currency, w.Confirmations, w.TxID)
+	case LowFee:
+		return fmt.Sprintf("%s withdrawal of %v %s stuck unconfirmed since %s paying %.1f per vbyte against a network rate of %.1f, txid %s",
+			w.Exchange, w.Amount, w.Currency, w.BroadcastAt.Format(time.RFC3339),
+			w.FeeRate, w.NetworkFeeRate, w.TxID)
+	case Failed:
+		return fmt.Sprintf("%s withdrawal %s of %v %s failed with status %s",
+			w.Exchange, w.ID, w.Amount, w.Currency, w.Status)
+	}
+	if w.TxID == "" {
+		return fmt.Sprintf("%s withdrawal %s of %v %s not broadcast since %s, status %s",
+			w.Exchange, w.ID, w.Amount, w.Currency, w.Timestamp.Format(time.RFC3339), w.Status)
+	}
+	return fmt.Sprintf("%s withdrawal of %v %s unconfirmed since %s, txid %s",
+		w.Exchange, w.Amount, w.Currency, w.BroadcastAt.Format(time.RFC3339), w.TxID)
+}
+
+// Monitor polls sources for withdrawals and follows their transactions on
+// explorers until confirmed
+type Monitor struct {
+	cfg         Config
+	sources     []Source
+	explorers   map[string]Explorer
+	onEvent     func(Event)
+	withdrawals map[string]*Withdrawal
+	seeded      map[string]bool
+	shutdown    chan struct{}
+	wg          sync.WaitGroup
+	mtx         sync.Mutex
+}
+
+// New returns a withdrawal monitor for the sources. Explorers are keyed by
+// currency code, withdrawals of currencies without an explorer are tracked
+// by their exchange status only
+func New(cfg Config, sources []Source, explorers map[string]Explorer, onEvent func(Event)) (*Monitor, error) {
+	if len(sources) == 0 {
+		return nil, errNoSources
+	}
+	if cfg.Confirmations <= 0 {
+		cfg.Confirmations = DefaultConfirmations
+	}
+	if cfg.StallAfter <= 0 {
+		cfg.StallAfter = DefaultStallAfter
+	}
+	if cfg.Lookback <= 0 {
+		cfg.Lookback = DefaultLookback
+	}
+	m := &Monitor{
+		cfg:         cfg,
+		sources:     sources,
+		explorers:   make(map[string]Explorer),
+		onEvent:     onEvent,
+		withdrawals: make(map[string]*Withdrawal),
+		seeded:      make(map[string]bool),
+	}
+	for k, v := range explorers {
+		m.explorers[strings.ToUpper(k)] = v
+	}
+	return m, nil
+}
+
+// Check polls every source for withdrawals and updates the confirmations of
+// those broadcast
+func (m *Monitor) Check() {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	for i := range m.sources {
+		err := m.check(m.sources[i])
+		if err != nil {
+			log.Errorf("Withdrawal monitor failed to check %s: %s", m.sources[i].GetName(), err)
+		}
+	}
+	m.prune()
+}
+
+// check updates the withdrawals of a single source, the caller must hold the
+// lock. State changes found on the first check are recorded without events
+func (m *Monitor) check(s Source) error {
+	withdrawals, err := s.GetWithdrawals(time.Now().Add(-m.cfg.Lookback))
+	if err != nil {
+		return err
+	}
+	name := strings.ToLower(s.GetName())
+	seeded := m.seeded[name]
+	m.seeded[name] = true
+
+	for i := range withdrawals {
+		w := withdrawals[i]
+		if w.Exchange == "" {
+			w.Exchange = s.GetName()
+		}
+		prev, known := m.withdrawals[w.key()]
+		if known {
+			w.BroadcastAt = prev.BroadcastAt
+			w.ConfirmedAt = prev.ConfirmedAt
+			w.Alerted = prev.Alerted
+			if w.State != StateFailed && prev.Confirmations > w.Confirmations {
+				w.Confirmations = prev.Confirmations
+				w.State = prev.State
+			}
+		}
+		m.update(&w)
+		m.withdrawals[w.key()] = &w
+
+		if !seeded {
+			continue
+		}
+		var prevState State
+		if known {
+			prevState = prev.State
+		}
+		switch {
+		case w.State == prevState:
+		case w.State == StateFailed:
+			m.emit(Failed, &w)
+		case w.State == StateConfirmed:
+			m.emit(Confirmed, &w)
+		case w.TxID != "" && (prevState == "" || prevState == StatePending):
+			m.emit(Broadcast, &w)
+		}
+	}
+	return nil
+}
+
+// update follows the withdrawal's transaction on its currency's explorer and
+// reports it once when it stalls, the caller must hold the lock
+func (m *Monitor) update(w *Withdrawal) {
+	if w.State == StateFailed || w.State == StateConfirmed {
+		return
+	}
+	if w.TxID == "" {
+		w.State = StatePending
+	} else if w.BroadcastAt.IsZero() {
+		w.BroadcastAt = time.Now()
+	}
+
+	explorer, ok := m.explorers[w.Currency.Upper().String()]
+	if !ok {
+		// Without an explorer the confirmations are unknown so a broadcast
+		// withdrawal cannot be told apart from a stalled one
+		if w.TxID != "" && w.State == StatePending {
+			w.State = StateBroadcast
+		}
+		if w.State == StatePending {
+			m.checkStall(w, w.Timestamp)
+		}
+		return
+	}
+
+	if w.TxID != "" {
+		tx, err := explorer.GetTransaction(w.TxID)
+		if err != nil && err != ErrTxNotFound {
+			log.Warnf("Withdrawal monitor failed to look up %s txid %s: %s",
+				w.Currency, w.TxID, err)
+		}
+		if err == nil {
+			w.Confirmations = tx.Confirmations
+			w.FeeRate = tx.FeeRate
+			w.NetworkFeeRate = tx.NetworkFeeRate
+		}
+		switch {
+		case w.Confirmations >= m.cfg.Confirmations:
+			w.State = StateConfirmed
+			w.ConfirmedAt = time.Now()
+			return
+		case w.Confirmations > 0:
+			w.State = StateConfirming
+			return
+		}
+		w.State = StateBroadcast
+		m.checkStall(w, w.BroadcastAt)
+		return
+	}
+	m.checkStall(w, w.Timestamp)
+}
+
+// checkStall reports a withdrawal without confirmations since the time, the
+// caller must hold the lock
+func (m *Monitor) checkStall(w *Withdrawal, since time.Time) {
+	if w.Alerted || since.IsZero() || time.Since(since) < m.cfg.StallAfter {
+		return
+	}
+	w.Alerted = true
+	if w.FeeRate > 0 && w.NetworkFeeRate > 0 && w.FeeRate < w.NetworkFeeRate {
+		m.emit(LowFee, w)
+		return
+	}
+	m.emit(Stalled, w)
+}
+
+// prune removes withdrawals older than the lookback which sources no longer
+// return, the caller must hold the lock
+func (m *Monitor) prune() {
+	cutoff := time.Now().Add(-m.cfg.Lookback)
+	for k, w := range m.withdrawals {
+		if w.Timestamp.Before(cutoff) {
+			delete(m.withdrawals, k)
+		}
+	}
+}
+
+// emit publishes an event, the caller must hold the lock
+func (m *Monitor) emit(eventType string, w *Withdrawal) {
+	if m.onEvent != nil {
+		m.onEvent(Event{Type: eventType, Withdrawal: *w})
+	}
+}
+
+// GetWithdrawals returns tracked withdrawals on the exchange, or on every
+// exchange when the name is empty, newest first. Confirmed and failed
+// withdrawals are only returned when all is set
+func (m *Monitor) GetWithdrawals(exchangeName string, all bool) []Withdrawal {
+	m.mtx.Lock()
+	defer m.mtx.Unlock()
+	var resp []Withdrawal
+	for _, w := range m.withdrawals {
+		if exchangeName != "" && !strings.EqualFold(w.Exchange, exchangeName) {
+			continue
+		}
+		if !all && (w.State == StateConfirmed || w.State == StateFailed) {
+			continue
+		}
+		resp = append(resp, *w)
+	}
+	sort.Slice(resp, func(i, j int) bool {
+		return resp[i].Timestamp.After(resp[j].Timestamp)
+	})
+	return resp
+}
+
+// Start checks the sources at the interval until stopped
+func (m *Monitor) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	m.mtx.Lock()
+	if m.shutdown != nil {
+		m.mtx.Unlock()
+		return
+	}
+	m.shutdown = make(chan struct{})
+	shutdown := m.shutdown
+	m.mtx.Unlock()
+
+	m.wg.Add(1)
+	go func() {
+		defer m.wg.Done()
+		tick := time.NewTicker(interval)
+		defer tick.Stop()
+		m.Check()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-tick.C:
+				m.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops the monitor
+func (m *Monitor) Stop() {
+	m.mtx.Lock()
+	if m.shutdown == nil {
+		m.mtx.Unlock()
+		return
+	}
+	close(m.shutdown)
+	m.shutdown = nil
+	m.mtx.Unlock()
+	m.wg.Wait()
+}
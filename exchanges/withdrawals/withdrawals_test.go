@@ -0,0 +1,171 @@
+package withdrawals
+
+import (
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testSource struct {
+	withdrawals []Withdrawal
+	err         error
+}
+
+func (t *testSource) GetName() string { return "Test" }
+
+func (t *testSource) GetWithdrawals(_ time.Time) ([]Withdrawal, error) {
+	return t.withdrawals, t.err
+}
+
+type testExplorer struct {
+	txs map[string]Transaction
+}
+
+func (t *testExplorer) GetTransaction(txid string) (Transaction, error) {
+	tx, ok := t.txs[txid]
+	if !ok {
+		return Transaction{}, ErrTxNotFound
+	}
+	return tx, nil
+}
+
+func TestNew(t *testing.T) {
+	if _, err := New(Config{}, nil, nil, nil); err != errNoSources {
+		t.Error("Test Failed - New() expected no sources error", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	now := time.Now()
+	s := &testSource{withdrawals: []Withdrawal{
+		{ID: "1", Currency: currency.BTC, Amount: 1, TxID: "done", State: StateBroadcast, Timestamp: now.Add(-time.Hour * 3)},
+	}}
+	explorer := &testExplorer{txs: map[string]Transaction{
+		"done": {Confirmations: 20},
+	}}
+	var events []Event
+	m, err := New(Config{Confirmations: 3},
+		[]Source{s},
+		map[string]Explorer{"btc": explorer},
+		func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Withdrawals found on the first check are not alerted
+	m.Check()
+	if len(events) != 0 || len(m.GetWithdrawals("", true)) != 1 || len(m.GetWithdrawals("", false)) != 0 {
+		t.Fatalf("Test Failed - Check() unexpected seed %+v", events)
+	}
+
+	s.withdrawals = append(s.withdrawals, Withdrawal{ID: "2", Currency: currency.BTC, Amount: 0.5, State: StatePending, Timestamp: now})
+	m.Check()
+	w := m.GetWithdrawals("test", false)
+	if len(events) != 0 || len(w) != 1 || w[0].State != StatePending {
+		t.Fatalf("Test Failed - Check() unexpected pending withdrawal %+v %+v", w, events)
+	}
+
+	s.withdrawals[1].TxID = "new"
+	s.withdrawals[1].State = StateBroadcast
+	m.Check()
+	if len(events) != 1 || events[0].Type != Broadcast || events[0].Withdrawal.BroadcastAt.IsZero() {
+		t.Fatalf("Test Failed - Check() expected broadcast event %+v", events)
+	}
+
+	explorer.txs["new"] = Transaction{Confirmations: 1}
+	m.Check()
+	w = m.GetWithdrawals("", false)
+	if len(events) != 1 || w[0].State != StateConfirming || w[0].Confirmations != 1 {
+		t.Fatalf("Test Failed - Check() expected confirming withdrawal %+v %+v", w, events)
+	}
+
+	explorer.txs["new"] = Transaction{Confirmations: 3}
+	m.Check()
+	if len(events) != 2 || events[1].Type != Confirmed || len(m.GetWithdrawals("", false)) != 0 {
+		t.Fatalf("Test Failed - Check() expected confirmed event %+v", events)
+	}
+
+	// confirmed withdrawals are not looked up again
+	delete(explorer.txs, "new")
+	m.Check()
+	w = m.GetWithdrawals("", true)
+	if len(events) != 2 || w[0].State != StateConfirmed || w[0].Confirmations != 3 {
+		t.Fatalf("Test Failed - Check() confirmed withdrawal changed %+v %+v", w, events)
+	}
+
+	s.withdrawals = append(s.withdrawals, Withdrawal{ID: "3", Currency: currency.LTC, Amount: 5, Status: "Failure", State: StateFailed, Timestamp: now})
+	m.Check()
+	if len(events) != 3 || events[2].Type != Failed {
+		t.Fatalf("Test Failed - Check() expected failed event %+v", events)
+	}
+}
+
+func TestStalled(t *testing.T) {
+	now := time.Now()
+	s := &testSource{}
+	explorer := &testExplorer{txs: map[string]Transaction{
+		"lowfee":  {FeeRate: 1.5, NetworkFeeRate: 12},
+		"goodfee": {FeeRate: 20, NetworkFeeRate: 12},
+	}}
+	var events []Event
+	m, err := New(Config{StallAfter: time.Minute},
+		[]Source{s},
+		map[string]Explorer{"BTC": explorer},
+		func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+	m.Check()
+
+	s.withdrawals = []Withdrawal{
+		{ID: "1", Currency: currency.BTC, TxID: "lowfee", State: StateBroadcast, Timestamp: now},
+		{ID: "2", Currency: currency.BTC, TxID: "goodfee", State: StateBroadcast, Timestamp: now},
+		{ID: "3", Currency: currency.XRP, State: StatePending, Timestamp: now.Add(-time.Hour)},
+		{ID: "4", Currency: currency.XRP, TxID: "nohistory", State: StateBroadcast, Timestamp: now.Add(-time.Hour)},
+	}
+	m.Check()
+	if len(events) != 4 {
+		t.Fatalf("Test Failed - Check() unexpected events %+v", events)
+	}
+	// broadcast withdrawals without an explorer have unknown confirmations
+	// so only the pending withdrawal can stall
+	for i := range events {
+		if (events[i].Type == Stalled) != (events[i].Withdrawal.ID == "3") {
+			t.Errorf("Test Failed - Check() unexpected event %+v", events[i])
+		}
+	}
+
+	// stalls are measured from when the transaction was first seen
+	for k, w := range m.withdrawals {
+		if w.TxID != "" {
+			m.withdrawals[k].BroadcastAt = now.Add(-time.Hour)
+		}
+	}
+	events = nil
+	m.Check()
+	if len(events) != 2 {
+		t.Fatalf("Test Failed - Check() expected two stall events, got %+v", events)
+	}
+	for i := range events {
+		switch events[i].Withdrawal.TxID {
+		case "lowfee":
+			if events[i].Type != LowFee {
+				t.Errorf("Test Failed - Check() expected low fee event %+v", events[i])
+			}
+		case "goodfee":
+			if events[i].Type != Stalled {
+				t.Errorf("Test Failed - Check() expected stalled event %+v", events[i])
+			}
+		default:
+			t.Errorf("Test Failed - Check() unexpected stall %+v", events[i])
+		}
+	}
+
+	// stalls are only reported once
+	events = nil
+	m.Check()
+	if len(events) != 0 {
+		t.Errorf("Test Failed - Check() stall reported again %+v", events)
+	}
+}
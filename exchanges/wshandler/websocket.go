@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/thrasher-corp/gocryptotrader/config"
@@ -173,6 +174,12 @@ func (w *Websocket) checkConnection() error {
 	return nil
 }
 
+// GetResetCount returns the number of times the connection has been reset
+// after a disconnect
+func (w *Websocket) GetResetCount() int64 {
+	return atomic.LoadInt64(&w.resets)
+}
+
 // IsConnected exposes websocket connection status
 func (w *Websocket) IsConnected() bool {
 	w.m.Lock()
@@ -226,6 +233,7 @@ func (w *Websocket) Shutdown() error {
 
 // WebsocketReset sends the shutdown command, waits for channel/func closure and then reconnects
 func (w *Websocket) WebsocketReset() error {
+	atomic.AddInt64(&w.resets, 1)
 	err := w.Shutdown()
 	if err != nil {
 		// does not return here to allow connection to be made if already shut down
@@ -815,6 +823,36 @@ func (w *Websocket) unsubscribeToChannels() error {
 	return nil
 }
 
+// UnsubscribeAll sends an unsubscribe for every subscribed channel so the
+// exchange stops streaming before the connection is closed, returning the
+// number of channels unsubscribed. Channels are no longer resubscribed
+func (w *Websocket) UnsubscribeAll() (int, error) {
+	w.subscriptionLock.Lock()
+	defer w.subscriptionLock.Unlock()
+	w.channelsToSubscribe = nil
+	if !w.SupportsFunctionality(WebsocketUnsubscribeSupported) || w.channelUnsubscriber == nil {
+		w.subscribedChannels = nil
+		return 0, nil
+	}
+
+	var unsubscribed int
+	var errs []string
+	for i := range w.subscribedChannels {
+		err := w.channelUnsubscriber(w.subscribedChannels[i])
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%v %v: %v",
+				w.subscribedChannels[i].Channel, w.subscribedChannels[i].Currency, err))
+			continue
+		}
+		unsubscribed++
+	}
+	w.subscribedChannels = nil
+	if len(errs) > 0 {
+		return unsubscribed, fmt.Errorf("%v failed to unsubscribe %s", w.exchangeName, strings.Join(errs, ", "))
+	}
+	return unsubscribed, nil
+}
+
 // RemoveSubscribedChannels removes supplied channels from channelsToSubscribe
 func (w *Websocket) RemoveSubscribedChannels(channels []WebsocketChannelSubscription) {
 	for i := range channels {
@@ -13,6 +13,8 @@ import (
 
 	"github.com/gorilla/websocket"
 	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/apiusage"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/chaos"
 	log "github.com/thrasher-corp/gocryptotrader/logger"
 )
 
@@ -62,7 +64,13 @@ func (w *WebsocketConnection) SendMessage(data interface{}) error {
 	if w.RateLimit > 0 {
 		time.Sleep(time.Duration(w.RateLimit) * time.Millisecond)
 	}
-	return w.Connection.WriteMessage(websocket.TextMessage, json)
+	err = w.Connection.WriteMessage(websocket.TextMessage, json)
+	if err == nil {
+		if t := apiusage.Active(); t != nil {
+			t.WebsocketMessage(w.ExchangeName, true, len(json))
+		}
+	}
+	return err
 }
 
 // SendMessageReturnResponse will send a WS message to the connection
@@ -112,7 +120,7 @@ func (w *WebsocketConnection) WaitForResult(id int64, wg *sync.WaitGroup) {
 
 // ReadMessage reads messages, can handle text, gzip and binary
 func (w *WebsocketConnection) ReadMessage() (WebsocketResponse, error) {
-	mType, resp, err := w.Connection.ReadMessage()
+	mType, resp, err := w.readMessage()
 	if err != nil {
 		return WebsocketResponse{}, err
 	}
@@ -134,6 +142,32 @@ func (w *WebsocketConnection) ReadMessage() (WebsocketResponse, error) {
 	return WebsocketResponse{Raw: standardMessage, Type: mType}, nil
 }
 
+// readMessage reads the next frame, passing it through the chaos fault
+// injector when enabled
+func (w *WebsocketConnection) readMessage() (int, []byte, error) {
+	for {
+		mType, resp, err := w.Connection.ReadMessage()
+		if err != nil {
+			return mType, resp, err
+		}
+		if t := apiusage.Active(); t != nil {
+			t.WebsocketMessage(w.ExchangeName, false, len(resp))
+		}
+		inj := chaos.Active()
+		if inj == nil {
+			return mType, resp, nil
+		}
+		drop, disconnect := inj.Frame(w.ExchangeName)
+		if disconnect {
+			w.Connection.Close()
+			return 0, nil, fmt.Errorf("%v websocket %v", w.ExchangeName, chaos.ErrOutage)
+		}
+		if !drop {
+			return mType, resp, nil
+		}
+	}
+}
+
 // parseBinaryResponse parses a websocket binaray response into a usable byte array
 func (w *WebsocketConnection) parseBinaryResponse(resp []byte) ([]byte, error) {
 	var standardMessage []byte
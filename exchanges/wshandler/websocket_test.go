@@ -367,6 +367,39 @@ func TestUnsubscribe(t *testing.T) {
 	}
 }
 
+// TestUnsubscribeAll logic test
+func TestUnsubscribeAll(t *testing.T) {
+	w := Websocket{
+		channelsToSubscribe: []WebsocketChannelSubscription{{Channel: "hello"}},
+		subscribedChannels:  []WebsocketChannelSubscription{{Channel: "hello"}, {Channel: "fail"}},
+	}
+	var sent []string
+	w.SetChannelUnsubscriber(func(c WebsocketChannelSubscription) error {
+		sent = append(sent, c.Channel)
+		if c.Channel == "fail" {
+			return fmt.Errorf("rejected")
+		}
+		return nil
+	})
+	n, err := w.UnsubscribeAll()
+	if n != 0 || err != nil || len(sent) != 0 {
+		t.Errorf("Unsubscribe sent without functionality support %v %v", n, err)
+	}
+	if len(w.subscribedChannels) != 0 || len(w.channelsToSubscribe) != 0 {
+		t.Error("Subscriptions should have been cleared")
+	}
+
+	w.Functionality = WebsocketUnsubscribeSupported
+	w.subscribedChannels = []WebsocketChannelSubscription{{Channel: "hello"}, {Channel: "fail"}}
+	n, err = w.UnsubscribeAll()
+	if n != 1 || err == nil || len(sent) != 2 {
+		t.Errorf("Expected one unsubscription and an error %v %v %v", n, err, sent)
+	}
+	if len(w.subscribedChannels) != 0 {
+		t.Error("Subscriptions should have been cleared")
+	}
+}
+
 // TestSubscriptionWithExistingEntry logic test
 func TestSubscriptionWithExistingEntry(t *testing.T) {
 	w := Websocket{
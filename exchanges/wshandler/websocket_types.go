@@ -103,6 +103,8 @@ type Websocket struct {
 	// Functionality defines websocket stream capabilities
 	Functionality                uint32
 	canUseAuthenticatedEndpoints bool
+	// resets counts the connection resets, accessed atomically
+	resets int64
 }
 
 // WebsocketChannelSubscription container for websocket subscriptions
@@ -148,7 +150,9 @@ type TradeData struct {
 	Side         string
 }
 
-// TickerData defines ticker feed
+// TickerData defines ticker feed. ClosePrice is the last traded price and
+// Quantity the traded volume, BidPrice and AskPrice hold the best bid and ask
+// when the exchange's ticker includes them, along with their sizes
 type TickerData struct {
 	Timestamp  time.Time
 	Pair       currency.Pair
@@ -159,6 +163,10 @@ type TickerData struct {
 	OpenPrice  float64
 	HighPrice  float64
 	LowPrice   float64
+	BidPrice   float64
+	BidSize    float64
+	AskPrice   float64
+	AskSize    float64
 }
 
 // KlineData defines kline feed
@@ -177,6 +185,42 @@ type KlineData struct {
 	Volume     float64
 }
 
+// OrderUpdate reflects a change to one of the account's orders, received from
+// an exchange's authenticated websocket account data. Fills are sent as
+// updates with a Filled amount
+type OrderUpdate struct {
+	Timestamp time.Time
+	Pair      currency.Pair
+	AssetType string
+	Exchange  string
+	OrderID   string
+	Side      string
+	Status    string
+	Price     float64
+	// Remaining is the amount of the order left to fill
+	Remaining float64
+	// Filled, FillPrice and Fee are set when the update is a fill, the fee is
+	// charged in FeeCurrency
+	Filled      float64
+	FillPrice   float64
+	Fee         float64
+	FeeCurrency currency.Code
+	TradeID     string
+}
+
+// BalanceUpdate reflects a change to one of the account's balances, received
+// from an exchange's authenticated websocket account data
+type BalanceUpdate struct {
+	Timestamp time.Time
+	Exchange  string
+	Currency  currency.Code
+	// Wallet is the exchange's account the balance is held in, e.g. exchange,
+	// margin or lending
+	Wallet string
+	// Change is the amount the balance changed by
+	Change float64
+}
+
 // WebsocketPositionUpdated reflects a change in orders/contracts on an exchange
 type WebsocketPositionUpdated struct {
 	Timestamp time.Time
@@ -0,0 +1,228 @@
+package wsrecorder
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"sort"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+)
+
+var errBookNotFound = errors.New("no snapshot recorded for orderbook before timestamp")
+
+// Book holds reconstructed orderbook state
+type Book struct {
+	Exchange    string
+	Pair        currency.Pair
+	AssetType   string
+	LastUpdated time.Time
+
+	bids map[float64]float64
+	asks map[float64]float64
+}
+
+func newBook(e *Event) *Book {
+	return &Book{
+		Exchange:  e.Exchange,
+		Pair:      e.Pair,
+		AssetType: e.AssetType,
+		bids:      make(map[float64]float64),
+		asks:      make(map[float64]float64),
+	}
+}
+
+// apply updates the book with a snapshot or delta event
+func (b *Book) apply(e *Event) {
+	if e.Type == Snapshot {
+		b.bids = make(map[float64]float64, len(e.Bids))
+		b.asks = make(map[float64]float64, len(e.Asks))
+	}
+	applyLevels(b.bids, e.Bids)
+	applyLevels(b.asks, e.Asks)
+	b.LastUpdated = e.Timestamp
+}
+
+func applyLevels(side map[float64]float64, levels []orderbook.Item) {
+	for i := range levels {
+		if levels[i].Amount <= 0 {
+			delete(side, levels[i].Price)
+			continue
+		}
+		side[levels[i].Price] = levels[i].Amount
+	}
+}
+
+// Bids returns the bids ordered from best to worst
+func (b *Book) Bids() []orderbook.Item {
+	bids := levels(b.bids)
+	sort.Slice(bids, func(i, j int) bool { return bids[i].Price > bids[j].Price })
+	return bids
+}
+
+// Asks returns the asks ordered from best to worst
+func (b *Book) Asks() []orderbook.Item {
+	asks := levels(b.asks)
+	sort.Slice(asks, func(i, j int) bool { return asks[i].Price < asks[j].Price })
+	return asks
+}
+
+func levels(side map[float64]float64) []orderbook.Item {
+	items := make([]orderbook.Item, 0, len(side))
+	for price, amount := range side {
+		items = append(items, orderbook.Item{Price: price, Amount: amount})
+	}
+	return items
+}
+
+// BidAmount returns the amount resting on the bid at the price level
+func (b *Book) BidAmount(price float64) float64 {
+	return b.bids[price]
+}
+
+// AskAmount returns the amount resting on the ask at the price level
+func (b *Book) AskAmount(price float64) float64 {
+	return b.asks[price]
+}
+
+// BestBid returns the best bid price, or zero if there are no bids
+func (b *Book) BestBid() float64 {
+	var best float64
+	for price := range b.bids {
+		if price > best {
+			best = price
+		}
+	}
+	return best
+}
+
+// BestAsk returns the best ask price, or zero if there are no asks
+func (b *Book) BestAsk() float64 {
+	var best float64
+	for price := range b.asks {
+		if best == 0 || price < best {
+			best = price
+		}
+	}
+	return best
+}
+
+// Base returns a copy of the book as an orderbook base
+func (b *Book) Base() orderbook.Base {
+	return orderbook.Base{
+		ExchangeName: b.Exchange,
+		Pair:         b.Pair,
+		AssetType:    b.AssetType,
+		Bids:         b.Bids(),
+		Asks:         b.Asks(),
+		LastUpdated:  b.LastUpdated,
+	}
+}
+
+// Iterator replays a recorded stream event by event while maintaining the
+// reconstructed state of every orderbook in the stream. Deltas received
+// before the first snapshot of their book are skipped
+type Iterator struct {
+	dec   *json.Decoder
+	books map[string]*Book
+	event Event
+	err   error
+}
+
+// NewIterator returns an iterator reading recorded events from r
+func NewIterator(r io.Reader) *Iterator {
+	return &Iterator{
+		dec:   json.NewDecoder(r),
+		books: make(map[string]*Book),
+	}
+}
+
+// Next advances to the next event, applying it to its book. It returns false
+// at the end of the stream or on error
+func (it *Iterator) Next() bool {
+	for {
+		var e Event
+		err := it.dec.Decode(&e)
+		if err != nil {
+			if err != io.EOF {
+				it.err = err
+			}
+			return false
+		}
+
+		if e.Type == Snapshot || e.Type == Delta {
+			k := e.key()
+			b, ok := it.books[k]
+			if !ok {
+				if e.Type == Delta {
+					continue
+				}
+				b = newBook(&e)
+				it.books[k] = b
+			}
+			b.apply(&e)
+		}
+		it.event = e
+		return true
+	}
+}
+
+// Event returns the current event
+func (it *Iterator) Event() Event {
+	return it.event
+}
+
+// Book returns the reconstructed book for the current event, or nil if no
+// snapshot has been seen for it. The book is updated in place as the iterator
+// advances
+func (it *Iterator) Book() *Book {
+	return it.books[it.event.key()]
+}
+
+// BookFor returns the reconstructed book for the exchange, pair and asset
+// type, or nil if no snapshot has been seen for it
+func (it *Iterator) BookFor(exchange string, p currency.Pair, assetType string) *Book {
+	return it.books[bookKey(exchange, p, assetType)]
+}
+
+// Err returns the first error encountered reading the stream
+func (it *Iterator) Err() error {
+	return it.err
+}
+
+// BookAt reconstructs the orderbook state as at the supplied timestamp from a
+// recorded stream
+func BookAt(r io.Reader, exchange string, p currency.Pair, assetType string, t time.Time) (orderbook.Base, error) {
+	k := bookKey(exchange, p, assetType)
+	var book *Book
+	dec := json.NewDecoder(r)
+	for {
+		var e Event
+		err := dec.Decode(&e)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return orderbook.Base{}, err
+		}
+		if e.Timestamp.After(t) {
+			break
+		}
+		if e.Type == Trade || e.key() != k {
+			continue
+		}
+		if book == nil {
+			if e.Type == Delta {
+				continue
+			}
+			book = newBook(&e)
+		}
+		book.apply(&e)
+	}
+	if book == nil {
+		return orderbook.Base{}, errBookNotFound
+	}
+	return book.Base(), nil
+}
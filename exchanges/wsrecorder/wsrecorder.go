@@ -0,0 +1,190 @@
+// Package wsrecorder records websocket orderbook and trade data as a stream of
+// snapshots, deltas and trades, and reconstructs historical orderbook state
+// from recorded streams
+package wsrecorder
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
+)
+
+// EventType defines a recorded event type
+type EventType string
+
+// Recorded event types
+const (
+	Snapshot EventType = "snapshot"
+	Delta    EventType = "delta"
+	Trade    EventType = "trade"
+)
+
+// DefaultSnapshotInterval is the number of deltas recorded between full
+// snapshots so that streams can be replayed from part way through
+const DefaultSnapshotInterval = 1000
+
+var (
+	errNilWriter    = errors.New("recorder writer is nil")
+	errNilOrderbook = errors.New("recorder orderbook is nil")
+)
+
+// Event defines a single recorded event. Snapshots hold every price level,
+// deltas hold only changed levels where a zero amount removes the level
+type Event struct {
+	Type      EventType        `json:"type"`
+	Timestamp time.Time        `json:"timestamp"`
+	Exchange  string           `json:"exchange"`
+	Pair      currency.Pair    `json:"pair"`
+	AssetType string           `json:"assetType"`
+	Bids      []orderbook.Item `json:"bids,omitempty"`
+	Asks      []orderbook.Item `json:"asks,omitempty"`
+	Price     float64          `json:"price,omitempty"`
+	Amount    float64          `json:"amount,omitempty"`
+	Side      string           `json:"side,omitempty"`
+}
+
+// key returns the book lookup key for the event
+func (e *Event) key() string {
+	return bookKey(e.Exchange, e.Pair, e.AssetType)
+}
+
+func bookKey(exchange string, p currency.Pair, assetType string) string {
+	return strings.ToLower(exchange + "_" + p.Base.String() + "_" +
+		p.Quote.String() + "_" + assetType)
+}
+
+// Recorder writes websocket orderbook and trade data to a stream as newline
+// delimited JSON events
+type Recorder struct {
+	// SnapshotInterval is the number of deltas recorded per book between full
+	// snapshots
+	SnapshotInterval int
+
+	enc    *json.Encoder
+	books  map[string]*Book
+	deltas map[string]int
+	mtx    sync.Mutex
+}
+
+// NewRecorder returns a recorder writing to w
+func NewRecorder(w io.Writer) (*Recorder, error) {
+	if w == nil {
+		return nil, errNilWriter
+	}
+	return &Recorder{
+		SnapshotInterval: DefaultSnapshotInterval,
+		enc:              json.NewEncoder(w),
+		books:            make(map[string]*Book),
+		deltas:           make(map[string]int),
+	}, nil
+}
+
+// HandleData records websocket data handler output. Orderbook updates are
+// recorded from the exchange's stored orderbook and unrelated data is ignored
+func (r *Recorder) HandleData(data interface{}) error {
+	switch d := data.(type) {
+	case wshandler.WebsocketOrderbookUpdate:
+		ob, err := orderbook.Get(d.Exchange, d.Pair, d.Asset)
+		if err != nil {
+			return err
+		}
+		return r.RecordOrderbook(&ob, time.Now())
+	case wshandler.TradeData:
+		return r.RecordTrade(&d)
+	}
+	return nil
+}
+
+// RecordOrderbook records the full orderbook state, writing only the levels
+// changed since the previous record unless a snapshot is due
+func (r *Recorder) RecordOrderbook(ob *orderbook.Base, t time.Time) error {
+	if ob == nil {
+		return errNilOrderbook
+	}
+	e := Event{
+		Timestamp: t,
+		Exchange:  ob.ExchangeName,
+		Pair:      ob.Pair,
+		AssetType: ob.AssetType,
+	}
+	k := e.key()
+
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+
+	prev, ok := r.books[k]
+	if !ok || r.deltas[k] >= r.SnapshotInterval {
+		e.Type = Snapshot
+		e.Bids = append([]orderbook.Item(nil), ob.Bids...)
+		e.Asks = append([]orderbook.Item(nil), ob.Asks...)
+		r.deltas[k] = 0
+	} else {
+		e.Type = Delta
+		e.Bids = diffLevels(prev.bids, ob.Bids)
+		e.Asks = diffLevels(prev.asks, ob.Asks)
+		if len(e.Bids) == 0 && len(e.Asks) == 0 {
+			return nil
+		}
+		r.deltas[k]++
+	}
+
+	err := r.enc.Encode(&e)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		prev = newBook(&e)
+		r.books[k] = prev
+	}
+	prev.apply(&e)
+	return nil
+}
+
+// RecordTrade records a public trade
+func (r *Recorder) RecordTrade(t *wshandler.TradeData) error {
+	ts := t.Timestamp
+	if ts.IsZero() {
+		ts = time.Now()
+	}
+	r.mtx.Lock()
+	defer r.mtx.Unlock()
+	return r.enc.Encode(&Event{
+		Type:      Trade,
+		Timestamp: ts,
+		Exchange:  t.Exchange,
+		Pair:      t.CurrencyPair,
+		AssetType: t.AssetType,
+		Price:     t.Price,
+		Amount:    t.Amount,
+		Side:      strings.ToUpper(t.Side),
+	})
+}
+
+// diffLevels returns the levels which differ between the previous and current
+// side of the book, with removed levels holding a zero amount
+func diffLevels(prev map[float64]float64, current []orderbook.Item) []orderbook.Item {
+	var changed []orderbook.Item
+	seen := make(map[float64]struct{}, len(current))
+	for i := range current {
+		seen[current[i].Price] = struct{}{}
+		if amount, ok := prev[current[i].Price]; !ok || amount != current[i].Amount {
+			changed = append(changed, orderbook.Item{
+				Price:  current[i].Price,
+				Amount: current[i].Amount,
+			})
+		}
+	}
+	for price := range prev {
+		if _, ok := seen[price]; !ok {
+			changed = append(changed, orderbook.Item{Price: price})
+		}
+	}
+	return changed
+}
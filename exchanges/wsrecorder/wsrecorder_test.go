@@ -0,0 +1,172 @@
+package wsrecorder
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
+)
+
+var (
+	testPair  = currency.NewPairWithDelimiter("BTC", "USD", "-")
+	testStart = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+)
+
+func testBook(bids, asks []orderbook.Item) *orderbook.Base {
+	return &orderbook.Base{
+		ExchangeName: "test",
+		Pair:         testPair,
+		AssetType:    orderbook.Spot,
+		Bids:         bids,
+		Asks:         asks,
+	}
+}
+
+// recordTestStream records three orderbook states one second apart with a
+// trade between the second and third
+func recordTestStream(t *testing.T, snapshotInterval int) *bytes.Buffer {
+	var buf bytes.Buffer
+	r, err := NewRecorder(&buf)
+	if err != nil {
+		t.Fatal("Test Failed - NewRecorder() error", err)
+	}
+	r.SnapshotInterval = snapshotInterval
+
+	states := []*orderbook.Base{
+		testBook([]orderbook.Item{{Price: 100, Amount: 1}, {Price: 99, Amount: 2}},
+			[]orderbook.Item{{Price: 101, Amount: 1}, {Price: 102, Amount: 3}}),
+		testBook([]orderbook.Item{{Price: 100, Amount: 5}, {Price: 99, Amount: 2}},
+			[]orderbook.Item{{Price: 102, Amount: 3}}),
+		testBook([]orderbook.Item{{Price: 100, Amount: 5}, {Price: 98, Amount: 4}},
+			[]orderbook.Item{{Price: 102, Amount: 3}, {Price: 103, Amount: 1}}),
+	}
+	for i := range states {
+		if i == 2 {
+			err = r.HandleData(wshandler.TradeData{
+				Timestamp:    testStart.Add(time.Millisecond * 1500),
+				Exchange:     "test",
+				CurrencyPair: testPair,
+				AssetType:    orderbook.Spot,
+				Price:        100,
+				Amount:       0.5,
+				Side:         "sell",
+			})
+			if err != nil {
+				t.Fatal("Test Failed - HandleData() error", err)
+			}
+		}
+		err = r.RecordOrderbook(states[i], testStart.Add(time.Duration(i)*time.Second))
+		if err != nil {
+			t.Fatal("Test Failed - RecordOrderbook() error", err)
+		}
+	}
+	// Unchanged books are not recorded
+	err = r.RecordOrderbook(states[2], testStart.Add(time.Second*3))
+	if err != nil {
+		t.Fatal("Test Failed - RecordOrderbook() error", err)
+	}
+	return &buf
+}
+
+func TestRecorder(t *testing.T) {
+	if _, err := NewRecorder(nil); err != errNilWriter {
+		t.Error("Test Failed - NewRecorder() expected nil writer error")
+	}
+
+	buf := recordTestStream(t, DefaultSnapshotInterval)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("Test Failed - Recorder expected 4 events, received %d", len(lines))
+	}
+	if !strings.Contains(lines[0], `"type":"snapshot"`) ||
+		!strings.Contains(lines[1], `"type":"delta"`) ||
+		!strings.Contains(lines[2], `"type":"trade"`) ||
+		!strings.Contains(lines[3], `"type":"delta"`) {
+		t.Errorf("Test Failed - Recorder unexpected event types %v", lines)
+	}
+
+	buf = recordTestStream(t, 1)
+	lines = strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if !strings.Contains(lines[3], `"type":"snapshot"`) {
+		t.Error("Test Failed - Recorder expected snapshot after interval")
+	}
+}
+
+func TestIterator(t *testing.T) {
+	it := NewIterator(recordTestStream(t, DefaultSnapshotInterval))
+	var events int
+	var trade Event
+	for it.Next() {
+		events++
+		if it.Event().Type == Trade {
+			trade = it.Event()
+			b := it.Book()
+			if b == nil || b.BidAmount(100) != 5 || b.AskAmount(101) != 0 {
+				t.Error("Test Failed - Iterator book state incorrect at trade")
+			}
+		}
+	}
+	if it.Err() != nil {
+		t.Fatal("Test Failed - Iterator error", it.Err())
+	}
+	if events != 4 {
+		t.Errorf("Test Failed - Iterator expected 4 events, received %d", events)
+	}
+	if trade.Price != 100 || trade.Side != "SELL" {
+		t.Errorf("Test Failed - Iterator unexpected trade %+v", trade)
+	}
+
+	b := it.BookFor("TEST", testPair, orderbook.Spot)
+	if b == nil {
+		t.Fatal("Test Failed - BookFor() expected book")
+	}
+	bids, asks := b.Bids(), b.Asks()
+	if len(bids) != 2 || bids[0].Price != 100 || bids[1].Price != 98 ||
+		len(asks) != 2 || asks[0].Price != 102 || asks[1].Price != 103 {
+		t.Errorf("Test Failed - Iterator final book incorrect %v %v", bids, asks)
+	}
+	if b.BestBid() != 100 || b.BestAsk() != 102 {
+		t.Error("Test Failed - Book best bid and ask incorrect")
+	}
+
+	// Deltas before the first snapshot cannot be applied
+	buf := recordTestStream(t, DefaultSnapshotInterval)
+	lines := strings.SplitN(buf.String(), "\n", 2)
+	it = NewIterator(strings.NewReader(lines[1]))
+	for it.Next() {
+		if it.Event().Type == Delta {
+			t.Fatal("Test Failed - Iterator expected deltas without snapshot to be skipped")
+		}
+	}
+}
+
+func TestBookAt(t *testing.T) {
+	buf := recordTestStream(t, DefaultSnapshotInterval)
+	data := buf.Bytes()
+
+	_, err := BookAt(bytes.NewReader(data), "test", testPair, orderbook.Spot, testStart.Add(-time.Second))
+	if err != errBookNotFound {
+		t.Error("Test Failed - BookAt() expected book not found error", err)
+	}
+
+	ob, err := BookAt(bytes.NewReader(data), "test", testPair, orderbook.Spot, testStart.Add(time.Millisecond*1700))
+	if err != nil {
+		t.Fatal("Test Failed - BookAt() error", err)
+	}
+	if len(ob.Bids) != 2 || ob.Bids[0].Amount != 5 || ob.Bids[1].Price != 99 ||
+		len(ob.Asks) != 1 || ob.Asks[0].Price != 102 {
+		t.Errorf("Test Failed - BookAt() unexpected book %+v", ob)
+	}
+	if !ob.LastUpdated.Equal(testStart.Add(time.Second)) {
+		t.Error("Test Failed - BookAt() unexpected last updated time", ob.LastUpdated)
+	}
+
+	_, err = BookAt(strings.NewReader("{bad"), "test", testPair, orderbook.Spot, testStart)
+	if err == nil {
+		t.Error("Test Failed - BookAt() expected decode error")
+	}
+}
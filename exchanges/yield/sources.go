@@ -0,0 +1,308 @@
+package yield
+
+import (
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/poloniex"
+)
+
+const (
+	// poloniexMinLoan is the smallest loan offer Poloniex accepts
+	poloniexMinLoan = 0.01
+	// DefaultLoanDuration is the number of days Poloniex loan offers are made
+	// for, the shortest duration returns lent funds soonest
+	DefaultLoanDuration = 2
+	daysPerYear         = 365
+
+	poloniexExchangeAccount = "exchange"
+	poloniexLendingAccount  = "lending"
+
+	// krakenStakedSuffix marks Kraken balances held in staking
+	krakenStakedSuffix = ".S"
+)
+
+// PoloniexLending lends idle balances on the Poloniex margin lending book at
+// the lowest offered rate. Loans already taken are returned when they expire
+type PoloniexLending struct {
+	Exchange *poloniex.Poloniex
+	// Duration is the loan offer duration in days
+	Duration int
+}
+
+// GetName returns the exchange name
+func (p *PoloniexLending) GetName() string {
+	return p.Exchange.GetName()
+}
+
+// GetAvailable returns the free exchange account balances
+func (p *PoloniexLending) GetAvailable() (map[string]float64, error) {
+	return accountAvailable(p.Exchange)
+}
+
+// GetOffer returns the lowest daily rate on the loan book annualised
+func (p *PoloniexLending) GetOffer(c currency.Code) (Offer, error) {
+	orders, err := p.Exchange.GetLoanOrders(c.Upper().String())
+	if err != nil {
+		return Offer{}, err
+	}
+	if len(orders.Offers) == 0 {
+		return Offer{}, ErrUnsupported
+	}
+	return Offer{
+		Rate:      orders.Offers[0].Rate * daysPerYear,
+		MinAmount: poloniexMinLoan,
+	}, nil
+}
+
+// GetPositions returns open loan offers and active loans by currency
+func (p *PoloniexLending) GetPositions() ([]Position, error) {
+	offers, err := p.Exchange.GetOpenLoanOffers()
+	if err != nil && err != poloniex.ErrNoOpenLoanOffers {
+		return nil, err
+	}
+	loans, err := p.Exchange.GetActiveLoans()
+	if err != nil {
+		return nil, err
+	}
+
+	positions := make(map[string]*Position)
+	add := func(code string, o *poloniex.LoanOffer) {
+		k := strings.ToUpper(code)
+		pos, ok := positions[k]
+		if !ok {
+			pos = &Position{Exchange: p.GetName(), Currency: currency.NewCode(k)}
+			positions[k] = pos
+		}
+		// Rate is the amount weighted daily rate annualised
+		if total := pos.Amount + o.Amount; total > 0 {
+			pos.Rate = (pos.Rate*pos.Amount + o.Rate*daysPerYear*o.Amount) / total
+		}
+		pos.Amount += o.Amount
+	}
+	for code, o := range offers {
+		for i := range o {
+			add(code, &o[i])
+		}
+	}
+	for i := range loans.Provided {
+		add(loans.Provided[i].Currency, &loans.Provided[i])
+	}
+
+	resp := make([]Position, 0, len(positions))
+	for _, pos := range positions {
+		resp = append(resp, *pos)
+	}
+	return resp, nil
+}
+
+// Deploy transfers the amount to the lending account and offers it at the
+// lowest rate on the loan book
+func (p *PoloniexLending) Deploy(c currency.Code, amount float64) error {
+	offer, err := p.GetOffer(c)
+	if err != nil {
+		return err
+	}
+	code := c.Upper().String()
+	_, err = p.Exchange.TransferBalance(code, poloniexExchangeAccount, poloniexLendingAccount, amount)
+	if err != nil {
+		return err
+	}
+	duration := p.Duration
+	if duration <= 0 {
+		duration = DefaultLoanDuration
+	}
+	_, err = p.Exchange.CreateLoanOffer(code, amount, offer.Rate/daysPerYear, duration, false)
+	if err != nil {
+		// Return the funds so they remain available to strategies
+		_, tErr := p.Exchange.TransferBalance(code, poloniexLendingAccount, poloniexExchangeAccount, amount)
+		if tErr != nil {
+			return tErr
+		}
+	}
+	return err
+}
+
+// Unwind cancels open loan offers until the amount is covered and transfers
+// them along with any repaid loans back to the exchange account
+func (p *PoloniexLending) Unwind(c currency.Code, amount float64) (float64, error) {
+	code := c.Upper().String()
+	balances, err := p.Exchange.GetAvailableBalances(poloniexLendingAccount)
+	if err != nil {
+		return 0, err
+	}
+	released := balances[poloniexLendingAccount][code]
+
+	if released < amount {
+		offers, err := p.Exchange.GetOpenLoanOffers()
+		if err != nil && err != poloniex.ErrNoOpenLoanOffers {
+			return 0, err
+		}
+		for code, o := range offers {
+			if !strings.EqualFold(code, c.String()) {
+				continue
+			}
+			for i := range o {
+				if released >= amount {
+					break
+				}
+				_, err = p.Exchange.CancelLoanOffer(o[i].ID)
+				if err != nil {
+					return 0, err
+				}
+				released += o[i].Amount
+			}
+		}
+	}
+	if released <= 0 {
+		return 0, nil
+	}
+	_, err = p.Exchange.TransferBalance(code, poloniexLendingAccount, poloniexExchangeAccount, released)
+	if err != nil {
+		return 0, err
+	}
+	return released, nil
+}
+
+// KrakenStaking stakes idle balances of assets Kraken offers staking for.
+// Unstaked funds are returned after the asset's unbonding period
+type KrakenStaking struct {
+	Exchange *kraken.Kraken
+}
+
+// GetName returns the exchange name
+func (k *KrakenStaking) GetName() string {
+	return k.Exchange.GetName()
+}
+
+// GetAvailable returns the free balances excluding staked balances
+func (k *KrakenStaking) GetAvailable() (map[string]float64, error) {
+	available, err := accountAvailable(k.Exchange)
+	if err != nil {
+		return nil, err
+	}
+	for code := range available {
+		if strings.HasSuffix(code, krakenStakedSuffix) {
+			delete(available, code)
+		}
+	}
+	return available, nil
+}
+
+// krakenMatch returns true if the Kraken asset is the currency, whether either
+// is given as Kraken's legacy code such as XETH or the standard code
+func krakenMatch(asset string, c currency.Code) bool {
+	return kraken.AssetCode(asset).Match(kraken.AssetCode(c.String()))
+}
+
+// asset returns the stakeable asset for the currency
+func (k *KrakenStaking) asset(c currency.Code) (*kraken.StakeableAsset, error) {
+	assets, err := k.Exchange.GetStakeableAssets()
+	if err != nil {
+		return nil, err
+	}
+	for i := range assets {
+		if !krakenMatch(assets[i].Asset, c) {
+			continue
+		}
+		if assets[i].Disabled || !assets[i].CanStake || !assets[i].EnabledForUser {
+			break
+		}
+		return &assets[i], nil
+	}
+	return nil, ErrUnsupported
+}
+
+// GetOffer returns the asset's yearly staking reward
+func (k *KrakenStaking) GetOffer(c currency.Code) (Offer, error) {
+	a, err := k.asset(c)
+	if err != nil {
+		return Offer{}, err
+	}
+	return Offer{
+		Rate:      a.Rewards.Reward / 100,
+		MinAmount: a.MinimumAmount.Staking,
+	}, nil
+}
+
+// GetPositions returns the staked balances and amounts still unbonding
+func (k *KrakenStaking) GetPositions() ([]Position, error) {
+	balances, err := k.Exchange.GetBalance()
+	if err != nil {
+		return nil, err
+	}
+	pending, err := k.Exchange.GetPendingStakingTransactions()
+	if err != nil {
+		return nil, err
+	}
+	assets, err := k.Exchange.GetStakeableAssets()
+	if err != nil {
+		return nil, err
+	}
+
+	var positions []Position
+	for i := range assets {
+		staked := balances[assets[i].StakingAsset]
+		var unbonding float64
+		for j := range pending {
+			if pending[j].Type == "unbonding" && strings.EqualFold(pending[j].Asset, assets[i].StakingAsset) {
+				unbonding += pending[j].Amount
+			}
+		}
+		if staked <= 0 && unbonding <= 0 {
+			continue
+		}
+		// Positions are keyed by the balance code of the unstaked asset
+		code := strings.ToUpper(assets[i].Asset)
+		if _, ok := balances["X"+code]; ok {
+			code = "X" + code
+		}
+		positions = append(positions, Position{
+			Exchange:  k.GetName(),
+			Currency:  currency.NewCode(code),
+			Amount:    staked,
+			Rate:      assets[i].Rewards.Reward / 100,
+			Unbonding: unbonding,
+		})
+	}
+	return positions, nil
+}
+
+// Deploy stakes the amount
+func (k *KrakenStaking) Deploy(c currency.Code, amount float64) error {
+	a, err := k.asset(c)
+	if err != nil {
+		return err
+	}
+	_, err = k.Exchange.Stake(a.Asset, a.Method, amount)
+	return err
+}
+
+// Unwind unstakes up to the amount. Funds are returned after the unbonding
+// period so nothing is released immediately
+func (k *KrakenStaking) Unwind(c currency.Code, amount float64) (float64, error) {
+	assets, err := k.Exchange.GetStakeableAssets()
+	if err != nil {
+		return 0, err
+	}
+	balances, err := k.Exchange.GetBalance()
+	if err != nil {
+		return 0, err
+	}
+	for i := range assets {
+		if !krakenMatch(assets[i].Asset, c) {
+			continue
+		}
+		staked := balances[assets[i].StakingAsset]
+		if staked <= 0 {
+			return 0, nil
+		}
+		if amount > staked {
+			amount = staked
+		}
+		_, err = k.Exchange.Unstake(assets[i].StakingAsset, amount)
+		return 0, err
+	}
+	return 0, ErrUnsupported
+}
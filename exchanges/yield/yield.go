@@ -0,0 +1,383 @@
+// Package yield deploys idle exchange balances to yield sources such as
+// Poloniex margin lending and Kraken staking. Balances are idle when they are
+// not allocated to a strategy, and deployed funds are unwound when strategies
+// need them again
+package yield
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Event types emitted by the optimizer
+const (
+	Deployed = "DEPLOYED"
+	Unwound  = "UNWOUND"
+)
+
+// Default optimizer settings
+const (
+	DefaultCheckInterval = time.Minute * 10
+	// DefaultReserve is the fraction of each idle balance kept on the exchange
+	DefaultReserve = 0.1
+)
+
+var (
+	errNoSources       = errors.New("no yield sources supplied")
+	errNilRequiredFunc = errors.New("yield required balance func is nil")
+	errInvalidReserve  = errors.New("yield reserve must be between 0 and 1")
+	errInvalidMinRate  = errors.New("yield minimum rate cannot be negative")
+	errSourceNotFound  = errors.New("yield source not found")
+	errInvalidAmount   = errors.New("yield amount must be positive")
+	errInsufficient    = errors.New("yield insufficient funds released")
+	// ErrUnsupported is returned by sources for currencies they cannot deploy
+	ErrUnsupported = errors.New("currency not supported by yield source")
+)
+
+// Offer defines the yield a source currently pays for a currency
+type Offer struct {
+	// Rate is the annualised yield, 0.05 is 5%
+	Rate      float64
+	MinAmount float64
+}
+
+// Position defines funds deployed to a yield source
+type Position struct {
+	Exchange string
+	Currency currency.Code
+	Amount   float64
+	Rate     float64
+	// Unbonding is being unwound but not yet returned to the exchange balance
+	Unbonding float64
+}
+
+// Source is implemented by exchanges which pay yield on deployed balances
+type Source interface {
+	GetName() string
+	// GetAvailable returns the exchange balances free to deploy keyed by
+	// upper case currency
+	GetAvailable() (map[string]float64, error)
+	GetOffer(c currency.Code) (Offer, error)
+	GetPositions() ([]Position, error)
+	Deploy(c currency.Code, amount float64) error
+	// Unwind withdraws up to the amount from the source, returning the
+	// amount immediately available on the exchange. Sources with unbonding
+	// periods release funds later
+	Unwind(c currency.Code, amount float64) (float64, error)
+}
+
+// RequiredFunc returns the amount of a currency strategies need on an
+// exchange, such as the total allocated to them
+type RequiredFunc func(exchangeName string, c currency.Code) float64
+
+// Event defines funds deployed to or unwound from a yield source
+type Event struct {
+	Type     string
+	Exchange string
+	Currency currency.Code
+	Amount   float64
+	Rate     float64
+	Time     time.Time
+}
+
+// String implements the stringer interface
+func (e *Event) String() string {
+	if e.Type == Deployed {
+		return fmt.Sprintf("%v %s deployed to %s yield at %.2f%% APR",
+			e.Amount, e.Currency, e.Exchange, e.Rate*100)
+	}
+	return fmt.Sprintf("%v %s unwound from %s yield", e.Amount, e.Currency, e.Exchange)
+}
+
+// Config defines when idle balances are deployed
+type Config struct {
+	// Reserve is the fraction of each idle balance left on the exchange
+	Reserve float64
+	// MinRate is the lowest annualised yield funds are deployed at
+	MinRate float64
+	// Currencies limits deployment to the listed currencies, empty deploys
+	// any currency a source supports
+	Currencies []currency.Code
+}
+
+// Optimizer deploys idle balances to yield sources
+type Optimizer struct {
+	sources  []Source
+	required RequiredFunc
+	cfg      Config
+	onEvent  func(Event)
+	events   []Event
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+	mtx      sync.Mutex
+}
+
+// New returns a yield optimizer for the sources, required returns the
+// balances strategies need which are never deployed
+func New(sources []Source, required RequiredFunc, cfg Config, onEvent func(Event)) (*Optimizer, error) {
+	if len(sources) == 0 {
+		return nil, errNoSources
+	}
+	if required == nil {
+		return nil, errNilRequiredFunc
+	}
+	if cfg.Reserve < 0 || cfg.Reserve >= 1 {
+		return nil, errInvalidReserve
+	}
+	if cfg.MinRate < 0 {
+		return nil, errInvalidMinRate
+	}
+	return &Optimizer{
+		sources:  sources,
+		required: required,
+		cfg:      cfg,
+		onEvent:  onEvent,
+	}, nil
+}
+
+func (o *Optimizer) source(exchangeName string) (Source, error) {
+	for i := range o.sources {
+		if strings.EqualFold(o.sources[i].GetName(), exchangeName) {
+			return o.sources[i], nil
+		}
+	}
+	return nil, fmt.Errorf("%s %v", exchangeName, errSourceNotFound)
+}
+
+func (o *Optimizer) allowed(c currency.Code) bool {
+	if len(o.cfg.Currencies) == 0 {
+		return true
+	}
+	for i := range o.cfg.Currencies {
+		if o.cfg.Currencies[i].Match(c) {
+			return true
+		}
+	}
+	return false
+}
+
+// Check deploys idle balances and unwinds deployed funds strategies need on
+// every source
+func (o *Optimizer) Check() {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	for i := range o.sources {
+		err := o.check(o.sources[i])
+		if err != nil {
+			log.Errorf("Yield optimizer failed to check %s: %s", o.sources[i].GetName(), err)
+		}
+	}
+}
+
+// check balances a single source, the caller must hold the lock
+func (o *Optimizer) check(s Source) error {
+	available, err := s.GetAvailable()
+	if err != nil {
+		return err
+	}
+	positions, err := s.GetPositions()
+	if err != nil {
+		return err
+	}
+	deployed := make(map[string]float64)
+	unbonding := make(map[string]float64)
+	for i := range positions {
+		deployed[positions[i].Currency.Upper().String()] += positions[i].Amount
+		unbonding[positions[i].Currency.Upper().String()] += positions[i].Unbonding
+	}
+
+	codes := make([]string, 0, len(available)+len(deployed))
+	for k := range available {
+		codes = append(codes, k)
+	}
+	for k := range deployed {
+		if _, ok := available[k]; !ok {
+			codes = append(codes, k)
+		}
+	}
+	sort.Strings(codes)
+
+	for _, k := range codes {
+		c := currency.NewCode(k)
+		required := o.required(s.GetName(), c)
+		free := available[k]
+		if free < required {
+			// Funds already unbonding will cover part of the shortfall
+			if short := required - free - unbonding[k]; short > 0 && deployed[k] > 0 {
+				o.unwind(s, c, short)
+			}
+			continue
+		}
+
+		if !o.allowed(c) {
+			continue
+		}
+		// The reserve is kept from the total of free and deployed funds so
+		// repeated checks do not deploy it in ever smaller amounts
+		idle := free - required - (free+deployed[k]-required)*o.cfg.Reserve
+		if idle <= 0 {
+			continue
+		}
+		offer, err := s.GetOffer(c)
+		if err != nil {
+			if err != ErrUnsupported {
+				log.Warnf("Yield optimizer %s %s offer unavailable: %s", s.GetName(), c, err)
+			}
+			continue
+		}
+		if offer.Rate < o.cfg.MinRate || idle < offer.MinAmount {
+			continue
+		}
+		err = s.Deploy(c, idle)
+		if err != nil {
+			log.Errorf("Yield optimizer failed to deploy %v %s to %s: %s", idle, c, s.GetName(), err)
+			continue
+		}
+		o.emit(Event{Type: Deployed, Exchange: s.GetName(), Currency: c, Amount: idle, Rate: offer.Rate})
+	}
+	return nil
+}
+
+// unwind withdraws funds from a source, the caller must hold the lock
+func (o *Optimizer) unwind(s Source, c currency.Code, amount float64) float64 {
+	released, err := s.Unwind(c, amount)
+	if err != nil {
+		log.Errorf("Yield optimizer failed to unwind %v %s from %s: %s", amount, c, s.GetName(), err)
+	}
+	if released > 0 {
+		o.emit(Event{Type: Unwound, Exchange: s.GetName(), Currency: c, Amount: released})
+	}
+	return released
+}
+
+// emit records and publishes an event, the caller must hold the lock
+func (o *Optimizer) emit(e Event) {
+	e.Time = time.Now()
+	o.events = append(o.events, e)
+	if o.onEvent != nil {
+		o.onEvent(e)
+	}
+}
+
+// Require unwinds deployed funds so the amount of a currency is free on the
+// exchange, for use before allocating funds to a strategy. Funds which are
+// still unbonding return an error
+func (o *Optimizer) Require(exchangeName string, c currency.Code, amount float64) error {
+	if amount <= 0 {
+		return errInvalidAmount
+	}
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	s, err := o.source(exchangeName)
+	if err != nil {
+		return err
+	}
+	available, err := s.GetAvailable()
+	if err != nil {
+		return err
+	}
+	free := available[c.Upper().String()] - o.required(s.GetName(), c)
+	if free >= amount {
+		return nil
+	}
+	if released := o.unwind(s, c, amount-free); free+released < amount {
+		return fmt.Errorf("%s %s %v: needed %v, free %v",
+			exchangeName, c, errInsufficient, amount, free+released)
+	}
+	return nil
+}
+
+// GetPositions returns the funds deployed to every source
+func (o *Optimizer) GetPositions() ([]Position, error) {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	var positions []Position
+	for i := range o.sources {
+		p, err := o.sources[i].GetPositions()
+		if err != nil {
+			return nil, err
+		}
+		positions = append(positions, p...)
+	}
+	return positions, nil
+}
+
+// GetEvents returns the funds deployed and unwound since the optimizer
+// started
+func (o *Optimizer) GetEvents() []Event {
+	o.mtx.Lock()
+	defer o.mtx.Unlock()
+	events := make([]Event, len(o.events))
+	copy(events, o.events)
+	return events
+}
+
+// Start checks the sources at the interval until stopped
+func (o *Optimizer) Start(interval time.Duration) {
+	if interval <= 0 {
+		interval = DefaultCheckInterval
+	}
+	o.mtx.Lock()
+	if o.shutdown != nil {
+		o.mtx.Unlock()
+		return
+	}
+	o.shutdown = make(chan struct{})
+	shutdown := o.shutdown
+	o.mtx.Unlock()
+
+	o.wg.Add(1)
+	go func() {
+		defer o.wg.Done()
+		t := time.NewTicker(interval)
+		defer t.Stop()
+		o.Check()
+		for {
+			select {
+			case <-shutdown:
+				return
+			case <-t.C:
+				o.Check()
+			}
+		}
+	}()
+}
+
+// Stop stops the optimizer, deployed funds are left in place
+func (o *Optimizer) Stop() {
+	o.mtx.Lock()
+	if o.shutdown == nil {
+		o.mtx.Unlock()
+		return
+	}
+	close(o.shutdown)
+	o.shutdown = nil
+	o.mtx.Unlock()
+	o.wg.Wait()
+}
+
+// accountAvailable returns an exchange's balances not held by open orders
+func accountAvailable(e exchange.IBotExchange) (map[string]float64, error) {
+	acc, err := e.GetAccountInfo()
+	if err != nil {
+		return nil, err
+	}
+	available := make(map[string]float64)
+	for i := range acc.Accounts {
+		for j := range acc.Accounts[i].Currencies {
+			c := acc.Accounts[i].Currencies[j]
+			if free := c.TotalValue - c.Hold; free > 0 {
+				available[c.CurrencyName.Upper().String()] += free
+			}
+		}
+	}
+	return available, nil
+}
@@ -0,0 +1,198 @@
+package yield
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+type testSource struct {
+	available map[string]float64
+	deployed  map[string]float64
+	unbonding map[string]float64
+	offers    map[string]Offer
+	// delayed sources release unwound funds after unbonding
+	delayed bool
+}
+
+func newTestSource() *testSource {
+	return &testSource{
+		available: map[string]float64{"BTC": 10, "USD": 1000},
+		deployed:  make(map[string]float64),
+		unbonding: make(map[string]float64),
+		offers:    map[string]Offer{"BTC": {Rate: 0.05, MinAmount: 0.1}},
+	}
+}
+
+func (t *testSource) GetName() string { return "Test" }
+
+func (t *testSource) GetAvailable() (map[string]float64, error) {
+	a := make(map[string]float64)
+	for k, v := range t.available {
+		a[k] = v
+	}
+	return a, nil
+}
+
+func (t *testSource) GetOffer(c currency.Code) (Offer, error) {
+	o, ok := t.offers[c.String()]
+	if !ok {
+		return Offer{}, ErrUnsupported
+	}
+	return o, nil
+}
+
+func (t *testSource) GetPositions() ([]Position, error) {
+	var p []Position
+	for k, v := range t.deployed {
+		p = append(p, Position{Exchange: "Test", Currency: currency.NewCode(k), Amount: v, Unbonding: t.unbonding[k]})
+	}
+	return p, nil
+}
+
+func (t *testSource) Deploy(c currency.Code, amount float64) error {
+	t.available[c.String()] -= amount
+	t.deployed[c.String()] += amount
+	return nil
+}
+
+func (t *testSource) Unwind(c currency.Code, amount float64) (float64, error) {
+	k := c.String()
+	if amount > t.deployed[k] {
+		amount = t.deployed[k]
+	}
+	t.deployed[k] -= amount
+	if t.delayed {
+		t.unbonding[k] += amount
+		return 0, nil
+	}
+	t.available[k] += amount
+	return amount, nil
+}
+
+func TestNew(t *testing.T) {
+	required := func(string, currency.Code) float64 { return 0 }
+	if _, err := New(nil, required, Config{}, nil); err != errNoSources {
+		t.Error("Test Failed - New() expected no sources error", err)
+	}
+	if _, err := New([]Source{newTestSource()}, nil, Config{}, nil); err != errNilRequiredFunc {
+		t.Error("Test Failed - New() expected nil required func error", err)
+	}
+	if _, err := New([]Source{newTestSource()}, required, Config{Reserve: 1}, nil); err != errInvalidReserve {
+		t.Error("Test Failed - New() expected invalid reserve error", err)
+	}
+	if _, err := New([]Source{newTestSource()}, required, Config{MinRate: -1}, nil); err != errInvalidMinRate {
+		t.Error("Test Failed - New() expected invalid min rate error", err)
+	}
+}
+
+func TestCheck(t *testing.T) {
+	s := newTestSource()
+	needed := 4.0
+	var events []Event
+	o, err := New([]Source{s}, func(_ string, c currency.Code) float64 {
+		if c.String() == "BTC" {
+			return needed
+		}
+		return 0
+	}, Config{Reserve: 0.5}, func(e Event) { events = append(events, e) })
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Half of the 6 idle BTC is deployed, USD is unsupported
+	o.Check()
+	if s.deployed["BTC"] != 3 || s.available["BTC"] != 7 || len(events) != 1 || events[0].Type != Deployed {
+		t.Fatalf("Test Failed - Check() unexpected deployment %v %v %+v", s.deployed, s.available, events)
+	}
+	// The reserve is not redeployed on later checks
+	o.Check()
+	if s.deployed["BTC"] != 3 || len(events) != 1 {
+		t.Errorf("Test Failed - Check() expected no further deployment, got %v", s.deployed)
+	}
+
+	// Strategies needing more than the free balance unwind the shortfall
+	needed = 9
+	o.Check()
+	if s.available["BTC"] != 9 || s.deployed["BTC"] != 1 || len(events) != 2 || events[1].Type != Unwound {
+		t.Errorf("Test Failed - Check() unexpected unwind %v %v %+v", s.deployed, s.available, events)
+	}
+	if len(o.GetEvents()) != 2 {
+		t.Error("Test Failed - GetEvents() expected 2 events")
+	}
+}
+
+func TestCheckMinimums(t *testing.T) {
+	s := newTestSource()
+	o, _ := New([]Source{s}, func(string, currency.Code) float64 { return 9.95 }, Config{}, nil)
+	o.Check()
+	if s.deployed["BTC"] != 0 {
+		t.Error("Test Failed - Check() expected amount below minimum not deployed")
+	}
+
+	s = newTestSource()
+	o, _ = New([]Source{s}, func(string, currency.Code) float64 { return 0 }, Config{MinRate: 0.1}, nil)
+	o.Check()
+	if s.deployed["BTC"] != 0 {
+		t.Error("Test Failed - Check() expected rate below minimum not deployed")
+	}
+
+	s = newTestSource()
+	o, _ = New([]Source{s}, func(string, currency.Code) float64 { return 0 },
+		Config{Currencies: []currency.Code{currency.ETH}}, nil)
+	o.Check()
+	if s.deployed["BTC"] != 0 {
+		t.Error("Test Failed - Check() expected currency not listed not deployed")
+	}
+}
+
+func TestUnbonding(t *testing.T) {
+	s := newTestSource()
+	s.delayed = true
+	s.available["BTC"] = 2
+	s.deployed["BTC"] = 8
+	o, _ := New([]Source{s}, func(string, currency.Code) float64 { return 5 }, Config{}, nil)
+	o.Check()
+	if s.unbonding["BTC"] != 3 || s.deployed["BTC"] != 5 {
+		t.Fatalf("Test Failed - Check() unexpected unbonding %v %v", s.unbonding, s.deployed)
+	}
+	// Funds already unbonding are not unwound again
+	o.Check()
+	if s.unbonding["BTC"] != 3 {
+		t.Errorf("Test Failed - Check() expected no further unwind, got %v", s.unbonding)
+	}
+}
+
+func TestRequire(t *testing.T) {
+	s := newTestSource()
+	s.available["BTC"] = 1
+	s.deployed["BTC"] = 5
+	o, _ := New([]Source{s}, func(string, currency.Code) float64 { return 0 }, Config{}, nil)
+	if err := o.Require("Test", currency.BTC, 0); err != errInvalidAmount {
+		t.Error("Test Failed - Require() expected invalid amount error", err)
+	}
+	if err := o.Require("Other", currency.BTC, 1); err == nil {
+		t.Error("Test Failed - Require() expected source not found error")
+	}
+	if err := o.Require("test", currency.BTC, 4); err != nil || s.available["BTC"] != 4 {
+		t.Error("Test Failed - Require() expected funds unwound", err, s.available)
+	}
+	err := o.Require("Test", currency.BTC, 10)
+	if err == nil || !strings.Contains(err.Error(), errInsufficient.Error()) {
+		t.Error("Test Failed - Require() expected insufficient error", err)
+	}
+	p, err := o.GetPositions()
+	if err != nil || len(p) != 1 || p[0].Amount != 0 {
+		t.Errorf("Test Failed - GetPositions() unexpected %+v %v", p, err)
+	}
+}
+
+func TestKrakenMatch(t *testing.T) {
+	if !krakenMatch("eth", currency.NewCode("XETH")) || !krakenMatch("XTZ", currency.NewCode("xtz")) {
+		t.Error("Test Failed - krakenMatch() expected match")
+	}
+	if krakenMatch("DOT", currency.NewCode("XTZ")) {
+		t.Error("Test Failed - krakenMatch() unexpected match")
+	}
+}
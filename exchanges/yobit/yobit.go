@@ -343,12 +343,26 @@ func (y *Yobit) SendHTTPRequest(path string, result interface{}) error {
 		y.HTTPDebugging)
 }
 
+// mutating returns whether the method changes exchange state. Every method
+// is a POST so they are classified by name
+func mutating(method string) bool {
+	switch method {
+	case privateTrade, privateCancelOrder, privateWithdrawCoinsToAddress,
+		privateCreateCoupon, privateRedeemCoupon:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends an authenticated HTTP request to Yobit
 func (y *Yobit) SendAuthenticatedHTTPRequest(path string, params url.Values, result interface{}) (err error) {
 	if !y.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet,
 			y.Name)
 	}
+	if y.DryRunRequest(mutating(path), path, params) {
+		return nil
+	}
 
 	if params == nil {
 		params = url.Values{}
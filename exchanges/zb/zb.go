@@ -386,11 +386,24 @@ func (z *ZB) SendHTTPRequest(path string, result interface{}) error {
 	return z.SendPayload(http.MethodGet, path, nil, nil, result, false, false, z.Verbose, z.HTTPDebugging)
 }
 
+// mutating returns whether the method changes exchange state. Every method
+// is sent as a GET so they are classified by name
+func mutating(method string) bool {
+	switch method {
+	case zbOrder, zbCancelOrder, zbWithdraw:
+		return true
+	}
+	return false
+}
+
 // SendAuthenticatedHTTPRequest sends authenticated requests to the zb API
 func (z *ZB) SendAuthenticatedHTTPRequest(httpMethod string, params url.Values, result interface{}) error {
 	if !z.AuthenticatedAPISupport {
 		return fmt.Errorf(exchange.WarningAuthenticatedRequestWithoutCredentialsSet, z.Name)
 	}
+	if z.DryRunRequest(mutating(params.Get("method")), params.Get("method"), params) {
+		return nil
+	}
 
 	params.Set("accesskey", z.APIKey)
 
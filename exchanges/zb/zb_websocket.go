@@ -98,8 +98,11 @@ func (z *ZB) WsHandleData() {
 					AssetType:  "SPOT",
 					Exchange:   z.GetName(),
 					ClosePrice: ticker.Data.Last,
+					Quantity:   ticker.Data.Volume24Hr,
 					HighPrice:  ticker.Data.High,
 					LowPrice:   ticker.Data.Low,
+					BidPrice:   ticker.Data.Buy,
+					AskPrice:   ticker.Data.Sell,
 				}
 
 			case common.StringContains(result.Channel, "depth"):
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/dropcopy"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/tca"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+	"github.com/thrasher-corp/gocryptotrader/strategy"
+)
+
+// ActivateExecutionQuality starts checking each strategy's recent maker/taker
+// ratio, spread capture and adverse selection against its earlier fills,
+// alerting when execution quality regresses
+func ActivateExecutionQuality() {
+	if !bot.executionQuality {
+		return
+	}
+
+	m := tca.NewMonitor(tca.DefaultAdverseHorizon, tca.DefaultRecentWindow,
+		tca.DefaultThresholds, handleExecutionQualityRegression)
+	m.Start(tca.DefaultQualityCheckInterval)
+	bot.executionQualityMonitor = m
+	log.Debugf("Execution quality monitor enabled.")
+}
+
+// recordStrategyFill records a strategy fill for transaction cost analysis.
+// Market orders are takers, limit orders are makers unless they crossed the
+// spread observed when they were submitted
+func recordStrategyFill(f *strategy.Fill) {
+	e := tca.Execution{
+		Exchange:       f.Exchange,
+		Strategy:       f.Strategy,
+		Pair:           f.Pair,
+		AssetType:      pairAssetType(f.Exchange, f.Pair),
+		OrderID:        f.OrderID,
+		Side:           string(f.Side),
+		ExecutionPrice: f.Price,
+		Amount:         f.Amount,
+		Maker:          f.OrderType != exchange.MarketOrderType,
+		Timestamp:      f.Timestamp,
+	}
+	s, err := tca.GetSpreadAt(f.Exchange, f.Pair, e.AssetType, f.Submitted)
+	if err == nil {
+		e.ArrivalMid = s.Mid
+		if e.Maker {
+			e.Maker = tca.IsMaker(e.Side, f.Price, &s)
+		}
+	}
+
+	err = tca.AddExecution(&e)
+	if err != nil {
+		log.Debugf("Failed to record strategy %s %s fill. Error: %s",
+			f.Strategy, f.Exchange, err)
+	}
+}
+
+// recordDropCopyExecution records a mirrored order fill for transaction cost
+// analysis, measured against the mid observed when the order was placed
+func recordDropCopyExecution(e *dropcopy.Event) {
+	if e.Order == nil || e.Type != dropcopy.OrderFill || e.Filled <= 0 {
+		return
+	}
+	execution := tca.Execution{
+		Exchange:       e.Exchange,
+		Pair:           e.Order.CurrencyPair,
+		AssetType:      pairAssetType(e.Exchange, e.Order.CurrencyPair),
+		OrderID:        e.Order.ID,
+		Side:           string(e.Order.OrderSide),
+		ExecutionPrice: e.Order.Price,
+		Amount:         e.Filled,
+		Maker:          e.Order.OrderType != exchange.MarketOrderType,
+		Timestamp:      e.Timestamp,
+	}
+	s, err := tca.GetSpreadAt(e.Exchange, e.Order.CurrencyPair, execution.AssetType, e.Order.OrderDate)
+	if err == nil {
+		execution.ArrivalMid = s.Mid
+		if execution.Maker {
+			execution.Maker = tca.IsMaker(execution.Side, e.Order.Price, &s)
+		}
+	}
+
+	err = tca.AddExecution(&execution)
+	if err != nil {
+		log.Debugf("Failed to record %s order %s fill. Error: %s",
+			e.Exchange, e.Order.ID, err)
+	}
+}
+
+// pairAssetType returns the asset type a pair trades as on an exchange, the
+// first of its asset types with a ticker stored for the pair
+func pairAssetType(exchName string, p currency.Pair) string {
+	assetTypes, err := exchange.GetExchangeAssetTypes(exchName)
+	if err != nil || len(assetTypes) == 0 {
+		return ticker.Spot
+	}
+	for i := range assetTypes {
+		if _, err = ticker.GetTicker(exchName, p, assetTypes[i]); err == nil {
+			return assetTypes[i]
+		}
+	}
+	return assetTypes[0]
+}
+
+func handleExecutionQualityRegression(r tca.Regression) {
+	log.Warnf("Execution quality: %s", r.String())
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "EXECUTION_QUALITY_ALERT",
+			TradeDetails: r.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(r, "execution_quality", "", "")
+	}
+}
+
+// GetStrategyFillQuality returns the maker/taker ratio, spread capture and
+// adverse selection of each strategy's fills
+func GetStrategyFillQuality() []tca.StrategyReport {
+	if bot.executionQualityMonitor != nil {
+		return bot.executionQualityMonitor.GetReports()
+	}
+	return tca.GetStrategyReports(tca.DefaultAdverseHorizon, tca.DefaultRecentWindow)
+}
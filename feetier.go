@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/feetier"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/huobi"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/poloniex"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// feeTierFile persists the fee tiers in the data directory
+const feeTierFile = "feetiers.json"
+
+var errFeeTiersDisabled = errors.New("fee tier tracking not enabled")
+
+// FeeTiersResponse holds each exchange's fee tier and the tier changes seen
+type FeeTiersResponse struct {
+	Tiers   []feetier.Status `json:"tiers"`
+	Changes []feetier.Change `json:"changes"`
+}
+
+// ActivateFeeTiers starts tracking the fee tier of every exchange with
+// authenticated API support which reports it, alerting when the trading
+// volume nears a tier with meaningfully lower fees
+func ActivateFeeTiers() {
+	if !bot.feeTiers {
+		return
+	}
+
+	var cfg feetier.Config
+	if bot.feeTierConfig != "" {
+		var err error
+		cfg, err = feetier.LoadConfig(bot.feeTierConfig)
+		if err != nil {
+			log.Errorf("Fee tier tracking failed to load from %s: %s", bot.feeTierConfig, err)
+			return
+		}
+	}
+
+	var sources []feetier.Source
+	for _, exch := range GetLoadedExchanges() {
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		switch e := exchange.Underlying(exch).(type) {
+		case *bitmex.Bitmex:
+			sources = append(sources, &feetier.BitmexTier{Exchange: e})
+		case *huobi.HUOBI:
+			sources = append(sources, &feetier.HuobiTier{Exchange: e})
+		case *kraken.Kraken:
+			sources = append(sources, &feetier.KrakenTier{Exchange: e})
+		case *poloniex.Poloniex:
+			sources = append(sources, &feetier.PoloniexTier{Exchange: e})
+		}
+	}
+
+	path := filepath.Join(bot.dataDir, feeTierFile)
+	t, err := feetier.New(cfg, path, sources, handleFeeTierEvent)
+	if err != nil {
+		log.Errorf("Fee tier tracking failed to start: %s", err)
+		return
+	}
+	t.Start(bot.feeTierInterval)
+	bot.feeTierTracker = t
+	log.Debugf("Fee tier tracking enabled for %d exchanges, persisting to %s.", len(sources), path)
+}
+
+func handleFeeTierEvent(e feetier.Event) {
+	if e.Type == feetier.Approaching {
+		log.Warnf("Fee tier: %s", e.String())
+	}
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e, "fee_tier", "", e.Tier.Exchange)
+	}
+}
+
+// GetFeeTiers returns each exchange's fee tier and the tier changes seen
+func GetFeeTiers() (FeeTiersResponse, error) {
+	if bot.feeTierTracker == nil {
+		return FeeTiersResponse{}, errFeeTiersDisabled
+	}
+	return FeeTiersResponse{
+		Tiers:   bot.feeTierTracker.GetTiers(),
+		Changes: bot.feeTierTracker.GetChanges(),
+	}, nil
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"time"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/funding"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okex"
+)
+
+// fundingForecaster returns a funding forecaster for the loaded exchanges
+// which publish perpetual swap funding rates
+func fundingForecaster() *funding.Forecaster {
+	var sources []funding.Source
+	for _, exch := range GetLoadedExchanges() {
+		switch e := exchange.Underlying(exch).(type) {
+		case *bitmex.Bitmex:
+			sources = append(sources, &funding.BitmexSource{Exchange: e})
+		case *okex.OKEX:
+			sources = append(sources, &funding.OKEXSource{Exchange: e})
+		}
+	}
+	return funding.New(sources...)
+}
+
+// GetFundingForecast forecasts the funding rate of a perpetual swap over the
+// number of funding periods, the default number of periods are forecast when
+// zero
+func GetFundingForecast(exchName, instrument string, periods int) (*funding.Forecast, error) {
+	if GetExchangeByName(exchName) == nil {
+		return nil, ErrExchangeNotFound
+	}
+	if periods == 0 {
+		periods = funding.DefaultPeriods
+	}
+	return fundingForecaster().Forecast(exchName, instrument, periods)
+}
+
+// GetFundingCarry compares holding a position through a perpetual swap until
+// a dated future expires against holding the dated future, so strategies can
+// choose the cheaper instrument
+func GetFundingCarry(exchName, perpetual string, futuresPrice, indexPrice float64, expiry time.Time, long bool) (funding.Carry, error) {
+	if GetExchangeByName(exchName) == nil {
+		return funding.Carry{}, ErrExchangeNotFound
+	}
+	return fundingForecaster().CompareCarry(exchName, perpetual, futuresPrice, indexPrice, expiry, long)
+}
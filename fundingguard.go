@@ -0,0 +1,138 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/funding"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/fundingguard"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okex"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var (
+	errFundingGuardDisabled = errors.New("funding guard not enabled")
+	errInvalidFundingHedge  = errors.New("funding guard hedges must be in the format EXCHANGE:INSTRUMENT=EXCHANGE:PAIR")
+)
+
+// readOnlyFundingVenue rejects closing and reopening positions on a venue
+// whose exchange is read only or simulating requests in dry run mode, so the
+// positions the guard would act on are still reported
+type readOnlyFundingVenue struct {
+	fundingguard.Venue
+}
+
+// ClosePosition is disabled for read only venues
+func (readOnlyFundingVenue) ClosePosition(_ *fundingguard.Position) (string, error) {
+	return "", exchange.ErrReadOnlyExchange
+}
+
+// OpenPosition is disabled for read only venues
+func (readOnlyFundingVenue) OpenPosition(_ *fundingguard.Position) (string, error) {
+	return "", exchange.ErrReadOnlyExchange
+}
+
+// ActivateFundingGuard starts closing or hedging BitMEX and OKEX perpetual
+// swap positions ahead of costly funding. Positions are closed through the
+// exchange API directly, so read only and dry run exchanges only report them
+func ActivateFundingGuard() {
+	if !bot.fundingGuard {
+		return
+	}
+
+	hedges, err := parseFundingHedges(bot.fundingGuardHedges)
+	if err != nil {
+		log.Errorf("Funding guard failed to start: %s", err)
+		return
+	}
+
+	var venues []fundingguard.Venue
+	for _, exch := range GetLoadedExchanges() {
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		var v fundingguard.Venue
+		switch e := exchange.Underlying(exch).(type) {
+		case *bitmex.Bitmex:
+			v = &fundingguard.BitmexVenue{BitmexSource: funding.BitmexSource{Exchange: e}}
+		case *okex.OKEX:
+			v = &fundingguard.OKEXVenue{OKEXSource: funding.OKEXSource{Exchange: e}}
+		default:
+			continue
+		}
+		if exchange.IsReadOnly(exch) || bot.dryRun {
+			v = readOnlyFundingVenue{v}
+		}
+		venues = append(venues, v)
+	}
+
+	path := filepath.Join(bot.dataDir, "fundingguard.json")
+	m, err := fundingguard.New(path, fundingguard.Config{
+		Lead:    bot.fundingGuardLead,
+		MinRate: bot.fundingGuardMinRate,
+		FeeRate: bot.fundingGuardFeeRate,
+		Hedges:  hedges,
+	}, venues, GetExchangeByName, handleFundingGuardEvent)
+	if err != nil {
+		log.Errorf("Funding guard failed to start: %s", err)
+		return
+	}
+	m.Start(fundingguard.DefaultCheckInterval)
+	bot.fundingGuardManager = m
+	log.Debugf("Funding guard enabled for %d venues, persisting to %s.", len(venues), path)
+}
+
+// parseFundingHedges parses hedges in the format
+// Bitmex:XBTUSD=Binance:BTC-USDT,OKEX:BTC-USD-SWAP=Binance:BTC-USDT
+func parseFundingHedges(s string) ([]fundingguard.Hedge, error) {
+	if s == "" {
+		return nil, nil
+	}
+	var hedges []fundingguard.Hedge
+	for _, h := range strings.Split(s, ",") {
+		legs := strings.Split(strings.TrimSpace(h), "=")
+		if len(legs) != 2 {
+			return nil, errInvalidFundingHedge
+		}
+		perpetual := strings.SplitN(legs[0], ":", 2)
+		hedge := strings.SplitN(legs[1], ":", 2)
+		if len(perpetual) != 2 || len(hedge) != 2 || perpetual[0] == "" || perpetual[1] == "" ||
+			hedge[0] == "" || hedge[1] == "" {
+			return nil, errInvalidFundingHedge
+		}
+		hedges = append(hedges, fundingguard.Hedge{
+			Exchange:      perpetual[0],
+			Instrument:    perpetual[1],
+			HedgeExchange: hedge[0],
+			Pair:          currency.NewPairDelimiter(hedge[1], "-"),
+		})
+	}
+	return hedges, nil
+}
+
+func handleFundingGuardEvent(e fundingguard.Event) {
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Action, "funding_guard_event", "", e.Action.Decision.Position.Exchange)
+	}
+}
+
+// GetFundingGuardStatus returns the funding guard's latest decision for each
+// position and every position it has closed or hedged
+func GetFundingGuardStatus() (fundingguard.Status, error) {
+	if bot.fundingGuardManager == nil {
+		return fundingguard.Status{}, errFundingGuardDisabled
+	}
+	return bot.fundingGuardManager.GetStatus(), nil
+}
@@ -6,5 +6,8 @@ require (
 	github.com/google/go-querystring v1.0.0
 	github.com/gorilla/mux v1.7.3
 	github.com/gorilla/websocket v1.4.0
+	github.com/lib/pq v1.7.0
+	github.com/mattn/go-sqlite3 v1.14.6
 	golang.org/x/crypto v0.0.0-20190513172903-22d7a77e9e5f
+	gopkg.in/yaml.v2 v2.4.0
 )
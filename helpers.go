@@ -6,6 +6,7 @@ import (
 
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/converter"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/stats"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
@@ -253,6 +254,40 @@ func GetSpecificOrderbook(currencyPair, exchangeName, assetType string) (orderbo
 	return specificOrderbook, err
 }
 
+// GetOrderbookDepth returns the best levels of an exchange's orderbook for a
+// pair, or the orderbooks of every loaded exchange consolidated by price when
+// no exchange is named. Only orderbooks already held in memory, maintained by
+// websocket feeds or REST polling, are read
+func GetOrderbookDepth(exchName string, p currency.Pair, assetType string, levels int) (orderbook.Depth, error) {
+	if exchName != "" {
+		exch := GetExchangeByName(exchName)
+		if exch == nil {
+			return orderbook.Depth{}, ErrExchangeNotFound
+		}
+		ob, err := orderbook.Get(exch.GetName(), p, assetType)
+		if err != nil {
+			return orderbook.Depth{}, err
+		}
+		if ob.Pair.IsEmpty() {
+			return orderbook.Depth{}, fmt.Errorf("no %s %s %s orderbook held", exch.GetName(), p, assetType)
+		}
+		return ob.Depth(levels), nil
+	}
+
+	var books []orderbook.Base
+	for _, exch := range GetLoadedExchanges() {
+		ob, err := orderbook.Get(exch.GetName(), p, assetType)
+		if err != nil || (len(ob.Bids) == 0 && len(ob.Asks) == 0) {
+			continue
+		}
+		books = append(books, ob)
+	}
+	if len(books) == 0 {
+		return orderbook.Depth{}, fmt.Errorf("no %s %s orderbooks held", p, assetType)
+	}
+	return orderbook.Consolidate(books, levels)
+}
+
 // GetSpecificTicker returns a specific ticker given the currency,
 // exchangeName and assetType
 func GetSpecificTicker(currencyPair, exchangeName, assetType string) (ticker.Price, error) {
@@ -420,3 +455,43 @@ func SeedExchangeAccountInfo(data []exchange.AccountInfo) {
 		}
 	}
 }
+
+// ConvertAsset quotes the best route for converting an amount of one
+// currency into another on the named exchange, executing each leg as a
+// market order when execute is set
+func ConvertAsset(exchName, from, to string, amount float64, execute bool) (converter.Result, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return converter.Result{}, ErrExchangeNotFound
+	}
+
+	fromCode := currency.NewCode(from)
+	toCode := currency.NewCode(to)
+	if !execute {
+		return converter.Quote(exch, fromCode, toCode, amount, converter.DefaultFeeRate)
+	}
+
+	result, err := converter.Convert(exch, fromCode, toCode, amount, converter.DefaultFeeRate)
+	if err != nil {
+		return result, err
+	}
+	log.Debugf("Converted %f %s to %s on %s via %s at an effective rate of %f\n",
+		amount,
+		fromCode,
+		toCode,
+		exchName,
+		result.Route,
+		result.EffectiveRate)
+	return result, nil
+}
+
+// GetLoadedExchanges returns all loaded exchanges
+func GetLoadedExchanges() []exchange.IBotExchange {
+	exchanges := make([]exchange.IBotExchange, 0, len(bot.exchanges))
+	for x := range bot.exchanges {
+		if bot.exchanges[x] != nil {
+			exchanges = append(exchanges, bot.exchanges[x])
+		}
+	}
+	return exchanges
+}
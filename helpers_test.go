@@ -3,12 +3,15 @@ package main
 import (
 	"testing"
 
+	"github.com/thrasher-corp/gocryptotrader/addressbook"
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/stats"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/tca"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 )
 
@@ -296,6 +299,48 @@ func TestGetSpecificOrderbook(t *testing.T) {
 	UnloadExchange("Bitstamp")
 }
 
+func TestGetOrderbookDepth(t *testing.T) {
+	SetupTestHelpers(t)
+
+	LoadExchange("Bitstamp", false, nil)
+
+	p := currency.NewPair(currency.BTC, currency.EUR)
+	base := orderbook.Base{
+		Pair:         p,
+		Bids:         []orderbook.Item{{Price: 1000, Amount: 1}, {Price: 999, Amount: 2}},
+		Asks:         []orderbook.Item{{Price: 1001, Amount: 1}},
+		ExchangeName: "Bitstamp",
+		AssetType:    orderbook.Spot,
+	}
+	err := base.Process()
+	if err != nil {
+		t.Fatal("Unexpected result", err)
+	}
+
+	d, err := GetOrderbookDepth("Bitstamp", p, orderbook.Spot, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Bids) != 1 || d.Bids[0].Price != 1000 || len(d.Asks) != 1 {
+		t.Errorf("Test Failed - GetOrderbookDepth() unexpected depth %+v", d)
+	}
+
+	d, err = GetOrderbookDepth("", p, orderbook.Spot, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Exchanges) != 1 || len(d.Bids) != 2 || d.Bids[1].Exchanges["Bitstamp"] != 2 {
+		t.Errorf("Test Failed - GetOrderbookDepth() unexpected consolidated depth %+v", d)
+	}
+
+	if _, err = GetOrderbookDepth("", currency.NewPair(currency.ETH, currency.EUR), orderbook.Spot, 0); err == nil {
+		t.Error("Test Failed - GetOrderbookDepth() expected no orderbooks error")
+	}
+	if _, err = GetOrderbookDepth("Bitstamp", p, "FUTURES", 0); err == nil {
+		t.Error("Test Failed - GetOrderbookDepth() expected no orderbook error")
+	}
+}
+
 func TestGetSpecificTicker(t *testing.T) {
 	SetupTestHelpers(t)
 
@@ -435,6 +480,48 @@ func TestGetExchangeHighestPriceByCurrencyPair(t *testing.T) {
 	}
 }
 
+func TestGetVenueRoutes(t *testing.T) {
+	SetupTestHelpers(t)
+
+	p := currency.NewPairFromStrings("LTC", "EUR")
+	stats.Add("Kraken", p, ticker.Spot, 100, 10000)
+	stats.Add("Bitstamp", p, ticker.Spot, 99.9, 10000)
+	err := tca.AddExecution(&tca.Execution{
+		Exchange:       "Bitstamp",
+		Pair:           p,
+		AssetType:      ticker.Spot,
+		Side:           "BUY",
+		ArrivalMid:     99.9,
+		ExecutionPrice: 100.4,
+		Amount:         1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Bitstamp's cheaper price does not cover its costs
+	routes, err := GetVenueRoutes(p, ticker.Spot, exchange.BuyOrderSide)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(routes) != 2 || routes[0].Exchange != "Kraken" || routes[1].CostBps <= 0 {
+		t.Errorf("Test Failed - GetVenueRoutes() expected Kraken first %+v", routes)
+	}
+
+	routes, err = GetVenueRoutes(p, ticker.Spot, exchange.SellOrderSide)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if routes[0].Exchange != "Kraken" {
+		t.Errorf("Test Failed - GetVenueRoutes() expected Kraken first selling %+v", routes)
+	}
+
+	_, err = GetVenueRoutes(currency.NewPairFromStrings("LTC", "AUD"), ticker.Spot, exchange.BuyOrderSide)
+	if err != errNoVenues {
+		t.Error("Test Failed - GetVenueRoutes() expected no venues error", err)
+	}
+}
+
 func TestGetExchangeLowestPriceByCurrencyPair(t *testing.T) {
 	SetupTestHelpers(t)
 
@@ -455,3 +542,143 @@ func TestGetExchangeLowestPriceByCurrencyPair(t *testing.T) {
 		t.Error("Unexpected reuslt")
 	}
 }
+
+func TestConvertAsset(t *testing.T) {
+	SetupTestHelpers(t)
+
+	_, err := ConvertAsset("ASDF", "BTC", "USD", 1, false)
+	if err != ErrExchangeNotFound {
+		t.Fatalf("Test Failed - ConvertAsset() expected %v, received %v",
+			ErrExchangeNotFound, err)
+	}
+
+	_, err = ConvertAsset("Bitstamp", "BTC", "BTC", 1, false)
+	if err == nil {
+		t.Fatal("Test Failed - ConvertAsset() expected error converting to the same currency")
+	}
+}
+
+func TestParseBorrowRateThresholds(t *testing.T) {
+	thresholds, err := parseBorrowRateThresholds("BTC:20, eth:7.5")
+	if err != nil {
+		t.Fatal("Test Failed - parseBorrowRateThresholds() error", err)
+	}
+	if thresholds[currency.BTC] != 0.2 || thresholds[currency.ETH] != 0.075 {
+		t.Errorf("Test Failed - parseBorrowRateThresholds() unexpected thresholds %v",
+			thresholds)
+	}
+
+	for _, s := range []string{"BTC", "BTC:abc", ":20", "BTC:20,"} {
+		if _, err = parseBorrowRateThresholds(s); err != errInvalidBorrowThreshold {
+			t.Errorf("Test Failed - parseBorrowRateThresholds() %s expected error", s)
+		}
+	}
+}
+
+func TestParseTradingRuleOverrides(t *testing.T) {
+	overrides, err := parseTradingRuleOverrides("Binance=nearest/truncate, Bitstamp:BTC-USD=/up/0.01/")
+	if err != nil {
+		t.Fatal("Test Failed - parseTradingRuleOverrides() error", err)
+	}
+	if overrides["Binance"].PriceRounding != exchange.RoundNearest ||
+		overrides["Binance"].AmountRounding != exchange.RoundDown {
+		t.Errorf("Test Failed - parseTradingRuleOverrides() unexpected exchange override %+v",
+			overrides["Binance"])
+	}
+	o := overrides["Bitstamp:BTC-USD"]
+	if o.PriceRounding != "" || o.AmountRounding != exchange.RoundUp ||
+		o.PriceStep != 0.01 || o.AmountStep != 0 {
+		t.Errorf("Test Failed - parseTradingRuleOverrides() unexpected pair override %+v", o)
+	}
+
+	for _, s := range []string{"Binance", "=down/down", "Binance=down", "Binance=down/down/0.1", "Binance=down/down/abc/0.1"} {
+		if _, err = parseTradingRuleOverrides(s); err != errInvalidTradingRuleOverride {
+			t.Errorf("Test Failed - parseTradingRuleOverrides() %s expected error", s)
+		}
+	}
+	if _, err = parseTradingRuleOverrides("Binance=sideways/down"); err == nil {
+		t.Error("Test Failed - parseTradingRuleOverrides() expected unknown rounding mode error")
+	}
+}
+
+func TestParseBitmexMarginSettings(t *testing.T) {
+	settings, err := parseBitmexMarginSettings("xbtusd=isolated/10/0.5, ETHUSD=Cross")
+	if err != nil {
+		t.Fatal("Test Failed - parseBitmexMarginSettings() error", err)
+	}
+	if len(settings) != 2 || settings[0].Symbol != "XBTUSD" ||
+		settings[0].Mode != bitmex.IsolatedMargin || settings[0].Leverage != 10 ||
+		settings[0].Buffer != 0.5 || settings[0].LiquidationDistance != 0 {
+		t.Errorf("Test Failed - parseBitmexMarginSettings() unexpected isolated settings %+v", settings)
+	}
+	if settings[1].Symbol != "ETHUSD" || settings[1].Mode != bitmex.CrossMargin {
+		t.Errorf("Test Failed - parseBitmexMarginSettings() unexpected cross settings %+v", settings[1])
+	}
+
+	for _, s := range []string{"XBTUSD", "=cross", "XBTUSD=cross/10", "XBTUSD=isolated", "XBTUSD=isolated/abc", "XBTUSD=isolated/1/2/3/4", "XBTUSD=hedged"} {
+		if _, err = parseBitmexMarginSettings(s); err != errInvalidBitmexMarginSpec {
+			t.Errorf("Test Failed - parseBitmexMarginSettings() %s expected error", s)
+		}
+	}
+}
+
+func TestParseColdStorageAddresses(t *testing.T) {
+	addresses, err := parseColdStorageAddresses("btc:1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy, ETH:0xb794f5ea0ba39494ce839613fffba74279579268")
+	if err != nil {
+		t.Fatal("Test Failed - parseColdStorageAddresses() error", err)
+	}
+	if len(addresses) != 2 || addresses[0].CoinType != currency.BTC ||
+		addresses[1].Address != "0xb794f5ea0ba39494ce839613fffba74279579268" {
+		t.Errorf("Test Failed - parseColdStorageAddresses() unexpected addresses %v", addresses)
+	}
+
+	for _, s := range []string{"BTC", "BTC:", ":address", "BTC:a:b"} {
+		if _, err = parseColdStorageAddresses(s); err != errInvalidColdStorageSpec {
+			t.Errorf("Test Failed - parseColdStorageAddresses() %s expected error", s)
+		}
+	}
+
+	weights, err := parseTargetWeights("btc:40, USD:60")
+	if err != nil {
+		t.Fatal("Test Failed - parseTargetWeights() error", err)
+	}
+	if weights[currency.BTC] != 0.4 || weights[currency.USD] != 0.6 {
+		t.Errorf("Test Failed - parseTargetWeights() unexpected weights %v", weights)
+	}
+	if _, err = parseTargetWeights("BTC:abc"); err != errInvalidTargetWeights {
+		t.Error("Test Failed - parseTargetWeights() expected error", err)
+	}
+}
+
+func TestParseVenueTargets(t *testing.T) {
+	targets, err := parseVenueTargets("Binance:60, Kraken:40")
+	if err != nil {
+		t.Fatal("Test Failed - parseVenueTargets() error", err)
+	}
+	if targets["Binance"] != 60 || targets["Kraken"] != 40 {
+		t.Errorf("Test Failed - parseVenueTargets() unexpected targets %v", targets)
+	}
+	for _, s := range []string{"Binance", ":60", "Binance:abc"} {
+		if _, err = parseVenueTargets(s); err != errInvalidVenueTargets {
+			t.Errorf("Test Failed - parseVenueTargets() %s expected error", s)
+		}
+	}
+}
+
+func TestWithdrawCryptocurrencyFunds(t *testing.T) {
+	req := &exchange.WithdrawRequest{Currency: currency.BTC, Address: "address", Amount: 1}
+	_, err := WithdrawCryptocurrencyFunds("Bitstamp", req)
+	if err != errAddressBookNotLoaded {
+		t.Error("Test Failed - WithdrawCryptocurrencyFunds() expected address book not loaded error", err)
+	}
+
+	bot.addressBook, err = addressbook.Load("")
+	if err != nil {
+		t.Fatal("Test Failed - addressbook.Load() error", err)
+	}
+	defer func() { bot.addressBook = nil }()
+	_, err = WithdrawCryptocurrencyFunds("nonexistent", req)
+	if err != ErrExchangeNotFound {
+		t.Error("Test Failed - WithdrawCryptocurrencyFunds() expected exchange not found error", err)
+	}
+}
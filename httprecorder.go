@@ -0,0 +1,43 @@
+package main
+
+import (
+	"os"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/httprecorder"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// ActivateHTTPRecorder starts recording redacted HTTP request and response
+// pairs for exchanges with HTTP debugging enabled. The record file can be
+// replayed as fixtures with the httprecorder package
+func ActivateHTTPRecorder() {
+	if bot.httpRecordFile == "" {
+		return
+	}
+
+	var debugging []string
+	for i := range bot.config.Exchanges {
+		if bot.config.Exchanges[i].Enabled && bot.config.Exchanges[i].HTTPDebugging {
+			debugging = append(debugging, bot.config.Exchanges[i].Name)
+		}
+	}
+	if len(debugging) == 0 {
+		log.Warnf("HTTP recorder enabled but no exchanges have HTTP debugging enabled, nothing will be recorded.")
+	}
+
+	f, err := os.OpenFile(bot.httpRecordFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0600)
+	if err != nil {
+		log.Errorf("HTTP recorder failed to start: %s", err)
+		return
+	}
+
+	r, err := httprecorder.NewRecorder(f)
+	if err != nil {
+		f.Close()
+		log.Errorf("HTTP recorder failed to start: %s", err)
+		return
+	}
+	httprecorder.Enable(r)
+	bot.httpRecorderFile = f
+	log.Debugf("HTTP recorder enabled for %v, recording to %s.", debugging, bot.httpRecordFile)
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/imbalance"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errImbalanceDisabled = errors.New("imbalance orders not enabled")
+
+// ActivateImbalanceOrders starts working imbalance orders, resuming the open
+// orders stored in the data directory
+func ActivateImbalanceOrders() {
+	if !bot.imbalanceOrders {
+		return
+	}
+
+	path := filepath.Join(bot.dataDir, "imbalance.json")
+	m, err := imbalance.New(path, GetExchangeByName, imbalanceBook, handleImbalanceEvent)
+	if err != nil {
+		log.Errorf("Imbalance order manager failed to load from %s: %s", path, err)
+		return
+	}
+	m.Start(imbalance.DefaultCheckInterval)
+	bot.imbalanceManager = m
+	log.Debugf("Imbalance order manager enabled, persisting to %s.", path)
+}
+
+// imbalanceBook returns the stored orderbook kept current by the websocket or
+// orderbook routines, fetching it when none is stored yet
+func imbalanceBook(exchName string, p currency.Pair, assetType string) (orderbook.Base, error) {
+	if assetType == "" {
+		assetType = orderbook.Spot
+	}
+	o, err := orderbook.Get(exchName, p, assetType)
+	if err == nil {
+		return o, nil
+	}
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return orderbook.Base{}, ErrExchangeNotFound
+	}
+	return exch.UpdateOrderbook(p, assetType)
+}
+
+func handleImbalanceEvent(e imbalance.Event) {
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Order, "imbalance_event", "", e.Order.Exchange)
+	}
+}
+
+// strategyImbalanceOrder submits an imbalance order through a strategy's
+// exchange so the strategy's tags and limits apply to the orders placed
+func strategyImbalanceOrder(exch exchange.IBotExchange, order *exchange.ImbalanceOrder) (string, error) {
+	o, err := bot.imbalanceManager.SubmitVia(exch, order)
+	if err != nil {
+		return "", err
+	}
+	return o.ID, nil
+}
+
+// SubmitImbalanceOrder submits an imbalance order on the named exchange
+func SubmitImbalanceOrder(exchName string, order *exchange.ImbalanceOrder) (imbalance.Order, error) {
+	if bot.imbalanceManager == nil {
+		return imbalance.Order{}, errImbalanceDisabled
+	}
+	return bot.imbalanceManager.Submit(exchName, order)
+}
+
+// CancelImbalanceOrder cancels an open imbalance order
+func CancelImbalanceOrder(id string) (imbalance.Order, error) {
+	if bot.imbalanceManager == nil {
+		return imbalance.Order{}, errImbalanceDisabled
+	}
+	return bot.imbalanceManager.Cancel(id)
+}
+
+// GetImbalanceOrders returns every imbalance order submitted through the bot
+func GetImbalanceOrders() ([]imbalance.Order, error) {
+	if bot.imbalanceManager == nil {
+		return nil, errImbalanceDisabled
+	}
+	return bot.imbalanceManager.List(), nil
+}
@@ -0,0 +1,112 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/inventory"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var (
+	errInventorySkewDisabled = errors.New("inventory skew not enabled")
+	errInvalidVenueTargets   = errors.New("venue targets must be in the format EXCHANGE:SHARE")
+)
+
+// ActivateInventorySkew skews the quotes of strategies quoting on several
+// venues by where inventory is held, combining the roll-up's balances with the
+// break-even tracker's positions
+func ActivateInventorySkew() {
+	if bot.inventorySkewBps <= 0 {
+		return
+	}
+	if bot.balanceRollup == nil && bot.breakEvenTracker == nil {
+		log.Errorf("Inventory skew requires -rollup or -breakeven to locate inventory.")
+		return
+	}
+
+	cfg := inventory.Config{
+		MaxSkewBps:  bot.inventorySkewBps,
+		MaxSizeSkew: bot.inventorySizeSkew,
+	}
+	if bot.inventoryTargets != "" {
+		targets, err := parseVenueTargets(bot.inventoryTargets)
+		if err != nil {
+			log.Errorf("Inventory skew failed to parse venue targets: %s", err)
+			return
+		}
+		cfg.Targets = targets
+	}
+	s, err := inventory.New(cfg, inventoryHoldings)
+	if err != nil {
+		log.Errorf("Inventory skew failed to start: %s", err)
+		return
+	}
+	bot.inventorySkew = s
+	log.Debugf("Inventory skew enabled, skewing quotes up to %v bps.", bot.inventorySkewBps)
+}
+
+// parseVenueTargets parses venue target shares in the format Binance:60,Kraken:40
+func parseVenueTargets(s string) (map[string]float64, error) {
+	targets := make(map[string]float64)
+	for _, t := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(t), ":")
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, errInvalidVenueTargets
+		}
+		share, err := strconv.ParseFloat(parts[1], 64)
+		if err != nil {
+			return nil, errInvalidVenueTargets
+		}
+		targets[parts[0]] = share
+	}
+	return targets, nil
+}
+
+// inventoryHoldings returns the balance of a currency on each exchange plus
+// the size of positions in pairs based on it
+func inventoryHoldings(c currency.Code) ([]inventory.Holding, error) {
+	var resp []inventory.Holding
+	if bot.balanceRollup != nil {
+		for _, h := range bot.balanceRollup.GetHoldings() {
+			if h.Currency.Match(c) {
+				resp = append(resp, inventory.Holding{Exchange: h.Exchange, Amount: h.Total})
+			}
+		}
+	}
+	if bot.breakEvenTracker != nil {
+		for _, p := range bot.breakEvenTracker.GetPositions() {
+			if !p.Pair.Base.Match(c) {
+				continue
+			}
+			size := p.Size
+			if p.Inverse {
+				// Inverse positions are sized in the quote currency
+				if p.MarkPrice <= 0 {
+					continue
+				}
+				size /= p.MarkPrice
+			}
+			resp = append(resp, inventory.Holding{Exchange: p.Exchange, Amount: size})
+		}
+	}
+	return resp, nil
+}
+
+// strategyInventorySkew skews a strategy's quote on a venue
+func strategyInventorySkew(exchName string, p currency.Pair, q inventory.Quote) (inventory.Quote, error) {
+	if bot.inventorySkew == nil {
+		return q, errInventorySkewDisabled
+	}
+	return bot.inventorySkew.Apply(exchName, p, q)
+}
+
+// GetInventorySkews returns the skew of each venue's quotes of a currency
+func GetInventorySkews(c currency.Code) ([]inventory.Skew, error) {
+	if bot.inventorySkew == nil {
+		return nil, errInventorySkewDisabled
+	}
+	return bot.inventorySkew.GetSkews(c)
+}
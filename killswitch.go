@@ -0,0 +1,103 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/killswitch"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errKillSwitchDisabled = errors.New("pair kill switch not enabled")
+
+// KillSwitchResponse holds a disabled pair and the resting orders cancelled on
+// each exchange when it was disabled
+type KillSwitchResponse struct {
+	Halt      killswitch.Halt
+	Cancelled []exchange.CancelOrdersResult
+}
+
+// ActivatePairKillSwitch guards every loaded exchange so trading of individual
+// pairs can be disabled across all strategies and exchanges. Disabled pairs
+// are stored in killswitch.json in the data directory and stay disabled
+// across restarts
+func ActivatePairKillSwitch() {
+	if !bot.killSwitch {
+		return
+	}
+
+	path := filepath.Join(bot.dataDir, "killswitch.json")
+	s, err := killswitch.New(path, handleKillSwitchEvent)
+	if err != nil {
+		log.Errorf("Pair kill switch failed to load from %s: %s", path, err)
+		return
+	}
+
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		bot.exchanges[x] = s.Guard(bot.exchanges[x])
+	}
+	bot.pairKillSwitch = s
+
+	halts := s.GetHalts()
+	for i := range halts {
+		log.Warnf("Pair kill switch: %s trading disabled since %s: %s",
+			halts[i].Pair, halts[i].Created, halts[i].Reason)
+	}
+	log.Debugf("Pair kill switch enabled, persisting to %s.", path)
+}
+
+func handleKillSwitchEvent(e killswitch.Event) {
+	log.Warnf("Pair kill switch: %s", e.String())
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e, "pair_kill_switch", "", "")
+	}
+}
+
+// DisablePairTrading blocks new orders for the pair on every exchange and
+// cancels its resting orders
+func DisablePairTrading(p currency.Pair, reason string) (KillSwitchResponse, error) {
+	if bot.pairKillSwitch == nil {
+		return KillSwitchResponse{}, errKillSwitchDisabled
+	}
+	h, err := bot.pairKillSwitch.Disable(p, reason)
+	if err != nil {
+		return KillSwitchResponse{}, err
+	}
+
+	results, err := CancelOrdersWhere("", &exchange.OrderFilter{
+		Pairs: []currency.Pair{p},
+	})
+	if err != nil {
+		log.Errorf("Pair kill switch failed to cancel %s orders: %s", p, err)
+	}
+	return KillSwitchResponse{Halt: h, Cancelled: results}, nil
+}
+
+// EnablePairTrading allows orders for a disabled pair again
+func EnablePairTrading(p currency.Pair) error {
+	if bot.pairKillSwitch == nil {
+		return errKillSwitchDisabled
+	}
+	return bot.pairKillSwitch.Enable(p)
+}
+
+// GetDisabledPairs returns the pairs whose trading is disabled
+func GetDisabledPairs() ([]killswitch.Halt, error) {
+	if bot.pairKillSwitch == nil {
+		return nil, errKillSwitchDisabled
+	}
+	return bot.pairKillSwitch.GetHalts(), nil
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/liquidation"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okex"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// ActivateLiquidationMonitor starts comparing the estimated liquidation price
+// of BitMEX and OKEX positions against their mark price, escalating alerts as
+// positions near liquidation and deleveraging them with reduce only orders
+// when a deleverage fraction is set. Positions are only alerted on in dry run
+// and drop copy modes
+func ActivateLiquidationMonitor() {
+	if !bot.liquidation {
+		return
+	}
+
+	var sources []liquidation.Source
+	for _, exch := range GetLoadedExchanges() {
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		switch e := exchange.Underlying(exch).(type) {
+		case *bitmex.Bitmex:
+			sources = append(sources, &liquidation.BitmexSource{Exchange: e})
+		case *okex.OKEX:
+			sources = append(sources, &liquidation.OKEXFuturesSource{Exchange: e})
+		}
+	}
+
+	t := liquidation.DefaultThresholds()
+	t.DeleverageFraction = bot.liquidationDeleverage
+	if bot.dryRun || bot.dropCopy {
+		t.DeleverageFraction = 0
+	}
+
+	m, err := liquidation.NewMonitor(sources, t, handleLiquidationAlert)
+	if err != nil {
+		log.Errorf("Liquidation monitor failed to start: %s", err)
+		return
+	}
+	m.Start(bot.liquidationInterval)
+	bot.liquidationMonitor = m
+	log.Debugf("Liquidation monitor enabled for %d exchanges.", len(sources))
+}
+
+func handleLiquidationAlert(a liquidation.Alert) {
+	log.Warnf("Liquidation alert: %s", a.String())
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "LIQUIDATION_ALERT",
+			TradeDetails: a.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(a, "liquidation_alert", "", a.Position.Exchange)
+	}
+}
@@ -12,16 +12,62 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/thrasher-corp/gocryptotrader/addressbook"
 	"github.com/thrasher-corp/gocryptotrader/common"
 	"github.com/thrasher-corp/gocryptotrader/communications"
 	"github.com/thrasher-corp/gocryptotrader/config"
 	"github.com/thrasher-corp/gocryptotrader/connchecker"
 	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/currency/coingecko"
 	"github.com/thrasher-corp/gocryptotrader/currency/coinmarketcap"
+	"github.com/thrasher-corp/gocryptotrader/dashboard"
+	"github.com/thrasher-corp/gocryptotrader/dropcopy"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/allocation"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/anomaly"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/balances"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bookfeed"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/borrow"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bracket"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/breakeven"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/candlestore"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/collateral"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/compliance"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/compositeindex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/deposits"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/drawdown"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/dust"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/errorstorm"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/feetier"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/fundingguard"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/imbalance"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/inventory"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/killswitch"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/liquidation"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/netting"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ordermanager"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/pairstats"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/pingorder"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/polling"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/priceband"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/rollup"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/spread"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/spreadhistory"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/sweep"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/tca"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/tradehistory"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/volatility"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/volumeprofile"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/warmup"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/withdrawals"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/yield"
 	log "github.com/thrasher-corp/gocryptotrader/logger"
 	"github.com/thrasher-corp/gocryptotrader/ntpclient"
 	"github.com/thrasher-corp/gocryptotrader/portfolio"
+	"github.com/thrasher-corp/gocryptotrader/reconcile"
+	"github.com/thrasher-corp/gocryptotrader/statereport"
+	"github.com/thrasher-corp/gocryptotrader/strategy"
 )
 
 // Bot contains configuration, portfolio, exchange & ticker data and is the
@@ -36,6 +82,215 @@ type Bot struct {
 	configFile   string
 	dataDir      string
 	connectivity *connchecker.Checker
+
+	dropCopy       bool
+	dropCopyMirror *dropcopy.Mirror
+
+	maxDrawdown     float64
+	drawdownWindow  time.Duration
+	drawdownFlatten bool
+	drawdownBreaker *drawdown.Breaker
+
+	wsRecordFile string
+	wsRecorder   *websocketRecorder
+
+	borrowRateThresholds string
+	borrowRateMonitor    *borrow.Monitor
+
+	warmupCandles  int
+	warmupInterval time.Duration
+	warmup         *warmup.Coordinator
+
+	addressBookFile         string
+	addressBookRequireProof bool
+	addressBook             *addressbook.Book
+
+	complianceRules string
+	compliance      *compliance.Filter
+
+	pnlAttribution         bool
+	pnlAttributionInterval time.Duration
+	pnlBase                string
+	pnlHistory             *portfolio.History
+
+	chaosSettings string
+	apiUsage      bool
+
+	allocations      bool
+	allocationLedger *allocation.Ledger
+
+	rollup          bool
+	rollupGroupings string
+	rollupInterval  time.Duration
+	balanceRollup   *rollup.Rollup
+
+	balances          bool
+	balancesInterval  time.Duration
+	balancesMaxAge    time.Duration
+	balanceAggregator *balances.Aggregator
+
+	breakEven        bool
+	breakEvenTracker *breakeven.Tracker
+
+	inventorySkewBps  float64
+	inventorySizeSkew float64
+	inventoryTargets  string
+	inventorySkew     *inventory.Skewer
+
+	httpRecordFile   string
+	httpRecorderFile *os.File
+
+	collateral        bool
+	collateralExecute bool
+	collateralManager *collateral.Manager
+
+	strategyFile   string
+	strategyReload time.Duration
+	strategyEngine *strategy.Engine
+
+	netting          bool
+	nettingInterval  time.Duration
+	nettingMinAmount float64
+	netter           *netting.Netter
+
+	yield          bool
+	yieldMinRate   float64
+	yieldOptimizer *yield.Optimizer
+
+	dashboard         bool
+	dashboardInterval time.Duration
+	dashboardView     *dashboard.Dashboard
+
+	depositTracking bool
+	depositTracker  *deposits.Tracker
+
+	withdrawalMonitoring bool
+	withdrawalStallAfter time.Duration
+	withdrawalMonitor    *withdrawals.Monitor
+
+	sweepConfig   string
+	sweepInterval time.Duration
+	sweeper       *sweep.Sweeper
+
+	anomalyWatch    bool
+	anomalyLockdown bool
+	anomalyWatcher  *anomaly.Watcher
+
+	stateReportFile    string
+	reconcileFile      string
+	reconcileAfterFile string
+	reconcileTolerance float64
+
+	indexTracking bool
+	indexTracker  *compositeindex.Tracker
+
+	bracketOrders  bool
+	bracketManager *bracket.Manager
+
+	imbalanceOrders  bool
+	imbalanceManager *imbalance.Manager
+
+	spreadOrders  bool
+	spreadManager *spread.Manager
+
+	fundingGuard        bool
+	fundingGuardMinRate float64
+	fundingGuardLead    time.Duration
+	fundingGuardFeeRate float64
+	fundingGuardHedges  string
+	fundingGuardManager *fundingguard.Manager
+
+	tradeHistory         bool
+	tradeHistoryInterval time.Duration
+	tradeHistoryStore    *tradehistory.Store
+	tradeHistoryDetector *tradehistory.Detector
+
+	errorBreaker            bool
+	errorBreakerWindow      time.Duration
+	errorBreakerRate        float64
+	errorBreakerDisconnects int
+	errorBreakerCooldown    time.Duration
+	errorStormBreaker       *errorstorm.Breaker
+
+	volatility         bool
+	volatilityInterval time.Duration
+	volatilityService  *volatility.Service
+
+	volumeProfile        bool
+	volumeProfileSession time.Duration
+	volumeProfileBucket  float64
+	volumeProfileService *volumeprofile.Service
+
+	spreadHistoryConfig string
+	spreadHistory       *spreadhistory.Recorder
+
+	candleDriver       string
+	candleDSN          string
+	candleIntervals    string
+	candleBackfill     time.Duration
+	candleSyncInterval time.Duration
+	candleStore        *candlestore.Manager
+
+	adaptivePolling    bool
+	adaptivePollingMin time.Duration
+	adaptivePollingMax time.Duration
+	pollingScheduler   *polling.Scheduler
+
+	executionQuality        bool
+	executionQualityMonitor *tca.Monitor
+
+	orderbookFeed bool
+	bookFeed      *bookfeed.Feed
+
+	pingOrders           string
+	pingOrderInterval    time.Duration
+	pingOrderMaxNotional float64
+	pingOrderChecker     *pingorder.Checker
+
+	killSwitch     bool
+	pairKillSwitch *killswitch.Switch
+
+	priceBand      float64
+	priceBandPause time.Duration
+	priceBandGuard *priceband.Guard
+
+	tradingRules         bool
+	tradingRuleOverrides string
+
+	coldStorage string
+
+	preflight bool
+
+	cancelTimeout time.Duration
+	cancelRetries int
+
+	cancelOnShutdown bool
+
+	duplicateWindow time.Duration
+
+	pairStats        bool
+	pairStatsTracker *pairstats.Tracker
+
+	orderManager         bool
+	orderManagerInterval time.Duration
+	orders               *ordermanager.Manager
+
+	feeTiers        bool
+	feeTierConfig   string
+	feeTierInterval time.Duration
+	feeTierTracker  *feetier.Tracker
+
+	dustQuote    string
+	dustInterval time.Duration
+
+	bitmexMargin         string
+	bitmexMarginInterval time.Duration
+	bitmexMarginManager  *bitmex.MarginManager
+
+	liquidation           bool
+	liquidationDeleverage float64
+	liquidationInterval   time.Duration
+	liquidationMonitor    *liquidation.Monitor
 	sync.Mutex
 }
 
@@ -62,15 +317,126 @@ func main() {
 	// Handle flags
 	flag.StringVar(&bot.configFile, "config", defaultPath, "config file to load")
 	flag.StringVar(&bot.dataDir, "datadir", common.GetDefaultDataDir(runtime.GOOS), "default data directory for GoCryptoTrader files")
-	dryrun := flag.Bool("dryrun", false, "dry runs bot, doesn't save config file")
+	dryrun := flag.Bool("dryrun", false, "dry runs bot, doesn't save config file and simulates orders, withdrawals and transfers on all exchanges")
 	version := flag.Bool("version", false, "retrieves current GoCryptoTrader version")
 	verbosity := flag.Bool("verbose", false, "increases logging verbosity for GoCryptoTrader")
+	flag.BoolVar(&bot.dropCopy, "dropcopy", false, "mirrors exchange account activity without trading, use with read only API keys")
+	flag.Float64Var(&bot.maxDrawdown, "maxdrawdown", 0, "percentage drawdown of account equity which cancels all orders and locks trading, disabled when 0")
+	flag.DurationVar(&bot.drawdownWindow, "drawdownwindow", drawdown.DefaultWindow, "rolling window the maximum drawdown is measured over")
+	flag.BoolVar(&bot.drawdownFlatten, "drawdownflatten", false, "flattens positions on all exchanges when the drawdown circuit breaker trips")
+	flag.StringVar(&bot.wsRecordFile, "wsrecord", "", "records websocket orderbook and trade data to the file for historical orderbook reconstruction")
+	flag.StringVar(&bot.borrowRateThresholds, "borrowratethresholds", "", "alerts when annualised borrow rates exceed the percentage per currency, e.g. BTC:20,ETH:15")
+	flag.IntVar(&bot.warmupCandles, "warmupcandles", 0, "preloads historical candles per enabled pair and waits for websocket feeds before strategies activate, disabled when 0")
+	flag.DurationVar(&bot.warmupInterval, "warmupinterval", warmup.DefaultInterval, "candle interval preloaded by the market data warmup")
+	flag.StringVar(&bot.addressBookFile, "addressbook", "", "withdrawal address book file, defaults to addressbook.json in the data directory")
+	flag.BoolVar(&bot.addressBookRequireProof, "addressbookrequireproof", false, "rejects withdrawals to address book destinations without an ownership proof")
+	flag.BoolVar(&bot.pnlAttribution, "pnlattribution", false, "records portfolio snapshots to split PnL in the base currency between asset price moves and FX rate moves")
+	flag.DurationVar(&bot.pnlAttributionInterval, "pnlattributioninterval", portfolio.DefaultSnapshotInterval, "interval portfolio snapshots are recorded for PnL attribution")
+	flag.StringVar(&bot.pnlBase, "pnlbase", "", "base currency PnL is attributed in, defaults to the fiat display currency")
+	flag.StringVar(&bot.complianceRules, "compliance", "", "compliance rules file blacklisting assets, venues and sanctioned addresses, orders and withdrawals breaching them are blocked and journaled to compliance.log in the data directory")
+	flag.StringVar(&bot.chaosSettings, "chaos", "", "injects latency, dropped websocket frames, HTTP errors and outages for resilience testing, e.g. latency=200ms,5xx=0.05,429=0.02,drop=0.01,outage=0.001,outageduration=30s")
+	flag.BoolVar(&bot.apiUsage, "apiusage", false, "counts daily REST requests by endpoint class, rate limit rejections and websocket messages per exchange, projecting them against each exchange's rate limits")
+	flag.BoolVar(&bot.allocations, "allocations", false, "enables per-strategy capital allocation with virtual sub-accounts on shared exchange accounts")
+	flag.BoolVar(&bot.rollup, "rollup", false, "aggregates the balances of every exchange account and sub-account by exchange, account and strategy, and into the groupings in the -rollupgroupings file")
+	flag.StringVar(&bot.rollupGroupings, "rollupgroupings", "", "JSON file assigning exchanges, Exchange:Account accounts and strategy:Name allocations to the groups of each balance roll-up grouping, e.g. {\"desk\":{\"spot\":[\"Binance\",\"Huobi:subusers\"],\"quant\":[\"strategy:grid\"]}}")
+	flag.DurationVar(&bot.rollupInterval, "rollupinterval", rollup.DefaultRefreshInterval, "interval balances are fetched for the roll-up")
+	flag.BoolVar(&bot.balances, "balances", false, "polls the balances of every exchange and aggregates them into one portfolio snapshot per currency, valued in the fiat display currency")
+	flag.DurationVar(&bot.balancesInterval, "balancesinterval", balances.DefaultRefreshInterval, "interval balances are fetched for the portfolio snapshot")
+	flag.DurationVar(&bot.balancesMaxAge, "balancesmaxage", 0, "age an exchange's cached balances are reported stale after. Zero reports them stale after three -balancesinterval intervals")
+	flag.BoolVar(&bot.breakEven, "breakeven", false, "tracks the break-even exit price of open positions including fees and funding, alerting when the mark price crosses it")
+	flag.Float64Var(&bot.inventorySkewBps, "inventoryskew", 0, "skews multi-venue quotes up to the basis points by where inventory is held, located with -rollup balances and -breakeven positions. Zero disables the skew")
+	flag.Float64Var(&bot.inventorySizeSkew, "inventorysizeskew", 0, "fraction quote sizes on the accumulating side shrink by on a venue holding the entire inventory above its target")
+	flag.StringVar(&bot.inventoryTargets, "inventorytargets", "", "target share of inventory held on each venue, e.g. Binance:60,Kraken:40. Inventory is split equally between the venues holding it when empty")
+	flag.BoolVar(&bot.collateral, "collateral", false, "values BitMEX and OKEX margin in the display currency, alerting on maintenance margin utilization and recommending collateral moves between venues")
+	flag.BoolVar(&bot.collateralExecute, "collateralexecute", false, "executes recommended collateral moves to destinations approved in the address book")
+	flag.BoolVar(&bot.yield, "yield", false, "deploys balances not allocated to strategies to Poloniex lending and Kraken staking, unwinding them when strategies need the funds")
+	flag.Float64Var(&bot.yieldMinRate, "yieldminrate", 0, "minimum annualised percentage yield idle balances are deployed at")
+	flag.BoolVar(&config.Headless, "headless", false, "disables interactive prompts, an encrypted config is decrypted with the "+config.ConfigKeyEnv+" environment variable")
+	flag.StringVar(&bot.strategyFile, "strategyfile", "", "YAML or JSON file declaring packaged strategies, their pairs, sizing and risk limits, reloaded when modified")
+	flag.DurationVar(&bot.strategyReload, "strategyreload", strategy.DefaultReloadInterval, "interval the strategy file is checked for modifications")
+	flag.BoolVar(&bot.netting, "netting", false, "nets the target positions strategies emit for the same pair, trading only the net difference so opposing strategies do not cross each other's orders")
+	flag.DurationVar(&bot.nettingInterval, "nettinginterval", netting.DefaultRebalanceInterval, "interval the net difference of strategy targets is traded")
+	flag.Float64Var(&bot.nettingMinAmount, "nettingminamount", 0, "smallest net difference of strategy targets traded, smaller differences wait for further target changes")
+	flag.StringVar(&bot.httpRecordFile, "httprecord", "", "records redacted HTTP requests and responses of exchanges with HTTP debugging enabled to the file as replayable fixtures")
+	flag.BoolVar(&bot.dashboard, "dashboard", false, "draws a terminal dashboard of tickers, open orders, positions and session PnL, refreshed in place")
+	flag.DurationVar(&bot.dashboardInterval, "dashboardinterval", dashboard.DefaultInterval, "interval the terminal dashboard is redrawn")
+	flag.BoolVar(&bot.depositTracking, "deposits", false, "tracks inbound deposit confirmations against the count each exchange requires, alerting when deposits are credited")
+	flag.BoolVar(&bot.withdrawalMonitoring, "withdrawals", false, "follows outbound withdrawal transactions on public blockchain explorers until confirmed, alerting when one stalls or is stuck paying a fee below the network rate")
+	flag.DurationVar(&bot.withdrawalStallAfter, "withdrawalstallafter", withdrawals.DefaultStallAfter, "time a withdrawal can go without being broadcast or confirmed before it is reported as stalled")
+	flag.BoolVar(&bot.anomalyWatch, "anomalywatch", false, "watches accounts for API key changes, withdrawals to destinations outside the address book and suspicious notifications")
+	flag.BoolVar(&bot.anomalyLockdown, "anomalylockdown", false, "cancels all orders and blocks new orders and withdrawals when the anomaly watcher finds a critical anomaly")
+	flag.BoolVar(&bot.indexTracking, "compositeindex", false, "tracks the Bitmex .BXBT index constituents, alerting when the index recomputed from constituent exchange prices diverges from the published index")
+	flag.BoolVar(&bot.bracketOrders, "brackets", false, "manages bracket orders, placing each take profit and stop loss once its entry fills. Brackets are stored in brackets.json in the data directory")
+	flag.BoolVar(&bot.imbalanceOrders, "imbalanceorders", false, "works imbalance orders, placing or turning a limit order aggressive once the order book imbalance on its side crosses a threshold. Orders are stored in imbalance.json in the data directory")
+	flag.BoolVar(&bot.spreadOrders, "spreads", false, "works two leg spread orders, hedging each lean leg fill on the hedge leg and unwinding persistent leg imbalances. Spreads are stored in spreads.json in the data directory")
+	flag.BoolVar(&bot.fundingGuard, "fundingguard", false, "closes BitMEX and OKEX perpetual swap positions shortly before funding when the funding they would pay exceeds the estimated cost of closing and reopening them, reopening once funding has passed. Actions are stored in fundingguard.json in the data directory")
+	flag.Float64Var(&bot.fundingGuardMinRate, "fundingguardminrate", fundingguard.DefaultMinRate, "smallest funding rate the funding guard acts upon")
+	flag.DurationVar(&bot.fundingGuardLead, "fundingguardlead", fundingguard.DefaultLead, "time before funding positions are evaluated by the funding guard")
+	flag.Float64Var(&bot.fundingGuardFeeRate, "fundingguardfee", fundingguard.DefaultFeeRate, "taker fee rate paid per trade used to estimate the cost of closing and reopening a position")
+	flag.StringVar(&bot.fundingGuardHedges, "fundingguardhedges", "", "hedges perpetual swap positions on another instrument over funding instead of closing them, e.g. Bitmex:XBTUSD=Binance:BTC-USDT")
+	flag.BoolVar(&bot.tradeHistory, "tradehistory", false, "persists strategy and drop copy fills to fills.log in the data directory, comparing them with each exchange's order history to backfill fills missed while the bot was down and flag irreconcilable differences")
+	flag.DurationVar(&bot.tradeHistoryInterval, "tradehistoryinterval", tradehistory.DefaultCheckInterval, "interval recorded fills are compared with exchange order history")
+	flag.BoolVar(&bot.errorBreaker, "errorbreaker", false, "pauses new orders to an exchange while its authenticated requests are failing or its websocket keeps disconnecting, resuming after a cool down and health check")
+	flag.DurationVar(&bot.errorBreakerWindow, "errorbreakerwindow", errorstorm.DefaultWindow, "window request errors and websocket disconnects are counted over by the error storm breaker")
+	flag.Float64Var(&bot.errorBreakerRate, "errorbreakerrate", errorstorm.DefaultMaxErrorRate, "fraction of authenticated requests failing within the window which pauses an exchange")
+	flag.IntVar(&bot.errorBreakerDisconnects, "errorbreakerdisconnects", errorstorm.DefaultMaxDisconnects, "websocket disconnects within the window which pause an exchange")
+	flag.DurationVar(&bot.errorBreakerCooldown, "errorbreakercooldown", errorstorm.DefaultCooldown, "time a paused exchange waits before its health check")
+	flag.BoolVar(&bot.volatility, "volatility", false, "measures the 1h, 24h, 7d and 30d realized volatility of every enabled pair from stored candles, used by strategies declaring a target volatility")
+	flag.DurationVar(&bot.volatilityInterval, "volatilityinterval", volatility.DefaultCheckInterval, "interval realized volatility is refreshed")
+	flag.BoolVar(&bot.volumeProfile, "volumeprofile", false, "aggregates websocket trades into volume by price per session for every pair, exposing the point of control and value area to strategies")
+	flag.DurationVar(&bot.volumeProfileSession, "volumeprofilesession", volumeprofile.DefaultSession, "length of each volume profile session, aligned to UTC midnight")
+	flag.Float64Var(&bot.volumeProfileBucket, "volumeprofilebucket", volumeprofile.DefaultBucketBps, "volume profile price bucket width in basis points of the session's first trade price")
+	flag.StringVar(&bot.candleDriver, "candles", "", "persists the candles of every enabled pair to a sqlite3 or postgres database, downloading only candles not yet stored. ")
+	flag.StringVar(&bot.candleDSN, "candlesdsn", "", "candle database DSN, defaults to candles.db in the data directory for sqlite3")
+	flag.StringVar(&bot.candleIntervals, "candlesintervals", "1h", "comma separated candle intervals synced for every enabled pair, e.g. 1m,1h,1d")
+	flag.DurationVar(&bot.candleBackfill, "candlesbackfill", candlestore.DefaultBackfill, "how far back candles are downloaded when a pair is first synced")
+	flag.DurationVar(&bot.candleSyncInterval, "candlessync", candlestore.DefaultSyncInterval, "interval newly closed candles are synced")
+	flag.StringVar(&bot.spreadHistoryConfig, "spreadhistory", "", "JSON file of instrument pairs whose spread is recorded to spreadhistory.jsonl in the data directory, e.g. {\"spreads\":[{\"name\":\"btc-basis\",\"a\":{\"exchange\":\"OKEX\",\"pair\":\"BTC-USD\",\"assetType\":\"perpetual\"},\"b\":{\"exchange\":\"Bitstamp\",\"pair\":\"BTCUSD\"},\"measure\":\"bps\",\"frequency\":\"30s\",\"retention\":\"720h\"}]}")
+	flag.BoolVar(&bot.adaptivePolling, "adaptivepolling", false, "shares each exchange's REST ticker and orderbook polling by pair volatility, polling volatile pairs more often and subscribing them deeper websocket books. Requires -volatility")
+	flag.DurationVar(&bot.adaptivePollingMin, "adaptivepollingmin", polling.DefaultMinInterval, "shortest interval a volatile pair is polled at")
+	flag.DurationVar(&bot.adaptivePollingMax, "adaptivepollingmax", polling.DefaultMaxInterval, "longest interval a quiet pair is polled at")
+	flag.BoolVar(&bot.executionQuality, "executionquality", false, "alerts when a strategy's maker/taker ratio, spread capture or adverse selection over the last day regresses from its earlier fills")
+	flag.BoolVar(&bot.orderbookFeed, "orderbookfeed", false, "distributes orderbook updates to strategies as sequenced deltas of the levels changed, conflated to each subscriber's max update rate")
+	flag.StringVar(&bot.pingOrders, "pingorders", "", "periodically places and immediately cancels a tiny buy order far below the market to check the authenticated order path, with the pair and amount per exchange, e.g. Poloniex:BTC-USDT:0.001,Kraken:XBT-USD:0.002")
+	flag.DurationVar(&bot.pingOrderInterval, "pingorderinterval", pingorder.DefaultInterval, "interval ping orders are placed on each exchange")
+	flag.Float64Var(&bot.pingOrderMaxNotional, "pingordermaxnotional", pingorder.DefaultMaxNotional, "maximum value of a ping order in its quote currency, ping orders above it are not placed")
+	flag.BoolVar(&bot.killSwitch, "killswitch", false, "enables the pair kill switch control API, disabling trading of a pair across all strategies and exchanges and cancelling its resting orders. Disabled pairs are stored in killswitch.json in the data directory")
+	flag.Float64Var(&bot.priceBand, "priceband", 0, "rejects orders on a pair while the exchange's last price deviates from the median of the other venues' last prices by more than the fraction, e.g. 0.02, protecting against single venue manipulation and flash crash wicks. Zero disables the guard")
+	flag.DurationVar(&bot.priceBandPause, "pricebandpause", priceband.DefaultPause, "time orders on a pair stay paused after its last price returns within the price band")
+	flag.BoolVar(&bot.tradingRules, "tradingrules", false, "rounds order prices and amounts to each exchange's published increments at submission, prices towards the passive side and amounts down")
+	flag.StringVar(&bot.tradingRuleOverrides, "tradingruleoverrides", "", "overrides the price and amount rounding modes (passive, nearest, down, up) and optionally steps per exchange or pair, e.g. Binance=nearest/down,Bitstamp:BTC-USD=passive/down/0.01/0.00000001")
+	flag.StringVar(&bot.sweepConfig, "sweep", "", "sweep file of exchange balances withdrawn to cold storage above the amount kept and the withdrawal limits of each exchange and verification tier. Sweeps exceeding a limit are split across days. Targets without an address use the -coldstorage address for their currency")
+	flag.DurationVar(&bot.sweepInterval, "sweepinterval", sweep.DefaultCheckInterval, "interval balances are checked for sweeping to cold storage")
+	flag.StringVar(&bot.coldStorage, "coldstorage", "", "cold wallet addresses whose balances are fetched from public blockchain explorers and valued as non tradeable portfolio holdings, e.g. BTC:1JCe8z4jJVNXSjohjM4i9Hh813dLCNx2Sy,ETH:0xb794f5ea0ba39494ce839613fffba74279579268")
+	flag.BoolVar(&bot.preflight, "preflight", false, "validates orders exchange side before submitting them on exchanges which support it, such as Kraken, so rejected orders do not spend the order rate limit")
+	flag.DurationVar(&bot.cancelTimeout, "cancelconfirm", 0, "waits up to the duration for each cancelled order to reach a terminal state, resending the cancel while it stays open and reporting orders which filled before they were cancelled, e.g. 10s. Zero disables confirmation")
+	flag.IntVar(&bot.cancelRetries, "cancelretries", exchange.DefaultCancelRetries, "cancels resent to an order still open once the cancel confirmation timeout passes")
+	flag.BoolVar(&bot.cancelOnShutdown, "cancelonshutdown", false, "cancels all resting orders on exchanges with authenticated API support when shutting down")
+	flag.BoolVar(&bot.pairStats, "pairstats", false, "counts the orders the bot submits, fills and cancels per exchange and pair each UTC day with the notional traded, fees paid and net position change")
+	flag.BoolVar(&bot.orderManager, "ordermanager", false, "tracks every order the bot places across exchanges with their statuses normalised, reconciling open orders with each exchange's active orders and picking up orders placed outside the bot")
+	flag.DurationVar(&bot.orderManagerInterval, "ordermanagerinterval", ordermanager.DefaultCheckInterval, "interval open orders are reconciled with each exchange's active orders")
+	flag.BoolVar(&bot.feeTiers, "feetiers", false, "tracks and persists the account fee tier of exchanges which report it, such as Bitmex, Huobi, Kraken and Poloniex, alerting when the trading volume nears a tier with meaningfully lower fees")
+	flag.StringVar(&bot.feeTierConfig, "feetierconfig", "", "fee tier file of the alert proximity and minimum fee reduction, and the fee schedules of exchanges which do not report their next tier")
+	flag.DurationVar(&bot.feeTierInterval, "feetierinterval", feetier.DefaultCheckInterval, "interval fee tiers are checked")
+	flag.DurationVar(&bot.duplicateWindow, "duplicatewindow", 0, "blocks orders repeating the pair, side, price and amount of an order submitted within the window, e.g. 5s, protecting against strategy bugs and retry storms. Ping orders and strategies declaring allowDuplicateOrders are let through. Zero disables the guard")
+	flag.StringVar(&bot.dustQuote, "dust", "", "cleans up balances below each exchange's minimum order notional valued in the currency, e.g. USDT, converting them on exchanges with a dust conversion endpoint and otherwise selling them once their value meets the minimum. Disabled when empty")
+	flag.DurationVar(&bot.dustInterval, "dustinterval", dust.DefaultCleanupInterval, "interval dust balances are cleaned up")
+	flag.StringVar(&bot.bitmexMargin, "bitmexmargin", "", "sets the margin mode and leverage of Bitmex positions before strategies start and tops up isolated margin below the buffered maintenance margin or near liquidation, e.g. XBTUSD=isolated/10/0.5/0.05,ETHUSD=cross")
+	flag.DurationVar(&bot.bitmexMarginInterval, "bitmexmargininterval", bitmex.DefaultMarginCheckInterval, "interval isolated Bitmex positions are checked for margin top ups")
+	flag.BoolVar(&bot.liquidation, "liquidation", false, "compares the estimated liquidation price of BitMEX and OKEX positions against their mark price, escalating alerts as positions near liquidation")
+	flag.Float64Var(&bot.liquidationDeleverage, "liquidationdeleverage", 0, "fraction of a position closed with a reduce only order each check while it stays within the deleverage distance of liquidation. Zero only alerts")
+	flag.DurationVar(&bot.liquidationInterval, "liquidationinterval", liquidation.DefaultCheckInterval, "interval positions are checked against their liquidation price")
+	flag.StringVar(&bot.stateReportFile, "statereport", "", "writes a signed report of open orders, positions and balances to the file once started, then exits. The HMAC key is read from the "+statereport.KeyEnv+" environment variable")
+	flag.StringVar(&bot.reconcileFile, "reconcile", "", "compares the signed state report in the file against the live state once started, explaining balance changes with the fills, fees, transfers and funding recorded since, then exits")
+	flag.StringVar(&bot.reconcileAfterFile, "reconcileafter", "", "a later signed state report to reconcile against instead of the live state")
+	flag.Float64Var(&bot.reconcileTolerance, "reconciletolerance", reconcile.DefaultTolerance, "fraction of a balance which may change unexplained before reconciliation flags it")
 
 	Coinmarketcap := flag.Bool("c", false, "overrides config and runs currency analaysis")
 	FxCurrencyConverter := flag.Bool("fxa", false, "overrides config and sets up foreign exchange Currency Converter")
 	FxCurrencyLayer := flag.Bool("fxb", false, "overrides config and sets up foreign exchange Currency Layer")
 	FxFixer := flag.Bool("fxc", false, "overrides config and sets up foreign exchange Fixer.io")
 	FxOpenExchangeRates := flag.Bool("fxd", false, "overrides config and sets up foreign exchange Open Exchange Rates")
+	FxExchangeRates := flag.Bool("fxe", false, "overrides config and sets up foreign exchange Exchange Rates API")
 
 	flag.Parse()
 
@@ -122,11 +488,22 @@ func main() {
 
 	common.HTTPClient = common.NewHTTPClientWithTimeout(bot.config.GlobalHTTPTimeout)
 	log.Debugf("Global HTTP request timeout: %v.\n", common.HTTPClient.Timeout)
+	common.SetDisplayFormat(bot.config.Currency.DisplayFormat())
 
+	ActivateChaos()
+	ActivateAPIUsage()
+	ActivateHTTPRecorder()
 	SetupExchanges()
+	ActivateDryRun()
+	ActivatePairStats()
+	ActivateOrderManager()
 
 	log.Debugf("Starting communication mediums..")
 	cfg := bot.config.GetCommunicationsConfig()
+	err = cfg.ResolveSecrets()
+	if err != nil {
+		log.Errorf("Failed to resolve communication secrets: %s", err)
+	}
 	bot.comms = communications.NewComm(&cfg)
 	bot.comms.GetEnabledCommunicationMediums()
 
@@ -141,14 +518,16 @@ func main() {
 		FxCurrencyLayer:     *FxCurrencyLayer,
 		FxFixer:             *FxFixer,
 		FxOpenExchangeRates: *FxOpenExchangeRates,
+		FxExchangeRates:     *FxExchangeRates,
 	},
 		&currency.MainConfiguration{
-			ForexProviders:         newFxSettings,
-			CryptocurrencyProvider: coinmarketcap.Settings(bot.config.Currency.CryptocurrencyProvider),
-			Cryptocurrencies:       bot.config.Currency.Cryptocurrencies,
-			FiatDisplayCurrency:    bot.config.Currency.FiatDisplayCurrency,
-			CurrencyDelay:          bot.config.Currency.CurrencyFileUpdateDuration,
-			FxRateDelay:            bot.config.Currency.ForeignExchangeUpdateDuration,
+			ForexProviders:                  newFxSettings,
+			CryptocurrencyProvider:          coinmarketcap.Settings(bot.config.Currency.CryptocurrencyProvider),
+			SecondaryCryptocurrencyProvider: coingecko.Settings(bot.config.Currency.SecondaryCryptoProvider),
+			Cryptocurrencies:                bot.config.Currency.Cryptocurrencies,
+			FiatDisplayCurrency:             bot.config.Currency.FiatDisplayCurrency,
+			CurrencyDelay:                   bot.config.Currency.CurrencyFileUpdateDuration,
+			FxRateDelay:                     bot.config.Currency.ForeignExchangeUpdateDuration,
 		},
 		bot.dataDir,
 		*verbosity)
@@ -162,6 +541,70 @@ func main() {
 	SeedExchangeAccountInfo(GetAllEnabledExchangeAccountInfo().Data)
 
 	ActivateWebServer()
+	ActivatePreflightValidation()
+	ActivateCancelConfirmation()
+	ActivateDuplicateOrderGuard()
+	ActivateTradeHistory()
+	ActivateDropCopy()
+	ActivateDrawdownBreaker()
+	ActivateErrorStormBreaker()
+	ActivatePairKillSwitch()
+	ActivatePriceBand()
+	ActivateTradingRules()
+	ActivatePingOrders()
+	ActivateWebsocketRecorder()
+	ActivateBorrowRateMonitor()
+	ActivateWarmup()
+	ActivateAddressBook()
+	ActivateCompliance()
+	ActivateColdStorage()
+	ActivatePnLAttribution()
+	ActivateAllocations()
+	ActivateRollup()
+	ActivateBalances()
+	ActivateBreakEvenTracker()
+	ActivateInventorySkew()
+	ActivateCollateralManager()
+	ActivateLiquidationMonitor()
+	ActivateYieldOptimizer()
+	ActivateDepositTracker()
+	ActivateWithdrawalMonitor()
+	ActivateSweeps()
+	ActivateFeeTiers()
+	ActivateDustCleanup()
+	ActivateAnomalyWatcher()
+	ActivateIndexTracker()
+	ActivateBracketOrders()
+	ActivateImbalanceOrders()
+	ActivateSpreadOrders()
+	ActivateFundingGuard()
+	ActivateCandleStore()
+	ActivateVolatilityService()
+	ActivateVolumeProfile()
+	ActivateSpreadHistory()
+	ActivateAdaptivePolling()
+	ActivateExecutionQuality()
+	ActivateOrderbookFeed()
+	ActivateNetting()
+	ActivateBitmexMargin()
+	ActivateStrategies()
+	ActivateDashboard()
+
+	if bot.stateReportFile != "" {
+		err = WriteStateReport(bot.stateReportFile)
+		if err != nil {
+			log.Errorf("Failed to write state report: %s", err)
+		}
+		Shutdown()
+	}
+
+	if bot.reconcileFile != "" {
+		err = Reconcile(os.Stdout, bot.reconcileFile, bot.reconcileAfterFile, bot.reconcileTolerance)
+		if err != nil {
+			log.Errorf("Failed to reconcile state reports: %s", err)
+		}
+		Shutdown()
+	}
 
 	go portfolio.StartPortfolioWatcher()
 
@@ -268,27 +711,135 @@ func HandleInterrupt() {
 		sig := <-c
 		log.Debugf("Captured %v, shutdown requested.", sig)
 		close(bot.shutdown)
+		sig = <-c
+		log.Warnf("Captured %v during shutdown, exiting immediately.", sig)
+		log.CloseLogFile()
+		os.Exit(1)
 	}()
 }
 
-// Shutdown correctly shuts down bot saving configuration files
+// Shutdown stops new orders being placed, optionally cancels resting orders,
+// stops services, saves configuration files and closes websockets before
+// logging a summary of the shutdown
 func Shutdown() {
 	log.Debugln("Bot shutting down..")
 
-	if len(portfolio.Portfolio.Addresses) != 0 {
-		bot.config.Portfolio = portfolio.Portfolio
+	summary := shutdownSummary{started: time.Now()}
+	stopOrderSources(&summary)
+	if bot.cancelOnShutdown {
+		cancelRestingOrders(&summary)
+	}
+
+	if bot.drawdownBreaker != nil {
+		bot.drawdownBreaker.Stop()
 	}
 
-	if !bot.dryRun {
-		err := bot.config.SaveConfig(bot.configFile)
+	if bot.errorStormBreaker != nil {
+		bot.errorStormBreaker.Stop()
+	}
+
+	if bot.breakEvenTracker != nil {
+		bot.breakEvenTracker.Stop()
+	}
+
+	if bot.borrowRateMonitor != nil {
+		bot.borrowRateMonitor.Stop()
+	}
+
+	if bot.collateralManager != nil {
+		bot.collateralManager.Stop()
+	}
 
+	if bot.bitmexMarginManager != nil {
+		bot.bitmexMarginManager.Stop()
+	}
+
+	if bot.liquidationMonitor != nil {
+		bot.liquidationMonitor.Stop()
+	}
+
+	if bot.dashboardView != nil {
+		err := bot.dashboardView.Stop()
 		if err != nil {
-			log.Warn("Unable to save config.")
-		} else {
-			log.Debugln("Config file saved successfully.")
+			log.Warnf("Unable to stop dashboard. Err: %s", err)
 		}
 	}
 
+	if bot.depositTracker != nil {
+		bot.depositTracker.Stop()
+	}
+
+	if bot.withdrawalMonitor != nil {
+		bot.withdrawalMonitor.Stop()
+	}
+
+	if bot.sweeper != nil {
+		bot.sweeper.Stop()
+	}
+
+	if bot.anomalyWatcher != nil {
+		bot.anomalyWatcher.Stop()
+	}
+
+	if bot.indexTracker != nil {
+		bot.indexTracker.Stop()
+	}
+
+	if bot.tradeHistoryDetector != nil {
+		bot.tradeHistoryDetector.Stop()
+	}
+
+	if bot.priceBandGuard != nil {
+		bot.priceBandGuard.Stop()
+	}
+
+	if bot.balanceRollup != nil {
+		bot.balanceRollup.Stop()
+	}
+
+	if bot.balanceAggregator != nil {
+		bot.balanceAggregator.Stop()
+	}
+
+	if bot.pollingScheduler != nil {
+		bot.pollingScheduler.Stop()
+	}
+
+	if bot.volatilityService != nil {
+		bot.volatilityService.Stop()
+	}
+
+	if bot.spreadHistory != nil {
+		bot.spreadHistory.Stop()
+	}
+
+	if bot.candleStore != nil {
+		err := bot.candleStore.Stop()
+		if err != nil {
+			log.Warnf("Unable to close candle store. Err: %s", err)
+		}
+	}
+
+	if bot.executionQualityMonitor != nil {
+		bot.executionQualityMonitor.Stop()
+	}
+
+	if bot.pingOrderChecker != nil {
+		bot.pingOrderChecker.Stop()
+	}
+
+	if bot.orders != nil {
+		bot.orders.Stop()
+	}
+
+	if bot.feeTierTracker != nil {
+		bot.feeTierTracker.Stop()
+	}
+
+	flushPersistence(&summary)
+	closeWebsockets(&summary)
+	summary.report()
+
 	log.Debugln("Exiting.")
 
 	log.CloseLogFile()
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"errors"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+var errTradeabilityUnsupported = errors.New("exchange does not publish market statuses")
+
+// GetUntradeablePairs returns the frozen, disabled and delisted markets of an
+// exchange along with the reason each cannot be traded
+func GetUntradeablePairs(exchName string) (map[string]string, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+	t, ok := exchange.Underlying(exch).(exchange.Tradeability)
+	if !ok {
+		return nil, errTradeabilityUnsupported
+	}
+	return t.GetUntradeablePairs()
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/netting"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// nettingFile persists the strategy targets and traded positions in the data
+// directory
+const nettingFile = "netting.json"
+
+var errNettingDisabled = errors.New("strategy target netting not enabled")
+
+// ActivateNetting starts netting the target positions emitted by strategies,
+// trading the net difference of each pair through the engine's exchanges
+func ActivateNetting() {
+	if !bot.netting {
+		return
+	}
+
+	path := filepath.Join(bot.dataDir, nettingFile)
+	n, err := netting.New(netting.Config{MinAmount: bot.nettingMinAmount}, path,
+		func(exchName string) exchange.IBotExchange {
+			return GetExchangeByName(exchName)
+		}, handleNettingEvent)
+	if err != nil {
+		log.Errorf("Strategy target netting failed to load from %s: %s", path, err)
+		return
+	}
+	n.Start(bot.nettingInterval)
+	bot.netter = n
+	log.Debugf("Strategy target netting enabled, trading net differences every %s.", bot.nettingInterval)
+}
+
+func handleNettingEvent(e netting.Event) {
+	if e.Error != "" {
+		log.Errorf("Netting: %s", e.String())
+	} else {
+		log.Debugf("Netting: %s", e.String())
+	}
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e, "netting", "", e.Exchange)
+	}
+}
+
+// GetNettingBooks returns the strategy targets and traded position of every
+// pair netted
+func GetNettingBooks() ([]netting.Book, error) {
+	if bot.netter == nil {
+		return nil, errNettingDisabled
+	}
+	return bot.netter.GetBooks(), nil
+}
+
+// RemoveNettingStrategy removes a strategy's targets, trading out its
+// positions unless offset by other strategies
+func RemoveNettingStrategy(name string) error {
+	if bot.netter == nil {
+		return errNettingDisabled
+	}
+	bot.netter.RemoveStrategy(name)
+	return nil
+}
@@ -0,0 +1,176 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ordermanager"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errOrderManagerDisabled = errors.New("order manager not enabled")
+
+// ActivateOrderManager wraps every loaded exchange with authenticated API
+// support so the orders placed through it are tracked in one place, and
+// starts reconciling their open orders. Orders are reconciled and cancelled
+// through the fully wrapped exchange
+func ActivateOrderManager() {
+	if !bot.orderManager {
+		return
+	}
+
+	m, err := ordermanager.New(GetExchangeByName, handleOrderManagerEvent)
+	if err != nil {
+		log.Errorf("Order manager failed to start: %s", err)
+		return
+	}
+	var count int
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil ||
+			!bot.exchanges[x].GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		bot.exchanges[x] = m.Track(bot.exchanges[x])
+		count++
+	}
+	m.Start(bot.orderManagerInterval)
+	bot.orders = m
+	log.Debugf("Order manager enabled for %d exchanges.", count)
+}
+
+func handleOrderManagerEvent(e ordermanager.Event) {
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e, "order_update", "", e.Order.Exchange)
+	}
+}
+
+// GetOrders returns the tracked orders matching the filter, oldest first
+func GetOrders(f *ordermanager.Filter) ([]ordermanager.Order, error) {
+	if bot.orders == nil {
+		return nil, errOrderManagerDisabled
+	}
+	if f.Exchange != "" && GetExchangeByName(f.Exchange) == nil {
+		return nil, ErrExchangeNotFound
+	}
+	return bot.orders.List(f), nil
+}
+
+// GetOrderReconciliations returns the latest reconciliation of each exchange
+// the order manager tracks
+func GetOrderReconciliations() ([]ordermanager.Reconciliation, error) {
+	if bot.orders == nil {
+		return nil, errOrderManagerDisabled
+	}
+	return bot.orders.GetReconciliations(), nil
+}
+
+// CancelAllTrackedOrders cancels every open tracked order matching the
+// filter, returning a result for each exchange with matching orders
+func CancelAllTrackedOrders(f *ordermanager.Filter) ([]exchange.CancelOrdersResult, error) {
+	if bot.orders == nil {
+		return nil, errOrderManagerDisabled
+	}
+	if f.Exchange != "" && GetExchangeByName(f.Exchange) == nil {
+		return nil, ErrExchangeNotFound
+	}
+
+	results := bot.orders.CancelAll(f)
+	for i := range results {
+		msg := fmt.Sprintf("%s cancelled %d tracked orders, %d failed",
+			results[i].Exchange, len(results[i].Cancelled), len(results[i].Failed))
+		log.Debugln(msg)
+		if bot.comms != nil {
+			bot.comms.PushEvent(base.Event{
+				Type:         "ORDERS_CANCELLED",
+				TradeDetails: msg,
+			})
+		}
+	}
+	return results, nil
+}
+
+// CancelOrdersWhere cancels all open orders matching the filter on the named
+// exchange, or on every loaded exchange with authenticated API support when
+// the name is empty. A result is returned for each exchange searched
+func CancelOrdersWhere(exchName string, f *exchange.OrderFilter) ([]exchange.CancelOrdersResult, error) {
+	if err := f.Validate(); err != nil {
+		return nil, err
+	}
+
+	var exchanges []exchange.IBotExchange
+	if exchName != "" {
+		exch := GetExchangeByName(exchName)
+		if exch == nil {
+			return nil, ErrExchangeNotFound
+		}
+		exchanges = append(exchanges, exch)
+	} else {
+		for _, exch := range GetLoadedExchanges() {
+			if exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+				exchanges = append(exchanges, exch)
+			}
+		}
+	}
+
+	var results []exchange.CancelOrdersResult
+	var errs []string
+	for i := range exchanges {
+		resp, err := exchange.CancelOrdersWhere(exchanges[i], f)
+		if err != nil {
+			log.Errorf("Failed to cancel %s orders by filter: %s", exchanges[i].GetName(), err)
+			errs = append(errs, exchanges[i].GetName()+": "+err.Error())
+		}
+		resp.Exchange = exchanges[i].GetName()
+		results = append(results, resp)
+		if len(resp.Cancelled) == 0 && len(resp.Failed) == 0 {
+			continue
+		}
+
+		msg := fmt.Sprintf("%s cancelled %d orders by filter, %d failed",
+			resp.Exchange, len(resp.Cancelled), len(resp.Failed))
+		log.Debugln(msg)
+		if bot.comms != nil {
+			bot.comms.PushEvent(base.Event{
+				Type:         "ORDERS_CANCELLED",
+				TradeDetails: msg,
+			})
+		}
+	}
+
+	if exchName != "" && len(errs) > 0 {
+		return results, fmt.Errorf("%s", strings.Join(errs, ", "))
+	}
+	return results, nil
+}
+
+// AmendOrder changes the price and amount of an open order on the named
+// exchange, a zero price or amount is left unchanged. Exchanges without native
+// amend support have the order cancelled and replaced
+func AmendOrder(exchName, orderID string, price, amount float64) (exchange.AmendResult, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return exchange.AmendResult{}, ErrExchangeNotFound
+	}
+
+	result, err := exchange.AmendOrder(exch, orderID, price, amount)
+	if err != nil {
+		return result, err
+	}
+
+	msg := fmt.Sprintf("%s order %s amended to %v @ %v, new order %s",
+		exch.GetName(), orderID, result.Amount, price, result.OrderID)
+	if result.Replaced {
+		msg += fmt.Sprintf(" replaced after %v filled", result.Filled)
+	}
+	log.Debugln(msg)
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "ORDER_AMENDED",
+			TradeDetails: msg,
+		})
+	}
+	return result, nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/pairstats"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errPairStatsDisabled = errors.New("pair stats not enabled")
+
+// ActivatePairStats wraps every loaded exchange so the orders the bot submits,
+// fills and cancels are counted per pair for the day. It wraps the exchanges
+// inside every guard so only orders which reach the exchange are counted
+func ActivatePairStats() {
+	if !bot.pairStats {
+		return
+	}
+
+	t := pairstats.New(handlePairStatsEvent)
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		bot.exchanges[x] = t.Track(bot.exchanges[x])
+	}
+	bot.pairStatsTracker = t
+	log.Debugf("Pair stats enabled.")
+}
+
+func handlePairStatsEvent(e pairstats.Event) {
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e, "order_activity", "", e.Exchange)
+	}
+}
+
+// GetPairStats returns the day's trading activity of every pair traded on the
+// named exchange, or on every exchange when the name is empty
+func GetPairStats(exchName string) ([]pairstats.Stats, error) {
+	if bot.pairStatsTracker == nil {
+		return nil, errPairStatsDisabled
+	}
+	if exchName != "" && GetExchangeByName(exchName) == nil {
+		return nil, ErrExchangeNotFound
+	}
+	return bot.pairStatsTracker.List(exchName), nil
+}
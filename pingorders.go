@@ -0,0 +1,96 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/pingorder"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var (
+	errPingOrdersDisabled   = errors.New("ping orders not enabled")
+	errInvalidPingOrderSpec = errors.New("ping orders must be in the format EXCHANGE:PAIR:AMOUNT")
+)
+
+// ActivatePingOrders starts periodically placing and immediately cancelling a
+// tiny buy order far below the market on each configured exchange, verifying
+// the authenticated order path works
+func ActivatePingOrders() {
+	if bot.pingOrders == "" {
+		return
+	}
+
+	targets, err := parsePingOrderTargets(bot.pingOrders)
+	if err != nil {
+		log.Errorf("Ping orders failed to start: %s", err)
+		return
+	}
+
+	c, err := pingorder.New(pingorder.Config{
+		Interval:    bot.pingOrderInterval,
+		MaxNotional: bot.pingOrderMaxNotional,
+	}, GetLoadedExchanges(), targets, handlePingOrderEvent)
+	if err != nil {
+		log.Errorf("Ping orders failed to start: %s", err)
+		return
+	}
+	c.Start()
+	bot.pingOrderChecker = c
+	log.Debugf("Ping orders enabled for %d exchanges.", len(targets))
+}
+
+// parsePingOrderTargets parses targets in the format
+// Poloniex:BTC-USDT:0.001,Kraken:XBT-USD:0.002 where each value is the ping
+// order amount
+func parsePingOrderTargets(s string) ([]pingorder.Target, error) {
+	var targets []pingorder.Target
+	for _, t := range strings.Split(s, ",") {
+		parts := strings.Split(strings.TrimSpace(t), ":")
+		if len(parts) != 3 || parts[0] == "" || parts[1] == "" {
+			return nil, errInvalidPingOrderSpec
+		}
+		amount, err := strconv.ParseFloat(parts[2], 64)
+		if err != nil {
+			return nil, errInvalidPingOrderSpec
+		}
+		targets = append(targets, pingorder.Target{
+			Exchange: parts[0],
+			Pair:     currency.NewPairDelimiter(parts[1], "-"),
+			Amount:   amount,
+		})
+	}
+	return targets, nil
+}
+
+func handlePingOrderEvent(e pingorder.Event) {
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Result, "ping_order", "", e.Result.Exchange)
+	}
+}
+
+// GetPingOrderStatus returns the ping order health of each configured exchange
+func GetPingOrderStatus() ([]pingorder.Status, error) {
+	if bot.pingOrderChecker == nil {
+		return nil, errPingOrdersDisabled
+	}
+	return bot.pingOrderChecker.GetStatus(), nil
+}
+
+// PingOrder places and cancels a ping order on the exchange immediately
+func PingOrder(exchName string) (pingorder.Result, error) {
+	if bot.pingOrderChecker == nil {
+		return pingorder.Result{}, errPingOrdersDisabled
+	}
+	return bot.pingOrderChecker.Ping(exchName)
+}
@@ -0,0 +1,100 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/drawdown"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+	"github.com/thrasher-corp/gocryptotrader/portfolio"
+)
+
+var errPnLAttributionDisabled = errors.New("PnL attribution not enabled")
+
+// attributionPricing is the currency holdings are priced in before
+// conversion to the base currency, the quote of most crypto markets
+var attributionPricing = currency.USD
+
+// ActivatePnLAttribution starts recording portfolio snapshots so the change in
+// value in the base currency, the fiat display currency unless set, can be
+// split between asset price moves and conversion rate moves
+func ActivatePnLAttribution() {
+	if !bot.pnlAttribution {
+		return
+	}
+	if bot.pnlBase == "" {
+		bot.pnlBase = bot.config.Currency.FiatDisplayCurrency.String()
+	}
+
+	h := portfolio.NewHistory(portfolio.DefaultSnapshotRetention)
+	h.Add(pnlSnapshot(time.Now()))
+	bot.pnlHistory = h
+
+	go func() {
+		for {
+			select {
+			case <-bot.shutdown:
+				return
+			case <-time.After(bot.pnlAttributionInterval):
+			}
+			h.Add(pnlSnapshot(time.Now()))
+		}
+	}()
+	log.Debugf("PnL attribution enabled in %s, snapshotting every %s.",
+		bot.pnlBase, bot.pnlAttributionInterval)
+}
+
+// attributionPrice returns the price of a coin in the pricing currency from
+// the first loaded exchange able to value it
+func attributionPrice(c currency.Code) (float64, error) {
+	err := errors.New("no exchange can value " + c.String())
+	for _, exch := range GetLoadedExchanges() {
+		var price float64
+		price, err = drawdown.ValueInQuote(exch, c, attributionPricing)
+		if err == nil {
+			return price, nil
+		}
+	}
+	return 0, err
+}
+
+func pnlSnapshot(t time.Time) portfolio.Snapshot {
+	v := bot.portfolio.GetValuation(attributionPrice)
+	return portfolio.NewSnapshot(&v, attributionPricing, t)
+}
+
+// attributionRate returns the conversion rate recorded at the time, rates
+// more recent than the rate history interval are read live
+func attributionRate(from, to currency.Code, t time.Time) (float64, error) {
+	if time.Since(t) < currency.RateHistoryInterval {
+		return currency.ConvertCurrency(1, from, to)
+	}
+	return currency.GetRateAt(from, to, t)
+}
+
+// GetPnLAttribution returns the change in the portfolio's value in the base
+// currency over the period, split between asset price moves, conversion rate
+// moves and flows. The period starts at the oldest snapshot when it predates
+// the history
+func GetPnLAttribution(period time.Duration) (portfolio.Attribution, error) {
+	if bot.pnlHistory == nil {
+		return portfolio.Attribution{}, errPnLAttributionDisabled
+	}
+	now := time.Now()
+	start, err := bot.pnlHistory.At(now.Add(-period))
+	if err != nil {
+		return portfolio.Attribution{}, err
+	}
+	end := pnlSnapshot(now)
+	// Coins sold since the start still need a price to value their flows
+	for c := range start.Holdings {
+		if _, ok := end.Prices[c]; ok {
+			continue
+		}
+		if price, err := attributionPrice(c); err == nil {
+			end.Prices[c] = price
+		}
+	}
+	return portfolio.Attribute(&start, &end, currency.NewCode(bot.pnlBase).Upper(), attributionRate)
+}
@@ -0,0 +1,98 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/polling"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errAdaptivePollingDisabled = errors.New("adaptive polling not enabled")
+
+// defaultPollInterval is the delay between REST ticker and orderbook polls
+// when adaptive polling is disabled
+const defaultPollInterval = time.Second * 10
+
+// ActivateAdaptivePolling starts scheduling each pair's REST ticker and
+// orderbook polls, and its websocket book depth, by its realized volatility
+// relative to the other pairs of its exchange. It requires the volatility
+// service
+func ActivateAdaptivePolling() {
+	if !bot.adaptivePolling {
+		return
+	}
+	if bot.volatilityService == nil {
+		log.Errorf("Adaptive polling failed to start: %s", errVolatilityDisabled)
+		return
+	}
+
+	s, err := polling.New(GetLoadedExchanges(), strategyVolatility, polling.Config{
+		Base: defaultPollInterval,
+		Min:  bot.adaptivePollingMin,
+		Max:  bot.adaptivePollingMax,
+	}, setOrderbookDepth)
+	if err != nil {
+		log.Errorf("Adaptive polling failed to start: %s", err)
+		return
+	}
+	s.Start(bot.volatilityInterval)
+	bot.pollingScheduler = s
+	log.Debugf("Adaptive polling enabled, polling pairs every %s to %s.",
+		bot.adaptivePollingMin, bot.adaptivePollingMax)
+}
+
+// setOrderbookDepth applies a pair's scheduled websocket orderbook depth on
+// exchanges supporting it
+func setOrderbookDepth(exchName string, p currency.Pair, depth int) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return
+	}
+	setter, ok := exchange.Underlying(exch).(exchange.OrderbookDepthSetter)
+	if !ok {
+		return
+	}
+	err := setter.SetOrderbookDepth(p, depth)
+	if err != nil {
+		log.Errorf("%s %s failed to set orderbook depth %d: %s", exchName, p, depth, err)
+		return
+	}
+	log.Debugf("%s %s websocket orderbook depth set to %d.", exchName, p, depth)
+}
+
+// duePairs returns the pairs whose data of the kind is due to be polled, all
+// pairs when adaptive polling is disabled
+func duePairs(exchName string, pairs []currency.Pair, kind string) []currency.Pair {
+	if bot.pollingScheduler == nil {
+		return pairs
+	}
+	now := time.Now()
+	var due []currency.Pair
+	for i := range pairs {
+		if bot.pollingScheduler.Due(exchName, pairs[i], kind, now) {
+			due = append(due, pairs[i])
+		}
+	}
+	return due
+}
+
+// pollInterval returns the delay between polling passes, the shortest
+// scheduled interval when adaptive polling is enabled
+func pollInterval() time.Duration {
+	if bot.pollingScheduler == nil {
+		return defaultPollInterval
+	}
+	return bot.pollingScheduler.MinInterval()
+}
+
+// GetPollingSchedules returns the polling interval and orderbook depth of
+// every scheduled pair
+func GetPollingSchedules() ([]polling.Schedule, error) {
+	if bot.pollingScheduler == nil {
+		return nil, errAdaptivePollingDisabled
+	}
+	return bot.pollingScheduler.GetAll(), nil
+}
@@ -0,0 +1,229 @@
+package portfolio
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+// Snapshot history settings
+const (
+	// DefaultSnapshotInterval is the delay between recorded snapshots
+	DefaultSnapshotInterval = time.Minute * 15
+	// DefaultSnapshotRetention matches the conversion rate history so every
+	// snapshot kept can be converted to the base currency
+	DefaultSnapshotRetention = currency.RateHistoryRetention
+)
+
+var (
+	errNoSnapshots      = errors.New("no portfolio snapshots recorded")
+	errSnapshotsReverse = errors.New("attribution end snapshot precedes its start")
+)
+
+// RateFunc returns the conversion rate between currencies at a time
+type RateFunc func(from, to currency.Code, t time.Time) (float64, error)
+
+// Snapshot holds the portfolio's holdings and their prices in the pricing
+// currency at a time
+type Snapshot struct {
+	Time     time.Time
+	Pricing  currency.Code
+	Holdings map[currency.Code]float64
+	Prices   map[currency.Code]float64
+}
+
+// NewSnapshot returns the holdings and prices of a valuation priced in the
+// pricing currency. Unpriced holdings are kept without a price
+func NewSnapshot(v *Valuation, pricing currency.Code, t time.Time) Snapshot {
+	s := Snapshot{
+		Time:     t,
+		Pricing:  pricing,
+		Holdings: make(map[currency.Code]float64),
+		Prices:   make(map[currency.Code]float64),
+	}
+	for i := range v.Holdings {
+		h := &v.Holdings[i]
+		s.Holdings[h.Coin] = h.Tradeable + h.NonTradeable
+		if !h.Unpriced {
+			s.Prices[h.Coin] = h.Price
+		}
+	}
+	return s
+}
+
+// CoinAttribution splits the change in a coin's value in the base currency.
+// Asset is the move in the coin's price in the pricing currency at the start
+// conversion rate, FX the move in the conversion rate to the base currency
+// and Flow the change in the amount held, from trading, deposits and
+// withdrawals, at end prices. Fiat holdings have no asset move
+type CoinAttribution struct {
+	Coin        currency.Code `json:"coin"`
+	StartAmount float64       `json:"start_amount"`
+	EndAmount   float64       `json:"end_amount"`
+	StartPrice  float64       `json:"start_price"`
+	EndPrice    float64       `json:"end_price"`
+	StartValue  float64       `json:"start_value"`
+	EndValue    float64       `json:"end_value"`
+	Asset       float64       `json:"asset"`
+	FX          float64       `json:"fx"`
+	Flow        float64       `json:"flow"`
+	Total       float64       `json:"total"`
+}
+
+// Attribution splits the change in the portfolio's value in the base
+// currency between asset price moves, conversion rate moves and flows
+type Attribution struct {
+	Base    currency.Code `json:"base"`
+	Pricing currency.Code `json:"pricing"`
+	Start   time.Time     `json:"start"`
+	End     time.Time     `json:"end"`
+	// StartRate and EndRate convert the pricing currency to the base
+	StartRate  float64           `json:"start_rate"`
+	EndRate    float64           `json:"end_rate"`
+	Coins      []CoinAttribution `json:"coins"`
+	StartValue float64           `json:"start_value"`
+	EndValue   float64           `json:"end_value"`
+	Asset      float64           `json:"asset"`
+	FX         float64           `json:"fx"`
+	Flow       float64           `json:"flow"`
+	Total      float64           `json:"total"`
+	// Unpriced lists coins missing a price or rate at either snapshot,
+	// which are excluded from the totals
+	Unpriced []currency.Code `json:"unpriced,omitempty"`
+}
+
+// Attribute splits the change in value in the base currency between the
+// snapshots, which must share a pricing currency
+func Attribute(start, end *Snapshot, base currency.Code, rate RateFunc) (Attribution, error) {
+	if end.Time.Before(start.Time) {
+		return Attribution{}, errSnapshotsReverse
+	}
+	a := Attribution{
+		Base:    base,
+		Pricing: start.Pricing,
+		Start:   start.Time,
+		End:     end.Time,
+	}
+	var err error
+	a.StartRate, err = rate(start.Pricing, base, start.Time)
+	if err != nil {
+		return Attribution{}, err
+	}
+	a.EndRate, err = rate(end.Pricing, base, end.Time)
+	if err != nil {
+		return Attribution{}, err
+	}
+
+	coins := make(map[currency.Code]struct{})
+	for c := range start.Holdings {
+		coins[c] = struct{}{}
+	}
+	for c := range end.Holdings {
+		coins[c] = struct{}{}
+	}
+	for c := range coins {
+		ca := CoinAttribution{
+			Coin:        c,
+			StartAmount: start.Holdings[c],
+			EndAmount:   end.Holdings[c],
+		}
+		if ca.StartAmount == 0 && ca.EndAmount == 0 {
+			continue
+		}
+		if c.IsFiatCurrency() {
+			// Fiat is valued by its own rate to the base, any move in its
+			// value is currency effect
+			ca.StartPrice, err = rate(c, base, start.Time)
+			if err == nil {
+				ca.EndPrice, err = rate(c, base, end.Time)
+			}
+			if err != nil {
+				a.Unpriced = append(a.Unpriced, c)
+				continue
+			}
+			ca.FX = ca.StartAmount * (ca.EndPrice - ca.StartPrice)
+			ca.Flow = (ca.EndAmount - ca.StartAmount) * ca.EndPrice
+			ca.StartValue = ca.StartAmount * ca.StartPrice
+			ca.EndValue = ca.EndAmount * ca.EndPrice
+		} else {
+			var ok bool
+			ca.StartPrice, ok = start.Prices[c]
+			if !ok && ca.StartAmount != 0 {
+				a.Unpriced = append(a.Unpriced, c)
+				continue
+			}
+			ca.EndPrice, ok = end.Prices[c]
+			if !ok {
+				a.Unpriced = append(a.Unpriced, c)
+				continue
+			}
+			ca.Asset = ca.StartAmount * (ca.EndPrice - ca.StartPrice) * a.StartRate
+			ca.FX = ca.StartAmount * ca.EndPrice * (a.EndRate - a.StartRate)
+			ca.Flow = (ca.EndAmount - ca.StartAmount) * ca.EndPrice * a.EndRate
+			ca.StartValue = ca.StartAmount * ca.StartPrice * a.StartRate
+			ca.EndValue = ca.EndAmount * ca.EndPrice * a.EndRate
+		}
+		ca.Total = ca.EndValue - ca.StartValue
+
+		a.StartValue += ca.StartValue
+		a.EndValue += ca.EndValue
+		a.Asset += ca.Asset
+		a.FX += ca.FX
+		a.Flow += ca.Flow
+		a.Coins = append(a.Coins, ca)
+	}
+	a.Total = a.EndValue - a.StartValue
+	sort.Slice(a.Coins, func(i, j int) bool {
+		return a.Coins[i].Coin.String() < a.Coins[j].Coin.String()
+	})
+	sort.Slice(a.Unpriced, func(i, j int) bool {
+		return a.Unpriced[i].String() < a.Unpriced[j].String()
+	})
+	return a, nil
+}
+
+// History holds the snapshots recorded within its retention, oldest first
+type History struct {
+	retention time.Duration
+	snapshots []Snapshot
+	mtx       sync.Mutex
+}
+
+// NewHistory returns a snapshot history keeping snapshots for the retention
+func NewHistory(retention time.Duration) *History {
+	if retention <= 0 {
+		retention = DefaultSnapshotRetention
+	}
+	return &History{retention: retention}
+}
+
+// Add records a snapshot, dropping snapshots older than the retention
+func (h *History) Add(s Snapshot) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	cutoff := s.Time.Add(-h.retention)
+	i := sort.Search(len(h.snapshots), func(i int) bool {
+		return h.snapshots[i].Time.After(cutoff)
+	})
+	h.snapshots = append(h.snapshots[i:], s)
+}
+
+// At returns the latest snapshot at or before the time, or the oldest
+// snapshot when none were recorded before it
+func (h *History) At(t time.Time) (Snapshot, error) {
+	h.mtx.Lock()
+	defer h.mtx.Unlock()
+	if len(h.snapshots) == 0 {
+		return Snapshot{}, errNoSnapshots
+	}
+	i := sort.Search(len(h.snapshots), func(i int) bool {
+		return h.snapshots[i].Time.After(t)
+	})
+	if i == 0 {
+		return h.snapshots[0], nil
+	}
+	return h.snapshots[i-1], nil
+}
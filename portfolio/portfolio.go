@@ -16,10 +16,16 @@ const (
 	ethplorerAPIURL      = "https://api.ethplorer.io"
 	ethplorerAddressInfo = "getAddressInfo"
 
+	blockchainInfoAPIURL         = "https://blockchain.info"
+	blockchainInfoAddressBalance = "q/addressbalance"
+
 	// PortfolioAddressExchange is a label for an exchange address
 	PortfolioAddressExchange = "Exchange"
 	// PortfolioAddressPersonal is a label for a personal/offline address
 	PortfolioAddressPersonal = "Personal"
+	// PortfolioAddressColdStorage is a label for a cold wallet address, its
+	// balance is kept when empty and is never tradeable
+	PortfolioAddressColdStorage = "ColdStorage"
 )
 
 // Portfolio is variable store holding an array of portfolioAddress
@@ -61,6 +67,42 @@ func GetCryptoIDAddress(address string, coinType currency.Code) (float64, error)
 	return result.(float64), nil
 }
 
+// GetBitcoinBalance queries Blockchain.info for a bitcoin address balance
+func GetBitcoinBalance(address string) (float64, error) {
+	valid, _ := common.IsValidCryptoAddress(address, "btc")
+	if !valid {
+		return 0, errors.New("not a Bitcoin address")
+	}
+
+	urlPath := fmt.Sprintf("%s/%s/%s?confirmations=1",
+		blockchainInfoAPIURL, blockchainInfoAddressBalance, address)
+	var satoshis int64
+	err := common.SendHTTPGetRequest(urlPath, true, false, &satoshis)
+	if err != nil {
+		return 0, err
+	}
+	return float64(satoshis) / 1e8, nil
+}
+
+// GetExplorerBalance returns an address balance from a public blockchain
+// explorer for the cryptocurrency
+func GetExplorerBalance(address string, coinType currency.Code) (float64, error) {
+	switch {
+	case coinType.Match(currency.BTC):
+		return GetBitcoinBalance(address)
+	case coinType.Match(currency.ETH):
+		result, err := GetEthereumBalance(address)
+		if err != nil {
+			return 0, err
+		}
+		if result.Error.Message != "" {
+			return 0, errors.New(result.Error.Message)
+		}
+		return result.ETH.Balance, nil
+	}
+	return GetCryptoIDAddress(address, coinType)
+}
+
 // GetAddressBalance acceses the portfolio base and returns the balance by passed
 // in address, coin type and description
 func (p *Base) GetAddressBalance(address, description string, coinType currency.Code) (float64, bool) {
@@ -167,6 +209,41 @@ func (p *Base) AddAddress(address, description string, coinType currency.Code, b
 	}
 }
 
+// AddColdStorageAddress adds a cold wallet address to the portfolio base, an
+// address already in the portfolio is relabelled as cold storage
+func (p *Base) AddColdStorageAddress(address string, coinType currency.Code) {
+	for x := range p.Addresses {
+		if p.Addresses[x].Address == address && p.Addresses[x].CoinType == coinType {
+			p.Addresses[x].Description = PortfolioAddressColdStorage
+			return
+		}
+	}
+	p.Addresses = append(p.Addresses, Address{Address: address,
+		CoinType: coinType, Description: PortfolioAddressColdStorage})
+}
+
+// UpdateColdStorage refreshes the balance of every cold wallet address from
+// public blockchain explorers. Addresses whose balance cannot be fetched keep
+// their last balance and the number of failures is returned
+func (p *Base) UpdateColdStorage() int {
+	numErrors := 0
+	for x := range p.Addresses {
+		if p.Addresses[x].Description != PortfolioAddressColdStorage {
+			continue
+		}
+		balance, err := GetExplorerBalance(p.Addresses[x].Address,
+			p.Addresses[x].CoinType)
+		if err != nil {
+			log.Errorf("Portfolio: Failed to update cold storage %s address %s: %s",
+				p.Addresses[x].CoinType, p.Addresses[x].Address, err)
+			numErrors++
+			continue
+		}
+		p.Addresses[x].Balance = balance
+	}
+	return numErrors
+}
+
 // RemoveAddress removes an address when checked against the correct address and
 // coinType
 func (p *Base) RemoveAddress(address, description string, coinType currency.Code) {
@@ -248,6 +325,17 @@ func (p *Base) GetExchangePortfolio() map[currency.Code]float64 {
 	return result
 }
 
+// GetColdStoragePortfolio returns the cold wallet balances by coin
+func (p *Base) GetColdStoragePortfolio() map[currency.Code]float64 {
+	result := make(map[currency.Code]float64)
+	for _, x := range p.Addresses {
+		if x.Description == PortfolioAddressColdStorage {
+			result[x.CoinType] += x.Balance
+		}
+	}
+	return result
+}
+
 // GetPersonalPortfolio returns current portfolio base information
 func (p *Base) GetPersonalPortfolio() map[currency.Code]float64 {
 	result := make(map[currency.Code]float64)
@@ -353,6 +441,14 @@ func (p *Base) GetPortfolioSummary() Summary {
 	}
 	portfolioOutput.OnlineSummary = exchangeSummary
 
+	for x, y := range p.GetColdStoragePortfolio() {
+		portfolioOutput.ColdStorage = append(portfolioOutput.ColdStorage, Coin{
+			Coin:       x,
+			Balance:    y,
+			Percentage: getPercentageSpecific(y, x, totalCoins),
+		})
+	}
+
 	offlineSummary := make(map[currency.Code][]OfflineCoinSummary)
 	for _, x := range p.Addresses {
 		if x.Description != PortfolioAddressExchange {
@@ -380,7 +476,8 @@ func (p *Base) GetPortfolioSummary() Summary {
 func (p *Base) GetPortfolioGroupedCoin() map[currency.Code][]string {
 	result := make(map[currency.Code][]string)
 	for _, x := range p.Addresses {
-		if common.StringContains(x.Description, PortfolioAddressExchange) {
+		if common.StringContains(x.Description, PortfolioAddressExchange) ||
+			x.Description == PortfolioAddressColdStorage {
 			continue
 		}
 		result[x.CoinType] = append(result[x.CoinType], x.Address)
@@ -411,6 +508,9 @@ func StartPortfolioWatcher() {
 				)
 			}
 		}
+		if n := Portfolio.UpdateColdStorage(); n > 0 {
+			log.Warnf("PortfolioWatcher: %d cold storage address(es) failed to update\n", n)
+		}
 		time.Sleep(time.Minute * 10)
 	}
 }
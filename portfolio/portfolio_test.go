@@ -1,6 +1,7 @@
 package portfolio
 
 import (
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -391,6 +392,89 @@ func TestGetPortfolioGroupedCoin(t *testing.T) {
 	}
 }
 
+func TestColdStorage(t *testing.T) {
+	newbase := Base{}
+	newbase.AddAddress("someaddress", PortfolioAddressPersonal, currency.BTC, 0.5)
+	newbase.AddColdStorageAddress("someaddress", currency.BTC)
+	newbase.AddColdStorageAddress("coldaddress", currency.BTC)
+	newbase.AddColdStorageAddress("coldaddress", currency.BTC)
+	newbase.AddAddress("Bitstamp", PortfolioAddressExchange, currency.BTC, 1)
+	if len(newbase.Addresses) != 3 {
+		t.Fatalf("Test Failed - AddColdStorageAddress() expected 3 addresses, got %d",
+			len(newbase.Addresses))
+	}
+
+	if b := newbase.GetColdStoragePortfolio()[currency.BTC]; b != 0.5 {
+		t.Errorf("Test Failed - GetColdStoragePortfolio() expected 0.5, got %v", b)
+	}
+	if len(newbase.GetPortfolioGroupedCoin()[currency.BTC]) != 0 {
+		t.Error("Test Failed - GetPortfolioGroupedCoin() cold storage addresses should not be grouped")
+	}
+	summary := newbase.GetPortfolioSummary()
+	if len(summary.ColdStorage) != 1 || summary.ColdStorage[0].Balance != 0.5 {
+		t.Errorf("Test Failed - GetPortfolioSummary() unexpected cold storage %v",
+			summary.ColdStorage)
+	}
+}
+
+func TestGetValuation(t *testing.T) {
+	newbase := Base{}
+	newbase.AddAddress("Bitstamp", PortfolioAddressExchange, currency.BTC, 1)
+	newbase.AddColdStorageAddress("coldaddress", currency.BTC)
+	newbase.Addresses[1].Balance = 3
+	newbase.AddAddress("Bitstamp", PortfolioAddressExchange, currency.USD, 2000)
+	newbase.AddAddress("Bitstamp", PortfolioAddressExchange, currency.DOGE, 100)
+
+	prices := map[currency.Code]float64{currency.BTC: 1000, currency.USD: 1}
+	v := newbase.GetValuation(func(c currency.Code) (float64, error) {
+		if p, ok := prices[c]; ok {
+			return p, nil
+		}
+		return 0, errors.New("no price")
+	})
+	if v.TradeableValue != 3000 || v.NonTradeableValue != 3000 || v.TotalValue != 6000 {
+		t.Errorf("Test Failed - GetValuation() unexpected totals %+v", v)
+	}
+	if len(v.Holdings) != 3 || v.Holdings[0].Coin != currency.BTC ||
+		v.Holdings[0].ColdStorage != 3 || v.Holdings[0].Value != 4000 {
+		t.Errorf("Test Failed - GetValuation() unexpected holdings %+v", v.Holdings)
+	}
+	if !v.Holdings[2].Unpriced {
+		t.Error("Test Failed - GetValuation() expected unpriced holding")
+	}
+
+	if _, err := v.Rebalance(nil); err != errNoTargetWeights {
+		t.Error("Test Failed - Rebalance() expected no weights error", err)
+	}
+	if _, err := v.Rebalance(map[currency.Code]float64{currency.BTC: 0.5}); err != errInvalidWeights {
+		t.Error("Test Failed - Rebalance() expected invalid weights error", err)
+	}
+	if _, err := v.Rebalance(map[currency.Code]float64{currency.DOGE: 1}); err == nil {
+		t.Error("Test Failed - Rebalance() expected unpriced coin error")
+	}
+
+	// 3 BTC in cold storage is worth half the portfolio on its own, so only
+	// the tradeable BTC can be sold towards a 40% target
+	a, err := v.Rebalance(map[currency.Code]float64{currency.BTC: 0.4, currency.USD: 0.6})
+	if err != nil {
+		t.Fatal("Test Failed - Rebalance() error", err)
+	}
+	if len(a) != 2 || a[0].Coin != currency.BTC || a[0].Amount != -1 || !a[0].Constrained {
+		t.Errorf("Test Failed - Rebalance() unexpected BTC adjustment %+v", a)
+	}
+	if a[1].TargetValue != 3600 || a[1].Amount != 1600 || a[1].Constrained {
+		t.Errorf("Test Failed - Rebalance() unexpected USD adjustment %+v", a[1])
+	}
+
+	a, err = v.Rebalance(map[currency.Code]float64{currency.BTC: 0.75, currency.USD: 0.25})
+	if err != nil {
+		t.Fatal("Test Failed - Rebalance() error", err)
+	}
+	if a[0].Amount != 0.5 || a[1].Amount != -500 {
+		t.Errorf("Test Failed - Rebalance() unexpected adjustments %+v", a)
+	}
+}
+
 func TestSeedPortfolio(t *testing.T) {
 	newbase := Base{}
 	newbase.AddAddress("someaddress", currency.LTC.String(), currency.LTC, 0.02)
@@ -430,3 +514,87 @@ func TestGetPortfolio(t *testing.T) {
 		t.Error("Test Failed - portfolio_test.go - GetoPortfolio error")
 	}
 }
+
+func TestAttribute(t *testing.T) {
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	end := start.Add(time.Hour * 24)
+	usdEUR := map[time.Time]float64{start: 0.9, end: 0.8}
+	rate := func(from, to currency.Code, at time.Time) (float64, error) {
+		if from.Match(to) {
+			return 1, nil
+		}
+		if from.Match(currency.USD) && to.Match(currency.EUR) {
+			return usdEUR[at], nil
+		}
+		return 0, errors.New("no rate")
+	}
+
+	before := Snapshot{
+		Time:     start,
+		Pricing:  currency.USD,
+		Holdings: map[currency.Code]float64{currency.BTC: 1, currency.USD: 1000, currency.EUR: 500, currency.DOGE: 100},
+		Prices:   map[currency.Code]float64{currency.BTC: 10000, currency.USD: 1},
+	}
+	after := Snapshot{
+		Time:     end,
+		Pricing:  currency.USD,
+		Holdings: map[currency.Code]float64{currency.BTC: 1.5, currency.EUR: 500},
+		Prices:   map[currency.Code]float64{currency.BTC: 12000},
+	}
+	if _, err := Attribute(&after, &before, currency.EUR, rate); err != errSnapshotsReverse {
+		t.Error("Test Failed - Attribute() expected reversed snapshots error", err)
+	}
+	a, err := Attribute(&before, &after, currency.EUR, rate)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// BTC's 2000 USD rise at 0.9 less the 0.1 rate fall on 12000 USD, with
+	// 0.5 BTC bought for the USD at 0.8
+	if len(a.Coins) != 3 || a.Coins[0].Coin != currency.BTC ||
+		!closeTo(a.Coins[0].Asset, 1800) || !closeTo(a.Coins[0].FX, -1200) || !closeTo(a.Coins[0].Flow, 4800) {
+		t.Fatalf("Test Failed - Attribute() unexpected coins %+v", a.Coins)
+	}
+	usd := a.Coins[2]
+	if usd.Coin != currency.USD || usd.Asset != 0 || !closeTo(usd.FX, -100) || !closeTo(usd.Flow, -800) {
+		t.Errorf("Test Failed - Attribute() unexpected fiat attribution %+v", usd)
+	}
+	if a.Coins[1].Total != 0 || a.Coins[1].EndValue != 500 {
+		t.Errorf("Test Failed - Attribute() base currency should not move %+v", a.Coins[1])
+	}
+	if !closeTo(a.Asset, 1800) || !closeTo(a.FX, -1300) || !closeTo(a.Flow, 4000) ||
+		!closeTo(a.Total, 4500) || !closeTo(a.StartValue, 10400) || !closeTo(a.EndValue, 14900) {
+		t.Errorf("Test Failed - Attribute() unexpected totals %+v", a)
+	}
+	if len(a.Unpriced) != 1 || a.Unpriced[0] != currency.DOGE {
+		t.Errorf("Test Failed - Attribute() expected DOGE unpriced %v", a.Unpriced)
+	}
+}
+
+func closeTo(a, b float64) bool {
+	return a-b < 1e-9 && b-a < 1e-9
+}
+
+func TestHistory(t *testing.T) {
+	h := NewHistory(time.Hour * 2)
+	if _, err := h.At(time.Now()); err != errNoSnapshots {
+		t.Error("Test Failed - At() expected no snapshots error", err)
+	}
+	start := time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	for i := 0; i < 4; i++ {
+		h.Add(Snapshot{Time: start.Add(time.Hour * time.Duration(i))})
+	}
+	if len(h.snapshots) != 2 {
+		t.Fatalf("Test Failed - Add() expected snapshots beyond the retention dropped, got %d", len(h.snapshots))
+	}
+	s, err := h.At(start.Add(time.Minute * 150))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !s.Time.Equal(start.Add(time.Hour * 2)) {
+		t.Errorf("Test Failed - At() unexpected snapshot %s", s.Time)
+	}
+	if s, _ = h.At(start); !s.Time.Equal(start.Add(time.Hour * 2)) {
+		t.Errorf("Test Failed - At() expected the oldest snapshot, got %s", s.Time)
+	}
+}
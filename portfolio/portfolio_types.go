@@ -116,4 +116,5 @@ type Summary struct {
 	OfflineSummary map[currency.Code][]OfflineCoinSummary         `json:"offline_summary"`
 	Online         []Coin                                         `json:"coins_online"`
 	OnlineSummary  map[string]map[currency.Code]OnlineCoinSummary `json:"online_summary"`
+	ColdStorage    []Coin                                         `json:"coins_cold_storage"`
 }
@@ -0,0 +1,149 @@
+package portfolio
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+)
+
+var (
+	errNoTargetWeights = errors.New("no target weights supplied")
+	errInvalidWeights  = errors.New("target weights must be positive and sum to 1")
+	errNoValuation     = errors.New("portfolio has no valued holdings")
+)
+
+// PriceFunc returns the price of a coin in the valuation currency
+type PriceFunc func(c currency.Code) (float64, error)
+
+// Holding is a coin's balance split by whether the bot can trade it. Exchange
+// balances are tradeable, cold storage and other offline addresses are not
+type Holding struct {
+	Coin         currency.Code `json:"coin"`
+	Tradeable    float64       `json:"tradeable"`
+	NonTradeable float64       `json:"non_tradeable"`
+	ColdStorage  float64       `json:"cold_storage"`
+	Price        float64       `json:"price"`
+	Value        float64       `json:"value"`
+	Unpriced     bool          `json:"unpriced,omitempty"`
+}
+
+// Valuation is the portfolio's value including non tradeable holdings
+type Valuation struct {
+	Holdings          []Holding `json:"holdings"`
+	TradeableValue    float64   `json:"tradeable_value"`
+	NonTradeableValue float64   `json:"non_tradeable_value"`
+	TotalValue        float64   `json:"total_value"`
+}
+
+// Adjustment is the trade needed for a coin to reach its target weight. Only
+// tradeable balances can be sold, so a coin whose non tradeable holdings alone
+// exceed its target is constrained
+type Adjustment struct {
+	Coin         currency.Code `json:"coin"`
+	Weight       float64       `json:"weight"`
+	CurrentValue float64       `json:"current_value"`
+	TargetValue  float64       `json:"target_value"`
+	Amount       float64       `json:"amount"`
+	Constrained  bool          `json:"constrained,omitempty"`
+}
+
+// GetValuation values every holding with the price function. Coins which
+// cannot be priced are returned as unpriced and excluded from the totals
+func (p *Base) GetValuation(price PriceFunc) Valuation {
+	holdings := make(map[currency.Code]*Holding)
+	for _, x := range p.Addresses {
+		h, ok := holdings[x.CoinType]
+		if !ok {
+			h = &Holding{Coin: x.CoinType}
+			holdings[x.CoinType] = h
+		}
+		switch x.Description {
+		case PortfolioAddressExchange:
+			h.Tradeable += x.Balance
+		case PortfolioAddressColdStorage:
+			h.ColdStorage += x.Balance
+			h.NonTradeable += x.Balance
+		default:
+			h.NonTradeable += x.Balance
+		}
+	}
+
+	var v Valuation
+	for _, h := range holdings {
+		px, err := price(h.Coin)
+		if err != nil || px <= 0 {
+			h.Unpriced = true
+		} else {
+			h.Price = px
+			h.Value = (h.Tradeable + h.NonTradeable) * px
+			v.TradeableValue += h.Tradeable * px
+			v.NonTradeableValue += h.NonTradeable * px
+		}
+		v.Holdings = append(v.Holdings, *h)
+	}
+	v.TotalValue = v.TradeableValue + v.NonTradeableValue
+	sort.Slice(v.Holdings, func(i, j int) bool {
+		return v.Holdings[i].Value > v.Holdings[j].Value
+	})
+	return v
+}
+
+// Rebalance returns the trades moving the valuation's holdings to the target
+// weights, which are fractions of the total value including non tradeable
+// holdings. A positive amount is bought and a negative amount sold, sells are
+// limited to the tradeable balance. Priced coins without a weight target zero
+func (v *Valuation) Rebalance(weights map[currency.Code]float64) ([]Adjustment, error) {
+	if len(weights) == 0 {
+		return nil, errNoTargetWeights
+	}
+	var sum float64
+	for _, w := range weights {
+		if w < 0 {
+			return nil, errInvalidWeights
+		}
+		sum += w
+	}
+	if math.Abs(sum-1) > 1e-6 {
+		return nil, errInvalidWeights
+	}
+	if v.TotalValue <= 0 {
+		return nil, errNoValuation
+	}
+
+	holdings := make(map[currency.Code]Holding)
+	for i := range v.Holdings {
+		holdings[v.Holdings[i].Coin] = v.Holdings[i]
+	}
+	for c := range weights {
+		h, ok := holdings[c]
+		if !ok || h.Unpriced {
+			return nil, fmt.Errorf("%s has no price to rebalance with", c)
+		}
+	}
+
+	var adjustments []Adjustment
+	for c, h := range holdings {
+		if h.Unpriced {
+			continue
+		}
+		a := Adjustment{
+			Coin:         c,
+			Weight:       weights[c],
+			CurrentValue: h.Value,
+			TargetValue:  weights[c] * v.TotalValue,
+		}
+		a.Amount = (a.TargetValue - a.CurrentValue) / h.Price
+		if -a.Amount > h.Tradeable {
+			a.Amount = -h.Tradeable
+			a.Constrained = true
+		}
+		adjustments = append(adjustments, a)
+	}
+	sort.Slice(adjustments, func(i, j int) bool {
+		return adjustments[i].Coin.String() < adjustments[j].Coin.String()
+	})
+	return adjustments, nil
+}
@@ -0,0 +1,28 @@
+package main
+
+import (
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// ActivatePreflightValidation wraps every loaded exchange so orders are
+// validated exchange side before they are submitted, on exchanges which
+// support validating orders. It wraps the exchanges before any other guard so
+// orders are validated exactly as they are submitted
+func ActivatePreflightValidation() {
+	if !bot.preflight {
+		return
+	}
+
+	var validated int
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		if _, ok := exchange.Underlying(bot.exchanges[x]).(exchange.OrderValidator); ok {
+			validated++
+		}
+		bot.exchanges[x] = exchange.NewPreflight(bot.exchanges[x])
+	}
+	log.Debugf("Order preflight validation enabled, %d exchanges validate orders.", validated)
+}
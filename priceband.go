@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/priceband"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errPriceBandDisabled = errors.New("price band not enabled")
+
+// ActivatePriceBand guards every loaded exchange so orders are rejected while
+// its last price of the pair deviates from the median of the other venues'
+// stored tickers by more than the band
+func ActivatePriceBand() {
+	if bot.priceBand == 0 {
+		return
+	}
+
+	g, err := priceband.New(priceband.Config{
+		Band:  bot.priceBand,
+		Pause: bot.priceBandPause,
+	}, venuePrices, handlePriceBandEvent)
+	if err != nil {
+		log.Errorf("Price band failed to start: %s", err)
+		return
+	}
+
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		bot.exchanges[x] = g.Guard(bot.exchanges[x])
+	}
+	g.Start(priceband.DefaultCheckInterval)
+	bot.priceBandGuard = g
+	log.Debugf("Price band enabled, rejecting orders deviating %v%% from the index.", bot.priceBand*100)
+}
+
+// venuePrices returns every loaded exchange's stored last price of the pair
+func venuePrices(p currency.Pair, assetType string) []priceband.Price {
+	var resp []priceband.Price
+	for _, exch := range GetLoadedExchanges() {
+		name := exch.GetName()
+		t, err := ticker.GetTicker(name, p, assetType)
+		if err != nil && p.Base.Match(currency.BTC) {
+			t, err = ticker.GetTicker(name, currency.NewPair(currency.XBT, p.Quote), assetType)
+		}
+		if err != nil {
+			continue
+		}
+		resp = append(resp, priceband.Price{
+			Exchange: name,
+			Last:     t.Last,
+			Updated:  t.LastUpdated,
+		})
+	}
+	return resp
+}
+
+func handlePriceBandEvent(e priceband.Event) {
+	if e.Type == priceband.Breached {
+		log.Warnf("Price band: %s", e.String())
+	}
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Status, "price_band", e.Status.AssetType, e.Status.Exchange)
+	}
+}
+
+// GetPriceBandStatus returns each evaluated pair's last price against the
+// index and whether its orders are paused
+func GetPriceBandStatus() ([]priceband.Status, error) {
+	if bot.priceBandGuard == nil {
+		return nil, errPriceBandDisabled
+	}
+	return bot.priceBandGuard.GetStatus(), nil
+}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"io"
+
+	"github.com/thrasher-corp/gocryptotrader/accounting"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/reconcile"
+	"github.com/thrasher-corp/gocryptotrader/statereport"
+)
+
+// reconcileSources returns the wallet history sources explaining balance
+// changes of an exchange over the period. Kraken's ledger records every
+// balance change, other exchanges report transfers and filled orders
+// separately
+func reconcileSources(exch exchange.IBotExchange, before, after *statereport.Report) []accounting.Source {
+	if e, ok := exchange.Underlying(exch).(*kraken.Kraken); ok {
+		return []accounting.Source{&accounting.KrakenLedger{Exchange: e}}
+	}
+	return []accounting.Source{
+		&accounting.FundingHistory{Exchange: exch},
+		&accounting.OrderHistory{Exchange: exch, Start: before.Generated, End: after.Generated},
+	}
+}
+
+// Reconcile writes the balance and position changes between the signed state
+// report in the before file and the report in the after file, or the live
+// state when no after file is given. Changes are cross-referenced against the
+// wallet history recorded over the period and those left unexplained beyond
+// the tolerance are flagged
+func Reconcile(w io.Writer, beforePath, afterPath string, tolerance float64) error {
+	before, err := ReadStateReport(beforePath)
+	if err != nil {
+		return err
+	}
+	var after statereport.Report
+	if afterPath != "" {
+		after, err = ReadStateReport(afterPath)
+		if err != nil {
+			return err
+		}
+	} else {
+		after = buildStateReport()
+	}
+
+	var entries []accounting.Entry
+	var notes []string
+	for i := range after.Venues {
+		exch := GetExchangeByName(after.Venues[i].Exchange)
+		if exch == nil {
+			notes = append(notes, after.Venues[i].Exchange+" history: exchange not loaded")
+			continue
+		}
+		for _, s := range reconcileSources(exch, &before, &after) {
+			resp, err := s.GetEntries()
+			if err != nil {
+				notes = append(notes, exch.GetName()+" history: "+err.Error())
+				continue
+			}
+			entries = append(entries, resp...)
+		}
+	}
+
+	d, err := reconcile.Compare(&before, &after, entries, tolerance)
+	if err != nil {
+		return err
+	}
+	d.Notes = append(d.Notes, notes...)
+	return d.Write(w)
+}
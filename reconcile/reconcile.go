@@ -0,0 +1,354 @@
+// Package reconcile compares two state reports, or a report and the live
+// state, and explains the balance changes between them with the fills, fees,
+// transfers and funding recorded over the period. Changes left unexplained
+// beyond a tolerance are flagged as discrepancies for investigation
+package reconcile
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/accounting"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/breakeven"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-corp/gocryptotrader/statereport"
+)
+
+// DefaultTolerance is the fraction of a balance which may change unexplained
+// before it is flagged
+const DefaultTolerance = 0.0001
+
+// Explanation types in addition to the wallet history entry types
+const (
+	Fee     = "fee"
+	Funding = "funding"
+)
+
+// dust is the unexplained amount always tolerated, absorbing float noise on
+// empty balances
+const dust = 1e-8
+
+var (
+	errInvalidTolerance = errors.New("reconciliation tolerance must be between 0 and 1")
+	errOutOfOrder       = errors.New("reconciliation reports must be compared oldest first")
+)
+
+// Balance holds the change of a currency balance on an exchange between the
+// reports
+type Balance struct {
+	Exchange string
+	Currency string
+	Before   float64
+	After    float64
+	// Explained holds the recorded changes by wallet history entry type,
+	// with fees charged held under Fee and position funding under Funding
+	Explained   map[string]float64
+	Unexplained float64
+	Discrepancy bool
+}
+
+// Change returns the change of the balance
+func (b *Balance) Change() float64 {
+	return b.After - b.Before
+}
+
+// explained returns the total recorded change
+func (b *Balance) explained() float64 {
+	var total float64
+	for _, v := range b.Explained {
+		total += v
+	}
+	return total
+}
+
+// Position holds the change of a tracked position between the reports
+type Position struct {
+	Exchange  string
+	Pair      currency.Pair
+	AssetType string
+	Before    float64
+	After     float64
+	// Fees and Funding are the costs accrued between the reports, in the
+	// position's settlement currency
+	Fees    float64
+	Funding float64
+}
+
+// Diff holds the reconciled changes between two reports
+type Diff struct {
+	From      time.Time
+	To        time.Time
+	Tolerance float64
+	Balances  []Balance
+	Positions []Position
+	// Notes lists the state and history which could not be fetched. Venues
+	// missing balances in either report are not reconciled
+	Notes []string
+}
+
+// Discrepancies returns the balances with changes left unexplained beyond the
+// tolerance
+func (d *Diff) Discrepancies() []Balance {
+	var resp []Balance
+	for i := range d.Balances {
+		if d.Balances[i].Discrepancy {
+			resp = append(resp, d.Balances[i])
+		}
+	}
+	return resp
+}
+
+// balances returns the total balance of each currency of each venue keyed by
+// exchange and currency, nil for venues whose balances were not fetched
+func balances(r *statereport.Report, label string, notes *[]string) map[string]map[string]float64 {
+	resp := make(map[string]map[string]float64)
+	for i := range r.Venues {
+		v := &r.Venues[i]
+		var failed bool
+		for _, e := range v.Errors {
+			if strings.HasPrefix(e, "balances") {
+				failed = true
+				*notes = append(*notes, fmt.Sprintf("%s %s %s", v.Exchange, label, e))
+			}
+		}
+		if failed {
+			resp[v.Exchange] = nil
+			continue
+		}
+		b := make(map[string]float64)
+		for j := range v.Balances {
+			for k := range v.Balances[j].Currencies {
+				c := &v.Balances[j].Currencies[k]
+				b[c.CurrencyName.Upper().String()] += c.TotalValue
+			}
+		}
+		resp[v.Exchange] = b
+	}
+	return resp
+}
+
+// positionKey identifies a position across reports
+func positionKey(p *breakeven.Position) string {
+	return strings.ToLower(p.Exchange + " " + p.Pair.String() + " " + p.AssetType)
+}
+
+// settlement returns the currency a position's costs are charged in
+func settlement(p *breakeven.Position) string {
+	if p.Inverse {
+		return p.Pair.Base.Upper().String()
+	}
+	return p.Pair.Quote.Upper().String()
+}
+
+// Compare reconciles the balances of the before and after reports against
+// the wallet history entries recorded between them. Derivative position fees
+// and funding accrued between the reports explain changes to their settlement
+// currency, spot fills are expected in the wallet history
+func Compare(before, after *statereport.Report, entries []accounting.Entry, tolerance float64) (Diff, error) {
+	if tolerance < 0 || tolerance >= 1 {
+		return Diff{}, errInvalidTolerance
+	}
+	if after.Generated.Before(before.Generated) {
+		return Diff{}, errOutOfOrder
+	}
+	d := Diff{
+		From:      before.Generated,
+		To:        after.Generated,
+		Tolerance: tolerance,
+	}
+
+	from := balances(before, "before", &d.Notes)
+	to := balances(after, "after", &d.Notes)
+	explained := make(map[string]map[string]map[string]float64)
+	explain := func(exchName, c, kind string, amount float64) {
+		if amount == 0 {
+			return
+		}
+		if explained[exchName] == nil {
+			explained[exchName] = make(map[string]map[string]float64)
+		}
+		if explained[exchName][c] == nil {
+			explained[exchName][c] = make(map[string]float64)
+		}
+		explained[exchName][c][kind] += amount
+	}
+
+	for i := range entries {
+		e := &entries[i]
+		if !e.Time.After(d.From) || e.Time.After(d.To) {
+			continue
+		}
+		for j := range e.Legs {
+			c := strings.ToUpper(e.Legs[j].Currency)
+			explain(e.Exchange, c, e.Type, e.Legs[j].Amount)
+			explain(e.Exchange, c, Fee, -e.Legs[j].Fee)
+		}
+	}
+
+	positions := make(map[string]*breakeven.Position)
+	for i := range before.Positions {
+		positions[positionKey(&before.Positions[i])] = &before.Positions[i]
+	}
+	seen := make(map[string]bool)
+	for i := range after.Positions {
+		p := &after.Positions[i]
+		k := positionKey(p)
+		seen[k] = true
+		change := Position{Exchange: p.Exchange, Pair: p.Pair, AssetType: p.AssetType, After: p.Size, Fees: p.EntryFees, Funding: p.FundingPaid}
+		if prev, ok := positions[k]; ok {
+			change.Before = prev.Size
+			change.Fees -= prev.EntryFees
+			change.Funding -= prev.FundingPaid
+		}
+		if !strings.EqualFold(p.AssetType, ticker.Spot) {
+			explain(p.Exchange, settlement(p), Fee, -change.Fees)
+			explain(p.Exchange, settlement(p), Funding, -change.Funding)
+		}
+		d.Positions = append(d.Positions, change)
+	}
+	for i := range before.Positions {
+		p := &before.Positions[i]
+		if !seen[positionKey(p)] {
+			d.Positions = append(d.Positions, Position{Exchange: p.Exchange, Pair: p.Pair, AssetType: p.AssetType, Before: p.Size})
+		}
+	}
+
+	for exchName, b := range to {
+		a := from[exchName]
+		if a == nil || b == nil {
+			continue
+		}
+		currencies := make(map[string]bool)
+		for c := range a {
+			currencies[c] = true
+		}
+		for c := range b {
+			currencies[c] = true
+		}
+		for c := range explained[exchName] {
+			currencies[c] = true
+		}
+		for c := range currencies {
+			bal := Balance{
+				Exchange:  exchName,
+				Currency:  c,
+				Before:    a[c],
+				After:     b[c],
+				Explained: explained[exchName][c],
+			}
+			bal.Unexplained = bal.Change() - bal.explained()
+			limit := math.Max(dust, tolerance*math.Max(math.Abs(bal.Before), math.Abs(bal.After)))
+			bal.Discrepancy = math.Abs(bal.Unexplained) > limit
+			if bal.Change() == 0 && len(bal.Explained) == 0 {
+				continue
+			}
+			d.Balances = append(d.Balances, bal)
+		}
+	}
+	for exchName := range from {
+		if _, ok := to[exchName]; !ok {
+			d.Notes = append(d.Notes, exchName+" missing from after report")
+		}
+	}
+	for exchName := range to {
+		if _, ok := from[exchName]; !ok {
+			d.Notes = append(d.Notes, exchName+" missing from before report")
+		}
+	}
+
+	sort.Slice(d.Balances, func(i, j int) bool {
+		if d.Balances[i].Exchange != d.Balances[j].Exchange {
+			return d.Balances[i].Exchange < d.Balances[j].Exchange
+		}
+		return d.Balances[i].Currency < d.Balances[j].Currency
+	})
+	sort.Slice(d.Positions, func(i, j int) bool {
+		a, b := &d.Positions[i], &d.Positions[j]
+		if a.Exchange != b.Exchange {
+			return a.Exchange < b.Exchange
+		}
+		if a.Pair.String() != b.Pair.String() {
+			return a.Pair.String() < b.Pair.String()
+		}
+		return a.AssetType < b.AssetType
+	})
+	sort.Strings(d.Notes)
+	return d, nil
+}
+
+// format returns the amount rounded to the satoshi, hiding float noise
+func format(v float64) string {
+	v = math.Round(v/dust) * dust
+	if v == 0 {
+		// avoids printing negative zero
+		v = 0
+	}
+	return strconv.FormatFloat(v, 'f', -1, 64)
+}
+
+// signed returns the formatted amount with its sign
+func signed(v float64) string {
+	s := format(v)
+	if !strings.HasPrefix(s, "-") {
+		s = "+" + s
+	}
+	return s
+}
+
+// Write writes the diff as a human readable report, listing each balance
+// change with its recorded explanation and flagging discrepancies
+func (d *Diff) Write(w io.Writer) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "Reconciliation %s to %s, tolerance %v%%\n\n",
+		d.From.Format(time.RFC3339), d.To.Format(time.RFC3339), d.Tolerance*100)
+
+	fmt.Fprintln(tw, "EXCHANGE\tCURRENCY\tBEFORE\tAFTER\tCHANGE\tEXPLAINED BY\tUNEXPLAINED\t")
+	for i := range d.Balances {
+		b := &d.Balances[i]
+		status := "ok"
+		if b.Discrepancy {
+			status = "DISCREPANCY"
+		}
+		kinds := make([]string, 0, len(b.Explained))
+		for k := range b.Explained {
+			kinds = append(kinds, k)
+		}
+		sort.Strings(kinds)
+		explanation := make([]string, 0, len(kinds))
+		for _, k := range kinds {
+			explanation = append(explanation, k+" "+signed(b.Explained[k]))
+		}
+		if len(explanation) == 0 {
+			explanation = append(explanation, "nothing recorded")
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", b.Exchange, b.Currency,
+			format(b.Before), format(b.After), signed(b.Change()), strings.Join(explanation, ", "),
+			signed(b.Unexplained), status)
+	}
+
+	if len(d.Positions) > 0 {
+		fmt.Fprintln(tw, "\nPOSITION\tASSET\tBEFORE\tAFTER\tFEES\tFUNDING\t")
+		for i := range d.Positions {
+			p := &d.Positions[i]
+			fmt.Fprintf(tw, "%s %s\t%s\t%s\t%s\t%s\t%s\t\n", p.Exchange, p.Pair, p.AssetType,
+				format(p.Before), format(p.After), format(p.Fees), format(p.Funding))
+		}
+	}
+
+	if len(d.Notes) > 0 {
+		fmt.Fprintln(tw, "\nIncomplete:")
+		for _, n := range d.Notes {
+			fmt.Fprintln(tw, "  "+n)
+		}
+	}
+	fmt.Fprintf(tw, "\n%d discrepancies found\n", len(d.Discrepancies()))
+	return tw.Flush()
+}
@@ -0,0 +1,142 @@
+package reconcile
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/accounting"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/breakeven"
+	"github.com/thrasher-corp/gocryptotrader/statereport"
+)
+
+var (
+	testFrom = time.Date(2019, 6, 1, 0, 0, 0, 0, time.UTC)
+	testTo   = testFrom.Add(time.Hour * 24)
+)
+
+func testReport(generated time.Time, btc, usd, xbt, funding float64) statereport.Report {
+	return statereport.Report{
+		Generated: generated,
+		Venues: []statereport.Venue{
+			{Exchange: "Bitstamp", Balances: []exchange.Account{{Currencies: []exchange.AccountCurrencyInfo{
+				{CurrencyName: currency.BTC, TotalValue: btc},
+				{CurrencyName: currency.USD, TotalValue: usd},
+			}}}},
+			{Exchange: "Bitmex", Balances: []exchange.Account{{Currencies: []exchange.AccountCurrencyInfo{
+				{CurrencyName: currency.XBT, TotalValue: xbt},
+			}}}},
+		},
+		Positions: []breakeven.Position{{
+			Exchange:    "Bitmex",
+			Pair:        currency.NewPair(currency.XBT, currency.USD),
+			AssetType:   "perpetual",
+			Inverse:     true,
+			Size:        1000,
+			FundingPaid: funding,
+		}},
+	}
+}
+
+func TestCompare(t *testing.T) {
+	before := testReport(testFrom, 1, 1000, 2, 0.001)
+	// sold 0.5 BTC for 4000 USD paying 10 USD, a 0.01 BTC deposit never
+	// recorded and 0.002 XBT funding paid
+	after := testReport(testTo, 0.51, 4990, 1.998, 0.003)
+	entries := []accounting.Entry{
+		{Time: testFrom.Add(time.Hour), Exchange: "Bitstamp", Type: accounting.Trade, Legs: []accounting.Leg{
+			{Currency: "btc", Amount: -0.5},
+			{Currency: "USD", Amount: 4000, Fee: 10},
+		}},
+		// outside the period
+		{Time: testFrom.Add(-time.Hour), Exchange: "Bitstamp", Type: accounting.Deposit, Legs: []accounting.Leg{
+			{Currency: "BTC", Amount: 0.01},
+		}},
+	}
+
+	d, err := Compare(&before, &after, entries, DefaultTolerance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Balances) != 3 || len(d.Positions) != 1 {
+		t.Fatalf("Test Failed - Compare() unexpected diff %+v", d)
+	}
+	bitmex, btc, usd := d.Balances[0], d.Balances[1], d.Balances[2]
+	if bitmex.Currency != "XBT" || bitmex.Discrepancy || bitmex.Explained[Funding] != -0.002 {
+		t.Errorf("Test Failed - Compare() funding not explained %+v", bitmex)
+	}
+	if btc.Currency != "BTC" || !btc.Discrepancy || !closeTo(btc.Unexplained, 0.01) {
+		t.Errorf("Test Failed - Compare() unrecorded deposit not flagged %+v", btc)
+	}
+	if usd.Currency != "USD" || usd.Discrepancy || usd.Explained[Fee] != -10 {
+		t.Errorf("Test Failed - Compare() trade not explained %+v", usd)
+	}
+	if p := d.Positions[0]; p.Funding != 0.002 || p.Before != 1000 || p.After != 1000 {
+		t.Errorf("Test Failed - Compare() unexpected position change %+v", p)
+	}
+	if len(d.Discrepancies()) != 1 {
+		t.Errorf("Test Failed - Discrepancies() expected 1, got %d", len(d.Discrepancies()))
+	}
+
+	// a loose tolerance accepts the deposit
+	d, err = Compare(&before, &after, entries, 0.05)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Discrepancies()) != 0 {
+		t.Errorf("Test Failed - Compare() unexpected discrepancies %+v", d.Discrepancies())
+	}
+
+	if _, err = Compare(&after, &before, entries, DefaultTolerance); err != errOutOfOrder {
+		t.Error("Test Failed - Compare() expected out of order error", err)
+	}
+	if _, err = Compare(&before, &after, entries, 1); err != errInvalidTolerance {
+		t.Error("Test Failed - Compare() expected invalid tolerance error", err)
+	}
+}
+
+func closeTo(a, b float64) bool {
+	return a-b < 1e-12 && b-a < 1e-12
+}
+
+func TestCompareIncomplete(t *testing.T) {
+	before := testReport(testFrom, 1, 1000, 2, 0)
+	after := testReport(testTo, 2, 1000, 2, 0)
+	after.Venues[0].Balances = nil
+	after.Venues[0].Errors = []string{"balances: timeout"}
+	after.Venues = append(after.Venues, statereport.Venue{Exchange: "Kraken"})
+
+	d, err := Compare(&before, &after, nil, DefaultTolerance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(d.Balances) != 0 {
+		t.Errorf("Test Failed - Compare() venue without balances reconciled %+v", d.Balances)
+	}
+	if len(d.Notes) != 2 || d.Notes[0] != "Bitstamp after balances: timeout" ||
+		d.Notes[1] != "Kraken missing from before report" {
+		t.Errorf("Test Failed - Compare() unexpected notes %v", d.Notes)
+	}
+}
+
+func TestWrite(t *testing.T) {
+	before := testReport(testFrom, 1, 1000, 2, 0)
+	after := testReport(testTo, 1.01, 1000, 2, 0)
+	d, err := Compare(&before, &after, nil, DefaultTolerance)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var b bytes.Buffer
+	if err = d.Write(&b); err != nil {
+		t.Fatal(err)
+	}
+	out := b.String()
+	for _, s := range []string{"2019-06-01T00:00:00Z to 2019-06-02T00:00:00Z", "nothing recorded", "DISCREPANCY", "1 discrepancies found"} {
+		if !strings.Contains(out, s) {
+			t.Errorf("Test Failed - Write() expected %q in\n%s", s, out)
+		}
+	}
+}
@@ -99,6 +99,24 @@ func NewRouter() *mux.Router {
 			"/portfolio/all",
 			RESTGetPortfolio,
 		},
+		Route{
+			"GetPortfolioValuation",
+			http.MethodGet,
+			"/portfolio/valuation",
+			RESTGetPortfolioValuation,
+		},
+		Route{
+			"GetPortfolioRebalance",
+			http.MethodGet,
+			"/portfolio/rebalance",
+			RESTGetPortfolioRebalance,
+		},
+		Route{
+			"GetPortfolioSnapshot",
+			http.MethodGet,
+			"/portfolio/snapshot",
+			RESTGetPortfolioSnapshot,
+		},
 		Route{
 			"AllActiveExchangesAndOrderbooks",
 			http.MethodGet,
@@ -111,6 +129,534 @@ func NewRouter() *mux.Router {
 			"/exchanges/{exchangeName}/orderbook/latest/{currency}",
 			RESTGetOrderbook,
 		},
+		Route{
+			"ConsolidatedOrderbookDepth",
+			http.MethodGet,
+			"/orderbook/depth/{currency}",
+			RESTGetOrderbookDepth,
+		},
+		Route{
+			"IndividualExchangeOrderbookDepth",
+			http.MethodGet,
+			"/exchanges/{exchangeName}/orderbook/depth/{currency}",
+			RESTGetOrderbookDepth,
+		},
+		Route{
+			"VenueTransactionCostReports",
+			http.MethodGet,
+			"/exchanges/tca/{currency}",
+			RESTGetVenueReports,
+		},
+		Route{
+			"VenueRoutes",
+			http.MethodGet,
+			"/exchanges/route/{currency}",
+			RESTGetVenueRoutes,
+		},
+		Route{
+			"ConvertAsset",
+			http.MethodPost,
+			"/exchanges/{exchangeName}/convert",
+			RESTConvertAsset,
+		},
+		Route{
+			"DrawdownBreakerStatus",
+			http.MethodGet,
+			"/drawdown/status",
+			RESTGetDrawdownStatus,
+		},
+		Route{
+			"DrawdownBreakerReset",
+			http.MethodPost,
+			"/drawdown/reset",
+			RESTResetDrawdownBreaker,
+		},
+		Route{
+			"ErrorStormBreakerStatus",
+			http.MethodGet,
+			"/errorbreaker/status",
+			RESTGetErrorStormStatus,
+		},
+		Route{
+			"ErrorStormBreakerReset",
+			http.MethodPost,
+			"/errorbreaker/reset/{exchangeName}",
+			RESTResetErrorStormBreaker,
+		},
+		Route{
+			"PingOrderStatus",
+			http.MethodGet,
+			"/pingorders/status",
+			RESTGetPingOrderStatus,
+		},
+		Route{
+			"PingOrder",
+			http.MethodPost,
+			"/pingorders/{exchangeName}",
+			RESTPingOrder,
+		},
+		Route{
+			"DisabledPairs",
+			http.MethodGet,
+			"/killswitch",
+			RESTGetDisabledPairs,
+		},
+		Route{
+			"DisablePairTrading",
+			http.MethodPost,
+			"/killswitch/{currency}",
+			RESTDisablePairTrading,
+		},
+		Route{
+			"EnablePairTrading",
+			http.MethodDelete,
+			"/killswitch/{currency}",
+			RESTEnablePairTrading,
+		},
+		Route{
+			"BorrowRates",
+			http.MethodGet,
+			"/borrowrates/{currency}",
+			RESTGetBorrowRates,
+		},
+		Route{
+			"WarmupStatus",
+			http.MethodGet,
+			"/warmup/status",
+			RESTGetWarmupStatus,
+		},
+		Route{
+			"AddressBook",
+			http.MethodGet,
+			"/addressbook",
+			RESTGetAddressBook,
+		},
+		Route{
+			"AddressBookAdd",
+			http.MethodPost,
+			"/addressbook",
+			RESTAddAddressBookEntry,
+		},
+		Route{
+			"AddressBookUpdate",
+			http.MethodPut,
+			"/addressbook/{id}",
+			RESTUpdateAddressBookEntry,
+		},
+		Route{
+			"AddressBookRemove",
+			http.MethodDelete,
+			"/addressbook/{id}",
+			RESTRemoveAddressBookEntry,
+		},
+		Route{
+			"AddressBookProof",
+			http.MethodPost,
+			"/addressbook/{id}/proof",
+			RESTAddAddressBookProof,
+		},
+		Route{
+			"ChaosStats",
+			http.MethodGet,
+			"/chaos/stats",
+			RESTGetChaosStats,
+		},
+		Route{
+			"APIUsage",
+			http.MethodGet,
+			"/apiusage",
+			RESTGetAPIUsage,
+		},
+		Route{
+			"ExchangeAPIUsage",
+			http.MethodGet,
+			"/exchanges/{exchangeName}/apiusage",
+			RESTGetAPIUsage,
+		},
+		Route{
+			"StrategyAllocations",
+			http.MethodGet,
+			"/allocations",
+			RESTGetStrategyAllocations,
+		},
+		Route{
+			"AllocateStrategyCapital",
+			http.MethodPost,
+			"/allocations/{strategy}",
+			RESTAllocateStrategyCapital,
+		},
+		Route{
+			"BreakEvenPositions",
+			http.MethodGet,
+			"/breakeven",
+			RESTGetBreakEvenPositions,
+		},
+		Route{
+			"CollateralReport",
+			http.MethodGet,
+			"/collateral",
+			RESTGetCollateralReport,
+		},
+		Route{
+			"ExecuteCollateralMove",
+			http.MethodPost,
+			"/collateral/moves/{id}",
+			RESTExecuteCollateralMove,
+		},
+		Route{
+			"FundingForecast",
+			http.MethodGet,
+			"/funding/{exchange}/{instrument}",
+			RESTGetFundingForecast,
+		},
+		Route{
+			"CancelOrdersWhere",
+			http.MethodPost,
+			"/orders/cancel",
+			RESTCancelOrdersWhere,
+		},
+		Route{
+			"TrackedOrders",
+			http.MethodGet,
+			"/orders",
+			RESTGetOrders,
+		},
+		Route{
+			"OrderReconciliations",
+			http.MethodGet,
+			"/orders/reconciliations",
+			RESTGetOrderReconciliations,
+		},
+		Route{
+			"CancelAllTrackedOrders",
+			http.MethodPost,
+			"/orders/cancelall",
+			RESTCancelAllTrackedOrders,
+		},
+		Route{
+			"RunningStrategies",
+			http.MethodGet,
+			"/strategies",
+			RESTGetRunningStrategies,
+		},
+		Route{
+			"StrategyRequests",
+			http.MethodGet,
+			"/strategies/requests",
+			RESTGetRequestTagStats,
+		},
+		Route{
+			"StrategyFillQuality",
+			http.MethodGet,
+			"/strategies/quality",
+			RESTGetStrategyFillQuality,
+		},
+		Route{
+			"StrategySandboxes",
+			http.MethodGet,
+			"/strategies/sandbox",
+			RESTGetStrategySandboxes,
+		},
+		Route{
+			"ResumeStrategy",
+			http.MethodPost,
+			"/strategies/{strategy}/resume",
+			RESTResumeStrategy,
+		},
+		Route{
+			"StrategyNetting",
+			http.MethodGet,
+			"/strategies/netting",
+			RESTGetNettingBooks,
+		},
+		Route{
+			"StrategyNettingRemove",
+			http.MethodDelete,
+			"/strategies/netting/{strategy}",
+			RESTRemoveNettingStrategy,
+		},
+		Route{
+			"SpreadHistory",
+			http.MethodGet,
+			"/spreadhistory",
+			RESTGetSpreadHistoryStatus,
+		},
+		Route{
+			"SpreadHistoryStats",
+			http.MethodGet,
+			"/spreadhistory/{spread}",
+			RESTGetSpreadHistoryStats,
+		},
+		Route{
+			"SpreadHistorySamples",
+			http.MethodGet,
+			"/spreadhistory/{spread}/samples",
+			RESTGetSpreadHistorySamples,
+		},
+		Route{
+			"YieldPositions",
+			http.MethodGet,
+			"/yield",
+			RESTGetYieldPositions,
+		},
+		Route{
+			"AmendOrder",
+			http.MethodPost,
+			"/exchanges/{exchangeName}/orders/{orderID}/amend",
+			RESTAmendOrder,
+		},
+		Route{
+			"Deposits",
+			http.MethodGet,
+			"/deposits",
+			RESTGetDeposits,
+		},
+		Route{
+			"UntradeablePairs",
+			http.MethodGet,
+			"/exchanges/{exchangeName}/untradeable",
+			RESTGetUntradeablePairs,
+		},
+		Route{
+			"AssetStatus",
+			http.MethodGet,
+			"/exchanges/{exchangeName}/assets/{currency}/status",
+			RESTGetAssetStatus,
+		},
+		Route{
+			"ChainInfo",
+			http.MethodGet,
+			"/exchanges/{exchangeName}/assets/{currency}/chains",
+			RESTGetChainInfo,
+		},
+		Route{
+			"DepositAddress",
+			http.MethodGet,
+			"/exchanges/{exchangeName}/assets/{currency}/address",
+			RESTGetDepositAddress,
+		},
+		Route{
+			"ExchangeDeposits",
+			http.MethodGet,
+			"/exchanges/{exchangeName}/deposits",
+			RESTGetDeposits,
+		},
+		Route{
+			"Withdrawals",
+			http.MethodGet,
+			"/withdrawals",
+			RESTGetWithdrawals,
+		},
+		Route{
+			"Sweeps",
+			http.MethodGet,
+			"/sweeps",
+			RESTGetSweeps,
+		},
+		Route{
+			"FeeTiers",
+			http.MethodGet,
+			"/feetiers",
+			RESTGetFeeTiers,
+		},
+		Route{
+			"ExchangeWithdrawals",
+			http.MethodGet,
+			"/exchanges/{exchangeName}/withdrawals",
+			RESTGetWithdrawals,
+		},
+		Route{
+			"AnomalyWatcherStatus",
+			http.MethodGet,
+			"/anomalies",
+			RESTGetAnomalyStatus,
+		},
+		Route{
+			"AnomalyLockdownReset",
+			http.MethodPost,
+			"/anomalies/reset",
+			RESTResetAnomalyLockdown,
+		},
+		Route{
+			"StateReport",
+			http.MethodGet,
+			"/report",
+			RESTGetStateReport,
+		},
+		Route{
+			"CompositeIndices",
+			http.MethodGet,
+			"/indices",
+			RESTGetCompositeIndices,
+		},
+		Route{
+			"CompositeIndexHistory",
+			http.MethodGet,
+			"/indices/{symbol}",
+			RESTGetCompositeIndexHistory,
+		},
+		Route{
+			"BracketOrders",
+			http.MethodGet,
+			"/brackets",
+			RESTGetBracketOrders,
+		},
+		Route{
+			"BracketOrderSubmit",
+			http.MethodPost,
+			"/brackets",
+			RESTSubmitBracketOrder,
+		},
+		Route{
+			"BracketOrderCancel",
+			http.MethodDelete,
+			"/brackets/{id}",
+			RESTCancelBracketOrder,
+		},
+		Route{
+			"ImbalanceOrders",
+			http.MethodGet,
+			"/imbalance",
+			RESTGetImbalanceOrders,
+		},
+		Route{
+			"ImbalanceOrderSubmit",
+			http.MethodPost,
+			"/imbalance",
+			RESTSubmitImbalanceOrder,
+		},
+		Route{
+			"ImbalanceOrderCancel",
+			http.MethodDelete,
+			"/imbalance/{id}",
+			RESTCancelImbalanceOrder,
+		},
+		Route{
+			"SpreadOrders",
+			http.MethodGet,
+			"/spreads",
+			RESTGetSpreadOrders,
+		},
+		Route{
+			"SpreadOrderSubmit",
+			http.MethodPost,
+			"/spreads",
+			RESTSubmitSpreadOrder,
+		},
+		Route{
+			"SpreadOrderCancel",
+			http.MethodDelete,
+			"/spreads/{id}",
+			RESTCancelSpreadOrder,
+		},
+		Route{
+			"FundingGuard",
+			http.MethodGet,
+			"/fundingguard",
+			RESTGetFundingGuard,
+		},
+		Route{
+			"PairStats",
+			http.MethodGet,
+			"/stats/pairs",
+			RESTGetPairStats,
+		},
+		Route{
+			"ExchangePairStats",
+			http.MethodGet,
+			"/stats/pairs/{exchangeName}",
+			RESTGetPairStats,
+		},
+		Route{
+			"TradeHistory",
+			http.MethodGet,
+			"/tradehistory",
+			RESTGetTradeHistory,
+		},
+		Route{
+			"PriceBand",
+			http.MethodGet,
+			"/priceband",
+			RESTGetPriceBand,
+		},
+		Route{
+			"Rollup",
+			http.MethodGet,
+			"/rollup",
+			RESTGetRollup,
+		},
+		Route{
+			"RollupGrouping",
+			http.MethodGet,
+			"/rollup/{grouping}",
+			RESTGetRollupGrouping,
+		},
+		Route{
+			"InventorySkew",
+			http.MethodGet,
+			"/inventory/{currency}/skew",
+			RESTGetInventorySkew,
+		},
+		Route{
+			"OrderbookFeedStats",
+			http.MethodGet,
+			"/orderbook/feed",
+			RESTGetOrderbookFeedStats,
+		},
+		Route{
+			"AccountingExport",
+			http.MethodGet,
+			"/accounting",
+			RESTExportAccounting,
+		},
+		Route{
+			"Candles",
+			http.MethodGet,
+			"/candles/{exchangeName}/{currency}",
+			RESTGetCandles,
+		},
+		Route{
+			"Volatility",
+			http.MethodGet,
+			"/volatility",
+			RESTGetAllVolatility,
+		},
+		Route{
+			"PairVolatility",
+			http.MethodGet,
+			"/volatility/{exchangeName}/{currency}",
+			RESTGetVolatility,
+		},
+		Route{
+			"VolumeProfiles",
+			http.MethodGet,
+			"/volumeprofile",
+			RESTGetAllVolumeProfiles,
+		},
+		Route{
+			"PairVolumeProfile",
+			http.MethodGet,
+			"/volumeprofile/{exchangeName}/{currency}",
+			RESTGetVolumeProfile,
+		},
+		Route{
+			"PollingSchedules",
+			http.MethodGet,
+			"/polling",
+			RESTGetPollingSchedules,
+		},
+		Route{
+			"Compliance",
+			http.MethodGet,
+			"/compliance",
+			RESTGetCompliance,
+		},
+		Route{
+			"PnLAttribution",
+			http.MethodGet,
+			"/pnl/attribution",
+			RESTGetPnLAttribution,
+		},
 		Route{
 			"ws",
 			http.MethodGet,
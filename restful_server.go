@@ -3,11 +3,21 @@ package main
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/gorilla/mux"
+	"github.com/thrasher-corp/gocryptotrader/accounting"
+	"github.com/thrasher-corp/gocryptotrader/addressbook"
 	"github.com/thrasher-corp/gocryptotrader/config"
+	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ordermanager"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/spread"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/tca"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 	log "github.com/thrasher-corp/gocryptotrader/logger"
 )
@@ -24,6 +34,104 @@ type EnabledExchangeOrderbooks struct {
 	ExchangeValues []orderbook.Base `json:"exchangeValues"`
 }
 
+// ConvertRequest holds the parameters of an asset conversion request, the
+// conversion is only quoted unless Execute is set
+type ConvertRequest struct {
+	From    string  `json:"from"`
+	To      string  `json:"to"`
+	Amount  float64 `json:"amount"`
+	Execute bool    `json:"execute"`
+}
+
+// AddressBookProofRequest holds an ownership proof to record against an
+// address book entry
+type AddressBookProofRequest struct {
+	Method    string `json:"method"`
+	Reference string `json:"reference"`
+	Note      string `json:"note"`
+}
+
+// AllocationRequest holds an amount of an exchange balance to allocate to a
+// strategy, a negative amount returns funds from the strategy
+type AllocationRequest struct {
+	Exchange string  `json:"exchange"`
+	Currency string  `json:"currency"`
+	Amount   float64 `json:"amount"`
+}
+
+// CancelOrdersRequest holds the criteria open orders are cancelled by, an
+// empty exchange cancels matching orders on every exchange. Ages are
+// durations such as 30m
+type CancelOrdersRequest struct {
+	Exchange  string   `json:"exchange"`
+	Pairs     []string `json:"pairs"`
+	Side      string   `json:"side"`
+	OrderType string   `json:"orderType"`
+	MinAge    string   `json:"minAge"`
+	MaxAge    string   `json:"maxAge"`
+	MinPrice  float64  `json:"minPrice"`
+	MaxPrice  float64  `json:"maxPrice"`
+}
+
+// TrackedOrdersRequest holds the criteria tracked orders are cancelled by,
+// empty criteria match every open order
+type TrackedOrdersRequest struct {
+	Exchange string `json:"exchange"`
+	Pair     string `json:"pair"`
+	Side     string `json:"side"`
+}
+
+// AmendOrderRequest holds the new price and amount of an order, a zero value
+// leaves it unchanged
+type AmendOrderRequest struct {
+	Price  float64 `json:"price"`
+	Amount float64 `json:"amount"`
+}
+
+// BracketOrderRequest holds an entry order and its exits, a zero entry price
+// enters at market and a zero take profit or stop loss leaves it unset
+type BracketOrderRequest struct {
+	Exchange   string  `json:"exchange"`
+	Pair       string  `json:"pair"`
+	Side       string  `json:"side"`
+	Amount     float64 `json:"amount"`
+	EntryPrice float64 `json:"entryPrice"`
+	TakeProfit float64 `json:"takeProfit"`
+	StopLoss   float64 `json:"stopLoss"`
+}
+
+// ImbalanceOrderRequest holds a limit order triggered by the order book
+// imbalance, a zero price joins the best bid or ask when placed
+type ImbalanceOrderRequest struct {
+	Exchange  string  `json:"exchange"`
+	Pair      string  `json:"pair"`
+	AssetType string  `json:"assetType"`
+	Side      string  `json:"side"`
+	Amount    float64 `json:"amount"`
+	Price     float64 `json:"price"`
+	Trigger   string  `json:"trigger"`
+	Threshold float64 `json:"threshold"`
+	Levels    int     `json:"levels"`
+}
+
+// SpreadOrderRequest holds a two leg spread order, the lean leg is traded by
+// side at the hedge leg's price plus the spread. Zero limits are defaulted
+type SpreadOrderRequest struct {
+	LeanExchange     string  `json:"leanExchange"`
+	LeanPair         string  `json:"leanPair"`
+	LeanAsset        string  `json:"leanAsset"`
+	HedgeExchange    string  `json:"hedgeExchange"`
+	HedgePair        string  `json:"hedgePair"`
+	HedgeAsset       string  `json:"hedgeAsset"`
+	Side             string  `json:"side"`
+	Amount           float64 `json:"amount"`
+	Ratio            float64 `json:"ratio"`
+	Spread           float64 `json:"spread"`
+	MaxImbalance     float64 `json:"maxImbalance"`
+	ImbalanceTimeout string  `json:"imbalanceTimeout"`
+	RequoteBps       float64 `json:"requoteBps"`
+}
+
 // AllEnabledExchangeCurrencies holds the enabled exchange currencies
 type AllEnabledExchangeCurrencies struct {
 	Data []EnabledExchangeCurrencies `json:"data"`
@@ -113,6 +221,40 @@ func RESTGetOrderbook(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// RESTGetOrderbookDepth returns the best levels of an exchange's orderbook for
+// a pair, or of every exchange's orderbook consolidated by price when no
+// exchange is named. The levels per side and asset type are set by the depth
+// and asset query parameters
+func RESTGetOrderbookDepth(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	q := r.URL.Query()
+	levels := orderbook.DefaultDepthLevels
+	if d := q.Get("depth"); d != "" {
+		var err error
+		levels, err = strconv.Atoi(d)
+		if err != nil {
+			RESTfulError(r.Method, err)
+			return
+		}
+	}
+	assetType := q.Get("asset")
+	if assetType == "" {
+		assetType = orderbook.Spot
+	}
+
+	resp, err := GetOrderbookDepth(vars["exchangeName"],
+		currency.NewPairFromString(vars["currency"]), assetType, levels)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
 // GetAllActiveOrderbooks returns all enabled exchanges orderbooks
 func GetAllActiveOrderbooks() []EnabledExchangeOrderbooks {
 	var orderbookData []EnabledExchangeOrderbooks
@@ -184,6 +326,45 @@ func RESTGetPortfolio(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
+// RESTGetPortfolioValuation returns the portfolio value including cold storage
+// holdings
+func RESTGetPortfolioValuation(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, GetPortfolioValuation())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetPortfolioSnapshot returns the balances of every exchange aggregated
+// by currency with when each exchange's balances were fetched
+func RESTGetPortfolioSnapshot(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetPortfolioSnapshot()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetPortfolioRebalance returns the trades moving the portfolio to the
+// target weights read from the query, e.g. ?weights=BTC:40,USD:60
+func RESTGetPortfolioRebalance(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetPortfolioRebalance(r.URL.Query().Get("weights"))
+	if err != nil {
+		log.Errorf("Failed to calculate portfolio rebalance: %s", err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
 // RESTGetTicker returns ticker info for a given currency, exchange and
 // asset type
 func RESTGetTicker(w http.ResponseWriter, r *http.Request) {
@@ -297,3 +478,1597 @@ func RESTGetAllEnabledAccountInfo(w http.ResponseWriter, r *http.Request) {
 		RESTfulError(r.Method, err)
 	}
 }
+
+// RESTGetVenueReports returns transaction cost analysis reports per venue for
+// a given currency pair and the assetType query parameter, spot by default
+func RESTGetVenueReports(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	assetType := r.URL.Query().Get("assetType")
+	if assetType == "" {
+		assetType = ticker.Spot
+	}
+
+	if len(vars["currency"]) < 6 {
+		log.Errorf("Failed to fetch transaction cost reports, invalid currency pair: %s\n",
+			vars["currency"])
+		return
+	}
+
+	p := currency.NewPairFromString(vars["currency"])
+	err := RESTfulJSONResponse(w, tca.GetVenueReports(p, assetType))
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetVenueRoutes ranks the venues quoting a currency pair for an order on
+// the side query parameter after transaction costs. The assetType query
+// parameter defaults to spot
+func RESTGetVenueRoutes(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	assetType := q.Get("assetType")
+	if assetType == "" {
+		assetType = ticker.Spot
+	}
+
+	p := currency.NewPairFromString(mux.Vars(r)["currency"])
+	routes, err := GetVenueRoutes(p, assetType, exchange.OrderSide(strings.ToUpper(q.Get("side"))))
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, routes)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTConvertAsset quotes or executes the best conversion route between two
+// currencies on a given exchange
+func RESTConvertAsset(w http.ResponseWriter, r *http.Request) {
+	exchangeName := mux.Vars(r)["exchangeName"]
+
+	var request ConvertRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	result, err := ConvertAsset(exchangeName,
+		request.From,
+		request.To,
+		request.Amount,
+		request.Execute)
+	if err != nil {
+		log.Errorf("Failed to convert %f %s to %s on %s: %s\n",
+			request.Amount,
+			request.From,
+			request.To,
+			exchangeName,
+			err)
+		// Report partially executed routes so the filled legs are visible
+		if len(result.Route.Legs) == 0 {
+			return
+		}
+	}
+
+	err = RESTfulJSONResponse(w, result)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetDrawdownStatus returns the drawdown circuit breaker status
+func RESTGetDrawdownStatus(w http.ResponseWriter, r *http.Request) {
+	if bot.drawdownBreaker == nil {
+		RESTfulError(r.Method, errDrawdownBreakerDisabled)
+		return
+	}
+
+	err := RESTfulJSONResponse(w, bot.drawdownBreaker.GetStatus())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTResetDrawdownBreaker unlocks trading after the drawdown circuit breaker
+// has tripped
+func RESTResetDrawdownBreaker(w http.ResponseWriter, r *http.Request) {
+	err := ResetDrawdownBreaker()
+	if err != nil {
+		log.Errorf("Failed to reset drawdown circuit breaker: %s", err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, bot.drawdownBreaker.GetStatus())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetErrorStormStatus returns the error storm breaker status of each
+// exchange
+func RESTGetErrorStormStatus(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetErrorStormStatus()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTResetErrorStormBreaker resumes order flow to an exchange paused by the
+// error storm breaker
+func RESTResetErrorStormBreaker(w http.ResponseWriter, r *http.Request) {
+	exchName := mux.Vars(r)["exchangeName"]
+	err := ResetErrorStormBreaker(exchName)
+	if err != nil {
+		log.Errorf("Failed to reset %s error storm breaker: %s", exchName, err)
+		return
+	}
+
+	resp, err := GetErrorStormStatus()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetPingOrderStatus returns the ping order health of each configured
+// exchange
+func RESTGetPingOrderStatus(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetPingOrderStatus()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTPingOrder places and cancels a ping order on an exchange immediately
+func RESTPingOrder(w http.ResponseWriter, r *http.Request) {
+	exchName := mux.Vars(r)["exchangeName"]
+	resp, err := PingOrder(exchName)
+	if err != nil {
+		log.Errorf("Failed to place %s ping order: %s", exchName, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetDisabledPairs returns the pairs whose trading is disabled by the
+// kill switch
+func RESTGetDisabledPairs(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetDisabledPairs()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTDisablePairTrading disables trading of a pair across all strategies and
+// exchanges, cancelling its resting orders. The reason is read from the query
+func RESTDisablePairTrading(w http.ResponseWriter, r *http.Request) {
+	pair := mux.Vars(r)["currency"]
+	if len(pair) < 6 {
+		log.Errorf("Failed to disable pair trading, invalid currency pair: %s", pair)
+		return
+	}
+	p := currency.NewPairFromString(pair)
+	resp, err := DisablePairTrading(p, r.URL.Query().Get("reason"))
+	if err != nil {
+		log.Errorf("Failed to disable %s trading: %s", p, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTEnablePairTrading enables trading of a pair disabled by the kill switch
+func RESTEnablePairTrading(w http.ResponseWriter, r *http.Request) {
+	pair := mux.Vars(r)["currency"]
+	if len(pair) < 6 {
+		log.Errorf("Failed to enable pair trading, invalid currency pair: %s", pair)
+		return
+	}
+	p := currency.NewPairFromString(pair)
+	err := EnablePairTrading(p)
+	if err != nil {
+		log.Errorf("Failed to enable %s trading: %s", p, err)
+		return
+	}
+
+	resp, err := GetDisabledPairs()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetBorrowRates returns the borrow rates for a currency across all
+// loaded exchanges ordered from cheapest to most expensive
+func RESTGetBorrowRates(w http.ResponseWriter, r *http.Request) {
+	c := mux.Vars(r)["currency"]
+	rates, err := GetBorrowRates(c)
+	if err != nil {
+		log.Errorf("Failed to fetch borrow rates for %s: %s\n", c, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, rates)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetWarmupStatus returns the status of the market data warmup
+func RESTGetWarmupStatus(w http.ResponseWriter, r *http.Request) {
+	status, err := GetWarmupStatus()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, status)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetAddressBook returns every withdrawal destination in the address book
+func RESTGetAddressBook(w http.ResponseWriter, r *http.Request) {
+	b, err := GetAddressBook()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, b.List())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTAddAddressBookEntry adds a withdrawal destination to the address book
+func RESTAddAddressBookEntry(w http.ResponseWriter, r *http.Request) {
+	b, err := GetAddressBook()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	var entry addressbook.Entry
+	err = json.NewDecoder(r.Body).Decode(&entry)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	entry, err = b.Add(entry)
+	if err != nil {
+		log.Errorf("Failed to add address book entry %s: %s\n", entry.Label, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, entry)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTUpdateAddressBookEntry replaces the details of an address book entry
+func RESTUpdateAddressBookEntry(w http.ResponseWriter, r *http.Request) {
+	b, err := GetAddressBook()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	var entry addressbook.Entry
+	err = json.NewDecoder(r.Body).Decode(&entry)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+	entry.ID = mux.Vars(r)["id"]
+
+	err = b.Update(entry)
+	if err != nil {
+		log.Errorf("Failed to update address book entry %s: %s\n", entry.ID, err)
+		return
+	}
+
+	entry, err = b.Get(entry.ID)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, entry)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTRemoveAddressBookEntry removes a withdrawal destination from the address
+// book
+func RESTRemoveAddressBookEntry(w http.ResponseWriter, r *http.Request) {
+	b, err := GetAddressBook()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	err = b.Remove(id)
+	if err != nil {
+		log.Errorf("Failed to remove address book entry %s: %s\n", id, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, b.List())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTAddAddressBookProof records an ownership proof against an address book
+// entry
+func RESTAddAddressBookProof(w http.ResponseWriter, r *http.Request) {
+	b, err := GetAddressBook()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	var request AddressBookProofRequest
+	err = json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	id := mux.Vars(r)["id"]
+	err = b.AddProof(id, addressbook.Proof{
+		Method:    request.Method,
+		Reference: request.Reference,
+		Note:      request.Note,
+	})
+	if err != nil {
+		log.Errorf("Failed to add ownership proof to address book entry %s: %s\n", id, err)
+		return
+	}
+
+	entry, err := b.Get(id)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, entry)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetChaosStats returns the faults injected by chaos testing mode
+func RESTGetChaosStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := GetChaosStats()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, stats)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetAPIUsage returns the API usage of every enabled exchange, or of the
+// exchange named in the path, projected against their rate limits
+func RESTGetAPIUsage(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetAPIUsage(mux.Vars(r)["exchangeName"])
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetStrategyAllocations returns every strategy's allocated balances and
+// PnL
+func RESTGetStrategyAllocations(w http.ResponseWriter, r *http.Request) {
+	reports, err := GetStrategyAllocations()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, reports)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTAllocateStrategyCapital allocates an exchange balance to a strategy
+func RESTAllocateStrategyCapital(w http.ResponseWriter, r *http.Request) {
+	var request AllocationRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	strategy := mux.Vars(r)["strategy"]
+	err = AllocateStrategyCapital(strategy, request.Exchange,
+		currency.NewCode(request.Currency), request.Amount)
+	if err != nil {
+		log.Errorf("Failed to allocate capital to strategy %s: %s\n", strategy, err)
+		return
+	}
+
+	reports, err := GetStrategyAllocations()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, reports)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetBreakEvenPositions returns tracked positions and their break-even
+// exit prices
+func RESTGetBreakEvenPositions(w http.ResponseWriter, r *http.Request) {
+	positions, err := GetBreakEvenPositions()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, positions)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetCollateralReport returns the margin utilization of each collateral
+// venue and the recommended collateral moves
+func RESTGetCollateralReport(w http.ResponseWriter, r *http.Request) {
+	report, err := GetCollateralReport()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, report)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTExecuteCollateralMove executes a recommended collateral move
+func RESTExecuteCollateralMove(w http.ResponseWriter, r *http.Request) {
+	id, err := strconv.ParseInt(mux.Vars(r)["id"], 10, 64)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = ExecuteCollateralMove(id)
+	if err != nil {
+		log.Errorf("Failed to execute collateral move %d: %s\n", id, err)
+		return
+	}
+
+	report, err := GetCollateralReport()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, report)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetFundingForecast returns the forecast funding rates of a perpetual
+// swap, the number of periods is set by the periods query parameter
+func RESTGetFundingForecast(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	var periods int
+	if p := r.URL.Query().Get("periods"); p != "" {
+		var err error
+		periods, err = strconv.Atoi(p)
+		if err != nil {
+			RESTfulError(r.Method, err)
+			return
+		}
+	}
+
+	forecast, err := GetFundingForecast(vars["exchange"], vars["instrument"], periods)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, forecast)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTCancelOrdersWhere cancels all open orders matching the request criteria
+func RESTCancelOrdersWhere(w http.ResponseWriter, r *http.Request) {
+	var request CancelOrdersRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	filter := exchange.OrderFilter{
+		Side:      exchange.OrderSide(strings.ToUpper(request.Side)),
+		OrderType: exchange.OrderType(strings.ToUpper(request.OrderType)),
+		MinPrice:  request.MinPrice,
+		MaxPrice:  request.MaxPrice,
+	}
+	for i := range request.Pairs {
+		filter.Pairs = append(filter.Pairs, currency.NewPairFromString(request.Pairs[i]))
+	}
+	for _, age := range []struct {
+		s string
+		d *time.Duration
+	}{{request.MinAge, &filter.MinAge}, {request.MaxAge, &filter.MaxAge}} {
+		if age.s == "" {
+			continue
+		}
+		*age.d, err = time.ParseDuration(age.s)
+		if err != nil {
+			RESTfulError(r.Method, err)
+			return
+		}
+	}
+
+	results, err := CancelOrdersWhere(request.Exchange, &filter)
+	if err != nil {
+		log.Errorf("Failed to cancel orders by filter: %s\n", err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, results)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetOrders returns the tracked orders matching the exchange, pair, side,
+// status and open query parameters
+func RESTGetOrders(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	open, _ := strconv.ParseBool(q.Get("open"))
+	f := ordermanager.Filter{
+		Exchange: q.Get("exchange"),
+		Side:     exchange.OrderSide(strings.ToUpper(q.Get("side"))),
+		Status:   exchange.OrderStatus(strings.ToUpper(q.Get("status"))),
+		OpenOnly: open,
+	}
+	if p := q.Get("pair"); p != "" {
+		f.Pair = currency.NewPairFromString(p)
+	}
+
+	resp, err := GetOrders(&f)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetOrderReconciliations returns the latest reconciliation of each
+// exchange the order manager tracks
+func RESTGetOrderReconciliations(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetOrderReconciliations()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTCancelAllTrackedOrders cancels every open tracked order matching the
+// request criteria
+func RESTCancelAllTrackedOrders(w http.ResponseWriter, r *http.Request) {
+	var request TrackedOrdersRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	f := ordermanager.Filter{
+		Exchange: request.Exchange,
+		Side:     exchange.OrderSide(strings.ToUpper(request.Side)),
+	}
+	if request.Pair != "" {
+		f.Pair = currency.NewPairFromString(request.Pair)
+	}
+
+	results, err := CancelAllTrackedOrders(&f)
+	if err != nil {
+		log.Errorf("Failed to cancel tracked orders: %s\n", err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, results)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetRunningStrategies returns the declarations of running strategies
+func RESTGetRunningStrategies(w http.ResponseWriter, r *http.Request) {
+	defs, err := GetRunningStrategies()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, defs)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetRequestTagStats returns the requests sent under each strategy's
+// request tag per exchange
+func RESTGetRequestTagStats(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, GetRequestTagStats())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetStrategyFillQuality returns the maker/taker ratio, spread capture
+// and adverse selection of each strategy's fills
+func RESTGetStrategyFillQuality(w http.ResponseWriter, r *http.Request) {
+	err := RESTfulJSONResponse(w, GetStrategyFillQuality())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetStrategySandboxes returns whether each running strategy is
+// suspended along with its sandbox resource accounting
+func RESTGetStrategySandboxes(w http.ResponseWriter, r *http.Request) {
+	stats, err := GetStrategySandboxes()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, stats)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTResumeStrategy restarts a strategy suspended by its sandbox
+func RESTResumeStrategy(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["strategy"]
+	err := ResumeStrategy(name)
+	if err != nil {
+		log.Errorf("Failed to resume strategy %s: %s", name, err)
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	stats, err := GetStrategySandboxes()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+	err = RESTfulJSONResponse(w, stats[name])
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetNettingBooks returns the strategy targets and traded position of
+// every pair netted
+func RESTGetNettingBooks(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetNettingBooks()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTRemoveNettingStrategy removes the targets of a strategy no longer run,
+// such as one removed while the bot was stopped
+func RESTRemoveNettingStrategy(w http.ResponseWriter, r *http.Request) {
+	name := mux.Vars(r)["strategy"]
+	err := RemoveNettingStrategy(name)
+	if err != nil {
+		log.Errorf("Failed to remove strategy %s targets: %s", name, err)
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	resp, err := GetNettingBooks()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetSpreadHistoryStatus returns the recording status of every configured
+// spread
+func RESTGetSpreadHistoryStatus(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetSpreadHistoryStatus()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// spreadHistoryWindow parses the window query parameter, zero covering every
+// retained sample
+func spreadHistoryWindow(r *http.Request) (time.Duration, error) {
+	w := r.URL.Query().Get("window")
+	if w == "" {
+		return 0, nil
+	}
+	return time.ParseDuration(w)
+}
+
+// RESTGetSpreadHistoryStats returns a spread's percentile statistics over the
+// window query parameter. The percentiles reported are set by the comma
+// separated percentiles query parameter, e.g. percentiles=5,50,95
+func RESTGetSpreadHistoryStats(w http.ResponseWriter, r *http.Request) {
+	window, err := spreadHistoryWindow(r)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+	var percentiles []float64
+	if p := r.URL.Query().Get("percentiles"); p != "" {
+		for _, v := range strings.Split(p, ",") {
+			f, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+			if err != nil {
+				RESTfulError(r.Method, err)
+				return
+			}
+			percentiles = append(percentiles, f)
+		}
+	}
+
+	resp, err := GetSpreadHistoryStats(mux.Vars(r)["spread"], window, percentiles)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetSpreadHistorySamples returns a spread's samples over the window query
+// parameter
+func RESTGetSpreadHistorySamples(w http.ResponseWriter, r *http.Request) {
+	window, err := spreadHistoryWindow(r)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	resp, err := GetSpreadHistorySamples(mux.Vars(r)["spread"], window)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetYieldPositions returns the balances deployed to yield sources
+func RESTGetYieldPositions(w http.ResponseWriter, r *http.Request) {
+	positions, err := GetYieldPositions()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, positions)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTAmendOrder changes the price and amount of an open order
+func RESTAmendOrder(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	exchangeName := vars["exchangeName"]
+	orderID := vars["orderID"]
+
+	var request AmendOrderRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	result, err := AmendOrder(exchangeName, orderID, request.Price, request.Amount)
+	if err != nil {
+		log.Errorf("Failed to amend %s order %s: %s\n", exchangeName, orderID, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, result)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetDeposits returns tracked deposits and their confirmations, credited
+// deposits are included with the all query parameter set
+func RESTGetDeposits(w http.ResponseWriter, r *http.Request) {
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	resp, err := GetDeposits(mux.Vars(r)["exchangeName"], all)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetWithdrawals returns tracked withdrawals and their confirmations,
+// confirmed and failed withdrawals are included with the all query parameter
+// set
+func RESTGetWithdrawals(w http.ResponseWriter, r *http.Request) {
+	all, _ := strconv.ParseBool(r.URL.Query().Get("all"))
+	resp, err := GetWithdrawals(mux.Vars(r)["exchangeName"], all)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetFeeTiers returns each exchange's fee tier and the tier changes seen
+func RESTGetFeeTiers(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetFeeTiers()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetSweeps returns each cold storage sweep's plan within the withdrawal
+// limits and the sweeps made
+func RESTGetSweeps(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetSweeps()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetAnomalyStatus returns the account anomaly watcher's lockdown state
+// and recent anomalies
+func RESTGetAnomalyStatus(w http.ResponseWriter, r *http.Request) {
+	if bot.anomalyWatcher == nil {
+		RESTfulError(r.Method, errAnomalyWatcherDisabled)
+		return
+	}
+
+	err := RESTfulJSONResponse(w, bot.anomalyWatcher.GetStatus())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTResetAnomalyLockdown lifts the lockdown applied by the account anomaly
+// watcher
+func RESTResetAnomalyLockdown(w http.ResponseWriter, r *http.Request) {
+	err := ResetAnomalyLockdown()
+	if err != nil {
+		log.Errorf("Failed to reset anomaly lockdown: %s", err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, bot.anomalyWatcher.GetStatus())
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetStateReport returns a signed report of open orders, positions and
+// balances across venues
+func RESTGetStateReport(w http.ResponseWriter, r *http.Request) {
+	report, err := GetStateReport()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, report)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetCompositeIndices returns the latest published and locally computed
+// value of each tracked composite index
+func RESTGetCompositeIndices(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetCompositeIndices()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetCompositeIndexHistory returns the recorded constituents, weights and
+// values of a composite index
+func RESTGetCompositeIndexHistory(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetCompositeIndexHistory(mux.Vars(r)["symbol"])
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetUntradeablePairs returns the markets an exchange has frozen, disabled
+// or delisted
+func RESTGetUntradeablePairs(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetUntradeablePairs(mux.Vars(r)["exchangeName"])
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetAssetStatus returns whether deposits and withdrawals of a currency
+// are enabled on an exchange
+func RESTGetAssetStatus(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	resp, err := AssetStatus(vars["exchangeName"], currency.NewCode(vars["currency"]))
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetChainInfo returns the transfer rules of each of a currency's chains
+// on an exchange
+func RESTGetChainInfo(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	resp, err := GetChainInfo(vars["exchangeName"], currency.NewCode(vars["currency"]))
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetDepositAddress returns the deposit address of a currency on the
+// chain query parameter, or the exchange's default chain when omitted
+func RESTGetDepositAddress(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	resp, err := GetDepositAddress(vars["exchangeName"], currency.NewCode(vars["currency"]),
+		r.URL.Query().Get("chain"))
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetBracketOrders returns the bracket orders placed through the bot
+func RESTGetBracketOrders(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetBracketOrders()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTSubmitBracketOrder places a bracket order
+func RESTSubmitBracketOrder(w http.ResponseWriter, r *http.Request) {
+	var request BracketOrderRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	resp, err := SubmitBracketOrder(request.Exchange, &exchange.BracketOrder{
+		Pair:       currency.NewPairFromString(request.Pair),
+		Side:       exchange.OrderSide(strings.ToUpper(request.Side)),
+		Amount:     request.Amount,
+		EntryPrice: request.EntryPrice,
+		TakeProfit: request.TakeProfit,
+		StopLoss:   request.StopLoss,
+	})
+	if err != nil {
+		log.Errorf("Failed to submit %s bracket order: %s\n", request.Exchange, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTCancelBracketOrder cancels the open orders of a bracket
+func RESTCancelBracketOrder(w http.ResponseWriter, r *http.Request) {
+	resp, err := CancelBracketOrder(mux.Vars(r)["id"])
+	if err != nil {
+		log.Errorf("Failed to cancel bracket order %s: %s\n", mux.Vars(r)["id"], err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetImbalanceOrders returns the imbalance orders submitted through the
+// bot
+func RESTGetImbalanceOrders(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetImbalanceOrders()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTSubmitImbalanceOrder submits an imbalance order
+func RESTSubmitImbalanceOrder(w http.ResponseWriter, r *http.Request) {
+	var request ImbalanceOrderRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	resp, err := SubmitImbalanceOrder(request.Exchange, &exchange.ImbalanceOrder{
+		Pair:      currency.NewPairFromString(request.Pair),
+		AssetType: request.AssetType,
+		Side:      exchange.OrderSide(strings.ToUpper(request.Side)),
+		Amount:    request.Amount,
+		Price:     request.Price,
+		Trigger:   exchange.ImbalanceTrigger(strings.ToUpper(request.Trigger)),
+		Threshold: request.Threshold,
+		Levels:    request.Levels,
+	})
+	if err != nil {
+		log.Errorf("Failed to submit %s imbalance order: %s\n", request.Exchange, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTCancelImbalanceOrder cancels an open imbalance order
+func RESTCancelImbalanceOrder(w http.ResponseWriter, r *http.Request) {
+	resp, err := CancelImbalanceOrder(mux.Vars(r)["id"])
+	if err != nil {
+		log.Errorf("Failed to cancel imbalance order %s: %s\n", mux.Vars(r)["id"], err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetSpreadOrders returns the spread orders worked by the bot
+func RESTGetSpreadOrders(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetSpreadOrders()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTSubmitSpreadOrder starts working a spread order
+func RESTSubmitSpreadOrder(w http.ResponseWriter, r *http.Request) {
+	var request SpreadOrderRequest
+	err := json.NewDecoder(r.Body).Decode(&request)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	var timeout time.Duration
+	if request.ImbalanceTimeout != "" {
+		timeout, err = time.ParseDuration(request.ImbalanceTimeout)
+		if err != nil {
+			RESTfulError(r.Method, err)
+			return
+		}
+	}
+
+	resp, err := SubmitSpreadOrder(&spread.Order{
+		Lean: spread.Leg{
+			Exchange:  request.LeanExchange,
+			Pair:      currency.NewPairFromString(request.LeanPair),
+			AssetType: request.LeanAsset,
+		},
+		Hedge: spread.Leg{
+			Exchange:  request.HedgeExchange,
+			Pair:      currency.NewPairFromString(request.HedgePair),
+			AssetType: request.HedgeAsset,
+		},
+		Side:             exchange.OrderSide(strings.ToUpper(request.Side)),
+		Amount:           request.Amount,
+		Ratio:            request.Ratio,
+		Spread:           request.Spread,
+		MaxImbalance:     request.MaxImbalance,
+		ImbalanceTimeout: timeout,
+		RequoteBps:       request.RequoteBps,
+	})
+	if err != nil {
+		log.Errorf("Failed to submit %s/%s spread order: %s\n", request.LeanExchange, request.HedgeExchange, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTCancelSpreadOrder withdraws a spread's lean order
+func RESTCancelSpreadOrder(w http.ResponseWriter, r *http.Request) {
+	resp, err := CancelSpreadOrder(mux.Vars(r)["id"])
+	if err != nil {
+		log.Errorf("Failed to cancel spread order %s: %s\n", mux.Vars(r)["id"], err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetFundingGuard returns the funding guard's latest decision for each
+// position and the positions it has closed or hedged over funding
+func RESTGetFundingGuard(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetFundingGuardStatus()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetPairStats returns the day's trading activity of every pair traded on
+// the named exchange, or on every exchange when none is named
+func RESTGetPairStats(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetPairStats(mux.Vars(r)["exchangeName"])
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetTradeHistory returns each exchange's latest comparison of recorded
+// fills with its order history and the irreconcilable differences found
+func RESTGetTradeHistory(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetTradeHistoryReport()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetPriceBand returns each evaluated pair's last price against the
+// multi-venue index and whether its orders are paused
+func RESTGetPriceBand(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetPriceBandStatus()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetRollup returns the balances of every group in every roll-up grouping
+func RESTGetRollup(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetRollupReports()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetRollupGrouping returns the balances of every group in a roll-up
+// grouping
+func RESTGetRollupGrouping(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetRollupReport(mux.Vars(r)["grouping"])
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetInventorySkew returns the skew of each venue's quotes of a currency
+func RESTGetInventorySkew(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetInventorySkews(currency.NewCode(mux.Vars(r)["currency"]))
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetOrderbookFeedStats returns the delivery statistics of every
+// orderbook feed subscriber
+func RESTGetOrderbookFeedStats(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetOrderbookFeedStats()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTExportAccounting returns wallet history in the beancount or ledger
+// format. The exchange, format and account name templates are read from the
+// query
+func RESTExportAccounting(w http.ResponseWriter, r *http.Request) {
+	q := r.URL.Query()
+	format := accounting.Format(q.Get("format"))
+	if format == "" {
+		format = accounting.Beancount
+	}
+	resp, err := ExportAccounting(q.Get("exchange"), format, accounting.Accounts{
+		Asset:    q.Get("asset"),
+		Fee:      q.Get("fee"),
+		Transfer: q.Get("transfer"),
+		Other:    q.Get("other"),
+	})
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=UTF-8")
+	w.WriteHeader(http.StatusOK)
+	_, err = w.Write(resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetAllVolatility returns the realized volatility term structure of every
+// enabled pair
+func RESTGetAllVolatility(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetAllVolatility()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetVolatility returns the realized volatility term structure of a pair
+func RESTGetVolatility(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	resp, err := GetVolatility(vars["exchangeName"], currency.NewPairFromString(vars["currency"]))
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetCandles returns the closed candles of a pair from the candle store.
+// The interval query parameter defaults to 1h, from and to are RFC3339 times
+// defaulting to the last 24 hours
+func RESTGetCandles(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	q := r.URL.Query()
+	interval := kline.OneHour
+	to := time.Now()
+	var err error
+	if i := q.Get("interval"); i != "" {
+		interval, err = kline.ParseInterval(i)
+		if err != nil {
+			RESTfulError(r.Method, err)
+			return
+		}
+	}
+	if t := q.Get("to"); t != "" {
+		to, err = time.Parse(time.RFC3339, t)
+		if err != nil {
+			RESTfulError(r.Method, err)
+			return
+		}
+	}
+	from := to.Add(-time.Hour * 24)
+	if f := q.Get("from"); f != "" {
+		from, err = time.Parse(time.RFC3339, f)
+		if err != nil {
+			RESTfulError(r.Method, err)
+			return
+		}
+	}
+
+	resp, err := GetCandles(vars["exchangeName"], currency.NewPairFromString(vars["currency"]), interval, from, to)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetAllVolumeProfiles returns the current session volume profile of
+// every pair
+func RESTGetAllVolumeProfiles(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetAllVolumeProfiles()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetVolumeProfile returns the current and completed session volume
+// profiles of a pair
+func RESTGetVolumeProfile(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	resp, err := GetVolumeProfile(vars["exchangeName"], currency.NewPairFromString(vars["currency"]))
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetPollingSchedules returns the polling interval and orderbook depth of
+// every scheduled pair
+func RESTGetPollingSchedules(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetPollingSchedules()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetCompliance returns the compliance rules enforced and the number of
+// actions blocked
+func RESTGetCompliance(w http.ResponseWriter, r *http.Request) {
+	resp, err := GetCompliance()
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
+
+// RESTGetPnLAttribution returns the portfolio's PnL in the base currency split
+// between asset and FX moves over the period query parameter, defaulting to
+// 24h
+func RESTGetPnLAttribution(w http.ResponseWriter, r *http.Request) {
+	period := time.Hour * 24
+	if p := r.URL.Query().Get("period"); p != "" {
+		var err error
+		period, err = time.ParseDuration(p)
+		if err != nil {
+			RESTfulError(r.Method, err)
+			return
+		}
+	}
+
+	resp, err := GetPnLAttribution(period)
+	if err != nil {
+		RESTfulError(r.Method, err)
+		return
+	}
+
+	err = RESTfulJSONResponse(w, resp)
+	if err != nil {
+		RESTfulError(r.Method, err)
+	}
+}
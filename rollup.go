@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/allocation"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/drawdown"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/rollup"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errRollupDisabled = errors.New("balance roll-up not enabled")
+
+// ActivateRollup starts aggregating the balances of every exchange account and
+// sub-account with authenticated API support into the groupings loaded from
+// the -rollupgroupings file, alongside the exchange, account and, when
+// capital allocation is enabled, strategy groupings
+func ActivateRollup() {
+	if !bot.rollup {
+		return
+	}
+
+	var exchanges []exchange.IBotExchange
+	for _, exch := range GetLoadedExchanges() {
+		if exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			exchanges = append(exchanges, exch)
+		}
+	}
+
+	quote := bot.config.Currency.FiatDisplayCurrency
+	var strategies rollup.StrategiesFunc
+	if bot.allocationLedger != nil {
+		strategies = func() []allocation.Report {
+			return bot.allocationLedger.GetReports()
+		}
+	}
+	r, err := rollup.Load(bot.rollupGroupings, exchanges,
+		func(exchName string, c currency.Code) (float64, error) {
+			exch := GetExchangeByName(exchName)
+			if exch == nil {
+				return 0, ErrExchangeNotFound
+			}
+			return drawdown.ValueInQuote(exch, c, quote)
+		}, strategies)
+	if err != nil {
+		log.Errorf("Balance roll-up failed to load groupings from %s: %s", bot.rollupGroupings, err)
+		return
+	}
+	r.Start(bot.rollupInterval)
+	bot.balanceRollup = r
+	log.Debugf("Balance roll-up enabled for %d exchanges, reporting in %s.", len(exchanges), quote)
+}
+
+// GetRollupReports returns the balances of every group in every grouping
+func GetRollupReports() ([]rollup.Report, error) {
+	if bot.balanceRollup == nil {
+		return nil, errRollupDisabled
+	}
+	return bot.balanceRollup.GetReports()
+}
+
+// GetRollupReport returns the balances of every group in the grouping
+func GetRollupReport(grouping string) (rollup.Report, error) {
+	if bot.balanceRollup == nil {
+		return rollup.Report{}, errRollupDisabled
+	}
+	return bot.balanceRollup.GetReport(grouping)
+}
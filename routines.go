@@ -10,7 +10,9 @@ import (
 	"github.com/thrasher-corp/gocryptotrader/currency"
 	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/polling"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/stats"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/tca"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
 	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
 	log "github.com/thrasher-corp/gocryptotrader/logger"
@@ -22,7 +24,7 @@ func printCurrencyFormat(price float64) string {
 		log.Errorf("Failed to get display symbol: %s", err)
 	}
 
-	return fmt.Sprintf("%s%.8f", displaySymbol, price)
+	return displaySymbol + common.FormatPrice(price)
 }
 
 func printConvertCurrencyFormat(origCurrency currency.Code, origPrice float64) string {
@@ -46,12 +48,12 @@ func printConvertCurrencyFormat(origCurrency currency.Code, origPrice float64) s
 			err)
 	}
 
-	return fmt.Sprintf("%s%.2f %s (%s%.2f %s)",
+	return fmt.Sprintf("%s%s %s (%s%s %s)",
 		displaySymbol,
-		conv,
+		common.FormatPrice(conv),
 		displayCurrency,
 		origSymbol,
-		origPrice,
+		common.FormatPrice(origPrice),
 		origCurrency,
 	)
 }
@@ -66,10 +68,16 @@ func printTickerSummary(result *ticker.Price, p currency.Pair, assetType, exchan
 	}
 
 	stats.Add(exchangeName, p, assetType, result.Last, result.Volume)
+	if result.Bid > 0 && result.Ask > 0 {
+		if spreadErr := tca.AddSpread(exchangeName, p, assetType, result.Bid, result.Ask); spreadErr != nil {
+			log.Debugf("Failed to record %s %s spread. Error: %s",
+				exchangeName, p, spreadErr)
+		}
+	}
 	if p.Quote.IsFiatCurrency() &&
 		p.Quote != bot.config.Currency.FiatDisplayCurrency {
 		origCurrency := p.Quote.Upper()
-		log.Infof("%s %s %s: TICKER: Last %s Ask %s Bid %s High %s Low %s Volume %.8f",
+		log.Infof("%s %s %s: TICKER: Last %s Ask %s Bid %s High %s Low %s Volume %s",
 			exchangeName,
 			exchange.FormatCurrency(p).String(),
 			assetType,
@@ -78,11 +86,11 @@ func printTickerSummary(result *ticker.Price, p currency.Pair, assetType, exchan
 			printConvertCurrencyFormat(origCurrency, result.Bid),
 			printConvertCurrencyFormat(origCurrency, result.High),
 			printConvertCurrencyFormat(origCurrency, result.Low),
-			result.Volume)
+			common.FormatAmount(result.Volume, p.Base.String()))
 	} else {
 		if p.Quote.IsFiatCurrency() &&
 			p.Quote == bot.config.Currency.FiatDisplayCurrency {
-			log.Infof("%s %s %s: TICKER: Last %s Ask %s Bid %s High %s Low %s Volume %.8f",
+			log.Infof("%s %s %s: TICKER: Last %s Ask %s Bid %s High %s Low %s Volume %s",
 				exchangeName,
 				exchange.FormatCurrency(p).String(),
 				assetType,
@@ -91,18 +99,18 @@ func printTickerSummary(result *ticker.Price, p currency.Pair, assetType, exchan
 				printCurrencyFormat(result.Bid),
 				printCurrencyFormat(result.High),
 				printCurrencyFormat(result.Low),
-				result.Volume)
+				common.FormatAmount(result.Volume, p.Base.String()))
 		} else {
-			log.Infof("%s %s %s: TICKER: Last %.8f Ask %.8f Bid %.8f High %.8f Low %.8f Volume %.8f",
+			log.Infof("%s %s %s: TICKER: Last %s Ask %s Bid %s High %s Low %s Volume %s",
 				exchangeName,
 				exchange.FormatCurrency(p).String(),
 				assetType,
-				result.Last,
-				result.Ask,
-				result.Bid,
-				result.High,
-				result.Low,
-				result.Volume)
+				common.FormatPrice(result.Last),
+				common.FormatPrice(result.Ask),
+				common.FormatPrice(result.Bid),
+				common.FormatPrice(result.High),
+				common.FormatPrice(result.Low),
+				common.FormatAmount(result.Volume, p.Base.String()))
 		}
 	}
 }
@@ -123,48 +131,48 @@ func printOrderbookSummary(result *orderbook.Base, p currency.Pair, assetType, e
 	if p.Quote.IsFiatCurrency() &&
 		p.Quote != bot.config.Currency.FiatDisplayCurrency {
 		origCurrency := p.Quote.Upper()
-		log.Infof("%s %s %s: ORDERBOOK: Bids len: %d Amount: %f %s. Total value: %s Asks len: %d Amount: %f %s. Total value: %s",
+		log.Infof("%s %s %s: ORDERBOOK: Bids len: %d Amount: %s %s. Total value: %s Asks len: %d Amount: %s %s. Total value: %s",
 			exchangeName,
 			exchange.FormatCurrency(p).String(),
 			assetType,
 			len(result.Bids),
-			bidsAmount,
+			common.FormatAmount(bidsAmount, p.Base.String()),
 			p.Base.String(),
 			printConvertCurrencyFormat(origCurrency, bidsValue),
 			len(result.Asks),
-			asksAmount,
+			common.FormatAmount(asksAmount, p.Base.String()),
 			p.Base.String(),
 			printConvertCurrencyFormat(origCurrency, asksValue),
 		)
 	} else {
 		if p.Quote.IsFiatCurrency() &&
 			p.Quote == bot.config.Currency.FiatDisplayCurrency {
-			log.Infof("%s %s %s: ORDERBOOK: Bids len: %d Amount: %f %s. Total value: %s Asks len: %d Amount: %f %s. Total value: %s",
+			log.Infof("%s %s %s: ORDERBOOK: Bids len: %d Amount: %s %s. Total value: %s Asks len: %d Amount: %s %s. Total value: %s",
 				exchangeName,
 				exchange.FormatCurrency(p).String(),
 				assetType,
 				len(result.Bids),
-				bidsAmount,
+				common.FormatAmount(bidsAmount, p.Base.String()),
 				p.Base.String(),
 				printCurrencyFormat(bidsValue),
 				len(result.Asks),
-				asksAmount,
+				common.FormatAmount(asksAmount, p.Base.String()),
 				p.Base.String(),
 				printCurrencyFormat(asksValue),
 			)
 		} else {
-			log.Infof("%s %s %s: ORDERBOOK: Bids len: %d Amount: %f %s. Total value: %f Asks len: %d Amount: %f %s. Total value: %f",
+			log.Infof("%s %s %s: ORDERBOOK: Bids len: %d Amount: %s %s. Total value: %s Asks len: %d Amount: %s %s. Total value: %s",
 				exchangeName,
 				exchange.FormatCurrency(p).String(),
 				assetType,
 				len(result.Bids),
-				bidsAmount,
+				common.FormatAmount(bidsAmount, p.Base.String()),
 				p.Base.String(),
-				bidsValue,
+				common.FormatAmount(bidsValue, p.Quote.String()),
 				len(result.Asks),
-				asksAmount,
+				common.FormatAmount(asksAmount, p.Base.String()),
 				p.Base.String(),
-				asksValue,
+				common.FormatAmount(asksValue, p.Quote.String()),
 			)
 		}
 	}
@@ -218,12 +226,14 @@ func TickerUpdaterRoutine() {
 					printTickerSummary(&result, c, assetType, exchangeName, err)
 					if err == nil {
 						bot.comms.StageTickerData(exchangeName, assetType, &result)
+						routeTicker(exchangeName, c, assetType, &result)
 						if bot.config.Webserver.Enabled {
 							relayWebsocketEvent(result, "ticker_update", assetType, exchangeName)
 						}
 					}
 				}
 
+				enabledCurrencies = duePairs(exchangeName, enabledCurrencies, polling.Ticker)
 				for y := range assetTypes {
 					for z := range enabledCurrencies {
 						if supportsBatching && z > 0 {
@@ -237,7 +247,7 @@ func TickerUpdaterRoutine() {
 		}
 		wg.Wait()
 		log.Debugln("All enabled currency tickers fetched.")
-		time.Sleep(time.Second * 10)
+		time.Sleep(pollInterval())
 	}
 }
 
@@ -269,12 +279,15 @@ func OrderbookUpdaterRoutine() {
 					printOrderbookSummary(&result, c, assetType, exchangeName, err)
 					if err == nil {
 						bot.comms.StageOrderbookData(exchangeName, assetType, &result)
+						publishOrderbook(&result)
+						routeOrderbook(&result)
 						if bot.config.Webserver.Enabled {
 							relayWebsocketEvent(result, "orderbook_update", assetType, exchangeName)
 						}
 					}
 				}
 
+				enabledCurrencies = duePairs(exchangeName, enabledCurrencies, polling.Orderbook)
 				for y := range assetTypes {
 					for z := range enabledCurrencies {
 						processOrderbook(bot.exchanges[x], enabledCurrencies[z], assetTypes[y])
@@ -284,7 +297,7 @@ func OrderbookUpdaterRoutine() {
 		}
 		wg.Wait()
 		log.Debugln("All enabled currency orderbooks fetched.")
-		time.Sleep(time.Second * 10)
+		time.Sleep(pollInterval())
 	}
 }
 
@@ -414,15 +427,36 @@ func WebsocketDataHandler(ws *wshandler.Websocket, verbose bool) {
 
 			case wshandler.TradeData:
 				// Trade Data
+				recordWebsocketData(d)
+				recordVolumeProfileTrade(&d)
+				routeTrade(&d)
 				if verbose {
 					log.Infoln("Websocket trades Updated:   ", d)
 				}
 
 			case wshandler.TickerData:
 				// Ticker data
+				if d.BidPrice > 0 && d.AskPrice > 0 {
+					if spreadErr := tca.AddSpread(d.Exchange, d.Pair, d.AssetType, d.BidPrice, d.AskPrice); spreadErr != nil {
+						log.Debugf("Failed to record %s %s websocket spread. Error: %s",
+							d.Exchange, d.Pair, spreadErr)
+					}
+				}
+				routeWebsocketTicker(&d)
 				if verbose {
 					log.Infoln("Websocket Ticker Updated:   ", d)
 				}
+			case wshandler.OrderUpdate:
+				// Account order data
+				routeOrderUpdate(&d)
+				if verbose {
+					log.Infoln("Websocket Order Updated:    ", d)
+				}
+			case wshandler.BalanceUpdate:
+				// Account balance data
+				if verbose {
+					log.Infoln("Websocket Balance Updated:  ", d)
+				}
 			case wshandler.KlineData:
 				// Kline data
 				if verbose {
@@ -430,6 +464,9 @@ func WebsocketDataHandler(ws *wshandler.Websocket, verbose bool) {
 				}
 			case wshandler.WebsocketOrderbookUpdate:
 				// Orderbook data
+				recordWebsocketData(d)
+				publishWebsocketOrderbook(d.Exchange, d.Pair, d.Asset)
+				routeWebsocketOrderbook(d.Exchange, d.Pair, d.Asset)
 				if verbose {
 					log.Infoln("Websocket Orderbook Updated:", d)
 				}
@@ -0,0 +1,219 @@
+package main
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/httprecorder"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+	"github.com/thrasher-corp/gocryptotrader/portfolio"
+)
+
+// shutdownSummary records what each stage of the shutdown did
+type shutdownSummary struct {
+	started           time.Time
+	strategiesStopped int
+	// resting holds the orders open on each exchange before they were
+	// cancelled and cancelFailed the orders the exchange failed to cancel
+	resting      map[string]int
+	cancelFailed map[string]int
+	unsubscribed int
+	wsClosed     int
+	configSaved  bool
+	errs         []string
+	mtx          sync.Mutex
+}
+
+func (s *shutdownSummary) fail(stage string, err error) {
+	s.mtx.Lock()
+	s.errs = append(s.errs, stage+": "+err.Error())
+	s.mtx.Unlock()
+	log.Warnf("Shutdown %s. Err: %s", stage, err)
+}
+
+// report logs the outcome of the shutdown
+func (s *shutdownSummary) report() {
+	log.Debugf("Shutdown summary, completed in %s:", time.Since(s.started).Truncate(time.Millisecond))
+	log.Debugf("\tStrategies stopped: %d", s.strategiesStopped)
+	if s.resting != nil {
+		var names []string
+		for name := range s.resting {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			log.Debugf("\t%s resting orders: %d, failed to cancel: %d",
+				name, s.resting[name], s.cancelFailed[name])
+		}
+	} else {
+		log.Debugln("\tResting orders left open.")
+	}
+	log.Debugf("\tWebsockets closed: %d, channels unsubscribed: %d", s.wsClosed, s.unsubscribed)
+	log.Debugf("\tConfig saved: %v", s.configSaved)
+	if len(s.errs) == 0 {
+		log.Debugln("\tNo errors.")
+		return
+	}
+	log.Warnf("\t%d errors:\n\t\t%s", len(s.errs), strings.Join(s.errs, "\n\t\t"))
+}
+
+// stopOrderSources stops the strategy engine and the services which place
+// orders on their own so no new orders are submitted while shutting down
+func stopOrderSources(s *shutdownSummary) {
+	if bot.strategyEngine != nil {
+		s.strategiesStopped = len(bot.strategyEngine.GetRunning())
+		err := bot.strategyEngine.Stop()
+		if err != nil {
+			s.fail("unable to stop strategy engine", err)
+		}
+	}
+
+	if bot.netter != nil {
+		bot.netter.Stop()
+	}
+
+	if bot.bracketManager != nil {
+		bot.bracketManager.Stop()
+	}
+
+	if bot.imbalanceManager != nil {
+		bot.imbalanceManager.Stop()
+	}
+
+	if bot.spreadManager != nil {
+		bot.spreadManager.Stop()
+	}
+
+	if bot.fundingGuardManager != nil {
+		bot.fundingGuardManager.Stop()
+	}
+
+	if bot.yieldOptimizer != nil {
+		bot.yieldOptimizer.Stop()
+	}
+}
+
+// cancelRestingOrders cancels every open order on the exchanges with
+// authenticated API support
+func cancelRestingOrders(s *shutdownSummary) {
+	s.resting = make(map[string]int)
+	s.cancelFailed = make(map[string]int)
+
+	var wg sync.WaitGroup
+	for _, exch := range GetLoadedExchanges() {
+		if !exch.IsEnabled() || !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		wg.Add(1)
+		go func(exch exchange.IBotExchange) {
+			defer wg.Done()
+			name := exch.GetName()
+			open, err := exch.GetActiveOrders(&exchange.GetOrdersRequest{})
+			if err != nil {
+				s.fail("unable to get "+name+" open orders", err)
+			} else if len(open) == 0 {
+				s.mtx.Lock()
+				s.resting[name] = 0
+				s.mtx.Unlock()
+				return
+			}
+
+			resp, err := exch.CancelAllOrders(&exchange.OrderCancellation{})
+			s.mtx.Lock()
+			s.resting[name] = len(open)
+			s.cancelFailed[name] = len(resp.OrderStatus)
+			s.mtx.Unlock()
+			if err != nil {
+				s.fail("unable to cancel "+name+" orders", err)
+				return
+			}
+			for id, status := range resp.OrderStatus {
+				log.Warnf("%s order %s was not cancelled: %s", name, id, status)
+			}
+		}(exch)
+	}
+	wg.Wait()
+}
+
+// flushPersistence stops the services writing records and saves the config
+func flushPersistence(s *shutdownSummary) {
+	if bot.dropCopyMirror != nil {
+		err := bot.dropCopyMirror.Stop()
+		if err != nil {
+			s.fail("unable to stop drop copy mirror", err)
+		}
+	}
+
+	if bot.wsRecorder != nil {
+		err := bot.wsRecorder.Close()
+		if err != nil {
+			s.fail("unable to close websocket recorder", err)
+		}
+	}
+
+	if bot.httpRecorderFile != nil {
+		httprecorder.Enable(nil)
+		err := bot.httpRecorderFile.Close()
+		if err != nil {
+			s.fail("unable to close HTTP recorder", err)
+		}
+	}
+
+	if currency.IsStorageUpdaterRunning() {
+		err := currency.StopStorageUpdater()
+		if err != nil {
+			s.fail("unable to stop currency storage updater", err)
+		}
+	}
+
+	if len(portfolio.Portfolio.Addresses) != 0 {
+		bot.config.Portfolio = portfolio.Portfolio
+	}
+
+	if !bot.dryRun {
+		err := bot.config.SaveConfig(bot.configFile)
+		if err != nil {
+			s.fail("unable to save config", err)
+		} else {
+			s.configSaved = true
+			log.Debugln("Config file saved successfully.")
+		}
+	}
+}
+
+// closeWebsockets unsubscribes from every channel and closes each connected
+// websocket
+func closeWebsockets(s *shutdownSummary) {
+	var wg sync.WaitGroup
+	for _, exch := range GetLoadedExchanges() {
+		ws, err := exch.GetWebsocket()
+		if err != nil || ws == nil || !ws.IsConnected() {
+			continue
+		}
+		wg.Add(1)
+		go func(name string) {
+			defer wg.Done()
+			n, err := ws.UnsubscribeAll()
+			s.mtx.Lock()
+			s.unsubscribed += n
+			s.mtx.Unlock()
+			if err != nil {
+				s.fail("unable to unsubscribe "+name+" websocket", err)
+			}
+
+			err = ws.Shutdown()
+			if err != nil {
+				s.fail("unable to close "+name+" websocket", err)
+				return
+			}
+			s.mtx.Lock()
+			s.wsClosed++
+			s.mtx.Unlock()
+		}(exch.GetName())
+	}
+	wg.Wait()
+}
@@ -0,0 +1,67 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/spreadhistory"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// spreadHistoryFile holds the recorded spread samples in the data directory
+const spreadHistoryFile = "spreadhistory.jsonl"
+
+var errSpreadHistoryDisabled = errors.New("spread history recorder not enabled")
+
+// ActivateSpreadHistory starts recording the spreads between the instrument
+// pairs configured in the -spreadhistory file, pricing each leg with its
+// ticker
+func ActivateSpreadHistory() {
+	if bot.spreadHistoryConfig == "" {
+		return
+	}
+
+	cfg, err := spreadhistory.LoadConfig(bot.spreadHistoryConfig)
+	if err != nil {
+		log.Errorf("Spread history failed to load %s: %s", bot.spreadHistoryConfig, err)
+		return
+	}
+	path := filepath.Join(bot.dataDir, spreadHistoryFile)
+	r, err := spreadhistory.New(cfg, path, spreadPrice)
+	if err != nil {
+		log.Errorf("Spread history failed to load samples from %s: %s", path, err)
+		return
+	}
+	r.Start()
+	bot.spreadHistory = r
+	log.Debugf("Spread history recorder enabled, recording %d spreads.", len(cfg.Spreads))
+}
+
+// GetSpreadHistoryStatus returns the recording status of every configured
+// spread
+func GetSpreadHistoryStatus() ([]spreadhistory.Status, error) {
+	if bot.spreadHistory == nil {
+		return nil, errSpreadHistoryDisabled
+	}
+	return bot.spreadHistory.GetStatus(), nil
+}
+
+// GetSpreadHistoryStats returns the percentile statistics of a spread over the
+// window, every retained sample when zero. Pairs and basis strategies set
+// their entry and exit bands from them
+func GetSpreadHistoryStats(name string, window time.Duration, percentiles []float64) (spreadhistory.Stats, error) {
+	if bot.spreadHistory == nil {
+		return spreadhistory.Stats{}, errSpreadHistoryDisabled
+	}
+	return bot.spreadHistory.GetStats(name, window, percentiles)
+}
+
+// GetSpreadHistorySamples returns a spread's samples over the window, every
+// retained sample when zero
+func GetSpreadHistorySamples(name string, window time.Duration) ([]spreadhistory.Sample, error) {
+	if bot.spreadHistory == nil {
+		return nil, errSpreadHistoryDisabled
+	}
+	return bot.spreadHistory.GetSamples(name, window)
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/spread"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errSpreadsDisabled = errors.New("spread orders not enabled")
+
+// ActivateSpreadOrders starts working two leg spread orders, resuming the
+// open spreads stored in the data directory
+func ActivateSpreadOrders() {
+	if !bot.spreadOrders {
+		return
+	}
+
+	path := filepath.Join(bot.dataDir, "spreads.json")
+	m, err := spread.New(path, GetExchangeByName, spreadPrice, handleSpreadEvent)
+	if err != nil {
+		log.Errorf("Spread order manager failed to load from %s: %s", path, err)
+		return
+	}
+	m.Start(spread.DefaultCheckInterval)
+	bot.spreadManager = m
+	log.Debugf("Spread order manager enabled, persisting to %s.", path)
+}
+
+// spreadPrice fetches the hedge leg's last price the lean order is quoted
+// off, contracts are priced by their asset type
+func spreadPrice(exchName string, p currency.Pair, assetType string) (float64, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return 0, ErrExchangeNotFound
+	}
+	if assetType == "" {
+		assetType = ticker.Spot
+	}
+	t, err := exch.UpdateTicker(p, assetType)
+	if err != nil {
+		return 0, err
+	}
+	return t.Last, nil
+}
+
+func handleSpreadEvent(e spread.Event) {
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Spread, "spread_event", "", e.Spread.Order.Lean.Exchange)
+	}
+}
+
+// SubmitSpreadOrder starts working a spread order
+func SubmitSpreadOrder(order *spread.Order) (spread.Spread, error) {
+	if bot.spreadManager == nil {
+		return spread.Spread{}, errSpreadsDisabled
+	}
+	return bot.spreadManager.Submit(order)
+}
+
+// CancelSpreadOrder withdraws a spread's lean order, its unhedged fills are
+// hedged before the spread is cancelled
+func CancelSpreadOrder(id string) (spread.Spread, error) {
+	if bot.spreadManager == nil {
+		return spread.Spread{}, errSpreadsDisabled
+	}
+	return bot.spreadManager.Cancel(id)
+}
+
+// GetSpreadOrders returns every spread order worked by the bot
+func GetSpreadOrders() ([]spread.Spread, error) {
+	if bot.spreadManager == nil {
+		return nil, errSpreadsDisabled
+	}
+	return bot.spreadManager.List(), nil
+}
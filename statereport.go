@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"os"
+	"time"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+	"github.com/thrasher-corp/gocryptotrader/statereport"
+)
+
+// GetStateReport returns a signed report of the open orders and balances of
+// every authenticated exchange and the tracked positions. Reports are signed
+// with the key held in the statereport.KeyEnv environment variable
+func GetStateReport() (statereport.Signed, error) {
+	r := buildStateReport()
+	return statereport.Sign(&r, []byte(os.Getenv(statereport.KeyEnv)))
+}
+
+// buildStateReport returns an unsigned report of the live state of every
+// authenticated exchange
+func buildStateReport() statereport.Report {
+	var exchanges []exchange.IBotExchange
+	for _, exch := range GetLoadedExchanges() {
+		if exch.IsEnabled() && exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			exchanges = append(exchanges, exch)
+		}
+	}
+	return statereport.Build(exchanges, dashboardPositions(), time.Now())
+}
+
+// ReadStateReport reads a signed state report from the file, verifying its
+// signature with the key held in the statereport.KeyEnv environment variable
+func ReadStateReport(path string) (statereport.Report, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return statereport.Report{}, err
+	}
+	var s statereport.Signed
+	err = json.Unmarshal(data, &s)
+	if err != nil {
+		return statereport.Report{}, err
+	}
+	return statereport.Verify(&s, []byte(os.Getenv(statereport.KeyEnv)))
+}
+
+// WriteStateReport writes a signed state report to the file
+func WriteStateReport(path string) error {
+	s, err := GetStateReport()
+	if err != nil {
+		return err
+	}
+	data, err := json.MarshalIndent(s, "", " ")
+	if err != nil {
+		return err
+	}
+	err = ioutil.WriteFile(path, data, 0600)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Signed state report written to %s.", path)
+	return nil
+}
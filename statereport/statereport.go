@@ -0,0 +1,125 @@
+// Package statereport produces point in time reports of open orders,
+// positions and balances across venues, signed with an HMAC so fund
+// administrators can verify reports used for audit and reconciliation have
+// not been altered
+package statereport
+
+import (
+	"crypto/hmac"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"sort"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/breakeven"
+)
+
+// KeyEnv holds the report signing key
+const KeyEnv = "GCT_REPORT_KEY"
+
+// Algorithm is the signature algorithm used
+const Algorithm = "HMAC-SHA256"
+
+// Version is the report format version
+const Version = 1
+
+var (
+	errNoKey            = errors.New("report signing key not set")
+	errUnknownAlgorithm = errors.New("unknown report signature algorithm")
+	errInvalidSignature = errors.New("report signature invalid")
+)
+
+// Venue holds the state of a single exchange
+type Venue struct {
+	Exchange string                 `json:"exchange"`
+	Balances []exchange.Account     `json:"balances"`
+	Orders   []exchange.OrderDetail `json:"orders"`
+	// Errors lists the state which could not be fetched, so an incomplete
+	// venue is not mistaken for an empty one
+	Errors []string `json:"errors,omitempty"`
+}
+
+// Report holds the state of every venue at a point in time
+type Report struct {
+	Version   int                  `json:"version"`
+	Generated time.Time            `json:"generated"`
+	Venues    []Venue              `json:"venues"`
+	Positions []breakeven.Position `json:"positions"`
+}
+
+// Signed holds a report and its signature. The report is kept as the exact
+// bytes signed so it can be verified without re-encoding
+type Signed struct {
+	Report    json.RawMessage `json:"report"`
+	Algorithm string          `json:"algorithm"`
+	Signature string          `json:"signature"`
+}
+
+// Build fetches the balances and open orders of each exchange. Failures are
+// recorded against the venue rather than failing the report
+func Build(exchanges []exchange.IBotExchange, positions []breakeven.Position, now time.Time) Report {
+	r := Report{
+		Version:   Version,
+		Generated: now.UTC(),
+		Venues:    make([]Venue, 0, len(exchanges)),
+		Positions: positions,
+	}
+	for i := range exchanges {
+		v := Venue{Exchange: exchanges[i].GetName()}
+		acc, err := exchanges[i].GetAccountInfo()
+		if err != nil {
+			v.Errors = append(v.Errors, "balances: "+err.Error())
+		} else {
+			v.Balances = acc.Accounts
+		}
+		orders, err := exchanges[i].GetActiveOrders(&exchange.GetOrdersRequest{})
+		if err != nil {
+			v.Errors = append(v.Errors, "orders: "+err.Error())
+		} else {
+			v.Orders = orders
+		}
+		r.Venues = append(r.Venues, v)
+	}
+	sort.Slice(r.Venues, func(i, j int) bool {
+		return r.Venues[i].Exchange < r.Venues[j].Exchange
+	})
+	return r
+}
+
+// Sign encodes the report and signs it with the key
+func Sign(r *Report, key []byte) (Signed, error) {
+	if len(key) == 0 {
+		return Signed{}, errNoKey
+	}
+	payload, err := json.Marshal(r)
+	if err != nil {
+		return Signed{}, err
+	}
+	return Signed{
+		Report:    payload,
+		Algorithm: Algorithm,
+		Signature: common.HexEncodeToString(common.GetHMAC(common.HashSHA256, payload, key)),
+	}, nil
+}
+
+// Verify checks the signature with the key and returns the decoded report
+func Verify(s *Signed, key []byte) (Report, error) {
+	if len(key) == 0 {
+		return Report{}, errNoKey
+	}
+	if s.Algorithm != Algorithm {
+		return Report{}, errUnknownAlgorithm
+	}
+	sig, err := hex.DecodeString(s.Signature)
+	if err != nil {
+		return Report{}, errInvalidSignature
+	}
+	if !hmac.Equal(sig, common.GetHMAC(common.HashSHA256, s.Report, key)) {
+		return Report{}, errInvalidSignature
+	}
+	var r Report
+	return r, json.Unmarshal(s.Report, &r)
+}
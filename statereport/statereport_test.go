@@ -0,0 +1,101 @@
+package statereport
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/breakeven"
+)
+
+type testExchange struct {
+	exchange.IBotExchange
+	name      string
+	ordersErr error
+}
+
+func (t *testExchange) GetName() string { return t.name }
+
+func (t *testExchange) GetAccountInfo() (exchange.AccountInfo, error) {
+	return exchange.AccountInfo{
+		Exchange: t.name,
+		Accounts: []exchange.Account{{
+			Currencies: []exchange.AccountCurrencyInfo{{CurrencyName: currency.BTC, TotalValue: 1.5}},
+		}},
+	}, nil
+}
+
+func (t *testExchange) GetActiveOrders(_ *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	if t.ordersErr != nil {
+		return nil, t.ordersErr
+	}
+	return []exchange.OrderDetail{{ID: "1", Exchange: t.name, Price: 100, Amount: 2}}, nil
+}
+
+func TestBuild(t *testing.T) {
+	now := time.Date(2019, 6, 1, 12, 0, 0, 0, time.UTC)
+	r := Build([]exchange.IBotExchange{
+		&testExchange{name: "zeta"},
+		&testExchange{name: "alpha", ordersErr: errors.New("timeout")},
+	}, []breakeven.Position{{Exchange: "zeta", Size: 1}}, now)
+
+	if r.Version != Version || !r.Generated.Equal(now) || len(r.Positions) != 1 {
+		t.Fatalf("Test Failed - Build() unexpected report %+v", r)
+	}
+	if len(r.Venues) != 2 || r.Venues[0].Exchange != "alpha" {
+		t.Fatalf("Test Failed - Build() expected venues sorted by name %+v", r.Venues)
+	}
+	if len(r.Venues[0].Errors) != 1 || r.Venues[0].Orders != nil || len(r.Venues[0].Balances) != 1 {
+		t.Errorf("Test Failed - Build() expected order error recorded %+v", r.Venues[0])
+	}
+	if len(r.Venues[1].Errors) != 0 || len(r.Venues[1].Orders) != 1 {
+		t.Errorf("Test Failed - Build() unexpected venue %+v", r.Venues[1])
+	}
+}
+
+func TestSignVerify(t *testing.T) {
+	r := Build([]exchange.IBotExchange{&testExchange{name: "test"}}, nil, time.Now())
+	key := []byte("secret")
+
+	if _, err := Sign(&r, nil); err != errNoKey {
+		t.Error("Test Failed - Sign() expected no key error", err)
+	}
+	s, err := Sign(&r, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The signed report survives encoding to and from JSON
+	data, err := json.Marshal(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var decoded Signed
+	if err = json.Unmarshal(data, &decoded); err != nil {
+		t.Fatal(err)
+	}
+	v, err := Verify(&decoded, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(v.Venues) != 1 || v.Venues[0].Orders[0].ID != "1" {
+		t.Errorf("Test Failed - Verify() unexpected report %+v", v)
+	}
+
+	if _, err = Verify(&decoded, []byte("wrong")); err != errInvalidSignature {
+		t.Error("Test Failed - Verify() expected invalid signature with wrong key", err)
+	}
+	tampered := decoded
+	tampered.Report = json.RawMessage(string(decoded.Report[:len(decoded.Report)-1]) + " }")
+	if _, err = Verify(&tampered, key); err != errInvalidSignature {
+		t.Error("Test Failed - Verify() expected invalid signature for altered report", err)
+	}
+	tampered = decoded
+	tampered.Algorithm = "none"
+	if _, err = Verify(&tampered, key); err != errUnknownAlgorithm {
+		t.Error("Test Failed - Verify() expected unknown algorithm error", err)
+	}
+}
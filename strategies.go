@@ -0,0 +1,239 @@
+package main
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/request"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/ticker"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+	"github.com/thrasher-corp/gocryptotrader/strategy"
+)
+
+var errStrategiesDisabled = errors.New("strategy file not loaded")
+
+// ActivateStrategies starts the packaged strategies declared in the strategy
+// file and reloads them whenever the file is modified. Strategies start once
+// the market data warmup is ready when it is enabled
+func ActivateStrategies() {
+	if bot.strategyFile == "" {
+		return
+	}
+
+	e, err := strategy.NewEngine(bot.strategyFile, strategyExchange)
+	if err != nil {
+		log.Errorf("Strategy engine failed to start: %s", err)
+		return
+	}
+	for name, f := range map[string]strategy.Factory{
+		"twap":        strategy.NewTWAP,
+		"iceberg":     strategy.NewIceberg,
+		"marketmaker": strategy.NewMarketMaker,
+		"position":    strategy.NewPosition,
+	} {
+		err = e.Register(name, f)
+		if err != nil {
+			log.Errorf("Strategy engine failed to register %s: %s", name, err)
+		}
+	}
+	e.SetVolatility(strategyVolatility)
+	e.SetVolumeProfile(GetVolumeProfile)
+	e.SetOrderbookFeed(SubscribeOrderbookFeed)
+	e.SetInventorySkew(strategyInventorySkew)
+	e.SetFills(handleStrategyFill)
+	if bot.netter != nil {
+		e.SetTargets(bot.netter)
+	}
+	if bot.imbalanceManager != nil {
+		e.SetImbalanceOrders(strategyImbalanceOrder)
+	}
+	bot.strategyEngine = e
+
+	go func() {
+		if bot.warmup != nil {
+			select {
+			case <-bot.shutdown:
+				return
+			case <-bot.warmup.Ready():
+			}
+		}
+		err := e.Reload()
+		if err != nil {
+			log.Errorf("Strategy file %s failed to load: %s", bot.strategyFile, err)
+		}
+		err = e.Start(bot.strategyReload)
+		if err != nil {
+			log.Errorf("Strategy engine failed to watch %s: %s", bot.strategyFile, err)
+		}
+	}()
+	log.Debugf("Strategy engine enabled, loading %s.", bot.strategyFile)
+}
+
+// strategyExchange returns the exchange a declared strategy trades through,
+// limited to its capital allocation when allocations are enabled
+func strategyExchange(exchName, strategyName string) (exchange.IBotExchange, error) {
+	if bot.allocationLedger != nil {
+		return GetAllocatedExchange(exchName, strategyName)
+	}
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+	return exch, nil
+}
+
+// routeTicker routes a polled ticker to the strategies trading its pair
+func routeTicker(exchName string, p currency.Pair, assetType string, t *ticker.Price) {
+	if bot.strategyEngine == nil {
+		return
+	}
+	bot.strategyEngine.OnTick(&strategy.Ticker{
+		Exchange:  exchName,
+		Pair:      p,
+		AssetType: assetType,
+		Last:      t.Last,
+		Bid:       t.Bid,
+		Ask:       t.Ask,
+		High:      t.High,
+		Low:       t.Low,
+		Volume:    t.Volume,
+		Time:      t.LastUpdated,
+	})
+}
+
+// routeWebsocketTicker routes a websocket ticker to the strategies trading its
+// pair
+func routeWebsocketTicker(d *wshandler.TickerData) {
+	if bot.strategyEngine == nil {
+		return
+	}
+	bot.strategyEngine.OnTick(&strategy.Ticker{
+		Exchange:  d.Exchange,
+		Pair:      d.Pair,
+		AssetType: d.AssetType,
+		Last:      d.ClosePrice,
+		Bid:       d.BidPrice,
+		Ask:       d.AskPrice,
+		High:      d.HighPrice,
+		Low:       d.LowPrice,
+		Volume:    d.Quantity,
+		Time:      d.Timestamp,
+	})
+}
+
+// routeOrderbook routes an orderbook to the strategies trading its pair
+func routeOrderbook(o *orderbook.Base) {
+	if bot.strategyEngine == nil {
+		return
+	}
+	bot.strategyEngine.OnOrderbook(o)
+}
+
+// routeWebsocketOrderbook routes the stored orderbook of a websocket orderbook
+// update to the strategies trading its pair
+func routeWebsocketOrderbook(exchName string, p currency.Pair, assetType string) {
+	if bot.strategyEngine == nil {
+		return
+	}
+	o, err := orderbook.Get(exchName, p, assetType)
+	if err != nil {
+		log.Debugf("Failed to get %s %s websocket orderbook: %s", exchName, p, err)
+		return
+	}
+	bot.strategyEngine.OnOrderbook(&o)
+}
+
+// routeTrade routes a websocket trade to the strategies trading its pair
+func routeTrade(d *wshandler.TradeData) {
+	if bot.strategyEngine == nil {
+		return
+	}
+	side := exchange.OrderSide(strings.ToUpper(d.Side))
+	switch side {
+	case exchange.BidOrderSide:
+		side = exchange.BuyOrderSide
+	case exchange.AskOrderSide:
+		side = exchange.SellOrderSide
+	}
+	bot.strategyEngine.OnTrade(&strategy.Trade{
+		Exchange:  d.Exchange,
+		Pair:      d.CurrencyPair,
+		AssetType: d.AssetType,
+		Price:     d.Price,
+		Amount:    d.Amount,
+		Side:      side,
+		Time:      d.Timestamp,
+	})
+}
+
+// routeOrderUpdate routes a websocket account order update to the strategies
+// trading its pair
+func routeOrderUpdate(d *wshandler.OrderUpdate) {
+	if bot.strategyEngine == nil {
+		return
+	}
+	bot.strategyEngine.OnOrderUpdate(&strategy.OrderUpdate{
+		Exchange:    d.Exchange,
+		Pair:        d.Pair,
+		AssetType:   d.AssetType,
+		OrderID:     d.OrderID,
+		Side:        exchange.OrderSide(d.Side),
+		Status:      exchange.OrderStatus(d.Status),
+		Price:       d.Price,
+		Remaining:   d.Remaining,
+		Filled:      d.Filled,
+		FillPrice:   d.FillPrice,
+		Fee:         d.Fee,
+		FeeCurrency: d.FeeCurrency,
+		Time:        d.Timestamp,
+	})
+}
+
+// GetRunningStrategies returns the declarations of running strategies
+func GetRunningStrategies() ([]strategy.Definition, error) {
+	if bot.strategyEngine == nil {
+		return nil, errStrategiesDisabled
+	}
+	return bot.strategyEngine.GetRunning(), nil
+}
+
+// GetStrategySandboxes returns the sandbox state and resource accounting of
+// each running strategy
+func GetStrategySandboxes() (map[string]strategy.SandboxStats, error) {
+	if bot.strategyEngine == nil {
+		return nil, errStrategiesDisabled
+	}
+	return bot.strategyEngine.GetSandboxStats(), nil
+}
+
+// ResumeStrategy restarts a strategy suspended after panicking, getting stuck
+// or exceeding its resource limits
+func ResumeStrategy(name string) error {
+	if bot.strategyEngine == nil {
+		return errStrategiesDisabled
+	}
+	return bot.strategyEngine.Resume(name)
+}
+
+// GetRequestTagStats returns the requests each exchange has sent under each
+// request tag, attributing rate limit consumption and errors to strategies
+func GetRequestTagStats() map[string]map[string]request.TagStats {
+	type tagStats interface {
+		GetRequestTagStats() map[string]request.TagStats
+	}
+	resp := make(map[string]map[string]request.TagStats)
+	for _, exch := range GetLoadedExchanges() {
+		e, ok := exchange.Underlying(exch).(tagStats)
+		if !ok {
+			continue
+		}
+		if stats := e.GetRequestTagStats(); len(stats) > 0 {
+			resp[exch.GetName()] = stats
+		}
+	}
+	return resp
+}
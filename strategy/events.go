@@ -0,0 +1,176 @@
+package strategy
+
+import (
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+)
+
+// DefaultEventBuffer is the number of market data events queued per strategy,
+// events arriving while the queue is full are dropped
+const DefaultEventBuffer = 256
+
+// Ticker is a ticker update from an exchange's REST poller or websocket
+type Ticker struct {
+	Exchange  string        `json:"exchange"`
+	Pair      currency.Pair `json:"pair"`
+	AssetType string        `json:"assetType"`
+	Last      float64       `json:"last"`
+	Bid       float64       `json:"bid"`
+	Ask       float64       `json:"ask"`
+	High      float64       `json:"high"`
+	Low       float64       `json:"low"`
+	Volume    float64       `json:"volume"`
+	Time      time.Time     `json:"time"`
+}
+
+// Trade is a public trade from an exchange's websocket
+type Trade struct {
+	Exchange  string             `json:"exchange"`
+	Pair      currency.Pair      `json:"pair"`
+	AssetType string             `json:"assetType"`
+	Price     float64            `json:"price"`
+	Amount    float64            `json:"amount"`
+	Side      exchange.OrderSide `json:"side"`
+	Time      time.Time          `json:"time"`
+}
+
+// OrderUpdate is a change to one of the account's orders from an exchange's
+// authenticated websocket, fills set Filled and FillPrice
+type OrderUpdate struct {
+	Exchange    string               `json:"exchange"`
+	Pair        currency.Pair        `json:"pair"`
+	AssetType   string               `json:"assetType"`
+	OrderID     string               `json:"orderID"`
+	Side        exchange.OrderSide   `json:"side"`
+	Status      exchange.OrderStatus `json:"status"`
+	Price       float64              `json:"price"`
+	Remaining   float64              `json:"remaining"`
+	Filled      float64              `json:"filled"`
+	FillPrice   float64              `json:"fillPrice"`
+	Fee         float64              `json:"fee"`
+	FeeCurrency currency.Code        `json:"feeCurrency"`
+	Time        time.Time            `json:"time"`
+}
+
+// TickHandler is implemented by strategies reacting to ticker updates of
+// their declared pairs
+type TickHandler interface {
+	OnTick(t *Ticker)
+}
+
+// OrderbookHandler is implemented by strategies reacting to orderbook updates
+// of their declared pairs. The orderbook is shared and must not be modified
+type OrderbookHandler interface {
+	OnOrderbook(o *orderbook.Base)
+}
+
+// TradeHandler is implemented by strategies reacting to public trades of
+// their declared pairs
+type TradeHandler interface {
+	OnTrade(t *Trade)
+}
+
+// OrderUpdateHandler is implemented by strategies tracking their orders from
+// the exchange's account notifications rather than polling open orders
+type OrderUpdateHandler interface {
+	OnOrderUpdate(o *OrderUpdate)
+}
+
+// handlesEvents returns true if the strategy implements a market data handler
+func handlesEvents(s Strategy) bool {
+	switch s.(type) {
+	case TickHandler, OrderbookHandler, TradeHandler, OrderUpdateHandler:
+		return true
+	}
+	return false
+}
+
+// trades returns true if the strategy declares the pair on the exchange
+func (i *instance) trades(exchName string, p currency.Pair) bool {
+	if !strings.EqualFold(i.def.Exchange, exchName) {
+		return false
+	}
+	for j := range i.pairs {
+		if strings.EqualFold(i.pairs[j].Base.String(), p.Base.String()) &&
+			strings.EqualFold(i.pairs[j].Quote.String(), p.Quote.String()) {
+			return true
+		}
+	}
+	return false
+}
+
+// deliver runs the strategy's handlers in its sandbox in the order events
+// arrived until the strategy is stopped
+func (i *instance) deliver(events <-chan func(), quit <-chan struct{}) {
+	for {
+		select {
+		case <-quit:
+			return
+		case f := <-events:
+			// Panics and suspensions are handled by the sandbox, events
+			// reaching a suspended strategy are discarded
+			_ = i.sandbox.Tick(f)
+		}
+	}
+}
+
+// queue queues an event for the strategy without blocking the caller
+func (i *instance) queue(f func()) {
+	select {
+	case i.events <- f:
+	default:
+		i.sandbox.drop()
+	}
+}
+
+// dispatch queues an event to every running strategy declaring the pair on
+// the exchange whose handler accepts it
+func (e *Engine) dispatch(exchName string, p currency.Pair, handler func(s Strategy) (func(), bool)) {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	for _, r := range e.running {
+		if r.events == nil || !r.trades(exchName, p) {
+			continue
+		}
+		if f, ok := handler(r.strategy); ok {
+			r.queue(f)
+		}
+	}
+}
+
+// OnTick routes a ticker update to the strategies trading its pair
+func (e *Engine) OnTick(t *Ticker) {
+	e.dispatch(t.Exchange, t.Pair, func(s Strategy) (func(), bool) {
+		h, ok := s.(TickHandler)
+		return func() { h.OnTick(t) }, ok
+	})
+}
+
+// OnOrderbook routes an orderbook update to the strategies trading its pair
+func (e *Engine) OnOrderbook(o *orderbook.Base) {
+	e.dispatch(o.ExchangeName, o.Pair, func(s Strategy) (func(), bool) {
+		h, ok := s.(OrderbookHandler)
+		return func() { h.OnOrderbook(o) }, ok
+	})
+}
+
+// OnTrade routes a public trade to the strategies trading its pair
+func (e *Engine) OnTrade(t *Trade) {
+	e.dispatch(t.Exchange, t.Pair, func(s Strategy) (func(), bool) {
+		h, ok := s.(TradeHandler)
+		return func() { h.OnTrade(t) }, ok
+	})
+}
+
+// OnOrderUpdate routes an account order update to the strategies trading its
+// pair
+func (e *Engine) OnOrderUpdate(o *OrderUpdate) {
+	e.dispatch(o.Exchange, o.Pair, func(s Strategy) (func(), bool) {
+		h, ok := s.(OrderUpdateHandler)
+		return func() { h.OnOrderUpdate(o) }, ok
+	})
+}
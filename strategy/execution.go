@@ -0,0 +1,146 @@
+package strategy
+
+import (
+	"errors"
+	"strings"
+	"sync"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/execution"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errNoAmount = errors.New("strategy sizing amount not set")
+
+// Execution works a parent order of the sizing amount into each declared pair
+// using TWAP or iceberg execution. Parameters are side, orderType, price,
+// slices, display, interval, precision and variance
+type Execution struct {
+	strategy   execution.Strategy
+	volatility VolatilityFunc
+	sandbox    *Sandbox
+	shutdown   chan struct{}
+	wg         sync.WaitGroup
+}
+
+// NewTWAP returns a packaged TWAP execution strategy
+func NewTWAP() Strategy {
+	return &Execution{strategy: execution.TWAP}
+}
+
+// NewIceberg returns a packaged iceberg execution strategy
+func NewIceberg() Strategy {
+	return &Execution{strategy: execution.Iceberg}
+}
+
+// Start starts working a parent order in each pair
+func (x *Execution) Start(e exchange.IBotExchange, d *Definition) error {
+	if d.Sizing.Amount <= 0 {
+		return errNoAmount
+	}
+	cfg, err := x.config(d)
+	if err != nil {
+		return err
+	}
+
+	var executors []*execution.Executor
+	pairs := d.GetPairs()
+	for i := range pairs {
+		cfg.Pair = pairs[i]
+		cfg.Amount = d.PairAmount(pairs[i], x.volatility)
+		ex, err := execution.New(e, cfg)
+		if err != nil {
+			return err
+		}
+		executors = append(executors, ex)
+	}
+
+	x.shutdown = make(chan struct{})
+	for i := range executors {
+		ex := executors[i]
+		run := func() {
+			defer x.wg.Done()
+			r, err := ex.Run(x.shutdown)
+			if err != nil {
+				log.Errorf("Strategy %s execution stopped: %s. %s", d.Name, err, r.String())
+				return
+			}
+			log.Debugf("Strategy %s execution complete. %s", d.Name, r.String())
+		}
+		x.wg.Add(1)
+		if x.sandbox == nil {
+			go run()
+			continue
+		}
+		if err = x.sandbox.Go(run); err != nil {
+			x.wg.Done()
+			x.Stop()
+			return err
+		}
+	}
+	return nil
+}
+
+// SetSandbox sets the sandbox executions run in
+func (x *Execution) SetSandbox(s *Sandbox) {
+	x.sandbox = s
+}
+
+// SetVolatility sets the source of realized volatility for volatility
+// targeted sizing
+func (x *Execution) SetVolatility(f VolatilityFunc) {
+	x.volatility = f
+}
+
+func (x *Execution) config(d *Definition) (execution.Config, error) {
+	cfg := execution.Config{
+		Strategy:  x.strategy,
+		Side:      exchange.BuyOrderSide,
+		OrderType: exchange.LimitOrderType,
+		Amount:    d.Sizing.Amount,
+	}
+	if side, ok := d.Param("side"); ok {
+		cfg.Side = exchange.OrderSide(strings.ToUpper(side))
+	}
+	if orderType, ok := d.Param("orderType"); ok {
+		cfg.OrderType = exchange.OrderType(strings.ToUpper(orderType))
+	}
+
+	var err error
+	if cfg.Price, err = d.FloatParam("price", 0); err != nil {
+		return cfg, err
+	}
+	if cfg.Slices, err = d.IntParam("slices", 0); err != nil {
+		return cfg, err
+	}
+	if cfg.DisplayAmount, err = d.FloatParam("display", 0); err != nil {
+		return cfg, err
+	}
+	if cfg.Interval, err = d.DurationParam("interval", 0); err != nil {
+		return cfg, err
+	}
+	if cfg.AmountPrecision, err = d.IntParam("precision", 0); err != nil {
+		return cfg, err
+	}
+	variance, err := d.FloatParam("variance", 0)
+	if err != nil {
+		return cfg, err
+	}
+	if variance > 0 {
+		cfg.Randomise = &execution.Randomisation{
+			SizeVariance:     variance,
+			IntervalVariance: variance,
+		}
+	}
+	return cfg, nil
+}
+
+// Stop cancels any parent orders still being worked
+func (x *Execution) Stop() {
+	if x.shutdown == nil {
+		return
+	}
+	close(x.shutdown)
+	x.wg.Wait()
+	x.shutdown = nil
+}
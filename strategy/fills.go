@@ -0,0 +1,166 @@
+package strategy
+
+import (
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// Fill holds an amount of a strategy's order observed to have filled
+type Fill struct {
+	Strategy  string
+	Exchange  string
+	OrderID   string
+	Pair      currency.Pair
+	Side      exchange.OrderSide
+	OrderType exchange.OrderType
+	Price     float64
+	Amount    float64
+	Submitted time.Time
+	Timestamp time.Time
+}
+
+// FillFunc receives the fills of strategy orders
+type FillFunc func(f *Fill)
+
+// submitted holds an order submitted by a strategy and the amount observed
+// filled so far
+type submitted struct {
+	pair      currency.Pair
+	side      exchange.OrderSide
+	orderType exchange.OrderType
+	price     float64
+	amount    float64
+	filled    float64
+	time      time.Time
+}
+
+// Recorded wraps the exchange a strategy trades through, reporting fills of
+// the strategy's orders as they are observed when the strategy fetches them
+type Recorded struct {
+	exchange.IBotExchange
+	name   string
+	onFill FillFunc
+	orders map[string]*submitted
+	mtx    sync.Mutex
+}
+
+// NewRecorded returns an exchange which reports fills of the strategy's orders
+func NewRecorded(e exchange.IBotExchange, name string, onFill FillFunc) *Recorded {
+	return &Recorded{
+		IBotExchange: e,
+		name:         name,
+		onFill:       onFill,
+		orders:       make(map[string]*submitted),
+	}
+}
+
+// Unwrap returns the underlying exchange
+func (r *Recorded) Unwrap() exchange.IBotExchange {
+	return r.IBotExchange
+}
+
+// SubmitOrder submits the order, remembering it so its fills can be reported
+func (r *Recorded) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	now := time.Now()
+	resp, err := r.IBotExchange.SubmitOrder(p, side, orderType, amount, price, clientID)
+	if err == nil && resp.IsOrderPlaced && resp.OrderID != "" {
+		r.mtx.Lock()
+		r.orders[resp.OrderID] = &submitted{
+			pair:      p,
+			side:      side,
+			orderType: orderType,
+			price:     price,
+			amount:    amount,
+			time:      now,
+		}
+		r.mtx.Unlock()
+	}
+	return resp, err
+}
+
+// CancelOrder cancels the order, forgetting it once cancelled. An order which
+// filled before it could be cancelled is fetched so its fill is reported
+func (r *Recorded) CancelOrder(order *exchange.OrderCancellation) error {
+	err := r.IBotExchange.CancelOrder(order)
+	switch err {
+	case nil:
+		r.mtx.Lock()
+		delete(r.orders, order.OrderID)
+		r.mtx.Unlock()
+	case exchange.ErrCancelOrderFilled:
+		// Errors are ignored, the fill is reported when next fetched
+		_, _ = r.GetOrderInfo(order.OrderID)
+	}
+	return err
+}
+
+// GetOrderInfo fetches the order, reporting any new fill
+func (r *Recorded) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	detail, err := r.IBotExchange.GetOrderInfo(orderID)
+	if err == nil {
+		if detail.ID == "" {
+			detail.ID = orderID
+		}
+		r.observe([]exchange.OrderDetail{detail})
+	}
+	return detail, err
+}
+
+// GetActiveOrders fetches open orders, reporting any new fills
+func (r *Recorded) GetActiveOrders(req *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	orders, err := r.IBotExchange.GetActiveOrders(req)
+	if err == nil {
+		r.observe(orders)
+	}
+	return orders, err
+}
+
+// GetOrderHistory fetches order history, reporting any new fills
+func (r *Recorded) GetOrderHistory(req *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	orders, err := r.IBotExchange.GetOrderHistory(req)
+	if err == nil {
+		r.observe(orders)
+	}
+	return orders, err
+}
+
+// observe reports the amount each known order has filled since it was last
+// observed, forgetting orders once completely filled
+func (r *Recorded) observe(orders []exchange.OrderDetail) {
+	var fills []Fill
+	r.mtx.Lock()
+	for i := range orders {
+		o, ok := r.orders[orders[i].ID]
+		if !ok || orders[i].ExecutedAmount <= o.filled {
+			continue
+		}
+		price := orders[i].Price
+		if price <= 0 {
+			price = o.price
+		}
+		fills = append(fills, Fill{
+			Strategy:  r.name,
+			Exchange:  r.GetName(),
+			OrderID:   orders[i].ID,
+			Pair:      o.pair,
+			Side:      o.side,
+			OrderType: o.orderType,
+			Price:     price,
+			Amount:    orders[i].ExecutedAmount - o.filled,
+			Submitted: o.time,
+			Timestamp: time.Now(),
+		})
+		o.filled = orders[i].ExecutedAmount
+		if o.filled >= o.amount {
+			delete(r.orders, orders[i].ID)
+		}
+	}
+	r.mtx.Unlock()
+
+	for i := range fills {
+		r.onFill(&fills[i])
+	}
+}
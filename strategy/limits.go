@@ -0,0 +1,81 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/duplicates"
+)
+
+// ErrRiskLimit is returned when an order breaches a strategy's declared sizing
+// or risk limits
+var ErrRiskLimit = errors.New("order exceeds strategy risk limit")
+
+// Limited wraps the exchange a strategy trades through, rejecting orders which
+// breach the limits declared for the strategy
+type Limited struct {
+	exchange.IBotExchange
+	name   string
+	sizing Sizing
+	risk   Risk
+	// traded holds the amount submitted per pair for the position limit
+	traded map[string]float64
+	mtx    sync.Mutex
+}
+
+// NewLimited returns an exchange which enforces the strategy's limits
+func NewLimited(e exchange.IBotExchange, d *Definition) *Limited {
+	return &Limited{
+		IBotExchange: e,
+		name:         d.Name,
+		sizing:       d.Sizing,
+		risk:         d.Risk,
+		traded:       make(map[string]float64),
+	}
+}
+
+// Unwrap returns the underlying exchange
+func (l *Limited) Unwrap() exchange.IBotExchange {
+	return l.IBotExchange
+}
+
+// SubmitOrder submits the order if it is within the strategy's limits
+func (l *Limited) SubmitOrder(p currency.Pair, side exchange.OrderSide, orderType exchange.OrderType, amount, price float64, clientID string) (exchange.SubmitOrderResponse, error) {
+	l.mtx.Lock()
+	defer l.mtx.Unlock()
+	if l.risk.MaxOrderAmount > 0 && amount > l.risk.MaxOrderAmount {
+		return exchange.SubmitOrderResponse{}, fmt.Errorf("%s %v: amount %v exceeds %v",
+			l.name, ErrRiskLimit, amount, l.risk.MaxOrderAmount)
+	}
+	if l.risk.MaxOrderValue > 0 && price > 0 && amount*price > l.risk.MaxOrderValue {
+		return exchange.SubmitOrderResponse{}, fmt.Errorf("%s %v: value %v exceeds %v",
+			l.name, ErrRiskLimit, amount*price, l.risk.MaxOrderValue)
+	}
+	k := p.String()
+	if l.sizing.MaxPosition > 0 && l.traded[k]+amount > l.sizing.MaxPosition {
+		return exchange.SubmitOrderResponse{}, fmt.Errorf("%s %v: %s position %v exceeds %v",
+			l.name, ErrRiskLimit, k, l.traded[k]+amount, l.sizing.MaxPosition)
+	}
+	if l.risk.MaxOpenOrders > 0 {
+		orders, err := l.IBotExchange.GetActiveOrders(&exchange.GetOrdersRequest{
+			Currencies: []currency.Pair{p},
+		})
+		if err != nil {
+			return exchange.SubmitOrderResponse{}, err
+		}
+		if len(orders) >= l.risk.MaxOpenOrders {
+			return exchange.SubmitOrderResponse{}, fmt.Errorf("%s %v: %d open orders",
+				l.name, ErrRiskLimit, len(orders))
+		}
+	}
+
+	resp, err := duplicates.SubmitOrder(l.IBotExchange, p, side, orderType, amount, price, clientID,
+		l.risk.AllowDuplicateOrders)
+	if err == nil && resp.IsOrderPlaced {
+		l.traded[k] += amount
+	}
+	return resp, err
+}
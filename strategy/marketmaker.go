@@ -0,0 +1,159 @@
+package strategy
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bookfeed"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/inventory"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/quoting"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errNoOrderbookFeed = errors.New("strategy requires the orderbook feed")
+
+// MarketMaker quotes both sides of each declared pair around the mid price
+// of its orderbook, pulling the quote whenever the book data it is based on
+// is older than the latency budget. Parameters are spreadBps, requoteBps,
+// latencyBudget and assetType
+type MarketMaker struct {
+	books    OrderbookFeedFunc
+	skew     InventorySkewFunc
+	sandbox  *Sandbox
+	shutdown chan struct{}
+	wg       sync.WaitGroup
+}
+
+// NewMarketMaker returns a packaged market making strategy
+func NewMarketMaker() Strategy {
+	return &MarketMaker{}
+}
+
+// SetOrderbookFeed sets the orderbook feed quotes are based on
+func (m *MarketMaker) SetOrderbookFeed(f OrderbookFeedFunc) {
+	m.books = f
+}
+
+// SetInventorySkew sets the inventory skew applied to quotes
+func (m *MarketMaker) SetInventorySkew(f InventorySkewFunc) {
+	m.skew = f
+}
+
+// SetSandbox sets the sandbox quoters run in
+func (m *MarketMaker) SetSandbox(s *Sandbox) {
+	m.sandbox = s
+}
+
+// Start starts quoting each pair
+func (m *MarketMaker) Start(e exchange.IBotExchange, d *Definition) error {
+	if m.books == nil {
+		return errNoOrderbookFeed
+	}
+	if d.Sizing.Amount <= 0 {
+		return errNoAmount
+	}
+	cfg, err := m.config(d)
+	if err != nil {
+		return err
+	}
+
+	var quoters []*quoting.Quoter
+	pairs := d.GetPairs()
+	for i := range pairs {
+		cfg.Pair = pairs[i]
+		cfg.Amount = d.Sizing.Amount
+		cfg.Skew = m.skewFunc(e.GetName(), pairs[i])
+		q, err := quoting.New(e, cfg, func(ev quoting.Event) {
+			log.Debugf("Strategy %s %s", d.Name, ev.String())
+		})
+		if err != nil {
+			return err
+		}
+		quoters = append(quoters, q)
+	}
+
+	m.shutdown = make(chan struct{})
+	for i := range quoters {
+		opts := bookfeed.Options{
+			Name:      d.Name,
+			Exchange:  e.GetName(),
+			Pair:      pairs[i],
+			AssetType: cfg.AssetType,
+		}
+		q := quoters[i]
+		run := func() {
+			defer m.wg.Done()
+			m.run(d.Name, q, opts, cfg.LatencyBudget)
+		}
+		m.wg.Add(1)
+		if m.sandbox == nil {
+			go run()
+			continue
+		}
+		if err = m.sandbox.Go(run); err != nil {
+			m.wg.Done()
+			m.Stop()
+			return err
+		}
+	}
+	return nil
+}
+
+// run quotes from a subscription to the pair's book, subscribing again from
+// a snapshot when the subscription gaps or fails
+func (m *MarketMaker) run(name string, q *quoting.Quoter, opts bookfeed.Options, budget time.Duration) {
+	for {
+		sub, err := m.books(opts)
+		if err == nil {
+			err = q.Run(sub, m.shutdown)
+			sub.Close()
+			if err == nil {
+				return
+			}
+		}
+		log.Warnf("Strategy %s %s %s quoting interrupted: %s", name, opts.Exchange, opts.Pair, err)
+		select {
+		case <-m.shutdown:
+			q.Pull("quoting stopped")
+			return
+		case <-time.After(budget):
+		}
+	}
+}
+
+func (m *MarketMaker) skewFunc(exchName string, p currency.Pair) quoting.SkewFunc {
+	if m.skew == nil {
+		return nil
+	}
+	return func(q inventory.Quote) (inventory.Quote, error) {
+		return m.skew(exchName, p, q)
+	}
+}
+
+func (m *MarketMaker) config(d *Definition) (quoting.Config, error) {
+	var cfg quoting.Config
+	cfg.AssetType, _ = d.Param("assetType")
+
+	var err error
+	if cfg.SpreadBps, err = d.FloatParam("spreadBps", quoting.DefaultSpreadBps); err != nil {
+		return cfg, err
+	}
+	if cfg.RequoteBps, err = d.FloatParam("requoteBps", quoting.DefaultRequoteBps); err != nil {
+		return cfg, err
+	}
+	cfg.LatencyBudget, err = d.DurationParam("latencyBudget", quoting.DefaultLatencyBudget)
+	return cfg, err
+}
+
+// Stop pulls every quote
+func (m *MarketMaker) Stop() {
+	if m.shutdown == nil {
+		return
+	}
+	close(m.shutdown)
+	m.wg.Wait()
+	m.shutdown = nil
+}
@@ -0,0 +1,45 @@
+package strategy
+
+import (
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+)
+
+// Position holds a fixed target position in each declared pair, netted with
+// the targets of other strategies trading the pair, such as a standing hedge.
+// Parameters are position, the signed amount in the base currency which
+// defaults to the sizing amount
+type Position struct {
+	targets TargetFunc
+}
+
+// NewPosition returns a packaged target position strategy
+func NewPosition() Strategy {
+	return &Position{}
+}
+
+// SetTargets sets the target setter the positions are emitted through
+func (x *Position) SetTargets(f TargetFunc) {
+	x.targets = f
+}
+
+// Start sets the target position of each pair
+func (x *Position) Start(_ exchange.IBotExchange, d *Definition) error {
+	if x.targets == nil {
+		return errNoTargets
+	}
+	position, err := d.FloatParam("position", d.Sizing.Amount)
+	if err != nil {
+		return err
+	}
+	pairs := d.GetPairs()
+	for i := range pairs {
+		if err = x.targets(pairs[i], position); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Stop leaves the targets in place, they are removed once the strategy is no
+// longer declared
+func (x *Position) Stop() {}
@@ -0,0 +1,287 @@
+package strategy
+
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"runtime/debug"
+	"sync"
+	"time"
+
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// Default sandbox settings
+const (
+	DefaultWatchdogInterval = time.Second
+	DefaultAccountingWindow = time.Minute
+)
+
+var (
+	// ErrSuspended is returned when running work in a suspended strategy's
+	// sandbox
+	ErrSuspended = errors.New("strategy suspended")
+
+	errPanic         = errors.New("strategy panicked")
+	errStuck         = errors.New("strategy tick handler stuck")
+	errCPULimit      = errors.New("strategy exceeded CPU limit")
+	errMemoryLimit   = errors.New("strategy exceeded memory limit")
+	errInvalidLimits = errors.New("strategy sandbox limits cannot be negative")
+)
+
+// Limits defines the resources a strategy's sandbox allows, zero disables a
+// limit. CPU and memory are accounted across tick handlers only as the runtime
+// cannot attribute usage to goroutines
+type Limits struct {
+	// TickTimeout suspends the strategy when a tick handler runs longer
+	TickTimeout string `json:"tickTimeout" yaml:"tickTimeout"`
+	// MaxCPU is the share of a core tick handlers may use over the accounting
+	// window, e.g. 0.25
+	MaxCPU float64 `json:"maxCPU" yaml:"maxCPU"`
+	// MaxAllocMB is the memory tick handlers may allocate over the accounting
+	// window. Allocations are measured from runtime memory statistics read
+	// around each handler so concurrent allocations elsewhere are included
+	MaxAllocMB float64 `json:"maxAllocMB" yaml:"maxAllocMB"`
+}
+
+func (l *Limits) validate() error {
+	if l.MaxCPU < 0 || l.MaxAllocMB < 0 {
+		return errInvalidLimits
+	}
+	if l.TickTimeout != "" {
+		if d, err := time.ParseDuration(l.TickTimeout); err != nil || d <= 0 {
+			return fmt.Errorf("%v: tick timeout %q", errInvalidLimits, l.TickTimeout)
+		}
+	}
+	return nil
+}
+
+// Sandboxed is implemented by strategies running their goroutines and tick
+// handlers through the engine's sandbox, so a panicking or stuck strategy is
+// suspended rather than taking down the bot
+type Sandboxed interface {
+	SetSandbox(s *Sandbox)
+}
+
+// SandboxStats holds a sandbox's state and resource accounting
+type SandboxStats struct {
+	Suspended   bool          `json:"suspended"`
+	Reason      string        `json:"reason,omitempty"`
+	SuspendedAt time.Time     `json:"suspendedAt,omitempty"`
+	Goroutines  int           `json:"goroutines"`
+	Ticks       int64         `json:"ticks"`
+	Panics      int64         `json:"panics"`
+	Busy        time.Duration `json:"busy"`
+	// Dropped is the number of market data events dropped while the
+	// strategy's handlers were behind
+	Dropped int64 `json:"dropped"`
+	// CPU and AllocMB are the usage over the last complete accounting window
+	CPU     float64 `json:"cpu"`
+	AllocMB float64 `json:"allocMB"`
+}
+
+// Sandbox isolates a strategy's goroutines and tick handlers, recovering
+// panics and suspending the strategy when it breaches its limits
+type Sandbox struct {
+	name        string
+	tickTimeout time.Duration
+	maxCPU      float64
+	maxAlloc    float64
+	window      time.Duration
+	onSuspend   func(reason error)
+	done        chan struct{}
+	ticks       map[uint64]time.Time
+	nextTick    uint64
+	windowStart time.Time
+	windowBusy  time.Duration
+	windowAlloc uint64
+	stats       SandboxStats
+	mtx         sync.Mutex
+}
+
+// NewSandbox returns a sandbox enforcing the limits, onSuspend is called once
+// in its own goroutine when the strategy is suspended
+func NewSandbox(name string, l Limits, onSuspend func(reason error)) (*Sandbox, error) {
+	err := l.validate()
+	if err != nil {
+		return nil, err
+	}
+	s := &Sandbox{
+		name:        name,
+		maxCPU:      l.MaxCPU,
+		maxAlloc:    l.MaxAllocMB * 1024 * 1024,
+		window:      DefaultAccountingWindow,
+		onSuspend:   onSuspend,
+		done:        make(chan struct{}),
+		ticks:       make(map[uint64]time.Time),
+		windowStart: time.Now(),
+	}
+	if l.TickTimeout != "" {
+		s.tickTimeout, _ = time.ParseDuration(l.TickTimeout)
+	}
+	return s, nil
+}
+
+// Go runs f in a goroutine of the sandbox, a panic suspends the strategy
+func (s *Sandbox) Go(f func()) error {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	if s.stats.Suspended {
+		return ErrSuspended
+	}
+	s.stats.Goroutines++
+	go func() {
+		defer func() {
+			s.mtx.Lock()
+			s.stats.Goroutines--
+			s.mtx.Unlock()
+		}()
+		defer s.recover()
+		f()
+	}()
+	return nil
+}
+
+// Tick runs a tick handler, timing it for the watchdog and accounting its
+// resource usage. A panic suspends the strategy and is returned as an error
+func (s *Sandbox) Tick(f func()) (err error) {
+	s.mtx.Lock()
+	if s.stats.Suspended {
+		s.mtx.Unlock()
+		return ErrSuspended
+	}
+	id := s.nextTick
+	s.nextTick++
+	start := time.Now()
+	s.ticks[id] = start
+	measureAlloc := s.maxAlloc > 0
+	s.mtx.Unlock()
+
+	var before runtime.MemStats
+	if measureAlloc {
+		runtime.ReadMemStats(&before)
+	}
+	defer func() {
+		var alloc uint64
+		if measureAlloc {
+			var after runtime.MemStats
+			runtime.ReadMemStats(&after)
+			alloc = after.TotalAlloc - before.TotalAlloc
+		}
+		if r := recover(); r != nil {
+			err = s.panicked(r)
+		}
+		s.account(id, start, time.Now(), alloc)
+	}()
+	f()
+	return nil
+}
+
+// drop counts a market data event dropped before reaching the strategy
+func (s *Sandbox) drop() {
+	s.mtx.Lock()
+	s.stats.Dropped++
+	s.mtx.Unlock()
+}
+
+// Done returns a channel closed when the strategy is suspended so its
+// goroutines can exit
+func (s *Sandbox) Done() <-chan struct{} {
+	return s.done
+}
+
+// Suspended returns true if the strategy has been suspended
+func (s *Sandbox) Suspended() bool {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.stats.Suspended
+}
+
+// Stats returns the sandbox's state and resource accounting
+func (s *Sandbox) Stats() SandboxStats {
+	s.mtx.Lock()
+	defer s.mtx.Unlock()
+	return s.stats
+}
+
+// recover suspends the strategy on a panic in one of its goroutines
+func (s *Sandbox) recover() {
+	if r := recover(); r != nil {
+		s.panicked(r)
+	}
+}
+
+func (s *Sandbox) panicked(r interface{}) error {
+	log.Errorf("Strategy %s panicked: %v\n%s", s.name, r, debug.Stack())
+	s.mtx.Lock()
+	s.stats.Panics++
+	s.mtx.Unlock()
+	err := fmt.Errorf("%v: %v", errPanic, r)
+	s.suspend(err)
+	return err
+}
+
+// account records a completed tick and enforces the CPU and memory limits
+// once the accounting window elapses
+func (s *Sandbox) account(id uint64, start, end time.Time, alloc uint64) {
+	s.mtx.Lock()
+	delete(s.ticks, id)
+	busy := end.Sub(start)
+	s.stats.Ticks++
+	s.stats.Busy += busy
+	s.windowBusy += busy
+	s.windowAlloc += alloc
+
+	var reason error
+	if s.maxAlloc > 0 && float64(s.windowAlloc) > s.maxAlloc {
+		reason = fmt.Errorf("%v: %.2fMB allocated", errMemoryLimit, float64(s.windowAlloc)/1024/1024)
+	}
+	if elapsed := end.Sub(s.windowStart); elapsed >= s.window {
+		s.stats.CPU = float64(s.windowBusy) / float64(elapsed)
+		s.stats.AllocMB = float64(s.windowAlloc) / 1024 / 1024
+		if reason == nil && s.maxCPU > 0 && s.stats.CPU > s.maxCPU {
+			reason = fmt.Errorf("%v: %.2f of a core", errCPULimit, s.stats.CPU)
+		}
+		s.windowStart = end
+		s.windowBusy = 0
+		s.windowAlloc = 0
+	}
+	s.mtx.Unlock()
+	if reason != nil {
+		s.suspend(reason)
+	}
+}
+
+// check suspends the strategy if a tick handler has run past its timeout
+func (s *Sandbox) check(now time.Time) {
+	s.mtx.Lock()
+	var stuck time.Duration
+	if s.tickTimeout > 0 && !s.stats.Suspended {
+		for _, start := range s.ticks {
+			if d := now.Sub(start); d > s.tickTimeout && d > stuck {
+				stuck = d
+			}
+		}
+	}
+	s.mtx.Unlock()
+	if stuck > 0 {
+		s.suspend(fmt.Errorf("%v for %s", errStuck, stuck.Truncate(time.Millisecond)))
+	}
+}
+
+// suspend marks the strategy suspended once and notifies the engine
+func (s *Sandbox) suspend(reason error) {
+	s.mtx.Lock()
+	if s.stats.Suspended {
+		s.mtx.Unlock()
+		return
+	}
+	s.stats.Suspended = true
+	s.stats.Reason = reason.Error()
+	s.stats.SuspendedAt = time.Now()
+	close(s.done)
+	s.mtx.Unlock()
+	if s.onSuspend != nil {
+		go s.onSuspend(reason)
+	}
+}
@@ -0,0 +1,787 @@
+// Package strategy runs packaged trading strategies declared in a YAML or JSON
+// strategy file. Each declaration names the strategy type, the exchange and
+// pairs it trades, its sizing and risk limits so strategies can be run without
+// writing Go code. The file is watched and strategies are started, restarted
+// or stopped as their declarations change. Strategies implementing a tick,
+// orderbook or trade handler receive the market data of their declared pairs
+// routed from the exchanges' REST pollers and websockets
+package strategy
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bookfeed"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/inventory"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/volumeprofile"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+	yaml "gopkg.in/yaml.v2"
+)
+
+// DefaultReloadInterval is the default delay between strategy file checks
+const DefaultReloadInterval = time.Second * 5
+
+var (
+	errNoPath            = errors.New("strategy file path not set")
+	errUnknownFormat     = errors.New("strategy file must be .yaml, .yml or .json")
+	errNoName            = errors.New("strategy name not set")
+	errDuplicateName     = errors.New("strategy name declared more than once")
+	errNoType            = errors.New("strategy type not set")
+	errNoExchange        = errors.New("strategy exchange not set")
+	errNoPairs           = errors.New("strategy pairs not set")
+	errInvalidSizing     = errors.New("strategy sizing cannot be negative")
+	errInvalidRisk       = errors.New("strategy risk limits cannot be negative")
+	errUnknownType       = errors.New("strategy type not registered")
+	errAlreadyRegistered = errors.New("strategy type already registered")
+	errNilExchangeFunc   = errors.New("strategy exchange func not supplied")
+	errAlreadyRunning    = errors.New("strategy engine already running")
+	errNotRunning        = errors.New("strategy engine not running")
+	errStrategyNotFound  = errors.New("strategy not running")
+	errNotSuspended      = errors.New("strategy not suspended")
+	errNoTargets         = errors.New("strategy targets not supported, netting not enabled")
+)
+
+// Sizing defines how large a strategy's orders are
+type Sizing struct {
+	// Amount is the order amount in the base currency
+	Amount float64 `json:"amount" yaml:"amount"`
+	// MaxPosition is the largest total amount the strategy trades per pair,
+	// zero is unlimited
+	MaxPosition float64 `json:"maxPosition" yaml:"maxPosition"`
+	// TargetVolatility scales Amount per pair by the target over the pair's
+	// annualized realized volatility, e.g. 0.5 for 50%. Zero disables scaling
+	TargetVolatility float64 `json:"targetVolatility" yaml:"targetVolatility"`
+	// VolatilityWindow is the realized volatility window scaled against, such
+	// as 24h
+	VolatilityWindow string `json:"volatilityWindow" yaml:"volatilityWindow"`
+}
+
+// DefaultVolatilityWindow is the realized volatility window used for
+// volatility targeted sizing when none is declared
+const DefaultVolatilityWindow = time.Hour * 24
+
+// VolatilityFunc returns the annualized realized volatility of a pair over a
+// window
+type VolatilityFunc func(exchangeName string, p currency.Pair, window time.Duration) (float64, error)
+
+// VolatilitySizer is implemented by strategies which size orders by realized
+// volatility, the engine supplies its volatility source before Start
+type VolatilitySizer interface {
+	SetVolatility(f VolatilityFunc)
+}
+
+// VolumeProfileFunc returns the volume profiles of a pair's current session
+// followed by its completed sessions, newest first
+type VolumeProfileFunc func(exchangeName string, p currency.Pair) ([]volumeprofile.Profile, error)
+
+// VolumeProfiler is implemented by strategies trading around volume profile
+// levels such as the point of control, the engine supplies its profile source
+// before Start
+type VolumeProfiler interface {
+	SetVolumeProfile(f VolumeProfileFunc)
+}
+
+// OrderbookFeedFunc subscribes to orderbook updates distributed as deltas
+type OrderbookFeedFunc func(opts bookfeed.Options) (*bookfeed.Subscription, error)
+
+// OrderbookSubscriber is implemented by strategies consuming orderbook
+// updates as they arrive, the engine supplies its orderbook feed before Start.
+// Strategies close their subscriptions when stopped
+type OrderbookSubscriber interface {
+	SetOrderbookFeed(f OrderbookFeedFunc)
+}
+
+// InventorySkewFunc returns a venue's quote of a pair skewed by where the
+// pair's base asset is held across venues
+type InventorySkewFunc func(exchangeName string, p currency.Pair, q inventory.Quote) (inventory.Quote, error)
+
+// InventorySkewer is implemented by strategies quoting on several venues at
+// once, the engine supplies its inventory skew before Start
+type InventorySkewer interface {
+	SetInventorySkew(f InventorySkewFunc)
+}
+
+// TargetFunc sets the strategy's target position in a pair in the base
+// currency, positive long and negative short. The targets of strategies
+// trading the same pair are netted before the difference is traded
+type TargetFunc func(p currency.Pair, position float64) error
+
+// Targeter is implemented by strategies emitting target positions rather than
+// placing orders, the engine supplies the strategy's target setter before
+// Start
+type Targeter interface {
+	SetTargets(f TargetFunc)
+}
+
+// ImbalanceOrderFunc starts working an imbalance order placed through the
+// exchange, returning its ID
+type ImbalanceOrderFunc func(exch exchange.IBotExchange, o *exchange.ImbalanceOrder) (string, error)
+
+// ImbalanceSubmitFunc starts working an imbalance order placed through the
+// strategy's exchange, so its orders pass through the strategy's limits
+type ImbalanceSubmitFunc func(o *exchange.ImbalanceOrder) (string, error)
+
+// ImbalanceTrader is implemented by strategies executing passively until the
+// order book imbalance turns in their favour, the engine supplies the
+// strategy's imbalance order submitter before Start. The submitter is nil when
+// imbalance orders are not enabled
+type ImbalanceTrader interface {
+	SetImbalanceOrders(f ImbalanceSubmitFunc)
+}
+
+// Targets nets the target positions of strategies. The targets of a strategy
+// no longer declared are removed
+type Targets interface {
+	SetTarget(strategy, exchangeName string, p currency.Pair, position float64) error
+	RemoveStrategy(strategy string)
+}
+
+// PairAmount returns the order amount for a pair, scaled to the target
+// volatility when declared. The declared amount is used when no volatility
+// estimate is available
+func (d *Definition) PairAmount(p currency.Pair, vol VolatilityFunc) float64 {
+	if d.Sizing.TargetVolatility <= 0 || vol == nil {
+		return d.Sizing.Amount
+	}
+	window := DefaultVolatilityWindow
+	if d.Sizing.VolatilityWindow != "" {
+		window, _ = time.ParseDuration(d.Sizing.VolatilityWindow)
+	}
+	realized, err := vol(d.Exchange, p, window)
+	if err != nil || realized <= 0 {
+		log.Warnf("Strategy %s %s volatility unavailable, using declared amount: %v", d.Name, p, err)
+		return d.Sizing.Amount
+	}
+	return d.Sizing.Amount * d.Sizing.TargetVolatility / realized
+}
+
+// Risk defines the limits orders placed by a strategy are checked against,
+// zero disables a limit
+type Risk struct {
+	MaxOrderAmount float64 `json:"maxOrderAmount" yaml:"maxOrderAmount"`
+	MaxOrderValue  float64 `json:"maxOrderValue" yaml:"maxOrderValue"`
+	MaxOpenOrders  int     `json:"maxOpenOrders" yaml:"maxOpenOrders"`
+	// AllowDuplicateOrders lets orders repeat one submitted within the
+	// duplicate order window, e.g. for iceberg slices of the same size
+	AllowDuplicateOrders bool `json:"allowDuplicateOrders" yaml:"allowDuplicateOrders"`
+}
+
+// Definition declares a strategy and its parameters
+type Definition struct {
+	Name     string   `json:"name" yaml:"name"`
+	Type     string   `json:"type" yaml:"type"`
+	Exchange string   `json:"exchange" yaml:"exchange"`
+	Pairs    []string `json:"pairs" yaml:"pairs"`
+	Disabled bool     `json:"disabled" yaml:"disabled"`
+	Sizing   Sizing   `json:"sizing" yaml:"sizing"`
+	Risk     Risk     `json:"risk" yaml:"risk"`
+	Sandbox  Limits   `json:"sandbox" yaml:"sandbox"`
+	// Params holds settings specific to the strategy type
+	Params map[string]interface{} `json:"params" yaml:"params"`
+}
+
+// File defines the contents of a strategy file
+type File struct {
+	Strategies []Definition `json:"strategies" yaml:"strategies"`
+}
+
+func (d *Definition) validate() error {
+	switch {
+	case d.Name == "":
+		return errNoName
+	case d.Type == "":
+		return fmt.Errorf("%s %v", d.Name, errNoType)
+	case d.Exchange == "":
+		return fmt.Errorf("%s %v", d.Name, errNoExchange)
+	case len(d.Pairs) == 0:
+		return fmt.Errorf("%s %v", d.Name, errNoPairs)
+	case d.Sizing.Amount < 0 || d.Sizing.MaxPosition < 0 || d.Sizing.TargetVolatility < 0:
+		return fmt.Errorf("%s %v", d.Name, errInvalidSizing)
+	case d.Risk.MaxOrderAmount < 0 || d.Risk.MaxOrderValue < 0 || d.Risk.MaxOpenOrders < 0:
+		return fmt.Errorf("%s %v", d.Name, errInvalidRisk)
+	}
+	if err := d.Sandbox.validate(); err != nil {
+		return fmt.Errorf("%s %v", d.Name, err)
+	}
+	if d.Sizing.VolatilityWindow != "" {
+		if w, err := time.ParseDuration(d.Sizing.VolatilityWindow); err != nil || w <= 0 {
+			return fmt.Errorf("%s %v: volatility window %q", d.Name, errInvalidSizing, d.Sizing.VolatilityWindow)
+		}
+	}
+	return nil
+}
+
+// GetPairs returns the declared pairs
+func (d *Definition) GetPairs() []currency.Pair {
+	pairs := make([]currency.Pair, len(d.Pairs))
+	for i := range d.Pairs {
+		pairs[i] = currency.NewPairFromString(d.Pairs[i])
+	}
+	return pairs
+}
+
+// Param returns a parameter as a string and whether it was set
+func (d *Definition) Param(key string) (string, bool) {
+	v, ok := d.Params[key]
+	if !ok || v == nil {
+		return "", false
+	}
+	return fmt.Sprint(v), true
+}
+
+// FloatParam returns a numeric parameter, or the default when unset
+func (d *Definition) FloatParam(key string, def float64) (float64, error) {
+	v, ok := d.Param(key)
+	if !ok {
+		return def, nil
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil {
+		return 0, fmt.Errorf("%s param %s: %v", d.Name, key, err)
+	}
+	return f, nil
+}
+
+// IntParam returns an integer parameter, or the default when unset
+func (d *Definition) IntParam(key string, def int) (int, error) {
+	v, ok := d.Param(key)
+	if !ok {
+		return def, nil
+	}
+	i, err := strconv.Atoi(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s param %s: %v", d.Name, key, err)
+	}
+	return i, nil
+}
+
+// DurationParam returns a duration parameter such as 30s, or the default when
+// unset
+func (d *Definition) DurationParam(key string, def time.Duration) (time.Duration, error) {
+	v, ok := d.Param(key)
+	if !ok {
+		return def, nil
+	}
+	t, err := time.ParseDuration(v)
+	if err != nil {
+		return 0, fmt.Errorf("%s param %s: %v", d.Name, key, err)
+	}
+	return t, nil
+}
+
+// Load reads and validates the strategy file at path, the format is chosen by
+// the file extension
+func Load(path string) (*File, error) {
+	if path == "" {
+		return nil, errNoPath
+	}
+	data, err := common.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var f File
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		err = yaml.UnmarshalStrict(data, &f)
+	case ".json":
+		err = json.Unmarshal(data, &f)
+	default:
+		return nil, errUnknownFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err = f.validate(); err != nil {
+		return nil, err
+	}
+	return &f, nil
+}
+
+func (f *File) validate() error {
+	names := make(map[string]bool)
+	for i := range f.Strategies {
+		err := f.Strategies[i].validate()
+		if err != nil {
+			return err
+		}
+		if names[f.Strategies[i].Name] {
+			return fmt.Errorf("%s %v", f.Strategies[i].Name, errDuplicateName)
+		}
+		names[f.Strategies[i].Name] = true
+	}
+	return nil
+}
+
+// Strategy is implemented by packaged strategies. Start must not block, the
+// strategy trades until Stop is called
+type Strategy interface {
+	Start(e exchange.IBotExchange, d *Definition) error
+	Stop()
+}
+
+// Factory returns a new instance of a strategy type
+type Factory func() Strategy
+
+// ExchangeFunc returns the exchange a strategy trades through
+type ExchangeFunc func(exchangeName, strategy string) (exchange.IBotExchange, error)
+
+type instance struct {
+	def      Definition
+	pairs    []currency.Pair
+	strategy Strategy
+	sandbox  *Sandbox
+	// events queues market data for strategies implementing a handler
+	events  chan func()
+	quit    chan struct{}
+	stopped bool
+	mtx     sync.Mutex
+}
+
+// stop stops the strategy once without waiting on a stop already in progress,
+// which may never return for a stuck strategy
+func (i *instance) stop() {
+	i.mtx.Lock()
+	if i.stopped {
+		i.mtx.Unlock()
+		return
+	}
+	i.stopped = true
+	if i.quit != nil {
+		close(i.quit)
+	}
+	i.mtx.Unlock()
+	i.strategy.Stop()
+}
+
+// Engine runs the strategies declared in a strategy file
+type Engine struct {
+	path       string
+	exchange   ExchangeFunc
+	volatility VolatilityFunc
+	profiles   VolumeProfileFunc
+	books      OrderbookFeedFunc
+	skew       InventorySkewFunc
+	fills      FillFunc
+	targets    Targets
+	imbalance  ImbalanceOrderFunc
+	factories  map[string]Factory
+	running    map[string]*instance
+	modified   time.Time
+	shutdown   chan struct{}
+	wg         sync.WaitGroup
+	mtx        sync.Mutex
+}
+
+// NewEngine returns an engine for the strategy file, strategies trade through
+// the exchanges returned by exch
+func NewEngine(path string, exch ExchangeFunc) (*Engine, error) {
+	if path == "" {
+		return nil, errNoPath
+	}
+	if exch == nil {
+		return nil, errNilExchangeFunc
+	}
+	return &Engine{
+		path:      path,
+		exchange:  exch,
+		factories: make(map[string]Factory),
+		running:   make(map[string]*instance),
+	}, nil
+}
+
+// Register adds a packaged strategy type which can be declared in the
+// strategy file
+func (e *Engine) Register(strategyType string, f Factory) error {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	k := strings.ToLower(strategyType)
+	if _, ok := e.factories[k]; ok {
+		return fmt.Errorf("%s %v", strategyType, errAlreadyRegistered)
+	}
+	e.factories[k] = f
+	return nil
+}
+
+// SetVolatility sets the realized volatility source supplied to strategies
+// which size orders by volatility
+func (e *Engine) SetVolatility(f VolatilityFunc) {
+	e.mtx.Lock()
+	e.volatility = f
+	e.mtx.Unlock()
+}
+
+// SetVolumeProfile sets the volume profile source supplied to strategies
+// trading around volume profile levels
+func (e *Engine) SetVolumeProfile(f VolumeProfileFunc) {
+	e.mtx.Lock()
+	e.profiles = f
+	e.mtx.Unlock()
+}
+
+// SetOrderbookFeed sets the orderbook feed supplied to strategies consuming
+// orderbook updates
+func (e *Engine) SetOrderbookFeed(f OrderbookFeedFunc) {
+	e.mtx.Lock()
+	e.books = f
+	e.mtx.Unlock()
+}
+
+// SetInventorySkew sets the inventory skew supplied to strategies quoting on
+// several venues
+func (e *Engine) SetInventorySkew(f InventorySkewFunc) {
+	e.mtx.Lock()
+	e.skew = f
+	e.mtx.Unlock()
+}
+
+// SetFills sets the receiver of fills observed on strategy orders
+func (e *Engine) SetFills(f FillFunc) {
+	e.mtx.Lock()
+	e.fills = f
+	e.mtx.Unlock()
+}
+
+// SetTargets sets the netting of target positions emitted by strategies
+func (e *Engine) SetTargets(t Targets) {
+	e.mtx.Lock()
+	e.targets = t
+	e.mtx.Unlock()
+}
+
+// SetImbalanceOrders sets the imbalance order manager strategies submit
+// imbalance orders to
+func (e *Engine) SetImbalanceOrders(f ImbalanceOrderFunc) {
+	e.mtx.Lock()
+	e.imbalance = f
+	e.mtx.Unlock()
+}
+
+// imbalanceFunc returns the imbalance order submitter of a strategy trading
+// through the exchange
+func (e *Engine) imbalanceFunc(exch exchange.IBotExchange) ImbalanceSubmitFunc {
+	if e.imbalance == nil {
+		return nil
+	}
+	f := e.imbalance
+	return func(o *exchange.ImbalanceOrder) (string, error) {
+		return f(exch, o)
+	}
+}
+
+// targetFunc returns the target setter of a strategy, limited to its declared
+// maximum position
+func (e *Engine) targetFunc(d *Definition) TargetFunc {
+	if e.targets == nil {
+		return nil
+	}
+	t, name, exchName, limit := e.targets, d.Name, d.Exchange, d.Sizing.MaxPosition
+	return func(p currency.Pair, position float64) error {
+		if limit > 0 && math.Abs(position) > limit {
+			return fmt.Errorf("%s %v: %s target %v exceeds %v", name, ErrRiskLimit, p, position, limit)
+		}
+		return t.SetTarget(name, exchName, p, position)
+	}
+}
+
+// Reload reads the strategy file and applies it. Strategies no longer declared
+// or disabled are stopped, changed strategies are restarted and new
+// strategies are started. An invalid file leaves running strategies untouched
+func (e *Engine) Reload() error {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return err
+	}
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	// Invalid files are not retried until modified again
+	e.modified = info.ModTime()
+	f, err := Load(e.path)
+	if err != nil {
+		return err
+	}
+	for i := range f.Strategies {
+		if f.Strategies[i].Disabled {
+			continue
+		}
+		if _, ok := e.factories[strings.ToLower(f.Strategies[i].Type)]; !ok {
+			return fmt.Errorf("%s %s %v", f.Strategies[i].Name, f.Strategies[i].Type, errUnknownType)
+		}
+	}
+
+	declared := make(map[string]*Definition)
+	for i := range f.Strategies {
+		if !f.Strategies[i].Disabled {
+			declared[f.Strategies[i].Name] = &f.Strategies[i]
+		}
+	}
+	for name, r := range e.running {
+		d, ok := declared[name]
+		if ok && reflect.DeepEqual(*d, r.def) {
+			// Suspended strategies stay suspended until resumed or changed
+			continue
+		}
+		r.stop()
+		delete(e.running, name)
+		if !ok && e.targets != nil {
+			e.targets.RemoveStrategy(name)
+		}
+		log.Debugf("Strategy %s stopped.", name)
+	}
+
+	var errs []string
+	for i := range f.Strategies {
+		d := f.Strategies[i]
+		if d.Disabled {
+			continue
+		}
+		if _, ok := e.running[d.Name]; ok {
+			continue
+		}
+		err = e.start(&d)
+		if err != nil {
+			errs = append(errs, fmt.Sprintf("%s: %v", d.Name, err))
+			continue
+		}
+		log.Debugf("Strategy %s started, %s on %s %s.", d.Name, d.Type, d.Exchange, strings.Join(d.Pairs, ","))
+	}
+	if len(errs) > 0 {
+		return errors.New(strings.Join(errs, ", "))
+	}
+	return nil
+}
+
+// start starts a strategy, the caller must hold the lock
+func (e *Engine) start(d *Definition) error {
+	exch, err := e.exchange(d.Exchange, d.Name)
+	if err != nil {
+		return err
+	}
+	s := e.factories[strings.ToLower(d.Type)]()
+	if v, ok := s.(VolatilitySizer); ok {
+		v.SetVolatility(e.volatility)
+	}
+	if v, ok := s.(VolumeProfiler); ok {
+		v.SetVolumeProfile(e.profiles)
+	}
+	if v, ok := s.(OrderbookSubscriber); ok {
+		v.SetOrderbookFeed(e.books)
+	}
+	if v, ok := s.(InventorySkewer); ok {
+		v.SetInventorySkew(e.skew)
+	}
+	if v, ok := s.(Targeter); ok {
+		v.SetTargets(e.targetFunc(d))
+	}
+	r := &instance{def: *d, pairs: d.GetPairs(), strategy: s}
+	r.sandbox, err = NewSandbox(d.Name, d.Sandbox, func(reason error) {
+		e.suspended(r, reason)
+	})
+	if err != nil {
+		return err
+	}
+	if v, ok := s.(Sandboxed); ok {
+		v.SetSandbox(r.sandbox)
+	}
+	exch = NewLimited(exch, d)
+	if e.fills != nil {
+		exch = NewRecorded(exch, d.Name, e.fills)
+	}
+	exch = NewTagged(exch, d.Name)
+	if v, ok := s.(ImbalanceTrader); ok {
+		v.SetImbalanceOrders(e.imbalanceFunc(exch))
+	}
+	err = startRecovered(s, exch, d)
+	if err != nil {
+		return err
+	}
+	if handlesEvents(s) {
+		r.events = make(chan func(), DefaultEventBuffer)
+		r.quit = make(chan struct{})
+		go r.deliver(r.events, r.quit)
+	}
+	e.running[d.Name] = r
+	return nil
+}
+
+// startRecovered starts the strategy, returning a panic as an error
+func startRecovered(s Strategy, exch exchange.IBotExchange, d *Definition) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("%v: %v", errPanic, r)
+		}
+	}()
+	return s.Start(exch, d)
+}
+
+// suspended stops a strategy suspended by its sandbox, leaving it declared as
+// running so reloading an unchanged strategy file does not restart it
+func (e *Engine) suspended(r *instance, reason error) {
+	e.mtx.Lock()
+	current := e.running[r.def.Name] == r
+	e.mtx.Unlock()
+	if !current {
+		return
+	}
+	log.Errorf("Strategy %s suspended: %s", r.def.Name, reason)
+	r.stop()
+}
+
+// Resume restarts a suspended strategy with its declaration
+func (e *Engine) Resume(name string) error {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	r, ok := e.running[name]
+	if !ok {
+		return fmt.Errorf("%s %v", name, errStrategyNotFound)
+	}
+	if !r.sandbox.Suspended() {
+		return fmt.Errorf("%s %v", name, errNotSuspended)
+	}
+	// A stuck strategy may never stop, its sandbox already refuses new work
+	go r.stop()
+	delete(e.running, name)
+	d := r.def
+	err := e.start(&d)
+	if err != nil {
+		return err
+	}
+	log.Debugf("Strategy %s resumed.", name)
+	return nil
+}
+
+// GetSandboxStats returns the sandbox state of each running strategy
+func (e *Engine) GetSandboxStats() map[string]SandboxStats {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	resp := make(map[string]SandboxStats, len(e.running))
+	for name, r := range e.running {
+		resp[name] = r.sandbox.Stats()
+	}
+	return resp
+}
+
+// checkSandboxes suspends strategies with stuck tick handlers
+func (e *Engine) checkSandboxes(now time.Time) {
+	e.mtx.Lock()
+	boxes := make([]*Sandbox, 0, len(e.running))
+	for _, r := range e.running {
+		boxes = append(boxes, r.sandbox)
+	}
+	e.mtx.Unlock()
+	for i := range boxes {
+		boxes[i].check(now)
+	}
+}
+
+// GetRunning returns the definitions of running strategies sorted by name
+func (e *Engine) GetRunning() []Definition {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	defs := make([]Definition, 0, len(e.running))
+	for _, r := range e.running {
+		defs = append(defs, r.def)
+	}
+	sort.Slice(defs, func(i, j int) bool { return defs[i].Name < defs[j].Name })
+	return defs
+}
+
+// changed returns true if the strategy file has been modified since it was
+// last loaded
+func (e *Engine) changed() bool {
+	info, err := os.Stat(e.path)
+	if err != nil {
+		return false
+	}
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	return !info.ModTime().Equal(e.modified)
+}
+
+// Start watches the strategy file, reloading it when modified
+func (e *Engine) Start(interval time.Duration) error {
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	if e.shutdown != nil {
+		return errAlreadyRunning
+	}
+	if interval <= 0 {
+		interval = DefaultReloadInterval
+	}
+	e.shutdown = make(chan struct{})
+	e.wg.Add(2)
+	go e.watch(interval, e.shutdown)
+	go e.watchdog(DefaultWatchdogInterval, e.shutdown)
+	return nil
+}
+
+// watchdog periodically checks strategy sandboxes for stuck tick handlers
+func (e *Engine) watchdog(interval time.Duration, shutdown chan struct{}) {
+	defer e.wg.Done()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-shutdown:
+			return
+		case now := <-t.C:
+			e.checkSandboxes(now)
+		}
+	}
+}
+
+func (e *Engine) watch(interval time.Duration, shutdown chan struct{}) {
+	defer e.wg.Done()
+	t := time.NewTicker(interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-shutdown:
+			return
+		case <-t.C:
+			if !e.changed() {
+				continue
+			}
+			log.Debugf("Strategy file %s modified, reloading.", e.path)
+			err := e.Reload()
+			if err != nil {
+				log.Errorf("Strategy file %s reload failed: %s", e.path, err)
+			}
+		}
+	}
+}
+
+// Stop stops watching the strategy file and stops all running strategies
+func (e *Engine) Stop() error {
+	e.mtx.Lock()
+	if e.shutdown == nil {
+		e.mtx.Unlock()
+		return errNotRunning
+	}
+	close(e.shutdown)
+	e.shutdown = nil
+	e.mtx.Unlock()
+	e.wg.Wait()
+
+	e.mtx.Lock()
+	defer e.mtx.Unlock()
+	for name, r := range e.running {
+		r.stop()
+		delete(e.running, name)
+	}
+	return nil
+}
@@ -0,0 +1,717 @@
+package strategy
+
+import (
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+)
+
+const testYAML = `strategies:
+  - name: accumulate
+    type: twap
+    exchange: Bitmex
+    pairs: [BTC-USD]
+    sizing:
+      amount: 1
+      maxPosition: 2
+    risk:
+      maxOrderAmount: 0.5
+    params:
+      slices: 4
+      interval: 30s
+  - name: idle
+    type: unknown
+    exchange: OKEX
+    pairs: [ETH-USD]
+    disabled: true
+`
+
+const testJSON = `{"strategies": [{"name": "accumulate", "type": "TWAP", "exchange": "Bitmex",
+	"pairs": ["BTC-USD"], "sizing": {"amount": 1}, "params": {"slices": 4, "price": "100.5"}}]}`
+
+type testStrategy struct {
+	started, stopped int
+	err              error
+}
+
+func (t *testStrategy) Start(_ exchange.IBotExchange, _ *Definition) error {
+	t.started++
+	return t.err
+}
+
+func (t *testStrategy) Stop() { t.stopped++ }
+
+type testExchange struct {
+	exchange.IBotExchange
+	open      int
+	submitted float64
+}
+
+func (t *testExchange) GetActiveOrders(_ *exchange.GetOrdersRequest) ([]exchange.OrderDetail, error) {
+	return make([]exchange.OrderDetail, t.open), nil
+}
+
+func (t *testExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, amount, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	t.submitted += amount
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func writeFile(t *testing.T, dir, name, data string) string {
+	path := filepath.Join(dir, name)
+	err := ioutil.WriteFile(path, []byte(data), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func tempDir(t *testing.T) string {
+	dir, err := ioutil.TempDir("", "strategy")
+	if err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestLoad(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+
+	f, err := Load(writeFile(t, dir, "strategies.yaml", testYAML))
+	if err != nil {
+		t.Fatal("Test Failed - Load() yaml error", err)
+	}
+	if len(f.Strategies) != 2 || !f.Strategies[1].Disabled {
+		t.Fatalf("Test Failed - Load() unexpected strategies %+v", f.Strategies)
+	}
+	d := &f.Strategies[0]
+	if d.Sizing.Amount != 1 || d.Sizing.MaxPosition != 2 || d.Risk.MaxOrderAmount != 0.5 {
+		t.Errorf("Test Failed - Load() unexpected limits %+v %+v", d.Sizing, d.Risk)
+	}
+	if slices, err := d.IntParam("slices", 0); err != nil || slices != 4 {
+		t.Error("Test Failed - IntParam() expected 4", slices, err)
+	}
+	if interval, err := d.DurationParam("interval", 0); err != nil || interval != time.Second*30 {
+		t.Error("Test Failed - DurationParam() expected 30s", interval, err)
+	}
+	if _, err = d.FloatParam("interval", 0); err == nil {
+		t.Error("Test Failed - FloatParam() expected parse error")
+	}
+	if p := d.GetPairs(); len(p) != 1 || p[0].String() != "BTC-USD" {
+		t.Errorf("Test Failed - GetPairs() unexpected pairs %v", p)
+	}
+
+	f, err = Load(writeFile(t, dir, "strategies.json", testJSON))
+	if err != nil {
+		t.Fatal("Test Failed - Load() json error", err)
+	}
+	if price, err := f.Strategies[0].FloatParam("price", 0); err != nil || price != 100.5 {
+		t.Error("Test Failed - FloatParam() expected 100.5", price, err)
+	}
+	if slices, _ := f.Strategies[0].IntParam("slices", 0); slices != 4 {
+		t.Error("Test Failed - IntParam() expected 4 from json", slices)
+	}
+
+	if _, err = Load(writeFile(t, dir, "strategies.txt", testJSON)); err != errUnknownFormat {
+		t.Error("Test Failed - Load() expected unknown format error", err)
+	}
+	dup := strings.Replace(testYAML, "name: idle", "name: accumulate", 1)
+	if _, err = Load(writeFile(t, dir, "dup.yml", dup)); err == nil || !strings.Contains(err.Error(), errDuplicateName.Error()) {
+		t.Error("Test Failed - Load() expected duplicate name error", err)
+	}
+	unknown := strings.Replace(testYAML, "maxPosition", "maxPositon", 1)
+	if _, err = Load(writeFile(t, dir, "unknown.yml", unknown)); err == nil {
+		t.Error("Test Failed - Load() expected unknown field error")
+	}
+}
+
+func TestPairAmount(t *testing.T) {
+	d := Definition{Name: "vol", Exchange: "Bitmex", Sizing: Sizing{Amount: 2, TargetVolatility: 0.4}}
+	p := currency.NewPairWithDelimiter("BTC", "USD", "-")
+	var window time.Duration
+	vol := func(_ string, _ currency.Pair, w time.Duration) (float64, error) {
+		window = w
+		return 0.8, nil
+	}
+	if a := d.PairAmount(p, vol); a != 1 || window != DefaultVolatilityWindow {
+		t.Errorf("Test Failed - PairAmount() expected 1 over the default window, got %v %s", a, window)
+	}
+	d.Sizing.VolatilityWindow = "168h"
+	if d.PairAmount(p, vol); window != time.Hour*168 {
+		t.Errorf("Test Failed - PairAmount() expected declared window, got %s", window)
+	}
+	unavailable := func(string, currency.Pair, time.Duration) (float64, error) {
+		return 0, errors.New("not tracked")
+	}
+	if a := d.PairAmount(p, unavailable); a != 2 {
+		t.Errorf("Test Failed - PairAmount() expected declared amount, got %v", a)
+	}
+
+	d.Pairs = []string{"BTC-USD"}
+	d.Type = "twap"
+	d.Sizing.VolatilityWindow = "weekly"
+	if err := d.validate(); err == nil {
+		t.Error("Test Failed - validate() expected invalid volatility window error")
+	}
+}
+
+func TestEngineReload(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "strategies.yaml", testYAML)
+
+	var exchanges []string
+	e, err := NewEngine(path, func(exchName, strategy string) (exchange.IBotExchange, error) {
+		exchanges = append(exchanges, exchName+"/"+strategy)
+		return &testExchange{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var instances []*testStrategy
+	err = e.Register("TWAP", func() Strategy {
+		s := &testStrategy{}
+		instances = append(instances, s)
+		return s
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = e.Register("twap", nil); err == nil {
+		t.Error("Test Failed - Register() expected already registered error")
+	}
+
+	if err = e.Reload(); err != nil {
+		t.Fatal("Test Failed - Reload() error", err)
+	}
+	if len(instances) != 1 || instances[0].started != 1 || exchanges[0] != "Bitmex/accumulate" {
+		t.Fatalf("Test Failed - Reload() expected strategy started, got %v %v", instances, exchanges)
+	}
+	if r := e.GetRunning(); len(r) != 1 || r[0].Name != "accumulate" {
+		t.Errorf("Test Failed - GetRunning() unexpected %+v", r)
+	}
+
+	// Unchanged declarations keep running
+	if err = e.Reload(); err != nil || len(instances) != 1 {
+		t.Error("Test Failed - Reload() expected unchanged strategy to keep running", err)
+	}
+
+	// Enabling an unregistered type leaves running strategies untouched
+	writeFile(t, dir, "strategies.yaml", strings.Replace(testYAML, "disabled: true", "disabled: false", 1))
+	if err = e.Reload(); err == nil || instances[0].stopped != 0 {
+		t.Error("Test Failed - Reload() expected unknown type error", err)
+	}
+
+	// Changed declarations are restarted
+	writeFile(t, dir, "strategies.yaml", strings.Replace(testYAML, "slices: 4", "slices: 8", 1))
+	if err = e.Reload(); err != nil {
+		t.Fatal("Test Failed - Reload() error", err)
+	}
+	if len(instances) != 2 || instances[0].stopped != 1 || instances[1].started != 1 {
+		t.Error("Test Failed - Reload() expected changed strategy restarted")
+	}
+
+	// Removed declarations are stopped
+	writeFile(t, dir, "strategies.yaml", "strategies: []\n")
+	if err = e.Reload(); err != nil || instances[1].stopped != 1 || len(e.GetRunning()) != 0 {
+		t.Error("Test Failed - Reload() expected removed strategy stopped", err)
+	}
+
+	if err = e.Stop(); err != errNotRunning {
+		t.Error("Test Failed - Stop() expected not running error", err)
+	}
+	if err = e.Start(time.Millisecond); err != nil {
+		t.Fatal(err)
+	}
+	if err = e.Start(time.Millisecond); err != errAlreadyRunning {
+		t.Error("Test Failed - Start() expected already running error", err)
+	}
+	writeFile(t, dir, "strategies.yaml", testYAML)
+	os.Chtimes(path, time.Now().Add(time.Minute), time.Now().Add(time.Minute))
+	for i := 0; i < 100 && len(e.GetRunning()) == 0; i++ {
+		time.Sleep(time.Millisecond * 10)
+	}
+	if len(e.GetRunning()) != 1 {
+		t.Error("Test Failed - Start() expected modified file to be reloaded")
+	}
+	if err = e.Stop(); err != nil || instances[2].stopped != 1 {
+		t.Error("Test Failed - Stop() expected running strategies stopped", err)
+	}
+}
+
+func TestEngineStartError(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "strategies.yaml", testYAML)
+
+	e, _ := NewEngine(path, func(_, _ string) (exchange.IBotExchange, error) {
+		return nil, errors.New("exchange not found")
+	})
+	e.Register("twap", func() Strategy { return &testStrategy{} })
+	if err := e.Reload(); err == nil || len(e.GetRunning()) != 0 {
+		t.Error("Test Failed - Reload() expected exchange error", err)
+	}
+}
+
+func TestLimited(t *testing.T) {
+	x := &testExchange{}
+	l := NewLimited(x, &Definition{
+		Name:   "test",
+		Sizing: Sizing{MaxPosition: 1},
+		Risk:   Risk{MaxOrderAmount: 0.6, MaxOrderValue: 50, MaxOpenOrders: 2},
+	})
+	p := currency.NewPair(currency.BTC, currency.USD)
+	submit := func(amount, price float64) error {
+		_, err := l.SubmitOrder(p, exchange.BuyOrderSide, exchange.LimitOrderType, amount, price, "")
+		return err
+	}
+	if err := submit(0.7, 10); err == nil {
+		t.Error("Test Failed - SubmitOrder() expected max order amount error")
+	}
+	if err := submit(0.5, 200); err == nil {
+		t.Error("Test Failed - SubmitOrder() expected max order value error")
+	}
+	if err := submit(0.5, 0); err != nil {
+		t.Error("Test Failed - SubmitOrder() error", err)
+	}
+	if err := submit(0.6, 0); err == nil {
+		t.Error("Test Failed - SubmitOrder() expected max position error")
+	}
+	x.open = 2
+	if err := submit(0.1, 0); err == nil {
+		t.Error("Test Failed - SubmitOrder() expected max open orders error")
+	}
+	if x.submitted != 0.5 {
+		t.Errorf("Test Failed - SubmitOrder() expected 0.5 submitted, got %v", x.submitted)
+	}
+	if exchange.Underlying(l) != x {
+		t.Error("Test Failed - Unwrap() expected underlying exchange")
+	}
+}
+
+type tagExchange struct {
+	exchange.IBotExchange
+	tags   []string
+	errors int
+}
+
+func (t *tagExchange) RecordRequestTag(tag string, err error) {
+	t.tags = append(t.tags, tag)
+	if err != nil {
+		t.errors++
+	}
+}
+
+func (t *tagExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true}, nil
+}
+
+func (t *tagExchange) CancelOrder(_ *exchange.OrderCancellation) error {
+	return errors.New("order not found")
+}
+
+func TestTagged(t *testing.T) {
+	x := &tagExchange{}
+	e := NewTagged(x, "twap-btc")
+	_, err := e.SubmitOrder(currency.NewPair(currency.BTC, currency.USD), exchange.BuyOrderSide, exchange.LimitOrderType, 1, 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = e.CancelOrder(&exchange.OrderCancellation{}); err == nil {
+		t.Fatal("Test Failed - CancelOrder() expected error")
+	}
+	if len(x.tags) != 2 || x.tags[0] != "strategy/twap-btc" || x.tags[1] != "strategy/twap-btc" || x.errors != 1 {
+		t.Errorf("Test Failed - Tagged expected calls recorded under the strategy, got %v", x.tags)
+	}
+	if exchange.Underlying(e) != x {
+		t.Error("Test Failed - Unwrap() expected underlying exchange")
+	}
+}
+
+type fillExchange struct {
+	exchange.IBotExchange
+	executed float64
+}
+
+func (f *fillExchange) GetName() string { return "test" }
+
+func (f *fillExchange) SubmitOrder(_ currency.Pair, _ exchange.OrderSide, _ exchange.OrderType, _, _ float64, _ string) (exchange.SubmitOrderResponse, error) {
+	return exchange.SubmitOrderResponse{IsOrderPlaced: true, OrderID: "1"}, nil
+}
+
+func (f *fillExchange) GetOrderInfo(orderID string) (exchange.OrderDetail, error) {
+	return exchange.OrderDetail{ID: orderID, ExecutedAmount: f.executed}, nil
+}
+
+func TestRecorded(t *testing.T) {
+	x := &fillExchange{}
+	var fills []Fill
+	r := NewRecorded(x, "maker", func(f *Fill) {
+		fills = append(fills, *f)
+	})
+	_, err := r.SubmitOrder(currency.NewPair(currency.BTC, currency.USD), exchange.SellOrderSide, exchange.LimitOrderType, 2, 100, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, executed := range []float64{0, 0.5, 0.5, 2, 2} {
+		x.executed = executed
+		if _, err = r.GetOrderInfo("1"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	if len(fills) != 2 || fills[0].Amount != 0.5 || fills[1].Amount != 1.5 {
+		t.Fatalf("Test Failed - Recorded expected fills of 0.5 and 1.5, got %v", fills)
+	}
+	if fills[0].Strategy != "maker" || fills[0].Exchange != "test" ||
+		fills[0].Price != 100 || fills[0].Side != exchange.SellOrderSide {
+		t.Errorf("Test Failed - Recorded unexpected fill %v", fills[0])
+	}
+	if len(r.orders) != 0 {
+		t.Error("Test Failed - Recorded expected filled order forgotten")
+	}
+}
+
+type sandboxedStrategy struct {
+	testStrategy
+	sandbox *Sandbox
+	stop    chan struct{}
+}
+
+func (s *sandboxedStrategy) SetSandbox(sb *Sandbox) { s.sandbox = sb }
+
+func (s *sandboxedStrategy) Stop() { close(s.stop) }
+
+func TestSandbox(t *testing.T) {
+	if _, err := NewSandbox("test", Limits{TickTimeout: "-1s"}, nil); err == nil {
+		t.Error("Test Failed - NewSandbox() expected invalid tick timeout error")
+	}
+
+	suspended := make(chan error, 1)
+	s, err := NewSandbox("test", Limits{TickTimeout: "1s"}, func(reason error) {
+		suspended <- reason
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = s.Tick(func() {}); err != nil {
+		t.Fatal(err)
+	}
+
+	// A tick handler running past its timeout is detected by the watchdog
+	s.mtx.Lock()
+	s.ticks[s.nextTick] = time.Now().Add(-time.Second * 2)
+	s.mtx.Unlock()
+	s.check(time.Now())
+	select {
+	case reason := <-suspended:
+		if !strings.Contains(reason.Error(), errStuck.Error()) {
+			t.Errorf("Test Failed - check() expected stuck reason, received %v", reason)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Test Failed - check() expected stuck tick to suspend the strategy")
+	}
+	select {
+	case <-s.Done():
+	default:
+		t.Error("Test Failed - Done() expected closed on suspension")
+	}
+	if err = s.Tick(func() {}); err != ErrSuspended {
+		t.Errorf("Test Failed - Tick() expected ErrSuspended, received %v", err)
+	}
+	if err = s.Go(func() {}); err != ErrSuspended {
+		t.Errorf("Test Failed - Go() expected ErrSuspended, received %v", err)
+	}
+	if stats := s.Stats(); !stats.Suspended || stats.Ticks != 1 || stats.Reason == "" {
+		t.Errorf("Test Failed - Stats() unexpected %+v", stats)
+	}
+
+	// Panics are recovered and suspend the strategy
+	s, _ = NewSandbox("test", Limits{}, nil)
+	if err = s.Tick(func() { panic("tick") }); err == nil || !s.Suspended() {
+		t.Errorf("Test Failed - Tick() expected panic to suspend, received %v", err)
+	}
+	s, _ = NewSandbox("test", Limits{}, nil)
+	if err = s.Go(func() { panic("goroutine") }); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-s.Done():
+	case <-time.After(time.Second):
+		t.Fatal("Test Failed - Go() expected panic to suspend the strategy")
+	}
+	if stats := s.Stats(); stats.Panics != 1 || stats.Goroutines != 0 {
+		t.Errorf("Test Failed - Stats() unexpected %+v", stats)
+	}
+
+	// CPU usage is enforced once the accounting window elapses
+	s, _ = NewSandbox("test", Limits{MaxCPU: 0.5}, nil)
+	s.window = time.Millisecond * 20
+	if err = s.Tick(func() { time.Sleep(time.Millisecond * 30) }); err != nil {
+		t.Fatal(err)
+	}
+	if stats := s.Stats(); !stats.Suspended || !strings.Contains(stats.Reason, errCPULimit.Error()) {
+		t.Errorf("Test Failed - Tick() expected CPU limit suspension %+v", stats)
+	}
+}
+
+func TestEngineSandbox(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "strategies.yaml", testYAML)
+
+	e, err := NewEngine(path, func(_, _ string) (exchange.IBotExchange, error) {
+		return &testExchange{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var instances []*sandboxedStrategy
+	e.Register("twap", func() Strategy {
+		s := &sandboxedStrategy{stop: make(chan struct{})}
+		instances = append(instances, s)
+		return s
+	})
+	if err = e.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 1 || instances[0].sandbox == nil {
+		t.Fatal("Test Failed - Reload() expected sandbox set")
+	}
+	if err = e.Resume("accumulate"); err == nil || !strings.Contains(err.Error(), errNotSuspended.Error()) {
+		t.Errorf("Test Failed - Resume() expected not suspended error, received %v", err)
+	}
+	if err = e.Resume("missing"); err == nil {
+		t.Error("Test Failed - Resume() expected not found error")
+	}
+
+	instances[0].sandbox.Tick(func() { panic("tick") })
+	select {
+	case <-instances[0].stop:
+	case <-time.After(time.Second):
+		t.Fatal("Test Failed - Tick() expected panicking strategy stopped")
+	}
+	if !e.GetSandboxStats()["accumulate"].Suspended {
+		t.Error("Test Failed - GetSandboxStats() expected strategy suspended")
+	}
+
+	// Suspended strategies are not restarted by reloading an unchanged file
+	if err = e.Reload(); err != nil || len(instances) != 1 {
+		t.Error("Test Failed - Reload() expected suspended strategy to stay suspended", err)
+	}
+	if err = e.Resume("accumulate"); err != nil {
+		t.Fatal(err)
+	}
+	if len(instances) != 2 || instances[1].started != 1 || e.GetSandboxStats()["accumulate"].Suspended {
+		t.Error("Test Failed - Resume() expected strategy restarted")
+	}
+}
+
+type testTargets struct {
+	targets map[string]float64
+}
+
+func (t *testTargets) SetTarget(strategy, exchName string, p currency.Pair, position float64) error {
+	t.targets[strategy+"/"+exchName+"/"+p.String()] = position
+	return nil
+}
+
+func (t *testTargets) RemoveStrategy(strategy string) {
+	for k := range t.targets {
+		if strings.HasPrefix(k, strategy+"/") {
+			delete(t.targets, k)
+		}
+	}
+}
+
+func TestEngineTargets(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	const hedgeYAML = `strategies:
+  - name: hedge
+    type: position
+    exchange: Bitmex
+    pairs: [BTC-USD]
+    params:
+      position: -2
+`
+	const targetsYAML = hedgeYAML + `  - name: trend
+    type: position
+    exchange: Bitmex
+    pairs: [BTC-USD]
+    sizing:
+      amount: 3
+      maxPosition: 4
+`
+	path := writeFile(t, dir, "strategies.yaml", targetsYAML)
+	e, err := NewEngine(path, func(_, _ string) (exchange.IBotExchange, error) {
+		return &testExchange{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = e.Register("position", NewPosition); err != nil {
+		t.Fatal(err)
+	}
+	if err = e.Reload(); err == nil || !strings.Contains(err.Error(), errNoTargets.Error()) {
+		t.Error("Test Failed - Reload() expected targets not supported error", err)
+	}
+
+	targets := &testTargets{targets: make(map[string]float64)}
+	e.SetTargets(targets)
+	writeFile(t, dir, "strategies.yaml", strings.Replace(targetsYAML, "amount: 3", "amount: 5", 1))
+	if err = e.Reload(); err == nil || !strings.Contains(err.Error(), ErrRiskLimit.Error()) {
+		t.Error("Test Failed - Reload() expected target beyond the maximum position rejected", err)
+	}
+	writeFile(t, dir, "strategies.yaml", targetsYAML)
+	if err = e.Reload(); err != nil {
+		t.Fatal("Test Failed - Reload() error", err)
+	}
+	if targets.targets["hedge/Bitmex/BTC-USD"] != -2 || targets.targets["trend/Bitmex/BTC-USD"] != 3 {
+		t.Fatalf("Test Failed - Reload() unexpected targets %v", targets.targets)
+	}
+
+	// Changed strategies keep their targets while restarting, removed
+	// strategies lose them
+	writeFile(t, dir, "strategies.yaml", strings.Replace(hedgeYAML, "position: -2", "position: -1", 1))
+	if err = e.Reload(); err != nil {
+		t.Fatal("Test Failed - Reload() error", err)
+	}
+	if len(targets.targets) != 1 || targets.targets["hedge/Bitmex/BTC-USD"] != -1 {
+		t.Errorf("Test Failed - Reload() expected removed strategy's targets removed %v", targets.targets)
+	}
+}
+
+type imbalanceStrategy struct {
+	testStrategy
+	submit ImbalanceSubmitFunc
+}
+
+func (s *imbalanceStrategy) SetImbalanceOrders(f ImbalanceSubmitFunc) { s.submit = f }
+
+func TestEngineImbalanceOrders(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "strategies.yaml", testYAML)
+
+	e, err := NewEngine(path, func(_, _ string) (exchange.IBotExchange, error) {
+		return &testExchange{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var via exchange.IBotExchange
+	e.SetImbalanceOrders(func(exch exchange.IBotExchange, _ *exchange.ImbalanceOrder) (string, error) {
+		via = exch
+		return "1", nil
+	})
+	s := &imbalanceStrategy{}
+	e.Register("twap", func() Strategy { return s })
+	if err = e.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if s.submit == nil {
+		t.Fatal("Test Failed - Reload() expected the imbalance order submitter set")
+	}
+
+	// Imbalance orders are placed through the strategy's tagged exchange
+	id, err := s.submit(&exchange.ImbalanceOrder{})
+	if err != nil || id != "1" {
+		t.Fatal("Test Failed - submit() unexpected response", id, err)
+	}
+	if _, ok := via.(*Tagged); !ok {
+		t.Errorf("Test Failed - submit() expected the strategy's exchange, got %T", via)
+	}
+}
+
+type eventStrategy struct {
+	testStrategy
+	ticks  chan *Ticker
+	trades chan *Trade
+	orders chan *OrderUpdate
+}
+
+func (s *eventStrategy) OnTick(t *Ticker) { s.ticks <- t }
+
+func (s *eventStrategy) OnTrade(t *Trade) { s.trades <- t }
+
+func (s *eventStrategy) OnOrderUpdate(o *OrderUpdate) { s.orders <- o }
+
+func TestEngineEvents(t *testing.T) {
+	dir := tempDir(t)
+	defer os.RemoveAll(dir)
+	path := writeFile(t, dir, "strategies.yaml", testYAML)
+
+	e, err := NewEngine(path, func(_, _ string) (exchange.IBotExchange, error) {
+		return &testExchange{}, nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	s := &eventStrategy{
+		ticks:  make(chan *Ticker),
+		trades: make(chan *Trade, 1),
+		orders: make(chan *OrderUpdate, 1),
+	}
+	e.Register("twap", func() Strategy { return s })
+	if err = e.Reload(); err != nil {
+		t.Fatal(err)
+	}
+
+	// Only the declared pair on the declared exchange is routed, regardless of
+	// the pair's delimiter
+	e.OnTick(&Ticker{Exchange: "OKEX", Pair: currency.NewPairFromString("BTC-USD"), Last: 1})
+	e.OnTick(&Ticker{Exchange: "Bitmex", Pair: currency.NewPairFromString("ETH-USD"), Last: 2})
+	e.OnTick(&Ticker{Exchange: "bitmex", Pair: currency.NewPairFromString("BTCUSD"), Last: 3})
+	select {
+	case tick := <-s.ticks:
+		if tick.Last != 3 {
+			t.Errorf("Test Failed - OnTick() routed the wrong ticker %+v", tick)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Test Failed - OnTick() expected ticker delivered")
+	}
+	// Strategies without an orderbook handler are skipped
+	e.OnOrderbook(&orderbook.Base{ExchangeName: "Bitmex", Pair: currency.NewPairFromString("BTC-USD")})
+	e.OnTrade(&Trade{Exchange: "Bitmex", Pair: currency.NewPairFromString("BTC-USD"), Price: 4})
+	select {
+	case trade := <-s.trades:
+		if trade.Price != 4 {
+			t.Errorf("Test Failed - OnTrade() routed the wrong trade %+v", trade)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Test Failed - OnTrade() expected trade delivered")
+	}
+	e.OnOrderUpdate(&OrderUpdate{Exchange: "Bitmex", Pair: currency.NewPairFromString("BTC-USD"), OrderID: "1"})
+	select {
+	case order := <-s.orders:
+		if order.OrderID != "1" {
+			t.Errorf("Test Failed - OnOrderUpdate() routed the wrong order %+v", order)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Test Failed - OnOrderUpdate() expected order update delivered")
+	}
+
+	// A strategy behind on its handlers has events dropped rather than
+	// blocking the caller
+	for i := 0; i < DefaultEventBuffer+2; i++ {
+		e.OnTick(&Ticker{Exchange: "Bitmex", Pair: currency.NewPairFromString("BTC-USD")})
+	}
+	if stats := e.GetSandboxStats()["accumulate"]; stats.Dropped == 0 {
+		t.Errorf("Test Failed - OnTick() expected events dropped %+v", stats)
+	}
+
+	e.mtx.Lock()
+	e.running["accumulate"].stop()
+	e.mtx.Unlock()
+	if s.stopped != 1 {
+		t.Error("Test Failed - stop() expected strategy stopped")
+	}
+}
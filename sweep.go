@@ -0,0 +1,119 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/drawdown"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/sweep"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// sweepFile persists the sweeps made in the data directory
+const sweepFile = "sweeps.json"
+
+var errSweepsDisabled = errors.New("cold storage sweeps not enabled")
+
+// SweepsResponse holds each sweep target's plan and the sweeps made
+type SweepsResponse struct {
+	Plans   []sweep.Plan   `json:"plans"`
+	Records []sweep.Record `json:"records"`
+}
+
+// ActivateSweeps starts sweeping exchange balances above the amount kept to
+// cold storage, within the configured withdrawal limits and those Kraken
+// reports. Sweeps go through the loaded exchanges so the address book,
+// compliance rules and dry run mode apply to them
+func ActivateSweeps() {
+	if bot.sweepConfig == "" {
+		return
+	}
+
+	cfg, err := sweep.LoadConfig(bot.sweepConfig)
+	if err != nil {
+		log.Errorf("Cold storage sweeps failed to load from %s: %s", bot.sweepConfig, err)
+		return
+	}
+	if bot.coldStorage != "" {
+		addresses, err := parseColdStorageAddresses(bot.coldStorage)
+		if err != nil {
+			log.Errorf("Cold storage sweeps failed to load: %s", err)
+			return
+		}
+		for i := range cfg.Targets {
+			if cfg.Targets[i].Address != "" || cfg.Targets[i].Key != "" {
+				continue
+			}
+			for j := range addresses {
+				if addresses[j].CoinType.Match(cfg.Targets[i].Currency) {
+					cfg.Targets[i].Address = addresses[j].Address
+					break
+				}
+			}
+		}
+	}
+
+	var sources []sweep.LimitSource
+	for _, exch := range GetLoadedExchanges() {
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		if k, ok := exchange.Underlying(exch).(*kraken.Kraken); ok {
+			sources = append(sources, &sweep.KrakenLimits{Exchange: k})
+		}
+	}
+
+	path := filepath.Join(bot.dataDir, sweepFile)
+	s, err := sweep.New(cfg, path, GetExchangeByName, sweepValue, sources, handleSweepEvent)
+	if err != nil {
+		log.Errorf("Cold storage sweeps failed to start: %s", err)
+		return
+	}
+	s.Start(bot.sweepInterval)
+	bot.sweeper = s
+	log.Debugf("Cold storage sweeps enabled for %d targets within %d withdrawal limits.",
+		len(cfg.Targets), len(cfg.Limits))
+}
+
+// sweepValue values a currency against an account wide withdrawal limit using
+// the exchange's latest ticker
+func sweepValue(exchName string, c, quote currency.Code) (float64, error) {
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return 0, ErrExchangeNotFound
+	}
+	return drawdown.ValueInQuote(exch, c, quote)
+}
+
+func handleSweepEvent(e sweep.Event) {
+	if e.Type == sweep.Swept {
+		log.Infof("Sweep: %s", e.String())
+	} else {
+		log.Warnf("Sweep: %s", e.String())
+	}
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Plan, "sweep_event", "", e.Plan.Target.Exchange)
+	}
+}
+
+// GetSweeps returns each sweep target's latest plan and the sweeps made
+func GetSweeps() (SweepsResponse, error) {
+	if bot.sweeper == nil {
+		return SweepsResponse{}, errSweepsDisabled
+	}
+	return SweepsResponse{
+		Plans:   bot.sweeper.GetPlans(),
+		Records: bot.sweeper.GetRecords(),
+	}, nil
+}
@@ -0,0 +1,118 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/arbvalidate"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/orderbook"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// parseVenue parses a venue in the format
+// exchange:takerfee:latency:transfertime[:withdrawfee[:base[:quote]]]
+// e.g. Binance:0.001:150ms:30m:0.0005
+func parseVenue(s string) (arbvalidate.Venue, error) {
+	fields := strings.Split(s, ":")
+	if len(fields) < 4 || len(fields) > 7 {
+		return arbvalidate.Venue{}, fmt.Errorf("invalid venue %q, expected exchange:takerfee:latency:transfertime[:withdrawfee[:base[:quote]]]", s)
+	}
+	v := arbvalidate.Venue{Exchange: fields[0]}
+	var err error
+	if v.TakerFee, err = strconv.ParseFloat(fields[1], 64); err != nil {
+		return v, fmt.Errorf("invalid %s taker fee: %s", v.Exchange, err)
+	}
+	if v.Latency, err = time.ParseDuration(fields[2]); err != nil {
+		return v, fmt.Errorf("invalid %s latency: %s", v.Exchange, err)
+	}
+	if v.TransferTime, err = time.ParseDuration(fields[3]); err != nil {
+		return v, fmt.Errorf("invalid %s transfer time: %s", v.Exchange, err)
+	}
+	amounts := []*float64{&v.WithdrawFee, &v.Base, &v.Quote}
+	for i, f := range fields[4:] {
+		if *amounts[i], err = strconv.ParseFloat(f, 64); err != nil {
+			return v, fmt.Errorf("invalid %s amount %q: %s", v.Exchange, f, err)
+		}
+	}
+	return v, nil
+}
+
+func main() {
+	var recordFile, venues, pair, assetType, outFile string
+	var maxAmount, minEdge, minSharpe, minCapture float64
+	var minTrades int
+	var sharpePeriod time.Duration
+
+	flag.StringVar(&recordFile, "record", "", "The websocket recording to replay, as written by the bot's -wsrecord flag.")
+	flag.StringVar(&venues, "venues", "", "Comma separated venues as exchange:takerfee:latency:transfertime[:withdrawfee[:base[:quote]]], zero balances are unlimited.")
+	flag.StringVar(&pair, "pair", "BTC-USD", "The currency pair arbitraged.")
+	flag.StringVar(&assetType, "asset", orderbook.Spot, "The asset type arbitraged.")
+	flag.Float64Var(&maxAmount, "maxamount", 0, "The largest amount traded on a single opportunity.")
+	flag.Float64Var(&minEdge, "minedge", arbvalidate.DefaultMinEdgeBps, "The edge in basis points after taker fees each traded unit must clear.")
+	flag.DurationVar(&sharpePeriod, "sharpeperiod", arbvalidate.DefaultSharpePeriod, "The period profits are bucketed by for the Sharpe ratio.")
+	flag.Float64Var(&minSharpe, "minsharpe", arbvalidate.DefaultMinSharpe, "The minimum annualised Sharpe ratio to go live.")
+	flag.Float64Var(&minCapture, "mincapture", arbvalidate.DefaultMinCapture, "The minimum fraction of the expected profit captured to go live.")
+	flag.IntVar(&minTrades, "mintrades", arbvalidate.DefaultMinTrades, "The minimum number of completed trades to go live.")
+	flag.StringVar(&outFile, "out", "", "Writes the full report including each trade to the JSON file.")
+	flag.Parse()
+
+	log.Println("GoCryptoTrader: arbitrage backtest validator tool.")
+
+	if recordFile == "" || venues == "" || maxAmount <= 0 {
+		log.Fatal("A recording, venues and max amount must be specified.")
+	}
+
+	cfg := arbvalidate.Config{
+		Pair:         currency.NewPairDelimiter(pair, "-"),
+		AssetType:    assetType,
+		MaxAmount:    maxAmount,
+		MinEdgeBps:   minEdge,
+		SharpePeriod: sharpePeriod,
+		MinSharpe:    minSharpe,
+		MinCapture:   minCapture,
+		MinTrades:    minTrades,
+	}
+	for _, s := range common.SplitStrings(venues, ",") {
+		v, err := parseVenue(s)
+		if err != nil {
+			log.Fatal(err)
+		}
+		cfg.Venues = append(cfg.Venues, v)
+	}
+
+	f, err := os.Open(recordFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	log.Printf("Replaying %s..", recordFile)
+	report, err := arbvalidate.Validate(f, cfg)
+	f.Close()
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println(report.String())
+
+	if outFile != "" {
+		data, err := json.MarshalIndent(report, "", " ")
+		if err != nil {
+			log.Fatal(err)
+		}
+		err = ioutil.WriteFile(outFile, data, 0644)
+		if err != nil {
+			log.Fatal(err)
+		}
+		log.Printf("Report written to %s.", outFile)
+	}
+	if !report.Go {
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"strings"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/common"
+	"github.com/thrasher-corp/gocryptotrader/config"
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/binance"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitfinex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitmex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/bitstamp"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/coinbasepro"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/hitbtc"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/huobi"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/latency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/okex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/poloniex"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+func loadExchange(name string) exchange.IBotExchange {
+	switch strings.ToLower(name) {
+	case "binance":
+		return new(binance.Binance)
+	case "bitfinex":
+		return new(bitfinex.Bitfinex)
+	case "bitmex":
+		return new(bitmex.Bitmex)
+	case "bitstamp":
+		return new(bitstamp.Bitstamp)
+	case "coinbasepro":
+		return new(coinbasepro.CoinbasePro)
+	case "hitbtc":
+		return new(hitbtc.HitBTC)
+	case "huobi":
+		return new(huobi.HUOBI)
+	case "kraken":
+		return new(kraken.Kraken)
+	case "okex":
+		return new(okex.OKEX)
+	case "poloniex":
+		return new(poloniex.Poloniex)
+	}
+	return nil
+}
+
+func main() {
+	var inFile, exchanges, pair, side, orderType string
+	var amount, price float64
+	var samples int
+	var interval, timeout time.Duration
+
+	defaultCfg, err := config.GetFilePath("")
+	if err != nil {
+		log.Fatal(err)
+	}
+
+	flag.StringVar(&inFile, "config", defaultCfg, "The config input file to process.")
+	flag.StringVar(&exchanges, "exchanges", "", "Comma separated list of exchanges to benchmark.")
+	flag.StringVar(&pair, "pair", "BTC-USD", "The currency pair to place orders on.")
+	flag.StringVar(&side, "side", "BUY", "The order side, BUY or SELL.")
+	flag.StringVar(&orderType, "ordertype", "MARKET", "The order type, MARKET or LIMIT.")
+	flag.Float64Var(&amount, "amount", 0, "The order amount, use the smallest amount the exchange allows.")
+	flag.Float64Var(&price, "price", 0, "The order price for limit orders, set at a marketable level to ensure a fill.")
+	flag.IntVar(&samples, "samples", latency.DefaultSamples, "The number of orders to place per exchange.")
+	flag.DurationVar(&interval, "interval", latency.DefaultInterval, "The delay between orders.")
+	flag.DurationVar(&timeout, "timeout", latency.DefaultTimeout, "How long to wait for each fill notification.")
+	flag.Parse()
+
+	log.Println("GoCryptoTrader: websocket fill latency benchmark tool.")
+	log.Println("WARNING: this tool places real orders, use testnet API endpoints where available.")
+
+	if exchanges == "" || amount <= 0 {
+		log.Fatal("An exchange and order amount must be specified.")
+	}
+
+	var cfg config.Config
+	err = cfg.LoadConfig(inFile)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Println("Loaded config file.")
+
+	benchCfg := latency.Config{
+		Pair:      currency.NewPairDelimiter(pair, "-"),
+		Side:      exchange.OrderSide(strings.ToUpper(side)),
+		OrderType: exchange.OrderType(strings.ToUpper(orderType)),
+		Amount:    amount,
+		Price:     price,
+		Samples:   samples,
+		Interval:  interval,
+		Timeout:   timeout,
+	}
+
+	var reports []latency.Report
+	for _, name := range common.SplitStrings(exchanges, ",") {
+		exch := loadExchange(name)
+		if exch == nil {
+			log.Errorf("%s exchange is not supported by this tool", name)
+			continue
+		}
+		exch.SetDefaults()
+		exchCfg, err := cfg.GetExchangeConfig(exch.GetName())
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		err = exchCfg.ResolveSecrets()
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		exchCfg.Enabled = true
+		exch.Setup(&exchCfg)
+
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			log.Errorf("%s authenticated API support is disabled", exch.GetName())
+			continue
+		}
+
+		ws, err := exch.GetWebsocket()
+		if err != nil || !ws.IsEnabled() {
+			log.Warnf("%s websocket unavailable, measuring REST detection only", exch.GetName())
+		} else {
+			err = ws.Connect()
+			if err != nil {
+				log.Warnf("%s websocket failed to connect: %s", exch.GetName(), err)
+			}
+		}
+
+		b, err := latency.New(exch, benchCfg)
+		if err != nil {
+			log.Error(err)
+			continue
+		}
+		log.Printf("Benchmarking %s with %d orders..", exch.GetName(), samples)
+		reports = append(reports, b.Run())
+
+		if ws != nil && ws.IsConnected() {
+			err = ws.Shutdown()
+			if err != nil {
+				log.Error(err)
+			}
+		}
+	}
+
+	log.Println()
+	log.Println("Fill latency from order submission:")
+	for i := range reports {
+		log.Println(reports[i].String())
+	}
+}
@@ -0,0 +1,122 @@
+package main
+
+import (
+	"errors"
+	"path/filepath"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/dropcopy"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/tradehistory"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+	"github.com/thrasher-corp/gocryptotrader/strategy"
+)
+
+const tradeHistoryFile = "fills.log"
+
+var errTradeHistoryDisabled = errors.New("trade history not enabled")
+
+// ActivateTradeHistory starts persisting strategy and drop copy fills and
+// comparing them with the order history of every exchange with authenticated
+// API support, backfilling missed fills and flagging irreconcilable ones
+func ActivateTradeHistory() {
+	if !bot.tradeHistory {
+		return
+	}
+
+	path := filepath.Join(bot.dataDir, tradeHistoryFile)
+	s, err := tradehistory.NewStore(path)
+	if err != nil {
+		log.Errorf("Trade history failed to load from %s: %s", path, err)
+		return
+	}
+
+	var sources []tradehistory.Source
+	for _, exch := range GetLoadedExchanges() {
+		if exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			sources = append(sources, exch)
+		}
+	}
+	d, err := tradehistory.New(s, sources, tradehistory.Config{}, handleTradeHistoryEvent)
+	if err != nil {
+		log.Errorf("Trade history failed to start: %s", err)
+		return
+	}
+	d.Start(bot.tradeHistoryInterval)
+	bot.tradeHistoryStore = s
+	bot.tradeHistoryDetector = d
+	log.Debugf("Trade history enabled for %d exchanges, persisting to %s.", len(sources), path)
+}
+
+func handleTradeHistoryEvent(e tradehistory.Event) {
+	if e.Type == tradehistory.Irreconcilable {
+		log.Warnf("Trade history: %s", e.String())
+	}
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		exchName := ""
+		if e.Fill != nil {
+			exchName = e.Fill.Exchange
+		} else if e.Discrepancy != nil {
+			exchName = e.Discrepancy.Exchange
+		}
+		relayWebsocketEvent(e, "trade_history", "", exchName)
+	}
+}
+
+// recordTradeHistoryFill persists a fill when trade history is enabled
+func recordTradeHistoryFill(f *tradehistory.Fill) {
+	if bot.tradeHistoryStore == nil {
+		return
+	}
+	err := bot.tradeHistoryStore.Add(f)
+	if err != nil {
+		log.Errorf("Trade history failed to record %s order %s fill: %s", f.Exchange, f.OrderID, err)
+	}
+}
+
+// handleStrategyFill records a strategy fill for transaction cost analysis
+// and in the trade history
+func handleStrategyFill(f *strategy.Fill) {
+	recordStrategyFill(f)
+	recordTradeHistoryFill(&tradehistory.Fill{
+		Exchange:  f.Exchange,
+		OrderID:   f.OrderID,
+		Pair:      f.Pair,
+		Side:      f.Side,
+		Price:     f.Price,
+		Amount:    f.Amount,
+		Timestamp: f.Timestamp,
+	})
+}
+
+// recordDropCopyTrade records a mirrored order fill in the trade history
+func recordDropCopyTrade(e *dropcopy.Event) {
+	if e.Order == nil || e.Type != dropcopy.OrderFill || e.Filled <= 0 {
+		return
+	}
+	recordTradeHistoryFill(&tradehistory.Fill{
+		Exchange:  e.Exchange,
+		OrderID:   e.Order.ID,
+		Pair:      e.Order.CurrencyPair,
+		Side:      e.Order.OrderSide,
+		Price:     e.Order.Price,
+		Amount:    e.Filled,
+		Timestamp: e.Timestamp,
+	})
+}
+
+// GetTradeHistoryReport returns each exchange's latest trade history
+// comparison and the irreconcilable differences found
+func GetTradeHistoryReport() (tradehistory.Report, error) {
+	if bot.tradeHistoryDetector == nil {
+		return tradehistory.Report{}, errTradeHistoryDisabled
+	}
+	return bot.tradeHistoryDetector.GetReport(), nil
+}
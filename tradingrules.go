@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/tradingrules"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errInvalidTradingRuleOverride = errors.New("trading rule overrides must be in the format EXCHANGE[:PAIR]=PRICEMODE/AMOUNTMODE[/PRICESTEP/AMOUNTSTEP]")
+
+// ActivateTradingRules guards every loaded exchange so order prices and
+// amounts are rounded to the exchange's increments when they are submitted
+func ActivateTradingRules() {
+	if !bot.tradingRules && bot.tradingRuleOverrides == "" {
+		return
+	}
+
+	var overrides map[string]exchange.TradingRules
+	if bot.tradingRuleOverrides != "" {
+		var err error
+		overrides, err = parseTradingRuleOverrides(bot.tradingRuleOverrides)
+		if err != nil {
+			log.Errorf("Trading rules failed to start: %s", err)
+			return
+		}
+	}
+
+	e := tradingrules.New(overrides)
+	for x := range bot.exchanges {
+		if bot.exchanges[x] == nil {
+			continue
+		}
+		bot.exchanges[x] = e.Guard(bot.exchanges[x])
+	}
+	log.Debugf("Trading rules enabled with %d overrides.", len(overrides))
+}
+
+// parseTradingRuleOverrides parses overrides in the format
+// Binance=nearest/down,Bitstamp:BTC-USD=passive/down/0.01/0.00000001 where an
+// empty mode or step keeps the exchange's value
+func parseTradingRuleOverrides(s string) (map[string]exchange.TradingRules, error) {
+	overrides := make(map[string]exchange.TradingRules)
+	for _, o := range strings.Split(s, ",") {
+		kv := strings.Split(strings.TrimSpace(o), "=")
+		if len(kv) != 2 || kv[0] == "" {
+			return nil, errInvalidTradingRuleOverride
+		}
+		parts := strings.Split(kv[1], "/")
+		if len(parts) != 2 && len(parts) != 4 {
+			return nil, errInvalidTradingRuleOverride
+		}
+
+		var r exchange.TradingRules
+		var err error
+		if parts[0] != "" {
+			r.PriceRounding, err = exchange.ParseRoundingMode(parts[0])
+			if err != nil {
+				return nil, err
+			}
+		}
+		if parts[1] != "" {
+			r.AmountRounding, err = exchange.ParseRoundingMode(parts[1])
+			if err != nil {
+				return nil, err
+			}
+		}
+		if len(parts) == 4 {
+			r.PriceStep, err = parseOptionalStep(parts[2])
+			if err != nil {
+				return nil, err
+			}
+			r.AmountStep, err = parseOptionalStep(parts[3])
+			if err != nil {
+				return nil, err
+			}
+		}
+		overrides[kv[0]] = r
+	}
+	return overrides, nil
+}
+
+func parseOptionalStep(s string) (float64, error) {
+	if s == "" {
+		return 0, nil
+	}
+	step, err := strconv.ParseFloat(s, 64)
+	if err != nil || step < 0 {
+		return 0, errInvalidTradingRuleOverride
+	}
+	return step, nil
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"errors"
+	"sort"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/stats"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/tca"
+)
+
+var errNoVenues = errors.New("no venues quote the currency pair and asset type")
+
+// VenueRoute ranks a venue for an order of a pair by its last price adjusted
+// by the venue's transaction costs
+type VenueRoute struct {
+	Exchange string  `json:"exchange"`
+	Price    float64 `json:"price"`
+	// CostBps is the venue's average shortfall and fees, zero for venues
+	// without recorded executions
+	CostBps        float64 `json:"costBps"`
+	EffectivePrice float64 `json:"effectivePrice"`
+	// Score is the venue's transaction cost score, 1 being the cheapest
+	Score float64 `json:"score"`
+}
+
+// GetVenueRoutes ranks the venues quoting a pair for an order on the side,
+// best first. Buys are ranked by the lowest and sells by the highest price
+// after transaction costs, venues at the same price by their cost score
+func GetVenueRoutes(p currency.Pair, assetType string, side exchange.OrderSide) ([]VenueRoute, error) {
+	items := stats.SortExchangesByPrice(p, assetType, false)
+	if len(items) == 0 {
+		return nil, errNoVenues
+	}
+
+	costs := make(map[string]float64)
+	reports := tca.GetVenueReports(p, assetType)
+	for i := range reports {
+		costs[reports[i].Exchange] = reports[i].AvgTotalCostBps
+	}
+	scores := tca.GetVenueScores(p, assetType)
+
+	buy := side == exchange.BuyOrderSide || side == exchange.BidOrderSide
+	routes := make([]VenueRoute, 0, len(items))
+	for i := range items {
+		r := VenueRoute{
+			Exchange: items[i].Exchange,
+			Price:    items[i].Price,
+			CostBps:  costs[items[i].Exchange],
+			Score:    1,
+		}
+		if s, ok := scores[items[i].Exchange]; ok {
+			r.Score = s
+		}
+		if buy {
+			r.EffectivePrice = r.Price * (1 + r.CostBps/10000)
+		} else {
+			r.EffectivePrice = r.Price * (1 - r.CostBps/10000)
+		}
+		routes = append(routes, r)
+	}
+
+	sort.SliceStable(routes, func(i, j int) bool {
+		if routes[i].EffectivePrice == routes[j].EffectivePrice {
+			return routes[i].Score > routes[j].Score
+		}
+		if buy {
+			return routes[i].EffectivePrice < routes[j].EffectivePrice
+		}
+		return routes[i].EffectivePrice > routes[j].EffectivePrice
+	})
+	return routes, nil
+}
@@ -0,0 +1,95 @@
+package main
+
+import (
+	"errors"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kline"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/volatility"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/warmup"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errVolatilityDisabled = errors.New("volatility service not enabled")
+
+// ActivateVolatilityService starts measuring the realized volatility term
+// structure of every enabled pair, streaming each refresh to websocket
+// clients. Strategies size against it when they declare a target volatility
+func ActivateVolatilityService() {
+	if !bot.volatility {
+		return
+	}
+
+	s, err := volatility.New(GetLoadedExchanges(), nil, volatilityCandles, handleVolatilityUpdate)
+	if err != nil {
+		log.Errorf("Volatility service failed to start: %s", err)
+		return
+	}
+	s.Start(bot.volatilityInterval)
+	bot.volatilityService = s
+	log.Debugf("Volatility service enabled, refreshing every %s.", bot.volatilityInterval)
+}
+
+// volatilityCandles returns the candles stored by the market data warmup when
+// they cover the window, then those of the candle store, otherwise candles are
+// fetched from the exchange
+func volatilityCandles(exchName string, p currency.Pair, interval time.Duration, limit int) ([]exchange.Candle, error) {
+	if bot.warmup != nil && bot.warmupInterval == interval {
+		candles := bot.warmup.GetCandles(exchName, p)
+		if len(candles) >= limit {
+			return candles[len(candles)-limit:], nil
+		}
+	}
+
+	if bot.candleStore != nil {
+		now := time.Now()
+		candles, err := bot.candleStore.GetCandles(exchName, p, kline.Interval(interval), now.Add(-interval*time.Duration(limit+1)), now)
+		if err == nil && len(candles) >= limit {
+			return candles[len(candles)-limit:], nil
+		}
+	}
+
+	exch := GetExchangeByName(exchName)
+	if exch == nil {
+		return nil, ErrExchangeNotFound
+	}
+	provider, ok := exchange.Underlying(exch).(exchange.CandleProvider)
+	if !ok {
+		return nil, exchange.ErrCandleIntervalUnsupported
+	}
+	return provider.GetHistoricCandles(p, warmup.DefaultAssetType, kline.Interval(interval), limit)
+}
+
+func handleVolatilityUpdate(t volatility.TermStructure) {
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(t, "volatility", warmup.DefaultAssetType, t.Exchange)
+	}
+}
+
+// strategyVolatility returns the annualized realized volatility strategies
+// size against
+func strategyVolatility(exchName string, p currency.Pair, window time.Duration) (float64, error) {
+	if bot.volatilityService == nil {
+		return 0, errVolatilityDisabled
+	}
+	return bot.volatilityService.Annualized(exchName, p, window)
+}
+
+// GetVolatility returns the realized volatility term structure of a pair
+func GetVolatility(exchName string, p currency.Pair) (volatility.TermStructure, error) {
+	if bot.volatilityService == nil {
+		return volatility.TermStructure{}, errVolatilityDisabled
+	}
+	return bot.volatilityService.Get(exchName, p)
+}
+
+// GetAllVolatility returns the realized volatility term structure of every
+// enabled pair
+func GetAllVolatility() ([]volatility.TermStructure, error) {
+	if bot.volatilityService == nil {
+		return nil, errVolatilityDisabled
+	}
+	return bot.volatilityService.GetAll(), nil
+}
@@ -0,0 +1,70 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/currency"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/volumeprofile"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wshandler"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errVolumeProfileDisabled = errors.New("volume profile service not enabled")
+
+// ActivateVolumeProfile starts aggregating websocket trades into per session
+// volume profiles, streaming each completed session to websocket clients.
+// Strategies trading around volume profile levels read the profiles
+func ActivateVolumeProfile() {
+	if !bot.volumeProfile {
+		return
+	}
+
+	s, err := volumeprofile.New(volumeprofile.Config{
+		Session:   bot.volumeProfileSession,
+		BucketBps: bot.volumeProfileBucket,
+	}, handleVolumeProfileSession)
+	if err != nil {
+		log.Errorf("Volume profile service failed to start: %s", err)
+		return
+	}
+	bot.volumeProfileService = s
+	log.Debugf("Volume profile service enabled, profiling %s sessions.", bot.volumeProfileSession)
+}
+
+// recordVolumeProfileTrade adds a websocket trade to its pair's profile if the
+// service is enabled
+func recordVolumeProfileTrade(t *wshandler.TradeData) {
+	if bot.volumeProfileService == nil {
+		return
+	}
+	err := bot.volumeProfileService.Add(t)
+	if err != nil {
+		log.Debugf("Failed to add trade to volume profile: %s", err)
+	}
+}
+
+func handleVolumeProfileSession(p volumeprofile.Profile) {
+	log.Debugf("Volume profile session closed, %s", p.String())
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(p, "volumeprofile", "", p.Exchange)
+	}
+}
+
+// GetVolumeProfile returns a pair's current session profile followed by its
+// completed sessions, newest first. Strategies trading around volume profile
+// levels read profiles through it
+func GetVolumeProfile(exchName string, p currency.Pair) ([]volumeprofile.Profile, error) {
+	if bot.volumeProfileService == nil {
+		return nil, errVolumeProfileDisabled
+	}
+	return bot.volumeProfileService.GetSessions(exchName, p)
+}
+
+// GetAllVolumeProfiles returns the current session profile of every pair
+// traded since the service started
+func GetAllVolumeProfiles() ([]volumeprofile.Profile, error) {
+	if bot.volumeProfileService == nil {
+		return nil, errVolumeProfileDisabled
+	}
+	return bot.volumeProfileService.GetAll(), nil
+}
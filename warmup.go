@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/warmup"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errWarmupDisabled = errors.New("market data warmup not enabled")
+
+// ActivateWarmup preloads historical candles for every enabled pair and waits
+// for websocket feeds to connect before emitting the warmup ready event.
+// Strategies wait on bot.warmup before activating
+func ActivateWarmup() {
+	if bot.warmupCandles <= 0 {
+		return
+	}
+
+	c, err := warmup.New(GetLoadedExchanges(), warmup.Config{
+		Candles:  bot.warmupCandles,
+		Interval: bot.warmupInterval,
+	}, handleWarmupReady)
+	if err != nil {
+		log.Errorf("Market data warmup failed to start: %s", err)
+		return
+	}
+	bot.warmup = c
+
+	go func() {
+		for {
+			_, err := c.Run()
+			if err == nil {
+				return
+			}
+			log.Warnf("Market data warmup incomplete, retrying: %s", err)
+			select {
+			case <-bot.shutdown:
+				return
+			case <-time.After(warmup.DefaultFeedTimeout):
+			}
+		}
+	}()
+	log.Debugf("Market data warmup started for %d candles.", bot.warmupCandles)
+}
+
+func handleWarmupReady(s warmup.Status) {
+	var candles int
+	for i := range s.Pairs {
+		candles += s.Pairs[i].Candles
+	}
+	msg := fmt.Sprintf("Market data warmup complete, %d candles loaded across %d pairs and %d websocket feeds healthy",
+		candles, len(s.Pairs), len(s.Feeds))
+	log.Debugln(msg)
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "WARMUP_READY",
+			TradeDetails: msg,
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(s, "warmup_ready", "", "")
+	}
+}
+
+// GetWarmupStatus returns the status of the market data warmup
+func GetWarmupStatus() (warmup.Status, error) {
+	if bot.warmup == nil {
+		return warmup.Status{}, errWarmupDisabled
+	}
+	return bot.warmup.GetStatus(), nil
+}
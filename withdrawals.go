@@ -0,0 +1,76 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/poloniex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/withdrawals"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errWithdrawalMonitorDisabled = errors.New("withdrawal confirmation monitor not enabled")
+
+// ActivateWithdrawalMonitor starts following outbound withdrawals on Poloniex
+// and Kraken through to their blockchain confirmation, alerting when a
+// transaction stalls
+func ActivateWithdrawalMonitor() {
+	if !bot.withdrawalMonitoring {
+		return
+	}
+
+	var sources []withdrawals.Source
+	for _, exch := range GetLoadedExchanges() {
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		switch e := exchange.Underlying(exch).(type) {
+		case *poloniex.Poloniex:
+			sources = append(sources, &withdrawals.PoloniexWithdrawals{Exchange: e})
+		case *kraken.Kraken:
+			sources = append(sources, &withdrawals.KrakenWithdrawals{Exchange: e})
+		}
+	}
+
+	m, err := withdrawals.New(withdrawals.Config{StallAfter: bot.withdrawalStallAfter},
+		sources,
+		withdrawals.DefaultExplorers(),
+		handleWithdrawalEvent)
+	if err != nil {
+		log.Errorf("Withdrawal confirmation monitor failed to start: %s", err)
+		return
+	}
+	m.Start(withdrawals.DefaultCheckInterval)
+	bot.withdrawalMonitor = m
+	log.Debugf("Withdrawal confirmation monitor enabled for %d exchanges.", len(sources))
+}
+
+func handleWithdrawalEvent(e withdrawals.Event) {
+	if e.Type == withdrawals.Stalled || e.Type == withdrawals.LowFee {
+		log.Warnf("Withdrawal monitor: %s", e.String())
+	} else {
+		log.Debugln(e.String())
+	}
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e.Withdrawal, "withdrawal_event", "", e.Withdrawal.Exchange)
+	}
+}
+
+// GetWithdrawals returns tracked withdrawals on the named exchange, or every
+// exchange when empty. Confirmed and failed withdrawals are only included
+// when all is set
+func GetWithdrawals(exchName string, all bool) ([]withdrawals.Withdrawal, error) {
+	if bot.withdrawalMonitor == nil {
+		return nil, errWithdrawalMonitorDisabled
+	}
+	return bot.withdrawalMonitor.GetWithdrawals(exchName, all), nil
+}
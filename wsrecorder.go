@@ -0,0 +1,68 @@
+package main
+
+import (
+	"os"
+	"sync"
+
+	"github.com/thrasher-corp/gocryptotrader/exchanges/wsrecorder"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+// websocketRecorder records websocket data to a file for later orderbook
+// reconstruction
+type websocketRecorder struct {
+	file     *os.File
+	recorder *wsrecorder.Recorder
+	mtx      sync.Mutex
+}
+
+// ActivateWebsocketRecorder starts recording websocket orderbook and trade
+// data to the configured file
+func ActivateWebsocketRecorder() {
+	if bot.wsRecordFile == "" {
+		return
+	}
+
+	f, err := os.OpenFile(bot.wsRecordFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0640)
+	if err != nil {
+		log.Errorf("Websocket recorder failed to start: %s", err)
+		return
+	}
+
+	r, err := wsrecorder.NewRecorder(f)
+	if err != nil {
+		f.Close()
+		log.Errorf("Websocket recorder failed to start: %s", err)
+		return
+	}
+	bot.wsRecorder = &websocketRecorder{file: f, recorder: r}
+	log.Debugf("Websocket recorder enabled, recording to %s.", bot.wsRecordFile)
+}
+
+// recordWebsocketData records websocket data if the recorder is enabled
+func recordWebsocketData(data interface{}) {
+	if bot.wsRecorder == nil {
+		return
+	}
+	bot.wsRecorder.mtx.Lock()
+	defer bot.wsRecorder.mtx.Unlock()
+	if bot.wsRecorder.file == nil {
+		return
+	}
+	err := bot.wsRecorder.recorder.HandleData(data)
+	if err != nil {
+		log.Errorf("Websocket recorder failed to record data: %s", err)
+	}
+}
+
+// Close stops recording and closes the record file
+func (w *websocketRecorder) Close() error {
+	w.mtx.Lock()
+	defer w.mtx.Unlock()
+	if w.file == nil {
+		return nil
+	}
+	err := w.file.Close()
+	w.file = nil
+	return err
+}
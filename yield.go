@@ -0,0 +1,79 @@
+package main
+
+import (
+	"errors"
+
+	"github.com/thrasher-corp/gocryptotrader/communications/base"
+	exchange "github.com/thrasher-corp/gocryptotrader/exchanges"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/kraken"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/poloniex"
+	"github.com/thrasher-corp/gocryptotrader/exchanges/yield"
+	log "github.com/thrasher-corp/gocryptotrader/logger"
+)
+
+var errYieldOptimizerDisabled = errors.New("idle balance yield optimizer not enabled")
+
+// ActivateYieldOptimizer starts deploying balances not allocated to strategies
+// to Poloniex lending and Kraken staking. Strategy capital allocation must be
+// enabled so the optimizer knows which balances strategies need
+func ActivateYieldOptimizer() {
+	if !bot.yield {
+		return
+	}
+	if bot.allocationLedger == nil {
+		log.Errorf("Idle balance yield optimizer failed to start: %s", errAllocationsDisabled)
+		return
+	}
+
+	var sources []yield.Source
+	for _, exch := range GetLoadedExchanges() {
+		if !exch.GetAuthenticatedAPISupport(exchange.RestAuthentication) {
+			continue
+		}
+		// Lending and staking move funds, which read only and dry run
+		// exchanges must not do
+		if exchange.IsReadOnly(exch) || bot.dryRun {
+			continue
+		}
+		switch e := exchange.Underlying(exch).(type) {
+		case *poloniex.Poloniex:
+			sources = append(sources, &yield.PoloniexLending{Exchange: e})
+		case *kraken.Kraken:
+			sources = append(sources, &yield.KrakenStaking{Exchange: e})
+		}
+	}
+
+	o, err := yield.New(sources, bot.allocationLedger.GetAllocated, yield.Config{
+		Reserve: yield.DefaultReserve,
+		MinRate: bot.yieldMinRate / 100,
+	}, handleYieldEvent)
+	if err != nil {
+		log.Errorf("Idle balance yield optimizer failed to start: %s", err)
+		return
+	}
+	o.Start(yield.DefaultCheckInterval)
+	bot.yieldOptimizer = o
+	log.Debugf("Idle balance yield optimizer enabled for %d exchanges.", len(sources))
+}
+
+func handleYieldEvent(e yield.Event) {
+	log.Debugf("Yield optimizer: %s", e.String())
+	if bot.comms != nil {
+		bot.comms.PushEvent(base.Event{
+			Type:         "YIELD_" + e.Type,
+			TradeDetails: e.String(),
+		})
+	}
+
+	if bot.config.Webserver.Enabled {
+		relayWebsocketEvent(e, "yield_event", "", e.Exchange)
+	}
+}
+
+// GetYieldPositions returns the balances deployed to yield sources
+func GetYieldPositions() ([]yield.Position, error) {
+	if bot.yieldOptimizer == nil {
+		return nil, errYieldOptimizerDisabled
+	}
+	return bot.yieldOptimizer.GetPositions()
+}